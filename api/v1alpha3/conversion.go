@@ -40,7 +40,50 @@ func (r *AWSCluster) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 
-	restoreInstance(restored.Status.Bastion, dst.Status.Bastion)
+	RestoreInstance(restored.Status.Bastion, dst.Status.Bastion)
+	dst.Spec.S3Bucket = restored.Spec.S3Bucket
+	dst.Spec.ECRPullThroughCache = restored.Spec.ECRPullThroughCache
+	dst.Spec.OperationTimeouts = restored.Spec.OperationTimeouts
+	dst.Spec.RequeueIntervals = restored.Spec.RequeueIntervals
+	dst.Spec.Karpenter = restored.Spec.Karpenter
+	dst.Status.Karpenter = restored.Status.Karpenter
+	dst.Spec.Bastion.AutoScaling = restored.Spec.Bastion.AutoScaling
+	dst.Status.Bastions = restored.Status.Bastions
+	dst.Status.BastionLoadBalancer = restored.Status.BastionLoadBalancer
+	dst.Spec.SSHKeyPair = restored.Spec.SSHKeyPair
+	dst.Spec.MachineDefaults = restored.Spec.MachineDefaults
+	dst.Spec.ControlPlaneInstanceType = restored.Spec.ControlPlaneInstanceType
+	dst.Spec.DeletionPolicy = restored.Spec.DeletionPolicy
+	dst.Status.SSHKeyPair = restored.Status.SSHKeyPair
+	dst.Spec.NetworkSpec.AllowNodeToNodeTraffic = restored.Spec.NetworkSpec.AllowNodeToNodeTraffic
+	dst.Spec.NetworkSpec.SecurityGroupOverrideTracking = restored.Spec.NetworkSpec.SecurityGroupOverrideTracking
+	dst.Spec.NetworkSpec.SecurityGroupPolicy = restored.Spec.NetworkSpec.SecurityGroupPolicy
+	dst.Spec.NetworkSpec.Firewall = restored.Spec.NetworkSpec.Firewall
+	dst.Spec.NetworkSpec.GatewayLoadBalancerEndpointRoutes = restored.Spec.NetworkSpec.GatewayLoadBalancerEndpointRoutes
+	dst.Spec.NetworkSpec.Routes = restored.Spec.NetworkSpec.Routes
+	dst.Spec.NetworkSpec.ElasticIPPool = restored.Spec.NetworkSpec.ElasticIPPool
+	dst.Spec.NetworkSpec.NatGatewayAllocationIDs = restored.Spec.NetworkSpec.NatGatewayAllocationIDs
+	dst.Spec.NetworkSpec.VPC.IPv6 = restored.Spec.NetworkSpec.VPC.IPv6
+	RestoreSubnets(restored.Spec.NetworkSpec.Subnets, dst.Spec.NetworkSpec.Subnets)
+	dst.Status.Network.SharedVPC = restored.Status.Network.SharedVPC
+	dst.Status.Network.InternalAPIServerELB = restored.Status.Network.InternalAPIServerELB
+	dst.Status.Network.InternetGatewayID = restored.Status.Network.InternetGatewayID
+	dst.Status.Network.NatGateways = restored.Status.Network.NatGateways
+	dst.Status.Network.RouteTables = restored.Status.Network.RouteTables
+	dst.Status.Network.DiscoveredSubnets = restored.Status.Network.DiscoveredSubnets
+	dst.Status.Network.Firewall = restored.Status.Network.Firewall
+	dst.Status.Network.APIServerELB.Instances = restored.Status.Network.APIServerELB.Instances
+	dst.Status.Network.APIServerELB.ProxyProtocol = restored.Status.Network.APIServerELB.ProxyProtocol
+	dst.Status.Network.APIServerELB.Attributes.ConnectionDrainingTimeout = restored.Status.Network.APIServerELB.Attributes.ConnectionDrainingTimeout
+	if restored.Spec.ControlPlaneLoadBalancer != nil && dst.Spec.ControlPlaneLoadBalancer != nil {
+		dst.Spec.ControlPlaneLoadBalancer.AllowedCIDRBlocks = restored.Spec.ControlPlaneLoadBalancer.AllowedCIDRBlocks
+		dst.Spec.ControlPlaneLoadBalancer.InternalLoadBalancer = restored.Spec.ControlPlaneLoadBalancer.InternalLoadBalancer
+		dst.Spec.ControlPlaneLoadBalancer.IdleTimeout = restored.Spec.ControlPlaneLoadBalancer.IdleTimeout
+		dst.Spec.ControlPlaneLoadBalancer.ConnectionDrainingTimeout = restored.Spec.ControlPlaneLoadBalancer.ConnectionDrainingTimeout
+		dst.Spec.ControlPlaneLoadBalancer.ProxyProtocol = restored.Spec.ControlPlaneLoadBalancer.ProxyProtocol
+		dst.Spec.ControlPlaneLoadBalancer.DNSCheck = restored.Spec.ControlPlaneLoadBalancer.DNSCheck
+		dst.Spec.ControlPlaneLoadBalancer.InstancePort = restored.Spec.ControlPlaneLoadBalancer.InstancePort
+	}
 	return nil
 }
 
@@ -89,6 +132,25 @@ func (r *AWSMachine) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	RestoreAMIReference(&restored.Spec.AMI, &dst.Spec.AMI)
+	dst.Spec.ManagedIAMInstanceProfile = restored.Spec.ManagedIAMInstanceProfile
+	dst.Spec.ScheduledEventPolicy = restored.Spec.ScheduledEventPolicy
+	dst.Spec.UserDataChangePolicy = restored.Spec.UserDataChangePolicy
+	dst.Spec.FallbackInstanceTypes = restored.Spec.FallbackInstanceTypes
+	dst.Spec.GPU = restored.Spec.GPU
+	dst.Spec.Bottlerocket = restored.Spec.Bottlerocket
+	dst.Spec.CPUOptions = restored.Spec.CPUOptions
+	dst.Spec.InstanceMetadataOptions = restored.Spec.InstanceMetadataOptions
+	dst.Spec.HostResourceGroupARN = restored.Spec.HostResourceGroupARN
+	dst.Spec.LicenseConfigurationARNs = restored.Spec.LicenseConfigurationARNs
+	dst.Spec.PrivateIP = restored.Spec.PrivateIP
+	dst.Spec.PrivateIPFromPool = restored.Spec.PrivateIPFromPool
+	dst.Spec.NetworkInterfaceConfigs = restored.Spec.NetworkInterfaceConfigs
+	RestoreSpotMarketOptions(restored.Spec.SpotMarketOptions, dst.Spec.SpotMarketOptions)
+	dst.Status.SpotLaunchFailures = restored.Status.SpotLaunchFailures
+	dst.Status.OnDemandFallback = restored.Status.OnDemandFallback
+	dst.Status.Instance = restored.Status.Instance
+	dst.Status.ScheduledEvents = restored.Status.ScheduledEvents
+	dst.Status.RightSizing = restored.Status.RightSizing
 	return nil
 }
 
@@ -136,6 +198,21 @@ func (r *AWSMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	RestoreAMIReference(&restored.Spec.Template.Spec.AMI, &dst.Spec.Template.Spec.AMI)
+	dst.Status = restored.Status
+	dst.Spec.Template.Spec.ManagedIAMInstanceProfile = restored.Spec.Template.Spec.ManagedIAMInstanceProfile
+	dst.Spec.Template.Spec.ScheduledEventPolicy = restored.Spec.Template.Spec.ScheduledEventPolicy
+	dst.Spec.Template.Spec.UserDataChangePolicy = restored.Spec.Template.Spec.UserDataChangePolicy
+	dst.Spec.Template.Spec.FallbackInstanceTypes = restored.Spec.Template.Spec.FallbackInstanceTypes
+	dst.Spec.Template.Spec.GPU = restored.Spec.Template.Spec.GPU
+	dst.Spec.Template.Spec.Bottlerocket = restored.Spec.Template.Spec.Bottlerocket
+	dst.Spec.Template.Spec.CPUOptions = restored.Spec.Template.Spec.CPUOptions
+	dst.Spec.Template.Spec.InstanceMetadataOptions = restored.Spec.Template.Spec.InstanceMetadataOptions
+	dst.Spec.Template.Spec.HostResourceGroupARN = restored.Spec.Template.Spec.HostResourceGroupARN
+	dst.Spec.Template.Spec.LicenseConfigurationARNs = restored.Spec.Template.Spec.LicenseConfigurationARNs
+	dst.Spec.Template.Spec.PrivateIP = restored.Spec.Template.Spec.PrivateIP
+	dst.Spec.Template.Spec.PrivateIPFromPool = restored.Spec.Template.Spec.PrivateIPFromPool
+	dst.Spec.Template.Spec.NetworkInterfaceConfigs = restored.Spec.Template.Spec.NetworkInterfaceConfigs
+	RestoreSpotMarketOptions(restored.Spec.Template.Spec.SpotMarketOptions, dst.Spec.Template.Spec.SpotMarketOptions)
 	return nil
 }
 
@@ -176,6 +253,15 @@ func (r *AWSClusterStaticIdentity) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.SecretRef = r.Spec.SecretRef.Name
+
+	// Manually restore data.
+	restored := &v1alpha4.AWSClusterStaticIdentity{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.CABundle = restored.Spec.CABundle
+	dst.Spec.Proxy = restored.Spec.Proxy
 	return nil
 }
 
@@ -188,6 +274,12 @@ func (r *AWSClusterStaticIdentity) ConvertFrom(srcRaw conversion.Hub) error {
 	}
 
 	r.Spec.SecretRef.Name = src.Spec.SecretRef
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -209,14 +301,33 @@ func (r *AWSClusterStaticIdentityList) ConvertFrom(srcRaw conversion.Hub) error
 func (r *AWSClusterRoleIdentity) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.AWSClusterRoleIdentity)
 
-	return Convert_v1alpha3_AWSClusterRoleIdentity_To_v1alpha4_AWSClusterRoleIdentity(r, dst, nil)
+	if err := Convert_v1alpha3_AWSClusterRoleIdentity_To_v1alpha4_AWSClusterRoleIdentity(r, dst, nil); err != nil {
+		return err
+	}
+	// Manually restore data.
+	restored := &v1alpha4.AWSClusterRoleIdentity{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.CABundle = restored.Spec.CABundle
+	dst.Spec.Proxy = restored.Spec.Proxy
+	return nil
 }
 
 // ConvertFrom converts the v1alpha4 AWSClusterRoleIdentity receiver to a v1alpha3 AWSClusterRoleIdentity.
 func (r *AWSClusterRoleIdentity) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.AWSClusterRoleIdentity)
 
-	return Convert_v1alpha4_AWSClusterRoleIdentity_To_v1alpha3_AWSClusterRoleIdentity(src, r, nil)
+	if err := Convert_v1alpha4_AWSClusterRoleIdentity_To_v1alpha3_AWSClusterRoleIdentity(src, r, nil); err != nil {
+		return err
+	}
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // ConvertTo converts the v1alpha3 AWSClusterRoleIdentityList receiver to a v1alpha4 AWSClusterRoleIdentityList.
@@ -237,14 +348,33 @@ func (r *AWSClusterRoleIdentityList) ConvertFrom(srcRaw conversion.Hub) error {
 func (r *AWSClusterControllerIdentity) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.AWSClusterControllerIdentity)
 
-	return Convert_v1alpha3_AWSClusterControllerIdentity_To_v1alpha4_AWSClusterControllerIdentity(r, dst, nil)
+	if err := Convert_v1alpha3_AWSClusterControllerIdentity_To_v1alpha4_AWSClusterControllerIdentity(r, dst, nil); err != nil {
+		return err
+	}
+	// Manually restore data.
+	restored := &v1alpha4.AWSClusterControllerIdentity{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.CABundle = restored.Spec.CABundle
+	dst.Spec.Proxy = restored.Spec.Proxy
+	return nil
 }
 
 // ConvertFrom converts the v1alpha4 AWSClusterControllerIdentity receiver to a v1alpha3 AWSClusterControllerIdentity.
 func (r *AWSClusterControllerIdentity) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.AWSClusterControllerIdentity)
 
-	return Convert_v1alpha4_AWSClusterControllerIdentity_To_v1alpha3_AWSClusterControllerIdentity(src, r, nil)
+	if err := Convert_v1alpha4_AWSClusterControllerIdentity_To_v1alpha3_AWSClusterControllerIdentity(src, r, nil); err != nil {
+		return err
+	}
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // ConvertTo converts the v1alpha3 AWSClusterControllerIdentityList receiver to a v1alpha4 AWSClusterControllerIdentityList.
@@ -271,6 +401,11 @@ func Convert_v1alpha4_APIEndpoint_To_v1alpha3_APIEndpoint(in *apiv1alpha4.APIEnd
 	return apiv1alpha3.Convert_v1alpha4_APIEndpoint_To_v1alpha3_APIEndpoint(in, out, s)
 }
 
+// Convert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec is an autogenerated conversion function.
+func Convert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(in *v1alpha4.AWSClusterIdentitySpec, out *AWSClusterIdentitySpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(in, out, s)
+}
+
 // Convert_v1alpha3_AWSClusterStaticIdentitySpec_To_v1alpha4_AWSClusterStaticIdentitySpec is an autogenerated conversion function.
 func Convert_v1alpha3_AWSClusterStaticIdentitySpec_To_v1alpha4_AWSClusterStaticIdentitySpec(in *AWSClusterStaticIdentitySpec, out *v1alpha4.AWSClusterStaticIdentitySpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha3_AWSClusterStaticIdentitySpec_To_v1alpha4_AWSClusterStaticIdentitySpec(in, out, s)
@@ -286,17 +421,65 @@ func Convert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in *v1alpha4.AWS
 	return autoConvert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in, out, s)
 }
 
+// Convert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate is an autogenerated conversion function.
+func Convert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(in *v1alpha4.AWSMachineTemplate, out *AWSMachineTemplate, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(in, out, s)
+}
+
 // Convert_v1alpha4_Instance_To_v1alpha3_Instance is an autogenerated conversion function.
 func Convert_v1alpha4_Instance_To_v1alpha3_Instance(in *v1alpha4.Instance, out *Instance, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_Instance_To_v1alpha3_Instance(in, out, s)
 }
 
-// Manually restore the instance root device data
-func restoreInstance(restored, dst *v1alpha4.Instance) {
-	if restored == nil {
+// Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec is an autogenerated conversion function.
+func Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.NetworkSpec, out *NetworkSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in, out, s)
+}
+
+// RestoreInstance manually restores the instance fields that do not exist in v1alpha3.
+func RestoreInstance(restored, dst *v1alpha4.Instance) {
+	if restored == nil || dst == nil {
 		return
 	}
+	dst.StateReason = restored.StateReason
 	dst.VolumeIDs = restored.VolumeIDs
+	dst.LaunchTime = restored.LaunchTime
+	dst.Lifecycle = restored.Lifecycle
+	dst.SpotInstanceRequestID = restored.SpotInstanceRequestID
+	dst.HostID = restored.HostID
+	dst.PrimaryNetworkInterfaceID = restored.PrimaryNetworkInterfaceID
+	dst.HostResourceGroupARN = restored.HostResourceGroupARN
+	dst.LicenseConfigurationARNs = restored.LicenseConfigurationARNs
+	dst.CPUOptions = restored.CPUOptions
+	dst.InstanceMetadataOptions = restored.InstanceMetadataOptions
+	dst.NetworkInterfaceConfigs = restored.NetworkInterfaceConfigs
+	RestoreSpotMarketOptions(restored.SpotMarketOptions, dst.SpotMarketOptions)
+}
+
+// RestoreSubnets manually restores the subnet fields that do not exist in v1alpha3.
+func RestoreSubnets(restored, dst v1alpha4.Subnets) {
+	for i := range dst {
+		if subnet := restored.FindEqual(&dst[i]); subnet != nil {
+			dst[i].AvailabilityZoneID = subnet.AvailabilityZoneID
+			dst[i].IPv6CidrBlock = subnet.IPv6CidrBlock
+			dst[i].IsPublicOverride = subnet.IsPublicOverride
+			dst[i].Routes = subnet.Routes
+			dst[i].UnmanagedRouteTableID = subnet.UnmanagedRouteTableID
+		}
+	}
+}
+
+// Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions is an autogenerated conversion function.
+func Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(in *v1alpha4.SpotMarketOptions, out *SpotMarketOptions, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(in, out, s)
+}
+
+// RestoreSpotMarketOptions manually restores the spot market options that do not exist in v1alpha3.
+func RestoreSpotMarketOptions(restored, dst *v1alpha4.SpotMarketOptions) {
+	if restored == nil || dst == nil {
+		return
+	}
+	dst.FallbackToOnDemand = restored.FallbackToOnDemand
 }
 
 // Convert_v1alpha3_AWSResourceReference_To_v1alpha4_AMIReference is a conversion function.
@@ -311,10 +494,64 @@ func Convert_v1alpha4_AMIReference_To_v1alpha3_AWSResourceReference(in *v1alpha4
 	return nil
 }
 
-// RestoreAMIReference manually restore the EKSOptimizedLookupType for AWSMachine and AWSMachineTemplate
+// RestoreAMIReference manually restore the EKSOptimizedLookupType and OSFamily for AWSMachine and AWSMachineTemplate
 func RestoreAMIReference(restored, dst *v1alpha4.AMIReference) {
-	if restored == nil || restored.EKSOptimizedLookupType == nil {
+	if restored == nil {
+		return
+	}
+	dst.OSFamily = restored.OSFamily
+	if restored.EKSOptimizedLookupType == nil {
 		return
 	}
 	dst.EKSOptimizedLookupType = restored.EKSOptimizedLookupType
 }
+
+// Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec is an autogenerated conversion function.
+func Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in *v1alpha4.AWSClusterSpec, out *AWSClusterSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in, out, s)
+}
+
+// Convert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus is an autogenerated conversion function.
+func Convert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(in *v1alpha4.AWSMachineStatus, out *AWSMachineStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(in, out, s)
+}
+
+// Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec is an autogenerated conversion function.
+func Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in *v1alpha4.SubnetSpec, out *SubnetSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in, out, s)
+}
+
+// Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec is an autogenerated conversion function.
+func Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in *v1alpha4.VPCSpec, out *VPCSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in, out, s)
+}
+
+// Convert_v1alpha4_Network_To_v1alpha3_Network is an autogenerated conversion function.
+func Convert_v1alpha4_Network_To_v1alpha3_Network(in *v1alpha4.Network, out *Network, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_Network_To_v1alpha3_Network(in, out, s)
+}
+
+// Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec is an autogenerated conversion function.
+func Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in *v1alpha4.AWSLoadBalancerSpec, out *AWSLoadBalancerSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in, out, s)
+}
+
+// Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB is an autogenerated conversion function.
+func Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(in *v1alpha4.ClassicELB, out *ClassicELB, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(in, out, s)
+}
+
+// Convert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes is an autogenerated conversion function.
+func Convert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(in *v1alpha4.ClassicELBAttributes, out *ClassicELBAttributes, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(in, out, s)
+}
+
+// Convert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus is an autogenerated conversion function.
+func Convert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(in *v1alpha4.AWSClusterStatus, out *AWSClusterStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(in, out, s)
+}
+
+// Convert_v1alpha4_Bastion_To_v1alpha3_Bastion is an autogenerated conversion function.
+func Convert_v1alpha4_Bastion_To_v1alpha3_Bastion(in *v1alpha4.Bastion, out *Bastion, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_Bastion_To_v1alpha3_Bastion(in, out, s)
+}
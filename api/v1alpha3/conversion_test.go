@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/utils/pointer"
 	v1alpha4 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
@@ -105,3 +106,61 @@ func TestFuzzyConversion(t *testing.T) {
 		Spoke:  &AWSClusterRoleIdentity{},
 	}))
 }
+
+func TestConvertAWSMachineRoundTrip(t *testing.T) {
+	gpuLookupType := v1alpha4.AmazonLinuxGPU
+
+	tests := []struct {
+		name string
+		spec v1alpha4.AWSMachineSpec
+	}{
+		{
+			name: "spot market options",
+			spec: v1alpha4.AWSMachineSpec{
+				InstanceType:      "m5.large",
+				SpotMarketOptions: &v1alpha4.SpotMarketOptions{MaxPrice: pointer.StringPtr("0.05")},
+			},
+		},
+		{
+			name: "tenancy",
+			spec: v1alpha4.AWSMachineSpec{
+				InstanceType: "m5.large",
+				Tenancy:      "dedicated",
+			},
+		},
+		{
+			name: "fields that only exist in v1alpha4",
+			spec: v1alpha4.AWSMachineSpec{
+				InstanceType: "m5.large",
+				AMI: v1alpha4.AMIReference{
+					EKSOptimizedLookupType: &gpuLookupType,
+				},
+				ManagedIAMInstanceProfile: &v1alpha4.ManagedIAMInstanceProfile{
+					ManagedPolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			hub := &v1alpha4.AWSMachine{Spec: tt.spec}
+			spoke := &AWSMachine{}
+			g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			template := &v1alpha4.AWSMachineTemplate{Spec: v1alpha4.AWSMachineTemplateSpec{Template: v1alpha4.AWSMachineTemplateResource{Spec: tt.spec}}}
+			spokeTemplate := &AWSMachineTemplate{}
+			g.Expect(spokeTemplate.ConvertFrom(template)).To(Succeed())
+
+			restored := &v1alpha4.AWSMachine{}
+			g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+			g.Expect(restored.Spec).To(Equal(tt.spec))
+
+			restoredTemplate := &v1alpha4.AWSMachineTemplate{}
+			g.Expect(spokeTemplate.ConvertTo(restoredTemplate)).To(Succeed())
+			g.Expect(restoredTemplate.Spec.Template.Spec).To(Equal(tt.spec))
+		})
+	}
+}
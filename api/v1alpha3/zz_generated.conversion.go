@@ -84,11 +84,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSClusterIdentitySpec)(nil), (*AWSClusterIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(a.(*v1alpha4.AWSClusterIdentitySpec), b.(*AWSClusterIdentitySpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSClusterList)(nil), (*v1alpha4.AWSClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSClusterList_To_v1alpha4_AWSClusterList(a.(*AWSClusterList), b.(*v1alpha4.AWSClusterList), scope)
 	}); err != nil {
@@ -134,11 +129,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSClusterSpec)(nil), (*AWSClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(a.(*v1alpha4.AWSClusterSpec), b.(*AWSClusterSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSClusterStaticIdentity)(nil), (*v1alpha4.AWSClusterStaticIdentity)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSClusterStaticIdentity_To_v1alpha4_AWSClusterStaticIdentity(a.(*AWSClusterStaticIdentity), b.(*v1alpha4.AWSClusterStaticIdentity), scope)
 	}); err != nil {
@@ -164,11 +154,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSClusterStatus)(nil), (*AWSClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(a.(*v1alpha4.AWSClusterStatus), b.(*AWSClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSIdentityReference)(nil), (*v1alpha4.AWSIdentityReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSIdentityReference_To_v1alpha4_AWSIdentityReference(a.(*AWSIdentityReference), b.(*v1alpha4.AWSIdentityReference), scope)
 	}); err != nil {
@@ -184,11 +169,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSLoadBalancerSpec)(nil), (*AWSLoadBalancerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(a.(*v1alpha4.AWSLoadBalancerSpec), b.(*AWSLoadBalancerSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSMachine)(nil), (*v1alpha4.AWSMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSMachine_To_v1alpha4_AWSMachine(a.(*AWSMachine), b.(*v1alpha4.AWSMachine), scope)
 	}); err != nil {
@@ -219,21 +199,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSMachineStatus)(nil), (*AWSMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(a.(*v1alpha4.AWSMachineStatus), b.(*AWSMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSMachineTemplate)(nil), (*v1alpha4.AWSMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSMachineTemplate_To_v1alpha4_AWSMachineTemplate(a.(*AWSMachineTemplate), b.(*v1alpha4.AWSMachineTemplate), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSMachineTemplate)(nil), (*AWSMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(a.(*v1alpha4.AWSMachineTemplate), b.(*AWSMachineTemplate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSMachineTemplateList)(nil), (*v1alpha4.AWSMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSMachineTemplateList_To_v1alpha4_AWSMachineTemplateList(a.(*AWSMachineTemplateList), b.(*v1alpha4.AWSMachineTemplateList), scope)
 	}); err != nil {
@@ -299,11 +269,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_Bastion_To_v1alpha3_Bastion(a.(*v1alpha4.Bastion), b.(*Bastion), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*BuildParams)(nil), (*v1alpha4.BuildParams)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_BuildParams_To_v1alpha4_BuildParams(a.(*BuildParams), b.(*v1alpha4.BuildParams), scope)
 	}); err != nil {
@@ -339,21 +304,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.ClassicELB)(nil), (*ClassicELB)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(a.(*v1alpha4.ClassicELB), b.(*ClassicELB), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ClassicELBAttributes)(nil), (*v1alpha4.ClassicELBAttributes)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ClassicELBAttributes_To_v1alpha4_ClassicELBAttributes(a.(*ClassicELBAttributes), b.(*v1alpha4.ClassicELBAttributes), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.ClassicELBAttributes)(nil), (*ClassicELBAttributes)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(a.(*v1alpha4.ClassicELBAttributes), b.(*ClassicELBAttributes), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ClassicELBHealthCheck)(nil), (*v1alpha4.ClassicELBHealthCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ClassicELBHealthCheck_To_v1alpha4_ClassicELBHealthCheck(a.(*ClassicELBHealthCheck), b.(*v1alpha4.ClassicELBHealthCheck), scope)
 	}); err != nil {
@@ -414,21 +369,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_Network_To_v1alpha3_Network(a.(*v1alpha4.Network), b.(*Network), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NetworkSpec)(nil), (*v1alpha4.NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(a.(*NetworkSpec), b.(*v1alpha4.NetworkSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.NetworkSpec)(nil), (*NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(a.(*v1alpha4.NetworkSpec), b.(*NetworkSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*RouteTable)(nil), (*v1alpha4.RouteTable)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_RouteTable_To_v1alpha4_RouteTable(a.(*RouteTable), b.(*v1alpha4.RouteTable), scope)
 	}); err != nil {
@@ -454,31 +399,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.SpotMarketOptions)(nil), (*SpotMarketOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(a.(*v1alpha4.SpotMarketOptions), b.(*SpotMarketOptions), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*SubnetSpec)(nil), (*v1alpha4.SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_SubnetSpec_To_v1alpha4_SubnetSpec(a.(*SubnetSpec), b.(*v1alpha4.SubnetSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.SubnetSpec)(nil), (*SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(a.(*v1alpha4.SubnetSpec), b.(*SubnetSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VPCSpec)(nil), (*v1alpha4.VPCSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VPCSpec_To_v1alpha4_VPCSpec(a.(*VPCSpec), b.(*v1alpha4.VPCSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.VPCSpec)(nil), (*VPCSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(a.(*v1alpha4.VPCSpec), b.(*VPCSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Volume)(nil), (*v1alpha4.Volume)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Volume_To_v1alpha4_Volume(a.(*Volume), b.(*v1alpha4.Volume), scope)
 	}); err != nil {
@@ -514,21 +444,91 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSClusterIdentitySpec)(nil), (*AWSClusterIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(a.(*v1alpha4.AWSClusterIdentitySpec), b.(*AWSClusterIdentitySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSClusterSpec)(nil), (*AWSClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(a.(*v1alpha4.AWSClusterSpec), b.(*AWSClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha4.AWSClusterStaticIdentitySpec)(nil), (*AWSClusterStaticIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_AWSClusterStaticIdentitySpec_To_v1alpha3_AWSClusterStaticIdentitySpec(a.(*v1alpha4.AWSClusterStaticIdentitySpec), b.(*AWSClusterStaticIdentitySpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSClusterStatus)(nil), (*AWSClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(a.(*v1alpha4.AWSClusterStatus), b.(*AWSClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSLoadBalancerSpec)(nil), (*AWSLoadBalancerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(a.(*v1alpha4.AWSLoadBalancerSpec), b.(*AWSLoadBalancerSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha4.AWSMachineSpec)(nil), (*AWSMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(a.(*v1alpha4.AWSMachineSpec), b.(*AWSMachineSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSMachineStatus)(nil), (*AWSMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(a.(*v1alpha4.AWSMachineStatus), b.(*AWSMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSMachineTemplate)(nil), (*AWSMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(a.(*v1alpha4.AWSMachineTemplate), b.(*AWSMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.Bastion)(nil), (*Bastion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Bastion_To_v1alpha3_Bastion(a.(*v1alpha4.Bastion), b.(*Bastion), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.ClassicELB)(nil), (*ClassicELB)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(a.(*v1alpha4.ClassicELB), b.(*ClassicELB), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.ClassicELBAttributes)(nil), (*ClassicELBAttributes)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(a.(*v1alpha4.ClassicELBAttributes), b.(*ClassicELBAttributes), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha4.Instance)(nil), (*Instance)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_Instance_To_v1alpha3_Instance(a.(*v1alpha4.Instance), b.(*Instance), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.Network)(nil), (*Network)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Network_To_v1alpha3_Network(a.(*v1alpha4.Network), b.(*Network), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.NetworkSpec)(nil), (*NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(a.(*v1alpha4.NetworkSpec), b.(*NetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.SpotMarketOptions)(nil), (*SpotMarketOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(a.(*v1alpha4.SpotMarketOptions), b.(*SpotMarketOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.SubnetSpec)(nil), (*SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(a.(*v1alpha4.SubnetSpec), b.(*SubnetSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.VPCSpec)(nil), (*VPCSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(a.(*v1alpha4.VPCSpec), b.(*VPCSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -592,7 +592,17 @@ func Convert_v1alpha4_AWSClusterControllerIdentity_To_v1alpha3_AWSClusterControl
 
 func autoConvert_v1alpha3_AWSClusterControllerIdentityList_To_v1alpha4_AWSClusterControllerIdentityList(in *AWSClusterControllerIdentityList, out *v1alpha4.AWSClusterControllerIdentityList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.AWSClusterControllerIdentity)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.AWSClusterControllerIdentity, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_AWSClusterControllerIdentity_To_v1alpha4_AWSClusterControllerIdentity(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -603,7 +613,17 @@ func Convert_v1alpha3_AWSClusterControllerIdentityList_To_v1alpha4_AWSClusterCon
 
 func autoConvert_v1alpha4_AWSClusterControllerIdentityList_To_v1alpha3_AWSClusterControllerIdentityList(in *v1alpha4.AWSClusterControllerIdentityList, out *AWSClusterControllerIdentityList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]AWSClusterControllerIdentity)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AWSClusterControllerIdentity, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_AWSClusterControllerIdentity_To_v1alpha3_AWSClusterControllerIdentity(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -648,14 +668,11 @@ func Convert_v1alpha3_AWSClusterIdentitySpec_To_v1alpha4_AWSClusterIdentitySpec(
 
 func autoConvert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(in *v1alpha4.AWSClusterIdentitySpec, out *AWSClusterIdentitySpec, s conversion.Scope) error {
 	out.AllowedNamespaces = (*AllowedNamespaces)(unsafe.Pointer(in.AllowedNamespaces))
+	// WARNING: in.CABundle requires manual conversion: does not exist in peer-type
+	// WARNING: in.Proxy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec is an autogenerated conversion function.
-func Convert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(in *v1alpha4.AWSClusterIdentitySpec, out *AWSClusterIdentitySpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSClusterIdentitySpec_To_v1alpha3_AWSClusterIdentitySpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSClusterList_To_v1alpha4_AWSClusterList(in *AWSClusterList, out *v1alpha4.AWSClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -726,7 +743,17 @@ func Convert_v1alpha4_AWSClusterRoleIdentity_To_v1alpha3_AWSClusterRoleIdentity(
 
 func autoConvert_v1alpha3_AWSClusterRoleIdentityList_To_v1alpha4_AWSClusterRoleIdentityList(in *AWSClusterRoleIdentityList, out *v1alpha4.AWSClusterRoleIdentityList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.AWSClusterRoleIdentity)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.AWSClusterRoleIdentity, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_AWSClusterRoleIdentity_To_v1alpha4_AWSClusterRoleIdentity(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -737,7 +764,17 @@ func Convert_v1alpha3_AWSClusterRoleIdentityList_To_v1alpha4_AWSClusterRoleIdent
 
 func autoConvert_v1alpha4_AWSClusterRoleIdentityList_To_v1alpha3_AWSClusterRoleIdentityList(in *v1alpha4.AWSClusterRoleIdentityList, out *AWSClusterRoleIdentityList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]AWSClusterRoleIdentity)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AWSClusterRoleIdentity, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_AWSClusterRoleIdentity_To_v1alpha3_AWSClusterRoleIdentity(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -790,7 +827,15 @@ func autoConvert_v1alpha3_AWSClusterSpec_To_v1alpha4_AWSClusterSpec(in *AWSClust
 		return err
 	}
 	out.AdditionalTags = *(*v1alpha4.Tags)(unsafe.Pointer(&in.AdditionalTags))
-	out.ControlPlaneLoadBalancer = (*v1alpha4.AWSLoadBalancerSpec)(unsafe.Pointer(in.ControlPlaneLoadBalancer))
+	if in.ControlPlaneLoadBalancer != nil {
+		in, out := &in.ControlPlaneLoadBalancer, &out.ControlPlaneLoadBalancer
+		*out = new(v1alpha4.AWSLoadBalancerSpec)
+		if err := Convert_v1alpha3_AWSLoadBalancerSpec_To_v1alpha4_AWSLoadBalancerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ControlPlaneLoadBalancer = nil
+	}
 	out.ImageLookupFormat = in.ImageLookupFormat
 	out.ImageLookupOrg = in.ImageLookupOrg
 	out.ImageLookupBaseOS = in.ImageLookupBaseOS
@@ -816,7 +861,16 @@ func autoConvert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in *v1alpha4
 		return err
 	}
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
-	out.ControlPlaneLoadBalancer = (*AWSLoadBalancerSpec)(unsafe.Pointer(in.ControlPlaneLoadBalancer))
+	if in.ControlPlaneLoadBalancer != nil {
+		in, out := &in.ControlPlaneLoadBalancer, &out.ControlPlaneLoadBalancer
+		*out = new(AWSLoadBalancerSpec)
+		if err := Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ControlPlaneLoadBalancer = nil
+	}
+	// WARNING: in.ControlPlaneInstanceType requires manual conversion: does not exist in peer-type
 	out.ImageLookupFormat = in.ImageLookupFormat
 	out.ImageLookupOrg = in.ImageLookupOrg
 	out.ImageLookupBaseOS = in.ImageLookupBaseOS
@@ -824,14 +878,17 @@ func autoConvert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in *v1alpha4
 		return err
 	}
 	out.IdentityRef = (*AWSIdentityReference)(unsafe.Pointer(in.IdentityRef))
+	// WARNING: in.S3Bucket requires manual conversion: does not exist in peer-type
+	// WARNING: in.ECRPullThroughCache requires manual conversion: does not exist in peer-type
+	// WARNING: in.OperationTimeouts requires manual conversion: does not exist in peer-type
+	// WARNING: in.RequeueIntervals requires manual conversion: does not exist in peer-type
+	// WARNING: in.Karpenter requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHKeyPair requires manual conversion: does not exist in peer-type
+	// WARNING: in.MachineDefaults requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeletionPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec is an autogenerated conversion function.
-func Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in *v1alpha4.AWSClusterSpec, out *AWSClusterSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSClusterStaticIdentity_To_v1alpha4_AWSClusterStaticIdentity(in *AWSClusterStaticIdentity, out *v1alpha4.AWSClusterStaticIdentity, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_AWSClusterStaticIdentitySpec_To_v1alpha4_AWSClusterStaticIdentitySpec(&in.Spec, &out.Spec, s); err != nil {
@@ -956,14 +1013,13 @@ func autoConvert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(in *v1al
 		out.Bastion = nil
 	}
 	out.Conditions = *(*apiv1alpha3.Conditions)(unsafe.Pointer(&in.Conditions))
+	// WARNING: in.Karpenter requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bastions requires manual conversion: does not exist in peer-type
+	// WARNING: in.BastionLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHKeyPair requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus is an autogenerated conversion function.
-func Convert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(in *v1alpha4.AWSClusterStatus, out *AWSClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSClusterStatus_To_v1alpha3_AWSClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSIdentityReference_To_v1alpha4_AWSIdentityReference(in *AWSIdentityReference, out *v1alpha4.AWSIdentityReference, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Kind = v1alpha4.AWSIdentityKind(in.Kind)
@@ -1004,14 +1060,16 @@ func autoConvert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in
 	out.CrossZoneLoadBalancing = in.CrossZoneLoadBalancing
 	out.Subnets = *(*[]string)(unsafe.Pointer(&in.Subnets))
 	out.AdditionalSecurityGroups = *(*[]string)(unsafe.Pointer(&in.AdditionalSecurityGroups))
+	// WARNING: in.AllowedCIDRBlocks requires manual conversion: does not exist in peer-type
+	// WARNING: in.InternalLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.IdleTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConnectionDrainingTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProxyProtocol requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNSCheck requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstancePort requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec is an autogenerated conversion function.
-func Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in *v1alpha4.AWSLoadBalancerSpec, out *AWSLoadBalancerSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSMachine_To_v1alpha4_AWSMachine(in *AWSMachine, out *v1alpha4.AWSMachine, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_AWSMachineSpec_To_v1alpha4_AWSMachineSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1110,7 +1168,15 @@ func autoConvert_v1alpha3_AWSMachineSpec_To_v1alpha4_AWSMachineSpec(in *AWSMachi
 	if err := Convert_v1alpha3_CloudInit_To_v1alpha4_CloudInit(&in.CloudInit, &out.CloudInit, s); err != nil {
 		return err
 	}
-	out.SpotMarketOptions = (*v1alpha4.SpotMarketOptions)(unsafe.Pointer(in.SpotMarketOptions))
+	if in.SpotMarketOptions != nil {
+		in, out := &in.SpotMarketOptions, &out.SpotMarketOptions
+		*out = new(v1alpha4.SpotMarketOptions)
+		if err := Convert_v1alpha3_SpotMarketOptions_To_v1alpha4_SpotMarketOptions(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SpotMarketOptions = nil
+	}
 	out.Tenancy = in.Tenancy
 	return nil
 }
@@ -1130,6 +1196,9 @@ func autoConvert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in *v1alpha4
 	out.ImageLookupOrg = in.ImageLookupOrg
 	out.ImageLookupBaseOS = in.ImageLookupBaseOS
 	out.InstanceType = in.InstanceType
+	// WARNING: in.FallbackInstanceTypes requires manual conversion: does not exist in peer-type
+	// WARNING: in.GPU requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bottlerocket requires manual conversion: does not exist in peer-type
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
 	out.IAMInstanceProfile = in.IAMInstanceProfile
 	out.PublicIP = (*bool)(unsafe.Pointer(in.PublicIP))
@@ -1140,12 +1209,30 @@ func autoConvert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in *v1alpha4
 	out.RootVolume = (*Volume)(unsafe.Pointer(in.RootVolume))
 	out.NonRootVolumes = *(*[]Volume)(unsafe.Pointer(&in.NonRootVolumes))
 	out.NetworkInterfaces = *(*[]string)(unsafe.Pointer(&in.NetworkInterfaces))
+	// WARNING: in.NetworkInterfaceConfigs requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrivateIPFromPool requires manual conversion: does not exist in peer-type
 	out.UncompressedUserData = (*bool)(unsafe.Pointer(in.UncompressedUserData))
 	if err := Convert_v1alpha4_CloudInit_To_v1alpha3_CloudInit(&in.CloudInit, &out.CloudInit, s); err != nil {
 		return err
 	}
-	out.SpotMarketOptions = (*SpotMarketOptions)(unsafe.Pointer(in.SpotMarketOptions))
+	if in.SpotMarketOptions != nil {
+		in, out := &in.SpotMarketOptions, &out.SpotMarketOptions
+		*out = new(SpotMarketOptions)
+		if err := Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SpotMarketOptions = nil
+	}
 	out.Tenancy = in.Tenancy
+	// WARNING: in.HostResourceGroupARN requires manual conversion: does not exist in peer-type
+	// WARNING: in.LicenseConfigurationARNs requires manual conversion: does not exist in peer-type
+	// WARNING: in.CPUOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceMetadataOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedIAMInstanceProfile requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScheduledEventPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.UserDataChangePolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1168,19 +1255,19 @@ func Convert_v1alpha3_AWSMachineStatus_To_v1alpha4_AWSMachineStatus(in *AWSMachi
 func autoConvert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(in *v1alpha4.AWSMachineStatus, out *AWSMachineStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Interruptible = in.Interruptible
+	// WARNING: in.SpotLaunchFailures requires manual conversion: does not exist in peer-type
+	// WARNING: in.OnDemandFallback requires manual conversion: does not exist in peer-type
 	out.Addresses = *(*[]apiv1alpha3.MachineAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.Instance requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScheduledEvents requires manual conversion: does not exist in peer-type
+	// WARNING: in.RightSizing requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*apiv1alpha3.Conditions)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
-// Convert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus is an autogenerated conversion function.
-func Convert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(in *v1alpha4.AWSMachineStatus, out *AWSMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSMachineStatus_To_v1alpha3_AWSMachineStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSMachineTemplate_To_v1alpha4_AWSMachineTemplate(in *AWSMachineTemplate, out *v1alpha4.AWSMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_AWSMachineTemplateSpec_To_v1alpha4_AWSMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1199,14 +1286,10 @@ func autoConvert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(in *
 	if err := Convert_v1alpha4_AWSMachineTemplateSpec_To_v1alpha3_AWSMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	// WARNING: in.Status requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate is an autogenerated conversion function.
-func Convert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(in *v1alpha4.AWSMachineTemplate, out *AWSMachineTemplate, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSMachineTemplate_To_v1alpha3_AWSMachineTemplate(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSMachineTemplateList_To_v1alpha4_AWSMachineTemplateList(in *AWSMachineTemplateList, out *v1alpha4.AWSMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1391,14 +1474,10 @@ func autoConvert_v1alpha4_Bastion_To_v1alpha3_Bastion(in *v1alpha4.Bastion, out
 	out.AllowedCIDRBlocks = *(*[]string)(unsafe.Pointer(&in.AllowedCIDRBlocks))
 	out.InstanceType = in.InstanceType
 	out.AMI = in.AMI
+	// WARNING: in.AutoScaling requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_Bastion_To_v1alpha3_Bastion is an autogenerated conversion function.
-func Convert_v1alpha4_Bastion_To_v1alpha3_Bastion(in *v1alpha4.Bastion, out *Bastion, s conversion.Scope) error {
-	return autoConvert_v1alpha4_Bastion_To_v1alpha3_Bastion(in, out, s)
-}
-
 func autoConvert_v1alpha3_BuildParams_To_v1alpha4_BuildParams(in *BuildParams, out *v1alpha4.BuildParams, s conversion.Scope) error {
 	out.Lifecycle = v1alpha4.ResourceLifecycle(in.Lifecycle)
 	out.ClusterName = in.ClusterName
@@ -1509,14 +1588,11 @@ func autoConvert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(in *v1alpha4.Classic
 		return err
 	}
 	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.Instances requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProxyProtocol requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB is an autogenerated conversion function.
-func Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(in *v1alpha4.ClassicELB, out *ClassicELB, s conversion.Scope) error {
-	return autoConvert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(in, out, s)
-}
-
 func autoConvert_v1alpha3_ClassicELBAttributes_To_v1alpha4_ClassicELBAttributes(in *ClassicELBAttributes, out *v1alpha4.ClassicELBAttributes, s conversion.Scope) error {
 	out.IdleTimeout = time.Duration(in.IdleTimeout)
 	out.CrossZoneLoadBalancing = in.CrossZoneLoadBalancing
@@ -1531,14 +1607,10 @@ func Convert_v1alpha3_ClassicELBAttributes_To_v1alpha4_ClassicELBAttributes(in *
 func autoConvert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(in *v1alpha4.ClassicELBAttributes, out *ClassicELBAttributes, s conversion.Scope) error {
 	out.IdleTimeout = time.Duration(in.IdleTimeout)
 	out.CrossZoneLoadBalancing = in.CrossZoneLoadBalancing
+	// WARNING: in.ConnectionDrainingTimeout requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes is an autogenerated conversion function.
-func Convert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(in *v1alpha4.ClassicELBAttributes, out *ClassicELBAttributes, s conversion.Scope) error {
-	return autoConvert_v1alpha4_ClassicELBAttributes_To_v1alpha3_ClassicELBAttributes(in, out, s)
-}
-
 func autoConvert_v1alpha3_ClassicELBHealthCheck_To_v1alpha4_ClassicELBHealthCheck(in *ClassicELBHealthCheck, out *v1alpha4.ClassicELBHealthCheck, s conversion.Scope) error {
 	out.Target = in.Target
 	out.Interval = time.Duration(in.Interval)
@@ -1691,7 +1763,15 @@ func autoConvert_v1alpha3_Instance_To_v1alpha4_Instance(in *Instance, out *v1alp
 	out.NetworkInterfaces = *(*[]string)(unsafe.Pointer(&in.NetworkInterfaces))
 	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
 	out.AvailabilityZone = in.AvailabilityZone
-	out.SpotMarketOptions = (*v1alpha4.SpotMarketOptions)(unsafe.Pointer(in.SpotMarketOptions))
+	if in.SpotMarketOptions != nil {
+		in, out := &in.SpotMarketOptions, &out.SpotMarketOptions
+		*out = new(v1alpha4.SpotMarketOptions)
+		if err := Convert_v1alpha3_SpotMarketOptions_To_v1alpha4_SpotMarketOptions(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SpotMarketOptions = nil
+	}
 	out.Tenancy = in.Tenancy
 	return nil
 }
@@ -1704,6 +1784,7 @@ func Convert_v1alpha3_Instance_To_v1alpha4_Instance(in *Instance, out *v1alpha4.
 func autoConvert_v1alpha4_Instance_To_v1alpha3_Instance(in *v1alpha4.Instance, out *Instance, s conversion.Scope) error {
 	out.ID = in.ID
 	out.State = InstanceState(in.State)
+	// WARNING: in.StateReason requires manual conversion: does not exist in peer-type
 	out.Type = in.Type
 	out.SubnetID = in.SubnetID
 	out.ImageID = in.ImageID
@@ -1719,11 +1800,29 @@ func autoConvert_v1alpha4_Instance_To_v1alpha3_Instance(in *v1alpha4.Instance, o
 	out.RootVolume = (*Volume)(unsafe.Pointer(in.RootVolume))
 	out.NonRootVolumes = *(*[]Volume)(unsafe.Pointer(&in.NonRootVolumes))
 	out.NetworkInterfaces = *(*[]string)(unsafe.Pointer(&in.NetworkInterfaces))
+	// WARNING: in.NetworkInterfaceConfigs requires manual conversion: does not exist in peer-type
 	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
 	out.AvailabilityZone = in.AvailabilityZone
-	out.SpotMarketOptions = (*SpotMarketOptions)(unsafe.Pointer(in.SpotMarketOptions))
+	if in.SpotMarketOptions != nil {
+		in, out := &in.SpotMarketOptions, &out.SpotMarketOptions
+		*out = new(SpotMarketOptions)
+		if err := Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SpotMarketOptions = nil
+	}
 	out.Tenancy = in.Tenancy
+	// WARNING: in.HostResourceGroupARN requires manual conversion: does not exist in peer-type
+	// WARNING: in.LicenseConfigurationARNs requires manual conversion: does not exist in peer-type
+	// WARNING: in.CPUOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceMetadataOptions requires manual conversion: does not exist in peer-type
 	// WARNING: in.VolumeIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.LaunchTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.Lifecycle requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotInstanceRequestID requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostID requires manual conversion: does not exist in peer-type
+	// WARNING: in.PrimaryNetworkInterfaceID requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1745,19 +1844,31 @@ func autoConvert_v1alpha4_Network_To_v1alpha3_Network(in *v1alpha4.Network, out
 	if err := Convert_v1alpha4_ClassicELB_To_v1alpha3_ClassicELB(&in.APIServerELB, &out.APIServerELB, s); err != nil {
 		return err
 	}
+	// WARNING: in.InternalAPIServerELB requires manual conversion: does not exist in peer-type
+	// WARNING: in.SharedVPC requires manual conversion: does not exist in peer-type
+	// WARNING: in.InternetGatewayID requires manual conversion: does not exist in peer-type
+	// WARNING: in.NatGateways requires manual conversion: does not exist in peer-type
+	// WARNING: in.RouteTables requires manual conversion: does not exist in peer-type
+	// WARNING: in.DiscoveredSubnets requires manual conversion: does not exist in peer-type
+	// WARNING: in.Firewall requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_Network_To_v1alpha3_Network is an autogenerated conversion function.
-func Convert_v1alpha4_Network_To_v1alpha3_Network(in *v1alpha4.Network, out *Network, s conversion.Scope) error {
-	return autoConvert_v1alpha4_Network_To_v1alpha3_Network(in, out, s)
-}
-
 func autoConvert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(in *NetworkSpec, out *v1alpha4.NetworkSpec, s conversion.Scope) error {
 	if err := Convert_v1alpha3_VPCSpec_To_v1alpha4_VPCSpec(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
-	out.Subnets = *(*v1alpha4.Subnets)(unsafe.Pointer(&in.Subnets))
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make(v1alpha4.Subnets, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_SubnetSpec_To_v1alpha4_SubnetSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Subnets = nil
+	}
 	out.CNI = (*v1alpha4.CNISpec)(unsafe.Pointer(in.CNI))
 	out.SecurityGroupOverrides = *(*map[v1alpha4.SecurityGroupRole]string)(unsafe.Pointer(&in.SecurityGroupOverrides))
 	return nil
@@ -1772,17 +1883,30 @@ func autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.Netwo
 	if err := Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(&in.VPC, &out.VPC, s); err != nil {
 		return err
 	}
-	out.Subnets = *(*Subnets)(unsafe.Pointer(&in.Subnets))
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make(Subnets, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Subnets = nil
+	}
 	out.CNI = (*CNISpec)(unsafe.Pointer(in.CNI))
 	out.SecurityGroupOverrides = *(*map[SecurityGroupRole]string)(unsafe.Pointer(&in.SecurityGroupOverrides))
+	// WARNING: in.SecurityGroupOverrideTracking requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowNodeToNodeTraffic requires manual conversion: does not exist in peer-type
+	// WARNING: in.SecurityGroupPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Firewall requires manual conversion: does not exist in peer-type
+	// WARNING: in.GatewayLoadBalancerEndpointRoutes requires manual conversion: does not exist in peer-type
+	// WARNING: in.Routes requires manual conversion: does not exist in peer-type
+	// WARNING: in.ElasticIPPool requires manual conversion: does not exist in peer-type
+	// WARNING: in.NatGatewayAllocationIDs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec is an autogenerated conversion function.
-func Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.NetworkSpec, out *NetworkSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_RouteTable_To_v1alpha4_RouteTable(in *RouteTable, out *v1alpha4.RouteTable, s conversion.Scope) error {
 	out.ID = in.ID
 	return nil
@@ -1841,14 +1965,10 @@ func Convert_v1alpha3_SpotMarketOptions_To_v1alpha4_SpotMarketOptions(in *SpotMa
 
 func autoConvert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(in *v1alpha4.SpotMarketOptions, out *SpotMarketOptions, s conversion.Scope) error {
 	out.MaxPrice = (*string)(unsafe.Pointer(in.MaxPrice))
+	// WARNING: in.FallbackToOnDemand requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions is an autogenerated conversion function.
-func Convert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(in *v1alpha4.SpotMarketOptions, out *SpotMarketOptions, s conversion.Scope) error {
-	return autoConvert_v1alpha4_SpotMarketOptions_To_v1alpha3_SpotMarketOptions(in, out, s)
-}
-
 func autoConvert_v1alpha3_SubnetSpec_To_v1alpha4_SubnetSpec(in *SubnetSpec, out *v1alpha4.SubnetSpec, s conversion.Scope) error {
 	out.ID = in.ID
 	out.CidrBlock = in.CidrBlock
@@ -1868,19 +1988,19 @@ func Convert_v1alpha3_SubnetSpec_To_v1alpha4_SubnetSpec(in *SubnetSpec, out *v1a
 func autoConvert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in *v1alpha4.SubnetSpec, out *SubnetSpec, s conversion.Scope) error {
 	out.ID = in.ID
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.IPv6CidrBlock requires manual conversion: does not exist in peer-type
 	out.AvailabilityZone = in.AvailabilityZone
+	// WARNING: in.AvailabilityZoneID requires manual conversion: does not exist in peer-type
 	out.IsPublic = in.IsPublic
+	// WARNING: in.IsPublicOverride requires manual conversion: does not exist in peer-type
 	out.RouteTableID = (*string)(unsafe.Pointer(in.RouteTableID))
+	// WARNING: in.UnmanagedRouteTableID requires manual conversion: does not exist in peer-type
 	out.NatGatewayID = (*string)(unsafe.Pointer(in.NatGatewayID))
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
+	// WARNING: in.Routes requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec is an autogenerated conversion function.
-func Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in *v1alpha4.SubnetSpec, out *SubnetSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_VPCSpec_To_v1alpha4_VPCSpec(in *VPCSpec, out *v1alpha4.VPCSpec, s conversion.Scope) error {
 	out.ID = in.ID
 	out.CidrBlock = in.CidrBlock
@@ -1900,17 +2020,13 @@ func autoConvert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in *v1alpha4.VPCSpec, out
 	out.ID = in.ID
 	out.CidrBlock = in.CidrBlock
 	out.InternetGatewayID = (*string)(unsafe.Pointer(in.InternetGatewayID))
+	// WARNING: in.IPv6 requires manual conversion: does not exist in peer-type
 	out.Tags = *(*Tags)(unsafe.Pointer(&in.Tags))
 	out.AvailabilityZoneUsageLimit = (*int)(unsafe.Pointer(in.AvailabilityZoneUsageLimit))
 	out.AvailabilityZoneSelection = (*AZSelectionScheme)(unsafe.Pointer(in.AvailabilityZoneSelection))
 	return nil
 }
 
-// Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec is an autogenerated conversion function.
-func Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in *v1alpha4.VPCSpec, out *VPCSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_Volume_To_v1alpha4_Volume(in *Volume, out *v1alpha4.Volume, s conversion.Scope) error {
 	out.DeviceName = in.DeviceName
 	out.Size = in.Size
@@ -26,8 +26,18 @@ const (
 	// removing it from the apiserver.
 	ClusterFinalizer = "awscluster.infrastructure.cluster.x-k8s.io"
 
+	// EstimatedHourlyCostAnnotation is the annotation on AWSClusters and AWSMachinePools holding the approximate
+	// on-demand hourly cost of their AWS resources in US dollars, set when the CostEstimation feature gate is enabled.
+	EstimatedHourlyCostAnnotation = "aws.cluster.x-k8s.io/estimated-hourly-cost"
+
 	// AWSClusterControllerIdentityName is the name of the AWSClusterControllerIdentity singleton.
 	AWSClusterControllerIdentityName = "default"
+
+	// UnmanagedLoadBalancerSettingsAnnotation lists, comma separated, the settings of the API server load balancers
+	// which are only set when the load balancers are created, so that they can be changed out of band. The other
+	// settings are restored on each reconciliation. The settings are attributes, health-check, proxy-protocol,
+	// subnets, security-groups and listeners.
+	UnmanagedLoadBalancerSettingsAnnotation = "aws.cluster.x-k8s.io/unmanaged-load-balancer-settings"
 )
 
 // AWSClusterSpec defines the desired state of AWSCluster
@@ -55,6 +65,11 @@ type AWSClusterSpec struct {
 	// +optional
 	ControlPlaneLoadBalancer *AWSLoadBalancerSpec `json:"controlPlaneLoadBalancer,omitempty"`
 
+	// ControlPlaneInstanceType is the instance type of the control plane machines. When set, the
+	// availability zones where it is not offered are not published as control plane failure domains.
+	// +optional
+	ControlPlaneInstanceType string `json:"controlPlaneInstanceType,omitempty"`
+
 	// ImageLookupFormat is the AMI naming format to look up machine images when
 	// a machine does not specify an AMI. When set, this will be used for all
 	// cluster machines unless a machine specifies a different ImageLookupOrg.
@@ -89,6 +104,47 @@ type AWSClusterSpec struct {
 	// IdentityRef is a reference to a identity to be used when reconciling this cluster
 	// +optional
 	IdentityRef *AWSIdentityReference `json:"identityRef,omitempty"`
+
+	// S3Bucket contains options to configure a supporting S3 bucket for this
+	// cluster, used to store machine bootstrap data.
+	// +optional
+	S3Bucket *S3Bucket `json:"s3Bucket,omitempty"`
+
+	// ECRPullThroughCache configures ECR pull-through cache rules for upstream
+	// registries and uses them as container registry mirrors on nodes.
+	// +optional
+	ECRPullThroughCache *ECRPullThroughCache `json:"ecrPullThroughCache,omitempty"`
+
+	// OperationTimeouts overrides how long the controllers wait for AWS
+	// operations on this cluster's resources to complete.
+	// +optional
+	OperationTimeouts *OperationTimeouts `json:"operationTimeouts,omitempty"`
+
+	// RequeueIntervals overrides how often the controllers check on this
+	// cluster's AWS resources while they are not ready yet.
+	// +optional
+	RequeueIntervals *RequeueIntervals `json:"requeueIntervals,omitempty"`
+
+	// Karpenter provisions the node IAM role, the interruption queue and the discovery tags
+	// needed to run Karpenter on the cluster. Requires the Karpenter feature gate.
+	// +optional
+	Karpenter *Karpenter `json:"karpenter,omitempty"`
+
+	// SSHKeyPair makes the controller create an EC2 key pair for the cluster, and delete it with the
+	// cluster. The bastion host and the AWSMachines which don't set an SSH key name use it. It can't be
+	// set together with SSHKeyName.
+	// +optional
+	SSHKeyPair *SSHKeyPair `json:"sshKeyPair,omitempty"`
+
+	// MachineDefaults are the defaults of the AWSMachines of the cluster, used for the options
+	// which are not set on a machine.
+	// +optional
+	MachineDefaults *MachineDefaults `json:"machineDefaults,omitempty"`
+
+	// DeletionPolicy defines which classes of the AWS resources of the cluster are retained when the
+	// AWSCluster is deleted, for example to keep a VPC which is reused by another cluster.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // AWSIdentityKind defines allowed AWS identity types.
@@ -142,6 +198,35 @@ type Bastion struct {
 	// the AMI will default to one picked out in public space.
 	// +optional
 	AMI string `json:"ami,omitempty"`
+
+	// AutoScaling runs the bastion hosts in an auto scaling group spread across the availability zones of
+	// the public subnets, instead of a single instance. The group replaces the hosts that fail, including
+	// those lost with their availability zone.
+	// +optional
+	AutoScaling *BastionAutoScaling `json:"autoScaling,omitempty"`
+}
+
+// BastionAutoScaling defines the auto scaling group of the bastion hosts.
+type BastionAutoScaling struct {
+	// Replicas is the number of bastion hosts.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// LoadBalancer creates an internet-facing network load balancer which forwards SSH connections to
+	// the bastion hosts, so that they are reachable at a single address which outlives them.
+	// +optional
+	LoadBalancer bool `json:"loadBalancer,omitempty"`
+}
+
+// BastionLoadBalancer describes the network load balancer in front of the bastion hosts.
+type BastionLoadBalancer struct {
+	// ARN is the Amazon Resource Name of the load balancer.
+	ARN string `json:"arn,omitempty"`
+
+	// DNSName is the DNS name of the load balancer.
+	DNSName string `json:"dnsName,omitempty"`
 }
 
 // AWSLoadBalancerSpec defines the desired state of an AWS load balancer.
@@ -171,6 +256,57 @@ type AWSLoadBalancerSpec struct {
 	// This is optional - if not provided new security groups will be created for the load balancer
 	// +optional
 	AdditionalSecurityGroups []string `json:"additionalSecurityGroups,omitempty"`
+
+	// AllowedCIDRBlocks restricts the CIDR blocks that can reach the API server through the load balancer.
+	// The CIDR block of the VPC and, for an internet-facing load balancer, the public IPs of the NAT gateways
+	// of the VPC are always allowed, so that the machines of the cluster can reach the API server.
+	// Defaults to allowing any IPv4 address.
+	// +optional
+	AllowedCIDRBlocks []string `json:"allowedCIDRBlocks,omitempty"`
+
+	// InternalLoadBalancer creates an internal load balancer for the API server in the private subnets of the
+	// cluster, next to the internet-facing one, so that workloads within the VPC reach the API server without
+	// leaving it. Its DNS name is reported in status.network.internalApiServerElb.
+	// Can only be set if the scheme is internet-facing.
+	// +optional
+	InternalLoadBalancer bool `json:"internalLoadBalancer,omitempty"`
+
+	// IdleTimeout is the time that a connection through the load balancer is allowed to be idle
+	// before the load balancer closes it, between 1 second and 4000 seconds.
+	// Defaults to 10 minutes.
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// ConnectionDrainingTimeout enables connection draining, and sets how long the load balancer keeps the
+	// existing connections to an instance open once the instance is deregistered or fails its health check,
+	// up to 3600 seconds. This lets requests in flight complete while control plane machines are replaced.
+	// Connection draining is disabled if not set.
+	// +optional
+	ConnectionDrainingTimeout *metav1.Duration `json:"connectionDrainingTimeout,omitempty"`
+
+	// ProxyProtocol enables version 1 of the PROXY protocol on the connections from the load balancer to the
+	// API server port of the instances, so that the source IP of clients is preserved. Classic load balancers
+	// don't support version 2 of the protocol. The API server doesn't accept the PROXY protocol itself, so it
+	// can only be enabled if a proxy in front of the API server on the control plane instances handles it.
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+
+	// DNSCheck sets how the controller checks that the DNS name of the load balancer is ready before it is
+	// used as the control plane endpoint. Resolve looks the name up from the management cluster, AWS relies on
+	// the load balancer being returned by the ELB API with a DNS name, and None skips the check.
+	// Use AWS or None when the management cluster can't resolve the name, such as for an internal load
+	// balancer in a private hosted zone. Defaults to Resolve.
+	// +kubebuilder:validation:Enum=Resolve;AWS;None
+	// +optional
+	DNSCheck DNSCheck `json:"dnsCheck,omitempty"`
+
+	// InstancePort is the port the API server listens on on the control plane instances, which the load balancer
+	// forwards the connections to its API server port to. Defaults to the API server port of the cluster network
+	// (6443 if not set), which kubeadm binds the API server to unless its local API endpoint sets another port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	InstancePort *int32 `json:"instancePort,omitempty"`
 }
 
 // AWSClusterStatus defines the observed state of AWSCluster
@@ -181,6 +317,19 @@ type AWSClusterStatus struct {
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 	Bastion        *Instance                `json:"bastion,omitempty"`
 	Conditions     clusterv1.Conditions     `json:"conditions,omitempty"`
+	Karpenter      *KarpenterStatus         `json:"karpenter,omitempty"`
+
+	// Bastions are all the bastion hosts of the cluster. Bastion is the first of them.
+	// +optional
+	Bastions []Instance `json:"bastions,omitempty"`
+
+	// BastionLoadBalancer is the network load balancer in front of the bastion hosts, if any.
+	// +optional
+	BastionLoadBalancer *BastionLoadBalancer `json:"bastionLoadBalancer,omitempty"`
+
+	// SSHKeyPair is the EC2 key pair created for the cluster, if any.
+	// +optional
+	SSHKeyPair *SSHKeyPairStatus `json:"sshKeyPair,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -23,6 +23,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,41 +35,55 @@ import (
 var _ = logf.Log.WithName("awscluster-resource")
 
 func (r *AWSCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	namespaceReader = mgr.GetAPIReader()
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awscluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,versions=v1alpha4,name=validation.awscluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awscluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,versions=v1alpha4,name=default.awscluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
-	_ webhook.Validator = &AWSCluster{}
-	_ webhook.Defaulter = &AWSCluster{}
+	_ webhooks.Validator = &AWSCluster{}
+	_ webhook.Defaulter  = &AWSCluster{}
 )
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSCluster) ValidateCreate() error {
+// ValidateCreate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSCluster) ValidateCreate() (webhooks.Warnings, error) {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
 	allErrs = append(allErrs, r.validateSSHKeyName()...)
-
-	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+	allErrs = append(allErrs, validateSSHKeyPair(r.Spec.SSHKeyName, r.Spec.SSHKeyPair, field.NewPath("spec"))...)
+	allErrs = append(allErrs, r.Spec.S3Bucket.Validate()...)
+	allErrs = append(allErrs, r.Spec.ECRPullThroughCache.Validate()...)
+	allErrs = append(allErrs, r.Spec.ControlPlaneLoadBalancer.Validate()...)
+	allErrs = append(allErrs, r.Spec.Karpenter.Validate()...)
+	allErrs = append(allErrs, r.Spec.DeletionPolicy.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.SecurityGroupPolicy.Validate()...)
+	allErrs = append(allErrs, validateNetwork(&r.Spec.NetworkSpec, field.NewPath("spec", "networkSpec"))...)
+	allErrs = append(allErrs, validateNamespaceIdentity(r.Namespace, r.Spec.IdentityRef, field.NewPath("spec", "identityRef"))...)
+	allErrs = append(allErrs, validateStrictCluster(&r.Spec, field.NewPath("spec"))...)
+
+	return clusterSpecWarnings(&r.Spec, field.NewPath("spec")), aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSCluster) ValidateDelete() error {
-	return nil
+// ValidateDelete implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSCluster) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
+// ValidateUpdate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSCluster) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	var allErrs field.ErrorList
 
 	oldC, ok := old.(*AWSCluster)
 	if !ok {
-		return apierrors.NewBadRequest(fmt.Sprintf("expected an AWSCluster but got a %T", old))
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AWSCluster but got a %T", old))
 	}
 
 	if r.Spec.Region != oldC.Spec.Region {
@@ -137,14 +152,49 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
 		)
 	}
 
-	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	if oldC.Spec.S3Bucket != nil && r.Spec.S3Bucket != nil {
+		if oldC.Spec.S3Bucket.Name != r.Spec.S3Bucket.Name {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "s3Bucket", "name"), r.Spec.S3Bucket.Name, "field is immutable"),
+			)
+		}
+		if oldC.Spec.S3Bucket.Unmanaged != r.Spec.S3Bucket.Unmanaged {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "s3Bucket", "unmanaged"), r.Spec.S3Bucket.Unmanaged, "field is immutable"),
+			)
+		}
+		if oldC.Spec.S3Bucket.KeyPrefix != r.Spec.S3Bucket.KeyPrefix {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "s3Bucket", "keyPrefix"), r.Spec.S3Bucket.KeyPrefix, "field is immutable"),
+			)
+		}
+	}
 
-	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+	// The key pair can't be replaced, as the instances which use it would keep the old one.
+	if !reflect.DeepEqual(oldC.Spec.SSHKeyPair, r.Spec.SSHKeyPair) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "sshKeyPair"), r.Spec.SSHKeyPair, "field is immutable"),
+		)
+	}
+
+	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	allErrs = append(allErrs, validateSSHKeyPair(r.Spec.SSHKeyName, r.Spec.SSHKeyPair, field.NewPath("spec"))...)
+	allErrs = append(allErrs, r.Spec.S3Bucket.Validate()...)
+	allErrs = append(allErrs, r.Spec.ECRPullThroughCache.Validate()...)
+	allErrs = append(allErrs, r.Spec.ControlPlaneLoadBalancer.Validate()...)
+	allErrs = append(allErrs, r.Spec.Karpenter.Validate()...)
+	allErrs = append(allErrs, r.Spec.DeletionPolicy.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.SecurityGroupPolicy.Validate()...)
+	allErrs = append(allErrs, validateStrictCluster(&r.Spec, field.NewPath("spec"))...)
+
+	return clusterSpecWarnings(&r.Spec, field.NewPath("spec")), aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 // Default satisfies the defaulting webhook interface.
 func (r *AWSCluster) Default() {
+	defaultNamespaceIdentity(r.Namespace, &r.Spec)
 	SetDefaultsAWSClusterSpec(&r.Spec)
+	r.defaultSubnetLayout()
 }
 
 func (r *AWSCluster) validateSSHKeyName() field.ErrorList {
@@ -19,12 +19,17 @@ package v1alpha4
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	utildefaulting "sigs.k8s.io/cluster-api-provider-aws/test/helpers/defaulting"
 )
 
 func TestAWSClusterDefault(t *testing.T) {
@@ -232,6 +237,48 @@ func TestAWSCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "S3 bucket name is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "old-bucket"},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "new-bucket"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "S3 bucket key prefix is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "bucket", KeyPrefix: "old"},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "bucket", KeyPrefix: "new"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "S3 bucket versioning is mutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "bucket"},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{Name: "bucket", Versioning: true},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -455,6 +502,254 @@ func TestAWSCluster_ValidateAllowedCIDRBlocks(t *testing.T) {
 	}
 }
 
+func TestAWSCluster_ValidateS3Bucket(t *testing.T) {
+	tests := []struct {
+		name    string
+		awsc    *AWSCluster
+		wantErr bool
+	}{
+		{
+			name: "allow hardened bucket",
+			awsc: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{
+						Name:       "cluster-bucket",
+						Versioning: true,
+						Encryption: &S3BucketEncryption{KMSKeyID: "alias/cluster"},
+						Lifecycle: &S3BucketLifecycle{
+							ExpirationDays:                  7,
+							NoncurrentVersionExpirationDays: pointer.Int32(1),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "noncurrent version expiration not allowed without versioning",
+			awsc: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{
+						Name: "cluster-bucket",
+						Lifecycle: &S3BucketLifecycle{
+							ExpirationDays:                  7,
+							NoncurrentVersionExpirationDays: pointer.Int32(1),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow unmanaged bucket with key prefix",
+			awsc: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{
+						Name:      "shared-bucket",
+						Unmanaged: true,
+						KeyPrefix: "team-a/cluster",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "hardening options not allowed for unmanaged bucket",
+			awsc: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{
+						Name:       "shared-bucket",
+						Unmanaged:  true,
+						Versioning: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bucket name",
+			awsc: &AWSCluster{
+				Spec: AWSClusterSpec{
+					S3Bucket: &S3Bucket{
+						Name: "Invalid_Bucket",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			cluster := tt.awsc.DeepCopy()
+			cluster.ObjectMeta = metav1.ObjectMeta{
+				GenerateName: "cluster-",
+				Namespace:    "default",
+			}
+			if err := testEnv.Create(ctx, cluster); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateS3Bucket() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateECRPullThroughCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   *ECRPullThroughCache
+		wantErr bool
+	}{
+		{
+			name: "allow rules for distinct upstream registries",
+			cache: &ECRPullThroughCache{
+				Rules: []ECRPullThroughCacheRule{
+					{UpstreamRegistryURL: "public.ecr.aws", ECRRepositoryPrefix: "ecr-public"},
+					{UpstreamRegistryURL: "quay.io", ECRRepositoryPrefix: "quay"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "upstream registry URL with scheme not allowed",
+			cache: &ECRPullThroughCache{
+				Rules: []ECRPullThroughCacheRule{
+					{UpstreamRegistryURL: "https://quay.io", ECRRepositoryPrefix: "quay"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate repository prefix not allowed",
+			cache: &ECRPullThroughCache{
+				Rules: []ECRPullThroughCacheRule{
+					{UpstreamRegistryURL: "public.ecr.aws", ECRRepositoryPrefix: "mirror"},
+					{UpstreamRegistryURL: "quay.io", ECRRepositoryPrefix: "mirror"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid repository prefix",
+			cache: &ECRPullThroughCache{
+				Rules: []ECRPullThroughCacheRule{
+					{UpstreamRegistryURL: "quay.io", ECRRepositoryPrefix: "Quay"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			cluster := &AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "cluster-",
+					Namespace:    "default",
+				},
+				Spec: AWSClusterSpec{
+					ECRPullThroughCache: tt.cache,
+				},
+			}
+			if err := testEnv.Create(ctx, cluster); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateECRPullThroughCache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateControlPlaneLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name    string
+		lb      *AWSLoadBalancerSpec
+		wantErr bool
+	}{
+		{
+			name: "allow IPv4 CIDR blocks and security group IDs",
+			lb: &AWSLoadBalancerSpec{
+				AllowedCIDRBlocks:        []string{"192.168.0.0/16", "203.0.113.10/32"},
+				AdditionalSecurityGroups: []string{"sg-0123456789"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid CIDR block not allowed",
+			lb: &AWSLoadBalancerSpec{
+				AllowedCIDRBlocks: []string{"192.168.0.0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "IPv6 CIDR block not allowed",
+			lb: &AWSLoadBalancerSpec{
+				AllowedCIDRBlocks: []string{"2001:db8::/32"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow internal load balancer next to the internet-facing one",
+			lb: &AWSLoadBalancerSpec{
+				InternalLoadBalancer: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "internal load balancer not allowed with internal scheme",
+			lb: &AWSLoadBalancerSpec{
+				Scheme:               &ClassicELBSchemeInternal,
+				InternalLoadBalancer: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow idle and connection draining timeouts within their limits",
+			lb: &AWSLoadBalancerSpec{
+				IdleTimeout:               &metav1.Duration{Duration: 30 * time.Minute},
+				ConnectionDrainingTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+				ProxyProtocol:             true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "idle timeout above 4000 seconds not allowed",
+			lb: &AWSLoadBalancerSpec{
+				IdleTimeout: &metav1.Duration{Duration: 2 * time.Hour},
+			},
+			wantErr: true,
+		},
+		{
+			name: "connection draining timeout below 1 second not allowed",
+			lb: &AWSLoadBalancerSpec{
+				ConnectionDrainingTimeout: &metav1.Duration{Duration: 0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "security group name not allowed",
+			lb: &AWSLoadBalancerSpec{
+				AdditionalSecurityGroups: []string{"corporate-vpn"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			cluster := &AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "cluster-",
+					Namespace:    "default",
+				},
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: tt.lb,
+				},
+			}
+			if err := testEnv.Create(ctx, cluster); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateControlPlaneLoadBalancer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAWSCluster_DefaultAllowedCIDRBlocks(t *testing.T) {
 	g := NewWithT(t)
 	tests := []struct {
@@ -513,3 +808,559 @@ func TestAWSCluster_DefaultAllowedCIDRBlocks(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSCluster_ValidateKarpenter(t *testing.T) {
+	tests := []struct {
+		name           string
+		karpenter      *Karpenter
+		featureEnabled bool
+		wantErr        bool
+	}{
+		{
+			name:           "allow karpenter with the feature gate enabled",
+			karpenter:      &Karpenter{},
+			featureEnabled: true,
+			wantErr:        false,
+		},
+		{
+			name:           "karpenter not allowed with the feature gate disabled",
+			karpenter:      &Karpenter{},
+			featureEnabled: false,
+			wantErr:        true,
+		},
+		{
+			name: "allow additional node policy ARNs",
+			karpenter: &Karpenter{
+				AdditionalNodePolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"},
+			},
+			featureEnabled: true,
+			wantErr:        false,
+		},
+		{
+			name: "additional node policy which is not an ARN not allowed",
+			karpenter: &Karpenter{
+				AdditionalNodePolicyARNs: []string{"AmazonSSMManagedInstanceCore"},
+			},
+			featureEnabled: true,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.Karpenter, tt.featureEnabled)()
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{Karpenter: tt.karpenter}}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateSSHKeyPair(t *testing.T) {
+	tests := []struct {
+		name       string
+		sshKeyName *string
+		keyPair    *SSHKeyPair
+		oldKeyPair *SSHKeyPair
+		wantErr    bool
+	}{
+		{
+			name:    "allow a key pair generated by EC2",
+			keyPair: &SSHKeyPair{},
+			wantErr: false,
+		},
+		{
+			name:       "key pair not allowed together with an SSH key name",
+			sshKeyName: pointer.String("my-key"),
+			keyPair:    &SSHKeyPair{},
+			wantErr:    true,
+		},
+		{
+			name:       "key pair can't be changed",
+			keyPair:    &SSHKeyPair{PublicKeySecretName: "my-public-key"},
+			oldKeyPair: &SSHKeyPair{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{SSHKeyName: tt.sshKeyName, SSHKeyPair: tt.keyPair}}
+			var err error
+			if tt.oldKeyPair != nil {
+				_, err = cluster.ValidateUpdate(&AWSCluster{Spec: AWSClusterSpec{SSHKeyPair: tt.oldKeyPair}})
+			} else {
+				_, err = cluster.ValidateCreate()
+			}
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		deletionPolicy *DeletionPolicy
+		wantErr        bool
+	}{
+		{
+			name:           "allow retaining the network only",
+			deletionPolicy: &DeletionPolicy{Network: ResourceDeletionPolicyRetain},
+			wantErr:        false,
+		},
+		{
+			name:           "allow retaining the S3 bucket only",
+			deletionPolicy: &DeletionPolicy{S3Bucket: ResourceDeletionPolicyRetain},
+			wantErr:        false,
+		},
+		{
+			name: "allow retaining the instances and load balancers with the network",
+			deletionPolicy: &DeletionPolicy{
+				Network:       ResourceDeletionPolicyRetain,
+				LoadBalancers: ResourceDeletionPolicyRetain,
+				Instances:     ResourceDeletionPolicyRetain,
+			},
+			wantErr: false,
+		},
+		{
+			name:           "load balancers can't be retained without the network",
+			deletionPolicy: &DeletionPolicy{LoadBalancers: ResourceDeletionPolicyRetain},
+			wantErr:        true,
+		},
+		{
+			name: "instances can't be retained without the network",
+			deletionPolicy: &DeletionPolicy{
+				Network:   ResourceDeletionPolicyDelete,
+				Instances: ResourceDeletionPolicyRetain,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{DeletionPolicy: tt.deletionPolicy}}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateSecurityGroupPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *SecurityGroupPolicy
+		wantErr bool
+	}{
+		{
+			name: "allow port ranges",
+			policy: &SecurityGroupPolicy{
+				AllowedPorts: []SecurityGroupPortRange{
+					{Protocol: SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443},
+					{Protocol: SecurityGroupProtocolUDP, FromPort: 4789, ToPort: 4789},
+					{Protocol: SecurityGroupProtocolAll, FromPort: -1, ToPort: -1},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty policy not allowed",
+			policy: &SecurityGroupPolicy{
+				AllowedPorts: []SecurityGroupPortRange{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverted port range not allowed",
+			policy: &SecurityGroupPolicy{
+				AllowedPorts: []SecurityGroupPortRange{
+					{Protocol: SecurityGroupProtocolTCP, FromPort: 2380, ToPort: 2379},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "port out of range not allowed",
+			policy: &SecurityGroupPolicy{
+				AllowedPorts: []SecurityGroupPortRange{
+					{Protocol: SecurityGroupProtocolTCP, FromPort: 30000, ToPort: 70000},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			cluster := &AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "cluster-",
+					Namespace:    "default",
+				},
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{SecurityGroupPolicy: tt.policy},
+				},
+			}
+			if err := testEnv.Create(ctx, cluster); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSecurityGroupPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateNetworkFirewall(t *testing.T) {
+	tests := []struct {
+		name     string
+		firewall *NetworkFirewall
+		wantErr  bool
+	}{
+		{
+			name: "allow an existing firewall",
+			firewall: &NetworkFirewall{
+				ARN: "arn:aws:network-firewall:us-east-1:123456789012:firewall/inspection",
+			},
+			wantErr: false,
+		},
+		{
+			name: "allow a firewall created from a policy",
+			firewall: &NetworkFirewall{
+				PolicyARN: "arn:aws:network-firewall:us-east-1:123456789012:firewall-policy/egress",
+				Subnets: Subnets{
+					{CidrBlock: "10.0.240.0/28", AvailabilityZone: "us-east-1a"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "firewall without arn or policy not allowed",
+			firewall: &NetworkFirewall{},
+			wantErr:  true,
+		},
+		{
+			name: "firewall with both arn and policy not allowed",
+			firewall: &NetworkFirewall{
+				ARN:       "arn:aws:network-firewall:us-east-1:123456789012:firewall/inspection",
+				PolicyARN: "arn:aws:network-firewall:us-east-1:123456789012:firewall-policy/egress",
+			},
+			wantErr: true,
+		},
+		{
+			name: "firewall created from a policy without subnets not allowed",
+			firewall: &NetworkFirewall{
+				PolicyARN: "arn:aws:network-firewall:us-east-1:123456789012:firewall-policy/egress",
+			},
+			wantErr: true,
+		},
+		{
+			name: "firewall subnet without availability zone not allowed",
+			firewall: &NetworkFirewall{
+				PolicyARN: "arn:aws:network-firewall:us-east-1:123456789012:firewall-policy/egress",
+				Subnets: Subnets{
+					{CidrBlock: "10.0.240.0/28"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "firewall subnet outside of the VPC not allowed",
+			firewall: &NetworkFirewall{
+				PolicyARN: "arn:aws:network-firewall:us-east-1:123456789012:firewall-policy/egress",
+				Subnets: Subnets{
+					{CidrBlock: "192.168.0.0/28", AvailabilityZone: "us-east-1a"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{CidrBlock: "10.0.0.0/16"},
+						Subnets: Subnets{
+							{CidrBlock: "10.0.0.0/24", AvailabilityZone: "us-east-1a", IsPublic: true},
+							{CidrBlock: "10.0.1.0/24", AvailabilityZone: "us-east-1a"},
+						},
+						Firewall: tt.firewall,
+					},
+				},
+			}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateGatewayLoadBalancerEndpointRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  []GatewayLoadBalancerEndpointRoute
+		wantErr bool
+	}{
+		{
+			name: "allow routes to the same destination for different subnets",
+			routes: []GatewayLoadBalancerEndpointRoute{
+				{DestinationCidrBlock: "10.100.0.0/16", Endpoints: map[string]string{"us-east-1a": "vpce-01"}, SubnetRole: PrivateRoleTagValue},
+				{DestinationCidrBlock: "10.100.0.0/16", Endpoints: map[string]string{"us-east-1a": "vpce-02"}, SubnetRole: PublicRoleTagValue},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid destination not allowed",
+			routes: []GatewayLoadBalancerEndpointRoute{
+				{DestinationCidrBlock: "10.100.0.0", Endpoints: map[string]string{"us-east-1a": "vpce-01"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "route without endpoints not allowed",
+			routes: []GatewayLoadBalancerEndpointRoute{
+				{DestinationCidrBlock: "10.100.0.0/16"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "endpoint which is not a VPC endpoint not allowed",
+			routes: []GatewayLoadBalancerEndpointRoute{
+				{DestinationCidrBlock: "10.100.0.0/16", Endpoints: map[string]string{"us-east-1a": "nat-01"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "routes to the same destination for the same subnets not allowed",
+			routes: []GatewayLoadBalancerEndpointRoute{
+				{DestinationCidrBlock: "10.100.0.0/16", Endpoints: map[string]string{"us-east-1a": "vpce-01"}},
+				{DestinationCidrBlock: "10.100.0.0/16", Endpoints: map[string]string{"us-east-1a": "vpce-02"}, SubnetRole: PublicRoleTagValue},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{NetworkSpec: NetworkSpec{GatewayLoadBalancerEndpointRoutes: tt.routes}}}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		network NetworkSpec
+		wantErr bool
+	}{
+		{
+			name: "allow routes of the network and of a subnet",
+			network: NetworkSpec{
+				Routes: []Route{
+					{DestinationCidrBlock: "10.100.0.0/16", TransitGatewayID: "tgw-01"},
+				},
+				Subnets: Subnets{
+					{
+						ID: "subnet-01",
+						Routes: []Route{
+							{DestinationCidrBlock: "10.100.0.0/16", VpcPeeringConnectionID: "pcx-01"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "route without target not allowed",
+			network: NetworkSpec{
+				Routes: []Route{
+					{DestinationCidrBlock: "10.100.0.0/16"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "route with several targets not allowed",
+			network: NetworkSpec{
+				Routes: []Route{
+					{DestinationCidrBlock: "10.100.0.0/16", InstanceID: "i-01", NetworkInterfaceID: "eni-01"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid destination of a subnet route not allowed",
+			network: NetworkSpec{
+				Subnets: Subnets{
+					{
+						ID: "subnet-01",
+						Routes: []Route{
+							{DestinationCidrBlock: "10.100.0.0", InstanceID: "i-01"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allow a subnet associated with an unmanaged route table",
+			network: NetworkSpec{
+				Subnets: Subnets{
+					{ID: "subnet-01", UnmanagedRouteTableID: "rtb-01"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "routes of a subnet associated with an unmanaged route table not allowed",
+			network: NetworkSpec{
+				Subnets: Subnets{
+					{
+						ID:                    "subnet-01",
+						UnmanagedRouteTableID: "rtb-01",
+						Routes: []Route{
+							{DestinationCidrBlock: "10.100.0.0/16", InstanceID: "i-01"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unmanaged route table which is not a route table not allowed",
+			network: NetworkSpec{
+				Subnets: Subnets{
+					{ID: "subnet-01", UnmanagedRouteTableID: "subnet-02"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "routes to the same destination not allowed",
+			network: NetworkSpec{
+				Routes: []Route{
+					{DestinationCidrBlock: "10.100.0.0/16", TransitGatewayID: "tgw-01"},
+					{DestinationCidrBlock: "10.100.0.0/16", TransitGatewayID: "tgw-02"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{NetworkSpec: tt.network}}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateElasticIPPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    *ElasticIPPool
+		wantErr bool
+	}{
+		{
+			name:    "allow a pool of allocation IDs",
+			pool:    &ElasticIPPool{AllocationIDs: []string{"eipalloc-01", "eipalloc-02"}},
+			wantErr: false,
+		},
+		{
+			name:    "allow a pool selected by tags",
+			pool:    &ElasticIPPool{Tags: Tags{"allowlisted": "true"}},
+			wantErr: false,
+		},
+		{
+			name:    "empty pool not allowed",
+			pool:    &ElasticIPPool{},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate allocation IDs not allowed",
+			pool:    &ElasticIPPool{AllocationIDs: []string{"eipalloc-01", "eipalloc-01"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{NetworkSpec: NetworkSpec{ElasticIPPool: tt.pool}}}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSCluster_ValidateNatGatewayAllocationIDs(t *testing.T) {
+	tests := []struct {
+		name          string
+		allocationIDs map[string]string
+		wantErr       bool
+	}{
+		{
+			name:          "allow an Elastic IP per availability zone",
+			allocationIDs: map[string]string{"us-east-1a": "eipalloc-01", "us-east-1b": "eipalloc-02"},
+			wantErr:       false,
+		},
+		{
+			name:          "empty allocation ID not allowed",
+			allocationIDs: map[string]string{"us-east-1a": ""},
+			wantErr:       true,
+		},
+		{
+			name:          "same Elastic IP in several availability zones not allowed",
+			allocationIDs: map[string]string{"us-east-1a": "eipalloc-01", "us-east-1b": "eipalloc-01"},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &AWSCluster{Spec: AWSClusterSpec{NetworkSpec: NetworkSpec{NatGatewayAllocationIDs: tt.allocationIDs}}}
+			_, err := cluster.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
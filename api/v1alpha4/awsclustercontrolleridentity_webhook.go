@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -34,24 +35,27 @@ import (
 var _ = logf.Log.WithName("awsclustercontrolleridentity-resource")
 
 func (r *AWSClusterControllerIdentity) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsclustercontrolleridentity,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclustercontrolleridentities,versions=v1alpha4,name=validation.awsclustercontrolleridentity.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awsclustercontrolleridentity,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclustercontrolleridentities,versions=v1alpha4,name=default.awsclustercontrolleridentity.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
-	_ webhook.Validator = &AWSClusterControllerIdentity{}
-	_ webhook.Defaulter = &AWSClusterControllerIdentity{}
+	_ webhooks.Validator = &AWSClusterControllerIdentity{}
+	_ webhook.Defaulter  = &AWSClusterControllerIdentity{}
 )
 
 // ValidateCreate will do any extra validation when creating an AWSClusterControllerIdentity.
-func (r *AWSClusterControllerIdentity) ValidateCreate() error {
+func (r *AWSClusterControllerIdentity) ValidateCreate() (webhooks.Warnings, error) {
 	// Ensures AWSClusterControllerIdentity being singleton by only allowing "default" as name
 	if r.Name != AWSClusterControllerIdentityName {
-		return field.Invalid(field.NewPath("name"),
+		return nil, field.Invalid(field.NewPath("name"),
 			r.Name, "AWSClusterControllerIdentity is a singleton and only acceptable name is default")
 	}
 
@@ -59,41 +63,49 @@ func (r *AWSClusterControllerIdentity) ValidateCreate() error {
 	if r.Spec.AllowedNamespaces != nil {
 		_, err := metav1.LabelSelectorAsSelector(&r.Spec.AllowedNamespaces.Selector)
 		if err != nil {
-			return field.Invalid(field.NewPath("spec", "allowedNamespaces", "selector"), r.Spec.AllowedNamespaces.Selector, err.Error())
+			return nil, field.Invalid(field.NewPath("spec", "allowedNamespaces", "selector"), r.Spec.AllowedNamespaces.Selector, err.Error())
 		}
 	}
 
-	return nil
+	if err := r.Spec.validateCABundle(); err != nil {
+		return nil, err
+	}
+
+	if err := r.Spec.validateProxy(); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
 }
 
 // ValidateDelete allows you to add any extra validation when deleting an AWSClusterControllerIdentity.
-func (r *AWSClusterControllerIdentity) ValidateDelete() error {
-	return nil
+func (r *AWSClusterControllerIdentity) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
 
 // ValidateUpdate will do any extra validation when updating an AWSClusterControllerIdentity.
-func (r *AWSClusterControllerIdentity) ValidateUpdate(old runtime.Object) error {
+func (r *AWSClusterControllerIdentity) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	oldP, ok := old.(*AWSClusterControllerIdentity)
 	if !ok {
-		return apierrors.NewBadRequest(fmt.Sprintf("expected an AWSClusterControllerIdentity but got a %T", old))
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AWSClusterControllerIdentity but got a %T", old))
 	}
 
 	if !reflect.DeepEqual(r.Spec, oldP.Spec) {
-		return errors.New("AWSClusterControllerIdentity is immutable")
+		return nil, errors.New("AWSClusterControllerIdentity is immutable")
 	}
 
 	if r.Name != oldP.Name {
-		return field.Invalid(field.NewPath("name"),
+		return nil, field.Invalid(field.NewPath("name"),
 			r.Name, "AWSClusterControllerIdentity is a singleton and only acceptable name is default")
 	}
 
 	// Validate selector parses as Selector
 	_, err := metav1.LabelSelectorAsSelector(&r.Spec.AllowedNamespaces.Selector)
 	if err != nil {
-		return field.Invalid(field.NewPath("spec", "allowedNamespaces", "selectors"), r.Spec.AllowedNamespaces.Selector, err.Error())
+		return nil, field.Invalid(field.NewPath("spec", "allowedNamespaces", "selectors"), r.Spec.AllowedNamespaces.Selector, err.Error())
 	}
 
-	return nil
+	return nil, nil
 }
 
 // Default will set default values for the AWSClusterControllerIdentity.
@@ -274,3 +274,89 @@ func TestAWSClusterStaticIdentity_Default(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSClusterStaticIdentityValidateCABundle(t *testing.T) {
+	tests := []struct {
+		name      string
+		caBundle  []byte
+		wantError bool
+	}{
+		{
+			name:      "no CA bundle",
+			wantError: false,
+		},
+		{
+			name:      "CA bundle without PEM encoded certificates",
+			caBundle:  []byte("not a certificate"),
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			identity := &AWSClusterStaticIdentity{
+				Spec: AWSClusterStaticIdentitySpec{
+					AWSClusterIdentitySpec: AWSClusterIdentitySpec{
+						CABundle: tt.caBundle,
+					},
+					SecretRef: "test-secret",
+				},
+			}
+			_, err := identity.ValidateCreate()
+			if tt.wantError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSClusterStaticIdentityValidateProxy(t *testing.T) {
+	tests := []struct {
+		name      string
+		proxy     *IdentityProxy
+		wantError bool
+	}{
+		{
+			name:      "no proxy",
+			wantError: false,
+		},
+		{
+			name:      "http proxy",
+			proxy:     &IdentityProxy{URL: "http://proxy.example.com:3128", NoProxy: []string{".internal"}},
+			wantError: false,
+		},
+		{
+			name:      "proxy without scheme",
+			proxy:     &IdentityProxy{URL: "proxy.example.com:3128"},
+			wantError: true,
+		},
+		{
+			name:      "socks proxy",
+			proxy:     &IdentityProxy{URL: "socks5://proxy.example.com:1080"},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			identity := &AWSClusterStaticIdentity{
+				Spec: AWSClusterStaticIdentitySpec{
+					AWSClusterIdentitySpec: AWSClusterIdentitySpec{
+						Proxy: tt.proxy,
+					},
+					SecretRef: "test-secret",
+				},
+			}
+			_, err := identity.ValidateCreate()
+			if tt.wantError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -22,14 +22,19 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 func (r *AWSClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	namespaceReader = mgr.GetAPIReader()
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsclustertemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclustertemplates,versions=v1alpha4,name=validation.awsclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
@@ -39,32 +44,42 @@ var _ webhook.Defaulter = &AWSClusterTemplate{}
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
 func (r *AWSClusterTemplate) Default() {
+	defaultNamespaceIdentity(r.Namespace, &r.Spec.Template.Spec)
 	SetDefaultsAWSClusterSpec(&r.Spec.Template.Spec)
 }
 
-var _ webhook.Validator = &AWSClusterTemplate{}
+var _ webhooks.Validator = &AWSClusterTemplate{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSClusterTemplate) ValidateCreate() error {
+// ValidateCreate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSClusterTemplate) ValidateCreate() (webhooks.Warnings, error) {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, r.Spec.Template.Spec.Bastion.Validate()...)
 	allErrs = append(allErrs, validateSSHKeyName(r.Spec.Template.Spec.SSHKeyName)...)
-
-	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+	allErrs = append(allErrs, validateSSHKeyPair(r.Spec.Template.Spec.SSHKeyName, r.Spec.Template.Spec.SSHKeyPair, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, r.Spec.Template.Spec.S3Bucket.Validate()...)
+	allErrs = append(allErrs, r.Spec.Template.Spec.ECRPullThroughCache.Validate()...)
+	allErrs = append(allErrs, r.Spec.Template.Spec.ControlPlaneLoadBalancer.Validate()...)
+	allErrs = append(allErrs, r.Spec.Template.Spec.Karpenter.Validate()...)
+	allErrs = append(allErrs, r.Spec.Template.Spec.DeletionPolicy.Validate()...)
+	allErrs = append(allErrs, validateNetwork(&r.Spec.Template.Spec.NetworkSpec, field.NewPath("spec", "template", "spec", "networkSpec"))...)
+	allErrs = append(allErrs, validateNamespaceIdentity(r.Namespace, r.Spec.Template.Spec.IdentityRef, field.NewPath("spec", "template", "spec", "identityRef"))...)
+	allErrs = append(allErrs, validateStrictCluster(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+
+	return clusterSpecWarnings(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec")), aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSClusterTemplate) ValidateUpdate(oldRaw runtime.Object) error {
+// ValidateUpdate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSClusterTemplate) ValidateUpdate(oldRaw runtime.Object) (webhooks.Warnings, error) {
 	old := oldRaw.(*AWSClusterTemplate)
 
 	if !reflect.DeepEqual(r.Spec, old.Spec) {
-		return apierrors.NewBadRequest("AWSClusterTempalate.Spec is immutable")
+		return nil, apierrors.NewBadRequest("AWSClusterTempalate.Spec is immutable")
 	}
-	return nil
+	return nil, nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSClusterTemplate) ValidateDelete() error {
-	return nil
+// ValidateDelete implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSClusterTemplate) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
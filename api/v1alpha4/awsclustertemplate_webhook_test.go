@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAWSClusterTemplateValidateCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		network   NetworkSpec
+		wantError bool
+	}{
+		{
+			name:      "no VPC CIDR block",
+			network:   NetworkSpec{},
+			wantError: false,
+		},
+		{
+			name: "valid VPC and subnet CIDR blocks",
+			network: NetworkSpec{
+				VPC: VPCSpec{CidrBlock: "10.0.0.0/16"},
+				Subnets: Subnets{
+					{CidrBlock: "10.0.0.0/24"},
+					{CidrBlock: "10.0.1.0/24", IsPublic: true},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:      "invalid VPC CIDR block",
+			network:   NetworkSpec{VPC: VPCSpec{CidrBlock: "10.0.0.0"}},
+			wantError: true,
+		},
+		{
+			name:      "VPC CIDR block too large",
+			network:   NetworkSpec{VPC: VPCSpec{CidrBlock: "10.0.0.0/8"}},
+			wantError: true,
+		},
+		{
+			name: "subnet outside of the VPC",
+			network: NetworkSpec{
+				VPC:     VPCSpec{CidrBlock: "10.0.0.0/16"},
+				Subnets: Subnets{{CidrBlock: "10.1.0.0/24"}},
+			},
+			wantError: true,
+		},
+		{
+			name: "CIDR blocks of an unmanaged VPC are not checked",
+			network: NetworkSpec{
+				VPC:     VPCSpec{ID: "vpc-123", CidrBlock: "10.0.0.0/8"},
+				Subnets: Subnets{{CidrBlock: "10.1.0.0/24"}},
+			},
+			wantError: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template := &AWSClusterTemplate{
+				Spec: AWSClusterTemplateSpec{
+					Template: AWSClusterTemplateResource{
+						Spec: AWSClusterSpec{NetworkSpec: tt.network},
+					},
+				},
+			}
+			_, err := template.ValidateCreate()
+			if tt.wantError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
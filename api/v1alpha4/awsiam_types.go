@@ -88,7 +88,10 @@ type StatementEntry struct {
 	Effect       Effect     `json:"Effect"`
 	Action       Actions    `json:"Action"`
 	Resource     Resources  `json:",omitempty"`
-	Condition    Conditions `json:"Condition,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Condition Conditions `json:"Condition,omitempty"`
 }
 
 // Statements is the list of StatementEntries.
@@ -31,6 +31,31 @@ type AWSClusterIdentitySpec struct {
 	// +optional
 	// +nullable
 	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces"`
+
+	// CABundle is a PEM encoded bundle of certificate authorities which are trusted for the AWS API endpoints
+	// in addition to the system ones and those of the controller's --aws-ca-bundle flag, when the clusters
+	// using this identity call AWS. It is needed when the endpoints are reached through a TLS-intercepting
+	// proxy or are private endpoints serving certificates of a private certificate authority.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// Proxy is the HTTP(S) proxy the AWS API endpoints are reached through when the clusters using this
+	// identity call AWS, instead of the proxy of the controller's environment.
+	// +optional
+	Proxy *IdentityProxy `json:"proxy,omitempty"`
+}
+
+// IdentityProxy configures the HTTP(S) proxy the AWS API endpoints are reached through.
+type IdentityProxy struct {
+	// URL of the proxy, e.g. http://proxy.example.com:3128.
+	// +kubebuilder:validation:MinLength:=1
+	URL string `json:"url"`
+
+	// NoProxy lists the hosts which are reached directly rather than through the proxy, in the format of the
+	// NO_PROXY environment variable: host names, domains with a leading dot (e.g. .example.com), IP addresses
+	// and CIDR blocks.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
 }
 
 // AllowedNamespaces is a selector of namespaces that AWSClusters can
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
@@ -26,6 +27,18 @@ const (
 	// MachineFinalizer allows ReconcileAWSMachine to clean up AWS resources associated with AWSMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "awsmachine.infrastructure.cluster.x-k8s.io"
+
+	// AdoptInstanceAnnotation is the annotation on an AWSMachine that names an existing EC2 instance, by ID, to
+	// bring under management instead of launching a new one.
+	AdoptInstanceAnnotation = "aws.cluster.x-k8s.io/adopt-instance"
+
+	// AnalyzeConnectivityAnnotation is the annotation on an AWSMachine that requests an on-demand analysis of the
+	// path from its instance to the API server load balancer. It is removed once the analysis completed.
+	AnalyzeConnectivityAnnotation = "aws.cluster.x-k8s.io/analyze-connectivity"
+
+	// MachinePoolNameLabel is the label on the AWSMachines created for the instances of a MachinePool that
+	// names the MachinePool. The AWSMachine controller leaves such AWSMachines to the AWSMachinePool controller.
+	MachinePoolNameLabel = "cluster.x-k8s.io/pool-name"
 )
 
 // SecretBackend defines variants for backend secret storage.
@@ -39,6 +52,30 @@ var (
 	SecretBackendSecretsManager = SecretBackend("secrets-manager")
 )
 
+// ScheduledEventPolicy defines how the controller reacts to scheduled events of an instance.
+type ScheduledEventPolicy string
+
+var (
+	// ScheduledEventPolicyReport only reports scheduled events in the AWSMachine status and conditions.
+	ScheduledEventPolicyReport = ScheduledEventPolicy("Report")
+
+	// ScheduledEventPolicyReplace additionally marks the AWSMachine as failed so that a MachineHealthCheck
+	// replaces the machine before the event takes place.
+	ScheduledEventPolicyReplace = ScheduledEventPolicy("Replace")
+)
+
+// UserDataChangePolicy defines how the controller reacts to bootstrap data changing after the instance was launched.
+type UserDataChangePolicy string
+
+var (
+	// UserDataChangePolicyReport only reports the change through the UserDataOutOfDate condition.
+	UserDataChangePolicyReport = UserDataChangePolicy("Report")
+
+	// UserDataChangePolicyReplace additionally marks the AWSMachine as failed so that a MachineHealthCheck
+	// replaces the machine with one launched from the new bootstrap data.
+	UserDataChangePolicyReplace = UserDataChangePolicy("Replace")
+)
+
 // AWSMachineSpec defines the desired state of AWSMachine
 type AWSMachineSpec struct {
 	// ProviderID is the unique identifier as specified by the cloud provider.
@@ -74,6 +111,25 @@ type AWSMachineSpec struct {
 	// InstanceType is the type of instance to create. Example: m4.xlarge
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// FallbackInstanceTypes is an ordered list of instance types to launch the instance with
+	// when EC2 has no capacity for InstanceType, or does not support it, in the availability zone.
+	// The fallback instance types must be able to run the same AMI as InstanceType.
+	// +optional
+	FallbackInstanceTypes []string `json:"fallbackInstanceTypes,omitempty"`
+
+	// GPU prepares the instance for the GPUs of its instance type. On EKS managed
+	// clusters the GPU variant of the EKS optimized AMI is looked up, unless
+	// ami.eksLookupType is set. A script installing the driver, the container
+	// runtime hook and the device plugin is appended to the bootstrap data.
+	// +kubebuilder:validation:Enum:=nvidia
+	// +optional
+	GPU GPUVendor `json:"gpu,omitempty"`
+
+	// Bottlerocket are the settings rendered into the TOML user data of the instance.
+	// Only valid together with the Bottlerocket OS family.
+	// +optional
+	Bottlerocket *BottlerocketSettings `json:"bottlerocket,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// AWS provider. If both the AWSCluster and the AWSMachine specify the same tag name with different values, the
 	// AWSMachine's value takes precedence.
@@ -127,6 +183,24 @@ type AWSMachineSpec struct {
 	// +kubebuilder:validation:MaxItems=2
 	NetworkInterfaces []string `json:"networkInterfaces,omitempty"`
 
+	// NetworkInterfaceConfigs configures the attachment of the interfaces of NetworkInterfaces, for instance to
+	// place them on other network cards than the first one.
+	// +optional
+	NetworkInterfaceConfigs []NetworkInterfaceConfig `json:"networkInterfaceConfigs,omitempty"`
+
+	// PrivateIP is the private IPv4 address of the primary network interface of the instance. It must be a free
+	// address of the subnet of the instance, and not one of the addresses AWS reserves in the subnet. EC2 picks
+	// an address when it isn't set. Cannot be set together with PrivateIPFromPool or NetworkInterfaces.
+	// +optional
+	PrivateIP *string `json:"privateIp,omitempty"`
+
+	// PrivateIPFromPool is a reference to an IP pool of a Cluster API IPAM provider, e.g. an InClusterIPPool.
+	// An IPAddressClaim for the pool is created for the machine, and the instance is launched with the address
+	// allocated to the claim as the private IP address of its primary network interface. The address must be in
+	// the subnet of the instance. Cannot be set together with PrivateIP or NetworkInterfaces.
+	// +optional
+	PrivateIPFromPool *corev1.TypedLocalObjectReference `json:"privateIpFromPool,omitempty"`
+
 	// UncompressedUserData specify whether the user data is gzip-compressed before it is sent to ec2 instance.
 	// cloud-init has built-in support for gzip-compressed user data
 	// user data stored in aws secret manager is always gzip-compressed.
@@ -147,6 +221,57 @@ type AWSMachineSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum:=default;dedicated;host
 	Tenancy string `json:"tenancy,omitempty"`
+
+	// HostResourceGroupARN is the ARN of the host resource group to launch the instance in, so that
+	// License Manager allocates a dedicated host for it. Requires Tenancy to be unset or host.
+	// +optional
+	HostResourceGroupARN *string `json:"hostResourceGroupARN,omitempty"`
+
+	// LicenseConfigurationARNs are the ARNs of the License Manager license configurations to
+	// associate with the instance, to track the licenses it uses, e.g. when bringing your own license.
+	// +optional
+	LicenseConfigurationARNs []string `json:"licenseConfigurationARNs,omitempty"`
+
+	// CPUOptions sets the number of CPU cores and threads per core of the instance at launch,
+	// e.g. to disable multithreading. The instance type must support the given values.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// InstanceMetadataOptions are the options of the instance metadata service of the instance,
+	// e.g. to require IMDSv2 tokens. Defaults to the machine defaults of the cluster.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
+	// ManagedIAMInstanceProfile, when set, makes the controller create an IAM role and instance profile
+	// for this machine with the given policies, and delete them along with the machine.
+	// Cannot be set together with IAMInstanceProfile. Requires the MachineIAMInstanceProfile feature gate.
+	// +optional
+	ManagedIAMInstanceProfile *ManagedIAMInstanceProfile `json:"managedIAMInstanceProfile,omitempty"`
+
+	// ScheduledEventPolicy defines how the controller reacts to scheduled events of the instance,
+	// such as an instance retirement or a system reboot. Defaults to Report.
+	// Scheduled events are only observed when the InstanceScheduledEvents feature gate is enabled.
+	// +optional
+	// +kubebuilder:validation:Enum=Report;Replace
+	ScheduledEventPolicy ScheduledEventPolicy `json:"scheduledEventPolicy,omitempty"`
+
+	// UserDataChangePolicy defines how the controller reacts when the bootstrap data of the machine
+	// changes after the instance was launched. EC2 instances only run their user data on first boot,
+	// so such changes are never applied to the running instance. Defaults to Report.
+	// +optional
+	// +kubebuilder:validation:Enum=Report;Replace
+	UserDataChangePolicy UserDataChangePolicy `json:"userDataChangePolicy,omitempty"`
+}
+
+// ManagedIAMInstanceProfile defines the IAM role and instance profile created for a machine.
+type ManagedIAMInstanceProfile struct {
+	// ManagedPolicyARNs is a list of ARNs of managed policies to attach to the role.
+	// +optional
+	ManagedPolicyARNs []string `json:"managedPolicyARNs,omitempty"`
+
+	// InlinePolicy is an IAM policy document in JSON format added to the role as an inline policy.
+	// +optional
+	InlinePolicy string `json:"inlinePolicy,omitempty"`
 }
 
 // CloudInit defines options related to the bootstrapping systems where
@@ -187,6 +312,16 @@ type AWSMachineStatus struct {
 	// +optional
 	Interruptible bool `json:"interruptible,omitempty"`
 
+	// SpotLaunchFailures is the number of attempts to launch a spot instance for this machine
+	// that failed for lack of spot capacity. It is only tracked when spotMarketOptions.fallbackToOnDemand is set.
+	// +optional
+	SpotLaunchFailures int32 `json:"spotLaunchFailures,omitempty"`
+
+	// OnDemandFallback reports that the instance of this machine was launched as an on-demand
+	// instance because spot instances could not be launched.
+	// +optional
+	OnDemandFallback bool `json:"onDemandFallback,omitempty"`
+
 	// Addresses contains the AWS instance associated addresses.
 	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
 
@@ -194,6 +329,20 @@ type AWSMachineStatus struct {
 	// +optional
 	InstanceState *InstanceState `json:"instanceState,omitempty"`
 
+	// Instance contains details of the AWS instance for this machine, such as its
+	// launch time, purchasing option and placement.
+	// +optional
+	Instance *InstanceDetails `json:"instance,omitempty"`
+
+	// ScheduledEvents are the pending scheduled events of the AWS instance for this machine.
+	// +optional
+	ScheduledEvents []InstanceScheduledEvent `json:"scheduledEvents,omitempty"`
+
+	// RightSizing is the AWS Compute Optimizer recommendation for the instance of this machine. It is only
+	// fetched when the RightSizingRecommendations feature gate is enabled.
+	// +optional
+	RightSizing *RightSizingRecommendation `json:"rightSizing,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -246,6 +395,9 @@ type AWSMachineStatus struct {
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
 // +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".spec.providerID",description="EC2 instance ID"
 // +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object which owns with this AWSMachine"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".status.instance.instanceType",description="EC2 instance type the instance was launched with",priority=1
+// +kubebuilder:printcolumn:name="Lifecycle",type="string",JSONPath=".status.instance.lifecycle",description="EC2 instance purchasing option",priority=1
+// +kubebuilder:printcolumn:name="Launched",type="date",JSONPath=".status.instance.launchTime",description="Time the EC2 instance was launched",priority=1
 
 // AWSMachine is the Schema for the awsmachines API
 type AWSMachine struct {
@@ -17,12 +17,15 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -32,21 +35,25 @@ import (
 var _ = logf.Log.WithName("awsmachine-resource")
 
 func (r *AWSMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	namespaceReader = mgr.GetAPIReader()
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,versions=v1alpha4,name=validation.awsmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmachine,mutating=true,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,versions=v1alpha4,name=mawsmachine.kb.io,name=mutation.awsmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
-	_ webhook.Validator = &AWSMachine{}
-	_ webhook.Defaulter = &AWSMachine{}
+	_ webhooks.Validator = &AWSMachine{}
+	_ webhook.Defaulter  = &AWSMachine{}
 )
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSMachine) ValidateCreate() error {
+// ValidateCreate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSMachine) ValidateCreate() (webhooks.Warnings, error) {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, r.validateCloudInitSecret()...)
@@ -54,21 +61,31 @@ func (r *AWSMachine) ValidateCreate() error {
 	allErrs = append(allErrs, r.validateNonRootVolumes()...)
 	allErrs = append(allErrs, r.validateSSHKeyName()...)
 	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
-
-	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+	allErrs = append(allErrs, validateManagedIAMInstanceProfile(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateInstanceTypeOffering(&r.Spec, r.Namespace, r.Labels[clusterv1.ClusterLabelName], field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateFallbackInstanceTypes(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateLicensing(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePrivateIP(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePrivateIPFromPool(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateNetworkInterfaceConfigs(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, r.validatePrivateIPSubnet()...)
+	allErrs = append(allErrs, validateOSFamily(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateStrictMachine(&r.Spec, field.NewPath("spec"))...)
+
+	return machineSpecWarnings(&r.Spec, field.NewPath("spec")), aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSMachine) ValidateUpdate(old runtime.Object) error {
+// ValidateUpdate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSMachine) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	newAWSMachine, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r)
 	if err != nil {
-		return apierrors.NewInvalid(GroupVersion.WithKind("AWSMachine").GroupKind(), r.Name, field.ErrorList{
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AWSMachine").GroupKind(), r.Name, field.ErrorList{
 			field.InternalError(nil, errors.Wrap(err, "failed to convert new AWSMachine to unstructured object")),
 		})
 	}
 	oldAWSMachine, err := runtime.DefaultUnstructuredConverter.ToUnstructured(old)
 	if err != nil {
-		return apierrors.NewInvalid(GroupVersion.WithKind("AWSMachine").GroupKind(), r.Name, field.ErrorList{
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AWSMachine").GroupKind(), r.Name, field.ErrorList{
 			field.InternalError(nil, errors.Wrap(err, "failed to convert old AWSMachine to unstructured object")),
 		})
 	}
@@ -96,6 +113,12 @@ func (r *AWSMachine) ValidateUpdate(old runtime.Object) error {
 	delete(oldAWSMachineSpec, "additionalSecurityGroups")
 	delete(newAWSMachineSpec, "additionalSecurityGroups")
 
+	// allow changes to scheduledEventPolicy and userDataChangePolicy
+	delete(oldAWSMachineSpec, "scheduledEventPolicy")
+	delete(newAWSMachineSpec, "scheduledEventPolicy")
+	delete(oldAWSMachineSpec, "userDataChangePolicy")
+	delete(newAWSMachineSpec, "userDataChangePolicy")
+
 	// allow changes to secretPrefix, secretCount, and secureSecretsBackend
 	if cloudInit, ok := oldAWSMachineSpec["cloudInit"].(map[string]interface{}); ok {
 		delete(cloudInit, "secretPrefix")
@@ -113,7 +136,7 @@ func (r *AWSMachine) ValidateUpdate(old runtime.Object) error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
 	}
 
-	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 func (r *AWSMachine) validateCloudInitSecret() field.ErrorList {
@@ -176,9 +199,9 @@ func (r *AWSMachine) validateNonRootVolumes() field.ErrorList {
 	return allErrs
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSMachine) ValidateDelete() error {
-	return nil
+// ValidateDelete implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSMachine) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
 
 // Default implements webhook.Defaulter such that an empty CloudInit will be defined with a default
@@ -200,6 +223,36 @@ func (r *AWSMachine) validateAdditionalSecurityGroups() field.ErrorList {
 	return allErrs
 }
 
+// validatePrivateIPSubnet rejects a private IP address outside of the subnet of the machine, when the subnet is set by
+// ID and is one of the subnets of the AWSCluster. Machines of clusters which don't exist yet are not checked.
+func (r *AWSMachine) validatePrivateIPSubnet() field.ErrorList {
+	clusterName := r.Labels[clusterv1.ClusterLabelName]
+	if namespaceReader == nil || r.Spec.PrivateIP == nil || r.Spec.Subnet == nil || r.Spec.Subnet.ID == nil || clusterName == "" {
+		return nil
+	}
+
+	fldPath := field.NewPath("spec", "privateIp")
+	ctx, cancel := context.WithTimeout(context.Background(), namespaceLookupTimeout)
+	defer cancel()
+
+	_, awsCluster, err := getAWSCluster(ctx, r.Namespace, clusterName)
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	if awsCluster == nil {
+		return nil
+	}
+
+	subnet := awsCluster.Spec.NetworkSpec.Subnets.FindByID(*r.Spec.Subnet.ID)
+	if subnet == nil || subnet.CidrBlock == "" {
+		return nil
+	}
+	if err := ValidatePrivateIPInSubnet(*r.Spec.PrivateIP, subnet.CidrBlock); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, *r.Spec.PrivateIP, err.Error())}
+	}
+	return nil
+}
+
 func (r *AWSMachine) validateSSHKeyName() field.ErrorList {
 	return validateSSHKeyName(r.Spec.SSHKeyName)
 }
@@ -18,14 +18,22 @@ package v1alpha4
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	. "github.com/onsi/gomega"
 
 	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	utildefaulting "sigs.k8s.io/cluster-api-provider-aws/test/helpers/defaulting"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMachineDefault(t *testing.T) {
@@ -164,6 +172,26 @@ func TestAWSMachine_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "fallback instance types are allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:          "m5.large",
+					FallbackInstanceTypes: []string{"m5a.large", "m4.large"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fallback instance types can't repeat the instance type",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:          "m5.large",
+					FallbackInstanceTypes: []string{"m5a.large", "m5.large"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -310,3 +338,620 @@ func TestAWSMachine_SecretsBackend(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSMachine_ManagedIAMInstanceProfile(t *testing.T) {
+	tests := []struct {
+		name           string
+		featureEnabled bool
+		spec           AWSMachineSpec
+		wantErr        bool
+	}{
+		{
+			name:           "rejected when the feature gate is disabled",
+			featureEnabled: false,
+			spec: AWSMachineSpec{
+				ManagedIAMInstanceProfile: &ManagedIAMInstanceProfile{},
+			},
+			wantErr: true,
+		},
+		{
+			name:           "accepted with managed policies and an inline policy",
+			featureEnabled: true,
+			spec: AWSMachineSpec{
+				ManagedIAMInstanceProfile: &ManagedIAMInstanceProfile{
+					ManagedPolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"},
+					InlinePolicy:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "rejected together with iamInstanceProfile",
+			featureEnabled: true,
+			spec: AWSMachineSpec{
+				IAMInstanceProfile:        "nodes.cluster-api-provider-aws.sigs.k8s.io",
+				ManagedIAMInstanceProfile: &ManagedIAMInstanceProfile{},
+			},
+			wantErr: true,
+		},
+		{
+			name:           "rejected with an invalid inline policy",
+			featureEnabled: true,
+			spec: AWSMachineSpec{
+				ManagedIAMInstanceProfile: &ManagedIAMInstanceProfile{
+					InlinePolicy: `{"Version":"2012-10-17"}`,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineIAMInstanceProfile, tt.featureEnabled)()
+			g := NewWithT(t)
+
+			machine := &AWSMachine{Spec: tt.spec}
+			_, err := machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+type fakeInstanceTypeOfferingChecker map[string][]string
+
+func (f fakeInstanceTypeOfferingChecker) IsInstanceTypeOffered(_ *clusterv1.Cluster, awsCluster *AWSCluster, instanceType, availabilityZone string) (bool, error) {
+	// The offerings are only known for the identity of the test cluster.
+	if awsCluster.Spec.IdentityRef == nil || awsCluster.Spec.IdentityRef.Name != "team-a" {
+		return false, errors.New("unexpected identity")
+	}
+	offerings, ok := f[availabilityZone]
+	if !ok {
+		return false, errors.New("unknown availability zone")
+	}
+	for _, offering := range offerings {
+		if offering == instanceType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestAWSMachine_InstanceTypeOffering(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	previous := namespaceReader
+	namespaceReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AWSCluster", Name: "test"},
+			},
+		},
+		&AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: AWSClusterSpec{
+				Region:      "us-east-1",
+				IdentityRef: &AWSIdentityReference{Kind: ClusterRoleIdentityKind, Name: "team-a"},
+			},
+		},
+	).Build()
+	SetInstanceTypeOfferingChecker(fakeInstanceTypeOfferingChecker{"us-east-1a": {"m5.large"}})
+	defer func() {
+		namespaceReader = previous
+		SetInstanceTypeOfferingChecker(nil)
+	}()
+
+	tests := []struct {
+		name    string
+		cluster string
+		spec    AWSMachineSpec
+		wantErr bool
+	}{
+		{
+			name:    "offered instance type",
+			cluster: "test",
+			spec:    AWSMachineSpec{InstanceType: "m5.large", FailureDomain: aws.String("us-east-1a")},
+			wantErr: false,
+		},
+		{
+			name:    "instance type not offered in the failure domain",
+			cluster: "test",
+			spec:    AWSMachineSpec{InstanceType: "p4d.24xlarge", FailureDomain: aws.String("us-east-1a")},
+			wantErr: true,
+		},
+		{
+			name:    "no failure domain",
+			cluster: "test",
+			spec:    AWSMachineSpec{InstanceType: "p4d.24xlarge"},
+			wantErr: false,
+		},
+		{
+			name:    "offerings cannot be looked up",
+			cluster: "test",
+			spec:    AWSMachineSpec{InstanceType: "p4d.24xlarge", FailureDomain: aws.String("us-east-1b")},
+			wantErr: false,
+		},
+		{
+			name:    "cluster does not exist yet",
+			cluster: "missing",
+			spec:    AWSMachineSpec{InstanceType: "p4d.24xlarge", FailureDomain: aws.String("us-east-1a")},
+			wantErr: false,
+		},
+		{
+			name:    "no cluster label",
+			spec:    AWSMachineSpec{InstanceType: "p4d.24xlarge", FailureDomain: aws.String("us-east-1a")},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			meta := metav1.ObjectMeta{Name: "test", Namespace: "default"}
+			if tt.cluster != "" {
+				meta.Labels = map[string]string{clusterv1.ClusterLabelName: tt.cluster}
+			}
+			machine := &AWSMachine{ObjectMeta: meta, Spec: tt.spec}
+			template := &AWSMachineTemplate{ObjectMeta: meta, Spec: AWSMachineTemplateSpec{Template: AWSMachineTemplateResource{Spec: tt.spec}}}
+			_, machineErr := machine.ValidateCreate()
+			_, templateErr := template.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(machineErr).To(HaveOccurred())
+				g.Expect(templateErr).To(HaveOccurred())
+			} else {
+				g.Expect(machineErr).NotTo(HaveOccurred())
+				g.Expect(templateErr).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachine_Licensing(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    AWSMachineSpec
+		wantErr bool
+	}{
+		{
+			name: "accepted with a host resource group and license configurations",
+			spec: AWSMachineSpec{
+				HostResourceGroupARN:     aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+				LicenseConfigurationARNs: []string{"arn:aws:license-manager:us-east-1:123456789012:license-configuration:lic-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "accepted with a host resource group and host tenancy",
+			spec: AWSMachineSpec{
+				HostResourceGroupARN: aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+				Tenancy:              "host",
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejected with a host resource group and dedicated tenancy",
+			spec: AWSMachineSpec{
+				HostResourceGroupARN: aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+				Tenancy:              "dedicated",
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected with a license configuration that is not an ARN",
+			spec: AWSMachineSpec{
+				LicenseConfigurationARNs: []string{"lic-1"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &AWSMachine{Spec: tt.spec}
+			_, err := machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachine_PrivateIPFromPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    AWSMachineSpec
+		wantErr bool
+	}{
+		{
+			name: "accepted with a pool",
+			spec: AWSMachineSpec{
+				PrivateIPFromPool: &corev1.TypedLocalObjectReference{
+					APIGroup: aws.String("ipam.cluster.x-k8s.io"),
+					Kind:     "InClusterIPPool",
+					Name:     "control-plane",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejected without the API group of the pool",
+			spec: AWSMachineSpec{
+				PrivateIPFromPool: &corev1.TypedLocalObjectReference{
+					Kind: "InClusterIPPool",
+					Name: "control-plane",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected with network interfaces",
+			spec: AWSMachineSpec{
+				PrivateIPFromPool: &corev1.TypedLocalObjectReference{
+					APIGroup: aws.String("ipam.cluster.x-k8s.io"),
+					Kind:     "InClusterIPPool",
+					Name:     "control-plane",
+				},
+				NetworkInterfaces: []string{"eni-1"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &AWSMachine{Spec: tt.spec}
+			_, err := machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachine_NetworkInterfaceConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    AWSMachineSpec
+		wantErr bool
+	}{
+		{
+			name: "accepted with a secondary interface on another network card",
+			spec: AWSMachineSpec{
+				NetworkInterfaces: []string{"eni-1", "eni-2"},
+				NetworkInterfaceConfigs: []NetworkInterfaceConfig{
+					{NetworkInterfaceID: "eni-1", NetworkCardIndex: aws.Int64(0)},
+					{NetworkInterfaceID: "eni-2", NetworkCardIndex: aws.Int64(1)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejected for an interface not listed in networkInterfaces",
+			spec: AWSMachineSpec{
+				NetworkInterfaces: []string{"eni-1"},
+				NetworkInterfaceConfigs: []NetworkInterfaceConfig{
+					{NetworkInterfaceID: "eni-2", NetworkCardIndex: aws.Int64(1)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected when an interface is configured twice",
+			spec: AWSMachineSpec{
+				NetworkInterfaces: []string{"eni-1", "eni-2"},
+				NetworkInterfaceConfigs: []NetworkInterfaceConfig{
+					{NetworkInterfaceID: "eni-2", NetworkCardIndex: aws.Int64(1)},
+					{NetworkInterfaceID: "eni-2", NetworkCardIndex: aws.Int64(2)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepted with ENA Express for TCP and UDP",
+			spec: AWSMachineSpec{
+				NetworkInterfaces: []string{"eni-1"},
+				NetworkInterfaceConfigs: []NetworkInterfaceConfig{
+					{NetworkInterfaceID: "eni-1", EnaSrdSpecification: &EnaSrdSpecification{EnaSrdEnabled: aws.Bool(true), EnaSrdUDPEnabled: aws.Bool(true)}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejected with ENA Express for UDP only",
+			spec: AWSMachineSpec{
+				NetworkInterfaces: []string{"eni-1"},
+				NetworkInterfaceConfigs: []NetworkInterfaceConfig{
+					{NetworkInterfaceID: "eni-1", EnaSrdSpecification: &EnaSrdSpecification{EnaSrdUDPEnabled: aws.Bool(true)}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected when the primary interface is not on the first network card",
+			spec: AWSMachineSpec{
+				NetworkInterfaces: []string{"eni-1", "eni-2"},
+				NetworkInterfaceConfigs: []NetworkInterfaceConfig{
+					{NetworkInterfaceID: "eni-1", NetworkCardIndex: aws.Int64(1)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &AWSMachine{Spec: tt.spec}
+			_, err := machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachine_PrivateIP(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	previous := namespaceReader
+	namespaceReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AWSCluster", Name: "test"},
+			},
+		},
+		&AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: AWSClusterSpec{
+				NetworkSpec: NetworkSpec{
+					Subnets: Subnets{{ID: "subnet-1", CidrBlock: "10.0.1.0/24"}},
+				},
+			},
+		},
+	).Build()
+	defer func() { namespaceReader = previous }()
+
+	tests := []struct {
+		name    string
+		cluster string
+		spec    AWSMachineSpec
+		wantErr bool
+	}{
+		{
+			name:    "accepted with an address of the subnet",
+			cluster: "test",
+			spec: AWSMachineSpec{
+				Subnet:    &AWSResourceReference{ID: aws.String("subnet-1")},
+				PrivateIP: aws.String("10.0.1.10"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "rejected with an address outside of the subnet",
+			cluster: "test",
+			spec: AWSMachineSpec{
+				Subnet:    &AWSResourceReference{ID: aws.String("subnet-1")},
+				PrivateIP: aws.String("10.0.2.10"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with an address reserved by AWS",
+			cluster: "test",
+			spec: AWSMachineSpec{
+				Subnet:    &AWSResourceReference{ID: aws.String("subnet-1")},
+				PrivateIP: aws.String("10.0.1.255"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "accepted when the cluster does not exist yet",
+			cluster: "missing",
+			spec: AWSMachineSpec{
+				Subnet:    &AWSResourceReference{ID: aws.String("subnet-1")},
+				PrivateIP: aws.String("10.0.2.10"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejected with an address which is not IPv4",
+			spec: AWSMachineSpec{
+				PrivateIP: aws.String("fd00::10"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected with a pool",
+			spec: AWSMachineSpec{
+				PrivateIP: aws.String("10.0.1.10"),
+				PrivateIPFromPool: &corev1.TypedLocalObjectReference{
+					APIGroup: aws.String("ipam.cluster.x-k8s.io"),
+					Kind:     "InClusterIPPool",
+					Name:     "control-plane",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterLabelName: tt.cluster},
+				},
+				Spec: tt.spec,
+			}
+			_, err := machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidatePrivateIPInSubnet(t *testing.T) {
+	tests := []struct {
+		ip      string
+		cidr    string
+		wantErr bool
+	}{
+		{ip: "10.0.1.4", cidr: "10.0.1.0/24"},
+		{ip: "10.0.1.254", cidr: "10.0.1.0/24"},
+		{ip: "10.0.3.10", cidr: "10.0.0.0/22"},
+		{ip: "10.0.1.3", cidr: "10.0.1.0/24", wantErr: true},
+		{ip: "10.0.1.255", cidr: "10.0.1.0/24", wantErr: true},
+		{ip: "10.0.3.255", cidr: "10.0.0.0/22", wantErr: true},
+		{ip: "10.0.2.10", cidr: "10.0.1.0/24", wantErr: true},
+		{ip: "not-an-ip", cidr: "10.0.1.0/24", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip+" in "+tt.cidr, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidatePrivateIPInSubnet(tt.ip, tt.cidr)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachine_OSFamily(t *testing.T) {
+	amazonLinux := AmazonLinux
+	tests := []struct {
+		name    string
+		spec    AWSMachineSpec
+		wantErr bool
+	}{
+		{
+			name: "accepted with an OS family",
+			spec: AWSMachineSpec{AMI: AMIReference{OSFamily: OSFamilyAmazonLinux2023}},
+		},
+		{
+			name: "accepted with an EKS lookup type for AL2",
+			spec: AWSMachineSpec{AMI: AMIReference{OSFamily: OSFamilyAmazonLinux2, EKSOptimizedLookupType: &amazonLinux}},
+		},
+		{
+			name: "accepted for Bottlerocket without secret backend",
+			spec: AWSMachineSpec{
+				AMI:       AMIReference{OSFamily: OSFamilyBottlerocket},
+				CloudInit: CloudInit{InsecureSkipSecretsManager: true},
+			},
+		},
+		{
+			name:    "rejected with an AMI ID",
+			spec:    AWSMachineSpec{AMI: AMIReference{ID: aws.String("ami-1"), OSFamily: OSFamilyUbuntu}},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with an EKS lookup type for another family",
+			spec:    AWSMachineSpec{AMI: AMIReference{OSFamily: OSFamilyAmazonLinux2023, EKSOptimizedLookupType: &amazonLinux}},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with an image lookup base OS",
+			spec:    AWSMachineSpec{AMI: AMIReference{OSFamily: OSFamilyUbuntu}, ImageLookupBaseOS: "ubuntu-20.04"},
+			wantErr: true,
+		},
+		{
+			name: "rejected for Bottlerocket with an image lookup format",
+			spec: AWSMachineSpec{
+				AMI:               AMIReference{OSFamily: OSFamilyBottlerocket},
+				CloudInit:         CloudInit{InsecureSkipSecretsManager: true},
+				ImageLookupFormat: "bottlerocket-{{.K8sVersion}}-*",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "rejected for Bottlerocket with a secret backend",
+			spec:    AWSMachineSpec{AMI: AMIReference{OSFamily: OSFamilyBottlerocket}},
+			wantErr: true,
+		},
+		{
+			name: "rejected for Bottlerocket with compressed user data",
+			spec: AWSMachineSpec{
+				AMI:                  AMIReference{OSFamily: OSFamilyBottlerocket},
+				CloudInit:            CloudInit{InsecureSkipSecretsManager: true},
+				UncompressedUserData: aws.Bool(false),
+			},
+			wantErr: true,
+		},
+		{
+			name: "allowed with Bottlerocket settings",
+			spec: AWSMachineSpec{
+				AMI:       AMIReference{OSFamily: OSFamilyBottlerocket},
+				CloudInit: CloudInit{InsecureSkipSecretsManager: true},
+				Bottlerocket: &BottlerocketSettings{
+					Sysctls:         map[string]string{"net.ipv4.ip_forward": "1"},
+					AdminContainer:  true,
+					HostContainers:  []BottlerocketHostContainer{{Name: "agent", Source: "public.ecr.aws/example/agent:v1"}},
+					RegistryMirrors: []BottlerocketRegistryMirror{{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejected with Bottlerocket settings for another OS family",
+			spec: AWSMachineSpec{
+				AMI:          AMIReference{OSFamily: OSFamilyAmazonLinux2023},
+				Bottlerocket: &BottlerocketSettings{AdminContainer: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected with a host container named admin",
+			spec: AWSMachineSpec{
+				AMI:          AMIReference{OSFamily: OSFamilyBottlerocket},
+				CloudInit:    CloudInit{InsecureSkipSecretsManager: true},
+				Bottlerocket: &BottlerocketSettings{HostContainers: []BottlerocketHostContainer{{Name: "admin", Source: "admin:v1"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected with a registry mirror endpoint which is not a URL",
+			spec: AWSMachineSpec{
+				AMI:       AMIReference{OSFamily: OSFamilyBottlerocket},
+				CloudInit: CloudInit{InsecureSkipSecretsManager: true},
+				Bottlerocket: &BottlerocketSettings{
+					RegistryMirrors: []BottlerocketRegistryMirror{{Registry: "docker.io", Endpoints: []string{"mirror.example.com"}}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &AWSMachine{Spec: tt.spec}
+			_, err := machine.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,16 +26,27 @@ type AWSMachineTemplateSpec struct {
 	Template AWSMachineTemplateResource `json:"template"`
 }
 
+// AWSMachineTemplateStatus defines the observed state of AWSMachineTemplate.
+type AWSMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of a single machine created from this template,
+	// e.g. cpu, memory and nvidia.com/gpu. It is populated from the instance type and lets the
+	// cluster-autoscaler scale MachineDeployments from zero.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=awsmachinetemplates,scope=Namespaced,categories=cluster-api,shortName=awsmt
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // AWSMachineTemplate is the Schema for the awsmachinetemplates API
 type AWSMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec AWSMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   AWSMachineTemplateSpec   `json:"spec,omitempty"`
+	Status AWSMachineTemplateStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
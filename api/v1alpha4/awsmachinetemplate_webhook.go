@@ -17,29 +17,47 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 func (r *AWSMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	namespaceReader = mgr.GetAPIReader()
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmachinetemplates,versions=v1alpha4,name=validation.awsmachinetemplate.infrastructure.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
-	_ webhook.Validator = &AWSMachineTemplate{}
+	_ webhooks.Validator = &AWSMachineTemplate{}
+
+	// awsMachineTemplateMutableFields are the fields of spec.template.spec which can be changed
+	// without creating a new AWSMachineTemplate. Changes only apply to machines created afterwards.
+	awsMachineTemplateMutableFields = []string{
+		"additionalTags",
+		"scheduledEventPolicy",
+		"userDataChangePolicy",
+	}
 )
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSMachineTemplate) ValidateCreate() error {
+const awsMachineTemplateImmutableMessage = "AWSMachineTemplate spec is immutable, existing machines would not be updated. " +
+	"Create a new AWSMachineTemplate and reference it from the MachineDeployment or control plane to roll out the change"
+
+// ValidateCreate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSMachineTemplate) ValidateCreate() (webhooks.Warnings, error) {
 	var allErrs field.ErrorList
 	spec := r.Spec.Template.Spec
 
@@ -55,11 +73,27 @@ func (r *AWSMachineTemplate) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "providerID"), "cannot be set in templates"))
 	}
 
-	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+	allErrs = append(allErrs, validateManagedIAMInstanceProfile(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateInstanceTypeOffering(&spec, r.Namespace, r.Labels[clusterv1.ClusterLabelName], field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateFallbackInstanceTypes(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateLicensing(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validatePrivateIP(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validatePrivateIPFromPool(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateNetworkInterfaceConfigs(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateOSFamily(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateStrictMachine(&spec, field.NewPath("spec", "template", "spec"))...)
+
+	warnings := machineSpecWarnings(&spec, field.NewPath("spec", "template", "spec"))
+	if spec.PrivateIP != nil {
+		warnings = append(warnings, fmt.Sprintf("%s is set: all the machines created from the template request the same address, "+
+			"so only one of them can run at a time", field.NewPath("spec", "template", "spec", "privateIp")))
+	}
+
+	return warnings, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSMachineTemplate) ValidateUpdate(old runtime.Object) error {
+// ValidateUpdate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSMachineTemplate) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	oldAWSMachineTemplate := old.(*AWSMachineTemplate)
 
 	// Allow setting of cloudInit.secureSecretsBackend to "secrets-manager" only to handle v1alpha4 upgrade
@@ -67,14 +101,81 @@ func (r *AWSMachineTemplate) ValidateUpdate(old runtime.Object) error {
 		r.Spec.Template.Spec.CloudInit.SecureSecretsBackend = ""
 	}
 
-	if !reflect.DeepEqual(r.Spec, oldAWSMachineTemplate.Spec) {
-		return apierrors.NewBadRequest("AWSMachineTemplate.Spec is immutable")
+	allErrs := r.validateImmutability(oldAWSMachineTemplate)
+
+	return r.mutableFieldWarnings(oldAWSMachineTemplate), aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// validateImmutability returns an error for every field of spec.template.spec which changed,
+// except for those listed in awsMachineTemplateMutableFields.
+func (r *AWSMachineTemplate) validateImmutability(old *AWSMachineTemplate) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec", "template", "spec")
+
+	newSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&r.Spec.Template.Spec)
+	if err != nil {
+		return append(allErrs, field.InternalError(specPath, errors.Wrap(err, "failed to convert new AWSMachineTemplate spec to unstructured object")))
+	}
+	oldSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&old.Spec.Template.Spec)
+	if err != nil {
+		return append(allErrs, field.InternalError(specPath, errors.Wrap(err, "failed to convert old AWSMachineTemplate spec to unstructured object")))
+	}
+
+	for _, mutableField := range awsMachineTemplateMutableFields {
+		delete(newSpec, mutableField)
+		delete(oldSpec, mutableField)
 	}
 
-	return nil
+	changed := map[string]bool{}
+	for key, value := range newSpec {
+		if !reflect.DeepEqual(value, oldSpec[key]) {
+			changed[key] = true
+		}
+	}
+	for key := range oldSpec {
+		if _, ok := newSpec[key]; !ok {
+			changed[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child(key), awsMachineTemplateImmutableMessage))
+	}
+
+	return allErrs
+}
+
+// mutableFieldWarnings warns that changes to the mutable fields of spec.template.spec are not rolled out
+// to the machines which already exist.
+func (r *AWSMachineTemplate) mutableFieldWarnings(old *AWSMachineTemplate) webhooks.Warnings {
+	var warnings webhooks.Warnings
+	specPath := field.NewPath("spec", "template", "spec")
+
+	// Conversion errors are already reported by validateImmutability.
+	newSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&r.Spec.Template.Spec)
+	if err != nil {
+		return nil
+	}
+	oldSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&old.Spec.Template.Spec)
+	if err != nil {
+		return nil
+	}
+
+	for _, mutableField := range awsMachineTemplateMutableFields {
+		if !reflect.DeepEqual(newSpec[mutableField], oldSpec[mutableField]) {
+			warnings = append(warnings, fmt.Sprintf("%s changed: only machines created from now on use the new value", specPath.Child(mutableField)))
+		}
+	}
+	return warnings
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSMachineTemplate) ValidateDelete() error {
-	return nil
+// ValidateDelete implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSMachineTemplate) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
@@ -20,6 +20,8 @@ import (
 	"context"
 	"testing"
 
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -114,6 +116,34 @@ func TestAWSMachineTemplateValidateUpdate(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "allow changing additional tags",
+			modifiedTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							AdditionalTags: Tags{"key": "value"},
+						},
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "don't allow changing the instance type",
+			modifiedTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							InstanceType: "m5.large",
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -143,3 +173,35 @@ func TestAWSMachineTemplateValidateUpdate(t *testing.T) {
 		)
 	}
 }
+
+func TestAWSMachineTemplateValidateUpdateErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	oldTemplate := &AWSMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "template"},
+		Spec: AWSMachineTemplateSpec{
+			Template: AWSMachineTemplateResource{
+				Spec: AWSMachineSpec{
+					InstanceType:   "t3.large",
+					SSHKeyName:     pointer.String("default"),
+					AdditionalTags: Tags{"a": "b"},
+				},
+			},
+		},
+	}
+	newTemplate := oldTemplate.DeepCopy()
+	newTemplate.Spec.Template.Spec.InstanceType = "m5.large"
+	newTemplate.Spec.Template.Spec.SSHKeyName = nil
+	newTemplate.Spec.Template.Spec.AdditionalTags = Tags{"c": "d"}
+
+	_, err := newTemplate.ValidateUpdate(oldTemplate)
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+	statusErr, ok := err.(*apierrors.StatusError)
+	g.Expect(ok).To(BeTrue())
+	var fields []string
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	g.Expect(fields).To(Equal([]string{"spec.template.spec.instanceType", "spec.template.spec.sshKeyName"}))
+}
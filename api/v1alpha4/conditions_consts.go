@@ -35,6 +35,33 @@ const (
 	SourcePrincipalUsageUnauthorizedReason = "SourcePrincipalUsageUnauthorized"
 )
 
+const (
+	// PreflightChecksPassedCondition reports whether the service quotas of the AWS account leave room for the
+	// resources of a cluster. The checks only run before the network of the cluster is created.
+	PreflightChecksPassedCondition clusterv1.ConditionType = "PreflightChecksPassed"
+	// PreflightCheckFailedReason used when a service quota does not leave room for the resources of the cluster.
+	PreflightCheckFailedReason = "PreflightCheckFailed"
+)
+
+const (
+	// RegionEnabledCondition reports whether the region of a cluster is enabled for its AWS account. Regions
+	// launched after March 2019, such as me-south-1 or af-south-1, must be opted in to before they can be used.
+	// The check only runs until it succeeds once.
+	RegionEnabledCondition clusterv1.ConditionType = "RegionEnabled"
+	// RegionNotEnabledReason used when the AWS account is not opted in to the region of the cluster.
+	RegionNotEnabledReason = "RegionNotEnabled"
+	// RegionCheckFailedReason used when the opt-in status of the region of the cluster could not be checked.
+	RegionCheckFailedReason = "RegionCheckFailed"
+)
+
+const (
+	// PausedCondition reports whether the AWSCluster or its Cluster is paused. While it is true, the AWSCluster
+	// is not reconciled and no AWS session is kept for it.
+	PausedCondition clusterv1.ConditionType = "Paused"
+	// UnpausedReason used when the cluster was unpaused and is being reconciled again.
+	UnpausedReason = "Unpaused"
+)
+
 const (
 	// VpcReadyCondition reports on the successful reconciliation of a VPC.
 	VpcReadyCondition clusterv1.ConditionType = "VpcReady"
@@ -45,6 +72,15 @@ const (
 	VpcReconciliationFailedReason = "VpcReconciliationFailed"
 )
 
+const (
+	// NetworkDependenciesDeletedCondition reports whether the resources which use the network of a deleted cluster are
+	// gone, so that its security groups and network can be deleted.
+	NetworkDependenciesDeletedCondition clusterv1.ConditionType = "NetworkDependenciesDeleted"
+	// WaitingForNetworkDependenciesReason used while AWSMachines, AWSMachinePools or network interfaces still use the
+	// network of a deleted cluster.
+	WaitingForNetworkDependenciesReason = "WaitingForNetworkDependencies"
+)
+
 const (
 	// SubnetsReadyCondition reports on the successful reconciliation of subnets.
 	SubnetsReadyCondition clusterv1.ConditionType = "SubnetsReady"
@@ -60,6 +96,14 @@ const (
 	InternetGatewayFailedReason = "InternetGatewayFailed"
 )
 
+const (
+	// EgressOnlyInternetGatewayReadyCondition reports on the successful reconciliation of egress-only internet gateways.
+	// Only applicable to managed clusters with IPv6 enabled in their VPC.
+	EgressOnlyInternetGatewayReadyCondition clusterv1.ConditionType = "EgressOnlyInternetGatewayReady"
+	// EgressOnlyInternetGatewayFailedReason used when errors occur during egress-only internet gateway reconciliation.
+	EgressOnlyInternetGatewayFailedReason = "EgressOnlyInternetGatewayFailed"
+)
+
 const (
 	// NatGatewaysReadyCondition reports successful reconciliation of NAT gateways.
 	// Only applicable to managed clusters.
@@ -70,6 +114,14 @@ const (
 	NatGatewaysReconciliationFailedReason = "NatGatewaysReconciliationFailed"
 )
 
+const (
+	// NetworkFirewallReadyCondition reports successful reconciliation of the AWS Network Firewall inspecting the
+	// egress of the private subnets. Only applicable to managed clusters with spec.networkSpec.firewall set.
+	NetworkFirewallReadyCondition clusterv1.ConditionType = "NetworkFirewallReady"
+	// NetworkFirewallReconciliationFailedReason used when any errors occur during reconciliation of the firewall.
+	NetworkFirewallReconciliationFailedReason = "NetworkFirewallReconciliationFailed"
+)
+
 const (
 	// RouteTablesReadyCondition reports successful reconciliation of route tables.
 	// Only applicable to managed clusters.
@@ -93,6 +145,14 @@ const (
 	ClusterSecurityGroupReconciliationFailedReason = "SecurityGroupReconciliationFailed"
 )
 
+const (
+	// SecurityGroupPolicyCompliantCondition reports whether the ingress rules between the security groups managed
+	// for the cluster comply with its security group policy. It is only set when the cluster declares a policy.
+	SecurityGroupPolicyCompliantCondition clusterv1.ConditionType = "SecurityGroupPolicyCompliant"
+	// SecurityGroupPolicyViolationReason used when ingress rules open ports the security group policy does not allow.
+	SecurityGroupPolicyViolationReason = "SecurityGroupPolicyViolation"
+)
+
 const (
 	// BastionHostReadyCondition reports whether a bastion host is ready. Depending on the configuration, a cluster
 	// may not require a bastion host and this condition will be skipped.
@@ -103,6 +163,14 @@ const (
 	BastionHostFailedReason = "BastionHostFailed"
 )
 
+const (
+	// SSHKeyPairReadyCondition reports whether the EC2 key pair of the cluster is ready. It is only set when
+	// the cluster has an SSH key pair.
+	SSHKeyPairReadyCondition clusterv1.ConditionType = "SSHKeyPairReady"
+	// SSHKeyPairFailedReason used when the EC2 key pair of the cluster could not be created.
+	SSHKeyPairFailedReason = "SSHKeyPairFailed"
+)
+
 const (
 	// LoadBalancerReadyCondition reports on whether a control plane load balancer was successfully reconciled.
 	LoadBalancerReadyCondition clusterv1.ConditionType = "LoadBalancerReady"
@@ -110,8 +178,26 @@ const (
 	WaitForDNSNameReason = "WaitForDNSName"
 	// WaitForDNSNameResolveReason used while waiting for DNS name to resolve.
 	WaitForDNSNameResolveReason = "WaitForDNSNameResolve"
+	// WaitForDNSNameAWSReason used while waiting for the ELB API to return the load balancer with its DNS name.
+	WaitForDNSNameAWSReason = "WaitForDNSNameAWS"
 	// LoadBalancerFailedReason used when an error occurs during load balancer reconciliation.
 	LoadBalancerFailedReason = "LoadBalancerFailed"
+
+	// LoadBalancerInstancesHealthyCondition reports whether the control plane instances registered with the
+	// load balancers of the API server pass their health checks.
+	LoadBalancerInstancesHealthyCondition clusterv1.ConditionType = "LoadBalancerInstancesHealthy"
+	// LoadBalancerInstancesOutOfServiceReason used when an instance registered with a load balancer is out of service.
+	LoadBalancerInstancesOutOfServiceReason = "InstancesOutOfService"
+	// WaitingForLoadBalancerInstancesReason used when no instance is registered with a load balancer yet.
+	WaitingForLoadBalancerInstancesReason = "WaitingForInstances"
+)
+
+const (
+	// S3BucketReadyCondition reports on the successful reconciliation of the S3 bucket.
+	// Only applicable when spec.s3Bucket is set.
+	S3BucketReadyCondition clusterv1.ConditionType = "S3BucketReady"
+	// S3BucketFailedReason is used when any errors occur during reconciliation of the S3 bucket.
+	S3BucketFailedReason = "S3BucketFailed"
 )
 
 const (
@@ -130,10 +216,107 @@ const (
 	InstanceProvisionStartedReason = "InstanceProvisionStarted"
 	// InstanceProvisionFailedReason used for failures during instance provisioning.
 	InstanceProvisionFailedReason = "InstanceProvisionFailed"
+	// InstanceAdoptionFailedReason used when an existing instance can't be brought under management.
+	InstanceAdoptionFailedReason = "InstanceAdoptionFailed"
 	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// WaitingForIPAddressReason used when machine is waiting for an IPAM provider to allocate its private IP address.
+	WaitingForIPAddressReason = "WaitingForIPAddress"
+	// WaitingForInstanceProfileReason used when EC2 does not know the newly created IAM instance profile of the machine yet.
+	WaitingForInstanceProfileReason = "WaitingForInstanceProfile"
+	// InstanceTypeNotOfferedReason used when the instance type of the machine is not offered in its failure domain.
+	InstanceTypeNotOfferedReason = "InstanceTypeNotOffered"
+)
+
+const (
+	// InstanceUnexpectedTerminationCondition is set to true when the EC2 instance was shut down or terminated
+	// without the AWSMachine being deleted. The reason describes who initiated the termination.
+	InstanceUnexpectedTerminationCondition clusterv1.ConditionType = "InstanceUnexpectedTermination"
+
+	// InstanceSpotInterruptedReason used when AWS reclaimed a spot instance.
+	InstanceSpotInterruptedReason = "InstanceSpotInterrupted"
+	// InstanceUserTerminatedReason used when the instance was terminated through the EC2 API or shut down from within the OS.
+	InstanceUserTerminatedReason = "InstanceUserTerminated"
+	// InstanceAWSInitiatedTerminationReason used when AWS stopped or terminated the instance, for example because
+	// of a scheduled retirement or an internal error.
+	InstanceAWSInitiatedTerminationReason = "InstanceAWSInitiatedTermination"
+	// InstanceTerminationUnknownReason used when EC2 did not report why the instance was terminated.
+	InstanceTerminationUnknownReason = "InstanceTerminationUnknown"
+)
+
+const (
+	// InstanceScheduledEventsCondition is set to true when EC2 has scheduled events for the instance, such as a
+	// retirement or a reboot. The reason describes the earliest scheduled event.
+	InstanceScheduledEventsCondition clusterv1.ConditionType = "InstanceScheduledEvents"
+
+	// InstanceRetirementScheduledReason used when the instance is scheduled to be retired.
+	InstanceRetirementScheduledReason = "InstanceRetirementScheduled"
+	// InstanceStopScheduledReason used when the instance is scheduled to be stopped.
+	InstanceStopScheduledReason = "InstanceStopScheduled"
+	// InstanceRebootScheduledReason used when the instance or the underlying host is scheduled to be rebooted.
+	InstanceRebootScheduledReason = "InstanceRebootScheduled"
+	// SystemMaintenanceScheduledReason used when maintenance of the underlying host is scheduled.
+	SystemMaintenanceScheduledReason = "SystemMaintenanceScheduled"
+)
+
+const (
+	// InstanceSystemStatusImpairedCondition is set to true when EC2 reports the system status of the instance as
+	// impaired, which means the underlying host has a problem, and the node of the machine is tainted.
+	InstanceSystemStatusImpairedCondition clusterv1.ConditionType = "InstanceSystemStatusImpaired"
+
+	// SystemStatusCheckFailedReason used when a system status check of the instance failed.
+	SystemStatusCheckFailedReason = "SystemStatusCheckFailed"
+)
+
+const (
+	// UserDataOutOfDateCondition is set to true when the bootstrap data of the machine changed after its
+	// instance was launched, so the instance does not run the current bootstrap data.
+	UserDataOutOfDateCondition clusterv1.ConditionType = "UserDataOutOfDate"
+
+	// BootstrapDataChangedReason used when the bootstrap data secret no longer matches the user data of the instance.
+	BootstrapDataChangedReason = "BootstrapDataChanged"
+)
+
+const (
+	// ProviderIDConsistentCondition reports whether the provider ID of the node of the machine refers to its
+	// instance in the format of the AWSMachine. It is only set when the controller runs with --provider-id-format.
+	ProviderIDConsistentCondition clusterv1.ConditionType = "ProviderIDConsistent"
+
+	// NodeProviderIDInstanceMismatchReason used when the node of the machine has the provider ID of another instance.
+	NodeProviderIDInstanceMismatchReason = "NodeProviderIDInstanceMismatch"
+	// NodeProviderIDFormatMismatchReason used when the provider ID of the node of the machine is in another format.
+	NodeProviderIDFormatMismatchReason = "NodeProviderIDFormatMismatch"
+)
+
+const (
+	// SpotMaxPriceBelowMarketCondition is set to true when the spot max price of the machine is below the current
+	// spot price of its instance type, so EC2 will not launch the instance until the price drops.
+	SpotMaxPriceBelowMarketCondition clusterv1.ConditionType = "SpotMaxPriceBelowMarket"
+
+	// SpotPriceAboveMaxPriceReason used when the current spot price exceeds the configured max price.
+	SpotPriceAboveMaxPriceReason = "SpotPriceAboveMaxPrice"
+)
+
+const (
+	// APIServerReachableCondition reports whether the instance of the machine can reach the API server load
+	// balancer of the cluster. It is only set when the InstanceConnectivityCheck feature gate is enabled, or by an
+	// analysis requested with the AnalyzeConnectivityAnnotation.
+	APIServerReachableCondition clusterv1.ConditionType = "APIServerReachable"
+
+	// APIServerRouteMissingReason used when the route table of the subnet of the instance has no route to the
+	// API server load balancer.
+	APIServerRouteMissingReason = "APIServerRouteMissing"
+	// APIServerUnreachableReason used when the VPC Reachability Analyzer found no path from the instance to the
+	// API server load balancer, for example because of a security group or network ACL.
+	APIServerUnreachableReason = "APIServerUnreachable"
+	// ConnectivityAnalysisRunningReason used while the path from the instance to the API server load balancer
+	// is being analyzed.
+	ConnectivityAnalysisRunningReason = "ConnectivityAnalysisRunning"
+	// ConnectivityCheckFailedReason used when the path from the instance to the API server load balancer could
+	// not be checked.
+	ConnectivityCheckFailedReason = "ConnectivityCheckFailed"
 )
 
 const (
@@ -156,3 +339,17 @@ const (
 	// ELBDetachFailedReason used when a control plane node fails to detach from an ELB.
 	ELBDetachFailedReason = "ELBDetachFailed"
 )
+
+// Reasons used instead of the failure reason of a condition when the failure is caused by a classified AWS error.
+const (
+	// AWSThrottledReason used when AWS rate limited the requests of the controller.
+	AWSThrottledReason = "AWSThrottled"
+	// AWSAccessDeniedReason used when the AWS credentials of the controller are invalid, expired or not authorized.
+	AWSAccessDeniedReason = "AWSAccessDenied"
+	// AWSInsufficientCapacityReason used when AWS has no capacity for a resource or an account limit is reached.
+	AWSInsufficientCapacityReason = "AWSInsufficientCapacity"
+	// AWSDependencyFailedReason used when a resource depends on, or is used by, a resource which is not in the required state.
+	AWSDependencyFailedReason = "AWSDependencyFailed"
+	// AWSInvalidParameterReason used when AWS rejected a request as invalid, usually because of the resource configuration.
+	AWSInvalidParameterReason = "AWSInvalidParameter"
+)
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InstanceTypeOfferingChecker reports whether an EC2 instance type is offered in an availability zone, looked up
+// with the identity and in the region of the AWSCluster, as the names of the availability zones differ between accounts.
+type InstanceTypeOfferingChecker interface {
+	IsInstanceTypeOffered(cluster *clusterv1.Cluster, awsCluster *AWSCluster, instanceType, availabilityZone string) (bool, error)
+}
+
+var instanceTypeOfferingChecker InstanceTypeOfferingChecker
+
+// SetInstanceTypeOfferingChecker makes the AWSMachine and AWSMachineTemplate webhooks reject instance types
+// which are not offered in the failure domain of the machine.
+func SetInstanceTypeOfferingChecker(checker InstanceTypeOfferingChecker) {
+	instanceTypeOfferingChecker = checker
+}
+
+// validateInstanceTypeOffering rejects an instance type which is not offered in the failure domain of the machine.
+// Machines without a failure domain or whose AWSCluster doesn't exist yet are not checked, nor are they if the
+// offerings can't be looked up: the AWSMachine controller checks them again before launching the instance.
+func validateInstanceTypeOffering(spec *AWSMachineSpec, namespace, clusterName string, fldPath *field.Path) field.ErrorList {
+	if instanceTypeOfferingChecker == nil || namespaceReader == nil || clusterName == "" ||
+		spec.InstanceType == "" || spec.FailureDomain == nil || *spec.FailureDomain == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), namespaceLookupTimeout)
+	defer cancel()
+
+	cluster, awsCluster, err := getAWSCluster(ctx, namespace, clusterName)
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath.Child("instanceType"), err)}
+	}
+	if awsCluster == nil {
+		return nil
+	}
+
+	offered, err := instanceTypeOfferingChecker.IsInstanceTypeOffered(cluster, awsCluster, spec.InstanceType, *spec.FailureDomain)
+	if err != nil {
+		validateLog.Error(err, "unable to look up instance type offerings", "cluster", clusterName,
+			"instance-type", spec.InstanceType, "availability-zone", *spec.FailureDomain)
+		return nil
+	}
+
+	if !offered {
+		return field.ErrorList{field.Invalid(fldPath.Child("instanceType"), spec.InstanceType,
+			fmt.Sprintf("instance type is not offered in availability zone %q", *spec.FailureDomain))}
+	}
+	return nil
+}
+
+// getAWSCluster returns the cluster of the given name and its AWSCluster, or nils if either doesn't exist
+// or the infrastructure of the cluster is not an AWSCluster.
+func getAWSCluster(ctx context.Context, namespace, clusterName string) (*clusterv1.Cluster, *AWSCluster, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := namespaceReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.Wrapf(err, "failed to get cluster %q", clusterName)
+	}
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "AWSCluster" {
+		return nil, nil, nil
+	}
+
+	awsCluster := &AWSCluster{}
+	if err := namespaceReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, awsCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.Wrapf(err, "failed to get AWSCluster %q", ref.Name)
+	}
+	return cluster, awsCluster, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NamespaceDefaultIdentityAnnotation is the annotation on a namespace that names the identity of the
+// AWSClusters created in the namespace without an identityRef, in the form <kind>/<name>, e.g.
+// AWSClusterRoleIdentity/team-a. AWSClusters in such a namespace can't use the controller identity.
+const NamespaceDefaultIdentityAnnotation = "aws.cluster.x-k8s.io/default-identity"
+
+// namespaceLookupTimeout bounds how long a webhook waits for the namespace of an object.
+const namespaceLookupTimeout = 5 * time.Second
+
+// namespaceReader reads the namespaces of the objects admitted by the webhooks, and the clusters of the
+// machines. The webhooks are constructed by controller-runtime without access to the manager, so it is set
+// when they are set up. Namespace default identities are not applied while it is nil.
+var namespaceReader client.Reader
+
+// ParseNamespaceDefaultIdentity parses the value of the NamespaceDefaultIdentityAnnotation.
+func ParseNamespaceDefaultIdentity(value string) (*AWSIdentityReference, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid default identity %q, expected <kind>/<name>", value)
+	}
+
+	kind := AWSIdentityKind(parts[0])
+	switch kind {
+	case ClusterRoleIdentityKind, ClusterStaticIdentityKind:
+	default:
+		return nil, fmt.Errorf("invalid default identity %q, kind must be %s or %s", value, ClusterRoleIdentityKind, ClusterStaticIdentityKind)
+	}
+
+	return &AWSIdentityReference{Kind: kind, Name: parts[1]}, nil
+}
+
+// namespaceDefaultIdentity returns the default identity of the namespace, or nil if it has none.
+func namespaceDefaultIdentity(namespace string) (*AWSIdentityReference, error) {
+	if namespaceReader == nil || namespace == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), namespaceLookupTimeout)
+	defer cancel()
+
+	ns := &corev1.Namespace{}
+	if err := namespaceReader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return nil, fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+	}
+
+	value, ok := ns.Annotations[NamespaceDefaultIdentityAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	return ParseNamespaceDefaultIdentity(value)
+}
+
+// defaultNamespaceIdentity sets the identity to the default identity of the namespace if none is set. An
+// identity that can't be looked up is left for the validating webhook to reject.
+func defaultNamespaceIdentity(namespace string, spec *AWSClusterSpec) {
+	if spec.IdentityRef != nil {
+		return
+	}
+	if ref, err := namespaceDefaultIdentity(namespace); err == nil {
+		spec.IdentityRef = ref
+	}
+}
+
+// validateNamespaceIdentity rejects the controller identity in a namespace that has a default identity.
+func validateNamespaceIdentity(namespace string, ref *AWSIdentityReference, fldPath *field.Path) field.ErrorList {
+	defaultRef, err := namespaceDefaultIdentity(namespace)
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+
+	if defaultRef != nil && ref != nil && ref.Kind == ControllerIdentityKind {
+		return field.ErrorList{field.Forbidden(fldPath,
+			fmt.Sprintf("the controller identity can't be used in namespace %q, which defaults to %s/%s", namespace, defaultRef.Kind, defaultRef.Name))}
+	}
+	return nil
+}
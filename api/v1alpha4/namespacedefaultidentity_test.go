@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseNamespaceDefaultIdentity(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *AWSIdentityReference
+		wantErr bool
+	}{
+		{value: "AWSClusterRoleIdentity/team-a", want: &AWSIdentityReference{Kind: ClusterRoleIdentityKind, Name: "team-a"}},
+		{value: "AWSClusterStaticIdentity/team-b", want: &AWSIdentityReference{Kind: ClusterStaticIdentityKind, Name: "team-b"}},
+		{value: "AWSClusterControllerIdentity/default", wantErr: true},
+		{value: "team-a", wantErr: true},
+		{value: "AWSClusterRoleIdentity/", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := ParseNamespaceDefaultIdentity(tc.value)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestAWSCluster_NamespaceDefaultIdentity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	previous := namespaceReader
+	namespaceReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{NamespaceDefaultIdentityAnnotation: "AWSClusterRoleIdentity/team-a"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+	).Build()
+	defer func() { namespaceReader = previous }()
+
+	tests := []struct {
+		name         string
+		namespace    string
+		identityRef  *AWSIdentityReference
+		wantIdentity *AWSIdentityReference
+		wantErr      bool
+	}{
+		{
+			name:         "namespace default identity is used when none is set",
+			namespace:    "team-a",
+			wantIdentity: &AWSIdentityReference{Kind: ClusterRoleIdentityKind, Name: "team-a"},
+		},
+		{
+			name:         "identity set on the cluster is kept",
+			namespace:    "team-a",
+			identityRef:  &AWSIdentityReference{Kind: ClusterStaticIdentityKind, Name: "other"},
+			wantIdentity: &AWSIdentityReference{Kind: ClusterStaticIdentityKind, Name: "other"},
+		},
+		{
+			name:        "controller identity is rejected in a namespace with a default identity",
+			namespace:   "team-a",
+			identityRef: &AWSIdentityReference{Kind: ControllerIdentityKind, Name: AWSClusterControllerIdentityName},
+			wantErr:     true,
+		},
+		{
+			name:         "controller identity is used in a namespace without a default identity",
+			namespace:    "shared",
+			wantIdentity: &AWSIdentityReference{Kind: ControllerIdentityKind, Name: AWSClusterControllerIdentityName},
+		},
+		{
+			name:      "missing namespace is rejected",
+			namespace: "missing",
+			wantErr:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: tc.namespace},
+				Spec:       AWSClusterSpec{IdentityRef: tc.identityRef},
+			}
+			cluster.Default()
+
+			_, err := cluster.ValidateCreate()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cluster.Spec.IdentityRef).To(Equal(tc.wantIdentity))
+		})
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+)
+
+// awsReservedTagPrefix is the prefix of the tag keys reserved by AWS, which can't be set by users.
+const awsReservedTagPrefix = "aws:"
+
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// volumeIOPSLimits are the provisioned IOPS ranges of the EBS volume types that support them,
+// and the maximum ratio of IOPS to the size of the volume in GiB.
+var volumeIOPSLimits = map[string]struct {
+	min, max, perGiB int64
+}{
+	"gp3": {min: 3000, max: 16000, perGiB: 500},
+	"io1": {min: 100, max: 64000, perGiB: 50},
+	"io2": {min: 100, max: 256000, perGiB: 1000},
+}
+
+// volumeMinSizes are the minimum sizes in GiB of the EBS volume types larger than the minimum
+// size of a Volume.
+var volumeMinSizes = map[string]int64{
+	"st1": 125,
+	"sc1": 125,
+}
+
+var knownVolumeTypes = map[string]bool{
+	"standard": true,
+	"gp2":      true,
+	"gp3":      true,
+	"io1":      true,
+	"io2":      true,
+	"st1":      true,
+	"sc1":      true,
+}
+
+// validateStrictCluster runs the checks of the StrictValidation feature gate on an AWSCluster spec.
+func validateStrictCluster(spec *AWSClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !feature.Gates.Enabled(feature.StrictValidation) {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateSubnetOverlap(spec.NetworkSpec.Subnets, fldPath.Child("networkSpec", "subnets"))...)
+	if cni := spec.NetworkSpec.CNI; cni != nil {
+		for i, rule := range cni.CNIIngressRules {
+			allErrs = append(allErrs, validateIngressRulePorts(rule.Protocol, rule.FromPort, rule.ToPort, fldPath.Child("networkSpec", "cni", "cniIngressRules").Index(i))...)
+		}
+	}
+	allErrs = append(allErrs, validateTagKeys(spec.AdditionalTags, fldPath.Child("additionalTags"))...)
+
+	return allErrs
+}
+
+// validateStrictMachine runs the checks of the StrictValidation feature gate on an AWSMachine spec.
+// An AMI that doesn't match the architecture of the instance type is returned as an admission
+// warning by machineSpecWarnings instead.
+func validateStrictMachine(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !feature.Gates.Enabled(feature.StrictValidation) {
+		return allErrs
+	}
+
+	if spec.RootVolume != nil {
+		allErrs = append(allErrs, validateVolume(spec.RootVolume, fldPath.Child("rootVolume"))...)
+	}
+	for i := range spec.NonRootVolumes {
+		allErrs = append(allErrs, validateVolume(&spec.NonRootVolumes[i], fldPath.Child("nonRootVolumes").Index(i))...)
+	}
+	allErrs = append(allErrs, validateTagKeys(spec.AdditionalTags, fldPath.Child("additionalTags"))...)
+
+	return allErrs
+}
+
+// validateSubnetOverlap rejects subnets to be created whose CIDR blocks overlap each other.
+// Subnets with an ID already exist, EC2 has already checked them.
+func validateSubnetOverlap(subnets Subnets, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	networks := make([]*net.IPNet, len(subnets))
+	for i, subnet := range subnets {
+		if subnet.ID != "" || subnet.CidrBlock == "" {
+			continue
+		}
+		_, subnetNet, err := net.ParseCIDR(subnet.CidrBlock)
+		if err != nil {
+			// Invalid CIDR blocks are reported by validateNetwork.
+			continue
+		}
+		for j := 0; j < i; j++ {
+			if networks[j] != nil && (networks[j].Contains(subnetNet.IP) || subnetNet.Contains(networks[j].IP)) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("cidrBlock"), subnet.CidrBlock,
+					fmt.Sprintf("overlaps with the CIDR block %s of subnet %d", subnets[j].CidrBlock, j)))
+				break
+			}
+		}
+		networks[i] = subnetNet
+	}
+
+	return allErrs
+}
+
+// validateIngressRulePorts checks the protocol of a security group rule and that its port range
+// is valid for the protocol. For ICMP the ports are the ICMP type and code.
+func validateIngressRulePorts(protocol SecurityGroupProtocol, fromPort, toPort int64, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch protocol {
+	case SecurityGroupProtocolAll, SecurityGroupProtocolIPinIP:
+		// EC2 ignores the ports of these protocols.
+	case SecurityGroupProtocolTCP, SecurityGroupProtocolUDP:
+		if fromPort < 0 || fromPort > 65535 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("fromPort"), fromPort, "must be between 0 and 65535"))
+		}
+		if toPort < 0 || toPort > 65535 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("toPort"), toPort, "must be between 0 and 65535"))
+		}
+		if fromPort > toPort {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("toPort"), toPort, fmt.Sprintf("must not be lower than fromPort %d", fromPort)))
+		}
+	case SecurityGroupProtocolICMP, SecurityGroupProtocolICMPv6:
+		if fromPort < -1 || fromPort > 255 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("fromPort"), fromPort, "must be an ICMP type between 0 and 255, or -1 for all types"))
+		}
+		if toPort < -1 || toPort > 255 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("toPort"), toPort, "must be an ICMP code between 0 and 255, or -1 for all codes"))
+		}
+	default:
+		if number, err := strconv.Atoi(string(protocol)); err != nil || number < 0 || number > 255 {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("protocol"), protocol,
+				[]string{"tcp", "udp", "icmp", "-1", "an IP protocol number between 0 and 255"}))
+		}
+	}
+
+	return allErrs
+}
+
+// validateVolume checks that the type, size and IOPS of an EBS volume are compatible.
+func validateVolume(volume *Volume, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if volume.Type != "" && !knownVolumeTypes[volume.Type] {
+		return append(allErrs, field.NotSupported(fldPath.Child("type"), volume.Type,
+			[]string{"standard", "gp2", "gp3", "io1", "io2", "st1", "sc1"}))
+	}
+
+	if minSize, ok := volumeMinSizes[volume.Type]; ok && volume.Size < minSize {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), volume.Size, fmt.Sprintf("must be at least %d for %s volumes", minSize, volume.Type)))
+	}
+
+	if volume.IOPS == 0 {
+		return allErrs
+	}
+
+	limits, ok := volumeIOPSLimits[volume.Type]
+	if !ok {
+		return append(allErrs, field.Forbidden(fldPath.Child("iops"), "can only be set for gp3, io1 and io2 volumes"))
+	}
+	if volume.IOPS < limits.min || volume.IOPS > limits.max {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("iops"), volume.IOPS,
+			fmt.Sprintf("must be between %d and %d for %s volumes", limits.min, limits.max, volume.Type)))
+	} else if volume.IOPS > limits.perGiB*volume.Size {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("iops"), volume.IOPS,
+			fmt.Sprintf("must not exceed %d IOPS per GiB of size for %s volumes", limits.perGiB, volume.Type)))
+	}
+
+	return allErrs
+}
+
+// validateTagKeys rejects tags which EC2 refuses to create.
+func validateTagKeys(tags Tags, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for key, value := range tags {
+		keyPath := fldPath.Key(key)
+		switch {
+		case key == "":
+			allErrs = append(allErrs, field.Invalid(keyPath, key, "tag keys must not be empty"))
+		case strings.HasPrefix(strings.ToLower(key), awsReservedTagPrefix):
+			allErrs = append(allErrs, field.Invalid(keyPath, key, fmt.Sprintf("tag keys must not start with the reserved prefix %q", awsReservedTagPrefix)))
+		case len(key) > maxTagKeyLength:
+			allErrs = append(allErrs, field.TooLong(keyPath, key, maxTagKeyLength))
+		}
+		if len(value) > maxTagValueLength {
+			allErrs = append(allErrs, field.TooLong(keyPath, value, maxTagValueLength))
+		}
+	}
+
+	return allErrs
+}
+
+// ImageArchitectureChecker looks up the processor architectures of EC2 instance types and AMIs
+// in the region of an availability zone.
+type ImageArchitectureChecker interface {
+	InstanceTypeArchitectures(instanceType, availabilityZone string) ([]string, error)
+	ImageArchitecture(imageID, availabilityZone string) (string, error)
+}
+
+var imageArchitectureChecker ImageArchitectureChecker
+
+// SetImageArchitectureChecker makes the AWSMachine and AWSMachineTemplate webhooks warn about AMIs
+// which can't run on the instance type of the machine, when the StrictValidation feature gate is enabled.
+func SetImageArchitectureChecker(checker ImageArchitectureChecker) {
+	imageArchitectureChecker = checker
+}
+
+// imageArchitectureWarning returns a warning if the AMI of the machine can't run on its instance type,
+// or an empty string. The region is only known from the failure domain, so machines without one aren't checked.
+func imageArchitectureWarning(spec *AWSMachineSpec) string {
+	if imageArchitectureChecker == nil || spec.InstanceType == "" || spec.AMI.ID == nil || *spec.AMI.ID == "" ||
+		spec.FailureDomain == nil || *spec.FailureDomain == "" {
+		return ""
+	}
+
+	imageArchitecture, err := imageArchitectureChecker.ImageArchitecture(*spec.AMI.ID, *spec.FailureDomain)
+	if err != nil {
+		validateLog.Error(err, "unable to look up image architecture", "ami", *spec.AMI.ID)
+		return ""
+	}
+	instanceTypeArchitectures, err := imageArchitectureChecker.InstanceTypeArchitectures(spec.InstanceType, *spec.FailureDomain)
+	if err != nil {
+		validateLog.Error(err, "unable to look up instance type architectures", "instance-type", spec.InstanceType)
+		return ""
+	}
+
+	for _, architecture := range instanceTypeArchitectures {
+		if architecture == imageArchitecture {
+			return ""
+		}
+	}
+	return fmt.Sprintf("AMI %s has architecture %s, which is not supported by instance type %s (%s)",
+		*spec.AMI.ID, imageArchitecture, spec.InstanceType, strings.Join(instanceTypeArchitectures, ", "))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/aws-sdk-go/aws"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+)
+
+func TestValidateStrictCluster(t *testing.T) {
+	tests := []struct {
+		name           string
+		featureEnabled bool
+		spec           AWSClusterSpec
+		wantErrs       int
+	}{
+		{
+			name: "overlapping subnets are accepted without strict validation",
+			spec: AWSClusterSpec{NetworkSpec: NetworkSpec{Subnets: Subnets{
+				{CidrBlock: "10.0.0.0/24"}, {CidrBlock: "10.0.0.128/25"},
+			}}},
+		},
+		{
+			name:           "overlapping subnets are rejected",
+			featureEnabled: true,
+			spec: AWSClusterSpec{NetworkSpec: NetworkSpec{Subnets: Subnets{
+				{CidrBlock: "10.0.0.0/24"}, {CidrBlock: "10.0.1.0/24"}, {CidrBlock: "10.0.0.128/25"},
+			}}},
+			wantErrs: 1,
+		},
+		{
+			name:           "existing subnets are not checked for overlaps",
+			featureEnabled: true,
+			spec: AWSClusterSpec{NetworkSpec: NetworkSpec{Subnets: Subnets{
+				{ID: "subnet-1", CidrBlock: "10.0.0.0/24"}, {CidrBlock: "10.0.0.0/24"},
+			}}},
+		},
+		{
+			name:           "invalid CNI ingress rules are rejected",
+			featureEnabled: true,
+			spec: AWSClusterSpec{NetworkSpec: NetworkSpec{CNI: &CNISpec{CNIIngressRules: CNIIngressRules{
+				{Protocol: SecurityGroupProtocolTCP, FromPort: 179, ToPort: 179},
+				{Protocol: SecurityGroupProtocolUDP, FromPort: 8472, ToPort: 4789},
+				{Protocol: SecurityGroupProtocolTCP, FromPort: 0, ToPort: 70000},
+				{Protocol: SecurityGroupProtocolAll, FromPort: -1, ToPort: 65535},
+				{Protocol: SecurityGroupProtocolICMP, FromPort: -1, ToPort: -1},
+				{Protocol: "50", FromPort: -1, ToPort: -1},
+				{Protocol: "sctp", FromPort: 0, ToPort: 0},
+			}}}},
+			wantErrs: 3,
+		},
+		{
+			name:           "reserved tag keys are rejected",
+			featureEnabled: true,
+			spec:           AWSClusterSpec{AdditionalTags: Tags{"aws:cloudformation:stack-name": "x", "AWS:foo": "y", "team": "a"}},
+			wantErrs:       2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.StrictValidation, tt.featureEnabled)()
+			g := NewWithT(t)
+
+			errs := validateStrictCluster(&tt.spec, nil)
+			g.Expect(errs).To(HaveLen(tt.wantErrs), "%v", errs)
+		})
+	}
+}
+
+func TestValidateVolume(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  Volume
+		wantErr bool
+	}{
+		{name: "gp2 without iops", volume: Volume{Size: 8, Type: "gp2"}},
+		{name: "gp2 with iops", volume: Volume{Size: 8, Type: "gp2", IOPS: 100}, wantErr: true},
+		{name: "gp3 with iops", volume: Volume{Size: 100, Type: "gp3", IOPS: 4000}},
+		{name: "gp3 with too few iops", volume: Volume{Size: 100, Type: "gp3", IOPS: 1000}, wantErr: true},
+		{name: "io1 exceeding the iops per GiB", volume: Volume{Size: 10, Type: "io1", IOPS: 1000}, wantErr: true},
+		{name: "io2 with iops", volume: Volume{Size: 10, Type: "io2", IOPS: 1000}},
+		{name: "small st1", volume: Volume{Size: 100, Type: "st1"}, wantErr: true},
+		{name: "unknown type", volume: Volume{Size: 8, Type: "gp9"}, wantErr: true},
+		{name: "default type", volume: Volume{Size: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateVolume(&tt.volume, nil)
+			if tt.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+type fakeImageArchitectureChecker struct {
+	instanceTypes map[string][]string
+	images        map[string]string
+}
+
+func (f *fakeImageArchitectureChecker) InstanceTypeArchitectures(instanceType, _ string) ([]string, error) {
+	architectures, ok := f.instanceTypes[instanceType]
+	if !ok {
+		return nil, errors.New("unknown instance type")
+	}
+	return architectures, nil
+}
+
+func (f *fakeImageArchitectureChecker) ImageArchitecture(imageID, _ string) (string, error) {
+	architecture, ok := f.images[imageID]
+	if !ok {
+		return "", errors.New("unknown image")
+	}
+	return architecture, nil
+}
+
+func TestImageArchitectureWarning(t *testing.T) {
+	previous := imageArchitectureChecker
+	SetImageArchitectureChecker(&fakeImageArchitectureChecker{
+		instanceTypes: map[string][]string{"m5.large": {"i386", "x86_64"}, "m6g.large": {"arm64"}},
+		images:        map[string]string{"ami-amd64": "x86_64"},
+	})
+	defer SetImageArchitectureChecker(previous)
+
+	tests := []struct {
+		name        string
+		spec        AWSMachineSpec
+		wantWarning bool
+	}{
+		{
+			name: "matching architecture",
+			spec: AWSMachineSpec{InstanceType: "m5.large", AMI: AMIReference{ID: aws.String("ami-amd64")}, FailureDomain: aws.String("us-east-1a")},
+		},
+		{
+			name:        "mismatching architecture",
+			spec:        AWSMachineSpec{InstanceType: "m6g.large", AMI: AMIReference{ID: aws.String("ami-amd64")}, FailureDomain: aws.String("us-east-1a")},
+			wantWarning: true,
+		},
+		{
+			name: "no failure domain",
+			spec: AWSMachineSpec{InstanceType: "m6g.large", AMI: AMIReference{ID: aws.String("ami-amd64")}},
+		},
+		{
+			name: "lookup error",
+			spec: AWSMachineSpec{InstanceType: "m6g.large", AMI: AMIReference{ID: aws.String("ami-unknown")}, FailureDomain: aws.String("us-east-1a")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(imageArchitectureWarning(&tt.spec) != "").To(Equal(tt.wantWarning))
+		})
+	}
+}
+
+func TestValidateStrictMachine(t *testing.T) {
+	spec := &AWSMachineSpec{
+		InstanceType:   "m5.large",
+		RootVolume:     &Volume{Size: 8, Type: "gp2", IOPS: 3000},
+		NonRootVolumes: []Volume{{DeviceName: "/dev/sdb", Size: 100, Type: "sc1"}},
+		AdditionalTags: Tags{"aws:foo": "bar"},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.StrictValidation, false)()
+		g := NewWithT(t)
+		g.Expect(validateStrictMachine(spec, nil)).To(BeEmpty())
+	})
+	t.Run("enabled", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.StrictValidation, true)()
+		g := NewWithT(t)
+		g.Expect(validateStrictMachine(spec, nil)).To(HaveLen(3))
+	})
+}
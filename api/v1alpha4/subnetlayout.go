@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+// NetworkDefaulter computes the VPC CIDR block and subnets that the AWSCluster controller would
+// otherwise pick when it creates the network of a cluster.
+type NetworkDefaulter interface {
+	DefaultNetwork(region string, network *NetworkSpec) error
+}
+
+var networkDefaulter NetworkDefaulter
+
+// SetNetworkDefaulter makes the AWSCluster defaulting webhook persist the subnet layout of new clusters
+// with a managed VPC and no subnets.
+func SetNetworkDefaulter(defaulter NetworkDefaulter) {
+	networkDefaulter = defaulter
+}
+
+// defaultSubnetLayout sets the subnets of a new cluster with a managed VPC and no subnets. The spec is
+// left unchanged if the layout can't be computed, the controller picks the subnets then.
+func (r *AWSCluster) defaultSubnetLayout() {
+	network := &r.Spec.NetworkSpec
+	if networkDefaulter == nil || !r.CreationTimestamp.IsZero() || r.Spec.Region == "" ||
+		network.VPC.ID != "" || len(network.Subnets) > 0 {
+		return
+	}
+
+	// The availability zones are looked up with the credentials of the controller, whose account
+	// may not be the one the cluster is created in.
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != ControllerIdentityKind {
+		return
+	}
+
+	if err := networkDefaulter.DefaultNetwork(r.Spec.Region, network); err != nil {
+		validateLog.Error(err, "unable to compute the default subnets", "namespace", r.Namespace, "name", r.Name, "region", r.Spec.Region)
+	}
+}
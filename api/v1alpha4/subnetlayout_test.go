@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeNetworkDefaulter struct {
+	err error
+}
+
+func (f *fakeNetworkDefaulter) DefaultNetwork(region string, network *NetworkSpec) error {
+	if f.err != nil {
+		return f.err
+	}
+	network.VPC.CidrBlock = "10.0.0.0/16"
+	network.Subnets = Subnets{{CidrBlock: "10.0.0.0/17", AvailabilityZone: region + "a", IsPublic: true}}
+	return nil
+}
+
+func TestAWSCluster_DefaultSubnetLayout(t *testing.T) {
+	tests := []struct {
+		name        string
+		cluster     *AWSCluster
+		err         error
+		wantSubnets Subnets
+	}{
+		{
+			name:        "new cluster without subnets",
+			cluster:     &AWSCluster{Spec: AWSClusterSpec{Region: "us-east-1"}},
+			wantSubnets: Subnets{{CidrBlock: "10.0.0.0/17", AvailabilityZone: "us-east-1a", IsPublic: true}},
+		},
+		{
+			name: "new cluster with subnets",
+			cluster: &AWSCluster{Spec: AWSClusterSpec{Region: "us-east-1", NetworkSpec: NetworkSpec{
+				Subnets: Subnets{{CidrBlock: "10.0.1.0/24", AvailabilityZone: "us-east-1b"}},
+			}}},
+			wantSubnets: Subnets{{CidrBlock: "10.0.1.0/24", AvailabilityZone: "us-east-1b"}},
+		},
+		{
+			name: "unmanaged VPC",
+			cluster: &AWSCluster{Spec: AWSClusterSpec{Region: "us-east-1", NetworkSpec: NetworkSpec{
+				VPC: VPCSpec{ID: "vpc-1"},
+			}}},
+		},
+		{
+			name: "existing cluster",
+			cluster: &AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+				Spec:       AWSClusterSpec{Region: "us-east-1"},
+			},
+		},
+		{
+			name: "cluster with a role identity",
+			cluster: &AWSCluster{Spec: AWSClusterSpec{
+				Region:      "us-east-1",
+				IdentityRef: &AWSIdentityReference{Kind: ClusterRoleIdentityKind, Name: "team-a"},
+			}},
+		},
+		{
+			name:    "lookup error",
+			cluster: &AWSCluster{Spec: AWSClusterSpec{Region: "us-east-1"}},
+			err:     errors.New("throttled"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			previous := networkDefaulter
+			SetNetworkDefaulter(&fakeNetworkDefaulter{err: tt.err})
+			defer SetNetworkDefaulter(previous)
+
+			tt.cluster.defaultSubnetLayout()
+			g.Expect(tt.cluster.Spec.NetworkSpec.Subnets).To(Equal(tt.wantSubnets))
+		})
+	}
+}
@@ -111,12 +111,19 @@ const (
 	// dedicated to this cluster api provider implementation.
 	NameAWSSubnetAssociation = NameAWSProviderPrefix + "association"
 
+	// NameKarpenterDiscovery is the tag name Karpenter uses to discover the subnets and security groups
+	// of the nodes it launches. The tag value is the name of the cluster.
+	NameKarpenterDiscovery = "karpenter.sh/discovery"
+
 	// SecondarySubnetTagValue is the secondary subnet tag constant value.
 	SecondarySubnetTagValue = "secondary"
 
 	// APIServerRoleTagValue describes the value for the apiserver role.
 	APIServerRoleTagValue = "apiserver"
 
+	// InternalAPIServerRoleTagValue describes the value for the internal apiserver role.
+	InternalAPIServerRoleTagValue = "apiserver-internal"
+
 	// BastionRoleTagValue describes the value for the bastion role.
 	BastionRoleTagValue = "bastion"
 
@@ -129,6 +136,9 @@ const (
 	// PrivateRoleTagValue describes the value for the private role.
 	PrivateRoleTagValue = "private"
 
+	// FirewallRoleTagValue describes the value for the network firewall role.
+	FirewallRoleTagValue = "firewall"
+
 	// MachineNameTagKey is the key for machine name.
 	MachineNameTagKey = "MachineName"
 )
@@ -18,9 +18,11 @@ package v1alpha4
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
@@ -61,6 +63,13 @@ type AMIReference struct {
 	// +kubebuilder:validation:Enum:=AmazonLinux;AmazonLinuxGPU
 	// +optional
 	EKSOptimizedLookupType *EKSAMILookupType `json:"eksLookupType,omitempty"`
+
+	// OSFamily is the operating system family of the image to look up when no ID is set. It selects the
+	// SSM parameter of the EKS optimized image or the base OS of the image looked up by name, and the
+	// format of the user data. Bottlerocket images are always looked up in SSM and read TOML user data.
+	// +kubebuilder:validation:Enum:=AL2;AL2023;Bottlerocket;Ubuntu
+	// +optional
+	OSFamily OSFamily `json:"osFamily,omitempty"`
 }
 
 // AWSMachineTemplateResource describes the data needed to create am AWSMachine from a template
@@ -95,8 +104,143 @@ type Network struct {
 
 	// APIServerELB is the Kubernetes api server classic load balancer.
 	APIServerELB ClassicELB `json:"apiServerElb,omitempty"`
+
+	// InternalAPIServerELB is the internal Kubernetes api server classic load balancer, if the control plane
+	// load balancer is internet-facing and spec.controlPlaneLoadBalancer.internalLoadBalancer is set.
+	// +optional
+	InternalAPIServerELB ClassicELB `json:"internalApiServerElb,omitempty"`
+
+	// SharedVPC is set when the VPC of the cluster is owned by another AWS account and shared with the
+	// account of the cluster through AWS Resource Access Manager.
+	// +optional
+	SharedVPC *SharedVPC `json:"sharedVpc,omitempty"`
+
+	// InternetGatewayID is the ID of the internet gateway the provider created for the VPC.
+	// +optional
+	InternetGatewayID string `json:"internetGatewayId,omitempty"`
+
+	// NatGateways are the NAT gateways the provider created in the public subnets of the VPC.
+	// +optional
+	NatGateways []NatGateway `json:"natGateways,omitempty"`
+
+	// RouteTables maps the ID of every subnet of the cluster to the ID of the route table the provider
+	// associated with it.
+	// +optional
+	RouteTables map[string]string `json:"routeTables,omitempty"`
+
+	// DiscoveredSubnets describes the existing subnets of a cluster whose VPC isn't managed by the provider, as
+	// they are in AWS, and why each of them is considered public or private.
+	// +optional
+	DiscoveredSubnets []DiscoveredSubnet `json:"discoveredSubnets,omitempty"`
+
+	// Firewall describes the AWS Network Firewall inspecting the egress of the private subnets, if
+	// spec.networkSpec.firewall is set.
+	// +optional
+	Firewall *NetworkFirewallStatus `json:"firewall,omitempty"`
+}
+
+// NetworkFirewallStatus describes the AWS Network Firewall of the cluster.
+type NetworkFirewallStatus struct {
+	// ARN is the ARN of the firewall.
+	ARN string `json:"arn"`
+
+	// Endpoints maps the availability zones of the firewall to the IDs of its endpoints in them.
+	// +optional
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+}
+
+// DiscoveredSubnet describes an existing subnet used by the cluster.
+type DiscoveredSubnet struct {
+	// ID is the ID of the subnet.
+	ID string `json:"id"`
+
+	// IsPublic is true if the provider considers the subnet public.
+	IsPublic bool `json:"isPublic"`
+
+	// Classification is the reason the subnet is considered public or private.
+	Classification SubnetClassification `json:"classification"`
+
+	// RouteTableID is the ID of the route table the subnet uses.
+	// +optional
+	RouteTableID string `json:"routeTableId,omitempty"`
+
+	// MainRouteTable is true if the subnet isn't explicitly associated with a route table, and so uses the
+	// main route table of the VPC.
+	// +optional
+	MainRouteTable bool `json:"mainRouteTable,omitempty"`
+
+	// Tags are the tags of the subnet in AWS.
+	// +optional
+	Tags Tags `json:"tags,omitempty"`
+}
+
+// SubnetClassification is the reason a subnet is considered public or private.
+type SubnetClassification string
+
+var (
+	// SubnetClassificationInternetGatewayRoute means the subnet is public because its route table has a
+	// route to an internet gateway.
+	SubnetClassificationInternetGatewayRoute = SubnetClassification("InternetGatewayRoute")
+
+	// SubnetClassificationRoleTag means the subnet is public because its role tag is set to public.
+	SubnetClassificationRoleTag = SubnetClassification("RoleTag")
+
+	// SubnetClassificationSpec means the subnet is public because it is set as public in the spec. This is only
+	// honored for subnets shared from another account, whose route tables can't be seen.
+	SubnetClassificationSpec = SubnetClassification("Spec")
+
+	// SubnetClassificationOverride means the subnet is public or private because isPublicOverride is set in the spec.
+	SubnetClassificationOverride = SubnetClassification("Override")
+
+	// SubnetClassificationNoInternetGatewayRoute means the subnet is private because its route table has no
+	// route to an internet gateway and it isn't tagged as public.
+	SubnetClassificationNoInternetGatewayRoute = SubnetClassification("NoInternetGatewayRoute")
+)
+
+// NatGateway describes a NAT gateway created by the provider.
+type NatGateway struct {
+	// ID is the ID of the NAT gateway.
+	ID string `json:"id"`
+
+	// SubnetID is the ID of the public subnet the NAT gateway resides in.
+	SubnetID string `json:"subnetId"`
+
+	// AllocationID is the allocation ID of the elastic IP address of the NAT gateway.
+	// +optional
+	AllocationID string `json:"allocationId,omitempty"`
+
+	// PublicIP is the elastic IP address of the NAT gateway.
+	// +optional
+	PublicIP string `json:"publicIp,omitempty"`
+}
+
+// SharedVPC describes a VPC that another AWS account shares with the account of the cluster. The owner
+// account manages the VPC and its subnets, route tables, internet gateways and NAT gateways, none of which
+// the provider modifies or tags. The provider only manages the resources it creates in the account of the
+// cluster, such as security groups, load balancers and instances.
+type SharedVPC struct {
+	// OwnerID is the ID of the AWS account that owns the VPC.
+	OwnerID string `json:"ownerId"`
+
+	// SubnetIDs are the IDs of the subnets of the cluster, all of which are shared by the owner account.
+	// +optional
+	SubnetIDs []string `json:"subnetIds,omitempty"`
 }
 
+// DNSCheck defines how the DNS name of a load balancer is checked to be ready.
+type DNSCheck string
+
+const (
+	// DNSCheckResolve checks that the DNS name resolves from the management cluster.
+	DNSCheckResolve = DNSCheck("Resolve")
+
+	// DNSCheckAWS checks that the ELB API returns the load balancer with a DNS name, without resolving it.
+	DNSCheckAWS = DNSCheck("AWS")
+
+	// DNSCheckNone does not check the DNS name.
+	DNSCheckNone = DNSCheck("None")
+)
+
 // ClassicELBScheme defines the scheme of a classic load balancer.
 type ClassicELBScheme string
 
@@ -163,6 +307,47 @@ type ClassicELB struct {
 
 	// Tags is a map of tags associated with the load balancer.
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// Instances is the health of the instances registered with the load balancer.
+	// +optional
+	Instances []ClassicELBInstanceHealth `json:"instances,omitempty"`
+
+	// ProxyProtocol is whether the load balancer sends the PROXY protocol header to the instances.
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+}
+
+// ClassicELBInstanceState is the state of an instance registered with a classic load balancer.
+type ClassicELBInstanceState string
+
+var (
+	// ClassicELBInstanceStateInService is the state of an instance which passes the health check of the load balancer.
+	ClassicELBInstanceStateInService = ClassicELBInstanceState("InService")
+
+	// ClassicELBInstanceStateOutOfService is the state of an instance which fails the health check of the load balancer.
+	ClassicELBInstanceStateOutOfService = ClassicELBInstanceState("OutOfService")
+
+	// ClassicELBInstanceStateUnknown is the state of an instance whose health the load balancer can't determine.
+	ClassicELBInstanceStateUnknown = ClassicELBInstanceState("Unknown")
+)
+
+// ClassicELBInstanceHealth describes the health of an instance registered with a classic load balancer.
+type ClassicELBInstanceHealth struct {
+	// InstanceID is the ID of the instance.
+	InstanceID string `json:"instanceID"`
+
+	// State is the state of the instance: InService, OutOfService or Unknown.
+	State ClassicELBInstanceState `json:"state"`
+
+	// ReasonCode tells whether the instance is out of service because of the load balancer (ELB)
+	// or the instance itself (Instance).
+	// +optional
+	ReasonCode string `json:"reasonCode,omitempty"`
+
+	// Description explains why the instance is out of service, such as the health check
+	// of the load balancer failing for it.
+	// +optional
+	Description string `json:"description,omitempty"`
 }
 
 // ClassicELBAttributes defines extra attributes associated with a classic load balancer.
@@ -174,6 +359,11 @@ type ClassicELBAttributes struct {
 	// CrossZoneLoadBalancing enables the classic load balancer load balancing.
 	// +optional
 	CrossZoneLoadBalancing bool `json:"crossZoneLoadBalancing,omitempty"`
+
+	// ConnectionDrainingTimeout is how long the load balancer keeps the connections to a deregistered
+	// or unhealthy instance open. Connection draining is disabled if it is zero.
+	// +optional
+	ConnectionDrainingTimeout time.Duration `json:"connectionDrainingTimeout,omitempty"`
 }
 
 // ClassicELBListener defines an AWS classic load balancer listener.
@@ -222,6 +412,195 @@ type NetworkSpec struct {
 	// This is optional - if not provided new security groups will be created for the cluster
 	// +optional
 	SecurityGroupOverrides map[SecurityGroupRole]string `json:"securityGroupOverrides,omitempty"`
+
+	// SecurityGroupOverrideTracking defines how the security group overrides are followed when they are
+	// recreated outside of Cluster API. With ID, the default, the overrides are used as set. With Tags,
+	// the overrides must carry the cluster tag and the role tag (sigs.k8s.io/cluster-api-provider-aws/role)
+	// of the role they override: an override that no longer exists or no longer carries its role tag is
+	// replaced by the security group of the VPC carrying these tags, and the security groups of the
+	// instances are updated accordingly.
+	// +optional
+	// +kubebuilder:validation:Enum=ID;Tags
+	SecurityGroupOverrideTracking SecurityGroupOverrideTracking `json:"securityGroupOverrideTracking,omitempty"`
+
+	// AllowNodeToNodeTraffic allows all traffic between instances in the node security
+	// group. The Elastic Fabric Adapter (EFA) used by Trainium and Inferentia instances
+	// for collective communication requires it. EKS managed clusters do not need it,
+	// as nodes already share the EKS cluster security group.
+	// +optional
+	AllowNodeToNodeTraffic bool `json:"allowNodeToNodeTraffic,omitempty"`
+
+	// SecurityGroupPolicy declares the ports the security groups managed for the cluster may open to
+	// each other, for environments where the traffic between cluster components must be restricted.
+	// The ingress rules are still applied when they violate the policy: the violations are reported by
+	// the SecurityGroupPolicyCompliant condition.
+	// +optional
+	SecurityGroupPolicy *SecurityGroupPolicy `json:"securityGroupPolicy,omitempty"`
+
+	// Firewall routes the traffic between the private subnets of a managed VPC and their NAT gateways through
+	// AWS Network Firewall, so that their egress is inspected.
+	// +optional
+	Firewall *NetworkFirewall `json:"firewall,omitempty"`
+
+	// GatewayLoadBalancerEndpointRoutes steer the traffic to the given CIDR blocks through Gateway Load Balancer
+	// endpoints, for example to inspect it with third-party appliances. The routes are added to the route tables
+	// the provider manages and take precedence over the routes to the internet and NAT gateways for the same CIDR block.
+	// +optional
+	GatewayLoadBalancerEndpointRoutes []GatewayLoadBalancerEndpointRoute `json:"gatewayLoadBalancerEndpointRoutes,omitempty"`
+
+	// Routes are static routes added to the route tables of all the subnets the provider manages, such as
+	// routes to transit gateways or peered VPCs. They take precedence over the other routes for the same
+	// CIDR block, but not over the routes of the subnets themselves.
+	// +optional
+	Routes []Route `json:"routes,omitempty"`
+
+	// ElasticIPPool makes the NAT gateways and the bastion host use pre-allocated Elastic IPs instead of
+	// allocating new ones, for environments where the public IPs are allowlisted.
+	// +optional
+	ElasticIPPool *ElasticIPPool `json:"elasticIpPool,omitempty"`
+
+	// NatGatewayAllocationIDs are the allocation IDs of existing Elastic IPs the NAT gateways use, by availability
+	// zone, so that the egress IPs of the cluster remain the same when it is recreated. The NAT gateways of the other
+	// availability zones use Elastic IPs of the ElasticIPPool, or new ones.
+	// +optional
+	NatGatewayAllocationIDs map[string]string `json:"natGatewayAllocationIds,omitempty"`
+}
+
+// ElasticIPPool selects the pre-allocated Elastic IPs of a cluster. The Elastic IPs of the pool are neither tagged
+// nor released by the provider.
+type ElasticIPPool struct {
+	// AllocationIDs are the allocation IDs of the Elastic IPs of the pool.
+	// +optional
+	AllocationIDs []string `json:"allocationIds,omitempty"`
+
+	// Tags select the Elastic IPs of the pool by their tags: the Elastic IPs carrying all of them belong to it.
+	// +optional
+	Tags Tags `json:"tags,omitempty"`
+}
+
+// GatewayLoadBalancerEndpointRoute routes the traffic to a CIDR block through the Gateway Load Balancer endpoint
+// of the availability zone of each subnet.
+type GatewayLoadBalancerEndpointRoute struct {
+	// DestinationCidrBlock is the IPv4 CIDR block of the traffic sent to the endpoints.
+	DestinationCidrBlock string `json:"destinationCidrBlock"`
+
+	// Endpoints are the IDs of the Gateway Load Balancer endpoints by availability zone. The route tables of the
+	// subnets in zones without an endpoint don't get the route.
+	Endpoints map[string]string `json:"endpoints"`
+
+	// SubnetRole restricts the route to the route tables of the public or the private subnets.
+	// Defaults to the route tables of all the subnets.
+	// +kubebuilder:validation:Enum=public;private
+	// +optional
+	SubnetRole string `json:"subnetRole,omitempty"`
+}
+
+// Route is a static route of a route table. Exactly one target must be set.
+type Route struct {
+	// DestinationCidrBlock is the IPv4 CIDR block of the traffic sent to the target.
+	DestinationCidrBlock string `json:"destinationCidrBlock"`
+
+	// TransitGatewayID is the ID of the transit gateway the traffic is sent to.
+	// +optional
+	TransitGatewayID string `json:"transitGatewayId,omitempty"`
+
+	// VpcPeeringConnectionID is the ID of the VPC peering connection the traffic is sent to.
+	// +optional
+	VpcPeeringConnectionID string `json:"vpcPeeringConnectionId,omitempty"`
+
+	// InstanceID is the ID of the instance the traffic is sent to, such as a NAT instance.
+	// +optional
+	InstanceID string `json:"instanceId,omitempty"`
+
+	// NetworkInterfaceID is the ID of the network interface the traffic is sent to.
+	// +optional
+	NetworkInterfaceID string `json:"networkInterfaceId,omitempty"`
+}
+
+// Targets returns the targets set on the route.
+func (r *Route) Targets() []string {
+	targets := []string{}
+	for _, target := range []string{r.TransitGatewayID, r.VpcPeeringConnectionID, r.InstanceID, r.NetworkInterfaceID} {
+		if target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// AppliesTo returns true if the route is added to the route table of the given subnet.
+func (r *GatewayLoadBalancerEndpointRoute) AppliesTo(sn *SubnetSpec) bool {
+	switch r.SubnetRole {
+	case PublicRoleTagValue:
+		return sn.IsPublic
+	case PrivateRoleTagValue:
+		return !sn.IsPublic
+	}
+	return true
+}
+
+// NetworkFirewall describes the AWS Network Firewall inspecting the egress of the private subnets. The default
+// route of each private subnet points to the endpoint of the firewall in its availability zone, and the subnets
+// of the firewall route to the NAT gateway of the zone. Either ARN or PolicyARN must be set.
+type NetworkFirewall struct {
+	// ARN references an existing firewall in the VPC of the cluster, with an endpoint in every availability zone
+	// of the private subnets. Neither the firewall nor its subnets are modified by the provider, so the route
+	// tables of its subnets must route to the NAT gateways of their zones.
+	// +optional
+	ARN string `json:"arn,omitempty"`
+
+	// PolicyARN is the ARN of the firewall policy of the firewall the provider creates when ARN isn't set.
+	// +optional
+	PolicyARN string `json:"policyArn,omitempty"`
+
+	// Subnets are the inspection subnets the provider creates for its firewall, one in every availability zone
+	// of the private subnets. Their CIDR blocks must be within the VPC CIDR block and not overlap with the other
+	// subnets, so the subnets of the cluster must be set in the spec too. A /28 block is enough.
+	// +optional
+	Subnets Subnets `json:"subnets,omitempty"`
+}
+
+// IsManaged returns true if the firewall is created by the provider.
+func (f *NetworkFirewall) IsManaged() bool {
+	return f != nil && f.ARN == ""
+}
+
+// SecurityGroupOverrideTracking defines how the controller follows security group overrides.
+type SecurityGroupOverrideTracking string
+
+var (
+	// SecurityGroupOverrideTrackingID uses the security group overrides by their ID only.
+	SecurityGroupOverrideTrackingID = SecurityGroupOverrideTracking("ID")
+
+	// SecurityGroupOverrideTrackingTags replaces a security group override by the security group carrying
+	// its tags when it is recreated with a new ID.
+	SecurityGroupOverrideTrackingTags = SecurityGroupOverrideTracking("Tags")
+)
+
+// SecurityGroupPolicy declares the ports allowed on the paths between the security groups of a cluster,
+// such as from the API server load balancer to the control plane or between nodes for the CNI.
+type SecurityGroupPolicy struct {
+	// AllowedPorts are the port ranges the ingress rules between the security groups of the cluster may
+	// open. A rule complies when its protocol and all of its ports are within one of them. Rules whose
+	// sources are CIDR blocks only, such as the access to the API server load balancer, are not checked.
+	// +kubebuilder:validation:MinItems=1
+	AllowedPorts []SecurityGroupPortRange `json:"allowedPorts"`
+}
+
+// SecurityGroupPortRange is a range of ports of an IP protocol.
+type SecurityGroupPortRange struct {
+	// Protocol of the ports. The -1 protocol allows all the protocols and ports.
+	Protocol SecurityGroupProtocol `json:"protocol"`
+
+	// FromPort is the first port of the range.
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=65535
+	FromPort int64 `json:"fromPort"`
+
+	// ToPort is the last port of the range.
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=65535
+	ToPort int64 `json:"toPort"`
 }
 
 // VPCSpec configures an AWS VPC.
@@ -237,6 +616,11 @@ type VPCSpec struct {
 	// +optional
 	InternetGatewayID *string `json:"internetGatewayId,omitempty"`
 
+	// IPv6 enables IPv6 in the VPC. A managed VPC is created with an IPv6 CIDR block provided by Amazon, and the
+	// subnets created in it are assigned an IPv6 CIDR block from it. An unmanaged VPC must already have one.
+	// +optional
+	IPv6 *IPv6 `json:"ipv6,omitempty"`
+
 	// Tags is a collection of tags describing the resource.
 	Tags Tags `json:"tags,omitempty"`
 
@@ -273,6 +657,23 @@ func (v *VPCSpec) IsManaged(clusterName string) bool {
 	return !v.IsUnmanaged(clusterName)
 }
 
+// IsIPv6Enabled returns true if IPv6 is enabled in the VPC.
+func (v *VPCSpec) IsIPv6Enabled() bool {
+	return v.IPv6 != nil
+}
+
+// IPv6 configures the IPv6 addressing of a VPC.
+type IPv6 struct {
+	// CidrBlock is the IPv6 CIDR block of the VPC.
+	// +optional
+	CidrBlock string `json:"cidrBlock,omitempty"`
+
+	// EgressOnlyInternetGatewayID is the ID of the egress-only internet gateway the private subnets of a managed
+	// VPC route their IPv6 traffic through.
+	// +optional
+	EgressOnlyInternetGatewayID *string `json:"egressOnlyInternetGatewayId,omitempty"`
+}
+
 // SubnetSpec configures an AWS Subnet.
 type SubnetSpec struct {
 	// ID defines a unique identifier to reference this resource.
@@ -281,17 +682,41 @@ type SubnetSpec struct {
 	// CidrBlock is the CIDR block to be used when the provider creates a managed VPC.
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// IPv6CidrBlock is the IPv6 CIDR block of the subnet. Subnets created in a VPC with IPv6 enabled are
+	// assigned the next free /64 block of the VPC when it is not set.
+	// +optional
+	IPv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
+
 	// AvailabilityZone defines the availability zone to use for this subnet in the cluster's region.
 	AvailabilityZone string `json:"availabilityZone,omitempty"`
 
+	// AvailabilityZoneID is the ID of the availability zone of this subnet, e.g. use1-az1. Zone names are mapped
+	// to physical zones differently in every AWS account, while zone IDs are the same in all of them, so the ID
+	// identifies the zone of a subnet shared from another account. It may be set instead of AvailabilityZone
+	// for subnets created by the provider, and is filled in for all subnets once they exist.
+	// +optional
+	AvailabilityZoneID string `json:"availabilityZoneId,omitempty"`
+
 	// IsPublic defines the subnet as a public subnet. A subnet is public when it is associated with a route table that has a route to an internet gateway.
 	// +optional
 	IsPublic bool `json:"isPublic"`
 
+	// IsPublicOverride sets whether an existing subnet of a VPC which isn't managed by the provider is public,
+	// instead of detecting it from the route table and tags of the subnet. This is for subnets whose default route
+	// goes through an appliance, such as a firewall, rather than directly to an internet gateway.
+	// +optional
+	IsPublicOverride *bool `json:"isPublicOverride,omitempty"`
+
 	// RouteTableID is the routing table id associated with the subnet.
 	// +optional
 	RouteTableID *string `json:"routeTableId,omitempty"`
 
+	// UnmanagedRouteTableID is the ID of an existing route table of the VPC the subnet is associated with instead
+	// of a route table managed by the provider. The provider only associates the subnet with it, also in VPCs it
+	// doesn't manage, and never changes its routes or tags, nor deletes it.
+	// +optional
+	UnmanagedRouteTableID string `json:"unmanagedRouteTableId,omitempty"`
+
 	// NatGatewayID is the NAT gateway id associated with the subnet.
 	// Ignored unless the subnet is managed by the provider, in which case this is set on the public subnet where the NAT gateway resides. It is then used to determine routes for private subnets in the same AZ as the public subnet.
 	// +optional
@@ -299,6 +724,11 @@ type SubnetSpec struct {
 
 	// Tags is a collection of tags describing the resource.
 	Tags Tags `json:"tags,omitempty"`
+
+	// Routes are static routes added to the route table of the subnet when the provider manages it. They take
+	// precedence over the other routes for the same CIDR block.
+	// +optional
+	Routes []Route `json:"routes,omitempty"`
 }
 
 // String returns a string representation of the subnet.
@@ -306,6 +736,32 @@ func (s *SubnetSpec) String() string {
 	return fmt.Sprintf("id=%s/az=%s/public=%v", s.ID, s.AvailabilityZone, s.IsPublic)
 }
 
+// IsInZone returns true if the subnet lives in the availability zone, which can be given by either name or ID.
+func (s *SubnetSpec) IsInZone(zone string) bool {
+	if IsAvailabilityZoneID(zone) {
+		return s.AvailabilityZoneID == zone
+	}
+	return s.AvailabilityZone == zone
+}
+
+// IsInSameZone returns true if both subnets live in the same availability zone. The zone IDs are compared
+// if both are known, as the zone names of subnets described through different accounts may not match.
+func (s *SubnetSpec) IsInSameZone(other *SubnetSpec) bool {
+	if s.AvailabilityZoneID != "" && other.AvailabilityZoneID != "" {
+		return s.AvailabilityZoneID == other.AvailabilityZoneID
+	}
+	return s.AvailabilityZone == other.AvailabilityZone
+}
+
+// availabilityZoneIDRegex matches availability zone IDs such as use1-az1 and usw2-lax1-az1, but not
+// availability zone names such as us-east-1a.
+var availabilityZoneIDRegex = regexp.MustCompile(`^[a-z]+[0-9]+(-[a-z]+[0-9]+)?-az[0-9]+$`)
+
+// IsAvailabilityZoneID returns true if the zone is an availability zone ID rather than a name.
+func IsAvailabilityZoneID(zone string) bool {
+	return availabilityZoneIDRegex.MatchString(zone)
+}
+
 // Subnets is a slice of Subnet.
 type Subnets []SubnetSpec
 
@@ -371,10 +827,11 @@ func (s Subnets) FilterPublic() (res Subnets) {
 	return
 }
 
-// FilterByZone returns a slice containing all subnets that live in the availability zone specified.
+// FilterByZone returns a slice containing all subnets that live in the availability zone specified,
+// either by name or by ID.
 func (s Subnets) FilterByZone(zone string) (res Subnets) {
 	for _, x := range s {
-		if x.AvailabilityZone == zone {
+		if x.IsInZone(zone) {
 			res = append(res, x)
 		}
 	}
@@ -626,6 +1083,36 @@ var (
 	)
 )
 
+// NetworkInterfaceConfig configures how a network interface is attached to an instance.
+type NetworkInterfaceConfig struct {
+	// NetworkInterfaceID is the ID of the network interface, which must be listed in NetworkInterfaces.
+	NetworkInterfaceID string `json:"networkInterfaceId"`
+
+	// NetworkCardIndex is the index of the network card the interface is attached to. Instance types with several
+	// network cards, such as p4d.24xlarge, only get the bandwidth of the cards with an interface attached. The
+	// primary network interface must be attached to the first network card. Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NetworkCardIndex *int64 `json:"networkCardIndex,omitempty"`
+
+	// EnaSrdSpecification configures ENA Express on the interface when the instance is launched. It requires an
+	// instance type that supports ENA Express.
+	// +optional
+	EnaSrdSpecification *EnaSrdSpecification `json:"enaSrdSpecification,omitempty"`
+}
+
+// EnaSrdSpecification configures ENA Express, the SRD based networking of the Elastic Network Adapter, on a
+// network interface.
+type EnaSrdSpecification struct {
+	// EnaSrdEnabled enables ENA Express for the TCP traffic of the interface.
+	// +optional
+	EnaSrdEnabled *bool `json:"enaSrdEnabled,omitempty"`
+
+	// EnaSrdUDPEnabled enables ENA Express for the UDP traffic of the interface as well. It requires EnaSrdEnabled.
+	// +optional
+	EnaSrdUDPEnabled *bool `json:"enaSrdUdpEnabled,omitempty"`
+}
+
 // Instance describes an AWS instance.
 type Instance struct {
 	ID string `json:"id"`
@@ -633,6 +1120,11 @@ type Instance struct {
 	// The current state of the instance.
 	State InstanceState `json:"instanceState,omitempty"`
 
+	// StateReason is the reason code EC2 reported for the most recent state transition,
+	// for example Server.SpotInstanceTermination.
+	// +optional
+	StateReason string `json:"stateReason,omitempty"`
+
 	// The instance type.
 	Type string `json:"type,omitempty"`
 
@@ -681,6 +1173,10 @@ type Instance struct {
 	// Specifies ENIs attached to instance
 	NetworkInterfaces []string `json:"networkInterfaces,omitempty"`
 
+	// NetworkInterfaceConfigs configures the attachment of the ENIs of NetworkInterfaces.
+	// +optional
+	NetworkInterfaceConfigs []NetworkInterfaceConfig `json:"networkInterfaceConfigs,omitempty"`
+
 	// The tags associated with the instance.
 	Tags map[string]string `json:"tags,omitempty"`
 
@@ -694,9 +1190,148 @@ type Instance struct {
 	// +optional
 	Tenancy string `json:"tenancy,omitempty"`
 
+	// HostResourceGroupARN is the ARN of the host resource group the instance is launched in.
+	// +optional
+	HostResourceGroupARN string `json:"hostResourceGroupARN,omitempty"`
+
+	// LicenseConfigurationARNs are the ARNs of the license configurations associated with the instance.
+	// +optional
+	LicenseConfigurationARNs []string `json:"licenseConfigurationARNs,omitempty"`
+
+	// CPUOptions are the CPU options the instance was launched with.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// InstanceMetadataOptions are the options of the instance metadata service of the instance.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
 	// IDs of the instance's volumes
 	// +optional
 	VolumeIDs []string `json:"volumeIDs,omitempty"`
+
+	// LaunchTime is the time the instance was launched.
+	// +optional
+	LaunchTime *metav1.Time `json:"launchTime,omitempty"`
+
+	// Lifecycle indicates whether this is a spot, scheduled or on-demand instance.
+	// +optional
+	Lifecycle InstanceLifecycle `json:"lifecycle,omitempty"`
+
+	// SpotInstanceRequestID is the ID of the request for a spot instance, if applicable.
+	// +optional
+	SpotInstanceRequestID string `json:"spotInstanceRequestID,omitempty"`
+
+	// HostID is the ID of the dedicated host the instance is placed on, if applicable.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// PrimaryNetworkInterfaceID is the ID of the network interface at device index 0.
+	// +optional
+	PrimaryNetworkInterfaceID string `json:"primaryNetworkInterfaceID,omitempty"`
+}
+
+// InstanceLifecycle describes the purchasing option of an instance.
+type InstanceLifecycle string
+
+var (
+	// InstanceLifecycleOnDemand is an on-demand instance.
+	InstanceLifecycleOnDemand = InstanceLifecycle("on-demand")
+
+	// InstanceLifecycleSpot is a spot instance.
+	InstanceLifecycleSpot = InstanceLifecycle("spot")
+
+	// InstanceLifecycleScheduled is a scheduled instance.
+	InstanceLifecycleScheduled = InstanceLifecycle("scheduled")
+)
+
+// InstanceScheduledEvent describes a scheduled event of an EC2 instance.
+type InstanceScheduledEvent struct {
+	// Code is the event code, for example instance-retirement or system-reboot.
+	Code string `json:"code"`
+
+	// Description is the description of the event.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// NotBefore is the earliest scheduled start time of the event.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// NotAfter is the latest scheduled end time of the event.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// RightSizingFinding is the classification of an instance by AWS Compute Optimizer.
+type RightSizingFinding string
+
+var (
+	// RightSizingFindingUnderprovisioned means that at least one resource of the instance, such as its CPU or memory,
+	// does not meet the performance requirements of its workload.
+	RightSizingFindingUnderprovisioned = RightSizingFinding("Underprovisioned")
+
+	// RightSizingFindingOverprovisioned means that at least one resource of the instance can be sized down while still
+	// meeting the performance requirements of its workload.
+	RightSizingFindingOverprovisioned = RightSizingFinding("Overprovisioned")
+
+	// RightSizingFindingOptimized means that the instance meets the performance requirements of its workload and is
+	// not over provisioned.
+	RightSizingFindingOptimized = RightSizingFinding("Optimized")
+)
+
+// RightSizingRecommendation is the recommendation of AWS Compute Optimizer for the instance of an AWSMachine.
+type RightSizingRecommendation struct {
+	// Finding is the classification of the instance. It is empty while Compute Optimizer has not collected
+	// enough metrics of the instance to classify it.
+	// +optional
+	Finding RightSizingFinding `json:"finding,omitempty"`
+
+	// RecommendedInstanceTypes are the instance types recommended for the workload of the instance, best first.
+	// +optional
+	RecommendedInstanceTypes []string `json:"recommendedInstanceTypes,omitempty"`
+
+	// LastCheckTime is the time the recommendation was last fetched from Compute Optimizer.
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+}
+
+// InstanceDetails summarises the EC2 instance backing an AWSMachine.
+type InstanceDetails struct {
+	// LaunchTime is the time the instance was launched.
+	// +optional
+	LaunchTime *metav1.Time `json:"launchTime,omitempty"`
+
+	// InstanceType is the type the instance was launched with.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// Lifecycle indicates whether this is a spot, scheduled or on-demand instance.
+	// +optional
+	Lifecycle InstanceLifecycle `json:"lifecycle,omitempty"`
+
+	// SpotInstanceRequestID is the ID of the request for a spot instance, if applicable.
+	// +optional
+	SpotInstanceRequestID string `json:"spotInstanceRequestID,omitempty"`
+
+	// AvailabilityZone is the availability zone the instance is placed in.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// HostID is the ID of the dedicated host the instance is placed on, if applicable.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// PrimaryNetworkInterfaceID is the ID of the instance's primary network interface.
+	// +optional
+	PrimaryNetworkInterfaceID string `json:"primaryNetworkInterfaceID,omitempty"`
+
+	// VolumeIDs are the IDs of the EBS volumes attached to the instance.
+	// +optional
+	VolumeIDs []string `json:"volumeIDs,omitempty"`
+
+	// CPUOptions are the number of CPU cores and threads per core of the instance.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
 }
 
 // Volume encapsulates the configuration options for the storage device
@@ -737,6 +1372,327 @@ type SpotMarketOptions struct {
 	// +optional
 	// +kubebuilder:validation:pattern="^[0-9]+(\.[0-9]+)?$"
 	MaxPrice *string `json:"maxPrice,omitempty"`
+
+	// FallbackToOnDemand, when set, launches the instance as an on-demand instance
+	// when spot instances cannot be launched for lack of spot capacity.
+	// +optional
+	FallbackToOnDemand *SpotFallbackToOnDemand `json:"fallbackToOnDemand,omitempty"`
+}
+
+// SpotFallbackToOnDemand defines when an instance requested from the spot market
+// is launched as an on-demand instance instead.
+type SpotFallbackToOnDemand struct {
+	// Attempts is the number of spot launches that must fail with a capacity error
+	// before the instance is launched as an on-demand instance. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Attempts *int32 `json:"attempts,omitempty"`
+}
+
+// CPUOptions defines the number of CPU cores and threads of an instance. Options that are
+// not set default to the values of the instance type.
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores of the instance.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CoreCount *int32 `json:"coreCount,omitempty"`
+
+	// ThreadsPerCore is the number of threads per CPU core. Set it to 1 to disable
+	// multithreading, e.g. for software licensed per thread.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	ThreadsPerCore *int32 `json:"threadsPerCore,omitempty"`
+}
+
+// InstanceMetadataOptions defines the options of the instance metadata service of an instance.
+// Options that are not set default to the EC2 defaults.
+type InstanceMetadataOptions struct {
+	// HTTPEndpoint enables or disables the HTTP endpoint of the instance metadata service.
+	// +kubebuilder:validation:Enum:=enabled;disabled
+	// +optional
+	HTTPEndpoint string `json:"httpEndpoint,omitempty"`
+
+	// HTTPTokens is whether requests to the instance metadata service must carry a session token.
+	// Set it to required to only allow IMDSv2.
+	// +kubebuilder:validation:Enum:=optional;required
+	// +optional
+	HTTPTokens string `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit is the number of network hops the responses to session token requests
+	// can travel. Containers not running in the host network need at least 2.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	HTTPPutResponseHopLimit int64 `json:"httpPutResponseHopLimit,omitempty"`
+}
+
+// MachineDefaults are the defaults of the AWSMachines of a cluster.
+type MachineDefaults struct {
+	// InstanceMetadataOptions are the instance metadata options of the machines which don't set any.
+	// +optional
+	InstanceMetadataOptions *InstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+
+	// RootVolume is the root volume of the machines which don't set one. Its type is also used for
+	// the root volumes which don't set a type.
+	// +optional
+	RootVolume *RootVolumeDefaults `json:"rootVolume,omitempty"`
+
+	// AdditionalSecurityGroups are the additional security groups of the machines which don't set any.
+	// +optional
+	AdditionalSecurityGroups []AWSResourceReference `json:"additionalSecurityGroups,omitempty"`
+}
+
+// RootVolumeDefaults are the defaults of the root volumes of the AWSMachines of a cluster.
+type RootVolumeDefaults struct {
+	// Size is the size of the root volume in Gi.
+	// Must be greater than the image snapshot size or 8 (whichever is greater).
+	// +kubebuilder:validation:Minimum=8
+	Size int64 `json:"size"`
+
+	// Type is the type of the volume (e.g. gp2, gp3, io1, etc...).
+	// +optional
+	Type string `json:"type,omitempty"`
+}
+
+// S3Bucket defines a supporting S3 bucket for the cluster.
+type S3Bucket struct {
+	// Name defines name of S3 Bucket to be created.
+	// +kubebuilder:validation:MinLength:=3
+	// +kubebuilder:validation:MaxLength:=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`
+	Name string `json:"name"`
+
+	// Unmanaged indicates that the bucket already exists and is managed outside
+	// of Cluster API. When set, the bucket is neither created, configured nor
+	// deleted, and only objects stored under KeyPrefix are managed.
+	// +optional
+	Unmanaged bool `json:"unmanaged,omitempty"`
+
+	// KeyPrefix is the prefix of the keys of all objects stored for this cluster.
+	// Defaults to the cluster name.
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// NodesIAMRoles is a list of IAM role names which will be granted read
+	// access to objects in the bucket through the bucket policy. The roles
+	// must exist, as the policy grants access to their ARNs, path included.
+	// Defaults to the control plane and nodes roles created by clusterawsadm.
+	// +optional
+	NodesIAMRoles []string `json:"nodesIAMRoles,omitempty"`
+
+	// Versioning enables object versioning on the bucket.
+	// +optional
+	Versioning bool `json:"versioning,omitempty"`
+
+	// Encryption configures default server-side encryption of objects stored in the bucket.
+	// If omitted, objects are encrypted with S3 managed keys.
+	// +optional
+	Encryption *S3BucketEncryption `json:"encryption,omitempty"`
+
+	// PublicAccessBlock configures the public access block of the bucket.
+	// If omitted, all public access to the bucket is blocked.
+	// +optional
+	PublicAccessBlock *S3BucketPublicAccessBlock `json:"publicAccessBlock,omitempty"`
+
+	// Lifecycle configures expiration of stale objects stored in the bucket.
+	// +optional
+	Lifecycle *S3BucketLifecycle `json:"lifecycle,omitempty"`
+}
+
+// S3BucketEncryption defines the default server-side encryption of an S3 bucket.
+type S3BucketEncryption struct {
+	// KMSKeyID is the ID or ARN of a customer managed KMS key used to encrypt objects.
+	// The key must already exist and be usable by the controller and the node IAM roles.
+	// +kubebuilder:validation:MinLength=1
+	KMSKeyID string `json:"kmsKeyID"`
+}
+
+// S3BucketPublicAccessBlock defines the public access block settings of an S3 bucket.
+type S3BucketPublicAccessBlock struct {
+	// BlockPublicACLs rejects requests that grant public access through ACLs.
+	// +optional
+	BlockPublicACLs bool `json:"blockPublicACLs,omitempty"`
+
+	// IgnorePublicACLs ignores any public ACLs set on the bucket and its objects.
+	// +optional
+	IgnorePublicACLs bool `json:"ignorePublicACLs,omitempty"`
+
+	// BlockPublicPolicy rejects bucket policies that grant public access.
+	// +optional
+	BlockPublicPolicy bool `json:"blockPublicPolicy,omitempty"`
+
+	// RestrictPublicBuckets restricts access to a bucket with a public policy
+	// to AWS service principals and authorized users within the bucket owner's account.
+	// +optional
+	RestrictPublicBuckets bool `json:"restrictPublicBuckets,omitempty"`
+}
+
+// S3BucketLifecycle defines lifecycle rules for objects stored in an S3 bucket.
+type S3BucketLifecycle struct {
+	// ExpirationDays is the number of days after creation after which objects are expired.
+	// +kubebuilder:validation:Minimum=1
+	ExpirationDays int32 `json:"expirationDays"`
+
+	// NoncurrentVersionExpirationDays is the number of days after which noncurrent
+	// object versions are permanently deleted. Only applies when versioning is enabled.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NoncurrentVersionExpirationDays *int32 `json:"noncurrentVersionExpirationDays,omitempty"`
+}
+
+// ECRPullThroughCache defines the ECR pull-through cache rules used to mirror
+// upstream container registries.
+type ECRPullThroughCache struct {
+	// Rules is the list of pull-through cache rules to provision in the
+	// cluster's account and region.
+	// +kubebuilder:validation:MinItems=1
+	Rules []ECRPullThroughCacheRule `json:"rules"`
+
+	// DisableMirrors disables the injection of the containerd registry mirror
+	// configuration into the user data of nodes.
+	// +optional
+	DisableMirrors bool `json:"disableMirrors,omitempty"`
+}
+
+// ECRPullThroughCacheRule maps an upstream registry to an ECR repository prefix.
+type ECRPullThroughCacheRule struct {
+	// UpstreamRegistryURL is the URL of the upstream registry, e.g. public.ecr.aws or quay.io.
+	// +kubebuilder:validation:MinLength=1
+	UpstreamRegistryURL string `json:"upstreamRegistryURL"`
+
+	// ECRRepositoryPrefix is the repository prefix under which images of the
+	// upstream registry are cached.
+	// +kubebuilder:validation:MinLength=2
+	// +kubebuilder:validation:MaxLength=30
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`
+	ECRRepositoryPrefix string `json:"ecrRepositoryPrefix"`
+
+	// CredentialARN is the ARN of the Secrets Manager secret holding the
+	// credentials used to authenticate against the upstream registry.
+	// Only required for upstream registries that don't allow anonymous pulls.
+	// +optional
+	CredentialARN string `json:"credentialARN,omitempty"`
+}
+
+// OperationTimeouts overrides how long the controllers wait for AWS operations
+// to complete before giving up and retrying on the next reconciliation.
+type OperationTimeouts struct {
+	// InstanceRunning is how long to wait for a newly launched EC2 instance to
+	// reach the running state. Defaults to the controller's
+	// --instance-running-timeout flag.
+	// +optional
+	InstanceRunning *metav1.Duration `json:"instanceRunning,omitempty"`
+
+	// LoadBalancerReady is how long to wait for the control plane load balancer
+	// to be deleted or to accept attribute and health check changes. Defaults
+	// to the controller's --load-balancer-ready-timeout flag.
+	// +optional
+	LoadBalancerReady *metav1.Duration `json:"loadBalancerReady,omitempty"`
+}
+
+// RequeueIntervals overrides how often the controllers check on AWS resources
+// which are not ready yet.
+type RequeueIntervals struct {
+	// LoadBalancerDNS is how often to check whether the control plane load
+	// balancer has a DNS name which resolves. Defaults to the controller's
+	// --load-balancer-dns-requeue-interval flag.
+	// +optional
+	LoadBalancerDNS *metav1.Duration `json:"loadBalancerDNS,omitempty"`
+
+	// InstanceRunning is how often to check whether a pending EC2 instance is
+	// running. Defaults to the controller's --instance-running-requeue-interval
+	// flag.
+	// +optional
+	InstanceRunning *metav1.Duration `json:"instanceRunning,omitempty"`
+}
+
+// ResourceDeletionPolicy defines what happens to AWS resources when their AWSCluster is deleted.
+type ResourceDeletionPolicy string
+
+const (
+	// ResourceDeletionPolicyDelete deletes the AWS resources with the AWSCluster.
+	ResourceDeletionPolicyDelete = ResourceDeletionPolicy("Delete")
+
+	// ResourceDeletionPolicyRetain leaves the AWS resources in place when the AWSCluster is deleted.
+	ResourceDeletionPolicyRetain = ResourceDeletionPolicy("Retain")
+)
+
+// DeletionPolicy defines which classes of the AWS resources of a cluster are deleted with its AWSCluster.
+// The resources of a class without a policy are deleted.
+type DeletionPolicy struct {
+	// Network is the deletion policy of the VPC, subnets, gateways, route tables and security groups.
+	// It must be Retain when the load balancers or the instances are retained, as they use them.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	Network ResourceDeletionPolicy `json:"network,omitempty"`
+
+	// LoadBalancers is the deletion policy of the API server load balancers.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	LoadBalancers ResourceDeletionPolicy `json:"loadBalancers,omitempty"`
+
+	// S3Bucket is the deletion policy of the S3 bucket of the cluster.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	S3Bucket ResourceDeletionPolicy `json:"s3Bucket,omitempty"`
+
+	// Instances is the deletion policy of the bastion hosts, the instances of the AWSMachines and the
+	// EC2 key pair of the cluster. The instances of AWSMachines deleted while the cluster is not being
+	// deleted are always terminated.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	Instances ResourceDeletionPolicy `json:"instances,omitempty"`
+}
+
+// Karpenter defines the AWS resources provisioned for running Karpenter on the cluster.
+type Karpenter struct {
+	// AdditionalNodePolicyARNs is a list of IAM policy ARNs attached to the role of the nodes
+	// launched by Karpenter, in addition to the default worker node policies.
+	// +optional
+	AdditionalNodePolicyARNs []string `json:"additionalNodePolicyARNs,omitempty"`
+}
+
+// KarpenterStatus holds the names of the AWS resources provisioned for Karpenter,
+// to be used in its configuration.
+type KarpenterStatus struct {
+	// NodeInstanceProfile is the name of the IAM instance profile for the nodes launched by Karpenter.
+	NodeInstanceProfile string `json:"nodeInstanceProfile,omitempty"`
+
+	// InterruptionQueue is the name of the SQS queue receiving the interruption events
+	// of the instances, such as spot interruptions and scheduled maintenance.
+	InterruptionQueue string `json:"interruptionQueue,omitempty"`
+}
+
+// SSHPublicKeySecretKey is the key of the public key in the secret referenced by an SSHKeyPair.
+const SSHPublicKeySecretKey = "ssh-publickey"
+
+// SSHKeyPair defines the EC2 key pair created for a cluster.
+type SSHKeyPair struct {
+	// PublicKeySecretName is the name of a secret in the namespace of the cluster whose ssh-publickey key
+	// holds the OpenSSH public key imported as the key pair. When omitted, EC2 generates the key pair and
+	// its private key is written to the ssh-privatekey key of the <cluster name>-ssh-key secret.
+	// +optional
+	PublicKeySecretName string `json:"publicKeySecretName,omitempty"`
+}
+
+// SSHKeyPairStatus describes the EC2 key pair created for a cluster.
+type SSHKeyPairStatus struct {
+	// Name is the name of the key pair.
+	Name string `json:"name"`
+
+	// ID is the ID of the key pair.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Fingerprint is the fingerprint of the key pair.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// PrivateKeySecretName is the name of the secret holding the private key of the key pair, when it was
+	// generated by EC2.
+	// +optional
+	PrivateKeySecretName string `json:"privateKeySecretName,omitempty"`
 }
 
 // EKSAMILookupType specifies which AWS AMI to use for a AWSMachine and AWSMachinePool.
@@ -748,3 +1704,88 @@ const (
 	// AmazonLinuxGPU is the AmazonLinux GPU AMI type.
 	AmazonLinuxGPU EKSAMILookupType = "AmazonLinuxGPU"
 )
+
+// OSFamily is the operating system family of the image of a machine.
+type OSFamily string
+
+const (
+	// OSFamilyAmazonLinux2 is Amazon Linux 2.
+	OSFamilyAmazonLinux2 OSFamily = "AL2"
+	// OSFamilyAmazonLinux2023 is Amazon Linux 2023.
+	OSFamilyAmazonLinux2023 OSFamily = "AL2023"
+	// OSFamilyBottlerocket is Bottlerocket, which is configured through TOML settings instead of cloud-init.
+	OSFamilyBottlerocket OSFamily = "Bottlerocket"
+	// OSFamilyUbuntu is Ubuntu.
+	OSFamilyUbuntu OSFamily = "Ubuntu"
+)
+
+// UserDataFormat is the format of the user data read by an operating system at boot.
+type UserDataFormat string
+
+const (
+	// UserDataFormatCloudInit is user data processed by cloud-init, such as a cloud-config document, a script
+	// or a MIME multi-part archive of them.
+	UserDataFormatCloudInit UserDataFormat = "cloud-init"
+	// UserDataFormatTOML is the TOML settings read by Bottlerocket.
+	UserDataFormatTOML UserDataFormat = "toml"
+)
+
+// UserDataFormat returns the format of the user data read by the operating system family.
+func (f OSFamily) UserDataFormat() UserDataFormat {
+	if f == OSFamilyBottlerocket {
+		return UserDataFormatTOML
+	}
+	return UserDataFormatCloudInit
+}
+
+// BottlerocketSettings are the settings of a Bottlerocket instance that are rendered into its TOML user data.
+type BottlerocketSettings struct {
+	// Sysctls are the kernel parameters to set, keyed by name, e.g. net.ipv4.ip_forward.
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// AdminContainer enables the admin host container, which gives root access to the host
+	// through the control container.
+	// +optional
+	AdminContainer bool `json:"adminContainer,omitempty"`
+
+	// HostContainers are additional host containers to run.
+	// +optional
+	HostContainers []BottlerocketHostContainer `json:"hostContainers,omitempty"`
+
+	// RegistryMirrors are the mirrors to pull images of container registries from.
+	// +optional
+	RegistryMirrors []BottlerocketRegistryMirror `json:"registryMirrors,omitempty"`
+}
+
+// BottlerocketHostContainer is a host container of a Bottlerocket instance.
+type BottlerocketHostContainer struct {
+	// Name is the name of the host container. The admin container is enabled with adminContainer.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Source is the image of the host container.
+	Source string `json:"source"`
+
+	// Superpowered gives the host container full access to the host.
+	// +optional
+	Superpowered bool `json:"superpowered,omitempty"`
+}
+
+// BottlerocketRegistryMirror is a mirror of a container registry.
+type BottlerocketRegistryMirror struct {
+	// Registry is the container registry to mirror, e.g. docker.io.
+	Registry string `json:"registry"`
+
+	// Endpoints are the URLs of the mirrors, tried in order.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+}
+
+// GPUVendor is the vendor of the GPUs a machine is prepared for.
+type GPUVendor string
+
+const (
+	// GPUVendorNvidia prepares machines for NVIDIA GPUs.
+	GPUVendorNvidia GPUVendor = "nvidia"
+)
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIsAvailabilityZoneID(t *testing.T) {
+	tests := map[string]bool{
+		"use1-az1":         true,
+		"euw2-az3":         true,
+		"usw2-lax1-az1":    true,
+		"us-east-1a":       false,
+		"us-west-2-lax-1a": false,
+		"":                 false,
+	}
+	for zone, want := range tests {
+		if got := IsAvailabilityZoneID(zone); got != want {
+			t.Errorf("IsAvailabilityZoneID(%q) = %v, want %v", zone, got, want)
+		}
+	}
+}
+
+func TestSubnetsFilterByZone(t *testing.T) {
+	g := NewWithT(t)
+
+	subnets := Subnets{
+		{ID: "subnet-1", AvailabilityZone: "us-east-1a", AvailabilityZoneID: "use1-az4"},
+		{ID: "subnet-2", AvailabilityZone: "us-east-1b", AvailabilityZoneID: "use1-az6"},
+		{ID: "subnet-3", AvailabilityZone: "us-east-1a"},
+	}
+
+	g.Expect(subnets.FilterByZone("us-east-1a").IDs()).To(Equal([]string{"subnet-1", "subnet-3"}))
+	g.Expect(subnets.FilterByZone("use1-az6").IDs()).To(Equal([]string{"subnet-2"}))
+	g.Expect(subnets.FilterByZone("use1-az1")).To(BeEmpty())
+}
+
+func TestSubnetSpecIsInSameZone(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  SubnetSpec
+		equal bool
+	}{
+		{
+			name:  "zone IDs are compared when both are known",
+			a:     SubnetSpec{AvailabilityZone: "us-east-1a", AvailabilityZoneID: "use1-az4"},
+			b:     SubnetSpec{AvailabilityZone: "us-east-1b", AvailabilityZoneID: "use1-az4"},
+			equal: true,
+		},
+		{
+			name:  "different zone IDs with the same name are different zones",
+			a:     SubnetSpec{AvailabilityZone: "us-east-1a", AvailabilityZoneID: "use1-az4"},
+			b:     SubnetSpec{AvailabilityZone: "us-east-1a", AvailabilityZoneID: "use1-az6"},
+			equal: false,
+		},
+		{
+			name:  "zone names are compared when a zone ID is missing",
+			a:     SubnetSpec{AvailabilityZone: "us-east-1a", AvailabilityZoneID: "use1-az4"},
+			b:     SubnetSpec{AvailabilityZone: "us-east-1a"},
+			equal: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.IsInSameZone(&tc.b); got != tc.equal {
+				t.Errorf("IsInSameZone() = %v, want %v", got, tc.equal)
+			}
+		})
+	}
+}
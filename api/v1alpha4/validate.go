@@ -17,15 +17,27 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
 )
 
 var (
 	sshKeyValidNameRegex = regexp.MustCompile(`^[[:graph:]]+([[:print:]]*[[:graph:]]+)*$`)
+
+	validateLog = logf.Log.WithName("validate")
 )
 
 // Validate will validate the bastion fields.
@@ -49,6 +61,689 @@ func (b *Bastion) Validate() []*field.Error {
 	return errs
 }
 
+// validateCABundle checks that the CA bundle of an identity, if set, contains PEM encoded certificates.
+func (s *AWSClusterIdentitySpec) validateCABundle() *field.Error {
+	if len(s.CABundle) == 0 {
+		return nil
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(s.CABundle) {
+		return field.Invalid(field.NewPath("spec", "caBundle"), fmt.Sprintf("%d bytes", len(s.CABundle)),
+			"must contain at least one PEM encoded certificate")
+	}
+	return nil
+}
+
+// validateProxy checks that the proxy of an identity, if set, has an HTTP or HTTPS URL.
+func (s *AWSClusterIdentitySpec) validateProxy() *field.Error {
+	if s.Proxy == nil {
+		return nil
+	}
+	u, err := url.Parse(s.Proxy.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.Invalid(field.NewPath("spec", "proxy", "url"), s.Proxy.URL, "must be an http or https URL")
+	}
+	return nil
+}
+
+// Validate will validate the S3 bucket fields.
+func (b *S3Bucket) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if b == nil {
+		return errs
+	}
+
+	if b.Unmanaged {
+		fldPath := field.NewPath("spec", "s3Bucket")
+		if len(b.NodesIAMRoles) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("nodesIAMRoles"), "cannot be set if spec.s3Bucket.unmanaged is true"))
+		}
+		if b.Versioning {
+			errs = append(errs, field.Forbidden(fldPath.Child("versioning"), "cannot be set if spec.s3Bucket.unmanaged is true"))
+		}
+		if b.Encryption != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("encryption"), "cannot be set if spec.s3Bucket.unmanaged is true"))
+		}
+		if b.PublicAccessBlock != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("publicAccessBlock"), "cannot be set if spec.s3Bucket.unmanaged is true"))
+		}
+		if b.Lifecycle != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("lifecycle"), "cannot be set if spec.s3Bucket.unmanaged is true"))
+		}
+		return errs
+	}
+
+	if b.Lifecycle != nil && b.Lifecycle.NoncurrentVersionExpirationDays != nil && !b.Versioning {
+		errs = append(errs,
+			field.Forbidden(field.NewPath("spec", "s3Bucket", "lifecycle", "noncurrentVersionExpirationDays"), "can only be set if spec.s3Bucket.versioning is true"),
+		)
+	}
+
+	return errs
+}
+
+// Validate will validate the ECR pull-through cache rules.
+func (c *ECRPullThroughCache) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if c == nil {
+		return errs
+	}
+
+	fldPath := field.NewPath("spec", "ecrPullThroughCache", "rules")
+	prefixes := map[string]bool{}
+	upstreams := map[string]bool{}
+	for i, rule := range c.Rules {
+		if strings.Contains(rule.UpstreamRegistryURL, "://") || strings.Contains(rule.UpstreamRegistryURL, "/") {
+			errs = append(errs, field.Invalid(fldPath.Index(i).Child("upstreamRegistryURL"), rule.UpstreamRegistryURL, "must be a registry hostname without scheme or path"))
+		}
+		if prefixes[rule.ECRRepositoryPrefix] {
+			errs = append(errs, field.Duplicate(fldPath.Index(i).Child("ecrRepositoryPrefix"), rule.ECRRepositoryPrefix))
+		}
+		if upstreams[rule.UpstreamRegistryURL] {
+			errs = append(errs, field.Duplicate(fldPath.Index(i).Child("upstreamRegistryURL"), rule.UpstreamRegistryURL))
+		}
+		prefixes[rule.ECRRepositoryPrefix] = true
+		upstreams[rule.UpstreamRegistryURL] = true
+	}
+
+	return errs
+}
+
+// Validate will validate the control plane load balancer fields.
+func (s *AWSLoadBalancerSpec) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if s == nil {
+		return errs
+	}
+
+	fldPath := field.NewPath("spec", "controlPlaneLoadBalancer")
+	if s.InternalLoadBalancer && s.Scheme != nil && *s.Scheme != ClassicELBSchemeInternetFacing {
+		errs = append(errs, field.Forbidden(fldPath.Child("internalLoadBalancer"), "can only be set if spec.controlPlaneLoadBalancer.scheme is internet-facing"))
+	}
+	for i, cidr := range s.AllowedCIDRBlocks {
+		if ip, _, err := net.ParseCIDR(cidr); err != nil || ip.To4() == nil {
+			errs = append(errs, field.Invalid(fldPath.Child("allowedCIDRBlocks").Index(i), cidr, "must be a valid IPv4 CIDR block"))
+		}
+	}
+	for i, id := range s.AdditionalSecurityGroups {
+		if !strings.HasPrefix(id, "sg-") {
+			errs = append(errs, field.Invalid(fldPath.Child("additionalSecurityGroups").Index(i), id, "must be a security group ID"))
+		}
+	}
+	if s.IdleTimeout != nil && (s.IdleTimeout.Duration < time.Second || s.IdleTimeout.Duration > 4000*time.Second) {
+		errs = append(errs, field.Invalid(fldPath.Child("idleTimeout"), s.IdleTimeout.Duration.String(), "must be between 1s and 4000s"))
+	}
+	if s.ConnectionDrainingTimeout != nil && (s.ConnectionDrainingTimeout.Duration < time.Second || s.ConnectionDrainingTimeout.Duration > 3600*time.Second) {
+		errs = append(errs, field.Invalid(fldPath.Child("connectionDrainingTimeout"), s.ConnectionDrainingTimeout.Duration.String(), "must be between 1s and 3600s"))
+	}
+
+	return errs
+}
+
+// Validate will validate the Karpenter configuration.
+func (k *Karpenter) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if k == nil {
+		return errs
+	}
+
+	fldPath := field.NewPath("spec", "karpenter")
+	if !feature.Gates.Enabled(feature.Karpenter) {
+		errs = append(errs, field.Forbidden(fldPath, "can only be set if the Karpenter feature flag is enabled"))
+	}
+
+	for i, arn := range k.AdditionalNodePolicyARNs {
+		if !strings.HasPrefix(arn, "arn:") {
+			errs = append(errs, field.Invalid(fldPath.Child("additionalNodePolicyARNs").Index(i), arn, "must be an IAM policy ARN"))
+		}
+	}
+
+	return errs
+}
+
+// Validate will validate the deletion policy fields.
+func (p *DeletionPolicy) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if p == nil || p.Network == ResourceDeletionPolicyRetain {
+		return errs
+	}
+
+	// The retained load balancers and instances keep using the subnets and security groups of the network,
+	// which could not be deleted.
+	fldPath := field.NewPath("spec", "deletionPolicy")
+	if p.LoadBalancers == ResourceDeletionPolicyRetain {
+		errs = append(errs, field.Invalid(fldPath.Child("network"), p.Network, "must be Retain when the load balancers are retained"))
+	}
+	if p.Instances == ResourceDeletionPolicyRetain {
+		errs = append(errs, field.Invalid(fldPath.Child("network"), p.Network, "must be Retain when the instances are retained"))
+	}
+
+	return errs
+}
+
+// Validate will validate the security group policy fields.
+func (p *SecurityGroupPolicy) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if p == nil {
+		return errs
+	}
+
+	fldPath := field.NewPath("spec", "networkSpec", "securityGroupPolicy", "allowedPorts")
+	for i, r := range p.AllowedPorts {
+		if r.Protocol != SecurityGroupProtocolAll && r.FromPort > r.ToPort {
+			errs = append(errs, field.Invalid(fldPath.Index(i).Child("toPort"), r.ToPort, "must not be lower than fromPort"))
+		}
+	}
+
+	return errs
+}
+
+func validateManagedIAMInstanceProfile(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.ManagedIAMInstanceProfile == nil {
+		return allErrs
+	}
+
+	profilePath := fldPath.Child("managedIAMInstanceProfile")
+	if !feature.Gates.Enabled(feature.MachineIAMInstanceProfile) {
+		allErrs = append(allErrs, field.Forbidden(profilePath, "can only be set if the MachineIAMInstanceProfile feature flag is enabled"))
+	}
+
+	if spec.IAMInstanceProfile != "" {
+		allErrs = append(allErrs, field.Forbidden(profilePath, "cannot be set together with iamInstanceProfile"))
+	}
+
+	if policy := spec.ManagedIAMInstanceProfile.InlinePolicy; policy != "" {
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+			allErrs = append(allErrs, field.Invalid(profilePath.Child("inlinePolicy"), policy, fmt.Sprintf("must be a JSON IAM policy document: %v", err)))
+		} else if _, ok := doc["Statement"]; !ok {
+			allErrs = append(allErrs, field.Invalid(profilePath.Child("inlinePolicy"), policy, "must contain a Statement element"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateNetwork validates the CIDR blocks of a managed VPC and its subnets.
+func validateNetwork(network *NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateNetworkFirewall(network.Firewall, fldPath.Child("firewall"))...)
+	allErrs = append(allErrs, validateGatewayLoadBalancerEndpointRoutes(network.GatewayLoadBalancerEndpointRoutes, fldPath.Child("gatewayLoadBalancerEndpointRoutes"))...)
+	allErrs = append(allErrs, validateRoutes(network.Routes, fldPath.Child("routes"))...)
+	allErrs = append(allErrs, validateElasticIPPool(network.ElasticIPPool, fldPath.Child("elasticIpPool"))...)
+	allErrs = append(allErrs, validateNatGatewayAllocationIDs(network.NatGatewayAllocationIDs, fldPath.Child("natGatewayAllocationIds"))...)
+	for i := range network.Subnets {
+		allErrs = append(allErrs, validateRoutes(network.Subnets[i].Routes, fldPath.Child("subnets").Index(i).Child("routes"))...)
+		allErrs = append(allErrs, validateUnmanagedRouteTable(&network.Subnets[i], fldPath.Child("subnets").Index(i))...)
+	}
+	if network.Firewall != nil {
+		for i := range network.Firewall.Subnets {
+			allErrs = append(allErrs, validateRoutes(network.Firewall.Subnets[i].Routes, fldPath.Child("firewall", "subnets").Index(i).Child("routes"))...)
+		}
+	}
+	if network.Firewall.IsManaged() && len(network.Subnets) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("subnets"), "must be set when a firewall is created, so that the subnets of the firewall do not overlap with them"))
+	}
+
+	if network.VPC.ID != "" || network.VPC.CidrBlock == "" {
+		return allErrs
+	}
+
+	cidrPath := fldPath.Child("vpc", "cidrBlock")
+	_, vpcNet, err := net.ParseCIDR(network.VPC.CidrBlock)
+	if err != nil {
+		return append(allErrs, field.Invalid(cidrPath, network.VPC.CidrBlock, "must be a valid CIDR block"))
+	}
+	if ones, bits := vpcNet.Mask.Size(); bits != 32 || ones < 16 || ones > 28 {
+		allErrs = append(allErrs, field.Invalid(cidrPath, network.VPC.CidrBlock, "must be an IPv4 CIDR block with a netmask between /16 and /28"))
+	}
+
+	allErrs = append(allErrs, validateSubnetCidrBlocks(network.Subnets, vpcNet, fldPath.Child("subnets"))...)
+	if network.Firewall != nil {
+		allErrs = append(allErrs, validateSubnetCidrBlocks(network.Firewall.Subnets, vpcNet, fldPath.Child("firewall", "subnets"))...)
+	}
+
+	return allErrs
+}
+
+// validateSubnetCidrBlocks validates that the subnets to create are within the CIDR block of the VPC.
+func validateSubnetCidrBlocks(subnets Subnets, vpcNet *net.IPNet, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, subnet := range subnets {
+		if subnet.ID != "" || subnet.CidrBlock == "" {
+			continue
+		}
+		subnetPath := fldPath.Index(i).Child("cidrBlock")
+		ip, subnetNet, err := net.ParseCIDR(subnet.CidrBlock)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(subnetPath, subnet.CidrBlock, "must be a valid CIDR block"))
+			continue
+		}
+		subnetOnes, _ := subnetNet.Mask.Size()
+		vpcOnes, _ := vpcNet.Mask.Size()
+		if !vpcNet.Contains(ip) || subnetOnes < vpcOnes {
+			allErrs = append(allErrs, field.Invalid(subnetPath, subnet.CidrBlock, fmt.Sprintf("must be within the VPC CIDR block %s", vpcNet.String())))
+		}
+	}
+
+	return allErrs
+}
+
+// validateGatewayLoadBalancerEndpointRoutes validates the destinations and endpoints of the routes, and that
+// no two routes to the same destination are added to the same route tables.
+func validateGatewayLoadBalancerEndpointRoutes(routes []GatewayLoadBalancerEndpointRoute, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, route := range routes {
+		routePath := fldPath.Index(i)
+		if ip, _, err := net.ParseCIDR(route.DestinationCidrBlock); err != nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("destinationCidrBlock"), route.DestinationCidrBlock, "must be a valid IPv4 CIDR block"))
+		}
+		if len(route.Endpoints) == 0 {
+			allErrs = append(allErrs, field.Required(routePath.Child("endpoints"), "at least one endpoint is required"))
+		}
+		for zone, endpointID := range route.Endpoints {
+			if !strings.HasPrefix(endpointID, "vpce-") {
+				allErrs = append(allErrs, field.Invalid(routePath.Child("endpoints").Key(zone), endpointID, "must be the ID of a VPC endpoint"))
+			}
+		}
+		for j := range routes[:i] {
+			other := routes[j]
+			if other.DestinationCidrBlock == route.DestinationCidrBlock && (other.SubnetRole == "" || route.SubnetRole == "" || other.SubnetRole == route.SubnetRole) {
+				allErrs = append(allErrs, field.Duplicate(routePath.Child("destinationCidrBlock"), route.DestinationCidrBlock))
+				break
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateRoutes validates that the static routes have a single target and distinct destinations.
+func validateRoutes(routes []Route, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	destinations := map[string]struct{}{}
+	for i, route := range routes {
+		routePath := fldPath.Index(i)
+		if ip, _, err := net.ParseCIDR(route.DestinationCidrBlock); err != nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("destinationCidrBlock"), route.DestinationCidrBlock, "must be a valid IPv4 CIDR block"))
+		}
+		if _, ok := destinations[route.DestinationCidrBlock]; ok {
+			allErrs = append(allErrs, field.Duplicate(routePath.Child("destinationCidrBlock"), route.DestinationCidrBlock))
+		}
+		destinations[route.DestinationCidrBlock] = struct{}{}
+		if len(route.Targets()) != 1 {
+			allErrs = append(allErrs, field.Invalid(routePath, route.Targets(), "exactly one of transitGatewayId, vpcPeeringConnectionId, instanceId or networkInterfaceId must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateUnmanagedRouteTable validates that the routes of a subnet associated with an existing route table are
+// not expected to be managed.
+func validateUnmanagedRouteTable(subnet *SubnetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if subnet.UnmanagedRouteTableID == "" {
+		return allErrs
+	}
+
+	if !strings.HasPrefix(subnet.UnmanagedRouteTableID, "rtb-") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("unmanagedRouteTableId"), subnet.UnmanagedRouteTableID, "must be the ID of a route table"))
+	}
+	if len(subnet.Routes) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("routes"), "cannot be set when unmanagedRouteTableId is set, as the route table is not modified"))
+	}
+
+	return allErrs
+}
+
+// validateElasticIPPool validates that the Elastic IPs of a pool are selected, and that their allocation IDs are
+// distinct.
+func validateElasticIPPool(pool *ElasticIPPool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if pool == nil {
+		return allErrs
+	}
+
+	if len(pool.AllocationIDs) == 0 && len(pool.Tags) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "one of allocationIds or tags must be set"))
+	}
+
+	seen := map[string]bool{}
+	for i, id := range pool.AllocationIDs {
+		if seen[id] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("allocationIds").Index(i), id))
+		}
+		seen[id] = true
+	}
+
+	return allErrs
+}
+
+// validateNatGatewayAllocationIDs validates that the NAT gateways of distinct availability zones use distinct Elastic IPs.
+func validateNatGatewayAllocationIDs(allocationIDs map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	zones := make([]string, 0, len(allocationIDs))
+	for zone := range allocationIDs {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	seen := map[string]bool{}
+	for _, zone := range zones {
+		id := allocationIDs[zone]
+		switch {
+		case id == "":
+			allErrs = append(allErrs, field.Required(fldPath.Key(zone), ""))
+		case seen[id]:
+			allErrs = append(allErrs, field.Duplicate(fldPath.Key(zone), id))
+		}
+		seen[id] = true
+	}
+
+	return allErrs
+}
+
+// validateNetworkFirewall validates that a firewall is either referenced or created from a policy, and that
+// the subnets of a created firewall can be created.
+func validateNetworkFirewall(firewall *NetworkFirewall, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if firewall == nil {
+		return allErrs
+	}
+
+	switch {
+	case firewall.ARN == "" && firewall.PolicyARN == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of arn or policyArn must be set"))
+	case firewall.ARN != "" && firewall.PolicyARN != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("policyArn"), "cannot be set together with arn"))
+	case firewall.ARN != "" && len(firewall.Subnets) > 0:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnets"), "cannot be set for an existing firewall"))
+	case firewall.PolicyARN != "" && len(firewall.Subnets) == 0:
+		allErrs = append(allErrs, field.Required(fldPath.Child("subnets"), "at least one subnet is required to create a firewall"))
+	}
+
+	if firewall.PolicyARN == "" {
+		return allErrs
+	}
+
+	for i, subnet := range firewall.Subnets {
+		subnetPath := fldPath.Child("subnets").Index(i)
+		if subnet.ID != "" {
+			allErrs = append(allErrs, field.Forbidden(subnetPath.Child("id"), "the subnets of the firewall are created by the provider"))
+		}
+		if subnet.CidrBlock == "" {
+			allErrs = append(allErrs, field.Required(subnetPath.Child("cidrBlock"), ""))
+		}
+		if subnet.AvailabilityZone == "" && subnet.AvailabilityZoneID == "" {
+			allErrs = append(allErrs, field.Required(subnetPath.Child("availabilityZone"), "one of availabilityZone or availabilityZoneId must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateFallbackInstanceTypes(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := map[string]bool{spec.InstanceType: true}
+	for i, instanceType := range spec.FallbackInstanceTypes {
+		switch {
+		case instanceType == "":
+			allErrs = append(allErrs, field.Required(fldPath.Child("fallbackInstanceTypes").Index(i), "instance type must not be empty"))
+		case seen[instanceType]:
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("fallbackInstanceTypes").Index(i), instanceType))
+		}
+		seen[instanceType] = true
+	}
+
+	return allErrs
+}
+
+// ValidateOSFamily validates the AMI reference and image lookup options of a machine or launch template against the
+// OS family of its AMI.
+func ValidateOSFamily(ami *AMIReference, imageLookupFormat, imageLookupOrg, imageLookupBaseOS string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if ami.OSFamily == "" {
+		return allErrs
+	}
+
+	if ami.ID != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ami", "osFamily"), "cannot be set together with ami.id"))
+	}
+	if ami.EKSOptimizedLookupType != nil && ami.OSFamily != OSFamilyAmazonLinux2 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ami", "eksLookupType"), fmt.Sprintf("only applies to the %s OS family", OSFamilyAmazonLinux2)))
+	}
+	if imageLookupBaseOS != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageLookupBaseOS"), "cannot be set together with ami.osFamily, which selects the base OS"))
+	}
+	if ami.OSFamily == OSFamilyBottlerocket {
+		if imageLookupFormat != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageLookupFormat"), "cannot be set for the Bottlerocket OS family, whose images are looked up in SSM"))
+		}
+		if imageLookupOrg != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageLookupOrg"), "cannot be set for the Bottlerocket OS family, whose images are looked up in SSM"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateBottlerocketSettings validates the Bottlerocket settings of a machine or launch template of the given
+// OS family.
+func ValidateBottlerocketSettings(settings *BottlerocketSettings, osFamily OSFamily, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if settings == nil {
+		return allErrs
+	}
+	if osFamily != OSFamilyBottlerocket {
+		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("only applies to the %s OS family", OSFamilyBottlerocket)))
+	}
+
+	for name := range settings.Sysctls {
+		if name == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sysctls"), name, "kernel parameter names cannot be empty"))
+		}
+	}
+
+	names := make(map[string]struct{}, len(settings.HostContainers))
+	for i, container := range settings.HostContainers {
+		namePath := fldPath.Child("hostContainers").Index(i).Child("name")
+		if container.Name == "admin" {
+			allErrs = append(allErrs, field.Forbidden(namePath, "the admin container is enabled with adminContainer"))
+		}
+		if _, ok := names[container.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(namePath, container.Name))
+		}
+		names[container.Name] = struct{}{}
+	}
+
+	registries := make(map[string]struct{}, len(settings.RegistryMirrors))
+	for i, mirror := range settings.RegistryMirrors {
+		mirrorPath := fldPath.Child("registryMirrors").Index(i)
+		if _, ok := registries[mirror.Registry]; ok {
+			allErrs = append(allErrs, field.Duplicate(mirrorPath.Child("registry"), mirror.Registry))
+		}
+		registries[mirror.Registry] = struct{}{}
+		for j, endpoint := range mirror.Endpoints {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(mirrorPath.Child("endpoints").Index(j), endpoint, "must be an http or https URL"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateOSFamily validates the options of an AWSMachineSpec which depend on the OS family of its AMI.
+func validateOSFamily(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := ValidateOSFamily(&spec.AMI, spec.ImageLookupFormat, spec.ImageLookupOrg, spec.ImageLookupBaseOS, fldPath)
+	allErrs = append(allErrs, ValidateBottlerocketSettings(spec.Bottlerocket, spec.AMI.OSFamily, fldPath.Child("bottlerocket"))...)
+
+	if spec.AMI.OSFamily.UserDataFormat() == UserDataFormatTOML {
+		if !spec.CloudInit.InsecureSkipSecretsManager {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("cloudInit", "insecureSkipSecretsManager"), "must be true for the Bottlerocket OS family, which cannot fetch its user data from a secret backend"))
+		}
+		if spec.UncompressedUserData != nil && !*spec.UncompressedUserData {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("uncompressedUserData"), "cannot be false for the Bottlerocket OS family, which reads uncompressed TOML user data"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateLicensing validates the host resource group and license configurations of an AWSMachineSpec.
+func validateLicensing(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if arn := spec.HostResourceGroupARN; arn != nil {
+		if !strings.HasPrefix(*arn, "arn:") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hostResourceGroupARN"), *arn, "must be an ARN"))
+		}
+		if spec.Tenancy != "" && spec.Tenancy != "host" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("tenancy"), spec.Tenancy, "must be host when hostResourceGroupARN is set"))
+		}
+	}
+
+	for i, arn := range spec.LicenseConfigurationARNs {
+		if !strings.HasPrefix(arn, "arn:") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("licenseConfigurationARNs").Index(i), arn, "must be an ARN"))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePrivateIP validates the static private IP address of an AWSMachineSpec.
+func validatePrivateIP(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.PrivateIP == nil {
+		return allErrs
+	}
+
+	if ip := net.ParseIP(*spec.PrivateIP); ip == nil || ip.To4() == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("privateIp"), *spec.PrivateIP, "must be an IPv4 address"))
+	}
+	if spec.PrivateIPFromPool != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateIp"), "cannot be set together with privateIpFromPool"))
+	}
+	if len(spec.NetworkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateIp"), "cannot be set together with networkInterfaces"))
+	}
+
+	return allErrs
+}
+
+// ValidatePrivateIPInSubnet returns an error when the private IP address isn't one the instances of the subnet
+// with the given CIDR block can be launched with: an address of the subnet other than the first four and the
+// last, which AWS reserves.
+// See https://docs.aws.amazon.com/vpc/latest/userguide/subnet-sizing.html
+func ValidatePrivateIPInSubnet(privateIP, cidrBlock string) error {
+	ip := net.ParseIP(privateIP).To4()
+	if ip == nil {
+		return fmt.Errorf("%q is not an IPv4 address", privateIP)
+	}
+	_, subnet, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR block %q: %w", cidrBlock, err)
+	}
+	if !subnet.Contains(ip) {
+		return fmt.Errorf("%s is not in the subnet CIDR block %s", privateIP, cidrBlock)
+	}
+
+	network := subnet.IP.To4()
+	offset := uint32(0)
+	last := uint32(0)
+	for i := 0; i < 4; i++ {
+		offset = offset<<8 | uint32(ip[i]-network[i])
+		last = last<<8 | uint32(^subnet.Mask[i])
+	}
+	if offset < 4 || offset == last {
+		return fmt.Errorf("%s is reserved by AWS in the subnet CIDR block %s", privateIP, cidrBlock)
+	}
+	return nil
+}
+
+// validateNetworkInterfaceConfigs validates the configuration of the network interfaces of an AWSMachineSpec.
+func validateNetworkInterfaceConfigs(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	interfaces := sets.NewString(spec.NetworkInterfaces...)
+	configured := sets.NewString()
+	for i, config := range spec.NetworkInterfaceConfigs {
+		idxPath := fldPath.Child("networkInterfaceConfigs").Index(i)
+		if !interfaces.Has(config.NetworkInterfaceID) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("networkInterfaceId"), config.NetworkInterfaceID, "must be listed in networkInterfaces"))
+		}
+		if configured.Has(config.NetworkInterfaceID) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("networkInterfaceId"), config.NetworkInterfaceID))
+		}
+		configured.Insert(config.NetworkInterfaceID)
+
+		if srd := config.EnaSrdSpecification; srd != nil && srd.EnaSrdUDPEnabled != nil && *srd.EnaSrdUDPEnabled {
+			if srd.EnaSrdEnabled == nil || !*srd.EnaSrdEnabled {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("enaSrdSpecification", "enaSrdUdpEnabled"), true, "requires enaSrdEnabled"))
+			}
+		}
+
+		if config.NetworkCardIndex == nil {
+			continue
+		}
+		if *config.NetworkCardIndex < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("networkCardIndex"), *config.NetworkCardIndex, "must not be negative"))
+		}
+		if len(spec.NetworkInterfaces) > 0 && config.NetworkInterfaceID == spec.NetworkInterfaces[0] && *config.NetworkCardIndex != 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("networkCardIndex"), *config.NetworkCardIndex, "the primary network interface must be attached to network card 0"))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePrivateIPFromPool validates the reference of an AWSMachineSpec to an IP pool of an IPAM provider.
+func validatePrivateIPFromPool(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.PrivateIPFromPool == nil {
+		return allErrs
+	}
+
+	if spec.PrivateIPFromPool.APIGroup == nil || *spec.PrivateIPFromPool.APIGroup == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("privateIpFromPool", "apiGroup"), "must be the API group of the IP pool"))
+	}
+	if len(spec.NetworkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateIpFromPool"), "cannot be set together with networkInterfaces"))
+	}
+
+	return allErrs
+}
+
+func validateSSHKeyPair(sshKeyName *string, keyPair *SSHKeyPair, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if keyPair != nil && sshKeyName != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sshKeyPair"), "can't be set together with sshKeyName"))
+	}
+	return allErrs
+}
+
 func validateSSHKeyName(sshKeyName *string) field.ErrorList {
 	var allErrs field.ErrorList
 	switch {
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
+)
+
+// The webhooks return these warnings along with the admission response. They flag settings which
+// are accepted but are likely to break machines or to be rejected by AWS later on.
+
+// machineSpecWarnings returns the admission warnings of an AWSMachine spec.
+func machineSpecWarnings(spec *AWSMachineSpec, fldPath *field.Path) webhooks.Warnings {
+	var warnings webhooks.Warnings
+
+	if spec.CloudInit.InsecureSkipSecretsManager && spec.UncompressedUserData != nil && *spec.UncompressedUserData {
+		warnings = append(warnings, fmt.Sprintf("%s and %s are true: the bootstrap data is passed uncompressed in the user data, "+
+			"which EC2 limits to 16 KiB", fldPath.Child("cloudInit", "insecureSkipSecretsManager"), fldPath.Child("uncompressedUserData")))
+	}
+
+	warnings = append(warnings, instanceMetadataOptionsWarnings(spec.InstanceMetadataOptions, fldPath.Child("instanceMetadataOptions"))...)
+
+	if feature.Gates.Enabled(feature.StrictValidation) {
+		if warning := imageArchitectureWarning(spec); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
+// clusterSpecWarnings returns the admission warnings of an AWSCluster spec.
+func clusterSpecWarnings(spec *AWSClusterSpec, fldPath *field.Path) webhooks.Warnings {
+	var warnings webhooks.Warnings
+
+	if spec.Bastion.Enabled && !spec.Bastion.DisableIngressRules && len(spec.Bastion.AllowedCIDRBlocks) == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s is empty: SSH access to the bastion host is allowed from 0.0.0.0/0",
+			fldPath.Child("bastion", "allowedCIDRBlocks")))
+	}
+
+	if spec.MachineDefaults != nil {
+		warnings = append(warnings, instanceMetadataOptionsWarnings(spec.MachineDefaults.InstanceMetadataOptions,
+			fldPath.Child("machineDefaults", "instanceMetadataOptions"))...)
+	}
+
+	return warnings
+}
+
+// instanceMetadataOptionsWarnings warns when IMDSv2 is required with a hop limit of 1, as the session
+// tokens then never reach pods which don't run in the host network.
+func instanceMetadataOptionsWarnings(options *InstanceMetadataOptions, fldPath *field.Path) webhooks.Warnings {
+	if options == nil || options.HTTPTokens != "required" || options.HTTPPutResponseHopLimit > 1 {
+		return nil
+	}
+	return webhooks.Warnings{fmt.Sprintf("%s is required and %s is at most 1: pods which don't use the host network "+
+		"can't reach the instance metadata service", fldPath.Child("httpTokens"), fldPath.Child("httpPutResponseHopLimit"))}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/gomega"
+)
+
+func TestAWSMachineValidateCreateWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         AWSMachineSpec
+		wantWarnings []string
+	}{
+		{
+			name:         "no warnings",
+			spec:         AWSMachineSpec{InstanceType: "m5.large"},
+			wantWarnings: nil,
+		},
+		{
+			name: "uncompressed user data without secrets manager",
+			spec: AWSMachineSpec{
+				InstanceType:         "m5.large",
+				UncompressedUserData: aws.Bool(true),
+				CloudInit:            CloudInit{InsecureSkipSecretsManager: true},
+			},
+			wantWarnings: []string{"spec.cloudInit.insecureSkipSecretsManager and spec.uncompressedUserData are true: " +
+				"the bootstrap data is passed uncompressed in the user data, which EC2 limits to 16 KiB"},
+		},
+		{
+			name: "uncompressed user data with secrets manager",
+			spec: AWSMachineSpec{
+				InstanceType:         "m5.large",
+				UncompressedUserData: aws.Bool(true),
+			},
+			wantWarnings: nil,
+		},
+		{
+			name: "IMDSv2 required with the default hop limit",
+			spec: AWSMachineSpec{
+				InstanceType:            "m5.large",
+				InstanceMetadataOptions: &InstanceMetadataOptions{HTTPTokens: "required"},
+			},
+			wantWarnings: []string{"spec.instanceMetadataOptions.httpTokens is required and " +
+				"spec.instanceMetadataOptions.httpPutResponseHopLimit is at most 1: pods which don't use the host network " +
+				"can't reach the instance metadata service"},
+		},
+		{
+			name: "IMDSv2 required with a hop limit of 2",
+			spec: AWSMachineSpec{
+				InstanceType:            "m5.large",
+				InstanceMetadataOptions: &InstanceMetadataOptions{HTTPTokens: "required", HTTPPutResponseHopLimit: 2},
+			},
+			wantWarnings: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &AWSMachine{Spec: tt.spec}
+			warnings, err := machine.ValidateCreate()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect([]string(warnings)).To(Equal(tt.wantWarnings))
+		})
+	}
+}
+
+func TestAWSClusterValidateCreateWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         AWSClusterSpec
+		wantWarnings int
+	}{
+		{
+			name:         "bastion disabled",
+			spec:         AWSClusterSpec{},
+			wantWarnings: 0,
+		},
+		{
+			name:         "bastion open to the internet",
+			spec:         AWSClusterSpec{Bastion: Bastion{Enabled: true}},
+			wantWarnings: 1,
+		},
+		{
+			name:         "bastion with allowed CIDR blocks",
+			spec:         AWSClusterSpec{Bastion: Bastion{Enabled: true, AllowedCIDRBlocks: []string{"10.0.0.0/8"}}},
+			wantWarnings: 0,
+		},
+		{
+			name:         "bastion without ingress rules",
+			spec:         AWSClusterSpec{Bastion: Bastion{Enabled: true, DisableIngressRules: true}},
+			wantWarnings: 0,
+		},
+		{
+			name: "machine defaults require IMDSv2 with a hop limit of 1",
+			spec: AWSClusterSpec{MachineDefaults: &MachineDefaults{
+				InstanceMetadataOptions: &InstanceMetadataOptions{HTTPTokens: "required", HTTPPutResponseHopLimit: 1},
+			}},
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			warnings := clusterSpecWarnings(&tt.spec, nil)
+			g.Expect(warnings).To(HaveLen(tt.wantWarnings))
+		})
+	}
+}
+
+func TestAWSMachineTemplateValidateUpdateWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	oldTemplate := &AWSMachineTemplate{Spec: AWSMachineTemplateSpec{Template: AWSMachineTemplateResource{Spec: AWSMachineSpec{
+		InstanceType: "m5.large",
+	}}}}
+	newTemplate := oldTemplate.DeepCopy()
+	newTemplate.Spec.Template.Spec.AdditionalTags = Tags{"team": "a"}
+
+	warnings, err := newTemplate.ValidateUpdate(oldTemplate)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect([]string(warnings)).To(Equal([]string{
+		"spec.template.spec.additionalTags changed: only machines created from now on use the new value",
+	}))
+
+	warnings, err = oldTemplate.DeepCopy().ValidateUpdate(oldTemplate)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
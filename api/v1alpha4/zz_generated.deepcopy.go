@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha4
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
@@ -160,6 +162,16 @@ func (in *AWSClusterIdentitySpec) DeepCopyInto(out *AWSClusterIdentitySpec) {
 		*out = new(AllowedNamespaces)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(IdentityProxy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSClusterIdentitySpec.
@@ -312,6 +324,46 @@ func (in *AWSClusterSpec) DeepCopyInto(out *AWSClusterSpec) {
 		*out = new(AWSIdentityReference)
 		**out = **in
 	}
+	if in.S3Bucket != nil {
+		in, out := &in.S3Bucket, &out.S3Bucket
+		*out = new(S3Bucket)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ECRPullThroughCache != nil {
+		in, out := &in.ECRPullThroughCache, &out.ECRPullThroughCache
+		*out = new(ECRPullThroughCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperationTimeouts != nil {
+		in, out := &in.OperationTimeouts, &out.OperationTimeouts
+		*out = new(OperationTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequeueIntervals != nil {
+		in, out := &in.RequeueIntervals, &out.RequeueIntervals
+		*out = new(RequeueIntervals)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Karpenter != nil {
+		in, out := &in.Karpenter, &out.Karpenter
+		*out = new(Karpenter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSHKeyPair != nil {
+		in, out := &in.SSHKeyPair, &out.SSHKeyPair
+		*out = new(SSHKeyPair)
+		**out = **in
+	}
+	if in.MachineDefaults != nil {
+		in, out := &in.MachineDefaults, &out.MachineDefaults
+		*out = new(MachineDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSClusterSpec.
@@ -421,6 +473,28 @@ func (in *AWSClusterStatus) DeepCopyInto(out *AWSClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Karpenter != nil {
+		in, out := &in.Karpenter, &out.Karpenter
+		*out = new(KarpenterStatus)
+		**out = **in
+	}
+	if in.Bastions != nil {
+		in, out := &in.Bastions, &out.Bastions
+		*out = make([]Instance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BastionLoadBalancer != nil {
+		in, out := &in.BastionLoadBalancer, &out.BastionLoadBalancer
+		*out = new(BastionLoadBalancer)
+		**out = **in
+	}
+	if in.SSHKeyPair != nil {
+		in, out := &in.SSHKeyPair, &out.SSHKeyPair
+		*out = new(SSHKeyPairStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSClusterStatus.
@@ -556,6 +630,26 @@ func (in *AWSLoadBalancerSpec) DeepCopyInto(out *AWSLoadBalancerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedCIDRBlocks != nil {
+		in, out := &in.AllowedCIDRBlocks, &out.AllowedCIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ConnectionDrainingTimeout != nil {
+		in, out := &in.ConnectionDrainingTimeout, &out.ConnectionDrainingTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.InstancePort != nil {
+		in, out := &in.InstancePort, &out.InstancePort
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerSpec.
@@ -641,6 +735,16 @@ func (in *AWSMachineSpec) DeepCopyInto(out *AWSMachineSpec) {
 		**out = **in
 	}
 	in.AMI.DeepCopyInto(&out.AMI)
+	if in.FallbackInstanceTypes != nil {
+		in, out := &in.FallbackInstanceTypes, &out.FallbackInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bottlerocket != nil {
+		in, out := &in.Bottlerocket, &out.Bottlerocket
+		*out = new(BottlerocketSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
 		*out = make(Tags, len(*in))
@@ -690,6 +794,23 @@ func (in *AWSMachineSpec) DeepCopyInto(out *AWSMachineSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NetworkInterfaceConfigs != nil {
+		in, out := &in.NetworkInterfaceConfigs, &out.NetworkInterfaceConfigs
+		*out = make([]NetworkInterfaceConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrivateIP != nil {
+		in, out := &in.PrivateIP, &out.PrivateIP
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrivateIPFromPool != nil {
+		in, out := &in.PrivateIPFromPool, &out.PrivateIPFromPool
+		*out = new(corev1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UncompressedUserData != nil {
 		in, out := &in.UncompressedUserData, &out.UncompressedUserData
 		*out = new(bool)
@@ -701,6 +822,31 @@ func (in *AWSMachineSpec) DeepCopyInto(out *AWSMachineSpec) {
 		*out = new(SpotMarketOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostResourceGroupARN != nil {
+		in, out := &in.HostResourceGroupARN, &out.HostResourceGroupARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.LicenseConfigurationARNs != nil {
+		in, out := &in.LicenseConfigurationARNs, &out.LicenseConfigurationARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPUOptions != nil {
+		in, out := &in.CPUOptions, &out.CPUOptions
+		*out = new(CPUOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceMetadataOptions != nil {
+		in, out := &in.InstanceMetadataOptions, &out.InstanceMetadataOptions
+		*out = new(InstanceMetadataOptions)
+		**out = **in
+	}
+	if in.ManagedIAMInstanceProfile != nil {
+		in, out := &in.ManagedIAMInstanceProfile, &out.ManagedIAMInstanceProfile
+		*out = new(ManagedIAMInstanceProfile)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachineSpec.
@@ -726,6 +872,23 @@ func (in *AWSMachineStatus) DeepCopyInto(out *AWSMachineStatus) {
 		*out = new(InstanceState)
 		**out = **in
 	}
+	if in.Instance != nil {
+		in, out := &in.Instance, &out.Instance
+		*out = new(InstanceDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledEvents != nil {
+		in, out := &in.ScheduledEvents, &out.ScheduledEvents
+		*out = make([]InstanceScheduledEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RightSizing != nil {
+		in, out := &in.RightSizing, &out.RightSizing
+		*out = new(RightSizingRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -761,6 +924,7 @@ func (in *AWSMachineTemplate) DeepCopyInto(out *AWSMachineTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachineTemplate.
@@ -845,6 +1009,28 @@ func (in *AWSMachineTemplateSpec) DeepCopy() *AWSMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSMachineTemplateStatus) DeepCopyInto(out *AWSMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachineTemplateStatus.
+func (in *AWSMachineTemplateStatus) DeepCopy() *AWSMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSResourceReference) DeepCopyInto(out *AWSResourceReference) {
 	*out = *in
@@ -945,6 +1131,11 @@ func (in *Bastion) DeepCopyInto(out *Bastion) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AutoScaling != nil {
+		in, out := &in.AutoScaling, &out.AutoScaling
+		*out = new(BastionAutoScaling)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bastion.
@@ -957,6 +1148,105 @@ func (in *Bastion) DeepCopy() *Bastion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionAutoScaling) DeepCopyInto(out *BastionAutoScaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionAutoScaling.
+func (in *BastionAutoScaling) DeepCopy() *BastionAutoScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionAutoScaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BastionLoadBalancer) DeepCopyInto(out *BastionLoadBalancer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BastionLoadBalancer.
+func (in *BastionLoadBalancer) DeepCopy() *BastionLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(BastionLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BottlerocketHostContainer) DeepCopyInto(out *BottlerocketHostContainer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BottlerocketHostContainer.
+func (in *BottlerocketHostContainer) DeepCopy() *BottlerocketHostContainer {
+	if in == nil {
+		return nil
+	}
+	out := new(BottlerocketHostContainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BottlerocketRegistryMirror) DeepCopyInto(out *BottlerocketRegistryMirror) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BottlerocketRegistryMirror.
+func (in *BottlerocketRegistryMirror) DeepCopy() *BottlerocketRegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(BottlerocketRegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BottlerocketSettings) DeepCopyInto(out *BottlerocketSettings) {
+	*out = *in
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HostContainers != nil {
+		in, out := &in.HostContainers, &out.HostContainers
+		*out = make([]BottlerocketHostContainer, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]BottlerocketRegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BottlerocketSettings.
+func (in *BottlerocketSettings) DeepCopy() *BottlerocketSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(BottlerocketSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -1043,6 +1333,31 @@ func (in *CNISpec) DeepCopy() *CNISpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOptions) DeepCopyInto(out *CPUOptions) {
+	*out = *in
+	if in.CoreCount != nil {
+		in, out := &in.CoreCount, &out.CoreCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ThreadsPerCore != nil {
+		in, out := &in.ThreadsPerCore, &out.ThreadsPerCore
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOptions.
+func (in *CPUOptions) DeepCopy() *CPUOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClassicELB) DeepCopyInto(out *ClassicELB) {
 	*out = *in
@@ -1079,6 +1394,11 @@ func (in *ClassicELB) DeepCopyInto(out *ClassicELB) {
 			(*out)[key] = val
 		}
 	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]ClassicELBInstanceHealth, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClassicELB.
@@ -1121,6 +1441,21 @@ func (in *ClassicELBHealthCheck) DeepCopy() *ClassicELBHealthCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClassicELBInstanceHealth) DeepCopyInto(out *ClassicELBInstanceHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClassicELBInstanceHealth.
+func (in *ClassicELBInstanceHealth) DeepCopy() *ClassicELBInstanceHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClassicELBInstanceHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClassicELBListener) DeepCopyInto(out *ClassicELBListener) {
 	*out = *in
@@ -1152,90 +1487,276 @@ func (in *CloudInit) DeepCopy() *CloudInit {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Filter) DeepCopyInto(out *Filter) {
+func (in *DeletionPolicy) DeepCopyInto(out *DeletionPolicy) {
 	*out = *in
-	if in.Values != nil {
-		in, out := &in.Values, &out.Values
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
-func (in *Filter) DeepCopy() *Filter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicy.
+func (in *DeletionPolicy) DeepCopy() *DeletionPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(Filter)
+	out := new(DeletionPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+func (in *DiscoveredSubnet) DeepCopyInto(out *DiscoveredSubnet) {
 	*out = *in
-	if in.CidrBlocks != nil {
-		in, out := &in.CidrBlocks, &out.CidrBlocks
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.SourceSecurityGroupIDs != nil {
-		in, out := &in.SourceSecurityGroupIDs, &out.SourceSecurityGroupIDs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
-func (in *IngressRule) DeepCopy() *IngressRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredSubnet.
+func (in *DiscoveredSubnet) DeepCopy() *DiscoveredSubnet {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressRule)
+	out := new(DiscoveredSubnet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in IngressRules) DeepCopyInto(out *IngressRules) {
-	{
-		in := &in
-		*out = make(IngressRules, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+func (in *ECRPullThroughCache) DeepCopyInto(out *ECRPullThroughCache) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ECRPullThroughCacheRule, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRules.
-func (in IngressRules) DeepCopy() IngressRules {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ECRPullThroughCache.
+func (in *ECRPullThroughCache) DeepCopy() *ECRPullThroughCache {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressRules)
+	out := new(ECRPullThroughCache)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Instance) DeepCopyInto(out *Instance) {
+func (in *ECRPullThroughCacheRule) DeepCopyInto(out *ECRPullThroughCacheRule) {
 	*out = *in
-	if in.SSHKeyName != nil {
-		in, out := &in.SSHKeyName, &out.SSHKeyName
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ECRPullThroughCacheRule.
+func (in *ECRPullThroughCacheRule) DeepCopy() *ECRPullThroughCacheRule {
+	if in == nil {
+		return nil
 	}
-	if in.SecurityGroupIDs != nil {
-		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+	out := new(ECRPullThroughCacheRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticIPPool) DeepCopyInto(out *ElasticIPPool) {
+	*out = *in
+	if in.AllocationIDs != nil {
+		in, out := &in.AllocationIDs, &out.AllocationIDs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.UserData != nil {
-		in, out := &in.UserData, &out.UserData
-		*out = new(string)
-		**out = **in
-	}
-	if in.Addresses != nil {
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticIPPool.
+func (in *ElasticIPPool) DeepCopy() *ElasticIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnaSrdSpecification) DeepCopyInto(out *EnaSrdSpecification) {
+	*out = *in
+	if in.EnaSrdEnabled != nil {
+		in, out := &in.EnaSrdEnabled, &out.EnaSrdEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnaSrdUDPEnabled != nil {
+		in, out := &in.EnaSrdUDPEnabled, &out.EnaSrdUDPEnabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnaSrdSpecification.
+func (in *EnaSrdSpecification) DeepCopy() *EnaSrdSpecification {
+	if in == nil {
+		return nil
+	}
+	out := new(EnaSrdSpecification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filter) DeepCopyInto(out *Filter) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
+func (in *Filter) DeepCopy() *Filter {
+	if in == nil {
+		return nil
+	}
+	out := new(Filter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayLoadBalancerEndpointRoute) DeepCopyInto(out *GatewayLoadBalancerEndpointRoute) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayLoadBalancerEndpointRoute.
+func (in *GatewayLoadBalancerEndpointRoute) DeepCopy() *GatewayLoadBalancerEndpointRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayLoadBalancerEndpointRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPv6) DeepCopyInto(out *IPv6) {
+	*out = *in
+	if in.EgressOnlyInternetGatewayID != nil {
+		in, out := &in.EgressOnlyInternetGatewayID, &out.EgressOnlyInternetGatewayID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPv6.
+func (in *IPv6) DeepCopy() *IPv6 {
+	if in == nil {
+		return nil
+	}
+	out := new(IPv6)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityProxy) DeepCopyInto(out *IdentityProxy) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityProxy.
+func (in *IdentityProxy) DeepCopy() *IdentityProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+	*out = *in
+	if in.CidrBlocks != nil {
+		in, out := &in.CidrBlocks, &out.CidrBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceSecurityGroupIDs != nil {
+		in, out := &in.SourceSecurityGroupIDs, &out.SourceSecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
+func (in *IngressRule) DeepCopy() *IngressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in IngressRules) DeepCopyInto(out *IngressRules) {
+	{
+		in := &in
+		*out = make(IngressRules, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRules.
+func (in IngressRules) DeepCopy() IngressRules {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRules)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Instance) DeepCopyInto(out *Instance) {
+	*out = *in
+	if in.SSHKeyName != nil {
+		in, out := &in.SSHKeyName, &out.SSHKeyName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(string)
+		**out = **in
+	}
+	if in.Addresses != nil {
 		in, out := &in.Addresses, &out.Addresses
 		*out = make([]apiv1alpha4.MachineAddress, len(*in))
 		copy(*out, *in)
@@ -1275,6 +1796,13 @@ func (in *Instance) DeepCopyInto(out *Instance) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NetworkInterfaceConfigs != nil {
+		in, out := &in.NetworkInterfaceConfigs, &out.NetworkInterfaceConfigs
+		*out = make([]NetworkInterfaceConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -1282,47 +1810,334 @@ func (in *Instance) DeepCopyInto(out *Instance) {
 			(*out)[key] = val
 		}
 	}
-	if in.SpotMarketOptions != nil {
-		in, out := &in.SpotMarketOptions, &out.SpotMarketOptions
-		*out = new(SpotMarketOptions)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.VolumeIDs != nil {
-		in, out := &in.VolumeIDs, &out.VolumeIDs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	if in.SpotMarketOptions != nil {
+		in, out := &in.SpotMarketOptions, &out.SpotMarketOptions
+		*out = new(SpotMarketOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LicenseConfigurationARNs != nil {
+		in, out := &in.LicenseConfigurationARNs, &out.LicenseConfigurationARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPUOptions != nil {
+		in, out := &in.CPUOptions, &out.CPUOptions
+		*out = new(CPUOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceMetadataOptions != nil {
+		in, out := &in.InstanceMetadataOptions, &out.InstanceMetadataOptions
+		*out = new(InstanceMetadataOptions)
+		**out = **in
+	}
+	if in.VolumeIDs != nil {
+		in, out := &in.VolumeIDs, &out.VolumeIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LaunchTime != nil {
+		in, out := &in.LaunchTime, &out.LaunchTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instance.
+func (in *Instance) DeepCopy() *Instance {
+	if in == nil {
+		return nil
+	}
+	out := new(Instance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceDetails) DeepCopyInto(out *InstanceDetails) {
+	*out = *in
+	if in.LaunchTime != nil {
+		in, out := &in.LaunchTime, &out.LaunchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.VolumeIDs != nil {
+		in, out := &in.VolumeIDs, &out.VolumeIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPUOptions != nil {
+		in, out := &in.CPUOptions, &out.CPUOptions
+		*out = new(CPUOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceDetails.
+func (in *InstanceDetails) DeepCopy() *InstanceDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceMetadataOptions) DeepCopyInto(out *InstanceMetadataOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceMetadataOptions.
+func (in *InstanceMetadataOptions) DeepCopy() *InstanceMetadataOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceMetadataOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceScheduledEvent) DeepCopyInto(out *InstanceScheduledEvent) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceScheduledEvent.
+func (in *InstanceScheduledEvent) DeepCopy() *InstanceScheduledEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceScheduledEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Karpenter) DeepCopyInto(out *Karpenter) {
+	*out = *in
+	if in.AdditionalNodePolicyARNs != nil {
+		in, out := &in.AdditionalNodePolicyARNs, &out.AdditionalNodePolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Karpenter.
+func (in *Karpenter) DeepCopy() *Karpenter {
+	if in == nil {
+		return nil
+	}
+	out := new(Karpenter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KarpenterStatus) DeepCopyInto(out *KarpenterStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KarpenterStatus.
+func (in *KarpenterStatus) DeepCopy() *KarpenterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KarpenterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDefaults) DeepCopyInto(out *MachineDefaults) {
+	*out = *in
+	if in.InstanceMetadataOptions != nil {
+		in, out := &in.InstanceMetadataOptions, &out.InstanceMetadataOptions
+		*out = new(InstanceMetadataOptions)
+		**out = **in
+	}
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(RootVolumeDefaults)
+		**out = **in
+	}
+	if in.AdditionalSecurityGroups != nil {
+		in, out := &in.AdditionalSecurityGroups, &out.AdditionalSecurityGroups
+		*out = make([]AWSResourceReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDefaults.
+func (in *MachineDefaults) DeepCopy() *MachineDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedIAMInstanceProfile) DeepCopyInto(out *ManagedIAMInstanceProfile) {
+	*out = *in
+	if in.ManagedPolicyARNs != nil {
+		in, out := &in.ManagedPolicyARNs, &out.ManagedPolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedIAMInstanceProfile.
+func (in *ManagedIAMInstanceProfile) DeepCopy() *ManagedIAMInstanceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedIAMInstanceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGateway) DeepCopyInto(out *NatGateway) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGateway.
+func (in *NatGateway) DeepCopy() *NatGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Network) DeepCopyInto(out *Network) {
+	*out = *in
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make(map[SecurityGroupRole]SecurityGroup, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.APIServerELB.DeepCopyInto(&out.APIServerELB)
+	in.InternalAPIServerELB.DeepCopyInto(&out.InternalAPIServerELB)
+	if in.SharedVPC != nil {
+		in, out := &in.SharedVPC, &out.SharedVPC
+		*out = new(SharedVPC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NatGateways != nil {
+		in, out := &in.NatGateways, &out.NatGateways
+		*out = make([]NatGateway, len(*in))
+		copy(*out, *in)
+	}
+	if in.RouteTables != nil {
+		in, out := &in.RouteTables, &out.RouteTables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DiscoveredSubnets != nil {
+		in, out := &in.DiscoveredSubnets, &out.DiscoveredSubnets
+		*out = make([]DiscoveredSubnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(NetworkFirewallStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
+func (in *Network) DeepCopy() *Network {
+	if in == nil {
+		return nil
+	}
+	out := new(Network)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkFirewall) DeepCopyInto(out *NetworkFirewall) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make(Subnets, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkFirewall.
+func (in *NetworkFirewall) DeepCopy() *NetworkFirewall {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkFirewall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkFirewallStatus) DeepCopyInto(out *NetworkFirewallStatus) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instance.
-func (in *Instance) DeepCopy() *Instance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkFirewallStatus.
+func (in *NetworkFirewallStatus) DeepCopy() *NetworkFirewallStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Instance)
+	out := new(NetworkFirewallStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Network) DeepCopyInto(out *Network) {
+func (in *NetworkInterfaceConfig) DeepCopyInto(out *NetworkInterfaceConfig) {
 	*out = *in
-	if in.SecurityGroups != nil {
-		in, out := &in.SecurityGroups, &out.SecurityGroups
-		*out = make(map[SecurityGroupRole]SecurityGroup, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.NetworkCardIndex != nil {
+		in, out := &in.NetworkCardIndex, &out.NetworkCardIndex
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EnaSrdSpecification != nil {
+		in, out := &in.EnaSrdSpecification, &out.EnaSrdSpecification
+		*out = new(EnaSrdSpecification)
+		(*in).DeepCopyInto(*out)
 	}
-	in.APIServerELB.DeepCopyInto(&out.APIServerELB)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
-func (in *Network) DeepCopy() *Network {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterfaceConfig.
+func (in *NetworkInterfaceConfig) DeepCopy() *NetworkInterfaceConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Network)
+	out := new(NetworkInterfaceConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1350,6 +2165,40 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.SecurityGroupPolicy != nil {
+		in, out := &in.SecurityGroupPolicy, &out.SecurityGroupPolicy
+		*out = new(SecurityGroupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(NetworkFirewall)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GatewayLoadBalancerEndpointRoutes != nil {
+		in, out := &in.GatewayLoadBalancerEndpointRoutes, &out.GatewayLoadBalancerEndpointRoutes
+		*out = make([]GatewayLoadBalancerEndpointRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]Route, len(*in))
+		copy(*out, *in)
+	}
+	if in.ElasticIPPool != nil {
+		in, out := &in.ElasticIPPool, &out.ElasticIPPool
+		*out = new(ElasticIPPool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NatGatewayAllocationIDs != nil {
+		in, out := &in.NatGatewayAllocationIDs, &out.NatGatewayAllocationIDs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
@@ -1362,6 +2211,31 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationTimeouts) DeepCopyInto(out *OperationTimeouts) {
+	*out = *in
+	if in.InstanceRunning != nil {
+		in, out := &in.InstanceRunning, &out.InstanceRunning
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LoadBalancerReady != nil {
+		in, out := &in.LoadBalancerReady, &out.LoadBalancerReady
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationTimeouts.
+func (in *OperationTimeouts) DeepCopy() *OperationTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyDocument) DeepCopyInto(out *PolicyDocument) {
 	*out = *in
@@ -1451,6 +2325,82 @@ func (in Resources) DeepCopy() Resources {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeueIntervals) DeepCopyInto(out *RequeueIntervals) {
+	*out = *in
+	if in.LoadBalancerDNS != nil {
+		in, out := &in.LoadBalancerDNS, &out.LoadBalancerDNS
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.InstanceRunning != nil {
+		in, out := &in.InstanceRunning, &out.InstanceRunning
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeueIntervals.
+func (in *RequeueIntervals) DeepCopy() *RequeueIntervals {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeueIntervals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RightSizingRecommendation) DeepCopyInto(out *RightSizingRecommendation) {
+	*out = *in
+	if in.RecommendedInstanceTypes != nil {
+		in, out := &in.RecommendedInstanceTypes, &out.RecommendedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RightSizingRecommendation.
+func (in *RightSizingRecommendation) DeepCopy() *RightSizingRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(RightSizingRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RootVolumeDefaults) DeepCopyInto(out *RootVolumeDefaults) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootVolumeDefaults.
+func (in *RootVolumeDefaults) DeepCopy() *RootVolumeDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(RootVolumeDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
+func (in *Route) DeepCopy() *Route {
+	if in == nil {
+		return nil
+	}
+	out := new(Route)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -1466,6 +2416,121 @@ func (in *RouteTable) DeepCopy() *RouteTable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Bucket) DeepCopyInto(out *S3Bucket) {
+	*out = *in
+	if in.NodesIAMRoles != nil {
+		in, out := &in.NodesIAMRoles, &out.NodesIAMRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(S3BucketEncryption)
+		**out = **in
+	}
+	if in.PublicAccessBlock != nil {
+		in, out := &in.PublicAccessBlock, &out.PublicAccessBlock
+		*out = new(S3BucketPublicAccessBlock)
+		**out = **in
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(S3BucketLifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Bucket.
+func (in *S3Bucket) DeepCopy() *S3Bucket {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Bucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BucketEncryption) DeepCopyInto(out *S3BucketEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BucketEncryption.
+func (in *S3BucketEncryption) DeepCopy() *S3BucketEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BucketEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BucketLifecycle) DeepCopyInto(out *S3BucketLifecycle) {
+	*out = *in
+	if in.NoncurrentVersionExpirationDays != nil {
+		in, out := &in.NoncurrentVersionExpirationDays, &out.NoncurrentVersionExpirationDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BucketLifecycle.
+func (in *S3BucketLifecycle) DeepCopy() *S3BucketLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BucketLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BucketPublicAccessBlock) DeepCopyInto(out *S3BucketPublicAccessBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BucketPublicAccessBlock.
+func (in *S3BucketPublicAccessBlock) DeepCopy() *S3BucketPublicAccessBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BucketPublicAccessBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHKeyPair) DeepCopyInto(out *SSHKeyPair) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeyPair.
+func (in *SSHKeyPair) DeepCopy() *SSHKeyPair {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHKeyPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHKeyPairStatus) DeepCopyInto(out *SSHKeyPairStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeyPairStatus.
+func (in *SSHKeyPairStatus) DeepCopy() *SSHKeyPairStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHKeyPairStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
@@ -1495,6 +2560,81 @@ func (in *SecurityGroup) DeepCopy() *SecurityGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupPolicy) DeepCopyInto(out *SecurityGroupPolicy) {
+	*out = *in
+	if in.AllowedPorts != nil {
+		in, out := &in.AllowedPorts, &out.AllowedPorts
+		*out = make([]SecurityGroupPortRange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupPolicy.
+func (in *SecurityGroupPolicy) DeepCopy() *SecurityGroupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityGroupPortRange) DeepCopyInto(out *SecurityGroupPortRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityGroupPortRange.
+func (in *SecurityGroupPortRange) DeepCopy() *SecurityGroupPortRange {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityGroupPortRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVPC) DeepCopyInto(out *SharedVPC) {
+	*out = *in
+	if in.SubnetIDs != nil {
+		in, out := &in.SubnetIDs, &out.SubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVPC.
+func (in *SharedVPC) DeepCopy() *SharedVPC {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotFallbackToOnDemand) DeepCopyInto(out *SpotFallbackToOnDemand) {
+	*out = *in
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotFallbackToOnDemand.
+func (in *SpotFallbackToOnDemand) DeepCopy() *SpotFallbackToOnDemand {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotFallbackToOnDemand)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpotMarketOptions) DeepCopyInto(out *SpotMarketOptions) {
 	*out = *in
@@ -1503,6 +2643,11 @@ func (in *SpotMarketOptions) DeepCopyInto(out *SpotMarketOptions) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FallbackToOnDemand != nil {
+		in, out := &in.FallbackToOnDemand, &out.FallbackToOnDemand
+		*out = new(SpotFallbackToOnDemand)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotMarketOptions.
@@ -1595,6 +2740,11 @@ func (in Statements) DeepCopy() Statements {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
+	if in.IsPublicOverride != nil {
+		in, out := &in.IsPublicOverride, &out.IsPublicOverride
+		*out = new(bool)
+		**out = **in
+	}
 	if in.RouteTableID != nil {
 		in, out := &in.RouteTableID, &out.RouteTableID
 		*out = new(string)
@@ -1612,6 +2762,11 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]Route, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -1674,6 +2829,11 @@ func (in *VPCSpec) DeepCopyInto(out *VPCSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(IPv6)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(Tags, len(*in))
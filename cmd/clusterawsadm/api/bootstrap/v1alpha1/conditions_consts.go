@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+const (
+	// IAMResourcesReadyCondition reports on the IAM roles, instance profiles and managed policies of an
+	// AWSIAMConfiguration applied to the management cluster.
+	IAMResourcesReadyCondition clusterv1.ConditionType = "IAMResourcesReady"
+	// IAMResourcesReconciliationFailedReason used when the IAM resources couldn't be created or updated.
+	IAMResourcesReconciliationFailedReason = "IAMResourcesReconciliationFailed"
+	// IAMResourcesDeletionFailedReason used when the IAM resources couldn't be deleted.
+	IAMResourcesDeletionFailedReason = "IAMResourcesDeletionFailed"
+)
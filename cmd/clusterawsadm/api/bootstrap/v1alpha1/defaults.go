@@ -32,6 +32,8 @@ const (
 	DefaultPartitionName = "aws"
 	// DefaultKMSAliasPattern is the default KMS alias.
 	DefaultKMSAliasPattern = "cluster-api-provider-aws-*"
+	// DefaultS3BucketPrefix is the default S3 bucket prefix.
+	DefaultS3BucketPrefix = "cluster-api-provider-aws-"
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -56,6 +58,9 @@ func SetDefaults_AWSIAMConfigurationSpec(obj *AWSIAMConfigurationSpec) { //nolin
 	if obj.StackName == "" {
 		obj.StackName = DefaultStackName
 	}
+	if obj.S3Buckets.NamePrefix == "" {
+		obj.S3Buckets.NamePrefix = DefaultS3BucketPrefix
+	}
 	if obj.EKS == nil {
 		obj.EKS = &EKSConfig{
 			Enable:               false,
@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // BootstrapUser contains a list of elements that is specific
@@ -30,6 +31,7 @@ type BootstrapUser struct {
 	// This can be used to scope down the initial credentials used to bootstrap the
 	// cluster.
 	// Defaults to false.
+	// +optional
 	Enable bool `json:"enable"`
 
 	// UserName controls the username of the bootstrap user. Defaults to
@@ -51,6 +53,9 @@ type BootstrapUser struct {
 
 	// Tags is a map of tags to be applied to the AWS IAM user.
 	Tags infrav1.Tags `json:"tags,omitempty"`
+
+	// PermissionsBoundary is the ARN of the managed policy used to set the permissions boundary of the AWS IAM user.
+	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
 }
 
 // ControlPlane controls the configuration of the AWS IAM role for
@@ -63,9 +68,11 @@ type ControlPlane struct {
 	DisableClusterAPIControllerPolicyAttachment bool `json:"disableClusterAPIControllerPolicyAttachment,omitempty"`
 
 	// DisableCloudProviderPolicy if set to true, will not generate and attach the AWS IAM policy for the AWS Cloud Provider.
+	// +optional
 	DisableCloudProviderPolicy bool `json:"disableCloudProviderPolicy"`
 
 	// EnableCSIPolicy if set to true, will generate and attach the AWS IAM policy for the EBS CSI Driver.
+	// +optional
 	EnableCSIPolicy bool `json:"enableCSIPolicy"`
 }
 
@@ -73,6 +80,7 @@ type ControlPlane struct {
 // Kubernetes Cluster API Provider AWS.
 type AWSIAMRoleSpec struct {
 	// Disable if set to true will not create the AWS IAM role. Defaults to false.
+	// +optional
 	Disable bool `json:"disable"` // default: false
 
 	// ExtraPolicyAttachments is a list of additional policies to be attached to the IAM role.
@@ -87,11 +95,15 @@ type AWSIAMRoleSpec struct {
 
 	// Tags is a map of tags to be applied to the AWS IAM role.
 	Tags infrav1.Tags `json:"tags,omitempty"`
+
+	// PermissionsBoundary is the ARN of the managed policy used to set the permissions boundary of the AWS IAM role.
+	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
 }
 
 // EKSConfig represents the EKS related configuration config.
 type EKSConfig struct {
 	// Enable controls whether EKS-related permissions are granted
+	// +optional
 	Enable bool `json:"enable"`
 	// AllowIAMRoleCreation controls whether the EKS controllers have permissions for creating IAM
 	// roles per cluster
@@ -111,6 +123,9 @@ type EKSConfig struct {
 	// name that is prefixed by this.
 	// Defaults to cluster-api-provider-aws-*
 	KMSAliasPrefix string `json:"kmsAliasPrefix,omitempty"`
+	// IPv6 controls whether the node roles are granted the permissions the Amazon VPC CNI plugin needs to assign
+	// IPv6 addresses to pods, as required by EKS clusters using the ipv6 IP family.
+	IPv6 bool `json:"ipv6,omitempty"`
 }
 
 // EventBridgeConfig represents configuration for enabling experimental feature to consume
@@ -120,6 +135,19 @@ type EventBridgeConfig struct {
 	Enable bool `json:"enable,omitempty"`
 }
 
+// S3Buckets controls the configuration of the AWS IAM policy for S3 buckets
+// created by the Kubernetes Cluster API Provider AWS controller.
+type S3Buckets struct {
+	// Enable controls whether permissions are granted to manage S3 buckets.
+	// +optional
+	Enable bool `json:"enable"`
+
+	// NamePrefix will be prepended to every AWS S3 bucket name the controller is allowed to manage.
+	// The name of the S3 bucket of an AWSCluster must start with this prefix.
+	// Defaults to "cluster-api-provider-aws-".
+	NamePrefix string `json:"namePrefix,omitempty"`
+}
+
 // ClusterAPIControllers controls the configuration of the AWS IAM role for
 // the Kubernetes Cluster API Provider AWS controller.
 type ClusterAPIControllers struct {
@@ -137,22 +165,69 @@ type Nodes struct {
 
 	// DisableCloudProviderPolicy if set to true, will not generate and attach the policy for the AWS Cloud Provider.
 	// Defaults to false.
+	// +optional
 	DisableCloudProviderPolicy bool `json:"disableCloudProviderPolicy"`
 
 	// EC2ContainerRegistryReadOnly controls whether the node has read-only access to the
 	// EC2 container registry
+	// +optional
 	EC2ContainerRegistryReadOnly bool `json:"ec2ContainerRegistryReadOnly"`
 }
 
+const (
+	// AWSIAMConfigurationFinalizer allows the controller to delete the IAM resources of an AWSIAMConfiguration.
+	AWSIAMConfigurationFinalizer = "awsiamconfiguration.bootstrap.aws.infrastructure.cluster.x-k8s.io"
+)
+
 // +kubebuilder:object:root=true
+// +kubebuilder:resource:path=awsiamconfigurations,scope=Cluster,categories=cluster-api,shortName=awsiam
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="IAM resources are up to date"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // AWSIAMConfiguration controls the creation of AWS Identity and Access Management (IAM) resources for use
-// by Kubernetes clusters and Kubernetes Cluster API Provider AWS.
+// by Kubernetes clusters and Kubernetes Cluster API Provider AWS. It is read by clusterawsadm from a
+// configuration file, and can be applied to the management cluster, where the controller keeps the
+// IAM resources up to date.
 type AWSIAMConfiguration struct {
-	metav1.TypeMeta `json:",inline"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec AWSIAMConfigurationSpec `json:"spec,omitempty"`
+	Spec   AWSIAMConfigurationSpec   `json:"spec,omitempty"`
+	Status AWSIAMConfigurationStatus `json:"status,omitempty"`
+}
+
+// AWSIAMConfigurationStatus defines the observed state of an AWSIAMConfiguration applied to the management cluster.
+type AWSIAMConfigurationStatus struct {
+	// Ready is true when the IAM resources of the configuration are up to date.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Roles are the names of the IAM roles created for the configuration.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+
+	// InstanceProfiles are the names of the IAM instance profiles created for the configuration.
+	// +optional
+	InstanceProfiles []string `json:"instanceProfiles,omitempty"`
+
+	// ManagedPolicies are the ARNs of the IAM managed policies created for the configuration.
+	// +optional
+	ManagedPolicies []string `json:"managedPolicies,omitempty"`
+
+	// Conditions defines current service state of the AWSIAMConfiguration.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AWSIAMConfigurationList contains a list of AWSIAMConfiguration.
+type AWSIAMConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AWSIAMConfiguration `json:"items"`
 }
 
 // AWSIAMConfigurationSpec defines the specification of the AWSIAMConfiguration.
@@ -191,13 +266,20 @@ type AWSIAMConfigurationSpec struct {
 	// EventBridge controls configuration for consuming EventBridge events
 	EventBridge *EventBridgeConfig `json:"eventBridge,omitempty"`
 
+	// S3Buckets controls the configuration of the AWS S3 buckets managed by the controller.
+	S3Buckets S3Buckets `json:"s3Buckets,omitempty"`
+
 	// Partition is the AWS security partition being used. Defaults to "aws"
 	Partition string `json:"partition,omitempty"`
 
+	// Path is the AWS IAM path under which roles, instance profiles, users, groups and managed policies
+	// are created. It must begin and end with "/". Defaults to "/".
+	// +kubebuilder:validation:Pattern=`^/(.*/)?$`
+	Path string `json:"path,omitempty"`
+
 	// SecureSecretsBackend, when set to parameter-store will create AWS Systems Manager
 	// Parameter Storage policies. By default or with the value of secrets-manager,
 	// will generate AWS Secrets Manager policies instead.
-	// +kubebuilder:validation:Enum=secrets-manager;ssm-parameter-store
 	SecureSecretsBackends []infrav1.SecretBackend `json:"secureSecretBackends,omitempty"`
 }
 
@@ -206,6 +288,16 @@ func (obj *AWSIAMConfiguration) GetObjectKind() schema.ObjectKind {
 	return &obj.TypeMeta
 }
 
+// GetConditions returns the observations of the operational state of the AWSIAMConfiguration resource.
+func (obj *AWSIAMConfiguration) GetConditions() clusterv1.Conditions {
+	return obj.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the AWSIAMConfiguration to the predescribed clusterv1.Conditions.
+func (obj *AWSIAMConfiguration) SetConditions(conditions clusterv1.Conditions) {
+	obj.Status.Conditions = conditions
+}
+
 // NewAWSIAMConfiguration will generate a new default AWSIAMConfiguration.
 func NewAWSIAMConfiguration() *AWSIAMConfiguration {
 	conf := &AWSIAMConfiguration{}
@@ -23,13 +23,16 @@ package v1alpha1
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	apiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSIAMConfiguration) DeepCopyInto(out *AWSIAMConfiguration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSIAMConfiguration.
@@ -50,6 +53,38 @@ func (in *AWSIAMConfiguration) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSIAMConfigurationList) DeepCopyInto(out *AWSIAMConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AWSIAMConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSIAMConfigurationList.
+func (in *AWSIAMConfigurationList) DeepCopy() *AWSIAMConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSIAMConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSIAMConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSIAMConfigurationSpec) DeepCopyInto(out *AWSIAMConfigurationSpec) {
 	*out = *in
@@ -72,6 +107,7 @@ func (in *AWSIAMConfigurationSpec) DeepCopyInto(out *AWSIAMConfigurationSpec) {
 		*out = new(EventBridgeConfig)
 		**out = **in
 	}
+	out.S3Buckets = in.S3Buckets
 	if in.SecureSecretsBackends != nil {
 		in, out := &in.SecureSecretsBackends, &out.SecureSecretsBackends
 		*out = make([]v1alpha4.SecretBackend, len(*in))
@@ -89,6 +125,43 @@ func (in *AWSIAMConfigurationSpec) DeepCopy() *AWSIAMConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSIAMConfigurationStatus) DeepCopyInto(out *AWSIAMConfigurationStatus) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceProfiles != nil {
+		in, out := &in.InstanceProfiles, &out.InstanceProfiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedPolicies != nil {
+		in, out := &in.ManagedPolicies, &out.ManagedPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSIAMConfigurationStatus.
+func (in *AWSIAMConfigurationStatus) DeepCopy() *AWSIAMConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSIAMConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSIAMRoleSpec) DeepCopyInto(out *AWSIAMRoleSpec) {
 	*out = *in
@@ -262,3 +335,18 @@ func (in *Nodes) DeepCopy() *Nodes {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Buckets) DeepCopyInto(out *S3Buckets) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Buckets.
+func (in *S3Buckets) DeepCopy() *S3Buckets {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Buckets)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -29,6 +29,7 @@ import (
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&AWSIAMConfiguration{}, func(obj interface{}) { SetObjectDefaults_AWSIAMConfiguration(obj.(*AWSIAMConfiguration)) })
+	scheme.AddTypeDefaultingFunc(&AWSIAMConfigurationList{}, func(obj interface{}) { SetObjectDefaults_AWSIAMConfigurationList(obj.(*AWSIAMConfigurationList)) })
 	return nil
 }
 
@@ -37,3 +38,10 @@ func SetObjectDefaults_AWSIAMConfiguration(in *AWSIAMConfiguration) {
 	SetDefaults_AWSIAMConfigurationSpec(&in.Spec)
 	SetDefaults_BootstrapUser(&in.Spec.BootstrapUser)
 }
+
+func SetObjectDefaults_AWSIAMConfigurationList(in *AWSIAMConfigurationList) {
+	for i := range in.Items {
+		a := &in.Items[i]
+		SetObjectDefaults_AWSIAMConfiguration(a)
+	}
+}
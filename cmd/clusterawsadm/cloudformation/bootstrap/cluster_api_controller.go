@@ -51,7 +51,7 @@ func (t Template) controllersPolicyRoleAttachments() []string {
 }
 
 func (t Template) controllersTrustPolicy() *infrav1.PolicyDocument {
-	policyDocument := ec2AssumeRolePolicy()
+	policyDocument := t.ec2AssumeRolePolicy()
 	policyDocument.Statement = append(policyDocument.Statement, t.Spec.ClusterAPIControllers.TrustStatements...)
 	return policyDocument
 }
@@ -81,10 +81,12 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 			Resource: infrav1.Resources{infrav1.Any},
 			Action: infrav1.Actions{
 				"ec2:AllocateAddress",
+				"ec2:AssociateAddress",
 				"ec2:AssociateRouteTable",
 				"ec2:AttachInternetGateway",
 				"ec2:AuthorizeSecurityGroupIngress",
 				"ec2:CreateInternetGateway",
+				"ec2:CreateEgressOnlyInternetGateway",
 				"ec2:CreateNatGateway",
 				"ec2:CreateRoute",
 				"ec2:CreateRouteTable",
@@ -94,7 +96,9 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"ec2:CreateVpc",
 				"ec2:ModifyVpcAttribute",
 				"ec2:DeleteInternetGateway",
+				"ec2:DeleteEgressOnlyInternetGateway",
 				"ec2:DeleteNatGateway",
+				"ec2:DeleteNetworkInterface",
 				"ec2:DeleteRouteTable",
 				"ec2:DeleteSecurityGroup",
 				"ec2:DeleteSubnet",
@@ -104,42 +108,68 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"ec2:DescribeAddresses",
 				"ec2:DescribeAvailabilityZones",
 				"ec2:DescribeInstances",
+				"ec2:DescribeInstanceStatus",
+				"ec2:DescribeInstanceTypeOfferings",
+				"ec2:DescribeInstanceTypes",
 				"ec2:DescribeInternetGateways",
+				"ec2:DescribeEgressOnlyInternetGateways",
 				"ec2:DescribeImages",
 				"ec2:DescribeNatGateways",
 				"ec2:DescribeNetworkInterfaces",
 				"ec2:DescribeNetworkInterfaceAttribute",
+				"ec2:DescribeRegions",
 				"ec2:DescribeRouteTables",
 				"ec2:DescribeSecurityGroups",
+				"ec2:DescribeSpotPriceHistory",
 				"ec2:DescribeSubnets",
 				"ec2:DescribeVpcs",
 				"ec2:DescribeVpcAttribute",
 				"ec2:DescribeVolumes",
 				"ec2:DetachInternetGateway",
+				"ec2:DetachNetworkInterface",
 				"ec2:DisassociateRouteTable",
 				"ec2:DisassociateAddress",
 				"ec2:ModifyInstanceAttribute",
 				"ec2:ModifyNetworkInterfaceAttribute",
 				"ec2:ModifySubnetAttribute",
 				"ec2:ReleaseAddress",
+				"ec2:ReplaceRoute",
+				"ec2:ReplaceRouteTableAssociation",
 				"ec2:RevokeSecurityGroupIngress",
 				"ec2:RunInstances",
 				"ec2:TerminateInstances",
 				"tag:GetResources",
+				"servicequotas:GetAWSDefaultServiceQuota",
+				"servicequotas:GetServiceQuota",
+				"pricing:GetProducts",
+				"compute-optimizer:GetEC2InstanceRecommendations",
 				"elasticloadbalancing:AddTags",
 				"elasticloadbalancing:CreateLoadBalancer",
 				"elasticloadbalancing:ConfigureHealthCheck",
 				"elasticloadbalancing:DeleteLoadBalancer",
 				"elasticloadbalancing:DescribeLoadBalancers",
 				"elasticloadbalancing:DescribeLoadBalancerAttributes",
+				"elasticloadbalancing:DescribeInstanceHealth",
 				"elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+				"elasticloadbalancing:AttachLoadBalancerToSubnets",
+				"elasticloadbalancing:DetachLoadBalancerFromSubnets",
 				"elasticloadbalancing:DescribeTags",
 				"elasticloadbalancing:ModifyLoadBalancerAttributes",
+				"elasticloadbalancing:CreateLoadBalancerPolicy",
+				"elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
+				"elasticloadbalancing:CreateLoadBalancerListeners",
+				"elasticloadbalancing:DeleteLoadBalancerListeners",
 				"elasticloadbalancing:RegisterInstancesWithLoadBalancer",
 				"elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
 				"elasticloadbalancing:RemoveTags",
+				"elasticloadbalancing:CreateListener",
+				"elasticloadbalancing:CreateTargetGroup",
+				"elasticloadbalancing:DeleteTargetGroup",
+				"elasticloadbalancing:DescribeListeners",
+				"elasticloadbalancing:DescribeTargetGroups",
 				"autoscaling:DescribeAutoScalingGroups",
 				"autoscaling:DescribeInstanceRefreshes",
+				"autoscaling:DescribeLifecycleHooks",
 				"ec2:CreateLaunchTemplate",
 				"ec2:CreateLaunchTemplateVersion",
 				"ec2:DescribeLaunchTemplates",
@@ -147,6 +177,23 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"ec2:DeleteLaunchTemplate",
 				"ec2:DeleteLaunchTemplateVersions",
 				"ec2:DescribeKeyPairs",
+				"ec2:CreateKeyPair",
+				"ec2:ImportKeyPair",
+				"ec2:DeleteKeyPair",
+				"ec2:CreateFleet",
+				"ec2:CreateNetworkInsightsPath",
+				"ec2:DescribeNetworkInsightsPaths",
+				"ec2:DeleteNetworkInsightsPath",
+				"ec2:StartNetworkInsightsAnalysis",
+				"ec2:DescribeNetworkInsightsAnalyses",
+				"ec2:DeleteNetworkInsightsAnalysis",
+				"tiros:CreateQuery",
+				"tiros:GetQueryAnswer",
+				"tiros:GetQueryExplanation",
+				"network-firewall:CreateFirewall",
+				"network-firewall:DescribeFirewall",
+				"network-firewall:DeleteFirewall",
+				"network-firewall:TagResource",
 			},
 		},
 		{
@@ -161,6 +208,13 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"autoscaling:StartInstanceRefresh",
 				"autoscaling:DeleteAutoScalingGroup",
 				"autoscaling:DeleteTags",
+				"autoscaling:PutLifecycleHook",
+				"autoscaling:DeleteLifecycleHook",
+				"autoscaling:CompleteLifecycleAction",
+				"autoscaling:AttachLoadBalancerTargetGroups",
+				"autoscaling:DetachLoadBalancerTargetGroups",
+				"autoscaling:SetInstanceProtection",
+				"autoscaling:TerminateInstanceInAutoScalingGroup",
 			},
 		},
 		{
@@ -199,6 +253,30 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				infrav1.StringLike: map[string]string{"iam:AWSServiceName": "spot.amazonaws.com"},
 			},
 		},
+		{
+			Effect: infrav1.EffectAllow,
+			Action: infrav1.Actions{
+				"iam:CreateServiceLinkedRole",
+			},
+			Resource: infrav1.Resources{
+				"arn:*:iam::*:role/aws-service-role/ec2fleet.amazonaws.com/AWSServiceRoleForEC2Fleet",
+			},
+			Condition: infrav1.Conditions{
+				infrav1.StringLike: map[string]string{"iam:AWSServiceName": "ec2fleet.amazonaws.com"},
+			},
+		},
+		{
+			Effect: infrav1.EffectAllow,
+			Action: infrav1.Actions{
+				"iam:CreateServiceLinkedRole",
+			},
+			Resource: infrav1.Resources{
+				"arn:*:iam::*:role/aws-service-role/network-firewall.amazonaws.com/AWSServiceRoleForNetworkFirewall",
+			},
+			Condition: infrav1.Conditions{
+				infrav1.StringLike: map[string]string{"iam:AWSServiceName": "network-firewall.amazonaws.com"},
+			},
+		},
 		{
 			Effect:   infrav1.EffectAllow,
 			Resource: t.allowedEC2InstanceProfiles(),
@@ -206,6 +284,15 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"iam:PassRole",
 			},
 		},
+		{
+			Effect: infrav1.EffectAllow,
+			Resource: infrav1.Resources{
+				"arn:*:ssm:*:*:parameter/aws/service/bottlerocket/*",
+			},
+			Action: infrav1.Actions{
+				"ssm:GetParameter",
+			},
+		},
 	}
 	for _, secureSecretBackend := range t.Spec.SecureSecretsBackends {
 		switch secureSecretBackend {
@@ -244,6 +331,7 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 			Effect: infrav1.EffectAllow,
 			Resource: infrav1.Resources{
 				"arn:*:ssm:*:*:parameter/aws/service/eks/optimized-ami/*",
+				"arn:*:ssm:*:*:parameter/aws/service/canonical/ubuntu/eks/*",
 			},
 			Action: infrav1.Actions{
 				"ssm:GetParameter",
@@ -282,7 +370,7 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"iam:CreateServiceLinkedRole",
 			},
 			Resource: infrav1.Resources{
-				"arn:aws:iam::*:role/aws-service-role/eks-fargate-pods.amazonaws.com/AWSServiceRoleForAmazonEKSForFargate",
+				"arn:" + t.Spec.Partition + ":iam::*:role/aws-service-role/eks-fargate-pods.amazonaws.com/AWSServiceRoleForAmazonEKSForFargate",
 			},
 			Condition: infrav1.Conditions{
 				infrav1.StringLike: map[string]string{"iam:AWSServiceName": "eks-fargate.amazonaws.com"},
@@ -296,6 +384,7 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"iam:CreateRole",
 				"iam:TagRole",
 				"iam:AttachRolePolicy",
+				"iam:PutRolePermissionsBoundary",
 			}...)
 
 			statement = append(statement, infrav1.StatementEntry{
@@ -406,6 +495,7 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"events:DeleteRule",
 				"events:DescribeRule",
 				"events:ListTargetsByRule",
+				"events:PutPermission",
 				"events:PutRule",
 				"events:PutTargets",
 				"events:RemoveTargets",
@@ -420,6 +510,35 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 		})
 	}
 
+	if t.Spec.S3Buckets.Enable {
+		statement = append(statement, infrav1.StatementEntry{
+			Effect:   infrav1.EffectAllow,
+			Resource: infrav1.Resources{fmt.Sprintf("arn:*:s3:::%s*", t.Spec.S3Buckets.NamePrefix)},
+			Action: infrav1.Actions{
+				"s3:CreateBucket",
+				"s3:DeleteBucket",
+				"s3:DeleteObject",
+				"s3:GetBucketVersioning",
+				"s3:ListBucket",
+				"s3:PutBucketPolicy",
+				"s3:PutBucketPublicAccessBlock",
+				"s3:PutBucketTagging",
+				"s3:PutBucketVersioning",
+				"s3:PutEncryptionConfiguration",
+				"s3:PutLifecycleConfiguration",
+				"s3:PutObject",
+			},
+		})
+		// The bucket policy grants access to the ARNs of the node roles, looked up with their path.
+		statement = append(statement, infrav1.StatementEntry{
+			Effect:   infrav1.EffectAllow,
+			Resource: infrav1.Resources{"arn:*:iam::*:role/*"},
+			Action: infrav1.Actions{
+				"iam:GetRole",
+			},
+		})
+	}
+
 	return &infrav1.PolicyDocument{
 		Version:   infrav1.CurrentVersion,
 		Statement: statement,
@@ -18,6 +18,7 @@ package bootstrap
 
 import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
 )
 
 func (t Template) secretPolicy(secureSecretsBackend infrav1.SecretBackend) infrav1.StatementEntry {
@@ -97,5 +98,5 @@ func (t Template) nodePolicy() *infrav1.PolicyDocument {
 }
 
 func (t Template) generateAWSManagedPolicyARN(name string) string {
-	return "arn:" + t.Spec.Partition + ":iam::aws:policy/" + name
+	return awsarn.AWSManagedPolicy(t.Spec.Partition, name)
 }
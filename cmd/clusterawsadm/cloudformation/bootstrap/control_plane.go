@@ -39,7 +39,7 @@ func (t Template) controlPlanePolicies() []cfn_iam.Role_Policy {
 }
 
 func (t Template) controlPlaneTrustPolicy() *v1alpha4.PolicyDocument {
-	policyDocument := ec2AssumeRolePolicy()
+	policyDocument := t.ec2AssumeRolePolicy()
 	policyDocument.Statement = append(policyDocument.Statement, t.Spec.ControlPlane.TrustStatements...)
 	return policyDocument
 }
@@ -21,8 +21,8 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks"
 )
 
-func fargateProfilePolicies(roleSpec *bootstrapv1.AWSIAMRoleSpec) []string {
-	policies := eks.FargateRolePolicies()
+func fargateProfilePolicies(roleSpec *bootstrapv1.AWSIAMRoleSpec, partition string) []string {
+	policies := eks.FargateRolePolicies(partition)
 	if roleSpec.ExtraPolicyAttachments != nil {
 		policies = append(policies, roleSpec.ExtraPolicyAttachments...)
 	}
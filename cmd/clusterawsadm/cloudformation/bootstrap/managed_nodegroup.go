@@ -16,13 +16,58 @@ limitations under the License.
 
 package bootstrap
 
-import "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks"
+import (
+	cfn_iam "github.com/awslabs/goformation/v4/cloudformation/iam"
+	"sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks"
+)
 
 func (t Template) eksMachinePoolPolicies() []string {
-	policies := eks.NodegroupRolePolicies()
+	policies := eks.NodegroupRolePolicies(t.Spec.Partition)
 	if t.Spec.EKS.ManagedMachinePool.ExtraPolicyAttachments != nil {
 		policies = append(policies, t.Spec.EKS.ManagedMachinePool.ExtraPolicyAttachments...)
 	}
 
 	return policies
 }
+
+// eksIPv6CNIPolicy returns the policy the Amazon VPC CNI plugin needs to assign IPv6 addresses to the pods of the nodes
+// of an IPv6 EKS cluster, which AWS doesn't provide as a managed policy.
+func (t Template) eksIPv6CNIPolicy() cfn_iam.Role_Policy {
+	return cfn_iam.Role_Policy{
+		PolicyName: "AmazonEKS_CNI_IPv6_Policy",
+		PolicyDocument: v1alpha4.PolicyDocument{
+			Version: v1alpha4.CurrentVersion,
+			Statement: []v1alpha4.StatementEntry{
+				{
+					Effect:   v1alpha4.EffectAllow,
+					Resource: v1alpha4.Resources{v1alpha4.Any},
+					Action: v1alpha4.Actions{
+						"ec2:AssignIpv6Addresses",
+						"ec2:DescribeInstances",
+						"ec2:DescribeTags",
+						"ec2:DescribeNetworkInterfaces",
+						"ec2:DescribeInstanceTypes",
+					},
+				},
+				{
+					Effect: v1alpha4.EffectAllow,
+					Resource: v1alpha4.Resources{
+						"arn:*:ec2:*:*:network-interface/*",
+					},
+					Action: v1alpha4.Actions{
+						"ec2:CreateTags",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t Template) eksMachinePoolInlinePolicies() []cfn_iam.Role_Policy {
+	policies := []cfn_iam.Role_Policy{}
+	if t.Spec.EKS.IPv6 {
+		policies = append(policies, t.eksIPv6CNIPolicy())
+	}
+	return policies
+}
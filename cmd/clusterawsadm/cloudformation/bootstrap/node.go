@@ -34,11 +34,14 @@ func (t Template) nodePolicies() []cfn_iam.Role_Policy {
 			},
 		)
 	}
+	if t.Spec.EKS.Enable && t.Spec.EKS.IPv6 {
+		policies = append(policies, t.eksIPv6CNIPolicy())
+	}
 	return policies
 }
 
 func (t Template) nodeTrustPolicy() *v1alpha4.PolicyDocument {
-	policyDocument := ec2AssumeRolePolicy()
+	policyDocument := t.ec2AssumeRolePolicy()
 	policyDocument.Statement = append(policyDocument.Statement, t.Spec.Nodes.TrustStatements...)
 	return policyDocument
 }
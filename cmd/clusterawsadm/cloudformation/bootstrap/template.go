@@ -48,6 +48,8 @@ const (
 	ControlPlanePolicy                PolicyName = "AWSIAMManagedPolicyCloudProviderControlPlane"
 	NodePolicy                        PolicyName = "AWSIAMManagedPolicyCloudProviderNodes"
 	CSIPolicy                         PolicyName = "AWSEBSCSIPolicyController"
+
+	chinaPartitionName = "aws-cn"
 )
 
 // Template is an AWS CloudFormation template to bootstrap
@@ -76,20 +78,24 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	if t.Spec.BootstrapUser.Enable {
 		template.Resources[AWSIAMUserBootstrapper] = &cfn_iam.User{
-			UserName:          t.Spec.BootstrapUser.UserName,
-			Groups:            t.bootstrapUserGroups(),
-			ManagedPolicyArns: t.Spec.ControlPlane.ExtraPolicyAttachments,
-			Policies:          t.bootstrapUserPolicy(),
-			Tags:              converters.MapToCloudFormationTags(t.Spec.BootstrapUser.Tags),
+			UserName:            t.Spec.BootstrapUser.UserName,
+			Path:                t.Spec.Path,
+			PermissionsBoundary: t.Spec.BootstrapUser.PermissionsBoundary,
+			Groups:              t.bootstrapUserGroups(),
+			ManagedPolicyArns:   t.Spec.ControlPlane.ExtraPolicyAttachments,
+			Policies:            t.bootstrapUserPolicy(),
+			Tags:                converters.MapToCloudFormationTags(t.Spec.BootstrapUser.Tags),
 		}
 
 		template.Resources[AWSIAMGroupBootstrapper] = &cfn_iam.Group{
 			GroupName: t.Spec.BootstrapUser.GroupName,
+			Path:      t.Spec.Path,
 		}
 	}
 
 	template.Resources[string(ControllersPolicy)] = &cfn_iam.ManagedPolicy{
 		ManagedPolicyName: t.NewManagedName("controllers"),
+		Path:              t.Spec.Path,
 		Description:       `For the Kubernetes Cluster API Provider AWS Controllers`,
 		PolicyDocument:    t.ControllersPolicy(),
 		Groups:            t.controllersPolicyGroups(),
@@ -99,6 +105,7 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	if !t.Spec.ControlPlane.DisableCloudProviderPolicy {
 		template.Resources[string(ControlPlanePolicy)] = &cfn_iam.ManagedPolicy{
 			ManagedPolicyName: t.NewManagedName("control-plane"),
+			Path:              t.Spec.Path,
 			Description:       `For the Kubernetes Cloud Provider AWS Control Plane`,
 			PolicyDocument:    t.cloudProviderControlPlaneAwsPolicy(),
 			Roles:             t.cloudProviderControlPlaneAwsRoles(),
@@ -108,6 +115,7 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	if !t.Spec.Nodes.DisableCloudProviderPolicy {
 		template.Resources[string(NodePolicy)] = &cfn_iam.ManagedPolicy{
 			ManagedPolicyName: t.NewManagedName("nodes"),
+			Path:              t.Spec.Path,
 			Description:       `For the Kubernetes Cloud Provider AWS nodes`,
 			PolicyDocument:    t.nodePolicy(),
 			Roles:             t.cloudProviderNodeAwsRoles(),
@@ -117,6 +125,7 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	if t.Spec.ControlPlane.EnableCSIPolicy {
 		template.Resources[string(CSIPolicy)] = &cfn_iam.ManagedPolicy{
 			ManagedPolicyName: t.NewManagedName("csi"),
+			Path:              t.Spec.Path,
 			Description:       `For the AWS EBS CSI Driver for Kubernetes`,
 			PolicyDocument:    t.csiControllerPolicy(),
 			Roles:             t.csiControlPlaneAwsRoles(),
@@ -125,6 +134,8 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	template.Resources[AWSIAMRoleControlPlane] = &cfn_iam.Role{
 		RoleName:                 t.NewManagedName("control-plane"),
+		Path:                     t.Spec.Path,
+		PermissionsBoundary:      t.Spec.ControlPlane.PermissionsBoundary,
 		AssumeRolePolicyDocument: t.controlPlaneTrustPolicy(),
 		ManagedPolicyArns:        t.Spec.ControlPlane.ExtraPolicyAttachments,
 		Policies:                 t.controlPlanePolicies(),
@@ -133,6 +144,8 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	template.Resources[AWSIAMRoleControllers] = &cfn_iam.Role{
 		RoleName:                 t.NewManagedName("controllers"),
+		Path:                     t.Spec.Path,
+		PermissionsBoundary:      t.Spec.ClusterAPIControllers.PermissionsBoundary,
 		AssumeRolePolicyDocument: t.controllersTrustPolicy(),
 		Policies:                 t.controllersRolePolicy(),
 		Tags:                     converters.MapToCloudFormationTags(t.Spec.ClusterAPIControllers.Tags),
@@ -140,6 +153,8 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	template.Resources[AWSIAMRoleNodes] = &cfn_iam.Role{
 		RoleName:                 t.NewManagedName("nodes"),
+		Path:                     t.Spec.Path,
+		PermissionsBoundary:      t.Spec.Nodes.PermissionsBoundary,
 		AssumeRolePolicyDocument: t.nodeTrustPolicy(),
 		ManagedPolicyArns:        t.nodeManagedPolicies(),
 		Policies:                 t.nodePolicies(),
@@ -148,6 +163,7 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	template.Resources[AWSIAMInstanceProfileControlPlane] = &cfn_iam.InstanceProfile{
 		InstanceProfileName: t.NewManagedName("control-plane"),
+		Path:                t.Spec.Path,
 		Roles: []string{
 			cloudformation.Ref(AWSIAMRoleControlPlane),
 		},
@@ -155,6 +171,7 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	template.Resources[AWSIAMInstanceProfileControllers] = &cfn_iam.InstanceProfile{
 		InstanceProfileName: t.NewManagedName("controllers"),
+		Path:                t.Spec.Path,
 		Roles: []string{
 			cloudformation.Ref(AWSIAMRoleControllers),
 		},
@@ -162,6 +179,7 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 
 	template.Resources[AWSIAMInstanceProfileNodes] = &cfn_iam.InstanceProfile{
 		InstanceProfileName: t.NewManagedName("nodes"),
+		Path:                t.Spec.Path,
 		Roles: []string{
 			cloudformation.Ref(AWSIAMRoleNodes),
 		},
@@ -170,6 +188,8 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	if !t.Spec.EKS.DefaultControlPlaneRole.Disable {
 		template.Resources[AWSIAMRoleEKSControlPlane] = &cfn_iam.Role{
 			RoleName:                 ekscontrolplanev1.DefaultEKSControlPlaneRole,
+			Path:                     t.Spec.Path,
+			PermissionsBoundary:      t.Spec.EKS.DefaultControlPlaneRole.PermissionsBoundary,
 			AssumeRolePolicyDocument: AssumeRolePolicy(v1alpha4.PrincipalService, []string{"eks.amazonaws.com"}),
 			ManagedPolicyArns:        t.eksControlPlanePolicies(),
 			Tags:                     converters.MapToCloudFormationTags(t.Spec.EKS.DefaultControlPlaneRole.Tags),
@@ -179,8 +199,11 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	if !t.Spec.EKS.ManagedMachinePool.Disable {
 		template.Resources[AWSIAMRoleEKSNodegroup] = &cfn_iam.Role{
 			RoleName:                 infrav1exp.DefaultEKSNodegroupRole,
-			AssumeRolePolicyDocument: AssumeRolePolicy(v1alpha4.PrincipalService, []string{"ec2.amazonaws.com", "eks.amazonaws.com"}),
+			Path:                     t.Spec.Path,
+			PermissionsBoundary:      t.Spec.EKS.ManagedMachinePool.PermissionsBoundary,
+			AssumeRolePolicyDocument: AssumeRolePolicy(v1alpha4.PrincipalService, []string{t.ec2ServicePrincipal(), "eks.amazonaws.com"}),
 			ManagedPolicyArns:        t.eksMachinePoolPolicies(),
+			Policies:                 t.eksMachinePoolInlinePolicies(),
 			Tags:                     converters.MapToCloudFormationTags(t.Spec.EKS.ManagedMachinePool.Tags),
 		}
 	}
@@ -188,8 +211,10 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	if !t.Spec.EKS.Fargate.Disable {
 		template.Resources[AWSIAMRoleEKSFargate] = &cfn_iam.Role{
 			RoleName:                 infrav1exp.DefaultEKSFargateRole,
+			Path:                     t.Spec.Path,
+			PermissionsBoundary:      t.Spec.EKS.Fargate.PermissionsBoundary,
 			AssumeRolePolicyDocument: AssumeRolePolicy(v1alpha4.PrincipalService, []string{eksiam.EKSFargateService}),
-			ManagedPolicyArns:        fargateProfilePolicies(t.Spec.EKS.Fargate),
+			ManagedPolicyArns:        fargateProfilePolicies(t.Spec.EKS.Fargate, t.Spec.Partition),
 			Tags:                     converters.MapToCloudFormationTags(t.Spec.EKS.Fargate.Tags),
 		}
 	}
@@ -197,8 +222,17 @@ func (t Template) RenderCloudFormation() *cloudformation.Template {
 	return template
 }
 
-func ec2AssumeRolePolicy() *v1alpha4.PolicyDocument {
-	return AssumeRolePolicy(v1alpha4.PrincipalService, []string{"ec2.amazonaws.com"})
+func (t Template) ec2AssumeRolePolicy() *v1alpha4.PolicyDocument {
+	return AssumeRolePolicy(v1alpha4.PrincipalService, []string{t.ec2ServicePrincipal()})
+}
+
+// ec2ServicePrincipal returns the EC2 service principal, which differs in the
+// AWS China partition.
+func (t Template) ec2ServicePrincipal() string {
+	if t.Spec.Partition == chinaPartitionName {
+		return "ec2.amazonaws.com.cn"
+	}
+	return "ec2.amazonaws.com"
 }
 
 // AWSArnAssumeRolePolicy will assume Policies using PolicyArns.
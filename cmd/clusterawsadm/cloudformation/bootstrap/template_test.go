@@ -61,6 +61,33 @@ func Test_RenderCloudformation(t *testing.T) {
 				return t
 			},
 		},
+		{
+			fixture: "with_s3_bucket",
+			template: func() Template {
+				t := NewTemplate()
+				t.Spec.S3Buckets.Enable = true
+				return t
+			},
+		},
+		{
+			fixture: "with_path_and_permissions_boundary",
+			template: func() Template {
+				t := NewTemplate()
+				t.Spec.Path = "/capa/"
+				t.Spec.ControlPlane.PermissionsBoundary = "arn:aws:iam::123456789012:policy/capa-boundary"
+				t.Spec.ClusterAPIControllers.PermissionsBoundary = "arn:aws:iam::123456789012:policy/capa-boundary"
+				t.Spec.Nodes.PermissionsBoundary = "arn:aws:iam::123456789012:policy/capa-boundary"
+				return t
+			},
+		},
+		{
+			fixture: "with_china_partition",
+			template: func() Template {
+				t := NewTemplate()
+				t.Spec.Partition = "aws-cn"
+				return t
+			},
+		},
 		{
 			fixture: "customsuffix",
 			template: func() Template {
@@ -125,6 +152,17 @@ func Test_RenderCloudformation(t *testing.T) {
 				return t
 			},
 		},
+		{
+			fixture: "with_eks_ipv6",
+			template: func() Template {
+				t := NewTemplate()
+				t.Spec.EKS.Enable = true
+				t.Spec.EKS.IPv6 = true
+				t.Spec.Nodes.EC2ContainerRegistryReadOnly = true
+				t.Spec.EKS.ManagedMachinePool.Disable = false
+				return t
+			},
+		},
 		{
 			fixture: "with_extra_statements",
 			template: func() Template {
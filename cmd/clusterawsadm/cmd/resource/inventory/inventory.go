@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/cmd/flags"
+	cmdout "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/printers"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/resource"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd"
+)
+
+// InventoryAWSResourceCmd is the cmd to list all AWS resources tagged for a cluster.
+func InventoryAWSResourceCmd() *cobra.Command {
+	outputPrinterType := ""
+	clusterName := ""
+	includeIAM := false
+	newCmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "List all AWS resources tagged for a cluster",
+		Long: cmd.LongDesc(`
+			List all AWS resources in the given region that carry the Cluster API or the cloud provider tag of a cluster,
+			whether they are owned by the cluster or shared with it. IAM roles and instance profiles are included if
+			include-iam is set, which reads the tags of every role and instance profile in the account.
+			This helps finding the resources that hold up the deletion of a cluster, such as load balancers and security
+			groups created by the cloud provider.
+		`),
+		Example: cmd.Examples(`
+		# List AWS resources tagged for the cluster test-cluster in us-east-1
+		clusterawsadm resource inventory --region=us-east-1 --cluster-name=test-cluster
+
+		# List AWS resources tagged for the cluster test-cluster with their tags
+		clusterawsadm resource inventory --region=us-east-1 --cluster-name=test-cluster -o json
+
+		# List AWS resources tagged for the cluster test-cluster, including IAM roles and instance profiles
+		clusterawsadm resource inventory --region=us-east-1 --cluster-name=test-cluster --include-iam
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			region, err := flags.GetRegionWithError(cmd)
+			if err != nil {
+				return err
+			}
+
+			outputPrinter, err := cmdout.New(outputPrinterType, os.Stdout)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed creating output printer: %s\n", err.Error())
+				return err
+			}
+
+			resources, err := resource.ScanAWSResources(region, clusterName, includeIAM)
+			if err != nil {
+				return flags.ResolveAWSError(err)
+			}
+
+			resourceInventory := &resource.AWSResourceInventory{
+				ClusterName: clusterName,
+				Resources:   resources,
+			}
+			if outputPrinterType == string(cmdout.PrinterTypeTable) {
+				return outputPrinter.Print(resourceInventory.ToTable())
+			}
+			return outputPrinter.Print(resourceInventory)
+		},
+	}
+
+	flags.AddRegionFlag(newCmd)
+	newCmd.Flags().StringVarP(&clusterName, "cluster-name", "n", "", "The name of the cluster the AWS resources are tagged for")
+	newCmd.Flags().StringVarP(&outputPrinterType, "output", "o", "table", "The output format of the results. Possible values: table, json, yaml")
+	newCmd.Flags().BoolVar(&includeIAM, "include-iam", false, "List the IAM roles and instance profiles tagged for the cluster too")
+	newCmd.MarkFlagRequired("cluster-name") //nolint: errcheck
+	return newCmd
+}
@@ -32,17 +32,22 @@ func ListAWSResourceCmd() *cobra.Command {
 	outputPrinterType := ""
 	clusterName := ""
 	region := ""
+	includeIAM := false
 	newCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all AWS resources created by CAPA",
 		Long: cmd.LongDesc(`
 			List AWS resources directly created by CAPA based on region and cluster-name. There are some indirect resources like Cloudwatch alarms, rules, etc
 			which are not directly created by CAPA, so those resources are not listed here. 
+			IAM roles and instance profiles are only listed if include-iam is set, as their tags are read one by one.
 			If region and cluster-name are not set, then it will throw an error.
 		`),
 		Example: cmd.Examples(`
 		# List AWS resources directly created by CAPA in given region and clustername
 		clusterawsadm resource list --region=us-east-1 --cluster-name=test-cluster
+
+		# List AWS resources directly created by CAPA in given region and clustername, including IAM roles and instance profiles
+		clusterawsadm resource list --region=us-east-1 --cluster-name=test-cluster --include-iam
 		`),
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -53,7 +58,7 @@ func ListAWSResourceCmd() *cobra.Command {
 			}
 
 			fmt.Fprintf(os.Stdout, "Attempting to fetch resources created by CAPA for cluster:%s present in %s\n\n", clusterName, region)
-			resourceList, err := resource.ListAWSResource(&region, &clusterName)
+			resourceList, err := resource.ListAWSResource(&region, &clusterName, includeIAM)
 			if err != nil || len(resourceList.AWSResources) == 0 {
 				return err
 			}
@@ -78,6 +83,7 @@ func ListAWSResourceCmd() *cobra.Command {
 	newCmd.Flags().StringVarP(&region, "region", "r", "", "The AWS region where resources are created by CAPA")
 	newCmd.Flags().StringVarP(&clusterName, "cluster-name", "n", "", "The name of the cluster where AWS resources created by CAPA")
 	newCmd.Flags().StringVarP(&outputPrinterType, "output", "o", "table", "The output format of the results. Possible values: table, json, yaml")
+	newCmd.Flags().BoolVar(&includeIAM, "include-iam", false, "List the IAM roles and instance profiles created by CAPA too, which takes one IAM call per role and instance profile in the account")
 	newCmd.MarkFlagRequired("cluster-name") //nolint: errcheck
 	return newCmd
 }
@@ -18,7 +18,9 @@ package resource
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/cmd/resource/inventory"
 	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/cmd/resource/list"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/cmd/resource/retag"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd"
 )
 
@@ -31,6 +33,8 @@ func RootCmd() *cobra.Command {
 		Long: cmd.LongDesc(`
 			All AWS resources related actions such as:
 			# List of AWS resources created by CAPA
+			# Inventory of all AWS resources tagged for a cluster
+			# Retagging the AWS resources of a renamed cluster
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := cmd.Help(); err != nil {
@@ -41,6 +45,8 @@ func RootCmd() *cobra.Command {
 	}
 
 	newCmd.AddCommand(list.ListAWSResourceCmd())
+	newCmd.AddCommand(inventory.InventoryAWSResourceCmd())
+	newCmd.AddCommand(retag.RetagAWSResourceCmd())
 
 	return newCmd
 }
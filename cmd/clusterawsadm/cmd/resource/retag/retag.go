@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retag
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/cmd/flags"
+	cmdout "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/printers"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/resource"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd"
+)
+
+// RetagAWSResourceCmd is the cmd to move the cluster tags of AWS resources to a renamed cluster.
+func RetagAWSResourceCmd() *cobra.Command {
+	outputPrinterType := ""
+	clusterName := ""
+	newClusterName := ""
+	newCmd := &cobra.Command{
+		Use:   "retag",
+		Short: "Move the cluster tags of AWS resources to a renamed cluster",
+		Long: cmd.LongDesc(`
+			Move the Cluster API and the cloud provider tags of all AWS resources in the given region that are tagged
+			for a cluster to a new cluster name, keeping whether the resources are owned by the cluster or shared with it.
+			The tag keys contain the cluster name, so the resources of a cluster that is recreated under a new name, for
+			example when it is moved to another management cluster with clusterctl move, must be retagged for the
+			controllers and the cloud provider to find them. IAM roles and instance profiles are retagged as well.
+			Pause the cluster before retagging its resources.
+		`),
+		Example: cmd.Examples(`
+		# Move the tags of the AWS resources of the cluster test-cluster in us-east-1 to the cluster new-cluster
+		clusterawsadm resource retag --region=us-east-1 --cluster-name=test-cluster --new-cluster-name=new-cluster
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			region, err := flags.GetRegionWithError(cmd)
+			if err != nil {
+				return err
+			}
+
+			outputPrinter, err := cmdout.New(outputPrinterType, os.Stdout)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed creating output printer: %s\n", err.Error())
+				return err
+			}
+
+			resources, err := resource.RetagAWSResources(region, clusterName, newClusterName)
+			if err != nil {
+				return flags.ResolveAWSError(err)
+			}
+
+			resourceInventory := &resource.AWSResourceInventory{
+				ClusterName: newClusterName,
+				Resources:   resources,
+			}
+			if outputPrinterType == string(cmdout.PrinterTypeTable) {
+				return outputPrinter.Print(resourceInventory.ToTable())
+			}
+			return outputPrinter.Print(resourceInventory)
+		},
+	}
+
+	flags.AddRegionFlag(newCmd)
+	newCmd.Flags().StringVarP(&clusterName, "cluster-name", "n", "", "The name of the cluster the AWS resources are tagged for")
+	newCmd.Flags().StringVar(&newClusterName, "new-cluster-name", "", "The name of the cluster the AWS resources are retagged for")
+	newCmd.Flags().StringVarP(&outputPrinterType, "output", "o", "table", "The output format of the retagged resources. Possible values: table, json, yaml")
+	newCmd.MarkFlagRequired("cluster-name")     //nolint: errcheck
+	newCmd.MarkFlagRequired("new-cluster-name") //nolint: errcheck
+	return newCmd
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -125,5 +126,18 @@ func DecodeBootstrapConfiguration(bootstrapCodecs *serializer.CodecFactory, data
 
 	bootstrapv1.SetDefaults_AWSIAMConfiguration(obj)
 
+	if err := validateBootstrapConfiguration(obj); err != nil {
+		return nil, err
+	}
+
 	return obj, nil
 }
+
+// validateBootstrapConfiguration rejects configurations which AWS CloudFormation would only reject
+// once the stack is half created.
+func validateBootstrapConfiguration(obj *bootstrapv1.AWSIAMConfiguration) error {
+	if path := obj.Spec.Path; path != "" && (!strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/")) {
+		return errors.Errorf("invalid IAM path %q: it must begin and end with \"/\"", path)
+	}
+	return nil
+}
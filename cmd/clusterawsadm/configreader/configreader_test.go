@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	bootstrapschemev1 "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/api/bootstrap/v1alpha1/scheme"
+)
+
+func TestDecodeBootstrapConfigurationPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{
+			name: "root path",
+			path: "/",
+		},
+		{
+			name: "nested path",
+			path: "/capa/team-a/",
+		},
+		{
+			name:    "path without leading slash",
+			path:    "capa/",
+			wantErr: true,
+		},
+		{
+			name:    "path without trailing slash",
+			path:    "/capa",
+			wantErr: true,
+		},
+	}
+
+	_, codecs, err := bootstrapschemev1.NewSchemeAndCodecs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			data := []byte(`apiVersion: bootstrap.aws.infrastructure.cluster.x-k8s.io/v1alpha1
+kind: AWSIAMConfiguration
+spec:
+  path: ` + tt.path + "\n")
+
+			_, err := DecodeBootstrapConfiguration(codecs, data)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
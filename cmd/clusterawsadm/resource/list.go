@@ -19,63 +19,66 @@ package resource
 import (
 	"fmt"
 
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
-	"github.com/aws/aws-sdk-go/aws/session"
-	tagapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
-	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
-)
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 
-// ListAWSResource fetches all AWS resources created by CAPA.
-func ListAWSResource(region, clusterName *string) (AWSResourceList, error) {
-	var resourceList AWSResourceList
-	cfg := aws.Config{}
-	if *region != "" {
-		cfg.Region = region
-	}
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/inventory"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
 
-	sess, err := session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            cfg,
+// ScanAWSResources fetches all AWS resources tagged for the cluster in the given region, including
+// resources shared with the cluster and resources created by the cloud provider. IAM roles and
+// instance profiles are only fetched if includeIAM is set.
+func ScanAWSResources(region, clusterName string, includeIAM bool) ([]inventory.Resource, error) {
+	globalScope, err := scope.NewGlobalScope(scope.GlobalScopeParams{
+		ControllerName: "clusterawsadm",
+		Region:         region,
 	})
 	if err != nil {
-		return resourceList, err
+		return nil, err
 	}
 
-	resourceClient := tagapi.New(sess)
-	input := &tagapi.GetResourcesInput{
-		TagFilters: []*tagapi.TagFilter{},
-	}
+	scanner := inventory.NewScanner(globalScope, globalScope, logf.Log)
+	scanner.IncludeIAM = includeIAM
+	return scanner.Scan(clusterName)
+}
 
-	awsResourceTags := infrav1.Build(infrav1.BuildParams{
-		ClusterName: *clusterName,
-		Lifecycle:   infrav1.ResourceLifecycleOwned,
+// RetagAWSResources moves the cluster tags of all AWS resources tagged for the cluster oldName in the given
+// region, and of the IAM roles and instance profiles tagged for it, to the cluster newName, and returns the
+// retagged resources.
+func RetagAWSResources(region, oldName, newName string) ([]inventory.Resource, error) {
+	globalScope, err := scope.NewGlobalScope(scope.GlobalScopeParams{
+		ControllerName: "clusterawsadm",
+		Region:         region,
 	})
-
-	for tagKey, tagValue := range awsResourceTags {
-		tagFilter := &tagapi.TagFilter{}
-		tagFilter.SetKey(tagKey)
-		tagFilter.SetValues([]*string{aws.String(tagValue)})
-		input.TagFilters = append(input.TagFilters, tagFilter)
+	if err != nil {
+		return nil, err
 	}
 
-	output, err := resourceClient.GetResources(input)
+	scanner := inventory.NewScanner(globalScope, globalScope, logf.Log)
+	scanner.IncludeIAM = true
+	return scanner.Retag(oldName, newName)
+}
+
+// ListAWSResource fetches all AWS resources created by CAPA.
+func ListAWSResource(region, clusterName *string, includeIAM bool) (AWSResourceList, error) {
+	var resourceList AWSResourceList
+
+	resources, err := ScanAWSResources(*region, *clusterName, includeIAM)
 	if err != nil {
 		return resourceList, err
 	}
 
-	if len(output.ResourceTagMappingList) == 0 {
-		fmt.Println("Could not find any AWS resource created by CAPA")
-		return resourceList, nil
-	}
-
 	resourceList = AWSResourceList{
 		ClusterName:  *clusterName,
 		AWSResources: []AWSResource{},
 	}
 
-	for _, eachResource := range output.ResourceTagMappingList {
-		resourceARN, err := arn.Parse(*eachResource.ResourceARN)
+	for _, eachResource := range resources {
+		if !eachResource.Tags.HasOwned(*clusterName) {
+			continue
+		}
+		resourceARN, err := arn.Parse(eachResource.ARN)
 		if err != nil {
 			return resourceList, err
 		}
@@ -85,10 +88,14 @@ func ListAWSResource(region, clusterName *string) (AWSResourceList, error) {
 			Region:    resourceARN.Region,
 			AccountID: resourceARN.AccountID,
 			Resource:  resourceARN.Resource,
-			ARN:       *eachResource.ResourceARN,
+			ARN:       eachResource.ARN,
 		}
 		resourceList.AWSResources = append(resourceList.AWSResources, eachAWSResource)
 	}
 
+	if len(resourceList.AWSResources) == 0 {
+		fmt.Println("Could not find any AWS resource created by CAPA")
+	}
+
 	return resourceList, nil
 }
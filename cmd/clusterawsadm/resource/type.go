@@ -16,7 +16,12 @@ limitations under the License.
 
 package resource
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/inventory"
+)
 
 // AWSResource defines an AWS resource.
 type AWSResource struct {
@@ -77,3 +82,57 @@ func (a *AWSResourceList) ToTable() *metav1.Table {
 	}
 	return table
 }
+
+// AWSResourceInventory defines the AWS resources tagged for a cluster.
+type AWSResourceInventory struct {
+	ClusterName string               `json:"clusterName"`
+	Resources   []inventory.Resource `json:"resources"`
+}
+
+// ToTable converts AWSResourceInventory to Table.
+func (a *AWSResourceInventory) ToTable() *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{
+				Name: "Service",
+				Type: "string",
+			},
+			{
+				Name: "Type",
+				Type: "string",
+			},
+			{
+				Name: "ID",
+				Type: "string",
+			},
+			{
+				Name: "Region",
+				Type: "string",
+			},
+			{
+				Name: "Lifecycle",
+				Type: "string",
+			},
+			{
+				Name: "Tagged By",
+				Type: "string",
+			},
+		},
+	}
+
+	for _, resource := range a.Resources {
+		taggedBy := "cloud-provider"
+		if _, ok := resource.Tags[infrav1.ClusterTagKey(a.ClusterName)]; ok {
+			taggedBy = "cluster-api"
+		}
+		row := metav1.TableRow{
+			Cells: []interface{}{resource.Service, resource.Type, resource.ID, resource.Region, resource.Lifecycle, taggedBy},
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,13 +30,24 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ecr"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	iamsvc "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/karpenter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/keypair"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/network"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/pricing"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/quotas"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/s3"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/securitygroup"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
+	capawsrecord "sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -53,19 +65,53 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// preflightCheckRequeueAfter is how long a cluster which failed its preflight checks waits before they are run again.
+const preflightCheckRequeueAfter = 5 * time.Minute
+
+// regionNotEnabledRequeueAfter is how long a cluster whose region is not enabled for its AWS account waits before
+// the region is checked again.
+const regionNotEnabledRequeueAfter = 5 * time.Minute
+
+// loadBalancerInstancesRequeueAfter is how long a cluster whose control plane instances aren't all in service on the
+// load balancers of the API server waits before their health is described again.
+const loadBalancerInstancesRequeueAfter = time.Minute
+
+// bastionDeletionRequeueAfter is how long a cluster whose bastion auto scaling group is being deleted waits before
+// the group is described again.
+const bastionDeletionRequeueAfter = 30 * time.Second
+
+// networkDependenciesRequeueAfter is how long a deleted cluster whose network is still used by other resources waits
+// before they are looked up again.
+const networkDependenciesRequeueAfter = 30 * time.Second
+
+// maxListedNetworkDependencies is the number of resources using the network of a deleted cluster which are listed in
+// its NetworkDependenciesDeleted condition.
+const maxListedNetworkDependencies = 10
+
+// lookupIP resolves the DNS name of the control plane load balancer when its DNS check is Resolve.
+var lookupIP = net.LookupIP
+
 // AWSClusterReconciler reconciles a AwsCluster object.
 type AWSClusterReconciler struct {
 	client.Client
 	Recorder         record.EventRecorder
 	Endpoints        []scope.ServiceEndpoint
 	WatchFilterValue string
+
+	// InstanceStateQueues are the queues EC2 instance state changes of the clusters are delivered to when the
+	// EventBridgeInstanceState feature is enabled.
+	InstanceStateQueues *instancestate.SharedQueues
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinetemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusterroleidentities;awsclusterstaticidentities,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclustercontrolleridentities,verbs=get;list;watch;create;
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
 
 func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -91,11 +137,6 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
-	if annotations.IsPaused(cluster, awsCluster) {
-		log.Info("AWSCluster or linked Cluster is marked as paused. Won't reconcile")
-		return reconcile.Result{}, nil
-	}
-
 	log = log.WithValues("cluster", cluster.Name)
 	helper, err := patch.NewHelper(awsCluster, r.Client)
 	if err != nil {
@@ -109,12 +150,20 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 				infrav1.PrincipalCredentialRetrievedCondition,
 				infrav1.PrincipalUsageAllowedCondition,
+				infrav1.PausedCondition,
 			}})
 		if e != nil {
 			fmt.Println(e.Error())
 		}
 	}()
 
+	if annotations.IsPaused(cluster, awsCluster) {
+		log.Info("AWSCluster or linked Cluster is marked as paused. Won't reconcile")
+		reconcilePaused(awsCluster)
+		return reconcile.Result{}, nil
+	}
+	reconcileUnpaused(awsCluster)
+
 	// Create the scope.
 	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
 		Client:         r.Client,
@@ -135,50 +184,159 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
+	if annotations.IsExternallyManaged(awsCluster) {
+		if !awsCluster.DeletionTimestamp.IsZero() {
+			// The infrastructure belongs to the external system, so there is nothing to delete.
+			controllerutil.RemoveFinalizer(awsCluster, infrav1.ClusterFinalizer)
+			return reconcile.Result{}, nil
+		}
+		return reconcileExternallyManaged(clusterScope)
+	}
+
+	account := throttle.DefaultBackpressure.Account(scope.ThrottleKey(clusterScope))
+	if delay := account.Delay(); delay > 0 {
+		clusterScope.V(2).Info("Delaying reconciliation while AWS throttles the requests of the account", "delay", delay)
+		return reconcile.Result{RequeueAfter: delay}, nil
+	}
+
 	// Handle deleted clusters
 	if !awsCluster.DeletionTimestamp.IsZero() {
-		return reconcileDelete(clusterScope)
+		return account.Requeue(reconcileDelete(ctx, r.Client, clusterScope, r.InstanceStateQueues))
 	}
 
 	// Handle non-deleted clusters
-	return reconcileNormal(clusterScope)
+	return account.Requeue(reconcileNormal(clusterScope, r.InstanceStateQueues))
+}
+
+// reconcilePaused marks the AWSCluster as paused and drops its AWS sessions, so that the credentials of
+// its identity are not refreshed while nothing reconciles it.
+func reconcilePaused(awsCluster *infrav1.AWSCluster) {
+	scope.EvictClusterSessions(client.ObjectKeyFromObject(awsCluster))
+
+	if !conditions.IsTrue(awsCluster, infrav1.PausedCondition) {
+		conditions.MarkTrue(awsCluster, infrav1.PausedCondition)
+		capawsrecord.Eventf(awsCluster, "Paused", "Stopped reconciling AWSCluster while it or its Cluster is paused")
+	}
+}
+
+// reconcileUnpaused prepares an AWSCluster that was paused to be reconciled again. Its identity or the AWS
+// resources of the cluster may have been changed while it was paused, so a new session is created and the
+// following reconciliation compares all the resources of the cluster with its spec.
+func reconcileUnpaused(awsCluster *infrav1.AWSCluster) {
+	if !conditions.IsTrue(awsCluster, infrav1.PausedCondition) {
+		return
+	}
+
+	scope.EvictClusterSessions(client.ObjectKeyFromObject(awsCluster))
+	conditions.MarkFalse(awsCluster, infrav1.PausedCondition, infrav1.UnpausedReason, clusterv1.ConditionSeverityInfo, "")
+	capawsrecord.Eventf(awsCluster, "Unpaused", "Resyncing the AWS resources of the AWSCluster after it was unpaused")
 }
 
 // TODO(ncdc): should this be a function on ClusterScope?
-func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+func reconcileDelete(ctx context.Context, c client.Client, clusterScope *scope.ClusterScope, instanceStateQueues *instancestate.SharedQueues) (reconcile.Result, error) {
 	clusterScope.Info("Reconciling AWSCluster delete")
 
 	ec2svc := ec2.NewService(clusterScope)
 	elbsvc := elb.NewService(clusterScope)
 	networkSvc := network.NewService(clusterScope)
 	sgService := securitygroup.NewService(clusterScope)
+	s3Service := s3.NewService(clusterScope)
 
 	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
-		instancestateSvc := instancestate.NewService(clusterScope)
+		instancestateSvc := instancestate.NewService(clusterScope, instanceStateQueues)
 		if err := instancestateSvc.DeleteEC2Events(); err != nil {
 			// Not deleting the events isn't critical to cluster deletion
 			clusterScope.Error(err, "non-fatal: failed to delete EventBridge notifications")
 		}
 	}
 
-	if err := elbsvc.DeleteLoadbalancers(); err != nil {
+	deletionPolicy := clusterScope.DeletionPolicy()
+
+	if deletionPolicy.LoadBalancers == infrav1.ResourceDeletionPolicyRetain {
+		clusterScope.Info("Retaining load balancers, as required by the deletion policy")
+	} else if err := elbsvc.DeleteLoadbalancers(); err != nil {
 		clusterScope.Error(err, "error deleting load balancer")
 		return reconcile.Result{}, err
 	}
 
-	if err := ec2svc.DeleteBastion(); err != nil {
-		clusterScope.Error(err, "error deleting bastion")
-		return reconcile.Result{}, err
+	if deletionPolicy.Instances == infrav1.ResourceDeletionPolicyRetain {
+		clusterScope.Info("Retaining bastion and SSH key pair, as required by the deletion policy")
+	} else {
+		requeue, err := ec2svc.DeleteBastion()
+		if err != nil {
+			clusterScope.Error(err, "error deleting bastion")
+			return reconcile.Result{}, err
+		}
+		if requeue {
+			clusterScope.Info("Waiting for the bastion auto scaling group to be deleted")
+			return reconcile.Result{RequeueAfter: bastionDeletionRequeueAfter}, nil
+		}
+
+		if err := keypair.NewService(clusterScope).DeleteKeyPair(); err != nil {
+			clusterScope.Error(err, "error deleting SSH key pair")
+			return reconcile.Result{}, err
+		}
 	}
 
-	if err := sgService.DeleteSecurityGroups(); err != nil {
-		clusterScope.Error(err, "error deleting security groups")
+	if deletionPolicy.S3Bucket == infrav1.ResourceDeletionPolicyRetain {
+		clusterScope.Info("Retaining S3 bucket, as required by the deletion policy")
+	} else if err := s3Service.DeleteBucket(); err != nil {
+		clusterScope.Error(err, "error deleting S3 Bucket")
 		return reconcile.Result{}, err
 	}
 
-	if err := networkSvc.DeleteNetwork(); err != nil {
-		clusterScope.Error(err, "error deleting network")
-		return reconcile.Result{}, err
+	if deletionPolicy.Network == infrav1.ResourceDeletionPolicyRetain {
+		clusterScope.Info("Retaining security groups and network, as required by the deletion policy")
+	} else {
+		if feature.Gates.Enabled(feature.CNINetworkInterfaceCleanup) {
+			if err := networkSvc.DeleteCNINetworkInterfaces(); err != nil {
+				clusterScope.Error(err, "error deleting CNI network interfaces")
+				return reconcile.Result{}, err
+			}
+		}
+
+		// The security groups and subnets can't be deleted while other resources use them, so their deletion
+		// waits for those resources to be gone rather than failing with dependency violations.
+		dependencies, err := networkDependencies(ctx, c, clusterScope, networkSvc)
+		if err != nil {
+			clusterScope.Error(err, "error looking up the resources using the network")
+			return reconcile.Result{}, err
+		}
+		if len(dependencies) > 0 {
+			if len(dependencies) > maxListedNetworkDependencies {
+				dependencies = append(dependencies[:maxListedNetworkDependencies], fmt.Sprintf("%d more", len(dependencies)-maxListedNetworkDependencies))
+			}
+			message := strings.Join(dependencies, ", ")
+			clusterScope.Info("Waiting for the resources using the network to be deleted", "resources", message)
+			conditions.MarkFalse(clusterScope.AWSCluster, infrav1.NetworkDependenciesDeletedCondition, infrav1.WaitingForNetworkDependenciesReason, clusterv1.ConditionSeverityInfo,
+				"Waiting for the resources using the network to be deleted: %s", message)
+			return reconcile.Result{RequeueAfter: networkDependenciesRequeueAfter}, nil
+		}
+		conditions.MarkTrue(clusterScope.AWSCluster, infrav1.NetworkDependenciesDeletedCondition)
+
+		if err := sgService.DeleteSecurityGroups(); err != nil {
+			clusterScope.Error(err, "error deleting security groups")
+			return reconcile.Result{}, err
+		}
+
+		if err := networkSvc.DeleteNetwork(); err != nil {
+			clusterScope.Error(err, "error deleting network")
+			return reconcile.Result{}, err
+		}
+	}
+
+	if feature.Gates.Enabled(feature.Karpenter) {
+		if err := karpenter.NewService(clusterScope).DeleteKarpenter(); err != nil {
+			clusterScope.Error(err, "error deleting Karpenter resources")
+			return reconcile.Result{}, err
+		}
+	}
+
+	if feature.Gates.Enabled(feature.MachineIAMInstanceProfile) {
+		if err := iamsvc.NewService(clusterScope).DeleteInstanceProfiles(); err != nil {
+			clusterScope.Error(err, "error deleting IAM instance profiles")
+			return reconcile.Result{}, err
+		}
 	}
 
 	// Cluster is deleted so remove the finalizer.
@@ -187,8 +345,47 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 	return reconcile.Result{}, nil
 }
 
+// networkDependencies describes the resources which still use the network of a deleted cluster: its AWSMachines, its
+// AWSMachinePools and the network interfaces in its managed VPC.
+func networkDependencies(ctx context.Context, c client.Client, clusterScope *scope.ClusterScope, networkSvc *network.Service) ([]string, error) {
+	listOptions := []client.ListOption{
+		client.InNamespace(clusterScope.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterLabelName: clusterScope.Name()},
+	}
+
+	var dependencies []string
+
+	machines := &infrav1.AWSMachineList{}
+	if err := c.List(ctx, machines, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachines")
+	}
+	for _, machine := range machines.Items {
+		dependencies = append(dependencies, fmt.Sprintf("AWSMachine %s", machine.Name))
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		machinePools := &expinfrav1.AWSMachinePoolList{}
+		if err := c.List(ctx, machinePools, listOptions...); err != nil {
+			return nil, errors.Wrap(err, "failed to list AWSMachinePools")
+		}
+		for _, machinePool := range machinePools.Items {
+			dependencies = append(dependencies, fmt.Sprintf("AWSMachinePool %s", machinePool.Name))
+		}
+	}
+
+	interfaces, err := networkSvc.DependentNetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, ni := range interfaces {
+		dependencies = append(dependencies, fmt.Sprintf("network interface %s", ni))
+	}
+
+	return dependencies, nil
+}
+
 // TODO(ncdc): should this be a function on ClusterScope?
-func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+func reconcileNormal(clusterScope *scope.ClusterScope, instanceStateQueues *instancestate.SharedQueues) (reconcile.Result, error) {
 	clusterScope.Info("Reconciling AWSCluster")
 
 	awsCluster := clusterScope.AWSCluster
@@ -204,6 +401,29 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 	elbService := elb.NewService(clusterScope)
 	networkSvc := network.NewService(clusterScope)
 	sgService := securitygroup.NewService(clusterScope)
+	s3Service := s3.NewService(clusterScope)
+
+	enabled, err := ec2Service.ReconcileRegion()
+	if err != nil {
+		clusterScope.Error(err, "failed to check the region of the cluster")
+		return reconcile.Result{}, err
+	}
+	if !enabled {
+		return reconcile.Result{RequeueAfter: regionNotEnabledRequeueAfter}, nil
+	}
+
+	// Only check the service quotas before the network is created, as the resources of a cluster which
+	// is already provisioned count towards the quotas themselves.
+	if feature.Gates.Enabled(feature.PreflightQuotaChecks) && !conditions.Has(awsCluster, infrav1.VpcReadyCondition) {
+		passed, err := reconcilePreflightChecks(clusterScope, networkSvc, sgService)
+		if err != nil {
+			clusterScope.Error(err, "failed to run preflight checks")
+			return reconcile.Result{}, err
+		}
+		if !passed {
+			return reconcile.Result{RequeueAfter: preflightCheckRequeueAfter}, nil
+		}
+	}
 
 	if err := networkSvc.ReconcileNetwork(); err != nil {
 		clusterScope.Error(err, "failed to reconcile network")
@@ -217,40 +437,85 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 
 	if err := sgService.ReconcileSecurityGroups(); err != nil {
 		clusterScope.Error(err, "failed to reconcile security groups")
-		conditions.MarkFalse(awsCluster, infrav1.ClusterSecurityGroupsReadyCondition, infrav1.ClusterSecurityGroupReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(awsCluster, infrav1.ClusterSecurityGroupsReadyCondition, awserrors.ConditionReason(err, infrav1.ClusterSecurityGroupReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if err := keypair.NewService(clusterScope).ReconcileKeyPair(); err != nil {
+		conditions.MarkFalse(awsCluster, infrav1.SSHKeyPairReadyCondition, awserrors.ConditionReason(err, infrav1.SSHKeyPairFailedReason), clusterv1.ConditionSeverityError, err.Error())
+		clusterScope.Error(err, "failed to reconcile SSH key pair")
 		return reconcile.Result{}, err
 	}
 
-	if err := ec2Service.ReconcileBastion(); err != nil {
-		conditions.MarkFalse(awsCluster, infrav1.BastionHostReadyCondition, infrav1.BastionHostFailedReason, clusterv1.ConditionSeverityError, err.Error())
+	bastionRequeue, err := ec2Service.ReconcileBastion()
+	if err != nil {
+		conditions.MarkFalse(awsCluster, infrav1.BastionHostReadyCondition, awserrors.ConditionReason(err, infrav1.BastionHostFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		clusterScope.Error(err, "failed to reconcile bastion host")
 		return reconcile.Result{}, err
 	}
 
 	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
-		instancestateSvc := instancestate.NewService(clusterScope)
+		instancestateSvc := instancestate.NewService(clusterScope, instanceStateQueues)
 		if err := instancestateSvc.ReconcileEC2Events(); err != nil {
 			// non fatal error, so we continue
 			clusterScope.Error(err, "non-fatal: failed to set up EventBridge")
 		}
 	}
 
+	if err := s3Service.ReconcileBucket(); err != nil {
+		conditions.MarkFalse(awsCluster, infrav1.S3BucketReadyCondition, awserrors.ConditionReason(err, infrav1.S3BucketFailedReason), clusterv1.ConditionSeverityError, err.Error())
+		clusterScope.Error(err, "failed to reconcile S3 Bucket")
+		return reconcile.Result{}, err
+	}
+
+	if clusterScope.Bucket() != nil {
+		conditions.MarkTrue(awsCluster, infrav1.S3BucketReadyCondition)
+	}
+
+	if err := ecr.NewService(clusterScope).ReconcilePullThroughCacheRules(); err != nil {
+		clusterScope.Error(err, "failed to reconcile ECR pull-through cache rules")
+		return reconcile.Result{}, err
+	}
+
+	if feature.Gates.Enabled(feature.Karpenter) {
+		if err := karpenter.NewService(clusterScope).ReconcileKarpenter(); err != nil {
+			clusterScope.Error(err, "failed to reconcile Karpenter resources")
+			return reconcile.Result{}, err
+		}
+	}
+
 	if err := elbService.ReconcileLoadbalancers(); err != nil {
 		clusterScope.Error(err, "failed to reconcile load balancer")
-		conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, awserrors.ConditionReason(err, infrav1.LoadBalancerFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, err
 	}
 
 	if awsCluster.Status.Network.APIServerELB.DNSName == "" {
 		conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, infrav1.WaitForDNSNameReason, clusterv1.ConditionSeverityInfo, "")
 		clusterScope.Info("Waiting on API server ELB DNS name")
-		return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
+		return reconcile.Result{RequeueAfter: clusterScope.LoadBalancerDNSRequeueInterval()}, nil
 	}
 
-	if _, err := net.LookupIP(awsCluster.Status.Network.APIServerELB.DNSName); err != nil {
-		conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, infrav1.WaitForDNSNameResolveReason, clusterv1.ConditionSeverityInfo, "")
-		clusterScope.Info("Waiting on API server ELB DNS name to resolve")
-		return reconcile.Result{RequeueAfter: 15 * time.Second}, nil // nolint:nilerr
+	switch clusterScope.ControlPlaneLoadBalancerDNSCheck() {
+	case infrav1.DNSCheckNone:
+	case infrav1.DNSCheckAWS:
+		ready, err := elbService.APIServerELBDNSNameReady()
+		if err != nil {
+			clusterScope.Error(err, "failed to check API server ELB DNS name")
+			conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, awserrors.ConditionReason(err, infrav1.LoadBalancerFailedReason), clusterv1.ConditionSeverityError, err.Error())
+			return reconcile.Result{}, err
+		}
+		if !ready {
+			conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, infrav1.WaitForDNSNameAWSReason, clusterv1.ConditionSeverityInfo, "")
+			clusterScope.Info("Waiting on the ELB API to return the API server ELB DNS name")
+			return reconcile.Result{RequeueAfter: clusterScope.LoadBalancerDNSRequeueInterval()}, nil
+		}
+	default:
+		if _, err := lookupIP(awsCluster.Status.Network.APIServerELB.DNSName); err != nil {
+			conditions.MarkFalse(awsCluster, infrav1.LoadBalancerReadyCondition, infrav1.WaitForDNSNameResolveReason, clusterv1.ConditionSeverityInfo, "")
+			clusterScope.Info("Waiting on API server ELB DNS name to resolve")
+			return reconcile.Result{RequeueAfter: clusterScope.LoadBalancerDNSRequeueInterval()}, nil // nolint:nilerr
+		}
 	}
 	conditions.MarkTrue(awsCluster, infrav1.LoadBalancerReadyCondition)
 
@@ -259,22 +524,176 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		Port: clusterScope.APIServerPort(),
 	}
 
+	if err := setFailureDomains(clusterScope, ec2Service); err != nil {
+		clusterScope.Error(err, "failed to set failure domains")
+		return reconcile.Result{}, err
+	}
+
+	awsCluster.Status.Ready = true
+
+	if feature.Gates.Enabled(feature.CostEstimation) {
+		reconcileCostEstimate(clusterScope)
+	}
+
+	if bastionRequeue {
+		clusterScope.Info("Waiting for the bastion auto scaling group to be deleted")
+		return reconcile.Result{RequeueAfter: bastionDeletionRequeueAfter}, nil
+	}
+
+	// The AWSMachine controller registers the control plane instances with the load balancers,
+	// so their health is polled until they're all in service.
+	if conditions.IsFalse(awsCluster, infrav1.LoadBalancerInstancesHealthyCondition) {
+		return reconcile.Result{RequeueAfter: loadBalancerInstancesRequeueAfter}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileCostEstimate annotates the AWSCluster with the estimated hourly cost of its AWS resources. The estimate is
+// only informational, so failing to compute it does not fail the reconciliation.
+func reconcileCostEstimate(clusterScope *scope.ClusterScope) {
+	estimate, err := pricing.NewService(clusterScope).EstimateClusterCost(&clusterScope.AWSCluster.Status)
+	if err != nil {
+		clusterScope.Info("Failed to estimate the cost of the cluster", "error", err.Error())
+		return
+	}
+
+	if clusterScope.AWSCluster.Annotations == nil {
+		clusterScope.AWSCluster.Annotations = map[string]string{}
+	}
+	clusterScope.AWSCluster.Annotations[infrav1.EstimatedHourlyCostAnnotation] = estimate.String()
+}
+
+// reconcileExternallyManaged discovers the infrastructure of an AWSCluster provisioned by another
+// system and backfills its spec and status, so that machines can be created without filling them
+// in by hand. No AWS resources are created, modified or deleted, and marking the AWSCluster as
+// ready is left to the external system.
+// reconcilePreflightChecks compares the resources that creating the cluster takes with the service quotas of
+// the account, so that a cluster which would exceed a quota is stopped before any of its resources are created
+// instead of failing midway through the reconciliation of its network.
+func reconcilePreflightChecks(clusterScope *scope.ClusterScope, networkSvc *network.Service, sgService *securitygroup.Service) (bool, error) {
+	awsCluster := clusterScope.AWSCluster
+
+	requirements, err := preflightRequirements(clusterScope, networkSvc, sgService)
+	if err != nil {
+		conditions.MarkFalse(awsCluster, infrav1.PreflightChecksPassedCondition, awserrors.ConditionReason(err, infrav1.PreflightCheckFailedReason), clusterv1.ConditionSeverityWarning, err.Error())
+		return false, err
+	}
+
+	shortfalls, err := quotas.NewService(clusterScope).CheckRequirements(requirements)
+	if err != nil {
+		conditions.MarkFalse(awsCluster, infrav1.PreflightChecksPassedCondition, awserrors.ConditionReason(err, infrav1.PreflightCheckFailedReason), clusterv1.ConditionSeverityWarning, err.Error())
+		return false, err
+	}
+
+	if len(shortfalls) > 0 {
+		message := strings.Join(shortfalls, "; ")
+		conditions.MarkFalse(awsCluster, infrav1.PreflightChecksPassedCondition, infrav1.PreflightCheckFailedReason, clusterv1.ConditionSeverityError, "Service quotas would be exceeded: %s", message)
+		capawsrecord.Warnf(awsCluster, "PreflightCheckFailed", "Service quotas would be exceeded: %s", message)
+		return false, nil
+	}
+
+	conditions.MarkTrue(awsCluster, infrav1.PreflightChecksPassedCondition)
+	return true, nil
+}
+
+func preflightRequirements(clusterScope *scope.ClusterScope, networkSvc *network.Service, sgService *securitygroup.Service) (quotas.Requirements, error) {
+	requirements := quotas.Requirements{}
+
+	if clusterScope.VPC().ID == "" {
+		requirements.VPCs = 1
+		requirements.InternetGateways = 1
+	}
+
+	elasticIPs, err := networkSvc.PlannedElasticIPs()
+	if err != nil {
+		return requirements, err
+	}
+	requirements.ElasticIPs = elasticIPs
+
+	requirements.SecurityGroupRules, err = sgService.RequiredIngressRules()
+	if err != nil {
+		return requirements, err
+	}
+
+	requirements.InstanceTypes, err = clusterScope.PlannedInstanceTypes(context.TODO())
+	if err != nil {
+		return requirements, err
+	}
+
+	return requirements, nil
+}
+
+func reconcileExternallyManaged(clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	clusterScope.Info("Discovering externally managed AWSCluster")
+
+	awsCluster := clusterScope.AWSCluster
+
+	if err := network.NewService(clusterScope).DiscoverNetwork(); err != nil {
+		clusterScope.Error(err, "failed to discover network")
+		return reconcile.Result{}, err
+	}
+
+	if err := securitygroup.NewService(clusterScope).DiscoverSecurityGroups(); err != nil {
+		clusterScope.Error(err, "failed to discover security groups")
+		return reconcile.Result{}, err
+	}
+
+	if err := elb.NewService(clusterScope).DiscoverLoadbalancers(); err != nil {
+		clusterScope.Error(err, "failed to discover load balancer")
+		return reconcile.Result{}, err
+	}
+
+	if awsCluster.Spec.ControlPlaneEndpoint.IsZero() && awsCluster.Status.Network.APIServerELB.DNSName != "" {
+		awsCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
+			Host: awsCluster.Status.Network.APIServerELB.DNSName,
+			Port: clusterScope.APIServerPort(),
+		}
+	}
+
+	if err := setFailureDomains(clusterScope, ec2.NewService(clusterScope)); err != nil {
+		clusterScope.Error(err, "failed to set failure domains")
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// setFailureDomains sets a failure domain for the availability zone of each private subnet.
+// Control plane machines may use the zones the API server load balancer is attached to,
+// or any zone if no load balancer is known. When the control plane instance type is set,
+// they may not use the zones where it is not offered.
+func setFailureDomains(clusterScope *scope.ClusterScope, ec2Service *ec2.Service) error {
+	elbZones := clusterScope.AWSCluster.Status.Network.APIServerELB.AvailabilityZones
+
+	var offeredZones map[string]bool
+	if instanceType := clusterScope.AWSCluster.Spec.ControlPlaneInstanceType; instanceType != "" {
+		zones, err := ec2Service.GetInstanceTypeZones(instanceType)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the availability zones of control plane instance type %q", instanceType)
+		}
+		offeredZones = zones
+	}
+
 	for _, subnet := range clusterScope.Subnets().FilterPrivate() {
-		found := false
-		for _, az := range awsCluster.Status.Network.APIServerELB.AvailabilityZones {
+		found := len(elbZones) == 0
+		for _, az := range elbZones {
 			if az == subnet.AvailabilityZone {
 				found = true
 				break
 			}
 		}
+		if offeredZones != nil && !offeredZones[subnet.AvailabilityZone] {
+			clusterScope.V(2).Info("Control plane instance type is not offered in availability zone",
+				"instance-type", clusterScope.AWSCluster.Spec.ControlPlaneInstanceType, "availability-zone", subnet.AvailabilityZone)
+			found = false
+		}
 
 		clusterScope.SetFailureDomain(subnet.AvailabilityZone, clusterv1.FailureDomainSpec{
 			ControlPlane: found,
 		})
 	}
 
-	awsCluster.Status.Ready = true
-	return reconcile.Result{}, nil
+	return nil
 }
 
 func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -282,7 +701,8 @@ func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 	controller, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		For(&infrav1.AWSCluster{}).
-		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		WithEventFilter(predicates.ResourceHasFilterLabel(log, r.WatchFilterValue)).
+		WithEventFilter(resourceNotPausedOrPausing(log)).
 		WithEventFilter(
 			predicate.Funcs{
 				// Avoid reconciling if the event triggering the reconciliation is related to incremental status updates
@@ -305,13 +725,12 @@ func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 				},
 			},
 		).
-		WithEventFilter(predicates.ResourceIsNotExternallyManaged(log)).
 		Build(r)
 	if err != nil {
 		return errors.Wrap(err, "error creating controller")
 	}
 
-	return controller.Watch(
+	if err := controller.Watch(
 		&source.Kind{Type: &clusterv1.Cluster{}},
 		handler.EnqueueRequestsFromMapFunc(r.requeueAWSClusterForUnpausedCluster(ctx, log)),
 		predicate.Funcs{
@@ -324,6 +743,10 @@ func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 				case oldCluster.Spec.Paused && !newCluster.Spec.Paused:
 					log.V(4).Info("Cluster was unpaused, will attempt to map associated AWSCluster.")
 					return true
+				// return true if Cluster.Spec.Paused has changed from false to true, to mark the AWSCluster as paused
+				case !oldCluster.Spec.Paused && newCluster.Spec.Paused:
+					log.V(4).Info("Cluster was paused, will attempt to map associated AWSCluster.")
+					return true
 				// otherwise, return false
 				default:
 					log.V(4).Info("Cluster did not match expected conditions, will not attempt to map associated AWSCluster.")
@@ -353,7 +776,42 @@ func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 				return false
 			},
 		},
-	)
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for ready clusters")
+	}
+
+	// The credentials of the cached sessions are retrieved again when the identity they come from changes.
+	for _, identity := range []client.Object{&infrav1.AWSClusterRoleIdentity{}, &infrav1.AWSClusterStaticIdentity{}} {
+		if err := controller.Watch(
+			&source.Kind{Type: identity},
+			handler.EnqueueRequestsFromMapFunc(r.evictIdentitySessions(log)),
+			predicate.GenerationChangedPredicate{},
+		); err != nil {
+			return errors.Wrap(err, "failed adding a watch for cluster identities")
+		}
+	}
+	return nil
+}
+
+// evictIdentitySessions evicts the cached sessions using a changed identity, and requeues the AWSClusters they
+// were created for.
+func (r *AWSClusterReconciler) evictIdentitySessions(log logr.Logger) handler.MapFunc {
+	return func(o client.Object) []ctrl.Request {
+		ref := infrav1.AWSIdentityReference{Name: o.GetName()}
+		switch o.(type) {
+		case *infrav1.AWSClusterRoleIdentity:
+			ref.Kind = infrav1.ClusterRoleIdentityKind
+		case *infrav1.AWSClusterStaticIdentity:
+			ref.Kind = infrav1.ClusterStaticIdentityKind
+		}
+
+		requests := []ctrl.Request{}
+		for _, key := range scope.EvictIdentitySessions(ref) {
+			log.V(2).Info("Evicted cached AWS session of changed identity", "identity", ref.Name, "kind", ref.Kind, "namespace", key.Namespace, "awsCluster", key.Name)
+			requests = append(requests, ctrl.Request{NamespacedName: key})
+		}
+		return requests
+	}
 }
 
 func (r *AWSClusterReconciler) requeueAWSClusterForUnpausedCluster(ctx context.Context, log logr.Logger) handler.MapFunc {
@@ -390,11 +848,6 @@ func (r *AWSClusterReconciler) requeueAWSClusterForUnpausedCluster(ctx context.C
 			return nil
 		}
 
-		if annotations.IsExternallyManaged(awsCluster) {
-			log.V(4).Info("AWSCluster is externally managed, skipping mapping.")
-			return nil
-		}
-
 		log.V(4).Info("Adding request.", "awsCluster", c.Spec.InfrastructureRef.Name)
 		return []ctrl.Request{
 			{
@@ -403,3 +856,20 @@ func (r *AWSClusterReconciler) requeueAWSClusterForUnpausedCluster(ctx context.C
 		}
 	}
 }
+
+// resourceNotPausedOrPausing filters out the events of paused resources, except for the update which
+// adds the paused annotation, so that the resource can be marked as paused.
+func resourceNotPausedOrPausing(log logr.Logger) predicate.Funcs {
+	notPaused := predicates.ResourceNotPaused(log)
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if annotations.HasPausedAnnotation(e.ObjectNew) && !annotations.HasPausedAnnotation(e.ObjectOld) {
+				return true
+			}
+			return notPaused.Update(e)
+		},
+		CreateFunc:  notPaused.Create,
+		DeleteFunc:  notPaused.Delete,
+		GenericFunc: notPaused.Generic,
+	}
+}
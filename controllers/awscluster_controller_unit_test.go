@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/network"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestReconcilePausedAndUnpaused(t *testing.T) {
+	g := NewWithT(t)
+
+	awsCluster := &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+
+	// Clusters which were never paused don't get the condition.
+	reconcileUnpaused(awsCluster)
+	g.Expect(conditions.Has(awsCluster, infrav1.PausedCondition)).To(BeFalse())
+
+	reconcilePaused(awsCluster)
+	g.Expect(conditions.IsTrue(awsCluster, infrav1.PausedCondition)).To(BeTrue())
+
+	reconcileUnpaused(awsCluster)
+	g.Expect(conditions.IsFalse(awsCluster, infrav1.PausedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(awsCluster, infrav1.PausedCondition)).To(Equal(infrav1.UnpausedReason))
+	g.Expect(*conditions.GetSeverity(awsCluster, infrav1.PausedCondition)).To(Equal(clusterv1.ConditionSeverityInfo))
+}
+
+func TestResourceNotPausedOrPausing(t *testing.T) {
+	paused := &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test",
+		Annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+	}}
+	unpaused := &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	tests := []struct {
+		name     string
+		old, new *infrav1.AWSCluster
+		want     bool
+	}{
+		{name: "not paused", old: unpaused, new: unpaused, want: true},
+		{name: "pausing", old: unpaused, new: paused, want: true},
+		{name: "paused", old: paused, new: paused, want: false},
+		{name: "unpausing", old: paused, new: unpaused, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := resourceNotPausedOrPausing(klogr.New())
+			g.Expect(p.Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new})).To(Equal(tt.want))
+		})
+	}
+
+	g := NewWithT(t)
+	p := resourceNotPausedOrPausing(klogr.New())
+	g.Expect(p.Create(event.CreateEvent{Object: paused})).To(BeFalse())
+	g.Expect(p.Create(event.CreateEvent{Object: unpaused})).To(BeTrue())
+}
+
+func TestNetworkDependencies(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	machine := func(name, clusterName string) *infrav1.AWSMachine {
+		return &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: clusterName},
+		}}
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		machine("test-md-0", "test"),
+		machine("other-md-0", "other"),
+	).Build()
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:     client,
+		Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+		AWSCluster: &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The cluster has no managed VPC, so no network interfaces are looked up.
+	dependencies, err := networkDependencies(context.Background(), client, clusterScope, network.NewService(clusterScope))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dependencies).To(ConsistOf("AWSMachine test-md-0"))
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/go-logr/logr"
@@ -32,16 +34,20 @@ import (
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ecr"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
-	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+	iamsvc "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/secretsmanager"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ssm"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -60,6 +66,14 @@ import (
 // InstanceIDIndex defines the aws machine controller's instance ID index.
 const InstanceIDIndex = ".spec.instanceID"
 
+// connectivityAnalysisRequeueInterval is how often to check whether the analysis of the path from an instance
+// to the API server finished. An analysis usually takes a minute or two.
+const connectivityAnalysisRequeueInterval = 30 * time.Second
+
+// instanceProfileRequeueInterval is how long to wait before launching an instance again when EC2 doesn't know the
+// IAM instance profile just created for it. IAM changes usually propagate within seconds.
+const instanceProfileRequeueInterval = 10 * time.Second
+
 // AWSMachineReconciler reconciles a AwsMachine object.
 type AWSMachineReconciler struct {
 	client.Client
@@ -68,8 +82,13 @@ type AWSMachineReconciler struct {
 	ec2ServiceFactory            func(scope.EC2Scope) services.EC2MachineInterface
 	secretsManagerServiceFactory func(cloud.ClusterScoper) services.SecretInterface
 	SSMServiceFactory            func(cloud.ClusterScoper) services.SecretInterface
+	remoteClientGetter           remote.ClusterClientGetter
 	Endpoints                    []scope.ServiceEndpoint
 	WatchFilterValue             string
+
+	// ProviderIDFormat is the format of the provider IDs set on AWSMachines. When it is set, the provider IDs of
+	// existing machines are rewritten to it and the provider IDs of their nodes are checked.
+	ProviderIDFormat scope.ProviderIDFormat
 }
 
 const (
@@ -113,9 +132,12 @@ func (r *AWSMachineReconciler) getSecretService(machineScope *scope.MachineScope
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
 
 func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -130,6 +152,11 @@ func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// The AWSMachines of the instances of machine pools are managed by the AWSMachinePool controller.
+	if _, ok := awsMachine.Labels[infrav1.MachinePoolNameLabel]; ok {
+		return ctrl.Result{}, nil
+	}
+
 	// Fetch the Machine.
 	machine, err := util.GetOwnerMachine(ctx, r.Client, awsMachine.ObjectMeta)
 	if err != nil {
@@ -167,11 +194,12 @@ func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Create the machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
-		Client:       r.Client,
-		Cluster:      cluster,
-		Machine:      machine,
-		InfraCluster: infraCluster,
-		AWSMachine:   awsMachine,
+		Client:           r.Client,
+		Cluster:          cluster,
+		Machine:          machine,
+		InfraCluster:     infraCluster,
+		AWSMachine:       awsMachine,
+		ProviderIDFormat: r.ProviderIDFormat,
 	})
 	if err != nil {
 		log.Error(err, "failed to create scope")
@@ -185,19 +213,25 @@ func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
+	account := throttle.DefaultBackpressure.Account(scope.ThrottleKey(infraCluster))
+	if delay := account.Delay(); delay > 0 {
+		machineScope.V(2).Info("Delaying reconciliation while AWS throttles the requests of the account", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
 	switch infraScope := infraCluster.(type) {
 	case *scope.ManagedControlPlaneScope:
 		if !awsMachine.ObjectMeta.DeletionTimestamp.IsZero() {
-			return r.reconcileDelete(machineScope, infraScope, infraScope, nil)
+			return account.Requeue(r.reconcileDelete(machineScope, infraScope, infraScope, nil))
 		}
 
-		return r.reconcileNormal(ctx, machineScope, infraScope, infraScope, nil)
+		return account.Requeue(r.reconcileNormal(ctx, machineScope, infraScope, infraScope, nil))
 	case *scope.ClusterScope:
 		if !awsMachine.ObjectMeta.DeletionTimestamp.IsZero() {
-			return r.reconcileDelete(machineScope, infraScope, infraScope, infraScope)
+			return account.Requeue(r.reconcileDelete(machineScope, infraScope, infraScope, infraScope))
 		}
 
-		return r.reconcileNormal(ctx, machineScope, infraScope, infraScope, infraScope)
+		return account.Requeue(r.reconcileNormal(ctx, machineScope, infraScope, infraScope, infraScope))
 	default:
 		return ctrl.Result{}, errors.New("infraCluster has unknown type")
 	}
@@ -322,6 +356,22 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		}
 	}
 
+	// An on-demand analysis may have been interrupted by the deletion of the machine.
+	if _, ok := machineScope.AWSMachine.Annotations[infrav1.AnalyzeConnectivityAnnotation]; ok || feature.Gates.Enabled(feature.InstanceConnectivityCheck) {
+		if err := ec2Service.DeleteAPIServerConnectivityCheck(machineScope); err != nil {
+			machineScope.Error(err, "failed to delete the connectivity check of the instance")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The instances of a cluster which is being deleted are left in place when its deletion policy retains them.
+	if !machineScope.Cluster.DeletionTimestamp.IsZero() && ec2Scope.DeletionPolicy().Instances == infrav1.ResourceDeletionPolicyRetain {
+		machineScope.Info("Retaining EC2 instance, as required by the deletion policy of the cluster", "instance-id", aws.StringValue(machineScope.GetInstanceID()))
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "RetainedInstance", "Retained instance %q of deleted cluster", aws.StringValue(machineScope.GetInstanceID()))
+		controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, nil
+	}
+
 	instance, err := r.findInstance(machineScope, ec2Service)
 	if err != nil {
 		machineScope.Error(err, "unable to find instance")
@@ -338,6 +388,9 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		// 4. Scale controller deployment to 1
 		machineScope.V(2).Info("Unable to locate EC2 instance by ID or tags")
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "NoInstanceFound", "Unable to find matching EC2 instance")
+		if err := r.deleteManagedInstanceProfile(machineScope, clusterScope); err != nil {
+			return ctrl.Result{}, err
+		}
 		controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 		return ctrl.Result{}, nil
 	}
@@ -357,11 +410,6 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		conditions.MarkFalse(machineScope.AWSMachine, infrav1.ELBAttachedCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 	}
 
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
-		instancestateSvc := instancestate.NewService(ec2Scope)
-		instancestateSvc.RemoveInstanceFromEventPattern(instance.ID)
-	}
-
 	// Check the instance state. If it's already shutting down or terminated,
 	// do nothing. Otherwise attempt to delete it.
 	// This decision is based on the ec2-instance-lifecycle graph at
@@ -420,6 +468,10 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %q", instance.ID)
 	}
 
+	if err := r.deleteManagedInstanceProfile(machineScope, clusterScope); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Instance is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 
@@ -452,7 +504,24 @@ func (r *AWSMachineReconciler) findInstance(scope *scope.MachineScope, ec2svc se
 	return instance, nil
 }
 
-func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope, elbScope scope.ELBScope) (ctrl.Result, error) {
+// instanceToAdopt returns the ID of the existing instance the AWSMachine should be brought under management with, or
+// an empty string. That is the instance its ProviderID points at when the instance is not owned by the cluster yet,
+// or, while there's no ProviderID, the instance named by the adopt-instance annotation. Instances found by tags are
+// owned by the cluster already.
+func instanceToAdopt(scope *scope.MachineScope, clusterName string, instance *infrav1.Instance) string {
+	if scope.GetProviderID() == "" {
+		if instance != nil {
+			return ""
+		}
+		return scope.AWSMachine.Annotations[infrav1.AdoptInstanceAnnotation]
+	}
+	if instance == nil || infrav1.Tags(instance.Tags).HasOwned(clusterName) {
+		return ""
+	}
+	return instance.ID
+}
+
+func (r *AWSMachineReconciler) reconcileNormal(ctx context.Context, machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope, elbScope scope.ELBScope) (ctrl.Result, error) {
 	machineScope.Info("Reconciling AWSMachine")
 
 	// If the AWSMachine is in an error state, return early.
@@ -500,31 +569,82 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		conditions.MarkUnknown(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotFoundReason, err.Error())
 		return ctrl.Result{}, err
 	}
+	// Adopt the existing instance the AWSMachine points at, rather than launching a new one
+	if id := instanceToAdopt(machineScope, ec2Scope.Name(), instance); id != "" {
+		instance, err = ec2svc.AdoptInstance(machineScope, id)
+		if err != nil {
+			machineScope.Error(err, "unable to adopt instance")
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedAdoptInstance", "Failed to adopt instance %q: %v", id, err)
+			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceAdoptionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulAdoptInstance", "Adopted existing instance %q", id)
+	}
 	// Create new instance
 	if instance == nil {
+		// Wait for the IPAM provider to allocate the private IP address of the instance
+		if machineScope.AWSMachine.Spec.PrivateIPFromPool != nil {
+			address, err := r.reconcileIPAddressClaim(ctx, machineScope)
+			if err != nil {
+				machineScope.Error(err, "unable to reconcile IP address claim")
+				conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				return ctrl.Result{}, err
+			}
+			if address == nil {
+				machineScope.Info("Waiting for the IPAM provider to allocate the private IP address")
+				conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForIPAddressReason, clusterv1.ConditionSeverityInfo, "")
+				return ctrl.Result{RequeueAfter: ipAddressClaimRequeueInterval}, nil
+			}
+			machineScope.SetAllocatedPrivateIP(*address)
+		}
 		// Avoid a flickering condition between InstanceProvisionStarted and InstanceProvisionFailed if there's a persistent failure with createInstance
-		if conditions.GetReason(machineScope.AWSMachine, infrav1.InstanceReadyCondition) != infrav1.InstanceProvisionFailedReason {
+		if reason := conditions.GetReason(machineScope.AWSMachine, infrav1.InstanceReadyCondition); reason != infrav1.InstanceProvisionFailedReason && reason != infrav1.InstanceTypeNotOfferedReason && !awserrors.IsClassReason(reason) {
 			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceProvisionStartedReason, clusterv1.ConditionSeverityInfo, "")
 			if patchErr := machineScope.PatchObject(); err != nil {
 				machineScope.Error(patchErr, "failed to patch conditions")
 				return ctrl.Result{}, patchErr
 			}
 		}
+		if feature.Gates.Enabled(feature.InstanceTypeOfferingValidation) {
+			offered, err := r.instanceTypeOffered(machineScope, ec2svc)
+			switch {
+			case err != nil:
+				// RunInstances still reports unsupported instance types, so a failed lookup does not block the launch.
+				machineScope.Error(err, "unable to look up the instance type offerings")
+			case !offered:
+				message := fmt.Sprintf("Instance type %s is not offered in availability zone %s",
+					machineScope.AWSMachine.Spec.InstanceType, aws.StringValue(r.failureDomain(machineScope)))
+				conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceTypeNotOfferedReason, clusterv1.ConditionSeverityError, message)
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceTypeNotOffered", message)
+				return ctrl.Result{}, nil
+			}
+		}
+		if feature.Gates.Enabled(feature.SpotMaxPriceValidation) {
+			// The check is advisory, so a failed price lookup does not block the launch.
+			if err := r.reconcileSpotMaxPrice(machineScope, ec2svc); err != nil {
+				machineScope.Error(err, "unable to compare spot max price with the spot price history")
+			}
+		}
 		instance, err = r.createInstance(ec2svc, machineScope, clusterScope)
+		if err != nil && r.fallBackToOnDemand(machineScope, err) {
+			instance, err = r.createInstance(ec2svc, machineScope, clusterScope)
+		}
+		if err != nil && r.waitForInstanceProfile(machineScope, err) {
+			machineScope.Info("Waiting for the IAM instance profile to propagate before launching the instance")
+			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForInstanceProfileReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{RequeueAfter: instanceProfileRequeueInterval}, nil
+		}
 		if err != nil {
 			machineScope.Error(err, "unable to create instance")
-			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, awserrors.ConditionReason(err, infrav1.InstanceProvisionFailedReason), clusterv1.ConditionSeverityError, err.Error())
 			return ctrl.Result{}, err
 		}
+		conditions.Delete(machineScope.AWSMachine, infrav1.SpotMaxPriceBelowMarketCondition)
 	}
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
-		instancestateSvc := instancestate.NewService(ec2Scope)
-		if err := instancestateSvc.AddInstanceToEventPattern(instance.ID); err != nil {
-			return ctrl.Result{}, errors.Wrap(err, "failed to add instance to Event Bridge instance state rule")
-		}
-	}
-
 	// Make sure Spec.ProviderID and Spec.InstanceID are always set.
+	if providerID := machineScope.ProviderID(instance.ID, instance.AvailabilityZone); r.ProviderIDFormat != "" && machineScope.GetProviderID() != "" && machineScope.GetProviderID() != providerID {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulMigrateProviderID", "Changed provider ID from %q to %q", machineScope.GetProviderID(), providerID)
+	}
 	machineScope.SetProviderID(instance.ID, instance.AvailabilityZone)
 	machineScope.SetInstanceID(instance.ID)
 
@@ -535,6 +655,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 
 	existingInstanceState := machineScope.GetInstanceState()
 	machineScope.SetInstanceState(instance.State)
+	machineScope.SetInstanceDetails(instance)
 
 	// Proceed to reconcile the AWSMachine state.
 	if existingInstanceState == nil || *existingInstanceState != instance.State {
@@ -553,9 +674,16 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		conditions.MarkTrue(machineScope.AWSMachine, infrav1.InstanceReadyCondition)
 	case infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated:
 		machineScope.SetNotReady()
-		machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
-		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceUnexpectedTermination", "Unexpected EC2 instance termination")
+		reason := unexpectedTerminationReason(instance.StateReason)
+		machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID(), "reason", reason, "state-reason", instance.StateReason)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceUnexpectedTermination", "Unexpected EC2 instance termination: %s", reason)
 		conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceTerminatedReason, clusterv1.ConditionSeverityError, "")
+		conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+			Type:    infrav1.InstanceUnexpectedTerminationCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: instance.StateReason,
+		})
 	default:
 		machineScope.SetNotReady()
 		machineScope.Info("EC2 instance state is undefined", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
@@ -594,6 +722,8 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		}
 	}
 
+	result := ctrl.Result{}
+
 	// tasks that can only take place during operational instance states
 	if machineScope.InstanceIsOperational() {
 		machineScope.SetAddresses(instance.Addresses)
@@ -605,16 +735,227 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		}
 
 		// Ensure that the security groups are correct.
-		_, err = r.ensureSecurityGroups(ec2svc, machineScope, machineScope.AWSMachine.Spec.AdditionalSecurityGroups, existingSecurityGroups)
+		_, err = r.ensureSecurityGroups(ec2svc, machineScope, machineScope.AdditionalSecurityGroups(), existingSecurityGroups)
 		if err != nil {
-			conditions.MarkFalse(machineScope.AWSMachine, infrav1.SecurityGroupsReadyCondition, infrav1.SecurityGroupsFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(machineScope.AWSMachine, infrav1.SecurityGroupsReadyCondition, awserrors.ConditionReason(err, infrav1.SecurityGroupsFailedReason), clusterv1.ConditionSeverityError, err.Error())
 			machineScope.Error(err, "unable to ensure security groups")
 			return ctrl.Result{}, err
 		}
 		conditions.MarkTrue(machineScope.AWSMachine, infrav1.SecurityGroupsReadyCondition)
+
+		if feature.Gates.Enabled(feature.InstanceScheduledEvents) {
+			if err := r.reconcileScheduledEvents(machineScope, ec2svc); err != nil {
+				machineScope.Error(err, "unable to reconcile instance scheduled events")
+				return ctrl.Result{}, err
+			}
+		}
+
+		if err := r.reconcileUserDataHash(machineScope); err != nil {
+			machineScope.Error(err, "unable to compare bootstrap data with instance user data")
+			return ctrl.Result{}, err
+		}
+
+		if r.ProviderIDFormat != "" {
+			if err := r.reconcileNodeProviderID(ctx, machineScope, instance); err != nil {
+				machineScope.Error(err, "unable to check the provider ID of the node")
+				return ctrl.Result{}, err
+			}
+		}
+
+		if feature.Gates.Enabled(feature.NodeMetadataLabels) {
+			if err := r.reconcileNodeLabels(ctx, machineScope, instance); err != nil {
+				machineScope.Error(err, "unable to label node with instance metadata")
+				return ctrl.Result{}, err
+			}
+		}
+
+		if feature.Gates.Enabled(feature.InstanceStatusCheckTaints) && instance.State == infrav1.InstanceStateRunning {
+			impaired, err := r.reconcileNodeStatusCheckTaint(ctx, machineScope, ec2svc)
+			if err != nil {
+				machineScope.Error(err, "unable to reconcile the status check taint of the node")
+				return ctrl.Result{}, err
+			}
+			if impaired {
+				result.RequeueAfter = statusCheckRequeueInterval
+			}
+		}
+
+		if feature.Gates.Enabled(feature.RightSizingRecommendations) && instance.State == infrav1.InstanceStateRunning {
+			r.reconcileRightSizing(ctx, machineScope, ec2Scope, instance)
+		}
+
+		if _, ok := machineScope.AWSMachine.Annotations[infrav1.AnalyzeConnectivityAnnotation]; ok && instance.State == infrav1.InstanceStateRunning {
+			analyzing, err := ec2svc.AnalyzeAPIServerConnectivity(machineScope, instance)
+			if err != nil {
+				conditions.MarkFalse(machineScope.AWSMachine, infrav1.APIServerReachableCondition, awserrors.ConditionReason(err, infrav1.ConnectivityCheckFailedReason), clusterv1.ConditionSeverityWarning, err.Error())
+				machineScope.Error(err, "unable to analyze connectivity to the API server")
+				return ctrl.Result{}, err
+			}
+			if analyzing {
+				return ctrl.Result{RequeueAfter: connectivityAnalysisRequeueInterval}, nil
+			}
+			delete(machineScope.AWSMachine.Annotations, infrav1.AnalyzeConnectivityAnnotation)
+		} else if feature.Gates.Enabled(feature.InstanceConnectivityCheck) && instance.State == infrav1.InstanceStateRunning {
+			analyzing, err := ec2svc.ReconcileAPIServerConnectivity(machineScope, instance)
+			if err != nil {
+				conditions.MarkFalse(machineScope.AWSMachine, infrav1.APIServerReachableCondition, awserrors.ConditionReason(err, infrav1.ConnectivityCheckFailedReason), clusterv1.ConditionSeverityWarning, err.Error())
+				machineScope.Error(err, "unable to check connectivity to the API server")
+				return ctrl.Result{}, err
+			}
+			if analyzing {
+				return ctrl.Result{RequeueAfter: connectivityAnalysisRequeueInterval}, nil
+			}
+		}
 	}
 
-	return ctrl.Result{}, nil
+	// Check again once the pending instance is running, to mark the machine ready.
+	if instance.State == infrav1.InstanceStatePending {
+		return ctrl.Result{RequeueAfter: ec2Scope.InstanceRunningRequeueInterval()}, nil
+	}
+
+	return result, nil
+}
+
+// reconcileSpotMaxPrice reports when the spot max price of the machine is below the current spot
+// price of its instance type. EC2 does not launch spot instances in that case, which otherwise
+// only shows up as repeated capacity errors.
+func (r *AWSMachineReconciler) reconcileSpotMaxPrice(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface) error {
+	options := machineScope.SpotMarketOptions()
+	if options == nil || aws.StringValue(options.MaxPrice) == "" {
+		conditions.Delete(machineScope.AWSMachine, infrav1.SpotMaxPriceBelowMarketCondition)
+		return nil
+	}
+
+	maxPrice, err := strconv.ParseFloat(*options.MaxPrice, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid spot max price %q", *options.MaxPrice)
+	}
+
+	price, err := ec2svc.GetSpotPrice(machineScope.AWSMachine.Spec.InstanceType, aws.StringValue(r.failureDomain(machineScope)))
+	if err != nil {
+		if awserrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if maxPrice >= price {
+		conditions.Delete(machineScope.AWSMachine, infrav1.SpotMaxPriceBelowMarketCondition)
+		return nil
+	}
+
+	message := fmt.Sprintf("Spot max price %s is below the current spot price %g of instance type %s",
+		*options.MaxPrice, price, machineScope.AWSMachine.Spec.InstanceType)
+	conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+		Type:    infrav1.SpotMaxPriceBelowMarketCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.SpotPriceAboveMaxPriceReason,
+		Message: message,
+	})
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "SpotMaxPriceBelowMarket", message)
+	return nil
+}
+
+// failureDomain returns the availability zone the instance of the machine is launched in, if any.
+func (r *AWSMachineReconciler) failureDomain(machineScope *scope.MachineScope) *string {
+	if machineScope.Machine.Spec.FailureDomain != nil {
+		return machineScope.Machine.Spec.FailureDomain
+	}
+	return machineScope.AWSMachine.Spec.FailureDomain
+}
+
+// instanceTypeOffered reports whether the instance type of the machine is offered in its failure domain.
+// The webhooks already reject most such machines; this covers the failure domains set on the Machine and the
+// machines admitted while the offerings couldn't be looked up. The offerings are looked up with the identity of
+// the cluster, as the names of the availability zones differ between accounts. Machines without a failure domain
+// are not checked.
+func (r *AWSMachineReconciler) instanceTypeOffered(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface) (bool, error) {
+	failureDomain := aws.StringValue(r.failureDomain(machineScope))
+	if failureDomain == "" {
+		return true, nil
+	}
+
+	zones, err := ec2svc.GetInstanceTypeZones(machineScope.AWSMachine.Spec.InstanceType)
+	if err != nil {
+		return false, err
+	}
+	return zones[failureDomain], nil
+}
+
+// fallBackToOnDemand counts a spot launch of the machine which failed for lack of spot capacity, and
+// reports whether the instance is to be launched as an on-demand instance instead, as configured by
+// spotMarketOptions.fallbackToOnDemand.
+func (r *AWSMachineReconciler) fallBackToOnDemand(machineScope *scope.MachineScope, err error) bool {
+	options := machineScope.SpotMarketOptions()
+	if options == nil || options.FallbackToOnDemand == nil || !awserrors.IsSpotCapacityError(errors.Cause(err)) {
+		return false
+	}
+
+	machineScope.AWSMachine.Status.SpotLaunchFailures++
+	attempts := int32(1)
+	if options.FallbackToOnDemand.Attempts != nil {
+		attempts = *options.FallbackToOnDemand.Attempts
+	}
+	if machineScope.AWSMachine.Status.SpotLaunchFailures < attempts {
+		return false
+	}
+
+	machineScope.AWSMachine.Status.OnDemandFallback = true
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "SpotFallbackToOnDemand",
+		"Launching an on-demand instance after %d failed spot launches: %v", machineScope.AWSMachine.Status.SpotLaunchFailures, errors.Cause(err))
+	return true
+}
+
+// reconcileScheduledEvents reports the pending scheduled events of the instance and, when the
+// machine's ScheduledEventPolicy is Replace, marks the machine as failed so it gets remediated
+// before the event takes place.
+func (r *AWSMachineReconciler) reconcileScheduledEvents(machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface) error {
+	events, err := ec2svc.GetInstanceScheduledEvents(*machineScope.GetInstanceID())
+	if err != nil {
+		return err
+	}
+
+	machineScope.SetScheduledEvents(events)
+	if len(events) == 0 {
+		conditions.Delete(machineScope.AWSMachine, infrav1.InstanceScheduledEventsCondition)
+		return nil
+	}
+
+	next := events[0]
+	message := fmt.Sprintf("%s: %s", next.Code, next.Description)
+	if next.NotBefore != nil {
+		message = fmt.Sprintf("%s (not before %s)", message, next.NotBefore.UTC().Format(time.RFC3339))
+	}
+	conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+		Type:    infrav1.InstanceScheduledEventsCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  scheduledEventReason(next.Code),
+		Message: message,
+	})
+
+	if machineScope.AWSMachine.Spec.ScheduledEventPolicy != infrav1.ScheduledEventPolicyReplace || machineScope.AWSMachine.Status.FailureReason != nil {
+		return nil
+	}
+
+	machineScope.Info("Marking machine as failed to replace it before a scheduled event", "event", next.Code, "instance-id", *machineScope.GetInstanceID())
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceScheduledEvent", "Replacing machine before scheduled event %s", message)
+	machineScope.SetFailureReason(capierrors.UpdateMachineError)
+	machineScope.SetFailureMessage(errors.Errorf("EC2 instance has a scheduled event %s", message))
+	return nil
+}
+
+// scheduledEventReason maps an EC2 scheduled event code to the reason of the InstanceScheduledEvents condition.
+func scheduledEventReason(code string) string {
+	switch code {
+	case "instance-retirement":
+		return infrav1.InstanceRetirementScheduledReason
+	case "instance-stop":
+		return infrav1.InstanceStopScheduledReason
+	case "instance-reboot", "system-reboot":
+		return infrav1.InstanceRebootScheduledReason
+	default:
+		return infrav1.SystemMaintenanceScheduledReason
+	}
 }
 
 func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) error {
@@ -657,6 +998,12 @@ func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *
 func (r *AWSMachineReconciler) createInstance(ec2svc services.EC2MachineInterface, machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) (*infrav1.Instance, error) {
 	machineScope.Info("Creating EC2 instance")
 
+	if r.manageInstanceProfile(machineScope) {
+		if err := iamsvc.NewService(clusterScope).ReconcileInstanceProfile(machineScope); err != nil {
+			return nil, errors.Wrapf(err, "failed to reconcile IAM instance profile")
+		}
+	}
+
 	userData, userDataErr := r.resolveUserData(machineScope, clusterScope)
 	if userDataErr != nil {
 		return nil, errors.Wrapf(userDataErr, "failed to resolve userdata")
@@ -670,12 +1017,76 @@ func (r *AWSMachineReconciler) createInstance(ec2svc services.EC2MachineInterfac
 	return instance, nil
 }
 
+// unexpectedTerminationReason maps the EC2 state reason code of a shut down or terminated
+// instance to the reason of the InstanceUnexpectedTermination condition.
+// See https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_StateReason.html
+func unexpectedTerminationReason(stateReason string) string {
+	switch stateReason {
+	case "Server.SpotInstanceShutdown", "Server.SpotInstanceTermination":
+		return infrav1.InstanceSpotInterruptedReason
+	case "Client.UserInitiatedShutdown", "Client.InstanceInitiatedShutdown", "Client.UserInitiatedHibernate":
+		return infrav1.InstanceUserTerminatedReason
+	case "Server.ScheduledStop", "Server.InternalError", "Server.InsufficientInstanceCapacity":
+		return infrav1.InstanceAWSInitiatedTerminationReason
+	default:
+		return infrav1.InstanceTerminationUnknownReason
+	}
+}
+
+// manageInstanceProfile returns true if the IAM instance profile of the machine is created by the controller.
+func (r *AWSMachineReconciler) manageInstanceProfile(machineScope *scope.MachineScope) bool {
+	return feature.Gates.Enabled(feature.MachineIAMInstanceProfile) && machineScope.AWSMachine.Spec.ManagedIAMInstanceProfile != nil
+}
+
+// waitForInstanceProfile returns true if the instance could not be launched because EC2 doesn't know the IAM
+// instance profile created for the machine yet, in which case the launch is retried once it propagated.
+func (r *AWSMachineReconciler) waitForInstanceProfile(machineScope *scope.MachineScope, err error) bool {
+	return r.manageInstanceProfile(machineScope) && awserrors.IsInvalidInstanceProfileError(errors.Cause(err))
+}
+
+func (r *AWSMachineReconciler) deleteManagedInstanceProfile(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) error {
+	if !r.manageInstanceProfile(machineScope) {
+		return nil
+	}
+
+	if err := iamsvc.NewService(clusterScope).DeleteInstanceProfile(machineScope); err != nil {
+		machineScope.Error(err, "failed to delete IAM instance profile")
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDeleteInstanceProfile", "Failed to delete IAM instance profile: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) ([]byte, error) {
 	userData, err := machineScope.GetRawBootstrapData()
 	if err != nil {
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedGetBootstrapData", err.Error())
 		return nil, err
 	}
+	machineScope.SetAnnotation(UserDataHashAnnotation, userdata.ComputeHash(userData))
+
+	// The registry mirrors and GPU bootstrap are appended as cloud-init parts, which Bottlerocket does not read.
+	// Its NVIDIA images ship the drivers, and its settings are appended to the TOML user data instead.
+	if machineScope.UserDataFormat() == infrav1.UserDataFormatCloudInit {
+		userData, err = r.addRegistryMirrors(clusterScope, userData)
+		if err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedConfigureRegistryMirrors", err.Error())
+			return nil, err
+		}
+
+		userData, err = userdata.AddGPUBootstrap(machineScope.AWSMachine.Spec.GPU, userData)
+		if err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedConfigureGPU", err.Error())
+			return nil, err
+		}
+	} else {
+		userData, err = userdata.AddBottlerocketSettings(machineScope.AWSMachine.Spec.Bottlerocket, userData)
+		if err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedConfigureBottlerocket", err.Error())
+			return nil, err
+		}
+	}
 
 	if !machineScope.UseSecretsManager() {
 		return userData, nil
@@ -714,6 +1125,17 @@ func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope,
 	return encryptedCloudInit, nil
 }
 
+// addRegistryMirrors configures the ECR pull-through cache rules of the cluster
+// as registry mirrors of the container runtime.
+func (r *AWSMachineReconciler) addRegistryMirrors(clusterScope cloud.ClusterScoper, userData []byte) ([]byte, error) {
+	ecrScope, ok := clusterScope.(scope.ECRScope)
+	if !ok || ecrScope.ECRPullThroughCache() == nil || ecrScope.ECRPullThroughCache().DisableMirrors {
+		return userData, nil
+	}
+
+	return ecr.NewService(ecrScope).AddContainerdMirrors(userData)
+}
+
 func (r *AWSMachineReconciler) reconcileLBAttachment(machineScope *scope.MachineScope, clusterScope scope.ELBScope, i *infrav1.Instance) error {
 	if !machineScope.IsControlPlane() {
 		return nil
@@ -738,7 +1160,7 @@ func (r *AWSMachineReconciler) reconcileLBAttachment(machineScope *scope.Machine
 		if err := elbsvc.DeregisterInstanceFromAPIServerELB(i); err != nil {
 			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedDetachControlPlaneELB",
 				"Failed to deregister control plane instance %q from load balancer: %v", i.ID, err)
-			conditions.MarkFalse(machineScope.AWSMachine, infrav1.ELBAttachedCondition, infrav1.ELBDetachFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(machineScope.AWSMachine, infrav1.ELBAttachedCondition, awserrors.ConditionReason(err, infrav1.ELBDetachFailedReason), clusterv1.ConditionSeverityError, err.Error())
 			return errors.Wrapf(err, "could not deregister control plane instance %q from load balancer", i.ID)
 		}
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulDetachControlPlaneELB",
@@ -760,7 +1182,7 @@ func (r *AWSMachineReconciler) reconcileLBAttachment(machineScope *scope.Machine
 	if err := elbsvc.RegisterInstanceWithAPIServerELB(i); err != nil {
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedAttachControlPlaneELB",
 			"Failed to register control plane instance %q with load balancer: %v", i.ID, err)
-		conditions.MarkFalse(machineScope.AWSMachine, infrav1.ELBAttachedCondition, infrav1.ELBAttachFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.ELBAttachedCondition, awserrors.ConditionReason(err, infrav1.ELBAttachFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return errors.Wrapf(err, "could not register control plane instance %q with load balancer", i.ID)
 	}
 	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulAttachControlPlaneELB",
@@ -28,23 +28,32 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/mock_services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -317,6 +326,8 @@ func TestAWSMachineReconciler(t *testing.T) {
 					secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
 					g.Expect(ms.AWSMachine.Spec.ProviderID).To(PointTo(Equal("aws:////myMachine")))
+					g.Expect(ms.AWSMachine.Status.Instance).NotTo(BeNil())
+					g.Expect(ms.AWSMachine.Status.Instance.VolumeIDs).To(Equal([]string{"volume-1", "volume-2"}))
 				})
 
 				t.Run("should set instance to pending", func(t *testing.T) {
@@ -566,7 +577,25 @@ func TestAWSMachineReconciler(t *testing.T) {
 					g.Expect(buf.String()).To(ContainSubstring(("Unexpected EC2 instance termination")))
 					g.Eventually(recorder.Events).Should(Receive(ContainSubstring("UnexpectedTermination")))
 					g.Expect(ms.AWSMachine.Status.FailureMessage).To(PointTo(Equal("EC2 instance state \"terminated\" is unexpected")))
-					expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityError, infrav1.InstanceTerminatedReason}})
+					expectConditions(g, ms.AWSMachine, []conditionAssertion{
+						{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityError, infrav1.InstanceTerminatedReason},
+						{infrav1.InstanceUnexpectedTerminationCondition, corev1.ConditionTrue, "", infrav1.InstanceTerminationUnknownReason},
+					})
+				})
+
+				t.Run("should report a spot interruption as the cause of the termination", func(t *testing.T) {
+					g := NewWithT(t)
+					awsMachine := getAWSMachine()
+					setup(awsMachine, t, g)
+					defer teardown(t, g)
+					instanceCreate(t, g)
+					deleteMachine(t, g)
+
+					instance.State = infrav1.InstanceStateTerminated
+					instance.StateReason = "Server.SpotInstanceTermination"
+					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
+					g.Eventually(recorder.Events).Should(Receive(ContainSubstring(infrav1.InstanceSpotInterruptedReason)))
+					expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.InstanceUnexpectedTerminationCondition, corev1.ConditionTrue, "", infrav1.InstanceSpotInterruptedReason}})
 				})
 			})
 		})
@@ -870,6 +899,25 @@ func TestAWSMachineReconciler(t *testing.T) {
 			g.Expect(buf.String()).To(ContainSubstring("EC2 instance is shutting down or already terminated"))
 			g.Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
 		})
+		t.Run("should retain the instance when the cluster is deleted and its deletion policy retains instances", func(t *testing.T) {
+			g := NewWithT(t)
+			awsMachine := getAWSMachine()
+			setup(awsMachine, t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			now := metav1.Now()
+			ms.Cluster.DeletionTimestamp = &now
+			cs.AWSCluster.Spec.DeletionPolicy = &infrav1.DeletionPolicy{
+				Network:   infrav1.ResourceDeletionPolicyRetain,
+				Instances: infrav1.ResourceDeletionPolicyRetain,
+			}
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachine.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+			g.Eventually(recorder.Events).Should(Receive(ContainSubstring("RetainedInstance")))
+		})
 		t.Run("instance not shutting down yet", func(t *testing.T) {
 			id := "aws:////myid"
 			getRunningInstance := func(t *testing.T, g *WithT) {
@@ -1052,3 +1100,1009 @@ func expectConditions(g *WithT, m *infrav1.AWSMachine, expected []conditionAsser
 		g.Expect(actual.Reason).To(Equal(c.reason))
 	}
 }
+
+func TestAWSMachineReconcileScheduledEvents(t *testing.T) {
+	notBefore := metav1.NewTime(time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC))
+	retirement := infrav1.InstanceScheduledEvent{
+		Code:        "instance-retirement",
+		Description: "The instance is running on degraded hardware",
+		NotBefore:   &notBefore,
+	}
+
+	tests := []struct {
+		name           string
+		policy         infrav1.ScheduledEventPolicy
+		events         []infrav1.InstanceScheduledEvent
+		expectedReason string
+		expectFailure  bool
+	}{
+		{
+			name:   "should not set the condition when there are no scheduled events",
+			events: []infrav1.InstanceScheduledEvent{},
+		},
+		{
+			name:           "should report a scheduled retirement",
+			events:         []infrav1.InstanceScheduledEvent{retirement},
+			expectedReason: infrav1.InstanceRetirementScheduledReason,
+		},
+		{
+			name:           "should report the earliest event",
+			policy:         infrav1.ScheduledEventPolicyReport,
+			events:         []infrav1.InstanceScheduledEvent{{Code: "system-reboot", NotBefore: &notBefore}, retirement},
+			expectedReason: infrav1.InstanceRebootScheduledReason,
+		},
+		{
+			name:           "should mark the machine as failed when the policy is Replace",
+			policy:         infrav1.ScheduledEventPolicyReplace,
+			events:         []infrav1.InstanceScheduledEvent{retirement},
+			expectedReason: infrav1.InstanceRetirementScheduledReason,
+			expectFailure:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: infrav1.AWSMachineSpec{
+					ProviderID:           pointer.StringPtr("aws:////i-1"),
+					ScheduledEventPolicy: tt.policy,
+				},
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{},
+				Machine:      &clusterv1.Machine{},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			ec2Svc := mock_services.NewMockEC2MachineInterface(mockCtrl)
+			ec2Svc.EXPECT().GetInstanceScheduledEvents("i-1").Return(tt.events, nil)
+			reconciler := AWSMachineReconciler{Recorder: record.NewFakeRecorder(2)}
+
+			g.Expect(reconciler.reconcileScheduledEvents(ms, ec2Svc)).To(Succeed())
+			g.Expect(ms.AWSMachine.Status.ScheduledEvents).To(Equal(tt.events))
+
+			if tt.expectedReason == "" {
+				g.Expect(conditions.Has(ms.AWSMachine, infrav1.InstanceScheduledEventsCondition)).To(BeFalse())
+			} else {
+				g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.InstanceScheduledEventsCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ms.AWSMachine, infrav1.InstanceScheduledEventsCondition)).To(Equal(tt.expectedReason))
+			}
+
+			if tt.expectFailure {
+				g.Expect(ms.AWSMachine.Status.FailureReason).To(PointTo(Equal(capierrors.UpdateMachineError)))
+			} else {
+				g.Expect(ms.AWSMachine.Status.FailureReason).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestAWSMachineReconcileUserDataHash(t *testing.T) {
+	launchedHash := userdata.ComputeHash([]byte("shell-script"))
+
+	tests := []struct {
+		name            string
+		policy          infrav1.UserDataChangePolicy
+		annotations     map[string]string
+		bootstrapData   string
+		expectCondition bool
+		expectFailure   bool
+	}{
+		{
+			name:          "should record the hash of machines launched before it was tracked",
+			bootstrapData: "shell-script",
+		},
+		{
+			name:          "should not set the condition when the bootstrap data is unchanged",
+			annotations:   map[string]string{UserDataHashAnnotation: launchedHash},
+			bootstrapData: "shell-script",
+		},
+		{
+			name:            "should report changed bootstrap data",
+			annotations:     map[string]string{UserDataHashAnnotation: launchedHash},
+			bootstrapData:   "new-shell-script",
+			expectCondition: true,
+		},
+		{
+			name:            "should mark the machine as failed when the policy is Replace",
+			policy:          infrav1.UserDataChangePolicyReplace,
+			annotations:     map[string]string{UserDataHashAnnotation: launchedHash},
+			bootstrapData:   "new-shell-script",
+			expectCondition: true,
+			expectFailure:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tt.annotations},
+				Spec: infrav1.AWSMachineSpec{
+					ProviderID:           pointer.StringPtr("aws:////i-1"),
+					UserDataChangePolicy: tt.policy,
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data", Namespace: "default"},
+				Data:       map[string][]byte{"value": []byte(tt.bootstrapData)},
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:  fake.NewClientBuilder().WithObjects(awsMachine, secret).Build(),
+				Cluster: &clusterv1.Cluster{},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("bootstrap-data")},
+					},
+				},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+			reconciler := AWSMachineReconciler{Recorder: record.NewFakeRecorder(2)}
+
+			g.Expect(reconciler.reconcileUserDataHash(ms)).To(Succeed())
+
+			if tt.annotations == nil {
+				g.Expect(ms.AWSMachine.Annotations).To(HaveKeyWithValue(UserDataHashAnnotation, launchedHash))
+			}
+			if tt.expectCondition {
+				g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.UserDataOutOfDateCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ms.AWSMachine, infrav1.UserDataOutOfDateCondition)).To(Equal(infrav1.BootstrapDataChangedReason))
+			} else {
+				g.Expect(conditions.Has(ms.AWSMachine, infrav1.UserDataOutOfDateCondition)).To(BeFalse())
+			}
+			if tt.expectFailure {
+				g.Expect(ms.AWSMachine.Status.FailureReason).To(PointTo(Equal(capierrors.UpdateMachineError)))
+			} else {
+				g.Expect(ms.AWSMachine.Status.FailureReason).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestAWSMachineReconcileSpotMaxPrice(t *testing.T) {
+	tests := []struct {
+		name            string
+		spotOptions     *infrav1.SpotMarketOptions
+		failureDomain   *string
+		spotPrice       float64
+		expectLookup    bool
+		expectCondition bool
+	}{
+		{
+			name: "should not look up the spot price for on-demand instances",
+		},
+		{
+			name:        "should not look up the spot price without a max price",
+			spotOptions: &infrav1.SpotMarketOptions{},
+		},
+		{
+			name:         "should not set the condition when the max price is above the spot price",
+			spotOptions:  &infrav1.SpotMarketOptions{MaxPrice: aws.String("0.10")},
+			spotPrice:    0.05,
+			expectLookup: true,
+		},
+		{
+			name:            "should report a max price below the spot price",
+			spotOptions:     &infrav1.SpotMarketOptions{MaxPrice: aws.String("0.01")},
+			failureDomain:   aws.String("us-east-1a"),
+			spotPrice:       0.05,
+			expectLookup:    true,
+			expectCondition: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: infrav1.AWSMachineSpec{
+					InstanceType:      "m5.large",
+					SpotMarketOptions: tt.spotOptions,
+				},
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:  fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster: &clusterv1.Cluster{},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{FailureDomain: tt.failureDomain},
+				},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			ec2Svc := mock_services.NewMockEC2MachineInterface(mockCtrl)
+			if tt.expectLookup {
+				ec2Svc.EXPECT().GetSpotPrice("m5.large", aws.StringValue(tt.failureDomain)).Return(tt.spotPrice, nil)
+			}
+			reconciler := AWSMachineReconciler{Recorder: record.NewFakeRecorder(2)}
+
+			g.Expect(reconciler.reconcileSpotMaxPrice(ms, ec2Svc)).To(Succeed())
+
+			if tt.expectCondition {
+				g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.SpotMaxPriceBelowMarketCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ms.AWSMachine, infrav1.SpotMaxPriceBelowMarketCondition)).To(Equal(infrav1.SpotPriceAboveMaxPriceReason))
+			} else {
+				g.Expect(conditions.Has(ms.AWSMachine, infrav1.SpotMaxPriceBelowMarketCondition)).To(BeFalse())
+			}
+		})
+	}
+}
+
+func TestAWSMachineInstanceTypeOffered(t *testing.T) {
+	tests := []struct {
+		name          string
+		failureDomain *string
+		zones         map[string]bool
+		err           error
+		expectLookup  bool
+		expectOffered bool
+		expectErr     bool
+	}{
+		{
+			name:          "should not look up the offerings without a failure domain",
+			expectOffered: true,
+		},
+		{
+			name:          "should report an instance type offered in the failure domain",
+			failureDomain: aws.String("us-east-1a"),
+			zones:         map[string]bool{"us-east-1a": true, "us-east-1b": true},
+			expectLookup:  true,
+			expectOffered: true,
+		},
+		{
+			name:          "should report an instance type not offered in the failure domain",
+			failureDomain: aws.String("us-east-1e"),
+			zones:         map[string]bool{"us-east-1a": true, "us-east-1b": true},
+			expectLookup:  true,
+		},
+		{
+			name:          "should return the error of the lookup",
+			failureDomain: aws.String("us-east-1a"),
+			err:           errors.New("access denied"),
+			expectLookup:  true,
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       infrav1.AWSMachineSpec{InstanceType: "m5.large"},
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:  fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster: &clusterv1.Cluster{},
+				Machine: &clusterv1.Machine{
+					Spec: clusterv1.MachineSpec{FailureDomain: tt.failureDomain},
+				},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			ec2Svc := mock_services.NewMockEC2MachineInterface(mockCtrl)
+			if tt.expectLookup {
+				ec2Svc.EXPECT().GetInstanceTypeZones("m5.large").Return(tt.zones, tt.err)
+			}
+			reconciler := AWSMachineReconciler{}
+
+			offered, err := reconciler.instanceTypeOffered(ms, ec2Svc)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(offered).To(Equal(tt.expectOffered))
+		})
+	}
+}
+
+func TestAWSMachineFallBackToOnDemand(t *testing.T) {
+	capacityErr := errors.Wrap(awserr.New("InsufficientInstanceCapacity", "no capacity", nil), "failed to run instance")
+
+	tests := []struct {
+		name             string
+		spotOptions      *infrav1.SpotMarketOptions
+		failures         int32
+		err              error
+		expectFallback   bool
+		expectedFailures int32
+	}{
+		{
+			name: "should not fall back for on-demand instances",
+			err:  capacityErr,
+		},
+		{
+			name:        "should not fall back without a fallback policy",
+			spotOptions: &infrav1.SpotMarketOptions{},
+			err:         capacityErr,
+		},
+		{
+			name:        "should not fall back on errors unrelated to spot capacity",
+			spotOptions: &infrav1.SpotMarketOptions{FallbackToOnDemand: &infrav1.SpotFallbackToOnDemand{}},
+			err:         errors.New("access denied"),
+		},
+		{
+			name:             "should count failed spot launches until the attempts are exhausted",
+			spotOptions:      &infrav1.SpotMarketOptions{FallbackToOnDemand: &infrav1.SpotFallbackToOnDemand{Attempts: pointer.Int32Ptr(3)}},
+			failures:         1,
+			err:              capacityErr,
+			expectedFailures: 2,
+		},
+		{
+			name:             "should fall back after the first failed spot launch by default",
+			spotOptions:      &infrav1.SpotMarketOptions{FallbackToOnDemand: &infrav1.SpotFallbackToOnDemand{}},
+			err:              capacityErr,
+			expectFallback:   true,
+			expectedFailures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: infrav1.AWSMachineSpec{
+					InstanceType:      "m5.large",
+					SpotMarketOptions: tt.spotOptions,
+				},
+				Status: infrav1.AWSMachineStatus{SpotLaunchFailures: tt.failures},
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{},
+				Machine:      &clusterv1.Machine{},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			reconciler := AWSMachineReconciler{Recorder: record.NewFakeRecorder(2)}
+
+			g.Expect(reconciler.fallBackToOnDemand(ms, tt.err)).To(Equal(tt.expectFallback))
+			g.Expect(ms.AWSMachine.Status.SpotLaunchFailures).To(Equal(tt.expectedFailures))
+			g.Expect(ms.AWSMachine.Status.OnDemandFallback).To(Equal(tt.expectFallback))
+			if tt.expectFallback {
+				g.Expect(ms.SpotMarketOptions()).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestAWSMachineWaitForInstanceProfile(t *testing.T) {
+	profileErr := errors.Wrap(awserr.New("InvalidParameterValue", "Value (test) for parameter iamInstanceProfile.name is invalid. Invalid IAM Instance Profile name", nil), "failed to run instance")
+
+	tests := []struct {
+		name          string
+		gateEnabled   bool
+		profile       *infrav1.ManagedIAMInstanceProfile
+		err           error
+		expectWaiting bool
+	}{
+		{
+			name:          "should wait for a managed instance profile EC2 doesn't know yet",
+			gateEnabled:   true,
+			profile:       &infrav1.ManagedIAMInstanceProfile{},
+			err:           profileErr,
+			expectWaiting: true,
+		},
+		{
+			name:        "should not wait for an instance profile which is not managed",
+			gateEnabled: true,
+			err:         profileErr,
+		},
+		{
+			name:    "should not wait when the feature gate is disabled",
+			profile: &infrav1.ManagedIAMInstanceProfile{},
+			err:     profileErr,
+		},
+		{
+			name:        "should not wait on other invalid parameters",
+			gateEnabled: true,
+			profile:     &infrav1.ManagedIAMInstanceProfile{},
+			err:         awserr.New("InvalidParameterValue", "Invalid value 'm5.huge' for InstanceType.", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineIAMInstanceProfile, tt.gateEnabled)()
+
+			ms := &scope.MachineScope{AWSMachine: &infrav1.AWSMachine{
+				Spec: infrav1.AWSMachineSpec{ManagedIAMInstanceProfile: tt.profile},
+			}}
+			reconciler := AWSMachineReconciler{}
+
+			g.Expect(reconciler.waitForInstanceProfile(ms, tt.err)).To(Equal(tt.expectWaiting))
+		})
+	}
+}
+
+func TestAWSMachineInstanceToAdopt(t *testing.T) {
+	owned := &infrav1.Instance{ID: "i-owned", Tags: infrav1.Build(infrav1.BuildParams{ClusterName: "test", Lifecycle: infrav1.ResourceLifecycleOwned})}
+	unmanaged := &infrav1.Instance{ID: "i-unmanaged", Tags: map[string]string{"Name": "legacy-node"}}
+
+	tests := []struct {
+		name        string
+		providerID  *string
+		annotations map[string]string
+		instance    *infrav1.Instance
+		expected    string
+	}{
+		{
+			name: "should not adopt without a provider ID or annotation",
+		},
+		{
+			name:        "should adopt the annotated instance while there's no provider ID",
+			annotations: map[string]string{infrav1.AdoptInstanceAnnotation: "i-annotated"},
+			expected:    "i-annotated",
+		},
+		{
+			name:        "should not adopt the annotated instance once an instance was found by tags",
+			annotations: map[string]string{infrav1.AdoptInstanceAnnotation: "i-annotated"},
+			instance:    owned,
+		},
+		{
+			name:       "should adopt the instance of the provider ID when the cluster doesn't own it",
+			providerID: aws.String("aws:///us-east-1a/i-unmanaged"),
+			instance:   unmanaged,
+			expected:   "i-unmanaged",
+		},
+		{
+			name:       "should not adopt the instance of the provider ID when the cluster owns it",
+			providerID: aws.String("aws:///us-east-1a/i-owned"),
+			instance:   owned,
+		},
+		{
+			name:        "should ignore the annotation when the provider ID points at a missing instance",
+			providerID:  aws.String("aws:///us-east-1a/i-missing"),
+			annotations: map[string]string{infrav1.AdoptInstanceAnnotation: "i-annotated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tt.annotations},
+				Spec:       infrav1.AWSMachineSpec{ProviderID: tt.providerID},
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{},
+				Machine:      &clusterv1.Machine{},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			g.Expect(instanceToAdopt(ms, "test", tt.instance)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestAWSMachineReconcileNodeLabels(t *testing.T) {
+	instance := &infrav1.Instance{
+		ID:               "i-1",
+		Type:             "m5.large",
+		AvailabilityZone: "us-east-1a",
+		SubnetID:         "subnet-1",
+		Lifecycle:        infrav1.InstanceLifecycleSpot,
+		ImageID:          "ami-1",
+	}
+	expectedLabels := map[string]string{
+		NodeInstanceTypeLabel:      "m5.large",
+		NodeAvailabilityZoneLabel:  "us-east-1a",
+		NodeSubnetIDLabel:          "subnet-1",
+		NodeInstanceLifecycleLabel: "spot",
+		NodeAMIIDLabel:             "ami-1",
+	}
+
+	tests := []struct {
+		name          string
+		nodeRef       bool
+		annotations   map[string]string
+		nodeLabels    map[string]string
+		expectLabels  map[string]string
+		expectNoCalls bool
+	}{
+		{
+			name:          "should wait for the node to join the cluster",
+			expectNoCalls: true,
+		},
+		{
+			name:         "should label the node and keep its other labels",
+			nodeRef:      true,
+			nodeLabels:   map[string]string{"role": "worker"},
+			expectLabels: map[string]string{"role": "worker", NodeInstanceTypeLabel: "m5.large", NodeAvailabilityZoneLabel: "us-east-1a", NodeSubnetIDLabel: "subnet-1", NodeInstanceLifecycleLabel: "spot", NodeAMIIDLabel: "ami-1"},
+		},
+		{
+			name:    "should not contact the workload cluster when the labels are up to date",
+			nodeRef: true,
+			annotations: map[string]string{
+				NodeLabelsLastAppliedAnnotation: `{"aws.cluster.x-k8s.io/ami-id":"ami-1","aws.cluster.x-k8s.io/availability-zone":"us-east-1a","aws.cluster.x-k8s.io/instance-lifecycle":"spot","aws.cluster.x-k8s.io/instance-type":"m5.large","aws.cluster.x-k8s.io/subnet-id":"subnet-1"}`,
+			},
+			expectNoCalls: true,
+		},
+		{
+			name:    "should remove labels which are no longer applied",
+			nodeRef: true,
+			annotations: map[string]string{
+				NodeLabelsLastAppliedAnnotation: `{"aws.cluster.x-k8s.io/spot-instance-request-id":"sir-1"}`,
+			},
+			nodeLabels:   map[string]string{"aws.cluster.x-k8s.io/spot-instance-request-id": "sir-1"},
+			expectLabels: expectedLabels,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tt.annotations},
+			}
+			machine := &clusterv1.Machine{}
+			if tt.nodeRef {
+				machine.Status.NodeRef = &corev1.ObjectReference{Name: "node-1"}
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+				Machine:      machine,
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			remoteClient := fake.NewClientBuilder().WithObjects(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: tt.nodeLabels},
+			}).Build()
+			remoteCalls := 0
+			reconciler := AWSMachineReconciler{
+				remoteClientGetter: func(_ context.Context, _ string, _ client.Client, cluster client.ObjectKey) (client.Client, error) {
+					remoteCalls++
+					g.Expect(cluster).To(Equal(client.ObjectKey{Namespace: "default", Name: "test-cluster"}))
+					return remoteClient, nil
+				},
+			}
+
+			g.Expect(reconciler.reconcileNodeLabels(context.TODO(), ms, instance)).To(Succeed())
+
+			if tt.expectNoCalls {
+				g.Expect(remoteCalls).To(BeZero())
+				return
+			}
+			node := &corev1.Node{}
+			g.Expect(remoteClient.Get(context.TODO(), client.ObjectKey{Name: "node-1"}, node)).To(Succeed())
+			g.Expect(node.Labels).To(Equal(tt.expectLabels))
+
+			applied, err := reconciler.machineAnnotationJSON(ms.AWSMachine, NodeLabelsLastAppliedAnnotation)
+			g.Expect(err).To(BeNil())
+			g.Expect(applied).To(HaveLen(len(expectedLabels)))
+		})
+	}
+}
+
+func TestAWSMachineReconcileNodeStatusCheckTaint(t *testing.T) {
+	impaired := &ec2.InstanceStatusSummary{
+		Status:  aws.String(ec2.SummaryStatusImpaired),
+		Details: []*ec2.InstanceStatusDetails{{Name: aws.String("reachability"), Status: aws.String("failed")}},
+	}
+	ok := &ec2.InstanceStatusSummary{Status: aws.String(ec2.SummaryStatusOk)}
+	otherTaint := corev1.Taint{Key: "example.com/dedicated", Effect: corev1.TaintEffectNoSchedule}
+	statusTaint := corev1.Taint{Key: NodeImpairedSystemStatusTaint, Effect: corev1.TaintEffectNoExecute}
+
+	tests := []struct {
+		name            string
+		status          *ec2.InstanceStatusSummary
+		wasImpaired     bool
+		nodeRef         bool
+		nodeTaints      []corev1.Taint
+		expectImpaired  bool
+		expectTaints    []corev1.Taint
+		expectNoCalls   bool
+		expectCondition bool
+	}{
+		{
+			name:          "should not contact the workload cluster while the status checks pass",
+			status:        ok,
+			nodeRef:       true,
+			expectNoCalls: true,
+		},
+		{
+			name:          "should not contact the workload cluster when the status is not reported",
+			nodeRef:       true,
+			expectNoCalls: true,
+		},
+		{
+			name:            "should set the condition before the node joined the cluster",
+			status:          impaired,
+			expectImpaired:  true,
+			expectNoCalls:   true,
+			expectCondition: true,
+		},
+		{
+			name:            "should taint the node and keep its other taints",
+			status:          impaired,
+			nodeRef:         true,
+			nodeTaints:      []corev1.Taint{otherTaint},
+			expectImpaired:  true,
+			expectTaints:    []corev1.Taint{otherTaint, statusTaint},
+			expectCondition: true,
+		},
+		{
+			name:            "should not taint the node twice",
+			status:          impaired,
+			wasImpaired:     true,
+			nodeRef:         true,
+			nodeTaints:      []corev1.Taint{statusTaint},
+			expectImpaired:  true,
+			expectTaints:    []corev1.Taint{statusTaint},
+			expectCondition: true,
+		},
+		{
+			name:         "should remove the taint once the status checks pass again",
+			status:       ok,
+			wasImpaired:  true,
+			nodeRef:      true,
+			nodeTaints:   []corev1.Taint{otherTaint, statusTaint},
+			expectTaints: []corev1.Taint{otherTaint},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       infrav1.AWSMachineSpec{ProviderID: aws.String("aws:///us-east-1a/i-1")},
+			}
+			if tt.wasImpaired {
+				conditions.MarkTrue(awsMachine, infrav1.InstanceSystemStatusImpairedCondition)
+			}
+			machine := &clusterv1.Machine{}
+			if tt.nodeRef {
+				machine.Status.NodeRef = &corev1.ObjectReference{Name: "node-1"}
+			}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+				Machine:      machine,
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			ec2Svc := mock_services.NewMockEC2MachineInterface(mockCtrl)
+			ec2Svc.EXPECT().GetInstanceSystemStatus("i-1").Return(tt.status, nil)
+
+			remoteClient := fake.NewClientBuilder().WithObjects(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Spec:       corev1.NodeSpec{Taints: tt.nodeTaints},
+			}).Build()
+			remoteCalls := 0
+			reconciler := AWSMachineReconciler{
+				Recorder: record.NewFakeRecorder(2),
+				remoteClientGetter: func(_ context.Context, _ string, _ client.Client, _ client.ObjectKey) (client.Client, error) {
+					remoteCalls++
+					return remoteClient, nil
+				},
+			}
+
+			impairedStatus, err := reconciler.reconcileNodeStatusCheckTaint(context.TODO(), ms, ec2Svc)
+			g.Expect(err).To(BeNil())
+			g.Expect(impairedStatus).To(Equal(tt.expectImpaired))
+			g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.InstanceSystemStatusImpairedCondition)).To(Equal(tt.expectCondition))
+			if tt.expectCondition {
+				g.Expect(conditions.GetMessage(ms.AWSMachine, infrav1.InstanceSystemStatusImpairedCondition)).To(Equal("reachability failed"))
+			} else {
+				g.Expect(conditions.Has(ms.AWSMachine, infrav1.InstanceSystemStatusImpairedCondition)).To(BeFalse())
+			}
+
+			if tt.expectNoCalls {
+				g.Expect(remoteCalls).To(BeZero())
+				return
+			}
+			node := &corev1.Node{}
+			g.Expect(remoteClient.Get(context.TODO(), client.ObjectKey{Name: "node-1"}, node)).To(Succeed())
+			g.Expect(node.Spec.Taints).To(HaveLen(len(tt.expectTaints)))
+			for i, taint := range node.Spec.Taints {
+				g.Expect(taint.Key).To(Equal(tt.expectTaints[i].Key))
+				g.Expect(taint.Effect).To(Equal(tt.expectTaints[i].Effect))
+			}
+		})
+	}
+}
+
+func TestAWSMachineReconcileNodeProviderID(t *testing.T) {
+	instance := &infrav1.Instance{ID: "i-1", AvailabilityZone: "us-east-1a"}
+
+	tests := []struct {
+		name             string
+		format           scope.ProviderIDFormat
+		nodeProviderID   string
+		expectProviderID string
+		expectReason     string
+	}{
+		{
+			name:             "should set the provider ID of nodes which have none",
+			format:           scope.ProviderIDFormatZonal,
+			expectProviderID: "aws:///us-east-1a/i-1",
+		},
+		{
+			name:             "should accept nodes with the provider ID of the machine",
+			format:           scope.ProviderIDFormatZoneless,
+			nodeProviderID:   "aws:////i-1",
+			expectProviderID: "aws:////i-1",
+		},
+		{
+			name:             "should report nodes with a provider ID in another format",
+			format:           scope.ProviderIDFormatZoneless,
+			nodeProviderID:   "aws:///us-east-1a/i-1",
+			expectProviderID: "aws:///us-east-1a/i-1",
+			expectReason:     infrav1.NodeProviderIDFormatMismatchReason,
+		},
+		{
+			name:             "should report nodes with the provider ID of another instance",
+			format:           scope.ProviderIDFormatZonal,
+			nodeProviderID:   "aws:///us-east-1a/i-2",
+			expectProviderID: "aws:///us-east-1a/i-2",
+			expectReason:     infrav1.NodeProviderIDInstanceMismatchReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:  fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+				Machine: &clusterv1.Machine{
+					Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node-1"}},
+				},
+				InfraCluster:     &scope.ClusterScope{},
+				AWSMachine:       awsMachine,
+				ProviderIDFormat: tt.format,
+			})
+			g.Expect(err).To(BeNil())
+
+			remoteClient := fake.NewClientBuilder().WithObjects(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Spec:       corev1.NodeSpec{ProviderID: tt.nodeProviderID},
+			}).Build()
+			reconciler := AWSMachineReconciler{
+				Recorder:         record.NewFakeRecorder(2),
+				ProviderIDFormat: tt.format,
+				remoteClientGetter: func(_ context.Context, _ string, _ client.Client, _ client.ObjectKey) (client.Client, error) {
+					return remoteClient, nil
+				},
+			}
+
+			g.Expect(reconciler.reconcileNodeProviderID(context.TODO(), ms, instance)).To(Succeed())
+
+			node := &corev1.Node{}
+			g.Expect(remoteClient.Get(context.TODO(), client.ObjectKey{Name: "node-1"}, node)).To(Succeed())
+			g.Expect(node.Spec.ProviderID).To(Equal(tt.expectProviderID))
+			if tt.expectReason == "" {
+				g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.ProviderIDConsistentCondition)).To(BeTrue())
+			} else {
+				g.Expect(conditions.IsFalse(ms.AWSMachine, infrav1.ProviderIDConsistentCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ms.AWSMachine, infrav1.ProviderIDConsistentCondition)).To(Equal(tt.expectReason))
+			}
+		})
+	}
+}
+
+func TestAWSMachineSetRightSizingRecommendation(t *testing.T) {
+	instance := &infrav1.Instance{ID: "i-1", Type: "m5.xlarge"}
+	overprovisioned := &infrav1.RightSizingRecommendation{
+		Finding:                  infrav1.RightSizingFindingOverprovisioned,
+		RecommendedInstanceTypes: []string{"m5.large", "t3.large"},
+		LastCheckTime:            metav1.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		previous       *infrav1.RightSizingRecommendation
+		recommendation *infrav1.RightSizingRecommendation
+		deployment     string
+		expectEvents   []string
+	}{
+		{
+			name:           "should not record events for an optimized instance",
+			recommendation: &infrav1.RightSizingRecommendation{Finding: infrav1.RightSizingFindingOptimized},
+		},
+		{
+			name:           "should record an event on the machine",
+			recommendation: overprovisioned,
+			expectEvents: []string{
+				"Normal InstanceOverprovisioned Instance i-1 of type m5.xlarge is overprovisioned, Compute Optimizer recommends m5.large, t3.large",
+			},
+		},
+		{
+			name:           "should record an event on the machine and its MachineDeployment",
+			recommendation: &infrav1.RightSizingRecommendation{Finding: infrav1.RightSizingFindingUnderprovisioned},
+			deployment:     "md-0",
+			expectEvents: []string{
+				"Warning InstanceUnderprovisioned Instance i-1 of type m5.xlarge is underprovisioned",
+				"Warning InstanceUnderprovisioned Machine test: Instance i-1 of type m5.xlarge is underprovisioned",
+			},
+		},
+		{
+			name:           "should not record events again when the finding is unchanged",
+			previous:       &infrav1.RightSizingRecommendation{Finding: infrav1.RightSizingFindingOverprovisioned},
+			recommendation: overprovisioned,
+			deployment:     "md-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status:     infrav1.AWSMachineStatus{RightSizing: tt.previous},
+			}
+			machine := &clusterv1.Machine{}
+			objects := []client.Object{awsMachine}
+			if tt.deployment != "" {
+				machine.Labels = map[string]string{clusterv1.MachineDeploymentLabelName: tt.deployment}
+				objects = append(objects, &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Name: tt.deployment, Namespace: "default"}})
+			}
+			c := fake.NewClientBuilder().WithObjects(objects...).Build()
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       c,
+				Cluster:      &clusterv1.Cluster{},
+				Machine:      machine,
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			recorder := record.NewFakeRecorder(2)
+			reconciler := AWSMachineReconciler{Client: c, Recorder: recorder}
+
+			reconciler.setRightSizingRecommendation(context.TODO(), ms, instance, tt.recommendation)
+			g.Expect(ms.AWSMachine.Status.RightSizing).To(Equal(tt.recommendation))
+
+			close(recorder.Events)
+			events := []string{}
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			g.Expect(events).To(ConsistOf(tt.expectEvents))
+		})
+	}
+}
+
+func TestAWSMachineReconcileIPAddressClaim(t *testing.T) {
+	newAddress := func(name, address string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ipamAPIVersion)
+		u.SetKind("IPAddress")
+		u.SetNamespace("default")
+		u.SetName(name)
+		g := NewWithT(t)
+		g.Expect(unstructured.SetNestedField(u.Object, address, "spec", "address")).To(Succeed())
+		return u
+	}
+	newClaim := func(addressName string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ipamAPIVersion)
+		u.SetKind("IPAddressClaim")
+		u.SetNamespace("default")
+		u.SetName("test")
+		if addressName != "" {
+			g := NewWithT(t)
+			g.Expect(unstructured.SetNestedField(u.Object, addressName, "status", "addressRef", "name")).To(Succeed())
+		}
+		return u
+	}
+
+	tests := []struct {
+		name          string
+		objects       []client.Object
+		expectAddress *string
+		expectError   bool
+	}{
+		{
+			name: "should create a claim for the pool",
+		},
+		{
+			name:    "should wait for the address of the claim",
+			objects: []client.Object{newClaim("")},
+		},
+		{
+			name:          "should return the address of the claim",
+			objects:       []client.Object{newClaim("test-address"), newAddress("test-address", "10.0.1.10")},
+			expectAddress: aws.String("10.0.1.10"),
+		},
+		{
+			name:        "should fail when the address of the claim does not exist",
+			objects:     []client.Object{newClaim("test-address")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "uid"},
+				Spec: infrav1.AWSMachineSpec{
+					PrivateIPFromPool: &corev1.TypedLocalObjectReference{
+						APIGroup: aws.String("ipam.cluster.x-k8s.io"),
+						Kind:     "InClusterIPPool",
+						Name:     "control-plane",
+					},
+				},
+			}
+			c := fake.NewClientBuilder().WithObjects(append(tt.objects, awsMachine)...).Build()
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       c,
+				Cluster:      &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+				Machine:      &clusterv1.Machine{},
+				InfraCluster: &scope.ClusterScope{},
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).To(BeNil())
+
+			reconciler := AWSMachineReconciler{
+				Client:   c,
+				Recorder: record.NewFakeRecorder(2),
+			}
+
+			address, err := reconciler.reconcileIPAddressClaim(context.TODO(), ms)
+			if tt.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(address).To(Equal(tt.expectAddress))
+
+			claim := &unstructured.Unstructured{}
+			claim.SetAPIVersion(ipamAPIVersion)
+			claim.SetKind("IPAddressClaim")
+			g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "test"}, claim)).To(Succeed())
+			if len(tt.objects) == 0 {
+				poolName, _, _ := unstructured.NestedString(claim.Object, "spec", "poolRef", "name")
+				g.Expect(poolName).To(Equal("control-plane"))
+				g.Expect(claim.GetOwnerReferences()).To(HaveLen(1))
+				g.Expect(claim.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "test-cluster"))
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+const (
+	// ipamAPIVersion is the API version of the IPAddressClaim and IPAddress types of the Cluster API IPAM contract.
+	ipamAPIVersion = "ipam.cluster.x-k8s.io/v1alpha1"
+
+	// ipAddressClaimRequeueInterval is how often to check whether the IPAM provider allocated the address of a
+	// claim. The claims aren't watched, so that the controller starts without the IPAM CRDs installed.
+	ipAddressClaimRequeueInterval = 15 * time.Second
+)
+
+// reconcileIPAddressClaim makes sure an IPAddressClaim for the IP pool of the machine exists, and returns the
+// address the IPAM provider allocated to it, or nil while the address is pending. The claim is owned by the
+// AWSMachine, so that the address is released when the AWSMachine is deleted.
+func (r *AWSMachineReconciler) reconcileIPAddressClaim(ctx context.Context, machineScope *scope.MachineScope) (*string, error) {
+	poolRef := machineScope.AWSMachine.Spec.PrivateIPFromPool
+	key := types.NamespacedName{Namespace: machineScope.Namespace(), Name: machineScope.Name()}
+
+	claim := &unstructured.Unstructured{}
+	claim.SetAPIVersion(ipamAPIVersion)
+	claim.SetKind("IPAddressClaim")
+	err := r.Get(ctx, key, claim)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := r.createIPAddressClaim(ctx, machineScope, poolRef); err != nil {
+			return nil, err
+		}
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulCreateIPAddressClaim", "Created IPAddressClaim %q for %s %q", key.Name, poolRef.Kind, poolRef.Name)
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to get IPAddressClaim %q", key.Name)
+	}
+
+	addressName, _, err := unstructured.NestedString(claim.Object, "status", "addressRef", "name")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the address of IPAddressClaim %q", key.Name)
+	}
+	if addressName == "" {
+		return nil, nil
+	}
+
+	address := &unstructured.Unstructured{}
+	address.SetAPIVersion(ipamAPIVersion)
+	address.SetKind("IPAddress")
+	if err := r.Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: addressName}, address); err != nil {
+		return nil, errors.Wrapf(err, "failed to get IPAddress %q of IPAddressClaim %q", addressName, key.Name)
+	}
+	ip, _, err := unstructured.NestedString(address.Object, "spec", "address")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read IPAddress %q", addressName)
+	}
+	if ip == "" {
+		return nil, errors.Errorf("IPAddress %q of IPAddressClaim %q has no address", addressName, key.Name)
+	}
+
+	return &ip, nil
+}
+
+func (r *AWSMachineReconciler) createIPAddressClaim(ctx context.Context, machineScope *scope.MachineScope, poolRef *corev1.TypedLocalObjectReference) error {
+	claim := &unstructured.Unstructured{}
+	claim.SetAPIVersion(ipamAPIVersion)
+	claim.SetKind("IPAddressClaim")
+	claim.SetNamespace(machineScope.Namespace())
+	claim.SetName(machineScope.Name())
+	claim.SetLabels(map[string]string{clusterv1.ClusterLabelName: machineScope.Cluster.Name})
+	claim.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(machineScope.AWSMachine, infrav1.GroupVersion.WithKind("AWSMachine")),
+	})
+
+	ref := map[string]interface{}{
+		"kind": poolRef.Kind,
+		"name": poolRef.Name,
+	}
+	if poolRef.APIGroup != nil {
+		ref["apiGroup"] = *poolRef.APIGroup
+	}
+	if err := unstructured.SetNestedMap(claim.Object, ref, "spec", "poolRef"); err != nil {
+		return errors.Wrap(err, "failed to set the pool of the IPAddressClaim")
+	}
+
+	if err := r.Create(ctx, claim); err != nil {
+		return errors.Wrapf(err, "failed to create IPAddressClaim %q", machineScope.Name())
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// NodeInstanceTypeLabel is the label of the node of an AWSMachine holding the type of its instance.
+	NodeInstanceTypeLabel = "aws.cluster.x-k8s.io/instance-type"
+
+	// NodeAvailabilityZoneLabel is the label of the node of an AWSMachine holding the availability zone of its instance.
+	NodeAvailabilityZoneLabel = "aws.cluster.x-k8s.io/availability-zone"
+
+	// NodeSubnetIDLabel is the label of the node of an AWSMachine holding the subnet of its instance.
+	NodeSubnetIDLabel = "aws.cluster.x-k8s.io/subnet-id"
+
+	// NodeInstanceLifecycleLabel is the label of the node of an AWSMachine holding whether its instance is a spot,
+	// scheduled or on-demand instance.
+	NodeInstanceLifecycleLabel = "aws.cluster.x-k8s.io/instance-lifecycle"
+
+	// NodeAMIIDLabel is the label of the node of an AWSMachine holding the AMI its instance was launched from.
+	NodeAMIIDLabel = "aws.cluster.x-k8s.io/ami-id"
+
+	// NodeLabelsLastAppliedAnnotation is the key for the machine object annotation
+	// which tracks the labels last applied to the node of the machine, so that the
+	// workload cluster is only contacted when they change.
+	NodeLabelsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-aws-last-applied-node-labels"
+
+	// NodeImpairedSystemStatusTaint is the key of the taint of the node of an AWSMachine whose instance fails its
+	// EC2 system status checks. Its effect is NoExecute, so the pods which don't tolerate it are evicted.
+	NodeImpairedSystemStatusTaint = "aws.cluster.x-k8s.io/impaired-system-status"
+
+	// statusCheckRequeueInterval is how often the system status of an instance is checked while it is impaired,
+	// so that the taint of its node is removed soon after the status recovers.
+	statusCheckRequeueInterval = time.Minute
+
+	remoteClientSourceName = "awsmachine-controller"
+)
+
+// getRemoteClient returns a client of the workload cluster of the machine.
+func (r *AWSMachineReconciler) getRemoteClient(ctx context.Context, machineScope *scope.MachineScope) (client.Client, error) {
+	getter := r.remoteClientGetter
+	if getter == nil {
+		getter = remote.NewClusterClient
+	}
+	return getter(ctx, remoteClientSourceName, r.Client, util.ObjectKey(machineScope.Cluster))
+}
+
+// nodeLabels returns the labels describing the instance of the machine on its node.
+func nodeLabels(instance *infrav1.Instance) map[string]string {
+	lifecycle := instance.Lifecycle
+	if lifecycle == "" {
+		lifecycle = infrav1.InstanceLifecycleOnDemand
+	}
+
+	labels := map[string]string{
+		NodeInstanceTypeLabel:      instance.Type,
+		NodeAvailabilityZoneLabel:  instance.AvailabilityZone,
+		NodeSubnetIDLabel:          instance.SubnetID,
+		NodeInstanceLifecycleLabel: string(lifecycle),
+		NodeAMIIDLabel:             instance.ImageID,
+	}
+	for k, v := range labels {
+		if v == "" {
+			delete(labels, k)
+		}
+	}
+	return labels
+}
+
+// reconcileNodeLabels labels the node of the machine in the workload cluster with the metadata of its instance.
+// Nothing is done until the node joined the cluster.
+func (r *AWSMachineReconciler) reconcileNodeLabels(ctx context.Context, machineScope *scope.MachineScope, instance *infrav1.Instance) error {
+	if machineScope.Machine.Status.NodeRef == nil {
+		return nil
+	}
+
+	desired := nodeLabels(instance)
+	lastApplied, err := r.machineAnnotationJSON(machineScope.AWSMachine, NodeLabelsLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	annotation := make(map[string]interface{}, len(desired))
+	for k, v := range desired {
+		annotation[k] = v
+	}
+	if reflect.DeepEqual(lastApplied, annotation) {
+		return nil
+	}
+
+	remoteClient, err := r.getRemoteClient(ctx, machineScope)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: machineScope.Machine.Status.NodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %q", machineScope.Machine.Status.NodeRef.Name)
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	labels := node.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k := range lastApplied {
+		if _, ok := desired[k]; !ok {
+			delete(labels, k)
+		}
+	}
+	for k, v := range desired {
+		labels[k] = v
+	}
+	node.SetLabels(labels)
+	if err := remoteClient.Patch(ctx, node, patch); err != nil {
+		return errors.Wrapf(err, "failed to label node %q", node.Name)
+	}
+
+	b, err := json.Marshal(annotation)
+	if err != nil {
+		return err
+	}
+	machineScope.SetAnnotation(NodeLabelsLastAppliedAnnotation, string(b))
+	return nil
+}
+
+// reconcileNodeStatusCheckTaint taints the node of the machine with NodeImpairedSystemStatusTaint while EC2 reports
+// the system status of its instance as impaired, so that its pods move to other nodes before a MachineHealthCheck
+// deletes the machine, and removes the taint once the status recovers. It returns whether the status is impaired.
+func (r *AWSMachineReconciler) reconcileNodeStatusCheckTaint(ctx context.Context, machineScope *scope.MachineScope, ec2svc services.EC2MachineInterface) (bool, error) {
+	status, err := ec2svc.GetInstanceSystemStatus(*machineScope.GetInstanceID())
+	if err != nil {
+		return false, err
+	}
+	impaired := status != nil && aws.StringValue(status.Status) == ec2.SummaryStatusImpaired
+	wasImpaired := conditions.IsTrue(machineScope.AWSMachine, infrav1.InstanceSystemStatusImpairedCondition)
+
+	// The node is checked on every reconcile while the status is impaired, so that the taint is restored if it
+	// was removed, and once more after the status recovered.
+	if impaired || wasImpaired {
+		if err := r.setNodeTaint(ctx, machineScope, impaired); err != nil {
+			return impaired, err
+		}
+	}
+
+	if !impaired {
+		if wasImpaired {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "InstanceSystemStatusRecovered", "System status checks of instance %s pass again", *machineScope.GetInstanceID())
+		}
+		conditions.Delete(machineScope.AWSMachine, infrav1.InstanceSystemStatusImpairedCondition)
+		return false, nil
+	}
+
+	message := failedStatusChecks(status)
+	if !wasImpaired {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceSystemStatusImpaired", "System status checks of instance %s fail: %s", *machineScope.GetInstanceID(), message)
+	}
+	conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+		Type:     infrav1.InstanceSystemStatusImpairedCondition,
+		Status:   corev1.ConditionTrue,
+		Severity: clusterv1.ConditionSeverityWarning,
+		Reason:   infrav1.SystemStatusCheckFailedReason,
+		Message:  message,
+	})
+	return true, nil
+}
+
+// setNodeTaint adds NodeImpairedSystemStatusTaint to the node of the machine, or removes it. Nothing is done until
+// the node joined the cluster.
+func (r *AWSMachineReconciler) setNodeTaint(ctx context.Context, machineScope *scope.MachineScope, tainted bool) error {
+	if machineScope.Machine.Status.NodeRef == nil {
+		return nil
+	}
+
+	remoteClient, err := r.getRemoteClient(ctx, machineScope)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: machineScope.Machine.Status.NodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %q", machineScope.Machine.Status.NodeRef.Name)
+	}
+
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints)+1)
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != NodeImpairedSystemStatusTaint {
+			taints = append(taints, taint)
+		}
+	}
+	if tainted == (len(taints) < len(node.Spec.Taints)) {
+		return nil
+	}
+	if tainted {
+		now := metav1.Now()
+		taints = append(taints, corev1.Taint{
+			Key:       NodeImpairedSystemStatusTaint,
+			Effect:    corev1.TaintEffectNoExecute,
+			TimeAdded: &now,
+		})
+	}
+
+	// The taints are replaced as a whole, so the patch fails if the node changed in the meantime.
+	patch := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	node.Spec.Taints = taints
+	if err := remoteClient.Patch(ctx, node, patch); err != nil {
+		return errors.Wrapf(err, "failed to update the taints of node %q", node.Name)
+	}
+	return nil
+}
+
+// failedStatusChecks describes the status checks which didn't pass.
+func failedStatusChecks(status *ec2.InstanceStatusSummary) string {
+	failed := []string{}
+	for _, detail := range status.Details {
+		if aws.StringValue(detail.Status) == ec2.StatusTypePassed {
+			continue
+		}
+		check := fmt.Sprintf("%s %s", aws.StringValue(detail.Name), aws.StringValue(detail.Status))
+		if detail.ImpairedSince != nil {
+			check = fmt.Sprintf("%s since %s", check, detail.ImpairedSince.UTC().Format(time.RFC3339))
+		}
+		failed = append(failed, check)
+	}
+	if len(failed) == 0 {
+		return "system status impaired"
+	}
+	return strings.Join(failed, ", ")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileNodeProviderID checks that the node of the machine has the provider ID of its instance, in the format of
+// the AWSMachine. The provider ID of a node cannot be changed once set, so it is only set on nodes which have none, and
+// other differences are reported through the ProviderIDConsistent condition.
+func (r *AWSMachineReconciler) reconcileNodeProviderID(ctx context.Context, machineScope *scope.MachineScope, instance *infrav1.Instance) error {
+	if machineScope.Machine.Status.NodeRef == nil {
+		return nil
+	}
+	nodeName := machineScope.Machine.Status.NodeRef.Name
+
+	remoteClient, err := r.getRemoteClient(ctx, machineScope)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %q", nodeName)
+	}
+
+	expected := machineScope.ProviderID(instance.ID, instance.AvailabilityZone)
+	if node.Spec.ProviderID == "" {
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Spec.ProviderID = expected
+		if err := remoteClient.Patch(ctx, node, patch); err != nil {
+			return errors.Wrapf(err, "failed to set provider ID of node %q", nodeName)
+		}
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulSetNodeProviderID", "Set provider ID of node %q to %q", nodeName, expected)
+		conditions.MarkTrue(machineScope.AWSMachine, infrav1.ProviderIDConsistentCondition)
+		return nil
+	}
+
+	nodeProviderID, err := noderefutil.NewProviderID(node.Spec.ProviderID)
+	if err != nil || nodeProviderID.ID() != instance.ID {
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.ProviderIDConsistentCondition, infrav1.NodeProviderIDInstanceMismatchReason, clusterv1.ConditionSeverityError,
+			"Node %q has provider ID %q, which does not refer to instance %q", nodeName, node.Spec.ProviderID, instance.ID)
+		return nil
+	}
+
+	if node.Spec.ProviderID != expected {
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.ProviderIDConsistentCondition, infrav1.NodeProviderIDFormatMismatchReason, clusterv1.ConditionSeverityWarning,
+			"Node %q has provider ID %q instead of %q, the node must register again to change it", nodeName, node.Spec.ProviderID, expected)
+		return nil
+	}
+
+	conditions.MarkTrue(machineScope.AWSMachine, infrav1.ProviderIDConsistentCondition)
+	return nil
+}
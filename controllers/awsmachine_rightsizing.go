@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/computeoptimizer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rightSizingCheckInterval is how often the Compute Optimizer recommendation for the instance of an AWSMachine is
+// fetched. Compute Optimizer refreshes its recommendations daily.
+const rightSizingCheckInterval = 12 * time.Hour
+
+// reconcileRightSizing fetches the Compute Optimizer recommendation for the instance of the machine once per
+// rightSizingCheckInterval. The recommendation is only informational, so failing to fetch it does not fail the
+// reconciliation.
+func (r *AWSMachineReconciler) reconcileRightSizing(ctx context.Context, machineScope *scope.MachineScope, ec2Scope scope.EC2Scope, instance *infrav1.Instance) {
+	previous := machineScope.AWSMachine.Status.RightSizing
+	if previous != nil && time.Since(previous.LastCheckTime.Time) < rightSizingCheckInterval {
+		return
+	}
+
+	recommendation, err := computeoptimizer.NewService(ec2Scope).InstanceRecommendation(instance.ID)
+	if err != nil {
+		machineScope.Info("Failed to get the right-sizing recommendation for the instance", "instance-id", instance.ID, "error", err.Error())
+		return
+	}
+	r.setRightSizingRecommendation(ctx, machineScope, instance, recommendation)
+}
+
+// setRightSizingRecommendation records the recommendation in the status of the machine. When the instance becomes
+// over or under provisioned, an event is recorded on the machine and on its MachineDeployment, so that the finding
+// can be acted upon where the instance type is set.
+func (r *AWSMachineReconciler) setRightSizingRecommendation(ctx context.Context, machineScope *scope.MachineScope, instance *infrav1.Instance, recommendation *infrav1.RightSizingRecommendation) {
+	previous := machineScope.AWSMachine.Status.RightSizing
+	machineScope.AWSMachine.Status.RightSizing = recommendation
+
+	if previous != nil && previous.Finding == recommendation.Finding {
+		return
+	}
+
+	var eventType, reason string
+	switch recommendation.Finding {
+	case infrav1.RightSizingFindingUnderprovisioned:
+		eventType, reason = corev1.EventTypeWarning, "InstanceUnderprovisioned"
+	case infrav1.RightSizingFindingOverprovisioned:
+		eventType, reason = corev1.EventTypeNormal, "InstanceOverprovisioned"
+	default:
+		return
+	}
+
+	message := fmt.Sprintf("Instance %s of type %s is %s", instance.ID, instance.Type, strings.ToLower(string(recommendation.Finding)))
+	if len(recommendation.RecommendedInstanceTypes) > 0 {
+		message = fmt.Sprintf("%s, Compute Optimizer recommends %s", message, strings.Join(recommendation.RecommendedInstanceTypes, ", "))
+	}
+	r.Recorder.Event(machineScope.AWSMachine, eventType, reason, message)
+
+	deploymentName, ok := machineScope.Machine.Labels[clusterv1.MachineDeploymentLabelName]
+	if !ok {
+		return
+	}
+	machineDeployment := &clusterv1.MachineDeployment{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: machineScope.Namespace(), Name: deploymentName}, machineDeployment); err != nil {
+		machineScope.Info("Failed to get the MachineDeployment of the machine", "machinedeployment", deploymentName, "error", err.Error())
+		return
+	}
+	r.Recorder.Eventf(machineDeployment, eventType, reason, "Machine %s: %s", machineScope.Name(), message)
+}
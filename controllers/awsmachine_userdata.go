@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// UserDataHashAnnotation is the key for the machine object annotation
+	// which tracks the SHA256 hash of the bootstrap data the instance was launched with.
+	// It is kept as an annotation rather than in the status so that it survives a clusterctl move.
+	UserDataHashAnnotation = "sigs.k8s.io/cluster-api-provider-aws-user-data-hash"
+)
+
+// reconcileUserDataHash compares the current bootstrap data of the machine with the bootstrap data
+// its instance was launched with, and reports a change through the UserDataOutOfDate condition.
+// When the machine's UserDataChangePolicy is Replace, the machine is also marked as failed so it
+// gets remediated.
+func (r *AWSMachineReconciler) reconcileUserDataHash(machineScope *scope.MachineScope) error {
+	bootstrapData, err := machineScope.GetRawBootstrapData()
+	if err != nil {
+		return err
+	}
+	hash := userdata.ComputeHash(bootstrapData)
+
+	launchedHash, ok := machineScope.AWSMachine.GetAnnotations()[UserDataHashAnnotation]
+	if !ok {
+		// Instances launched before the hash was tracked are assumed to run the current bootstrap data.
+		machineScope.SetAnnotation(UserDataHashAnnotation, hash)
+		return nil
+	}
+
+	if launchedHash == hash {
+		conditions.Delete(machineScope.AWSMachine, infrav1.UserDataOutOfDateCondition)
+		return nil
+	}
+
+	conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+		Type:    infrav1.UserDataOutOfDateCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.BootstrapDataChangedReason,
+		Message: "Bootstrap data changed after the instance was launched",
+	})
+
+	if machineScope.AWSMachine.Spec.UserDataChangePolicy != infrav1.UserDataChangePolicyReplace || machineScope.AWSMachine.Status.FailureReason != nil {
+		return nil
+	}
+
+	machineScope.Info("Marking machine as failed to replace it with the new bootstrap data", "instance-id", *machineScope.GetInstanceID())
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "UserDataOutOfDate", "Replacing machine launched with outdated bootstrap data")
+	machineScope.SetFailureReason(capierrors.UpdateMachineError)
+	machineScope.SetFailureMessage(errors.New("EC2 instance was launched with bootstrap data that has since changed"))
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// templateGPUResourceNames maps GPU manufacturers reported by EC2 to the extended resource
+// name their device plugin registers.
+var templateGPUResourceNames = map[string]corev1.ResourceName{
+	"NVIDIA": "nvidia.com/gpu",
+	"AMD":    "amd.com/gpu",
+}
+
+// AWSMachineTemplateReconciler reconciles the capacity of AWSMachineTemplates, which the cluster-autoscaler
+// reads to scale MachineDeployments from zero.
+type AWSMachineTemplateReconciler struct {
+	client.Client
+	ec2ServiceFactory func(scope.EC2Scope) services.EC2MachineInterface
+	Endpoints         []scope.ServiceEndpoint
+	WatchFilterValue  string
+}
+
+func (r *AWSMachineTemplateReconciler) getEC2Service(scope scope.EC2Scope) services.EC2MachineInterface {
+	if r.ec2ServiceFactory != nil {
+		return r.ec2ServiceFactory(scope)
+	}
+
+	return ec2.NewService(scope)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinetemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+
+func (r *AWSMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the AWSMachineTemplate instance.
+	awsMachineTemplate := &infrav1.AWSMachineTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, awsMachineTemplate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Templates are owned by the Cluster once a MachineDeployment references them, and usually carry
+	// the cluster name label before that.
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, awsMachineTemplate.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		cluster, err = util.GetClusterFromMetadata(ctx, r.Client, awsMachineTemplate.ObjectMeta)
+		if err != nil {
+			log.Info("AWSMachineTemplate has no owner Cluster or cluster name label, skipping its capacity")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	log = log.WithValues("cluster", cluster.Name)
+
+	if annotations.IsPaused(cluster, awsMachineTemplate) {
+		log.Info("AWSMachineTemplate or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	instanceType := awsMachineTemplate.Spec.Template.Spec.InstanceType
+	if instanceType == "" {
+		return ctrl.Result{}, nil
+	}
+
+	infraCluster, err := r.getInfraCluster(ctx, log, cluster, awsMachineTemplate)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error getting infra provider cluster or control plane object")
+	}
+	if infraCluster == nil {
+		log.Info("AWSCluster or AWSManagedControlPlane is not ready yet")
+		return ctrl.Result{}, nil
+	}
+
+	info, err := r.getEC2Service(infraCluster).GetInstanceTypeInfo(instanceType)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+
+	helper, err := patch.NewHelper(awsMachineTemplate, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	awsMachineTemplate.Status.Capacity = instanceTypeCapacity(info)
+
+	return ctrl.Result{}, helper.Patch(ctx, awsMachineTemplate)
+}
+
+func (r *AWSMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AWSMachineTemplate{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Complete(r)
+}
+
+func (r *AWSMachineTemplateReconciler) getInfraCluster(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster, awsMachineTemplate *infrav1.AWSMachineTemplate) (scope.EC2Scope, error) {
+	if cluster.Spec.ControlPlaneRef != nil && cluster.Spec.ControlPlaneRef.Kind == AWSManagedControlPlaneRefKind {
+		controlPlane := &ekscontrolplanev1.AWSManagedControlPlane{}
+		controlPlaneName := client.ObjectKey{
+			Namespace: awsMachineTemplate.Namespace,
+			Name:      cluster.Spec.ControlPlaneRef.Name,
+		}
+
+		if err := r.Get(ctx, controlPlaneName, controlPlane); err != nil {
+			// AWSManagedControlPlane is not ready
+			return nil, nil // nolint:nilerr
+		}
+
+		return scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+			Client:         r.Client,
+			Logger:         log,
+			Cluster:        cluster,
+			ControlPlane:   controlPlane,
+			ControllerName: "awsManagedControlPlane",
+			Endpoints:      r.Endpoints,
+		})
+	}
+
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, nil
+	}
+
+	awsCluster := &infrav1.AWSCluster{}
+	infraClusterName := client.ObjectKey{
+		Namespace: awsMachineTemplate.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+
+	if err := r.Get(ctx, infraClusterName, awsCluster); err != nil {
+		// AWSCluster is not ready
+		return nil, nil // nolint:nilerr
+	}
+
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:         r.Client,
+		Logger:         log,
+		Cluster:        cluster,
+		AWSCluster:     awsCluster,
+		ControllerName: "awsmachinetemplate",
+		Endpoints:      r.Endpoints,
+	})
+}
+
+// instanceTypeCapacity returns the cpu, memory and GPU capacity of a machine of the given instance type.
+// GPUs are only reported when their manufacturer has a known device plugin resource name.
+func instanceTypeCapacity(info *awsec2.InstanceTypeInfo) corev1.ResourceList {
+	capacity := corev1.ResourceList{}
+	if info.VCpuInfo != nil && info.VCpuInfo.DefaultVCpus != nil {
+		capacity[corev1.ResourceCPU] = *resource.NewQuantity(aws.Int64Value(info.VCpuInfo.DefaultVCpus), resource.DecimalSI)
+	}
+	if info.MemoryInfo != nil && info.MemoryInfo.SizeInMiB != nil {
+		capacity[corev1.ResourceMemory] = resource.MustParse(fmt.Sprintf("%dMi", aws.Int64Value(info.MemoryInfo.SizeInMiB)))
+	}
+	if info.GpuInfo != nil {
+		gpus := map[corev1.ResourceName]int64{}
+		for _, gpu := range info.GpuInfo.Gpus {
+			if name, ok := templateGPUResourceNames[strings.ToUpper(aws.StringValue(gpu.Manufacturer))]; ok {
+				gpus[name] += aws.Int64Value(gpu.Count)
+			}
+		}
+		for name, count := range gpus {
+			if count > 0 {
+				capacity[name] = *resource.NewQuantity(count, resource.DecimalSI)
+			}
+		}
+	}
+	return capacity
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestInstanceTypeCapacity(t *testing.T) {
+	tests := []struct {
+		name string
+		info *ec2.InstanceTypeInfo
+		want corev1.ResourceList
+	}{
+		{
+			name: "cpu and memory",
+			info: &ec2.InstanceTypeInfo{
+				InstanceType: aws.String("m5.large"),
+				VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(2)},
+				MemoryInfo:   &ec2.MemoryInfo{SizeInMiB: aws.Int64(8192)},
+			},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("8192Mi"),
+			},
+		},
+		{
+			name: "nvidia gpus",
+			info: &ec2.InstanceTypeInfo{
+				InstanceType: aws.String("p3.8xlarge"),
+				VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(32)},
+				MemoryInfo:   &ec2.MemoryInfo{SizeInMiB: aws.Int64(249856)},
+				GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{
+					{Manufacturer: aws.String("NVIDIA"), Count: aws.Int64(4)},
+				}},
+			},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("32"),
+				corev1.ResourceMemory: resource.MustParse("249856Mi"),
+				"nvidia.com/gpu":      resource.MustParse("4"),
+			},
+		},
+		{
+			name: "gpus of unknown manufacturer are skipped",
+			info: &ec2.InstanceTypeInfo{
+				InstanceType: aws.String("vt1.3xlarge"),
+				VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(12)},
+				GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{
+					{Manufacturer: aws.String("Xilinx"), Count: aws.Int64(1)},
+				}},
+			},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("12"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := instanceTypeCapacity(tt.info)
+			g.Expect(got).To(HaveLen(len(tt.want)))
+			for name, quantity := range tt.want {
+				actual, ok := got[name]
+				g.Expect(ok).To(BeTrue(), "missing %s capacity", name)
+				g.Expect(actual.Cmp(quantity)).To(Equal(0), "unexpected %s capacity %s", name, actual.String())
+			}
+		})
+	}
+}
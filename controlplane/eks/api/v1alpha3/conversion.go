@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	clusterapiapiv1alpha3 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	clusterapiapiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -30,14 +31,61 @@ import (
 func (r *AWSManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.AWSManagedControlPlane)
 
-	return Convert_v1alpha3_AWSManagedControlPlane_To_v1alpha4_AWSManagedControlPlane(r, dst, nil)
+	if err := Convert_v1alpha3_AWSManagedControlPlane_To_v1alpha4_AWSManagedControlPlane(r, dst, nil); err != nil {
+		return err
+	}
+	// Manually restore data.
+	restored := &v1alpha4.AWSManagedControlPlane{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.RolePath = restored.Spec.RolePath
+	dst.Spec.RolePermissionsBoundary = restored.Spec.RolePermissionsBoundary
+	dst.Spec.NetworkSpec.AllowNodeToNodeTraffic = restored.Spec.NetworkSpec.AllowNodeToNodeTraffic
+	dst.Spec.NetworkSpec.SecurityGroupOverrideTracking = restored.Spec.NetworkSpec.SecurityGroupOverrideTracking
+	dst.Spec.NetworkSpec.SecurityGroupPolicy = restored.Spec.NetworkSpec.SecurityGroupPolicy
+	dst.Spec.NetworkSpec.Firewall = restored.Spec.NetworkSpec.Firewall
+	dst.Spec.NetworkSpec.GatewayLoadBalancerEndpointRoutes = restored.Spec.NetworkSpec.GatewayLoadBalancerEndpointRoutes
+	dst.Spec.NetworkSpec.Routes = restored.Spec.NetworkSpec.Routes
+	dst.Spec.NetworkSpec.ElasticIPPool = restored.Spec.NetworkSpec.ElasticIPPool
+	dst.Spec.NetworkSpec.NatGatewayAllocationIDs = restored.Spec.NetworkSpec.NatGatewayAllocationIDs
+	dst.Spec.NetworkSpec.VPC.IPv6 = restored.Spec.NetworkSpec.VPC.IPv6
+	dst.Spec.RequeueIntervals = restored.Spec.RequeueIntervals
+	dst.Spec.IPFamily = restored.Spec.IPFamily
+	dst.Spec.OutpostConfig = restored.Spec.OutpostConfig
+	infrav1alpha3.RestoreSubnets(restored.Spec.NetworkSpec.Subnets, dst.Spec.NetworkSpec.Subnets)
+	dst.Status.Network.SharedVPC = restored.Status.Network.SharedVPC
+	dst.Status.Network.InternalAPIServerELB = restored.Status.Network.InternalAPIServerELB
+	dst.Status.Network.InternetGatewayID = restored.Status.Network.InternetGatewayID
+	dst.Status.Network.NatGateways = restored.Status.Network.NatGateways
+	dst.Status.Network.RouteTables = restored.Status.Network.RouteTables
+	dst.Status.Network.DiscoveredSubnets = restored.Status.Network.DiscoveredSubnets
+	dst.Status.Network.Firewall = restored.Status.Network.Firewall
+	dst.Status.Network.APIServerELB.Instances = restored.Status.Network.APIServerELB.Instances
+	dst.Status.Network.APIServerELB.ProxyProtocol = restored.Status.Network.APIServerELB.ProxyProtocol
+	dst.Status.Network.APIServerELB.Attributes.ConnectionDrainingTimeout = restored.Status.Network.APIServerELB.Attributes.ConnectionDrainingTimeout
+	infrav1alpha3.RestoreInstance(restored.Status.Bastion, dst.Status.Bastion)
+	dst.Spec.Bastion.AutoScaling = restored.Spec.Bastion.AutoScaling
+	dst.Status.Bastions = restored.Status.Bastions
+	dst.Status.BastionLoadBalancer = restored.Status.BastionLoadBalancer
+	return nil
 }
 
 // ConvertFrom converts the v1alpha4 AWSManagedControlPlane receiver to a v1alpha3 AWSManagedControlPlane.
 func (r *AWSManagedControlPlane) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.AWSManagedControlPlane)
 
-	return Convert_v1alpha4_AWSManagedControlPlane_To_v1alpha3_AWSManagedControlPlane(src, r, nil)
+	if err := Convert_v1alpha4_AWSManagedControlPlane_To_v1alpha3_AWSManagedControlPlane(src, r, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // ConvertTo converts the v1alpha3 AWSManagedControlPlaneList receiver to a v1alpha4 AWSManagedControlPlaneList.
@@ -103,3 +151,13 @@ func Convert_v1alpha4_Instance_To_v1alpha3_Instance(in *infrav1alpha4.Instance,
 func Convert_v1alpha3_Instance_To_v1alpha4_Instance(in *infrav1alpha3.Instance, out *infrav1alpha4.Instance, s apiconversion.Scope) error {
 	return infrav1alpha3.Convert_v1alpha3_Instance_To_v1alpha4_Instance(in, out, s)
 }
+
+// Convert_v1alpha4_AWSManagedControlPlaneSpec_To_v1alpha3_AWSManagedControlPlaneSpec is an autogenerated conversion function.
+func Convert_v1alpha4_AWSManagedControlPlaneSpec_To_v1alpha3_AWSManagedControlPlaneSpec(in *v1alpha4.AWSManagedControlPlaneSpec, out *AWSManagedControlPlaneSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSManagedControlPlaneSpec_To_v1alpha3_AWSManagedControlPlaneSpec(in, out, s)
+}
+
+// Convert_v1alpha4_AWSManagedControlPlaneStatus_To_v1alpha3_AWSManagedControlPlaneStatus is an autogenerated conversion function.
+func Convert_v1alpha4_AWSManagedControlPlaneStatus_To_v1alpha3_AWSManagedControlPlaneStatus(in *v1alpha4.AWSManagedControlPlaneStatus, out *AWSManagedControlPlaneStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSManagedControlPlaneStatus_To_v1alpha3_AWSManagedControlPlaneStatus(in, out, s)
+}
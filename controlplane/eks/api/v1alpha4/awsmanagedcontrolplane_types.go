@@ -47,6 +47,21 @@ type AWSManagedControlPlaneSpec struct { //nolint: maligned
 	// +optional
 	SecondaryCidrBlock *string `json:"secondaryCidrBlock,omitempty"`
 
+	// IPFamily is the IP family of the addresses of the pods and services of the cluster. IPv6 clusters
+	// need Kubernetes 1.21 or later and the Amazon VPC CNI, and IPv6 is enabled in their VPC.
+	// Defaults to ipv4. The IP family of an existing cluster cannot be changed.
+	// +kubebuilder:default=ipv4
+	// +kubebuilder:validation:Enum=ipv4;ipv6
+	// +optional
+	IPFamily EKSIPFamily `json:"ipFamily,omitempty"`
+
+	// OutpostConfig creates the control plane of the cluster on an AWS Outpost rather than in the AWS
+	// Region, as an EKS local cluster. Local clusters need an existing VPC, only have a private endpoint
+	// and don't support addons, IPv6 or an OIDC provider. It cannot be changed once the cluster has been
+	// created.
+	// +optional
+	OutpostConfig *OutpostConfig `json:"outpostConfig,omitempty"`
+
 	// The AWS Region the cluster lives in.
 	Region string `json:"region,omitempty"`
 
@@ -77,6 +92,21 @@ type AWSManagedControlPlaneSpec struct { //nolint: maligned
 	// +optional
 	RoleAdditionalPolicies *[]string `json:"roleAdditionalPolicies,omitempty"`
 
+	// RolePath is the IAM path of the roles created for the control plane,
+	// its managed machine pools and Fargate profiles. The path of an
+	// existing role cannot be changed.
+	// +kubebuilder:validation:Pattern=`^/([!-~]+/)?$`
+	// +kubebuilder:validation:MaxLength:=512
+	// +optional
+	RolePath string `json:"rolePath,omitempty"`
+
+	// RolePermissionsBoundary is the ARN of the managed policy set as the
+	// permissions boundary of the roles created for the control plane, its
+	// managed machine pools and Fargate profiles.
+	// +kubebuilder:validation:Pattern=`^arn:[a-z-]+:iam::([0-9]{12}|aws):policy/.+$`
+	// +optional
+	RolePermissionsBoundary string `json:"rolePermissionsBoundary,omitempty"`
+
 	// Logging specifies which EKS Cluster logs should be enabled. Entries for
 	// each of the enabled logs will be sent to CloudWatch
 	// +optional
@@ -160,6 +190,11 @@ type AWSManagedControlPlaneSpec struct { //nolint: maligned
 	// Amazon VPC CNI addon or if you have specified a secondary CIDR block.
 	// +kubebuilder:default=false
 	DisableVPCCNI bool `json:"disableVPCCNI,omitempty"`
+
+	// RequeueIntervals overrides how often the controllers check on this
+	// cluster's AWS resources while they are not ready yet.
+	// +optional
+	RequeueIntervals *RequeueIntervals `json:"requeueIntervals,omitempty"`
 }
 
 // EndpointAccess specifies how control plane endpoints are accessible.
@@ -202,6 +237,12 @@ type AWSManagedControlPlaneStatus struct {
 	// Bastion holds details of the instance that is used as a bastion jump box
 	// +optional
 	Bastion *infrav1.Instance `json:"bastion,omitempty"`
+	// Bastions holds details of all the bastion hosts
+	// +optional
+	Bastions []infrav1.Instance `json:"bastions,omitempty"`
+	// BastionLoadBalancer holds details of the network load balancer in front of the bastion hosts, if any
+	// +optional
+	BastionLoadBalancer *infrav1.BastionLoadBalancer `json:"bastionLoadBalancer,omitempty"`
 	// OIDCProvider holds the status of the identity provider for this cluster
 	// +optional
 	OIDCProvider OIDCProviderStatus `json:"oidcProvider,omitempty"`
@@ -19,8 +19,10 @@ package v1alpha4
 import (
 	"fmt"
 	"net"
+	"reflect"
 
 	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/eks"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -35,6 +38,8 @@ import (
 
 const (
 	minAddonVersion      = "v1.18.0"
+	minIPv6Version       = "v1.21.0"
+	minOutpostVersion    = "v1.21.0"
 	maxClusterNameLength = 100
 )
 
@@ -49,16 +54,19 @@ const (
 
 // SetupWebhookWithManager will setup the webhooks for the AWSManagedControlPlane.
 func (r *AWSManagedControlPlane) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-controlplane-cluster-x-k8s-io-v1alpha4-awsmanagedcontrolplane,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=controlplane.cluster.x-k8s.io,resources=awsmanagedcontrolplanes,versions=v1alpha4,name=validation.awsmanagedcontrolplanes.controlplane.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-controlplane-cluster-x-k8s-io-v1alpha4-awsmanagedcontrolplane,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=controlplane.cluster.x-k8s.io,resources=awsmanagedcontrolplanes,versions=v1alpha4,name=default.awsmanagedcontrolplanes.controlplane.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.Defaulter = &AWSManagedControlPlane{}
-var _ webhook.Validator = &AWSManagedControlPlane{}
+var _ webhooks.Validator = &AWSManagedControlPlane{}
 
 func parseEKSVersion(raw string) (*version.Version, error) {
 	v, err := version.ParseGeneric(raw)
@@ -78,7 +86,7 @@ func normalizeVersion(raw string) (string, error) {
 }
 
 // ValidateCreate will do any extra validation when creating a AWSManagedControlPlane.
-func (r *AWSManagedControlPlane) ValidateCreate() error {
+func (r *AWSManagedControlPlane) ValidateCreate() (webhooks.Warnings, error) {
 	mcpLog.Info("AWSManagedControlPlane validate create", "name", r.Name)
 
 	var allErrs field.ErrorList
@@ -89,16 +97,19 @@ func (r *AWSManagedControlPlane) ValidateCreate() error {
 
 	allErrs = append(allErrs, r.validateEKSVersion(nil)...)
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.SecurityGroupPolicy.Validate()...)
 	allErrs = append(allErrs, r.validateIAMAuthConfig()...)
 	allErrs = append(allErrs, r.validateSecondaryCIDR()...)
 	allErrs = append(allErrs, r.validateEKSAddons()...)
 	allErrs = append(allErrs, r.validateDisableVPCCNI()...)
+	allErrs = append(allErrs, r.validateIPFamily(nil)...)
+	allErrs = append(allErrs, r.validateOutpostConfig(nil)...)
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		r.GroupVersionKind().GroupKind(),
 		r.Name,
 		allErrs,
@@ -106,11 +117,11 @@ func (r *AWSManagedControlPlane) ValidateCreate() error {
 }
 
 // ValidateUpdate will do any extra validation when updating a AWSManagedControlPlane.
-func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
+func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	mcpLog.Info("AWSManagedControlPlane validate update", "name", r.Name)
 	oldAWSManagedControlplane, ok := old.(*AWSManagedControlPlane)
 	if !ok {
-		return apierrors.NewInvalid(GroupVersion.WithKind("AWSManagedControlPlane").GroupKind(), r.Name, field.ErrorList{
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AWSManagedControlPlane").GroupKind(), r.Name, field.ErrorList{
 			field.InternalError(nil, errors.New("failed to convert old AWSManagedControlPlane to object")),
 		})
 	}
@@ -120,10 +131,13 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
 	allErrs = append(allErrs, r.validateEKSClusterNameSame(oldAWSManagedControlplane)...)
 	allErrs = append(allErrs, r.validateEKSVersion(oldAWSManagedControlplane)...)
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.SecurityGroupPolicy.Validate()...)
 	allErrs = append(allErrs, r.validateIAMAuthConfig()...)
 	allErrs = append(allErrs, r.validateSecondaryCIDR()...)
 	allErrs = append(allErrs, r.validateEKSAddons()...)
 	allErrs = append(allErrs, r.validateDisableVPCCNI()...)
+	allErrs = append(allErrs, r.validateIPFamily(oldAWSManagedControlplane)...)
+	allErrs = append(allErrs, r.validateOutpostConfig(oldAWSManagedControlplane)...)
 
 	if r.Spec.Region != oldAWSManagedControlplane.Spec.Region {
 		allErrs = append(allErrs,
@@ -131,6 +145,12 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
 		)
 	}
 
+	if r.Spec.RolePath != oldAWSManagedControlplane.Spec.RolePath {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "rolePath"), r.Spec.RolePath, "field is immutable"),
+		)
+	}
+
 	// If encryptionConfig is already set, do not allow removal of it.
 	if oldAWSManagedControlplane.Spec.EncryptionConfig != nil && r.Spec.EncryptionConfig == nil {
 		allErrs = append(allErrs,
@@ -154,10 +174,10 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
 	}
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		r.GroupVersionKind().GroupKind(),
 		r.Name,
 		allErrs,
@@ -165,10 +185,10 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
 }
 
 // ValidateDelete allows you to add any extra validation when deleting.
-func (r *AWSManagedControlPlane) ValidateDelete() error {
+func (r *AWSManagedControlPlane) ValidateDelete() (webhooks.Warnings, error) {
 	mcpLog.Info("AWSManagedControlPlane validate delete", "name", r.Name)
 
-	return nil
+	return nil, nil
 }
 
 func (r *AWSManagedControlPlane) validateEKSClusterName() field.ErrorList {
@@ -325,6 +345,108 @@ func (r *AWSManagedControlPlane) validateDisableVPCCNI() field.ErrorList {
 	return allErrs
 }
 
+func (r *AWSManagedControlPlane) validateIPFamily(old *AWSManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+	familyField := field.NewPath("spec", "ipFamily")
+
+	if old != nil && ipFamilyOrDefault(r.Spec.IPFamily) != ipFamilyOrDefault(old.Spec.IPFamily) {
+		allErrs = append(allErrs, field.Invalid(familyField, r.Spec.IPFamily, "field is immutable"))
+	}
+
+	if r.Spec.IPFamily != EKSIPFamilyIPv6 {
+		return allErrs
+	}
+
+	if r.Spec.Version != nil {
+		v, err := parseEKSVersion(*r.Spec.Version)
+		minVersion, _ := version.ParseSemantic(minIPv6Version)
+		if err == nil && v.LessThan(minVersion) {
+			message := fmt.Sprintf("IPv6 requires Kubernetes %s or greater", minIPv6Version)
+			allErrs = append(allErrs, field.Invalid(familyField, r.Spec.IPFamily, message))
+		}
+	}
+
+	if r.Spec.DisableVPCCNI {
+		allErrs = append(allErrs, field.Invalid(familyField, r.Spec.IPFamily, "IPv6 requires the vpc cni"))
+	}
+
+	if r.Spec.SecondaryCidrBlock != nil {
+		allErrs = append(allErrs, field.Invalid(familyField, r.Spec.IPFamily, "cannot use IPv6 if a secondary cidr is specified"))
+	}
+
+	if !r.Spec.NetworkSpec.VPC.IsIPv6Enabled() {
+		allErrs = append(allErrs, field.Invalid(familyField, r.Spec.IPFamily, "IPv6 requires spec.networkSpec.vpc.ipv6 to be set"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSManagedControlPlane) validateOutpostConfig(old *AWSManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+	outpostField := field.NewPath("spec", "outpostConfig")
+
+	if old != nil && !reflect.DeepEqual(r.Spec.OutpostConfig, old.Spec.OutpostConfig) {
+		allErrs = append(allErrs, field.Invalid(outpostField, r.Spec.OutpostConfig, "field is immutable"))
+	}
+
+	cfg := r.Spec.OutpostConfig
+	if cfg == nil {
+		return allErrs
+	}
+
+	if len(cfg.OutpostArns) != 1 {
+		allErrs = append(allErrs, field.Invalid(outpostField.Child("outpostArns"), cfg.OutpostArns, "exactly one outpost must be specified"))
+	}
+	if cfg.ControlPlaneInstanceType == "" {
+		allErrs = append(allErrs, field.Required(outpostField.Child("controlPlaneInstanceType"), "controlPlaneInstanceType is required"))
+	}
+
+	// Local clusters can't be upgraded.
+	if old != nil && r.Spec.Version != nil && old.Spec.Version != nil && *r.Spec.Version != *old.Spec.Version {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "version"), *r.Spec.Version, "the version of a local cluster is immutable"))
+	}
+	if r.Spec.Version != nil {
+		v, err := parseEKSVersion(*r.Spec.Version)
+		minVersion, _ := version.ParseSemantic(minOutpostVersion)
+		if err == nil && v.LessThan(minVersion) {
+			message := fmt.Sprintf("local clusters require Kubernetes %s or greater", minOutpostVersion)
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "version"), *r.Spec.Version, message))
+		}
+	}
+
+	if r.Spec.NetworkSpec.VPC.ID == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "networkSpec", "vpc", "id"), "local clusters require an existing vpc with subnets on the outpost"))
+	}
+
+	endpointField := field.NewPath("spec", "endpointAccess")
+	if aws.BoolValue(r.Spec.EndpointAccess.Public) {
+		allErrs = append(allErrs, field.Invalid(endpointField.Child("public"), true, "local clusters only have a private endpoint"))
+	}
+	if r.Spec.EndpointAccess.Private != nil && !*r.Spec.EndpointAccess.Private {
+		allErrs = append(allErrs, field.Invalid(endpointField.Child("private"), false, "local clusters only have a private endpoint"))
+	}
+
+	if r.Spec.Addons != nil && len(*r.Spec.Addons) > 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "addons"), len(*r.Spec.Addons), "local clusters don't support addons"))
+	}
+	if r.Spec.AssociateOIDCProvider {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "associateOIDCProvider"), true, "local clusters don't support an oidc provider"))
+	}
+	if r.Spec.IPFamily == EKSIPFamilyIPv6 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "ipFamily"), r.Spec.IPFamily, "local clusters don't support IPv6"))
+	}
+
+	return allErrs
+}
+
+// ipFamilyOrDefault returns the IP family, which is ipv4 for control planes created before it could be set.
+func ipFamilyOrDefault(family EKSIPFamily) EKSIPFamily {
+	if family == "" {
+		return EKSIPFamilyIPv4
+	}
+	return family
+}
+
 // Default will set default values for the AWSManagedControlPlane.
 func (r *AWSManagedControlPlane) Default() {
 	mcpLog.Info("AWSManagedControlPlane setting defaults", "name", r.Name)
@@ -358,6 +480,21 @@ func (r *AWSManagedControlPlane) Default() {
 		r.Spec.Version = &normalizedV
 	}
 
+	// IPv6 clusters need IPv6 in their VPC.
+	if r.Spec.IPFamily == EKSIPFamilyIPv6 && r.Spec.NetworkSpec.VPC.IPv6 == nil {
+		r.Spec.NetworkSpec.VPC.IPv6 = &infrav1.IPv6{}
+	}
+
+	// Local clusters only have a private endpoint.
+	if r.Spec.OutpostConfig != nil {
+		if r.Spec.EndpointAccess.Public == nil {
+			r.Spec.EndpointAccess.Public = aws.Bool(false)
+		}
+		if r.Spec.EndpointAccess.Private == nil {
+			r.Spec.EndpointAccess.Private = aws.Bool(true)
+		}
+	}
+
 	infrav1.SetDefaults_Bastion(&r.Spec.Bastion)
 	infrav1.SetDefaults_NetworkSpec(&r.Spec.NetworkSpec)
 }
@@ -28,7 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	utildefaulting "sigs.k8s.io/cluster-api-provider-aws/test/helpers/defaulting"
 )
 
 var (
@@ -46,6 +46,14 @@ func TestDefaultingWebhook(t *testing.T) {
 		AvailabilityZoneUsageLimit: &AZUsageLimit,
 		AvailabilityZoneSelection:  &infrav1.AZSelectionSchemeOrdered,
 	}
+	defaultIPv6VPCSpec := *defaultVPCSpec.DeepCopy()
+	defaultIPv6VPCSpec.IPv6 = &infrav1.IPv6{}
+	existingVPCSpec := *defaultVPCSpec.DeepCopy()
+	existingVPCSpec.ID = "vpc-123"
+	outpostConfig := &OutpostConfig{
+		OutpostArns:              []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"},
+		ControlPlaneInstanceType: "m5.large",
+	}
 	defaultIdentityRef := &infrav1.AWSIdentityReference{
 		Kind: infrav1.ControllerIdentityKind,
 		Name: infrav1.AWSClusterControllerIdentityName,
@@ -84,21 +92,21 @@ func TestDefaultingWebhook(t *testing.T) {
 			resourceName: "cluster1",
 			resourceNS:   "default",
 			expectHash:   false,
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
 		},
 		{
 			name:         "less than 100 chars, dot in name",
 			resourceName: "team1.cluster1",
 			resourceNS:   "default",
 			expectHash:   false,
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_team1_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_team1_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
 		},
 		{
 			name:         "more than 100 chars",
 			resourceName: "abcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcdeabcde",
 			resourceNS:   "default",
 			expectHash:   true,
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "capi_", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "capi_", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
 		},
 		{
 			name:         "with patch",
@@ -106,7 +114,7 @@ func TestDefaultingWebhook(t *testing.T) {
 			resourceNS:   "default",
 			expectHash:   false,
 			spec:         AWSManagedControlPlaneSpec{Version: &vV1_17_1},
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", Version: &vV1_17, IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", Version: &vV1_17, IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
 		},
 		{
 			name:         "with allowed ip on bastion",
@@ -114,7 +122,7 @@ func TestDefaultingWebhook(t *testing.T) {
 			resourceNS:   "default",
 			expectHash:   false,
 			spec:         AWSManagedControlPlaneSpec{Bastion: infrav1.Bastion{AllowedCIDRBlocks: []string{"100.100.100.100/0"}}},
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: infrav1.Bastion{AllowedCIDRBlocks: []string{"100.100.100.100/0"}}, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: infrav1.Bastion{AllowedCIDRBlocks: []string{"100.100.100.100/0"}}, NetworkSpec: defaultNetworkSpec, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
 		},
 		{
 			name:         "with CNI on network",
@@ -122,14 +130,30 @@ func TestDefaultingWebhook(t *testing.T) {
 			resourceNS:   "default",
 			expectHash:   false,
 			spec:         AWSManagedControlPlaneSpec{NetworkSpec: infrav1.NetworkSpec{CNI: &infrav1.CNISpec{}}},
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: infrav1.NetworkSpec{CNI: &infrav1.CNISpec{}, VPC: defaultVPCSpec}, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: infrav1.NetworkSpec{CNI: &infrav1.CNISpec{}, VPC: defaultVPCSpec}, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
 		},
 		{
 			name:         "secondary CIDR",
 			resourceName: "cluster1",
 			resourceNS:   "default",
 			expectHash:   false,
-			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, SecondaryCidrBlock: nil, TokenMethod: &EKSTokenMethodIAMAuthenticator},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: defaultNetworkSpec, SecondaryCidrBlock: nil, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4},
+		},
+		{
+			name:         "with IPv6",
+			resourceName: "cluster1",
+			resourceNS:   "default",
+			expectHash:   false,
+			spec:         AWSManagedControlPlaneSpec{IPFamily: EKSIPFamilyIPv6},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: infrav1.NetworkSpec{CNI: defaultNetworkSpec.CNI, VPC: defaultIPv6VPCSpec}, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv6},
+		},
+		{
+			name:         "with outpost config",
+			resourceName: "cluster1",
+			resourceNS:   "default",
+			expectHash:   false,
+			spec:         AWSManagedControlPlaneSpec{OutpostConfig: outpostConfig, NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-123"}}},
+			expectSpec:   AWSManagedControlPlaneSpec{EKSClusterName: "default_cluster1", IdentityRef: defaultIdentityRef, Bastion: defaultTestBastion, NetworkSpec: infrav1.NetworkSpec{CNI: defaultNetworkSpec.CNI, VPC: existingVPCSpec}, TokenMethod: &EKSTokenMethodIAMAuthenticator, IPFamily: EKSIPFamilyIPv4, OutpostConfig: outpostConfig, EndpointAccess: EndpointAccess{Public: aws.Bool(false), Private: aws.Bool(true)}},
 		},
 	}
 
@@ -421,6 +445,30 @@ func TestWebhookUpdate(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "changing role path is not allowed",
+			oldClusterSpec: AWSManagedControlPlaneSpec{
+				EKSClusterName: "default_cluster1",
+				RolePath:       "/capa/",
+			},
+			newClusterSpec: AWSManagedControlPlaneSpec{
+				EKSClusterName: "default_cluster1",
+				RolePath:       "/other/",
+			},
+			expectError: true,
+		},
+		{
+			name: "changing role permissions boundary is allowed",
+			oldClusterSpec: AWSManagedControlPlaneSpec{
+				EKSClusterName:          "default_cluster1",
+				RolePermissionsBoundary: "arn:aws:iam::123456789012:policy/boundary",
+			},
+			newClusterSpec: AWSManagedControlPlaneSpec{
+				EKSClusterName:          "default_cluster1",
+				RolePermissionsBoundary: "arn:aws:iam::123456789012:policy/other-boundary",
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -502,7 +550,7 @@ func TestValidatingWebhookCreate_SecondaryCidr(t *testing.T) {
 			if tc.cidrRange != "" {
 				mcp.Spec.SecondaryCidrBlock = &tc.cidrRange
 			}
-			err := mcp.ValidateCreate()
+			_, err := mcp.ValidateCreate()
 
 			if tc.expectError {
 				g.Expect(err).ToNot(BeNil())
@@ -573,7 +621,340 @@ func TestValidatingWebhookUpdate_SecondaryCidr(t *testing.T) {
 				},
 			}
 
-			err := newMCP.ValidateUpdate(oldMCP)
+			_, err := newMCP.ValidateUpdate(oldMCP)
+
+			if tc.expectError {
+				g.Expect(err).ToNot(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookCreate_IPFamily(t *testing.T) {
+	tests := []struct {
+		name          string
+		ipFamily      EKSIPFamily
+		eksVersion    string
+		disableVPCCNI bool
+		secondaryCidr *string
+		vpcIPv6       *infrav1.IPv6
+		expectError   bool
+	}{
+		{
+			name:        "ipv4",
+			ipFamily:    EKSIPFamilyIPv4,
+			eksVersion:  "v1.19",
+			expectError: false,
+		},
+		{
+			name:        "ipv6",
+			ipFamily:    EKSIPFamilyIPv6,
+			eksVersion:  "v1.21",
+			vpcIPv6:     &infrav1.IPv6{},
+			expectError: false,
+		},
+		{
+			name:        "ipv6 with not allowed k8s version",
+			ipFamily:    EKSIPFamilyIPv6,
+			eksVersion:  "v1.20",
+			vpcIPv6:     &infrav1.IPv6{},
+			expectError: true,
+		},
+		{
+			name:          "ipv6 with vpc cni disabled",
+			ipFamily:      EKSIPFamilyIPv6,
+			eksVersion:    "v1.21",
+			disableVPCCNI: true,
+			vpcIPv6:       &infrav1.IPv6{},
+			expectError:   true,
+		},
+		{
+			name:          "ipv6 with secondary cidr",
+			ipFamily:      EKSIPFamilyIPv6,
+			eksVersion:    "v1.21",
+			secondaryCidr: aws.String("100.64.0.0/16"),
+			vpcIPv6:       &infrav1.IPv6{},
+			expectError:   true,
+		},
+		{
+			name:        "ipv6 without ipv6 in the vpc",
+			ipFamily:    EKSIPFamilyIPv6,
+			eksVersion:  "v1.21",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mcp := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName:     "default_cluster1",
+					Version:            &tc.eksVersion,
+					IPFamily:           tc.ipFamily,
+					DisableVPCCNI:      tc.disableVPCCNI,
+					SecondaryCidrBlock: tc.secondaryCidr,
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{IPv6: tc.vpcIPv6},
+					},
+				},
+			}
+			_, err := mcp.ValidateCreate()
+
+			if tc.expectError {
+				g.Expect(err).ToNot(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookUpdate_IPFamily(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldIPFamily EKSIPFamily
+		newIPFamily EKSIPFamily
+		expectError bool
+	}{
+		{
+			name:        "unset to ipv4",
+			oldIPFamily: "",
+			newIPFamily: EKSIPFamilyIPv4,
+			expectError: false,
+		},
+		{
+			name:        "ipv4 to ipv6",
+			oldIPFamily: EKSIPFamilyIPv4,
+			newIPFamily: EKSIPFamilyIPv6,
+			expectError: true,
+		},
+		{
+			name:        "unset to ipv6",
+			oldIPFamily: "",
+			newIPFamily: EKSIPFamilyIPv6,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			vpc := infrav1.VPCSpec{IPv6: &infrav1.IPv6{}}
+			newMCP := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					Version:        aws.String("v1.21"),
+					IPFamily:       tc.newIPFamily,
+					NetworkSpec:    infrav1.NetworkSpec{VPC: vpc},
+				},
+			}
+			oldMCP := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					Version:        aws.String("v1.21"),
+					IPFamily:       tc.oldIPFamily,
+					NetworkSpec:    infrav1.NetworkSpec{VPC: vpc},
+				},
+			}
+
+			_, err := newMCP.ValidateUpdate(oldMCP)
+
+			if tc.expectError {
+				g.Expect(err).ToNot(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookCreate_OutpostConfig(t *testing.T) {
+	outpostConfig := &OutpostConfig{
+		OutpostArns:              []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"},
+		ControlPlaneInstanceType: "m5.large",
+	}
+
+	tests := []struct {
+		name        string
+		mutate      func(*AWSManagedControlPlaneSpec)
+		expectError bool
+	}{
+		{
+			name:        "local cluster",
+			mutate:      func(spec *AWSManagedControlPlaneSpec) {},
+			expectError: false,
+		},
+		{
+			name: "no outposts",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.OutpostConfig.OutpostArns = nil
+			},
+			expectError: true,
+		},
+		{
+			name: "several outposts",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.OutpostConfig.OutpostArns = append(spec.OutpostConfig.OutpostArns, "arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef1")
+			},
+			expectError: true,
+		},
+		{
+			name: "no control plane instance type",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.OutpostConfig.ControlPlaneInstanceType = ""
+			},
+			expectError: true,
+		},
+		{
+			name: "not allowed k8s version",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.Version = aws.String("v1.20")
+			},
+			expectError: true,
+		},
+		{
+			name: "managed vpc",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.NetworkSpec.VPC.ID = ""
+			},
+			expectError: true,
+		},
+		{
+			name: "public endpoint",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.EndpointAccess.Public = aws.Bool(true)
+			},
+			expectError: true,
+		},
+		{
+			name: "addons",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.Addons = &[]Addon{{Name: "vpc-cni", Version: "v1.10.1-eksbuild.1"}}
+			},
+			expectError: true,
+		},
+		{
+			name: "oidc provider",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.AssociateOIDCProvider = true
+			},
+			expectError: true,
+		},
+		{
+			name: "ipv6",
+			mutate: func(spec *AWSManagedControlPlaneSpec) {
+				spec.IPFamily = EKSIPFamilyIPv6
+				spec.NetworkSpec.VPC.IPv6 = &infrav1.IPv6{}
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mcp := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					Version:        aws.String("v1.21"),
+					OutpostConfig:  outpostConfig.DeepCopy(),
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{ID: "vpc-123"},
+					},
+				},
+			}
+			tc.mutate(&mcp.Spec)
+			_, err := mcp.ValidateCreate()
+
+			if tc.expectError {
+				g.Expect(err).ToNot(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookUpdate_OutpostConfig(t *testing.T) {
+	outpostConfig := &OutpostConfig{
+		OutpostArns:              []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"},
+		ControlPlaneInstanceType: "m5.large",
+	}
+
+	tests := []struct {
+		name             string
+		oldOutpostConfig *OutpostConfig
+		newOutpostConfig *OutpostConfig
+		newVersion       string
+		expectError      bool
+	}{
+		{
+			name:             "unchanged",
+			oldOutpostConfig: outpostConfig,
+			newOutpostConfig: outpostConfig,
+			newVersion:       "v1.21",
+			expectError:      false,
+		},
+		{
+			name:             "added",
+			newOutpostConfig: outpostConfig,
+			newVersion:       "v1.21",
+			expectError:      true,
+		},
+		{
+			name:             "removed",
+			oldOutpostConfig: outpostConfig,
+			newVersion:       "v1.21",
+			expectError:      true,
+		},
+		{
+			name:             "control plane instance type changed",
+			oldOutpostConfig: outpostConfig,
+			newOutpostConfig: &OutpostConfig{
+				OutpostArns:              outpostConfig.OutpostArns,
+				ControlPlaneInstanceType: "m5.xlarge",
+			},
+			newVersion:  "v1.21",
+			expectError: true,
+		},
+		{
+			name:             "upgraded",
+			oldOutpostConfig: outpostConfig,
+			newOutpostConfig: outpostConfig,
+			newVersion:       "v1.22",
+			expectError:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			vpc := infrav1.VPCSpec{ID: "vpc-123"}
+			newMCP := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					Version:        aws.String(tc.newVersion),
+					OutpostConfig:  tc.newOutpostConfig,
+					NetworkSpec:    infrav1.NetworkSpec{VPC: vpc},
+				},
+			}
+			oldMCP := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					Version:        aws.String("v1.21"),
+					OutpostConfig:  tc.oldOutpostConfig,
+					NetworkSpec:    infrav1.NetworkSpec{VPC: vpc},
+				},
+			}
+
+			_, err := newMCP.ValidateUpdate(oldMCP)
 
 			if tc.expectError {
 				g.Expect(err).ToNot(BeNil())
@@ -79,6 +79,31 @@ var (
 	EKSTokenMethodAWSCli = EKSTokenMethod("aws-cli")
 )
 
+// EKSIPFamily defines the IP family of the addresses of the pods and services of an EKS cluster.
+type EKSIPFamily string
+
+var (
+	// EKSIPFamilyIPv4 assigns IPv4 addresses to pods and services.
+	EKSIPFamilyIPv4 = EKSIPFamily("ipv4")
+
+	// EKSIPFamilyIPv6 assigns IPv6 addresses to pods and services.
+	EKSIPFamilyIPv6 = EKSIPFamily("ipv6")
+)
+
+// OutpostConfig configures an EKS local cluster, whose control plane runs on an AWS Outpost.
+type OutpostConfig struct {
+	// OutpostArns are the ARNs of the Outposts the control plane instances are created on. Local clusters
+	// support a single Outpost.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=1
+	OutpostArns []string `json:"outpostArns"`
+
+	// ControlPlaneInstanceType is the EC2 instance type of the control plane instances, which must be
+	// available on the Outpost.
+	// +kubebuilder:validation:MinLength:=2
+	ControlPlaneInstanceType string `json:"controlPlaneInstanceType"`
+}
+
 var (
 	// DefaultEKSControlPlaneRole is the name of the default IAM role to use for the EKS control plane
 	// if no other role is supplied in the spec and if iam role creation is not enabled. The default
@@ -209,6 +234,22 @@ type AddonIssue struct {
 	ResourceIDs []string `json:"resourceIds,omitempty"`
 }
 
+// RequeueIntervals overrides how often the controllers check on the AWS
+// resources of an EKS cluster which are not ready yet.
+type RequeueIntervals struct {
+	// InstanceRunning is how often to check whether a pending EC2 instance is
+	// running. Defaults to the controller's --instance-running-requeue-interval
+	// flag.
+	// +optional
+	InstanceRunning *metav1.Duration `json:"instanceRunning,omitempty"`
+
+	// NodegroupUpdate is how often to check whether an update of an EKS managed
+	// node group has completed. Defaults to the controller's
+	// --nodegroup-update-requeue-interval flag.
+	// +optional
+	NodegroupUpdate *metav1.Duration `json:"nodegroupUpdate,omitempty"`
+}
+
 const (
 	// SecurityGroupCluster is the security group for communication between EKS
 	// control plane and managed node groups.
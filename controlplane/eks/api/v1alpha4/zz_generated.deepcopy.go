@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1alpha4 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	cluster_apiapiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -99,6 +100,11 @@ func (in *AWSManagedControlPlaneSpec) DeepCopyInto(out *AWSManagedControlPlaneSp
 		*out = new(string)
 		**out = **in
 	}
+	if in.OutpostConfig != nil {
+		in, out := &in.OutpostConfig, &out.OutpostConfig
+		*out = new(OutpostConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SSHKeyName != nil {
 		in, out := &in.SSHKeyName, &out.SSHKeyName
 		*out = new(string)
@@ -164,6 +170,11 @@ func (in *AWSManagedControlPlaneSpec) DeepCopyInto(out *AWSManagedControlPlaneSp
 			}
 		}
 	}
+	if in.RequeueIntervals != nil {
+		in, out := &in.RequeueIntervals, &out.RequeueIntervals
+		*out = new(RequeueIntervals)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSManagedControlPlaneSpec.
@@ -192,6 +203,18 @@ func (in *AWSManagedControlPlaneStatus) DeepCopyInto(out *AWSManagedControlPlane
 		*out = new(apiv1alpha4.Instance)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Bastions != nil {
+		in, out := &in.Bastions, &out.Bastions
+		*out = make([]apiv1alpha4.Instance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BastionLoadBalancer != nil {
+		in, out := &in.BastionLoadBalancer, &out.BastionLoadBalancer
+		*out = new(apiv1alpha4.BastionLoadBalancer)
+		**out = **in
+	}
 	out.OIDCProvider = in.OIDCProvider
 	if in.ExternalManagedControlPlane != nil {
 		in, out := &in.ExternalManagedControlPlane, &out.ExternalManagedControlPlane
@@ -464,6 +487,51 @@ func (in *OIDCProviderStatus) DeepCopy() *OIDCProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutpostConfig) DeepCopyInto(out *OutpostConfig) {
+	*out = *in
+	if in.OutpostArns != nil {
+		in, out := &in.OutpostArns, &out.OutpostArns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutpostConfig.
+func (in *OutpostConfig) DeepCopy() *OutpostConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OutpostConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeueIntervals) DeepCopyInto(out *RequeueIntervals) {
+	*out = *in
+	if in.InstanceRunning != nil {
+		in, out := &in.InstanceRunning, &out.InstanceRunning
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NodegroupUpdate != nil {
+		in, out := &in.NodegroupUpdate, &out.NodegroupUpdate
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeueIntervals.
+func (in *RequeueIntervals) DeepCopy() *RequeueIntervals {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeueIntervals)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleMapping) DeepCopyInto(out *RoleMapping) {
 	*out = *in
@@ -30,6 +30,7 @@ import (
 	controlplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/awsnode"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
@@ -37,6 +38,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iamauth"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/network"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/securitygroup"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -47,6 +49,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -55,6 +58,14 @@ const (
 	// deleteRequeueAfter is how long to wait before checking again to see if the control plane still
 	// has dependencies during deletion.
 	deleteRequeueAfter = 20 * time.Second
+
+	// bastionDeletionRequeueAfter is how long a control plane whose bastion auto scaling group is being deleted
+	// waits before the group is described again.
+	bastionDeletionRequeueAfter = 30 * time.Second
+
+	// regionNotEnabledRequeueAfter is how long a control plane whose region is not enabled for its AWS account
+	// waits before the region is checked again.
+	regionNotEnabledRequeueAfter = 5 * time.Minute
 )
 
 // AWSManagedControlPlaneReconciler reconciles a AWSManagedControlPlane object.
@@ -95,9 +106,41 @@ func (r *AWSManagedControlPlaneReconciler) SetupWithManager(ctx context.Context,
 		return fmt.Errorf("failed adding a watch for ready clusters: %w", err)
 	}
 
+	// The credentials of the cached sessions are retrieved again when the identity they come from changes.
+	for _, identity := range []client.Object{&infrav1.AWSClusterRoleIdentity{}, &infrav1.AWSClusterStaticIdentity{}} {
+		if err = c.Watch(
+			&source.Kind{Type: identity},
+			handler.EnqueueRequestsFromMapFunc(r.evictIdentitySessions(log)),
+			predicate.GenerationChangedPredicate{},
+		); err != nil {
+			return fmt.Errorf("failed adding a watch for cluster identities: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// evictIdentitySessions evicts the cached sessions using a changed identity, and requeues the
+// AWSManagedControlPlanes they were created for.
+func (r *AWSManagedControlPlaneReconciler) evictIdentitySessions(log logr.Logger) handler.MapFunc {
+	return func(o client.Object) []ctrl.Request {
+		ref := infrav1.AWSIdentityReference{Name: o.GetName()}
+		switch o.(type) {
+		case *infrav1.AWSClusterRoleIdentity:
+			ref.Kind = infrav1.ClusterRoleIdentityKind
+		case *infrav1.AWSClusterStaticIdentity:
+			ref.Kind = infrav1.ClusterStaticIdentityKind
+		}
+
+		requests := []ctrl.Request{}
+		for _, key := range scope.EvictIdentitySessions(ref) {
+			log.V(2).Info("Evicted cached AWS session of changed identity", "identity", ref.Name, "kind", ref.Kind, "namespace", key.Namespace, "awsManagedControlPlane", key.Name)
+			requests = append(requests, ctrl.Request{NamespacedName: key})
+		}
+		return requests
+	}
+}
+
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
@@ -182,13 +225,19 @@ func (r *AWSManagedControlPlaneReconciler) Reconcile(ctx context.Context, req ct
 		}
 	}()
 
+	account := throttle.DefaultBackpressure.Account(scope.ThrottleKey(managedScope))
+	if delay := account.Delay(); delay > 0 {
+		managedScope.V(2).Info("Delaying reconciliation while AWS throttles the requests of the account", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
 	if !awsControlPlane.ObjectMeta.DeletionTimestamp.IsZero() {
 		// Handle deletion reconciliation loop.
-		return r.reconcileDelete(ctx, managedScope)
+		return account.Requeue(r.reconcileDelete(ctx, managedScope))
 	}
 
 	// Handle normal reconciliation loop.
-	return r.reconcileNormal(ctx, managedScope)
+	return account.Requeue(r.reconcileNormal(ctx, managedScope))
 }
 
 func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context, managedScope *scope.ManagedControlPlaneScope) (res ctrl.Result, reterr error) {
@@ -213,17 +262,26 @@ func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context,
 	authService := iamauth.NewService(managedScope, iamauth.BackendTypeConfigMap, managedScope.Client)
 	awsnodeService := awsnode.NewService(managedScope)
 
+	enabled, err := ec2Service.ReconcileRegion()
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to check the region of AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
+	}
+	if !enabled {
+		return reconcile.Result{RequeueAfter: regionNotEnabledRequeueAfter}, nil
+	}
+
 	if err := networkSvc.ReconcileNetwork(); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile network for AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
 	}
 
 	if err := sgService.ReconcileSecurityGroups(); err != nil {
-		conditions.MarkFalse(awsManagedControlPlane, infrav1.ClusterSecurityGroupsReadyCondition, infrav1.ClusterSecurityGroupReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(awsManagedControlPlane, infrav1.ClusterSecurityGroupsReadyCondition, awserrors.ConditionReason(err, infrav1.ClusterSecurityGroupReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile general security groups for AWSManagedControlPlane %s/%s", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name)
 	}
 
-	if err := ec2Service.ReconcileBastion(); err != nil {
-		conditions.MarkFalse(awsManagedControlPlane, infrav1.BastionHostReadyCondition, infrav1.BastionHostFailedReason, clusterv1.ConditionSeverityError, err.Error())
+	bastionRequeue, err := ec2Service.ReconcileBastion()
+	if err != nil {
+		conditions.MarkFalse(awsManagedControlPlane, infrav1.BastionHostReadyCondition, awserrors.ConditionReason(err, infrav1.BastionHostFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile bastion host for AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
 	}
 
@@ -232,12 +290,12 @@ func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context,
 	}
 
 	if err := awsnodeService.ReconcileCNI(ctx); err != nil {
-		conditions.MarkFalse(managedScope.InfraCluster(), infrav1.SecondaryCidrsReadyCondition, infrav1.SecondaryCidrReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(managedScope.InfraCluster(), infrav1.SecondaryCidrsReadyCondition, awserrors.ConditionReason(err, infrav1.SecondaryCidrReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile control plane for AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
 	}
 
 	if err := authService.ReconcileIAMAuthenticator(ctx); err != nil {
-		conditions.MarkFalse(awsManagedControlPlane, controlplanev1.IAMAuthenticatorConfiguredCondition, controlplanev1.IAMAuthenticatorConfigurationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(awsManagedControlPlane, controlplanev1.IAMAuthenticatorConfiguredCondition, awserrors.ConditionReason(err, controlplanev1.IAMAuthenticatorConfigurationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile aws-iam-authenticator config for AWSManagedControlPlane %s/%s", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name)
 	}
 	conditions.MarkTrue(awsManagedControlPlane, controlplanev1.IAMAuthenticatorConfiguredCondition)
@@ -248,6 +306,11 @@ func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context,
 		})
 	}
 
+	if bastionRequeue {
+		managedScope.Info("Waiting for the bastion auto scaling group to be deleted")
+		return reconcile.Result{RequeueAfter: bastionDeletionRequeueAfter}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -279,10 +342,15 @@ func (r *AWSManagedControlPlaneReconciler) reconcileDelete(ctx context.Context,
 		return reconcile.Result{}, err
 	}
 
-	if err := ec2svc.DeleteBastion(); err != nil {
+	requeue, err := ec2svc.DeleteBastion()
+	if err != nil {
 		log.Error(err, "error deleting bastion for AWSManagedControlPlane", "namespace", controlPlane.Namespace, "name", controlPlane.Name)
 		return reconcile.Result{}, err
 	}
+	if requeue {
+		log.Info("Waiting for the bastion auto scaling group to be deleted")
+		return reconcile.Result{RequeueAfter: bastionDeletionRequeueAfter}, nil
+	}
 
 	if err := sgService.DeleteSecurityGroups(); err != nil {
 		log.Error(err, "error deleting general security groups for AWSManagedControlPlane", "namespace", controlPlane.Namespace, "name", controlPlane.Name)
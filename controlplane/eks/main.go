@@ -106,6 +106,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
+	fs.DurationVar(&scope.MaxSessionDuration, "max-session-duration", 0,
+		"How long a cached AWS session is reused before it is recreated with fresh credentials. Set to 0 to reuse sessions until their identity changes (e.g. 1h)")
+
+	fs.DurationVar(&record.DefaultThrottleOptions.DedupWindow, "event-dedup-window", record.DefaultThrottleOptions.DedupWindow,
+		"How long identical events for an object are suppressed after one is recorded. Suppressed events are recorded once with a count when the window closes. Set to 0 to disable (e.g. 5m)")
+
+	fs.Float64Var(&record.DefaultThrottleOptions.PerObjectQPS, "event-qps-per-object", record.DefaultThrottleOptions.PerObjectQPS,
+		"The sustained rate at which events may be recorded for a single object. Set to 0 to disable rate limiting.")
+
+	fs.IntVar(&record.DefaultThrottleOptions.PerObjectBurst, "event-burst-per-object", record.DefaultThrottleOptions.PerObjectBurst,
+		"The number of events that may be recorded for a single object before event-qps-per-object applies.")
+
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port, disabled by default. When enabled, the manager will only work as webhook server, no reconcilers are installed.")
 
@@ -42,6 +42,23 @@ func (r *AWSMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	infrav1alpha3.RestoreAMIReference(&restored.Spec.AWSLaunchTemplate.AMI, &dst.Spec.AWSLaunchTemplate.AMI)
+	dst.Spec.AWSLaunchTemplate.GPU = restored.Spec.AWSLaunchTemplate.GPU
+	dst.Spec.AWSLaunchTemplate.Bottlerocket = restored.Spec.AWSLaunchTemplate.Bottlerocket
+	dst.Spec.AWSLaunchTemplate.PlacementGroupName = restored.Spec.AWSLaunchTemplate.PlacementGroupName
+	dst.Spec.AWSLaunchTemplate.Tenancy = restored.Spec.AWSLaunchTemplate.Tenancy
+	dst.Spec.AWSLaunchTemplate.CapacityReservation = restored.Spec.AWSLaunchTemplate.CapacityReservation
+	dst.Spec.AWSLaunchTemplate.LicenseConfigurationARNs = restored.Spec.AWSLaunchTemplate.LicenseConfigurationARNs
+	dst.Spec.AWSLaunchTemplate.VersionHistoryLimit = restored.Spec.AWSLaunchTemplate.VersionHistoryLimit
+	dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
+	dst.Spec.Backend = restored.Spec.Backend
+	if restored.Spec.RefreshPreferences != nil && dst.Spec.RefreshPreferences != nil {
+		dst.Spec.RefreshPreferences.MaxSurge = restored.Spec.RefreshPreferences.MaxSurge
+		dst.Spec.RefreshPreferences.MaxUnavailable = restored.Spec.RefreshPreferences.MaxUnavailable
+		dst.Spec.RefreshPreferences.CheckpointPercentages = restored.Spec.RefreshPreferences.CheckpointPercentages
+		dst.Spec.RefreshPreferences.CheckpointDelay = restored.Spec.RefreshPreferences.CheckpointDelay
+	}
+	dst.Status.InstanceDistribution = restored.Status.InstanceDistribution
+	dst.Status.InfrastructureMachineKind = restored.Status.InfrastructureMachineKind
 	return nil
 }
 
@@ -88,6 +105,7 @@ func (r *AWSManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.UpdateConfig = restored.Spec.UpdateConfig
 
 	return nil
 }
@@ -197,11 +215,36 @@ func Convert_v1alpha4_AWSResourceReference_To_v1alpha3_AWSResourceReference(in *
 	return infrav1alpha3.Convert_v1alpha4_AWSResourceReference_To_v1alpha3_AWSResourceReference(in, out, s)
 }
 
+// Convert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate is an autogenerated conversion function.
+func Convert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(in *infrav1alpha4exp.AWSLaunchTemplate, out *AWSLaunchTemplate, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(in, out, s)
+}
+
+// Convert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec is an autogenerated conversion function.
+func Convert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(in *infrav1alpha4exp.AWSMachinePoolSpec, out *AWSMachinePoolSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(in, out, s)
+}
+
+// Convert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus is an autogenerated conversion function.
+func Convert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(in *infrav1alpha4exp.AWSMachinePoolStatus, out *AWSMachinePoolStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(in, out, s)
+}
+
+// Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences is an autogenerated conversion function.
+func Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(in *infrav1alpha4exp.RefreshPreferences, out *RefreshPreferences, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(in, out, s)
+}
+
 // Convert_v1alpha4_AWSManagedMachinePoolSpec_To_v1alpha3_AWSManagedMachinePoolSpec is an autogenerated conversion function.
 func Convert_v1alpha4_AWSManagedMachinePoolSpec_To_v1alpha3_AWSManagedMachinePoolSpec(in *infrav1alpha4exp.AWSManagedMachinePoolSpec, out *AWSManagedMachinePoolSpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_AWSManagedMachinePoolSpec_To_v1alpha3_AWSManagedMachinePoolSpec(in, out, s)
 }
 
+// Convert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup is an autogenerated conversion function.
+func Convert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(in *infrav1alpha4exp.AutoScalingGroup, out *AutoScalingGroup, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(in, out, s)
+}
+
 // Convert_v1alpha4_Instance_To_v1alpha3_Instance is an autogenerated conversion function.
 func Convert_v1alpha4_Instance_To_v1alpha3_Instance(in *infrav1alpha4.Instance, out *infrav1alpha3.Instance, s apiconversion.Scope) error {
 	return infrav1alpha3.Convert_v1alpha4_Instance_To_v1alpha3_Instance(in, out, s)
@@ -65,11 +65,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSLaunchTemplate)(nil), (*AWSLaunchTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(a.(*v1alpha4.AWSLaunchTemplate), b.(*AWSLaunchTemplate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSMachinePool)(nil), (*v1alpha4.AWSMachinePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSMachinePool_To_v1alpha4_AWSMachinePool(a.(*AWSMachinePool), b.(*v1alpha4.AWSMachinePool), scope)
 	}); err != nil {
@@ -105,21 +100,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSMachinePoolSpec)(nil), (*AWSMachinePoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(a.(*v1alpha4.AWSMachinePoolSpec), b.(*AWSMachinePoolSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSMachinePoolStatus)(nil), (*v1alpha4.AWSMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSMachinePoolStatus_To_v1alpha4_AWSMachinePoolStatus(a.(*AWSMachinePoolStatus), b.(*v1alpha4.AWSMachinePoolStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AWSMachinePoolStatus)(nil), (*AWSMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(a.(*v1alpha4.AWSMachinePoolStatus), b.(*AWSMachinePoolStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSManagedCluster)(nil), (*v1alpha4.AWSManagedCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AWSManagedCluster_To_v1alpha4_AWSManagedCluster(a.(*AWSManagedCluster), b.(*v1alpha4.AWSManagedCluster), scope)
 	}); err != nil {
@@ -200,11 +185,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.AutoScalingGroup)(nil), (*AutoScalingGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(a.(*v1alpha4.AutoScalingGroup), b.(*AutoScalingGroup), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*BlockDeviceMapping)(nil), (*v1alpha4.BlockDeviceMapping)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_BlockDeviceMapping_To_v1alpha4_BlockDeviceMapping(a.(*BlockDeviceMapping), b.(*v1alpha4.BlockDeviceMapping), scope)
 	}); err != nil {
@@ -310,11 +290,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.RefreshPreferences)(nil), (*RefreshPreferences)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(a.(*v1alpha4.RefreshPreferences), b.(*RefreshPreferences), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddConversionFunc((*apiv1alpha3.APIEndpoint)(nil), (*apiv1alpha4.APIEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_APIEndpoint_To_v1alpha4_APIEndpoint(a.(*apiv1alpha3.APIEndpoint), b.(*apiv1alpha4.APIEndpoint), scope)
 	}); err != nil {
@@ -345,11 +320,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSLaunchTemplate)(nil), (*AWSLaunchTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(a.(*v1alpha4.AWSLaunchTemplate), b.(*AWSLaunchTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSMachinePoolSpec)(nil), (*AWSMachinePoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(a.(*v1alpha4.AWSMachinePoolSpec), b.(*AWSMachinePoolSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.AWSMachinePoolStatus)(nil), (*AWSMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(a.(*v1alpha4.AWSMachinePoolStatus), b.(*AWSMachinePoolStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha4.AWSManagedMachinePoolSpec)(nil), (*AWSManagedMachinePoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_AWSManagedMachinePoolSpec_To_v1alpha3_AWSManagedMachinePoolSpec(a.(*v1alpha4.AWSManagedMachinePoolSpec), b.(*AWSManagedMachinePoolSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.AutoScalingGroup)(nil), (*AutoScalingGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(a.(*v1alpha4.AutoScalingGroup), b.(*AutoScalingGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.RefreshPreferences)(nil), (*RefreshPreferences)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(a.(*v1alpha4.RefreshPreferences), b.(*RefreshPreferences), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*clusterapiproviderawsapiv1alpha4.AWSResourceReference)(nil), (*clusterapiproviderawsapiv1alpha3.AWSResourceReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_AWSResourceReference_To_v1alpha3_AWSResourceReference(a.(*clusterapiproviderawsapiv1alpha4.AWSResourceReference), b.(*clusterapiproviderawsapiv1alpha3.AWSResourceReference), scope)
 	}); err != nil {
@@ -459,6 +459,8 @@ func autoConvert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(in *v1
 	out.ImageLookupOrg = in.ImageLookupOrg
 	out.ImageLookupBaseOS = in.ImageLookupBaseOS
 	out.InstanceType = in.InstanceType
+	// WARNING: in.GPU requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bottlerocket requires manual conversion: does not exist in peer-type
 	out.RootVolume = (*clusterapiproviderawsapiv1alpha3.Volume)(unsafe.Pointer(in.RootVolume))
 	out.SSHKeyName = (*string)(unsafe.Pointer(in.SSHKeyName))
 	out.VersionNumber = (*int64)(unsafe.Pointer(in.VersionNumber))
@@ -473,14 +475,14 @@ func autoConvert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(in *v1
 	} else {
 		out.AdditionalSecurityGroups = nil
 	}
+	// WARNING: in.PlacementGroupName requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tenancy requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityReservation requires manual conversion: does not exist in peer-type
+	// WARNING: in.LicenseConfigurationARNs requires manual conversion: does not exist in peer-type
+	// WARNING: in.VersionHistoryLimit requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate is an autogenerated conversion function.
-func Convert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(in *v1alpha4.AWSLaunchTemplate, out *AWSLaunchTemplate, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSLaunchTemplate_To_v1alpha3_AWSLaunchTemplate(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSMachinePool_To_v1alpha4_AWSMachinePool(in *AWSMachinePool, out *v1alpha4.AWSMachinePool, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_AWSMachinePoolSpec_To_v1alpha4_AWSMachinePoolSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -600,7 +602,15 @@ func autoConvert_v1alpha3_AWSMachinePoolSpec_To_v1alpha4_AWSMachinePoolSpec(in *
 	out.MixedInstancesPolicy = (*v1alpha4.MixedInstancesPolicy)(unsafe.Pointer(in.MixedInstancesPolicy))
 	out.ProviderIDList = *(*[]string)(unsafe.Pointer(&in.ProviderIDList))
 	out.DefaultCoolDown = in.DefaultCoolDown
-	out.RefreshPreferences = (*v1alpha4.RefreshPreferences)(unsafe.Pointer(in.RefreshPreferences))
+	if in.RefreshPreferences != nil {
+		in, out := &in.RefreshPreferences, &out.RefreshPreferences
+		*out = new(v1alpha4.RefreshPreferences)
+		if err := Convert_v1alpha3_RefreshPreferences_To_v1alpha4_RefreshPreferences(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RefreshPreferences = nil
+	}
 	out.CapacityRebalance = in.CapacityRebalance
 	return nil
 }
@@ -633,16 +643,21 @@ func autoConvert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(in *
 	out.MixedInstancesPolicy = (*MixedInstancesPolicy)(unsafe.Pointer(in.MixedInstancesPolicy))
 	out.ProviderIDList = *(*[]string)(unsafe.Pointer(&in.ProviderIDList))
 	out.DefaultCoolDown = in.DefaultCoolDown
-	out.RefreshPreferences = (*RefreshPreferences)(unsafe.Pointer(in.RefreshPreferences))
+	if in.RefreshPreferences != nil {
+		in, out := &in.RefreshPreferences, &out.RefreshPreferences
+		*out = new(RefreshPreferences)
+		if err := Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RefreshPreferences = nil
+	}
 	out.CapacityRebalance = in.CapacityRebalance
+	// WARNING: in.NodeDrainTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.Backend requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec is an autogenerated conversion function.
-func Convert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(in *v1alpha4.AWSMachinePoolSpec, out *AWSMachinePoolSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSMachinePoolSpec_To_v1alpha3_AWSMachinePoolSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSMachinePoolStatus_To_v1alpha4_AWSMachinePoolStatus(in *AWSMachinePoolStatus, out *v1alpha4.AWSMachinePoolStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Replicas = in.Replicas
@@ -669,14 +684,11 @@ func autoConvert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.ASGStatus = (*ASGStatus)(unsafe.Pointer(in.ASGStatus))
+	// WARNING: in.InstanceDistribution requires manual conversion: does not exist in peer-type
+	// WARNING: in.InfrastructureMachineKind requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus is an autogenerated conversion function.
-func Convert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(in *v1alpha4.AWSMachinePoolStatus, out *AWSMachinePoolStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AWSMachinePoolStatus_To_v1alpha3_AWSMachinePoolStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_AWSManagedCluster_To_v1alpha4_AWSManagedCluster(in *AWSManagedCluster, out *v1alpha4.AWSManagedCluster, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_AWSManagedClusterSpec_To_v1alpha4_AWSManagedClusterSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -903,6 +915,7 @@ func autoConvert_v1alpha4_AWSManagedMachinePoolSpec_To_v1alpha3_AWSManagedMachin
 	out.AMIType = (*ManagedMachineAMIType)(unsafe.Pointer(in.AMIType))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
 	// WARNING: in.Taints requires manual conversion: does not exist in peer-type
+	// WARNING: in.UpdateConfig requires manual conversion: does not exist in peer-type
 	out.DiskSize = (*int32)(unsafe.Pointer(in.DiskSize))
 	out.InstanceType = (*string)(unsafe.Pointer(in.InstanceType))
 	out.Scaling = (*ManagedMachinePoolScaling)(unsafe.Pointer(in.Scaling))
@@ -995,14 +1008,11 @@ func autoConvert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(in *v1al
 	} else {
 		out.Instances = nil
 	}
+	// WARNING: in.ProtectedInstances requires manual conversion: does not exist in peer-type
+	// WARNING: in.TargetGroupARNs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup is an autogenerated conversion function.
-func Convert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(in *v1alpha4.AutoScalingGroup, out *AutoScalingGroup, s conversion.Scope) error {
-	return autoConvert_v1alpha4_AutoScalingGroup_To_v1alpha3_AutoScalingGroup(in, out, s)
-}
-
 func autoConvert_v1alpha3_BlockDeviceMapping_To_v1alpha4_BlockDeviceMapping(in *BlockDeviceMapping, out *v1alpha4.BlockDeviceMapping, s conversion.Scope) error {
 	out.DeviceName = in.DeviceName
 	if err := Convert_v1alpha3_EBS_To_v1alpha4_EBS(&in.Ebs, &out.Ebs, s); err != nil {
@@ -1261,10 +1271,9 @@ func autoConvert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(in *
 	out.Strategy = (*string)(unsafe.Pointer(in.Strategy))
 	out.InstanceWarmup = (*int64)(unsafe.Pointer(in.InstanceWarmup))
 	out.MinHealthyPercentage = (*int64)(unsafe.Pointer(in.MinHealthyPercentage))
+	// WARNING: in.MaxSurge requires manual conversion: does not exist in peer-type
+	// WARNING: in.MaxUnavailable requires manual conversion: does not exist in peer-type
+	// WARNING: in.CheckpointPercentages requires manual conversion: does not exist in peer-type
+	// WARNING: in.CheckpointDelay requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences is an autogenerated conversion function.
-func Convert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(in *v1alpha4.RefreshPreferences, out *RefreshPreferences, s conversion.Scope) error {
-	return autoConvert_v1alpha4_RefreshPreferences_To_v1alpha3_RefreshPreferences(in, out, s)
-}
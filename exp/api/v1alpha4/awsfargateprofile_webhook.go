@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/eks"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 )
 
 const (
@@ -36,16 +37,19 @@ const (
 
 // SetupWebhookWithManager will setup the webhooks for the AWSFargateProfile.
 func (r *AWSFargateProfile) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awsfargateprofile,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsfargateprofiles,versions=v1alpha4,name=default.awsfargateprofile.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsfargateprofile,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsfargateprofiles,versions=v1alpha4,name=validation.awsfargateprofile.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.Defaulter = &AWSFargateProfile{}
-var _ webhook.Validator = &AWSFargateProfile{}
+var _ webhooks.Validator = &AWSFargateProfile{}
 
 // Default will set default values for the AWSFargateProfile.
 func (r *AWSFargateProfile) Default() {
@@ -65,12 +69,12 @@ func (r *AWSFargateProfile) Default() {
 	}
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSFargateProfile) ValidateUpdate(oldObj runtime.Object) error {
+// ValidateUpdate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSFargateProfile) ValidateUpdate(oldObj runtime.Object) (webhooks.Warnings, error) {
 	gv := r.GroupVersionKind().GroupKind()
 	old, ok := oldObj.(*AWSFargateProfile)
 	if !ok {
-		return apierrors.NewInvalid(gv, r.Name, field.ErrorList{
+		return nil, apierrors.NewInvalid(gv, r.Name, field.ErrorList{
 			field.InternalError(nil, errors.Errorf("failed to convert old %s to object", gv.Kind)),
 		})
 	}
@@ -96,22 +100,22 @@ func (r *AWSFargateProfile) ValidateUpdate(oldObj runtime.Object) error {
 	}
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		gv,
 		r.Name,
 		allErrs,
 	)
 }
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSFargateProfile) ValidateCreate() error {
-	return nil
+// ValidateCreate implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSFargateProfile) ValidateCreate() (webhooks.Warnings, error) {
+	return nil, nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (r *AWSFargateProfile) ValidateDelete() error {
-	return nil
+// ValidateDelete implements webhooks.Validator so a webhook will be registered for the type.
+func (r *AWSFargateProfile) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
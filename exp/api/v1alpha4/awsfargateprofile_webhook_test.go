@@ -23,8 +23,8 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/eks"
+	utildefaulting "sigs.k8s.io/cluster-api-provider-aws/test/helpers/defaulting"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
 )
 
 func TestAWSFargateProfileDefault(t *testing.T) {
@@ -19,6 +19,7 @@ package v1alpha4
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
@@ -29,6 +30,14 @@ const (
 	// MachinePoolFinalizer is the finalizer for the machine pool.
 	MachinePoolFinalizer = "awsmachinepool.infrastructure.cluster.x-k8s.io"
 
+	// MachinePoolMachineFinalizer is the finalizer for the AWSMachines created for the instances of a machine pool.
+	// It lets the AWSMachinePool controller terminate the instance of such an AWSMachine when it is deleted.
+	MachinePoolMachineFinalizer = "awsmachinepool.infrastructure.cluster.x-k8s.io/machine"
+
+	// ScaleDownDisabledAnnotation is the cluster-autoscaler annotation on a node that keeps it from being scaled
+	// down. The instances of the Auto Scaling group whose node has it set to "true" are protected from scale-in.
+	ScaleDownDisabledAnnotation = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+
 	// LaunchTemplateLatestVersion defines the launching of the latest version of the template.
 	LaunchTemplateLatestVersion = "$Latest"
 )
@@ -85,6 +94,21 @@ type AWSMachinePoolSpec struct {
 	// Enable or disable the capacity rebalance autoscaling group feature
 	// +optional
 	CapacityRebalance bool `json:"capacityRebalance,omitempty"`
+
+	// NodeDrainTimeout enables draining the nodes of instances the ASG terminates, for example on
+	// scale-in. A lifecycle hook holds the instances in the Terminating:Wait state until their node
+	// is drained, or for at most this long. Must be between 30 seconds and 2 hours.
+	// Requires the EventBridgeInstanceState feature.
+	// +optional
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// Backend is the AWS service launching the instances of the pool. With EC2Fleet, the controller
+	// launches the instances with instant EC2 Fleets across the instance types of the mixed instances
+	// policy and the subnets of the pool, and tracks the capacity itself instead of an ASG.
+	// Defaults to AutoScalingGroup, and cannot be changed.
+	// +kubebuilder:validation:Enum=AutoScalingGroup;EC2Fleet
+	// +optional
+	Backend MachinePoolBackend `json:"backend,omitempty"`
 }
 
 // RefreshPreferences defines the specs for instance refreshing.
@@ -105,6 +129,31 @@ type RefreshPreferences struct {
 	// during an instance refresh. The default is 90.
 	// +optional
 	MinHealthyPercentage *int64 `json:"minHealthyPercentage,omitempty"`
+
+	// MaxSurge is the number or percentage of instances that can be launched above the
+	// desired capacity of the machine pool while an instance refresh is in progress.
+	// The desired capacity of the ASG is raised by this amount until the refresh finishes.
+	// Percentages are rounded up. Defaults to 0.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the number or percentage of the desired capacity of the machine pool
+	// that can be unhealthy while an instance refresh is in progress. It is used to compute
+	// the minimum healthy percentage of the instance refresh and cannot be set together with
+	// MinHealthyPercentage. Percentages are rounded down.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// CheckpointPercentages are the percentages of replaced instances at which the instance
+	// refresh pauses for CheckpointDelay, so the health of the new instances can be verified
+	// before the rollout continues. The percentages must be ascending and the last one must be 100.
+	// +optional
+	CheckpointPercentages []int64 `json:"checkpointPercentages,omitempty"`
+
+	// CheckpointDelay is the number of seconds the instance refresh pauses at each checkpoint.
+	// Defaults to one hour when checkpoints are set.
+	// +optional
+	CheckpointDelay *int64 `json:"checkpointDelay,omitempty"`
 }
 
 // AWSMachinePoolStatus defines the observed state of AWSMachinePool
@@ -167,6 +216,30 @@ type AWSMachinePoolStatus struct {
 	FailureMessage *string `json:"failureMessage,omitempty"`
 
 	ASGStatus *ASGStatus `json:"asgStatus,omitempty"`
+
+	// InstanceDistribution reports how the instances of the pool are spread across
+	// availability zones and purchase options.
+	// +optional
+	InstanceDistribution *InstanceDistributionStatus `json:"instanceDistribution,omitempty"`
+
+	// InfrastructureMachineKind is the kind of the infrastructure machines created for the instances of the pool,
+	// set when the MachinePoolMachines feature gate is enabled.
+	// +optional
+	InfrastructureMachineKind string `json:"infrastructureMachineKind,omitempty"`
+}
+
+// InstanceDistributionStatus defines the observed distribution of the instances of an AWSMachinePool.
+type InstanceDistributionStatus struct {
+	// AvailabilityZones maps each availability zone to the number of instances of the pool running in it.
+	// +optional
+	AvailabilityZones map[string]int32 `json:"availabilityZones,omitempty"`
+
+	// OnDemand is the number of on-demand instances of the pool.
+	// Instances launched from a scheduled reservation are counted as on-demand.
+	OnDemand int32 `json:"onDemand"`
+
+	// Spot is the number of spot instances of the pool.
+	Spot int32 `json:"spot"`
 }
 
 // AWSMachinePoolInstanceStatus defines the status of the AWSMachinePoolInstance.
@@ -23,8 +23,11 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -33,16 +36,19 @@ var log = logf.Log.WithName("awsmachinepool-resource")
 
 // SetupWebhookWithManager will setup the webhooks for the AWSMachinePool.
 func (r *AWSMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,versions=v1alpha4,name=validation.awsmachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmachinepool,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,versions=v1alpha4,name=default.awsmachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.Defaulter = &AWSMachinePool{}
-var _ webhook.Validator = &AWSMachinePool{}
+var _ webhooks.Validator = &AWSMachinePool{}
 
 func (r *AWSMachinePool) validateDefaultCoolDown() field.ErrorList {
 	var allErrs field.ErrorList
@@ -55,8 +61,131 @@ func (r *AWSMachinePool) validateDefaultCoolDown() field.ErrorList {
 	return allErrs
 }
 
+func (r *AWSMachinePool) validateCapacityReservation() field.ErrorList {
+	var allErrs field.ErrorList
+	reservation := r.Spec.AWSLaunchTemplate.CapacityReservation
+	if reservation == nil {
+		return allErrs
+	}
+
+	set := 0
+	if reservation.Preference != "" {
+		set++
+	}
+	if reservation.ID != nil {
+		set++
+	}
+	if reservation.ResourceGroupARN != nil {
+		set++
+	}
+	if set > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "awsLaunchTemplate", "capacityReservation"), reservation,
+			"only one of preference, id and resourceGroupARN can be set"))
+	}
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateRefreshPreferences() field.ErrorList {
+	var allErrs field.ErrorList
+	prefs := r.Spec.RefreshPreferences
+	if prefs == nil {
+		return allErrs
+	}
+	fldPath := field.NewPath("spec", "refreshPreferences")
+
+	if prefs.MaxUnavailable != nil && prefs.MinHealthyPercentage != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("maxUnavailable"), "maxUnavailable cannot be set together with minHealthyPercentage"))
+	}
+	surge, surgeErrs := validateIntOrPercent(prefs.MaxSurge, fldPath.Child("maxSurge"))
+	allErrs = append(allErrs, surgeErrs...)
+	unavailable, unavailableErrs := validateIntOrPercent(prefs.MaxUnavailable, fldPath.Child("maxUnavailable"))
+	allErrs = append(allErrs, unavailableErrs...)
+	if prefs.MaxUnavailable != nil && len(surgeErrs) == 0 && len(unavailableErrs) == 0 && surge == 0 && unavailable == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), prefs.MaxUnavailable.String(), "maxUnavailable cannot be 0 when maxSurge is 0"))
+	}
+
+	previous := int64(0)
+	for i, p := range prefs.CheckpointPercentages {
+		if p <= previous || p > 100 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("checkpointPercentages").Index(i), p, "checkpoint percentages must be ascending and between 1 and 100"))
+		}
+		previous = p
+	}
+	if n := len(prefs.CheckpointPercentages); n > 0 && prefs.CheckpointPercentages[n-1] != 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("checkpointPercentages"), prefs.CheckpointPercentages, "the last checkpoint percentage must be 100"))
+	}
+	if prefs.CheckpointDelay != nil {
+		if len(prefs.CheckpointPercentages) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("checkpointDelay"), "checkpointDelay requires checkpointPercentages"))
+		}
+		if *prefs.CheckpointDelay < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("checkpointDelay"), *prefs.CheckpointDelay, "checkpointDelay must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateNodeDrainTimeout() field.ErrorList {
+	var allErrs field.ErrorList
+	timeout := r.Spec.NodeDrainTimeout
+	if timeout == nil {
+		return allErrs
+	}
+	// The drain timeout is the heartbeat timeout of the lifecycle hook, which must be within this range.
+	if timeout.Duration < 30*time.Second || timeout.Duration > 2*time.Hour {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "nodeDrainTimeout"), timeout.Duration.String(), "must be between 30s and 2h"))
+	}
+	return allErrs
+}
+
+// validateBackend rejects the options implemented with the Auto Scaling group when the pool uses EC2 Fleet.
+func (r *AWSMachinePool) validateBackend() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.Backend != MachinePoolBackendEC2Fleet {
+		return allErrs
+	}
+	fldPath := field.NewPath("spec")
+	if r.Spec.NodeDrainTimeout != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeDrainTimeout"), "nodeDrainTimeout is not supported with the EC2Fleet backend"))
+	}
+	if r.Spec.RefreshPreferences != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("refreshPreferences"), "refreshPreferences is not supported with the EC2Fleet backend"))
+	}
+	if r.Spec.CapacityRebalance {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("capacityRebalance"), "capacityRebalance is not supported with the EC2Fleet backend"))
+	}
+	return allErrs
+}
+
+// validateOSFamily validates the options of the launch template which depend on the OS family of its AMI.
+func (r *AWSMachinePool) validateOSFamily() field.ErrorList {
+	lt := r.Spec.AWSLaunchTemplate
+	fldPath := field.NewPath("spec", "awsLaunchTemplate")
+	allErrs := infrav1.ValidateOSFamily(&lt.AMI, lt.ImageLookupFormat, lt.ImageLookupOrg, lt.ImageLookupBaseOS, fldPath)
+	return append(allErrs, infrav1.ValidateBottlerocketSettings(lt.Bottlerocket, lt.AMI.OSFamily, fldPath.Child("bottlerocket"))...)
+}
+
+// validateIntOrPercent validates a non-negative number or a percentage of at most 100%, and returns
+// its value scaled to a total of 100.
+func validateIntOrPercent(v *intstr.IntOrString, fldPath *field.Path) (int, field.ErrorList) {
+	if v == nil {
+		return 0, nil
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(v, 100, true)
+	if err != nil {
+		return 0, field.ErrorList{field.Invalid(fldPath, v.String(), "must be an integer or a percentage")}
+	}
+	if value < 0 {
+		return 0, field.ErrorList{field.Invalid(fldPath, v.String(), "must not be negative")}
+	}
+	if v.Type == intstr.String && value > 100 {
+		return 0, field.ErrorList{field.Invalid(fldPath, v.String(), "must not be greater than 100%")}
+	}
+	return value, nil
+}
+
 // ValidateCreate will do any extra validation when creating a AWSMachinePool.
-func (r *AWSMachinePool) ValidateCreate() error {
+func (r *AWSMachinePool) ValidateCreate() (webhooks.Warnings, error) {
 	log.Info("AWSMachinePool validate create", "name", r.Name)
 
 	var allErrs field.ErrorList
@@ -64,12 +193,17 @@ func (r *AWSMachinePool) ValidateCreate() error {
 	if errs := r.validateDefaultCoolDown(); errs != nil || len(errs) == 0 {
 		allErrs = append(allErrs, errs...)
 	}
+	allErrs = append(allErrs, r.validateCapacityReservation()...)
+	allErrs = append(allErrs, r.validateRefreshPreferences()...)
+	allErrs = append(allErrs, r.validateNodeDrainTimeout()...)
+	allErrs = append(allErrs, r.validateBackend()...)
+	allErrs = append(allErrs, r.validateOSFamily()...)
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		r.GroupVersionKind().GroupKind(),
 		r.Name,
 		allErrs,
@@ -77,17 +211,27 @@ func (r *AWSMachinePool) ValidateCreate() error {
 }
 
 // ValidateUpdate will do any extra validation when updating a AWSMachinePool.
-func (r *AWSMachinePool) ValidateUpdate(old runtime.Object) error {
+func (r *AWSMachinePool) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	var allErrs field.ErrorList
 	if errs := r.validateDefaultCoolDown(); errs != nil || len(errs) == 0 {
 		allErrs = append(allErrs, errs...)
 	}
+	allErrs = append(allErrs, r.validateCapacityReservation()...)
+	allErrs = append(allErrs, r.validateRefreshPreferences()...)
+	allErrs = append(allErrs, r.validateNodeDrainTimeout()...)
+	allErrs = append(allErrs, r.validateBackend()...)
+	allErrs = append(allErrs, r.validateOSFamily()...)
+
+	oldPool := old.(*AWSMachinePool)
+	if oldPool.Spec.Backend.OrDefault() != r.Spec.Backend.OrDefault() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "backend"), r.Spec.Backend, "field is immutable"))
+	}
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		r.GroupVersionKind().GroupKind(),
 		r.Name,
 		allErrs,
@@ -95,8 +239,8 @@ func (r *AWSMachinePool) ValidateUpdate(old runtime.Object) error {
 }
 
 // ValidateDelete allows you to add any extra validation when deleting.
-func (r *AWSMachinePool) ValidateDelete() error {
-	return nil
+func (r *AWSMachinePool) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
 
 // Default will set default values for the AWSMachinePool.
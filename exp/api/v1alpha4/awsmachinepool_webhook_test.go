@@ -18,11 +18,15 @@ package v1alpha4
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	utildefaulting "sigs.k8s.io/cluster-api-provider-aws/test/helpers/defaulting"
 )
 
 func TestAWSMachinePoolDefault(t *testing.T) {
@@ -32,3 +36,288 @@ func TestAWSMachinePoolDefault(t *testing.T) {
 	g := NewWithT(t)
 	g.Expect(m.Spec.DefaultCoolDown.Duration).To(BeNumerically(">=", 0))
 }
+
+func TestAWSMachinePool_CapacityReservation(t *testing.T) {
+	tests := []struct {
+		name        string
+		reservation *CapacityReservationSpecification
+		wantErr     bool
+	}{
+		{
+			name:        "accepted with a preference",
+			reservation: &CapacityReservationSpecification{Preference: CapacityReservationPreferenceNone},
+		},
+		{
+			name:        "accepted with a capacity reservation",
+			reservation: &CapacityReservationSpecification{ID: aws.String("cr-0123456789abcdef0")},
+		},
+		{
+			name: "rejected with a preference and a capacity reservation",
+			reservation: &CapacityReservationSpecification{
+				Preference: CapacityReservationPreferenceOpen,
+				ID:         aws.String("cr-0123456789abcdef0"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{AWSLaunchTemplate: AWSLaunchTemplate{CapacityReservation: tt.reservation}}}
+			_, err := pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachinePool_RefreshPreferences(t *testing.T) {
+	surge := intstr.FromString("25%")
+	zero := intstr.FromInt(0)
+	tooLarge := intstr.FromString("150%")
+
+	tests := []struct {
+		name    string
+		prefs   *RefreshPreferences
+		wantErr bool
+	}{
+		{
+			name: "accepted with surge, unavailability and checkpoints",
+			prefs: &RefreshPreferences{
+				MaxSurge:              &surge,
+				MaxUnavailable:        &zero,
+				CheckpointPercentages: []int64{20, 50, 100},
+				CheckpointDelay:       aws.Int64(600),
+			},
+		},
+		{
+			name:    "rejected with maxUnavailable and minHealthyPercentage",
+			prefs:   &RefreshPreferences{MaxUnavailable: &surge, MinHealthyPercentage: aws.Int64(90)},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with zero surge and zero unavailability",
+			prefs:   &RefreshPreferences{MaxUnavailable: &zero},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with a percentage above 100",
+			prefs:   &RefreshPreferences{MaxUnavailable: &tooLarge},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with checkpoints not ending at 100",
+			prefs:   &RefreshPreferences{CheckpointPercentages: []int64{20, 50}},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with descending checkpoints",
+			prefs:   &RefreshPreferences{CheckpointPercentages: []int64{50, 20, 100}},
+			wantErr: true,
+		},
+		{
+			name:    "rejected with a checkpoint delay without checkpoints",
+			prefs:   &RefreshPreferences{CheckpointDelay: aws.Int64(600)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{RefreshPreferences: tt.prefs}}
+			_, err := pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachinePool_NodeDrainTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout *metav1.Duration
+		wantErr bool
+	}{
+		{
+			name:    "accepted within the lifecycle hook heartbeat range",
+			timeout: &metav1.Duration{Duration: 10 * time.Minute},
+		},
+		{
+			name:    "rejected below 30 seconds",
+			timeout: &metav1.Duration{Duration: 10 * time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "rejected above 2 hours",
+			timeout: &metav1.Duration{Duration: 3 * time.Hour},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{NodeDrainTimeout: tt.timeout}}
+			_, err := pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachinePool_Backend(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    AWSMachinePoolSpec
+		wantErr bool
+	}{
+		{
+			name: "accepts the EC2Fleet backend",
+			spec: AWSMachinePoolSpec{Backend: MachinePoolBackendEC2Fleet},
+		},
+		{
+			name: "accepts a drain timeout with the AutoScalingGroup backend",
+			spec: AWSMachinePoolSpec{
+				Backend:          MachinePoolBackendAutoScalingGroup,
+				NodeDrainTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		{
+			name: "rejects a drain timeout with the EC2Fleet backend",
+			spec: AWSMachinePoolSpec{
+				Backend:          MachinePoolBackendEC2Fleet,
+				NodeDrainTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects refresh preferences with the EC2Fleet backend",
+			spec: AWSMachinePoolSpec{
+				Backend:            MachinePoolBackendEC2Fleet,
+				RefreshPreferences: &RefreshPreferences{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects capacity rebalance with the EC2Fleet backend",
+			spec: AWSMachinePoolSpec{
+				Backend:           MachinePoolBackendEC2Fleet,
+				CapacityRebalance: true,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: tt.spec}
+			_, err := pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachinePool_OSFamily(t *testing.T) {
+	tests := []struct {
+		name    string
+		lt      AWSLaunchTemplate
+		wantErr bool
+	}{
+		{
+			name: "accepts Bottlerocket",
+			lt:   AWSLaunchTemplate{AMI: infrav1.AMIReference{OSFamily: infrav1.OSFamilyBottlerocket}},
+		},
+		{
+			name:    "rejects Bottlerocket with an image lookup org",
+			lt:      AWSLaunchTemplate{AMI: infrav1.AMIReference{OSFamily: infrav1.OSFamilyBottlerocket}, ImageLookupOrg: "123456789012"},
+			wantErr: true,
+		},
+		{
+			name:    "rejects an OS family with an AMI ID",
+			lt:      AWSLaunchTemplate{AMI: infrav1.AMIReference{ID: aws.String("ami-1"), OSFamily: infrav1.OSFamilyAmazonLinux2}},
+			wantErr: true,
+		},
+		{
+			name: "accepts Bottlerocket settings",
+			lt: AWSLaunchTemplate{
+				AMI:          infrav1.AMIReference{OSFamily: infrav1.OSFamilyBottlerocket},
+				Bottlerocket: &infrav1.BottlerocketSettings{Sysctls: map[string]string{"vm.max_map_count": "262144"}},
+			},
+		},
+		{
+			name: "rejects Bottlerocket settings without the Bottlerocket OS family",
+			lt: AWSLaunchTemplate{
+				Bottlerocket: &infrav1.BottlerocketSettings{AdminContainer: true},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{AWSLaunchTemplate: tt.lt}}
+			_, err := pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAWSMachinePool_BackendImmutable(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldBackend MachinePoolBackend
+		newBackend MachinePoolBackend
+		wantErr    bool
+	}{
+		{
+			name:       "accepts setting the default backend",
+			newBackend: MachinePoolBackendAutoScalingGroup,
+		},
+		{
+			name:       "rejects switching to the EC2Fleet backend",
+			newBackend: MachinePoolBackendEC2Fleet,
+			wantErr:    true,
+		},
+		{
+			name:       "rejects switching to the AutoScalingGroup backend",
+			oldBackend: MachinePoolBackendEC2Fleet,
+			newBackend: MachinePoolBackendAutoScalingGroup,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			oldPool := &AWSMachinePool{Spec: AWSMachinePoolSpec{Backend: tt.oldBackend}}
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{Backend: tt.newBackend}}
+			_, err := pool.ValidateUpdate(oldPool)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -18,38 +18,42 @@ package v1alpha4
 
 import (
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 // SetupWebhookWithManager will setup the webhooks for the AWSManagedCluster.
 func (r *AWSManagedCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmanagedcluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmanagedclusters,versions=v1alpha4,name=default.awsmanagedcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmanagedcluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmanagedclusters,versions=v1alpha4,name=validation.awsmanagedcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.Defaulter = &AWSManagedCluster{}
-var _ webhook.Validator = &AWSManagedCluster{}
+var _ webhooks.Validator = &AWSManagedCluster{}
 
 // Default will set default values for the AWSManagedCluster.
 func (r *AWSManagedCluster) Default() {
 }
 
 // ValidateCreate will do any extra validation when creating a AWSManagedCluster.
-func (r *AWSManagedCluster) ValidateCreate() error {
-	return nil
+func (r *AWSManagedCluster) ValidateCreate() (webhooks.Warnings, error) {
+	return nil, nil
 }
 
 // ValidateUpdate will do any extra validation when updating a AWSManagedCluster.
-func (r *AWSManagedCluster) ValidateUpdate(old runtime.Object) error {
-	return nil
+func (r *AWSManagedCluster) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
+	return nil, nil
 }
 
 // ValidateDelete allows you to add any extra validation when deleting.
-func (r *AWSManagedCluster) ValidateDelete() error {
-	return nil
+func (r *AWSManagedCluster) ValidateDelete() (webhooks.Warnings, error) {
+	return nil, nil
 }
@@ -112,6 +112,11 @@ type AWSManagedMachinePoolSpec struct {
 	// +optional
 	Scaling *ManagedMachinePoolScaling `json:"scaling,omitempty"`
 
+	// UpdateConfig controls how many nodes of the node group can be unavailable
+	// while EKS rolls out an update of the node group
+	// +optional
+	UpdateConfig *UpdateConfig `json:"updateConfig,omitempty"`
+
 	// RemoteAccess specifies how machines can be accessed remotely
 	// +optional
 	RemoteAccess *ManagedRemoteAccess `json:"remoteAccess,omitempty"`
@@ -129,6 +134,24 @@ type ManagedMachinePoolScaling struct {
 	MaxSize *int32 `json:"maxSize,omitempty"`
 }
 
+// UpdateConfig specifies the update configuration of a managed node group.
+// Only one of MaxUnavailable and MaxUnavailablePercentage can be set.
+type UpdateConfig struct {
+	// MaxUnavailable is the maximum number of nodes that can be unavailable
+	// at once during an update. Nodes are updated in parallel, up to 100 at once.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+
+	// MaxUnavailablePercentage is the maximum percentage of nodes that can be
+	// unavailable at once during an update, up to 100 nodes at once.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxUnavailablePercentage *int32 `json:"maxUnavailablePercentage,omitempty"`
+}
+
 // ManagedRemoteAccess specifies remote access settings for EC2 instances.
 type ManagedRemoteAccess struct {
 	// SSHKeyName specifies which EC2 SSH key can be used to access machines.
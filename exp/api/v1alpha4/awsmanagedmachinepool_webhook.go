@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/eks"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
 )
 
 const (
@@ -42,16 +43,19 @@ var mmpLog = logf.Log.WithName("awsmanagedmachinepool-resource")
 
 // SetupWebhookWithManager will setup the webhooks for the AWSManagedMachinePool.
 func (r *AWSManagedMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		Complete()
+		Complete(); err != nil {
+		return err
+	}
+	return webhooks.RegisterValidator(mgr, r)
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmanagedmachinepools,versions=v1alpha4,name=validation.awsmanagedmachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awsmanagedmachinepool,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmanagedmachinepools,versions=v1alpha4,name=default.awsmanagedmachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.Defaulter = &AWSManagedMachinePool{}
-var _ webhook.Validator = &AWSManagedMachinePool{}
+var _ webhooks.Validator = &AWSManagedMachinePool{}
 
 func (r *AWSManagedMachinePool) validateScaling() field.ErrorList {
 	var allErrs field.ErrorList
@@ -96,8 +100,26 @@ func (r *AWSManagedMachinePool) validateRemoteAccess() field.ErrorList {
 	return allErrs
 }
 
+func (r *AWSManagedMachinePool) validateUpdateConfig() field.ErrorList {
+	var allErrs field.ErrorList
+	if r.Spec.UpdateConfig == nil {
+		return allErrs
+	}
+	updateConfigPath := field.NewPath("spec", "updateConfig")
+	updateConfig := r.Spec.UpdateConfig
+
+	if updateConfig.MaxUnavailable != nil && updateConfig.MaxUnavailablePercentage != nil {
+		allErrs = append(
+			allErrs,
+			field.Invalid(updateConfigPath.Child("maxUnavailablePercentage"), *updateConfig.MaxUnavailablePercentage, "cannot be set together with maxUnavailable"),
+		)
+	}
+
+	return allErrs
+}
+
 // ValidateCreate will do any extra validation when creating a AWSManagedMachinePool.
-func (r *AWSManagedMachinePool) ValidateCreate() error {
+func (r *AWSManagedMachinePool) ValidateCreate() (webhooks.Warnings, error) {
 	mmpLog.Info("AWSManagedMachinePool validate create", "name", r.Name)
 
 	var allErrs field.ErrorList
@@ -111,12 +133,15 @@ func (r *AWSManagedMachinePool) ValidateCreate() error {
 	if errs := r.validateRemoteAccess(); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
+	if errs := r.validateUpdateConfig(); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		r.GroupVersionKind().GroupKind(),
 		r.Name,
 		allErrs,
@@ -124,11 +149,11 @@ func (r *AWSManagedMachinePool) ValidateCreate() error {
 }
 
 // ValidateUpdate will do any extra validation when updating a AWSManagedMachinePool.
-func (r *AWSManagedMachinePool) ValidateUpdate(old runtime.Object) error {
+func (r *AWSManagedMachinePool) ValidateUpdate(old runtime.Object) (webhooks.Warnings, error) {
 	mmpLog.Info("AWSManagedMachinePool validate update", "name", r.Name)
 	oldPool, ok := old.(*AWSManagedMachinePool)
 	if !ok {
-		return apierrors.NewInvalid(GroupVersion.WithKind("AWSManagedMachinePool").GroupKind(), r.Name, field.ErrorList{
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AWSManagedMachinePool").GroupKind(), r.Name, field.ErrorList{
 			field.InternalError(nil, errors.New("failed to convert old AWSManagedMachinePool to object")),
 		})
 	}
@@ -139,12 +164,15 @@ func (r *AWSManagedMachinePool) ValidateUpdate(old runtime.Object) error {
 	if errs := r.validateScaling(); errs != nil || len(errs) == 0 {
 		allErrs = append(allErrs, errs...)
 	}
+	if errs := r.validateUpdateConfig(); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
 
 	if len(allErrs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return apierrors.NewInvalid(
+	return nil, apierrors.NewInvalid(
 		r.GroupVersionKind().GroupKind(),
 		r.Name,
 		allErrs,
@@ -152,10 +180,10 @@ func (r *AWSManagedMachinePool) ValidateUpdate(old runtime.Object) error {
 }
 
 // ValidateDelete allows you to add any extra validation when deleting.
-func (r *AWSManagedMachinePool) ValidateDelete() error {
+func (r *AWSManagedMachinePool) ValidateDelete() (webhooks.Warnings, error) {
 	mmpLog.Info("AWSManagedMachinePool validate delete", "name", r.Name)
 
-	return nil
+	return nil, nil
 }
 
 func (r *AWSManagedMachinePool) validateImmutable(old *AWSManagedMachinePool) field.ErrorList {
@@ -19,8 +19,11 @@ package v1alpha4
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/gomega"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	utildefaulting "sigs.k8s.io/cluster-api-provider-aws/test/helpers/defaulting"
 )
 
 func TestAWSManagedMachinePoolDefault(t *testing.T) {
@@ -28,3 +31,38 @@ func TestAWSManagedMachinePoolDefault(t *testing.T) {
 	t.Run("for AWSManagedMachinePool", utildefaulting.DefaultValidateTest(fargate))
 	fargate.Default()
 }
+
+func TestAWSManagedMachinePool_UpdateConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		updateConfig *UpdateConfig
+		wantErr      bool
+	}{
+		{
+			name:         "accepted with maxUnavailable",
+			updateConfig: &UpdateConfig{MaxUnavailable: aws.Int32(2)},
+		},
+		{
+			name:         "accepted with maxUnavailablePercentage",
+			updateConfig: &UpdateConfig{MaxUnavailablePercentage: aws.Int32(25)},
+		},
+		{
+			name:         "rejected with maxUnavailable and maxUnavailablePercentage",
+			updateConfig: &UpdateConfig{MaxUnavailable: aws.Int32(2), MaxUnavailablePercentage: aws.Int32(25)},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSManagedMachinePool{Spec: AWSManagedMachinePoolSpec{EKSNodegroupName: "eks-node-group", UpdateConfig: tt.updateConfig}}
+			_, err := pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -42,6 +42,14 @@ const (
 	InstanceRefreshNotReadyReason = "InstanceRefreshNotReady"
 	// InstanceRefreshFailedReason used to report when there instance refresh is not initiated.
 	InstanceRefreshFailedReason = "InstanceRefreshFailed"
+
+	// FleetReadyCondition reports whether the instances launched with EC2 Fleet for an AWSMachinePool
+	// using the EC2Fleet backend match its desired replicas.
+	FleetReadyCondition clusterv1.ConditionType = "FleetReady"
+	// FleetCapacityPendingReason used while instances are launched or terminated to match the desired replicas.
+	FleetCapacityPendingReason = "FleetCapacityPending"
+	// FleetLaunchFailedReason used when EC2 Fleet could not launch any instance.
+	FleetLaunchFailedReason = "FleetLaunchFailed"
 )
 
 const (
@@ -52,6 +60,16 @@ const (
 	// WaitingForEKSControlPlaneReason used when the machine pool is waiting for
 	// EKS control plane infrastructure to be ready before proceeding.
 	WaitingForEKSControlPlaneReason = "WaitingForEKSControlPlane"
+	// EKSNodegroupConfigSyncedCondition condition reports on whether the labels, taints and scaling configuration
+	// of the EKS node group match the AWSManagedMachinePool.
+	EKSNodegroupConfigSyncedCondition clusterv1.ConditionType = "EKSNodegroupConfigSynced"
+	// EKSNodegroupConfigUpdatingReason used while an update of the node group configuration is in progress.
+	EKSNodegroupConfigUpdatingReason = "EKSNodegroupConfigUpdating"
+	// EKSNodegroupConfigUpdateFailedReason used to report failures while updating the node group configuration.
+	EKSNodegroupConfigUpdateFailedReason = "EKSNodegroupConfigUpdateFailed"
+	// EKSNodegroupVersionUpdatingReason used when the configuration update of the node group waits
+	// for its version update to complete, as EKS only runs one update of a node group at a time.
+	EKSNodegroupVersionUpdatingReason = "EKSNodegroupVersionUpdating"
 )
 
 const (
@@ -91,6 +91,19 @@ type AWSLaunchTemplate struct {
 	// InstanceType is the type of instance to create. Example: m4.xlarge
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// GPU prepares the instance for the GPUs of its instance type. On EKS managed
+	// clusters the GPU variant of the EKS optimized AMI is looked up, unless
+	// ami.eksLookupType is set. A script installing the driver, the container
+	// runtime hook and the device plugin is appended to the bootstrap data.
+	// +kubebuilder:validation:Enum:=nvidia
+	// +optional
+	GPU infrav1.GPUVendor `json:"gpu,omitempty"`
+
+	// Bottlerocket are the settings rendered into the TOML user data of the instances.
+	// Only valid together with the Bottlerocket OS family.
+	// +optional
+	Bottlerocket *infrav1.BottlerocketSettings `json:"bottlerocket,omitempty"`
+
 	// RootVolume encapsulates the configuration options for the root volume
 	// +optional
 	RootVolume *infrav1.Volume `json:"rootVolume,omitempty"`
@@ -112,6 +125,66 @@ type AWSLaunchTemplate struct {
 	// at the cluster level or in the actuator.
 	// +optional
 	AdditionalSecurityGroups []infrav1.AWSResourceReference `json:"additionalSecurityGroups,omitempty"`
+
+	// PlacementGroupName is the name of the placement group to launch the instances in.
+	// +optional
+	PlacementGroupName string `json:"placementGroupName,omitempty"`
+
+	// Tenancy indicates if the instances should run on shared or single-tenant hardware.
+	// +optional
+	// +kubebuilder:validation:Enum:=default;dedicated;host
+	Tenancy string `json:"tenancy,omitempty"`
+
+	// CapacityReservation selects the capacity reservations the instances are launched in.
+	// +optional
+	CapacityReservation *CapacityReservationSpecification `json:"capacityReservation,omitempty"`
+
+	// LicenseConfigurationARNs are the ARNs of the License Manager license configurations to
+	// associate with the instances, to track the licenses they use.
+	// +optional
+	LicenseConfigurationARNs []string `json:"licenseConfigurationARNs,omitempty"`
+
+	// VersionHistoryLimit is the number of old launch template versions to retain, besides
+	// the latest version that is in use and the default version, which are never deleted.
+	// Older versions are pruned before a new version is created. Defaults to 1.
+	// EC2 allows at most 5000 versions per launch template.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4997
+	// +optional
+	VersionHistoryLimit *int32 `json:"versionHistoryLimit,omitempty"`
+}
+
+// DefaultLaunchTemplateVersionHistoryLimit is the number of old launch template versions retained
+// when VersionHistoryLimit is not set.
+const DefaultLaunchTemplateVersionHistoryLimit = 1
+
+// CapacityReservationPreference defines whether instances may run in any open capacity reservation.
+type CapacityReservationPreference string
+
+var (
+	// CapacityReservationPreferenceOpen runs instances in any open capacity reservation that has matching
+	// attributes, and otherwise as on-demand instances.
+	CapacityReservationPreferenceOpen = CapacityReservationPreference("open")
+
+	// CapacityReservationPreferenceNone never runs instances in a capacity reservation.
+	CapacityReservationPreferenceNone = CapacityReservationPreference("none")
+)
+
+// CapacityReservationSpecification selects the capacity reservations instances are launched in. Either
+// a preference, a capacity reservation or a capacity reservation group can be given.
+type CapacityReservationSpecification struct {
+	// Preference defines whether instances may run in any open capacity reservation. Defaults to open.
+	// +optional
+	// +kubebuilder:validation:Enum=open;none
+	Preference CapacityReservationPreference `json:"preference,omitempty"`
+
+	// ID is the ID of the capacity reservation to launch the instances in.
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// ResourceGroupARN is the ARN of the capacity reservation group to launch the instances in.
+	// +optional
+	ResourceGroupARN *string `json:"resourceGroupARN,omitempty"`
 }
 
 // Overrides are used to override the instance type specified by the launch template with multiple
@@ -186,6 +259,31 @@ type AutoScalingGroup struct {
 	MixedInstancesPolicy *MixedInstancesPolicy `json:"mixedInstancesPolicy,omitempty"`
 	Status               ASGStatus
 	Instances            []infrav1.Instance `json:"instances,omitempty"`
+
+	// ProtectedInstances are the IDs of the instances of the group protected from scale-in.
+	ProtectedInstances []string `json:"protectedInstances,omitempty"`
+
+	// TargetGroupARNs are the ARNs of the load balancer target groups the instances of the group are registered with.
+	TargetGroupARNs []string `json:"targetGroupARNs,omitempty"`
+}
+
+// MachinePoolBackend is the AWS service launching the instances of an AWSMachinePool.
+type MachinePoolBackend string
+
+var (
+	// MachinePoolBackendAutoScalingGroup manages the instances of the pool with an Auto Scaling group.
+	MachinePoolBackendAutoScalingGroup = MachinePoolBackend("AutoScalingGroup")
+
+	// MachinePoolBackendEC2Fleet launches the instances of the pool with instant EC2 Fleets.
+	MachinePoolBackendEC2Fleet = MachinePoolBackend("EC2Fleet")
+)
+
+// OrDefault returns the backend, or the Auto Scaling group backend if none is set.
+func (b MachinePoolBackend) OrDefault() MachinePoolBackend {
+	if b == "" {
+		return MachinePoolBackendAutoScalingGroup
+	}
+	return b
 }
 
 // ASGStatus is a status string returned by the autoscaling API
@@ -250,3 +348,14 @@ func (t *Taints) Contains(taint *Taint) bool {
 
 	return false
 }
+
+// ContainsKeyEffect checks for existence of a taint with the same key and effect, whatever its value.
+func (t *Taints) ContainsKeyEffect(taint *Taint) bool {
+	for _, t := range *t {
+		if t.Key == taint.Key && t.Effect == taint.Effect {
+			return true
+		}
+	}
+
+	return false
+}
@@ -21,7 +21,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apiv1alpha4 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	cluster_apiapiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
@@ -90,6 +92,11 @@ func (in *AWSFargateProfileList) DeepCopyObject() runtime.Object {
 func (in *AWSLaunchTemplate) DeepCopyInto(out *AWSLaunchTemplate) {
 	*out = *in
 	in.AMI.DeepCopyInto(&out.AMI)
+	if in.Bottlerocket != nil {
+		in, out := &in.Bottlerocket, &out.Bottlerocket
+		*out = new(apiv1alpha4.BottlerocketSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RootVolume != nil {
 		in, out := &in.RootVolume, &out.RootVolume
 		*out = new(apiv1alpha4.Volume)
@@ -112,6 +119,21 @@ func (in *AWSLaunchTemplate) DeepCopyInto(out *AWSLaunchTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(CapacityReservationSpecification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LicenseConfigurationARNs != nil {
+		in, out := &in.LicenseConfigurationARNs, &out.LicenseConfigurationARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VersionHistoryLimit != nil {
+		in, out := &in.VersionHistoryLimit, &out.VersionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLaunchTemplate.
@@ -242,6 +264,11 @@ func (in *AWSMachinePoolSpec) DeepCopyInto(out *AWSMachinePoolSpec) {
 		*out = new(RefreshPreferences)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeDrainTimeout != nil {
+		in, out := &in.NodeDrainTimeout, &out.NodeDrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachinePoolSpec.
@@ -286,6 +313,11 @@ func (in *AWSMachinePoolStatus) DeepCopyInto(out *AWSMachinePoolStatus) {
 		*out = new(ASGStatus)
 		**out = **in
 	}
+	if in.InstanceDistribution != nil {
+		in, out := &in.InstanceDistribution, &out.InstanceDistribution
+		*out = new(InstanceDistributionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachinePoolStatus.
@@ -511,6 +543,11 @@ func (in *AWSManagedMachinePoolSpec) DeepCopyInto(out *AWSManagedMachinePoolSpec
 		*out = new(ManagedMachinePoolScaling)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpdateConfig != nil {
+		in, out := &in.UpdateConfig, &out.UpdateConfig
+		*out = new(UpdateConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RemoteAccess != nil {
 		in, out := &in.RemoteAccess, &out.RemoteAccess
 		*out = new(ManagedRemoteAccess)
@@ -598,6 +635,16 @@ func (in *AutoScalingGroup) DeepCopyInto(out *AutoScalingGroup) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ProtectedInstances != nil {
+		in, out := &in.ProtectedInstances, &out.ProtectedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetGroupARNs != nil {
+		in, out := &in.TargetGroupARNs, &out.TargetGroupARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalingGroup.
@@ -626,6 +673,31 @@ func (in *BlockDeviceMapping) DeepCopy() *BlockDeviceMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationSpecification) DeepCopyInto(out *CapacityReservationSpecification) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceGroupARN != nil {
+		in, out := &in.ResourceGroupARN, &out.ResourceGroupARN
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationSpecification.
+func (in *CapacityReservationSpecification) DeepCopy() *CapacityReservationSpecification {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationSpecification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EBS) DeepCopyInto(out *EBS) {
 	*out = *in
@@ -729,6 +801,28 @@ func (in *FargateSelector) DeepCopy() *FargateSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceDistributionStatus) DeepCopyInto(out *InstanceDistributionStatus) {
+	*out = *in
+	if in.AvailabilityZones != nil {
+		in, out := &in.AvailabilityZones, &out.AvailabilityZones
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceDistributionStatus.
+func (in *InstanceDistributionStatus) DeepCopy() *InstanceDistributionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceDistributionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstancesDistribution) DeepCopyInto(out *InstancesDistribution) {
 	*out = *in
@@ -862,6 +956,26 @@ func (in *RefreshPreferences) DeepCopyInto(out *RefreshPreferences) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.CheckpointPercentages != nil {
+		in, out := &in.CheckpointPercentages, &out.CheckpointPercentages
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.CheckpointDelay != nil {
+		in, out := &in.CheckpointDelay, &out.CheckpointDelay
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshPreferences.
@@ -928,3 +1042,28 @@ func (in Taints) DeepCopy() Taints {
 	in.DeepCopyInto(out)
 	return *out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateConfig) DeepCopyInto(out *UpdateConfig) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnavailablePercentage != nil {
+		in, out := &in.MaxUnavailablePercentage, &out.MaxUnavailablePercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateConfig.
+func (in *UpdateConfig) DeepCopy() *UpdateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -28,6 +28,8 @@ import (
 	controlplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -63,6 +65,14 @@ func (r *AWSControllerIdentityReconciler) Reconcile(ctx context.Context, req ctr
 		log.V(4).Info("AWSCluster not found, trying AWSManagedControlPlane")
 		clusterFound = false
 	} else {
+		cluster, err := util.GetOwnerCluster(ctx, r.Client, awsCluster.ObjectMeta)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if cluster != nil && annotations.IsPaused(cluster, awsCluster) {
+			log.V(4).Info("AWSCluster or linked Cluster is marked as paused, skipping reconciliation")
+			return reconcile.Result{}, nil
+		}
 		log.V(4).Info("Found identityRef on AWSCluster")
 		identityRef = awsCluster.Spec.IdentityRef
 	}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/api/bootstrap/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// iamConfigurationResyncPeriod is how often the IAM resources of a configuration are compared with
+// the configuration, to revert changes made outside of the controller.
+const iamConfigurationResyncPeriod = 10 * time.Minute
+
+// iamConfigurationService reconciles the IAM resources of an AWSIAMConfiguration.
+type iamConfigurationService interface {
+	ReconcileConfiguration(conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec) error
+	DeleteConfiguration(conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec) error
+}
+
+// AWSIAMConfigurationReconciler reconciles the IAM roles, instance profiles and managed policies of
+// AWSIAMConfigurations applied to the management cluster.
+type AWSIAMConfigurationReconciler struct {
+	client.Client
+	Recorder                record.EventRecorder
+	Endpoints               []scope.ServiceEndpoint
+	WatchFilterValue        string
+	iamConfigurationFactory func(spec *bootstrapv1.AWSIAMConfigurationSpec, log logr.Logger) (iamConfigurationService, error)
+}
+
+func (r *AWSIAMConfigurationReconciler) getIAMConfigurationService(spec *bootstrapv1.AWSIAMConfigurationSpec, log logr.Logger) (iamConfigurationService, error) {
+	if r.iamConfigurationFactory != nil {
+		return r.iamConfigurationFactory(spec, log)
+	}
+
+	return iam.NewConfigurationService(spec, r.Endpoints, log)
+}
+
+// SetupWithManager is used to setup the controller.
+func (r *AWSIAMConfigurationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&bootstrapv1.AWSIAMConfiguration{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=bootstrap.aws.infrastructure.cluster.x-k8s.io,resources=awsiamconfigurations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=bootstrap.aws.infrastructure.cluster.x-k8s.io,resources=awsiamconfigurations/status,verbs=get;update;patch
+
+// Reconcile reconciles AWSIAMConfigurations.
+func (r *AWSIAMConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	conf := &bootstrapv1.AWSIAMConfiguration{}
+	if err := r.Get(ctx, req.NamespacedName, conf); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	helper, err := patch.NewHelper(conf, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	defer func() {
+		if err := helper.Patch(ctx, conf, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			bootstrapv1.IAMResourcesReadyCondition,
+		}}); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// The defaults clusterawsadm applies are not persisted, so that a newer version of the controller
+	// applies its own defaults.
+	spec := conf.Spec.DeepCopy()
+	bootstrapv1.SetDefaults_AWSIAMConfigurationSpec(spec)
+
+	svc, err := r.getIAMConfigurationService(spec, log)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create IAM configuration service")
+	}
+
+	if !conf.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(conf, spec, svc)
+	}
+
+	return r.reconcileNormal(log, conf, spec, svc)
+}
+
+func (r *AWSIAMConfigurationReconciler) reconcileNormal(log logr.Logger, conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec, svc iamConfigurationService) (ctrl.Result, error) {
+	log.Info("Reconciling AWSIAMConfiguration")
+
+	controllerutil.AddFinalizer(conf, bootstrapv1.AWSIAMConfigurationFinalizer)
+
+	if err := svc.ReconcileConfiguration(conf, spec); err != nil {
+		conf.Status.Ready = false
+		conditions.MarkFalse(conf, bootstrapv1.IAMResourcesReadyCondition, bootstrapv1.IAMResourcesReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		r.Recorder.Eventf(conf, corev1.EventTypeWarning, "FailedReconcileIAMResources", "Failed to reconcile IAM resources: %v", err)
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile IAM resources of AWSIAMConfiguration %s", conf.Name)
+	}
+
+	conf.Status.Ready = true
+	conditions.MarkTrue(conf, bootstrapv1.IAMResourcesReadyCondition)
+
+	return ctrl.Result{RequeueAfter: iamConfigurationResyncPeriod}, nil
+}
+
+func (r *AWSIAMConfigurationReconciler) reconcileDelete(conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec, svc iamConfigurationService) (ctrl.Result, error) {
+	if err := svc.DeleteConfiguration(conf, spec); err != nil {
+		conditions.MarkFalse(conf, bootstrapv1.IAMResourcesReadyCondition, bootstrapv1.IAMResourcesDeletionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		r.Recorder.Eventf(conf, corev1.EventTypeWarning, "FailedDeleteIAMResources", "Failed to delete IAM resources: %v", err)
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete IAM resources of AWSIAMConfiguration %s", conf.Name)
+	}
+
+	conf.Status.Ready = false
+	controllerutil.RemoveFinalizer(conf, bootstrapv1.AWSIAMConfigurationFinalizer)
+
+	return ctrl.Result{}, nil
+}
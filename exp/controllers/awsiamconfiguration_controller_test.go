@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/api/bootstrap/v1alpha1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeIAMConfigurationService struct {
+	spec      *bootstrapv1.AWSIAMConfigurationSpec
+	reconcile error
+	deleted   bool
+}
+
+func (f *fakeIAMConfigurationService) ReconcileConfiguration(conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec) error {
+	f.spec = spec
+	if f.reconcile != nil {
+		return f.reconcile
+	}
+	conf.Status.Roles = []string{"nodes.cluster-api-provider-aws.sigs.k8s.io"}
+	return nil
+}
+
+func (f *fakeIAMConfigurationService) DeleteConfiguration(conf *bootstrapv1.AWSIAMConfiguration, _ *bootstrapv1.AWSIAMConfigurationSpec) error {
+	f.deleted = true
+	conf.Status.Roles = nil
+	return nil
+}
+
+func TestAWSIAMConfigurationReconciler(t *testing.T) {
+	setup := func(svc *fakeIAMConfigurationService, conf *bootstrapv1.AWSIAMConfiguration) *AWSIAMConfigurationReconciler {
+		scheme := runtime.NewScheme()
+		_ = bootstrapv1.AddToScheme(scheme)
+		return &AWSIAMConfigurationReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(conf).Build(),
+			Recorder: record.NewFakeRecorder(10),
+			iamConfigurationFactory: func(*bootstrapv1.AWSIAMConfigurationSpec, logr.Logger) (iamConfigurationService, error) {
+				return svc, nil
+			},
+		}
+	}
+	key := types.NamespacedName{Name: "default"}
+
+	t.Run("should reconcile the defaulted spec and requeue to revert drift", func(t *testing.T) {
+		g := NewWithT(t)
+		svc := &fakeIAMConfigurationService{}
+		r := setup(svc, &bootstrapv1.AWSIAMConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+
+		res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(res.RequeueAfter).To(Equal(iamConfigurationResyncPeriod))
+		g.Expect(svc.spec.Partition).To(Equal("aws"))
+
+		conf := &bootstrapv1.AWSIAMConfiguration{}
+		g.Expect(r.Get(context.Background(), key, conf)).To(Succeed())
+		g.Expect(conf.Finalizers).To(ContainElement(bootstrapv1.AWSIAMConfigurationFinalizer))
+		g.Expect(conf.Status.Ready).To(BeTrue())
+		g.Expect(conf.Status.Roles).To(ConsistOf("nodes.cluster-api-provider-aws.sigs.k8s.io"))
+		g.Expect(conditions.IsTrue(conf, bootstrapv1.IAMResourcesReadyCondition)).To(BeTrue())
+		// Defaults are not persisted.
+		g.Expect(conf.Spec.Partition).To(BeEmpty())
+	})
+
+	t.Run("should report reconciliation failures", func(t *testing.T) {
+		g := NewWithT(t)
+		svc := &fakeIAMConfigurationService{reconcile: errors.New("access denied")}
+		r := setup(svc, &bootstrapv1.AWSIAMConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		g.Expect(err).To(HaveOccurred())
+
+		conf := &bootstrapv1.AWSIAMConfiguration{}
+		g.Expect(r.Get(context.Background(), key, conf)).To(Succeed())
+		g.Expect(conf.Status.Ready).To(BeFalse())
+		g.Expect(conditions.GetReason(conf, bootstrapv1.IAMResourcesReadyCondition)).To(Equal(bootstrapv1.IAMResourcesReconciliationFailedReason))
+	})
+
+	t.Run("should delete the IAM resources and remove the finalizer", func(t *testing.T) {
+		g := NewWithT(t)
+		svc := &fakeIAMConfigurationService{}
+		now := metav1.Now()
+		r := setup(svc, &bootstrapv1.AWSIAMConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Name:              "default",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{bootstrapv1.AWSIAMConfigurationFinalizer},
+		}})
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(svc.deleted).To(BeTrue())
+	})
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// The cluster-autoscaler reads these annotations from a MachinePool to learn the
+// capacity of its nodes when the pool has been scaled down to zero replicas.
+const (
+	// AutoscalerCPUAnnotation is the number of vCPUs of a node in the pool.
+	AutoscalerCPUAnnotation = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+	// AutoscalerMemoryAnnotation is the memory of a node in the pool.
+	AutoscalerMemoryAnnotation = "capacity.cluster-autoscaler.kubernetes.io/memory"
+	// AutoscalerGPUCountAnnotation is the number of GPUs of a node in the pool.
+	AutoscalerGPUCountAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+	// AutoscalerGPUTypeAnnotation is the resource name the GPUs of a node in the pool are exposed as.
+	AutoscalerGPUTypeAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+	// AutoscalerLabelsAnnotation is a comma separated list of key=value labels of a node in the pool.
+	AutoscalerLabelsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/labels"
+	// AutoscalerTaintsAnnotation is a comma separated list of key=value:Effect taints of a node in the pool.
+	// It is never written by the controller, as taints are set by the bootstrap provider.
+	AutoscalerTaintsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/taints"
+)
+
+// gpuResourceNames maps GPU manufacturers reported by EC2 to the extended resource
+// name their device plugin registers.
+var gpuResourceNames = map[string]string{
+	"NVIDIA": "nvidia.com/gpu",
+	"AMD":    "amd.com/gpu",
+}
+
+// architectureLabels maps EC2 processor architectures to the kubernetes.io/arch label value.
+var architectureLabels = map[string]string{
+	ec2.ArchitectureTypeX8664: "amd64",
+	ec2.ArchitectureTypeArm64: "arm64",
+}
+
+// reconcileScaleFromZeroAnnotations keeps the cluster-autoscaler capacity annotations of the
+// MachinePool in line with the instance type of its launch template.
+func (r *AWSMachinePoolReconciler) reconcileScaleFromZeroAnnotations(ctx context.Context, machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope) error {
+	instanceType := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.InstanceType
+	if instanceType == "" {
+		return nil
+	}
+
+	info, err := r.getEC2Service(ec2Scope).GetInstanceTypeInfo(instanceType)
+	if err != nil {
+		return err
+	}
+
+	machinePool := machinePoolScope.MachinePool
+	patchHelper, err := patch.NewHelper(machinePool, r.Client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper for MachinePool")
+	}
+
+	annotations := machinePool.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	setScaleFromZeroAnnotations(annotations, info)
+	machinePool.SetAnnotations(annotations)
+
+	return patchHelper.Patch(ctx, machinePool)
+}
+
+// setScaleFromZeroAnnotations sets the capacity annotations derived from the given instance type.
+// Labels set by the user are kept, only the well-known node labels are overwritten.
+func setScaleFromZeroAnnotations(annotations map[string]string, info *ec2.InstanceTypeInfo) {
+	if info.VCpuInfo != nil && info.VCpuInfo.DefaultVCpus != nil {
+		annotations[AutoscalerCPUAnnotation] = fmt.Sprintf("%d", aws.Int64Value(info.VCpuInfo.DefaultVCpus))
+	}
+	if info.MemoryInfo != nil && info.MemoryInfo.SizeInMiB != nil {
+		annotations[AutoscalerMemoryAnnotation] = fmt.Sprintf("%dMi", aws.Int64Value(info.MemoryInfo.SizeInMiB))
+	}
+
+	var gpuCount int64
+	gpuType := ""
+	if info.GpuInfo != nil {
+		for _, gpu := range info.GpuInfo.Gpus {
+			gpuCount += aws.Int64Value(gpu.Count)
+			if name, ok := gpuResourceNames[strings.ToUpper(aws.StringValue(gpu.Manufacturer))]; ok {
+				gpuType = name
+			}
+		}
+	}
+	if gpuCount > 0 && gpuType != "" {
+		annotations[AutoscalerGPUCountAnnotation] = fmt.Sprintf("%d", gpuCount)
+		annotations[AutoscalerGPUTypeAnnotation] = gpuType
+	} else {
+		// The instance type may have been changed to one without GPUs.
+		delete(annotations, AutoscalerGPUCountAnnotation)
+		delete(annotations, AutoscalerGPUTypeAnnotation)
+	}
+
+	labels := parseAutoscalerLabels(annotations[AutoscalerLabelsAnnotation])
+	labels["node.kubernetes.io/instance-type"] = aws.StringValue(info.InstanceType)
+	if info.ProcessorInfo != nil {
+		for _, arch := range info.ProcessorInfo.SupportedArchitectures {
+			if label, ok := architectureLabels[aws.StringValue(arch)]; ok {
+				labels["kubernetes.io/arch"] = label
+				break
+			}
+		}
+	}
+	annotations[AutoscalerLabelsAnnotation] = formatAutoscalerLabels(labels)
+}
+
+func parseAutoscalerLabels(value string) map[string]string {
+	labels := map[string]string{}
+	for _, label := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(label), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels
+}
+
+func formatAutoscalerLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -32,12 +34,16 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
 	ekscontrolplane "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	asg "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/pricing"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
@@ -58,6 +64,8 @@ type AWSMachinePoolReconciler struct {
 	WatchFilterValue  string
 	asgServiceFactory func(cloud.ClusterScoper) services.ASGInterface
 	ec2ServiceFactory func(scope.EC2Scope) services.EC2MachineInterface
+
+	fleetLaunches fleetLaunchTracker
 }
 
 func (r *AWSMachinePoolReconciler) getASGService(scope cloud.ClusterScoper) services.ASGInterface {
@@ -77,7 +85,8 @@ func (r *AWSMachinePoolReconciler) getEC2Service(scope scope.EC2Scope) services.
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines;awsmachines/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
@@ -141,13 +150,14 @@ func (r *AWSMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Always close the scope when exiting this function so we can persist any AWSMachine changes.
 	defer func() {
 		// set Ready condition before AWSMachinePool is patched
+		readyCondition := poolReadyCondition(machinePoolScope)
 		conditions.SetSummary(machinePoolScope.AWSMachinePool,
 			conditions.WithConditions(
-				infrav1exp.ASGReadyCondition,
+				readyCondition,
 				infrav1exp.LaunchTemplateReadyCondition,
 			),
 			conditions.WithStepCounterIfOnly(
-				infrav1exp.ASGReadyCondition,
+				readyCondition,
 				infrav1exp.LaunchTemplateReadyCondition,
 			),
 		)
@@ -157,31 +167,44 @@ func (r *AWSMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}()
 
+	account := throttle.DefaultBackpressure.Account(scope.ThrottleKey(infraCluster))
+	if delay := account.Delay(); delay > 0 {
+		machinePoolScope.V(2).Info("Delaying reconciliation while AWS throttles the requests of the account", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
 	switch infraScope := infraCluster.(type) {
 	case *scope.ManagedControlPlaneScope:
 		if !awsMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
-			return r.reconcileDelete(machinePoolScope, infraScope, infraScope)
+			return account.Requeue(r.reconcileDelete(ctx, machinePoolScope, infraScope, infraScope))
 		}
 
-		return r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope)
+		return account.Requeue(r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope))
 	case *scope.ClusterScope:
 		if !awsMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
-			return r.reconcileDelete(machinePoolScope, infraScope, infraScope)
+			return account.Requeue(r.reconcileDelete(ctx, machinePoolScope, infraScope, infraScope))
 		}
 
-		return r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope)
+		return account.Requeue(r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope))
 	default:
 		return ctrl.Result{}, errors.New("infraCluster has unknown type")
 	}
 }
 
 func (r *AWSMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1exp.AWSMachinePool{}).
 		Watches(
 			&source.Kind{Type: &capiv1exp.MachinePool{}},
 			handler.EnqueueRequestsFromMapFunc(machinePoolToInfrastructureMapFunc(infrav1exp.GroupVersion.WithKind("AWSMachinePool"))),
-		).
+		)
+
+	// The AWSMachines of the instances of the pool are watched to terminate the instances of the deleted ones.
+	if feature.Gates.Enabled(feature.MachinePoolMachines) {
+		b = b.Owns(&infrav1.AWSMachine{})
+	}
+
+	return b.
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Complete(r)
 }
@@ -208,14 +231,14 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 
 	if !machinePoolScope.Cluster.Status.InfrastructureReady {
 		machinePoolScope.Info("Cluster infrastructure is not ready yet")
-		conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.ASGReadyCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		conditions.MarkFalse(machinePoolScope.AWSMachinePool, poolReadyCondition(machinePoolScope), infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
 		return ctrl.Result{}, nil
 	}
 
 	// Make sure bootstrap data is available and populated
 	if machinePoolScope.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
 		machinePoolScope.Info("Bootstrap data secret reference is not yet available")
-		conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.ASGReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		conditions.MarkFalse(machinePoolScope.AWSMachinePool, poolReadyCondition(machinePoolScope), infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
 		return ctrl.Result{}, nil
 	}
 
@@ -228,6 +251,10 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 	// set the LaunchTemplateReady condition
 	conditions.MarkTrue(machinePoolScope.AWSMachinePool, infrav1exp.LaunchTemplateReadyCondition)
 
+	if machinePoolScope.IsFleetBackend() {
+		return r.reconcileFleet(ctx, machinePoolScope, ec2Scope)
+	}
+
 	// Initialize ASG client
 	asgsvc := r.getASGService(clusterScope)
 
@@ -241,7 +268,7 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 	if asg == nil {
 		// Create new ASG
 		if _, err := r.createPool(machinePoolScope, clusterScope); err != nil {
-			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.ASGReadyCondition, infrav1exp.ASGProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.ASGReadyCondition, awserrors.ConditionReason(err, infrav1exp.ASGProvisionFailedReason), clusterv1.ConditionSeverityError, err.Error())
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
@@ -252,11 +279,21 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileLifecycleHook(machinePoolScope, clusterScope); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error reconciling node drain lifecycle hook")
+	}
+
 	err = r.reconcileTags(machinePoolScope, clusterScope, ec2Scope)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "error updating tags")
 	}
 
+	if feature.Gates.Enabled(feature.MachinePoolScaleFromZero) {
+		if err := r.reconcileScaleFromZeroAnnotations(ctx, machinePoolScope, ec2Scope); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "error updating scale from zero annotations")
+		}
+	}
+
 	// Make sure Spec.ProviderID is always set.
 	machinePoolScope.AWSMachinePool.Spec.ProviderID = asg.ID
 	providerIDList := make([]string, len(asg.Instances))
@@ -277,39 +314,142 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 		machinePoolScope.Info("Failed updating instances", "instances", asg.Instances)
 	}
 
+	if err := r.reconcileInstanceProtection(ctx, machinePoolScope, asgsvc, asg); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error updating scale-in protection of instances")
+	}
+
+	instances, err := r.reconcileInstanceDistribution(machinePoolScope, ec2Scope, asg)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error updating instance distribution")
+	}
+
+	if err := r.reconcileInfrastructureMachines(ctx, machinePoolScope, ec2Scope, instances); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error reconciling machine pool machines")
+	}
+
+	if feature.Gates.Enabled(feature.CostEstimation) {
+		reconcileCostEstimate(machinePoolScope, ec2Scope, instances)
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope) (ctrl.Result, error) {
-	clusterScope.Info("Handling deleted AWSMachinePool")
+// reconcileInstanceDistribution refreshes the instance distribution of the pool from the instances of its ASG, and
+// returns them. The ASG does not report whether its instances are spot instances, so they are looked up in EC2.
+func (r *AWSMachinePoolReconciler) reconcileInstanceDistribution(machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, asg *infrav1exp.AutoScalingGroup) ([]infrav1.Instance, error) {
+	ids := make([]string, len(asg.Instances))
+	for i, instance := range asg.Instances {
+		ids[i] = instance.ID
+	}
 
-	ec2Svc := r.getEC2Service(ec2Scope)
-	asgSvc := r.getASGService(clusterScope)
+	instances, err := r.getEC2Service(ec2Scope).GetInstancesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
 
-	asg, err := r.findASG(machinePoolScope, asgSvc)
+	machinePoolScope.AWSMachinePool.Status.InstanceDistribution = instanceDistribution(instances)
+	return instances, nil
+}
+
+// reconcileCostEstimate annotates the AWSMachinePool with the estimated hourly cost of its instances. The estimate is
+// only informational, so failing to compute it does not fail the reconciliation.
+func reconcileCostEstimate(machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, instances []infrav1.Instance) {
+	estimate, err := pricing.NewService(ec2Scope).EstimateInstancesCost(instances)
 	if err != nil {
-		return ctrl.Result{}, err
+		machinePoolScope.Info("Failed to estimate the cost of the instances", "error", err.Error())
+		return
+	}
+	machinePoolScope.SetAnnotation(infrav1.EstimatedHourlyCostAnnotation, estimate.String())
+}
+
+// reconcileInstanceProtection protects the instances of the ASG whose node has the ScaleDownDisabledAnnotation set from
+// scale-in, and removes the protection of the other instances with a node.
+func (r *AWSMachinePoolReconciler) reconcileInstanceProtection(ctx context.Context, machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, asg *infrav1exp.AutoScalingGroup) error {
+	protection, err := machinePoolScope.GetScaleInProtection(ctx, asg.Instances)
+	if err != nil {
+		machinePoolScope.Info("Failed getting the nodes of instances, skipping scale-in protection", "error", err.Error())
+		return nil
 	}
 
-	if asg == nil {
-		machinePoolScope.V(2).Info("Unable to locate ASG")
-		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "NoASGFound", "Unable to find matching ASG")
-	} else {
-		machinePoolScope.SetASGStatus(asg.Status)
-		switch asg.Status {
-		case infrav1exp.ASGStatusDeleteInProgress:
-			// ASG is already deleting
-			machinePoolScope.SetNotReady()
-			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.ASGReadyCondition, infrav1exp.ASGDeletionInProgress, clusterv1.ConditionSeverityWarning, "")
-			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "DeletionInProgress", "ASG deletion in progress: %q", asg.Name)
-			machinePoolScope.Info("ASG is already deleting", "name", asg.Name)
-		default:
-			machinePoolScope.Info("Deleting ASG", "id", asg.Name, "status", asg.Status)
-			if err := asgSvc.DeleteASGAndWait(asg.Name); err != nil {
-				r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete ASG %q: %v", asg.Name, err)
-				return ctrl.Result{}, errors.Wrap(err, "failed to delete ASG")
+	protect, unprotect := instanceProtectionChanges(asg, protection)
+	if len(protect) > 0 {
+		if err := asgSvc.SetInstanceProtection(asg.Name, protect, true); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulProtectInstances", "Protected instances %v from scale-in", protect)
+	}
+	if len(unprotect) > 0 {
+		if err := asgSvc.SetInstanceProtection(asg.Name, unprotect, false); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulUnprotectInstances", "Removed scale-in protection of instances %v", unprotect)
+	}
+	return nil
+}
+
+// instanceProtectionChanges returns the in service instances of the ASG to protect from scale-in and the ones to
+// unprotect, given whether each instance should be protected.
+func instanceProtectionChanges(asg *infrav1exp.AutoScalingGroup, protection map[string]bool) ([]string, []string) {
+	protected := make(map[string]bool, len(asg.ProtectedInstances))
+	for _, id := range asg.ProtectedInstances {
+		protected[id] = true
+	}
+
+	var protect, unprotect []string
+	for _, instance := range asg.Instances {
+		// The protection of instances leaving the group cannot be changed.
+		if instance.State != infrav1.InstanceState(autoscaling.LifecycleStateInService) {
+			continue
+		}
+		wanted, ok := protection[instance.ID]
+		if !ok || wanted == protected[instance.ID] {
+			continue
+		}
+		if wanted {
+			protect = append(protect, instance.ID)
+		} else {
+			unprotect = append(unprotect, instance.ID)
+		}
+	}
+	return protect, unprotect
+}
+
+// instanceDistribution counts the instances per availability zone and purchase option.
+func instanceDistribution(instances []infrav1.Instance) *infrav1exp.InstanceDistributionStatus {
+	distribution := &infrav1exp.InstanceDistributionStatus{}
+	for _, instance := range instances {
+		if instance.AvailabilityZone != "" {
+			if distribution.AvailabilityZones == nil {
+				distribution.AvailabilityZones = map[string]int32{}
 			}
+			distribution.AvailabilityZones[instance.AvailabilityZone]++
+		}
+		if instance.Lifecycle == infrav1.InstanceLifecycleSpot {
+			distribution.Spot++
+		} else {
+			distribution.OnDemand++
+		}
+	}
+	return distribution
+}
+
+func (r *AWSMachinePoolReconciler) reconcileDelete(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope) (ctrl.Result, error) {
+	clusterScope.Info("Handling deleted AWSMachinePool")
+
+	if feature.Gates.Enabled(feature.MachinePoolMachines) {
+		if err := r.deleteMachinePoolMachines(ctx, machinePoolScope); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	ec2Svc := r.getEC2Service(ec2Scope)
+
+	if machinePoolScope.IsFleetBackend() {
+		if err := r.deleteFleetInstances(machinePoolScope, ec2Svc); err != nil {
+			return ctrl.Result{}, err
 		}
+	} else if err := r.deleteASG(machinePoolScope, clusterScope); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	launchTemplateID := machinePoolScope.AWSMachinePool.Status.LaunchTemplateID
@@ -339,6 +479,38 @@ func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.Machi
 	return ctrl.Result{}, nil
 }
 
+func (r *AWSMachinePoolReconciler) deleteASG(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper) error {
+	asgSvc := r.getASGService(clusterScope)
+
+	asg, err := r.findASG(machinePoolScope, asgSvc)
+	if err != nil {
+		return err
+	}
+
+	if asg == nil {
+		machinePoolScope.V(2).Info("Unable to locate ASG")
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "NoASGFound", "Unable to find matching ASG")
+		return nil
+	}
+
+	machinePoolScope.SetASGStatus(asg.Status)
+	switch asg.Status {
+	case infrav1exp.ASGStatusDeleteInProgress:
+		// ASG is already deleting
+		machinePoolScope.SetNotReady()
+		conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.ASGReadyCondition, infrav1exp.ASGDeletionInProgress, clusterv1.ConditionSeverityWarning, "")
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "DeletionInProgress", "ASG deletion in progress: %q", asg.Name)
+		machinePoolScope.Info("ASG is already deleting", "name", asg.Name)
+	default:
+		machinePoolScope.Info("Deleting ASG", "id", asg.Name, "status", asg.Status)
+		if err := asgSvc.DeleteASGAndWait(asg.Name); err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete ASG %q: %v", asg.Name, err)
+			return errors.Wrap(err, "failed to delete ASG")
+		}
+	}
+	return nil
+}
+
 func (r *AWSMachinePoolReconciler) updatePool(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, existingASG *infrav1exp.AutoScalingGroup) error {
 	if asgNeedsUpdates(machinePoolScope, existingASG) {
 		machinePoolScope.Info("updating AutoScalingGroup")
@@ -353,6 +525,19 @@ func (r *AWSMachinePoolReconciler) updatePool(machinePoolScope *scope.MachinePoo
 	return nil
 }
 
+// reconcileLifecycleHook installs the lifecycle hook holding the instances the ASG terminates until their node is
+// drained. The drain is driven by the lifecycle action events received from the instance state queue, so the hook is
+// removed when that feature is disabled.
+func (r *AWSMachinePoolReconciler) reconcileLifecycleHook(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper) error {
+	drainTimeout := machinePoolScope.AWSMachinePool.Spec.NodeDrainTimeout
+	if !feature.Gates.Enabled(feature.EventBridgeInstanceState) {
+		drainTimeout = nil
+	}
+
+	asgSvc := r.getASGService(clusterScope)
+	return asgSvc.ReconcileLifecycleHook(machinePoolScope, drainTimeout)
+}
+
 func (r *AWSMachinePoolReconciler) createPool(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper) (*infrav1exp.AutoScalingGroup, error) {
 	clusterScope.Info("Initializing ASG client")
 
@@ -382,6 +567,21 @@ func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *sco
 	if err != nil {
 		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedGetBootstrapData", err.Error())
 	}
+	// Bottlerocket does not read cloud-init parts, and its NVIDIA images ship the drivers. Its settings are
+	// appended to the TOML user data instead.
+	if machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.AMI.OSFamily.UserDataFormat() == infrav1.UserDataFormatCloudInit {
+		bootstrapData, err = userdata.AddGPUBootstrap(machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.GPU, bootstrapData)
+		if err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedConfigureGPU", err.Error())
+			return err
+		}
+	} else {
+		bootstrapData, err = userdata.AddBottlerocketSettings(machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.Bottlerocket, bootstrapData)
+		if err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedConfigureBottlerocket", err.Error())
+			return err
+		}
+	}
 	bootstrapDataHash := userdata.ComputeHash(bootstrapData)
 
 	ec2svc := r.getEC2Service(ec2Scope)
@@ -395,7 +595,7 @@ func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *sco
 
 	imageID, err := ec2svc.DiscoverLaunchTemplateAMI(machinePoolScope)
 	if err != nil {
-		conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.LaunchTemplateReadyCondition, infrav1exp.LaunchTemplateCreateFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.LaunchTemplateReadyCondition, awserrors.ConditionReason(err, infrav1exp.LaunchTemplateCreateFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 
@@ -403,7 +603,7 @@ func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *sco
 		machinePoolScope.Info("no existing launch template found, creating")
 		launchTemplateID, err := ec2svc.CreateLaunchTemplate(machinePoolScope, imageID, bootstrapData)
 		if err != nil {
-			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.LaunchTemplateReadyCondition, infrav1exp.LaunchTemplateCreateFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.LaunchTemplateReadyCondition, awserrors.ConditionReason(err, infrav1exp.LaunchTemplateCreateFailedReason), clusterv1.ConditionSeverityError, err.Error())
 			return err
 		}
 
@@ -439,7 +639,8 @@ func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *sco
 	// If there is a change: before changing the template, check if there exist an ongoing instance refresh,
 	// because only 1 instance refresh can be "InProgress". If template is updated when refresh cannot be started,
 	// that change will not trigger a refresh. Do not start an instance refresh if only userdata changed.
-	if needsUpdate || tagsChanged || *imageID != *launchTemplate.AMI.ID {
+	// Pools using the EC2Fleet backend have no ASG, and their instances are not refreshed.
+	if !machinePoolScope.IsFleetBackend() && (needsUpdate || tagsChanged || *imageID != *launchTemplate.AMI.ID) {
 		asgSvc := r.getASGService(ec2Scope)
 		canStart, err := asgSvc.CanStartASGInstanceRefresh(machinePoolScope)
 		if err != nil {
@@ -456,8 +657,13 @@ func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *sco
 	if needsUpdate || tagsChanged || *imageID != *launchTemplate.AMI.ID || launchTemplateUserDataHash != bootstrapDataHash {
 		machinePoolScope.Info("creating new version for launch template", "existing", launchTemplate, "incoming", machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate)
 		// There is a limit to the number of Launch Template Versions.
-		// We ensure that the number of versions does not grow without bound by following a simple rule: Before we create a new version, we delete one old version, if there is at least one old version that is not in use.
-		if err := ec2svc.PruneLaunchTemplateVersions(machinePoolScope.AWSMachinePool.Status.LaunchTemplateID); err != nil {
+		// We ensure that the number of versions does not grow without bound by following a simple rule: Before we create a new version,
+		// we delete the old versions that are not in use and exceed the version history limit.
+		historyLimit := int32(infrav1exp.DefaultLaunchTemplateVersionHistoryLimit)
+		if limit := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.VersionHistoryLimit; limit != nil {
+			historyLimit = *limit
+		}
+		if err := ec2svc.PruneLaunchTemplateVersions(machinePoolScope.AWSMachinePool.Status.LaunchTemplateID, historyLimit); err != nil {
 			return err
 		}
 		if err := ec2svc.CreateLaunchTemplateVersion(machinePoolScope, imageID, bootstrapData); err != nil {
@@ -474,11 +680,11 @@ func (r *AWSMachinePoolReconciler) reconcileLaunchTemplate(machinePoolScope *sco
 	// this conditional will not evaluate to true the next reconcile. If any machines use an older
 	// Launch Template version, and the difference between the older and current versions is _more_
 	// than userdata, we should start an Instance Refresh.
-	if needsUpdate || tagsChanged || *imageID != *launchTemplate.AMI.ID {
+	if !machinePoolScope.IsFleetBackend() && (needsUpdate || tagsChanged || *imageID != *launchTemplate.AMI.ID) {
 		machinePoolScope.Info("starting instance refresh", "number of instances", machinePoolScope.MachinePool.Spec.Replicas)
 		asgSvc := r.getASGService(ec2Scope)
 		if err := asgSvc.StartASGInstanceRefresh(machinePoolScope); err != nil {
-			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.InstanceRefreshStartedCondition, infrav1exp.InstanceRefreshFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.InstanceRefreshStartedCondition, awserrors.ConditionReason(err, infrav1exp.InstanceRefreshFailedReason), clusterv1.ConditionSeverityError, err.Error())
 			return err
 		}
 		conditions.MarkTrue(machinePoolScope.AWSMachinePool, infrav1exp.InstanceRefreshStartedCondition)
@@ -492,10 +698,13 @@ func (r *AWSMachinePoolReconciler) reconcileTags(machinePoolScope *scope.Machine
 	asgSvc := r.getASGService(clusterScope)
 
 	launchTemplateID := machinePoolScope.AWSMachinePool.Status.LaunchTemplateID
-	asgName := machinePoolScope.Name()
+	asgName := aws.String(machinePoolScope.Name())
+	if machinePoolScope.IsFleetBackend() {
+		asgName = nil
+	}
 	additionalTags := machinePoolScope.AdditionalTags()
 
-	tagsChanged, err := r.ensureTags(ec2Svc, asgSvc, machinePoolScope.AWSMachinePool, &launchTemplateID, &asgName, additionalTags)
+	tagsChanged, err := r.ensureTags(ec2Svc, asgSvc, machinePoolScope.AWSMachinePool, &launchTemplateID, asgName, additionalTags)
 	if err != nil {
 		return err
 	}
@@ -586,6 +795,12 @@ func machinePoolToInfrastructureMapFunc(gvk schema.GroupVersionKind) handler.Map
 }
 
 func (r *AWSMachinePoolReconciler) getInfraCluster(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster, awsMachinePool *infrav1exp.AWSMachinePool) (scope.EC2Scope, error) {
+	return GetInfraCluster(ctx, r.Client, log, cluster, awsMachinePool)
+}
+
+// GetInfraCluster returns the scope of the AWSCluster or the AWSManagedControlPlane of the cluster of the machine pool.
+// It returns nil when the object does not exist yet.
+func GetInfraCluster(ctx context.Context, c client.Client, log logr.Logger, cluster *clusterv1.Cluster, awsMachinePool *infrav1exp.AWSMachinePool) (scope.EC2Scope, error) {
 	var clusterScope *scope.ClusterScope
 	var managedControlPlaneScope *scope.ManagedControlPlaneScope
 	var err error
@@ -597,13 +812,13 @@ func (r *AWSMachinePoolReconciler) getInfraCluster(ctx context.Context, log logr
 			Name:      cluster.Spec.ControlPlaneRef.Name,
 		}
 
-		if err := r.Get(ctx, controlPlaneName, controlPlane); err != nil {
+		if err := c.Get(ctx, controlPlaneName, controlPlane); err != nil {
 			// AWSManagedControlPlane is not ready
 			return nil, nil // nolint:nilerr
 		}
 
 		managedControlPlaneScope, err = scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
-			Client:         r.Client,
+			Client:         c,
 			Logger:         log,
 			Cluster:        cluster,
 			ControlPlane:   controlPlane,
@@ -623,14 +838,14 @@ func (r *AWSMachinePoolReconciler) getInfraCluster(ctx context.Context, log logr
 		Name:      cluster.Spec.InfrastructureRef.Name,
 	}
 
-	if err := r.Client.Get(ctx, infraClusterName, awsCluster); err != nil {
+	if err := c.Get(ctx, infraClusterName, awsCluster); err != nil {
 		// AWSCluster is not ready
 		return nil, nil // nolint:nilerr
 	}
 
 	// Create the cluster scope
 	clusterScope, err = scope.NewClusterScope(scope.ClusterScopeParams{
-		Client:         r.Client,
+		Client:         c,
 		Logger:         log,
 		Cluster:        cluster,
 		AWSCluster:     awsCluster,
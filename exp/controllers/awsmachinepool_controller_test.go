@@ -25,9 +25,12 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -41,6 +44,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/mock_services"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	// "sigs.k8s.io/cluster-api/controllers/noderefutil" //nolint:godot.
@@ -274,7 +278,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 			expectedErr := errors.New("no connection available ")
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, expectedErr).AnyTimes()
 
-			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			_, err := reconciler.reconcileDelete(context.TODO(), ms, cs, cs)
 			g.Expect(errors.Cause(err)).To(MatchError(expectedErr))
 		})
 		t.Run("should log and remove finalizer when no machinepool exists", func(t *testing.T) {
@@ -289,7 +293,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 			buf := new(bytes.Buffer)
 			klog.SetOutput(buf)
 
-			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			_, err := reconciler.reconcileDelete(context.TODO(), ms, cs, cs)
 			g.Expect(err).To(BeNil())
 			g.Expect(buf.String()).To(ContainSubstring("Unable to locate ASG"))
 			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
@@ -310,7 +314,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 
 			buf := new(bytes.Buffer)
 			klog.SetOutput(buf)
-			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			_, err := reconciler.reconcileDelete(context.TODO(), ms, cs, cs)
 			g.Expect(err).To(BeNil())
 			g.Expect(ms.AWSMachinePool.Status.Ready).To(Equal(false))
 			g.Eventually(recorder.Events).Should(Receive(ContainSubstring("DeletionInProgress")))
@@ -327,6 +331,45 @@ type conditionAssertion struct {
 	reason        string
 }
 
+func TestSetScaleFromZeroAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	annotations := map[string]string{
+		AutoscalerLabelsAnnotation:   "node-role.kubernetes.io/gpu=,kubernetes.io/arch=arm64",
+		AutoscalerTaintsAnnotation:   "gpu=true:NoSchedule",
+		AutoscalerGPUCountAnnotation: "8",
+	}
+	setScaleFromZeroAnnotations(annotations, &ec2.InstanceTypeInfo{
+		InstanceType: aws.String("g4dn.xlarge"),
+		VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(4)},
+		MemoryInfo:   &ec2.MemoryInfo{SizeInMiB: aws.Int64(16384)},
+		GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{
+			{Count: aws.Int64(1), Manufacturer: aws.String("NVIDIA")},
+		}},
+		ProcessorInfo: &ec2.ProcessorInfo{SupportedArchitectures: aws.StringSlice([]string{"x86_64"})},
+	})
+	g.Expect(annotations).To(Equal(map[string]string{
+		AutoscalerCPUAnnotation:      "4",
+		AutoscalerMemoryAnnotation:   "16384Mi",
+		AutoscalerGPUCountAnnotation: "1",
+		AutoscalerGPUTypeAnnotation:  "nvidia.com/gpu",
+		AutoscalerLabelsAnnotation:   "kubernetes.io/arch=amd64,node-role.kubernetes.io/gpu=,node.kubernetes.io/instance-type=g4dn.xlarge",
+		AutoscalerTaintsAnnotation:   "gpu=true:NoSchedule",
+	}))
+
+	// Moving to an instance type without GPUs drops the GPU annotations.
+	setScaleFromZeroAnnotations(annotations, &ec2.InstanceTypeInfo{
+		InstanceType:  aws.String("m6g.large"),
+		VCpuInfo:      &ec2.VCpuInfo{DefaultVCpus: aws.Int64(2)},
+		MemoryInfo:    &ec2.MemoryInfo{SizeInMiB: aws.Int64(8192)},
+		ProcessorInfo: &ec2.ProcessorInfo{SupportedArchitectures: aws.StringSlice([]string{"arm64"})},
+	})
+	g.Expect(annotations).ToNot(HaveKey(AutoscalerGPUCountAnnotation))
+	g.Expect(annotations).ToNot(HaveKey(AutoscalerGPUTypeAnnotation))
+	g.Expect(annotations).To(HaveKeyWithValue(AutoscalerCPUAnnotation, "2"))
+	g.Expect(annotations).To(HaveKeyWithValue(AutoscalerLabelsAnnotation, "kubernetes.io/arch=arm64,node-role.kubernetes.io/gpu=,node.kubernetes.io/instance-type=m6g.large"))
+}
+
 func expectConditions(g *WithT, m *expinfrav1.AWSMachinePool, expected []conditionAssertion) {
 	g.Expect(len(m.Status.Conditions)).To(BeNumerically(">=", len(expected)), "number of conditions")
 	for _, c := range expected {
@@ -355,3 +398,258 @@ func setupCluster(clusterName string) (*scope.ClusterScope, error) {
 		Client:     client,
 	})
 }
+
+func TestFleetInstancesToTerminate(t *testing.T) {
+	g := NewWithT(t)
+
+	instance := func(id, zone, version string) infrav1.Instance {
+		return infrav1.Instance{ID: id, AvailabilityZone: zone, Tags: infrav1.Tags{launchTemplateVersionTagKey: version}}
+	}
+	instances := []infrav1.Instance{
+		instance("i-1", "us-east-1a", "2"),
+		instance("i-2", "us-east-1a", "2"),
+		instance("i-3", "us-east-1b", "1"),
+		instance("i-4", "us-east-1b", "2"),
+		instance("i-5", "us-east-1a", "2"),
+	}
+
+	ids := func(instances []infrav1.Instance) []string {
+		out := []string{}
+		for _, i := range instances {
+			out = append(out, i.ID)
+		}
+		return out
+	}
+	// The instance of the oldest version goes first, then the zones are balanced.
+	g.Expect(ids(fleetInstancesToTerminate(instances, 3))).To(Equal([]string{"i-3", "i-1", "i-2"}))
+	g.Expect(ids(fleetInstancesToTerminate(instances, 10))).To(HaveLen(5))
+}
+
+func TestInstanceDistribution(t *testing.T) {
+	g := NewWithT(t)
+
+	instance := func(zone string, lifecycle infrav1.InstanceLifecycle) infrav1.Instance {
+		return infrav1.Instance{AvailabilityZone: zone, Lifecycle: lifecycle}
+	}
+	instances := []infrav1.Instance{
+		instance("us-east-1a", infrav1.InstanceLifecycleOnDemand),
+		instance("us-east-1a", infrav1.InstanceLifecycleSpot),
+		instance("us-east-1b", infrav1.InstanceLifecycleSpot),
+		instance("us-east-1b", infrav1.InstanceLifecycleScheduled),
+		instance("us-east-1c", infrav1.InstanceLifecycleSpot),
+	}
+
+	g.Expect(instanceDistribution(instances)).To(Equal(&expinfrav1.InstanceDistributionStatus{
+		AvailabilityZones: map[string]int32{"us-east-1a": 2, "us-east-1b": 2, "us-east-1c": 1},
+		OnDemand:          2,
+		Spot:              3,
+	}))
+	g.Expect(instanceDistribution(nil)).To(Equal(&expinfrav1.InstanceDistributionStatus{}))
+}
+
+func TestAWSMachinePoolReconcileFleet(t *testing.T) {
+	setup := func(t *testing.T, replicas int32) (*AWSMachinePoolReconciler, *scope.MachinePoolScope, *mock_services.MockEC2MachineInterface) {
+		t.Helper()
+		g := NewWithT(t)
+
+		scheme := runtime.NewScheme()
+		_ = infrav1.AddToScheme(scheme)
+		_ = expinfrav1.AddToScheme(scheme)
+		awsMachinePool := &expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+			Spec: expinfrav1.AWSMachinePoolSpec{
+				MinSize: 0,
+				MaxSize: 5,
+				Backend: expinfrav1.MachinePoolBackendEC2Fleet,
+			},
+		}
+		cs, err := setupCluster("test-cluster")
+		g.Expect(err).NotTo(HaveOccurred())
+		ms, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+			Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsMachinePool).Build(),
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			MachinePool: &expclusterv1.MachinePool{
+				Spec: expclusterv1.MachinePoolSpec{Replicas: pointer.Int32Ptr(replicas)},
+			},
+			InfraCluster:   cs,
+			AWSMachinePool: awsMachinePool,
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		ec2Svc := mock_services.NewMockEC2MachineInterface(gomock.NewController(t))
+		r := &AWSMachinePoolReconciler{
+			ec2ServiceFactory: func(scope.EC2Scope) services.EC2MachineInterface {
+				return ec2Svc
+			},
+			Recorder: record.NewFakeRecorder(10),
+		}
+		return r, ms, ec2Svc
+	}
+	instance := func(id string) infrav1.Instance {
+		return infrav1.Instance{ID: id, AvailabilityZone: "us-east-1a", State: infrav1.InstanceStateRunning}
+	}
+
+	t.Run("launches the missing instances once", func(t *testing.T) {
+		g := NewWithT(t)
+		r, ms, ec2Svc := setup(t, 3)
+
+		existing := []infrav1.Instance{instance("i-1")}
+		ec2Svc.EXPECT().GetFleetInstances(gomock.Any()).Return(existing, nil).Times(2)
+		ec2Svc.EXPECT().CreateFleetInstances(gomock.Any(), existing, int32(2)).Return([]string{"i-2", "i-3"}, nil)
+
+		result, err := r.reconcileFleet(context.Background(), ms, ms.InfraCluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(fleetCapacityPendingRequeueInterval))
+		g.Expect(ms.AWSMachinePool.Spec.ProviderIDList).To(ConsistOf("aws:///us-east-1a/i-1"))
+		expectConditions(g, ms.AWSMachinePool, []conditionAssertion{{expinfrav1.FleetReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityInfo, expinfrav1.FleetCapacityPendingReason}})
+
+		// The instances just launched are not launched again while DescribeInstances does not return them.
+		_, err = r.reconcileFleet(context.Background(), ms, ms.InfraCluster)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("terminates the extra instances", func(t *testing.T) {
+		g := NewWithT(t)
+		r, ms, ec2Svc := setup(t, 1)
+
+		ec2Svc.EXPECT().GetFleetInstances(gomock.Any()).Return([]infrav1.Instance{instance("i-1"), instance("i-2")}, nil)
+		ec2Svc.EXPECT().TerminateInstance("i-1").Return(nil)
+		ec2Svc.EXPECT().GetFleetInstances(gomock.Any()).Return([]infrav1.Instance{instance("i-2")}, nil)
+
+		result, err := r.reconcileFleet(context.Background(), ms, ms.InfraCluster)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(BeZero())
+		g.Expect(ms.AWSMachinePool.Spec.ProviderIDList).To(ConsistOf("aws:///us-east-1a/i-2"))
+		g.Expect(ms.AWSMachinePool.Status.Replicas).To(Equal(int32(1)))
+		expectConditions(g, ms.AWSMachinePool, []conditionAssertion{{expinfrav1.FleetReadyCondition, corev1.ConditionTrue, "", ""}})
+	})
+
+	t.Run("reports the launch failures", func(t *testing.T) {
+		g := NewWithT(t)
+		r, ms, ec2Svc := setup(t, 1)
+
+		ec2Svc.EXPECT().GetFleetInstances(gomock.Any()).Return(nil, nil)
+		ec2Svc.EXPECT().CreateFleetInstances(gomock.Any(), gomock.Any(), int32(1)).Return(nil, errors.New("InsufficientInstanceCapacity"))
+
+		_, err := r.reconcileFleet(context.Background(), ms, ms.InfraCluster)
+		g.Expect(err).To(HaveOccurred())
+		expectConditions(g, ms.AWSMachinePool, []conditionAssertion{{expinfrav1.FleetReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityError, expinfrav1.FleetLaunchFailedReason}})
+	})
+}
+
+func TestReconcileMachinePoolMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = expinfrav1.AddToScheme(scheme)
+	_ = expclusterv1.AddToScheme(scheme)
+
+	awsMachinePool := &expinfrav1.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"},
+		Spec:       expinfrav1.AWSMachinePoolSpec{MinSize: 1, MaxSize: 5},
+	}
+	machinePool := &expclusterv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-pool", Namespace: "default"},
+		Spec:       expclusterv1.MachinePoolSpec{Replicas: pointer.Int32Ptr(3)},
+	}
+	poolMachine := func(instanceID string) *infrav1.AWSMachine {
+		return &infrav1.AWSMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pool-" + instanceID,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:   "test-cluster",
+					infrav1.MachinePoolNameLabel: "machine-pool",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(awsMachinePool, expinfrav1.GroupVersion.WithKind("AWSMachinePool")),
+				},
+				Finalizers: []string{expinfrav1.MachinePoolMachineFinalizer},
+			},
+			Spec: infrav1.AWSMachineSpec{InstanceID: aws.String(instanceID)},
+		}
+	}
+	deleted := poolMachine("i-3")
+	deleted.DeletionTimestamp = &metav1.Time{}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsMachinePool, machinePool, poolMachine("i-1"), poolMachine("i-2"), deleted).Build()
+
+	cs, err := setupCluster("test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	ms, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+		Client:         c,
+		Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+		MachinePool:    machinePool,
+		InfraCluster:   cs,
+		AWSMachinePool: awsMachinePool,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	asgSvc := mock_services.NewMockASGInterface(gomock.NewController(t))
+	r := &AWSMachinePoolReconciler{
+		Client: c,
+		asgServiceFactory: func(cloud.ClusterScoper) services.ASGInterface {
+			return asgSvc
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	// The instance of the deleted AWSMachine is terminated, and not replaced.
+	asgSvc.EXPECT().TerminateInstanceInASG("i-3", true).Return(nil)
+
+	instances := []infrav1.Instance{
+		{ID: "i-1", AvailabilityZone: "us-east-1a", State: infrav1.InstanceStateRunning},
+		{ID: "i-3", AvailabilityZone: "us-east-1a", State: infrav1.InstanceStateRunning},
+		{ID: "i-4", AvailabilityZone: "us-east-1b", State: infrav1.InstanceStateRunning, Type: "m5.large"},
+		{ID: "i-5", AvailabilityZone: "us-east-1b", State: infrav1.InstanceStateShuttingDown},
+	}
+	g.Expect(r.reconcileMachinePoolMachines(context.Background(), ms, cs, instances)).To(Succeed())
+
+	existing := &infrav1.AWSMachine{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "pool-i-1"}, existing)).To(Succeed())
+	g.Expect(existing.Status.Ready).To(BeTrue())
+
+	// The AWSMachine of the instance which left the pool is deleted.
+	err = c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "pool-i-2"}, &infrav1.AWSMachine{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	created := &infrav1.AWSMachine{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "pool-i-4"}, created)).To(Succeed())
+	g.Expect(created.Spec.ProviderID).To(Equal(aws.String("aws:///us-east-1b/i-4")))
+	g.Expect(created.Spec.InstanceType).To(Equal("m5.large"))
+	g.Expect(created.Labels).To(HaveKeyWithValue(infrav1.MachinePoolNameLabel, "machine-pool"))
+	g.Expect(metav1.IsControlledBy(created, awsMachinePool)).To(BeTrue())
+	g.Expect(created.Status.Ready).To(BeTrue())
+
+	// No AWSMachine is created for terminating instances.
+	err = c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "pool-i-5"}, &infrav1.AWSMachine{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// The replicas of the MachinePool follow the terminated instance.
+	updated := &expclusterv1.MachinePool{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "machine-pool"}, updated)).To(Succeed())
+	g.Expect(updated.Spec.Replicas).To(Equal(pointer.Int32Ptr(2)))
+}
+
+func TestInstanceProtectionChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	inService := infrav1.InstanceState("InService")
+	asg := &expinfrav1.AutoScalingGroup{
+		Instances: []infrav1.Instance{
+			{ID: "i-1", State: inService},
+			{ID: "i-2", State: inService},
+			{ID: "i-3", State: inService},
+			{ID: "i-4", State: inService},
+			{ID: "i-5", State: infrav1.InstanceState("Terminating")},
+			{ID: "i-6", State: inService},
+		},
+		ProtectedInstances: []string{"i-2", "i-3", "i-6"},
+	}
+	// i-4 has no node yet, and the protection of i-5 cannot change while it terminates.
+	protection := map[string]bool{"i-1": true, "i-2": true, "i-3": false, "i-5": true, "i-6": false}
+
+	protect, unprotect := instanceProtectionChanges(asg, protection)
+	g.Expect(protect).To(Equal([]string{"i-1"}))
+	g.Expect(unprotect).To(Equal([]string{"i-3", "i-6"}))
+}
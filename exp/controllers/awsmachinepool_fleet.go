@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// fleetLaunchVisibilityTimeout is how long the instances launched with EC2 Fleet are counted in the capacity
+	// of the pool before DescribeInstances returns them.
+	fleetLaunchVisibilityTimeout = 2 * time.Minute
+
+	// fleetCapacityPendingRequeueInterval is how often the capacity of the pool is checked while it does not match
+	// the desired replicas.
+	fleetCapacityPendingRequeueInterval = 20 * time.Second
+
+	// launchTemplateVersionTagKey is the tag EC2 sets on the instances launched from a launch template.
+	launchTemplateVersionTagKey = "aws:ec2launchtemplate:version"
+)
+
+// fleetLaunchTracker remembers the instances launched with EC2 Fleet until DescribeInstances returns them, so that
+// they are not launched again because of its eventual consistency.
+type fleetLaunchTracker struct {
+	mu       sync.Mutex
+	launches map[types.NamespacedName]map[string]time.Time
+}
+
+// record remembers instances just launched for the pool.
+func (t *fleetLaunchTracker) record(pool types.NamespacedName, instanceIDs []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.launches == nil {
+		t.launches = map[types.NamespacedName]map[string]time.Time{}
+	}
+	if t.launches[pool] == nil {
+		t.launches[pool] = map[string]time.Time{}
+	}
+	for _, id := range instanceIDs {
+		t.launches[pool][id] = time.Now().Add(fleetLaunchVisibilityTimeout)
+	}
+}
+
+// pending returns the instances launched for the pool that are not visible yet, and forgets the visible and
+// expired ones.
+func (t *fleetLaunchTracker) pending(pool types.NamespacedName, visible []infrav1.Instance) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	launches := t.launches[pool]
+	for _, i := range visible {
+		delete(launches, i.ID)
+	}
+	ids := []string{}
+	for id, deadline := range launches {
+		if time.Now().After(deadline) {
+			delete(launches, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(launches) == 0 {
+		delete(t.launches, pool)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// forget drops the instances launched for the pool.
+func (t *fleetLaunchTracker) forget(pool types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.launches, pool)
+}
+
+// poolReadyCondition returns the condition reporting on the instances of the pool for its backend.
+func poolReadyCondition(machinePoolScope *scope.MachinePoolScope) clusterv1.ConditionType {
+	if machinePoolScope.IsFleetBackend() {
+		return infrav1exp.FleetReadyCondition
+	}
+	return infrav1exp.ASGReadyCondition
+}
+
+// reconcileFleet launches or terminates instances with EC2 Fleet until the pool has its desired replicas.
+func (r *AWSMachinePoolReconciler) reconcileFleet(ctx context.Context, machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope) (ctrl.Result, error) {
+	ec2Svc := r.getEC2Service(ec2Scope)
+	poolKey := types.NamespacedName{Namespace: machinePoolScope.Namespace(), Name: machinePoolScope.Name()}
+
+	instances, err := ec2Svc.GetFleetInstances(machinePoolScope)
+	if err != nil {
+		conditions.MarkUnknown(machinePoolScope.AWSMachinePool, infrav1exp.FleetReadyCondition, infrav1exp.FleetLaunchFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+	pending := r.fleetLaunches.pending(poolKey, instances)
+
+	desired := fleetDesiredReplicas(machinePoolScope)
+	current := int32(len(instances) + len(pending))
+	switch {
+	case current < desired:
+		machinePoolScope.Info("Launching instances with EC2 Fleet", "count", desired-current)
+		ids, err := ec2Svc.CreateFleetInstances(machinePoolScope, instances, desired-current)
+		if err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedLaunchInstances", "Failed to launch instances with EC2 Fleet: %v", err)
+			conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.FleetReadyCondition, infrav1exp.FleetLaunchFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, err
+		}
+		r.fleetLaunches.record(poolKey, ids)
+		pending = append(pending, ids...)
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulLaunchInstances", "Launched instances %v", ids)
+	case current > desired && int32(len(instances)) > desired:
+		for _, instance := range fleetInstancesToTerminate(instances, len(instances)-int(desired)) {
+			machinePoolScope.Info("Terminating instance", "instance-id", instance.ID)
+			if err := ec2Svc.TerminateInstance(instance.ID); err != nil {
+				r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedTerminate", "Failed to terminate instance %q: %v", instance.ID, err)
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %q", instance.ID)
+		}
+		// Terminated instances leave the running state at once.
+		instances, err = ec2Svc.GetFleetInstances(machinePoolScope)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if feature.Gates.Enabled(feature.MachinePoolScaleFromZero) {
+		if err := r.reconcileScaleFromZeroAnnotations(ctx, machinePoolScope, ec2Scope); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "error updating scale from zero annotations")
+		}
+	}
+
+	providerIDList := make([]string, len(instances))
+	for i, instance := range instances {
+		providerIDList[i] = fmt.Sprintf("aws:///%s/%s", instance.AvailabilityZone, instance.ID)
+	}
+
+	machinePoolScope.SetAnnotation("cluster-api-provider-aws", "true")
+
+	machinePoolScope.AWSMachinePool.Spec.ProviderIDList = providerIDList
+	machinePoolScope.AWSMachinePool.Status.Replicas = int32(len(providerIDList))
+	machinePoolScope.AWSMachinePool.Status.Ready = true
+	machinePoolScope.AWSMachinePool.Status.InstanceDistribution = instanceDistribution(instances)
+
+	if err := machinePoolScope.UpdateInstanceStatuses(ctx, instances); err != nil {
+		machinePoolScope.Info("Failed updating instances", "instances", instances)
+	}
+
+	if err := r.reconcileInfrastructureMachines(ctx, machinePoolScope, ec2Scope, instances); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error reconciling machine pool machines")
+	}
+
+	if feature.Gates.Enabled(feature.CostEstimation) {
+		reconcileCostEstimate(machinePoolScope, ec2Scope, instances)
+	}
+
+	if int32(len(instances)) != desired || len(pending) > 0 {
+		conditions.MarkFalse(machinePoolScope.AWSMachinePool, infrav1exp.FleetReadyCondition, infrav1exp.FleetCapacityPendingReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d instances running, %d launching", len(instances), desired, len(pending))
+		return ctrl.Result{RequeueAfter: fleetCapacityPendingRequeueInterval}, nil
+	}
+	conditions.MarkTrue(machinePoolScope.AWSMachinePool, infrav1exp.FleetReadyCondition)
+
+	return ctrl.Result{}, nil
+}
+
+// deleteFleetInstances terminates all instances of a pool using the EC2Fleet backend.
+func (r *AWSMachinePoolReconciler) deleteFleetInstances(machinePoolScope *scope.MachinePoolScope, ec2Svc services.EC2MachineInterface) error {
+	poolKey := types.NamespacedName{Namespace: machinePoolScope.Namespace(), Name: machinePoolScope.Name()}
+
+	instances, err := ec2Svc.GetFleetInstances(machinePoolScope)
+	if err != nil {
+		return err
+	}
+	ids := r.fleetLaunches.pending(poolKey, instances)
+	for _, instance := range instances {
+		ids = append(ids, instance.ID)
+	}
+
+	machinePoolScope.SetNotReady()
+	errs := []error{}
+	for _, id := range ids {
+		machinePoolScope.Info("Terminating instance", "instance-id", id)
+		if err := ec2Svc.TerminateInstance(id); err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedTerminate", "Failed to terminate instance %q: %v", id, err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(kerrors.NewAggregate(errs), "failed to terminate instances of the pool")
+	}
+
+	r.fleetLaunches.forget(poolKey)
+	return nil
+}
+
+// fleetDesiredReplicas returns the replicas of the machine pool, within the size bounds of the AWSMachinePool.
+func fleetDesiredReplicas(machinePoolScope *scope.MachinePoolScope) int32 {
+	spec := machinePoolScope.AWSMachinePool.Spec
+	desired := spec.MinSize
+	if replicas := machinePoolScope.MachinePool.Spec.Replicas; replicas != nil {
+		desired = *replicas
+	}
+	if desired < spec.MinSize {
+		desired = spec.MinSize
+	}
+	if desired > spec.MaxSize {
+		desired = spec.MaxSize
+	}
+	return desired
+}
+
+// fleetInstancesToTerminate picks count instances to terminate when scaling in. Like an ASG, the instances launched
+// from the oldest launch template version go first, taken from the availability zone with the most instances so that
+// the pool stays balanced.
+func fleetInstancesToTerminate(instances []infrav1.Instance, count int) []infrav1.Instance {
+	candidates := make([]infrav1.Instance, len(instances))
+	copy(candidates, instances)
+	zones := map[string]int{}
+	for _, i := range candidates {
+		zones[i.AvailabilityZone]++
+	}
+
+	version := func(i infrav1.Instance) int {
+		v, err := strconv.Atoi(i.Tags[launchTemplateVersionTagKey])
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+
+	selected := []infrav1.Instance{}
+	for len(selected) < count && len(candidates) > 0 {
+		sort.SliceStable(candidates, func(a, b int) bool {
+			if va, vb := version(candidates[a]), version(candidates[b]); va != vb {
+				return va < vb
+			}
+			if za, zb := zones[candidates[a].AvailabilityZone], zones[candidates[b].AvailabilityZone]; za != zb {
+				return za > zb
+			}
+			return candidates[a].ID < candidates[b].ID
+		})
+		selected = append(selected, candidates[0])
+		zones[candidates[0].AvailabilityZone]--
+		candidates = candidates[1:]
+	}
+	return selected
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// machinePoolMachineKind is the kind of the infrastructure machines created for the instances of an AWSMachinePool.
+const machinePoolMachineKind = "AWSMachine"
+
+// reconcileInfrastructureMachines maintains the AWSMachines of the instances of the pool when the MachinePoolMachines
+// feature gate is enabled.
+func (r *AWSMachinePoolReconciler) reconcileInfrastructureMachines(ctx context.Context, machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, instances []infrav1.Instance) error {
+	if !feature.Gates.Enabled(feature.MachinePoolMachines) {
+		machinePoolScope.AWSMachinePool.Status.InfrastructureMachineKind = ""
+		return nil
+	}
+
+	machinePoolScope.AWSMachinePool.Status.InfrastructureMachineKind = machinePoolMachineKind
+	return r.reconcileMachinePoolMachines(ctx, machinePoolScope, ec2Scope, instances)
+}
+
+// reconcileMachinePoolMachines creates an AWSMachine for each instance of the pool and refreshes their status. The
+// AWSMachines of the instances which left the pool are deleted, and the instances of the AWSMachines being deleted
+// are terminated.
+func (r *AWSMachinePoolReconciler) reconcileMachinePoolMachines(ctx context.Context, machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, instances []infrav1.Instance) error {
+	awsMachines, err := r.listMachinePoolMachines(ctx, machinePoolScope)
+	if err != nil {
+		return err
+	}
+
+	poolInstances := make(map[string]infrav1.Instance, len(instances))
+	for _, instance := range instances {
+		poolInstances[instance.ID] = instance
+	}
+
+	var errs []error
+	known := make(map[string]bool, len(awsMachines))
+	for i := range awsMachines {
+		awsMachine := &awsMachines[i]
+		instanceID := aws.StringValue(awsMachine.Spec.InstanceID)
+		known[instanceID] = true
+
+		instance, inPool := poolInstances[instanceID]
+		switch {
+		case !awsMachine.DeletionTimestamp.IsZero():
+			errs = append(errs, r.deleteMachinePoolMachine(ctx, machinePoolScope, ec2Scope, awsMachine, inPool))
+		case !inPool || isInstanceTerminating(instance):
+			machinePoolScope.Info("Deleting AWSMachine of an instance which left the pool", "awsMachine", awsMachine.Name, "instance-id", instanceID)
+			errs = append(errs, r.removeMachinePoolMachine(ctx, awsMachine))
+		default:
+			errs = append(errs, r.updateMachinePoolMachine(ctx, awsMachine, instance))
+		}
+	}
+
+	for _, instance := range instances {
+		if known[instance.ID] || isInstanceTerminating(instance) {
+			continue
+		}
+		errs = append(errs, r.createMachinePoolMachine(ctx, machinePoolScope, instance))
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// createMachinePoolMachine creates the AWSMachine of an instance of the pool.
+func (r *AWSMachinePoolReconciler) createMachinePoolMachine(ctx context.Context, machinePoolScope *scope.MachinePoolScope, instance infrav1.Instance) error {
+	pool := machinePoolScope.AWSMachinePool
+	awsMachine := &infrav1.AWSMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", pool.Name, instance.ID),
+			Namespace: pool.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:   machinePoolScope.Cluster.Name,
+				infrav1.MachinePoolNameLabel: machinePoolScope.MachinePool.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pool, infrav1exp.GroupVersion.WithKind("AWSMachinePool")),
+			},
+			Finalizers: []string{infrav1exp.MachinePoolMachineFinalizer},
+		},
+		Spec: infrav1.AWSMachineSpec{
+			ProviderID:   aws.String(fmt.Sprintf("aws:///%s/%s", instance.AvailabilityZone, instance.ID)),
+			InstanceID:   aws.String(instance.ID),
+			InstanceType: instance.Type,
+		},
+	}
+
+	machinePoolScope.Info("Creating AWSMachine for instance", "awsMachine", awsMachine.Name, "instance-id", instance.ID)
+	if err := r.Client.Create(ctx, awsMachine); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to create AWSMachine for instance %q", instance.ID)
+	}
+	return r.updateMachinePoolMachine(ctx, awsMachine, instance)
+}
+
+// updateMachinePoolMachine refreshes the status of the AWSMachine of an instance of the pool.
+func (r *AWSMachinePoolReconciler) updateMachinePoolMachine(ctx context.Context, awsMachine *infrav1.AWSMachine, instance infrav1.Instance) error {
+	patchHelper, err := patch.NewHelper(awsMachine, r.Client)
+	if err != nil {
+		return err
+	}
+
+	state := instance.State
+	awsMachine.Status.InstanceState = &state
+	awsMachine.Status.Ready = instance.State == infrav1.InstanceStateRunning
+	awsMachine.Status.Interruptible = instance.Lifecycle == infrav1.InstanceLifecycleSpot
+	awsMachine.Status.Addresses = instance.Addresses
+
+	return patchHelper.Patch(ctx, awsMachine)
+}
+
+// deleteMachinePoolMachine terminates the instance of an AWSMachine being deleted, unless it already left the pool,
+// and releases the AWSMachine. The replicas of the MachinePool are decremented so that the instance is not replaced,
+// unless the pool is at its minimum size.
+func (r *AWSMachinePoolReconciler) deleteMachinePoolMachine(ctx context.Context, machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, awsMachine *infrav1.AWSMachine, inPool bool) error {
+	instanceID := aws.StringValue(awsMachine.Spec.InstanceID)
+	if inPool {
+		decrement := canDecrementReplicas(machinePoolScope)
+		machinePoolScope.Info("Terminating instance of deleted AWSMachine", "awsMachine", awsMachine.Name, "instance-id", instanceID, "decrement-replicas", decrement)
+
+		var err error
+		if machinePoolScope.IsFleetBackend() {
+			err = r.getEC2Service(ec2Scope).TerminateInstance(instanceID)
+		} else {
+			err = r.getASGService(ec2Scope).TerminateInstanceInASG(instanceID, decrement)
+		}
+		if err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedTerminate", "Failed to terminate instance %q: %v", instanceID, err)
+			return err
+		}
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %q of AWSMachine %q", instanceID, awsMachine.Name)
+
+		if decrement {
+			if err := r.decrementMachinePoolReplicas(ctx, machinePoolScope); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.releaseMachinePoolMachine(ctx, awsMachine)
+}
+
+// decrementMachinePoolReplicas lowers the replicas of the MachinePool by one, after one of its instances was terminated
+// on purpose.
+func (r *AWSMachinePoolReconciler) decrementMachinePoolReplicas(ctx context.Context, machinePoolScope *scope.MachinePoolScope) error {
+	machinePool := machinePoolScope.MachinePool
+	patchHelper, err := patch.NewHelper(machinePool, r.Client)
+	if err != nil {
+		return err
+	}
+
+	replicas := *machinePool.Spec.Replicas - 1
+	machinePool.Spec.Replicas = &replicas
+	if err := patchHelper.Patch(ctx, machinePool); err != nil {
+		return errors.Wrapf(err, "failed to decrement the replicas of MachinePool %q", machinePool.Name)
+	}
+	return nil
+}
+
+// canDecrementReplicas returns whether the replicas of the MachinePool are above the minimum size of the pool.
+func canDecrementReplicas(machinePoolScope *scope.MachinePoolScope) bool {
+	replicas := machinePoolScope.MachinePool.Spec.Replicas
+	return replicas != nil && *replicas > machinePoolScope.AWSMachinePool.Spec.MinSize
+}
+
+// removeMachinePoolMachine deletes the AWSMachine of an instance which is not part of the pool anymore.
+func (r *AWSMachinePoolReconciler) removeMachinePoolMachine(ctx context.Context, awsMachine *infrav1.AWSMachine) error {
+	if err := r.releaseMachinePoolMachine(ctx, awsMachine); err != nil {
+		return err
+	}
+	if err := r.Client.Delete(ctx, awsMachine); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete AWSMachine %q", awsMachine.Name)
+	}
+	return nil
+}
+
+// releaseMachinePoolMachine removes the finalizer of the AWSMachine of an instance of the pool.
+func (r *AWSMachinePoolReconciler) releaseMachinePoolMachine(ctx context.Context, awsMachine *infrav1.AWSMachine) error {
+	if !controllerutil.ContainsFinalizer(awsMachine, infrav1exp.MachinePoolMachineFinalizer) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(awsMachine, r.Client)
+	if err != nil {
+		return err
+	}
+	controllerutil.RemoveFinalizer(awsMachine, infrav1exp.MachinePoolMachineFinalizer)
+	if err := patchHelper.Patch(ctx, awsMachine); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteMachinePoolMachines deletes the AWSMachines of the pool. Their instances are terminated with the pool.
+func (r *AWSMachinePoolReconciler) deleteMachinePoolMachines(ctx context.Context, machinePoolScope *scope.MachinePoolScope) error {
+	awsMachines, err := r.listMachinePoolMachines(ctx, machinePoolScope)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range awsMachines {
+		errs = append(errs, r.removeMachinePoolMachine(ctx, &awsMachines[i]))
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// listMachinePoolMachines returns the AWSMachines created for the instances of the pool.
+func (r *AWSMachinePoolReconciler) listMachinePoolMachines(ctx context.Context, machinePoolScope *scope.MachinePoolScope) ([]infrav1.AWSMachine, error) {
+	awsMachineList := &infrav1.AWSMachineList{}
+	if err := r.Client.List(ctx, awsMachineList,
+		client.InNamespace(machinePoolScope.AWSMachinePool.Namespace),
+		client.MatchingLabels{
+			clusterv1.ClusterLabelName:   machinePoolScope.Cluster.Name,
+			infrav1.MachinePoolNameLabel: machinePoolScope.MachinePool.Name,
+		},
+	); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachines of the pool")
+	}
+
+	awsMachines := make([]infrav1.AWSMachine, 0, len(awsMachineList.Items))
+	for i := range awsMachineList.Items {
+		if metav1.IsControlledBy(&awsMachineList.Items[i], machinePoolScope.AWSMachinePool) {
+			awsMachines = append(awsMachines, awsMachineList.Items[i])
+		}
+	}
+	return awsMachines, nil
+}
+
+// isInstanceTerminating returns whether the instance is shutting down or already terminated.
+func isInstanceTerminating(instance infrav1.Instance) bool {
+	return instance.State == infrav1.InstanceStateShuttingDown || instance.State == infrav1.InstanceStateTerminated
+}
@@ -54,8 +54,11 @@ func (r *AWSMachinePoolReconciler) ensureTags(ec2svc services.EC2MachineInterfac
 			return false, err
 		}
 
-		if err := asgsvc.UpdateResourceTags(asgName, created, deleted); err != nil {
-			return false, err
+		// Pools using the EC2Fleet backend have no ASG.
+		if asgName != nil {
+			if err := asgsvc.UpdateResourceTags(asgName, created, deleted); err != nil {
+				return false, err
+			}
 		}
 
 		// We also need to update the annotation if anything changed.
@@ -183,6 +183,12 @@ func (r *AWSManagedMachinePoolReconciler) reconcileNormal(
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile machine pool for AWSManagedMachinePool %s/%s", machinePoolScope.ManagedMachinePool.Namespace, machinePoolScope.ManagedMachinePool.Name)
 	}
 
+	// Check again once the pending update of the node group completed, and apply the configuration
+	// changes waiting for it.
+	if conditions.IsFalse(machinePoolScope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition) {
+		return ctrl.Result{RequeueAfter: machinePoolScope.NodegroupUpdateRequeueInterval()}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
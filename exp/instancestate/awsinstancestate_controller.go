@@ -18,221 +18,190 @@ package instancestate
 
 import (
 	"context"
-	"encoding/json"
 	"sync"
-	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/sqs"
-	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
-	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // Ec2InstanceStateLabelKey defines an ec2 instance state label.
 const Ec2InstanceStateLabelKey = "ec2-instance-state"
 
-// AwsInstanceStateReconciler reconciles a AwsInstanceState object.
+// AwsInstanceStateReconciler labels AWSMachines with the state of their EC2 instance when it changes, which
+// triggers a reconcile of the machine. State changes of all the clusters are received from the shared queue
+// of the management cluster by a single consumer, and published on an event bus the controller subscribes to.
 type AwsInstanceStateReconciler struct {
 	client.Client
-	Log               logr.Logger
-	sqsServiceFactory func() sqsiface.SQSAPI
-	queueURLs         sync.Map
-	Endpoints         []scope.ServiceEndpoint
-	WatchFilterValue  string
+	Log              logr.Logger
+	Queues           *instancestate.SharedQueues
+	WatchFilterValue string
+
+	// DrainMachinePools enables draining the nodes of the AWSMachinePool instances held by the node drain lifecycle
+	// hook of their ASG.
+	DrainMachinePools bool
+
+	bus           eventBus
+	states        sync.Map
+	machineEvents chan event.GenericEvent
 }
 
-// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,verbs=get;list;watch
 
-func (r *AwsInstanceStateReconciler) getSQSService(region string) (sqsiface.SQSAPI, error) {
-	if r.sqsServiceFactory != nil {
-		return r.sqsServiceFactory(), nil
+// Reconcile labels the AWSMachine with the last state received for its instance.
+func (r *AwsInstanceStateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	state, ok := r.states.LoadAndDelete(req.NamespacedName)
+	if !ok {
+		return ctrl.Result{}, nil
 	}
 
-	globalScope, err := scope.NewGlobalScope(scope.GlobalScopeParams{
-		ControllerName: "awsinstancestate",
-		Region:         region,
-		Endpoints:      r.Endpoints,
-	})
-
-	if err != nil {
-		return nil, err
-	}
-	return scope.NewGlobalSQSClient(globalScope, globalScope), nil
-}
-
-func (r *AwsInstanceStateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// Fetch the AWSCluster instance
-	awsCluster := &infrav1.AWSCluster{}
-	err := r.Get(ctx, req.NamespacedName, awsCluster)
-	if err != nil {
+	machine := &infrav1.AWSMachine{}
+	if err := r.Get(ctx, req.NamespacedName, machine); err != nil {
 		if apierrors.IsNotFound(err) {
-			r.Log.Info("cluster not found, removing queue URL", "cluster", req.Name)
-			r.queueURLs.Delete(req.Name)
-			return reconcile.Result{}, nil
+			return ctrl.Result{}, nil
 		}
-		return reconcile.Result{}, err
+		r.states.LoadOrStore(req.NamespacedName, state)
+		return ctrl.Result{}, err
 	}
 
-	// Handle deleted clusters
-	if !awsCluster.DeletionTimestamp.IsZero() {
-		r.Log.Info("cluster deleted, removing queue URL", "cluster", awsCluster.Name)
-		r.queueURLs.Delete(req.Name)
+	if !machine.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
 	}
 
-	// retrieve queue URL if it isn't already tracked
-	if _, ok := r.queueURLs.Load(awsCluster.Name); !ok {
-		URL, err := r.getQueueURL(awsCluster)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-		r.queueURLs.Store(awsCluster.Name, queueParams{region: awsCluster.Spec.Region, URL: URL})
+	if err := r.labelMachine(ctx, machine, state.(infrav1.InstanceState)); err != nil {
+		// Keep the state for the retry, unless a newer one was received in the meantime.
+		r.states.LoadOrStore(req.NamespacedName, state)
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *AwsInstanceStateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	go func() {
-		r.watchQueuesForInstanceEvents()
-	}()
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&infrav1.AWSCluster{}).
-		WithOptions(options).
-		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
-		Complete(r)
-}
-
-func (r *AwsInstanceStateReconciler) watchQueuesForInstanceEvents() {
-	ctx := context.TODO()
-	awsClusterList := &infrav1.AWSClusterList{}
-	if err := r.Client.List(ctx, awsClusterList); err == nil {
-		for i, cluster := range awsClusterList.Items {
-			if URL, err := r.getQueueURL(&awsClusterList.Items[i]); err == nil {
-				r.queueURLs.Store(cluster.Name, queueParams{region: cluster.Spec.Region, URL: URL})
-			}
-		}
+func (r *AwsInstanceStateReconciler) labelMachine(ctx context.Context, machine *infrav1.AWSMachine, state infrav1.InstanceState) error {
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return err
 	}
-	for range time.Tick(1 * time.Second) {
-		// go through each cluster and check for messages on its queue
-		r.queueURLs.Range(func(key, val interface{}) bool {
-			go func() {
-				qp := val.(queueParams)
-				sqsSvs, err := r.getSQSService(qp.region)
-				if err != nil {
-					r.Log.Error(err, "unable to create SQS client")
-					return
-				}
-				resp, err := sqsSvs.ReceiveMessage(&sqs.ReceiveMessageInput{QueueUrl: aws.String(qp.URL)})
-				if err != nil {
-					r.Log.Error(err, "failed to receive messages")
-					return
-				}
-				for _, msg := range resp.Messages {
-					m := message{}
-					err := json.Unmarshal([]byte(*msg.Body), &m)
-
-					if err != nil {
-						r.Log.Error(err, "unable to marshall")
-						return
-					}
-					// TODO: handle errors during process message. We currently deletes the message regardless.
-					r.processMessage(ctx, m)
-
-					_, err = sqsSvs.DeleteMessage(&sqs.DeleteMessageInput{
-						QueueUrl:      aws.String(qp.URL),
-						ReceiptHandle: msg.ReceiptHandle,
-					})
-
-					if err != nil {
-						r.Log.Error(err, "error deleting message", "queueURL", qp.URL, "messageReceiptHandle", msg.ReceiptHandle)
-					}
-				}
-			}()
 
-			return true
-		})
+	// Trigger an update on the machine
+	labels := machine.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
 	}
+	labels[Ec2InstanceStateLabelKey] = string(state)
+	machine.SetLabels(labels)
+
+	return patchHelper.Patch(ctx, machine)
 }
 
-// processMessage triggers a reconcile on an AWSMachine if its EC2 instance state changed.
-func (r *AwsInstanceStateReconciler) processMessage(ctx context.Context, msg message) {
-	if msg.Source != "aws.ec2" || msg.DetailType != instancestate.Ec2StateChangeNotification || msg.MessageDetail == nil {
-		return
-	}
+func (r *AwsInstanceStateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	r.machineEvents = make(chan event.GenericEvent)
 
-	// Fetch the awsMachine instance by InstanceID
-	awsMachines := &infrav1.AWSMachineList{}
-	err := r.List(ctx, awsMachines, client.MatchingFields{controllers.InstanceIDIndex: msg.MessageDetail.InstanceID})
+	events := make(chan instanceEvent)
+	r.bus.subscribe(events)
 
-	if err != nil {
-		r.Log.Error(err, "unable to list machines by instance ID", "instanceID", msg.MessageDetail.InstanceID)
+	if err := mgr.Add(&queueConsumer{client: mgr.GetClient(), queues: r.Queues, bus: &r.bus, log: r.Log.WithName("queue-consumer")}); err != nil {
+		return err
+	}
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		r.dispatchEvents(ctx, events)
+		return nil
+	})); err != nil {
+		return err
 	}
 
-	if len(awsMachines.Items) > 0 {
-		machine := awsMachines.Items[0]
-		if !machine.ObjectMeta.DeletionTimestamp.IsZero() {
-			return
-		}
-		patchHelper, err := patch.NewHelper(&machine, r.Client)
-		if err != nil {
-			r.Log.Error(err, "unable to create patch helper")
-		}
-		// Trigger an update on the machine
-		labels := machine.GetLabels()
-		if labels == nil {
-			labels = make(map[string]string)
-		}
+	options.Reconciler = r
+	c, err := controller.New("awsinstancestate", mgr, options)
+	if err != nil {
+		return err
+	}
 
-		labels[Ec2InstanceStateLabelKey] = string(msg.MessageDetail.State)
-		machine.SetLabels(labels)
+	if err := c.Watch(
+		&source.Channel{Source: r.machineEvents},
+		&handler.EnqueueRequestForObject{},
+		predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
+	); err != nil {
+		return err
+	}
 
-		err = patchHelper.Patch(ctx, &machine)
-		if err != nil {
-			r.Log.Error(err, "unable to patch AWS machine")
-		}
+	if r.DrainMachinePools {
+		return r.setupMachinePoolDrain(ctx, mgr, options)
 	}
+	return nil
 }
 
-// getQueueURL retrieves the SQS queue URL for a given cluster.
-func (r *AwsInstanceStateReconciler) getQueueURL(cluster *infrav1.AWSCluster) (string, error) {
-	sqsSvs, err := r.getSQSService(cluster.Spec.Region)
-	if err != nil {
-		return "", err
+// setupMachinePoolDrain subscribes the machine pool drain controller to the lifecycle actions received from the
+// shared queue.
+func (r *AwsInstanceStateReconciler) setupMachinePoolDrain(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	drainReconciler := newMachinePoolDrainReconciler(r.Client, r.Log.WithName("machinepool-drain"))
+
+	events := make(chan instanceEvent)
+	r.bus.subscribe(events)
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		drainReconciler.dispatchEvents(ctx, events)
+		return nil
+	})); err != nil {
+		return err
 	}
-	queueName := instancestate.GenerateQueueName(cluster.Name)
-	resp, err := sqsSvs.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(queueName)})
 
+	options.Reconciler = drainReconciler
+	c, err := controller.New(machinePoolDrainControllerName, mgr, options)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return *resp.QueueUrl, nil
+	// The pools are reconciled when their pending lifecycle actions are recorded, and at startup so that the actions
+	// received before a restart are completed.
+	return c.Watch(
+		&source.Kind{Type: &infrav1exp.AWSMachinePool{}},
+		&handler.EnqueueRequestForObject{},
+		predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
+	)
 }
 
-type queueParams struct {
-	region string
-	URL    string
-}
-
-type message struct {
-	Source        string         `json:"source"`
-	DetailType    string         `json:"detail-type,omitempty"`
-	MessageDetail *messageDetail `json:"detail,omitempty"`
-}
-
-type messageDetail struct {
-	InstanceID string                `json:"instance-id,omitempty"`
-	State      infrav1.InstanceState `json:"state,omitempty"`
+// dispatchEvents records the state received for the instance of each AWSMachine and triggers a reconcile of
+// the machine.
+func (r *AwsInstanceStateReconciler) dispatchEvents(ctx context.Context, events <-chan instanceEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-events:
+			if e.LifecycleAction != nil {
+				e.ack(nil)
+				continue
+			}
+			awsMachines := &infrav1.AWSMachineList{}
+			if err := r.List(ctx, awsMachines, client.MatchingFields{controllers.InstanceIDIndex: e.InstanceID}); err != nil {
+				r.Log.Error(err, "unable to list machines by instance ID", "instanceID", e.InstanceID)
+				e.ack(err)
+				continue
+			}
+			e.ack(nil)
+
+			for i := range awsMachines.Items {
+				machine := &awsMachines.Items[i]
+				r.states.Store(types.NamespacedName{Namespace: machine.Namespace, Name: machine.Name}, e.State)
+				select {
+				case r.machineEvents <- event.GenericEvent{Object: machine}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
 }
@@ -25,15 +25,20 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/sqs"
-	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/golang/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_eventbridgeiface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/sts/mock_stsiface"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,30 +47,44 @@ import (
 func TestAWSInstanceStateController(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	sqsSvs = mock_sqsiface.NewMockSQSAPI(mockCtrl)
+	eventBridgeSvs := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+	stsSvs := mock_stsiface.NewMockSTSAPI(mockCtrl)
+	queues, err := instancestate.NewSharedQueues(nil, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := queues.List()[0]
+	queue.EventBridgeClient = eventBridgeSvs
+	queue.SQSClient = sqsSvs
+	queue.STSClient = stsSvs
 	instanceStateReconciler = &AwsInstanceStateReconciler{
 		Client: testEnv.Client,
 		Log:    ctrl.Log.WithName("controllers").WithName("AWSInstanceState"),
-		sqsServiceFactory: func() sqsiface.SQSAPI {
-			return sqsSvs
-		},
+		Queues: queues,
 	}
 	defer mockCtrl.Finish()
 
-	t.Run("should maintain list of cluster queue URLs and reconcile failing machines", func(t *testing.T) {
+	t.Run("should reconcile failing machines from the messages of the shared queue", func(t *testing.T) {
 		g := NewWithT(t)
 
 		failingMachineMeta := metav1.ObjectMeta{
 			Name:      "aws-cluster-1-instance-1",
 			Namespace: "default",
 		}
-		sqsSvs.EXPECT().GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("aws-cluster-1-queue")}).AnyTimes().
-			Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("aws-cluster-1-url")}, nil)
-		sqsSvs.EXPECT().GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("aws-cluster-2-queue")}).AnyTimes().
-			Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("aws-cluster-2-url")}, nil)
-		sqsSvs.EXPECT().GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("aws-cluster-3-queue")}).AnyTimes().
-			Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("aws-cluster-3-url")}, nil)
-		sqsSvs.EXPECT().ReceiveMessage(&sqs.ReceiveMessageInput{QueueUrl: aws.String("aws-cluster-1-url")}).AnyTimes().
-			DoAndReturn(func(arg *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+
+		stsSvs.EXPECT().GetCallerIdentity(gomock.Any()).AnyTimes().
+			Return(&sts.GetCallerIdentityOutput{Account: aws.String("111111111111")}, nil)
+		sqsSvs.EXPECT().GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(instancestate.SharedQueueName)}).AnyTimes().
+			Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("shared-queue-url")}, nil)
+		sqsSvs.EXPECT().GetQueueAttributes(gomock.Any()).AnyTimes().
+			Return(&sqs.GetQueueAttributesOutput{Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNameQueueArn: "shared-queue-arn"})}, nil)
+		sqsSvs.EXPECT().SetQueueAttributes(gomock.Any()).AnyTimes().Return(&sqs.SetQueueAttributesOutput{}, nil)
+		eventBridgeSvs.EXPECT().PutRule(gomock.Any()).AnyTimes().Return(&eventbridge.PutRuleOutput{RuleArn: aws.String("shared-rule-arn")}, nil)
+		eventBridgeSvs.EXPECT().PutTargets(gomock.Any()).AnyTimes().Return(&eventbridge.PutTargetsOutput{}, nil)
+
+		sqsSvs.EXPECT().ReceiveMessageWithContext(gomock.Any(), gomock.Any()).AnyTimes().
+			DoAndReturn(func(_ context.Context, arg *sqs.ReceiveMessageInput, _ ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+				g.Expect(aws.StringValue(arg.QueueUrl)).To(Equal("shared-queue-url"))
 				m := &infrav1.AWSMachine{}
 				lookupKey := types.NamespacedName{
 					Namespace: failingMachineMeta.Namespace,
@@ -82,14 +101,11 @@ func TestAWSInstanceStateController(t *testing.T) {
 					}, nil
 				}
 
+				// Stand in for the long polling of the queue.
+				time.Sleep(100 * time.Millisecond)
 				return &sqs.ReceiveMessageOutput{Messages: []*sqs.Message{}}, nil
 			})
-
-		sqsSvs.EXPECT().ReceiveMessage(&sqs.ReceiveMessageInput{QueueUrl: aws.String("aws-cluster-2-url")}).AnyTimes().
-			Return(&sqs.ReceiveMessageOutput{Messages: []*sqs.Message{}}, nil)
-		sqsSvs.EXPECT().ReceiveMessage(&sqs.ReceiveMessageInput{QueueUrl: aws.String("aws-cluster-3-url")}).AnyTimes().
-			Return(&sqs.ReceiveMessageOutput{Messages: []*sqs.Message{}}, nil)
-		sqsSvs.EXPECT().DeleteMessage(&sqs.DeleteMessageInput{QueueUrl: aws.String("aws-cluster-1-url"), ReceiptHandle: aws.String("message-receipt-handle")}).AnyTimes().
+		sqsSvs.EXPECT().DeleteMessageWithContext(gomock.Any(), &sqs.DeleteMessageInput{QueueUrl: aws.String("shared-queue-url"), ReceiptHandle: aws.String("message-receipt-handle")}).AnyTimes().
 			Return(nil, nil)
 
 		g.Expect(testEnv.Manager.GetFieldIndexer().IndexField(context.Background(), &infrav1.AWSMachine{},
@@ -116,7 +132,6 @@ func TestAWSInstanceStateController(t *testing.T) {
 		k8sClient = testEnv.GetClient()
 
 		persistObject(g, createAWSCluster("aws-cluster-1"))
-		persistObject(g, createAWSCluster("aws-cluster-2"))
 
 		machine1 := &infrav1.AWSMachine{
 			Spec: infrav1.AWSMachineSpec{
@@ -126,34 +141,6 @@ func TestAWSInstanceStateController(t *testing.T) {
 		}
 		persistObject(g, machine1)
 
-		t.Log("Ensuring queue URLs are up-to-date")
-		g.Eventually(func() bool {
-			exist := true
-			for _, cluster := range []string{"aws-cluster-1", "aws-cluster-2"} {
-				_, ok := instanceStateReconciler.queueURLs.Load(cluster)
-				exist = exist && ok
-			}
-			return exist
-		}, 10*time.Second).Should(Equal(true))
-
-		deleteAWSCluster(g, "aws-cluster-2")
-		t.Log("Ensuring we stop tracking deleted queue")
-		g.Eventually(func() bool {
-			_, ok := instanceStateReconciler.queueURLs.Load("aws-cluster-2")
-			return ok
-		}, 10*time.Second).Should(Equal(false))
-
-		persistObject(g, createAWSCluster("aws-cluster-3"))
-		t.Log("Ensuring newly created cluster is added to tracked clusters")
-		g.Eventually(func() bool {
-			exist := true
-			for _, cluster := range []string{"aws-cluster-1", "aws-cluster-3"} {
-				_, ok := instanceStateReconciler.queueURLs.Load(cluster)
-				exist = exist && ok
-			}
-			return exist
-		}, 10*time.Second).Should(Equal(true))
-
 		t.Log("Ensuring machine is labelled with correct instance state")
 		g.Eventually(func() bool {
 			m := &infrav1.AWSMachine{}
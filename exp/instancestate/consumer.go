@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-logr/logr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+)
+
+const (
+	// receiveWaitTimeSeconds is how long a receive call waits for messages to arrive on the queue.
+	receiveWaitTimeSeconds = 20
+
+	// consumerRetryInterval is how long the consumer waits after failing to talk to the queue.
+	consumerRetryInterval = 10 * time.Second
+
+	// consumerPausedInterval is how often the paused consumer checks whether a cluster was resumed.
+	consumerPausedInterval = 30 * time.Second
+)
+
+// queueConsumer receives the messages of the shared queues of every region and publishes the instance events they
+// carry on the event bus. It is the only reader of the queues, so it only runs on the leader.
+//
+// The consumer pauses while all the Clusters of the management cluster are paused, or there are none. The queue is
+// named the same in every management cluster, so a management cluster the clusters were moved away from with
+// clusterctl move, in the same account and region, would otherwise keep taking their messages from the queue, and
+// drop them.
+type queueConsumer struct {
+	client client.Reader
+	queues *instancestate.SharedQueues
+	bus    *eventBus
+	log    logr.Logger
+
+	paused bool
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *queueConsumer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start receives messages until the context is done.
+func (c *queueConsumer) Start(ctx context.Context) error {
+	for ctx.Err() == nil {
+		active, err := c.hasActiveClusters(ctx)
+		if err != nil {
+			c.log.Error(err, "failed to list clusters")
+			wait(ctx, consumerRetryInterval)
+			continue
+		}
+		if active == c.paused {
+			c.paused = !active
+			if c.paused {
+				c.log.Info("Pausing the instance state queue consumer, all clusters are paused", "queue", instancestate.SharedQueueName)
+			} else {
+				c.log.Info("Resuming the instance state queue consumer", "queue", instancestate.SharedQueueName)
+			}
+		}
+		if c.paused {
+			wait(ctx, consumerPausedInterval)
+			continue
+		}
+
+		queues := c.queues.List()
+		if len(queues) == 0 {
+			// No cluster has been reconciled yet.
+			wait(ctx, consumerRetryInterval)
+			continue
+		}
+		if failed := c.receiveAll(ctx, queues); failed && ctx.Err() == nil {
+			wait(ctx, consumerRetryInterval)
+		}
+	}
+
+	return nil
+}
+
+// receiveAll receives the messages of all the queues at once, and tells whether any of them failed.
+func (c *queueConsumer) receiveAll(ctx context.Context, queues []*instancestate.SharedQueue) bool {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(queue *instancestate.SharedQueue) {
+			defer wg.Done()
+			if err := c.receive(ctx, queue); err != nil && ctx.Err() == nil {
+				c.log.Error(err, "failed to receive instance state changes", "queue", instancestate.SharedQueueName, "region", queue.Region())
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}(queue)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// hasActiveClusters tells whether any Cluster of the management cluster is not paused.
+func (c *queueConsumer) hasActiveClusters(ctx context.Context) (bool, error) {
+	clusters := &clusterv1.ClusterList{}
+	if err := c.client.List(ctx, clusters); err != nil {
+		return false, err
+	}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if !cluster.Spec.Paused && !annotations.HasPausedAnnotation(cluster) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// wait returns after the given duration, or when the context is done.
+func wait(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+func (c *queueConsumer) receive(ctx context.Context, queue *instancestate.SharedQueue) error {
+	if err := queue.Reconcile(); err != nil {
+		return err
+	}
+	queueURL := queue.URL()
+
+	resp, err := queue.SQSClient.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(queueURL),
+		MaxNumberOfMessages: aws.Int64(10),
+		WaitTimeSeconds:     aws.Int64(receiveWaitTimeSeconds),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range resp.Messages {
+		m := message{}
+		if err := json.Unmarshal([]byte(aws.StringValue(msg.Body)), &m); err != nil {
+			c.log.Error(err, "unable to unmarshal message, dropping it", "messageID", aws.StringValue(msg.MessageId))
+		} else if e, ok := m.instanceEvent(); ok {
+			if err := c.bus.publish(ctx, e); err != nil {
+				// The message is received again once its visibility timeout expires.
+				return err
+			}
+		}
+
+		if _, err := queue.SQSClient.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(queueURL),
+			ReceiptHandle: msg.ReceiptHandle,
+		}); err != nil {
+			c.log.Error(err, "error deleting message", "queueURL", queueURL, "messageReceiptHandle", aws.StringValue(msg.ReceiptHandle))
+		}
+	}
+
+	return nil
+}
+
+type message struct {
+	Source        string         `json:"source"`
+	DetailType    string         `json:"detail-type,omitempty"`
+	MessageDetail *messageDetail `json:"detail,omitempty"`
+}
+
+type messageDetail struct {
+	InstanceID string                `json:"instance-id,omitempty"`
+	State      infrav1.InstanceState `json:"state,omitempty"`
+
+	// Details of lifecycle actions.
+	EC2InstanceID        string `json:"EC2InstanceId,omitempty"`
+	AutoScalingGroupName string `json:"AutoScalingGroupName,omitempty"`
+	LifecycleHookName    string `json:"LifecycleHookName,omitempty"`
+	LifecycleActionToken string `json:"LifecycleActionToken,omitempty"`
+}
+
+func (m message) isInstanceStateChange() bool {
+	return m.Source == "aws.ec2" && m.DetailType == instancestate.Ec2StateChangeNotification && m.MessageDetail != nil
+}
+
+func (m message) isTerminateLifecycleAction() bool {
+	return m.Source == "aws.autoscaling" && m.DetailType == instancestate.Ec2TerminateLifecycleAction && m.MessageDetail != nil
+}
+
+// instanceEvent returns the event carried by the message, if it is one the controllers react to.
+func (m message) instanceEvent() (instanceEvent, bool) {
+	switch {
+	case m.isInstanceStateChange():
+		return instanceEvent{InstanceID: m.MessageDetail.InstanceID, State: m.MessageDetail.State}, true
+	case m.isTerminateLifecycleAction():
+		return instanceEvent{
+			InstanceID: m.MessageDetail.EC2InstanceID,
+			LifecycleAction: &lifecycleAction{
+				AutoScalingGroupName: m.MessageDetail.AutoScalingGroupName,
+				HookName:             m.MessageDetail.LifecycleHookName,
+				Token:                m.MessageDetail.LifecycleActionToken,
+			},
+		}, true
+	default:
+		return instanceEvent{}, false
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQueueConsumer_HasActiveClusters(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(testScheme)
+
+	cluster := func(name string, paused bool, annotations map[string]string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec:       clusterv1.ClusterSpec{Paused: paused},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		clusters []client.Object
+		expect   bool
+	}{
+		{
+			name:   "no clusters",
+			expect: false,
+		},
+		{
+			name: "all clusters paused, as during clusterctl move",
+			clusters: []client.Object{
+				cluster("paused", true, nil),
+				cluster("annotated", false, map[string]string{clusterv1.PausedAnnotation: ""}),
+			},
+			expect: false,
+		},
+		{
+			name: "a cluster is not paused",
+			clusters: []client.Object{
+				cluster("paused", true, nil),
+				cluster("active", false, nil),
+			},
+			expect: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &queueConsumer{client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(tc.clusters...).Build()}
+			active, err := c.hasActiveClusters(context.TODO())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(active).To(Equal(tc.expect))
+		})
+	}
+}
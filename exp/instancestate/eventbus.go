@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"context"
+	"sync"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+// instanceEvent is a change of the state of an EC2 instance received from the shared queue, or a lifecycle action
+// holding an instance of an ASG.
+type instanceEvent struct {
+	InstanceID      string
+	State           infrav1.InstanceState
+	LifecycleAction *lifecycleAction
+
+	// handled receives the outcome of handling the event from each subscriber.
+	handled chan<- error
+}
+
+// ack tells the publisher the subscriber is done with the event. Subscribers call it once for every event they
+// receive, with the error that prevented them from handling it, if any.
+func (e instanceEvent) ack(err error) {
+	if e.handled != nil {
+		e.handled <- err
+	}
+}
+
+// lifecycleAction is an instance held in the Terminating:Wait state by a lifecycle hook of an ASG, until the
+// action is completed or the hook times out.
+type lifecycleAction struct {
+	AutoScalingGroupName string `json:"autoScalingGroupName"`
+	HookName             string `json:"hookName"`
+	Token                string `json:"token"`
+}
+
+// eventBus fans the instance events received by the queue consumer out to the subscribers of the controller.
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers []chan<- instanceEvent
+}
+
+// subscribe registers a channel receiving every event published after the call.
+func (b *eventBus) subscribe(ch chan<- instanceEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, ch)
+}
+
+// publish sends the event to every subscriber, blocking until they all handled it or the context is done. It returns
+// the error of the first subscriber that failed to handle the event, so that the message carrying it is kept in the
+// queue and received again.
+func (b *eventBus) publish(ctx context.Context, e instanceEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	handled := make(chan error, len(b.subscribers))
+	e.handled = handled
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var firstErr error
+	for range b.subscribers {
+		select {
+		case err := <-handled:
+			if firstErr == nil {
+				firstErr = err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestEventBus_Publish(t *testing.T) {
+	subscribe := func(bus *eventBus, err error) {
+		events := make(chan instanceEvent)
+		bus.subscribe(events)
+		go func() {
+			e := <-events
+			e.ack(err)
+		}()
+	}
+
+	t.Run("returns once every subscriber handled the event", func(t *testing.T) {
+		g := NewWithT(t)
+		bus := &eventBus{}
+		subscribe(bus, nil)
+		subscribe(bus, nil)
+
+		g.Expect(bus.publish(context.Background(), instanceEvent{InstanceID: "i-0123"})).To(Succeed())
+	})
+
+	t.Run("returns the error of a subscriber which failed to handle the event", func(t *testing.T) {
+		g := NewWithT(t)
+		bus := &eventBus{}
+		subscribe(bus, nil)
+		subscribe(bus, errors.New("some error"))
+
+		g.Expect(bus.publish(context.Background(), instanceEvent{InstanceID: "i-0123"})).To(MatchError("some error"))
+	})
+}
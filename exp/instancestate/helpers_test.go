@@ -22,9 +22,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -52,18 +50,3 @@ func persistObject(g *WithT, o client.Object) {
 		return err == nil
 	}, time.Second*10).Should(BeTrue())
 }
-
-func deleteAWSCluster(g *WithT, name string) {
-	ctx := context.TODO()
-	awsLookupKey := types.NamespacedName{Name: name, Namespace: "default"}
-	awsCluster := &infrav1.AWSCluster{}
-	err := k8sClient.Get(ctx, awsLookupKey, awsCluster)
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			// already deleted
-			return
-		}
-		Fail("Unexpected error when fetching cluster")
-	}
-	g.Expect(k8sClient.Delete(ctx, awsCluster)).To(Succeed())
-}
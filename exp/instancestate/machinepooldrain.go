@@ -0,0 +1,368 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	controllersexp "sigs.k8s.io/cluster-api-provider-aws/exp/controllers"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	asg "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	kubedrain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PendingLifecycleActionsAnnotation records the lifecycle actions of the instances of an AWSMachinePool waiting
+	// for their node to be drained, so that they survive a restart of the controller once their message was deleted
+	// from the queue.
+	PendingLifecycleActionsAnnotation = "aws.cluster.x-k8s.io/pending-lifecycle-actions"
+
+	machinePoolDrainControllerName = "awsmachinepooldrain"
+
+	// drainRetryInterval bounds each drain attempt, so that a node with pods that cannot be evicted yet does not
+	// block the other machine pools.
+	drainRetryInterval = 20 * time.Second
+)
+
+// pendingAction is a lifecycle action of an instance of a machine pool waiting for its node to be drained.
+type pendingAction struct {
+	lifecycleAction
+	Deadline metav1.Time `json:"deadline"`
+}
+
+// machinePoolDrainReconciler drains the node of the instances held by the node drain lifecycle hook of the ASG of an
+// AWSMachinePool, then completes their lifecycle action so that the ASG terminates them.
+type machinePoolDrainReconciler struct {
+	client.Client
+	Log logr.Logger
+
+	asgServiceFactory func(cloud.ClusterScoper) services.ASGInterface
+	drainNodeFunc     func(ctx context.Context, cluster *clusterv1.Cluster, instanceID string) error
+}
+
+func newMachinePoolDrainReconciler(c client.Client, log logr.Logger) *machinePoolDrainReconciler {
+	return &machinePoolDrainReconciler{
+		Client: c,
+		Log:    log,
+	}
+}
+
+func (r *machinePoolDrainReconciler) getASGService(scope cloud.ClusterScoper) services.ASGInterface {
+	if r.asgServiceFactory != nil {
+		return r.asgServiceFactory(scope)
+	}
+	return asg.NewService(scope)
+}
+
+func (r *machinePoolDrainReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, instanceID string) error {
+	if r.drainNodeFunc != nil {
+		return r.drainNodeFunc(ctx, cluster, instanceID)
+	}
+	return r.drainWorkloadNode(ctx, cluster, instanceID)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+
+// Reconcile drains the nodes of the instances of the AWSMachinePool held by a lifecycle action, and completes the
+// actions of the drained ones.
+func (r *machinePoolDrainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("namespace", req.Namespace, "awsMachinePool", req.Name)
+
+	awsMachinePool := &infrav1exp.AWSMachinePool{}
+	if err := r.Get(ctx, req.NamespacedName, awsMachinePool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	actions, err := pendingActions(awsMachinePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(actions) == 0 {
+		return ctrl.Result{}, nil
+	}
+	instanceIDs := make([]string, 0, len(actions))
+	for instanceID := range actions {
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, awsMachinePool.ObjectMeta)
+	if err != nil {
+		// The instances are terminated anyway once the hook times out.
+		log.Info("AWSMachinePool is missing cluster label or cluster does not exist, not draining its nodes")
+		return ctrl.Result{}, r.forgetActions(ctx, req.NamespacedName, instanceIDs...)
+	}
+
+	infraCluster, err := controllersexp.GetInfraCluster(ctx, r.Client, log, cluster, awsMachinePool)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error getting infra provider cluster or control plane object")
+	}
+	if infraCluster == nil {
+		log.Info("AWSCluster or AWSManagedControlPlane is not ready yet")
+		return ctrl.Result{}, r.forgetActions(ctx, req.NamespacedName, instanceIDs...)
+	}
+	asgSvc := r.getASGService(infraCluster)
+
+	requeue := false
+	done := []string{}
+	for instanceID, action := range actions {
+		if time.Now().After(action.Deadline.Time) {
+			// The hook timed out and the ASG already continued the termination.
+			log.Info("Lifecycle hook timed out before the node was drained", "instanceID", instanceID)
+			done = append(done, instanceID)
+			continue
+		}
+
+		if err := r.drainNode(ctx, cluster, instanceID); err != nil {
+			log.Error(err, "Drain failed, retry in 20s", "instanceID", instanceID)
+			requeue = true
+			continue
+		}
+
+		if err := asgSvc.CompleteLifecycleAction(action.AutoScalingGroupName, action.HookName, instanceID, action.Token); err != nil {
+			return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.forgetActions(ctx, req.NamespacedName, done...)})
+		}
+		log.Info("Drain successful, completed lifecycle action", "instanceID", instanceID)
+		done = append(done, instanceID)
+	}
+
+	if err := r.forgetActions(ctx, req.NamespacedName, done...); err != nil {
+		return ctrl.Result{}, err
+	}
+	if requeue {
+		return ctrl.Result{RequeueAfter: drainRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// drainWorkloadNode cordons and drains the node of the instance in the workload cluster. It succeeds when the
+// instance never joined the cluster.
+func (r *machinePoolDrainReconciler) drainWorkloadNode(ctx context.Context, cluster *clusterv1.Cluster, instanceID string) error {
+	restConfig, err := remote.RESTConfig(ctx, machinePoolDrainControllerName, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to get workload cluster config")
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create workload cluster client")
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list workload cluster nodes")
+	}
+	var node *corev1.Node
+	for i := range nodes.Items {
+		if strings.HasSuffix(nodes.Items[i].Spec.ProviderID, "/"+instanceID) {
+			node = &nodes.Items[i]
+			break
+		}
+	}
+	if node == nil {
+		return nil
+	}
+
+	log := r.Log.WithValues("cluster", cluster.Name, "node", node.Name)
+	drainer := &kubedrain.Helper{
+		Client:              kubeClient,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteLocalData:     true,
+		GracePeriodSeconds:  -1,
+		Timeout:             drainRetryInterval,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			verbStr := "Deleted"
+			if usingEviction {
+				verbStr = "Evicted"
+			}
+			log.Info(fmt.Sprintf("%s pod from Node", verbStr), "pod", fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		},
+		Out:    writer{func(msg string) { log.Info(msg) }},
+		ErrOut: writer{func(msg string) { log.Error(nil, msg) }},
+	}
+
+	if err := kubedrain.RunCordonOrUncordon(ctx, drainer, node, true); err != nil {
+		return errors.Wrapf(err, "unable to cordon node %s", node.Name)
+	}
+	if err := kubedrain.RunNodeDrain(ctx, drainer, node.Name); err != nil {
+		return errors.Wrapf(err, "unable to drain node %s", node.Name)
+	}
+	return nil
+}
+
+// dispatchEvents records the lifecycle actions of the node drain hook for the instances of each AWSMachinePool on the
+// pool, which triggers a reconcile of the pool.
+func (r *machinePoolDrainReconciler) dispatchEvents(ctx context.Context, events <-chan instanceEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-events:
+			if e.LifecycleAction == nil || e.LifecycleAction.HookName != asg.NodeDrainLifecycleHookName {
+				e.ack(nil)
+				continue
+			}
+			pool, err := r.findMachinePool(ctx, e.LifecycleAction.AutoScalingGroupName, e.InstanceID)
+			if err != nil {
+				r.Log.Error(err, "unable to find machine pool of instance", "instanceID", e.InstanceID)
+				e.ack(err)
+				continue
+			}
+			if pool == nil {
+				e.ack(nil)
+				continue
+			}
+
+			if err := r.storeAction(ctx, pool, e.InstanceID, *e.LifecycleAction); err != nil {
+				r.Log.Error(err, "unable to record lifecycle action of instance", "instanceID", e.InstanceID)
+				e.ack(err)
+				continue
+			}
+			e.ack(nil)
+		}
+	}
+}
+
+// findMachinePool returns the AWSMachinePool owning the ASG that has the instance, or nil when the ASG is not
+// managed by the controller.
+func (r *machinePoolDrainReconciler) findMachinePool(ctx context.Context, asgName, instanceID string) (*infrav1exp.AWSMachinePool, error) {
+	pools := &infrav1exp.AWSMachinePoolList{}
+	if err := r.List(ctx, pools); err != nil {
+		return nil, err
+	}
+
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		if pool.Name != asgName {
+			continue
+		}
+		for _, providerID := range pool.Spec.ProviderIDList {
+			if strings.HasSuffix(providerID, "/"+instanceID) {
+				return pool, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *machinePoolDrainReconciler) storeAction(ctx context.Context, pool *infrav1exp.AWSMachinePool, instanceID string, action lifecycleAction) error {
+	// The ASG continues the termination once the heartbeat timeout of the hook expires.
+	deadline := time.Now()
+	if pool.Spec.NodeDrainTimeout != nil {
+		deadline = deadline.Add(pool.Spec.NodeDrainTimeout.Duration)
+	}
+
+	return r.updateActions(ctx, types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}, func(actions map[string]pendingAction) {
+		actions[instanceID] = pendingAction{lifecycleAction: action, Deadline: metav1.NewTime(deadline)}
+	})
+}
+
+func (r *machinePoolDrainReconciler) forgetActions(ctx context.Context, key types.NamespacedName, instanceIDs ...string) error {
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	return r.updateActions(ctx, key, func(actions map[string]pendingAction) {
+		for _, instanceID := range instanceIDs {
+			delete(actions, instanceID)
+		}
+	})
+}
+
+// updateActions applies the update to the pending actions recorded on the AWSMachinePool. The actions are read again
+// on conflicts, since the dispatcher and the reconciler update them concurrently.
+func (r *machinePoolDrainReconciler) updateActions(ctx context.Context, key types.NamespacedName, update func(map[string]pendingAction)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool := &infrav1exp.AWSMachinePool{}
+		if err := r.Get(ctx, key, pool); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		actions, err := pendingActions(pool)
+		if err != nil {
+			return err
+		}
+		update(actions)
+
+		original := pool.DeepCopy()
+		if len(actions) == 0 {
+			delete(pool.Annotations, PendingLifecycleActionsAnnotation)
+		} else {
+			data, err := json.Marshal(actions)
+			if err != nil {
+				return err
+			}
+			if pool.Annotations == nil {
+				pool.Annotations = map[string]string{}
+			}
+			pool.Annotations[PendingLifecycleActionsAnnotation] = string(data)
+		}
+
+		return r.Patch(ctx, pool, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// pendingActions returns the lifecycle actions recorded on the AWSMachinePool, by instance ID.
+func pendingActions(pool *infrav1exp.AWSMachinePool) (map[string]pendingAction, error) {
+	actions := map[string]pendingAction{}
+	data, ok := pool.Annotations[PendingLifecycleActionsAnnotation]
+	if !ok {
+		return actions, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &actions); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse annotation %s", PendingLifecycleActionsAnnotation)
+	}
+	return actions, nil
+}
+
+// writer implements io.Writer as a pass-through for the output of the drain helper.
+type writer struct {
+	logFunc func(msg string)
+}
+
+// Write passes string(p) into writer's logFunc and always returns len(p).
+func (w writer) Write(p []byte) (n int, err error) {
+	w.logFunc(string(p))
+	return len(p), nil
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	asg "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/mock_services"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMessageInstanceEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   instanceEvent
+		wantOk bool
+	}{
+		{
+			name:   "instance state change",
+			body:   `{"source":"aws.ec2","detail-type":"EC2 Instance State-change Notification","detail":{"instance-id":"i-0123","state":"stopping"}}`,
+			want:   instanceEvent{InstanceID: "i-0123", State: infrav1.InstanceStateStopping},
+			wantOk: true,
+		},
+		{
+			name: "terminate lifecycle action",
+			body: `{"source":"aws.autoscaling","detail-type":"EC2 Instance-terminate Lifecycle Action","detail":{"EC2InstanceId":"i-0123","AutoScalingGroupName":"pool","LifecycleHookName":"cluster-api-provider-aws-node-drain","LifecycleActionToken":"token","LifecycleTransition":"autoscaling:EC2_INSTANCE_TERMINATING"}}`,
+			want: instanceEvent{
+				InstanceID: "i-0123",
+				LifecycleAction: &lifecycleAction{
+					AutoScalingGroupName: "pool",
+					HookName:             asg.NodeDrainLifecycleHookName,
+					Token:                "token",
+				},
+			},
+			wantOk: true,
+		},
+		{
+			name: "other event",
+			body: `{"source":"aws.autoscaling","detail-type":"EC2 Instance Launch Successful","detail":{"EC2InstanceId":"i-0123"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := message{}
+			g.Expect(json.Unmarshal([]byte(tt.body), &m)).To(Succeed())
+			e, ok := m.instanceEvent()
+			g.Expect(ok).To(Equal(tt.wantOk))
+			g.Expect(e).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolDrainReconciler_DispatchEvents(t *testing.T) {
+	pool := &infrav1exp.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+		Spec: infrav1exp.AWSMachinePoolSpec{
+			ProviderIDList:   []string{"aws:///us-east-1a/i-0123", "aws:///us-east-1a/i-0456"},
+			NodeDrainTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+		},
+	}
+	otherPool := &infrav1exp.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-pool", Namespace: "default"},
+		Spec: infrav1exp.AWSMachinePoolSpec{
+			ProviderIDList: []string{"aws:///us-east-1a/i-0789"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		event      instanceEvent
+		wantAction bool
+	}{
+		{
+			name: "records the action of an instance of the pool",
+			event: instanceEvent{
+				InstanceID:      "i-0456",
+				LifecycleAction: &lifecycleAction{AutoScalingGroupName: "pool", HookName: asg.NodeDrainLifecycleHookName, Token: "token"},
+			},
+			wantAction: true,
+		},
+		{
+			name: "ignores the hooks of other components",
+			event: instanceEvent{
+				InstanceID:      "i-0456",
+				LifecycleAction: &lifecycleAction{AutoScalingGroupName: "pool", HookName: "other-hook", Token: "token"},
+			},
+		},
+		{
+			name: "ignores instances of unmanaged ASGs",
+			event: instanceEvent{
+				InstanceID:      "i-0789",
+				LifecycleAction: &lifecycleAction{AutoScalingGroupName: "pool", HookName: asg.NodeDrainLifecycleHookName, Token: "token"},
+			},
+		},
+		{
+			name:  "ignores state changes",
+			event: instanceEvent{InstanceID: "i-0123", State: infrav1.InstanceStateStopping},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pool.DeepCopy(), otherPool.DeepCopy()).Build()
+			r := newMachinePoolDrainReconciler(c, ctrl.Log)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events := make(chan instanceEvent)
+			go r.dispatchEvents(ctx, events)
+			handled := make(chan error, 1)
+			e := tt.event
+			e.handled = handled
+			events <- e
+			g.Expect(<-handled).To(Succeed())
+
+			actions := poolActions(g, c, types.NamespacedName{Namespace: "default", Name: "pool"})
+			if !tt.wantAction {
+				g.Expect(actions).To(BeEmpty())
+				return
+			}
+			g.Expect(actions).To(HaveKey("i-0456"))
+			g.Expect(actions["i-0456"].lifecycleAction).To(Equal(*tt.event.LifecycleAction))
+			g.Expect(actions["i-0456"].Deadline.Time).To(BeTemporally("~", time.Now().Add(5*time.Minute), time.Second))
+		})
+	}
+}
+
+func TestMachinePoolDrainReconciler_Reconcile(t *testing.T) {
+	poolKey := types.NamespacedName{Namespace: "default", Name: "pool"}
+	action := lifecycleAction{AutoScalingGroupName: "pool", HookName: asg.NodeDrainLifecycleHookName, Token: "token"}
+
+	objects := func(actions map[string]pendingAction) []client.Object {
+		data, _ := json.Marshal(actions)
+		return []client.Object{
+			&clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{Name: "test-cluster"},
+				},
+			},
+			&infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec:       infrav1.AWSClusterSpec{Region: "us-east-1"},
+			},
+			&infrav1exp.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pool",
+					Namespace:   "default",
+					Labels:      map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+					Annotations: map[string]string{PendingLifecycleActionsAnnotation: string(data)},
+				},
+				Spec: infrav1exp.AWSMachinePoolSpec{
+					ProviderIDList:   []string{"aws:///us-east-1a/i-0123"},
+					NodeDrainTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		}
+	}
+
+	setup := func(t *testing.T, actions map[string]pendingAction, drainErr error) (*machinePoolDrainReconciler, *mock_services.MockASGInterface, *[]string) {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+		drained := []string{}
+
+		r := newMachinePoolDrainReconciler(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects(actions)...).Build(), ctrl.Log)
+		r.asgServiceFactory = func(cloud.ClusterScoper) services.ASGInterface {
+			return asgSvc
+		}
+		r.drainNodeFunc = func(_ context.Context, cluster *clusterv1.Cluster, instanceID string) error {
+			if cluster.Name != "test-cluster" {
+				return errors.Errorf("unexpected cluster %q", cluster.Name)
+			}
+			drained = append(drained, instanceID)
+			return drainErr
+		}
+		return r, asgSvc, &drained
+	}
+
+	t.Run("completes the lifecycle action once the node is drained", func(t *testing.T) {
+		g := NewWithT(t)
+		r, asgSvc, drained := setup(t, map[string]pendingAction{
+			"i-0123": {lifecycleAction: action, Deadline: metav1.NewTime(time.Now().Add(time.Minute))},
+		}, nil)
+
+		asgSvc.EXPECT().CompleteLifecycleAction("pool", asg.NodeDrainLifecycleHookName, "i-0123", "token").Return(nil)
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: poolKey})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(*drained).To(ConsistOf("i-0123"))
+		g.Expect(poolActions(g, r.Client, poolKey)).To(BeEmpty())
+	})
+
+	t.Run("retries the drain without completing the lifecycle action when it fails", func(t *testing.T) {
+		g := NewWithT(t)
+		r, _, drained := setup(t, map[string]pendingAction{
+			"i-0123": {lifecycleAction: action, Deadline: metav1.NewTime(time.Now().Add(time.Minute))},
+		}, errors.New("pods not evicted yet"))
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: poolKey})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(drainRetryInterval))
+		g.Expect(*drained).To(ConsistOf("i-0123"))
+		g.Expect(poolActions(g, r.Client, poolKey)).To(HaveKey("i-0123"))
+	})
+
+	t.Run("forgets the actions whose hook timed out", func(t *testing.T) {
+		g := NewWithT(t)
+		r, _, drained := setup(t, map[string]pendingAction{
+			"i-0123": {lifecycleAction: action, Deadline: metav1.NewTime(time.Now().Add(-time.Second))},
+		}, nil)
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: poolKey})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*drained).To(BeEmpty())
+		g.Expect(poolActions(g, r.Client, poolKey)).To(BeEmpty())
+	})
+
+	t.Run("keeps the lifecycle action when completing it fails", func(t *testing.T) {
+		g := NewWithT(t)
+		r, asgSvc, _ := setup(t, map[string]pendingAction{
+			"i-0123": {lifecycleAction: action, Deadline: metav1.NewTime(time.Now().Add(time.Minute))},
+		}, nil)
+
+		asgSvc.EXPECT().CompleteLifecycleAction("pool", asg.NodeDrainLifecycleHookName, "i-0123", "token").Return(errors.New("some error"))
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: poolKey})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(poolActions(g, r.Client, poolKey)).To(HaveKey("i-0123"))
+	})
+}
+
+func poolActions(g *WithT, c client.Client, key types.NamespacedName) map[string]pendingAction {
+	pool := &infrav1exp.AWSMachinePool{}
+	g.Expect(c.Get(context.Background(), key, pool)).To(Succeed())
+	actions, err := pendingActions(pool)
+	g.Expect(err).NotTo(HaveOccurred())
+	return actions
+}
@@ -57,6 +57,101 @@ const (
 	// owner: @sedefsavas
 	// alpha: v0.6
 	AutoControllerIdentityCreator featuregate.Feature = "AutoControllerIdentityCreator"
+
+	// MachineIAMInstanceProfile will create and delete an IAM role and instance profile per AWSMachine.
+	// owner: @ankitasw
+	// alpha: v0.7
+	MachineIAMInstanceProfile featuregate.Feature = "MachineIAMInstanceProfile"
+
+	// InstanceTypeOfferingValidation will reject AWSMachines and AWSMachineTemplates whose instance type is not offered in their failure domain.
+	// owner: @ankitasw
+	// alpha: v0.7
+	InstanceTypeOfferingValidation featuregate.Feature = "InstanceTypeOfferingValidation"
+
+	// InstanceScheduledEvents will poll EC2 for scheduled events of AWSMachine instances, such as retirements and reboots.
+	// owner: @ankitasw
+	// alpha: v0.7
+	InstanceScheduledEvents featuregate.Feature = "InstanceScheduledEvents"
+
+	// MachinePoolScaleFromZero will maintain the cluster-autoscaler scale-from-zero capacity annotations on MachinePools.
+	// owner: @ankitasw
+	// alpha: v0.7
+	MachinePoolScaleFromZero featuregate.Feature = "MachinePoolScaleFromZero"
+
+	// SpotMaxPriceValidation will compare the spot max price of AWSMachines with the recent spot price history before launching instances.
+	// owner: @ankitasw
+	// alpha: v0.7
+	SpotMaxPriceValidation featuregate.Feature = "SpotMaxPriceValidation"
+
+	// AuditAWSMutations will record an event for every AWS API call that changes a resource, carrying its request ID.
+	// owner: @ankitasw
+	// alpha: v0.7
+	AuditAWSMutations featuregate.Feature = "AuditAWSMutations"
+
+	// PreflightQuotaChecks will compare the resources a new AWSCluster needs with the service quotas of the account before creating its network.
+	// owner: @ankitasw
+	// alpha: v0.7
+	PreflightQuotaChecks featuregate.Feature = "PreflightQuotaChecks"
+
+	// StrictValidation will make the webhooks check the network, security group rules, volumes and tags of clusters and machines more deeply.
+	// owner: @ankitasw
+	// alpha: v0.7
+	StrictValidation featuregate.Feature = "StrictValidation"
+
+	// SubnetLayoutDefaulting will make the AWSCluster defaulting webhook set the subnets of new clusters with a managed VPC and no subnets.
+	// owner: @ankitasw
+	// alpha: v0.7
+	SubnetLayoutDefaulting featuregate.Feature = "SubnetLayoutDefaulting"
+
+	// Karpenter will provision the node IAM role, the interruption queue and the discovery tags Karpenter needs on AWSClusters which opt in.
+	// owner: @ankitasw
+	// alpha: v0.7
+	Karpenter featuregate.Feature = "Karpenter"
+
+	// NodeMetadataLabels will label the nodes of AWSMachines with the instance type, availability zone, subnet, lifecycle and AMI of their instance.
+	// owner: @ankitasw
+	// alpha: v0.7
+	NodeMetadataLabels featuregate.Feature = "NodeMetadataLabels"
+
+	// InstanceConnectivityCheck will check that the instances of AWSMachines can reach the API server load balancer of their cluster.
+	// owner: @ankitasw
+	// alpha: v0.7
+	InstanceConnectivityCheck featuregate.Feature = "InstanceConnectivityCheck"
+
+	// MachinePoolMachines will create an AWSMachine for each instance of an AWSMachinePool, so that the instances can be inspected and deleted one by one.
+	// owner: @ankitasw
+	// alpha: v0.7
+	MachinePoolMachines featuregate.Feature = "MachinePoolMachines"
+
+	// CostEstimation will annotate AWSClusters and AWSMachinePools with the estimated hourly cost of their AWS resources, looked up in the Pricing API.
+	// owner: @ankitasw
+	// alpha: v0.7
+	CostEstimation featuregate.Feature = "CostEstimation"
+
+	// RightSizingRecommendations will fetch the AWS Compute Optimizer recommendations of the instances of AWSMachines and report the over and under provisioned ones.
+	// owner: @ankitasw
+	// alpha: v0.7
+	RightSizingRecommendations featuregate.Feature = "RightSizingRecommendations"
+
+	// MachineTemplateCapacity will populate the capacity of AWSMachineTemplates from their instance type, so that the cluster-autoscaler can scale MachineDeployments from zero.
+	// owner: @ankitasw
+	// alpha: v0.7
+	MachineTemplateCapacity featuregate.Feature = "MachineTemplateCapacity"
+
+	// CNINetworkInterfaceCleanup will delete the network interfaces the Amazon VPC CNI plugin left behind in the managed VPC of a deleted cluster.
+	// owner: @ankitasw
+	// alpha: v0.7
+	CNINetworkInterfaceCleanup featuregate.Feature = "CNINetworkInterfaceCleanup"
+
+	// InstanceStatusCheckTaints will taint the nodes of AWSMachines whose instances fail their EC2 system status checks, so that their pods are evicted.
+	// owner: @ankitasw
+	// alpha: v0.7
+	InstanceStatusCheckTaints featuregate.Feature = "InstanceStatusCheckTaints"
+
+	// IAMConfiguration will reconcile the IAM roles, instance profiles and managed policies of AWSIAMConfigurations applied to the management cluster.
+	// owner: @ankitasw
+	// alpha: v0.7
+	IAMConfiguration featuregate.Feature = "IAMConfiguration"
 )
 
 func init() {
@@ -67,10 +162,29 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPAFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	EKS:                           {Default: false, PreRelease: featuregate.Alpha},
-	EKSEnableIAM:                  {Default: false, PreRelease: featuregate.Alpha},
-	EKSAllowAddRoles:              {Default: false, PreRelease: featuregate.Alpha},
-	EventBridgeInstanceState:      {Default: false, PreRelease: featuregate.Alpha},
-	MachinePool:                   {Default: false, PreRelease: featuregate.Alpha},
-	AutoControllerIdentityCreator: {Default: true, PreRelease: featuregate.Alpha},
+	EKS:                            {Default: false, PreRelease: featuregate.Alpha},
+	EKSEnableIAM:                   {Default: false, PreRelease: featuregate.Alpha},
+	EKSAllowAddRoles:               {Default: false, PreRelease: featuregate.Alpha},
+	EventBridgeInstanceState:       {Default: false, PreRelease: featuregate.Alpha},
+	MachinePool:                    {Default: false, PreRelease: featuregate.Alpha},
+	AutoControllerIdentityCreator:  {Default: true, PreRelease: featuregate.Alpha},
+	MachineIAMInstanceProfile:      {Default: false, PreRelease: featuregate.Alpha},
+	InstanceTypeOfferingValidation: {Default: false, PreRelease: featuregate.Alpha},
+	InstanceScheduledEvents:        {Default: false, PreRelease: featuregate.Alpha},
+	MachinePoolScaleFromZero:       {Default: false, PreRelease: featuregate.Alpha},
+	SpotMaxPriceValidation:         {Default: false, PreRelease: featuregate.Alpha},
+	AuditAWSMutations:              {Default: false, PreRelease: featuregate.Alpha},
+	PreflightQuotaChecks:           {Default: false, PreRelease: featuregate.Alpha},
+	StrictValidation:               {Default: false, PreRelease: featuregate.Alpha},
+	SubnetLayoutDefaulting:         {Default: false, PreRelease: featuregate.Alpha},
+	Karpenter:                      {Default: false, PreRelease: featuregate.Alpha},
+	NodeMetadataLabels:             {Default: false, PreRelease: featuregate.Alpha},
+	InstanceConnectivityCheck:      {Default: false, PreRelease: featuregate.Alpha},
+	MachinePoolMachines:            {Default: false, PreRelease: featuregate.Alpha},
+	CostEstimation:                 {Default: false, PreRelease: featuregate.Alpha},
+	RightSizingRecommendations:     {Default: false, PreRelease: featuregate.Alpha},
+	MachineTemplateCapacity:        {Default: false, PreRelease: featuregate.Alpha},
+	CNINetworkInterfaceCleanup:     {Default: false, PreRelease: featuregate.Alpha},
+	InstanceStatusCheckTaints:      {Default: false, PreRelease: featuregate.Alpha},
+	IAMConfiguration:               {Default: false, PreRelease: featuregate.Alpha},
 }
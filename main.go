@@ -26,6 +26,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/klog/v2/klogr"
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/api/bootstrap/v1alpha1"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
 	controlplanev1alpha3 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha3"
 	controlplanev1alpha4 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
@@ -45,6 +47,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
+	instancestateservice "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/network"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/transport"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-aws/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -60,6 +67,9 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// instanceTypeOfferingsCacheTTL is how long the instance types offered in an availability zone are cached for.
+const instanceTypeOfferingsCacheTTL = time.Hour
+
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = infrav1alpha3.AddToScheme(scheme)
@@ -70,6 +80,7 @@ func init() {
 	_ = controlplanev1alpha3.AddToScheme(scheme)
 	_ = controlplanev1alpha4.AddToScheme(scheme)
 	_ = clusterv1exp.AddToScheme(scheme)
+	_ = bootstrapv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -88,6 +99,9 @@ var (
 	webhookCertDir           string
 	healthAddr               string
 	serviceEndpoints         string
+	awsCABundle              string
+	instanceStateQueueRegion string
+	providerIDFormat         string
 )
 
 func main() {
@@ -151,26 +165,58 @@ func main() {
 		os.Exit(1)
 	}
 
+	if awsCABundle != "" {
+		if err = transport.LoadCABundle(awsCABundle); err != nil {
+			setupLog.Error(err, "unable to load the CA bundle of the AWS API endpoints")
+			os.Exit(1)
+		}
+	}
+
+	var machineProviderIDFormat scope.ProviderIDFormat
+	if providerIDFormat != "" {
+		machineProviderIDFormat, err = scope.ParseProviderIDFormat(providerIDFormat)
+		if err != nil {
+			setupLog.Error(err, "unable to parse provider ID format")
+			os.Exit(1)
+		}
+		setupLog.Info("Migrating the provider IDs of AWSMachines", "format", machineProviderIDFormat)
+	}
+
 	if err = (&controllers.AWSMachineReconciler{
 		Client:           mgr.GetClient(),
 		Log:              ctrl.Log.WithName("controllers").WithName("AWSMachine"),
 		Recorder:         mgr.GetEventRecorderFor("awsmachine-controller"),
 		Endpoints:        AWSServiceEndpoints,
 		WatchFilterValue: watchFilterValue,
+		ProviderIDFormat: machineProviderIDFormat,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: awsMachineConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSMachine")
 		os.Exit(1)
 	}
+	var instanceStateQueues *instancestateservice.SharedQueues
+	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
+		regions := []string{}
+		if instanceStateQueueRegion != "" {
+			regions = append(regions, instanceStateQueueRegion)
+		}
+		instanceStateQueues, err = instancestateservice.NewSharedQueues(AWSServiceEndpoints, regions...)
+		if err != nil {
+			setupLog.Error(err, "unable to create the instance state queues")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controllers.AWSClusterReconciler{
-		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("awscluster-controller"),
-		Endpoints:        AWSServiceEndpoints,
-		WatchFilterValue: watchFilterValue,
+		Client:              mgr.GetClient(),
+		Recorder:            mgr.GetEventRecorderFor("awscluster-controller"),
+		Endpoints:           AWSServiceEndpoints,
+		WatchFilterValue:    watchFilterValue,
+		InstanceStateQueues: instanceStateQueues,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: awsClusterConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSCluster")
 		os.Exit(1)
 	}
-	enableGates(ctx, mgr, AWSServiceEndpoints)
+	enableGates(ctx, mgr, AWSServiceEndpoints, instanceStateQueues)
 
 	if err = (&infrav1alpha4.AWSMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AWSMachineTemplate")
@@ -258,7 +304,7 @@ func main() {
 	}
 }
 
-func enableGates(ctx context.Context, mgr ctrl.Manager, awsServiceEndpoints []scope.ServiceEndpoint) {
+func enableGates(ctx context.Context, mgr ctrl.Manager, awsServiceEndpoints []scope.ServiceEndpoint, instanceStateQueues *instancestateservice.SharedQueues) {
 	if feature.Gates.Enabled(feature.EKS) {
 		setupLog.Info("enabling EKS controllers")
 
@@ -305,12 +351,24 @@ func enableGates(ctx context.Context, mgr ctrl.Manager, awsServiceEndpoints []sc
 	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
 		setupLog.Info("EventBridge notifications enabled. enabling AWSInstanceStateController")
 		if err := (&instancestate.AwsInstanceStateReconciler{
+			Client:            mgr.GetClient(),
+			Log:               ctrl.Log.WithName("controllers").WithName("AWSInstanceStateController"),
+			Queues:            instanceStateQueues,
+			WatchFilterValue:  watchFilterValue,
+			DrainMachinePools: feature.Gates.Enabled(feature.MachinePool),
+		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: instanceStateConcurrency}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AWSInstanceStateController")
+			os.Exit(1)
+		}
+	}
+	if feature.Gates.Enabled(feature.MachineTemplateCapacity) {
+		setupLog.Info("enabling AWSMachineTemplate capacity controller")
+		if err := (&controllers.AWSMachineTemplateReconciler{
 			Client:           mgr.GetClient(),
-			Log:              ctrl.Log.WithName("controllers").WithName("AWSInstanceStateController"),
 			Endpoints:        awsServiceEndpoints,
 			WatchFilterValue: watchFilterValue,
-		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: instanceStateConcurrency}); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "AWSInstanceStateController")
+		}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AWSMachineTemplate")
 			os.Exit(1)
 		}
 	}
@@ -326,6 +384,39 @@ func enableGates(ctx context.Context, mgr ctrl.Manager, awsServiceEndpoints []sc
 			os.Exit(1)
 		}
 	}
+	if feature.Gates.Enabled(feature.IAMConfiguration) {
+		setupLog.Info("enabling AWSIAMConfiguration controller")
+		if err := (&controllersexp.AWSIAMConfigurationReconciler{
+			Client:           mgr.GetClient(),
+			Recorder:         mgr.GetEventRecorderFor("awsiamconfiguration-controller"),
+			Endpoints:        awsServiceEndpoints,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AWSIAMConfiguration")
+			os.Exit(1)
+		}
+	}
+	if feature.Gates.Enabled(feature.InstanceTypeOfferingValidation) {
+		setupLog.Info("enabling instance type offering validation")
+		offeringsLog := ctrl.Log.WithName("webhooks").WithName("InstanceTypeOfferings")
+		infrav1alpha4.SetInstanceTypeOfferingChecker(ec2.NewInstanceTypeOfferings(func(cluster *clusterv1.Cluster, awsCluster *infrav1alpha4.AWSCluster) (ec2iface.EC2API, error) {
+			return scope.NewEC2ClientForCluster(mgr.GetClient(), cluster, awsCluster, awsServiceEndpoints, offeringsLog)
+		}, instanceTypeOfferingsCacheTTL))
+	}
+	if feature.Gates.Enabled(feature.StrictValidation) {
+		setupLog.Info("enabling strict validation")
+		architecturesLog := ctrl.Log.WithName("webhooks").WithName("Architectures")
+		infrav1alpha4.SetImageArchitectureChecker(ec2.NewArchitectures(func(region string) (ec2iface.EC2API, error) {
+			return scope.NewEC2ClientForRegion(region, awsServiceEndpoints, architecturesLog)
+		}))
+	}
+	if feature.Gates.Enabled(feature.SubnetLayoutDefaulting) {
+		setupLog.Info("enabling subnet layout defaulting")
+		subnetLayoutsLog := ctrl.Log.WithName("webhooks").WithName("SubnetLayouts")
+		infrav1alpha4.SetNetworkDefaulter(network.NewSubnetLayouts(func(region string) (ec2iface.EC2API, error) {
+			return scope.NewEC2ClientForRegion(region, awsServiceEndpoints, subnetLayoutsLog)
+		}))
+	}
 }
 func initFlags(fs *pflag.FlagSet) {
 	fs.StringVar(
@@ -387,6 +478,60 @@ func initFlags(fs *pflag.FlagSet) {
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)",
 	)
 
+	fs.DurationVar(&wait.DefaultInstanceRunningTimeout,
+		"instance-running-timeout",
+		wait.DefaultInstanceRunningTimeout,
+		"How long to wait for a new EC2 instance to be running, unless overridden by the AWSCluster's spec.operationTimeouts (e.g. 2m)",
+	)
+
+	fs.DurationVar(&wait.DefaultLoadBalancerReadyTimeout,
+		"load-balancer-ready-timeout",
+		wait.DefaultLoadBalancerReadyTimeout,
+		"How long to wait for load balancer operations to complete, unless overridden by the AWSCluster's spec.operationTimeouts (e.g. 10m)",
+	)
+
+	fs.DurationVar(&wait.DefaultLoadBalancerDNSRequeueInterval,
+		"load-balancer-dns-requeue-interval",
+		wait.DefaultLoadBalancerDNSRequeueInterval,
+		"How often to check whether the control plane load balancer has a DNS name which resolves, unless overridden by the AWSCluster's spec.requeueIntervals (e.g. 30s)",
+	)
+
+	fs.DurationVar(&wait.DefaultInstanceRunningRequeueInterval,
+		"instance-running-requeue-interval",
+		wait.DefaultInstanceRunningRequeueInterval,
+		"How often to check whether a pending EC2 instance is running, unless overridden by the spec.requeueIntervals of the AWSCluster or AWSManagedControlPlane (e.g. 1m)",
+	)
+
+	fs.DurationVar(&wait.DefaultNodegroupUpdateRequeueInterval,
+		"nodegroup-update-requeue-interval",
+		wait.DefaultNodegroupUpdateRequeueInterval,
+		"How often to check whether an update of an EKS managed node group has completed, unless overridden by the AWSManagedControlPlane's spec.requeueIntervals (e.g. 2m)",
+	)
+
+	fs.DurationVar(&scope.MaxSessionDuration,
+		"max-session-duration",
+		0,
+		"How long a cached AWS session is reused before it is recreated with fresh credentials. Set to 0 to reuse sessions until their identity changes (e.g. 1h)",
+	)
+
+	fs.DurationVar(&record.DefaultThrottleOptions.DedupWindow,
+		"event-dedup-window",
+		record.DefaultThrottleOptions.DedupWindow,
+		"How long identical events for an object are suppressed after one is recorded. Suppressed events are recorded once with a count when the window closes. Set to 0 to disable (e.g. 5m)",
+	)
+
+	fs.Float64Var(&record.DefaultThrottleOptions.PerObjectQPS,
+		"event-qps-per-object",
+		record.DefaultThrottleOptions.PerObjectQPS,
+		"The sustained rate at which events may be recorded for a single object. Set to 0 to disable rate limiting.",
+	)
+
+	fs.IntVar(&record.DefaultThrottleOptions.PerObjectBurst,
+		"event-burst-per-object",
+		record.DefaultThrottleOptions.PerObjectBurst,
+		"The number of events that may be recorded for a single object before event-qps-per-object applies.",
+	)
+
 	fs.IntVar(&webhookPort,
 		"webhook-port",
 		9443,
@@ -408,6 +553,24 @@ func initFlags(fs *pflag.FlagSet) {
 		"Set custom AWS service endpoins in semi-colon separated format: ${SigningRegion1}:${ServiceID1}=${URL},${ServiceID2}=${URL};${SigningRegion2}...",
 	)
 
+	fs.StringVar(&awsCABundle,
+		"aws-ca-bundle",
+		"",
+		"Path to a PEM encoded bundle of certificate authorities to trust for the AWS API endpoints in addition to the system ones, e.g. those of a TLS-intercepting proxy or of private endpoints. Identities can add their own with spec.caBundle.",
+	)
+
+	fs.StringVar(&instanceStateQueueRegion,
+		"instance-state-queue-region",
+		"",
+		"Region of an SQS queue EC2 instance state changes are delivered to, created at startup. The queues of the regions of the clusters are created when the clusters are reconciled.",
+	)
+
+	fs.StringVar(&providerIDFormat,
+		"provider-id-format",
+		"",
+		"Format to migrate the provider IDs of AWSMachines to, Zonal (aws:///<zone>/<instance-id>) or Zoneless (aws:////<instance-id>). When set, the provider IDs of the nodes of the machines are also checked and set on the nodes which have none. If unspecified, new machines use the Zonal format.",
+	)
+
 	fs.StringVar(
 		&watchFilterValue,
 		"watch-filter",
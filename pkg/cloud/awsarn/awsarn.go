@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package awsarn builds the ARNs of the resources used by the provider in the partition of their region,
+// so that clusters in the AWS GovCloud (US) and China partitions refer to the right resources.
+package awsarn
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// Partition returns the partition of the region, or the aws partition when the region is unknown.
+func Partition(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.ID()
+	}
+	return endpoints.AwsPartitionID
+}
+
+// DNSSuffix returns the DNS suffix of the endpoints of the partition of the region, or the suffix of the aws
+// partition when the region is unknown.
+func DNSSuffix(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.DNSSuffix()
+	}
+	return "amazonaws.com"
+}
+
+// EC2ServicePrincipal returns the service principal of EC2 in the partition of the region, which the roles of
+// instance profiles must trust.
+func EC2ServicePrincipal(region string) string {
+	if Partition(region) == endpoints.AwsCnPartitionID {
+		return "ec2.amazonaws.com.cn"
+	}
+	return "ec2.amazonaws.com"
+}
+
+// AWSManagedPolicy returns the ARN of the AWS managed IAM policy with the given name.
+func AWSManagedPolicy(partition, name string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "iam",
+		AccountID: "aws",
+		Resource:  fmt.Sprintf("policy/%s", name),
+	}.String()
+}
+
+// IAMRole returns the ARN of the IAM role with the given name.
+func IAMRole(partition, accountID, name string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "iam",
+		AccountID: accountID,
+		Resource:  fmt.Sprintf("role/%s", name),
+	}.String()
+}
+
+// S3Bucket returns the ARN of the S3 bucket with the given name.
+func S3Bucket(partition, bucket string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "s3",
+		Resource:  bucket,
+	}.String()
+}
+
+// S3Object returns the ARN of the objects of the S3 bucket matching the key, which may contain wildcards.
+func S3Object(partition, bucket, key string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "s3",
+		Resource:  fmt.Sprintf("%s/%s", bucket, key),
+	}.String()
+}
+
+// EventBus returns the ARN of the EventBridge event bus with the given name.
+func EventBus(partition, region, accountID, name string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "events",
+		Region:    region,
+		AccountID: accountID,
+		Resource:  fmt.Sprintf("event-bus/%s", name),
+	}.String()
+}
+
+// EC2Instance returns the ARN of the EC2 instance with the given ID.
+func EC2Instance(partition, region, accountID, instanceID string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "ec2",
+		Region:    region,
+		AccountID: accountID,
+		Resource:  fmt.Sprintf("instance/%s", instanceID),
+	}.String()
+}
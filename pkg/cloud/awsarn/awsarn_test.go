@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsarn
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPartition(t *testing.T) {
+	testCases := []struct {
+		region    string
+		partition string
+	}{
+		{region: "us-east-1", partition: "aws"},
+		{region: "me-south-1", partition: "aws"},
+		{region: "us-gov-west-1", partition: "aws-us-gov"},
+		{region: "cn-north-1", partition: "aws-cn"},
+		{region: "", partition: "aws"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.region, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Partition(tc.region)).To(Equal(tc.partition))
+		})
+	}
+}
+
+func TestDNSSuffix(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(DNSSuffix("us-east-1")).To(Equal("amazonaws.com"))
+	g.Expect(DNSSuffix("us-gov-west-1")).To(Equal("amazonaws.com"))
+	g.Expect(DNSSuffix("cn-north-1")).To(Equal("amazonaws.com.cn"))
+	g.Expect(DNSSuffix("")).To(Equal("amazonaws.com"))
+}
+
+func TestEC2ServicePrincipal(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(EC2ServicePrincipal("us-east-1")).To(Equal("ec2.amazonaws.com"))
+	g.Expect(EC2ServicePrincipal("us-gov-west-1")).To(Equal("ec2.amazonaws.com"))
+	g.Expect(EC2ServicePrincipal("cn-northwest-1")).To(Equal("ec2.amazonaws.com.cn"))
+}
+
+func TestARNs(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(AWSManagedPolicy("aws-cn", "AmazonEKSClusterPolicy")).To(Equal("arn:aws-cn:iam::aws:policy/AmazonEKSClusterPolicy"))
+	g.Expect(IAMRole("aws-us-gov", "123456789012", "nodes.cluster-api-provider-aws.sigs.k8s.io")).To(Equal("arn:aws-us-gov:iam::123456789012:role/nodes.cluster-api-provider-aws.sigs.k8s.io"))
+	g.Expect(S3Bucket("aws", "bucket")).To(Equal("arn:aws:s3:::bucket"))
+	g.Expect(S3Object("aws-cn", "bucket", "prefix/*")).To(Equal("arn:aws-cn:s3:::bucket/prefix/*"))
+	g.Expect(EventBus("aws", "us-west-2", "123456789012", "default")).To(Equal("arn:aws:events:us-west-2:123456789012:event-bus/default"))
+	g.Expect(EC2Instance("aws", "eu-west-1", "123456789012", "i-0123456789abcdef0")).To(Equal("arn:aws:ec2:eu-west-1:123456789012:instance/i-0123456789abcdef0"))
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awserrors
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+// Class is a class of AWS errors which share a condition reason and a retry behavior.
+type Class string
+
+const (
+	// ClassUnknown is the class of errors which are not classified.
+	ClassUnknown = Class("")
+
+	// ClassThrottling is the class of errors returned when AWS rate limits requests.
+	ClassThrottling = Class("Throttling")
+
+	// ClassAuth is the class of errors returned when the credentials in use are invalid,
+	// expired or not authorized to make a request.
+	ClassAuth = Class("Auth")
+
+	// ClassCapacity is the class of errors returned when AWS has no capacity for a
+	// resource, or an account limit is reached.
+	ClassCapacity = Class("Capacity")
+
+	// ClassDependency is the class of errors returned when a resource depends on, or is
+	// used by, a resource which is not in the required state.
+	ClassDependency = Class("Dependency")
+
+	// ClassInvalidParameter is the class of errors returned when AWS rejects a request as
+	// invalid, usually because of the configuration of the resource.
+	ClassInvalidParameter = Class("InvalidParameter")
+)
+
+// classCodes maps the error codes of the AWS APIs used by the controllers to their class.
+// Throttling and expired credential codes are taken from the AWS SDK.
+var classCodes = map[string]Class{
+	AuthFailure:                   ClassAuth,
+	NoCredentialProviders:         ClassAuth,
+	"AccessDenied":                ClassAuth,
+	"AccessDeniedException":       ClassAuth,
+	"Blocked":                     ClassAuth,
+	"IncompleteSignature":         ClassAuth,
+	"InvalidAccessKeyId":          ClassAuth,
+	"InvalidClientTokenId":        ClassAuth,
+	"MissingAuthenticationToken":  ClassAuth,
+	"OptInRequired":               ClassAuth,
+	"SignatureDoesNotMatch":       ClassAuth,
+	"UnauthorizedOperation":       ClassAuth,
+	"UnrecognizedClientException": ClassAuth,
+
+	InsufficientInstanceCapacity:           ClassCapacity,
+	MaxSpotInstanceCountExceeded:           ClassCapacity,
+	"InsufficientAddressCapacity":          ClassCapacity,
+	"InsufficientCapacity":                 ClassCapacity,
+	"InsufficientCapacityOnHost":           ClassCapacity,
+	"InsufficientFreeAddressesInSubnet":    ClassCapacity,
+	"InsufficientHostCapacity":             ClassCapacity,
+	"InsufficientReservedInstanceCapacity": ClassCapacity,
+	"InsufficientVolumeCapacity":           ClassCapacity,
+	"ServiceQuotaExceededException":        ClassCapacity,
+	"TooManyBuckets":                       ClassCapacity,
+	"TooManyLoadBalancers":                 ClassCapacity,
+
+	"DependencyViolation":    ClassDependency,
+	"IncorrectInstanceState": ClassDependency,
+	"IncorrectState":         ClassDependency,
+	"InvalidState":           ClassDependency,
+	"ResourceInUse":          ClassDependency,
+	"ResourceInUseException": ClassDependency,
+	"DeleteConflict":         ClassDependency,
+	"OperationAborted":       ClassDependency,
+
+	InvalidSubnet:                 ClassInvalidParameter,
+	SpotMaxPriceTooLow:            ClassInvalidParameter,
+	Unsupported:                   ClassInvalidParameter,
+	"InvalidBlockDeviceMapping":   ClassInvalidParameter,
+	"InvalidConfigurationRequest": ClassInvalidParameter,
+	"InvalidInput":                ClassInvalidParameter,
+	"InvalidParameter":            ClassInvalidParameter,
+	"InvalidParameterCombination": ClassInvalidParameter,
+	"InvalidParameterException":   ClassInvalidParameter,
+	"InvalidParameterValue":       ClassInvalidParameter,
+	"InvalidRequestException":     ClassInvalidParameter,
+	"MalformedPolicyDocument":     ClassInvalidParameter,
+	"MissingParameter":            ClassInvalidParameter,
+	"UnsupportedOperation":        ClassInvalidParameter,
+	"ValidationError":             ClassInvalidParameter,
+	"ValidationException":         ClassInvalidParameter,
+}
+
+var classReasons = map[Class]string{
+	ClassThrottling:       infrav1.AWSThrottledReason,
+	ClassAuth:             infrav1.AWSAccessDeniedReason,
+	ClassCapacity:         infrav1.AWSInsufficientCapacityReason,
+	ClassDependency:       infrav1.AWSDependencyFailedReason,
+	ClassInvalidParameter: infrav1.AWSInvalidParameterReason,
+}
+
+// Errors which need the user to act, or AWS to free up capacity, are retried less often
+// than the exponential backoff of the controllers would retry them.
+var classRequeueAfter = map[Class]time.Duration{
+	ClassThrottling:       30 * time.Second,
+	ClassAuth:             5 * time.Minute,
+	ClassCapacity:         time.Minute,
+	ClassDependency:       15 * time.Second,
+	ClassInvalidParameter: 5 * time.Minute,
+}
+
+// Classify returns the class of the given error, looking through errors wrapped with
+// github.com/pkg/errors.
+func Classify(err error) Class {
+	if err == nil {
+		return ClassUnknown
+	}
+	err = errors.Cause(err)
+
+	switch {
+	case IsFailedDependency(err):
+		return ClassDependency
+	case request.IsErrorThrottle(err):
+		return ClassThrottling
+	case request.IsErrorExpiredCreds(err):
+		return ClassAuth
+	}
+
+	code, ok := Code(err)
+	if !ok {
+		return ClassUnknown
+	}
+	if class, ok := classCodes[code]; ok {
+		return class
+	}
+	switch {
+	case strings.HasSuffix(code, ".Malformed"):
+		return ClassInvalidParameter
+	case strings.HasSuffix(code, "LimitExceeded"):
+		return ClassCapacity
+	}
+	return ClassUnknown
+}
+
+// Reason returns the condition reason for errors of the class, or an empty string for
+// unclassified errors.
+func (c Class) Reason() string {
+	return classReasons[c]
+}
+
+// RequeueAfter returns how long to wait before retrying after an error of the class, or
+// zero for unclassified errors.
+func (c Class) RequeueAfter() time.Duration {
+	return classRequeueAfter[c]
+}
+
+// ConditionReason returns the condition reason for the class of the given error, or
+// defaultReason when the error is not classified.
+func ConditionReason(err error, defaultReason string) string {
+	if reason := Classify(err).Reason(); reason != "" {
+		return reason
+	}
+	return defaultReason
+}
+
+// IsClassReason reports whether the given condition reason is the reason of a class of errors.
+func IsClassReason(reason string) bool {
+	for _, r := range classReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Requeue returns the result and error of a reconciliation the way the controller returns them.
+// A classified error is retried after the delay of its class instead of with exponential backoff,
+// which would retry errors that need the user to act far too often, and make throttling worse.
+// Callers are expected to have logged the error.
+func Requeue(res ctrl.Result, err error) (ctrl.Result, error) {
+	if requeueAfter := Classify(err).RequeueAfter(); requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return res, err
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awserrors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{
+			name: "nil error",
+			want: ClassUnknown,
+		},
+		{
+			name: "error which is not an AWS error",
+			err:  errors.New("connection refused"),
+			want: ClassUnknown,
+		},
+		{
+			name: "throttled request",
+			err:  awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil),
+			want: ClassThrottling,
+		},
+		{
+			name: "unauthorized operation wrapped by the services",
+			err:  errors.Wrap(awserr.New("UnauthorizedOperation", "You are not authorized to perform this operation.", nil), "failed to run instance"),
+			want: ClassAuth,
+		},
+		{
+			name: "expired credentials",
+			err:  awserr.New("ExpiredToken", "The security token included in the request is expired", nil),
+			want: ClassAuth,
+		},
+		{
+			name: "insufficient instance capacity",
+			err:  awserr.New(InsufficientInstanceCapacity, "We currently do not have sufficient capacity.", nil),
+			want: ClassCapacity,
+		},
+		{
+			name: "account limit",
+			err:  awserr.New("VcpuLimitExceeded", "You have requested more vCPU capacity than your current vCPU limit allows.", nil),
+			want: ClassCapacity,
+		},
+		{
+			name: "dependency violation",
+			err:  awserr.New("DependencyViolation", "resource sg-1 has a dependent object", nil),
+			want: ClassDependency,
+		},
+		{
+			name: "failed dependency reported by the services",
+			err:  NewFailedDependency("APIServer ELB not available"),
+			want: ClassDependency,
+		},
+		{
+			name: "invalid parameter",
+			err:  awserr.New("InvalidParameterValue", "Invalid value 'm5.huge' for InstanceType.", nil),
+			want: ClassInvalidParameter,
+		},
+		{
+			name: "malformed identifier",
+			err:  awserr.New("InvalidAMIID.Malformed", "Invalid id: \"abc\"", nil),
+			want: ClassInvalidParameter,
+		},
+		{
+			name: "not found",
+			err:  awserr.New(InvalidInstanceID, "The instance ID 'i-1' does not exist", nil),
+			want: ClassUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Classify(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestConditionReason(t *testing.T) {
+	g := NewWithT(t)
+
+	capacityErr := awserr.New(InsufficientInstanceCapacity, "We currently do not have sufficient capacity.", nil)
+	g.Expect(ConditionReason(capacityErr, infrav1.InstanceProvisionFailedReason)).To(Equal(infrav1.AWSInsufficientCapacityReason))
+	g.Expect(ConditionReason(errors.New("boom"), infrav1.InstanceProvisionFailedReason)).To(Equal(infrav1.InstanceProvisionFailedReason))
+
+	g.Expect(IsClassReason(infrav1.AWSInsufficientCapacityReason)).To(BeTrue())
+	g.Expect(IsClassReason(infrav1.InstanceProvisionFailedReason)).To(BeFalse())
+}
+
+func TestRequeue(t *testing.T) {
+	g := NewWithT(t)
+
+	res, err := Requeue(ctrl.Result{}, awserr.New("Throttling", "Rate exceeded", nil))
+	g.Expect(err).To(BeNil())
+	g.Expect(res.RequeueAfter).To(Equal(30 * time.Second))
+
+	unknownErr := errors.New("boom")
+	res, err = Requeue(ctrl.Result{Requeue: true}, unknownErr)
+	g.Expect(err).To(Equal(unknownErr))
+	g.Expect(res.Requeue).To(BeTrue())
+}
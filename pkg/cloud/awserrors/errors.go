@@ -18,6 +18,7 @@ package awserrors
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -42,8 +43,16 @@ const (
 	AssociationIDNotFound      = "InvalidAssociationID.NotFound"
 	InvalidInstanceID          = "InvalidInstanceID.NotFound"
 	LaunchTemplateNameNotFound = "InvalidLaunchTemplateName.NotFoundException"
+	KeyPairNotFound            = "InvalidKeyPair.NotFound"
+	NetworkInterfaceNotFound   = "InvalidNetworkInterfaceID.NotFound"
 	ResourceExists             = "ResourceExistsException"
 	NoCredentialProviders      = "NoCredentialProviders"
+
+	InsufficientInstanceCapacity = "InsufficientInstanceCapacity"
+	Unsupported                  = "Unsupported"
+	SpotMaxPriceTooLow           = "SpotMaxPriceTooLow"
+	MaxSpotInstanceCountExceeded = "MaxSpotInstanceCountExceeded"
+	InvalidParameterValue        = "InvalidParameterValue"
 )
 
 var _ error = &EC2Error{}
@@ -144,12 +153,51 @@ func IsInvalidNotFoundError(err error) bool {
 			return true
 		case LaunchTemplateNameNotFound:
 			return true
+		case NetworkInterfaceNotFound:
+			return true
 		}
 	}
 
 	return false
 }
 
+// IsSpotCapacityError tests for errors launching a spot instance which an on-demand launch
+// of the same instance may not hit.
+func IsSpotCapacityError(err error) bool {
+	if code, ok := Code(err); ok {
+		switch code {
+		case InsufficientInstanceCapacity, SpotMaxPriceTooLow, MaxSpotInstanceCountExceeded:
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsInstanceTypeUnavailableError tests for errors launching an instance which another
+// instance type may not hit.
+func IsInstanceTypeUnavailableError(err error) bool {
+	if code, ok := Code(err); ok {
+		switch code {
+		case InsufficientInstanceCapacity, Unsupported:
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsInvalidInstanceProfileError tests for errors launching an instance with an IAM instance
+// profile which EC2 doesn't know. IAM is eventually consistent, so this is returned for a few
+// seconds after the instance profile was created.
+func IsInvalidInstanceProfileError(err error) bool {
+	if code, ok := Code(err); ok && code == InvalidParameterValue {
+		return strings.Contains(Message(err), "Invalid IAM Instance Profile")
+	}
+
+	return false
+}
+
 // ReasonForError returns the HTTP status for a particular error.
 func ReasonForError(err error) int {
 	if t, ok := err.(*EC2Error); ok {
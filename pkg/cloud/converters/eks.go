@@ -107,8 +107,8 @@ func TaintsFromSDK(taints []*eks.Taint) (infrav1exp.Taints, error) {
 		}
 		converted = append(converted, infrav1exp.Taint{
 			Effect: convertedEffect,
-			Key:    *taint.Key,
-			Value:  *taint.Value,
+			Key:    aws.StringValue(taint.Key),
+			Value:  aws.StringValue(taint.Value),
 		})
 	}
 
@@ -142,3 +142,20 @@ func TaintEffectFromSDK(effect string) (infrav1exp.TaintEffect, error) {
 		return "", ErrUnknowTaintEffect
 	}
 }
+
+// NodegroupUpdateconfigToSDK is used to convert a CAPA UpdateConfig to AWS SDK NodegroupUpdateConfig.
+func NodegroupUpdateconfigToSDK(updateConfig *infrav1exp.UpdateConfig) *eks.NodegroupUpdateConfig {
+	if updateConfig == nil {
+		return nil
+	}
+
+	converted := &eks.NodegroupUpdateConfig{}
+	if updateConfig.MaxUnavailable != nil {
+		converted.MaxUnavailable = aws.Int64(int64(*updateConfig.MaxUnavailable))
+	}
+	if updateConfig.MaxUnavailablePercentage != nil {
+		converted.MaxUnavailablePercentage = aws.Int64(int64(*updateConfig.MaxUnavailablePercentage))
+	}
+
+	return converted
+}
@@ -21,6 +21,10 @@ import (
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
@@ -91,6 +95,22 @@ func MapToELBTags(src infrav1.Tags) []*elb.Tag {
 	return tags
 }
 
+// MapToELBV2Tags converts a infrav1.Tags to a []*elbv2.Tag.
+func MapToELBV2Tags(src infrav1.Tags) []*elbv2.Tag {
+	tags := make([]*elbv2.Tag, 0, len(src))
+
+	for k, v := range src {
+		tag := &elbv2.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
 // MapToSecretsManagerTags converts a infrav1.Tags to a []*secretsmanager.Tag.
 func MapToSecretsManagerTags(src infrav1.Tags) []*secretsmanager.Tag {
 	tags := make([]*secretsmanager.Tag, 0, len(src))
@@ -123,6 +143,60 @@ func MapToSSMTags(src infrav1.Tags) []*ssm.Tag {
 	return tags
 }
 
+// MapToS3Tags converts a infrav1.Tags to a []*s3.Tag.
+func MapToS3Tags(src infrav1.Tags) []*s3.Tag {
+	tags := make([]*s3.Tag, 0, len(src))
+
+	for k, v := range src {
+		tag := &s3.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// MapToIAMTags converts a infrav1.Tags to a []*iam.Tag.
+func MapToIAMTags(src infrav1.Tags) []*iam.Tag {
+	tags := make([]*iam.Tag, 0, len(src))
+
+	for k, v := range src {
+		tag := &iam.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// IAMTagsToMap converts a []*iam.Tag into a infrav1.Tags.
+func IAMTagsToMap(src []*iam.Tag) infrav1.Tags {
+	tags := make(infrav1.Tags, len(src))
+
+	for _, t := range src {
+		tags[*t.Key] = *t.Value
+	}
+
+	return tags
+}
+
+// ResourceTaggingTagsToMap converts a []*resourcegroupstaggingapi.Tag into a infrav1.Tags.
+func ResourceTaggingTagsToMap(src []*resourcegroupstaggingapi.Tag) infrav1.Tags {
+	tags := make(infrav1.Tags, len(src))
+
+	for _, t := range src {
+		tags[*t.Key] = *t.Value
+	}
+
+	return tags
+}
+
 // ASGTagsToMap converts a []*autoscaling.TagDescription into a infrav1.Tags.
 func ASGTagsToMap(src []*autoscaling.TagDescription) infrav1.Tags {
 	tags := make(infrav1.Tags, len(src))
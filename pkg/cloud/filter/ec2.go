@@ -18,6 +18,7 @@ package filter
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -25,11 +26,12 @@ import (
 )
 
 const (
-	filterNameTagKey        = "tag-key"
-	filterNameVpcID         = "vpc-id"
-	filterNameState         = "state"
-	filterNameVpcAttachment = "attachment.vpc-id"
-	filterAvailabilityZone  = "availability-zone"
+	filterNameTagKey         = "tag-key"
+	filterNameVpcID          = "vpc-id"
+	filterNameState          = "state"
+	filterNameVpcAttachment  = "attachment.vpc-id"
+	filterAvailabilityZone   = "availability-zone"
+	filterAvailabilityZoneID = "availability-zone-id"
 )
 
 // EC2 exposes the ec2 sdk related filters.
@@ -87,6 +89,24 @@ func (ec2Filters) ProviderOwned(clusterName string) *ec2.Filter {
 	}
 }
 
+// Tags returns the filters matching the resources which carry all the given tags, ordered by tag key.
+func (ec2Filters) Tags(tags infrav1.Tags) []*ec2.Filter {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]*ec2.Filter, 0, len(keys))
+	for _, key := range keys {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String(fmt.Sprintf("tag:%s", key)),
+			Values: aws.StringSlice([]string{tags[key]}),
+		})
+	}
+	return filters
+}
+
 // VPC returns a filter based on the id of the VPC.
 func (ec2Filters) VPC(vpcID string) *ec2.Filter {
 	return &ec2.Filter{
@@ -103,6 +123,14 @@ func (ec2Filters) VPCAttachment(vpcID string) *ec2.Filter {
 	}
 }
 
+// RouteTableAssociatedSubnets returns a filter based on the subnets a route table is associated with.
+func (ec2Filters) RouteTableAssociatedSubnets(subnetIDs ...string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String("association.subnet-id"),
+		Values: aws.StringSlice(subnetIDs),
+	}
+}
+
 // Available returns a filter based on the state being available.
 func (ec2Filters) Available() *ec2.Filter {
 	return &ec2.Filter{
@@ -150,6 +178,13 @@ func (ec2Filters) AvailabilityZone(zone string) *ec2.Filter {
 	}
 }
 
+func (ec2Filters) AvailabilityZoneID(zoneID string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String(filterAvailabilityZoneID),
+		Values: aws.StringSlice([]string{zoneID}),
+	}
+}
+
 func (ec2Filters) IgnoreLocalZones() *ec2.Filter {
 	return &ec2.Filter{
 		Name:   aws.String("opt-in-status"),
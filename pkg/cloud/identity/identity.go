@@ -25,11 +25,14 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/metrics"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/transport"
 )
 
 // AWSPrincipalTypeProvider defines the interface for AWS Principal Type Provider.
@@ -56,6 +59,20 @@ func NewAWSStaticPrincipalTypeProvider(identity *infrav1.AWSClusterStaticIdentit
 	}
 }
 
+// HTTPClientOptions returns the options of the HTTP client the AWS calls made with the credentials of an
+// identity use: the certificate authorities it trusts and its proxy.
+func HTTPClientOptions(spec *infrav1.AWSClusterIdentitySpec) transport.Options {
+	opts := transport.Options{}
+	if len(spec.CABundle) > 0 {
+		opts.CABundles = [][]byte{spec.CABundle}
+	}
+	if spec.Proxy != nil {
+		opts.ProxyURL = spec.Proxy.URL
+		opts.NoProxy = spec.Proxy.NoProxy
+	}
+	return opts
+}
+
 // GetAssumeRoleCredentials will return the Credentials of a given AWSRolePrincipalTypeProvider.
 func GetAssumeRoleCredentials(roleIdentityProvider *AWSRolePrincipalTypeProvider, awsConfig *aws.Config) *credentials.Credentials {
 	sess := session.Must(session.NewSession(awsConfig))
@@ -152,7 +169,16 @@ func (p *AWSRolePrincipalTypeProvider) Name() string {
 // Retrieve returns the credential values for the AWSRolePrincipalTypeProvider.
 func (p *AWSRolePrincipalTypeProvider) Retrieve() (credentials.Value, error) {
 	if p.credentials == nil || p.IsExpired() {
-		awsConfig := aws.NewConfig()
+		// Session tokens issued by the global STS endpoint are not valid in the regions which must be opted in to,
+		// unlike those issued by the regional endpoints.
+		awsConfig := aws.NewConfig().WithSTSRegionalEndpoint(endpoints.RegionalSTSEndpoint)
+		httpClient, err := transport.NewHTTPClient(HTTPClientOptions(&p.Principal.Spec.AWSClusterIdentitySpec))
+		if err != nil {
+			return credentials.Value{}, err
+		}
+		if httpClient != nil {
+			awsConfig = awsConfig.WithHTTPClient(httpClient)
+		}
 		if p.sourceProvider != nil {
 			sourceCreds, err := (*p.sourceProvider).Retrieve()
 			if err != nil {
@@ -164,10 +190,23 @@ func (p *AWSRolePrincipalTypeProvider) Retrieve() (credentials.Value, error) {
 		creds := GetAssumeRoleCredentials(p, awsConfig)
 		// Update credentials
 		p.credentials = creds
+
+		start := time.Now()
+		value, err := p.credentials.Get()
+		metrics.RecordAssumeRole(p.Principal.Name, time.Since(start), err)
+		return value, err
 	}
 	return p.credentials.Get()
 }
 
+// SourceProvider returns the provider of the credentials the role is assumed with, if any.
+func (p *AWSRolePrincipalTypeProvider) SourceProvider() AWSPrincipalTypeProvider {
+	if p.sourceProvider == nil {
+		return nil
+	}
+	return *p.sourceProvider
+}
+
 // IsExpired checks the expiration state of the AWSRolePrincipalTypeProvider.
 func (p *AWSRolePrincipalTypeProvider) IsExpired() bool {
 	return p.credentials.IsExpired()
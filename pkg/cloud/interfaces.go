@@ -71,6 +71,8 @@ type ClusterScoper interface {
 	ListOptionsLabelSelector() client.ListOption
 	// APIServerPort returns the port to use when communicating with the API server.
 	APIServerPort() int32
+	// APIServerInstancePort returns the port the API server listens on on the control plane instances.
+	APIServerInstancePort() int32
 	// AdditionalTags returns any tags that you would like to attach to AWS resources. The returned value will never be nil.
 	AdditionalTags() infrav1.Tags
 	// SetFailureDomain sets the infrastructure provider failure domain key to the spec given as input.
@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory lists the AWS resources that are tagged for a cluster.
+package inventory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	tagapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// Resource is an AWS resource tagged for a cluster.
+type Resource struct {
+	ARN       string `json:"arn"`
+	Service   string `json:"service"`
+	Region    string `json:"region,omitempty"`
+	AccountID string `json:"accountID,omitempty"`
+	// Type is the resource type as it appears in the ARN, e.g. instance or loadbalancer.
+	Type string `json:"type,omitempty"`
+	// ID is the part of the ARN that follows the resource type.
+	ID string `json:"id"`
+	// Lifecycle tells whether the resource is owned by the cluster or shared with it. It is taken
+	// from the Cluster API tag, or from the cloud provider tag for resources that only carry that.
+	Lifecycle infrav1.ResourceLifecycle `json:"lifecycle,omitempty"`
+	Tags      infrav1.Tags              `json:"tags,omitempty"`
+}
+
+// Scanner finds the AWS resources tagged for a cluster.
+type Scanner struct {
+	ResourceTagging resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+	IAM             iamiface.IAMAPI
+	// IncludeIAM makes Scan list the IAM roles and instance profiles tagged for the cluster too. Their tags
+	// are read one by one, which takes one call per role or profile in the account.
+	IncludeIAM bool
+}
+
+// NewScanner returns a Scanner using clients built from the given session.
+func NewScanner(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger) *Scanner {
+	return &Scanner{
+		ResourceTagging: scope.NewResourgeTaggingClient(scopeUser, session, logger, nil),
+		IAM:             scope.NewIAMClient(scopeUser, session, logger, nil),
+	}
+}
+
+// Scan returns the resources tagged for the cluster, sorted by ARN. A resource is tagged for the
+// cluster if it carries either the Cluster API cluster tag or the cloud provider cluster tag, so
+// that load balancers and volumes created by the cloud provider, which can hold up the deletion
+// of a cluster, are listed too.
+//
+// Regional resources are found with the Resource Groups Tagging API in the region of the
+// session. IAM roles and instance profiles are global and not covered by that API, so they are
+// only listed if IncludeIAM is set.
+func (s *Scanner) Scan(clusterName string) ([]Resource, error) {
+	found := map[string]*Resource{}
+
+	for _, key := range clusterTagKeys(clusterName) {
+		input := &tagapi.GetResourcesInput{
+			TagFilters: []*tagapi.TagFilter{{Key: aws.String(key)}},
+		}
+		err := s.ResourceTagging.GetResourcesPages(input, func(out *tagapi.GetResourcesOutput, lastPage bool) bool {
+			for _, mapping := range out.ResourceTagMappingList {
+				add(found, aws.StringValue(mapping.ResourceARN), converters.ResourceTaggingTagsToMap(mapping.Tags))
+			}
+			return true
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get resources tagged with %q", key)
+		}
+	}
+
+	if s.IncludeIAM {
+		if err := s.scanIAM(clusterName, found); err != nil {
+			return nil, err
+		}
+	}
+
+	resources := make([]Resource, 0, len(found))
+	for _, r := range found {
+		r.Lifecycle = lifecycle(clusterName, r.Tags)
+		resources = append(resources, *r)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].ARN < resources[j].ARN
+	})
+	return resources, nil
+}
+
+func (s *Scanner) scanIAM(clusterName string, found map[string]*Resource) error {
+	var roles []*iam.Role
+	err := s.IAM.ListRolesPages(&iam.ListRolesInput{}, func(out *iam.ListRolesOutput, lastPage bool) bool {
+		roles = append(roles, out.Roles...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list IAM roles")
+	}
+	for _, role := range roles {
+		out, err := s.IAM.ListRoleTags(&iam.ListRoleTagsInput{RoleName: role.RoleName})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list tags of IAM role %q", aws.StringValue(role.RoleName))
+		}
+		if tags := converters.IAMTagsToMap(out.Tags); isTaggedFor(clusterName, tags) {
+			add(found, aws.StringValue(role.Arn), tags)
+		}
+	}
+
+	var profiles []*iam.InstanceProfile
+	err = s.IAM.ListInstanceProfilesPages(&iam.ListInstanceProfilesInput{}, func(out *iam.ListInstanceProfilesOutput, lastPage bool) bool {
+		profiles = append(profiles, out.InstanceProfiles...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list IAM instance profiles")
+	}
+	for _, profile := range profiles {
+		out, err := s.IAM.ListInstanceProfileTags(&iam.ListInstanceProfileTagsInput{InstanceProfileName: profile.InstanceProfileName})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list tags of IAM instance profile %q", aws.StringValue(profile.InstanceProfileName))
+		}
+		if tags := converters.IAMTagsToMap(out.Tags); isTaggedFor(clusterName, tags) {
+			add(found, aws.StringValue(profile.Arn), tags)
+		}
+	}
+
+	return nil
+}
+
+func add(found map[string]*Resource, resourceARN string, tags infrav1.Tags) {
+	if r, ok := found[resourceARN]; ok {
+		r.Tags.Merge(tags)
+		return
+	}
+
+	r := &Resource{ARN: resourceARN, ID: resourceARN, Tags: tags}
+	if parsed, err := arn.Parse(resourceARN); err == nil {
+		r.Service = parsed.Service
+		r.Region = parsed.Region
+		r.AccountID = parsed.AccountID
+		r.Type, r.ID = splitResource(parsed.Service, parsed.Resource)
+	}
+	found[resourceARN] = r
+}
+
+// splitResource splits the resource part of an ARN into the resource type and ID. ARNs separate
+// them with either a slash or a colon, and S3 bucket ARNs only contain the bucket name.
+func splitResource(service, resource string) (string, string) {
+	if i := strings.IndexAny(resource, "/:"); i >= 0 {
+		return resource[:i], resource[i+1:]
+	}
+	if service == "s3" {
+		return "bucket", resource
+	}
+	return "", resource
+}
+
+func clusterTagKeys(clusterName string) []string {
+	return []string{
+		infrav1.ClusterTagKey(clusterName),
+		infrav1.ClusterAWSCloudProviderTagKey(clusterName),
+	}
+}
+
+func isTaggedFor(clusterName string, tags infrav1.Tags) bool {
+	return lifecycle(clusterName, tags) != ""
+}
+
+func lifecycle(clusterName string, tags infrav1.Tags) infrav1.ResourceLifecycle {
+	for _, key := range clusterTagKeys(clusterName) {
+		if value, ok := tags[key]; ok {
+			return infrav1.ResourceLifecycle(value)
+		}
+	}
+	return ""
+}
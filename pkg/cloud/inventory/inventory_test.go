@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	tagapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_resourcegroupstaggingapiiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam/mock_iamiface"
+)
+
+const (
+	capaKey  = "sigs.k8s.io/cluster-api-provider-aws/cluster/test"
+	cloudKey = "kubernetes.io/cluster/test"
+)
+
+func tagMapping(resourceARN string, tags map[string]string) *tagapi.ResourceTagMapping {
+	mapping := &tagapi.ResourceTagMapping{ResourceARN: aws.String(resourceARN)}
+	for k, v := range tags {
+		mapping.Tags = append(mapping.Tags, &tagapi.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return mapping
+}
+
+func expectTaggedResources(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder, key string, mappings ...*tagapi.ResourceTagMapping) {
+	m.GetResourcesPages(&tagapi.GetResourcesInput{
+		TagFilters: []*tagapi.TagFilter{{Key: aws.String(key)}},
+	}, gomock.Any()).DoAndReturn(func(_ *tagapi.GetResourcesInput, fn func(*tagapi.GetResourcesOutput, bool) bool) error {
+		fn(&tagapi.GetResourcesOutput{ResourceTagMappingList: mappings}, true)
+		return nil
+	})
+}
+
+func TestScan(t *testing.T) {
+	testCases := []struct {
+		name        string
+		taggingMock func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder)
+		iamMock     func(m *mock_iamiface.MockIAMAPIMockRecorder)
+		includeIAM  bool
+		expect      []Resource
+		expectErr   bool
+	}{
+		{
+			name: "lists regional and IAM resources tagged for the cluster",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				expectTaggedResources(m, capaKey,
+					tagMapping("arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", map[string]string{capaKey: "shared"}),
+					tagMapping("arn:aws:ec2:us-east-1:123456789012:instance/i-1", map[string]string{capaKey: "owned", cloudKey: "owned"}),
+					tagMapping("arn:aws:s3:::test-bucket", map[string]string{capaKey: "owned"}),
+				)
+				expectTaggedResources(m, cloudKey,
+					tagMapping("arn:aws:ec2:us-east-1:123456789012:instance/i-1", map[string]string{capaKey: "owned", cloudKey: "owned"}),
+					tagMapping("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/a123", map[string]string{cloudKey: "owned"}),
+				)
+			},
+			iamMock: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.ListRolesPages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *iam.ListRolesInput, fn func(*iam.ListRolesOutput, bool) bool) error {
+					fn(&iam.ListRolesOutput{Roles: []*iam.Role{
+						{RoleName: aws.String("test-eks-role"), Arn: aws.String("arn:aws:iam::123456789012:role/test-eks-role")},
+						{RoleName: aws.String("other"), Arn: aws.String("arn:aws:iam::123456789012:role/other")},
+					}}, true)
+					return nil
+				})
+				m.ListRoleTags(&iam.ListRoleTagsInput{RoleName: aws.String("test-eks-role")}).
+					Return(&iam.ListRoleTagsOutput{Tags: []*iam.Tag{{Key: aws.String(capaKey), Value: aws.String("owned")}}}, nil)
+				m.ListRoleTags(&iam.ListRoleTagsInput{RoleName: aws.String("other")}).
+					Return(&iam.ListRoleTagsOutput{}, nil)
+				m.ListInstanceProfilesPages(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			includeIAM: true,
+			expect: []Resource{
+				{
+					ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", Service: "ec2", Region: "us-east-1", AccountID: "123456789012",
+					Type: "instance", ID: "i-1", Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{capaKey: "owned", cloudKey: "owned"},
+				},
+				{
+					ARN: "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", Service: "ec2", Region: "us-east-1", AccountID: "123456789012",
+					Type: "vpc", ID: "vpc-1", Lifecycle: infrav1.ResourceLifecycleShared, Tags: infrav1.Tags{capaKey: "shared"},
+				},
+				{
+					ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/a123", Service: "elasticloadbalancing", Region: "us-east-1", AccountID: "123456789012",
+					Type: "loadbalancer", ID: "a123", Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{cloudKey: "owned"},
+				},
+				{
+					ARN: "arn:aws:iam::123456789012:role/test-eks-role", Service: "iam", AccountID: "123456789012",
+					Type: "role", ID: "test-eks-role", Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{capaKey: "owned"},
+				},
+				{
+					ARN: "arn:aws:s3:::test-bucket", Service: "s3",
+					Type: "bucket", ID: "test-bucket", Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{capaKey: "owned"},
+				},
+			},
+		},
+		{
+			name: "skips IAM resources unless they are included",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				expectTaggedResources(m, capaKey,
+					tagMapping("arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", map[string]string{capaKey: "owned"}),
+				)
+				expectTaggedResources(m, cloudKey)
+			},
+			iamMock: func(m *mock_iamiface.MockIAMAPIMockRecorder) {},
+			expect: []Resource{
+				{
+					ARN: "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", Service: "ec2", Region: "us-east-1", AccountID: "123456789012",
+					Type: "vpc", ID: "vpc-1", Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{capaKey: "owned"},
+				},
+			},
+		},
+		{
+			name: "returns an error if the tagged resources cannot be listed",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesPages(gomock.Any(), gomock.Any()).Return(errors.New("connection failure"))
+			},
+			iamMock:   func(m *mock_iamiface.MockIAMAPIMockRecorder) {},
+			expectErr: true,
+		},
+		{
+			name: "returns an error if IAM roles cannot be listed",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesPages(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			iamMock: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.ListRolesPages(gomock.Any(), gomock.Any()).Return(errors.New("access denied"))
+			},
+			includeIAM: true,
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			taggingMock := mock_resourcegroupstaggingapiiface.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+			iamMock := mock_iamiface.NewMockIAMAPI(mockCtrl)
+			tc.taggingMock(taggingMock.EXPECT())
+			tc.iamMock(iamMock.EXPECT())
+
+			s := &Scanner{ResourceTagging: taggingMock, IAM: iamMock, IncludeIAM: tc.includeIAM}
+			resources, err := s.Scan("test")
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resources).To(Equal(tc.expect))
+		})
+	}
+}
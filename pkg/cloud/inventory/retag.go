@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	tagapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+)
+
+// maxTaggedARNs is the maximum number of resources a TagResources or UntagResources call accepts.
+// See: https://docs.aws.amazon.com/sdk-for-go/api/service/resourcegroupstaggingapi/#TagResourcesInput
+const maxTaggedARNs = 20
+
+// retagBatch is a set of regional resources which are given the same cluster tags.
+type retagBatch struct {
+	tags    infrav1.Tags
+	oldKeys []string
+	arns    []string
+}
+
+// Retag moves the cluster tags of the resources tagged for the cluster oldName to the cluster newName,
+// keeping whether the resources are owned by the cluster or shared with it, and returns the retagged
+// resources. Both the Cluster API tag and the cloud provider tag are moved, so that the resources are
+// found by the tag filters of the controllers and the cloud provider after the cluster is renamed.
+//
+// IAM roles and instance profiles are only retagged if IncludeIAM is set. The new tags are added
+// before the old ones are removed, so a failed call can be retried.
+func (s *Scanner) Retag(oldName, newName string) ([]Resource, error) {
+	if oldName == newName {
+		return nil, errors.Errorf("the new cluster name is the same as the old one: %q", oldName)
+	}
+
+	resources, err := s.Scan(oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	oldKeys, newKeys := clusterTagKeys(oldName), clusterTagKeys(newName)
+	batches := map[string]*retagBatch{}
+	for i := range resources {
+		r := &resources[i]
+		tags := infrav1.Tags{}
+		removed := []string{}
+		for j, key := range oldKeys {
+			if value, ok := r.Tags[key]; ok {
+				tags[newKeys[j]] = value
+				removed = append(removed, key)
+			}
+		}
+
+		if r.Service == "iam" {
+			if err := s.retagIAM(r, tags, removed); err != nil {
+				return nil, err
+			}
+		} else {
+			// TagResources gives all the resources of a call the same tags, so the resources are grouped by
+			// their new tags. fmt prints maps sorted by key.
+			id := fmt.Sprint(tags)
+			if _, ok := batches[id]; !ok {
+				batches[id] = &retagBatch{tags: tags, oldKeys: removed}
+			}
+			batches[id].arns = append(batches[id].arns, r.ARN)
+		}
+
+		for _, key := range removed {
+			delete(r.Tags, key)
+		}
+		r.Tags.Merge(tags)
+	}
+
+	ids := make([]string, 0, len(batches))
+	for id := range batches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := s.retagRegional(batches[id]); err != nil {
+			return nil, err
+		}
+	}
+
+	return resources, nil
+}
+
+func (s *Scanner) retagRegional(batch *retagBatch) error {
+	for start := 0; start < len(batch.arns); start += maxTaggedARNs {
+		end := start + maxTaggedARNs
+		if end > len(batch.arns) {
+			end = len(batch.arns)
+		}
+		arns := aws.StringSlice(batch.arns[start:end])
+
+		tagOut, err := s.ResourceTagging.TagResources(&tagapi.TagResourcesInput{
+			ResourceARNList: arns,
+			Tags:            aws.StringMap(batch.tags),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to tag resources")
+		}
+		if err := failedResources(tagOut.FailedResourcesMap); err != nil {
+			return errors.Wrap(err, "failed to tag resources")
+		}
+
+		untagOut, err := s.ResourceTagging.UntagResources(&tagapi.UntagResourcesInput{
+			ResourceARNList: arns,
+			TagKeys:         aws.StringSlice(batch.oldKeys),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to untag resources")
+		}
+		if err := failedResources(untagOut.FailedResourcesMap); err != nil {
+			return errors.Wrap(err, "failed to untag resources")
+		}
+	}
+	return nil
+}
+
+// failedResources returns an error listing the resources a TagResources or UntagResources call failed for.
+func failedResources(failed map[string]*tagapi.FailureInfo) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(failed))
+	for resourceARN, info := range failed {
+		messages = append(messages, fmt.Sprintf("%s: %s", resourceARN, aws.StringValue(info.ErrorMessage)))
+	}
+	sort.Strings(messages)
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// retagIAM retags an IAM role or instance profile. They are global and not covered by the Resource Groups
+// Tagging API. Their names are the last part of their IDs, which include their paths.
+func (s *Scanner) retagIAM(r *Resource, tags infrav1.Tags, oldKeys []string) error {
+	name := r.ID[strings.LastIndex(r.ID, "/")+1:]
+
+	switch r.Type {
+	case "role":
+		if _, err := s.IAM.TagRole(&iam.TagRoleInput{RoleName: aws.String(name), Tags: converters.MapToIAMTags(tags)}); err != nil {
+			return errors.Wrapf(err, "failed to tag IAM role %q", name)
+		}
+		if _, err := s.IAM.UntagRole(&iam.UntagRoleInput{RoleName: aws.String(name), TagKeys: aws.StringSlice(oldKeys)}); err != nil {
+			return errors.Wrapf(err, "failed to untag IAM role %q", name)
+		}
+	case "instance-profile":
+		if _, err := s.IAM.TagInstanceProfile(&iam.TagInstanceProfileInput{InstanceProfileName: aws.String(name), Tags: converters.MapToIAMTags(tags)}); err != nil {
+			return errors.Wrapf(err, "failed to tag IAM instance profile %q", name)
+		}
+		if _, err := s.IAM.UntagInstanceProfile(&iam.UntagInstanceProfileInput{InstanceProfileName: aws.String(name), TagKeys: aws.StringSlice(oldKeys)}); err != nil {
+			return errors.Wrapf(err, "failed to untag IAM instance profile %q", name)
+		}
+	default:
+		return errors.Errorf("cannot retag IAM resource %q", r.ARN)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	tagapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_resourcegroupstaggingapiiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam/mock_iamiface"
+)
+
+const (
+	newCAPAKey  = "sigs.k8s.io/cluster-api-provider-aws/cluster/renamed"
+	newCloudKey = "kubernetes.io/cluster/renamed"
+)
+
+func TestRetag(t *testing.T) {
+	instanceARN := "arn:aws:ec2:us-east-1:123456789012:instance/i-1"
+	vpcARN := "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1"
+	roleARN := "arn:aws:iam::123456789012:role/capa/test-eks-role"
+
+	expectScan := func(tagging *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder, iamMock *mock_iamiface.MockIAMAPIMockRecorder) {
+		expectTaggedResources(tagging, capaKey,
+			tagMapping(instanceARN, map[string]string{capaKey: "owned", cloudKey: "owned", "Name": "test-control-plane"}),
+			tagMapping(vpcARN, map[string]string{capaKey: "shared"}),
+		)
+		expectTaggedResources(tagging, cloudKey,
+			tagMapping(instanceARN, map[string]string{capaKey: "owned", cloudKey: "owned", "Name": "test-control-plane"}),
+		)
+		iamMock.ListRolesPages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *iam.ListRolesInput, fn func(*iam.ListRolesOutput, bool) bool) error {
+			fn(&iam.ListRolesOutput{Roles: []*iam.Role{{RoleName: aws.String("test-eks-role"), Arn: aws.String(roleARN)}}}, true)
+			return nil
+		})
+		iamMock.ListRoleTags(&iam.ListRoleTagsInput{RoleName: aws.String("test-eks-role")}).
+			Return(&iam.ListRoleTagsOutput{Tags: []*iam.Tag{{Key: aws.String(capaKey), Value: aws.String("owned")}}}, nil)
+		iamMock.ListInstanceProfilesPages(gomock.Any(), gomock.Any()).Return(nil)
+	}
+
+	testCases := []struct {
+		name        string
+		newName     string
+		taggingMock func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder)
+		iamMock     func(m *mock_iamiface.MockIAMAPIMockRecorder)
+		expect      []Resource
+		expectErr   bool
+	}{
+		{
+			name:    "moves the cluster tags of regional and IAM resources to the new cluster name",
+			newName: "renamed",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.TagResources(&tagapi.TagResourcesInput{
+					ResourceARNList: aws.StringSlice([]string{instanceARN}),
+					Tags:            aws.StringMap(map[string]string{newCAPAKey: "owned", newCloudKey: "owned"}),
+				}).Return(&tagapi.TagResourcesOutput{}, nil)
+				m.UntagResources(&tagapi.UntagResourcesInput{
+					ResourceARNList: aws.StringSlice([]string{instanceARN}),
+					TagKeys:         aws.StringSlice([]string{capaKey, cloudKey}),
+				}).Return(&tagapi.UntagResourcesOutput{}, nil)
+				m.TagResources(&tagapi.TagResourcesInput{
+					ResourceARNList: aws.StringSlice([]string{vpcARN}),
+					Tags:            aws.StringMap(map[string]string{newCAPAKey: "shared"}),
+				}).Return(&tagapi.TagResourcesOutput{}, nil)
+				m.UntagResources(&tagapi.UntagResourcesInput{
+					ResourceARNList: aws.StringSlice([]string{vpcARN}),
+					TagKeys:         aws.StringSlice([]string{capaKey}),
+				}).Return(&tagapi.UntagResourcesOutput{}, nil)
+			},
+			iamMock: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.TagRole(&iam.TagRoleInput{
+					RoleName: aws.String("test-eks-role"),
+					Tags:     []*iam.Tag{{Key: aws.String(newCAPAKey), Value: aws.String("owned")}},
+				}).Return(&iam.TagRoleOutput{}, nil)
+				m.UntagRole(&iam.UntagRoleInput{
+					RoleName: aws.String("test-eks-role"),
+					TagKeys:  aws.StringSlice([]string{capaKey}),
+				}).Return(&iam.UntagRoleOutput{}, nil)
+			},
+			expect: []Resource{
+				{
+					ARN: instanceARN, Service: "ec2", Region: "us-east-1", AccountID: "123456789012", Type: "instance", ID: "i-1",
+					Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{newCAPAKey: "owned", newCloudKey: "owned", "Name": "test-control-plane"},
+				},
+				{
+					ARN: vpcARN, Service: "ec2", Region: "us-east-1", AccountID: "123456789012", Type: "vpc", ID: "vpc-1",
+					Lifecycle: infrav1.ResourceLifecycleShared, Tags: infrav1.Tags{newCAPAKey: "shared"},
+				},
+				{
+					ARN: roleARN, Service: "iam", AccountID: "123456789012", Type: "role", ID: "capa/test-eks-role",
+					Lifecycle: infrav1.ResourceLifecycleOwned, Tags: infrav1.Tags{newCAPAKey: "owned"},
+				},
+			},
+		},
+		{
+			name:    "returns an error if resources fail to be tagged",
+			newName: "renamed",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.TagResources(gomock.Any()).Return(&tagapi.TagResourcesOutput{
+					FailedResourcesMap: map[string]*tagapi.FailureInfo{instanceARN: {ErrorMessage: aws.String("access denied")}},
+				}, nil)
+			},
+			iamMock: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.TagRole(gomock.Any()).Return(&iam.TagRoleOutput{}, nil)
+				m.UntagRole(gomock.Any()).Return(&iam.UntagRoleOutput{}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:        "returns an error if the new cluster name is the same as the old one",
+			newName:     "test",
+			taggingMock: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {},
+			iamMock:     func(m *mock_iamiface.MockIAMAPIMockRecorder) {},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			taggingMock := mock_resourcegroupstaggingapiiface.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+			iamMock := mock_iamiface.NewMockIAMAPI(mockCtrl)
+			if tc.newName != "test" {
+				expectScan(taggingMock.EXPECT(), iamMock.EXPECT())
+			}
+			tc.taggingMock(taggingMock.EXPECT())
+			tc.iamMock(iamMock.EXPECT())
+
+			s := &Scanner{ResourceTagging: taggingMock, IAM: iamMock, IncludeIAM: true}
+			resources, err := s.Retag("test", tc.newName)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resources).To(Equal(tc.expect))
+		})
+	}
+}
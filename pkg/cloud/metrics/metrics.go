@@ -40,6 +40,16 @@ const (
 	metricControllerLabel    = "controller"
 	metricStatusCodeLabel    = "status_code"
 	metricErrorCodeLabel     = "error_code"
+
+	metricSessionCacheEntriesKey = "session_cache_entries"
+	metricSessionCacheLookupsKey = "session_cache_lookups_total"
+	metricAssumeRoleDurationKey  = "assume_role_duration_seconds"
+	metricResultLabel            = "result"
+	metricIdentityLabel          = "identity"
+	metricResultHit              = "hit"
+	metricResultMiss             = "miss"
+	metricResultSuccess          = "success"
+	metricResultError            = "error"
 )
 
 var (
@@ -59,12 +69,53 @@ var (
 		Help:      "Number of retries made against an AWS API",
 		Buckets:   []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 	}, []string{metricControllerLabel, metricServiceLabel, metricRegionLabel, metricOperationLabel})
+	awsSessionCacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: metricAWSSubsystem,
+		Name:      metricSessionCacheEntriesKey,
+		Help:      "Number of AWS sessions cached by the controller",
+	})
+	awsSessionCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricAWSSubsystem,
+		Name:      metricSessionCacheLookupsKey,
+		Help:      "Total number of AWS session cache lookups, by whether a cached session was used",
+	}, []string{metricResultLabel})
+	awsAssumeRoleDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: metricAWSSubsystem,
+		Name:      metricAssumeRoleDurationKey,
+		Help:      "Latency of the AssumeRole calls made for AWSClusterRoleIdentities",
+	}, []string{metricIdentityLabel, metricResultLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(awsRequestCount)
 	metrics.Registry.MustRegister(awsRequestDurationSeconds)
 	metrics.Registry.MustRegister(awsCallRetries)
+	metrics.Registry.MustRegister(awsSessionCacheEntries)
+	metrics.Registry.MustRegister(awsSessionCacheLookups)
+	metrics.Registry.MustRegister(awsAssumeRoleDurationSeconds)
+}
+
+// SetSessionCacheEntries records the number of cached AWS sessions.
+func SetSessionCacheEntries(entries int) {
+	awsSessionCacheEntries.Set(float64(entries))
+}
+
+// RecordSessionCacheLookup records whether a cached AWS session was found.
+func RecordSessionCacheLookup(hit bool) {
+	result := metricResultMiss
+	if hit {
+		result = metricResultHit
+	}
+	awsSessionCacheLookups.WithLabelValues(result).Inc()
+}
+
+// RecordAssumeRole records the latency of an AssumeRole call made for a role identity.
+func RecordAssumeRole(identity string, duration time.Duration, err error) {
+	result := metricResultSuccess
+	if err != nil {
+		result = metricResultError
+	}
+	awsAssumeRoleDurationSeconds.WithLabelValues(identity, result).Observe(duration.Seconds())
 }
 
 // CaptureRequestMetrics will monitor and capture request metrics.
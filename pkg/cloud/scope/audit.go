@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	auditServiceAnnotation   = "aws.cluster.x-k8s.io/service"
+	auditOperationAnnotation = "aws.cluster.x-k8s.io/operation"
+	auditRegionAnnotation    = "aws.cluster.x-k8s.io/region"
+	auditRequestIDAnnotation = "aws.cluster.x-k8s.io/request-id"
+	auditErrorCodeAnnotation = "aws.cluster.x-k8s.io/error-code"
+)
+
+// mutatingVerbs are the leading words of the names of AWS API operations that change resources.
+// Operations starting with anything else, such as Describe, Get or List, are not audited.
+var mutatingVerbs = []string{
+	"Add", "Allocate", "Apply", "Associate", "Attach", "Authorize", "Cancel", "Copy", "Create",
+	"Delete", "Deregister", "Detach", "Disable", "Disassociate", "Enable", "Import", "Modify",
+	"Put", "Reboot", "Register", "Release", "Remove", "Replace", "Request", "Reset", "Resume",
+	"Revoke", "Run", "Set", "Start", "Stop", "Suspend", "Tag", "Terminate", "Untag", "Update",
+}
+
+func isMutatingOperation(operation string) bool {
+	for _, verb := range mutatingVerbs {
+		if !strings.HasPrefix(operation, verb) {
+			continue
+		}
+		// Only match whole words, so that Tag doesn't match a hypothetical Tagging operation.
+		rest := operation[len(verb):]
+		if rest == "" || unicode.IsUpper(rune(rest[0])) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordAWSMutation records an event on target for every completed AWS API call that changes
+// a resource when the AuditAWSMutations feature gate is enabled. The request ID is part of the
+// message and of the event annotations, so that the call can be found in CloudTrail.
+func recordAWSMutation(target runtime.Object) func(r *request.Request) {
+	return func(r *request.Request) {
+		if !feature.Gates.Enabled(feature.AuditAWSMutations) || !isMutatingOperation(r.Operation.Name) {
+			return
+		}
+
+		region := aws.StringValue(r.Config.Region)
+		annotations := map[string]string{
+			auditServiceAnnotation:   r.ClientInfo.ServiceID,
+			auditOperationAnnotation: r.Operation.Name,
+			auditRegionAnnotation:    region,
+			auditRequestIDAnnotation: r.RequestID,
+		}
+
+		if r.Error != nil {
+			code, ok := awserrors.Code(r.Error)
+			if !ok {
+				code = "internal"
+			}
+			annotations[auditErrorCodeAnnotation] = code
+			record.AnnotatedWarnf(target, annotations, "AWSCallFailed", "%s %s in %s failed with %s (request ID %q)",
+				r.ClientInfo.ServiceID, r.Operation.Name, region, code, r.RequestID)
+			return
+		}
+
+		record.AnnotatedEventf(target, annotations, "AWSCallSucceeded", "%s %s in %s succeeded (request ID %q)",
+			r.ClientInfo.ServiceID, r.Operation.Name, region, r.RequestID)
+	}
+}
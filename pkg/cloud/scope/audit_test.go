@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cgrecord "k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+func TestIsMutatingOperation(t *testing.T) {
+	testCases := []struct {
+		operation string
+		expected  bool
+	}{
+		{operation: "RunInstances", expected: true},
+		{operation: "CreateSecurityGroup", expected: true},
+		{operation: "DeleteTags", expected: true},
+		{operation: "AuthorizeSecurityGroupIngress", expected: true},
+		{operation: "Untag", expected: true},
+		{operation: "DescribeInstances", expected: false},
+		{operation: "GetCallerIdentity", expected: false},
+		{operation: "ListClusters", expected: false},
+		{operation: "Tagging", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.operation, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isMutatingOperation(tc.operation)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRecordAWSMutation(t *testing.T) {
+	g := NewWithT(t)
+	recorder := cgrecord.NewFakeRecorder(10)
+	record.InitFromRecorder(recorder)
+
+	target := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "target", UID: "target"}}
+	newRequest := func(operation string, err error) *request.Request {
+		return &request.Request{
+			Config:     aws.Config{Region: aws.String("us-east-1")},
+			ClientInfo: metadata.ClientInfo{ServiceID: "EC2"},
+			Operation:  &request.Operation{Name: operation},
+			RequestID:  "req-" + operation,
+			Error:      err,
+		}
+	}
+	handler := recordAWSMutation(target)
+
+	handler(newRequest("RunInstances", nil))
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	defer featuregatetesting.SetFeatureGateDuringTest(t, feature.Gates, feature.AuditAWSMutations, true)()
+
+	handler(newRequest("DescribeInstances", nil))
+	handler(newRequest("RunInstances", nil))
+	handler(newRequest("CreateSecurityGroup", awserr.New("InvalidGroup.Duplicate", "duplicate", nil)))
+	handler(newRequest("DeleteTags", errors.New("connection reset")))
+	g.Expect(recorder.Events).To(HaveLen(3))
+	g.Expect(<-recorder.Events).To(Equal(`Normal AWSCallSucceeded EC2 RunInstances in us-east-1 succeeded (request ID "req-RunInstances")`))
+	g.Expect(<-recorder.Events).To(Equal(`Warning AWSCallFailed EC2 CreateSecurityGroup in us-east-1 failed with InvalidGroup.Duplicate (request ID "req-CreateSecurityGroup")`))
+	g.Expect(<-recorder.Events).To(Equal(`Warning AWSCallFailed EC2 DeleteTags in us-east-1 failed with internal (request ID "req-DeleteTags")`))
+}
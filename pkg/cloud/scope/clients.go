@@ -20,22 +20,37 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/computeoptimizer"
+	"github.com/aws/aws-sdk-go/service/computeoptimizer/computeoptimizeriface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/networkfirewall"
+	"github.com/aws/aws-sdk-go/service/networkfirewall/networkfirewalliface"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -45,10 +60,14 @@ import (
 	"github.com/go-logr/logr"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
 	awslogs "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/logs"
 	awsmetrics "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/metrics"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-aws/version"
 )
@@ -59,6 +78,7 @@ func NewASGClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 	asgClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	asgClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	asgClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	asgClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return asgClient
 }
@@ -75,6 +95,46 @@ func NewEC2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 		ec2Client.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(ec2.ServiceID).ReviewResponse)
 	}
 	ec2Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ec2Client.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return ec2Client
+}
+
+// NewEC2ClientForRegion creates a new EC2 API client using the credentials of the controller in the given region.
+// It is meant for callers which don't act on behalf of a cluster, such as webhooks.
+func NewEC2ClientForRegion(region string, endpoints []ServiceEndpoint, logger logr.Logger) (ec2iface.EC2API, error) {
+	session, serviceLimiters, err := sessionForRegion(region, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEC2ClientForSession(session, serviceLimiters, logger), nil
+}
+
+// NewEC2ClientForCluster creates a new EC2 API client using the identity and in the region of the AWSCluster.
+// It is meant for callers which act on behalf of a cluster without reconciling it, such as webhooks.
+func NewEC2ClientForCluster(k8sClient client.Client, cluster *clusterv1.Cluster, awsCluster *infrav1.AWSCluster, endpoints []ServiceEndpoint, logger logr.Logger) (ec2iface.EC2API, error) {
+	clusterScope := &ClusterScope{
+		Logger:     logger,
+		client:     k8sClient,
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+	}
+	session, serviceLimiters, err := sessionForClusterWithRegion(k8sClient, clusterScope, awsCluster.Spec.Region, endpoints, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEC2ClientForSession(session, serviceLimiters, logger), nil
+}
+
+func newEC2ClientForSession(sess *session.Session, serviceLimiters throttle.ServiceLimiters, logger logr.Logger) ec2iface.EC2API {
+	ec2Client := ec2.New(sess, aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	ec2Client.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	if limiter, ok := serviceLimiters[ec2.ServiceID]; ok {
+		ec2Client.Handlers.Sign.PushFront(limiter.LimitRequest)
+		ec2Client.Handlers.CompleteAttempt.PushFront(limiter.ReviewResponse)
+	}
 
 	return ec2Client
 }
@@ -87,16 +147,42 @@ func NewELBClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 	elbClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	elbClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(elb.ServiceID).ReviewResponse)
 	elbClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	elbClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return elbClient
 }
 
+// NewELBV2Client creates a new ELBv2 API client for a given session.
+func NewELBV2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) elbv2iface.ELBV2API {
+	elbv2Client := elbv2.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	elbv2Client.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	elbv2Client.Handlers.Sign.PushFront(session.ServiceLimiter(elbv2.ServiceID).LimitRequest)
+	elbv2Client.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	elbv2Client.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(elbv2.ServiceID).ReviewResponse)
+	elbv2Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	elbv2Client.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return elbv2Client
+}
+
+// NewNetworkFirewallClient creates a new Network Firewall API client for a given session.
+func NewNetworkFirewallClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) networkfirewalliface.NetworkFirewallAPI {
+	networkFirewallClient := networkfirewall.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	networkFirewallClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	networkFirewallClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	networkFirewallClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	networkFirewallClient.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return networkFirewallClient
+}
+
 // NewEventBridgeClient creates a new EventBridge API client for a given session.
 func NewEventBridgeClient(scopeUser cloud.ScopeUsage, session cloud.Session, target runtime.Object) eventbridgeiface.EventBridgeAPI {
 	eventBridgeClient := eventbridge.New(session.Session())
 	eventBridgeClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	eventBridgeClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	eventBridgeClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	eventBridgeClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return eventBridgeClient
 }
@@ -107,6 +193,7 @@ func NewSQSClient(scopeUser cloud.ScopeUsage, session cloud.Session, target runt
 	SQSClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	SQSClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	SQSClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	SQSClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return SQSClient
 }
@@ -120,6 +207,24 @@ func NewGlobalSQSClient(scopeUser cloud.ScopeUsage, session cloud.Session) sqsif
 	return SQSClient
 }
 
+// NewGlobalEventBridgeClient for creating a new EventBridge API client that isn't tied to a cluster.
+func NewGlobalEventBridgeClient(scopeUser cloud.ScopeUsage, session cloud.Session) eventbridgeiface.EventBridgeAPI {
+	eventBridgeClient := eventbridge.New(session.Session())
+	eventBridgeClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	eventBridgeClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+
+	return eventBridgeClient
+}
+
+// NewGlobalSTSClient for creating a new STS API client that isn't tied to a cluster.
+func NewGlobalSTSClient(scopeUser cloud.ScopeUsage, session cloud.Session) stsiface.STSAPI {
+	stsClient := sts.New(session.Session())
+	stsClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	stsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+
+	return stsClient
+}
+
 // NewResourgeTaggingClient creates a new Resource Tagging API client for a given session.
 func NewResourgeTaggingClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI {
 	resourceTagging := resourcegroupstaggingapi.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
@@ -128,6 +233,7 @@ func NewResourgeTaggingClient(scopeUser cloud.ScopeUsage, session cloud.Session,
 	resourceTagging.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	resourceTagging.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(resourceTagging.ServiceID).ReviewResponse)
 	resourceTagging.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	resourceTagging.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return resourceTagging
 }
@@ -140,16 +246,52 @@ func NewSecretsManagerClient(scopeUser cloud.ScopeUsage, session cloud.Session,
 	secretsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	secretsClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(secretsClient.ServiceID).ReviewResponse)
 	secretsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	secretsClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return secretsClient
 }
 
+// NewServiceQuotasClient creates a new Service Quotas API client for a given session.
+func NewServiceQuotasClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) servicequotasiface.ServiceQuotasAPI {
+	serviceQuotasClient := servicequotas.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	serviceQuotasClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	serviceQuotasClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	serviceQuotasClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	serviceQuotasClient.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return serviceQuotasClient
+}
+
+// pricingRegion is the region of the Pricing API endpoint used for the prices of every region.
+const pricingRegion = "us-east-1"
+
+// NewPricingClient creates a new Pricing API client for a given session.
+func NewPricingClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) pricingiface.PricingAPI {
+	pricingClient := pricing.New(session.Session(), aws.NewConfig().WithRegion(pricingRegion).WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	pricingClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	pricingClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	pricingClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+
+	return pricingClient
+}
+
+// NewComputeOptimizerClient creates a new Compute Optimizer API client for a given session.
+func NewComputeOptimizerClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) computeoptimizeriface.ComputeOptimizerAPI {
+	computeOptimizerClient := computeoptimizer.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	computeOptimizerClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	computeOptimizerClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	computeOptimizerClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+
+	return computeOptimizerClient
+}
+
 // NewEKSClient creates a new EKS API client for a given session.
 func NewEKSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) eksiface.EKSAPI {
 	eksClient := eks.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	eksClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	eksClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	eksClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	eksClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return eksClient
 }
@@ -160,6 +302,16 @@ func NewIAMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 	iamClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	iamClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	iamClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	iamClient.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return iamClient
+}
+
+// NewGlobalIAMClient for creating a new IAM API client that isn't tied to a cluster.
+func NewGlobalIAMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger) iamiface.IAMAPI {
+	iamClient := iam.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	iamClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	iamClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 
 	return iamClient
 }
@@ -170,6 +322,7 @@ func NewSTSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 	stsClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	stsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	stsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	stsClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return stsClient
 }
@@ -180,10 +333,33 @@ func NewSSMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 	ssmClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	ssmClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	ssmClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ssmClient.Handlers.Complete.PushBack(recordAWSMutation(target))
 
 	return ssmClient
 }
 
+// NewS3Client creates a new S3 API client for a given session.
+func NewS3Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) s3iface.S3API {
+	s3Client := s3.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	s3Client.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	s3Client.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	s3Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	s3Client.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return s3Client
+}
+
+// NewECRClient creates a new ECR API client for a given session.
+func NewECRClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) ecriface.ECRAPI {
+	ecrClient := ecr.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	ecrClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	ecrClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	ecrClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ecrClient.Handlers.Complete.PushBack(recordAWSMutation(target))
+
+	return ecrClient
+}
+
 func recordAWSPermissionsIssue(target runtime.Object) func(r *request.Request) {
 	return func(r *request.Request) {
 		if awsErr, ok := r.Error.(awserr.Error); ok {
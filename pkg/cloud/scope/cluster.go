@@ -19,15 +19,22 @@ package scope
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	awsclient "github.com/aws/aws-sdk-go/aws/client"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2/klogr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -130,11 +137,26 @@ func (s *ClusterScope) CNIIngressRules() infrav1.CNIIngressRules {
 	return infrav1.CNIIngressRules{}
 }
 
+// AllowNodeToNodeTraffic returns whether all traffic between nodes is allowed.
+func (s *ClusterScope) AllowNodeToNodeTraffic() bool {
+	return s.AWSCluster.Spec.NetworkSpec.AllowNodeToNodeTraffic
+}
+
+// SecurityGroupPolicy returns the ports the security groups of the cluster may open to each other.
+func (s *ClusterScope) SecurityGroupPolicy() *infrav1.SecurityGroupPolicy {
+	return s.AWSCluster.Spec.NetworkSpec.SecurityGroupPolicy
+}
+
 // SecurityGroupOverrides returns the cluster security group overrides.
 func (s *ClusterScope) SecurityGroupOverrides() map[infrav1.SecurityGroupRole]string {
 	return s.AWSCluster.Spec.NetworkSpec.SecurityGroupOverrides
 }
 
+// SecurityGroupOverrideTracking returns how the cluster security group overrides are followed.
+func (s *ClusterScope) SecurityGroupOverrideTracking() infrav1.SecurityGroupOverrideTracking {
+	return s.AWSCluster.Spec.NetworkSpec.SecurityGroupOverrideTracking
+}
+
 // SecurityGroups returns the cluster security groups as a map, it creates the map if empty.
 func (s *ClusterScope) SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup {
 	return s.AWSCluster.Status.Network.SecurityGroups
@@ -145,6 +167,31 @@ func (s *ClusterScope) SecondaryCidrBlock() *string {
 	return nil
 }
 
+// Firewall returns the AWS Network Firewall inspecting the egress of the private subnets, if any.
+func (s *ClusterScope) Firewall() *infrav1.NetworkFirewall {
+	return s.AWSCluster.Spec.NetworkSpec.Firewall
+}
+
+// GatewayLoadBalancerEndpointRoutes returns the routes through Gateway Load Balancer endpoints of the route tables.
+func (s *ClusterScope) GatewayLoadBalancerEndpointRoutes() []infrav1.GatewayLoadBalancerEndpointRoute {
+	return s.AWSCluster.Spec.NetworkSpec.GatewayLoadBalancerEndpointRoutes
+}
+
+// Routes returns the static routes of the route tables of all the subnets.
+func (s *ClusterScope) Routes() []infrav1.Route {
+	return s.AWSCluster.Spec.NetworkSpec.Routes
+}
+
+// ElasticIPPool returns the pre-allocated Elastic IPs of the NAT gateways and the bastion host, if any.
+func (s *ClusterScope) ElasticIPPool() *infrav1.ElasticIPPool {
+	return s.AWSCluster.Spec.NetworkSpec.ElasticIPPool
+}
+
+// NatGatewayAllocationIDs returns the allocation IDs of the Elastic IPs of the NAT gateways by availability zone.
+func (s *ClusterScope) NatGatewayAllocationIDs() map[string]string {
+	return s.AWSCluster.Spec.NetworkSpec.NatGatewayAllocationIDs
+}
+
 // Name returns the CAPI cluster name.
 func (s *ClusterScope) Name() string {
 	return s.Cluster.Name
@@ -184,6 +231,32 @@ func (s *ClusterScope) ControlPlaneLoadBalancerScheme() infrav1.ClassicELBScheme
 	return infrav1.ClassicELBSchemeInternetFacing
 }
 
+// ControlPlaneLoadBalancerDNSCheck returns how to check that the DNS name of the control plane load balancer
+// is ready, defaulting to resolving it.
+func (s *ClusterScope) ControlPlaneLoadBalancerDNSCheck() infrav1.DNSCheck {
+	if s.ControlPlaneLoadBalancer() != nil && s.ControlPlaneLoadBalancer().DNSCheck != "" {
+		return s.ControlPlaneLoadBalancer().DNSCheck
+	}
+	return infrav1.DNSCheckResolve
+}
+
+// LoadBalancerReadyTimeout returns how long to wait for load balancer operations to complete.
+func (s *ClusterScope) LoadBalancerReadyTimeout() time.Duration {
+	if t := s.AWSCluster.Spec.OperationTimeouts; t != nil && t.LoadBalancerReady != nil {
+		return t.LoadBalancerReady.Duration
+	}
+	return wait.DefaultLoadBalancerReadyTimeout
+}
+
+// LoadBalancerDNSRequeueInterval returns how often to check whether the control plane load balancer has a DNS
+// name which resolves.
+func (s *ClusterScope) LoadBalancerDNSRequeueInterval() time.Duration {
+	if i := s.AWSCluster.Spec.RequeueIntervals; i != nil && i.LoadBalancerDNS != nil && i.LoadBalancerDNS.Duration > 0 {
+		return i.LoadBalancerDNS.Duration
+	}
+	return wait.DefaultLoadBalancerDNSRequeueInterval
+}
+
 // ControlPlaneConfigMapName returns the name of the ConfigMap used to
 // coordinate the bootstrapping of control plane nodes.
 func (s *ClusterScope) ControlPlaneConfigMapName() string {
@@ -219,6 +292,18 @@ func (s *ClusterScope) PatchObject() error {
 		}
 	}
 
+	if s.AWSCluster.Spec.S3Bucket != nil {
+		applicableConditions = append(applicableConditions, infrav1.S3BucketReadyCondition)
+	}
+
+	if s.AWSCluster.Spec.SSHKeyPair != nil {
+		applicableConditions = append(applicableConditions, infrav1.SSHKeyPairReadyCondition)
+	}
+
+	if conditions.Has(s.AWSCluster, infrav1.PreflightChecksPassedCondition) {
+		applicableConditions = append(applicableConditions, infrav1.PreflightChecksPassedCondition)
+	}
+
 	conditions.SetSummary(s.AWSCluster,
 		conditions.WithConditions(applicableConditions...),
 		conditions.WithStepCounterIf(s.AWSCluster.ObjectMeta.DeletionTimestamp.IsZero()),
@@ -236,9 +321,15 @@ func (s *ClusterScope) PatchObject() error {
 			infrav1.NatGatewaysReadyCondition,
 			infrav1.RouteTablesReadyCondition,
 			infrav1.ClusterSecurityGroupsReadyCondition,
+			infrav1.SecurityGroupPolicyCompliantCondition,
 			infrav1.BastionHostReadyCondition,
+			infrav1.SSHKeyPairReadyCondition,
 			infrav1.LoadBalancerReadyCondition,
+			infrav1.LoadBalancerInstancesHealthyCondition,
+			infrav1.S3BucketReadyCondition,
 			infrav1.PrincipalUsageAllowedCondition,
+			infrav1.PreflightChecksPassedCondition,
+			infrav1.PausedCondition,
 		}})
 }
 
@@ -264,6 +355,15 @@ func (s *ClusterScope) APIServerPort() int32 {
 	return 6443
 }
 
+// APIServerInstancePort returns the port the API server listens on on the control plane instances, which defaults
+// to the API server port.
+func (s *ClusterScope) APIServerInstancePort() int32 {
+	if lb := s.ControlPlaneLoadBalancer(); lb != nil && lb.InstancePort != nil {
+		return *lb.InstancePort
+	}
+	return s.APIServerPort()
+}
+
 // SetFailureDomain sets the infrastructure provider failure domain key to the spec given as input.
 func (s *ClusterScope) SetFailureDomain(id string, spec clusterv1.FailureDomainSpec) {
 	if s.AWSCluster.Status.FailureDomains == nil {
@@ -300,17 +400,91 @@ func (s *ClusterScope) SetBastionInstance(instance *infrav1.Instance) {
 	s.AWSCluster.Status.Bastion = instance
 }
 
-// SSHKeyName returns the SSH key name to use for instances.
+// BastionInstances returns all the bastion hosts recorded in the status of the cluster.
+func (s *ClusterScope) BastionInstances() []infrav1.Instance {
+	return s.AWSCluster.Status.Bastions
+}
+
+// SetBastionInstances sets all the bastion hosts in the status of the cluster.
+func (s *ClusterScope) SetBastionInstances(instances []infrav1.Instance) {
+	s.AWSCluster.Status.Bastions = instances
+}
+
+// BastionLoadBalancer returns the load balancer in front of the bastion hosts recorded in the status of the cluster.
+func (s *ClusterScope) BastionLoadBalancer() *infrav1.BastionLoadBalancer {
+	return s.AWSCluster.Status.BastionLoadBalancer
+}
+
+// SetBastionLoadBalancer sets the load balancer in front of the bastion hosts in the status of the cluster.
+func (s *ClusterScope) SetBastionLoadBalancer(lb *infrav1.BastionLoadBalancer) {
+	s.AWSCluster.Status.BastionLoadBalancer = lb
+}
+
+// SSHKeyName returns the SSH key name to use for instances, which is the key pair created for the cluster
+// if the cluster has one.
 func (s *ClusterScope) SSHKeyName() *string {
+	if s.AWSCluster.Spec.SSHKeyName == nil && s.AWSCluster.Spec.SSHKeyPair != nil {
+		return aws.String(s.SSHKeyPairName())
+	}
 	return s.AWSCluster.Spec.SSHKeyName
 }
 
+// SSHKeyPair returns the configuration of the EC2 key pair created for the cluster.
+func (s *ClusterScope) SSHKeyPair() *infrav1.SSHKeyPair {
+	return s.AWSCluster.Spec.SSHKeyPair
+}
+
+// SSHKeyPairName returns the name of the EC2 key pair created for the cluster.
+func (s *ClusterScope) SSHKeyPairName() string {
+	return fmt.Sprintf("%s-ssh-key", s.Name())
+}
+
+// SSHKeyPairStatus returns the EC2 key pair created for the cluster.
+func (s *ClusterScope) SSHKeyPairStatus() *infrav1.SSHKeyPairStatus {
+	return s.AWSCluster.Status.SSHKeyPair
+}
+
+// SetSSHKeyPairStatus sets the EC2 key pair created for the cluster in the status of the cluster.
+func (s *ClusterScope) SetSSHKeyPairStatus(status *infrav1.SSHKeyPairStatus) {
+	s.AWSCluster.Status.SSHKeyPair = status
+}
+
+// KubeClient returns the client of the management cluster.
+func (s *ClusterScope) KubeClient() client.Client {
+	return s.client
+}
+
 // ControllerName returns the name of the controller that
 // created the ClusterScope.
 func (s *ClusterScope) ControllerName() string {
 	return s.controllerName
 }
 
+// Bucket returns the cluster bucket configuration.
+func (s *ClusterScope) Bucket() *infrav1.S3Bucket {
+	return s.AWSCluster.Spec.S3Bucket
+}
+
+// ECRPullThroughCache returns the cluster ECR pull-through cache configuration.
+func (s *ClusterScope) ECRPullThroughCache() *infrav1.ECRPullThroughCache {
+	return s.AWSCluster.Spec.ECRPullThroughCache
+}
+
+// Karpenter returns the Karpenter configuration of the cluster.
+func (s *ClusterScope) Karpenter() *infrav1.Karpenter {
+	return s.AWSCluster.Spec.Karpenter
+}
+
+// KarpenterStatus returns the AWS resources provisioned for Karpenter.
+func (s *ClusterScope) KarpenterStatus() *infrav1.KarpenterStatus {
+	return s.AWSCluster.Status.Karpenter
+}
+
+// SetKarpenterStatus sets the AWS resources provisioned for Karpenter in the status of the cluster.
+func (s *ClusterScope) SetKarpenterStatus(status *infrav1.KarpenterStatus) {
+	s.AWSCluster.Status.Karpenter = status
+}
+
 // ImageLookupFormat returns the format string to use when looking up AMIs.
 func (s *ClusterScope) ImageLookupFormat() string {
 	return s.AWSCluster.Spec.ImageLookupFormat
@@ -325,3 +499,115 @@ func (s *ClusterScope) ImageLookupOrg() string {
 func (s *ClusterScope) ImageLookupBaseOS() string {
 	return s.AWSCluster.Spec.ImageLookupBaseOS
 }
+
+// MachineDefaults returns the defaults of the AWSMachines of the cluster.
+func (s *ClusterScope) MachineDefaults() *infrav1.MachineDefaults {
+	return s.AWSCluster.Spec.MachineDefaults
+}
+
+// DeletionPolicy returns the deletion policies of the AWS resources of the cluster.
+func (s *ClusterScope) DeletionPolicy() infrav1.DeletionPolicy {
+	if s.AWSCluster.Spec.DeletionPolicy == nil {
+		return infrav1.DeletionPolicy{}
+	}
+	return *s.AWSCluster.Spec.DeletionPolicy
+}
+
+// PlannedInstanceTypes returns the instance type of every on-demand instance that the cluster is set to run:
+// its bastion, and the replicas of its KubeadmControlPlane and MachineDeployments. Replicas whose infrastructure
+// template is not an AWSMachineTemplate, or which run on spot instances, are left out.
+func (s *ClusterScope) PlannedInstanceTypes(ctx context.Context) ([]string, error) {
+	var instanceTypes []string
+
+	if s.AWSCluster.Spec.Bastion.Enabled {
+		instanceType := s.AWSCluster.Spec.Bastion.InstanceType
+		if instanceType == "" {
+			// The fallback types of the bastion count towards the same quota.
+			instanceType = "t3.micro"
+		}
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+
+	if ref := s.Cluster.Spec.ControlPlaneRef; ref != nil && ref.Kind == "KubeadmControlPlane" {
+		controlPlane, err := external.Get(ctx, s.client, ref, s.Namespace())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get control plane %s/%s", ref.Kind, ref.Name)
+		}
+		replicas, found, err := unstructured.NestedInt64(controlPlane.Object, "spec", "replicas")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read replicas of control plane %s/%s", ref.Kind, ref.Name)
+		}
+		if !found {
+			replicas = 1
+		}
+		var infraRef corev1.ObjectReference
+		if obj, found, err := unstructured.NestedMap(controlPlane.Object, "spec", "machineTemplate", "infrastructureRef"); err == nil && found {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &infraRef); err != nil {
+				return nil, errors.Wrapf(err, "failed to read infrastructure template of control plane %s/%s", ref.Kind, ref.Name)
+			}
+			types, err := s.plannedInstanceTypes(ctx, infraRef, replicas)
+			if err != nil {
+				return nil, err
+			}
+			instanceTypes = append(instanceTypes, types...)
+		}
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := s.client.List(ctx, machineDeployments, client.InNamespace(s.Namespace()), client.MatchingLabels{clusterv1.ClusterLabelName: s.Cluster.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	for _, md := range machineDeployments.Items {
+		replicas := int64(1)
+		if md.Spec.Replicas != nil {
+			replicas = int64(*md.Spec.Replicas)
+		}
+		types, err := s.plannedInstanceTypes(ctx, md.Spec.Template.Spec.InfrastructureRef, replicas)
+		if err != nil {
+			return nil, err
+		}
+		instanceTypes = append(instanceTypes, types...)
+	}
+
+	return instanceTypes, nil
+}
+
+func (s *ClusterScope) plannedInstanceTypes(ctx context.Context, ref corev1.ObjectReference, replicas int64) ([]string, error) {
+	if ref.Kind != "AWSMachineTemplate" || replicas <= 0 {
+		return nil, nil
+	}
+
+	template := &infrav1.AWSMachineTemplate{}
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = s.Namespace()
+	}
+	if err := s.client.Get(ctx, key, template); err != nil {
+		return nil, errors.Wrapf(err, "failed to get AWSMachineTemplate %s", key)
+	}
+	if template.Spec.Template.Spec.SpotMarketOptions != nil {
+		return nil, nil
+	}
+
+	instanceTypes := make([]string, replicas)
+	for i := range instanceTypes {
+		instanceTypes[i] = template.Spec.Template.Spec.InstanceType
+	}
+	return instanceTypes, nil
+}
+
+// InstanceRunningTimeout returns how long to wait for a new instance to be running.
+func (s *ClusterScope) InstanceRunningTimeout() time.Duration {
+	if t := s.AWSCluster.Spec.OperationTimeouts; t != nil && t.InstanceRunning != nil {
+		return t.InstanceRunning.Duration
+	}
+	return wait.DefaultInstanceRunningTimeout
+}
+
+// InstanceRunningRequeueInterval returns how often to check whether a pending instance is running.
+func (s *ClusterScope) InstanceRunningRequeueInterval() time.Duration {
+	if i := s.AWSCluster.Spec.RequeueIntervals; i != nil && i.InstanceRunning != nil && i.InstanceRunning.Duration > 0 {
+		return i.InstanceRunning.Duration
+	}
+	return wait.DefaultInstanceRunningRequeueInterval
+}
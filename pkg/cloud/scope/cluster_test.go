@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+)
+
+func TestClusterScopeRequeueIntervals(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		intervals             *infrav1.RequeueIntervals
+		expectLoadBalancerDNS time.Duration
+		expectInstanceRunning time.Duration
+	}{
+		{
+			name:                  "defaults to the controller's intervals",
+			expectLoadBalancerDNS: wait.DefaultLoadBalancerDNSRequeueInterval,
+			expectInstanceRunning: wait.DefaultInstanceRunningRequeueInterval,
+		},
+		{
+			name: "uses the intervals of the cluster",
+			intervals: &infrav1.RequeueIntervals{
+				LoadBalancerDNS: &metav1.Duration{Duration: time.Minute},
+				InstanceRunning: &metav1.Duration{Duration: 2 * time.Minute},
+			},
+			expectLoadBalancerDNS: time.Minute,
+			expectInstanceRunning: 2 * time.Minute,
+		},
+		{
+			name: "ignores intervals which would disable the requeue",
+			intervals: &infrav1.RequeueIntervals{
+				LoadBalancerDNS: &metav1.Duration{},
+			},
+			expectLoadBalancerDNS: wait.DefaultLoadBalancerDNSRequeueInterval,
+			expectInstanceRunning: wait.DefaultInstanceRunningRequeueInterval,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{RequeueIntervals: tc.intervals},
+				},
+			}
+			g.Expect(s.LoadBalancerDNSRequeueInterval()).To(Equal(tc.expectLoadBalancerDNS))
+			g.Expect(s.InstanceRunningRequeueInterval()).To(Equal(tc.expectInstanceRunning))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package scope
 
 import (
+	"time"
+
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
 )
@@ -25,6 +27,9 @@ import (
 type EC2Scope interface {
 	cloud.ClusterScoper
 
+	// ELBScope gives access to the load balancer of the bastion hosts.
+	ELBScope
+
 	// VPC returns the cluster VPC.
 	VPC() *infrav1.VPCSpec
 
@@ -43,6 +48,22 @@ type EC2Scope interface {
 	// SetBastionInstance sets the bastion instance in the status of the cluster.
 	SetBastionInstance(instance *infrav1.Instance)
 
+	// BastionInstances returns all the bastion hosts recorded in the status of the cluster.
+	BastionInstances() []infrav1.Instance
+
+	// SetBastionInstances sets all the bastion hosts in the status of the cluster.
+	SetBastionInstances(instances []infrav1.Instance)
+
+	// BastionLoadBalancer returns the load balancer in front of the bastion hosts recorded in the status of the
+	// cluster, if any.
+	BastionLoadBalancer() *infrav1.BastionLoadBalancer
+
+	// SetBastionLoadBalancer sets the load balancer in front of the bastion hosts in the status of the cluster.
+	SetBastionLoadBalancer(lb *infrav1.BastionLoadBalancer)
+
+	// ElasticIPPool returns the pre-allocated Elastic IPs of the NAT gateways and the bastion host, if any.
+	ElasticIPPool() *infrav1.ElasticIPPool
+
 	// SSHKeyName returns the SSH key name to use for instances.
 	SSHKeyName() *string
 
@@ -54,4 +75,16 @@ type EC2Scope interface {
 
 	// ImageLookupBaseOS returns the base operating system name to use when looking up AMIs
 	ImageLookupBaseOS() string
+
+	// MachineDefaults returns the defaults of the AWSMachines of the cluster.
+	MachineDefaults() *infrav1.MachineDefaults
+
+	// DeletionPolicy returns the deletion policies of the AWS resources of the cluster.
+	DeletionPolicy() infrav1.DeletionPolicy
+
+	// InstanceRunningTimeout returns how long to wait for a new instance to be running.
+	InstanceRunningTimeout() time.Duration
+
+	// InstanceRunningRequeueInterval returns how often to check whether a pending instance is running.
+	InstanceRunningRequeueInterval() time.Duration
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package scope
 
 import (
+	"time"
+
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
 )
@@ -42,4 +44,7 @@ type ELBScope interface {
 
 	// ControlPlaneLoadBalancerScheme returns the Classic ELB scheme (public or internal facing)
 	ControlPlaneLoadBalancerScheme() infrav1.ClassicELBScheme
+
+	// LoadBalancerReadyTimeout returns how long to wait for load balancer operations to complete.
+	LoadBalancerReadyTimeout() time.Duration
 }
@@ -147,6 +147,16 @@ func (s *FargateProfileScope) RoleName() string {
 	return s.FargateProfile.Spec.RoleName
 }
 
+// RolePath returns the path of the IAM roles created for the cluster.
+func (s *FargateProfileScope) RolePath() string {
+	return s.ControlPlane.Spec.RolePath
+}
+
+// RolePermissionsBoundary returns the permissions boundary of the IAM roles created for the cluster.
+func (s *FargateProfileScope) RolePermissionsBoundary() string {
+	return s.ControlPlane.Spec.RolePermissionsBoundary
+}
+
 // ControlPlaneSubnets returns the control plane subnets.
 func (s *FargateProfileScope) ControlPlaneSubnets() *infrav1.Subnets {
 	return &s.ControlPlane.Spec.NetworkSpec.Subnets
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+)
+
+// KeyPairScope is the interface for the scope to be used with the key pair service.
+type KeyPairScope interface {
+	cloud.ClusterScoper
+
+	// SSHKeyPair returns the configuration of the EC2 key pair created for the cluster.
+	SSHKeyPair() *infrav1.SSHKeyPair
+
+	// SSHKeyPairName returns the name of the EC2 key pair created for the cluster.
+	SSHKeyPairName() string
+
+	// SSHKeyPairStatus returns the EC2 key pair created for the cluster.
+	SSHKeyPairStatus() *infrav1.SSHKeyPairStatus
+
+	// SetSSHKeyPairStatus sets the EC2 key pair created for the cluster.
+	SetSSHKeyPairStatus(status *infrav1.SSHKeyPairStatus)
+
+	// KubeClient returns the client of the management cluster, to read and write the secrets of the key pair.
+	KubeClient() client.Client
+}
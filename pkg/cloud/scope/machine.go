@@ -18,7 +18,9 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/go-logr/logr"
@@ -38,6 +40,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// maxIAMRoleNameLength is the maximum length of an IAM role name.
+const maxIAMRoleNameLength = 64
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client       client.Client
@@ -46,6 +51,9 @@ type MachineScopeParams struct {
 	Machine      *clusterv1.Machine
 	InfraCluster EC2Scope
 	AWSMachine   *infrav1.AWSMachine
+
+	// ProviderIDFormat is the format of the provider ID set on the AWSMachine. Defaults to ProviderIDFormatZonal.
+	ProviderIDFormat ProviderIDFormat
 }
 
 // NewMachineScope creates a new MachineScope from the supplied parameters.
@@ -84,6 +92,8 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		Machine:      params.Machine,
 		InfraCluster: params.InfraCluster,
 		AWSMachine:   params.AWSMachine,
+
+		providerIDFormat: params.ProviderIDFormat,
 	}, nil
 }
 
@@ -97,6 +107,11 @@ type MachineScope struct {
 	Machine      *clusterv1.Machine
 	InfraCluster EC2Scope
 	AWSMachine   *infrav1.AWSMachine
+
+	providerIDFormat ProviderIDFormat
+
+	// allocatedPrivateIP is the private IP address an IPAM provider allocated to the machine.
+	allocatedPrivateIP *string
 }
 
 // Name returns the AWSMachine name.
@@ -122,6 +137,86 @@ func (m *MachineScope) Role() string {
 	return "node"
 }
 
+// IAMInstanceProfile returns the name of the IAM instance profile to assign to the instance.
+func (m *MachineScope) IAMInstanceProfile() string {
+	if m.AWSMachine.Spec.ManagedIAMInstanceProfile != nil {
+		return m.ManagedIAMInstanceProfileName()
+	}
+	return m.AWSMachine.Spec.IAMInstanceProfile
+}
+
+// RootVolume returns the root volume of the machine. Machines without a root volume use the default
+// root volume of the cluster, and root volumes without a type use its type.
+func (m *MachineScope) RootVolume() *infrav1.Volume {
+	var defaults *infrav1.RootVolumeDefaults
+	if machineDefaults := m.InfraCluster.MachineDefaults(); machineDefaults != nil {
+		defaults = machineDefaults.RootVolume
+	}
+
+	rootVolume := m.AWSMachine.Spec.RootVolume
+	switch {
+	case defaults == nil:
+		return rootVolume
+	case rootVolume == nil:
+		return &infrav1.Volume{Size: defaults.Size, Type: defaults.Type}
+	case rootVolume.Type == "":
+		rootVolume = rootVolume.DeepCopy()
+		rootVolume.Type = defaults.Type
+	}
+	return rootVolume
+}
+
+// InstanceMetadataOptions returns the instance metadata options of the machine, or the default ones of the
+// cluster when the machine doesn't set any.
+func (m *MachineScope) InstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	if m.AWSMachine.Spec.InstanceMetadataOptions != nil {
+		return m.AWSMachine.Spec.InstanceMetadataOptions
+	}
+	if machineDefaults := m.InfraCluster.MachineDefaults(); machineDefaults != nil {
+		return machineDefaults.InstanceMetadataOptions
+	}
+	return nil
+}
+
+// AdditionalSecurityGroups returns the additional security groups of the machine, or the default ones of the
+// cluster when the machine doesn't set any.
+func (m *MachineScope) AdditionalSecurityGroups() []infrav1.AWSResourceReference {
+	if len(m.AWSMachine.Spec.AdditionalSecurityGroups) > 0 {
+		return m.AWSMachine.Spec.AdditionalSecurityGroups
+	}
+	if machineDefaults := m.InfraCluster.MachineDefaults(); machineDefaults != nil {
+		return machineDefaults.AdditionalSecurityGroups
+	}
+	return nil
+}
+
+// PrivateIP returns the private IP address the primary network interface of the instance is launched with: the
+// address of the spec, or the one allocated by an IPAM provider. It returns nil when EC2 picks one.
+func (m *MachineScope) PrivateIP() *string {
+	if m.AWSMachine.Spec.PrivateIP != nil {
+		return m.AWSMachine.Spec.PrivateIP
+	}
+	return m.allocatedPrivateIP
+}
+
+// SetAllocatedPrivateIP sets the private IP address an IPAM provider allocated to the machine.
+func (m *MachineScope) SetAllocatedPrivateIP(address string) {
+	m.allocatedPrivateIP = &address
+}
+
+// ManagedIAMInstanceProfileName returns the name of the IAM role and instance profile created for the machine.
+// Names exceeding the IAM role name limit are truncated and suffixed with a hash to keep them unique.
+func (m *MachineScope) ManagedIAMInstanceProfileName() string {
+	name := fmt.Sprintf("%s-%s-%s", m.Cluster.Name, m.Namespace(), m.Name())
+	if len(name) <= maxIAMRoleNameLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:])[:8]
+	return fmt.Sprintf("%s-%s", name[:maxIAMRoleNameLength-len(suffix)-1], suffix)
+}
+
 // GetInstanceID returns the AWSMachine instance id by parsing Spec.ProviderID.
 func (m *MachineScope) GetInstanceID() *string {
 	parsed, err := noderefutil.NewProviderID(m.GetProviderID())
@@ -141,8 +236,12 @@ func (m *MachineScope) GetProviderID() string {
 
 // SetProviderID sets the AWSMachine providerID in spec.
 func (m *MachineScope) SetProviderID(instanceID, availabilityZone string) {
-	providerID := fmt.Sprintf("aws:///%s/%s", availabilityZone, instanceID)
-	m.AWSMachine.Spec.ProviderID = pointer.StringPtr(providerID)
+	m.AWSMachine.Spec.ProviderID = pointer.StringPtr(m.ProviderID(instanceID, availabilityZone))
+}
+
+// ProviderID returns the provider ID of the instance in the format of the scope.
+func (m *MachineScope) ProviderID(instanceID, availabilityZone string) string {
+	return m.providerIDFormat.ProviderID(instanceID, availabilityZone)
 }
 
 // SetInstanceID sets the AWSMachine instanceID in spec.
@@ -160,6 +259,26 @@ func (m *MachineScope) SetInstanceState(v infrav1.InstanceState) {
 	m.AWSMachine.Status.InstanceState = &v
 }
 
+// SetInstanceDetails sets the AWSMachine status instance details from the given instance.
+func (m *MachineScope) SetInstanceDetails(i *infrav1.Instance) {
+	m.AWSMachine.Status.Instance = &infrav1.InstanceDetails{
+		LaunchTime:                i.LaunchTime,
+		InstanceType:              i.Type,
+		Lifecycle:                 i.Lifecycle,
+		SpotInstanceRequestID:     i.SpotInstanceRequestID,
+		AvailabilityZone:          i.AvailabilityZone,
+		HostID:                    i.HostID,
+		PrimaryNetworkInterfaceID: i.PrimaryNetworkInterfaceID,
+		VolumeIDs:                 i.VolumeIDs,
+		CPUOptions:                i.CPUOptions,
+	}
+}
+
+// SetScheduledEvents sets the AWSMachine status scheduled events.
+func (m *MachineScope) SetScheduledEvents(events []infrav1.InstanceScheduledEvent) {
+	m.AWSMachine.Status.ScheduledEvents = events
+}
+
 // SetReady sets the AWSMachine Ready Status.
 func (m *MachineScope) SetReady() {
 	m.AWSMachine.Status.Ready = true
@@ -200,11 +319,19 @@ func (m *MachineScope) SecureSecretsBackend() infrav1.SecretBackend {
 }
 
 // UserDataIsUncompressed returns the computed value of whether or not
-// userdata should be compressed using gzip.
+// userdata should be compressed using gzip. TOML user data is never compressed.
 func (m *MachineScope) UserDataIsUncompressed() bool {
+	if m.UserDataFormat() == infrav1.UserDataFormatTOML {
+		return true
+	}
 	return m.AWSMachine.Spec.UncompressedUserData != nil && *m.AWSMachine.Spec.UncompressedUserData
 }
 
+// UserDataFormat returns the format of the user data read by the OS family of the machine.
+func (m *MachineScope) UserDataFormat() infrav1.UserDataFormat {
+	return m.AWSMachine.Spec.AMI.OSFamily.UserDataFormat()
+}
+
 // GetSecretPrefix returns the prefix for the secrets belonging
 // to the AWSMachine in AWS Secrets Manager.
 func (m *MachineScope) GetSecretPrefix() string {
@@ -282,6 +409,11 @@ func (m *MachineScope) PatchObject() error {
 		applicableConditions = append(applicableConditions, infrav1.ELBAttachedCondition)
 	}
 
+	// The connectivity check is optional, so it only affects the summary of the machines it is set on.
+	if conditions.Has(m.AWSMachine, infrav1.APIServerReachableCondition) {
+		applicableConditions = append(applicableConditions, infrav1.APIServerReachableCondition)
+	}
+
 	conditions.SetSummary(m.AWSMachine,
 		conditions.WithConditions(applicableConditions...),
 		conditions.WithStepCounterIf(m.AWSMachine.ObjectMeta.DeletionTimestamp.IsZero()),
@@ -296,6 +428,11 @@ func (m *MachineScope) PatchObject() error {
 			infrav1.InstanceReadyCondition,
 			infrav1.SecurityGroupsReadyCondition,
 			infrav1.ELBAttachedCondition,
+			infrav1.InstanceUnexpectedTerminationCondition,
+			infrav1.InstanceScheduledEventsCondition,
+			infrav1.UserDataOutOfDateCondition,
+			infrav1.SpotMaxPriceBelowMarketCondition,
+			infrav1.APIServerReachableCondition,
 		}})
 }
 
@@ -355,9 +492,16 @@ func (m *MachineScope) IsExternallyManaged() bool {
 	return annotations.IsExternallyManaged(m.InfraCluster.InfraCluster())
 }
 
+// SpotMarketOptions returns the spot market options the instance of the AWSMachine is launched with.
+// It returns nil once the machine has fallen back to an on-demand instance.
+func (m *MachineScope) SpotMarketOptions() *infrav1.SpotMarketOptions {
+	if m.AWSMachine.Status.OnDemandFallback {
+		return nil
+	}
+	return m.AWSMachine.Spec.SpotMarketOptions
+}
+
 // SetInterruptible sets the AWSMachine status Interruptible.
 func (m *MachineScope) SetInterruptible() {
-	if m.AWSMachine.Spec.SpotMarketOptions != nil {
-		m.AWSMachine.Status.Interruptible = true
-	}
+	m.AWSMachine.Status.Interruptible = m.SpotMarketOptions() != nil
 }
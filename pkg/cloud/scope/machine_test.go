@@ -224,4 +224,75 @@ func TestSetProviderID(t *testing.T) {
 	if providerID != expectedProviderID {
 		t.Fatalf("Expected providerID %s, got %s", expectedProviderID, providerID)
 	}
+
+	scope.providerIDFormat = ProviderIDFormatZoneless
+	scope.SetProviderID("test-id", "test-zone-1a")
+	providerID = *scope.AWSMachine.Spec.ProviderID
+	expectedProviderID = "aws:////test-id"
+	if providerID != expectedProviderID {
+		t.Fatalf("Expected providerID %s, got %s", expectedProviderID, providerID)
+	}
+}
+
+func TestParseProviderIDFormat(t *testing.T) {
+	for _, s := range []string{"Zonal", "Zoneless"} {
+		if f, err := ParseProviderIDFormat(s); err != nil || string(f) != s {
+			t.Fatalf("Expected format %s to be parsed, got %q, %v", s, f, err)
+		}
+	}
+	if _, err := ParseProviderIDFormat("zonal"); err == nil {
+		t.Fatal("Expected unknown format to be rejected")
+	}
+}
+
+func TestMachineDefaults(t *testing.T) {
+	scope, err := setupMachineScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scope.RootVolume() != nil || scope.InstanceMetadataOptions() != nil || scope.AdditionalSecurityGroups() != nil {
+		t.Fatal("Expected no root volume, instance metadata options or additional security groups without defaults")
+	}
+
+	defaultOptions := &infrav1.InstanceMetadataOptions{HTTPTokens: "required", HTTPPutResponseHopLimit: 2}
+	defaultGroups := []infrav1.AWSResourceReference{{ID: pointer.StringPtr("sg-default")}}
+	scope.InfraCluster.(*ClusterScope).AWSCluster.Spec.MachineDefaults = &infrav1.MachineDefaults{
+		InstanceMetadataOptions:  defaultOptions,
+		RootVolume:               &infrav1.RootVolumeDefaults{Size: 50, Type: "gp3"},
+		AdditionalSecurityGroups: defaultGroups,
+	}
+
+	if v := scope.RootVolume(); v == nil || v.Size != 50 || v.Type != "gp3" {
+		t.Fatalf("Expected the default root volume, got %+v", v)
+	}
+	if o := scope.InstanceMetadataOptions(); o != defaultOptions {
+		t.Fatalf("Expected the default instance metadata options, got %+v", o)
+	}
+	if g := scope.AdditionalSecurityGroups(); len(g) != 1 || *g[0].ID != "sg-default" {
+		t.Fatalf("Expected the default additional security groups, got %+v", g)
+	}
+
+	machineOptions := &infrav1.InstanceMetadataOptions{HTTPEndpoint: "disabled"}
+	scope.AWSMachine.Spec.RootVolume = &infrav1.Volume{Size: 100}
+	scope.AWSMachine.Spec.InstanceMetadataOptions = machineOptions
+	scope.AWSMachine.Spec.AdditionalSecurityGroups = []infrav1.AWSResourceReference{{ID: pointer.StringPtr("sg-machine")}}
+
+	if v := scope.RootVolume(); v.Size != 100 || v.Type != "gp3" {
+		t.Fatalf("Expected the machine root volume with the default type, got %+v", v)
+	}
+	if scope.AWSMachine.Spec.RootVolume.Type != "" {
+		t.Fatal("Expected the machine root volume to be left unchanged")
+	}
+	if o := scope.InstanceMetadataOptions(); o != machineOptions {
+		t.Fatalf("Expected the machine instance metadata options, got %+v", o)
+	}
+	if g := scope.AdditionalSecurityGroups(); len(g) != 1 || *g[0].ID != "sg-machine" {
+		t.Fatalf("Expected the machine additional security groups, got %+v", g)
+	}
+
+	scope.AWSMachine.Spec.RootVolume.Type = "io1"
+	if v := scope.RootVolume(); v.Type != "io1" {
+		t.Fatalf("Expected the machine root volume type, got %+v", v)
+	}
 }
@@ -163,6 +163,7 @@ func (m *MachinePoolScope) PatchObject() error {
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			expinfrav1.ASGReadyCondition,
 			expinfrav1.LaunchTemplateReadyCondition,
+			expinfrav1.FleetReadyCondition,
 		}})
 }
 
@@ -219,6 +220,11 @@ func (m *MachinePoolScope) IsEKSManaged() bool {
 	return m.InfraCluster.InfraCluster().GetObjectKind().GroupVersionKind().Kind == "AWSManagedControlPlane"
 }
 
+// IsFleetBackend checks if the instances of the AWSMachinePool are launched with EC2 Fleet instead of an ASG.
+func (m *MachinePoolScope) IsFleetBackend() bool {
+	return m.AWSMachinePool.Spec.Backend == expinfrav1.MachinePoolBackendEC2Fleet
+}
+
 // SubnetIDs returns the machine pool subnet IDs.
 func (m *MachinePoolScope) SubnetIDs() ([]string, error) {
 	subnetIDs := make([]string, len(m.AWSMachinePool.Spec.Subnets))
@@ -313,6 +319,41 @@ func (m *MachinePoolScope) getNodeStatusByProviderID(ctx context.Context, provid
 	return nodeStatusMap, nil
 }
 
+// GetScaleInProtection returns whether the instances should be protected from scale-in, according to the
+// ScaleDownDisabledAnnotation of their node. The instances without a node are left out.
+func (m *MachinePoolScope) GetScaleInProtection(ctx context.Context, instances []infrav1.Instance) (map[string]bool, error) {
+	instanceIDs := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		instanceIDs[instance.ID] = true
+	}
+
+	workloadClient, err := remote.NewClusterClient(ctx, "", m.client, util.ObjectKey(m.Cluster))
+	if err != nil {
+		return nil, err
+	}
+
+	protection := map[string]bool{}
+	nodeList := corev1.NodeList{}
+	for {
+		if err := workloadClient.List(ctx, &nodeList, client.Continue(nodeList.Continue)); err != nil {
+			return nil, errors.Wrapf(err, "failed to List nodes")
+		}
+
+		for _, node := range nodeList.Items {
+			strList := strings.Split(node.Spec.ProviderID, "/")
+			if instanceID := strList[len(strList)-1]; instanceIDs[instanceID] {
+				protection[instanceID] = node.Annotations[expinfrav1.ScaleDownDisabledAnnotation] == "true"
+			}
+		}
+
+		if nodeList.Continue == "" {
+			break
+		}
+	}
+
+	return protection, nil
+}
+
 func nodeIsReady(node corev1.Node) bool {
 	for _, n := range node.Status.Conditions {
 		if n.Type == corev1.NodeReady {
@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"fmt"
+	"time"
 
 	awsclient "github.com/aws/aws-sdk-go/aws/client"
 	"github.com/go-logr/logr"
@@ -38,6 +39,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
 )
 
 var (
@@ -179,6 +181,16 @@ func (s *ManagedControlPlaneScope) CNIIngressRules() infrav1.CNIIngressRules {
 	return infrav1.CNIIngressRules{}
 }
 
+// AllowNodeToNodeTraffic returns whether all traffic between nodes is allowed.
+func (s *ManagedControlPlaneScope) AllowNodeToNodeTraffic() bool {
+	return s.ControlPlane.Spec.NetworkSpec.AllowNodeToNodeTraffic
+}
+
+// SecurityGroupPolicy returns the ports the security groups of the cluster may open to each other.
+func (s *ManagedControlPlaneScope) SecurityGroupPolicy() *infrav1.SecurityGroupPolicy {
+	return s.ControlPlane.Spec.NetworkSpec.SecurityGroupPolicy
+}
+
 // SecurityGroups returns the control plane security groups as a map, it creates the map if empty.
 func (s *ManagedControlPlaneScope) SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup {
 	return s.ControlPlane.Status.Network.SecurityGroups
@@ -189,11 +201,41 @@ func (s *ManagedControlPlaneScope) SecondaryCidrBlock() *string {
 	return s.ControlPlane.Spec.SecondaryCidrBlock
 }
 
+// Firewall returns the AWS Network Firewall inspecting the egress of the private subnets, if any.
+func (s *ManagedControlPlaneScope) Firewall() *infrav1.NetworkFirewall {
+	return s.ControlPlane.Spec.NetworkSpec.Firewall
+}
+
+// GatewayLoadBalancerEndpointRoutes returns the routes through Gateway Load Balancer endpoints of the route tables.
+func (s *ManagedControlPlaneScope) GatewayLoadBalancerEndpointRoutes() []infrav1.GatewayLoadBalancerEndpointRoute {
+	return s.ControlPlane.Spec.NetworkSpec.GatewayLoadBalancerEndpointRoutes
+}
+
+// Routes returns the static routes of the route tables of all the subnets.
+func (s *ManagedControlPlaneScope) Routes() []infrav1.Route {
+	return s.ControlPlane.Spec.NetworkSpec.Routes
+}
+
+// ElasticIPPool returns the pre-allocated Elastic IPs of the NAT gateways and the bastion host, if any.
+func (s *ManagedControlPlaneScope) ElasticIPPool() *infrav1.ElasticIPPool {
+	return s.ControlPlane.Spec.NetworkSpec.ElasticIPPool
+}
+
+// NatGatewayAllocationIDs returns the allocation IDs of the Elastic IPs of the NAT gateways by availability zone.
+func (s *ManagedControlPlaneScope) NatGatewayAllocationIDs() map[string]string {
+	return s.ControlPlane.Spec.NetworkSpec.NatGatewayAllocationIDs
+}
+
 // SecurityGroupOverrides returns the the security groups that are overridden in the ControlPlane spec.
 func (s *ManagedControlPlaneScope) SecurityGroupOverrides() map[infrav1.SecurityGroupRole]string {
 	return s.ControlPlane.Spec.NetworkSpec.SecurityGroupOverrides
 }
 
+// SecurityGroupOverrideTracking returns how the security group overrides of the ControlPlane spec are followed.
+func (s *ManagedControlPlaneScope) SecurityGroupOverrideTracking() infrav1.SecurityGroupOverrideTracking {
+	return s.ControlPlane.Spec.NetworkSpec.SecurityGroupOverrideTracking
+}
+
 // Name returns the CAPI cluster name.
 func (s *ManagedControlPlaneScope) Name() string {
 	return s.Cluster.Name
@@ -233,6 +275,7 @@ func (s *ManagedControlPlaneScope) PatchObject() error {
 			infrav1.NatGatewaysReadyCondition,
 			infrav1.RouteTablesReadyCondition,
 			infrav1.BastionHostReadyCondition,
+			infrav1.SecurityGroupPolicyCompliantCondition,
 			ekscontrolplanev1.EKSControlPlaneCreatingCondition,
 			ekscontrolplanev1.EKSControlPlaneReadyCondition,
 			ekscontrolplanev1.EKSControlPlaneUpdatingCondition,
@@ -259,6 +302,11 @@ func (s *ManagedControlPlaneScope) APIServerPort() int32 {
 	return 443
 }
 
+// APIServerInstancePort returns the port of the API server, as EKS doesn't run it on instances of the cluster.
+func (s *ManagedControlPlaneScope) APIServerInstancePort() int32 {
+	return s.APIServerPort()
+}
+
 // SetFailureDomain sets the infrastructure provider failure domain key to the spec given as input.
 func (s *ManagedControlPlaneScope) SetFailureDomain(id string, spec clusterv1.FailureDomainSpec) {
 	if s.ControlPlane.Status.FailureDomains == nil {
@@ -282,11 +330,52 @@ func (s *ManagedControlPlaneScope) Bastion() *infrav1.Bastion {
 	return &s.ControlPlane.Spec.Bastion
 }
 
+// ControlPlaneLoadBalancer returns nil, as the API server of an EKS cluster isn't behind a load balancer the provider manages.
+func (s *ManagedControlPlaneScope) ControlPlaneLoadBalancer() *infrav1.AWSLoadBalancerSpec {
+	return nil
+}
+
+// ControlPlaneLoadBalancerScheme returns the default scheme, as the API server of an EKS cluster isn't behind a load
+// balancer the provider manages.
+func (s *ManagedControlPlaneScope) ControlPlaneLoadBalancerScheme() infrav1.ClassicELBScheme {
+	return infrav1.ClassicELBSchemeInternetFacing
+}
+
+// LoadBalancerReadyTimeout returns how long to wait for load balancer operations to complete.
+func (s *ManagedControlPlaneScope) LoadBalancerReadyTimeout() time.Duration {
+	return wait.DefaultLoadBalancerReadyTimeout
+}
+
+// Karpenter returns nil, as the AWS resources for Karpenter are only provisioned for AWSClusters.
+func (s *ManagedControlPlaneScope) Karpenter() *infrav1.Karpenter {
+	return nil
+}
+
 // SetBastionInstance sets the bastion instance in the status of the cluster.
 func (s *ManagedControlPlaneScope) SetBastionInstance(instance *infrav1.Instance) {
 	s.ControlPlane.Status.Bastion = instance
 }
 
+// BastionInstances returns all the bastion hosts recorded in the status of the cluster.
+func (s *ManagedControlPlaneScope) BastionInstances() []infrav1.Instance {
+	return s.ControlPlane.Status.Bastions
+}
+
+// SetBastionInstances sets all the bastion hosts in the status of the cluster.
+func (s *ManagedControlPlaneScope) SetBastionInstances(instances []infrav1.Instance) {
+	s.ControlPlane.Status.Bastions = instances
+}
+
+// BastionLoadBalancer returns the load balancer in front of the bastion hosts recorded in the status of the cluster.
+func (s *ManagedControlPlaneScope) BastionLoadBalancer() *infrav1.BastionLoadBalancer {
+	return s.ControlPlane.Status.BastionLoadBalancer
+}
+
+// SetBastionLoadBalancer sets the load balancer in front of the bastion hosts in the status of the cluster.
+func (s *ManagedControlPlaneScope) SetBastionLoadBalancer(lb *infrav1.BastionLoadBalancer) {
+	s.ControlPlane.Status.BastionLoadBalancer = lb
+}
+
 // SSHKeyName returns the SSH key name to use for instances.
 func (s *ManagedControlPlaneScope) SSHKeyName() *string {
 	return s.ControlPlane.Spec.SSHKeyName
@@ -323,6 +412,16 @@ func (s *ManagedControlPlaneScope) AllowAdditionalRoles() bool {
 	return s.allowAdditionalRoles
 }
 
+// RolePath returns the path of the IAM roles created for the cluster.
+func (s *ManagedControlPlaneScope) RolePath() string {
+	return s.ControlPlane.Spec.RolePath
+}
+
+// RolePermissionsBoundary returns the permissions boundary of the IAM roles created for the cluster.
+func (s *ManagedControlPlaneScope) RolePermissionsBoundary() string {
+	return s.ControlPlane.Spec.RolePermissionsBoundary
+}
+
 // ImageLookupFormat returns the format string to use when looking up AMIs.
 func (s *ManagedControlPlaneScope) ImageLookupFormat() string {
 	return s.ControlPlane.Spec.ImageLookupFormat
@@ -338,6 +437,29 @@ func (s *ManagedControlPlaneScope) ImageLookupBaseOS() string {
 	return s.ControlPlane.Spec.ImageLookupBaseOS
 }
 
+// MachineDefaults returns nil, as managed control planes don't set defaults for their AWSMachines.
+func (s *ManagedControlPlaneScope) MachineDefaults() *infrav1.MachineDefaults {
+	return nil
+}
+
+// DeletionPolicy returns no deletion policies, as the AWS resources of managed control planes are always deleted.
+func (s *ManagedControlPlaneScope) DeletionPolicy() infrav1.DeletionPolicy {
+	return infrav1.DeletionPolicy{}
+}
+
+// InstanceRunningTimeout returns how long to wait for a new instance to be running.
+func (s *ManagedControlPlaneScope) InstanceRunningTimeout() time.Duration {
+	return wait.DefaultInstanceRunningTimeout
+}
+
+// InstanceRunningRequeueInterval returns how often to check whether a pending instance is running.
+func (s *ManagedControlPlaneScope) InstanceRunningRequeueInterval() time.Duration {
+	if i := s.ControlPlane.Spec.RequeueIntervals; i != nil && i.InstanceRunning != nil && i.InstanceRunning.Duration > 0 {
+		return i.InstanceRunning.Duration
+	}
+	return wait.DefaultInstanceRunningRequeueInterval
+}
+
 // IAMAuthConfig returns the IAM authenticator config. The returned value will never be nil.
 func (s *ManagedControlPlaneScope) IAMAuthConfig() *ekscontrolplanev1.IAMAuthenticatorConfig {
 	if s.ControlPlane.Spec.IAMAuthenticatorConfig == nil {
@@ -19,11 +19,13 @@ package scope
 import (
 	"context"
 	"fmt"
+	"time"
 
 	awsclient "github.com/aws/aws-sdk-go/aws/client"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -159,11 +161,29 @@ func (s *ManagedMachinePoolScope) RoleName() string {
 	return s.ManagedMachinePool.Spec.RoleName
 }
 
+// RolePath returns the path of the IAM roles created for the cluster.
+func (s *ManagedMachinePoolScope) RolePath() string {
+	return s.ControlPlane.Spec.RolePath
+}
+
+// RolePermissionsBoundary returns the permissions boundary of the IAM roles created for the cluster.
+func (s *ManagedMachinePoolScope) RolePermissionsBoundary() string {
+	return s.ControlPlane.Spec.RolePermissionsBoundary
+}
+
 // Version returns the nodegroup Kubernetes version.
 func (s *ManagedMachinePoolScope) Version() *string {
 	return s.MachinePool.Spec.Template.Spec.Version
 }
 
+// NodegroupUpdateRequeueInterval returns how often to check whether an update of the nodegroup has completed.
+func (s *ManagedMachinePoolScope) NodegroupUpdateRequeueInterval() time.Duration {
+	if i := s.ControlPlane.Spec.RequeueIntervals; i != nil && i.NodegroupUpdate != nil && i.NodegroupUpdate.Duration > 0 {
+		return i.NodegroupUpdate.Duration
+	}
+	return wait.DefaultNodegroupUpdateRequeueInterval
+}
+
 // ControlPlaneSubnets returns the control plane subnets.
 func (s *ManagedMachinePoolScope) ControlPlaneSubnets() infrav1.Subnets {
 	return s.ControlPlane.Spec.NetworkSpec.Subnets
@@ -231,6 +251,7 @@ func (s *ManagedMachinePoolScope) PatchObject() error {
 		s.ManagedMachinePool,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			infrav1exp.EKSNodegroupReadyCondition,
+			infrav1exp.EKSNodegroupConfigSyncedCondition,
 			infrav1exp.IAMNodegroupRolesReadyCondition,
 		}})
 }
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ProviderIDFormat is the format of the provider IDs set on AWSMachines.
+type ProviderIDFormat string
+
+const (
+	// ProviderIDFormatZonal is the aws:///<availability-zone>/<instance-id> format, which the in-tree and the
+	// external AWS cloud providers set on nodes.
+	ProviderIDFormatZonal ProviderIDFormat = "Zonal"
+
+	// ProviderIDFormatZoneless is the aws:////<instance-id> format set by earlier releases of the provider.
+	ProviderIDFormatZoneless ProviderIDFormat = "Zoneless"
+)
+
+// ParseProviderIDFormat returns the provider ID format with the given name.
+func ParseProviderIDFormat(s string) (ProviderIDFormat, error) {
+	switch f := ProviderIDFormat(s); f {
+	case ProviderIDFormatZonal, ProviderIDFormatZoneless:
+		return f, nil
+	default:
+		return "", errors.Errorf("unknown provider ID format %q, must be %s or %s", s, ProviderIDFormatZonal, ProviderIDFormatZoneless)
+	}
+}
+
+// ProviderID returns the provider ID of the instance in the format.
+func (f ProviderIDFormat) ProviderID(instanceID, availabilityZone string) string {
+	if f == ProviderIDFormatZoneless {
+		availabilityZone = ""
+	}
+	return fmt.Sprintf("aws:///%s/%s", availabilityZone, instanceID)
+}
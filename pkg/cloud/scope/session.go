@@ -21,13 +21,16 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/go-logr/logr"
@@ -37,7 +40,9 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/identity"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/metrics"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/transport"
 	"sigs.k8s.io/cluster-api-provider-aws/util/system"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
@@ -60,9 +65,45 @@ type ServiceEndpoint struct {
 var sessionCache sync.Map
 var providerCache sync.Map
 
+// MaxSessionDuration is how long the cached AWS session of a cluster is used before it is created again, with
+// credentials retrieved anew from its identity. Zero keeps the sessions until they are evicted.
+var MaxSessionDuration time.Duration
+
 type sessionCacheEntry struct {
 	session         *session.Session
 	serviceLimiters throttle.ServiceLimiters
+	// cluster is the AWSCluster or AWSManagedControlPlane the session was created for, if any.
+	cluster client.ObjectKey
+	// identities are the identities the credentials of the session come from, including the source identities
+	// of role identities.
+	identities []infrav1.AWSIdentityReference
+	// providerHashes are the keys of the cached providers of the session.
+	providerHashes []string
+	// throttleKey identifies the AWS account and region the session makes requests to.
+	throttleKey string
+	created     time.Time
+}
+
+// storeSession caches a session and records the size of the cache.
+func storeSession(name string, entry *sessionCacheEntry) {
+	entry.created = time.Now()
+	sessionCache.Store(name, entry)
+	recordSessionCacheEntries()
+}
+
+// deleteSession drops a cached session and records the size of the cache.
+func deleteSession(name interface{}) {
+	sessionCache.Delete(name)
+	recordSessionCacheEntries()
+}
+
+func recordSessionCacheEntries() {
+	entries := 0
+	sessionCache.Range(func(_, _ interface{}) bool {
+		entries++
+		return true
+	})
+	metrics.SetSessionCacheEntries(entries)
 }
 
 // SessionInterface is the interface for AWSCluster and ManagedCluster to be used to get session using identityRef.
@@ -71,9 +112,11 @@ var SessionInterface interface {
 
 func sessionForRegion(region string, endpoint []ServiceEndpoint) (*session.Session, throttle.ServiceLimiters, error) {
 	if s, ok := sessionCache.Load(region); ok {
+		metrics.RecordSessionCacheLookup(true)
 		entry := s.(*sessionCacheEntry)
 		return entry.session, entry.serviceLimiters, nil
 	}
+	metrics.RecordSessionCacheLookup(false)
 
 	resolver := func(service, region string, optFns ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
 		for _, s := range endpoint {
@@ -86,16 +129,22 @@ func sessionForRegion(region string, endpoint []ServiceEndpoint) (*session.Sessi
 		}
 		return endpoints.DefaultResolver().EndpointFor(service, region, optFns...)
 	}
+	httpClient, err := transport.NewHTTPClient(transport.Options{})
+	if err != nil {
+		return nil, nil, err
+	}
 	ns, err := session.NewSession(&aws.Config{
-		Region:           aws.String(region),
-		EndpointResolver: endpoints.ResolverFunc(resolver),
+		Region:              aws.String(region),
+		EndpointResolver:    endpoints.ResolverFunc(resolver),
+		STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
+		HTTPClient:          httpClient,
 	})
 	if err != nil {
 		return nil, nil, err
 	}
 
 	sl := newServiceLimiters()
-	sessionCache.Store(region, &sessionCacheEntry{
+	storeSession(region, &sessionCacheEntry{
 		session:         ns,
 		serviceLimiters: sl,
 	})
@@ -106,6 +155,11 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Cl
 	log := logger.WithName("identity")
 	log.V(4).Info("Creating an AWS Session")
 
+	sessionName := getSessionName(region, clusterScoper)
+	if evictExpiredSession(sessionName) {
+		log.V(2).Info("Cached AWS session exceeded the maximum session duration, creating a new one", "max-session-duration", MaxSessionDuration)
+	}
+
 	resolver := func(service, region string, optFns ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
 		for _, s := range endpoint {
 			if service == s.ServiceID {
@@ -127,6 +181,7 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Cl
 
 	isChanged := false
 	awsProviders := make([]credentials.Provider, len(providers))
+	providerHashes := make([]string, len(providers))
 	for i, provider := range providers {
 		// load an existing matching providers from the cache if such a providers exists
 		providerHash, err := provider.Hash()
@@ -136,23 +191,41 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Cl
 		cachedProvider, ok := providerCache.Load(providerHash)
 		if ok {
 			provider = cachedProvider.(identity.AWSPrincipalTypeProvider)
+			providers[i] = provider
 		} else {
 			isChanged = true
 			// add this providers to the cache
 			providerCache.Store(providerHash, provider)
 		}
 		awsProviders[i] = provider.(credentials.Provider)
+		providerHashes[i] = providerHash
 	}
 
 	if !isChanged {
-		if s, ok := sessionCache.Load(getSessionName(region, clusterScoper)); ok {
+		if s, ok := sessionCache.Load(sessionName); ok {
+			metrics.RecordSessionCacheLookup(true)
 			entry := s.(*sessionCacheEntry)
 			return entry.session, entry.serviceLimiters, nil
 		}
 	}
+	metrics.RecordSessionCacheLookup(false)
+
+	httpClientOptions, err := identityHTTPClientOptions(context.Background(), k8sClient, clusterScoper.IdentityRef())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get the HTTP client options of the identity")
+	}
+	httpClient, err := transport.NewHTTPClient(httpClientOptions)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create the HTTP client of the AWS session")
+	}
+
+	// Use the STS endpoint of the region rather than the global one, whose session tokens are not valid in the
+	// regions which must be opted in to.
 	awsConfig := &aws.Config{
-		Region:           aws.String(region),
-		EndpointResolver: endpoints.ResolverFunc(resolver),
+		Region:              aws.String(region),
+		EndpointResolver:    endpoints.ResolverFunc(resolver),
+		STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
+		HTTPClient:          httpClient,
 	}
 
 	if len(providers) > 0 {
@@ -172,14 +245,135 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Cl
 		return nil, nil, errors.Wrap(err, "Failed to create a new AWS session")
 	}
 	sl := newServiceLimiters()
-	sessionCache.Store(getSessionName(region, clusterScoper), &sessionCacheEntry{
+	storeSession(sessionName, &sessionCacheEntry{
 		session:         ns,
 		serviceLimiters: sl,
+		cluster:         client.ObjectKey{Namespace: clusterScoper.Namespace(), Name: clusterScoper.InfraClusterName()},
+		identities:      sessionIdentities(clusterScoper.IdentityRef(), providers),
+		providerHashes:  providerHashes,
+		throttleKey:     sessionThrottleKey(region, clusterScoper.IdentityRef(), providers),
 	})
 
 	return ns, sl, nil
 }
 
+// EvictClusterSessions removes the cached sessions of an AWSCluster or AWSManagedControlPlane in all regions,
+// so that their credentials are no longer kept and refreshed. A new session is created when the cluster is
+// reconciled again.
+func EvictClusterSessions(key client.ObjectKey) {
+	sessionCache.Range(func(name, value interface{}) bool {
+		if value.(*sessionCacheEntry).cluster == key {
+			deleteSession(name)
+		}
+		return true
+	})
+}
+
+// EvictIdentitySessions removes the cached sessions whose credentials come from an identity, directly or as
+// the source identity of a role identity, together with their cached credential providers. It is called when
+// the identity changes so that new credentials are retrieved from it, and returns the clusters of the evicted
+// sessions.
+func EvictIdentitySessions(ref infrav1.AWSIdentityReference) []client.ObjectKey {
+	clusters := []client.ObjectKey{}
+	sessionCache.Range(func(name, value interface{}) bool {
+		entry := value.(*sessionCacheEntry)
+		for _, id := range entry.identities {
+			if id == ref {
+				evictSessionEntry(name, entry)
+				clusters = append(clusters, entry.cluster)
+				break
+			}
+		}
+		return true
+	})
+	return clusters
+}
+
+// evictExpiredSession removes the cached session if it is older than MaxSessionDuration, and returns whether
+// it did.
+func evictExpiredSession(name string) bool {
+	if MaxSessionDuration <= 0 {
+		return false
+	}
+	value, ok := sessionCache.Load(name)
+	if !ok {
+		return false
+	}
+	entry := value.(*sessionCacheEntry)
+	if time.Since(entry.created) < MaxSessionDuration {
+		return false
+	}
+	evictSessionEntry(name, entry)
+	return true
+}
+
+// evictSessionEntry removes a cached session and its credential providers, which hold the credentials it
+// retrieved.
+func evictSessionEntry(name interface{}, entry *sessionCacheEntry) {
+	for _, hash := range entry.providerHashes {
+		providerCache.Delete(hash)
+	}
+	deleteSession(name)
+}
+
+// sessionIdentities returns the identities the credentials of a session come from.
+func sessionIdentities(ref *infrav1.AWSIdentityReference, providers []identity.AWSPrincipalTypeProvider) []infrav1.AWSIdentityReference {
+	identities := []infrav1.AWSIdentityReference{}
+	add := func(id infrav1.AWSIdentityReference) {
+		for _, existing := range identities {
+			if existing == id {
+				return
+			}
+		}
+		identities = append(identities, id)
+	}
+	if ref != nil {
+		add(*ref)
+	}
+	for len(providers) > 0 {
+		var sources []identity.AWSPrincipalTypeProvider
+		for _, provider := range providers {
+			switch p := provider.(type) {
+			case *identity.AWSRolePrincipalTypeProvider:
+				add(infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: p.Principal.Name})
+				if source := p.SourceProvider(); source != nil {
+					sources = append(sources, source)
+				}
+			case *identity.AWSStaticPrincipalTypeProvider:
+				add(infrav1.AWSIdentityReference{Kind: infrav1.ClusterStaticIdentityKind, Name: p.Principal.Name})
+			}
+		}
+		providers = sources
+	}
+	return identities
+}
+
+// ThrottleKey returns the key of the AWS account and region the session of the cluster makes requests to, which
+// the clusters whose requests are throttled together share.
+func ThrottleKey(clusterScoper cloud.ClusterScoper) string {
+	name := getSessionName(clusterScoper.Region(), clusterScoper)
+	if s, ok := sessionCache.Load(name); ok {
+		return s.(*sessionCacheEntry).throttleKey
+	}
+	return name
+}
+
+// sessionThrottleKey identifies the AWS account of a session as far as it is known without calling AWS: the
+// account of the role assumed by a role identity, otherwise the identity itself.
+func sessionThrottleKey(region string, ref *infrav1.AWSIdentityReference, providers []identity.AWSPrincipalTypeProvider) string {
+	for _, provider := range providers {
+		if p, ok := provider.(*identity.AWSRolePrincipalTypeProvider); ok {
+			if role, err := arn.Parse(p.Principal.Spec.RoleArn); err == nil {
+				return fmt.Sprintf("%s/%s", region, role.AccountID)
+			}
+		}
+	}
+	if ref == nil || ref.Kind == infrav1.ControllerIdentityKind {
+		return fmt.Sprintf("%s/%s", region, infrav1.ControllerIdentityKind)
+	}
+	return fmt.Sprintf("%s/%s/%s", region, ref.Kind, ref.Name)
+}
+
 func getSessionName(region string, clusterScoper cloud.ClusterScoper) string {
 	return fmt.Sprintf("%s-%s-%s", region, clusterScoper.InfraClusterName(), clusterScoper.Namespace())
 }
@@ -188,6 +382,7 @@ func newServiceLimiters() throttle.ServiceLimiters {
 	return throttle.ServiceLimiters{
 		ec2.ServiceID:                      newEC2ServiceLimiter(),
 		elb.ServiceID:                      newGenericServiceLimiter(),
+		elbv2.ServiceID:                    newGenericServiceLimiter(),
 		resourcegroupstaggingapi.ServiceID: newGenericServiceLimiter(),
 		secretsmanager.ServiceID:           newGenericServiceLimiter(),
 	}
@@ -401,6 +596,38 @@ func buildAWSClusterControllerIdentity(ctx context.Context, identityObjectKey cl
 	return nil
 }
 
+// identityHTTPClientOptions returns the options of the HTTP client of the AWS session of a cluster: the
+// certificate authorities the referenced identity trusts and its proxy.
+func identityHTTPClientOptions(ctx context.Context, k8sClient client.Client, ref *infrav1.AWSIdentityReference) (transport.Options, error) {
+	if ref == nil {
+		return transport.Options{}, nil
+	}
+
+	key := client.ObjectKey{Name: ref.Name}
+	switch ref.Kind {
+	case infrav1.ControllerIdentityKind:
+		controllerIdentity := &infrav1.AWSClusterControllerIdentity{}
+		if err := k8sClient.Get(ctx, key, controllerIdentity); err != nil {
+			return transport.Options{}, err
+		}
+		return identity.HTTPClientOptions(&controllerIdentity.Spec.AWSClusterIdentitySpec), nil
+	case infrav1.ClusterStaticIdentityKind:
+		staticIdentity := &infrav1.AWSClusterStaticIdentity{}
+		if err := k8sClient.Get(ctx, key, staticIdentity); err != nil {
+			return transport.Options{}, err
+		}
+		return identity.HTTPClientOptions(&staticIdentity.Spec.AWSClusterIdentitySpec), nil
+	case infrav1.ClusterRoleIdentityKind:
+		roleIdentity := &infrav1.AWSClusterRoleIdentity{}
+		if err := k8sClient.Get(ctx, key, roleIdentity); err != nil {
+			return transport.Options{}, err
+		}
+		return identity.HTTPClientOptions(&roleIdentity.Spec.AWSClusterIdentitySpec), nil
+	default:
+		return transport.Options{}, errors.Errorf("No such provider known: '%s'", ref.Kind)
+	}
+}
+
 func getProvidersForCluster(ctx context.Context, k8sClient client.Client, clusterScoper cloud.ClusterScoper, log logr.Logger) ([]identity.AWSPrincipalTypeProvider, error) {
 	providers := make([]identity.AWSPrincipalTypeProvider, 0)
 	providers, err := buildProvidersForRef(ctx, providers, k8sClient, clusterScoper, clusterScoper.IdentityRef(), log)
@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -485,3 +486,116 @@ func TestPrincipalParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestEvictClusterSessions(t *testing.T) {
+	g := NewWithT(t)
+
+	key := client.ObjectKey{Namespace: "default", Name: "evicted"}
+	other := client.ObjectKey{Namespace: "other", Name: "evicted"}
+	sessionCache.Store("us-east-1-evicted-default", &sessionCacheEntry{cluster: key})
+	sessionCache.Store("eu-west-1-evicted-default", &sessionCacheEntry{cluster: key})
+	sessionCache.Store("us-east-1-evicted-other", &sessionCacheEntry{cluster: other})
+	sessionCache.Store("us-east-2", &sessionCacheEntry{})
+	defer func() {
+		sessionCache.Delete("us-east-1-evicted-other")
+		sessionCache.Delete("us-east-2")
+	}()
+
+	EvictClusterSessions(key)
+
+	_, ok := sessionCache.Load("us-east-1-evicted-default")
+	g.Expect(ok).To(BeFalse())
+	_, ok = sessionCache.Load("eu-west-1-evicted-default")
+	g.Expect(ok).To(BeFalse())
+	_, ok = sessionCache.Load("us-east-1-evicted-other")
+	g.Expect(ok).To(BeTrue())
+	_, ok = sessionCache.Load("us-east-2")
+	g.Expect(ok).To(BeTrue())
+}
+
+func TestEvictIdentitySessions(t *testing.T) {
+	g := NewWithT(t)
+
+	role := infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: "role"}
+	static := infrav1.AWSIdentityReference{Kind: infrav1.ClusterStaticIdentityKind, Name: "static"}
+	roleCluster := client.ObjectKey{Namespace: "default", Name: "role"}
+	sourceCluster := client.ObjectKey{Namespace: "default", Name: "source"}
+
+	sessionCache.Store("us-east-1-role-default", &sessionCacheEntry{cluster: roleCluster, identities: []infrav1.AWSIdentityReference{role, static}, providerHashes: []string{"role-hash"}})
+	sessionCache.Store("us-east-1-source-default", &sessionCacheEntry{cluster: sourceCluster, identities: []infrav1.AWSIdentityReference{static}})
+	providerCache.Store("role-hash", &identity.AWSRolePrincipalTypeProvider{})
+	defer sessionCache.Delete("us-east-1-source-default")
+
+	g.Expect(EvictIdentitySessions(role)).To(ConsistOf(roleCluster))
+	_, ok := sessionCache.Load("us-east-1-role-default")
+	g.Expect(ok).To(BeFalse())
+	_, ok = providerCache.Load("role-hash")
+	g.Expect(ok).To(BeFalse())
+	_, ok = sessionCache.Load("us-east-1-source-default")
+	g.Expect(ok).To(BeTrue())
+
+	g.Expect(EvictIdentitySessions(role)).To(BeEmpty())
+	g.Expect(EvictIdentitySessions(static)).To(ConsistOf(sourceCluster))
+}
+
+func TestEvictExpiredSession(t *testing.T) {
+	g := NewWithT(t)
+
+	defer func(d time.Duration) { MaxSessionDuration = d }(MaxSessionDuration)
+	defer sessionCache.Delete("us-east-1-expired-default")
+
+	sessionCache.Store("us-east-1-expired-default", &sessionCacheEntry{created: time.Now().Add(-2 * time.Hour)})
+
+	MaxSessionDuration = 0
+	g.Expect(evictExpiredSession("us-east-1-expired-default")).To(BeFalse())
+
+	MaxSessionDuration = 3 * time.Hour
+	g.Expect(evictExpiredSession("us-east-1-expired-default")).To(BeFalse())
+
+	MaxSessionDuration = time.Hour
+	g.Expect(evictExpiredSession("us-east-1-expired-default")).To(BeTrue())
+	_, ok := sessionCache.Load("us-east-1-expired-default")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestSessionIdentities(t *testing.T) {
+	g := NewWithT(t)
+
+	var source identity.AWSPrincipalTypeProvider = identity.NewAWSStaticPrincipalTypeProvider(
+		&infrav1.AWSClusterStaticIdentity{ObjectMeta: metav1.ObjectMeta{Name: "static"}}, &corev1.Secret{})
+	role := identity.NewAWSRolePrincipalTypeProvider(
+		&infrav1.AWSClusterRoleIdentity{ObjectMeta: metav1.ObjectMeta{Name: "role"}}, &source, klogr.New())
+
+	g.Expect(sessionIdentities(&infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: "role"}, []identity.AWSPrincipalTypeProvider{role})).To(ConsistOf(
+		infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: "role"},
+		infrav1.AWSIdentityReference{Kind: infrav1.ClusterStaticIdentityKind, Name: "static"},
+	))
+	g.Expect(sessionIdentities(&infrav1.AWSIdentityReference{Kind: infrav1.ControllerIdentityKind, Name: infrav1.AWSClusterControllerIdentityName}, nil)).To(ConsistOf(
+		infrav1.AWSIdentityReference{Kind: infrav1.ControllerIdentityKind, Name: infrav1.AWSClusterControllerIdentityName},
+	))
+}
+
+func TestSessionThrottleKey(t *testing.T) {
+	g := NewWithT(t)
+
+	role := func(name, arn string) identity.AWSPrincipalTypeProvider {
+		return identity.NewAWSRolePrincipalTypeProvider(&infrav1.AWSClusterRoleIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: infrav1.AWSClusterRoleIdentitySpec{
+				AWSRoleSpec: infrav1.AWSRoleSpec{RoleArn: arn},
+			},
+		}, nil, klogr.New())
+	}
+
+	// Roles of the same account share a key.
+	g.Expect(sessionThrottleKey("us-east-1", &infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: "a"},
+		[]identity.AWSPrincipalTypeProvider{role("a", "arn:aws:iam::123456789012:role/a")})).To(Equal("us-east-1/123456789012"))
+	g.Expect(sessionThrottleKey("us-east-1", &infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: "b"},
+		[]identity.AWSPrincipalTypeProvider{role("b", "arn:aws:iam::123456789012:role/b")})).To(Equal("us-east-1/123456789012"))
+
+	g.Expect(sessionThrottleKey("us-east-1", &infrav1.AWSIdentityReference{Kind: infrav1.ClusterStaticIdentityKind, Name: "static"}, nil)).
+		To(Equal("us-east-1/AWSClusterStaticIdentity/static"))
+	g.Expect(sessionThrottleKey("us-east-1", nil, nil)).To(Equal("us-east-1/AWSClusterControllerIdentity"))
+	g.Expect(sessionThrottleKey("us-east-1", &infrav1.AWSIdentityReference{Kind: infrav1.ControllerIdentityKind, Name: infrav1.AWSClusterControllerIdentityName}, nil)).
+		To(Equal("us-east-1/AWSClusterControllerIdentity"))
+}
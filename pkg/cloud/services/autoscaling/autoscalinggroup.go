@@ -23,6 +23,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
@@ -74,6 +75,16 @@ func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*expinfrav1.AutoS
 		i.Status = expinfrav1.ASGStatus(*v.Status)
 	}
 
+	if zoneIdentifier := aws.StringValue(v.VPCZoneIdentifier); zoneIdentifier != "" {
+		for _, subnet := range strings.Split(zoneIdentifier, ",") {
+			i.Subnets = append(i.Subnets, strings.TrimSpace(subnet))
+		}
+	}
+
+	if len(v.TargetGroupARNs) > 0 {
+		i.TargetGroupARNs = aws.StringValueSlice(v.TargetGroupARNs)
+	}
+
 	if len(v.Tags) > 0 {
 		i.Tags = converters.ASGTagsToMap(v.Tags)
 	}
@@ -85,6 +96,9 @@ func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*expinfrav1.AutoS
 				State: infrav1.InstanceState(*autoscalingInstance.LifecycleState),
 			}
 			i.Instances = append(i.Instances, *tmp)
+			if aws.BoolValue(autoscalingInstance.ProtectedFromScaleIn) {
+				i.ProtectedInstances = append(i.ProtectedInstances, tmp.ID)
+			}
 		}
 	}
 
@@ -111,6 +125,8 @@ func (s *Service) ASGIfExists(name *string) (*expinfrav1.AutoScalingGroup, error
 	case err != nil:
 		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAutoScalingGroups", "failed to describe ASG %q: %v", *name, err)
 		return nil, errors.Wrapf(err, "failed to describe AutoScaling Group: %q", *name)
+	case len(out.AutoScalingGroups) == 0:
+		return nil, nil
 	}
 	//TODO: double check if you're handling nil vals
 	return s.SDKToAutoScalingGroup(out.AutoScalingGroups[0])
@@ -180,7 +196,7 @@ func (s *Service) CreateASG(scope *scope.MachinePoolScope) (*expinfrav1.AutoScal
 	})
 
 	s.scope.Info("Running instance")
-	if err := s.runPool(input, scope.AWSMachinePool.Status.LaunchTemplateID); err != nil {
+	if err := s.CreateASGWithLaunchTemplate(input, scope.AWSMachinePool.Status.LaunchTemplateID); err != nil {
 		// Only record the failure event if the error is not related to failed dependencies.
 		// This is to avoid spamming failure events since the machine will be requeued by the actuator.
 		// if !awserrors.IsFailedDependency(errors.Cause(err)) {
@@ -194,7 +210,9 @@ func (s *Service) CreateASG(scope *scope.MachinePoolScope) (*expinfrav1.AutoScal
 	return nil, nil
 }
 
-func (s *Service) runPool(i *expinfrav1.AutoScalingGroup, launchTemplateID string) error {
+// CreateASGWithLaunchTemplate creates an autoscaling group from its spec, launching the latest version of a launch
+// template unless the spec has a mixed instances policy.
+func (s *Service) CreateASGWithLaunchTemplate(i *expinfrav1.AutoScalingGroup, launchTemplateID string) error {
 	input := &autoscaling.CreateAutoScalingGroupInput{
 		AutoScalingGroupName: aws.String(i.Name),
 		MaxSize:              aws.Int64(int64(i.MaxSize)),
@@ -221,6 +239,10 @@ func (s *Service) runPool(i *expinfrav1.AutoScalingGroup, launchTemplateID strin
 		input.Tags = BuildTagsFromMap(i.Name, i.Tags)
 	}
 
+	if len(i.TargetGroupARNs) > 0 {
+		input.TargetGroupARNs = aws.StringSlice(i.TargetGroupARNs)
+	}
+
 	if _, err := s.ASGClient.CreateAutoScalingGroup(input); err != nil {
 		return errors.Wrap(err, "failed to create autoscaling group")
 	}
@@ -264,6 +286,89 @@ func (s *Service) DeleteASG(name string) error {
 	return nil
 }
 
+// ResizeASG sets the minimum, maximum and desired capacity and the subnets of an ASG to those of its spec.
+func (s *Service) ResizeASG(i *expinfrav1.AutoScalingGroup) error {
+	input := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(i.Name),
+		MinSize:              aws.Int64(int64(i.MinSize)),
+		MaxSize:              aws.Int64(int64(i.MaxSize)),
+		VPCZoneIdentifier:    aws.String(strings.Join(i.Subnets, ", ")),
+	}
+
+	if i.DesiredCapacity != nil {
+		input.DesiredCapacity = aws.Int64(int64(aws.Int32Value(i.DesiredCapacity)))
+	}
+
+	if _, err := s.ASGClient.UpdateAutoScalingGroup(input); err != nil {
+		return errors.Wrapf(err, "failed to resize ASG %q", i.Name)
+	}
+
+	s.scope.V(2).Info("Resized ASG", "name", i.Name, "min-size", i.MinSize, "max-size", i.MaxSize)
+	return nil
+}
+
+// AttachTargetGroups registers the instances of an ASG with load balancer target groups.
+func (s *Service) AttachTargetGroups(name string, targetGroupARNs []string) error {
+	input := &autoscaling.AttachLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String(name),
+		TargetGroupARNs:      aws.StringSlice(targetGroupARNs),
+	}
+
+	if _, err := s.ASGClient.AttachLoadBalancerTargetGroups(input); err != nil {
+		return errors.Wrapf(err, "failed to attach target groups %v to ASG %q", targetGroupARNs, name)
+	}
+
+	s.scope.V(2).Info("Attached target groups to ASG", "name", name, "target-groups", targetGroupARNs)
+	return nil
+}
+
+// DetachTargetGroups deregisters the instances of an ASG from load balancer target groups.
+func (s *Service) DetachTargetGroups(name string, targetGroupARNs []string) error {
+	input := &autoscaling.DetachLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String(name),
+		TargetGroupARNs:      aws.StringSlice(targetGroupARNs),
+	}
+
+	if _, err := s.ASGClient.DetachLoadBalancerTargetGroups(input); err != nil {
+		return errors.Wrapf(err, "failed to detach target groups %v from ASG %q", targetGroupARNs, name)
+	}
+
+	s.scope.V(2).Info("Detached target groups from ASG", "name", name, "target-groups", targetGroupARNs)
+	return nil
+}
+
+// SetInstanceProtection protects instances of an ASG from scale-in, or removes their protection.
+func (s *Service) SetInstanceProtection(name string, instanceIDs []string, protected bool) error {
+	input := &autoscaling.SetInstanceProtectionInput{
+		AutoScalingGroupName: aws.String(name),
+		InstanceIds:          aws.StringSlice(instanceIDs),
+		ProtectedFromScaleIn: aws.Bool(protected),
+	}
+
+	if _, err := s.ASGClient.SetInstanceProtection(input); err != nil {
+		return errors.Wrapf(err, "failed to set scale-in protection of instances %v of ASG %q", instanceIDs, name)
+	}
+
+	s.scope.V(2).Info("Set scale-in protection of instances", "name", name, "instances", instanceIDs, "protected", protected)
+	return nil
+}
+
+// TerminateInstanceInASG terminates an instance of an ASG. When decrementCapacity is set, the desired capacity of the
+// ASG is decremented, otherwise the ASG launches an instance to replace it.
+func (s *Service) TerminateInstanceInASG(instanceID string, decrementCapacity bool) error {
+	input := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(instanceID),
+		ShouldDecrementDesiredCapacity: aws.Bool(decrementCapacity),
+	}
+
+	if _, err := s.ASGClient.TerminateInstanceInAutoScalingGroup(input); err != nil {
+		return errors.Wrapf(err, "failed to terminate instance %q", instanceID)
+	}
+
+	s.scope.V(2).Info("Terminated instance of ASG", "instance-id", instanceID, "decrement-capacity", decrementCapacity)
+	return nil
+}
+
 // UpdateASG will update the ASG of a service.
 func (s *Service) UpdateASG(scope *scope.MachinePoolScope) error {
 	subnetIDs := make([]string, len(scope.AWSMachinePool.Spec.Subnets))
@@ -287,6 +392,17 @@ func (s *Service) UpdateASG(scope *scope.MachinePoolScope) error {
 
 	if scope.MachinePool.Spec.Replicas != nil {
 		input.DesiredCapacity = aws.Int64(int64(*scope.MachinePool.Spec.Replicas))
+
+		// Keep the surge capacity until the instance refresh that launched it finishes.
+		if surge := refreshSurge(scope); surge > 0 {
+			canStart, err := s.CanStartASGInstanceRefresh(scope)
+			if err != nil {
+				return errors.Wrapf(err, "failed to describe instance refreshes of ASG %q", scope.Name())
+			}
+			if !canStart {
+				setSurgeCapacity(input, scope, surge)
+			}
+		}
 	}
 
 	if scope.AWSMachinePool.Spec.MixedInstancesPolicy != nil {
@@ -331,7 +447,9 @@ func (s *Service) CanStartASGInstanceRefresh(scope *scope.MachinePoolScope) (boo
 // StartASGInstanceRefresh will start an ASG instance with refresh.
 func (s *Service) StartASGInstanceRefresh(scope *scope.MachinePoolScope) error {
 	strategy := pointer.StringPtr(autoscaling.RefreshStrategyRolling)
-	var minHealthyPercentage, instanceWarmup *int64
+	var minHealthyPercentage, instanceWarmup, checkpointDelay *int64
+	var checkpointPercentages []*int64
+	surge := refreshSurge(scope)
 	if scope.AWSMachinePool.Spec.RefreshPreferences != nil {
 		if scope.AWSMachinePool.Spec.RefreshPreferences.Strategy != nil {
 			strategy = scope.AWSMachinePool.Spec.RefreshPreferences.Strategy
@@ -341,6 +459,24 @@ func (s *Service) StartASGInstanceRefresh(scope *scope.MachinePoolScope) error {
 		}
 		if scope.AWSMachinePool.Spec.RefreshPreferences.MinHealthyPercentage != nil {
 			minHealthyPercentage = scope.AWSMachinePool.Spec.RefreshPreferences.MinHealthyPercentage
+		} else {
+			minHealthyPercentage = refreshMinHealthyPercentage(scope, surge)
+		}
+		if len(scope.AWSMachinePool.Spec.RefreshPreferences.CheckpointPercentages) > 0 {
+			checkpointPercentages = aws.Int64Slice(scope.AWSMachinePool.Spec.RefreshPreferences.CheckpointPercentages)
+		}
+		checkpointDelay = scope.AWSMachinePool.Spec.RefreshPreferences.CheckpointDelay
+	}
+
+	// Launch the surge capacity before the refresh starts, so the first replacements already
+	// happen with the surge instances in service.
+	if surge > 0 {
+		input := &autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(scope.Name()),
+		}
+		setSurgeCapacity(input, scope, surge)
+		if _, err := s.ASGClient.UpdateAutoScalingGroup(input); err != nil {
+			return errors.Wrapf(err, "failed to add surge capacity to ASG %q", scope.Name())
 		}
 	}
 
@@ -348,8 +484,10 @@ func (s *Service) StartASGInstanceRefresh(scope *scope.MachinePoolScope) error {
 		AutoScalingGroupName: aws.String(scope.Name()),
 		Strategy:             strategy,
 		Preferences: &autoscaling.RefreshPreferences{
-			InstanceWarmup:       instanceWarmup,
-			MinHealthyPercentage: minHealthyPercentage,
+			InstanceWarmup:        instanceWarmup,
+			MinHealthyPercentage:  minHealthyPercentage,
+			CheckpointPercentages: checkpointPercentages,
+			CheckpointDelay:       checkpointDelay,
 		},
 	}
 
@@ -360,6 +498,61 @@ func (s *Service) StartASGInstanceRefresh(scope *scope.MachinePoolScope) error {
 	return nil
 }
 
+// refreshSurge returns the number of instances to launch above the desired capacity of the machine pool
+// during an instance refresh.
+func refreshSurge(scope *scope.MachinePoolScope) int {
+	prefs := scope.AWSMachinePool.Spec.RefreshPreferences
+	if prefs == nil || prefs.MaxSurge == nil || scope.MachinePool.Spec.Replicas == nil {
+		return 0
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(prefs.MaxSurge, int(*scope.MachinePool.Spec.Replicas), true)
+	if err != nil || surge < 0 {
+		return 0
+	}
+	return surge
+}
+
+// refreshMinHealthyPercentage translates MaxUnavailable into the percentage of the ASG capacity, including
+// the surge instances, that must remain healthy during an instance refresh. It returns nil to use the
+// default of the ASG when neither MaxUnavailable nor MaxSurge is set.
+func refreshMinHealthyPercentage(scope *scope.MachinePoolScope, surge int) *int64 {
+	prefs := scope.AWSMachinePool.Spec.RefreshPreferences
+	if prefs == nil || (prefs.MaxUnavailable == nil && surge == 0) || scope.MachinePool.Spec.Replicas == nil {
+		return nil
+	}
+	replicas := int(*scope.MachinePool.Spec.Replicas)
+	if replicas == 0 {
+		return nil
+	}
+
+	unavailable := 0
+	if prefs.MaxUnavailable != nil {
+		v, err := intstr.GetScaledValueFromIntOrPercent(prefs.MaxUnavailable, replicas, false)
+		if err != nil {
+			return nil
+		}
+		unavailable = v
+	}
+	healthy := replicas - unavailable
+	if healthy < 0 {
+		healthy = 0
+	}
+
+	// Round up, so that at least replicas-unavailable instances stay healthy.
+	capacity := replicas + surge
+	percentage := (100*healthy + capacity - 1) / capacity
+	return aws.Int64(int64(percentage))
+}
+
+// setSurgeCapacity raises the desired capacity of the ASG by surge, and the maximum size along with it if needed.
+func setSurgeCapacity(input *autoscaling.UpdateAutoScalingGroupInput, scope *scope.MachinePoolScope, surge int) {
+	desired := int64(*scope.MachinePool.Spec.Replicas) + int64(surge)
+	input.DesiredCapacity = aws.Int64(desired)
+	if int64(scope.AWSMachinePool.Spec.MaxSize) < desired {
+		input.MaxSize = aws.Int64(desired)
+	}
+}
+
 func createSDKMixedInstancesPolicy(name string, i *expinfrav1.MixedInstancesPolicy) *autoscaling.MixedInstancesPolicy {
 	mixedInstancesPolicy := &autoscaling.MixedInstancesPolicy{
 		LaunchTemplate: &autoscaling.LaunchTemplate{
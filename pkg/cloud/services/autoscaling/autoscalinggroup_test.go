@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling/mock_autoscalingiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 )
 
 func TestService_GetASGByName(t *testing.T) {
@@ -205,6 +207,28 @@ func TestService_SDKToAutoScalingGroup(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid input - with subnets and target groups",
+			input: &autoscaling.Group{
+				AutoScalingGroupARN:  aws.String("test-id"),
+				AutoScalingGroupName: aws.String("test-name"),
+				DesiredCapacity:      aws.Int64(1),
+				MaxSize:              aws.Int64(1),
+				MinSize:              aws.Int64(1),
+				VPCZoneIdentifier:    aws.String("subnet-1, subnet-2"),
+				TargetGroupARNs:      aws.StringSlice([]string{"tg-arn"}),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				ID:              "test-id",
+				Name:            "test-name",
+				DesiredCapacity: aws.Int32(1),
+				MaxSize:         int32(1),
+				MinSize:         int32(1),
+				Subnets:         []string{"subnet-1", "subnet-2"},
+				TargetGroupARNs: []string{"tg-arn"},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -220,3 +244,111 @@ func TestService_SDKToAutoScalingGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestService_StartASGInstanceRefresh(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	surge := intstr.FromString("20%")
+	unavailable := intstr.FromInt(1)
+
+	tests := []struct {
+		name   string
+		prefs  *expinfrav1.RefreshPreferences
+		expect func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+	}{
+		{
+			name: "uses the default preferences of the ASG",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.StartInstanceRefresh(gomock.Eq(&autoscaling.StartInstanceRefreshInput{
+					AutoScalingGroupName: aws.String("pool"),
+					Strategy:             aws.String(autoscaling.RefreshStrategyRolling),
+					Preferences:          &autoscaling.RefreshPreferences{},
+				})).Return(&autoscaling.StartInstanceRefreshOutput{}, nil)
+			},
+		},
+		{
+			name: "launches the surge capacity and keeps the replicas minus maxUnavailable healthy",
+			prefs: &expinfrav1.RefreshPreferences{
+				MaxSurge:              &surge,
+				MaxUnavailable:        &unavailable,
+				CheckpointPercentages: []int64{50, 100},
+				CheckpointDelay:       aws.Int64(300),
+			},
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.UpdateAutoScalingGroup(gomock.Eq(&autoscaling.UpdateAutoScalingGroupInput{
+					AutoScalingGroupName: aws.String("pool"),
+					DesiredCapacity:      aws.Int64(12),
+					MaxSize:              aws.Int64(12),
+				})).Return(&autoscaling.UpdateAutoScalingGroupOutput{}, nil)
+				m.StartInstanceRefresh(gomock.Eq(&autoscaling.StartInstanceRefreshInput{
+					AutoScalingGroupName: aws.String("pool"),
+					Strategy:             aws.String(autoscaling.RefreshStrategyRolling),
+					Preferences: &autoscaling.RefreshPreferences{
+						MinHealthyPercentage:  aws.Int64(75),
+						CheckpointPercentages: aws.Int64Slice([]int64{50, 100}),
+						CheckpointDelay:       aws.Int64(300),
+					},
+				})).Return(&autoscaling.StartInstanceRefreshOutput{}, nil)
+			},
+		},
+		{
+			name:  "keeps all replicas healthy when only maxSurge is set",
+			prefs: &expinfrav1.RefreshPreferences{MaxSurge: &surge},
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.UpdateAutoScalingGroup(gomock.Eq(&autoscaling.UpdateAutoScalingGroupInput{
+					AutoScalingGroupName: aws.String("pool"),
+					DesiredCapacity:      aws.Int64(12),
+					MaxSize:              aws.Int64(12),
+				})).Return(&autoscaling.UpdateAutoScalingGroupOutput{}, nil)
+				m.StartInstanceRefresh(gomock.Eq(&autoscaling.StartInstanceRefreshInput{
+					AutoScalingGroupName: aws.String("pool"),
+					Strategy:             aws.String(autoscaling.RefreshStrategyRolling),
+					Preferences: &autoscaling.RefreshPreferences{
+						MinHealthyPercentage: aws.Int64(84),
+					},
+				})).Return(&autoscaling.StartInstanceRefreshOutput{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tt.expect(asgMock.EXPECT())
+			s := NewService(cs)
+			s.ASGClient = asgMock
+
+			replicas := int32(10)
+			mps := &scope.MachinePoolScope{
+				AWSMachinePool: &expinfrav1.AWSMachinePool{
+					Spec: expinfrav1.AWSMachinePoolSpec{
+						MaxSize:            10,
+						RefreshPreferences: tt.prefs,
+					},
+				},
+				MachinePool: &capiv1exp.MachinePool{
+					Spec: capiv1exp.MachinePoolSpec{Replicas: &replicas},
+				},
+			}
+			mps.AWSMachinePool.Name = "pool"
+
+			if err := s.StartASGInstanceRefresh(mps); err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+const (
+	// NodeDrainLifecycleHookName is the name of the lifecycle hook that holds the instances the ASG terminates
+	// until their node is drained.
+	NodeDrainLifecycleHookName = "cluster-api-provider-aws-node-drain"
+
+	terminatingTransition   = "autoscaling:EC2_INSTANCE_TERMINATING"
+	lifecycleResultContinue = "CONTINUE"
+)
+
+// ReconcileLifecycleHook ensures the ASG of the machine pool has the node drain lifecycle hook with the given
+// timeout, or does not have it when the timeout is nil.
+func (s *Service) ReconcileLifecycleHook(scope *scope.MachinePoolScope, drainTimeout *metav1.Duration) error {
+	out, err := s.ASGClient.DescribeLifecycleHooks(&autoscaling.DescribeLifecycleHooksInput{
+		AutoScalingGroupName: aws.String(scope.Name()),
+		LifecycleHookNames:   aws.StringSlice([]string{NodeDrainLifecycleHookName}),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe lifecycle hooks of ASG %q", scope.Name())
+	}
+	var existing *autoscaling.LifecycleHook
+	if len(out.LifecycleHooks) > 0 {
+		existing = out.LifecycleHooks[0]
+	}
+
+	if drainTimeout == nil {
+		if existing == nil {
+			return nil
+		}
+		if _, err := s.ASGClient.DeleteLifecycleHook(&autoscaling.DeleteLifecycleHookInput{
+			AutoScalingGroupName: aws.String(scope.Name()),
+			LifecycleHookName:    aws.String(NodeDrainLifecycleHookName),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete lifecycle hook of ASG %q", scope.Name())
+		}
+		return nil
+	}
+
+	heartbeatTimeout := int64(drainTimeout.Duration.Seconds())
+	if existing != nil &&
+		aws.Int64Value(existing.HeartbeatTimeout) == heartbeatTimeout &&
+		aws.StringValue(existing.DefaultResult) == lifecycleResultContinue &&
+		aws.StringValue(existing.LifecycleTransition) == terminatingTransition {
+		return nil
+	}
+
+	// Terminations continue once the timeout expires, so instances are never held when the drain does not finish.
+	if _, err := s.ASGClient.PutLifecycleHook(&autoscaling.PutLifecycleHookInput{
+		AutoScalingGroupName: aws.String(scope.Name()),
+		LifecycleHookName:    aws.String(NodeDrainLifecycleHookName),
+		LifecycleTransition:  aws.String(terminatingTransition),
+		HeartbeatTimeout:     aws.Int64(heartbeatTimeout),
+		DefaultResult:        aws.String(lifecycleResultContinue),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to put lifecycle hook of ASG %q", scope.Name())
+	}
+	return nil
+}
+
+// CompleteLifecycleAction lets the ASG continue terminating an instance held by a lifecycle hook.
+func (s *Service) CompleteLifecycleAction(asgName, hookName, instanceID, token string) error {
+	if _, err := s.ASGClient.CompleteLifecycleAction(&autoscaling.CompleteLifecycleActionInput{
+		AutoScalingGroupName:  aws.String(asgName),
+		LifecycleHookName:     aws.String(hookName),
+		InstanceId:            aws.String(instanceID),
+		LifecycleActionToken:  aws.String(token),
+		LifecycleActionResult: aws.String(lifecycleResultContinue),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to complete lifecycle action of instance %q in ASG %q", instanceID, asgName)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package computeoptimizer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/computeoptimizer"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
+)
+
+// InstanceRecommendation returns the Compute Optimizer recommendation for an instance of the cluster. The finding of
+// the recommendation is empty when Compute Optimizer has not classified the instance yet, which takes at least 30 hours
+// of metrics.
+func (s *Service) InstanceRecommendation(instanceID string) (*infrav1.RightSizingRecommendation, error) {
+	identity, err := s.STSClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting account ID")
+	}
+	instanceARN := awsarn.EC2Instance(awsarn.Partition(s.scope.Region()), s.scope.Region(), aws.StringValue(identity.Account), instanceID)
+
+	out, err := s.ComputeOptimizerClient.GetEC2InstanceRecommendations(&computeoptimizer.GetEC2InstanceRecommendationsInput{
+		InstanceArns: aws.StringSlice([]string{instanceARN}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the recommendations for instance %q", instanceID)
+	}
+
+	recommendation := &infrav1.RightSizingRecommendation{LastCheckTime: metav1.Now()}
+	for _, r := range out.InstanceRecommendations {
+		if aws.StringValue(r.InstanceArn) != instanceARN {
+			continue
+		}
+		recommendation.Finding = rightSizingFinding(aws.StringValue(r.Finding))
+		recommendation.RecommendedInstanceTypes = recommendedInstanceTypes(r.RecommendationOptions)
+	}
+	return recommendation, nil
+}
+
+// rightSizingFinding maps a finding of Compute Optimizer to a RightSizingFinding. The API documents both the
+// Underprovisioned and UNDER_PROVISIONED spellings.
+func rightSizingFinding(finding string) infrav1.RightSizingFinding {
+	switch strings.ReplaceAll(strings.ToLower(finding), "_", "") {
+	case "underprovisioned":
+		return infrav1.RightSizingFindingUnderprovisioned
+	case "overprovisioned":
+		return infrav1.RightSizingFindingOverprovisioned
+	case "optimized":
+		return infrav1.RightSizingFindingOptimized
+	default:
+		return infrav1.RightSizingFinding(finding)
+	}
+}
+
+// recommendedInstanceTypes returns the instance types of the recommendation options, best ranked first.
+func recommendedInstanceTypes(options []*computeoptimizer.InstanceRecommendationOption) []string {
+	sorted := make([]*computeoptimizer.InstanceRecommendationOption, len(options))
+	copy(sorted, options)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return aws.Int64Value(sorted[i].Rank) < aws.Int64Value(sorted[j].Rank)
+	})
+
+	instanceTypes := make([]string, 0, len(sorted))
+	for _, option := range sorted {
+		if instanceType := aws.StringValue(option.InstanceType); instanceType != "" {
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+	return instanceTypes
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package computeoptimizer
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/computeoptimizer"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+func TestRightSizingFinding(t *testing.T) {
+	testCases := []struct {
+		finding string
+		want    infrav1.RightSizingFinding
+	}{
+		{finding: "Underprovisioned", want: infrav1.RightSizingFindingUnderprovisioned},
+		{finding: "UNDER_PROVISIONED", want: infrav1.RightSizingFindingUnderprovisioned},
+		{finding: "Overprovisioned", want: infrav1.RightSizingFindingOverprovisioned},
+		{finding: "OVER_PROVISIONED", want: infrav1.RightSizingFindingOverprovisioned},
+		{finding: "OPTIMIZED", want: infrav1.RightSizingFindingOptimized},
+		{finding: "NotOptimized", want: infrav1.RightSizingFinding("NotOptimized")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.finding, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(rightSizingFinding(tc.finding)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestRecommendedInstanceTypes(t *testing.T) {
+	g := NewWithT(t)
+
+	options := []*computeoptimizer.InstanceRecommendationOption{
+		{InstanceType: aws.String("t3.large"), Rank: aws.Int64(3)},
+		{InstanceType: aws.String("m5.large"), Rank: aws.Int64(1)},
+		{InstanceType: aws.String("m6g.large"), Rank: aws.Int64(2)},
+	}
+	g.Expect(recommendedInstanceTypes(options)).To(Equal([]string{"m5.large", "m6g.large", "t3.large"}))
+	g.Expect(aws.StringValue(options[0].InstanceType)).To(Equal("t3.large"))
+	g.Expect(recommendedInstanceTypes(nil)).To(BeEmpty())
+}
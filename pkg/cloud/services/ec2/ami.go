@@ -60,8 +60,31 @@ const (
 
 	// EKS GPU AMI ID SSM Parameter name.
 	eksGPUAmiSSMParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id"
+
+	// EKS Amazon Linux 2023 AMI ID SSM Parameter name.
+	eksAL2023AmiSSMParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id"
+
+	// EKS Amazon Linux 2023 NVIDIA AMI ID SSM Parameter name.
+	eksAL2023GPUAmiSSMParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/nvidia/recommended/image_id"
+
+	// Bottlerocket AMI ID SSM Parameter name.
+	bottlerocketAmiSSMParameterFormat = "/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_id"
+
+	// Bottlerocket NVIDIA AMI ID SSM Parameter name.
+	bottlerocketGPUAmiSSMParameterFormat = "/aws/service/bottlerocket/aws-k8s-%s-nvidia/x86_64/latest/image_id"
+
+	// Canonical's Ubuntu EKS AMI ID SSM Parameter name.
+	eksUbuntuAmiSSMParameterFormat = "/aws/service/canonical/ubuntu/eks/20.04/%s/stable/current/amd64/hvm/ebs-gp2/ami-id"
 )
 
+// osFamilyBaseOS are the base operating systems of the images built by image-builder for the OS families.
+// Bottlerocket images are not built by image-builder.
+var osFamilyBaseOS = map[v1alpha4.OSFamily]string{
+	v1alpha4.OSFamilyAmazonLinux2:    "amazon-2",
+	v1alpha4.OSFamilyAmazonLinux2023: "amazon-2023",
+	v1alpha4.OSFamilyUbuntu:          defaultMachineAMILookupBaseOS,
+}
+
 // AMILookup contains the parameters used to template AMI names used for lookup.
 type AMILookup struct {
 	BaseOS     string
@@ -229,25 +252,83 @@ func (s *Service) defaultBastionAMILookup(region string) string {
 	}
 }
 
-func (s *Service) eksAMILookup(kubernetesVersion string, amiType *v1alpha4.EKSAMILookupType) (string, error) {
-	// format ssm parameter path properly
-	formattedVersion, err := formatVersionForEKS(kubernetesVersion)
-	if err != nil {
-		return "", err
+// neuronInstanceFamilies are the instance families with AWS Inferentia or
+// Trainium accelerators, which are driven by the Neuron SDK.
+var neuronInstanceFamilies = []string{"inf1", "inf2", "trn1", "trn1n"}
+
+// isNeuronInstanceType returns whether the instance type has Neuron accelerators.
+func isNeuronInstanceType(instanceType string) bool {
+	family := strings.SplitN(instanceType, ".", 2)[0]
+	for _, f := range neuronInstanceFamilies {
+		if family == f {
+			return true
+		}
 	}
+	return false
+}
 
-	var paramName string
+// eksAMILookupType returns the EKS optimized AMI variant to look up. GPU and
+// Neuron instances default to the accelerated variant, which ships the NVIDIA
+// and Neuron drivers, unless a lookup type is set explicitly.
+func eksAMILookupType(lookupType *v1alpha4.EKSAMILookupType, gpu v1alpha4.GPUVendor, instanceType string) *v1alpha4.EKSAMILookupType {
+	if lookupType != nil || (gpu == "" && !isNeuronInstanceType(instanceType)) {
+		return lookupType
+	}
+	gpuLookupType := v1alpha4.AmazonLinuxGPU
+	return &gpuLookupType
+}
 
-	if amiType == nil {
-		amiType = new(v1alpha4.EKSAMILookupType)
+// useSSMAMILookup returns whether the AMI of a machine is looked up in the SSM parameters published by AWS rather
+// than by name. Bottlerocket images are only published in SSM; the EKS optimized images are used for EKS clusters
+// unless a name lookup is configured.
+func useSSMAMILookup(eksManaged bool, osFamily v1alpha4.OSFamily, imageLookupFormat, imageLookupOrg, imageLookupBaseOS string) bool {
+	if osFamily == v1alpha4.OSFamilyBottlerocket {
+		return true
 	}
+	return eksManaged && imageLookupFormat == "" && imageLookupOrg == "" && imageLookupBaseOS == ""
+}
+
+// amiLookupBaseOS returns the base OS of the image looked up by name: the one of the OS family when it is set,
+// otherwise the one configured on the machine or cluster.
+func amiLookupBaseOS(osFamily v1alpha4.OSFamily, imageLookupBaseOS string) string {
+	if baseOS, ok := osFamilyBaseOS[osFamily]; ok {
+		return baseOS
+	}
+	return imageLookupBaseOS
+}
 
-	switch *amiType {
-	case v1alpha4.AmazonLinuxGPU:
-		paramName = fmt.Sprintf(eksGPUAmiSSMParameterFormat, formattedVersion)
+// eksAMISSMParameter returns the name of the SSM parameter holding the ID of the latest image of the OS family for
+// the Kubernetes version. The accelerated variant ships the NVIDIA drivers; Ubuntu has none.
+func eksAMISSMParameter(osFamily v1alpha4.OSFamily, formattedVersion string, accelerated bool) string {
+	switch osFamily {
+	case v1alpha4.OSFamilyAmazonLinux2023:
+		if accelerated {
+			return fmt.Sprintf(eksAL2023GPUAmiSSMParameterFormat, formattedVersion)
+		}
+		return fmt.Sprintf(eksAL2023AmiSSMParameterFormat, formattedVersion)
+	case v1alpha4.OSFamilyBottlerocket:
+		if accelerated {
+			return fmt.Sprintf(bottlerocketGPUAmiSSMParameterFormat, formattedVersion)
+		}
+		return fmt.Sprintf(bottlerocketAmiSSMParameterFormat, formattedVersion)
+	case v1alpha4.OSFamilyUbuntu:
+		return fmt.Sprintf(eksUbuntuAmiSSMParameterFormat, formattedVersion)
 	default:
-		paramName = fmt.Sprintf(eksAmiSSMParameterFormat, formattedVersion)
+		if accelerated {
+			return fmt.Sprintf(eksGPUAmiSSMParameterFormat, formattedVersion)
+		}
+		return fmt.Sprintf(eksAmiSSMParameterFormat, formattedVersion)
 	}
+}
+
+func (s *Service) eksAMILookup(kubernetesVersion string, osFamily v1alpha4.OSFamily, amiType *v1alpha4.EKSAMILookupType) (string, error) {
+	// format ssm parameter path properly
+	formattedVersion, err := formatVersionForEKS(kubernetesVersion)
+	if err != nil {
+		return "", err
+	}
+
+	paramName := eksAMISSMParameter(osFamily, formattedVersion, amiType != nil && *amiType == v1alpha4.AmazonLinuxGPU)
 
 	input := &ssm.GetParameterInput{
 		Name: aws.String(paramName),
@@ -158,3 +158,120 @@ func setupCluster(clusterName string) (*scope.ClusterScope, error) {
 		Client:     client,
 	})
 }
+
+func TestEKSAMILookupType(t *testing.T) {
+	amazonLinux := infrav1.AmazonLinux
+	amazonLinuxGPU := infrav1.AmazonLinuxGPU
+
+	tests := []struct {
+		name         string
+		lookupType   *infrav1.EKSAMILookupType
+		gpu          infrav1.GPUVendor
+		instanceType string
+		expected     *infrav1.EKSAMILookupType
+	}{
+		{
+			name:     "defaults to the standard variant without GPUs",
+			expected: nil,
+		},
+		{
+			name:     "defaults to the GPU variant with GPUs",
+			gpu:      infrav1.GPUVendorNvidia,
+			expected: &amazonLinuxGPU,
+		},
+		{
+			name:         "defaults to the GPU variant for Trainium instances",
+			instanceType: "trn1.32xlarge",
+			expected:     &amazonLinuxGPU,
+		},
+		{
+			name:         "defaults to the GPU variant for Inferentia instances",
+			instanceType: "inf1.xlarge",
+			expected:     &amazonLinuxGPU,
+		},
+		{
+			name:         "defaults to the standard variant for other instance types",
+			instanceType: "m5.large",
+			expected:     nil,
+		},
+		{
+			name:       "keeps an explicit lookup type",
+			lookupType: &amazonLinux,
+			gpu:        infrav1.GPUVendorNvidia,
+			expected:   &amazonLinux,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(eksAMILookupType(tc.lookupType, tc.gpu, tc.instanceType)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestEKSAMISSMParameter(t *testing.T) {
+	tests := []struct {
+		name        string
+		osFamily    infrav1.OSFamily
+		accelerated bool
+		expected    string
+	}{
+		{
+			name:     "defaults to Amazon Linux 2",
+			expected: "/aws/service/eks/optimized-ami/1.21/amazon-linux-2/recommended/image_id",
+		},
+		{
+			name:        "Amazon Linux 2 GPU",
+			osFamily:    infrav1.OSFamilyAmazonLinux2,
+			accelerated: true,
+			expected:    "/aws/service/eks/optimized-ami/1.21/amazon-linux-2-gpu/recommended/image_id",
+		},
+		{
+			name:     "Amazon Linux 2023",
+			osFamily: infrav1.OSFamilyAmazonLinux2023,
+			expected: "/aws/service/eks/optimized-ami/1.21/amazon-linux-2023/x86_64/standard/recommended/image_id",
+		},
+		{
+			name:        "Amazon Linux 2023 NVIDIA",
+			osFamily:    infrav1.OSFamilyAmazonLinux2023,
+			accelerated: true,
+			expected:    "/aws/service/eks/optimized-ami/1.21/amazon-linux-2023/x86_64/nvidia/recommended/image_id",
+		},
+		{
+			name:     "Bottlerocket",
+			osFamily: infrav1.OSFamilyBottlerocket,
+			expected: "/aws/service/bottlerocket/aws-k8s-1.21/x86_64/latest/image_id",
+		},
+		{
+			name:        "Bottlerocket NVIDIA",
+			osFamily:    infrav1.OSFamilyBottlerocket,
+			accelerated: true,
+			expected:    "/aws/service/bottlerocket/aws-k8s-1.21-nvidia/x86_64/latest/image_id",
+		},
+		{
+			name:        "Ubuntu has no accelerated variant",
+			osFamily:    infrav1.OSFamilyUbuntu,
+			accelerated: true,
+			expected:    "/aws/service/canonical/ubuntu/eks/20.04/1.21/stable/current/amd64/hvm/ebs-gp2/ami-id",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(eksAMISSMParameter(tc.osFamily, "1.21", tc.accelerated)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestUseSSMAMILookup(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(useSSMAMILookup(true, "", "", "", "")).To(BeTrue())
+	g.Expect(useSSMAMILookup(true, infrav1.OSFamilyAmazonLinux2023, "", "", "")).To(BeTrue())
+	g.Expect(useSSMAMILookup(true, "", "", "", "ubuntu-20.04")).To(BeFalse())
+	g.Expect(useSSMAMILookup(false, infrav1.OSFamilyUbuntu, "", "", "")).To(BeFalse())
+	g.Expect(useSSMAMILookup(false, infrav1.OSFamilyBottlerocket, "", "", "")).To(BeTrue())
+
+	g.Expect(amiLookupBaseOS(infrav1.OSFamilyAmazonLinux2, "ubuntu-20.04")).To(Equal("amazon-2"))
+	g.Expect(amiLookupBaseOS("", "ubuntu-20.04")).To(Equal("ubuntu-20.04"))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+)
+
+// Architectures looks up the processor architectures of instance types and AMIs. Neither
+// ever changes, so the lookups are cached for the lifetime of the process.
+type Architectures struct {
+	newClient func(region string) (ec2iface.EC2API, error)
+
+	mu            sync.Mutex
+	instanceTypes map[string][]string
+	images        map[string]string
+}
+
+// NewArchitectures returns an Architectures which creates its EC2 clients with newClient.
+func NewArchitectures(newClient func(region string) (ec2iface.EC2API, error)) *Architectures {
+	return &Architectures{
+		newClient:     newClient,
+		instanceTypes: map[string][]string{},
+		images:        map[string]string{},
+	}
+}
+
+// InstanceTypeArchitectures returns the architectures supported by the instance type in the region of the availability zone.
+func (a *Architectures) InstanceTypeArchitectures(instanceType, availabilityZone string) ([]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	region, err := regionOfZone(availabilityZone)
+	if err != nil {
+		return nil, err
+	}
+
+	key := region + "/" + instanceType
+	if architectures, ok := a.instanceTypes[key]; ok {
+		return architectures, nil
+	}
+
+	client, err := a.newClient(region)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create EC2 client for region %q", region)
+	}
+	out, err := client.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice([]string{instanceType}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+	if len(out.InstanceTypes) == 0 || out.InstanceTypes[0].ProcessorInfo == nil {
+		return nil, errors.Errorf("no processor information found for instance type %q", instanceType)
+	}
+
+	architectures := aws.StringValueSlice(out.InstanceTypes[0].ProcessorInfo.SupportedArchitectures)
+	a.instanceTypes[key] = architectures
+	return architectures, nil
+}
+
+// ImageArchitecture returns the architecture of the AMI in the region of the availability zone.
+func (a *Architectures) ImageArchitecture(imageID, availabilityZone string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	region, err := regionOfZone(availabilityZone)
+	if err != nil {
+		return "", err
+	}
+
+	key := region + "/" + imageID
+	if architecture, ok := a.images[key]; ok {
+		return architecture, nil
+	}
+
+	client, err := a.newClient(region)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create EC2 client for region %q", region)
+	}
+	out, err := client.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{imageID}),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe AMI %q", imageID)
+	}
+	if len(out.Images) == 0 {
+		return "", errors.Errorf("AMI %q not found", imageID)
+	}
+
+	architecture := aws.StringValue(out.Images[0].Architecture)
+	a.images[key] = architecture
+	return architecture, nil
+}
+
+// availabilityZoneRegex matches the region an availability zone, local zone or wavelength zone belongs to.
+var availabilityZoneRegex = regexp.MustCompile(`^([a-z]{2}(-gov|-iso|-isob)?-[a-z]+-[0-9]+)`)
+
+func regionOfZone(availabilityZone string) (string, error) {
+	match := availabilityZoneRegex.FindStringSubmatch(availabilityZone)
+	if match == nil {
+		return "", errors.Errorf("unable to determine the region of availability zone %q", availabilityZone)
+	}
+	return match[1], nil
+}
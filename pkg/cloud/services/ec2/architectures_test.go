@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/golang/mock/gomock"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+)
+
+func TestArchitectures(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	ec2Mock.EXPECT().DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice([]string{"m6g.large"}),
+	}).Return(&ec2.DescribeInstanceTypesOutput{
+		InstanceTypes: []*ec2.InstanceTypeInfo{
+			{ProcessorInfo: &ec2.ProcessorInfo{SupportedArchitectures: aws.StringSlice([]string{"arm64"})}},
+		},
+	}, nil).Times(1)
+	ec2Mock.EXPECT().DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{"ami-123"}),
+	}).Return(&ec2.DescribeImagesOutput{
+		Images: []*ec2.Image{{Architecture: aws.String("x86_64")}},
+	}, nil).Times(1)
+	ec2Mock.EXPECT().DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{"ami-missing"}),
+	}).Return(&ec2.DescribeImagesOutput{}, nil).Times(1)
+
+	var regions []string
+	architectures := NewArchitectures(func(region string) (ec2iface.EC2API, error) {
+		regions = append(regions, region)
+		return ec2Mock, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		instanceTypeArchitectures, err := architectures.InstanceTypeArchitectures("m6g.large", "eu-west-1a")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(instanceTypeArchitectures).To(Equal([]string{"arm64"}))
+
+		imageArchitecture, err := architectures.ImageArchitecture("ami-123", "eu-west-1a")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(imageArchitecture).To(Equal("x86_64"))
+	}
+
+	_, err := architectures.ImageArchitecture("ami-missing", "eu-west-1b")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = architectures.ImageArchitecture("ami-123", "not-a-zone")
+	g.Expect(err).To(HaveOccurred())
+
+	// Clients are only created for lookups which are not cached.
+	g.Expect(regions).To(Equal([]string{"eu-west-1", "eu-west-1", "eu-west-1"}))
+}
@@ -19,6 +19,7 @@ package ec2
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -28,7 +29,6 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
-	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/userdata"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
@@ -43,17 +43,11 @@ var (
 	fallbackBastionUsEast1InstanceType = "t2.micro"
 )
 
-// ReconcileBastion ensures a bastion is created for the cluster.
-func (s *Service) ReconcileBastion() error {
+// ReconcileBastion ensures a bastion is created for the cluster. It returns whether the auto scaling group of the
+// bastion hosts is still being deleted, in which case it should be called again.
+func (s *Service) ReconcileBastion() (requeue bool, err error) {
 	if !s.scope.Bastion().Enabled {
 		s.scope.V(4).Info("Skipping bastion reconcile")
-		_, err := s.describeBastionInstance()
-		if err != nil {
-			if awserrors.IsNotFound(err) {
-				return nil
-			}
-			return err
-		}
 		return s.DeleteBastion()
 	}
 
@@ -62,69 +56,165 @@ func (s *Service) ReconcileBastion() error {
 	subnets := s.scope.Subnets()
 	if len(subnets.FilterPrivate()) == 0 {
 		s.scope.V(2).Info("No private subnets available, skipping bastion host")
-		return nil
+		return false, nil
 	} else if len(subnets.FilterPublic()) == 0 {
-		return errors.New("failed to reconcile bastion host, no public subnets are available")
+		return false, errors.New("failed to reconcile bastion host, no public subnets are available")
+	}
+
+	// Describe bastion instances, if any.
+	instances, err := s.describeBastionInstances()
+	if err != nil {
+		return false, err
 	}
 
-	// Describe bastion instance, if any.
-	instance, err := s.describeBastionInstance()
-	if awserrors.IsNotFound(err) { // nolint:nestif
+	if s.scope.Bastion().AutoScaling != nil {
+		return false, s.reconcileBastionAutoScaling(instances)
+	}
+
+	// The auto scaling group is replaced by a single instance when the bastion no longer runs in one.
+	if s.bastionAutoScalingMayExist(instances) {
+		requeue, err := s.deleteBastionAutoScalingIfExists()
+		if err != nil || requeue {
+			return requeue, err
+		}
+	}
+
+	var instance *infrav1.Instance
+	if standalone := standaloneBastionInstances(instances); len(standalone) > 0 {
+		instance = standalone[0]
+	} else {
 		if !conditions.Has(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition) {
 			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, infrav1.BastionCreationStartedReason, clusterv1.ConditionSeverityInfo, "")
 			if err := s.scope.PatchObject(); err != nil {
-				return errors.Wrap(err, "failed to patch conditions")
+				return false, errors.Wrap(err, "failed to patch conditions")
 			}
 		}
 		instance, err = s.runInstance("bastion", s.getDefaultBastion(s.scope.Bastion().InstanceType, s.scope.Bastion().AMI))
 		if err != nil {
 			record.Warnf(s.scope.InfraCluster(), "FailedCreateBastion", "Failed to create bastion instance: %v", err)
-			return err
+			return false, err
 		}
 
 		record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateBastion", "Created bastion instance %q", instance.ID)
 		s.scope.V(2).Info("Created new bastion host", "instance", instance)
-	} else if err != nil {
-		return err
 	}
 
 	// TODO(vincepri): check for possible changes between the default spec and the instance.
 
+	if err := s.associateBastionAddress(instance); err != nil {
+		return false, err
+	}
+
 	s.scope.SetBastionInstance(instance.DeepCopy())
+	s.scope.SetBastionInstances([]infrav1.Instance{*instance.DeepCopy()})
 	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition)
 	s.scope.V(2).Info("Reconcile bastion completed successfully")
 
-	return nil
+	return false, nil
 }
 
-// DeleteBastion deletes the Bastion instance.
-func (s *Service) DeleteBastion() error {
-	instance, err := s.describeBastionInstance()
+// DeleteBastion deletes the Bastion instance, or the auto scaling group of the bastion hosts. It returns whether
+// the auto scaling group is still being deleted, in which case it should be called again.
+func (s *Service) DeleteBastion() (requeue bool, err error) {
+	instances, err := s.describeBastionInstances()
 	if err != nil {
-		if awserrors.IsNotFound(err) {
-			s.scope.V(4).Info("bastion instance does not exist")
-			return nil
+		return false, errors.Wrap(err, "unable to describe bastion instance")
+	}
+
+	var launchTemplateID string
+	if s.bastionAutoScalingMayExist(instances) {
+		launchTemplateID, err = s.GetLaunchTemplateID(s.bastionAutoScalingName())
+		if err != nil {
+			return false, errors.Wrap(err, "unable to describe bastion launch template")
 		}
-		return errors.Wrap(err, "unable to describe bastion instance")
+	}
+
+	standalone := standaloneBastionInstances(instances)
+	if launchTemplateID == "" && len(standalone) == 0 {
+		s.scope.V(4).Info("bastion instance does not exist")
+		return false, nil
 	}
 
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
-		return err
+		return false, err
 	}
 
-	if err := s.TerminateInstanceAndWait(instance.ID); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
-		record.Warnf(s.scope.InfraCluster(), "FailedTerminateBastion", "Failed to terminate bastion instance %q: %v", instance.ID, err)
-		return errors.Wrap(err, "unable to delete bastion instance")
+	if launchTemplateID != "" {
+		requeue, err := s.deleteBastionAutoScaling(launchTemplateID)
+		if err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return false, errors.Wrap(err, "unable to delete bastion auto scaling group")
+		}
+		if requeue {
+			return true, nil
+		}
+	}
+
+	for _, instance := range standalone {
+		if err := s.TerminateInstanceAndWait(instance.ID); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			record.Warnf(s.scope.InfraCluster(), "FailedTerminateBastion", "Failed to terminate bastion instance %q: %v", instance.ID, err)
+			return false, errors.Wrap(err, "unable to delete bastion instance")
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulTerminateBastion", "Terminated bastion instance %q", instance.ID)
 	}
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
-	record.Eventf(s.scope.InfraCluster(), "SuccessfulTerminateBastion", "Terminated bastion instance %q", instance.ID)
+
+	return false, nil
+}
+
+// associateBastionAddress associates an Elastic IP of the pool with the bastion instance, unless one already is.
+func (s *Service) associateBastionAddress(instance *infrav1.Instance) error {
+	pool := s.scope.ElasticIPPool()
+	if pool == nil {
+		return nil
+	}
+
+	input := &ec2.DescribeAddressesInput{}
+	if len(pool.AllocationIDs) > 0 {
+		input.AllocationIds = aws.StringSlice(pool.AllocationIDs)
+	}
+	if len(pool.Tags) > 0 {
+		input.Filters = filter.EC2.Tags(pool.Tags)
+	}
+	out, err := s.EC2Client.DescribeAddresses(input)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAddresses", "Failed to query the addresses of the Elastic IP pool: %v", err)
+		return errors.Wrap(err, "failed to query the addresses of the Elastic IP pool")
+	}
+
+	var available *ec2.Address
+	for _, address := range out.Addresses {
+		if aws.StringValue(address.InstanceId) == instance.ID {
+			instance.PublicIP = address.PublicIp
+			return nil
+		}
+		if address.AssociationId == nil && available == nil {
+			available = address
+		}
+	}
+	if available == nil {
+		record.Warnf(s.scope.InfraCluster(), "InsufficientElasticIPPool", "The Elastic IP pool has no available address for the bastion host")
+		return errors.New("the Elastic IP pool has no available address for the bastion host")
+	}
+
+	if _, err := s.EC2Client.AssociateAddress(&ec2.AssociateAddressInput{
+		AllocationId: available.AllocationId,
+		InstanceId:   aws.String(instance.ID),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateEIP", "Failed to associate Elastic IP %q with bastion instance %q: %v", aws.StringValue(available.AllocationId), instance.ID, err)
+		return errors.Wrapf(err, "failed to associate Elastic IP %q with bastion instance %q", aws.StringValue(available.AllocationId), instance.ID)
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAssociateEIP", "Associated Elastic IP %q with bastion instance %q", aws.StringValue(available.PublicIp), instance.ID)
+	instance.PublicIP = available.PublicIp
 
 	return nil
 }
 
-func (s *Service) describeBastionInstance() (*infrav1.Instance, error) {
+// describeBastionInstances returns the non-terminated bastion instances of the cluster, including those
+// launched by the auto scaling group of the bastion hosts, ordered by ID.
+func (s *Service) describeBastionInstances() ([]*infrav1.Instance, error) {
 	input := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			filter.EC2.ProviderRole(infrav1.BastionRoleTagValue),
@@ -144,17 +234,46 @@ func (s *Service) describeBastionInstance() (*infrav1.Instance, error) {
 		return nil, errors.Wrap(err, "failed to describe bastion host")
 	}
 
-	// TODO: properly handle multiple bastions found rather than just returning
-	// the first non-terminated.
+	instances := []*infrav1.Instance{}
 	for _, res := range out.Reservations {
 		for _, instance := range res.Instances {
-			if aws.StringValue(instance.State.Name) != ec2.InstanceStateNameTerminated {
-				return s.SDKToInstance(instance)
+			if aws.StringValue(instance.State.Name) == ec2.InstanceStateNameTerminated {
+				continue
 			}
+			i, err := s.SDKToInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, i)
 		}
 	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+
+	return instances, nil
+}
 
-	return nil, awserrors.NewNotFound("bastion host not found")
+// standaloneBastionInstances returns the bastion instances which were not launched by an auto scaling group.
+func standaloneBastionInstances(instances []*infrav1.Instance) []*infrav1.Instance {
+	res := []*infrav1.Instance{}
+	for _, instance := range instances {
+		if _, ok := instance.Tags[autoScalingGroupNameTag]; !ok {
+			res = append(res, instance)
+		}
+	}
+	return res
+}
+
+// autoScalingBastionInstances returns the bastion instances which were launched by an auto scaling group.
+func autoScalingBastionInstances(instances []*infrav1.Instance) []*infrav1.Instance {
+	res := []*infrav1.Instance{}
+	for _, instance := range instances {
+		if _, ok := instance.Tags[autoScalingGroupNameTag]; ok {
+			res = append(res, instance)
+		}
+	}
+	return res
 }
 
 func (s *Service) getDefaultBastion(instanceType, ami string) *infrav1.Instance {
@@ -0,0 +1,349 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// autoScalingGroupNameTag is the tag EC2 Auto Scaling sets on the instances it launches.
+const autoScalingGroupNameTag = "aws:autoscaling:groupName"
+
+// reconcileBastionAutoScaling ensures the bastion hosts run in an auto scaling group spread across the public
+// subnets, with a network load balancer in front of them if requested. A bastion instance created before is
+// terminated once a host of the group is running.
+func (s *Service) reconcileBastionAutoScaling(instances []*infrav1.Instance) error {
+	spec := s.scope.Bastion().AutoScaling
+	subnets := s.bastionSubnets()
+
+	launchTemplateID, err := s.reconcileBastionLaunchTemplate()
+	if err != nil {
+		return err
+	}
+
+	var lb *infrav1.BastionLoadBalancer
+	var targetGroupARN string
+	if spec.LoadBalancer {
+		lb, targetGroupARN, err = s.ELBService.ReconcileBastionLoadBalancer(subnets)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.reconcileBastionAutoScalingGroup(launchTemplateID, subnets, targetGroupARN); err != nil {
+		return err
+	}
+
+	if !spec.LoadBalancer && s.scope.BastionLoadBalancer() != nil {
+		if err := s.ELBService.DeleteBastionLoadBalancer(); err != nil {
+			return err
+		}
+	}
+	s.scope.SetBastionLoadBalancer(lb)
+
+	hosts := autoScalingBastionInstances(instances)
+	bastions := make([]infrav1.Instance, 0, len(hosts))
+	running := false
+	for _, host := range hosts {
+		bastions = append(bastions, *host.DeepCopy())
+		running = running || host.State == infrav1.InstanceStateRunning
+	}
+	s.scope.SetBastionInstances(bastions)
+
+	if len(bastions) == 0 {
+		s.scope.SetBastionInstance(nil)
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition, infrav1.BastionCreationStartedReason, clusterv1.ConditionSeverityInfo, "")
+		s.scope.V(2).Info("Waiting for the auto scaling group to launch bastion hosts")
+		return nil
+	}
+	s.scope.SetBastionInstance(bastions[0].DeepCopy())
+
+	if running {
+		for _, instance := range standaloneBastionInstances(instances) {
+			if err := s.TerminateInstance(instance.ID); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedTerminateBastion", "Failed to terminate bastion instance %q: %v", instance.ID, err)
+				return errors.Wrap(err, "unable to delete bastion instance")
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulTerminateBastion", "Terminated bastion instance %q replaced by the auto scaling group", instance.ID)
+		}
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.BastionHostReadyCondition)
+	s.scope.V(2).Info("Reconcile bastion completed successfully", "hosts", len(bastions))
+
+	return nil
+}
+
+// bastionAutoScalingMayExist returns whether the auto scaling group of the bastion hosts, its load balancer or its
+// launch template may exist. The hosts of a group being deleted are no longer described, so the hosts and the load
+// balancer recorded in the status of the cluster are checked too.
+func (s *Service) bastionAutoScalingMayExist(instances []*infrav1.Instance) bool {
+	if s.scope.Bastion().AutoScaling != nil || len(autoScalingBastionInstances(instances)) > 0 || s.scope.BastionLoadBalancer() != nil {
+		return true
+	}
+	for _, instance := range s.scope.BastionInstances() {
+		if _, ok := instance.Tags[autoScalingGroupNameTag]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBastionAutoScalingIfExists deletes the auto scaling group of the bastion hosts and the resources
+// around it, if its launch template exists. It returns whether the deletion is still in progress.
+func (s *Service) deleteBastionAutoScalingIfExists() (requeue bool, err error) {
+	launchTemplateID, err := s.GetLaunchTemplateID(s.bastionAutoScalingName())
+	if err != nil {
+		return false, errors.Wrap(err, "unable to describe bastion launch template")
+	}
+	if launchTemplateID == "" {
+		return false, nil
+	}
+	return s.deleteBastionAutoScaling(launchTemplateID)
+}
+
+// deleteBastionAutoScaling deletes the auto scaling group of the bastion hosts with their instances, their
+// load balancer and their launch template. The launch template is created first and deleted last, so that
+// its existence tells whether any of the other resources may be left. Deleting the group takes until its
+// instances are terminated, so it returns whether the deletion is still in progress rather than waiting.
+func (s *Service) deleteBastionAutoScaling(launchTemplateID string) (requeue bool, err error) {
+	name := s.bastionAutoScalingName()
+
+	group, err := s.ASGService.ASGIfExists(aws.String(name))
+	if err != nil {
+		return false, err
+	}
+	if group != nil {
+		if group.Status != expinfrav1.ASGStatusDeleteInProgress {
+			if err := s.ASGService.DeleteASG(name); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedDeleteBastion", "Failed to delete bastion auto scaling group %q: %v", name, err)
+				return false, err
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteBastion", "Deleted bastion auto scaling group %q", name)
+		}
+		s.scope.V(2).Info("Waiting for the bastion auto scaling group to be deleted", "name", name)
+		return true, nil
+	}
+
+	if s.scope.BastionLoadBalancer() != nil {
+		if err := s.ELBService.DeleteBastionLoadBalancer(); err != nil {
+			return false, err
+		}
+		s.scope.SetBastionLoadBalancer(nil)
+	}
+
+	if err := s.DeleteLaunchTemplate(launchTemplateID); err != nil {
+		return false, err
+	}
+
+	s.scope.SetBastionInstances(nil)
+	s.scope.SetBastionInstance(nil)
+	return false, nil
+}
+
+// bastionAutoScalingName returns the name of the launch template and auto scaling group of the bastion hosts.
+func (s *Service) bastionAutoScalingName() string {
+	return fmt.Sprintf("%s-%s", s.scope.Name(), infrav1.BastionRoleTagValue)
+}
+
+// bastionSubnets returns a public subnet of each availability zone, as a network load balancer can only be in
+// one subnet per availability zone.
+func (s *Service) bastionSubnets() infrav1.Subnets {
+	public := s.scope.Subnets().FilterPublic()
+	zones := public.GetUniqueZones()
+	sort.Strings(zones)
+
+	subnets := infrav1.Subnets{}
+	for _, zone := range zones {
+		subnets = append(subnets, public.FilterByZone(zone)[0])
+	}
+	return subnets
+}
+
+// bastionReplicas returns the number of bastion hosts, which is at least one.
+func (s *Service) bastionReplicas() int32 {
+	if replicas := s.scope.Bastion().AutoScaling.Replicas; replicas > 1 {
+		return replicas
+	}
+	return 1
+}
+
+// reconcileBastionLaunchTemplate ensures the launch template of the bastion hosts exists, and creates a new
+// version of it when the instance type, AMI, SSH key or security groups of the bastion change. The hosts that
+// are running keep the version they were launched with until they are replaced.
+func (s *Service) reconcileBastionLaunchTemplate() (string, error) {
+	name := s.bastionAutoScalingName()
+	bastion := s.getDefaultBastion(s.scope.Bastion().InstanceType, s.scope.Bastion().AMI)
+
+	data := &ec2.RequestLaunchTemplateData{
+		ImageId:          aws.String(bastion.ImageID),
+		InstanceType:     aws.String(bastion.Type),
+		KeyName:          bastion.SSHKeyName,
+		UserData:         bastion.UserData,
+		SecurityGroupIds: aws.StringSlice(bastion.SecurityGroupIDs),
+		TagSpecifications: []*ec2.LaunchTemplateTagSpecificationRequest{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeInstance),
+				Tags:         converters.MapToTags(bastion.Tags),
+			},
+		},
+	}
+
+	out, err := s.EC2Client.DescribeLaunchTemplateVersions(&ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateName: aws.String(name),
+		Versions:           aws.StringSlice([]string{expinfrav1.LaunchTemplateLatestVersion}),
+	})
+	switch {
+	case awserrors.IsNotFound(err) || (err == nil && len(out.LaunchTemplateVersions) == 0):
+		result, err := s.EC2Client.CreateLaunchTemplate(&ec2.CreateLaunchTemplateInput{
+			LaunchTemplateName: aws.String(name),
+			LaunchTemplateData: data,
+			TagSpecifications: []*ec2.TagSpecification{
+				{
+					ResourceType: aws.String(ec2.ResourceTypeLaunchTemplate),
+					Tags:         converters.MapToTags(bastion.Tags),
+				},
+			},
+		})
+		if err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedCreateBastion", "Failed to create bastion launch template: %v", err)
+			return "", errors.Wrapf(err, "failed to create bastion launch template %q", name)
+		}
+		s.scope.V(2).Info("Created bastion launch template", "name", name)
+		return aws.StringValue(result.LaunchTemplate.LaunchTemplateId), nil
+	case err != nil:
+		return "", errors.Wrapf(err, "failed to describe bastion launch template %q", name)
+	}
+
+	latest := out.LaunchTemplateVersions[0]
+	id := aws.StringValue(latest.LaunchTemplateId)
+	if !bastionLaunchTemplateNeedsUpdate(latest.LaunchTemplateData, data) {
+		return id, nil
+	}
+
+	if _, err := s.EC2Client.CreateLaunchTemplateVersion(&ec2.CreateLaunchTemplateVersionInput{
+		LaunchTemplateId:   aws.String(id),
+		LaunchTemplateData: data,
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to create bastion launch template version for %q", name)
+	}
+	s.scope.V(2).Info("Created bastion launch template version", "name", name)
+	return id, nil
+}
+
+// bastionLaunchTemplateNeedsUpdate returns whether the bastion spec changed since the launch template version was
+// created.
+func bastionLaunchTemplateNeedsUpdate(existing *ec2.ResponseLaunchTemplateData, desired *ec2.RequestLaunchTemplateData) bool {
+	if existing == nil {
+		return true
+	}
+	if aws.StringValue(existing.ImageId) != aws.StringValue(desired.ImageId) ||
+		aws.StringValue(existing.InstanceType) != aws.StringValue(desired.InstanceType) ||
+		aws.StringValue(existing.KeyName) != aws.StringValue(desired.KeyName) {
+		return true
+	}
+
+	existingGroups := aws.StringValueSlice(existing.SecurityGroupIds)
+	desiredGroups := aws.StringValueSlice(desired.SecurityGroupIds)
+	sort.Strings(existingGroups)
+	sort.Strings(desiredGroups)
+	return strings.Join(existingGroups, ",") != strings.Join(desiredGroups, ",")
+}
+
+// reconcileBastionAutoScalingGroup ensures the auto scaling group of the bastion hosts exists with the size and
+// subnets of the spec, and registers its instances with the target group of the load balancer, if any.
+func (s *Service) reconcileBastionAutoScalingGroup(launchTemplateID string, subnets infrav1.Subnets, targetGroupARN string) error {
+	name := s.bastionAutoScalingName()
+	size := s.bastionReplicas()
+	spec := &expinfrav1.AutoScalingGroup{
+		Name:            name,
+		MinSize:         size,
+		MaxSize:         size,
+		DesiredCapacity: aws.Int32(size),
+		Subnets:         subnets.IDs(),
+	}
+
+	group, err := s.ASGService.ASGIfExists(aws.String(name))
+	if err != nil {
+		return err
+	}
+
+	if group == nil {
+		spec.Tags = infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.scope.Name(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        aws.String(name),
+			Role:        aws.String(infrav1.BastionRoleTagValue),
+			Additional:  s.scope.AdditionalTags(),
+		})
+		if targetGroupARN != "" {
+			spec.TargetGroupARNs = []string{targetGroupARN}
+		}
+		if err := s.ASGService.CreateASGWithLaunchTemplate(spec, launchTemplateID); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedCreateBastion", "Failed to create bastion auto scaling group: %v", err)
+			return errors.Wrapf(err, "failed to create bastion auto scaling group %q", name)
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateBastion", "Created bastion auto scaling group %q", name)
+		return nil
+	}
+
+	if group.MinSize != size || group.MaxSize != size || aws.Int32Value(group.DesiredCapacity) != size ||
+		!sets.NewString(group.Subnets...).Equal(sets.NewString(spec.Subnets...)) {
+		if err := s.ASGService.ResizeASG(spec); err != nil {
+			return err
+		}
+	}
+
+	lbName, err := elb.BastionLoadBalancerName(s.scope.Name())
+	if err != nil {
+		return err
+	}
+	attached := false
+	for _, arn := range group.TargetGroupARNs {
+		switch {
+		case arn == targetGroupARN:
+			attached = true
+		case strings.Contains(arn, fmt.Sprintf(":targetgroup/%s/", lbName)):
+			if err := s.ASGService.DetachTargetGroups(name, []string{arn}); err != nil {
+				return err
+			}
+		}
+	}
+	if targetGroupARN != "" && !attached {
+		if err := s.ASGService.AttachTargetGroups(name, []string{targetGroupARN}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
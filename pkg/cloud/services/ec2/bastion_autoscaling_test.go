@@ -0,0 +1,335 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbv2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileBastionAutoScaling(t *testing.T) {
+	clusterName := "cluster"
+	asgName := "cluster-bastion"
+
+	bastionInstance := func(id string, asg bool) *ec2.Instance {
+		tags := []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String(asgName)}}
+		if asg {
+			tags = append(tags, &ec2.Tag{Key: aws.String(autoScalingGroupNameTag), Value: aws.String(asgName)})
+		}
+		return &ec2.Instance{
+			InstanceId: aws.String(id),
+			State:      &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+			Placement:  &ec2.Placement{AvailabilityZone: aws.String("us-west-2a")},
+			Tags:       tags,
+		}
+	}
+	lbNotFound := awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "not found", nil)
+	tgNotFound := awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "not found", nil)
+
+	testCases := []struct {
+		name                  string
+		autoScaling           *infrav1.BastionAutoScaling
+		expectEC2             func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectASG             func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+		expectELBV2           func(m *mock_elbv2iface.MockELBV2APIMockRecorder)
+		expectBastions        []string
+		expectReady           bool
+		expectLoadBalancerDNS string
+	}{
+		{
+			name:        "creates the launch template, load balancer and auto scaling group",
+			autoScaling: &infrav1.BastionAutoScaling{Replicas: 2, LoadBalancer: true},
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil)
+				m.DescribeLaunchTemplateVersions(gomock.Any()).
+					Return(nil, awserr.New(awserrors.LaunchTemplateNameNotFound, "not found", nil))
+				m.CreateLaunchTemplate(gomock.Any()).
+					DoAndReturn(func(input *ec2.CreateLaunchTemplateInput) (*ec2.CreateLaunchTemplateOutput, error) {
+						if aws.StringValue(input.LaunchTemplateData.ImageId) != "ami-bastion" {
+							t.Errorf("Expected the bastion AMI, got %q", aws.StringValue(input.LaunchTemplateData.ImageId))
+						}
+						return &ec2.CreateLaunchTemplateOutput{
+							LaunchTemplate: &ec2.LaunchTemplate{LaunchTemplateId: aws.String("lt-bastion")},
+						}, nil
+					})
+			},
+			expectELBV2: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Any()).Return(nil, lbNotFound)
+				m.CreateLoadBalancer(gomock.Any()).
+					DoAndReturn(func(input *elbv2.CreateLoadBalancerInput) (*elbv2.CreateLoadBalancerOutput, error) {
+						if subnets := aws.StringValueSlice(input.Subnets); len(subnets) != 2 {
+							t.Errorf("Expected a subnet in each availability zone, got %v", subnets)
+						}
+						return &elbv2.CreateLoadBalancerOutput{
+							LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String("lb-arn"), DNSName: aws.String("bastion.elb.amazonaws.com")}},
+						}, nil
+					})
+				m.DescribeTargetGroups(gomock.Any()).Return(nil, tgNotFound)
+				m.CreateTargetGroup(gomock.Any()).Return(&elbv2.CreateTargetGroupOutput{
+					TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String("tg-arn")}},
+				}, nil)
+				m.DescribeListeners(gomock.Any()).Return(&elbv2.DescribeListenersOutput{}, nil)
+				m.CreateListener(gomock.Any()).Return(&elbv2.CreateListenerOutput{}, nil)
+			},
+			expectASG: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeAutoScalingGroups(gomock.Any()).Return(&autoscaling.DescribeAutoScalingGroupsOutput{}, nil)
+				m.CreateAutoScalingGroup(gomock.Any()).
+					DoAndReturn(func(input *autoscaling.CreateAutoScalingGroupInput) (*autoscaling.CreateAutoScalingGroupOutput, error) {
+						if aws.Int64Value(input.DesiredCapacity) != 2 {
+							t.Errorf("Expected 2 bastion hosts, got %d", aws.Int64Value(input.DesiredCapacity))
+						}
+						if targetGroups := aws.StringValueSlice(input.TargetGroupARNs); len(targetGroups) != 1 || targetGroups[0] != "tg-arn" {
+							t.Errorf("Expected the target group of the load balancer, got %v", targetGroups)
+						}
+						return &autoscaling.CreateAutoScalingGroupOutput{}, nil
+					})
+			},
+			expectBastions:        []string{},
+			expectLoadBalancerDNS: "bastion.elb.amazonaws.com",
+		},
+		{
+			name:        "terminates the bastion instance once a host of the auto scaling group is running",
+			autoScaling: &infrav1.BastionAutoScaling{Replicas: 1},
+			expectEC2: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{bastionInstance("i-2", true), bastionInstance("i-1", false)}}},
+				}, nil)
+				m.DescribeLaunchTemplateVersions(gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+					LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{
+						LaunchTemplateId: aws.String("lt-bastion"),
+						LaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+							ImageId:          aws.String("ami-bastion"),
+							InstanceType:     aws.String("t3.micro"),
+							KeyName:          aws.String(defaultSSHKeyName),
+							SecurityGroupIds: aws.StringSlice([]string{"sg-bastion"}),
+						},
+					}},
+				}, nil)
+				m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{
+					InstanceIds: aws.StringSlice([]string{"i-1"}),
+				})).Return(&ec2.TerminateInstancesOutput{}, nil)
+			},
+			expectELBV2: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {},
+			expectASG: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeAutoScalingGroups(gomock.Any()).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+					AutoScalingGroups: []*autoscaling.Group{{
+						AutoScalingGroupName: aws.String(asgName),
+						MinSize:              aws.Int64(1),
+						MaxSize:              aws.Int64(1),
+						DesiredCapacity:      aws.Int64(1),
+						VPCZoneIdentifier:    aws.String("subnet-public-a,subnet-public-b"),
+					}},
+				}, nil)
+			},
+			expectBastions: []string{"i-2"},
+			expectReady:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).To(BeNil())
+
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{ID: "vpc-id"},
+						Subnets: infrav1.Subnets{
+							{ID: "subnet-private", AvailabilityZone: "us-west-2a", IsPublic: false},
+							{ID: "subnet-public-a", AvailabilityZone: "us-west-2a", IsPublic: true},
+							{ID: "subnet-public-b", AvailabilityZone: "us-west-2b", IsPublic: true},
+						},
+					},
+					Bastion: infrav1.Bastion{
+						Enabled:      true,
+						AMI:          "ami-bastion",
+						InstanceType: "t3.micro",
+						AutoScaling:  tc.autoScaling,
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupBastion: {ID: "sg-bastion"},
+						},
+					},
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			g.Expect(client.Create(context.TODO(), awsCluster)).To(Succeed())
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: clusterName},
+				},
+				AWSCluster: awsCluster,
+				Client:     client,
+			})
+			g.Expect(err).To(BeNil())
+
+			tc.expectEC2(ec2Mock.EXPECT())
+			tc.expectASG(asgMock.EXPECT())
+			tc.expectELBV2(elbv2Mock.EXPECT())
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+			s.ASGService.ASGClient = asgMock
+			s.ELBService.ELBV2Client = elbv2Mock
+
+			requeue, err := s.ReconcileBastion()
+			g.Expect(err).To(BeNil())
+			g.Expect(requeue).To(BeFalse())
+
+			bastions := []string{}
+			for _, instance := range awsCluster.Status.Bastions {
+				bastions = append(bastions, instance.ID)
+			}
+			g.Expect(bastions).To(Equal(tc.expectBastions))
+			g.Expect(conditions.IsTrue(awsCluster, infrav1.BastionHostReadyCondition)).To(Equal(tc.expectReady))
+			if tc.expectLoadBalancerDNS == "" {
+				g.Expect(awsCluster.Status.BastionLoadBalancer).To(BeNil())
+			} else {
+				g.Expect(awsCluster.Status.BastionLoadBalancer).NotTo(BeNil())
+				g.Expect(awsCluster.Status.BastionLoadBalancer.DNSName).To(Equal(tc.expectLoadBalancerDNS))
+			}
+		})
+	}
+}
+
+func TestDeleteBastionAutoScaling(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+	scheme, err := setupScheme()
+	g.Expect(err).To(BeNil())
+
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			Bastion: infrav1.Bastion{
+				AutoScaling: &infrav1.BastionAutoScaling{Replicas: 1, LoadBalancer: true},
+			},
+		},
+		Status: infrav1.AWSClusterStatus{
+			Bastions:            []infrav1.Instance{{ID: "i-1", Tags: infrav1.Tags{autoScalingGroupNameTag: "cluster-bastion"}}},
+			BastionLoadBalancer: &infrav1.BastionLoadBalancer{ARN: "lb-arn"},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	g.Expect(client.Create(context.TODO(), awsCluster)).To(Succeed())
+
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	g.Expect(err).To(BeNil())
+
+	s := NewService(scope)
+	s.EC2Client = ec2Mock
+	s.ASGService.ASGClient = asgMock
+	s.ELBService.ELBV2Client = elbv2Mock
+
+	// The deletion of the auto scaling group is requested, and checked again on the next call.
+	gomock.InOrder(
+		ec2Mock.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil),
+		ec2Mock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{LaunchTemplateId: aws.String("lt-bastion")}},
+		}, nil),
+		asgMock.EXPECT().DescribeAutoScalingGroups(gomock.Any()).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{{AutoScalingGroupName: aws.String("cluster-bastion")}},
+		}, nil),
+		asgMock.EXPECT().DeleteAutoScalingGroup(gomock.Eq(&autoscaling.DeleteAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String("cluster-bastion"),
+			ForceDelete:          aws.Bool(true),
+		})).Return(&autoscaling.DeleteAutoScalingGroupOutput{}, nil),
+	)
+
+	requeue, err := s.DeleteBastion()
+	g.Expect(err).To(BeNil())
+	g.Expect(requeue).To(BeTrue())
+	g.Expect(awsCluster.Status.Bastions).NotTo(BeEmpty())
+
+	// Once the group is gone, its load balancer and launch template are deleted, even if the bastion no longer runs
+	// in an auto scaling group, as its hosts are recorded in the status.
+	awsCluster.Spec.Bastion.AutoScaling = nil
+	gomock.InOrder(
+		ec2Mock.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil),
+		ec2Mock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{LaunchTemplateId: aws.String("lt-bastion")}},
+		}, nil),
+		asgMock.EXPECT().DescribeAutoScalingGroups(gomock.Any()).Return(&autoscaling.DescribeAutoScalingGroupsOutput{}, nil),
+		elbv2Mock.EXPECT().DescribeLoadBalancers(gomock.Any()).Return(&elbv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String("lb-arn")}},
+		}, nil),
+		elbv2Mock.EXPECT().DeleteLoadBalancer(gomock.Eq(&elbv2.DeleteLoadBalancerInput{
+			LoadBalancerArn: aws.String("lb-arn"),
+		})).Return(&elbv2.DeleteLoadBalancerOutput{}, nil),
+		elbv2Mock.EXPECT().WaitUntilLoadBalancersDeleted(gomock.Any()).Return(nil),
+		elbv2Mock.EXPECT().DescribeTargetGroups(gomock.Any()).Return(&elbv2.DescribeTargetGroupsOutput{
+			TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String("tg-arn")}},
+		}, nil),
+		elbv2Mock.EXPECT().DeleteTargetGroup(gomock.Eq(&elbv2.DeleteTargetGroupInput{
+			TargetGroupArn: aws.String("tg-arn"),
+		})).Return(&elbv2.DeleteTargetGroupOutput{}, nil),
+		ec2Mock.EXPECT().DeleteLaunchTemplate(gomock.Eq(&ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: aws.String("lt-bastion"),
+		})).Return(&ec2.DeleteLaunchTemplateOutput{}, nil),
+	)
+
+	requeue, err = s.DeleteBastion()
+	g.Expect(err).To(BeNil())
+	g.Expect(requeue).To(BeFalse())
+	g.Expect(awsCluster.Status.Bastions).To(BeEmpty())
+	g.Expect(awsCluster.Status.BastionLoadBalancer).To(BeNil())
+}
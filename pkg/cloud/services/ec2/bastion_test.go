@@ -209,14 +209,113 @@ func TestDeleteBastion(t *testing.T) {
 				s := NewService(scope)
 				s.EC2Client = ec2Mock
 
-				err = s.DeleteBastion()
+				requeue, err := s.DeleteBastion()
 				if tc.expectError {
 					g.Expect(err).NotTo(BeNil())
 					return
 				}
 
 				g.Expect(err).To(BeNil())
+				g.Expect(requeue).To(BeFalse())
 			})
 		}
 	}
 }
+
+func TestAssociateBastionAddress(t *testing.T) {
+	describeInput := &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("tag:allowlisted"), Values: aws.StringSlice([]string{"true"})},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		addresses    []*ec2.Address
+		expect       func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		wantPublicIP string
+		expectError  bool
+	}{
+		{
+			name: "address of the pool already associated with the instance",
+			addresses: []*ec2.Address{
+				{AllocationId: aws.String("eipalloc-1"), AssociationId: aws.String("eipassoc-1"), InstanceId: aws.String("id123"), PublicIp: aws.String("1.2.3.4")},
+				{AllocationId: aws.String("eipalloc-2"), PublicIp: aws.String("5.6.7.8")},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.AssociateAddress(gomock.Any()).Times(0)
+			},
+			wantPublicIP: "1.2.3.4",
+		},
+		{
+			name: "available address of the pool is associated with the instance",
+			addresses: []*ec2.Address{
+				{AllocationId: aws.String("eipalloc-1"), AssociationId: aws.String("eipassoc-1"), InstanceId: aws.String("id456"), PublicIp: aws.String("1.2.3.4")},
+				{AllocationId: aws.String("eipalloc-2"), PublicIp: aws.String("5.6.7.8")},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId: aws.String("eipalloc-2"),
+					InstanceId:   aws.String("id123"),
+				})).Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+			wantPublicIP: "5.6.7.8",
+		},
+		{
+			name: "no available address in the pool",
+			addresses: []*ec2.Address{
+				{AllocationId: aws.String("eipalloc-1"), AssociationId: aws.String("eipassoc-1"), InstanceId: aws.String("id456"), PublicIp: aws.String("1.2.3.4")},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.AssociateAddress(gomock.Any()).Times(0)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockControl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).To(BeNil())
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							ElasticIPPool: &infrav1.ElasticIPPool{Tags: infrav1.Tags{"allowlisted": "true"}},
+						},
+					},
+				},
+				Client: client,
+			})
+			g.Expect(err).To(BeNil())
+
+			ec2Mock.EXPECT().DescribeAddresses(gomock.Eq(describeInput)).
+				Return(&ec2.DescribeAddressesOutput{Addresses: tc.addresses}, nil)
+			tc.expect(ec2Mock.EXPECT())
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			instance := &infrav1.Instance{ID: "id123"}
+			err = s.associateBastionAddress(instance)
+			if tc.expectError {
+				g.Expect(err).NotTo(BeNil())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(aws.StringValue(instance.PublicIP)).To(Equal(tc.wantPublicIP))
+		})
+	}
+}
@@ -0,0 +1,410 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// connectivityRecheckInterval is how long the result of an analysis which found no path to the API
+	// server is kept before the path is analyzed again. Every analysis is billed.
+	connectivityRecheckInterval = 30 * time.Minute
+
+	// maxConnectivityExplanations is the number of explanations of a failed analysis reported in the
+	// message of the APIServerReachable condition.
+	maxConnectivityExplanations = 3
+)
+
+// ReconcileAPIServerConnectivity checks that the instance of the machine can reach the API server load balancer
+// of the cluster and reports the result with the APIServerReachable condition of the machine. The route table of
+// the subnet of the instance is inspected on every call, and the VPC Reachability Analyzer is used to analyze the
+// path from the instance to the load balancer through its security groups and network ACLs. It reports whether
+// an analysis is still running.
+func (s *Service) ReconcileAPIServerConnectivity(scope *scope.MachineScope, instance *infrav1.Instance) (bool, error) {
+	lb := s.scope.Network().APIServerELB
+	if lb.Name == "" {
+		// Clusters without a load balancer, such as EKS clusters, have no path to check.
+		conditions.Delete(scope.AWSMachine, infrav1.APIServerReachableCondition)
+		return false, nil
+	}
+
+	if conditions.IsTrue(scope.AWSMachine, infrav1.APIServerReachableCondition) {
+		return false, nil
+	}
+
+	if lb.Scheme != infrav1.ClassicELBSchemeInternal {
+		message, err := s.missingDefaultRoute(instance.SubnetID)
+		if err != nil {
+			return false, err
+		}
+		if message != "" {
+			conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.APIServerRouteMissingReason, clusterv1.ConditionSeverityError, message)
+			return false, nil
+		}
+	}
+
+	destination, err := s.connectivityDestination(lb)
+	if err != nil {
+		return false, err
+	}
+	if destination == "" {
+		// The route table is all that can be checked without an interface to analyze the path to.
+		conditions.MarkTrue(scope.AWSMachine, infrav1.APIServerReachableCondition)
+		return false, nil
+	}
+
+	pathID, err := s.ensureNetworkInsightsPath(scope.Name(), instance.ID, destination)
+	if err != nil {
+		return false, err
+	}
+
+	analysis, err := s.latestNetworkInsightsAnalysis(pathID)
+	if err != nil {
+		return false, err
+	}
+
+	if analysis == nil || (aws.StringValue(analysis.Status) != ec2.AnalysisStatusRunning && time.Since(aws.TimeValue(analysis.StartDate)) > connectivityRecheckInterval) {
+		if _, err := s.EC2Client.StartNetworkInsightsAnalysis(&ec2.StartNetworkInsightsAnalysisInput{
+			NetworkInsightsPathId: aws.String(pathID),
+		}); err != nil {
+			return false, errors.Wrapf(err, "failed to start analysis of network insights path %q", pathID)
+		}
+		s.scope.V(2).Info("Started analysis of the path to the API server", "instance-id", instance.ID, "path-id", pathID)
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.ConnectivityAnalysisRunningReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	}
+
+	switch aws.StringValue(analysis.Status) {
+	case ec2.AnalysisStatusRunning:
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.ConnectivityAnalysisRunningReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	case ec2.AnalysisStatusFailed:
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.ConnectivityCheckFailedReason, clusterv1.ConditionSeverityWarning,
+			"Analysis of the path to the API server failed: %s", aws.StringValue(analysis.StatusMessage))
+		return false, nil
+	}
+
+	if !aws.BoolValue(analysis.NetworkPathFound) {
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.APIServerUnreachableReason, clusterv1.ConditionSeverityError,
+			"No network path from instance %s to the API server on port %d: %s", instance.ID, s.scope.APIServerPort(), explainAnalysis(analysis.Explanations))
+		return false, nil
+	}
+
+	conditions.MarkTrue(scope.AWSMachine, infrav1.APIServerReachableCondition)
+
+	// The result is not checked again, so the path and its analyses are no longer needed.
+	if err := s.deleteNetworkInsightsPath(pathID); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// AnalyzeAPIServerConnectivity runs an on-demand analysis of the path from the instance of the machine to the API
+// server load balancer of the cluster, independent of the periodic check. The findings are published as an event and
+// with the APIServerReachable condition of the machine, and the network insights path and its analyses are deleted
+// once the analysis completed. It reports whether the analysis is still running.
+func (s *Service) AnalyzeAPIServerConnectivity(scope *scope.MachineScope, instance *infrav1.Instance) (bool, error) {
+	lb := s.scope.Network().APIServerELB
+	if lb.Name == "" {
+		record.Warnf(scope.AWSMachine, "FailedAnalyzeConnectivity", "Cluster %s has no API server load balancer to analyze the path to", s.scope.Name())
+		return false, nil
+	}
+
+	name := connectivityAnalysisPathName(scope)
+	pathID, err := s.findNetworkInsightsPath(name)
+	if err != nil {
+		return false, err
+	}
+	if pathID == "" {
+		destination, err := s.connectivityDestination(lb)
+		if err != nil {
+			return false, err
+		}
+		if destination == "" {
+			record.Warnf(scope.AWSMachine, "FailedAnalyzeConnectivity", "No load balancer network interface to analyze the path from instance %s to", instance.ID)
+			return false, nil
+		}
+		if pathID, err = s.createNetworkInsightsPath(name, instance.ID, destination); err != nil {
+			return false, err
+		}
+	}
+
+	analysis, err := s.latestNetworkInsightsAnalysis(pathID)
+	if err != nil {
+		return false, err
+	}
+
+	if analysis == nil {
+		if _, err := s.EC2Client.StartNetworkInsightsAnalysis(&ec2.StartNetworkInsightsAnalysisInput{
+			NetworkInsightsPathId: aws.String(pathID),
+		}); err != nil {
+			return false, errors.Wrapf(err, "failed to start analysis of network insights path %q", pathID)
+		}
+		s.scope.V(2).Info("Started on-demand analysis of the path to the API server", "instance-id", instance.ID, "path-id", pathID)
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.ConnectivityAnalysisRunningReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	}
+
+	switch {
+	case aws.StringValue(analysis.Status) == ec2.AnalysisStatusRunning:
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.ConnectivityAnalysisRunningReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	case aws.StringValue(analysis.Status) == ec2.AnalysisStatusFailed:
+		record.Warnf(scope.AWSMachine, "FailedAnalyzeConnectivity", "Analysis of the path from instance %s to the API server failed: %s", instance.ID, aws.StringValue(analysis.StatusMessage))
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.ConnectivityCheckFailedReason, clusterv1.ConditionSeverityWarning,
+			"Analysis of the path to the API server failed: %s", aws.StringValue(analysis.StatusMessage))
+	case !aws.BoolValue(analysis.NetworkPathFound):
+		explanation := explainAnalysis(analysis.Explanations)
+		record.Warnf(scope.AWSMachine, "FailedAnalyzeConnectivity", "No network path from instance %s to the API server on port %d: %s", instance.ID, s.scope.APIServerPort(), explanation)
+		conditions.MarkFalse(scope.AWSMachine, infrav1.APIServerReachableCondition, infrav1.APIServerUnreachableReason, clusterv1.ConditionSeverityError,
+			"No network path from instance %s to the API server on port %d: %s", instance.ID, s.scope.APIServerPort(), explanation)
+	default:
+		record.Eventf(scope.AWSMachine, "SuccessfulAnalyzeConnectivity", "Found a network path from instance %s to the API server on port %d", instance.ID, s.scope.APIServerPort())
+		conditions.MarkTrue(scope.AWSMachine, infrav1.APIServerReachableCondition)
+	}
+
+	if err := s.deleteNetworkInsightsPath(pathID); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// DeleteAPIServerConnectivityCheck deletes the network insights paths used to check that the instance of the
+// machine can reach the API server, and their analyses.
+func (s *Service) DeleteAPIServerConnectivityCheck(scope *scope.MachineScope) error {
+	for _, name := range []string{scope.Name(), connectivityAnalysisPathName(scope)} {
+		pathID, err := s.findNetworkInsightsPath(name)
+		if err != nil {
+			return err
+		}
+		if pathID == "" {
+			continue
+		}
+		if err := s.deleteNetworkInsightsPath(pathID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectivityAnalysisPathName returns the name of the network insights path of an on-demand analysis, which is
+// kept apart from the path of the periodic check so their analyses are not mixed up.
+func connectivityAnalysisPathName(scope *scope.MachineScope) string {
+	return scope.Name() + "-analysis"
+}
+
+// missingDefaultRoute returns why the subnet has no route to an internet-facing load balancer, or an empty
+// string if the route table of the subnet has an active default route.
+func (s *Service) missingDefaultRoute(subnetID string) (string, error) {
+	out, err := s.EC2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("association.subnet-id"), Values: aws.StringSlice([]string{subnetID})}},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe route table of subnet %q", subnetID)
+	}
+
+	if len(out.RouteTables) == 0 {
+		// Subnets without an explicit association use the main route table of the VPC.
+		out, err = s.EC2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+			Filters: []*ec2.Filter{
+				filter.EC2.VPC(s.scope.VPC().ID),
+				{Name: aws.String("association.main"), Values: aws.StringSlice([]string{"true"})},
+			},
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to describe main route table of vpc %q", s.scope.VPC().ID)
+		}
+		if len(out.RouteTables) == 0 {
+			return fmt.Sprintf("Subnet %s has no route table", subnetID), nil
+		}
+	}
+
+	table := out.RouteTables[0]
+	for _, route := range table.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) != "0.0.0.0/0" {
+			continue
+		}
+		if aws.StringValue(route.State) == ec2.RouteStateBlackhole {
+			return fmt.Sprintf("The default route of route table %s of subnet %s is a blackhole, so the internet-facing API server load balancer cannot be reached",
+				aws.StringValue(table.RouteTableId), subnetID), nil
+		}
+		return "", nil
+	}
+	return fmt.Sprintf("Route table %s of subnet %s has no default route, so the internet-facing API server load balancer cannot be reached",
+		aws.StringValue(table.RouteTableId), subnetID), nil
+}
+
+// connectivityDestination returns the ID of the network interface of the API server load balancer which the path
+// is analyzed to, whatever the scheme of the load balancer, so that the analysis covers the security groups of
+// the load balancer. It returns an empty string if the load balancer has no network interface yet.
+func (s *Service) connectivityDestination(lb infrav1.ClassicELB) (string, error) {
+	out, err := s.EC2Client.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			{Name: aws.String("description"), Values: aws.StringSlice([]string{"ELB " + lb.Name})},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe network interfaces of load balancer %q", lb.Name)
+	}
+	ids := make([]string, 0, len(out.NetworkInterfaces))
+	for _, eni := range out.NetworkInterfaces {
+		ids = append(ids, aws.StringValue(eni.NetworkInterfaceId))
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	sort.Strings(ids)
+	return ids[0], nil
+}
+
+// findNetworkInsightsPath returns the ID of the network insights path with the given name, or an empty string if
+// there is none.
+func (s *Service) findNetworkInsightsPath(name string) (string, error) {
+	out, err := s.EC2Client.DescribeNetworkInsightsPaths(&ec2.DescribeNetworkInsightsPathsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.Name(name),
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe network insights path %q", name)
+	}
+	if len(out.NetworkInsightsPaths) == 0 {
+		return "", nil
+	}
+	return aws.StringValue(out.NetworkInsightsPaths[0].NetworkInsightsPathId), nil
+}
+
+func (s *Service) ensureNetworkInsightsPath(name, instanceID, destination string) (string, error) {
+	pathID, err := s.findNetworkInsightsPath(name)
+	if err != nil || pathID != "" {
+		return pathID, err
+	}
+	return s.createNetworkInsightsPath(name, instanceID, destination)
+}
+
+func (s *Service) createNetworkInsightsPath(name, instanceID, destination string) (string, error) {
+	out, err := s.EC2Client.CreateNetworkInsightsPath(&ec2.CreateNetworkInsightsPathInput{
+		Source:          aws.String(instanceID),
+		Destination:     aws.String(destination),
+		DestinationPort: aws.Int64(int64(s.scope.APIServerPort())),
+		Protocol:        aws.String(ec2.ProtocolTcp),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypeNetworkInsightsPath, infrav1.BuildParams{
+				ClusterName: s.scope.Name(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        aws.String(name),
+				Additional:  s.scope.AdditionalTags(),
+			}),
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create network insights path from instance %q to %q", instanceID, destination)
+	}
+	pathID := aws.StringValue(out.NetworkInsightsPath.NetworkInsightsPathId)
+	s.scope.V(2).Info("Created network insights path to the API server", "instance-id", instanceID, "path-id", pathID)
+	return pathID, nil
+}
+
+// latestNetworkInsightsAnalysis returns the analysis of the path which started last, or nil if the path was
+// never analyzed.
+func (s *Service) latestNetworkInsightsAnalysis(pathID string) (*ec2.NetworkInsightsAnalysis, error) {
+	analyses, err := s.networkInsightsAnalyses(pathID)
+	if err != nil || len(analyses) == 0 {
+		return nil, err
+	}
+	sort.Slice(analyses, func(i, j int) bool {
+		return aws.TimeValue(analyses[i].StartDate).After(aws.TimeValue(analyses[j].StartDate))
+	})
+	return analyses[0], nil
+}
+
+func (s *Service) networkInsightsAnalyses(pathID string) ([]*ec2.NetworkInsightsAnalysis, error) {
+	analyses := []*ec2.NetworkInsightsAnalysis{}
+	input := &ec2.DescribeNetworkInsightsAnalysesInput{NetworkInsightsPathId: aws.String(pathID)}
+	if err := s.EC2Client.DescribeNetworkInsightsAnalysesPages(input, func(out *ec2.DescribeNetworkInsightsAnalysesOutput, _ bool) bool {
+		analyses = append(analyses, out.NetworkInsightsAnalyses...)
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe analyses of network insights path %q", pathID)
+	}
+	return analyses, nil
+}
+
+// deleteNetworkInsightsPath deletes the network insights path and its analyses. A path can only be deleted once
+// all its analyses are.
+func (s *Service) deleteNetworkInsightsPath(pathID string) error {
+	analyses, err := s.networkInsightsAnalyses(pathID)
+	if err != nil {
+		return err
+	}
+	for _, analysis := range analyses {
+		if _, err := s.EC2Client.DeleteNetworkInsightsAnalysis(&ec2.DeleteNetworkInsightsAnalysisInput{
+			NetworkInsightsAnalysisId: analysis.NetworkInsightsAnalysisId,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete network insights analysis %q", aws.StringValue(analysis.NetworkInsightsAnalysisId))
+		}
+	}
+
+	if _, err := s.EC2Client.DeleteNetworkInsightsPath(&ec2.DeleteNetworkInsightsPathInput{
+		NetworkInsightsPathId: aws.String(pathID),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete network insights path %q", pathID)
+	}
+	s.scope.V(2).Info("Deleted network insights path to the API server", "path-id", pathID)
+	return nil
+}
+
+// explainAnalysis summarizes why an analysis found no path, such as the security group or route table which
+// blocks it.
+func explainAnalysis(explanations []*ec2.Explanation) string {
+	if len(explanations) == 0 {
+		return "no explanation was given"
+	}
+
+	messages := []string{}
+	for _, e := range explanations {
+		if len(messages) == maxConnectivityExplanations {
+			break
+		}
+		message := aws.StringValue(e.ExplanationCode)
+		for _, c := range []*ec2.AnalysisComponent{e.SecurityGroup, e.Acl, e.RouteTable, e.Subnet, e.Component} {
+			if c != nil && c.Id != nil {
+				message = fmt.Sprintf("%s (%s)", message, aws.StringValue(c.Id))
+				break
+			}
+		}
+		messages = append(messages, message)
+	}
+	return strings.Join(messages, ", ")
+}
@@ -0,0 +1,450 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileAPIServerConnectivity(t *testing.T) {
+	defaultRoute := &ec2.DescribeRouteTablesOutput{
+		RouteTables: []*ec2.RouteTable{{
+			RouteTableId: aws.String("rtb-1"),
+			Routes: []*ec2.Route{
+				{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local"), State: aws.String(ec2.RouteStateActive)},
+				{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1"), State: aws.String(ec2.RouteStateActive)},
+			},
+		}},
+	}
+	lbInterfaces := &ec2.DescribeNetworkInterfacesOutput{
+		NetworkInterfaces: []*ec2.NetworkInterface{{NetworkInterfaceId: aws.String("eni-lb")}},
+	}
+	existingPath := &ec2.DescribeNetworkInsightsPathsOutput{
+		NetworkInsightsPaths: []*ec2.NetworkInsightsPath{{NetworkInsightsPathId: aws.String("nip-1")}},
+	}
+	analyses := func(analyses ...*ec2.NetworkInsightsAnalysis) func(*ec2.DescribeNetworkInsightsAnalysesInput, func(*ec2.DescribeNetworkInsightsAnalysesOutput, bool) bool) error {
+		return func(_ *ec2.DescribeNetworkInsightsAnalysesInput, fn func(*ec2.DescribeNetworkInsightsAnalysesOutput, bool) bool) error {
+			fn(&ec2.DescribeNetworkInsightsAnalysesOutput{NetworkInsightsAnalyses: analyses}, true)
+			return nil
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		lb              infrav1.ClassicELB
+		expect          func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectAnalyzing bool
+		expectStatus    corev1.ConditionStatus
+		expectReason    string
+		expectMessage   string
+	}{
+		{
+			name: "clusters without a load balancer are not checked",
+		},
+		{
+			name: "subnet without a default route",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternetFacing},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRouteTables(gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{}, nil)
+				m.DescribeRouteTables(gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{
+					RouteTables: []*ec2.RouteTable{{RouteTableId: aws.String("rtb-main")}},
+				}, nil)
+			},
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  infrav1.APIServerRouteMissingReason,
+			expectMessage: "Route table rtb-main of subnet subnet-1 has no default route, so the internet-facing API server load balancer cannot be reached",
+		},
+		{
+			name: "subnet with a blackhole default route",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternetFacing},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRouteTables(gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{
+					RouteTables: []*ec2.RouteTable{{
+						RouteTableId: aws.String("rtb-1"),
+						Routes: []*ec2.Route{
+							{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-deleted"), State: aws.String(ec2.RouteStateBlackhole)},
+						},
+					}},
+				}, nil)
+			},
+			expectStatus: corev1.ConditionFalse,
+			expectReason: infrav1.APIServerRouteMissingReason,
+		},
+		{
+			name: "creates a path to the network interface of an internet-facing load balancer and starts an analysis",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternetFacing},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRouteTables(gomock.Any()).Return(defaultRoute, nil)
+				m.DescribeNetworkInterfaces(gomock.Any()).Return(lbInterfaces, nil)
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(&ec2.DescribeNetworkInsightsPathsOutput{}, nil)
+				m.CreateNetworkInsightsPath(gomock.Any()).DoAndReturn(func(input *ec2.CreateNetworkInsightsPathInput) (*ec2.CreateNetworkInsightsPathOutput, error) {
+					if aws.StringValue(input.Source) != "i-1" || aws.StringValue(input.Destination) != "eni-lb" || aws.Int64Value(input.DestinationPort) != 6443 {
+						t.Fatalf("unexpected path %v", input)
+					}
+					return &ec2.CreateNetworkInsightsPathOutput{
+						NetworkInsightsPath: &ec2.NetworkInsightsPath{NetworkInsightsPathId: aws.String("nip-1")},
+					}, nil
+				})
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(analyses())
+				m.StartNetworkInsightsAnalysis(&ec2.StartNetworkInsightsAnalysisInput{NetworkInsightsPathId: aws.String("nip-1")}).
+					Return(&ec2.StartNetworkInsightsAnalysisOutput{}, nil)
+			},
+			expectAnalyzing: true,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    infrav1.ConnectivityAnalysisRunningReason,
+		},
+		{
+			name: "reports the explanations of an analysis which found no path",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternetFacing},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRouteTables(gomock.Any()).Return(defaultRoute, nil)
+				m.DescribeNetworkInterfaces(gomock.Any()).Return(lbInterfaces, nil)
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Minute)),
+					Status:                    aws.String(ec2.AnalysisStatusSucceeded),
+					NetworkPathFound:          aws.Bool(false),
+					Explanations: []*ec2.Explanation{{
+						ExplanationCode: aws.String("ENI_SG_RULES_MISMATCH"),
+						Component:       &ec2.AnalysisComponent{Id: aws.String("eni-1")},
+						SecurityGroup:   &ec2.AnalysisComponent{Id: aws.String("sg-1")},
+					}},
+				}))
+			},
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  infrav1.APIServerUnreachableReason,
+			expectMessage: "No network path from instance i-1 to the API server on port 6443: ENI_SG_RULES_MISMATCH (sg-1)",
+		},
+		{
+			name: "analyzes the path again once the result is old",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternetFacing},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRouteTables(gomock.Any()).Return(defaultRoute, nil)
+				m.DescribeNetworkInterfaces(gomock.Any()).Return(lbInterfaces, nil)
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Hour)),
+					Status:                    aws.String(ec2.AnalysisStatusSucceeded),
+					NetworkPathFound:          aws.Bool(false),
+				}))
+				m.StartNetworkInsightsAnalysis(gomock.Any()).Return(&ec2.StartNetworkInsightsAnalysisOutput{}, nil)
+			},
+			expectAnalyzing: true,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    infrav1.ConnectivityAnalysisRunningReason,
+		},
+		{
+			name: "deletes the path once it was found to an internal load balancer",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternal},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{
+					NetworkInterfaces: []*ec2.NetworkInterface{
+						{NetworkInterfaceId: aws.String("eni-2")},
+						{NetworkInterfaceId: aws.String("eni-1")},
+					},
+				}, nil)
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				found := analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Minute)),
+					Status:                    aws.String(ec2.AnalysisStatusSucceeded),
+					NetworkPathFound:          aws.Bool(true),
+				})
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(found).Times(2)
+				m.DeleteNetworkInsightsAnalysis(&ec2.DeleteNetworkInsightsAnalysisInput{NetworkInsightsAnalysisId: aws.String("nia-1")}).
+					Return(&ec2.DeleteNetworkInsightsAnalysisOutput{}, nil)
+				m.DeleteNetworkInsightsPath(&ec2.DeleteNetworkInsightsPathInput{NetworkInsightsPathId: aws.String("nip-1")}).
+					Return(&ec2.DeleteNetworkInsightsPathOutput{}, nil)
+			},
+			expectStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "reports a failed analysis",
+			lb:   infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternal},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{
+					NetworkInterfaces: []*ec2.NetworkInterface{{NetworkInterfaceId: aws.String("eni-1")}},
+				}, nil)
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Minute)),
+					Status:                    aws.String(ec2.AnalysisStatusFailed),
+					StatusMessage:             aws.String("internal error"),
+				}))
+			},
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  infrav1.ConnectivityCheckFailedReason,
+			expectMessage: "Analysis of the path to the API server failed: internal error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}}
+			awsMachine := &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}}
+			awsCluster := &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-1"}},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{APIServerELB: tc.lb},
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    cluster,
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				Machine:      machine,
+				AWSMachine:   awsMachine,
+				InfraCluster: clusterScope,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tc.expect != nil {
+				tc.expect(ec2Mock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			analyzing, err := s.ReconcileAPIServerConnectivity(machineScope, &infrav1.Instance{ID: "i-1", SubnetID: "subnet-1"})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(analyzing).To(Equal(tc.expectAnalyzing))
+
+			condition := conditions.Get(awsMachine, infrav1.APIServerReachableCondition)
+			if tc.expectStatus == "" {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectReason))
+			if tc.expectMessage != "" {
+				g.Expect(condition.Message).To(Equal(tc.expectMessage))
+			}
+		})
+	}
+}
+
+func TestAnalyzeAPIServerConnectivity(t *testing.T) {
+	existingPath := &ec2.DescribeNetworkInsightsPathsOutput{
+		NetworkInsightsPaths: []*ec2.NetworkInsightsPath{{NetworkInsightsPathId: aws.String("nip-1")}},
+	}
+	analyses := func(analyses ...*ec2.NetworkInsightsAnalysis) func(*ec2.DescribeNetworkInsightsAnalysesInput, func(*ec2.DescribeNetworkInsightsAnalysesOutput, bool) bool) error {
+		return func(_ *ec2.DescribeNetworkInsightsAnalysesInput, fn func(*ec2.DescribeNetworkInsightsAnalysesOutput, bool) bool) error {
+			fn(&ec2.DescribeNetworkInsightsAnalysesOutput{NetworkInsightsAnalyses: analyses}, true)
+			return nil
+		}
+	}
+	deletePath := func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+		m.DeleteNetworkInsightsAnalysis(&ec2.DeleteNetworkInsightsAnalysisInput{NetworkInsightsAnalysisId: aws.String("nia-1")}).
+			Return(&ec2.DeleteNetworkInsightsAnalysisOutput{}, nil)
+		m.DeleteNetworkInsightsPath(&ec2.DeleteNetworkInsightsPathInput{NetworkInsightsPathId: aws.String("nip-1")}).
+			Return(&ec2.DeleteNetworkInsightsPathOutput{}, nil)
+	}
+	lb := infrav1.ClassicELB{Name: "test-apiserver", Scheme: infrav1.ClassicELBSchemeInternetFacing}
+
+	testCases := []struct {
+		name            string
+		lb              infrav1.ClassicELB
+		reachable       bool
+		expect          func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectAnalyzing bool
+		expectStatus    corev1.ConditionStatus
+		expectReason    string
+	}{
+		{
+			name: "clusters without a load balancer are not analyzed",
+		},
+		{
+			name:      "creates a separate path and starts an analysis of a reachable machine",
+			lb:        lb,
+			reachable: true,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(&ec2.DescribeNetworkInsightsPathsOutput{}, nil)
+				m.DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{
+					NetworkInterfaces: []*ec2.NetworkInterface{{NetworkInterfaceId: aws.String("eni-lb")}},
+				}, nil)
+				m.CreateNetworkInsightsPath(gomock.Any()).DoAndReturn(func(input *ec2.CreateNetworkInsightsPathInput) (*ec2.CreateNetworkInsightsPathOutput, error) {
+					if tagList := input.TagSpecifications[0].Tags; !hasTag(tagList, "Name", "test-machine-analysis") {
+						t.Fatalf("unexpected tags %v", tagList)
+					}
+					return &ec2.CreateNetworkInsightsPathOutput{
+						NetworkInsightsPath: &ec2.NetworkInsightsPath{NetworkInsightsPathId: aws.String("nip-1")},
+					}, nil
+				})
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(analyses())
+				m.StartNetworkInsightsAnalysis(&ec2.StartNetworkInsightsAnalysisInput{NetworkInsightsPathId: aws.String("nip-1")}).
+					Return(&ec2.StartNetworkInsightsAnalysisOutput{}, nil)
+			},
+			expectAnalyzing: true,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    infrav1.ConnectivityAnalysisRunningReason,
+		},
+		{
+			name: "waits for a running analysis",
+			lb:   lb,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Hour)),
+					Status:                    aws.String(ec2.AnalysisStatusRunning),
+				}))
+			},
+			expectAnalyzing: true,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    infrav1.ConnectivityAnalysisRunningReason,
+		},
+		{
+			name: "publishes the findings and deletes the path",
+			lb:   lb,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				notFound := analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Hour)),
+					Status:                    aws.String(ec2.AnalysisStatusSucceeded),
+					NetworkPathFound:          aws.Bool(false),
+				})
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(notFound).Times(2)
+				deletePath(m)
+			},
+			expectStatus: corev1.ConditionFalse,
+			expectReason: infrav1.APIServerUnreachableReason,
+		},
+		{
+			name: "marks the machine reachable once a path was found",
+			lb:   lb,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInsightsPaths(gomock.Any()).Return(existingPath, nil)
+				found := analyses(&ec2.NetworkInsightsAnalysis{
+					NetworkInsightsAnalysisId: aws.String("nia-1"),
+					StartDate:                 aws.Time(time.Now().Add(-time.Minute)),
+					Status:                    aws.String(ec2.AnalysisStatusSucceeded),
+					NetworkPathFound:          aws.Bool(true),
+				})
+				m.DescribeNetworkInsightsAnalysesPages(gomock.Any(), gomock.Any()).DoAndReturn(found).Times(2)
+				deletePath(m)
+			},
+			expectStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}}
+			awsMachine := &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine"}}
+			if tc.reachable {
+				conditions.MarkTrue(awsMachine, infrav1.APIServerReachableCondition)
+			}
+			awsCluster := &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-1"}},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{APIServerELB: tc.lb},
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    cluster,
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				Machine:      machine,
+				AWSMachine:   awsMachine,
+				InfraCluster: clusterScope,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tc.expect != nil {
+				tc.expect(ec2Mock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			analyzing, err := s.AnalyzeAPIServerConnectivity(machineScope, &infrav1.Instance{ID: "i-1", SubnetID: "subnet-1"})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(analyzing).To(Equal(tc.expectAnalyzing))
+
+			condition := conditions.Get(awsMachine, infrav1.APIServerReachableCondition)
+			if tc.expectStatus == "" {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectReason))
+		})
+	}
+}
+
+func hasTag(tags []*ec2.Tag, key, value string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key && aws.StringValue(tag.Value) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// GetFleetInstances returns the pending and running instances launched for a machine pool using the EC2Fleet backend.
+func (s *Service) GetFleetInstances(scope *scope.MachinePoolScope) ([]infrav1.Instance, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.Name(scope.Name()),
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning),
+		},
+	}
+
+	instances := []infrav1.Instance{}
+	var convertErr error
+	err := s.EC2Client.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, res := range out.Reservations {
+			for _, inst := range res.Instances {
+				instance, err := s.SDKToInstance(inst)
+				if err != nil {
+					convertErr = err
+					return false
+				}
+				instances = append(instances, *instance)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		record.Eventf(scope.AWSMachinePool, "FailedDescribeInstances", "Failed to describe instances of the pool: %v", err)
+		return nil, errors.Wrapf(err, "failed to describe instances of machine pool %q", scope.Name())
+	}
+	if convertErr != nil {
+		return nil, convertErr
+	}
+	return instances, nil
+}
+
+// CreateFleetInstances launches count instances for a machine pool using the EC2Fleet backend, with an instant
+// EC2 Fleet built from its launch template, subnets and mixed instances policy. The on-demand and spot capacities
+// are chosen so that, together with the existing instances of the pool, they follow the instances distribution.
+// The IDs of the launched instances are returned, and an error if none could be launched.
+func (s *Service) CreateFleetInstances(scope *scope.MachinePoolScope, existing []infrav1.Instance, count int32) ([]string, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	if scope.AWSMachinePool.Status.LaunchTemplateID == "" {
+		return nil, errors.New("launch template of the machine pool is not created yet")
+	}
+
+	subnetIDs, err := scope.SubnetIDs()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get subnets of machine pool %q", scope.Name())
+	}
+	if len(subnetIDs) == 0 {
+		return nil, errors.Errorf("no subnets found for machine pool %q", scope.Name())
+	}
+
+	input := fleetInput(scope.AWSMachinePool, subnetIDs, existing, count)
+	out, err := s.EC2Client.CreateFleet(input)
+	if err != nil {
+		record.Warnf(scope.AWSMachinePool, "FailedCreateFleet", "Failed to create EC2 Fleet: %v", err)
+		return nil, errors.Wrapf(err, "failed to create EC2 Fleet for machine pool %q", scope.Name())
+	}
+
+	ids := []string{}
+	for _, i := range out.Instances {
+		ids = append(ids, aws.StringValueSlice(i.InstanceIds)...)
+	}
+	if len(ids) == 0 {
+		messages := []string{}
+		for _, e := range out.Errors {
+			messages = append(messages, aws.StringValue(e.ErrorCode)+": "+aws.StringValue(e.ErrorMessage))
+		}
+		return nil, errors.Errorf("EC2 Fleet launched no instance for machine pool %q: %s", scope.Name(), strings.Join(messages, "; "))
+	}
+
+	s.scope.V(2).Info("Launched instances with EC2 Fleet", "machine-pool", scope.Name(), "fleet-id", aws.StringValue(out.FleetId), "instance-ids", ids)
+	return ids, nil
+}
+
+// fleetInput builds the instant EC2 Fleet launching count instances of the pool across its subnets.
+func fleetInput(pool *expinfrav1.AWSMachinePool, subnetIDs []string, existing []infrav1.Instance, count int32) *ec2.CreateFleetInput {
+	instanceTypes := []string{}
+	distribution := &expinfrav1.InstancesDistribution{}
+	if policy := pool.Spec.MixedInstancesPolicy; policy != nil {
+		for _, o := range policy.Overrides {
+			instanceTypes = append(instanceTypes, o.InstanceType)
+		}
+		if policy.InstancesDistribution != nil {
+			distribution = policy.InstancesDistribution
+		}
+	}
+
+	overrides := []*ec2.FleetLaunchTemplateOverridesRequest{}
+	for _, subnetID := range subnetIDs {
+		if len(instanceTypes) == 0 {
+			overrides = append(overrides, &ec2.FleetLaunchTemplateOverridesRequest{SubnetId: aws.String(subnetID)})
+			continue
+		}
+		for i, instanceType := range instanceTypes {
+			overrides = append(overrides, &ec2.FleetLaunchTemplateOverridesRequest{
+				SubnetId:     aws.String(subnetID),
+				InstanceType: aws.String(instanceType),
+				Priority:     aws.Float64(float64(i)),
+			})
+		}
+	}
+
+	onDemand := fleetOnDemandCapacity(distribution, existing, count)
+	defaultCapacityType := ec2.DefaultTargetCapacityTypeOnDemand
+	if onDemand == 0 {
+		defaultCapacityType = ec2.DefaultTargetCapacityTypeSpot
+	}
+
+	input := &ec2.CreateFleetInput{
+		Type: aws.String(ec2.FleetTypeInstant),
+		LaunchTemplateConfigs: []*ec2.FleetLaunchTemplateConfigRequest{
+			{
+				LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
+					LaunchTemplateId: aws.String(pool.Status.LaunchTemplateID),
+					Version:          aws.String(expinfrav1.LaunchTemplateLatestVersion),
+				},
+				Overrides: overrides,
+			},
+		},
+		TargetCapacitySpecification: &ec2.TargetCapacitySpecificationRequest{
+			TotalTargetCapacity:       aws.Int64(int64(count)),
+			OnDemandTargetCapacity:    aws.Int64(int64(onDemand)),
+			SpotTargetCapacity:        aws.Int64(int64(count - onDemand)),
+			DefaultTargetCapacityType: aws.String(defaultCapacityType),
+		},
+	}
+	if distribution.OnDemandAllocationStrategy != "" {
+		input.OnDemandOptions = &ec2.OnDemandOptionsRequest{
+			AllocationStrategy: aws.String(string(distribution.OnDemandAllocationStrategy)),
+		}
+	}
+	if distribution.SpotAllocationStrategy != "" {
+		input.SpotOptions = &ec2.SpotOptionsRequest{
+			AllocationStrategy: aws.String(string(distribution.SpotAllocationStrategy)),
+		}
+	}
+	return input
+}
+
+// fleetOnDemandCapacity returns how many of the count instances to launch must be on-demand instances for the pool
+// to follow its instances distribution. Like an ASG, the pool keeps OnDemandBaseCapacity on-demand instances, and
+// OnDemandPercentageAboveBaseCapacity percent of the instances above them, rounded up, are on-demand instances too.
+func fleetOnDemandCapacity(distribution *expinfrav1.InstancesDistribution, existing []infrav1.Instance, count int32) int32 {
+	base := int32(aws.Int64Value(distribution.OnDemandBaseCapacity))
+	percentage := int32(100)
+	if distribution.OnDemandPercentageAboveBaseCapacity != nil {
+		percentage = int32(*distribution.OnDemandPercentageAboveBaseCapacity)
+	}
+
+	existingOnDemand := int32(0)
+	for _, i := range existing {
+		if i.Lifecycle == "" || i.Lifecycle == infrav1.InstanceLifecycleOnDemand {
+			existingOnDemand++
+		}
+	}
+
+	total := int32(len(existing)) + count
+	desired := total
+	if total > base {
+		desired = base + ((total-base)*percentage+99)/100
+	}
+
+	onDemand := desired - existingOnDemand
+	switch {
+	case onDemand < 0:
+		return 0
+	case onDemand > count:
+		return count
+	}
+	return onDemand
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+)
+
+func TestFleetOnDemandCapacity(t *testing.T) {
+	instances := func(onDemand, spot int) []infrav1.Instance {
+		out := []infrav1.Instance{}
+		for i := 0; i < onDemand; i++ {
+			out = append(out, infrav1.Instance{Lifecycle: infrav1.InstanceLifecycleOnDemand})
+		}
+		for i := 0; i < spot; i++ {
+			out = append(out, infrav1.Instance{Lifecycle: infrav1.InstanceLifecycleSpot})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name         string
+		distribution *expinfrav1.InstancesDistribution
+		existing     []infrav1.Instance
+		count        int32
+		want         int32
+	}{
+		{
+			name:         "launches on-demand instances by default",
+			distribution: &expinfrav1.InstancesDistribution{},
+			count:        3,
+			want:         3,
+		},
+		{
+			name: "fills the on-demand base capacity first",
+			distribution: &expinfrav1.InstancesDistribution{
+				OnDemandBaseCapacity:                aws.Int64(2),
+				OnDemandPercentageAboveBaseCapacity: aws.Int64(0),
+			},
+			count: 5,
+			want:  2,
+		},
+		{
+			name: "rounds the on-demand percentage above the base capacity up",
+			distribution: &expinfrav1.InstancesDistribution{
+				OnDemandBaseCapacity:                aws.Int64(1),
+				OnDemandPercentageAboveBaseCapacity: aws.Int64(50),
+			},
+			count: 4,
+			want:  3,
+		},
+		{
+			name: "accounts for the existing instances",
+			distribution: &expinfrav1.InstancesDistribution{
+				OnDemandBaseCapacity:                aws.Int64(2),
+				OnDemandPercentageAboveBaseCapacity: aws.Int64(0),
+			},
+			existing: instances(2, 1),
+			count:    2,
+			want:     0,
+		},
+		{
+			name: "launches the missing on-demand instances of the existing ones",
+			distribution: &expinfrav1.InstancesDistribution{
+				OnDemandPercentageAboveBaseCapacity: aws.Int64(50),
+			},
+			existing: instances(0, 4),
+			count:    2,
+			want:     2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(fleetOnDemandCapacity(tt.distribution, tt.existing, tt.count)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestFleetInput(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &expinfrav1.AWSMachinePool{
+		Spec: expinfrav1.AWSMachinePoolSpec{
+			MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+				InstancesDistribution: &expinfrav1.InstancesDistribution{
+					OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+					SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyCapacityOptimized,
+					OnDemandPercentageAboveBaseCapacity: aws.Int64(0),
+				},
+				Overrides: []expinfrav1.Overrides{{InstanceType: "m5.large"}, {InstanceType: "m5a.large"}},
+			},
+		},
+		Status: expinfrav1.AWSMachinePoolStatus{LaunchTemplateID: "lt-12345"},
+	}
+
+	input := fleetInput(pool, []string{"subnet-1", "subnet-2"}, nil, 3)
+	g.Expect(input).To(Equal(&ec2.CreateFleetInput{
+		Type: aws.String(ec2.FleetTypeInstant),
+		LaunchTemplateConfigs: []*ec2.FleetLaunchTemplateConfigRequest{
+			{
+				LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
+					LaunchTemplateId: aws.String("lt-12345"),
+					Version:          aws.String("$Latest"),
+				},
+				Overrides: []*ec2.FleetLaunchTemplateOverridesRequest{
+					{SubnetId: aws.String("subnet-1"), InstanceType: aws.String("m5.large"), Priority: aws.Float64(0)},
+					{SubnetId: aws.String("subnet-1"), InstanceType: aws.String("m5a.large"), Priority: aws.Float64(1)},
+					{SubnetId: aws.String("subnet-2"), InstanceType: aws.String("m5.large"), Priority: aws.Float64(0)},
+					{SubnetId: aws.String("subnet-2"), InstanceType: aws.String("m5a.large"), Priority: aws.Float64(1)},
+				},
+			},
+		},
+		TargetCapacitySpecification: &ec2.TargetCapacitySpecificationRequest{
+			TotalTargetCapacity:       aws.Int64(3),
+			OnDemandTargetCapacity:    aws.Int64(0),
+			SpotTargetCapacity:        aws.Int64(3),
+			DefaultTargetCapacityType: aws.String(ec2.DefaultTargetCapacityTypeSpot),
+		},
+		OnDemandOptions: &ec2.OnDemandOptionsRequest{AllocationStrategy: aws.String("prioritized")},
+		SpotOptions:     &ec2.SpotOptionsRequest{AllocationStrategy: aws.String("capacity-optimized")},
+	}))
+}
+
+func TestCreateFleetInstances(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  *ec2.CreateFleetOutput
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "returns the launched instances",
+			output: &ec2.CreateFleetOutput{
+				FleetId: aws.String("fleet-1"),
+				Instances: []*ec2.CreateFleetInstance{
+					{InstanceIds: aws.StringSlice([]string{"i-1", "i-2"})},
+					{InstanceIds: aws.StringSlice([]string{"i-3"})},
+				},
+				Errors: []*ec2.CreateFleetError{
+					{ErrorCode: aws.String("InsufficientInstanceCapacity"), ErrorMessage: aws.String("no m5a.large capacity")},
+				},
+			},
+			want: []string{"i-1", "i-2", "i-3"},
+		},
+		{
+			name: "fails when no instance was launched",
+			output: &ec2.CreateFleetOutput{
+				FleetId: aws.String("fleet-1"),
+				Errors: []*ec2.CreateFleetError{
+					{ErrorCode: aws.String("InsufficientInstanceCapacity"), ErrorMessage: aws.String("no capacity")},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			ac := &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			clusterScope := &scope.ClusterScope{Logger: klogr.New(), AWSCluster: ac}
+			machinePoolScope := &scope.MachinePoolScope{
+				Logger:       klogr.New(),
+				InfraCluster: clusterScope,
+				MachinePool:  &expclusterv1.MachinePool{},
+				AWSMachinePool: &expinfrav1.AWSMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+					Spec: expinfrav1.AWSMachinePoolSpec{
+						Subnets: []infrav1.AWSResourceReference{{ID: aws.String("subnet-1")}},
+					},
+					Status: expinfrav1.AWSMachinePoolStatus{LaunchTemplateID: "lt-12345"},
+				},
+			}
+
+			ec2Mock.EXPECT().CreateFleet(gomock.Any()).DoAndReturn(func(input *ec2.CreateFleetInput) (*ec2.CreateFleetOutput, error) {
+				g.Expect(input.TargetCapacitySpecification.TotalTargetCapacity).To(Equal(aws.Int64(3)))
+				g.Expect(input.LaunchTemplateConfigs[0].Overrides).To(ConsistOf(&ec2.FleetLaunchTemplateOverridesRequest{SubnetId: aws.String("subnet-1")}))
+				return tt.output, nil
+			})
+
+			s := &Service{scope: clusterScope, EC2Client: ec2Mock}
+
+			ids, err := s.CreateFleetInstances(machinePoolScope, nil, 3)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ids).To(Equal(tt.want))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +29,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
@@ -104,16 +107,134 @@ func (s *Service) InstanceIfExists(id *string) (*infrav1.Instance, error) {
 	return nil, nil
 }
 
+// GetInstancesByIDs returns the instances with the given IDs.
+func (s *Service) GetInstancesByIDs(ids []string) ([]infrav1.Instance, error) {
+	instances := []infrav1.Instance{}
+	if len(ids) == 0 {
+		return instances, nil
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(ids),
+	}
+
+	var convertErr error
+	err := s.EC2Client.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, res := range out.Reservations {
+			for _, inst := range res.Instances {
+				instance, err := s.SDKToInstance(inst)
+				if err != nil {
+					convertErr = err
+					return false
+				}
+				instances = append(instances, *instance)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeInstances", "Failed to describe instances %v: %v", ids, err)
+		return nil, errors.Wrapf(err, "failed to describe instances %v", ids)
+	}
+	if convertErr != nil {
+		return nil, convertErr
+	}
+	return instances, nil
+}
+
+// AdoptInstance brings an existing instance under the management of the machine instead of launching a new one.
+// The instance must live in the VPC of the cluster, must not be shutting down or terminated, and must not be owned by
+// another cluster or machine. It is then tagged exactly as if it had been launched for the machine.
+func (s *Service) AdoptInstance(scope *scope.MachineScope, id string) (*infrav1.Instance, error) {
+	s.scope.V(2).Info("Adopting existing instance for machine", "instance-id", id)
+
+	out, err := s.EC2Client.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	})
+	switch {
+	case awserrors.IsNotFound(err):
+		return nil, errors.Errorf("instance %q to adopt does not exist", id)
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to describe instance %q", id)
+	}
+	if len(out.Reservations) == 0 || len(out.Reservations[0].Instances) == 0 {
+		return nil, errors.Errorf("instance %q to adopt does not exist", id)
+	}
+	v := out.Reservations[0].Instances[0]
+
+	if vpcID := aws.StringValue(v.VpcId); vpcID != s.scope.VPC().ID {
+		return nil, errors.Errorf("instance %q is in vpc %q, not in vpc %q of the cluster", id, vpcID, s.scope.VPC().ID)
+	}
+
+	instance, err := s.SDKToInstance(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if !infrav1.InstanceOperationalStates.Has(string(instance.State)) {
+		return nil, errors.Errorf("instance %q can't be adopted in state %q", id, instance.State)
+	}
+
+	if err := s.checkInstanceAdoptable(scope, instance); err != nil {
+		return nil, err
+	}
+
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(scope.Name()),
+		Role:        aws.String(scope.Role()),
+		Additional:  scope.AdditionalTags(),
+	}.WithCloudProvider(s.scope.Name()).WithMachineName(scope.Machine))
+
+	if err := s.UpdateResourceTags(aws.String(id), tags, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to tag instance %q", id)
+	}
+	if instance.Tags == nil {
+		instance.Tags = map[string]string{}
+	}
+	for key, value := range tags {
+		instance.Tags[key] = value
+	}
+
+	return instance, nil
+}
+
+// checkInstanceAdoptable returns an error when the tags of the instance show it belongs to another cluster, or to
+// another machine of this cluster.
+func (s *Service) checkInstanceAdoptable(scope *scope.MachineScope, instance *infrav1.Instance) error {
+	for key, value := range instance.Tags {
+		if infrav1.ResourceLifecycle(value) != infrav1.ResourceLifecycleOwned {
+			continue
+		}
+		if key == infrav1.ClusterTagKey(s.scope.Name()) || key == infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name()) {
+			continue
+		}
+		if strings.HasPrefix(key, infrav1.NameAWSProviderOwned) || strings.HasPrefix(key, infrav1.NameKubernetesAWSCloudProviderPrefix) {
+			return errors.Errorf("instance %q is owned by another cluster (tag %q)", instance.ID, key)
+		}
+	}
+
+	machineName := types.NamespacedName{Namespace: scope.Machine.Namespace, Name: scope.Machine.Name}.String()
+	if owner, ok := instance.Tags[infrav1.MachineNameTagKey]; ok && owner != machineName {
+		return errors.Errorf("instance %q belongs to machine %q", instance.ID, owner)
+	}
+
+	return nil
+}
+
 // CreateInstance runs an ec2 instance.
 func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*infrav1.Instance, error) {
 	s.scope.V(2).Info("Creating an instance for a machine")
 
 	input := &infrav1.Instance{
-		Type:              scope.AWSMachine.Spec.InstanceType,
-		IAMProfile:        scope.AWSMachine.Spec.IAMInstanceProfile,
-		RootVolume:        scope.AWSMachine.Spec.RootVolume,
-		NonRootVolumes:    scope.AWSMachine.Spec.NonRootVolumes,
-		NetworkInterfaces: scope.AWSMachine.Spec.NetworkInterfaces,
+		Type:                    scope.AWSMachine.Spec.InstanceType,
+		IAMProfile:              scope.IAMInstanceProfile(),
+		RootVolume:              scope.RootVolume(),
+		NonRootVolumes:          scope.AWSMachine.Spec.NonRootVolumes,
+		NetworkInterfaces:       scope.AWSMachine.Spec.NetworkInterfaces,
+		PrivateIP:               scope.PrivateIP(),
+		NetworkInterfaceConfigs: scope.AWSMachine.Spec.NetworkInterfaceConfigs,
 	}
 
 	// Make sure to use the MachineScope here to get the merger of AWSCluster and AWSMachine tags
@@ -154,13 +275,14 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 			imageLookupBaseOS = scope.InfraCluster.ImageLookupBaseOS()
 		}
 
-		if scope.IsEKSManaged() && imageLookupFormat == "" && imageLookupOrg == "" && imageLookupBaseOS == "" {
-			input.ImageID, err = s.eksAMILookup(*scope.Machine.Spec.Version, scope.AWSMachine.Spec.AMI.EKSOptimizedLookupType)
+		osFamily := scope.AWSMachine.Spec.AMI.OSFamily
+		if useSSMAMILookup(scope.IsEKSManaged(), osFamily, imageLookupFormat, imageLookupOrg, imageLookupBaseOS) {
+			input.ImageID, err = s.eksAMILookup(*scope.Machine.Spec.Version, osFamily, eksAMILookupType(scope.AWSMachine.Spec.AMI.EKSOptimizedLookupType, scope.AWSMachine.Spec.GPU, scope.AWSMachine.Spec.InstanceType))
 			if err != nil {
 				return nil, err
 			}
 		} else {
-			input.ImageID, err = s.defaultAMIIDLookup(imageLookupFormat, imageLookupOrg, imageLookupBaseOS, *scope.Machine.Spec.Version)
+			input.ImageID, err = s.defaultAMIIDLookup(imageLookupFormat, imageLookupOrg, amiLookupBaseOS(osFamily, imageLookupBaseOS), *scope.Machine.Spec.Version)
 			if err != nil {
 				return nil, err
 			}
@@ -173,6 +295,15 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	}
 	input.SubnetID = subnetID
 
+	if input.PrivateIP != nil {
+		if subnet := s.scope.Subnets().FindByID(subnetID); subnet != nil && subnet.CidrBlock != "" {
+			if err := infrav1.ValidatePrivateIPInSubnet(*input.PrivateIP, subnet.CidrBlock); err != nil {
+				record.Warnf(scope.AWSMachine, "FailedCreate", "Failed to create instance: private IP %v", err)
+				return nil, errors.Wrapf(err, "failed to run machine %q, invalid private IP", scope.Name())
+			}
+		}
+	}
+
 	if !scope.IsExternallyManaged() && !scope.IsEKSManaged() && s.scope.Network().APIServerELB.DNSName == "" {
 		record.Eventf(s.scope.InfraCluster(), "FailedCreateInstance", "Failed to run controlplane, APIServer ELB not available")
 
@@ -220,12 +351,30 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		input.SSHKeyName = aws.String(prioritizedSSHKeyName)
 	}
 
-	input.SpotMarketOptions = scope.AWSMachine.Spec.SpotMarketOptions
+	input.SpotMarketOptions = scope.SpotMarketOptions()
 
 	input.Tenancy = scope.AWSMachine.Spec.Tenancy
 
-	s.scope.V(2).Info("Running instance", "machine-role", scope.Role())
-	out, err := s.runInstance(scope.Role(), input)
+	input.HostResourceGroupARN = aws.StringValue(scope.AWSMachine.Spec.HostResourceGroupARN)
+
+	input.LicenseConfigurationARNs = scope.AWSMachine.Spec.LicenseConfigurationARNs
+
+	input.CPUOptions = scope.AWSMachine.Spec.CPUOptions
+
+	input.InstanceMetadataOptions = scope.InstanceMetadataOptions()
+
+	// Try the fallback instance types in order when EC2 cannot launch the preferred one.
+	instanceTypes := append([]string{input.Type}, scope.AWSMachine.Spec.FallbackInstanceTypes...)
+	var out *infrav1.Instance
+	for i, instanceType := range instanceTypes {
+		input.Type = instanceType
+		s.scope.V(2).Info("Running instance", "machine-role", scope.Role(), "instance-type", instanceType)
+		out, err = s.runInstance(scope.Role(), input)
+		if err == nil || i == len(instanceTypes)-1 || !awserrors.IsInstanceTypeUnavailableError(errors.Cause(err)) {
+			break
+		}
+		record.Warnf(scope.AWSMachine, "InstanceTypeUnavailable", "Instance type %q is unavailable, trying instance type %q: %v", instanceType, instanceTypes[i+1], errors.Cause(err))
+	}
 	if err != nil {
 		// Only record the failure event if the error is not related to failed dependencies.
 		// This is to avoid spamming failure events since the machine will be requeued by the actuator.
@@ -275,7 +424,7 @@ func (s *Service) findSubnet(scope *scope.MachineScope) (string, error) {
 				)
 			}
 
-			if subnet.AvailabilityZone != *failureDomain {
+			if !subnet.IsInZone(*failureDomain) {
 				record.Warnf(scope.AWSMachine, "FailedCreate",
 					"Failed to create instance: subnet's availability zone %q does not match with the failure domain %q",
 					subnet.AvailabilityZone,
@@ -298,7 +447,11 @@ func (s *Service) findSubnet(scope *scope.MachineScope) (string, error) {
 			criteria = append(criteria, filter.EC2.VPC(s.scope.VPC().ID))
 		}
 		if failureDomain != nil {
-			criteria = append(criteria, filter.EC2.AvailabilityZone(*failureDomain))
+			if infrav1.IsAvailabilityZoneID(*failureDomain) {
+				criteria = append(criteria, filter.EC2.AvailabilityZoneID(*failureDomain))
+			} else {
+				criteria = append(criteria, filter.EC2.AvailabilityZone(*failureDomain))
+			}
 		}
 		for _, f := range scope.AWSMachine.Spec.Subnet.Filters {
 			criteria = append(criteria, &ec2.Filter{Name: aws.String(f.Name), Values: aws.StringSlice(f.Values)})
@@ -343,10 +496,56 @@ func (s *Service) findSubnet(scope *scope.MachineScope) (string, error) {
 			record.Eventf(s.scope.InfraCluster(), "FailedCreateInstance", "Failed to run machine %q, no subnets available", scope.Name())
 			return "", awserrors.NewFailedDependency(fmt.Sprintf("failed to run machine %q, no subnets available", scope.Name()))
 		}
+		if scope.IsControlPlane() {
+			return s.findControlPlaneSubnet(scope, sns)
+		}
 		return sns[0].ID, nil
 	}
 }
 
+// findControlPlaneSubnet returns the first of the given subnets in the availability zone with the fewest control
+// plane instances of the cluster. Control plane machines without a failure domain are spread across the zones
+// this way, so that no zone runs more than ceil(n/zones) of n control plane instances and losing a zone doesn't
+// lose the etcd quorum.
+func (s *Service) findControlPlaneSubnet(scope *scope.MachineScope, subnets infrav1.Subnets) (string, error) {
+	if len(subnets.GetUniqueZones()) < 2 {
+		return subnets[0].ID, nil
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.ProviderRole(scope.Role()),
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning),
+		},
+	}
+	out, err := s.EC2Client.DescribeInstances(input)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeInstances", "Failed to describe control plane instances: %v", err)
+		return "", errors.Wrap(err, "failed to describe control plane instances")
+	}
+
+	instancesPerZone := map[string]int{}
+	for _, res := range out.Reservations {
+		for _, inst := range res.Instances {
+			if inst.Placement != nil {
+				instancesPerZone[aws.StringValue(inst.Placement.AvailabilityZone)]++
+			}
+		}
+	}
+
+	subnet := subnets[0]
+	for _, sn := range subnets[1:] {
+		if instancesPerZone[sn.AvailabilityZone] < instancesPerZone[subnet.AvailabilityZone] {
+			subnet = sn
+		}
+	}
+	scope.V(2).Info("Spreading control plane machine without failure domain across availability zones",
+		"availability-zone", subnet.AvailabilityZone, "control-plane-instances", instancesPerZone[subnet.AvailabilityZone])
+	return subnet.ID, nil
+}
+
 // getFilteredSubnets fetches subnets filtered based on the criteria passed.
 func (s *Service) getFilteredSubnets(criteria ...*ec2.Filter) ([]*ec2.Subnet, error) {
 	out, err := s.EC2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: criteria})
@@ -359,10 +558,6 @@ func (s *Service) getFilteredSubnets(criteria ...*ec2.Filter) ([]*ec2.Subnet, er
 // GetCoreSecurityGroups looks up the security group IDs managed by this actuator
 // They are considered "core" to its proper functioning.
 func (s *Service) GetCoreSecurityGroups(scope *scope.MachineScope) ([]string, error) {
-	if scope.IsExternallyManaged() {
-		return nil, nil
-	}
-
 	// These are common across both controlplane and node machines
 	sgRoles := []infrav1.SecurityGroupRole{
 		infrav1.SecurityGroupNode,
@@ -386,6 +581,10 @@ func (s *Service) GetCoreSecurityGroups(scope *scope.MachineScope) ([]string, er
 	ids := make([]string, 0, len(sgRoles))
 	for _, sg := range sgRoles {
 		if _, ok := s.scope.SecurityGroups()[sg]; !ok {
+			// Externally managed clusters only provide the security groups that could be discovered.
+			if scope.IsExternallyManaged() {
+				continue
+			}
 			return nil, awserrors.NewFailedDependency(fmt.Sprintf("%s security group not available", sg))
 		}
 		ids = append(ids, s.scope.SecurityGroups()[sg].ID)
@@ -473,15 +672,33 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		netInterfaces := make([]*ec2.InstanceNetworkInterfaceSpecification, 0, len(i.NetworkInterfaces))
 
 		for index, id := range i.NetworkInterfaces {
-			netInterfaces = append(netInterfaces, &ec2.InstanceNetworkInterfaceSpecification{
+			netInterface := &ec2.InstanceNetworkInterfaceSpecification{
 				NetworkInterfaceId: aws.String(id),
 				DeviceIndex:        aws.Int64(int64(index)),
-			})
+			}
+			for _, config := range i.NetworkInterfaceConfigs {
+				if config.NetworkInterfaceID != id {
+					continue
+				}
+				netInterface.NetworkCardIndex = config.NetworkCardIndex
+				if srd := config.EnaSrdSpecification; srd != nil {
+					netInterface.EnaSrdSpecification = &ec2.EnaSrdSpecificationRequest{
+						EnaSrdEnabled: srd.EnaSrdEnabled,
+					}
+					if srd.EnaSrdUDPEnabled != nil {
+						netInterface.EnaSrdSpecification.EnaSrdUdpSpecification = &ec2.EnaSrdUdpSpecificationRequest{
+							EnaSrdUdpEnabled: srd.EnaSrdUDPEnabled,
+						}
+					}
+				}
+			}
+			netInterfaces = append(netInterfaces, netInterface)
 		}
 
 		input.NetworkInterfaces = netInterfaces
 	} else {
 		input.SubnetId = aws.String(i.SubnetID)
+		input.PrivateIpAddress = i.PrivateIP
 
 		if len(i.SecurityGroupIDs) > 0 {
 			input.SecurityGroupIds = aws.StringSlice(i.SecurityGroupIDs)
@@ -589,6 +806,23 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		}
 	}
 
+	if i.HostResourceGroupARN != "" {
+		if input.Placement == nil {
+			input.Placement = &ec2.Placement{}
+		}
+		input.Placement.HostResourceGroupArn = aws.String(i.HostResourceGroupARN)
+	}
+
+	for _, arn := range i.LicenseConfigurationARNs {
+		input.LicenseSpecifications = append(input.LicenseSpecifications, &ec2.LicenseConfigurationRequest{
+			LicenseConfigurationArn: aws.String(arn),
+		})
+	}
+
+	input.CpuOptions = getCPUOptionsRequest(i.CPUOptions)
+
+	input.MetadataOptions = getInstanceMetadataOptionsRequest(i.InstanceMetadataOptions)
+
 	out, err := s.EC2Client.RunInstances(input)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to run instance")
@@ -598,7 +832,7 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		return nil, errors.Errorf("no instance returned for reservation %v", out.GoString())
 	}
 
-	waitTimeout := 1 * time.Minute
+	waitTimeout := s.scope.InstanceRunningTimeout()
 	s.scope.V(2).Info("Waiting for instance to be in running state", "instance-id", *out.Instances[0].InstanceId, "timeout", waitTimeout.String())
 	ctx, cancel := context.WithTimeout(aws.BackgroundContext(), waitTimeout)
 	defer cancel()
@@ -633,6 +867,150 @@ func (s *Service) GetInstanceSecurityGroups(instanceID string) (map[string][]str
 	return out, nil
 }
 
+// GetInstanceScheduledEvents returns the scheduled events of the given EC2 instance which have
+// neither completed nor been canceled, ordered by their earliest start time.
+func (s *Service) GetInstanceScheduledEvents(instanceID string) ([]infrav1.InstanceScheduledEvent, error) {
+	out, err := s.EC2Client.DescribeInstanceStatus(&ec2.DescribeInstanceStatusInput{
+		InstanceIds:         []*string{aws.String(instanceID)},
+		IncludeAllInstances: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe status of instance %q", instanceID)
+	}
+
+	events := []infrav1.InstanceScheduledEvent{}
+	for _, status := range out.InstanceStatuses {
+		for _, e := range status.Events {
+			description := aws.StringValue(e.Description)
+			// EC2 keeps finished events around for a while and marks them in the description.
+			if strings.HasPrefix(description, "[Completed]") || strings.HasPrefix(description, "[Canceled]") {
+				continue
+			}
+			event := infrav1.InstanceScheduledEvent{
+				Code:        aws.StringValue(e.Code),
+				Description: description,
+			}
+			if e.NotBefore != nil {
+				notBefore := metav1.NewTime(*e.NotBefore)
+				event.NotBefore = &notBefore
+			}
+			if e.NotAfter != nil {
+				notAfter := metav1.NewTime(*e.NotAfter)
+				event.NotAfter = &notAfter
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].NotBefore == nil {
+			return false
+		}
+		if events[j].NotBefore == nil {
+			return true
+		}
+		return events[i].NotBefore.Before(events[j].NotBefore)
+	})
+
+	return events, nil
+}
+
+// GetInstanceSystemStatus returns the result of the system status checks of the given EC2 instance, which
+// monitor the host the instance runs on. It returns nil when EC2 reports no status for the instance.
+func (s *Service) GetInstanceSystemStatus(instanceID string) (*ec2.InstanceStatusSummary, error) {
+	out, err := s.EC2Client.DescribeInstanceStatus(&ec2.DescribeInstanceStatusInput{
+		InstanceIds:         []*string{aws.String(instanceID)},
+		IncludeAllInstances: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe status of instance %q", instanceID)
+	}
+
+	for _, status := range out.InstanceStatuses {
+		if aws.StringValue(status.InstanceId) == instanceID {
+			return status.SystemStatus, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetInstanceTypeInfo returns the hardware description of the given instance type.
+func (s *Service) GetInstanceTypeInfo(instanceType string) (*ec2.InstanceTypeInfo, error) {
+	out, err := s.EC2Client.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+
+	if len(out.InstanceTypes) == 0 {
+		return nil, awserrors.NewNotFound(fmt.Sprintf("instance type %q not found", instanceType))
+	}
+
+	return out.InstanceTypes[0], nil
+}
+
+// GetInstanceTypeZones returns the availability zones of the region where the given instance type is offered.
+func (s *Service) GetInstanceTypeZones(instanceType string) (map[string]bool, error) {
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: aws.StringSlice([]string{instanceType}),
+			},
+		},
+	}
+
+	zones := map[string]bool{}
+	if err := s.EC2Client.DescribeInstanceTypeOfferingsPages(input, func(out *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+		for _, offering := range out.InstanceTypeOfferings {
+			zones[aws.StringValue(offering.Location)] = true
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe the offerings of instance type %q", instanceType)
+	}
+
+	return zones, nil
+}
+
+// GetSpotPrice returns the current spot price of the given instance type for Linux instances in the
+// given availability zone. Without an availability zone the lowest price in the region is returned.
+func (s *Service) GetSpotPrice(instanceType, availabilityZone string) (float64, error) {
+	input := &ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []*string{aws.String(instanceType)},
+		ProductDescriptions: []*string{aws.String("Linux/UNIX")},
+		// The results include the price in effect at the start time.
+		StartTime: aws.Time(time.Now()),
+	}
+	if availabilityZone != "" {
+		input.AvailabilityZone = aws.String(availabilityZone)
+	}
+
+	price := -1.0
+	if err := s.EC2Client.DescribeSpotPriceHistoryPages(input, func(out *ec2.DescribeSpotPriceHistoryOutput, lastPage bool) bool {
+		for _, p := range out.SpotPriceHistory {
+			v, err := strconv.ParseFloat(aws.StringValue(p.SpotPrice), 64)
+			if err != nil {
+				continue
+			}
+			if price < 0 || v < price {
+				price = v
+			}
+		}
+		return true
+	}); err != nil {
+		return 0, errors.Wrapf(err, "failed to describe spot price history of instance type %q", instanceType)
+	}
+
+	if price < 0 {
+		return 0, awserrors.NewNotFound(fmt.Sprintf("no spot price found for instance type %q", instanceType))
+	}
+
+	return price, nil
+}
+
 // UpdateInstanceSecurityGroups modifies the security groups of the given
 // EC2 instance.
 func (s *Service) UpdateInstanceSecurityGroups(instanceID string, ids []string) error {
@@ -720,7 +1098,9 @@ func (s *Service) getInstanceENIs(instanceID string) ([]*ec2.NetworkInterface, e
 	return output.NetworkInterfaces, nil
 }
 
-func (s *Service) getImageRootDevice(imageID string) (*string, error) {
+// getImageRootDevice returns the block device mapping of the root device of an image. Images name their root
+// device differently, e.g. /dev/xvda or /dev/sda1, and may list other devices before it.
+func (s *Service) getImageRootDevice(imageID string) (*ec2.BlockDeviceMapping, error) {
 	input := &ec2.DescribeImagesInput{
 		ImageIds: []*string{aws.String(imageID)},
 	}
@@ -734,24 +1114,14 @@ func (s *Service) getImageRootDevice(imageID string) (*string, error) {
 		return nil, errors.Errorf("no images returned when looking up ID %q", imageID)
 	}
 
-	return output.Images[0].RootDeviceName, nil
-}
-
-func (s *Service) getImageSnapshotSize(imageID string) (*int64, error) {
-	input := &ec2.DescribeImagesInput{
-		ImageIds: []*string{aws.String(imageID)},
-	}
-
-	output, err := s.EC2Client.DescribeImages(input)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(output.Images) == 0 {
-		return nil, errors.Errorf("no images returned when looking up ID %q", imageID)
+	image := output.Images[0]
+	for _, mapping := range image.BlockDeviceMappings {
+		if aws.StringValue(mapping.DeviceName) == aws.StringValue(image.RootDeviceName) {
+			return mapping, nil
+		}
 	}
 
-	return output.Images[0].BlockDeviceMappings[0].Ebs.VolumeSize, nil
+	return nil, errors.Errorf("image %q has no block device mapping for its root device %q", imageID, aws.StringValue(image.RootDeviceName))
 }
 
 // SDKToInstance converts an AWS EC2 SDK instance to the CAPA instance type.
@@ -772,6 +1142,10 @@ func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 		EBSOptimized: v.EbsOptimized,
 	}
 
+	if v.StateReason != nil {
+		i.StateReason = aws.StringValue(v.StateReason.Code)
+	}
+
 	// Extract IAM Instance Profile name from ARN
 	// TODO: Handle this comparison more safely, perhaps by querying IAM for the
 	// instance profile ARN and comparing to the ARN returned by EC2
@@ -793,11 +1167,51 @@ func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 	i.Addresses = s.getInstanceAddresses(v)
 
 	i.AvailabilityZone = aws.StringValue(v.Placement.AvailabilityZone)
+	i.HostID = aws.StringValue(v.Placement.HostId)
+	i.HostResourceGroupARN = aws.StringValue(v.Placement.HostResourceGroupArn)
+
+	for _, license := range v.Licenses {
+		i.LicenseConfigurationARNs = append(i.LicenseConfigurationARNs, aws.StringValue(license.LicenseConfigurationArn))
+	}
+
+	if v.CpuOptions != nil {
+		i.CPUOptions = &infrav1.CPUOptions{
+			CoreCount:      int32Ptr(v.CpuOptions.CoreCount),
+			ThreadsPerCore: int32Ptr(v.CpuOptions.ThreadsPerCore),
+		}
+	}
+
+	if v.MetadataOptions != nil {
+		i.InstanceMetadataOptions = &infrav1.InstanceMetadataOptions{
+			HTTPEndpoint:            aws.StringValue(v.MetadataOptions.HttpEndpoint),
+			HTTPTokens:              aws.StringValue(v.MetadataOptions.HttpTokens),
+			HTTPPutResponseHopLimit: aws.Int64Value(v.MetadataOptions.HttpPutResponseHopLimit),
+		}
+	}
 
 	for _, volume := range v.BlockDeviceMappings {
 		i.VolumeIDs = append(i.VolumeIDs, *volume.Ebs.VolumeId)
 	}
 
+	if v.LaunchTime != nil {
+		launchTime := metav1.NewTime(*v.LaunchTime)
+		i.LaunchTime = &launchTime
+	}
+
+	// EC2 only reports a lifecycle for spot and scheduled instances.
+	i.Lifecycle = infrav1.InstanceLifecycleOnDemand
+	if v.InstanceLifecycle != nil {
+		i.Lifecycle = infrav1.InstanceLifecycle(*v.InstanceLifecycle)
+	}
+	i.SpotInstanceRequestID = aws.StringValue(v.SpotInstanceRequestId)
+
+	for _, eni := range v.NetworkInterfaces {
+		if eni.Attachment != nil && aws.Int64Value(eni.Attachment.DeviceIndex) == 0 {
+			i.PrimaryNetworkInterfaceID = aws.StringValue(eni.NetworkInterfaceId)
+			break
+		}
+	}
+
 	return i, nil
 }
 
@@ -909,21 +1323,20 @@ func (s *Service) DetachSecurityGroupsFromNetworkInterface(groups []string, inte
 // checkRootVolume checks the input root volume options against the requested AMI's defaults
 // and returns the AMI's root device name.
 func (s *Service) checkRootVolume(rootVolume *infrav1.Volume, imageID string) (*string, error) {
-	rootDeviceName, err := s.getImageRootDevice(imageID)
+	rootDevice, err := s.getImageRootDevice(imageID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get root volume from image %q", imageID)
 	}
 
-	snapshotSize, err := s.getImageSnapshotSize(imageID)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get root volume from image %q", imageID)
+	if rootDevice.Ebs == nil {
+		return nil, errors.Errorf("root device %q of image %q is not an EBS volume", aws.StringValue(rootDevice.DeviceName), imageID)
 	}
 
-	if rootVolume.Size < *snapshotSize {
-		return nil, errors.Errorf("root volume size (%d) must be greater than or equal to snapshot size (%d)", rootVolume.Size, *snapshotSize)
+	if snapshotSize := aws.Int64Value(rootDevice.Ebs.VolumeSize); rootVolume.Size < snapshotSize {
+		return nil, errors.Errorf("root volume size (%d) must be greater than or equal to snapshot size (%d)", rootVolume.Size, snapshotSize)
 	}
 
-	return rootDeviceName, nil
+	return rootDevice.DeviceName, nil
 }
 
 // filterGroups filters a list for a string.
@@ -946,6 +1359,46 @@ func containsGroup(list []string, strToSearch string) bool {
 	return false
 }
 
+func getCPUOptionsRequest(cpuOptions *infrav1.CPUOptions) *ec2.CpuOptionsRequest {
+	if cpuOptions == nil || (cpuOptions.CoreCount == nil && cpuOptions.ThreadsPerCore == nil) {
+		return nil
+	}
+
+	request := &ec2.CpuOptionsRequest{}
+	if cpuOptions.CoreCount != nil {
+		request.CoreCount = aws.Int64(int64(*cpuOptions.CoreCount))
+	}
+	if cpuOptions.ThreadsPerCore != nil {
+		request.ThreadsPerCore = aws.Int64(int64(*cpuOptions.ThreadsPerCore))
+	}
+	return request
+}
+
+func getInstanceMetadataOptionsRequest(options *infrav1.InstanceMetadataOptions) *ec2.InstanceMetadataOptionsRequest {
+	if options == nil || (options.HTTPEndpoint == "" && options.HTTPTokens == "" && options.HTTPPutResponseHopLimit == 0) {
+		return nil
+	}
+
+	request := &ec2.InstanceMetadataOptionsRequest{}
+	if options.HTTPEndpoint != "" {
+		request.HttpEndpoint = aws.String(options.HTTPEndpoint)
+	}
+	if options.HTTPTokens != "" {
+		request.HttpTokens = aws.String(options.HTTPTokens)
+	}
+	if options.HTTPPutResponseHopLimit != 0 {
+		request.HttpPutResponseHopLimit = aws.Int64(options.HTTPPutResponseHopLimit)
+	}
+	return request
+}
+
+func int32Ptr(v *int64) *int32 {
+	if v == nil {
+		return nil
+	}
+	return pointer.Int32Ptr(int32(*v))
+}
+
 func getInstanceMarketOptionsRequest(spotMarketOptions *infrav1.SpotMarketOptions) *ec2.InstanceMarketOptionsRequest {
 	if spotMarketOptions == nil {
 		// Instance is not a Spot instance
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
@@ -143,6 +145,81 @@ func TestInstanceIfExists(t *testing.T) {
 				if instance.ID != "id-1" {
 					t.Fatalf("expected id-1 but got: %v", instance.ID)
 				}
+
+				if instance.Lifecycle != infrav1.InstanceLifecycleOnDemand {
+					t.Fatalf("expected on-demand lifecycle but got: %v", instance.Lifecycle)
+				}
+			},
+		},
+		{
+			name:       "spot instance exists",
+			instanceID: "id-2",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{
+					InstanceIds: []*string{aws.String("id-2")},
+				})).
+					Return(&ec2.DescribeInstancesOutput{
+						Reservations: []*ec2.Reservation{
+							{
+								Instances: []*ec2.Instance{
+									{
+										InstanceId:            aws.String("id-2"),
+										InstanceType:          aws.String("m5.large"),
+										SubnetId:              aws.String("subnet-1"),
+										ImageId:               aws.String("ami-1"),
+										InstanceLifecycle:     aws.String(ec2.InstanceLifecycleTypeSpot),
+										SpotInstanceRequestId: aws.String("sir-1"),
+										LaunchTime:            aws.Time(time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)),
+										State: &ec2.InstanceState{
+											Code: aws.Int64(16),
+											Name: aws.String(ec2.StateAvailable),
+										},
+										StateReason: &ec2.StateReason{
+											Code: aws.String("Server.SpotInstanceTermination"),
+										},
+										NetworkInterfaces: []*ec2.InstanceNetworkInterface{
+											{
+												NetworkInterfaceId: aws.String("eni-2"),
+												Attachment:         &ec2.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int64(1)},
+											},
+											{
+												NetworkInterfaceId: aws.String("eni-1"),
+												Attachment:         &ec2.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int64(0)},
+											},
+										},
+										Placement: &ec2.Placement{
+											AvailabilityZone: aws.String("test-zone-1a"),
+											HostId:           aws.String("h-1"),
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if instance.Lifecycle != infrav1.InstanceLifecycleSpot {
+					t.Fatalf("expected spot lifecycle but got: %v", instance.Lifecycle)
+				}
+				if instance.StateReason != "Server.SpotInstanceTermination" {
+					t.Fatalf("expected spot termination state reason but got: %v", instance.StateReason)
+				}
+				if instance.SpotInstanceRequestID != "sir-1" {
+					t.Fatalf("expected spot request sir-1 but got: %v", instance.SpotInstanceRequestID)
+				}
+				if instance.HostID != "h-1" {
+					t.Fatalf("expected host h-1 but got: %v", instance.HostID)
+				}
+				if instance.PrimaryNetworkInterfaceID != "eni-1" {
+					t.Fatalf("expected primary network interface eni-1 but got: %v", instance.PrimaryNetworkInterfaceID)
+				}
+				if instance.LaunchTime == nil || !instance.LaunchTime.Time.Equal(time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)) {
+					t.Fatalf("expected launch time to be set but got: %v", instance.LaunchTime)
+				}
 			},
 		},
 		{
@@ -270,6 +347,339 @@ func TestTerminateInstance(t *testing.T) {
 	}
 }
 
+func TestGetInstanceScheduledEvents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	early := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2021, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		events        []*ec2.InstanceStatusEvent
+		err           error
+		expectedCodes []string
+		expectErr     bool
+	}{
+		{
+			name:          "no scheduled events",
+			expectedCodes: []string{},
+		},
+		{
+			name: "completed and canceled events are ignored",
+			events: []*ec2.InstanceStatusEvent{
+				{Code: aws.String("system-reboot"), Description: aws.String("[Completed] scheduled reboot"), NotBefore: aws.Time(early)},
+				{Code: aws.String("instance-stop"), Description: aws.String("[Canceled] scheduled stop"), NotBefore: aws.Time(early)},
+				{Code: aws.String("instance-retirement"), Description: aws.String("The instance is running on degraded hardware"), NotBefore: aws.Time(late)},
+			},
+			expectedCodes: []string{"instance-retirement"},
+		},
+		{
+			name: "events are ordered by their earliest start time",
+			events: []*ec2.InstanceStatusEvent{
+				{Code: aws.String("instance-retirement"), NotBefore: aws.Time(late)},
+				{Code: aws.String("system-maintenance")},
+				{Code: aws.String("system-reboot"), NotBefore: aws.Time(early)},
+			},
+			expectedCodes: []string{"system-reboot", "instance-retirement", "system-maintenance"},
+		},
+		{
+			name:      "error describing instance status",
+			err:       errors.New("access denied"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeInstanceStatus(gomock.Eq(&ec2.DescribeInstanceStatusInput{
+				InstanceIds:         []*string{aws.String("i-1")},
+				IncludeAllInstances: aws.Bool(true),
+			})).Return(&ec2.DescribeInstanceStatusOutput{
+				InstanceStatuses: []*ec2.InstanceStatus{{InstanceId: aws.String("i-1"), Events: tc.events}},
+			}, tc.err)
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			events, err := s.GetInstanceScheduledEvents("i-1")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+
+			codes := []string{}
+			for _, e := range events {
+				codes = append(codes, e.Code)
+			}
+			if !reflect.DeepEqual(codes, tc.expectedCodes) {
+				t.Fatalf("expected events %v but got %v", tc.expectedCodes, codes)
+			}
+		})
+	}
+}
+
+func TestGetInstanceSystemStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	impaired := &ec2.InstanceStatusSummary{
+		Status:  aws.String(ec2.SummaryStatusImpaired),
+		Details: []*ec2.InstanceStatusDetails{{Name: aws.String("reachability"), Status: aws.String("failed")}},
+	}
+
+	testCases := []struct {
+		name           string
+		statuses       []*ec2.InstanceStatus
+		err            error
+		expectedStatus *ec2.InstanceStatusSummary
+		expectErr      bool
+	}{
+		{
+			name:           "returns the system status of the instance",
+			statuses:       []*ec2.InstanceStatus{{InstanceId: aws.String("i-1"), SystemStatus: impaired}},
+			expectedStatus: impaired,
+		},
+		{
+			name: "returns nothing if the status of the instance is not reported",
+		},
+		{
+			name:      "error describing instance status",
+			err:       errors.New("access denied"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeInstanceStatus(gomock.Eq(&ec2.DescribeInstanceStatusInput{
+				InstanceIds:         []*string{aws.String("i-1")},
+				IncludeAllInstances: aws.Bool(true),
+			})).Return(&ec2.DescribeInstanceStatusOutput{InstanceStatuses: tc.statuses}, tc.err)
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			status, err := s.GetInstanceSystemStatus("i-1")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if !reflect.DeepEqual(status, tc.expectedStatus) {
+				t.Fatalf("expected status %v but got %v", tc.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestGetSpotPrice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name           string
+		history        []*ec2.SpotPrice
+		err            error
+		expectedPrice  float64
+		expectErr      bool
+		expectNotFound bool
+	}{
+		{
+			name: "returns the lowest price across availability zones",
+			history: []*ec2.SpotPrice{
+				{AvailabilityZone: aws.String("us-east-1a"), SpotPrice: aws.String("0.0312")},
+				{AvailabilityZone: aws.String("us-east-1b"), SpotPrice: aws.String("0.0298")},
+				{AvailabilityZone: aws.String("us-east-1c"), SpotPrice: aws.String("not-a-price")},
+			},
+			expectedPrice: 0.0298,
+		},
+		{
+			name:           "no price history",
+			expectErr:      true,
+			expectNotFound: true,
+		},
+		{
+			name:      "error describing spot price history",
+			err:       errors.New("access denied"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeSpotPriceHistoryPages(gomock.AssignableToTypeOf(&ec2.DescribeSpotPriceHistoryInput{}), gomock.Any()).
+				DoAndReturn(func(input *ec2.DescribeSpotPriceHistoryInput, fn func(*ec2.DescribeSpotPriceHistoryOutput, bool) bool) error {
+					if aws.StringValue(input.InstanceTypes[0]) != "m5.large" {
+						t.Fatalf("unexpected instance type %q", aws.StringValue(input.InstanceTypes[0]))
+					}
+					if input.AvailabilityZone != nil {
+						t.Fatalf("did not expect an availability zone filter but got %q", aws.StringValue(input.AvailabilityZone))
+					}
+					if tc.err != nil {
+						return tc.err
+					}
+					fn(&ec2.DescribeSpotPriceHistoryOutput{SpotPriceHistory: tc.history}, true)
+					return nil
+				})
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			price, err := s.GetSpotPrice("m5.large", "")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if tc.expectNotFound && !awserrors.IsNotFound(err) {
+					t.Fatalf("expected a not found error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if price != tc.expectedPrice {
+				t.Fatalf("expected price %v but got %v", tc.expectedPrice, price)
+			}
+		})
+	}
+}
+
+func TestGetInstanceTypeZones(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name          string
+		offerings     []*ec2.InstanceTypeOffering
+		err           error
+		expectedZones map[string]bool
+		expectErr     bool
+	}{
+		{
+			name: "returns the availability zones where the instance type is offered",
+			offerings: []*ec2.InstanceTypeOffering{
+				{InstanceType: aws.String("m5.large"), Location: aws.String("us-east-1a")},
+				{InstanceType: aws.String("m5.large"), Location: aws.String("us-east-1c")},
+			},
+			expectedZones: map[string]bool{"us-east-1a": true, "us-east-1c": true},
+		},
+		{
+			name:          "instance type not offered",
+			expectedZones: map[string]bool{},
+		},
+		{
+			name:      "error describing instance type offerings",
+			err:       errors.New("access denied"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeInstanceTypeOfferingsPages(gomock.Eq(&ec2.DescribeInstanceTypeOfferingsInput{
+				LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("instance-type"),
+						Values: aws.StringSlice([]string{"m5.large"}),
+					},
+				},
+			}), gomock.Any()).
+				DoAndReturn(func(_ *ec2.DescribeInstanceTypeOfferingsInput, fn func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool) error {
+					if tc.err != nil {
+						return tc.err
+					}
+					fn(&ec2.DescribeInstanceTypeOfferingsOutput{InstanceTypeOfferings: tc.offerings}, true)
+					return nil
+				})
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			zones, err := s.GetInstanceTypeZones("m5.large")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if !reflect.DeepEqual(zones, tc.expectedZones) {
+				t.Fatalf("expected zones %v but got %v", tc.expectedZones, zones)
+			}
+		})
+	}
+}
+
 func TestCreateInstance(t *testing.T) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1304,20 +1714,28 @@ func TestCreateInstance(t *testing.T) {
 			},
 		},
 		{
-			name: "expect the default SSH key when none is provided",
+			name: "with a host resource group and license configurations",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"set": "node"},
+					Labels:    map[string]string{"set": "node"},
+					Namespace: "default",
+					Name:      "machine-aws-test1",
 				},
 				Spec: clusterv1.MachineSpec{
 					Bootstrap: clusterv1.Bootstrap{
 						DataSecretName: pointer.StringPtr("bootstrap-data"),
 					},
-					Version: pointer.StringPtr("v1.16.1"),
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				InstanceType: "m5.large",
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:         "m5.large",
+				HostResourceGroupARN: aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+				LicenseConfigurationARNs: []string{
+					"arn:aws:license-manager:us-east-1:123456789012:license-configuration:lic-1",
+				},
 			},
 			awsCluster: &infrav1.AWSCluster{
 				ObjectMeta: metav1.ObjectMeta{Name: "test"},
@@ -1354,21 +1772,481 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				m.
-					DescribeImages(gomock.Any()).
-					Return(&ec2.DescribeImagesOutput{
-						Images: []*ec2.Image{
-							{
-								Name:         aws.String("ami-1"),
-								CreationDate: aws.String("2011-02-08T17:02:31.000Z"),
-							},
-						},
-					}, nil)
 				m. // TODO: Restore these parameters, but with the tags as well
-					RunInstances(gomock.Any()).
-					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
-						if input.KeyName == nil {
-							t.Fatal("Expected key name not to be nil")
+					RunInstances(gomock.Eq(&ec2.RunInstancesInput{
+						ImageId:      aws.String("abc"),
+						InstanceType: aws.String("m5.large"),
+						KeyName:      aws.String("default"),
+						MaxCount:     aws.Int64(1),
+						MinCount:     aws.Int64(1),
+						Placement: &ec2.Placement{
+							HostResourceGroupArn: aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+						},
+						LicenseSpecifications: []*ec2.LicenseConfigurationRequest{
+							{
+								LicenseConfigurationArn: aws.String("arn:aws:license-manager:us-east-1:123456789012:license-configuration:lic-1"),
+							},
+						},
+						SecurityGroupIds: []*string{aws.String("2"), aws.String("3")},
+						SubnetId:         aws.String("subnet-1"),
+						TagSpecifications: []*ec2.TagSpecification{
+							{
+								ResourceType: aws.String("instance"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("MachineName"),
+										Value: aws.String("default/machine-aws-test1"),
+									},
+									{
+										Key:   aws.String("Name"),
+										Value: aws.String("aws-test1"),
+									},
+									{
+										Key:   aws.String("kubernetes.io/cluster/test1"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test1"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+										Value: aws.String("node"),
+									},
+								},
+							},
+						},
+						UserData: aws.String(base64.StdEncoding.EncodeToString(userData)),
+					})).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+								Placement: &ec2.Placement{
+									AvailabilityZone:     &az,
+									HostResourceGroupArn: aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+								},
+								Licenses: []*ec2.LicenseConfiguration{
+									{
+										LicenseConfigurationArn: aws.String("arn:aws:license-manager:us-east-1:123456789012:license-configuration:lic-1"),
+									},
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.HostResourceGroupARN != "arn:aws:resource-groups:us-east-1:123456789012:group/hosts" {
+					t.Fatalf("expected the host resource group but got: %v", instance.HostResourceGroupARN)
+				}
+				if len(instance.LicenseConfigurationARNs) != 1 {
+					t.Fatalf("expected one license configuration but got: %v", instance.LicenseConfigurationARNs)
+				}
+			},
+		},
+		{
+			name: "with a static private IP",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:    map[string]string{"set": "node"},
+					Namespace: "default",
+					Name:      "machine-aws-test1",
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Subnet: &infrav1.AWSResourceReference{
+					ID: aws.String("subnet-1"),
+				},
+				PrivateIP: aws.String("10.0.1.10"),
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:        "subnet-1",
+								CidrBlock: "10.0.1.0/24",
+								IsPublic:  false,
+							},
+							infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Eq(&ec2.RunInstancesInput{
+						ImageId:          aws.String("abc"),
+						InstanceType:     aws.String("m5.large"),
+						KeyName:          aws.String("default"),
+						MaxCount:         aws.Int64(1),
+						MinCount:         aws.Int64(1),
+						PrivateIpAddress: aws.String("10.0.1.10"),
+						SecurityGroupIds: []*string{aws.String("2"), aws.String("3")},
+						SubnetId:         aws.String("subnet-1"),
+						TagSpecifications: []*ec2.TagSpecification{
+							{
+								ResourceType: aws.String("instance"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("MachineName"),
+										Value: aws.String("default/machine-aws-test1"),
+									},
+									{
+										Key:   aws.String("Name"),
+										Value: aws.String("aws-test1"),
+									},
+									{
+										Key:   aws.String("kubernetes.io/cluster/test1"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test1"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+										Value: aws.String("node"),
+									},
+								},
+							},
+						},
+						UserData: aws.String(base64.StdEncoding.EncodeToString(userData)),
+					})).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+								PrivateIpAddress: aws.String("10.0.1.10"),
+								Placement: &ec2.Placement{
+									AvailabilityZone: &az,
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if aws.StringValue(instance.PrivateIP) != "10.0.1.10" {
+					t.Fatalf("expected private IP 10.0.1.10 but got: %v", aws.StringValue(instance.PrivateIP))
+				}
+			},
+		},
+		{
+			name: "with network interfaces on several network cards and ENA Express",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:    map[string]string{"set": "node"},
+					Namespace: "default",
+					Name:      "machine-aws-test1",
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:      "p4d.24xlarge",
+				NetworkInterfaces: []string{"eni-1", "eni-2"},
+				NetworkInterfaceConfigs: []infrav1.NetworkInterfaceConfig{
+					{
+						NetworkInterfaceID: "eni-2",
+						NetworkCardIndex:   aws.Int64(1),
+						EnaSrdSpecification: &infrav1.EnaSrdSpecification{
+							EnaSrdEnabled:    aws.Bool(true),
+							EnaSrdUDPEnabled: aws.Bool(true),
+						},
+					},
+				},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					RunInstances(gomock.Any()).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						expected := []*ec2.InstanceNetworkInterfaceSpecification{
+							{NetworkInterfaceId: aws.String("eni-1"), DeviceIndex: aws.Int64(0)},
+							{
+								NetworkInterfaceId: aws.String("eni-2"),
+								DeviceIndex:        aws.Int64(1),
+								NetworkCardIndex:   aws.Int64(1),
+								EnaSrdSpecification: &ec2.EnaSrdSpecificationRequest{
+									EnaSrdEnabled:          aws.Bool(true),
+									EnaSrdUdpSpecification: &ec2.EnaSrdUdpSpecificationRequest{EnaSrdUdpEnabled: aws.Bool(true)},
+								},
+							},
+						}
+						if !reflect.DeepEqual(input.NetworkInterfaces, expected) {
+							t.Fatalf("expected network interfaces %v but got: %v", expected, input.NetworkInterfaces)
+						}
+						return &ec2.Reservation{
+							Instances: []*ec2.Instance{
+								{
+									State: &ec2.InstanceState{
+										Name: aws.String(ec2.InstanceStateNamePending),
+									},
+									InstanceId:   aws.String("two"),
+									InstanceType: aws.String("p4d.24xlarge"),
+									SubnetId:     aws.String("subnet-1"),
+									ImageId:      aws.String("abc"),
+									Placement: &ec2.Placement{
+										AvailabilityZone: &az,
+									},
+								},
+							},
+						}, nil
+					})
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+				m.DescribeNetworkInterfaceAttribute(gomock.Any()).
+					Return(&ec2.DescribeNetworkInterfaceAttributeOutput{
+						Groups: []*ec2.GroupIdentifier{{GroupId: aws.String("2")}, {GroupId: aws.String("3")}},
+					}, nil).Times(2)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with a static private IP outside of the subnet",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:    map[string]string{"set": "node"},
+					Namespace: "default",
+					Name:      "machine-aws-test1",
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Subnet: &infrav1.AWSResourceReference{
+					ID: aws.String("subnet-1"),
+				},
+				PrivateIP: aws.String("10.0.2.10"),
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:        "subnet-1",
+								CidrBlock: "10.0.1.0/24",
+								IsPublic:  false,
+							},
+							infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			check: func(instance *infrav1.Instance, err error) {
+				if err == nil {
+					t.Fatalf("expected an error for a private IP outside of the subnet")
+				}
+			},
+		},
+		{
+			name: "expect the default SSH key when none is provided",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+					Version: pointer.StringPtr("v1.16.1"),
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				InstanceType: "m5.large",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								CreationDate: aws.String("2011-02-08T17:02:31.000Z"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						if input.KeyName == nil {
+							t.Fatal("Expected key name not to be nil")
 						}
 						if *input.KeyName != defaultSSHKeyName {
 							t.Fatalf("Expected SSH key name to be '%s', not '%s'", defaultSSHKeyName, *input.KeyName)
@@ -1897,51 +2775,139 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				m.
-					DescribeImages(gomock.Any()).
-					Return(&ec2.DescribeImagesOutput{
-						Images: []*ec2.Image{
-							{
-								Name:         aws.String("ami-1"),
-								CreationDate: aws.String("2011-02-08T17:02:31.000Z"),
-							},
-						},
-					}, nil)
-				m. // TODO: Restore these parameters, but with the tags as well
-					RunInstances(gomock.Any()).
-					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
-						if input.KeyName != nil {
-							t.Fatalf("Expected key name to be nil/unspecified, not '%s'", *input.KeyName)
-						}
-						return &ec2.Reservation{
-							Instances: []*ec2.Instance{
-								{
-									State: &ec2.InstanceState{
-										Name: aws.String(ec2.InstanceStateNamePending),
-									},
-									IamInstanceProfile: &ec2.IamInstanceProfile{
-										Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
-									},
-									InstanceId:     aws.String("two"),
-									InstanceType:   aws.String("m5.large"),
-									SubnetId:       aws.String("subnet-1"),
-									ImageId:        aws.String("ami-1"),
-									RootDeviceName: aws.String("device-1"),
-									BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
-										{
-											DeviceName: aws.String("device-1"),
-											Ebs: &ec2.EbsInstanceBlockDevice{
-												VolumeId: aws.String("volume-1"),
-											},
+				m.
+					DescribeImages(gomock.Any()).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								CreationDate: aws.String("2011-02-08T17:02:31.000Z"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						if input.KeyName != nil {
+							t.Fatalf("Expected key name to be nil/unspecified, not '%s'", *input.KeyName)
+						}
+						return &ec2.Reservation{
+							Instances: []*ec2.Instance{
+								{
+									State: &ec2.InstanceState{
+										Name: aws.String(ec2.InstanceStateNamePending),
+									},
+									IamInstanceProfile: &ec2.IamInstanceProfile{
+										Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+									},
+									InstanceId:     aws.String("two"),
+									InstanceType:   aws.String("m5.large"),
+									SubnetId:       aws.String("subnet-1"),
+									ImageId:        aws.String("ami-1"),
+									RootDeviceName: aws.String("device-1"),
+									BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+										{
+											DeviceName: aws.String("device-1"),
+											Ebs: &ec2.EbsInstanceBlockDevice{
+												VolumeId: aws.String("volume-1"),
+											},
+										},
+									},
+									Placement: &ec2.Placement{
+										AvailabilityZone: &az,
+									},
+								},
+							},
+						}, nil
+					})
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "falls back to the next instance type when there is no capacity",
+			machine: clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:          "m5.large",
+				FallbackInstanceTypes: []string{"m5a.large", "m4.large"},
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.RunInstances(gomock.Any()).
+						DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+							if aws.StringValue(input.InstanceType) != "m5.large" {
+								t.Fatalf("expected instance type m5.large but got %q", aws.StringValue(input.InstanceType))
+							}
+							return nil, awserr.New(awserrors.InsufficientInstanceCapacity, "no capacity", nil)
+						}),
+					m.RunInstances(gomock.Any()).
+						DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+							if aws.StringValue(input.InstanceType) != "m5a.large" {
+								t.Fatalf("expected instance type m5a.large but got %q", aws.StringValue(input.InstanceType))
+							}
+							return &ec2.Reservation{
+								Instances: []*ec2.Instance{
+									{
+										State: &ec2.InstanceState{
+											Name: aws.String(ec2.InstanceStateNamePending),
+										},
+										InstanceId:   aws.String("two"),
+										InstanceType: aws.String("m5a.large"),
+										SubnetId:     aws.String("subnet-1"),
+										ImageId:      aws.String("ami-1"),
+										Placement: &ec2.Placement{
+											AvailabilityZone: &az,
 										},
-									},
-									Placement: &ec2.Placement{
-										AvailabilityZone: &az,
 									},
 								},
-							},
-						}, nil
-					})
+							}, nil
+						}),
+				)
 				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
 			},
@@ -1949,6 +2915,9 @@ func TestCreateInstance(t *testing.T) {
 				if err != nil {
 					t.Fatalf("did not expect error: %v", err)
 				}
+				if instance.Type != "m5a.large" {
+					t.Fatalf("expected instance type m5a.large but got %q", instance.Type)
+				}
 			},
 		},
 	}
@@ -2088,6 +3057,54 @@ func TestGetInstanceMarketOptionsRequest(t *testing.T) {
 	}
 }
 
+func TestGetCPUOptionsRequest(t *testing.T) {
+	testCases := []struct {
+		name            string
+		cpuOptions      *infrav1.CPUOptions
+		expectedRequest *ec2.CpuOptionsRequest
+	}{
+		{
+			name:            "with no CPU options specified",
+			cpuOptions:      nil,
+			expectedRequest: nil,
+		},
+		{
+			name:            "with empty CPU options specified",
+			cpuOptions:      &infrav1.CPUOptions{},
+			expectedRequest: nil,
+		},
+		{
+			name: "with multithreading disabled",
+			cpuOptions: &infrav1.CPUOptions{
+				ThreadsPerCore: pointer.Int32Ptr(1),
+			},
+			expectedRequest: &ec2.CpuOptionsRequest{
+				ThreadsPerCore: aws.Int64(1),
+			},
+		},
+		{
+			name: "with a core count and threads per core specified",
+			cpuOptions: &infrav1.CPUOptions{
+				CoreCount:      pointer.Int32Ptr(4),
+				ThreadsPerCore: pointer.Int32Ptr(2),
+			},
+			expectedRequest: &ec2.CpuOptionsRequest{
+				CoreCount:      aws.Int64(4),
+				ThreadsPerCore: aws.Int64(2),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := getCPUOptionsRequest(tc.cpuOptions)
+			if !reflect.DeepEqual(request, tc.expectedRequest) {
+				t.Errorf("Case: %s. Got: %v, expected: %v", tc.name, request, tc.expectedRequest)
+			}
+		})
+	}
+}
+
 func TestGetFilteredSecurityGroupID(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -2222,6 +3239,354 @@ func TestGetFilteredSecurityGroupID(t *testing.T) {
 	}
 }
 
+func TestAdoptInstance(t *testing.T) {
+	describeInstance := func(id, vpcID, state string, tags map[string]string) *ec2.DescribeInstancesOutput {
+		instance := &ec2.Instance{
+			InstanceId: aws.String(id),
+			VpcId:      aws.String(vpcID),
+			SubnetId:   aws.String("subnet-1"),
+			State:      &ec2.InstanceState{Name: aws.String(state)},
+			Placement:  &ec2.Placement{AvailabilityZone: aws.String("us-east-1a")},
+		}
+		for k, v := range tags {
+			instance.Tags = append(instance.Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		return &ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{instance}}},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		expect        func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "adopts an unmanaged instance in the cluster vpc",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{"i-1"})})).
+					Return(describeInstance("i-1", "vpc-1", ec2.InstanceStateNameRunning, map[string]string{"Name": "legacy-node"}), nil)
+				m.CreateTags(gomock.Any()).
+					Do(func(input *ec2.CreateTagsInput) {
+						tags := converters.TagsToMap(input.Tags)
+						if tags["sigs.k8s.io/cluster-api-provider-aws/cluster/test1"] != "owned" {
+							t.Errorf("expected the instance to be tagged as owned by the cluster, got %v", tags)
+						}
+						if tags["MachineName"] != "default/test1" {
+							t.Errorf("expected the instance to be tagged with the machine name, got %v", tags)
+						}
+					}).
+					Return(&ec2.CreateTagsOutput{}, nil)
+			},
+		},
+		{
+			name: "instance does not exist",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).Return(nil, awserr.New(awserrors.InvalidInstanceID, "does not exist", nil))
+			},
+			expectedError: "does not exist",
+		},
+		{
+			name: "instance in another vpc",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).
+					Return(describeInstance("i-1", "vpc-2", ec2.InstanceStateNameRunning, nil), nil)
+			},
+			expectedError: "not in vpc",
+		},
+		{
+			name: "terminated instance",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).
+					Return(describeInstance("i-1", "vpc-1", ec2.InstanceStateNameTerminated, nil), nil)
+			},
+			expectedError: "can't be adopted",
+		},
+		{
+			name: "instance owned by another cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).
+					Return(describeInstance("i-1", "vpc-1", ec2.InstanceStateNameRunning, map[string]string{
+						"kubernetes.io/cluster/other": "owned",
+					}), nil)
+			},
+			expectedError: "owned by another cluster",
+		},
+		{
+			name: "instance of another machine",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).
+					Return(describeInstance("i-1", "vpc-1", ec2.InstanceStateNameRunning, map[string]string{
+						"sigs.k8s.io/cluster-api-provider-aws/cluster/test1": "owned",
+						"MachineName": "default/test2",
+					}), nil)
+			},
+			expectedError: "belongs to machine",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			if err != nil {
+				t.Fatalf("failed to create scheme: %v", err)
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test1"}}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:  client,
+				Cluster: cluster,
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-1"}},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				Machine:      &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "default"}},
+				AWSMachine:   &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "aws-test1", Namespace: "default"}},
+				InfraCluster: clusterScope,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			instance, err := s.AdoptInstance(machineScope, "i-1")
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q but got: %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if !infrav1.Tags(instance.Tags).HasOwned("test1") || instance.Tags["Name"] != "aws-test1" {
+				t.Fatalf("expected the adopted instance to carry the machine tags but got: %v", instance.Tags)
+			}
+		})
+	}
+}
+
+func TestFindControlPlaneSubnet(t *testing.T) {
+	describeInstances := func(zones ...string) *ec2.DescribeInstancesOutput {
+		instances := []*ec2.Instance{}
+		for _, zone := range zones {
+			instances = append(instances, &ec2.Instance{Placement: &ec2.Placement{AvailabilityZone: aws.String(zone)}})
+		}
+		return &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: instances}}}
+	}
+
+	testCases := []struct {
+		name           string
+		subnets        infrav1.Subnets
+		expect         func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedSubnet string
+	}{
+		{
+			name: "uses the first subnet when there is a single availability zone",
+			subnets: infrav1.Subnets{
+				{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+				{ID: "subnet-2", AvailabilityZone: "us-east-1a"},
+			},
+			expect:         func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectedSubnet: "subnet-1",
+		},
+		{
+			name: "uses the first subnet when there are no control plane instances",
+			subnets: infrav1.Subnets{
+				{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+				{ID: "subnet-2", AvailabilityZone: "us-east-1b"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{
+					Filters: []*ec2.Filter{
+						filter.EC2.VPC("vpc-1"),
+						filter.EC2.ClusterOwned("test1"),
+						filter.EC2.ProviderRole("control-plane"),
+						filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning),
+					},
+				})).Return(describeInstances(), nil)
+			},
+			expectedSubnet: "subnet-1",
+		},
+		{
+			name: "uses the subnet in the availability zone with the fewest control plane instances",
+			subnets: infrav1.Subnets{
+				{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+				{ID: "subnet-2", AvailabilityZone: "us-east-1b"},
+				{ID: "subnet-3", AvailabilityZone: "us-east-1c"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstances(gomock.Any()).Return(describeInstances("us-east-1a", "us-east-1b", "us-east-1a"), nil)
+			},
+			expectedSubnet: "subnet-3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			if err != nil {
+				t.Fatalf("failed to create scheme: %v", err)
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test1"}}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:  client,
+				Cluster: cluster,
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-1"}},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:  client,
+				Cluster: cluster,
+				Machine: &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test1",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.MachineControlPlaneLabelName: ""},
+				}},
+				AWSMachine:   &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "aws-test1", Namespace: "default"}},
+				InfraCluster: clusterScope,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			subnet, err := s.findControlPlaneSubnet(machineScope, tc.subnets)
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if subnet != tc.expectedSubnet {
+				t.Fatalf("expected subnet %q but got %q", tc.expectedSubnet, subnet)
+			}
+		})
+	}
+}
+
+func TestCheckRootVolume(t *testing.T) {
+	ephemeral := &ec2.BlockDeviceMapping{DeviceName: aws.String("/dev/sdb"), VirtualName: aws.String("ephemeral0")}
+	tests := []struct {
+		name           string
+		image          *ec2.Image
+		size           int64
+		wantDeviceName string
+		wantErr        bool
+	}{
+		{
+			name: "finds an xvda root device listed after other devices",
+			image: &ec2.Image{
+				RootDeviceName: aws.String("/dev/xvda"),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					ephemeral,
+					{DeviceName: aws.String("/dev/xvda"), Ebs: &ec2.EbsBlockDevice{VolumeSize: aws.Int64(8)}},
+				},
+			},
+			size:           8,
+			wantDeviceName: "/dev/xvda",
+		},
+		{
+			name: "finds an sda1 root device",
+			image: &ec2.Image{
+				RootDeviceName: aws.String("/dev/sda1"),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					{DeviceName: aws.String("/dev/sda1"), Ebs: &ec2.EbsBlockDevice{VolumeSize: aws.Int64(20)}},
+					ephemeral,
+				},
+			},
+			size:           30,
+			wantDeviceName: "/dev/sda1",
+		},
+		{
+			name: "rejects a root volume smaller than the snapshot of the root device",
+			image: &ec2.Image{
+				RootDeviceName: aws.String("/dev/sda1"),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					{DeviceName: aws.String("/dev/xvdb"), Ebs: &ec2.EbsBlockDevice{VolumeSize: aws.Int64(8)}},
+					{DeviceName: aws.String("/dev/sda1"), Ebs: &ec2.EbsBlockDevice{VolumeSize: aws.Int64(20)}},
+				},
+			},
+			size:    10,
+			wantErr: true,
+		},
+		{
+			name: "rejects an image without a mapping for its root device",
+			image: &ec2.Image{
+				RootDeviceName:      aws.String("/dev/sda1"),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{ephemeral},
+			},
+			size:    10,
+			wantErr: true,
+		},
+		{
+			name: "rejects an instance store root device",
+			image: &ec2.Image{
+				RootDeviceName:      aws.String("/dev/sdb"),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{ephemeral},
+			},
+			size:    10,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ec2Mock.EXPECT().DescribeImages(&ec2.DescribeImagesInput{
+				ImageIds: aws.StringSlice([]string{"ami-1"}),
+			}).Return(&ec2.DescribeImagesOutput{Images: []*ec2.Image{tt.image}}, nil)
+
+			s := &Service{EC2Client: ec2Mock}
+			deviceName, err := s.checkRootVolume(&infrav1.Volume{Size: tt.size}, "ami-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error but got device %q", aws.StringValue(deviceName))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if aws.StringValue(deviceName) != tt.wantDeviceName {
+				t.Fatalf("expected root device %q but got %q", tt.wantDeviceName, aws.StringValue(deviceName))
+			}
+		})
+	}
+}
+
 func setupScheme() (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
 	if err := clusterv1.AddToScheme(scheme); err != nil {
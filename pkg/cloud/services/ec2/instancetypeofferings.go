@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// InstanceTypeOfferings looks up the instance types offered in availability zones and
+// caches them, so that they can be checked on every admission request.
+type InstanceTypeOfferings struct {
+	newClient func(cluster *clusterv1.Cluster, awsCluster *infrav1.AWSCluster) (ec2iface.EC2API, error)
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]instanceTypeOfferingsEntry
+}
+
+type instanceTypeOfferingsEntry struct {
+	instanceTypes map[string]bool
+	expires       time.Time
+}
+
+// NewInstanceTypeOfferings returns an InstanceTypeOfferings which creates the EC2 clients of a cluster with
+// newClient and refreshes the offerings of an availability zone once they are older than ttl.
+func NewInstanceTypeOfferings(newClient func(cluster *clusterv1.Cluster, awsCluster *infrav1.AWSCluster) (ec2iface.EC2API, error), ttl time.Duration) *InstanceTypeOfferings {
+	return &InstanceTypeOfferings{
+		newClient: newClient,
+		ttl:       ttl,
+		now:       time.Now,
+		entries:   map[string]instanceTypeOfferingsEntry{},
+	}
+}
+
+// IsInstanceTypeOffered returns true if the instance type is offered in the availability zone of the account of the
+// cluster. The offerings are cached per identity, as the names of the availability zones differ between accounts.
+func (o *InstanceTypeOfferings) IsInstanceTypeOffered(cluster *clusterv1.Cluster, awsCluster *infrav1.AWSCluster, instanceType, availabilityZone string) (bool, error) {
+	key := instanceTypeOfferingsKey(awsCluster, availabilityZone)
+
+	o.mu.Lock()
+	entry, ok := o.entries[key]
+	o.mu.Unlock()
+	if ok && !o.now().After(entry.expires) {
+		return entry.instanceTypes[instanceType], nil
+	}
+
+	// The lock isn't held while EC2 is called, so that a slow lookup doesn't hold up every other admission request.
+	// Concurrent requests for the same availability zone may then both look it up.
+	instanceTypes, err := o.describeInstanceTypeOfferings(cluster, awsCluster, availabilityZone)
+	if err != nil {
+		return false, err
+	}
+
+	o.mu.Lock()
+	o.entries[key] = instanceTypeOfferingsEntry{
+		instanceTypes: instanceTypes,
+		expires:       o.now().Add(o.ttl),
+	}
+	o.mu.Unlock()
+
+	return instanceTypes[instanceType], nil
+}
+
+// instanceTypeOfferingsKey identifies the offerings of an availability zone for the identity of the cluster.
+func instanceTypeOfferingsKey(awsCluster *infrav1.AWSCluster, availabilityZone string) string {
+	ref := awsCluster.Spec.IdentityRef
+	if ref == nil {
+		return string(infrav1.ControllerIdentityKind) + "/" + availabilityZone
+	}
+	return string(ref.Kind) + "/" + ref.Name + "/" + availabilityZone
+}
+
+func (o *InstanceTypeOfferings) describeInstanceTypeOfferings(cluster *clusterv1.Cluster, awsCluster *infrav1.AWSCluster, availabilityZone string) (map[string]bool, error) {
+	client, err := o.newClient(cluster, awsCluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create EC2 client for cluster %q", cluster.Name)
+	}
+
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("location"),
+				Values: aws.StringSlice([]string{availabilityZone}),
+			},
+		},
+	}
+
+	instanceTypes := map[string]bool{}
+	if err := client.DescribeInstanceTypeOfferingsPages(input, func(out *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+		for _, offering := range out.InstanceTypeOfferings {
+			instanceTypes[aws.StringValue(offering.InstanceType)] = true
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance type offerings in availability zone %q", availabilityZone)
+	}
+
+	// An availability zone which is unknown or not in the region of the cluster has no offerings at all.
+	// Don't cache it, so that every instance type isn't reported as unavailable there.
+	if len(instanceTypes) == 0 {
+		return nil, errors.Errorf("no instance type offerings found in availability zone %q", availabilityZone)
+	}
+
+	return instanceTypes, nil
+}
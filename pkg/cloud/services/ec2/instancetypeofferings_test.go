@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+func TestInstanceTypeOfferings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	describeOfferings := func(offerings ...string) func(*ec2.DescribeInstanceTypeOfferingsInput, func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool) error {
+		return func(_ *ec2.DescribeInstanceTypeOfferingsInput, fn func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool) error {
+			out := &ec2.DescribeInstanceTypeOfferingsOutput{}
+			for _, offering := range offerings {
+				out.InstanceTypeOfferings = append(out.InstanceTypeOfferings, &ec2.InstanceTypeOffering{InstanceType: aws.String(offering)})
+			}
+			fn(out, true)
+			return nil
+		}
+	}
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{
+			{Name: aws.String("location"), Values: aws.StringSlice([]string{"us-east-1e"})},
+		},
+	}
+	ec2Mock.EXPECT().DescribeInstanceTypeOfferingsPages(input, gomock.Any()).DoAndReturn(describeOfferings("m5.large", "t3.large")).Times(1)
+	ec2Mock.EXPECT().DescribeInstanceTypeOfferingsPages(input, gomock.Any()).DoAndReturn(describeOfferings("m5.large")).Times(1)
+	ec2Mock.EXPECT().DescribeInstanceTypeOfferingsPages(input, gomock.Any()).DoAndReturn(describeOfferings("m5.large", "t3.large")).Times(1)
+	ec2Mock.EXPECT().DescribeInstanceTypeOfferingsPages(gomock.Any(), gomock.Any()).DoAndReturn(describeOfferings()).Times(1)
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	awsCluster := &infrav1.AWSCluster{Spec: infrav1.AWSClusterSpec{Region: "us-east-1"}}
+	otherAWSCluster := &infrav1.AWSCluster{Spec: infrav1.AWSClusterSpec{
+		Region:      "us-east-1",
+		IdentityRef: &infrav1.AWSIdentityReference{Kind: infrav1.ClusterRoleIdentityKind, Name: "team-a"},
+	}}
+	var clients []*infrav1.AWSCluster
+	offerings := NewInstanceTypeOfferings(func(_ *clusterv1.Cluster, awsCluster *infrav1.AWSCluster) (ec2iface.EC2API, error) {
+		clients = append(clients, awsCluster)
+		return ec2Mock, nil
+	}, time.Hour)
+	now := time.Now()
+	offerings.now = func() time.Time { return now }
+
+	offered, err := offerings.IsInstanceTypeOffered(cluster, awsCluster, "m5.large", "us-east-1e")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(offered).To(BeTrue())
+
+	// Served from the cache.
+	offered, err = offerings.IsInstanceTypeOffered(cluster, awsCluster, "c5.large", "us-east-1e")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(offered).To(BeFalse())
+
+	// Looked up again for another identity, whose account may name its availability zones differently.
+	offered, err = offerings.IsInstanceTypeOffered(cluster, otherAWSCluster, "t3.large", "us-east-1e")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(offered).To(BeFalse())
+
+	// Refreshed once the cache entry expired.
+	now = now.Add(2 * time.Hour)
+	offered, err = offerings.IsInstanceTypeOffered(cluster, awsCluster, "t3.large", "us-east-1e")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(offered).To(BeTrue())
+
+	// Unknown availability zones have no offerings and are reported as an error.
+	_, err = offerings.IsInstanceTypeOffered(cluster, awsCluster, "m5.large", "us-east-1-lax-1z")
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(clients).To(Equal([]*infrav1.AWSCluster{awsCluster, otherAWSCluster, awsCluster, awsCluster}))
+}
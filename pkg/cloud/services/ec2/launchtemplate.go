@@ -26,6 +26,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
@@ -191,7 +192,7 @@ func (s *Service) createLaunchTemplateData(scope *scope.MachinePoolScope, imageI
 	// set the AMI ID
 	data.ImageId = imageID
 
-	// Set up root volume
+	// Set up root volume on the root device of the image, whose name differs between images, as for instances.
 	if lt.RootVolume != nil {
 		rootDeviceName, err := s.checkRootVolume(lt.RootVolume, *data.ImageId)
 		if err != nil {
@@ -225,11 +226,48 @@ func (s *Service) createLaunchTemplateData(scope *scope.MachinePoolScope, imageI
 		}
 	}
 
+	if lt.PlacementGroupName != "" || lt.Tenancy != "" {
+		data.Placement = &ec2.LaunchTemplatePlacementRequest{}
+		if lt.PlacementGroupName != "" {
+			data.Placement.GroupName = aws.String(lt.PlacementGroupName)
+		}
+		if lt.Tenancy != "" {
+			data.Placement.Tenancy = aws.String(lt.Tenancy)
+		}
+	}
+
+	data.CapacityReservationSpecification = getLaunchTemplateCapacityReservationRequest(lt.CapacityReservation)
+
+	for _, arn := range lt.LicenseConfigurationARNs {
+		data.LicenseSpecifications = append(data.LicenseSpecifications, &ec2.LaunchTemplateLicenseConfigurationRequest{
+			LicenseConfigurationArn: aws.String(arn),
+		})
+	}
+
 	data.TagSpecifications = s.buildLaunchTemplateTagSpecificationRequest(scope)
 
 	return data, nil
 }
 
+func getLaunchTemplateCapacityReservationRequest(reservation *expinfrav1.CapacityReservationSpecification) *ec2.LaunchTemplateCapacityReservationSpecificationRequest {
+	switch {
+	case reservation == nil:
+		return nil
+	case reservation.ID != nil || reservation.ResourceGroupARN != nil:
+		return &ec2.LaunchTemplateCapacityReservationSpecificationRequest{
+			CapacityReservationTarget: &ec2.CapacityReservationTarget{
+				CapacityReservationId:               reservation.ID,
+				CapacityReservationResourceGroupArn: reservation.ResourceGroupARN,
+			},
+		}
+	case reservation.Preference != "":
+		return &ec2.LaunchTemplateCapacityReservationSpecificationRequest{
+			CapacityReservationPreference: aws.String(string(reservation.Preference)),
+		}
+	}
+	return nil
+}
+
 // DeleteLaunchTemplate delete a launch template.
 func (s *Service) DeleteLaunchTemplate(id string) error {
 	s.scope.V(2).Info("Deleting launch template", "id", id)
@@ -246,60 +284,96 @@ func (s *Service) DeleteLaunchTemplate(id string) error {
 	return nil
 }
 
-// PruneLaunchTemplateVersions deletes one old launch template version.
+// PruneLaunchTemplateVersions deletes the old launch template versions that exceed the history limit.
+// It is called before a new version is created, at which point the current latest version becomes
+// an old version as well, so it retains historyLimit-1 of the other old versions.
 // It does not delete the "latest" version, because that version may still be in use.
 // It does not delete the "default" version, because that version cannot be deleted.
 // It does not assume that versions are sequential. Versions may be deleted out of band.
-func (s *Service) PruneLaunchTemplateVersions(id string) error {
-	// When there is one version available, it is the default and the latest.
-	// When there are two versions available, one the is the default, the other is the latest.
-	// Therefore we only prune when there are at least 3 versions available.
-	const minCountToAllowPrune = 3
-
+func (s *Service) PruneLaunchTemplateVersions(id string, historyLimit int32) error {
 	input := &ec2.DescribeLaunchTemplateVersionsInput{
 		LaunchTemplateId: aws.String(id),
-		MinVersion:       aws.String("0"),
-		MaxVersion:       aws.String(expinfrav1.LaunchTemplateLatestVersion),
-		MaxResults:       aws.Int64(minCountToAllowPrune),
 	}
 
-	out, err := s.EC2Client.DescribeLaunchTemplateVersions(input)
+	var versions []*ec2.LaunchTemplateVersion
+	err := s.EC2Client.DescribeLaunchTemplateVersionsPages(input, func(out *ec2.DescribeLaunchTemplateVersionsOutput, lastPage bool) bool {
+		versions = append(versions, out.LaunchTemplateVersions...)
+		return true
+	})
 	if err != nil {
-		s.scope.Info("", "aerr", err.Error())
-		return err
+		return errors.Wrapf(err, "failed to describe versions of launch template %q", id)
 	}
 
-	// len(out.LaunchTemplateVersions)	|	items
-	// -------------------------------- + -----------------------
-	// 								1	|	[default/latest]
-	// 								2	|	[default, latest]
-	// 								3	| 	[default, versionToPrune, latest]
-	if len(out.LaunchTemplateVersions) < minCountToAllowPrune {
+	versionsToPrune := launchTemplateVersionsToPrune(versions, historyLimit)
+	if len(versionsToPrune) == 0 {
 		return nil
 	}
-	versionToPrune := out.LaunchTemplateVersions[1].VersionNumber
-	return s.deleteLaunchTemplateVersion(id, versionToPrune)
+	return s.deleteLaunchTemplateVersions(id, versionsToPrune)
 }
 
-func (s *Service) deleteLaunchTemplateVersion(id string, version *int64) error {
-	s.scope.V(2).Info("Deleting launch template version", "id", id)
-
-	if version == nil {
-		return errors.New("version is a nil pointer")
+// launchTemplateVersionsToPrune returns the version numbers to delete, oldest first, so that at most
+// historyLimit-1 versions remain besides the default and the latest version.
+func launchTemplateVersionsToPrune(versions []*ec2.LaunchTemplateVersion, historyLimit int32) []int64 {
+	var latest int64
+	for _, v := range versions {
+		if n := aws.Int64Value(v.VersionNumber); n > latest {
+			latest = n
+		}
 	}
-	versions := []string{strconv.FormatInt(*version, 10)}
 
-	input := &ec2.DeleteLaunchTemplateVersionsInput{
-		LaunchTemplateId: aws.String(id),
-		Versions:         aws.StringSlice(versions),
+	candidates := []int64{}
+	for _, v := range versions {
+		n := aws.Int64Value(v.VersionNumber)
+		if n == latest || aws.BoolValue(v.DefaultVersion) {
+			continue
+		}
+		candidates = append(candidates, n)
 	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
 
-	_, err := s.EC2Client.DeleteLaunchTemplateVersions(input)
-	if err != nil {
-		return err
+	retain := int(historyLimit) - 1
+	if retain < 0 {
+		retain = 0
 	}
+	if len(candidates) <= retain {
+		return nil
+	}
+	return candidates[:len(candidates)-retain]
+}
+
+func (s *Service) deleteLaunchTemplateVersions(id string, versions []int64) error {
+	// DeleteLaunchTemplateVersions accepts at most 200 versions per request.
+	const maxVersionsPerRequest = 200
 
-	s.scope.V(2).Info("Deleted launch template", "id", id, "version", *version)
+	for len(versions) > 0 {
+		batch := versions
+		if len(batch) > maxVersionsPerRequest {
+			batch = batch[:maxVersionsPerRequest]
+		}
+		versions = versions[len(batch):]
+
+		input := &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String(id),
+		}
+		for _, v := range batch {
+			input.Versions = append(input.Versions, aws.String(strconv.FormatInt(v, 10)))
+		}
+
+		s.scope.V(2).Info("Deleting launch template versions", "id", id, "versions", batch)
+		out, err := s.EC2Client.DeleteLaunchTemplateVersions(input)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete versions of launch template %q", id)
+		}
+		if len(out.UnsuccessfullyDeletedLaunchTemplateVersions) > 0 {
+			failed := out.UnsuccessfullyDeletedLaunchTemplateVersions[0]
+			reason := ""
+			if failed.ResponseError != nil {
+				reason = aws.StringValue(failed.ResponseError.Message)
+			}
+			return errors.Errorf("failed to delete version %d of launch template %q: %s", aws.Int64Value(failed.VersionNumber), id, reason)
+		}
+		s.scope.V(2).Info("Deleted launch template versions", "id", id, "versions", batch)
+	}
 	return nil
 }
 
@@ -325,6 +399,29 @@ func (s *Service) SDKToLaunchTemplate(d *ec2.LaunchTemplateVersion) (*expinfrav1
 		}
 	}
 
+	if v.Placement != nil {
+		i.PlacementGroupName = aws.StringValue(v.Placement.GroupName)
+		i.Tenancy = aws.StringValue(v.Placement.Tenancy)
+	}
+
+	if r := v.CapacityReservationSpecification; r != nil {
+		switch {
+		case r.CapacityReservationTarget != nil:
+			i.CapacityReservation = &expinfrav1.CapacityReservationSpecification{
+				ID:               r.CapacityReservationTarget.CapacityReservationId,
+				ResourceGroupARN: r.CapacityReservationTarget.CapacityReservationResourceGroupArn,
+			}
+		case r.CapacityReservationPreference != nil:
+			i.CapacityReservation = &expinfrav1.CapacityReservationSpecification{
+				Preference: expinfrav1.CapacityReservationPreference(aws.StringValue(r.CapacityReservationPreference)),
+			}
+		}
+	}
+
+	for _, license := range v.LicenseSpecifications {
+		i.LicenseConfigurationARNs = append(i.LicenseConfigurationARNs, aws.StringValue(license.LicenseConfigurationArn))
+	}
+
 	for _, id := range v.SecurityGroupIds {
 		// FIXME(dlipovetsky): This will include the core security groups as well, making the
 		// "Additional" a bit dishonest. However, including the core groups drastically simplifies
@@ -356,6 +453,18 @@ func (s *Service) LaunchTemplateNeedsUpdate(scope *scope.MachinePoolScope, incom
 		return true, nil
 	}
 
+	if incoming.PlacementGroupName != existing.PlacementGroupName || tenancyOrDefault(incoming.Tenancy) != tenancyOrDefault(existing.Tenancy) {
+		return true, nil
+	}
+
+	if !reflect.DeepEqual(getLaunchTemplateCapacityReservationRequest(incoming.CapacityReservation), getLaunchTemplateCapacityReservationRequest(existing.CapacityReservation)) {
+		return true, nil
+	}
+
+	if !sets.NewString(incoming.LicenseConfigurationARNs...).Equal(sets.NewString(existing.LicenseConfigurationARNs...)) {
+		return true, nil
+	}
+
 	incomingIDs := make([]string, len(incoming.AdditionalSecurityGroups))
 	for i, ref := range incoming.AdditionalSecurityGroups {
 		incomingIDs[i] = aws.StringValue(ref.ID)
@@ -383,6 +492,15 @@ func (s *Service) LaunchTemplateNeedsUpdate(scope *scope.MachinePoolScope, incom
 	return false, nil
 }
 
+// tenancyOrDefault returns the tenancy, or the default tenancy when none is set, as EC2 may report it
+// for launch templates created without one.
+func tenancyOrDefault(tenancy string) string {
+	if tenancy == "" {
+		return ec2.TenancyDefault
+	}
+	return tenancy
+}
+
 // DiscoverLaunchTemplateAMI will discover the AMI launch template.
 func (s *Service) DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*string, error) {
 	lt := scope.AWSMachinePool.Spec.AWSLaunchTemplate
@@ -415,13 +533,13 @@ func (s *Service) DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*str
 		imageLookupBaseOS = scope.InfraCluster.ImageLookupBaseOS()
 	}
 
-	if scope.IsEKSManaged() && imageLookupFormat == "" && imageLookupOrg == "" && imageLookupBaseOS == "" {
-		lookupAMI, err = s.eksAMILookup(*scope.MachinePool.Spec.Template.Spec.Version, scope.AWSMachinePool.Spec.AWSLaunchTemplate.AMI.EKSOptimizedLookupType)
+	if useSSMAMILookup(scope.IsEKSManaged(), lt.AMI.OSFamily, imageLookupFormat, imageLookupOrg, imageLookupBaseOS) {
+		lookupAMI, err = s.eksAMILookup(*scope.MachinePool.Spec.Template.Spec.Version, lt.AMI.OSFamily, eksAMILookupType(lt.AMI.EKSOptimizedLookupType, lt.GPU, lt.InstanceType))
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		lookupAMI, err = s.defaultAMIIDLookup(imageLookupFormat, imageLookupOrg, imageLookupBaseOS, *scope.MachinePool.Spec.Template.Spec.Version)
+		lookupAMI, err = s.defaultAMIIDLookup(imageLookupFormat, imageLookupOrg, amiLookupBaseOS(lt.AMI.OSFamily, imageLookupBaseOS), *scope.MachinePool.Spec.Template.Spec.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -270,6 +270,80 @@ func TestService_LaunchTemplateNeedsUpdate(t *testing.T) {
 			want:    true,
 			wantErr: false,
 		},
+		{
+			name: "default tenancy reported for a template created without one",
+			incoming: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-999")},
+				},
+			},
+			existing: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-111")},
+					{ID: aws.String("sg-222")},
+					{ID: aws.String("sg-999")},
+				},
+				Tenancy: "default",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "new placement group",
+			incoming: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-999")},
+				},
+				PlacementGroupName: "cluster-pg",
+			},
+			existing: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-111")},
+					{ID: aws.String("sg-222")},
+					{ID: aws.String("sg-999")},
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "capacity reservation targeted",
+			incoming: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-999")},
+				},
+				CapacityReservation: &expinfrav1.CapacityReservationSpecification{ID: aws.String("cr-1")},
+			},
+			existing: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-111")},
+					{ID: aws.String("sg-222")},
+					{ID: aws.String("sg-999")},
+				},
+				CapacityReservation: &expinfrav1.CapacityReservationSpecification{Preference: expinfrav1.CapacityReservationPreferenceOpen},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "the same license configurations in another order",
+			incoming: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-999")},
+				},
+				LicenseConfigurationARNs: []string{"arn:lic-1", "arn:lic-2"},
+			},
+			existing: &expinfrav1.AWSLaunchTemplate{
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{ID: aws.String("sg-111")},
+					{ID: aws.String("sg-222")},
+					{ID: aws.String("sg-999")},
+				},
+				LicenseConfigurationARNs: []string{"arn:lic-2", "arn:lic-1"},
+			},
+			want:    false,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -309,3 +383,94 @@ func TestService_LaunchTemplateNeedsUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestPruneLaunchTemplateVersions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	versions := func(defaultVersion int64, numbers ...int64) []*ec2.LaunchTemplateVersion {
+		out := []*ec2.LaunchTemplateVersion{}
+		for _, n := range numbers {
+			out = append(out, &ec2.LaunchTemplateVersion{
+				VersionNumber:  aws.Int64(n),
+				DefaultVersion: aws.Bool(n == defaultVersion),
+			})
+		}
+		return out
+	}
+
+	testCases := []struct {
+		name         string
+		historyLimit int32
+		versions     []*ec2.LaunchTemplateVersion
+		expect       func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name:         "does not prune the default and the latest version",
+			historyLimit: 1,
+			versions:     versions(1, 1, 2),
+		},
+		{
+			name:         "prunes all old versions with a history limit of 1",
+			historyLimit: 1,
+			versions:     versions(1, 1, 2, 3, 4),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DeleteLaunchTemplateVersions(gomock.Eq(&ec2.DeleteLaunchTemplateVersionsInput{
+					LaunchTemplateId: aws.String("lt-12345"),
+					Versions:         aws.StringSlice([]string{"2", "3"}),
+				})).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil)
+			},
+		},
+		{
+			name:         "retains the newest old versions and skips a default version that is not the first",
+			historyLimit: 3,
+			versions:     versions(3, 1, 2, 3, 5, 6, 8),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DeleteLaunchTemplateVersions(gomock.Eq(&ec2.DeleteLaunchTemplateVersionsInput{
+					LaunchTemplateId: aws.String("lt-12345"),
+					Versions:         aws.StringSlice([]string{"1", "2"}),
+				})).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil)
+			},
+		},
+		{
+			name:         "does not prune when the history limit is not reached",
+			historyLimit: 5,
+			versions:     versions(1, 1, 2, 3, 4),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeLaunchTemplateVersionsPages(gomock.Eq(&ec2.DescribeLaunchTemplateVersionsInput{
+				LaunchTemplateId: aws.String("lt-12345"),
+			}), gomock.Any()).DoAndReturn(func(_ *ec2.DescribeLaunchTemplateVersionsInput, fn func(*ec2.DescribeLaunchTemplateVersionsOutput, bool) bool) error {
+				fn(&ec2.DescribeLaunchTemplateVersionsOutput{LaunchTemplateVersions: tc.versions}, true)
+				return nil
+			})
+			if tc.expect != nil {
+				tc.expect(ec2Mock.EXPECT())
+			}
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			if err := s.PruneLaunchTemplateVersions("lt-12345", tc.historyLimit); err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// regionNotOptedIn is the opt-in status of a region the account is not opted in to.
+const regionNotOptedIn = "not-opted-in"
+
+// optInRegions are the regions which must be enabled for an AWS account before they can be used.
+var optInRegions = map[string]bool{
+	"af-south-1":     true,
+	"ap-east-1":      true,
+	"ap-south-2":     true,
+	"ap-southeast-3": true,
+	"ap-southeast-4": true,
+	"eu-central-2":   true,
+	"eu-south-1":     true,
+	"eu-south-2":     true,
+	"me-central-1":   true,
+	"me-south-1":     true,
+}
+
+// IsOptInRegion returns whether the region must be enabled for an AWS account before it can be used.
+func IsOptInRegion(region string) bool {
+	return optInRegions[region]
+}
+
+// ReconcileRegion checks that the region of the cluster is enabled for its AWS account and reports it with the
+// RegionEnabled condition, returning false when it is not. Once the check succeeded it is not made again.
+func (s *Service) ReconcileRegion() (bool, error) {
+	if conditions.IsTrue(s.scope.InfraCluster(), infrav1.RegionEnabledCondition) {
+		return true, nil
+	}
+
+	region := s.scope.Region()
+	enabled, err := s.isRegionEnabled(region)
+	if err != nil {
+		conditions.MarkUnknown(s.scope.InfraCluster(), infrav1.RegionEnabledCondition, infrav1.RegionCheckFailedReason, err.Error())
+		return false, err
+	}
+	if !enabled {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.RegionEnabledCondition, infrav1.RegionNotEnabledReason, clusterv1.ConditionSeverityError,
+			"Region %s is not enabled for the AWS account", region)
+		record.Warnf(s.scope.InfraCluster(), "RegionNotEnabled", "Region %s is not enabled for the AWS account, it must be enabled in the account settings", region)
+		return false, nil
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.RegionEnabledCondition)
+	return true, nil
+}
+
+func (s *Service) isRegionEnabled(region string) (bool, error) {
+	// Regions enabled by default cannot be disabled.
+	if !IsOptInRegion(region) {
+		return true, nil
+	}
+
+	out, err := s.EC2Client.DescribeRegions(&ec2.DescribeRegionsInput{
+		AllRegions:  aws.Bool(true),
+		RegionNames: aws.StringSlice([]string{region}),
+	})
+	if err != nil {
+		// The endpoints of a region the account is not opted in to reject its credentials.
+		if code, ok := awserrors.Code(err); ok && code == awserrors.AuthFailure {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to describe region %q", region)
+	}
+
+	for _, r := range out.Regions {
+		if aws.StringValue(r.RegionName) == region {
+			return aws.StringValue(r.OptInStatus) != regionNotOptedIn, nil
+		}
+	}
+	return false, errors.Errorf("region %q not found", region)
+}
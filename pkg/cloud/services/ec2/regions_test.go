@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileRegion(t *testing.T) {
+	describeRegion := func(region string) *ec2.DescribeRegionsInput {
+		return &ec2.DescribeRegionsInput{
+			AllRegions:  aws.Bool(true),
+			RegionNames: aws.StringSlice([]string{region}),
+		}
+	}
+	regionOutput := func(region, status string) *ec2.DescribeRegionsOutput {
+		return &ec2.DescribeRegionsOutput{
+			Regions: []*ec2.Region{{RegionName: aws.String(region), OptInStatus: aws.String(status)}},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		region        string
+		expect        func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectEnabled bool
+		expectError   bool
+		expectReason  string
+	}{
+		{
+			name:          "region enabled by default",
+			region:        "us-east-1",
+			expect:        func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expectEnabled: true,
+		},
+		{
+			name:   "opted in region",
+			region: "me-south-1",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRegions(describeRegion("me-south-1")).Return(regionOutput("me-south-1", "opted-in"), nil)
+			},
+			expectEnabled: true,
+		},
+		{
+			name:   "region not opted in",
+			region: "af-south-1",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRegions(describeRegion("af-south-1")).Return(regionOutput("af-south-1", "not-opted-in"), nil)
+			},
+			expectReason: infrav1.RegionNotEnabledReason,
+		},
+		{
+			name:   "region endpoint rejects the credentials",
+			region: "af-south-1",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRegions(describeRegion("af-south-1")).Return(nil, awserr.New(awserrors.AuthFailure, "", nil))
+			},
+			expectReason: infrav1.RegionNotEnabledReason,
+		},
+		{
+			name:   "region cannot be described",
+			region: "ap-east-1",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeRegions(describeRegion("ap-east-1")).Return(nil, awserr.New("RequestLimitExceeded", "", nil))
+			},
+			expectError:  true,
+			expectReason: infrav1.RegionCheckFailedReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).To(BeNil())
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec:       infrav1.AWSClusterSpec{Region: tc.region},
+				},
+				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			})
+			g.Expect(err).To(BeNil())
+
+			tc.expect(ec2Mock.EXPECT())
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			enabled, err := s.ReconcileRegion()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(enabled).To(Equal(tc.expectEnabled))
+			if tc.expectEnabled {
+				g.Expect(conditions.IsTrue(clusterScope.AWSCluster, infrav1.RegionEnabledCondition)).To(BeTrue())
+				// The check is not made again once it succeeded.
+				enabled, err = s.ReconcileRegion()
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(enabled).To(BeTrue())
+			} else {
+				g.Expect(conditions.GetReason(clusterScope.AWSCluster, infrav1.RegionEnabledCondition)).To(Equal(tc.expectReason))
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	asg "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/autoscaling"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
 )
 
 // Service holds a collection of interfaces.
@@ -32,6 +34,10 @@ type Service struct {
 
 	// SSMClient is used to look up the official EKS AMI ID
 	SSMClient ssmiface.SSMAPI
+
+	// ASGService and ELBService manage the auto scaling group of the bastion hosts and its load balancer.
+	ASGService *asg.Service
+	ELBService *elb.Service
 }
 
 // NewService returns a new service given the ec2 api client.
@@ -40,5 +46,8 @@ func NewService(clusterScope scope.EC2Scope) *Service {
 		scope:     clusterScope,
 		EC2Client: scope.NewEC2Client(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
 		SSMClient: scope.NewSSMClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
+
+		ASGService: asg.NewService(clusterScope),
+		ELBService: elb.NewService(clusterScope),
 	}
 }
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecr
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/internal/mime"
+)
+
+const (
+	dockerHubRegistry  = "registry-1.docker.io"
+	dockerHubNamespace = "docker.io"
+)
+
+// containerdMirrorBoothook writes a containerd hosts.toml for every mirrored
+// registry. containerd reads the files from its config_path on every pull, so
+// no restart is needed as long as the node image sets config_path to
+// /etc/containerd/certs.d. Pulls fall back to the upstream registry whenever
+// the mirror is unavailable.
+var containerdMirrorBoothook = template.Must(template.New("containerd-mirror-boothook").Parse(`#cloud-boothook
+#!/bin/sh
+{{- range .Mirrors }}
+mkdir -p /etc/containerd/certs.d/{{ .Namespace }}
+cat > /etc/containerd/certs.d/{{ .Namespace }}/hosts.toml <<'EOF'
+server = "https://{{ .Upstream }}"
+
+[host."https://{{ $.Registry }}/v2/{{ .Prefix }}"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+EOF
+{{- end }}
+`))
+
+type registryMirror struct {
+	Namespace string
+	Upstream  string
+	Prefix    string
+}
+
+// ContainerdMirrorBoothook returns a cloud-init boothook configuring the
+// pull-through cache rules of the cluster as containerd registry mirrors.
+func (s *Service) ContainerdMirrorBoothook() ([]byte, error) {
+	registry, err := s.RegistryEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := s.scope.ECRPullThroughCache().Rules
+	mirrors := make([]registryMirror, 0, len(rules))
+	for _, rule := range rules {
+		namespace := rule.UpstreamRegistryURL
+		if namespace == dockerHubRegistry {
+			namespace = dockerHubNamespace
+		}
+		mirrors = append(mirrors, registryMirror{
+			Namespace: namespace,
+			Upstream:  rule.UpstreamRegistryURL,
+			Prefix:    rule.ECRRepositoryPrefix,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := containerdMirrorBoothook.Execute(&buf, struct {
+		Registry string
+		Mirrors  []registryMirror
+	}{
+		Registry: registry,
+		Mirrors:  mirrors,
+	}); err != nil {
+		return nil, errors.Wrap(err, "rendering containerd mirror configuration")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AddContainerdMirrors prepends the containerd mirror boothook to the given user data.
+func (s *Service) AddContainerdMirrors(userData []byte) ([]byte, error) {
+	boothook, err := s.ContainerdMirrorBoothook()
+	if err != nil {
+		return nil, err
+	}
+
+	return mime.PrependBoothook(userData, boothook)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecr
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
+)
+
+// ReconcilePullThroughCacheRules ensures the pull-through cache rules of the cluster exist.
+// Rules are shared by every cluster in the account and region, so they are never
+// modified or removed by the controller.
+func (s *Service) ReconcilePullThroughCacheRules() error {
+	cache := s.scope.ECRPullThroughCache()
+	if cache == nil {
+		return nil
+	}
+
+	existing, err := s.describePullThroughCacheRules()
+	if err != nil {
+		return errors.Wrap(err, "describing pull-through cache rules")
+	}
+
+	for _, rule := range cache.Rules {
+		if current, ok := existing[rule.ECRRepositoryPrefix]; ok {
+			if aws.StringValue(current.UpstreamRegistryUrl) != rule.UpstreamRegistryURL {
+				return errors.Errorf("pull-through cache rule for prefix %q already exists with upstream registry %q",
+					rule.ECRRepositoryPrefix, aws.StringValue(current.UpstreamRegistryUrl))
+			}
+			continue
+		}
+
+		input := &ecr.CreatePullThroughCacheRuleInput{
+			EcrRepositoryPrefix: aws.String(rule.ECRRepositoryPrefix),
+			UpstreamRegistryUrl: aws.String(rule.UpstreamRegistryURL),
+		}
+		if rule.CredentialARN != "" {
+			input.CredentialArn = aws.String(rule.CredentialARN)
+		}
+
+		if _, err := s.ECRClient.CreatePullThroughCacheRule(input); err != nil {
+			return errors.Wrapf(err, "creating pull-through cache rule for prefix %q", rule.ECRRepositoryPrefix)
+		}
+
+		s.scope.Info("Created pull-through cache rule", "prefix", rule.ECRRepositoryPrefix, "upstream", rule.UpstreamRegistryURL)
+	}
+
+	return nil
+}
+
+// RegistryEndpoint returns the hostname of the private ECR registry of the cluster's account and region.
+func (s *Service) RegistryEndpoint() (string, error) {
+	identity, err := s.STSClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.Wrap(err, "getting account ID")
+	}
+
+	return fmt.Sprintf("%s.dkr.ecr.%s.%s", aws.StringValue(identity.Account), s.scope.Region(), awsarn.DNSSuffix(s.scope.Region())), nil
+}
+
+func (s *Service) describePullThroughCacheRules() (map[string]*ecr.PullThroughCacheRule, error) {
+	rules := map[string]*ecr.PullThroughCacheRule{}
+
+	input := &ecr.DescribePullThroughCacheRulesInput{}
+	for {
+		out, err := s.ECRClient.DescribePullThroughCacheRules(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range out.PullThroughCacheRules {
+			rules[aws.StringValue(rule.EcrRepositoryPrefix)] = rule
+		}
+
+		if aws.StringValue(out.NextToken) == "" {
+			return rules, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
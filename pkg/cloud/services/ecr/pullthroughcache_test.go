@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// fakeECR records the pull-through cache rules created by the service.
+type fakeECR struct {
+	ecriface.ECRAPI
+	existing []*ecr.PullThroughCacheRule
+	created  []*ecr.CreatePullThroughCacheRuleInput
+}
+
+func (f *fakeECR) DescribePullThroughCacheRules(in *ecr.DescribePullThroughCacheRulesInput) (*ecr.DescribePullThroughCacheRulesOutput, error) {
+	// Return one rule per page to exercise pagination.
+	out := &ecr.DescribePullThroughCacheRulesOutput{}
+	idx := 0
+	if in.NextToken != nil {
+		idx = len(aws.StringValue(in.NextToken))
+	}
+	if idx < len(f.existing) {
+		out.PullThroughCacheRules = []*ecr.PullThroughCacheRule{f.existing[idx]}
+	}
+	if idx+1 < len(f.existing) {
+		out.NextToken = aws.String(strings.Repeat("n", idx+1))
+	}
+	return out, nil
+}
+
+func (f *fakeECR) CreatePullThroughCacheRule(in *ecr.CreatePullThroughCacheRuleInput) (*ecr.CreatePullThroughCacheRuleOutput, error) {
+	f.created = append(f.created, in)
+	return &ecr.CreatePullThroughCacheRuleOutput{}, nil
+}
+
+type fakeSTS struct {
+	stsiface.STSAPI
+}
+
+func (f *fakeSTS) GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil
+}
+
+func TestReconcilePullThroughCacheRules(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cache     *infrav1.ECRPullThroughCache
+		ecr       *fakeECR
+		expectErr bool
+		verify    func(g *WithT, f *fakeECR)
+	}{
+		{
+			name: "does nothing when pull-through cache is not configured",
+			ecr:  &fakeECR{},
+			verify: func(g *WithT, f *fakeECR) {
+				g.Expect(f.created).To(BeEmpty())
+			},
+		},
+		{
+			name: "creates missing rules",
+			cache: &infrav1.ECRPullThroughCache{
+				Rules: []infrav1.ECRPullThroughCacheRule{
+					{UpstreamRegistryURL: "public.ecr.aws", ECRRepositoryPrefix: "ecr-public"},
+					{UpstreamRegistryURL: "quay.io", ECRRepositoryPrefix: "quay"},
+					{UpstreamRegistryURL: "registry-1.docker.io", ECRRepositoryPrefix: "docker-hub", CredentialARN: "arn:aws:secretsmanager:us-west-2:123456789012:secret:ecr-pullthroughcache/docker-hub"},
+				},
+			},
+			ecr: &fakeECR{
+				existing: []*ecr.PullThroughCacheRule{
+					{EcrRepositoryPrefix: aws.String("ecr-public"), UpstreamRegistryUrl: aws.String("public.ecr.aws")},
+					{EcrRepositoryPrefix: aws.String("other"), UpstreamRegistryUrl: aws.String("ghcr.io")},
+				},
+			},
+			verify: func(g *WithT, f *fakeECR) {
+				g.Expect(f.created).To(Equal([]*ecr.CreatePullThroughCacheRuleInput{
+					{EcrRepositoryPrefix: aws.String("quay"), UpstreamRegistryUrl: aws.String("quay.io")},
+					{
+						EcrRepositoryPrefix: aws.String("docker-hub"),
+						UpstreamRegistryUrl: aws.String("registry-1.docker.io"),
+						CredentialArn:       aws.String("arn:aws:secretsmanager:us-west-2:123456789012:secret:ecr-pullthroughcache/docker-hub"),
+					},
+				}))
+			},
+		},
+		{
+			name: "errors if prefix is used for another upstream registry",
+			cache: &infrav1.ECRPullThroughCache{
+				Rules: []infrav1.ECRPullThroughCacheRule{
+					{UpstreamRegistryURL: "quay.io", ECRRepositoryPrefix: "quay"},
+				},
+			},
+			ecr: &fakeECR{
+				existing: []*ecr.PullThroughCacheRule{
+					{EcrRepositoryPrefix: aws.String("other"), UpstreamRegistryUrl: aws.String("ghcr.io")},
+					{EcrRepositoryPrefix: aws.String("quay"), UpstreamRegistryUrl: aws.String("ghcr.io")},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster(tc.cache)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := NewService(clusterScope)
+			s.ECRClient = tc.ecr
+			s.STSClient = &fakeSTS{}
+
+			err = s.ReconcilePullThroughCacheRules()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.verify(g, tc.ecr)
+		})
+	}
+}
+
+func TestContainerdMirrorBoothook(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope, err := setupCluster(&infrav1.ECRPullThroughCache{
+		Rules: []infrav1.ECRPullThroughCacheRule{
+			{UpstreamRegistryURL: "quay.io", ECRRepositoryPrefix: "quay"},
+			{UpstreamRegistryURL: "registry-1.docker.io", ECRRepositoryPrefix: "docker-hub"},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s := NewService(clusterScope)
+	s.STSClient = &fakeSTS{}
+
+	boothook, err := s.ContainerdMirrorBoothook()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(boothook)).To(Equal(`#cloud-boothook
+#!/bin/sh
+mkdir -p /etc/containerd/certs.d/quay.io
+cat > /etc/containerd/certs.d/quay.io/hosts.toml <<'EOF'
+server = "https://quay.io"
+
+[host."https://123456789012.dkr.ecr.us-west-2.amazonaws.com/v2/quay"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+EOF
+mkdir -p /etc/containerd/certs.d/docker.io
+cat > /etc/containerd/certs.d/docker.io/hosts.toml <<'EOF'
+server = "https://registry-1.docker.io"
+
+[host."https://123456789012.dkr.ecr.us-west-2.amazonaws.com/v2/docker-hub"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+EOF
+`))
+}
+
+func TestRegistryEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope, err := setupCluster(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterScope.AWSCluster.Spec.Region = "cn-north-1"
+
+	s := NewService(clusterScope)
+	s.STSClient = &fakeSTS{}
+
+	endpoint, err := s.RegistryEndpoint()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(endpoint).To(Equal("123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn"))
+}
+
+func setupCluster(cache *infrav1.ECRPullThroughCache) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			Region:              "us-west-2",
+			ECRPullThroughCache: cache,
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).Build()
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+}
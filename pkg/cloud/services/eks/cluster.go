@@ -365,6 +365,18 @@ func (s *Service) createCluster(eksClusterName string) (*eks.Cluster, error) {
 		Tags:               tags,
 	}
 
+	if s.scope.ControlPlane.Spec.IPFamily == controlplanev1.EKSIPFamilyIPv6 {
+		input.KubernetesNetworkConfig = &eks.KubernetesNetworkConfigRequest{
+			IpFamily: aws.String(eks.IpFamilyIpv6),
+		}
+	}
+	if cfg := s.scope.ControlPlane.Spec.OutpostConfig; cfg != nil {
+		input.OutpostConfig = &eks.OutpostConfigRequest{
+			OutpostArns:              aws.StringSlice(cfg.OutpostArns),
+			ControlPlaneInstanceType: aws.String(cfg.ControlPlaneInstanceType),
+		}
+	}
+
 	var out *eks.CreateClusterOutput
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
 		if out, err = s.EKSClient.CreateCluster(input); err != nil {
@@ -594,6 +606,19 @@ func (s *Service) describeEKSCluster(eksClusterName string) (*eks.Cluster, error
 	return out.Cluster, nil
 }
 
+// describeLocalClusterID returns the ID of an EKS local cluster, which identifies it in authentication tokens instead
+// of its name.
+func (s *Service) describeLocalClusterID(eksClusterName string) (string, error) {
+	cluster, err := s.describeEKSCluster(eksClusterName)
+	if err != nil {
+		return "", err
+	}
+	if cluster == nil || aws.StringValue(cluster.Id) == "" {
+		return "", errors.Errorf("local cluster %q has no id", eksClusterName)
+	}
+	return aws.StringValue(cluster.Id), nil
+}
+
 func (s *Service) updateEncryptionConfig(updatedEncryptionConfigs []*eks.EncryptionConfig) error {
 	input := &eks.AssociateEncryptionConfigInput{
 		ClusterName:      aws.String(s.scope.KubernetesClusterName()),
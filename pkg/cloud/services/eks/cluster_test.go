@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -34,6 +35,7 @@ import (
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks/mock_eksiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam/mock_iamiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
@@ -530,3 +532,106 @@ func TestReconcileEKSEncryptionConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateCluster(t *testing.T) {
+	clusterName := "default.cluster"
+	tests := []struct {
+		name   string
+		spec   func(spec *ekscontrolplanev1.AWSManagedControlPlaneSpec)
+		verify func(g *WithT, input *eks.CreateClusterInput)
+	}{
+		{
+			name: "ipv4 cluster in the region",
+			spec: func(spec *ekscontrolplanev1.AWSManagedControlPlaneSpec) {},
+			verify: func(g *WithT, input *eks.CreateClusterInput) {
+				g.Expect(input.KubernetesNetworkConfig).To(BeNil())
+				g.Expect(input.OutpostConfig).To(BeNil())
+			},
+		},
+		{
+			name: "ipv6 cluster",
+			spec: func(spec *ekscontrolplanev1.AWSManagedControlPlaneSpec) {
+				spec.IPFamily = ekscontrolplanev1.EKSIPFamilyIPv6
+			},
+			verify: func(g *WithT, input *eks.CreateClusterInput) {
+				g.Expect(input.KubernetesNetworkConfig).To(Equal(&eks.KubernetesNetworkConfigRequest{
+					IpFamily: aws.String(eks.IpFamilyIpv6),
+				}))
+			},
+		},
+		{
+			name: "local cluster on an outpost",
+			spec: func(spec *ekscontrolplanev1.AWSManagedControlPlaneSpec) {
+				spec.OutpostConfig = &ekscontrolplanev1.OutpostConfig{
+					OutpostArns:              []string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"},
+					ControlPlaneInstanceType: "m5.large",
+				}
+			},
+			verify: func(g *WithT, input *eks.CreateClusterInput) {
+				g.Expect(input.OutpostConfig).To(Equal(&eks.OutpostConfigRequest{
+					OutpostArns:              aws.StringSlice([]string{"arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"}),
+					ControlPlaneInstanceType: aws.String("m5.large"),
+				}))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			eksMock := mock_eksiface.NewMockEKSAPI(mockControl)
+			iamMock := mock_iamiface.NewMockIAMAPI(mockControl)
+
+			spec := ekscontrolplanev1.AWSManagedControlPlaneSpec{
+				Version:  aws.String("1.21"),
+				RoleName: aws.String("eks-controlplane"),
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{ID: "subnet-1", AvailabilityZone: "us-west-2a"},
+						{ID: "subnet-2", AvailabilityZone: "us-west-2b"},
+					},
+				},
+			}
+			tc.spec(&spec)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			_ = ekscontrolplanev1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      clusterName,
+					},
+				},
+				ControlPlane: &ekscontrolplanev1.AWSManagedControlPlane{
+					Spec: spec,
+				},
+			})
+			g.Expect(err).To(BeNil())
+
+			iamMock.EXPECT().GetRole(&iam.GetRoleInput{RoleName: aws.String("eks-controlplane")}).
+				Return(&iam.GetRoleOutput{Role: &iam.Role{Arn: aws.String("arn:aws:iam::123456789012:role/eks-controlplane")}}, nil)
+			eksMock.EXPECT().CreateCluster(gomock.AssignableToTypeOf(&eks.CreateClusterInput{})).
+				DoAndReturn(func(input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
+					g.Expect(aws.StringValue(input.RoleArn)).To(Equal("arn:aws:iam::123456789012:role/eks-controlplane"))
+					tc.verify(g, input)
+					return &eks.CreateClusterOutput{Cluster: &eks.Cluster{Name: input.Name}}, nil
+				})
+
+			s := NewService(scope)
+			s.EKSClient = eksMock
+			s.IAMClient = iamMock
+
+			cluster, err := s.createCluster(clusterName)
+			g.Expect(err).To(BeNil())
+			g.Expect(aws.StringValue(cluster.Name)).To(Equal(clusterName))
+		})
+	}
+}
@@ -189,6 +189,11 @@ func (s *Service) createUserKubeconfigSecret(ctx context.Context, cluster *eks.C
 		return fmt.Errorf("creating base kubeconfig: %w", err)
 	}
 
+	tokenClusterID, err := s.tokenClusterID()
+	if err != nil {
+		return fmt.Errorf("getting cluster id: %w", err)
+	}
+
 	execConfig := &api.ExecConfig{APIVersion: "client.authentication.k8s.io/v1alpha1"}
 	switch s.scope.TokenMethod() {
 	case ekscontrolplanev1.EKSTokenMethodIAMAuthenticator:
@@ -196,15 +201,19 @@ func (s *Service) createUserKubeconfigSecret(ctx context.Context, cluster *eks.C
 		execConfig.Args = []string{
 			"token",
 			"-i",
-			clusterName,
+			tokenClusterID,
 		}
 	case ekscontrolplanev1.EKSTokenMethodAWSCli:
+		clusterFlag := "--cluster-name"
+		if s.scope.ControlPlane.Spec.OutpostConfig != nil {
+			clusterFlag = "--cluster-id"
+		}
 		execConfig.Command = "aws"
 		execConfig.Args = []string{
 			"eks",
 			"get-token",
-			"--cluster-name",
-			clusterName,
+			clusterFlag,
+			tokenClusterID,
 		}
 	default:
 		return fmt.Errorf("using token method %s: %w", s.scope.TokenMethod(), ErrUnknownTokenMethod)
@@ -258,11 +267,24 @@ func (s *Service) createBaseKubeConfig(cluster *eks.Cluster, userName string) (*
 	return cfg, nil
 }
 
-func (s *Service) generateToken() (string, error) {
+// tokenClusterID returns the identifier of the cluster in its authentication tokens, which is the name of the
+// cluster, or its ID for a local cluster.
+func (s *Service) tokenClusterID() (string, error) {
 	eksClusterName := s.scope.KubernetesClusterName()
+	if s.scope.ControlPlane.Spec.OutpostConfig == nil {
+		return eksClusterName, nil
+	}
+	return s.describeLocalClusterID(eksClusterName)
+}
+
+func (s *Service) generateToken() (string, error) {
+	tokenClusterID, err := s.tokenClusterID()
+	if err != nil {
+		return "", fmt.Errorf("getting cluster id: %w", err)
+	}
 
 	req, output := s.STSClient.GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
-	req.HTTPRequest.Header.Add(clusterNameHeader, eksClusterName)
+	req.HTTPRequest.Header.Add(clusterNameHeader, tokenClusterID)
 	s.Logger.V(4).Info("generating token for AWS identity", "user", output.UserId, "account", output.Account, "arn", output.Arn)
 
 	presignedURL, err := req.Presign(tokenAgeMins * time.Minute)
@@ -44,6 +44,11 @@ const (
 type IAMService struct {
 	logr.Logger
 	IAMClient iamiface.IAMAPI
+
+	// RolePath is the path of the roles created by the service.
+	RolePath string
+	// PermissionsBoundary is the ARN of the policy set as permissions boundary of the roles created by the service.
+	PermissionsBoundary string
 }
 
 // GetIAMRole will return the IAM role for the IAMService.
@@ -193,6 +198,12 @@ func (s *IAMService) CreateRole(
 		Tags:                     tags,
 		AssumeRolePolicyDocument: aws.String(trustRelationshipJSON),
 	}
+	if s.RolePath != "" {
+		input.Path = aws.String(s.RolePath)
+	}
+	if s.PermissionsBoundary != "" {
+		input.PermissionsBoundary = aws.String(s.PermissionsBoundary)
+	}
 
 	out, err := s.IAMClient.CreateRole(input)
 	if err != nil {
@@ -282,6 +293,29 @@ func (s *IAMService) EnsureTagsAndPolicy(
 	return updated, nil
 }
 
+// EnsurePermissionsBoundary will set the permissions boundary of the IAMService on the role if it differs.
+// An existing boundary is left in place when the IAMService has none configured.
+func (s *IAMService) EnsurePermissionsBoundary(role *iam.Role) (bool, error) {
+	if s.PermissionsBoundary == "" {
+		return false, nil
+	}
+
+	if role.PermissionsBoundary != nil && aws.StringValue(role.PermissionsBoundary.PermissionsBoundaryArn) == s.PermissionsBoundary {
+		return false, nil
+	}
+
+	s.V(2).Info("Setting permissions boundary on role", "role", aws.StringValue(role.RoleName), "permissions-boundary", s.PermissionsBoundary)
+	input := &iam.PutRolePermissionsBoundaryInput{
+		RoleName:            role.RoleName,
+		PermissionsBoundary: aws.String(s.PermissionsBoundary),
+	}
+	if _, err := s.IAMClient.PutRolePermissionsBoundary(input); err != nil {
+		return true, errors.Wrapf(err, "error setting permissions boundary on role %s", aws.StringValue(role.RoleName))
+	}
+
+	return true, nil
+}
+
 func (s *IAMService) detachAllPoliciesForRole(name string) error {
 	s.V(3).Info("Detaching all policies for role", "role", name)
 	input := &iam.ListAttachedRolePoliciesInput{
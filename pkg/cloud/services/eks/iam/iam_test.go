@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+type fakeIAM struct {
+	iamiface.IAMAPI
+
+	createRoleInput *iam.CreateRoleInput
+	boundaryInputs  []*iam.PutRolePermissionsBoundaryInput
+}
+
+func (f *fakeIAM) CreateRole(in *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	f.createRoleInput = in
+	return &iam.CreateRoleOutput{Role: &iam.Role{RoleName: in.RoleName, Path: in.Path}}, nil
+}
+
+func (f *fakeIAM) PutRolePermissionsBoundary(in *iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	f.boundaryInputs = append(f.boundaryInputs, in)
+	return &iam.PutRolePermissionsBoundaryOutput{}, nil
+}
+
+func TestCreateRole(t *testing.T) {
+	tests := []struct {
+		name                string
+		rolePath            string
+		permissionsBoundary string
+		expectPath          *string
+		expectBoundary      *string
+	}{
+		{
+			name: "without path and permissions boundary",
+		},
+		{
+			name:                "with path and permissions boundary",
+			rolePath:            "/capa/",
+			permissionsBoundary: "arn:aws:iam::123456789012:policy/boundary",
+			expectPath:          aws.String("/capa/"),
+			expectBoundary:      aws.String("arn:aws:iam::123456789012:policy/boundary"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := &fakeIAM{}
+			s := &IAMService{
+				Logger:              klogr.New(),
+				IAMClient:           client,
+				RolePath:            tt.rolePath,
+				PermissionsBoundary: tt.permissionsBoundary,
+			}
+
+			_, err := s.CreateRole("role", "cluster", NodegroupTrustRelationship(), infrav1.Tags{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(client.createRoleInput.Path).To(Equal(tt.expectPath))
+			g.Expect(client.createRoleInput.PermissionsBoundary).To(Equal(tt.expectBoundary))
+		})
+	}
+}
+
+func TestEnsurePermissionsBoundary(t *testing.T) {
+	const boundary = "arn:aws:iam::123456789012:policy/boundary"
+
+	tests := []struct {
+		name                string
+		permissionsBoundary string
+		role                *iam.Role
+		expectUpdate        bool
+	}{
+		{
+			name:         "no boundary configured leaves the role untouched",
+			role:         &iam.Role{RoleName: aws.String("role")},
+			expectUpdate: false,
+		},
+		{
+			name:                "sets a missing boundary",
+			permissionsBoundary: boundary,
+			role:                &iam.Role{RoleName: aws.String("role")},
+			expectUpdate:        true,
+		},
+		{
+			name:                "replaces a different boundary",
+			permissionsBoundary: boundary,
+			role: &iam.Role{
+				RoleName:            aws.String("role"),
+				PermissionsBoundary: &iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String("arn:aws:iam::123456789012:policy/other")},
+			},
+			expectUpdate: true,
+		},
+		{
+			name:                "keeps a matching boundary",
+			permissionsBoundary: boundary,
+			role: &iam.Role{
+				RoleName:            aws.String("role"),
+				PermissionsBoundary: &iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String(boundary)},
+			},
+			expectUpdate: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := &fakeIAM{}
+			s := &IAMService{
+				Logger:              klogr.New(),
+				IAMClient:           client,
+				PermissionsBoundary: tt.permissionsBoundary,
+			}
+
+			updated, err := s.EnsurePermissionsBoundary(tt.role)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(updated).To(Equal(tt.expectUpdate))
+			if tt.expectUpdate {
+				g.Expect(client.boundaryInputs).To(HaveLen(1))
+				g.Expect(aws.StringValue(client.boundaryInputs[0].PermissionsBoundary)).To(Equal(boundary))
+			} else {
+				g.Expect(client.boundaryInputs).To(BeEmpty())
+			}
+		})
+	}
+}
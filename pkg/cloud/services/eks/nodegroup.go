@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func (s *NodegroupService) describeNodegroup() (*eks.Nodegroup, error) {
@@ -189,6 +190,9 @@ func (s *NodegroupService) createNodegroup() (*eks.Nodegroup, error) {
 	if managedPool.InstanceType != nil {
 		input.InstanceTypes = []*string{managedPool.InstanceType}
 	}
+	if managedPool.UpdateConfig != nil {
+		input.UpdateConfig = converters.NodegroupUpdateconfigToSDK(managedPool.UpdateConfig)
+	}
 	if len(managedPool.Taints) > 0 {
 		s.Info("adding taints to nodegroup", "nodegroup", nodegroupName)
 		taints, err := converters.TaintsToSDK(managedPool.Taints)
@@ -273,7 +277,9 @@ func (s *NodegroupService) deleteNodegroupAndWait() (reterr error) {
 	return nil
 }
 
-func (s *NodegroupService) reconcileNodegroupVersion(ng *eks.Nodegroup) error {
+// reconcileNodegroupVersion updates the Kubernetes or AMI version of the node group when it differs from the spec,
+// and returns whether an update was started.
+func (s *NodegroupService) reconcileNodegroupVersion(ng *eks.Nodegroup) (bool, error) {
 	var specVersion *version.Version
 	if s.scope.Version() != nil {
 		specVersion = parseEKSVersion(*s.scope.Version())
@@ -312,15 +318,16 @@ func (s *NodegroupService) reconcileNodegroupVersion(ng *eks.Nodegroup) error {
 			return true, nil
 		}); err != nil {
 			record.Warnf(s.scope.ManagedMachinePool, "FailedUpdateEKSNodegroup", "failed to update the EKS nodegroup %s %s: %v", eksClusterName, updateMsg, err)
-			return errors.Wrapf(err, "failed to update EKS nodegroup")
+			return false, errors.Wrapf(err, "failed to update EKS nodegroup")
 		}
+		return true, nil
 	}
-	return nil
+	return false, nil
 }
 
 func createLabelUpdate(specLabels map[string]string, ng *eks.Nodegroup) *eks.UpdateLabelsPayload {
 	current := ng.Labels
-	payload := eks.UpdateLabelsPayload{}
+	payload := eks.UpdateLabelsPayload{AddOrUpdateLabels: map[string]*string{}}
 	for k, v := range specLabels {
 		if currentV, ok := current[k]; !ok || currentV == nil || v != *currentV {
 			payload.AddOrUpdateLabels[k] = aws.String(v)
@@ -356,7 +363,8 @@ func (s *NodegroupService) createTaintsUpdate(specTaints infrav1exp.Taints, ng *
 	}
 	for _, currentTaint := range current {
 		ct := currentTaint.DeepCopy()
-		if !specTaints.Contains(ct) {
+		// A taint whose value changed is updated in place, EKS rejects removing it in the same update.
+		if !specTaints.Contains(ct) && !specTaints.ContainsKeyEffect(ct) {
 			sdkTaint, err := converters.TaintToSDK(*ct)
 			if err != nil {
 				return nil, fmt.Errorf("converting taint to sdk: %w", err)
@@ -373,6 +381,16 @@ func (s *NodegroupService) createTaintsUpdate(specTaints infrav1exp.Taints, ng *
 	return nil, nil
 }
 
+// updateConfigNeedsUpdate returns whether the update config of the node group differs from the spec. EKS always
+// reports an update config, defaulting to a maxUnavailable of 1.
+func updateConfigNeedsUpdate(specUpdateConfig *infrav1exp.UpdateConfig, ngUpdateConfig *eks.NodegroupUpdateConfig) bool {
+	if ngUpdateConfig == nil {
+		return true
+	}
+	return aws.Int64Value(ngUpdateConfig.MaxUnavailable) != int64(aws.Int32Value(specUpdateConfig.MaxUnavailable)) ||
+		aws.Int64Value(ngUpdateConfig.MaxUnavailablePercentage) != int64(aws.Int32Value(specUpdateConfig.MaxUnavailablePercentage))
+}
+
 func (s *NodegroupService) reconcileNodegroupConfig(ng *eks.Nodegroup) error {
 	eksClusterName := s.scope.KubernetesClusterName()
 	s.V(2).Info("reconciling node group config", "cluster", eksClusterName, "name", *ng.NodegroupName)
@@ -408,14 +426,20 @@ func (s *NodegroupService) reconcileNodegroupConfig(ng *eks.Nodegroup) error {
 		input.ScalingConfig = s.scalingConfig()
 		needsUpdate = true
 	}
-	if (aws.Int64Value(ng.ScalingConfig.MaxSize) != int64(aws.Int32Value(managedPool.Scaling.MaxSize))) ||
-		(aws.Int64Value(ng.ScalingConfig.MinSize) != int64(aws.Int32Value(managedPool.Scaling.MinSize))) {
+	if scaling := managedPool.Scaling; scaling != nil && ((aws.Int64Value(ng.ScalingConfig.MaxSize) != int64(aws.Int32Value(scaling.MaxSize))) ||
+		(aws.Int64Value(ng.ScalingConfig.MinSize) != int64(aws.Int32Value(scaling.MinSize)))) {
 		s.V(2).Info("Nodegroup min/max differ from spec, updating scaling configuration", "nodegroup", ng.NodegroupName)
 		input.ScalingConfig = s.scalingConfig()
 		needsUpdate = true
 	}
+	if updateConfig := managedPool.UpdateConfig; updateConfig != nil && updateConfigNeedsUpdate(updateConfig, ng.UpdateConfig) {
+		s.V(2).Info("Nodegroup update config differs from spec, updating update configuration", "nodegroup", ng.NodegroupName)
+		input.UpdateConfig = converters.NodegroupUpdateconfigToSDK(updateConfig)
+		needsUpdate = true
+	}
 	if !needsUpdate {
 		s.V(2).Info("node group config update not needed", "cluster", eksClusterName, "name", *ng.NodegroupName)
+		conditions.MarkTrue(s.scope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition)
 		return nil
 	}
 	if err := input.Validate(); err != nil {
@@ -424,8 +448,12 @@ func (s *NodegroupService) reconcileNodegroupConfig(ng *eks.Nodegroup) error {
 
 	_, err = s.EKSClient.UpdateNodegroupConfig(input)
 	if err != nil {
+		record.Warnf(s.scope.ManagedMachinePool, "FailedUpdateEKSNodegroupConfig", "Failed to update the configuration of EKS nodegroup %s: %v", *ng.NodegroupName, err)
+		conditions.MarkFalse(s.scope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition, infrav1exp.EKSNodegroupConfigUpdateFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return errors.Wrap(err, "failed to update nodegroup config")
 	}
+	record.Eventf(s.scope.ManagedMachinePool, "SuccessfulUpdateEKSNodegroupConfig", "Updated the configuration of EKS nodegroup %s", *ng.NodegroupName)
+	conditions.MarkFalse(s.scope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition, infrav1exp.EKSNodegroupConfigUpdatingReason, clusterv1.ConditionSeverityInfo, "")
 
 	return nil
 }
@@ -466,11 +494,16 @@ func (s *NodegroupService) reconcileNodegroup() error {
 		return errors.Wrap(err, "failed to wait for nodegroup to be active")
 	}
 
-	if err := s.reconcileNodegroupVersion(ng); err != nil {
+	updatingVersion, err := s.reconcileNodegroupVersion(ng)
+	if err != nil {
 		return errors.Wrap(err, "failed to reconcile nodegroup version")
 	}
 
-	if err := s.reconcileNodegroupConfig(ng); err != nil {
+	// EKS only runs one update of a node group at a time, so the configuration is reconciled once the version
+	// update completed.
+	if updatingVersion {
+		conditions.MarkFalse(s.scope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition, infrav1exp.EKSNodegroupVersionUpdatingReason, clusterv1.ConditionSeverityInfo, "")
+	} else if err := s.reconcileNodegroupConfig(ng); err != nil {
 		return errors.Wrap(err, "failed to reconcile nodegroup config")
 	}
 
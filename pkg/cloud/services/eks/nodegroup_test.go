@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	controlplanev1exp "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks/iam"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks/mock_eksiface"
+	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestCreateLabelUpdate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    map[string]string
+		current map[string]*string
+		expect  *eks.UpdateLabelsPayload
+	}{
+		{
+			name:    "no update when the labels match",
+			spec:    map[string]string{"a": "1"},
+			current: map[string]*string{"a": aws.String("1")},
+		},
+		{
+			name:    "adds labels to a node group without labels",
+			spec:    map[string]string{"a": "1"},
+			current: nil,
+			expect: &eks.UpdateLabelsPayload{
+				AddOrUpdateLabels: map[string]*string{"a": aws.String("1")},
+			},
+		},
+		{
+			name:    "updates changed labels and removes deleted ones",
+			spec:    map[string]string{"a": "2"},
+			current: map[string]*string{"a": aws.String("1"), "b": aws.String("1")},
+			expect: &eks.UpdateLabelsPayload{
+				AddOrUpdateLabels: map[string]*string{"a": aws.String("2")},
+				RemoveLabels:      aws.StringSlice([]string{"b"}),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			payload := createLabelUpdate(tc.spec, &eks.Nodegroup{Labels: tc.current})
+			if tc.expect == nil {
+				g.Expect(payload).To(BeNil())
+				return
+			}
+			g.Expect(payload.AddOrUpdateLabels).To(Equal(tc.expect.AddOrUpdateLabels))
+			g.Expect(payload.RemoveLabels).To(ConsistOf(tc.expect.RemoveLabels))
+		})
+	}
+}
+
+func TestCreateTaintsUpdate(t *testing.T) {
+	taint := func(key, value, effect string) *eks.Taint {
+		return &eks.Taint{Key: aws.String(key), Value: aws.String(value), Effect: aws.String(effect)}
+	}
+
+	testCases := []struct {
+		name         string
+		spec         infrav1exp.Taints
+		current      []*eks.Taint
+		expectAdd    []*eks.Taint
+		expectRemove []*eks.Taint
+	}{
+		{
+			name:    "no update when the taints match",
+			spec:    infrav1exp.Taints{{Key: "a", Value: "1", Effect: infrav1exp.TaintEffectNoSchedule}},
+			current: []*eks.Taint{taint("a", "1", eks.TaintEffectNoSchedule)},
+		},
+		{
+			name: "adds new taints and removes deleted ones",
+			spec: infrav1exp.Taints{{Key: "a", Value: "1", Effect: infrav1exp.TaintEffectNoSchedule}},
+			current: []*eks.Taint{
+				taint("b", "1", eks.TaintEffectNoExecute),
+			},
+			expectAdd:    []*eks.Taint{taint("a", "1", eks.TaintEffectNoSchedule)},
+			expectRemove: []*eks.Taint{taint("b", "1", eks.TaintEffectNoExecute)},
+		},
+		{
+			name:      "updates the value of a taint in place",
+			spec:      infrav1exp.Taints{{Key: "a", Value: "2", Effect: infrav1exp.TaintEffectNoSchedule}},
+			current:   []*eks.Taint{taint("a", "1", eks.TaintEffectNoSchedule)},
+			expectAdd: []*eks.Taint{taint("a", "2", eks.TaintEffectNoSchedule)},
+		},
+		{
+			name:         "removes a taint whose effect changed",
+			spec:         infrav1exp.Taints{{Key: "a", Value: "1", Effect: infrav1exp.TaintEffectNoExecute}},
+			current:      []*eks.Taint{taint("a", "1", eks.TaintEffectNoSchedule)},
+			expectAdd:    []*eks.Taint{taint("a", "1", eks.TaintEffectNoExecute)},
+			expectRemove: []*eks.Taint{taint("a", "1", eks.TaintEffectNoSchedule)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &NodegroupService{
+				scope:      &scope.ManagedMachinePoolScope{Logger: klogr.New()},
+				IAMService: iam.IAMService{Logger: klogr.New()},
+			}
+
+			payload, err := s.createTaintsUpdate(tc.spec, &eks.Nodegroup{NodegroupName: aws.String("ng"), Taints: tc.current})
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expectAdd == nil && tc.expectRemove == nil {
+				g.Expect(payload).To(BeNil())
+				return
+			}
+			g.Expect(payload.AddOrUpdateTaints).To(Equal(tc.expectAdd))
+			g.Expect(payload.RemoveTaints).To(Equal(tc.expectRemove))
+		})
+	}
+}
+
+func TestUpdateConfigNeedsUpdate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    *infrav1exp.UpdateConfig
+		current *eks.NodegroupUpdateConfig
+		expect  bool
+	}{
+		{
+			name:    "no update when maxUnavailable matches",
+			spec:    &infrav1exp.UpdateConfig{MaxUnavailable: aws.Int32(1)},
+			current: &eks.NodegroupUpdateConfig{MaxUnavailable: aws.Int64(1)},
+		},
+		{
+			name:    "updates a changed maxUnavailable",
+			spec:    &infrav1exp.UpdateConfig{MaxUnavailable: aws.Int32(3)},
+			current: &eks.NodegroupUpdateConfig{MaxUnavailable: aws.Int64(1)},
+			expect:  true,
+		},
+		{
+			name:    "updates from maxUnavailable to maxUnavailablePercentage",
+			spec:    &infrav1exp.UpdateConfig{MaxUnavailablePercentage: aws.Int32(25)},
+			current: &eks.NodegroupUpdateConfig{MaxUnavailable: aws.Int64(1)},
+			expect:  true,
+		},
+		{
+			name:   "updates a node group without update config",
+			spec:   &infrav1exp.UpdateConfig{MaxUnavailable: aws.Int32(1)},
+			expect: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(updateConfigNeedsUpdate(tc.spec, tc.current)).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestReconcileNodegroupConfig(t *testing.T) {
+	testCases := []struct {
+		name         string
+		labels       map[string]string
+		updateErr    error
+		expectUpdate bool
+		expectStatus bool
+		expectReason string
+	}{
+		{
+			name:         "marks the config synced when no update is needed",
+			labels:       map[string]string{"a": "1"},
+			expectStatus: true,
+		},
+		{
+			name:         "updates changed labels",
+			labels:       map[string]string{"a": "2"},
+			expectUpdate: true,
+			expectReason: infrav1exp.EKSNodegroupConfigUpdatingReason,
+		},
+		{
+			name:         "reports a failed update",
+			labels:       map[string]string{"a": "2"},
+			updateErr:    errors.New("ResourceInUseException"),
+			expectUpdate: true,
+			expectReason: infrav1exp.EKSNodegroupConfigUpdateFailedReason,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			eksMock := mock_eksiface.NewMockEKSAPI(mockCtrl)
+
+			replicas := int32(1)
+			s := &NodegroupService{
+				scope: &scope.ManagedMachinePoolScope{
+					Logger: klogr.New(),
+					ControlPlane: &controlplanev1exp.AWSManagedControlPlane{
+						Spec: controlplanev1exp.AWSManagedControlPlaneSpec{EKSClusterName: "cluster"},
+					},
+					ManagedMachinePool: &infrav1exp.AWSManagedMachinePool{
+						ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+						Spec: infrav1exp.AWSManagedMachinePoolSpec{
+							EKSNodegroupName: "ng",
+							Labels:           tc.labels,
+						},
+					},
+					MachinePool: &clusterv1exp.MachinePool{
+						Spec: clusterv1exp.MachinePoolSpec{Replicas: &replicas},
+					},
+				},
+				EKSClient:  eksMock,
+				IAMService: iam.IAMService{Logger: klogr.New()},
+			}
+
+			if tc.expectUpdate {
+				eksMock.EXPECT().UpdateNodegroupConfig(gomock.Any()).DoAndReturn(func(input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
+					g.Expect(input.Labels.AddOrUpdateLabels).To(Equal(map[string]*string{"a": aws.String("2")}))
+					g.Expect(input.Taints).To(BeNil())
+					g.Expect(input.ScalingConfig).To(BeNil())
+					return &eks.UpdateNodegroupConfigOutput{}, tc.updateErr
+				})
+			}
+
+			err := s.reconcileNodegroupConfig(&eks.Nodegroup{
+				NodegroupName: aws.String("ng"),
+				Labels:        map[string]*string{"a": aws.String("1")},
+				ScalingConfig: &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(1)},
+			})
+			g.Expect(err != nil).To(Equal(tc.updateErr != nil))
+
+			condition := conditions.Get(s.scope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(conditions.IsTrue(s.scope.ManagedMachinePool, infrav1exp.EKSNodegroupConfigSyncedCondition)).To(Equal(tc.expectStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectReason))
+		})
+	}
+}
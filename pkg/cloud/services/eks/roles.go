@@ -26,6 +26,7 @@ import (
 
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
 	eksiam "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/eks/iam"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/eks"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
@@ -36,19 +37,19 @@ const (
 	maxIAMRoleNameLength = 64
 )
 
-// NodegroupRolePolicies gives the policies required for a nodegroup role.
-func NodegroupRolePolicies() []string {
+// NodegroupRolePolicies gives the policies required for a nodegroup role in the given partition.
+func NodegroupRolePolicies(partition string) []string {
 	return []string{
-		"arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
-		"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy", //TODO: Can remove when CAPA supports provisioning of OIDC web identity federation with service account token volume projection
-		"arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+		awsarn.AWSManagedPolicy(partition, "AmazonEKSWorkerNodePolicy"),
+		awsarn.AWSManagedPolicy(partition, "AmazonEKS_CNI_Policy"), //TODO: Can remove when CAPA supports provisioning of OIDC web identity federation with service account token volume projection
+		awsarn.AWSManagedPolicy(partition, "AmazonEC2ContainerRegistryReadOnly"),
 	}
 }
 
-// FargateRolePolicies gives the policies required for a fargate role.
-func FargateRolePolicies() []string {
+// FargateRolePolicies gives the policies required for a fargate role in the given partition.
+func FargateRolePolicies(partition string) []string {
 	return []string{
-		"arn:aws:iam::aws:policy/AmazonEKSFargatePodExecutionRolePolicy",
+		awsarn.AWSManagedPolicy(partition, "AmazonEKSFargatePodExecutionRolePolicy"),
 	}
 }
 
@@ -91,10 +92,14 @@ func (s *Service) reconcileControlPlaneIAMRole() error {
 		return nil
 	}
 
+	if _, err := s.EnsurePermissionsBoundary(role); err != nil {
+		return err
+	}
+
 	//TODO: check tags and trust relationship to see if they need updating
 
 	policies := []*string{
-		aws.String("arn:aws:iam::aws:policy/AmazonEKSClusterPolicy"),
+		aws.String(awsarn.AWSManagedPolicy(awsarn.Partition(s.scope.Region()), "AmazonEKSClusterPolicy")),
 	}
 	if s.scope.ControlPlane.Spec.RoleAdditionalPolicies != nil {
 		if !s.scope.AllowAdditionalRoles() && len(*s.scope.ControlPlane.Spec.RoleAdditionalPolicies) > 0 {
@@ -203,7 +208,11 @@ func (s *NodegroupService) reconcileNodegroupIAMRole() error {
 		return errors.Wrapf(err, "error ensuring tags and policy document are set on node role")
 	}
 
-	policies := NodegroupRolePolicies()
+	if _, err := s.EnsurePermissionsBoundary(role); err != nil {
+		return err
+	}
+
+	policies := NodegroupRolePolicies(awsarn.Partition(s.scope.ControlPlane.Spec.Region))
 	_, err = s.EnsurePoliciesAttached(role, aws.StringSlice(policies))
 	if err != nil {
 		return errors.Wrapf(err, "error ensuring policies are attached: %v", policies)
@@ -311,13 +320,18 @@ func (s *FargateService) reconcileFargateIAMRole() (requeue bool, err error) {
 		return updatedRole, errors.Wrapf(err, "error ensuring tags and policy document are set on fargate role")
 	}
 
-	policies := FargateRolePolicies()
+	updatedBoundary, err := s.EnsurePermissionsBoundary(role)
+	if err != nil {
+		return updatedRole, err
+	}
+
+	policies := FargateRolePolicies(awsarn.Partition(s.scope.ControlPlane.Spec.Region))
 	updatedPolicies, err := s.EnsurePoliciesAttached(role, aws.StringSlice(policies))
 	if err != nil {
 		return updatedRole, errors.Wrapf(err, "error ensuring policies are attached: %v", policies)
 	}
 
-	return createdRole || updatedRole || updatedPolicies, nil
+	return createdRole || updatedRole || updatedBoundary || updatedPolicies, nil
 }
 
 func (s *FargateService) deleteFargateIAMRole() (reterr error) {
@@ -59,8 +59,10 @@ func NewService(controlPlaneScope *scope.ManagedControlPlaneScope) *Service {
 			EKSAPI: scope.NewEKSClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
 		},
 		IAMService: iam.IAMService{
-			Logger:    controlPlaneScope.Logger,
-			IAMClient: scope.NewIAMClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
+			Logger:              controlPlaneScope.Logger,
+			IAMClient:           scope.NewIAMClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
+			RolePath:            controlPlaneScope.RolePath(),
+			PermissionsBoundary: controlPlaneScope.RolePermissionsBoundary(),
 		},
 		STSClient: scope.NewSTSClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
 	}
@@ -84,8 +86,10 @@ func NewNodegroupService(machinePoolScope *scope.ManagedMachinePoolScope) *Nodeg
 		AutoscalingClient: scope.NewASGClient(machinePoolScope, machinePoolScope, machinePoolScope, machinePoolScope.ManagedMachinePool),
 		EKSClient:         scope.NewEKSClient(machinePoolScope, machinePoolScope, machinePoolScope, machinePoolScope.ManagedMachinePool),
 		IAMService: iam.IAMService{
-			Logger:    machinePoolScope.Logger,
-			IAMClient: scope.NewIAMClient(machinePoolScope, machinePoolScope, machinePoolScope, machinePoolScope.ManagedMachinePool),
+			Logger:              machinePoolScope.Logger,
+			IAMClient:           scope.NewIAMClient(machinePoolScope, machinePoolScope, machinePoolScope, machinePoolScope.ManagedMachinePool),
+			RolePath:            machinePoolScope.RolePath(),
+			PermissionsBoundary: machinePoolScope.RolePermissionsBoundary(),
 		},
 		STSClient: scope.NewSTSClient(machinePoolScope, machinePoolScope, machinePoolScope, machinePoolScope.ManagedMachinePool),
 	}
@@ -106,8 +110,10 @@ func NewFargateService(fargatePoolScope *scope.FargateProfileScope) *FargateServ
 		scope:     fargatePoolScope,
 		EKSClient: scope.NewEKSClient(fargatePoolScope, fargatePoolScope, fargatePoolScope, fargatePoolScope.FargateProfile),
 		IAMService: iam.IAMService{
-			Logger:    fargatePoolScope.Logger,
-			IAMClient: scope.NewIAMClient(fargatePoolScope, fargatePoolScope, fargatePoolScope, fargatePoolScope.FargateProfile),
+			Logger:              fargatePoolScope.Logger,
+			IAMClient:           scope.NewIAMClient(fargatePoolScope, fargatePoolScope, fargatePoolScope, fargatePoolScope.FargateProfile),
+			RolePath:            fargatePoolScope.RolePath(),
+			PermissionsBoundary: fargatePoolScope.RolePermissionsBoundary(),
 		},
 		STSClient: scope.NewSTSClient(fargatePoolScope, fargatePoolScope, fargatePoolScope, fargatePoolScope.FargateProfile),
 	}
@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/hash"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// bastionSSHPort is the port the load balancer of the bastion hosts forwards to them.
+const bastionSSHPort = 22
+
+// BastionLoadBalancerName returns the name of the network load balancer of the bastion hosts and of its target
+// group, which can't be longer than 32 characters.
+func BastionLoadBalancerName(clusterName string) (string, error) {
+	name := fmt.Sprintf("%s-%s", strings.ReplaceAll(clusterName, ".", "-"), infrav1.BastionRoleTagValue)
+	if len(name) <= 32 {
+		return name, nil
+	}
+
+	// hashSize = 32 - length of "-bastion" = 24
+	shortName, err := hash.Base36TruncatedHash(clusterName, 24)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to create bastion load balancer name")
+	}
+	return fmt.Sprintf("%s-%s", shortName, infrav1.BastionRoleTagValue), nil
+}
+
+// ReconcileBastionLoadBalancer ensures the network load balancer of the bastion hosts exists in the given subnets
+// with a target group and an SSH listener, and returns its status and the ARN of the target group.
+func (s *Service) ReconcileBastionLoadBalancer(subnets infrav1.Subnets) (*infrav1.BastionLoadBalancer, string, error) {
+	name, err := BastionLoadBalancerName(s.scope.Name())
+	if err != nil {
+		return nil, "", err
+	}
+	tags := converters.MapToELBV2Tags(infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.BastionRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}))
+
+	lb, err := s.describeBastionLoadBalancer(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if lb == nil {
+		out, err := s.ELBV2Client.CreateLoadBalancer(&elbv2.CreateLoadBalancerInput{
+			Name:    aws.String(name),
+			Type:    aws.String(elbv2.LoadBalancerTypeEnumNetwork),
+			Scheme:  aws.String(elbv2.LoadBalancerSchemeEnumInternetFacing),
+			Subnets: aws.StringSlice(subnets.IDs()),
+			Tags:    tags,
+		})
+		if err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedCreateBastionLoadBalancer", "Failed to create bastion load balancer: %v", err)
+			return nil, "", errors.Wrapf(err, "failed to create bastion load balancer %q", name)
+		}
+		lb = out.LoadBalancers[0]
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateBastionLoadBalancer", "Created bastion load balancer %q", name)
+	}
+
+	tg, err := s.describeBastionTargetGroup(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if tg == nil {
+		out, err := s.ELBV2Client.CreateTargetGroup(&elbv2.CreateTargetGroupInput{
+			Name:       aws.String(name),
+			Protocol:   aws.String(elbv2.ProtocolEnumTcp),
+			Port:       aws.Int64(bastionSSHPort),
+			VpcId:      aws.String(s.scope.VPC().ID),
+			TargetType: aws.String(elbv2.TargetTypeEnumInstance),
+			Tags:       tags,
+		})
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "failed to create bastion target group %q", name)
+		}
+		tg = out.TargetGroups[0]
+	}
+
+	listeners, err := s.ELBV2Client.DescribeListeners(&elbv2.DescribeListenersInput{
+		LoadBalancerArn: lb.LoadBalancerArn,
+	})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to describe listeners of bastion load balancer %q", name)
+	}
+	if len(listeners.Listeners) == 0 {
+		if _, err := s.ELBV2Client.CreateListener(&elbv2.CreateListenerInput{
+			LoadBalancerArn: lb.LoadBalancerArn,
+			Protocol:        aws.String(elbv2.ProtocolEnumTcp),
+			Port:            aws.Int64(bastionSSHPort),
+			DefaultActions: []*elbv2.Action{
+				{
+					Type:           aws.String(elbv2.ActionTypeEnumForward),
+					TargetGroupArn: tg.TargetGroupArn,
+				},
+			},
+		}); err != nil {
+			return nil, "", errors.Wrapf(err, "failed to create listener of bastion load balancer %q", name)
+		}
+	}
+
+	return &infrav1.BastionLoadBalancer{
+		ARN:     aws.StringValue(lb.LoadBalancerArn),
+		DNSName: aws.StringValue(lb.DNSName),
+	}, aws.StringValue(tg.TargetGroupArn), nil
+}
+
+// DeleteBastionLoadBalancer deletes the network load balancer of the bastion hosts and its target group, if they
+// exist.
+func (s *Service) DeleteBastionLoadBalancer() error {
+	name, err := BastionLoadBalancerName(s.scope.Name())
+	if err != nil {
+		return err
+	}
+
+	lb, err := s.describeBastionLoadBalancer(name)
+	if err != nil {
+		return err
+	}
+	if lb != nil {
+		if _, err := s.ELBV2Client.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: lb.LoadBalancerArn}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDeleteBastionLoadBalancer", "Failed to delete bastion load balancer %q: %v", name, err)
+			return errors.Wrapf(err, "failed to delete bastion load balancer %q", name)
+		}
+		if err := s.ELBV2Client.WaitUntilLoadBalancersDeleted(&elbv2.DescribeLoadBalancersInput{
+			LoadBalancerArns: []*string{lb.LoadBalancerArn},
+		}); err != nil {
+			return errors.Wrapf(err, "failed to wait for bastion load balancer %q to be deleted", name)
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteBastionLoadBalancer", "Deleted bastion load balancer %q", name)
+	}
+
+	tg, err := s.describeBastionTargetGroup(name)
+	if err != nil {
+		return err
+	}
+	if tg != nil {
+		if _, err := s.ELBV2Client.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{TargetGroupArn: tg.TargetGroupArn}); err != nil {
+			return errors.Wrapf(err, "failed to delete bastion target group %q", name)
+		}
+	}
+
+	return nil
+}
+
+// describeBastionLoadBalancer returns the load balancer of the bastion hosts, or nil if it doesn't exist.
+func (s *Service) describeBastionLoadBalancer(name string) (*elbv2.LoadBalancer, error) {
+	out, err := s.ELBV2Client.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		Names: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		if code, ok := awserrors.Code(err); ok && code == elbv2.ErrCodeLoadBalancerNotFoundException {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to describe bastion load balancer %q", name)
+	}
+	if len(out.LoadBalancers) == 0 {
+		return nil, nil
+	}
+	return out.LoadBalancers[0], nil
+}
+
+// describeBastionTargetGroup returns the target group of the bastion hosts, or nil if it doesn't exist.
+func (s *Service) describeBastionTargetGroup(name string) (*elbv2.TargetGroup, error) {
+	out, err := s.ELBV2Client.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		Names: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		if code, ok := awserrors.Code(err); ok && code == elbv2.ErrCodeTargetGroupNotFoundException {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to describe bastion target group %q", name)
+	}
+	if len(out.TargetGroups) == 0 {
+		return nil, nil
+	}
+	return out.TargetGroups[0], nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elb
+
+import (
+	"testing"
+)
+
+func TestBastionLoadBalancerName(t *testing.T) {
+	testCases := []struct {
+		clusterName string
+		expectName  string
+	}{
+		{clusterName: "cluster.example", expectName: "cluster-example-bastion"},
+		{clusterName: "a-cluster-with-a-very-long-name", expectName: "jsxekdw2klsqpnlqktg6sisa-bastion"},
+	}
+
+	for _, tc := range testCases {
+		name, err := BastionLoadBalancerName(tc.clusterName)
+		if err != nil {
+			t.Fatalf("Failed to get the bastion load balancer name: %v", err)
+		}
+		if name != tc.expectName {
+			t.Errorf("Expected name %q, got %q", tc.expectName, name)
+		}
+	}
+}
@@ -47,6 +47,20 @@ const elbResourceType = "elasticloadbalancing:loadbalancer"
 // see: https://docs.aws.amazon.com/elasticloadbalancing/2012-06-01/APIReference/API_DescribeTags.html
 const maxELBsDescribeTagsRequest = 20
 
+// proxyProtocolPolicyName is the name of the policy enabling the PROXY protocol on the connections to the instances.
+// see: https://docs.aws.amazon.com/elasticloadbalancing/latest/classic/enable-proxy-protocol.html
+const proxyProtocolPolicyName = "k8s-proxyprotocol-enabled"
+
+// The settings of the API server load balancers which can be listed in the UnmanagedLoadBalancerSettingsAnnotation.
+const (
+	attributesSetting     = "attributes"
+	healthCheckSetting    = "health-check"
+	proxyProtocolSetting  = "proxy-protocol"
+	subnetsSetting        = "subnets"
+	securityGroupsSetting = "security-groups"
+	listenersSetting      = "listeners"
+)
+
 // ReconcileLoadbalancers reconciles the load balancers for the given cluster.
 func (s *Service) ReconcileLoadbalancers() error {
 	s.scope.V(2).Info("Reconciling load balancers")
@@ -57,39 +71,224 @@ func (s *Service) ReconcileLoadbalancers() error {
 		return err
 	}
 
+	apiELB, err := s.reconcileClassicELB(spec)
+	if err != nil {
+		return err
+	}
+
+	if apiELB.Instances, err = s.describeInstanceHealth(apiELB.Name); err != nil {
+		return err
+	}
+	s.recordInstanceHealthChanges(s.scope.Network().APIServerELB.Instances, apiELB)
+
+	// TODO(vincepri): check if anything has changed and reconcile as necessary.
+	apiELB.DeepCopyInto(&s.scope.Network().APIServerELB)
+	s.scope.V(4).Info("Control plane load balancer", "api-server-elb", apiELB)
+
+	if err := s.reconcileInternalLoadbalancer(); err != nil {
+		return err
+	}
+
+	s.reconcileInstanceHealthCondition()
+
+	s.scope.V(2).Info("Reconcile load balancers completed successfully")
+	return nil
+}
+
+// APIServerELBDNSNameReady returns whether the ELB API describes the API server load balancer with the DNS
+// name recorded in the cluster status. The ELB API may not describe a load balancer right after creating it.
+func (s *Service) APIServerELBDNSNameReady() (bool, error) {
+	lb := s.scope.Network().APIServerELB
+	out, err := s.ELBClient.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: aws.StringSlice([]string{lb.Name}),
+	})
+	if err != nil {
+		if code, ok := awserrors.Code(err); ok && code == elb.ErrCodeAccessPointNotFoundException {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to describe classic load balancer %q", lb.Name)
+	}
+	for _, description := range out.LoadBalancerDescriptions {
+		if aws.StringValue(description.DNSName) == lb.DNSName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// describeInstanceHealth returns the health of the instances registered with a classic load balancer.
+func (s *Service) describeInstanceHealth(name string) ([]infrav1.ClassicELBInstanceHealth, error) {
+	out, err := s.ELBClient.DescribeInstanceHealth(&elb.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String(name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe the health of the instances registered with load balancer %q", name)
+	}
+
+	var instances []infrav1.ClassicELBInstanceHealth
+	for _, state := range out.InstanceStates {
+		instance := infrav1.ClassicELBInstanceHealth{
+			InstanceID: aws.StringValue(state.InstanceId),
+			State:      infrav1.ClassicELBInstanceState(aws.StringValue(state.State)),
+		}
+		// The load balancer describes instances in service as "N/A".
+		if instance.State != infrav1.ClassicELBInstanceStateInService {
+			instance.ReasonCode = aws.StringValue(state.ReasonCode)
+			instance.Description = aws.StringValue(state.Description)
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// recordInstanceHealthChanges emits an event for each instance whose state changed since the last reconciliation
+// of the load balancer, so that a control plane instance failing its health check shows up on the AWSCluster.
+func (s *Service) recordInstanceHealthChanges(previous []infrav1.ClassicELBInstanceHealth, lb *infrav1.ClassicELB) {
+	states := make(map[string]infrav1.ClassicELBInstanceState, len(previous))
+	for _, instance := range previous {
+		states[instance.InstanceID] = instance.State
+	}
+
+	for _, instance := range lb.Instances {
+		if state, ok := states[instance.InstanceID]; ok && state == instance.State {
+			continue
+		}
+		if instance.State == infrav1.ClassicELBInstanceStateInService {
+			record.Eventf(s.scope.InfraCluster(), "InstanceInService", "Instance %s is in service on load balancer %s", instance.InstanceID, lb.Name)
+		} else {
+			record.Warnf(s.scope.InfraCluster(), "InstanceOutOfService", "Instance %s is %s on load balancer %s: %s", instance.InstanceID, instance.State, lb.Name, instance.Description)
+		}
+	}
+}
+
+// reconcileInstanceHealthCondition reports whether the instances registered with the load balancers of the API server
+// are in service.
+func (s *Service) reconcileInstanceHealthCondition() {
+	lbs := []infrav1.ClassicELB{s.scope.Network().APIServerELB}
+	if s.scope.Network().InternalAPIServerELB.Name != "" {
+		lbs = append(lbs, s.scope.Network().InternalAPIServerELB)
+	}
+
+	registered := false
+	var outOfService []string
+	for _, lb := range lbs {
+		for _, instance := range lb.Instances {
+			registered = true
+			if instance.State != infrav1.ClassicELBInstanceStateInService {
+				outOfService = append(outOfService, fmt.Sprintf("instance %s is %s on load balancer %s: %s", instance.InstanceID, instance.State, lb.Name, instance.Description))
+			}
+		}
+	}
+
+	switch {
+	case !registered:
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerInstancesHealthyCondition, infrav1.WaitingForLoadBalancerInstancesReason, clusterv1.ConditionSeverityInfo, "")
+	case len(outOfService) > 0:
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerInstancesHealthyCondition, infrav1.LoadBalancerInstancesOutOfServiceReason, clusterv1.ConditionSeverityWarning, "%s", strings.Join(outOfService, "; "))
+	default:
+		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.LoadBalancerInstancesHealthyCondition)
+	}
+}
+
+// reconcileInternalLoadbalancer creates the internal API server load balancer if one is requested
+// next to the internet-facing one, and deletes it once it isn't anymore.
+func (s *Service) reconcileInternalLoadbalancer() error {
+	if !s.internalLoadBalancerEnabled() {
+		if name := s.scope.Network().InternalAPIServerELB.Name; name != "" {
+			s.scope.V(2).Info("Deleting internal classic load balancer for apiserver", "api-server-elb-name", name)
+			if err := s.deleteClassicELB(name); err != nil {
+				return errors.Wrapf(err, "failed to delete internal apiserver load balancer %q", name)
+			}
+			s.scope.Network().InternalAPIServerELB = infrav1.ClassicELB{}
+		}
+		return nil
+	}
+
+	spec, err := s.getInternalAPIServerClassicELBSpec()
+	if err != nil {
+		return err
+	}
+
+	internalELB, err := s.reconcileClassicELB(spec)
+	if err != nil {
+		return err
+	}
+
+	if internalELB.Instances, err = s.describeInstanceHealth(internalELB.Name); err != nil {
+		return err
+	}
+	s.recordInstanceHealthChanges(s.scope.Network().InternalAPIServerELB.Instances, internalELB)
+
+	internalELB.DeepCopyInto(&s.scope.Network().InternalAPIServerELB)
+	s.scope.V(4).Info("Internal control plane load balancer", "api-server-elb", internalELB)
+	return nil
+}
+
+// internalLoadBalancerEnabled returns whether an internal API server load balancer is requested
+// next to an internet-facing one.
+func (s *Service) internalLoadBalancerEnabled() bool {
+	lb := s.scope.ControlPlaneLoadBalancer()
+	return lb != nil && lb.InternalLoadBalancer && s.scope.ControlPlaneLoadBalancerScheme() == infrav1.ClassicELBSchemeInternetFacing
+}
+
+// reconcileClassicELB creates the classic load balancer of the spec if it doesn't exist,
+// and brings its attributes, tags, subnets and security groups in line with the spec.
+func (s *Service) reconcileClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicELB, error) {
 	// Describe or create.
 	apiELB, err := s.describeClassicELB(spec.Name)
 	if IsNotFound(err) {
 		apiELB, err = s.createClassicELB(spec)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		s.scope.V(2).Info("Created new classic load balancer for apiserver", "api-server-elb-name", apiELB.Name)
 	} else if err != nil {
-		return err
+		return nil, err
 	}
 
-	if !reflect.DeepEqual(spec.Attributes, apiELB.Attributes) {
+	if s.settingManaged(listenersSetting) && !sets.NewString(listenerKeys(spec.Listeners)...).Equal(sets.NewString(listenerKeys(apiELB.Listeners)...)) {
+		if err := s.configureListeners(apiELB.Name, apiELB.Listeners, spec.Listeners); err != nil {
+			return nil, err
+		}
+		apiELB.Listeners = spec.Listeners
+		s.recordSettingUpdated(apiELB.Name, listenersSetting)
+	}
+
+	if s.settingManaged(attributesSetting) && !reflect.DeepEqual(spec.Attributes, apiELB.Attributes) {
 		err := s.configureAttributes(apiELB.Name, spec.Attributes)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		s.recordSettingUpdated(apiELB.Name, attributesSetting)
+	}
+
+	if s.settingManaged(healthCheckSetting) && spec.HealthCheck != nil && !reflect.DeepEqual(spec.HealthCheck, apiELB.HealthCheck) {
+		if err := s.configureHealthCheck(apiELB.Name, spec.HealthCheck); err != nil {
+			return nil, err
+		}
+		apiELB.HealthCheck = spec.HealthCheck
+		s.recordSettingUpdated(apiELB.Name, healthCheckSetting)
+	}
+
+	if s.settingManaged(proxyProtocolSetting) && spec.ProxyProtocol != apiELB.ProxyProtocol {
+		if err := s.configureProxyProtocol(apiELB.Name, spec.ProxyProtocol); err != nil {
+			return nil, err
 		}
+		apiELB.ProxyProtocol = spec.ProxyProtocol
+		s.recordSettingUpdated(apiELB.Name, proxyProtocolSetting)
 	}
 
 	if err := s.reconcileELBTags(apiELB.Name, spec.Tags); err != nil {
-		return errors.Wrapf(err, "failed to reconcile tags for apiserver load balancer %q", apiELB.Name)
+		return nil, errors.Wrapf(err, "failed to reconcile tags for apiserver load balancer %q", apiELB.Name)
 	}
 
 	// Reconcile the subnets and availability zones from the spec
 	// and the ones currently attached to the load balancer.
-	if len(apiELB.SubnetIDs) != len(spec.SubnetIDs) {
-		_, err := s.ELBClient.AttachLoadBalancerToSubnets(&elb.AttachLoadBalancerToSubnetsInput{
-			LoadBalancerName: &apiELB.Name,
-			Subnets:          aws.StringSlice(spec.SubnetIDs),
-		})
-		if err != nil {
-			return errors.Wrapf(err, "failed to attach apiserver load balancer %q to subnets", apiELB.Name)
+	if s.settingManaged(subnetsSetting) {
+		if err := s.reconcileClassicELBSubnets(apiELB, spec.SubnetIDs); err != nil {
+			return nil, err
 		}
 	}
 	if len(apiELB.AvailabilityZones) != len(spec.AvailabilityZones) {
@@ -97,21 +296,115 @@ func (s *Service) ReconcileLoadbalancers() error {
 	}
 
 	// Reconcile the security groups from the spec and the ones currently attached to the load balancer
-	if !sets.NewString(apiELB.SecurityGroupIDs...).Equal(sets.NewString(spec.SecurityGroupIDs...)) {
+	if s.settingManaged(securityGroupsSetting) && !sets.NewString(apiELB.SecurityGroupIDs...).Equal(sets.NewString(spec.SecurityGroupIDs...)) {
 		_, err := s.ELBClient.ApplySecurityGroupsToLoadBalancer(&elb.ApplySecurityGroupsToLoadBalancerInput{
 			LoadBalancerName: &apiELB.Name,
 			SecurityGroups:   aws.StringSlice(spec.SecurityGroupIDs),
 		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to apply security groups to load balancer %q", apiELB.Name)
+			return nil, errors.Wrapf(err, "failed to apply security groups to load balancer %q", apiELB.Name)
 		}
+		apiELB.SecurityGroupIDs = spec.SecurityGroupIDs
+		s.recordSettingUpdated(apiELB.Name, securityGroupsSetting)
+	}
+
+	return apiELB, nil
+}
+
+// settingManaged returns whether a setting of the API server load balancers is restored when it differs from the
+// spec, that is whether it is not listed in the UnmanagedLoadBalancerSettingsAnnotation of the cluster.
+func (s *Service) settingManaged(setting string) bool {
+	unmanaged, ok := s.scope.InfraCluster().GetAnnotations()[infrav1.UnmanagedLoadBalancerSettingsAnnotation]
+	if !ok {
+		return true
+	}
+	for _, name := range strings.Split(unmanaged, ",") {
+		if strings.TrimSpace(name) == setting {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) recordSettingUpdated(name, setting string) {
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulUpdateLoadBalancer", "Updated the %s of load balancer %q to match the spec", setting, name)
+}
+
+// reconcileClassicELBSubnets attaches the subnets of the spec missing from a classic load balancer and detaches the
+// ones which are not in the spec.
+func (s *Service) reconcileClassicELBSubnets(lb *infrav1.ClassicELB, subnetIDs []string) error {
+	current := sets.NewString(lb.SubnetIDs...)
+	desired := sets.NewString(subnetIDs...)
+	if current.Equal(desired) {
+		lb.SubnetIDs = subnetIDs
+		return nil
+	}
+
+	attach := func() error {
+		missing := desired.Difference(current)
+		if missing.Len() == 0 {
+			return nil
+		}
+		if _, err := s.ELBClient.AttachLoadBalancerToSubnets(&elb.AttachLoadBalancerToSubnetsInput{
+			LoadBalancerName: aws.String(lb.Name),
+			Subnets:          aws.StringSlice(missing.List()),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to attach apiserver load balancer %q to subnets", lb.Name)
+		}
+		return nil
+	}
+	detach := func() error {
+		extra := current.Difference(desired)
+		if extra.Len() == 0 {
+			return nil
+		}
+		if _, err := s.ELBClient.DetachLoadBalancerFromSubnets(&elb.DetachLoadBalancerFromSubnetsInput{
+			LoadBalancerName: aws.String(lb.Name),
+			Subnets:          aws.StringSlice(extra.List()),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to detach apiserver load balancer %q from subnets", lb.Name)
+		}
+		return nil
+	}
+
+	// A load balancer has at most one subnet per availability zone and at least one subnet. The extra subnets are
+	// detached first so that the subnets replacing them can be attached, unless none of the subnets would be left.
+	steps := []func() error{detach, attach}
+	if !current.HasAny(subnetIDs...) {
+		steps = []func() error{attach, detach}
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	lb.SubnetIDs = subnetIDs
+	s.recordSettingUpdated(lb.Name, subnetsSetting)
+	return nil
+}
+
+// DiscoverLoadbalancers populates the API server load balancer of an externally managed
+// cluster if one exists with the name the controller would have given it.
+// A missing load balancer is not an error, as the control plane endpoint may be provided otherwise.
+func (s *Service) DiscoverLoadbalancers() error {
+	s.scope.V(2).Info("Discovering load balancers")
+
+	elbName, err := GenerateELBName(s.scope.Name())
+	if err != nil {
+		return err
+	}
+
+	apiELB, err := s.describeClassicELB(elbName)
+	if IsNotFound(err) {
+		s.scope.V(2).Info("No classic load balancer found for apiserver", "api-server-elb-name", elbName)
+		return nil
+	} else if err != nil {
+		return err
 	}
 
-	// TODO(vincepri): check if anything has changed and reconcile as necessary.
 	apiELB.DeepCopyInto(&s.scope.Network().APIServerELB)
 	s.scope.V(4).Info("Control plane load balancer", "api-server-elb", apiELB)
-
-	s.scope.V(2).Info("Reconcile load balancers completed successfully")
 	return nil
 }
 
@@ -130,6 +423,16 @@ func (s *Service) DeleteLoadbalancers() error {
 	}
 	elbs = append(elbs, elbName)
 
+	internalELBName := s.scope.Network().InternalAPIServerELB.Name
+	if internalELBName == "" && s.internalLoadBalancerEnabled() {
+		if internalELBName, err = GenerateInternalELBName(s.scope.Name()); err != nil {
+			return err
+		}
+	}
+	if internalELBName != "" {
+		elbs = append(elbs, internalELBName)
+	}
+
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
 		return err
@@ -143,7 +446,7 @@ func (s *Service) DeleteLoadbalancers() error {
 		}
 	}
 
-	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (done bool, err error) {
+	if err := wait.WaitForWithRetryable(wait.NewBackoffWithTimeout(s.scope.LoadBalancerReadyTimeout()), func() (done bool, err error) {
 		elbs, err := s.listOwnedELBs()
 		if err != nil {
 			return false, err
@@ -151,6 +454,10 @@ func (s *Service) DeleteLoadbalancers() error {
 
 		_, err = s.describeClassicELB(elbName)
 		done = len(elbs) == 0 && IsNotFound(err)
+		if done && internalELBName != "" {
+			_, err = s.describeClassicELB(internalELBName)
+			done = IsNotFound(err)
+		}
 		return done, nil
 	}); err != nil {
 		return errors.Wrapf(err, "failed to wait for %q ELB deletions", s.scope.Name())
@@ -175,13 +482,25 @@ func (s *Service) RegisterInstanceWithClassicELB(instanceID, loadBalancer string
 	return nil
 }
 
-// InstanceIsRegisteredWithAPIServerELB returns true if the instance is already registered with the APIServer ELB.
+// InstanceIsRegisteredWithAPIServerELB returns true if the instance is already registered with the APIServer ELB,
+// and with the internal APIServer ELB if the cluster has one.
 func (s *Service) InstanceIsRegisteredWithAPIServerELB(i *infrav1.Instance) (bool, error) {
-	name, err := GenerateELBName(s.scope.Name())
+	names, err := s.apiServerELBNames()
 	if err != nil {
 		return false, err
 	}
 
+	for _, name := range names {
+		registered, err := s.instanceIsRegisteredWithClassicELB(i, name)
+		if err != nil || !registered {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (s *Service) instanceIsRegisteredWithClassicELB(i *infrav1.Instance, name string) (bool, error) {
 	input := &elb.DescribeLoadBalancersInput{
 		LoadBalancerNames: aws.StringSlice([]string{name}),
 	}
@@ -203,12 +522,24 @@ func (s *Service) InstanceIsRegisteredWithAPIServerELB(i *infrav1.Instance) (boo
 	return false, nil
 }
 
-// RegisterInstanceWithAPIServerELB registers an instance with a classic ELB.
+// RegisterInstanceWithAPIServerELB registers an instance with the APIServer ELB,
+// and with the internal APIServer ELB if the cluster has one.
 func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
-	name, err := GenerateELBName(s.scope.Name())
+	names, err := s.apiServerELBNames()
 	if err != nil {
 		return err
 	}
+
+	for _, name := range names {
+		if err := s.registerInstanceWithAPIServerELB(i, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) registerInstanceWithAPIServerELB(i *infrav1.Instance, name string) error {
 	out, err := s.describeClassicELB(name)
 	if err != nil {
 		return err
@@ -222,13 +553,13 @@ func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
 	instanceAZ := subnet.AvailabilityZone
 	found := false
 	for _, subnetID := range out.SubnetIDs {
-		if subnet := s.scope.Subnets().FindByID(subnetID); subnet != nil && instanceAZ == subnet.AvailabilityZone {
+		if elbSubnet := s.scope.Subnets().FindByID(subnetID); elbSubnet != nil && subnet.IsInSameZone(elbSubnet) {
 			found = true
 			break
 		}
 	}
 	if !found {
-		return errors.Errorf("failed to register instance with APIServer ELB %q: instance is in availability zone %q, no public subnets attached to the ELB in the same zone", name, instanceAZ)
+		return errors.Errorf("failed to register instance with APIServer ELB %q: instance is in availability zone %q, no subnets attached to the ELB in the same zone", name, instanceAZ)
 	}
 
 	input := &elb.RegisterInstancesWithLoadBalancerInput{
@@ -240,31 +571,51 @@ func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
 	return err
 }
 
-// DeregisterInstanceFromAPIServerELB de-registers an instance from a classic ELB.
+// DeregisterInstanceFromAPIServerELB de-registers an instance from the APIServer ELB,
+// and from the internal APIServer ELB if the cluster has one.
 func (s *Service) DeregisterInstanceFromAPIServerELB(i *infrav1.Instance) error {
-	name, err := GenerateELBName(s.scope.Name())
+	names, err := s.apiServerELBNames()
 	if err != nil {
 		return err
 	}
 
-	input := &elb.DeregisterInstancesFromLoadBalancerInput{
-		Instances:        []*elb.Instance{{InstanceId: aws.String(i.ID)}},
-		LoadBalancerName: aws.String(name),
+	for _, name := range names {
+		input := &elb.DeregisterInstancesFromLoadBalancerInput{
+			Instances:        []*elb.Instance{{InstanceId: aws.String(i.ID)}},
+			LoadBalancerName: aws.String(name),
+		}
+
+		if _, err := s.ELBClient.DeregisterInstancesFromLoadBalancer(input); err != nil {
+			if aerr, ok := err.(awserr.Error); ok {
+				switch aerr.Code() {
+				case elb.ErrCodeAccessPointNotFoundException, elb.ErrCodeInvalidEndPointException:
+					// Ignoring LoadBalancerNotFound and InvalidInstance when deregistering
+					continue
+				}
+			}
+			return err
+		}
 	}
+	return nil
+}
 
-	_, err = s.ELBClient.DeregisterInstancesFromLoadBalancer(input)
+// apiServerELBNames returns the names of the load balancers the control plane instances are registered with.
+func (s *Service) apiServerELBNames() ([]string, error) {
+	name, err := GenerateELBName(s.scope.Name())
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			case elb.ErrCodeAccessPointNotFoundException, elb.ErrCodeInvalidEndPointException:
-				// Ignoring LoadBalancerNotFound and InvalidInstance when deregistering
-				return nil
-			default:
-				return err
-			}
+		return nil, err
+	}
+	names := []string{name}
+
+	if s.internalLoadBalancerEnabled() {
+		internalName, err := GenerateInternalELBName(s.scope.Name())
+		if err != nil {
+			return nil, err
 		}
+		names = append(names, internalName)
 	}
-	return err
+
+	return names, nil
 }
 
 // GenerateELBName generates a formatted ELB name via either
@@ -303,12 +654,95 @@ func generateHashedELBName(clusterName string) (string, error) {
 	return fmt.Sprintf("%s-%s", shortName, "k8s"), nil
 }
 
+// GenerateInternalELBName generates a formatted name for the internal ELB of the API server via either
+// concatenating the cluster name to the "-apiserver-internal" suffix
+// or computing a hash for clusters with names above 32 characters.
+func GenerateInternalELBName(clusterName string) (string, error) {
+	elbCompatibleClusterName := strings.ReplaceAll(clusterName, ".", "-")
+	standardELBName := fmt.Sprintf("%s-%s", elbCompatibleClusterName, infrav1.InternalAPIServerRoleTagValue)
+	if len(standardELBName) <= 32 {
+		return standardELBName, nil
+	}
+
+	// hashSize = 32 - length of "k8s-int" - length of "-" = 24
+	shortName, err := hash.Base36TruncatedHash(clusterName, 24)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to create internal ELB name")
+	}
+
+	return fmt.Sprintf("%s-%s", shortName, "k8s-int"), nil
+}
+
 func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 	elbName, err := GenerateELBName(s.scope.Name())
 	if err != nil {
 		return nil, err
 	}
 
+	res := s.newAPIServerClassicELBSpec(elbName, s.scope.ControlPlaneLoadBalancerScheme(), infrav1.APIServerRoleTagValue)
+
+	// If subnet IDs have been specified for this load balancer
+	if s.scope.ControlPlaneLoadBalancer() != nil && len(s.scope.ControlPlaneLoadBalancer().Subnets) > 0 {
+		// This set of subnets may not match the subnets specified on the Cluster, so we may not have already discovered them
+		// We need to call out to AWS to describe them just in case
+		input := &ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice(s.scope.ControlPlaneLoadBalancer().Subnets),
+		}
+		out, err := s.EC2Client.DescribeSubnets(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, sn := range out.Subnets {
+			res.AvailabilityZones = append(res.AvailabilityZones, *sn.AvailabilityZone)
+			res.SubnetIDs = append(res.SubnetIDs, *sn.SubnetId)
+		}
+	} else {
+		subnets := s.scope.Subnets().FilterPrivate()
+
+		if s.scope.ControlPlaneLoadBalancerScheme() == infrav1.ClassicELBSchemeInternetFacing {
+			subnets = s.scope.Subnets().FilterPublic()
+		}
+
+		res.AvailabilityZones, res.SubnetIDs = subnetsPerZone(subnets)
+	}
+
+	return res, nil
+}
+
+// getInternalAPIServerClassicELBSpec returns the spec of the internal load balancer of the API server,
+// which always uses the private subnets of the cluster, even if subnets are set for the internet-facing one.
+func (s *Service) getInternalAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
+	elbName, err := GenerateInternalELBName(s.scope.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	res := s.newAPIServerClassicELBSpec(elbName, infrav1.ClassicELBSchemeInternal, infrav1.InternalAPIServerRoleTagValue)
+	res.AvailabilityZones, res.SubnetIDs = subnetsPerZone(s.scope.Subnets().FilterPrivate())
+	return res, nil
+}
+
+// subnetsPerZone returns the availability zones of the subnets and the first subnet in each of them,
+// as the load balancer APIs require us to only attach one subnet for each AZ.
+func subnetsPerZone(subnets infrav1.Subnets) (zones []string, subnetIDs []string) {
+subnetLoop:
+	for _, sn := range subnets {
+		for _, az := range zones {
+			if sn.AvailabilityZone == az {
+				// If we already attached another subnet in the same AZ, there is no need to
+				// add this subnet to the list of the ELB's subnets.
+				continue subnetLoop
+			}
+		}
+		zones = append(zones, sn.AvailabilityZone)
+		subnetIDs = append(subnetIDs, sn.ID)
+	}
+	return zones, subnetIDs
+}
+
+// newAPIServerClassicELBSpec returns the listeners, health check, security groups, attributes and tags
+// shared by the load balancers of the API server.
+func (s *Service) newAPIServerClassicELBSpec(elbName string, scheme infrav1.ClassicELBScheme, role string) *infrav1.ClassicELB {
 	securityGroupIDs := []string{}
 	controlPlaneLoadBalancer := s.scope.ControlPlaneLoadBalancer()
 	if controlPlaneLoadBalancer != nil && len(controlPlaneLoadBalancer.AdditionalSecurityGroups) != 0 {
@@ -318,17 +752,17 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 
 	res := &infrav1.ClassicELB{
 		Name:   elbName,
-		Scheme: s.scope.ControlPlaneLoadBalancerScheme(),
+		Scheme: scheme,
 		Listeners: []infrav1.ClassicELBListener{
 			{
 				Protocol:         infrav1.ClassicELBProtocolTCP,
 				Port:             int64(s.scope.APIServerPort()),
 				InstanceProtocol: infrav1.ClassicELBProtocolTCP,
-				InstancePort:     6443,
+				InstancePort:     int64(s.scope.APIServerInstancePort()),
 			},
 		},
 		HealthCheck: &infrav1.ClassicELBHealthCheck{
-			Target:             fmt.Sprintf("%v:%d", infrav1.ClassicELBProtocolSSL, 6443),
+			Target:             fmt.Sprintf("%v:%d", infrav1.ClassicELBProtocolSSL, s.scope.APIServerInstancePort()),
 			Interval:           10 * time.Second,
 			Timeout:            5 * time.Second,
 			HealthyThreshold:   5,
@@ -340,56 +774,26 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 		},
 	}
 
-	if s.scope.ControlPlaneLoadBalancer() != nil {
-		res.Attributes.CrossZoneLoadBalancing = s.scope.ControlPlaneLoadBalancer().CrossZoneLoadBalancing
+	if controlPlaneLoadBalancer != nil {
+		res.Attributes.CrossZoneLoadBalancing = controlPlaneLoadBalancer.CrossZoneLoadBalancing
+		if controlPlaneLoadBalancer.IdleTimeout != nil {
+			res.Attributes.IdleTimeout = controlPlaneLoadBalancer.IdleTimeout.Duration
+		}
+		if controlPlaneLoadBalancer.ConnectionDrainingTimeout != nil {
+			res.Attributes.ConnectionDrainingTimeout = controlPlaneLoadBalancer.ConnectionDrainingTimeout.Duration
+		}
+		res.ProxyProtocol = controlPlaneLoadBalancer.ProxyProtocol
 	}
 
 	res.Tags = infrav1.Build(infrav1.BuildParams{
 		ClusterName: s.scope.Name(),
 		Lifecycle:   infrav1.ResourceLifecycleOwned,
 		Name:        aws.String(elbName),
-		Role:        aws.String(infrav1.APIServerRoleTagValue),
+		Role:        aws.String(role),
 		Additional:  s.scope.AdditionalTags(),
 	})
 
-	// If subnet IDs have been specified for this load balancer
-	if s.scope.ControlPlaneLoadBalancer() != nil && len(s.scope.ControlPlaneLoadBalancer().Subnets) > 0 {
-		// This set of subnets may not match the subnets specified on the Cluster, so we may not have already discovered them
-		// We need to call out to AWS to describe them just in case
-		input := &ec2.DescribeSubnetsInput{
-			SubnetIds: aws.StringSlice(s.scope.ControlPlaneLoadBalancer().Subnets),
-		}
-		out, err := s.EC2Client.DescribeSubnets(input)
-		if err != nil {
-			return nil, err
-		}
-		for _, sn := range out.Subnets {
-			res.AvailabilityZones = append(res.AvailabilityZones, *sn.AvailabilityZone)
-			res.SubnetIDs = append(res.SubnetIDs, *sn.SubnetId)
-		}
-	} else {
-		// The load balancer APIs require us to only attach one subnet for each AZ.
-		subnets := s.scope.Subnets().FilterPrivate()
-
-		if s.scope.ControlPlaneLoadBalancerScheme() == infrav1.ClassicELBSchemeInternetFacing {
-			subnets = s.scope.Subnets().FilterPublic()
-		}
-
-	subnetLoop:
-		for _, sn := range subnets {
-			for _, az := range res.AvailabilityZones {
-				if sn.AvailabilityZone == az {
-					// If we already attached another subnet in the same AZ, there is no need to
-					// add this subnet to the list of the ELB's subnets.
-					continue subnetLoop
-				}
-			}
-			res.AvailabilityZones = append(res.AvailabilityZones, sn.AvailabilityZone)
-			res.SubnetIDs = append(res.SubnetIDs, sn.ID)
-		}
-	}
-
-	return res, nil
+	return res
 }
 
 func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicELB, error) {
@@ -416,22 +820,14 @@ func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicEL
 	}
 
 	if spec.HealthCheck != nil {
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if _, err := s.ELBClient.ConfigureHealthCheck(&elb.ConfigureHealthCheckInput{
-				LoadBalancerName: aws.String(spec.Name),
-				HealthCheck: &elb.HealthCheck{
-					Target:             aws.String(spec.HealthCheck.Target),
-					Interval:           aws.Int64(int64(spec.HealthCheck.Interval.Seconds())),
-					Timeout:            aws.Int64(int64(spec.HealthCheck.Timeout.Seconds())),
-					HealthyThreshold:   aws.Int64(spec.HealthCheck.HealthyThreshold),
-					UnhealthyThreshold: aws.Int64(spec.HealthCheck.UnhealthyThreshold),
-				},
-			}); err != nil {
-				return false, err
-			}
-			return true, nil
-		}, awserrors.LoadBalancerNotFound); err != nil {
-			return nil, errors.Wrapf(err, "failed to configure health check for classic load balancer: %v", spec)
+		if err := s.configureHealthCheck(spec.Name, spec.HealthCheck); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.ProxyProtocol {
+		if err := s.configureProxyProtocol(spec.Name, true); err != nil {
+			return nil, err
 		}
 	}
 
@@ -442,6 +838,85 @@ func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicEL
 	return res, nil
 }
 
+// configureListeners replaces the listeners of a classic load balancer which differ from the wanted ones. A listener
+// can't be modified, so a listener whose instance port changes is deleted before being created again.
+func (s *Service) configureListeners(name string, current, wanted []infrav1.ClassicELBListener) error {
+	wantedKeys := sets.NewString(listenerKeys(wanted)...)
+	stale := []*int64{}
+	kept := sets.NewString()
+	for _, ln := range current {
+		if wantedKeys.Has(listenerKey(ln)) {
+			kept.Insert(listenerKey(ln))
+			continue
+		}
+		stale = append(stale, aws.Int64(ln.Port))
+	}
+
+	if len(stale) > 0 {
+		if _, err := s.ELBClient.DeleteLoadBalancerListeners(&elb.DeleteLoadBalancerListenersInput{
+			LoadBalancerName:  aws.String(name),
+			LoadBalancerPorts: stale,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete the listeners of classic load balancer: %v", name)
+		}
+	}
+
+	input := &elb.CreateLoadBalancerListenersInput{LoadBalancerName: aws.String(name)}
+	for _, ln := range wanted {
+		if kept.Has(listenerKey(ln)) {
+			continue
+		}
+		input.Listeners = append(input.Listeners, &elb.Listener{
+			Protocol:         aws.String(string(ln.Protocol)),
+			LoadBalancerPort: aws.Int64(ln.Port),
+			InstanceProtocol: aws.String(string(ln.InstanceProtocol)),
+			InstancePort:     aws.Int64(ln.InstancePort),
+		})
+	}
+	if len(input.Listeners) > 0 {
+		if _, err := s.ELBClient.CreateLoadBalancerListeners(input); err != nil {
+			return errors.Wrapf(err, "failed to create the listeners of classic load balancer: %v", name)
+		}
+	}
+
+	return nil
+}
+
+// listenerKey identifies a listener of a classic load balancer by its ports and protocols.
+func listenerKey(ln infrav1.ClassicELBListener) string {
+	return fmt.Sprintf("%s:%d/%s:%d", ln.Protocol, ln.Port, ln.InstanceProtocol, ln.InstancePort)
+}
+
+func listenerKeys(listeners []infrav1.ClassicELBListener) []string {
+	keys := make([]string, 0, len(listeners))
+	for _, ln := range listeners {
+		keys = append(keys, listenerKey(ln))
+	}
+	return keys
+}
+
+func (s *Service) configureHealthCheck(name string, healthCheck *infrav1.ClassicELBHealthCheck) error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoffWithTimeout(s.scope.LoadBalancerReadyTimeout()), func() (bool, error) {
+		if _, err := s.ELBClient.ConfigureHealthCheck(&elb.ConfigureHealthCheckInput{
+			LoadBalancerName: aws.String(name),
+			HealthCheck: &elb.HealthCheck{
+				Target:             aws.String(healthCheck.Target),
+				Interval:           aws.Int64(int64(healthCheck.Interval.Seconds())),
+				Timeout:            aws.Int64(int64(healthCheck.Timeout.Seconds())),
+				HealthyThreshold:   aws.Int64(healthCheck.HealthyThreshold),
+				UnhealthyThreshold: aws.Int64(healthCheck.UnhealthyThreshold),
+			},
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.LoadBalancerNotFound); err != nil {
+		return errors.Wrapf(err, "failed to configure health check for classic load balancer: %v", name)
+	}
+
+	return nil
+}
+
 func (s *Service) configureAttributes(name string, attributes infrav1.ClassicELBAttributes) error {
 	attrs := &elb.ModifyLoadBalancerAttributesInput{
 		LoadBalancerName: aws.String(name),
@@ -458,7 +933,14 @@ func (s *Service) configureAttributes(name string, attributes infrav1.ClassicELB
 		}
 	}
 
-	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+	attrs.LoadBalancerAttributes.ConnectionDraining = &elb.ConnectionDraining{
+		Enabled: aws.Bool(attributes.ConnectionDrainingTimeout > 0),
+	}
+	if attributes.ConnectionDrainingTimeout > 0 {
+		attrs.LoadBalancerAttributes.ConnectionDraining.Timeout = aws.Int64(int64(attributes.ConnectionDrainingTimeout.Seconds()))
+	}
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoffWithTimeout(s.scope.LoadBalancerReadyTimeout()), func() (bool, error) {
 		if _, err := s.ELBClient.ModifyLoadBalancerAttributes(attrs); err != nil {
 			return false, err
 		}
@@ -470,6 +952,47 @@ func (s *Service) configureAttributes(name string, attributes infrav1.ClassicELB
 	return nil
 }
 
+// configureProxyProtocol enables or disables the PROXY protocol on the connections from a classic load balancer
+// to the API server port of the instances.
+func (s *Service) configureProxyProtocol(name string, enabled bool) error {
+	policyNames := []*string{}
+	if enabled {
+		input := &elb.CreateLoadBalancerPolicyInput{
+			LoadBalancerName: aws.String(name),
+			PolicyName:       aws.String(proxyProtocolPolicyName),
+			PolicyTypeName:   aws.String("ProxyProtocolPolicyType"),
+			PolicyAttributes: []*elb.PolicyAttribute{
+				{
+					AttributeName:  aws.String("ProxyProtocol"),
+					AttributeValue: aws.String("true"),
+				},
+			},
+		}
+		if err := wait.WaitForWithRetryable(wait.NewBackoffWithTimeout(s.scope.LoadBalancerReadyTimeout()), func() (bool, error) {
+			if _, err := s.ELBClient.CreateLoadBalancerPolicy(input); err != nil {
+				if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elb.ErrCodeDuplicatePolicyNameException {
+					return true, nil
+				}
+				return false, err
+			}
+			return true, nil
+		}, awserrors.LoadBalancerNotFound); err != nil {
+			return errors.Wrapf(err, "failed to create proxy protocol policy for classic load balancer: %v", name)
+		}
+		policyNames = aws.StringSlice([]string{proxyProtocolPolicyName})
+	}
+
+	if _, err := s.ELBClient.SetLoadBalancerPoliciesForBackendServer(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+		LoadBalancerName: aws.String(name),
+		InstancePort:     aws.Int64(int64(s.scope.APIServerInstancePort())),
+		PolicyNames:      policyNames,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to set the backend server policies of classic load balancer: %v", name)
+	}
+
+	return nil
+}
+
 func (s *Service) deleteClassicELB(name string) error {
 	input := &elb.DeleteLoadBalancerInput{
 		LoadBalancerName: aws.String(name),
@@ -567,6 +1090,18 @@ func (s *Service) listOwnedELBs() ([]string, error) {
 	return arns, nil
 }
 
+// expectedClassicELBScheme returns the scheme the load balancer of the API server with the given name must have,
+// or nil if the scheme of the control plane load balancer isn't set.
+func (s *Service) expectedClassicELBScheme(name string) *infrav1.ClassicELBScheme {
+	if internalName, err := GenerateInternalELBName(s.scope.Name()); err == nil && name == internalName {
+		return &infrav1.ClassicELBSchemeInternal
+	}
+	if s.scope.ControlPlaneLoadBalancer() != nil {
+		return s.scope.ControlPlaneLoadBalancer().Scheme
+	}
+	return nil
+}
+
 func (s *Service) describeClassicELB(name string) (*infrav1.ClassicELB, error) {
 	input := &elb.DescribeLoadBalancersInput{
 		LoadBalancerNames: aws.StringSlice([]string{name}),
@@ -598,9 +1133,8 @@ func (s *Service) describeClassicELB(name string) (*infrav1.ClassicELB, error) {
 			name, *out.LoadBalancerDescriptions[0].VPCId)
 	}
 
-	if s.scope.ControlPlaneLoadBalancer() != nil &&
-		s.scope.ControlPlaneLoadBalancer().Scheme != nil &&
-		string(*s.scope.ControlPlaneLoadBalancer().Scheme) != aws.StringValue(out.LoadBalancerDescriptions[0].Scheme) {
+	if scheme := s.expectedClassicELBScheme(name); scheme != nil &&
+		string(*scheme) != aws.StringValue(out.LoadBalancerDescriptions[0].Scheme) {
 		return nil, errors.Errorf(
 			"ELB names must be unique within a region: %q ELB already exists in this region with a different scheme %q",
 			name, *out.LoadBalancerDescriptions[0].Scheme)
@@ -613,7 +1147,7 @@ func (s *Service) describeClassicELB(name string) (*infrav1.ClassicELB, error) {
 		return nil, errors.Wrapf(err, "failed to describe classic load balancer %q attributes", name)
 	}
 
-	return fromSDKTypeToClassicELB(out.LoadBalancerDescriptions[0], outAtt.LoadBalancerAttributes), nil
+	return fromSDKTypeToClassicELB(out.LoadBalancerDescriptions[0], outAtt.LoadBalancerAttributes, int64(s.scope.APIServerInstancePort())), nil
 }
 
 func (s *Service) reconcileELBTags(name string, desiredTags map[string]string) error {
@@ -667,7 +1201,9 @@ func (s *Service) reconcileELBTags(name string, desiredTags map[string]string) e
 	return nil
 }
 
-func fromSDKTypeToClassicELB(v *elb.LoadBalancerDescription, attrs *elb.LoadBalancerAttributes) *infrav1.ClassicELB {
+// fromSDKTypeToClassicELB converts a classic load balancer, whose proxy protocol setting is read from the policies
+// of the backend servers on the given instance port.
+func fromSDKTypeToClassicELB(v *elb.LoadBalancerDescription, attrs *elb.LoadBalancerAttributes, instancePort int64) *infrav1.ClassicELB {
 	res := &infrav1.ClassicELB{
 		Name:             aws.StringValue(v.LoadBalancerName),
 		Scheme:           infrav1.ClassicELBScheme(*v.Scheme),
@@ -676,12 +1212,49 @@ func fromSDKTypeToClassicELB(v *elb.LoadBalancerDescription, attrs *elb.LoadBala
 		DNSName:          aws.StringValue(v.DNSName),
 	}
 
+	for _, ld := range v.ListenerDescriptions {
+		if ld.Listener == nil {
+			continue
+		}
+		res.Listeners = append(res.Listeners, infrav1.ClassicELBListener{
+			Protocol:         infrav1.ClassicELBProtocol(aws.StringValue(ld.Listener.Protocol)),
+			Port:             aws.Int64Value(ld.Listener.LoadBalancerPort),
+			InstanceProtocol: infrav1.ClassicELBProtocol(aws.StringValue(ld.Listener.InstanceProtocol)),
+			InstancePort:     aws.Int64Value(ld.Listener.InstancePort),
+		})
+	}
+
+	if v.HealthCheck != nil {
+		res.HealthCheck = &infrav1.ClassicELBHealthCheck{
+			Target:             aws.StringValue(v.HealthCheck.Target),
+			Interval:           time.Duration(aws.Int64Value(v.HealthCheck.Interval)) * time.Second,
+			Timeout:            time.Duration(aws.Int64Value(v.HealthCheck.Timeout)) * time.Second,
+			HealthyThreshold:   aws.Int64Value(v.HealthCheck.HealthyThreshold),
+			UnhealthyThreshold: aws.Int64Value(v.HealthCheck.UnhealthyThreshold),
+		}
+	}
+
 	if attrs.ConnectionSettings != nil && attrs.ConnectionSettings.IdleTimeout != nil {
 		res.Attributes.IdleTimeout = time.Duration(*attrs.ConnectionSettings.IdleTimeout) * time.Second
 	}
 
 	res.Attributes.CrossZoneLoadBalancing = aws.BoolValue(attrs.CrossZoneLoadBalancing.Enabled)
 
+	if attrs.ConnectionDraining != nil && aws.BoolValue(attrs.ConnectionDraining.Enabled) {
+		res.Attributes.ConnectionDrainingTimeout = time.Duration(aws.Int64Value(attrs.ConnectionDraining.Timeout)) * time.Second
+	}
+
+	for _, backend := range v.BackendServerDescriptions {
+		if aws.Int64Value(backend.InstancePort) != instancePort {
+			continue
+		}
+		for _, policyName := range aws.StringValueSlice(backend.PolicyNames) {
+			if policyName == proxyProtocolPolicyName {
+				res.ProxyProtocol = true
+			}
+		}
+	}
+
 	return res
 }
 
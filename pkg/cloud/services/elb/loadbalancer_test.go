@@ -19,7 +19,9 @@ package elb
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -27,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
@@ -36,6 +39,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_resourcegroupstaggingapiiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -83,6 +87,46 @@ func TestGenerateELBName(t *testing.T) {
 	}
 }
 
+func TestGenerateInternalELBName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{
+			name:     "test",
+			expected: "test-apiserver-internal",
+		},
+		{
+			name:     "0123456789012",
+			expected: "0123456789012-apiserver-internal",
+		},
+		{
+			name:     "01234567890123",
+			expected: "1ey0kio7bcg3ejf18m5mzup4-k8s-int",
+		},
+		{
+			name:     "anotherverylongtoolongname",
+			expected: "228l7f5anm0kbuoq81bn0yey-k8s-int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elbName, err := GenerateInternalELBName(tt.name)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if elbName != tt.expected {
+				t.Errorf("expected ELB name: %v, got name: %v", tt.expected, elbName)
+			}
+
+			if len(elbName) > 32 {
+				t.Errorf("ELB name too long: %v vs. %s", len(elbName), "32")
+			}
+		})
+	}
+}
+
 func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -146,6 +190,26 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "load balancer config with idle timeout, connection draining and proxy protocol",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				IdleTimeout:               &metav1.Duration{Duration: 30 * time.Minute},
+				ConnectionDrainingTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+				ProxyProtocol:             true,
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expect: func(t *testing.T, res *infrav1.ClassicELB) {
+				if res.Attributes.IdleTimeout != 30*time.Minute {
+					t.Errorf("Expected load balancer to have an idle timeout of 30m, got %v", res.Attributes.IdleTimeout)
+				}
+				if res.Attributes.ConnectionDrainingTimeout != 5*time.Minute {
+					t.Errorf("Expected load balancer to have a connection draining timeout of 5m, got %v", res.Attributes.ConnectionDrainingTimeout)
+				}
+				if !res.ProxyProtocol {
+					t.Error("Expected load balancer to have the proxy protocol enabled")
+				}
+			},
+		},
 		{
 			name: "load balancer config with additional security groups specified",
 			lb: &infrav1.AWSLoadBalancerSpec{
@@ -158,6 +222,21 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "load balancer config with instance port specified",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				InstancePort: aws.Int32(8443),
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expect: func(t *testing.T, res *infrav1.ClassicELB) {
+				if len(res.Listeners) != 1 || res.Listeners[0].Port != 6443 || res.Listeners[0].InstancePort != 8443 {
+					t.Errorf("Expected load balancer to forward port 6443 to instance port 8443, got %v", res.Listeners)
+				}
+				if res.HealthCheck.Target != "SSL:8443" {
+					t.Errorf("Expected load balancer to check the health of instance port 8443, got %v", res.HealthCheck.Target)
+				}
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -205,6 +284,266 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestGetInternalAPIServerClassicELBSpec(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "bar",
+			},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: infrav1.AWSClusterSpec{
+				ControlPlaneLoadBalancer: &infrav1.AWSLoadBalancerSpec{
+					Subnets:              []string{"subnet-public-1"},
+					InternalLoadBalancer: true,
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{ID: "subnet-public-1", AvailabilityZone: "us-east-1a", IsPublic: true},
+						{ID: "subnet-private-1", AvailabilityZone: "us-east-1a"},
+						{ID: "subnet-private-2", AvailabilityZone: "us-east-1a"},
+						{ID: "subnet-private-3", AvailabilityZone: "us-east-1b"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{
+		scope: clusterScope,
+	}
+
+	spec, err := s.getInternalAPIServerClassicELBSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.Name != "bar-apiserver-internal" {
+		t.Errorf("Expected load balancer to be named %q, got %q", "bar-apiserver-internal", spec.Name)
+	}
+	if spec.Scheme != infrav1.ClassicELBSchemeInternal {
+		t.Errorf("Expected load balancer to be internal, got %q", spec.Scheme)
+	}
+	if spec.Tags[infrav1.NameAWSClusterAPIRole] != infrav1.InternalAPIServerRoleTagValue {
+		t.Errorf("Expected load balancer to have role %q, got %q", infrav1.InternalAPIServerRoleTagValue, spec.Tags[infrav1.NameAWSClusterAPIRole])
+	}
+	if expected := []string{"subnet-private-1", "subnet-private-3"}; !reflect.DeepEqual(spec.SubnetIDs, expected) {
+		t.Errorf("Expected load balancer to be configured for subnets %v, got %v", expected, spec.SubnetIDs)
+	}
+}
+
+func TestRegisterInstanceWithAPIServerELB(t *testing.T) {
+	tests := []struct {
+		name   string
+		lb     *infrav1.AWSLoadBalancerSpec
+		expect func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name: "internet-facing load balancer only",
+			lb:   nil,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				expectRegisterInstance(m, "bar-apiserver", infrav1.ClassicELBSchemeInternetFacing)
+			},
+		},
+		{
+			name: "internal load balancer next to the internet-facing one",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				InternalLoadBalancer: true,
+			},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				expectRegisterInstance(m, "bar-apiserver", infrav1.ClassicELBSchemeInternetFacing)
+				expectRegisterInstance(m, "bar-apiserver-internal", infrav1.ClassicELBSchemeInternal)
+			},
+		},
+		{
+			name: "internal load balancer ignored for an internal scheme",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				Scheme:               &infrav1.ClassicELBSchemeInternal,
+				InternalLoadBalancer: true,
+			},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				expectRegisterInstance(m, "bar-apiserver", infrav1.ClassicELBSchemeInternal)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbAPIMocks := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						ControlPlaneLoadBalancer: tc.lb,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: infrav1.Subnets{
+								{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbAPIMocks.EXPECT())
+
+			s := &Service{
+				scope:     clusterScope,
+				ELBClient: elbAPIMocks,
+			}
+
+			if err := s.RegisterInstanceWithAPIServerELB(&infrav1.Instance{ID: "i-1", SubnetID: "subnet-1"}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func expectRegisterInstance(m *mock_elbiface.MockELBAPIMockRecorder, name string, scheme infrav1.ClassicELBScheme) {
+	m.DescribeLoadBalancers(gomock.Eq(&elb.DescribeLoadBalancersInput{LoadBalancerNames: aws.StringSlice([]string{name})})).
+		Return(&elb.DescribeLoadBalancersOutput{
+			LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+				{
+					LoadBalancerName: aws.String(name),
+					Scheme:           aws.String(string(scheme)),
+					Subnets:          aws.StringSlice([]string{"subnet-1"}),
+				},
+			},
+		}, nil)
+	m.DescribeLoadBalancerAttributes(gomock.Eq(&elb.DescribeLoadBalancerAttributesInput{LoadBalancerName: aws.String(name)})).
+		Return(&elb.DescribeLoadBalancerAttributesOutput{
+			LoadBalancerAttributes: &elb.LoadBalancerAttributes{
+				CrossZoneLoadBalancing: &elb.CrossZoneLoadBalancing{Enabled: aws.Bool(false)},
+			},
+		}, nil)
+	m.RegisterInstancesWithLoadBalancer(gomock.Eq(&elb.RegisterInstancesWithLoadBalancerInput{
+		Instances:        []*elb.Instance{{InstanceId: aws.String("i-1")}},
+		LoadBalancerName: aws.String(name),
+	})).Return(&elb.RegisterInstancesWithLoadBalancerOutput{}, nil)
+}
+
+func TestAPIServerELBDNSNameReady(t *testing.T) {
+	describeInput := &elb.DescribeLoadBalancersInput{LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"})}
+
+	tests := []struct {
+		name        string
+		expect      func(m *mock_elbiface.MockELBAPIMockRecorder)
+		expectReady bool
+		expectError bool
+	}{
+		{
+			name: "ready when the load balancer is described with the DNS name of the status",
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(describeInput)).Return(&elb.DescribeLoadBalancersOutput{
+					LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+						{LoadBalancerName: aws.String("bar-apiserver"), DNSName: aws.String("bar-apiserver.us-east-1.elb.amazonaws.com")},
+					},
+				}, nil)
+			},
+			expectReady: true,
+		},
+		{
+			name: "not ready while the load balancer is not described yet",
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(describeInput)).
+					Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "not found", nil))
+			},
+		},
+		{
+			name: "not ready while the load balancer is described with another DNS name",
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(describeInput)).Return(&elb.DescribeLoadBalancersOutput{
+					LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+						{LoadBalancerName: aws.String("bar-apiserver"), DNSName: aws.String("")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "returns the other errors",
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(describeInput)).
+					Return(nil, awserr.New("AccessDenied", "access denied", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbAPIMocks := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							APIServerELB: infrav1.ClassicELB{
+								Name:    "bar-apiserver",
+								DNSName: "bar-apiserver.us-east-1.elb.amazonaws.com",
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbAPIMocks.EXPECT())
+
+			s := &Service{
+				scope:     clusterScope,
+				ELBClient: elbAPIMocks,
+			}
+
+			ready, err := s.APIServerELBDNSNameReady()
+			if (err != nil) != tc.expectError {
+				t.Fatalf("Expected error: %t, got %v", tc.expectError, err)
+			}
+			if ready != tc.expectReady {
+				t.Fatalf("Expected ready: %t, got %t", tc.expectReady, ready)
+			}
+		})
+	}
+}
+
 func TestDeleteLoadbalancers(t *testing.T) {
 	clusterName := "bar"
 	tests := []struct {
@@ -356,6 +695,18 @@ func TestDescribeLoadbalancers(t *testing.T) {
 				})).Return(&elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: []*elb.LoadBalancerDescription{{Scheme: pointer.StringPtr(string(infrav1.ClassicELBSchemeInternal))}}}, nil)
 			},
 		},
+		{
+			name:   "Error if existing internal loadbalancer isn't internal",
+			lbName: "bar-apiserver-internal",
+			rgAPIMocks: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesPages(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			},
+			DescribeElbAPIMocks: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(&elb.DescribeLoadBalancersInput{
+					LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver-internal"}),
+				})).Return(&elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: []*elb.LoadBalancerDescription{{Scheme: pointer.StringPtr(string(infrav1.ClassicELBSchemeInternetFacing))}}}, nil)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -452,3 +803,577 @@ func setupScheme() (*runtime.Scheme, error) {
 	}
 	return scheme, nil
 }
+
+func TestDescribeInstanceHealth(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	elbAPIMocks := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+	elbAPIMocks.EXPECT().DescribeInstanceHealth(gomock.Eq(&elb.DescribeInstanceHealthInput{LoadBalancerName: aws.String("bar-apiserver")})).
+		Return(&elb.DescribeInstanceHealthOutput{
+			InstanceStates: []*elb.InstanceState{
+				{
+					InstanceId:  aws.String("i-1"),
+					State:       aws.String("InService"),
+					ReasonCode:  aws.String("N/A"),
+					Description: aws.String("N/A"),
+				},
+				{
+					InstanceId:  aws.String("i-2"),
+					State:       aws.String("OutOfService"),
+					ReasonCode:  aws.String("Instance"),
+					Description: aws.String("Instance has failed at least the UnhealthyThreshold number of health checks consecutively."),
+				},
+			},
+		}, nil)
+
+	s := &Service{
+		ELBClient: elbAPIMocks,
+	}
+
+	instances, err := s.describeInstanceHealth("bar-apiserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []infrav1.ClassicELBInstanceHealth{
+		{
+			InstanceID: "i-1",
+			State:      infrav1.ClassicELBInstanceStateInService,
+		},
+		{
+			InstanceID:  "i-2",
+			State:       infrav1.ClassicELBInstanceStateOutOfService,
+			ReasonCode:  "Instance",
+			Description: "Instance has failed at least the UnhealthyThreshold number of health checks consecutively.",
+		},
+	}
+	if !reflect.DeepEqual(instances, expected) {
+		t.Errorf("Expected instances %v, got %v", expected, instances)
+	}
+}
+
+func TestReconcileInstanceHealthCondition(t *testing.T) {
+	tests := []struct {
+		name           string
+		network        infrav1.Network
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name: "no instances registered",
+			network: infrav1.Network{
+				APIServerELB: infrav1.ClassicELB{Name: "bar-apiserver"},
+			},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: infrav1.WaitingForLoadBalancerInstancesReason,
+		},
+		{
+			name: "all instances in service",
+			network: infrav1.Network{
+				APIServerELB: infrav1.ClassicELB{
+					Name: "bar-apiserver",
+					Instances: []infrav1.ClassicELBInstanceHealth{
+						{InstanceID: "i-1", State: infrav1.ClassicELBInstanceStateInService},
+					},
+				},
+				InternalAPIServerELB: infrav1.ClassicELB{
+					Name: "bar-apiserver-internal",
+					Instances: []infrav1.ClassicELBInstanceHealth{
+						{InstanceID: "i-1", State: infrav1.ClassicELBInstanceStateInService},
+					},
+				},
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "instance out of service on the internal load balancer",
+			network: infrav1.Network{
+				APIServerELB: infrav1.ClassicELB{
+					Name: "bar-apiserver",
+					Instances: []infrav1.ClassicELBInstanceHealth{
+						{InstanceID: "i-1", State: infrav1.ClassicELBInstanceStateInService},
+					},
+				},
+				InternalAPIServerELB: infrav1.ClassicELB{
+					Name: "bar-apiserver-internal",
+					Instances: []infrav1.ClassicELBInstanceHealth{
+						{InstanceID: "i-1", State: infrav1.ClassicELBInstanceStateOutOfService, ReasonCode: "ELB", Description: "Instance registration is still in progress."},
+					},
+				},
+			},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: infrav1.LoadBalancerInstancesOutOfServiceReason,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status: infrav1.AWSClusterStatus{
+					Network: tc.network,
+				},
+			}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: awsCluster,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{
+				scope: clusterScope,
+			}
+			s.reconcileInstanceHealthCondition()
+
+			condition := conditions.Get(awsCluster, infrav1.LoadBalancerInstancesHealthyCondition)
+			if condition == nil {
+				t.Fatal("Expected the LoadBalancerInstancesHealthy condition to be set")
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("Expected condition status %q, got %q", tc.expectedStatus, condition.Status)
+			}
+			if condition.Reason != tc.expectedReason {
+				t.Errorf("Expected condition reason %q, got %q", tc.expectedReason, condition.Reason)
+			}
+		})
+	}
+}
+
+func TestExpectedClassicELBScheme(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "bar",
+			},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: infrav1.AWSClusterSpec{ControlPlaneLoadBalancer: &infrav1.AWSLoadBalancerSpec{
+				Scheme:               &infrav1.ClassicELBSchemeInternetFacing,
+				InternalLoadBalancer: true,
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{
+		scope: clusterScope,
+	}
+
+	if got := s.expectedClassicELBScheme("bar-apiserver"); got == nil || *got != infrav1.ClassicELBSchemeInternetFacing {
+		t.Errorf("Expected the scheme of the control plane load balancer, got %v", got)
+	}
+	if got := s.expectedClassicELBScheme("bar-apiserver-internal"); got == nil || *got != infrav1.ClassicELBSchemeInternal {
+		t.Errorf("Expected the internal scheme, got %v", got)
+	}
+}
+
+func TestConfigureProxyProtocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		expect  func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name:    "enable proxy protocol",
+			enabled: true,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.CreateLoadBalancerPolicy(gomock.Eq(&elb.CreateLoadBalancerPolicyInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					PolicyName:       aws.String(proxyProtocolPolicyName),
+					PolicyTypeName:   aws.String("ProxyProtocolPolicyType"),
+					PolicyAttributes: []*elb.PolicyAttribute{
+						{
+							AttributeName:  aws.String("ProxyProtocol"),
+							AttributeValue: aws.String("true"),
+						},
+					},
+				})).Return(&elb.CreateLoadBalancerPolicyOutput{}, nil)
+				m.SetLoadBalancerPoliciesForBackendServer(gomock.Eq(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					InstancePort:     aws.Int64(6443),
+					PolicyNames:      aws.StringSlice([]string{proxyProtocolPolicyName}),
+				})).Return(&elb.SetLoadBalancerPoliciesForBackendServerOutput{}, nil)
+			},
+		},
+		{
+			name:    "enable proxy protocol with an existing policy",
+			enabled: true,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.CreateLoadBalancerPolicy(gomock.Any()).
+					Return(nil, awserr.New(elb.ErrCodeDuplicatePolicyNameException, "policy already exists", nil))
+				m.SetLoadBalancerPoliciesForBackendServer(gomock.Eq(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					InstancePort:     aws.Int64(6443),
+					PolicyNames:      aws.StringSlice([]string{proxyProtocolPolicyName}),
+				})).Return(&elb.SetLoadBalancerPoliciesForBackendServerOutput{}, nil)
+			},
+		},
+		{
+			name:    "disable proxy protocol",
+			enabled: false,
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.SetLoadBalancerPoliciesForBackendServer(gomock.Eq(&elb.SetLoadBalancerPoliciesForBackendServerInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					InstancePort:     aws.Int64(6443),
+					PolicyNames:      []*string{},
+				})).Return(&elb.SetLoadBalancerPoliciesForBackendServerOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbAPIMocks := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      "bar",
+					},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbAPIMocks.EXPECT())
+
+			s := &Service{
+				scope:     clusterScope,
+				ELBClient: elbAPIMocks,
+			}
+
+			if err := s.configureProxyProtocol("bar-apiserver", tc.enabled); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestFromSDKTypeToClassicELB(t *testing.T) {
+	res := fromSDKTypeToClassicELB(
+		&elb.LoadBalancerDescription{
+			LoadBalancerName: aws.String("bar-apiserver"),
+			Scheme:           aws.String("internet-facing"),
+			HealthCheck: &elb.HealthCheck{
+				Target:             aws.String("SSL:6443"),
+				Interval:           aws.Int64(10),
+				Timeout:            aws.Int64(5),
+				HealthyThreshold:   aws.Int64(5),
+				UnhealthyThreshold: aws.Int64(3),
+			},
+			BackendServerDescriptions: []*elb.BackendServerDescription{
+				{
+					InstancePort: aws.Int64(6443),
+					PolicyNames:  aws.StringSlice([]string{proxyProtocolPolicyName}),
+				},
+			},
+		},
+		&elb.LoadBalancerAttributes{
+			CrossZoneLoadBalancing: &elb.CrossZoneLoadBalancing{Enabled: aws.Bool(true)},
+			ConnectionSettings:     &elb.ConnectionSettings{IdleTimeout: aws.Int64(600)},
+			ConnectionDraining:     &elb.ConnectionDraining{Enabled: aws.Bool(true), Timeout: aws.Int64(300)},
+		},
+		6443,
+	)
+
+	expected := infrav1.ClassicELBAttributes{
+		IdleTimeout:               10 * time.Minute,
+		CrossZoneLoadBalancing:    true,
+		ConnectionDrainingTimeout: 5 * time.Minute,
+	}
+	if !reflect.DeepEqual(res.Attributes, expected) {
+		t.Errorf("Expected attributes %v, got %v", expected, res.Attributes)
+	}
+	if !res.ProxyProtocol {
+		t.Error("Expected load balancer to have the proxy protocol enabled")
+	}
+	expectedHealthCheck := &infrav1.ClassicELBHealthCheck{
+		Target:             "SSL:6443",
+		Interval:           10 * time.Second,
+		Timeout:            5 * time.Second,
+		HealthyThreshold:   5,
+		UnhealthyThreshold: 3,
+	}
+	if !reflect.DeepEqual(res.HealthCheck, expectedHealthCheck) {
+		t.Errorf("Expected health check %v, got %v", expectedHealthCheck, res.HealthCheck)
+	}
+}
+
+func TestSettingManaged(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		managed     []string
+		unmanaged   []string
+	}{
+		{
+			name:    "no annotation",
+			managed: []string{attributesSetting, healthCheckSetting, proxyProtocolSetting, subnetsSetting, securityGroupsSetting},
+		},
+		{
+			name:        "unmanaged settings",
+			annotations: map[string]string{infrav1.UnmanagedLoadBalancerSettingsAnnotation: "health-check, subnets"},
+			managed:     []string{attributesSetting, proxyProtocolSetting, securityGroupsSetting},
+			unmanaged:   []string{healthCheckSetting, subnetsSetting},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: tc.annotations},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Service{scope: clusterScope}
+			for _, setting := range tc.managed {
+				if !s.settingManaged(setting) {
+					t.Errorf("Expected %s to be managed", setting)
+				}
+			}
+			for _, setting := range tc.unmanaged {
+				if s.settingManaged(setting) {
+					t.Errorf("Expected %s not to be managed", setting)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileClassicELBSubnets(t *testing.T) {
+	tests := []struct {
+		name      string
+		attached  []string
+		subnetIDs []string
+		expect    func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name:      "subnets match",
+			attached:  []string{"subnet-1", "subnet-2"},
+			subnetIDs: []string{"subnet-2", "subnet-1"},
+			expect:    func(m *mock_elbiface.MockELBAPIMockRecorder) {},
+		},
+		{
+			name:      "subnet replaced",
+			attached:  []string{"subnet-1", "subnet-2"},
+			subnetIDs: []string{"subnet-1", "subnet-3"},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				gomock.InOrder(
+					m.DetachLoadBalancerFromSubnets(gomock.Eq(&elb.DetachLoadBalancerFromSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-2"}),
+					})).Return(&elb.DetachLoadBalancerFromSubnetsOutput{}, nil),
+					m.AttachLoadBalancerToSubnets(gomock.Eq(&elb.AttachLoadBalancerToSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-3"}),
+					})).Return(&elb.AttachLoadBalancerToSubnetsOutput{}, nil),
+				)
+			},
+		},
+		{
+			name:      "all subnets replaced",
+			attached:  []string{"subnet-1"},
+			subnetIDs: []string{"subnet-2"},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				gomock.InOrder(
+					m.AttachLoadBalancerToSubnets(gomock.Eq(&elb.AttachLoadBalancerToSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-2"}),
+					})).Return(&elb.AttachLoadBalancerToSubnetsOutput{}, nil),
+					m.DetachLoadBalancerFromSubnets(gomock.Eq(&elb.DetachLoadBalancerFromSubnetsInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Subnets:          aws.StringSlice([]string{"subnet-1"}),
+					})).Return(&elb.DetachLoadBalancerFromSubnetsOutput{}, nil),
+				)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbAPIMocks := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbAPIMocks.EXPECT())
+
+			s := &Service{
+				scope:     clusterScope,
+				ELBClient: elbAPIMocks,
+			}
+
+			lb := &infrav1.ClassicELB{Name: "bar-apiserver", SubnetIDs: tc.attached}
+			if err := s.reconcileClassicELBSubnets(lb, tc.subnetIDs); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(lb.SubnetIDs, tc.subnetIDs) {
+				t.Errorf("Expected subnets %v, got %v", tc.subnetIDs, lb.SubnetIDs)
+			}
+		})
+	}
+}
+
+func TestConfigureListeners(t *testing.T) {
+	listener := func(port, instancePort int64) infrav1.ClassicELBListener {
+		return infrav1.ClassicELBListener{
+			Protocol:         infrav1.ClassicELBProtocolTCP,
+			Port:             port,
+			InstanceProtocol: infrav1.ClassicELBProtocolTCP,
+			InstancePort:     instancePort,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		current []infrav1.ClassicELBListener
+		wanted  []infrav1.ClassicELBListener
+		expect  func(m *mock_elbiface.MockELBAPIMockRecorder)
+	}{
+		{
+			name:    "instance port changed",
+			current: []infrav1.ClassicELBListener{listener(6443, 6443)},
+			wanted:  []infrav1.ClassicELBListener{listener(6443, 8443)},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				gomock.InOrder(
+					m.DeleteLoadBalancerListeners(gomock.Eq(&elb.DeleteLoadBalancerListenersInput{
+						LoadBalancerName:  aws.String("bar-apiserver"),
+						LoadBalancerPorts: aws.Int64Slice([]int64{6443}),
+					})).Return(&elb.DeleteLoadBalancerListenersOutput{}, nil),
+					m.CreateLoadBalancerListeners(gomock.Eq(&elb.CreateLoadBalancerListenersInput{
+						LoadBalancerName: aws.String("bar-apiserver"),
+						Listeners: []*elb.Listener{
+							{
+								Protocol:         aws.String("TCP"),
+								LoadBalancerPort: aws.Int64(6443),
+								InstanceProtocol: aws.String("TCP"),
+								InstancePort:     aws.Int64(8443),
+							},
+						},
+					})).Return(&elb.CreateLoadBalancerListenersOutput{}, nil),
+				)
+			},
+		},
+		{
+			name:    "listener added",
+			current: []infrav1.ClassicELBListener{listener(6443, 6443)},
+			wanted:  []infrav1.ClassicELBListener{listener(6443, 6443), listener(443, 443)},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.CreateLoadBalancerListeners(gomock.Eq(&elb.CreateLoadBalancerListenersInput{
+					LoadBalancerName: aws.String("bar-apiserver"),
+					Listeners: []*elb.Listener{
+						{
+							Protocol:         aws.String("TCP"),
+							LoadBalancerPort: aws.Int64(443),
+							InstanceProtocol: aws.String("TCP"),
+							InstancePort:     aws.Int64(443),
+						},
+					},
+				})).Return(&elb.CreateLoadBalancerListenersOutput{}, nil)
+			},
+		},
+		{
+			name:    "listener removed",
+			current: []infrav1.ClassicELBListener{listener(6443, 6443), listener(443, 443)},
+			wanted:  []infrav1.ClassicELBListener{listener(6443, 6443)},
+			expect: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DeleteLoadBalancerListeners(gomock.Eq(&elb.DeleteLoadBalancerListenersInput{
+					LoadBalancerName:  aws.String("bar-apiserver"),
+					LoadBalancerPorts: aws.Int64Slice([]int64{443}),
+				})).Return(&elb.DeleteLoadBalancerListenersOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbAPIMocks := mock_elbiface.NewMockELBAPI(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(elbAPIMocks.EXPECT())
+
+			s := &Service{
+				scope:     clusterScope,
+				ELBClient: elbAPIMocks,
+			}
+
+			if err := s.configureListeners("bar-apiserver", tc.current, tc.wanted); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
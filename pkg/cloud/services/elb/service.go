@@ -19,6 +19,7 @@ package elb
 import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
 
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
@@ -32,6 +33,9 @@ type Service struct {
 	EC2Client             ec2iface.EC2API
 	ELBClient             elbiface.ELBAPI
 	ResourceTaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+
+	// ELBV2Client manages the network load balancer in front of the bastion hosts.
+	ELBV2Client elbv2iface.ELBV2API
 }
 
 // NewService returns a new service given the api clients.
@@ -41,5 +45,7 @@ func NewService(elbScope scope.ELBScope) *Service {
 		EC2Client:             scope.NewEC2Client(elbScope, elbScope, elbScope, elbScope.InfraCluster()),
 		ELBClient:             scope.NewELBClient(elbScope, elbScope, elbScope, elbScope.InfraCluster()),
 		ResourceTaggingClient: scope.NewResourgeTaggingClient(elbScope, elbScope, elbScope, elbScope.InfraCluster()),
+
+		ELBV2Client: scope.NewELBV2Client(elbScope, elbScope, elbScope, elbScope.InfraCluster()),
 	}
 }
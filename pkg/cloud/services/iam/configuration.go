@@ -0,0 +1,788 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	cfn_iam "github.com/awslabs/goformation/v4/cloudformation/iam"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/api/bootstrap/v1alpha1"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/cloudformation/bootstrap"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	awsconverters "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+const (
+	// ConfigurationTagKey is the tag of the IAM roles, instance profiles and managed policies created for an
+	// AWSIAMConfiguration. Its value is the name of the configuration.
+	ConfigurationTagKey = infrav1.NameAWSProviderPrefix + "iam-configuration"
+
+	configurationControllerName = "awsiamconfiguration"
+
+	// maxPolicyVersions is the number of versions IAM keeps of a managed policy.
+	maxPolicyVersions = 5
+)
+
+// partitionRegions are the regions the IAM endpoint of a partition is reached through.
+var partitionRegions = map[string]string{
+	"aws":        "us-east-1",
+	"aws-cn":     "cn-north-1",
+	"aws-us-gov": "us-gov-west-1",
+}
+
+// ConfigurationService reconciles the IAM roles, instance profiles and managed policies clusterawsadm
+// would create with AWS CloudFormation for an AWSIAMConfiguration, using the credentials of the controller.
+// The bootstrap user and group are only created by clusterawsadm.
+type ConfigurationService struct {
+	IAMClient iamiface.IAMAPI
+	STSClient stsiface.STSAPI
+	log       logr.Logger
+}
+
+// NewConfigurationService returns a ConfigurationService for the partition of the given configuration.
+func NewConfigurationService(spec *bootstrapv1.AWSIAMConfigurationSpec, endpoints []scope.ServiceEndpoint, log logr.Logger) (*ConfigurationService, error) {
+	region := spec.Region
+	if region == "" {
+		region = partitionRegions[spec.Partition]
+	}
+	if region == "" {
+		return nil, errors.Errorf("unable to determine a region of partition %q, set the region of the configuration", spec.Partition)
+	}
+
+	globalScope, err := scope.NewGlobalScope(scope.GlobalScopeParams{
+		ControllerName: configurationControllerName,
+		Region:         region,
+		Endpoints:      endpoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigurationService{
+		IAMClient: scope.NewGlobalIAMClient(globalScope, globalScope, log),
+		STSClient: scope.NewGlobalSTSClient(globalScope, globalScope),
+		log:       log,
+	}, nil
+}
+
+type configurationRole struct {
+	name                string
+	path                string
+	permissionsBoundary string
+	trustPolicy         string
+	inlinePolicies      map[string]string
+	managedPolicyARNs   []string
+	tags                infrav1.Tags
+}
+
+type configurationPolicy struct {
+	name        string
+	path        string
+	description string
+	document    string
+	roles       []string
+}
+
+func (p configurationPolicy) arn(partition, accountID string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:policy%s%s", partition, accountID, p.path, p.name)
+}
+
+type configurationInstanceProfile struct {
+	name string
+	path string
+	role string
+}
+
+type configurationResources struct {
+	roles            []configurationRole
+	policies         []configurationPolicy
+	instanceProfiles []configurationInstanceProfile
+}
+
+// ReconcileConfiguration creates or updates the IAM resources of the configuration, and deletes those
+// it created for a previous version of the configuration. The defaulted spec is rendered, while the names
+// of the resources are recorded in the status of conf.
+func (s *ConfigurationService) ReconcileConfiguration(conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec) error {
+	desired, err := renderConfiguration(spec)
+	if err != nil {
+		return err
+	}
+
+	partition, accountID, err := s.account()
+	if err != nil {
+		return err
+	}
+
+	owner := conf.Name
+	tags := infrav1.Tags{ConfigurationTagKey: owner}
+
+	policyARNs := []string{}
+	roleManagedPolicies := map[string][]string{}
+	for _, policy := range desired.policies {
+		policyARN := policy.arn(partition, accountID)
+		if err := s.ensurePolicy(owner, policyARN, policy, tags); err != nil {
+			return errors.Wrapf(err, "ensuring IAM managed policy %q", policy.name)
+		}
+		policyARNs = append(policyARNs, policyARN)
+		for _, role := range policy.roles {
+			roleManagedPolicies[role] = append(roleManagedPolicies[role], policyARN)
+		}
+	}
+
+	roles := []string{}
+	for _, role := range desired.roles {
+		role.managedPolicyARNs = append(role.managedPolicyARNs, roleManagedPolicies[role.name]...)
+		if err := s.ensureConfigurationRole(owner, role, tags); err != nil {
+			return errors.Wrapf(err, "ensuring IAM role %q", role.name)
+		}
+		roles = append(roles, role.name)
+	}
+
+	instanceProfiles := []string{}
+	for _, profile := range desired.instanceProfiles {
+		if err := s.ensureConfigurationInstanceProfile(owner, profile, tags); err != nil {
+			return errors.Wrapf(err, "ensuring IAM instance profile %q", profile.name)
+		}
+		instanceProfiles = append(instanceProfiles, profile.name)
+	}
+
+	// Delete what was created for a previous version of the configuration.
+	if err := s.deleteResources(owner, difference(conf.Status.InstanceProfiles, instanceProfiles), difference(conf.Status.Roles, roles), difference(conf.Status.ManagedPolicies, policyARNs)); err != nil {
+		return err
+	}
+
+	sort.Strings(roles)
+	sort.Strings(instanceProfiles)
+	sort.Strings(policyARNs)
+	conf.Status.Roles = roles
+	conf.Status.InstanceProfiles = instanceProfiles
+	conf.Status.ManagedPolicies = policyARNs
+	return nil
+}
+
+// DeleteConfiguration deletes the IAM resources recorded in the status of the configuration, and those
+// of the defaulted spec which a failed reconciliation may have created without recording them.
+func (s *ConfigurationService) DeleteConfiguration(conf *bootstrapv1.AWSIAMConfiguration, spec *bootstrapv1.AWSIAMConfigurationSpec) error {
+	desired, err := renderConfiguration(spec)
+	if err != nil {
+		return err
+	}
+
+	partition, accountID, err := s.account()
+	if err != nil {
+		return err
+	}
+
+	instanceProfiles := append([]string{}, conf.Status.InstanceProfiles...)
+	for _, profile := range desired.instanceProfiles {
+		instanceProfiles = append(instanceProfiles, profile.name)
+	}
+	roles := append([]string{}, conf.Status.Roles...)
+	for _, role := range desired.roles {
+		roles = append(roles, role.name)
+	}
+	policyARNs := append([]string{}, conf.Status.ManagedPolicies...)
+	for _, policy := range desired.policies {
+		policyARNs = append(policyARNs, policy.arn(partition, accountID))
+	}
+
+	if err := s.deleteResources(conf.Name, unique(instanceProfiles), unique(roles), unique(policyARNs)); err != nil {
+		return err
+	}
+
+	conf.Status.Roles = nil
+	conf.Status.InstanceProfiles = nil
+	conf.Status.ManagedPolicies = nil
+	return nil
+}
+
+func (s *ConfigurationService) account() (string, string, error) {
+	identity, err := s.STSClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", "", errors.Wrap(err, "unable to get the caller identity")
+	}
+	parsed, err := arn.Parse(aws.StringValue(identity.Arn))
+	if err != nil {
+		return "", "", errors.Wrap(err, "unable to parse the ARN of the caller identity")
+	}
+	return parsed.Partition, aws.StringValue(identity.Account), nil
+}
+
+// renderConfiguration renders the CloudFormation template of clusterawsadm for the configuration and
+// resolves the references between its resources.
+func renderConfiguration(spec *bootstrapv1.AWSIAMConfigurationSpec) (*configurationResources, error) {
+	template := bootstrap.Template{Spec: spec}.RenderCloudFormation()
+
+	// Ref of a role returns its name.
+	names := map[string]string{}
+	for logicalName, resource := range template.Resources {
+		if role, ok := resource.(*cfn_iam.Role); ok {
+			names[logicalName] = role.RoleName
+		}
+	}
+	resolve := func(refs []string) ([]string, error) {
+		resolved := []string{}
+		for _, ref := range refs {
+			decoded, err := base64.StdEncoding.DecodeString(ref)
+			if err != nil {
+				return nil, errors.Errorf("unsupported reference %q", ref)
+			}
+			var intrinsic struct{ Ref string }
+			if err := json.Unmarshal(decoded, &intrinsic); err != nil || intrinsic.Ref == "" {
+				return nil, errors.Errorf("unsupported reference %s", decoded)
+			}
+			// Groups and users are left to clusterawsadm.
+			if name, ok := names[intrinsic.Ref]; ok {
+				resolved = append(resolved, name)
+			}
+		}
+		return resolved, nil
+	}
+
+	resources := &configurationResources{}
+	logicalNames := make([]string, 0, len(template.Resources))
+	for logicalName := range template.Resources {
+		logicalNames = append(logicalNames, logicalName)
+	}
+	sort.Strings(logicalNames)
+
+	for _, logicalName := range logicalNames {
+		switch resource := template.Resources[logicalName].(type) {
+		case *cfn_iam.ManagedPolicy:
+			document, err := json.Marshal(resource.PolicyDocument)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal the document of managed policy %q", resource.ManagedPolicyName)
+			}
+			roles, err := resolve(resource.Roles)
+			if err != nil {
+				return nil, err
+			}
+			resources.policies = append(resources.policies, configurationPolicy{
+				name:        resource.ManagedPolicyName,
+				path:        iamPath(resource.Path),
+				description: resource.Description,
+				document:    string(document),
+				roles:       roles,
+			})
+		case *cfn_iam.Role:
+			trustPolicy, err := json.Marshal(resource.AssumeRolePolicyDocument)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal the trust policy of role %q", resource.RoleName)
+			}
+			inlinePolicies := map[string]string{}
+			for _, policy := range resource.Policies {
+				document, err := json.Marshal(policy.PolicyDocument)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to marshal inline policy %q of role %q", policy.PolicyName, resource.RoleName)
+				}
+				inlinePolicies[policy.PolicyName] = string(document)
+			}
+			tags := infrav1.Tags{}
+			for _, tag := range resource.Tags {
+				tags[tag.Key] = tag.Value
+			}
+			resources.roles = append(resources.roles, configurationRole{
+				name:                resource.RoleName,
+				path:                iamPath(resource.Path),
+				permissionsBoundary: resource.PermissionsBoundary,
+				trustPolicy:         string(trustPolicy),
+				inlinePolicies:      inlinePolicies,
+				managedPolicyARNs:   append([]string{}, resource.ManagedPolicyArns...),
+				tags:                tags,
+			})
+		case *cfn_iam.InstanceProfile:
+			roles, err := resolve(resource.Roles)
+			if err != nil {
+				return nil, err
+			}
+			if len(roles) != 1 {
+				return nil, errors.Errorf("instance profile %q must have exactly one role", resource.InstanceProfileName)
+			}
+			resources.instanceProfiles = append(resources.instanceProfiles, configurationInstanceProfile{
+				name: resource.InstanceProfileName,
+				path: iamPath(resource.Path),
+				role: roles[0],
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+func (s *ConfigurationService) ensurePolicy(owner, policyARN string, policy configurationPolicy, tags infrav1.Tags) error {
+	out, err := s.IAMClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: aws.String(policyARN)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		if _, err := s.IAMClient.CreatePolicy(&iam.CreatePolicyInput{
+			PolicyName:     aws.String(policy.name),
+			Path:           aws.String(policy.path),
+			Description:    aws.String(policy.description),
+			PolicyDocument: aws.String(policy.document),
+			Tags:           awsconverters.MapToIAMTags(tags),
+		}); err != nil {
+			return err
+		}
+		s.log.Info("Created IAM managed policy", "policy", policy.name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(owner, out.Policy.Tags); err != nil {
+		return err
+	}
+
+	version, err := s.IAMClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: out.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return err
+	}
+	document, err := url.QueryUnescape(aws.StringValue(version.PolicyVersion.Document))
+	if err != nil {
+		return errors.Wrap(err, "unable to decode the policy document")
+	}
+	equal, err := equalDocuments(document, policy.document)
+	if err != nil || equal {
+		return err
+	}
+
+	// IAM keeps a limited number of versions, make room for the new one.
+	versions, err := s.IAMClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{PolicyArn: aws.String(policyARN)})
+	if err != nil {
+		return err
+	}
+	if len(versions.Versions) >= maxPolicyVersions {
+		if err := s.deleteOldestPolicyVersion(policyARN, versions.Versions); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.IAMClient.CreatePolicyVersion(&iam.CreatePolicyVersionInput{
+		PolicyArn:      aws.String(policyARN),
+		PolicyDocument: aws.String(policy.document),
+		SetAsDefault:   aws.Bool(true),
+	}); err != nil {
+		return err
+	}
+	s.log.Info("Updated IAM managed policy", "policy", policy.name)
+	return nil
+}
+
+func (s *ConfigurationService) deleteOldestPolicyVersion(policyARN string, versions []*iam.PolicyVersion) error {
+	var oldest *iam.PolicyVersion
+	for _, version := range versions {
+		if aws.BoolValue(version.IsDefaultVersion) {
+			continue
+		}
+		if oldest == nil || version.CreateDate.Before(aws.TimeValue(oldest.CreateDate)) {
+			oldest = version
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+	_, err := s.IAMClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: oldest.VersionId,
+	})
+	return err
+}
+
+func (s *ConfigurationService) ensureConfigurationRole(owner string, role configurationRole, ownerTags infrav1.Tags) error {
+	tags := infrav1.Tags{}
+	tags.Merge(role.tags)
+	tags.Merge(ownerTags)
+
+	out, err := s.IAMClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(role.name)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		input := &iam.CreateRoleInput{
+			RoleName:                 aws.String(role.name),
+			Path:                     aws.String(role.path),
+			AssumeRolePolicyDocument: aws.String(role.trustPolicy),
+			Tags:                     awsconverters.MapToIAMTags(tags),
+		}
+		if role.permissionsBoundary != "" {
+			input.PermissionsBoundary = aws.String(role.permissionsBoundary)
+		}
+		if _, err := s.IAMClient.CreateRole(input); err != nil {
+			return err
+		}
+		s.log.Info("Created IAM role", "role", role.name)
+	} else if err != nil {
+		return err
+	} else {
+		if err := s.updateConfigurationRole(owner, role, tags, out.Role); err != nil {
+			return err
+		}
+	}
+
+	if err := s.ensureInlinePolicies(role.name, role.inlinePolicies); err != nil {
+		return err
+	}
+
+	return ensureRoleManagedPolicies(s.IAMClient, role.name, role.managedPolicyARNs)
+}
+
+func (s *ConfigurationService) updateConfigurationRole(owner string, role configurationRole, tags infrav1.Tags, existing *iam.Role) error {
+	if err := checkOwner(owner, existing.Tags); err != nil {
+		return err
+	}
+	if path := aws.StringValue(existing.Path); path != role.path {
+		return errors.Errorf("role exists under path %q, the path of a role can't be changed", path)
+	}
+
+	trustPolicy, err := url.QueryUnescape(aws.StringValue(existing.AssumeRolePolicyDocument))
+	if err != nil {
+		return errors.Wrap(err, "unable to decode the trust policy")
+	}
+	equal, err := equalDocuments(trustPolicy, role.trustPolicy)
+	if err != nil {
+		return err
+	}
+	if !equal {
+		if _, err := s.IAMClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(role.name),
+			PolicyDocument: aws.String(role.trustPolicy),
+		}); err != nil {
+			return err
+		}
+	}
+
+	boundary := ""
+	if existing.PermissionsBoundary != nil {
+		boundary = aws.StringValue(existing.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	switch {
+	case boundary == role.permissionsBoundary:
+	case role.permissionsBoundary == "":
+		if _, err := s.IAMClient.DeleteRolePermissionsBoundary(&iam.DeleteRolePermissionsBoundaryInput{RoleName: aws.String(role.name)}); err != nil {
+			return err
+		}
+	default:
+		if _, err := s.IAMClient.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+			RoleName:            aws.String(role.name),
+			PermissionsBoundary: aws.String(role.permissionsBoundary),
+		}); err != nil {
+			return err
+		}
+	}
+
+	existingTags := awsconverters.IAMTagsToMap(existing.Tags)
+	if diff := existingTags.Difference(tags); len(diff) > 0 {
+		keys := make([]*string, 0, len(diff))
+		for key := range diff {
+			keys = append(keys, aws.String(key))
+		}
+		if _, err := s.IAMClient.UntagRole(&iam.UntagRoleInput{RoleName: aws.String(role.name), TagKeys: keys}); err != nil {
+			return err
+		}
+	}
+	if diff := tags.Difference(existingTags); len(diff) > 0 {
+		if _, err := s.IAMClient.TagRole(&iam.TagRoleInput{RoleName: aws.String(role.name), Tags: awsconverters.MapToIAMTags(diff)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ConfigurationService) ensureInlinePolicies(roleName string, policies map[string]string) error {
+	out, err := s.IAMClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		return err
+	}
+	for _, name := range out.PolicyNames {
+		if _, ok := policies[aws.StringValue(name)]; ok {
+			continue
+		}
+		if _, err := s.IAMClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: aws.String(roleName), PolicyName: name}); err != nil {
+			return errors.Wrapf(err, "deleting inline policy %q", aws.StringValue(name))
+		}
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		existing, err := s.IAMClient.GetRolePolicy(&iam.GetRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(name)})
+		if code, _ := awserrors.Code(err); err != nil && code != iam.ErrCodeNoSuchEntityException {
+			return err
+		}
+		if err == nil {
+			document, err := url.QueryUnescape(aws.StringValue(existing.PolicyDocument))
+			if err != nil {
+				return errors.Wrapf(err, "unable to decode inline policy %q", name)
+			}
+			equal, err := equalDocuments(document, policies[name])
+			if err != nil {
+				return err
+			}
+			if equal {
+				continue
+			}
+		}
+		if _, err := s.IAMClient.PutRolePolicy(&iam.PutRolePolicyInput{
+			RoleName:       aws.String(roleName),
+			PolicyName:     aws.String(name),
+			PolicyDocument: aws.String(policies[name]),
+		}); err != nil {
+			return errors.Wrapf(err, "putting inline policy %q", name)
+		}
+	}
+
+	return nil
+}
+
+func (s *ConfigurationService) ensureConfigurationInstanceProfile(owner string, profile configurationInstanceProfile, tags infrav1.Tags) error {
+	out, err := s.IAMClient.GetInstanceProfile(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(profile.name)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		if _, err := s.IAMClient.CreateInstanceProfile(&iam.CreateInstanceProfileInput{
+			InstanceProfileName: aws.String(profile.name),
+			Path:                aws.String(profile.path),
+			Tags:                awsconverters.MapToIAMTags(tags),
+		}); err != nil {
+			return err
+		}
+		s.log.Info("Created IAM instance profile", "instance-profile", profile.name)
+	} else if err != nil {
+		return err
+	} else {
+		if err := checkOwner(owner, out.InstanceProfile.Tags); err != nil {
+			return err
+		}
+		for _, role := range out.InstanceProfile.Roles {
+			if aws.StringValue(role.RoleName) == profile.role {
+				return nil
+			}
+			if _, err := s.IAMClient.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+				InstanceProfileName: aws.String(profile.name),
+				RoleName:            role.RoleName,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = s.IAMClient.AddRoleToInstanceProfile(&iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(profile.name),
+		RoleName:            aws.String(profile.role),
+	})
+	return err
+}
+
+// deleteResources deletes the given IAM resources which are owned by the configuration, leaving
+// those created by other means alone.
+func (s *ConfigurationService) deleteResources(owner string, instanceProfiles, roles, policyARNs []string) error {
+	for _, name := range instanceProfiles {
+		if err := s.deleteConfigurationInstanceProfile(owner, name); err != nil {
+			return errors.Wrapf(err, "deleting IAM instance profile %q", name)
+		}
+	}
+	for _, name := range roles {
+		if err := s.deleteConfigurationRole(owner, name); err != nil {
+			return errors.Wrapf(err, "deleting IAM role %q", name)
+		}
+	}
+	for _, policyARN := range policyARNs {
+		if err := s.deletePolicy(owner, policyARN); err != nil {
+			return errors.Wrapf(err, "deleting IAM managed policy %q", policyARN)
+		}
+	}
+	return nil
+}
+
+func (s *ConfigurationService) deleteConfigurationInstanceProfile(owner, name string) error {
+	out, err := s.IAMClient.GetInstanceProfile(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(owner, out.InstanceProfile.Tags); err != nil {
+		s.log.Info("Skipping deletion of IAM instance profile not managed by the configuration", "instance-profile", name)
+		return nil
+	}
+
+	for _, role := range out.InstanceProfile.Roles {
+		if _, err := s.IAMClient.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+			RoleName:            role.RoleName,
+		}); err != nil {
+			return err
+		}
+	}
+	if _, err := s.IAMClient.DeleteInstanceProfile(&iam.DeleteInstanceProfileInput{InstanceProfileName: aws.String(name)}); err != nil {
+		return err
+	}
+	s.log.Info("Deleted IAM instance profile", "instance-profile", name)
+	return nil
+}
+
+func (s *ConfigurationService) deleteConfigurationRole(owner, name string) error {
+	out, err := s.IAMClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(name)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(owner, out.Role.Tags); err != nil {
+		s.log.Info("Skipping deletion of IAM role not managed by the configuration", "role", name)
+		return nil
+	}
+
+	if err := ensureRoleManagedPolicies(s.IAMClient, name, nil); err != nil {
+		return err
+	}
+	if err := s.ensureInlinePolicies(name, nil); err != nil {
+		return err
+	}
+	if _, err := s.IAMClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(name)}); err != nil {
+		return err
+	}
+	s.log.Info("Deleted IAM role", "role", name)
+	return nil
+}
+
+func (s *ConfigurationService) deletePolicy(owner, policyARN string) error {
+	out, err := s.IAMClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: aws.String(policyARN)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(owner, out.Policy.Tags); err != nil {
+		s.log.Info("Skipping deletion of IAM managed policy not managed by the configuration", "policy", policyARN)
+		return nil
+	}
+
+	entities, err := s.IAMClient.ListEntitiesForPolicy(&iam.ListEntitiesForPolicyInput{PolicyArn: aws.String(policyARN)})
+	if err != nil {
+		return err
+	}
+	for _, role := range entities.PolicyRoles {
+		if _, err := s.IAMClient.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: role.RoleName, PolicyArn: aws.String(policyARN)}); err != nil {
+			return err
+		}
+	}
+	for _, group := range entities.PolicyGroups {
+		if _, err := s.IAMClient.DetachGroupPolicy(&iam.DetachGroupPolicyInput{GroupName: group.GroupName, PolicyArn: aws.String(policyARN)}); err != nil {
+			return err
+		}
+	}
+	for _, user := range entities.PolicyUsers {
+		if _, err := s.IAMClient.DetachUserPolicy(&iam.DetachUserPolicyInput{UserName: user.UserName, PolicyArn: aws.String(policyARN)}); err != nil {
+			return err
+		}
+	}
+
+	versions, err := s.IAMClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{PolicyArn: aws.String(policyARN)})
+	if err != nil {
+		return err
+	}
+	for _, version := range versions.Versions {
+		if aws.BoolValue(version.IsDefaultVersion) {
+			continue
+		}
+		if _, err := s.IAMClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{PolicyArn: aws.String(policyARN), VersionId: version.VersionId}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.IAMClient.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: aws.String(policyARN)}); err != nil {
+		return err
+	}
+	s.log.Info("Deleted IAM managed policy", "policy", policyARN)
+	return nil
+}
+
+// checkOwner returns an error unless the tags mark the resource as created for the configuration.
+func checkOwner(owner string, tags []*iam.Tag) error {
+	if value, ok := awsconverters.IAMTagsToMap(tags)[ConfigurationTagKey]; !ok || value != owner {
+		return errors.Errorf("resource exists and is not managed by AWSIAMConfiguration %q", owner)
+	}
+	return nil
+}
+
+// equalDocuments compares two JSON policy documents regardless of their formatting.
+func equalDocuments(a, b string) (bool, error) {
+	var da, db interface{}
+	if err := json.Unmarshal([]byte(a), &da); err != nil {
+		return false, errors.Wrap(err, "unable to parse policy document")
+	}
+	if err := json.Unmarshal([]byte(b), &db); err != nil {
+		return false, errors.Wrap(err, "unable to parse policy document")
+	}
+	return reflect.DeepEqual(da, db), nil
+}
+
+// iamPath returns the IAM path a resource is created under when CloudFormation is given path.
+func iamPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// unique returns the elements of a without duplicates.
+func unique(a []string) []string {
+	seen := make(map[string]bool, len(a))
+	out := []string{}
+	for _, s := range a {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// difference returns the elements of a which are not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	out := []string{}
+	for _, s := range a {
+		if !in[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/api/bootstrap/v1alpha1"
+)
+
+type fakeSTS struct {
+	stsiface.STSAPI
+}
+
+func (f *fakeSTS) GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{
+		Account: aws.String("123456789012"),
+		Arn:     aws.String("arn:aws:sts::123456789012:assumed-role/controllers/session"),
+	}, nil
+}
+
+func (f *fakeIAM) UpdateAssumeRolePolicy(in *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	f.roles[aws.StringValue(in.RoleName)].AssumeRolePolicyDocument = in.PolicyDocument
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+func (f *fakeIAM) PutRolePermissionsBoundary(in *iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	f.roles[aws.StringValue(in.RoleName)].PermissionsBoundary = &iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: in.PermissionsBoundary}
+	return &iam.PutRolePermissionsBoundaryOutput{}, nil
+}
+
+func (f *fakeIAM) DeleteRolePermissionsBoundary(in *iam.DeleteRolePermissionsBoundaryInput) (*iam.DeleteRolePermissionsBoundaryOutput, error) {
+	f.roles[aws.StringValue(in.RoleName)].PermissionsBoundary = nil
+	return &iam.DeleteRolePermissionsBoundaryOutput{}, nil
+}
+
+func (f *fakeIAM) TagRole(in *iam.TagRoleInput) (*iam.TagRoleOutput, error) {
+	role := f.roles[aws.StringValue(in.RoleName)]
+	role.Tags = append(role.Tags, in.Tags...)
+	return &iam.TagRoleOutput{}, nil
+}
+
+func (f *fakeIAM) UntagRole(in *iam.UntagRoleInput) (*iam.UntagRoleOutput, error) {
+	role := f.roles[aws.StringValue(in.RoleName)]
+	keys := map[string]bool{}
+	for _, key := range in.TagKeys {
+		keys[aws.StringValue(key)] = true
+	}
+	var remaining []*iam.Tag
+	for _, tag := range role.Tags {
+		if !keys[aws.StringValue(tag.Key)] {
+			remaining = append(remaining, tag)
+		}
+	}
+	role.Tags = remaining
+	return &iam.UntagRoleOutput{}, nil
+}
+
+func (f *fakeIAM) ListRolePolicies(in *iam.ListRolePoliciesInput) (*iam.ListRolePoliciesOutput, error) {
+	out := &iam.ListRolePoliciesOutput{}
+	for name := range f.inlinePolicies[aws.StringValue(in.RoleName)] {
+		out.PolicyNames = append(out.PolicyNames, aws.String(name))
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) GetRolePolicy(in *iam.GetRolePolicyInput) (*iam.GetRolePolicyOutput, error) {
+	document, ok := f.inlinePolicies[aws.StringValue(in.RoleName)][aws.StringValue(in.PolicyName)]
+	if !ok {
+		return nil, noSuchEntity()
+	}
+	return &iam.GetRolePolicyOutput{PolicyDocument: aws.String(url.QueryEscape(document))}, nil
+}
+
+func (f *fakeIAM) CreatePolicy(in *iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+	policyARN := fmt.Sprintf("arn:aws:iam::123456789012:policy%s%s", aws.StringValue(in.Path), aws.StringValue(in.PolicyName))
+	policy := &iam.Policy{Arn: aws.String(policyARN), PolicyName: in.PolicyName, Tags: in.Tags, DefaultVersionId: aws.String("v1")}
+	f.policies[policyARN] = policy
+	f.policyVersions[policyARN] = []*iam.PolicyVersion{{
+		VersionId:        aws.String("v1"),
+		Document:         in.PolicyDocument,
+		IsDefaultVersion: aws.Bool(true),
+		CreateDate:       aws.Time(time.Now()),
+	}}
+	return &iam.CreatePolicyOutput{Policy: policy}, nil
+}
+
+func (f *fakeIAM) GetPolicy(in *iam.GetPolicyInput) (*iam.GetPolicyOutput, error) {
+	policy, ok := f.policies[aws.StringValue(in.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity()
+	}
+	return &iam.GetPolicyOutput{Policy: policy}, nil
+}
+
+func (f *fakeIAM) GetPolicyVersion(in *iam.GetPolicyVersionInput) (*iam.GetPolicyVersionOutput, error) {
+	for _, version := range f.policyVersions[aws.StringValue(in.PolicyArn)] {
+		if aws.StringValue(version.VersionId) == aws.StringValue(in.VersionId) {
+			return &iam.GetPolicyVersionOutput{PolicyVersion: version}, nil
+		}
+	}
+	return nil, noSuchEntity()
+}
+
+func (f *fakeIAM) ListPolicyVersions(in *iam.ListPolicyVersionsInput) (*iam.ListPolicyVersionsOutput, error) {
+	return &iam.ListPolicyVersionsOutput{Versions: f.policyVersions[aws.StringValue(in.PolicyArn)]}, nil
+}
+
+func (f *fakeIAM) CreatePolicyVersion(in *iam.CreatePolicyVersionInput) (*iam.CreatePolicyVersionOutput, error) {
+	policyARN := aws.StringValue(in.PolicyArn)
+	policy := f.policies[policyARN]
+	versions := f.policyVersions[policyARN]
+	if len(versions) >= maxPolicyVersions {
+		return nil, fmt.Errorf("too many versions of policy %q", policyARN)
+	}
+	last := 0
+	for _, version := range versions {
+		version.IsDefaultVersion = aws.Bool(false)
+		var id int
+		if _, err := fmt.Sscanf(aws.StringValue(version.VersionId), "v%d", &id); err == nil && id > last {
+			last = id
+		}
+	}
+	policy.DefaultVersionId = aws.String(fmt.Sprintf("v%d", last+1))
+	f.policyVersions[policyARN] = append(versions, &iam.PolicyVersion{
+		VersionId:        policy.DefaultVersionId,
+		Document:         aws.String(url.QueryEscape(aws.StringValue(in.PolicyDocument))),
+		IsDefaultVersion: aws.Bool(true),
+		CreateDate:       aws.Time(time.Now()),
+	})
+	return &iam.CreatePolicyVersionOutput{}, nil
+}
+
+func (f *fakeIAM) DeletePolicyVersion(in *iam.DeletePolicyVersionInput) (*iam.DeletePolicyVersionOutput, error) {
+	policyARN := aws.StringValue(in.PolicyArn)
+	var remaining []*iam.PolicyVersion
+	for _, version := range f.policyVersions[policyARN] {
+		if aws.StringValue(version.VersionId) != aws.StringValue(in.VersionId) {
+			remaining = append(remaining, version)
+		}
+	}
+	f.policyVersions[policyARN] = remaining
+	return &iam.DeletePolicyVersionOutput{}, nil
+}
+
+func (f *fakeIAM) ListEntitiesForPolicy(in *iam.ListEntitiesForPolicyInput) (*iam.ListEntitiesForPolicyOutput, error) {
+	out := &iam.ListEntitiesForPolicyOutput{}
+	for role, policyARNs := range f.attachedPolicies {
+		for _, policyARN := range policyARNs {
+			if policyARN == aws.StringValue(in.PolicyArn) {
+				out.PolicyRoles = append(out.PolicyRoles, &iam.PolicyRole{RoleName: aws.String(role)})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) DeletePolicy(in *iam.DeletePolicyInput) (*iam.DeletePolicyOutput, error) {
+	delete(f.policies, aws.StringValue(in.PolicyArn))
+	delete(f.policyVersions, aws.StringValue(in.PolicyArn))
+	return &iam.DeletePolicyOutput{}, nil
+}
+
+func newTestConfiguration(mutate func(*bootstrapv1.AWSIAMConfigurationSpec)) (*bootstrapv1.AWSIAMConfiguration, *bootstrapv1.AWSIAMConfigurationSpec) {
+	conf := &bootstrapv1.AWSIAMConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	spec := conf.Spec.DeepCopy()
+	if mutate != nil {
+		mutate(spec)
+	}
+	bootstrapv1.SetDefaults_AWSIAMConfigurationSpec(spec)
+	return conf, spec
+}
+
+func TestReconcileConfiguration(t *testing.T) {
+	g := NewWithT(t)
+
+	iamClient := newFakeIAM()
+	s := &ConfigurationService{IAMClient: iamClient, STSClient: &fakeSTS{}, log: klogr.New()}
+
+	conf, spec := newTestConfiguration(func(spec *bootstrapv1.AWSIAMConfigurationSpec) {
+		spec.Nodes.ExtraPolicyAttachments = []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}
+	})
+	g.Expect(s.ReconcileConfiguration(conf, spec)).To(Succeed())
+	// A second pass must not change anything.
+	g.Expect(s.ReconcileConfiguration(conf, spec)).To(Succeed())
+
+	g.Expect(conf.Status.Roles).To(ConsistOf("control-plane.cluster-api-provider-aws.sigs.k8s.io", "controllers.cluster-api-provider-aws.sigs.k8s.io", "nodes.cluster-api-provider-aws.sigs.k8s.io"))
+	g.Expect(conf.Status.InstanceProfiles).To(ConsistOf("control-plane.cluster-api-provider-aws.sigs.k8s.io", "controllers.cluster-api-provider-aws.sigs.k8s.io", "nodes.cluster-api-provider-aws.sigs.k8s.io"))
+	g.Expect(conf.Status.ManagedPolicies).To(HaveLen(3))
+	g.Expect(conf.Status.ManagedPolicies).To(ContainElement("arn:aws:iam::123456789012:policy/controllers.cluster-api-provider-aws.sigs.k8s.io"))
+
+	for _, name := range conf.Status.Roles {
+		g.Expect(iamClient.roles).To(HaveKey(name))
+		g.Expect(awsTagValue(iamClient.roles[name].Tags)).To(Equal("test"))
+		g.Expect(iamClient.instanceProfiles[name]).To(ConsistOf(name))
+	}
+	g.Expect(iamClient.attachedPolicies["nodes.cluster-api-provider-aws.sigs.k8s.io"]).To(ContainElement("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"))
+	g.Expect(iamClient.attachedPolicies["controllers.cluster-api-provider-aws.sigs.k8s.io"]).To(ConsistOf("arn:aws:iam::123456789012:policy/controllers.cluster-api-provider-aws.sigs.k8s.io"))
+	for _, versions := range iamClient.policyVersions {
+		g.Expect(versions).To(HaveLen(1))
+	}
+
+	// Changes of the configuration are applied to the existing resources.
+	_, spec = newTestConfiguration(func(spec *bootstrapv1.AWSIAMConfigurationSpec) {
+		spec.Nodes.PermissionsBoundary = "arn:aws:iam::123456789012:policy/boundary"
+		spec.Nodes.Tags = infrav1.Tags{"team": "platform"}
+		spec.Nodes.ExtraStatements = []infrav1.StatementEntry{{
+			Effect:   infrav1.EffectAllow,
+			Action:   infrav1.Actions{"s3:GetObject"},
+			Resource: infrav1.Resources{"*"},
+		}}
+	})
+	g.Expect(s.ReconcileConfiguration(conf, spec)).To(Succeed())
+
+	nodes := iamClient.roles["nodes.cluster-api-provider-aws.sigs.k8s.io"]
+	g.Expect(aws.StringValue(nodes.PermissionsBoundary.PermissionsBoundaryArn)).To(Equal("arn:aws:iam::123456789012:policy/boundary"))
+	g.Expect(nodes.Tags).To(ContainElement(&iam.Tag{Key: aws.String("team"), Value: aws.String("platform")}))
+	g.Expect(iamClient.attachedPolicies["nodes.cluster-api-provider-aws.sigs.k8s.io"]).NotTo(ContainElement("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"))
+
+	// Renamed resources are created and the previous ones deleted.
+	_, spec = newTestConfiguration(func(spec *bootstrapv1.AWSIAMConfigurationSpec) {
+		spec.NameSuffix = aws.String(".example.com")
+	})
+	g.Expect(s.ReconcileConfiguration(conf, spec)).To(Succeed())
+	g.Expect(conf.Status.Roles).To(ConsistOf("control-plane.example.com", "controllers.example.com", "nodes.example.com"))
+	g.Expect(iamClient.roles).To(HaveLen(3))
+	g.Expect(iamClient.instanceProfiles).To(HaveLen(3))
+	g.Expect(iamClient.policies).To(HaveLen(3))
+
+	g.Expect(s.DeleteConfiguration(conf, spec)).To(Succeed())
+	g.Expect(iamClient.roles).To(BeEmpty())
+	g.Expect(iamClient.instanceProfiles).To(BeEmpty())
+	g.Expect(iamClient.policies).To(BeEmpty())
+	g.Expect(iamClient.inlinePolicies).To(BeEmpty())
+	g.Expect(conf.Status.Roles).To(BeEmpty())
+}
+
+func TestReconcileConfigurationUpdatesManagedPolicies(t *testing.T) {
+	g := NewWithT(t)
+
+	iamClient := newFakeIAM()
+	s := &ConfigurationService{IAMClient: iamClient, STSClient: &fakeSTS{}, log: klogr.New()}
+	conf, spec := newTestConfiguration(nil)
+	g.Expect(s.ReconcileConfiguration(conf, spec)).To(Succeed())
+
+	// The oldest version is deleted to make room for more than the maximum number of versions.
+	for i := 0; i < maxPolicyVersions+1; i++ {
+		_, spec = newTestConfiguration(func(spec *bootstrapv1.AWSIAMConfigurationSpec) {
+			spec.ClusterAPIControllers.AllowedEC2InstanceProfiles = []string{fmt.Sprintf("profile-%d", i)}
+		})
+		g.Expect(s.ReconcileConfiguration(conf, spec)).To(Succeed())
+	}
+
+	policyARN := "arn:aws:iam::123456789012:policy/controllers.cluster-api-provider-aws.sigs.k8s.io"
+	g.Expect(iamClient.policyVersions[policyARN]).To(HaveLen(maxPolicyVersions))
+	version, err := iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{PolicyArn: aws.String(policyARN), VersionId: iamClient.policies[policyARN].DefaultVersionId})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(aws.StringValue(version.PolicyVersion.Document)).To(ContainSubstring(url.QueryEscape("profile-5")))
+}
+
+func TestReconcileConfigurationRoleNotOwned(t *testing.T) {
+	g := NewWithT(t)
+
+	iamClient := newFakeIAM()
+	iamClient.roles["nodes.cluster-api-provider-aws.sigs.k8s.io"] = &iam.Role{
+		RoleName: aws.String("nodes.cluster-api-provider-aws.sigs.k8s.io"),
+		Path:     aws.String("/"),
+	}
+	s := &ConfigurationService{IAMClient: iamClient, STSClient: &fakeSTS{}, log: klogr.New()}
+
+	conf, spec := newTestConfiguration(nil)
+	g.Expect(s.ReconcileConfiguration(conf, spec)).NotTo(Succeed())
+
+	// Resources which aren't owned by the configuration are never deleted.
+	conf.Status.Roles = []string{"nodes.cluster-api-provider-aws.sigs.k8s.io"}
+	g.Expect(s.DeleteConfiguration(conf, spec)).To(Succeed())
+	g.Expect(iamClient.roles).To(HaveKey("nodes.cluster-api-provider-aws.sigs.k8s.io"))
+}
+
+func awsTagValue(tags []*iam.Tag) string {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == ConfigurationTagKey {
+			return aws.StringValue(tag.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	awsconverters "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+const (
+	// pathPrefix is the IAM path under which the roles and instance profiles of machines are created.
+	pathPrefix = "/cluster-api-provider-aws/"
+
+	// inlinePolicyName is the name of the inline policy built from ManagedIAMInstanceProfile.InlinePolicy.
+	inlinePolicyName = "cluster-api-provider-aws-inline"
+)
+
+// ReconcileInstanceProfile ensures the IAM role and instance profile of the machine exist
+// and carry the policies declared in its spec.
+func (s *Service) ReconcileInstanceProfile(m *scope.MachineScope) error {
+	spec := m.AWSMachine.Spec.ManagedIAMInstanceProfile
+	if spec == nil {
+		return nil
+	}
+
+	name := m.ManagedIAMInstanceProfileName()
+
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(m.Name()),
+		Role:        aws.String(m.Role()),
+		Additional:  m.AdditionalTags(),
+	})
+
+	if err := s.ensureRole(name, tags); err != nil {
+		return errors.Wrapf(err, "ensuring IAM role %q", name)
+	}
+
+	if err := ensureRoleManagedPolicies(s.IAMClient, name, spec.ManagedPolicyARNs); err != nil {
+		return errors.Wrapf(err, "ensuring managed policies of IAM role %q", name)
+	}
+
+	if err := s.ensureInlinePolicy(name, spec.InlinePolicy); err != nil {
+		return errors.Wrapf(err, "ensuring inline policy of IAM role %q", name)
+	}
+
+	if err := s.ensureInstanceProfile(name); err != nil {
+		return errors.Wrapf(err, "ensuring IAM instance profile %q", name)
+	}
+
+	return nil
+}
+
+// ReconcileRoleAndInstanceProfile ensures an IAM role and an instance profile of the given name exist
+// under the IAM path of the cluster, and that the role carries exactly the given managed policies.
+func (s *Service) ReconcileRoleAndInstanceProfile(name string, policyARNs []string, tags infrav1.Tags) error {
+	if err := s.ensureRole(name, tags); err != nil {
+		return errors.Wrapf(err, "ensuring IAM role %q", name)
+	}
+
+	if err := ensureRoleManagedPolicies(s.IAMClient, name, policyARNs); err != nil {
+		return errors.Wrapf(err, "ensuring managed policies of IAM role %q", name)
+	}
+
+	if err := s.ensureInstanceProfile(name); err != nil {
+		return errors.Wrapf(err, "ensuring IAM instance profile %q", name)
+	}
+
+	return nil
+}
+
+// DeleteRoleAndInstanceProfile deletes the IAM instance profile and role of the given name.
+func (s *Service) DeleteRoleAndInstanceProfile(name string) error {
+	return s.deleteInstanceProfileAndRole(name)
+}
+
+// DeleteInstanceProfile deletes the IAM instance profile and role of the machine.
+func (s *Service) DeleteInstanceProfile(m *scope.MachineScope) error {
+	if m.AWSMachine.Spec.ManagedIAMInstanceProfile == nil {
+		return nil
+	}
+
+	return s.deleteInstanceProfileAndRole(m.ManagedIAMInstanceProfileName())
+}
+
+// DeleteInstanceProfiles deletes every IAM instance profile and role created for machines of the cluster,
+// including those left behind by machines which were removed without being cleaned up.
+func (s *Service) DeleteInstanceProfiles() error {
+	var names []string
+	err := s.IAMClient.ListRolesPages(&iam.ListRolesInput{PathPrefix: aws.String(s.path())}, func(page *iam.ListRolesOutput, lastPage bool) bool {
+		for _, role := range page.Roles {
+			names = append(names, aws.StringValue(role.RoleName))
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing IAM roles of machines")
+	}
+
+	for _, name := range names {
+		if err := s.deleteInstanceProfileAndRole(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ensureRole(name string, tags infrav1.Tags) error {
+	out, err := s.IAMClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(name)})
+	if err == nil {
+		if !awsconverters.IAMTagsToMap(out.Role.Tags).HasOwned(s.scope.Name()) {
+			return errors.Errorf("role exists and is not owned by cluster %q", s.scope.Name())
+		}
+		return nil
+	}
+	if code, _ := awserrors.Code(err); code != iam.ErrCodeNoSuchEntityException {
+		return err
+	}
+
+	trustPolicy, err := converters.IAMPolicyDocumentToJSON(infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		Statement: infrav1.Statements{
+			{
+				Effect:    infrav1.EffectAllow,
+				Principal: infrav1.Principals{infrav1.PrincipalService: infrav1.PrincipalID{awsarn.EC2ServicePrincipal(s.scope.Region())}},
+				Action:    infrav1.Actions{"sts:AssumeRole"},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "building trust policy")
+	}
+
+	if _, err := s.IAMClient.CreateRole(&iam.CreateRoleInput{
+		RoleName:                 aws.String(name),
+		Path:                     aws.String(s.path()),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+		Tags:                     awsconverters.MapToIAMTags(tags),
+	}); err != nil {
+		return err
+	}
+
+	s.scope.Info("Created IAM role", "role", name)
+	return nil
+}
+
+// ensureRoleManagedPolicies attaches exactly the given managed policies to the role.
+func ensureRoleManagedPolicies(client iamiface.IAMAPI, roleName string, policyARNs []string) error {
+	out, err := client.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(policyARNs))
+	for _, arn := range policyARNs {
+		wanted[arn] = true
+	}
+
+	for _, policy := range out.AttachedPolicies {
+		arn := aws.StringValue(policy.PolicyArn)
+		if wanted[arn] {
+			delete(wanted, arn)
+			continue
+		}
+		if _, err := client.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: policy.PolicyArn}); err != nil {
+			return errors.Wrapf(err, "detaching policy %q", arn)
+		}
+	}
+
+	for _, arn := range policyARNs {
+		if !wanted[arn] {
+			continue
+		}
+		if _, err := client.AttachRolePolicy(&iam.AttachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(arn)}); err != nil {
+			return errors.Wrapf(err, "attaching policy %q", arn)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ensureInlinePolicy(roleName string, policy string) error {
+	if policy == "" {
+		_, err := s.IAMClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+			RoleName:   aws.String(roleName),
+			PolicyName: aws.String(inlinePolicyName),
+		})
+		if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+			return nil
+		}
+		return err
+	}
+
+	_, err := s.IAMClient.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(inlinePolicyName),
+		PolicyDocument: aws.String(policy),
+	})
+	return err
+}
+
+func (s *Service) ensureInstanceProfile(name string) error {
+	out, err := s.IAMClient.GetInstanceProfile(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		if _, err := s.IAMClient.CreateInstanceProfile(&iam.CreateInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+			Path:                aws.String(s.path()),
+		}); err != nil {
+			return err
+		}
+		s.scope.Info("Created IAM instance profile", "instance-profile", name)
+	} else if err != nil {
+		return err
+	} else {
+		for _, role := range out.InstanceProfile.Roles {
+			if aws.StringValue(role.RoleName) == name {
+				return nil
+			}
+		}
+	}
+
+	_, err = s.IAMClient.AddRoleToInstanceProfile(&iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		RoleName:            aws.String(name),
+	})
+	return err
+}
+
+// deleteInstanceProfileAndRole deletes the IAM instance profile and role of the given name, unless the role is not
+// owned by the cluster. ensureRole refuses to adopt such a role, so its name collides with a role created outside
+// of the cluster, which must be left alone.
+func (s *Service) deleteInstanceProfileAndRole(name string) error {
+	out, err := s.IAMClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(name)})
+	if code, _ := awserrors.Code(err); code == iam.ErrCodeNoSuchEntityException {
+		// The instance profile is deleted before the role, so neither is left.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "getting role %q", name)
+	}
+	if !awsconverters.IAMTagsToMap(out.Role.Tags).HasOwned(s.scope.Name()) {
+		s.scope.Info("Skipping deletion of IAM role not owned by the cluster", "role", name)
+		return nil
+	}
+
+	steps := []struct {
+		description string
+		delete      func() error
+	}{
+		{
+			description: "removing role from instance profile",
+			delete: func() error {
+				_, err := s.IAMClient.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+					InstanceProfileName: aws.String(name),
+					RoleName:            aws.String(name),
+				})
+				return err
+			},
+		},
+		{
+			description: "deleting instance profile",
+			delete: func() error {
+				_, err := s.IAMClient.DeleteInstanceProfile(&iam.DeleteInstanceProfileInput{InstanceProfileName: aws.String(name)})
+				return err
+			},
+		},
+		{
+			description: "detaching managed policies",
+			delete: func() error {
+				return ensureRoleManagedPolicies(s.IAMClient, name, nil)
+			},
+		},
+		{
+			description: "deleting inline policy",
+			delete: func() error {
+				return s.ensureInlinePolicy(name, "")
+			},
+		},
+		{
+			description: "deleting role",
+			delete: func() error {
+				_, err := s.IAMClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(name)})
+				return err
+			},
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.delete(); err != nil {
+			if code, _ := awserrors.Code(errors.Cause(err)); code == iam.ErrCodeNoSuchEntityException {
+				continue
+			}
+			return errors.Wrapf(err, "%s %q", step.description, name)
+		}
+	}
+
+	s.scope.Info("Deleted IAM instance profile and role", "name", name)
+	return nil
+}
+
+// path returns the IAM path of the roles and instance profiles created for machines of the cluster.
+func (s *Service) path() string {
+	return fmt.Sprintf("%s%s/%s/", pathPrefix, s.scope.Namespace(), s.scope.Name())
+}
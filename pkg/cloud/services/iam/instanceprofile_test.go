@@ -0,0 +1,326 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// fakeIAM keeps roles, instance profiles and managed policies in memory.
+type fakeIAM struct {
+	iamiface.IAMAPI
+
+	roles            map[string]*iam.Role
+	attachedPolicies map[string][]string
+	inlinePolicies   map[string]map[string]string
+	instanceProfiles map[string][]string
+	profileTags      map[string][]*iam.Tag
+	policies         map[string]*iam.Policy
+	policyVersions   map[string][]*iam.PolicyVersion
+}
+
+func newFakeIAM() *fakeIAM {
+	return &fakeIAM{
+		roles:            map[string]*iam.Role{},
+		attachedPolicies: map[string][]string{},
+		inlinePolicies:   map[string]map[string]string{},
+		instanceProfiles: map[string][]string{},
+		profileTags:      map[string][]*iam.Tag{},
+		policies:         map[string]*iam.Policy{},
+		policyVersions:   map[string][]*iam.PolicyVersion{},
+	}
+}
+
+func noSuchEntity() error {
+	return awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil)
+}
+
+func (f *fakeIAM) GetRole(in *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	role, ok := f.roles[aws.StringValue(in.RoleName)]
+	if !ok {
+		return nil, noSuchEntity()
+	}
+	return &iam.GetRoleOutput{Role: role}, nil
+}
+
+func (f *fakeIAM) CreateRole(in *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	role := &iam.Role{RoleName: in.RoleName, Path: in.Path, Tags: in.Tags, AssumeRolePolicyDocument: in.AssumeRolePolicyDocument}
+	if in.PermissionsBoundary != nil {
+		role.PermissionsBoundary = &iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: in.PermissionsBoundary}
+	}
+	f.roles[aws.StringValue(in.RoleName)] = role
+	return &iam.CreateRoleOutput{Role: role}, nil
+}
+
+func (f *fakeIAM) DeleteRole(in *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error) {
+	if _, ok := f.roles[aws.StringValue(in.RoleName)]; !ok {
+		return nil, noSuchEntity()
+	}
+	delete(f.roles, aws.StringValue(in.RoleName))
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+func (f *fakeIAM) ListRolesPages(in *iam.ListRolesInput, fn func(*iam.ListRolesOutput, bool) bool) error {
+	out := &iam.ListRolesOutput{}
+	for _, role := range f.roles {
+		if aws.StringValue(role.Path) == aws.StringValue(in.PathPrefix) {
+			out.Roles = append(out.Roles, role)
+		}
+	}
+	fn(out, true)
+	return nil
+}
+
+func (f *fakeIAM) ListAttachedRolePolicies(in *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
+	name := aws.StringValue(in.RoleName)
+	if _, ok := f.roles[name]; !ok {
+		return nil, noSuchEntity()
+	}
+	out := &iam.ListAttachedRolePoliciesOutput{}
+	for _, arn := range f.attachedPolicies[name] {
+		out.AttachedPolicies = append(out.AttachedPolicies, &iam.AttachedPolicy{PolicyArn: aws.String(arn)})
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) AttachRolePolicy(in *iam.AttachRolePolicyInput) (*iam.AttachRolePolicyOutput, error) {
+	name := aws.StringValue(in.RoleName)
+	f.attachedPolicies[name] = append(f.attachedPolicies[name], aws.StringValue(in.PolicyArn))
+	return &iam.AttachRolePolicyOutput{}, nil
+}
+
+func (f *fakeIAM) DetachRolePolicy(in *iam.DetachRolePolicyInput) (*iam.DetachRolePolicyOutput, error) {
+	name := aws.StringValue(in.RoleName)
+	var remaining []string
+	for _, arn := range f.attachedPolicies[name] {
+		if arn != aws.StringValue(in.PolicyArn) {
+			remaining = append(remaining, arn)
+		}
+	}
+	f.attachedPolicies[name] = remaining
+	return &iam.DetachRolePolicyOutput{}, nil
+}
+
+func (f *fakeIAM) PutRolePolicy(in *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
+	name := aws.StringValue(in.RoleName)
+	if f.inlinePolicies[name] == nil {
+		f.inlinePolicies[name] = map[string]string{}
+	}
+	f.inlinePolicies[name][aws.StringValue(in.PolicyName)] = aws.StringValue(in.PolicyDocument)
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func (f *fakeIAM) DeleteRolePolicy(in *iam.DeleteRolePolicyInput) (*iam.DeleteRolePolicyOutput, error) {
+	name := aws.StringValue(in.RoleName)
+	if _, ok := f.inlinePolicies[name][aws.StringValue(in.PolicyName)]; !ok {
+		return nil, noSuchEntity()
+	}
+	delete(f.inlinePolicies[name], aws.StringValue(in.PolicyName))
+	if len(f.inlinePolicies[name]) == 0 {
+		delete(f.inlinePolicies, name)
+	}
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+func (f *fakeIAM) GetInstanceProfile(in *iam.GetInstanceProfileInput) (*iam.GetInstanceProfileOutput, error) {
+	roles, ok := f.instanceProfiles[aws.StringValue(in.InstanceProfileName)]
+	if !ok {
+		return nil, noSuchEntity()
+	}
+	profile := &iam.InstanceProfile{InstanceProfileName: in.InstanceProfileName, Tags: f.profileTags[aws.StringValue(in.InstanceProfileName)]}
+	for _, role := range roles {
+		profile.Roles = append(profile.Roles, &iam.Role{RoleName: aws.String(role)})
+	}
+	return &iam.GetInstanceProfileOutput{InstanceProfile: profile}, nil
+}
+
+func (f *fakeIAM) CreateInstanceProfile(in *iam.CreateInstanceProfileInput) (*iam.CreateInstanceProfileOutput, error) {
+	f.instanceProfiles[aws.StringValue(in.InstanceProfileName)] = nil
+	f.profileTags[aws.StringValue(in.InstanceProfileName)] = in.Tags
+	return &iam.CreateInstanceProfileOutput{}, nil
+}
+
+func (f *fakeIAM) DeleteInstanceProfile(in *iam.DeleteInstanceProfileInput) (*iam.DeleteInstanceProfileOutput, error) {
+	if _, ok := f.instanceProfiles[aws.StringValue(in.InstanceProfileName)]; !ok {
+		return nil, noSuchEntity()
+	}
+	delete(f.instanceProfiles, aws.StringValue(in.InstanceProfileName))
+	delete(f.profileTags, aws.StringValue(in.InstanceProfileName))
+	return &iam.DeleteInstanceProfileOutput{}, nil
+}
+
+func (f *fakeIAM) AddRoleToInstanceProfile(in *iam.AddRoleToInstanceProfileInput) (*iam.AddRoleToInstanceProfileOutput, error) {
+	name := aws.StringValue(in.InstanceProfileName)
+	f.instanceProfiles[name] = append(f.instanceProfiles[name], aws.StringValue(in.RoleName))
+	return &iam.AddRoleToInstanceProfileOutput{}, nil
+}
+
+func (f *fakeIAM) RemoveRoleFromInstanceProfile(in *iam.RemoveRoleFromInstanceProfileInput) (*iam.RemoveRoleFromInstanceProfileOutput, error) {
+	name := aws.StringValue(in.InstanceProfileName)
+	if _, ok := f.instanceProfiles[name]; !ok {
+		return nil, noSuchEntity()
+	}
+	f.instanceProfiles[name] = nil
+	return &iam.RemoveRoleFromInstanceProfileOutput{}, nil
+}
+
+func TestReconcileInstanceProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope, err := setupMachine("us-west-2", &infrav1.ManagedIAMInstanceProfile{
+		ManagedPolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"},
+		InlinePolicy:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"*"}]}`,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	iamClient := newFakeIAM()
+	iamClient.attachedPolicies["test-default-machine"] = []string{"arn:aws:iam::aws:policy/Stale"}
+	s := NewService(machineScope.InfraCluster)
+	s.IAMClient = iamClient
+
+	g.Expect(s.ReconcileInstanceProfile(machineScope)).To(Succeed())
+	// A second pass must not change anything.
+	g.Expect(s.ReconcileInstanceProfile(machineScope)).To(Succeed())
+
+	g.Expect(iamClient.roles).To(HaveKey("test-default-machine"))
+	g.Expect(aws.StringValue(iamClient.roles["test-default-machine"].Path)).To(Equal("/cluster-api-provider-aws/default/test/"))
+	g.Expect(iamClient.attachedPolicies["test-default-machine"]).To(ConsistOf("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"))
+	g.Expect(iamClient.inlinePolicies).To(HaveKey("test-default-machine"))
+	g.Expect(iamClient.instanceProfiles["test-default-machine"]).To(ConsistOf("test-default-machine"))
+
+	g.Expect(s.DeleteInstanceProfile(machineScope)).To(Succeed())
+	g.Expect(iamClient.roles).To(BeEmpty())
+	g.Expect(iamClient.attachedPolicies["test-default-machine"]).To(BeEmpty())
+	g.Expect(iamClient.inlinePolicies).To(BeEmpty())
+	g.Expect(iamClient.instanceProfiles).To(BeEmpty())
+
+	// Deleting again is a no-op.
+	g.Expect(s.DeleteInstanceProfile(machineScope)).To(Succeed())
+}
+
+func TestReconcileInstanceProfileTrustsEC2OfPartition(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope, err := setupMachine("cn-north-1", &infrav1.ManagedIAMInstanceProfile{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	iamClient := newFakeIAM()
+	s := NewService(machineScope.InfraCluster)
+	s.IAMClient = iamClient
+
+	g.Expect(s.ReconcileInstanceProfile(machineScope)).To(Succeed())
+	g.Expect(iamClient.roles).To(HaveKey("test-default-machine"))
+	g.Expect(aws.StringValue(iamClient.roles["test-default-machine"].AssumeRolePolicyDocument)).To(ContainSubstring(`"ec2.amazonaws.com.cn"`))
+}
+
+func TestReconcileInstanceProfileRoleNotOwned(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope, err := setupMachine("us-west-2", &infrav1.ManagedIAMInstanceProfile{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	iamClient := newFakeIAM()
+	iamClient.roles["test-default-machine"] = &iam.Role{RoleName: aws.String("test-default-machine")}
+	s := NewService(machineScope.InfraCluster)
+	s.IAMClient = iamClient
+
+	g.Expect(s.ReconcileInstanceProfile(machineScope)).NotTo(Succeed())
+	g.Expect(iamClient.instanceProfiles).To(BeEmpty())
+}
+
+func TestDeleteInstanceProfileRoleNotOwned(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope, err := setupMachine("us-west-2", &infrav1.ManagedIAMInstanceProfile{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	iamClient := newFakeIAM()
+	iamClient.roles["test-default-machine"] = &iam.Role{
+		RoleName: aws.String("test-default-machine"),
+		Tags:     []*iam.Tag{{Key: aws.String(infrav1.ClusterTagKey("other")), Value: aws.String(string(infrav1.ResourceLifecycleOwned))}},
+	}
+	iamClient.attachedPolicies["test-default-machine"] = []string{"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}
+	iamClient.instanceProfiles["test-default-machine"] = []string{"test-default-machine"}
+	s := NewService(machineScope.InfraCluster)
+	s.IAMClient = iamClient
+
+	g.Expect(s.ReconcileInstanceProfile(machineScope)).NotTo(Succeed())
+	g.Expect(s.DeleteInstanceProfile(machineScope)).To(Succeed())
+	g.Expect(iamClient.roles).To(HaveKey("test-default-machine"))
+	g.Expect(iamClient.attachedPolicies["test-default-machine"]).To(ConsistOf("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"))
+	g.Expect(iamClient.instanceProfiles["test-default-machine"]).To(ConsistOf("test-default-machine"))
+}
+
+func TestDeleteInstanceProfiles(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope, err := setupMachine("us-west-2", &infrav1.ManagedIAMInstanceProfile{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	iamClient := newFakeIAM()
+	s := NewService(machineScope.InfraCluster)
+	s.IAMClient = iamClient
+	g.Expect(s.ReconcileInstanceProfile(machineScope)).To(Succeed())
+	iamClient.roles["other"] = &iam.Role{RoleName: aws.String("other"), Path: aws.String("/")}
+
+	g.Expect(s.DeleteInstanceProfiles()).To(Succeed())
+	g.Expect(iamClient.roles).To(HaveLen(1))
+	g.Expect(iamClient.roles).To(HaveKey("other"))
+	g.Expect(iamClient.instanceProfiles).To(BeEmpty())
+}
+
+func setupMachine(region string, profile *infrav1.ManagedIAMInstanceProfile) (*scope.MachineScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec:       infrav1.AWSClusterSpec{Region: region},
+	}
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:     client,
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scope.NewMachineScope(scope.MachineScopeParams{
+		Client:       client,
+		Cluster:      cluster,
+		Machine:      &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"}},
+		InfraCluster: clusterScope,
+		AWSMachine: &infrav1.AWSMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+			Spec:       infrav1.AWSMachineSpec{ManagedIAMInstanceProfile: profile},
+		},
+	})
+}
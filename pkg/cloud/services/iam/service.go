@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the ec2 client.
+type Service struct {
+	scope     cloud.ClusterScoper
+	IAMClient iamiface.IAMAPI
+}
+
+// NewService returns a new service given the api clients.
+func NewService(clusterScope cloud.ClusterScoper) *Service {
+	return &Service{
+		scope:     clusterScope,
+		IAMClient: scope.NewIAMClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
+	}
+}
@@ -16,16 +16,63 @@ limitations under the License.
 
 package instancestate
 
-// ReconcileEC2Events will reconcile a Service's EC2 events.
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+)
+
+// ReconcileEC2Events ensures EC2 instance state changes of the cluster reach the shared queue of the
+// management cluster in the region of the cluster, and deletes the per-cluster rule and queue created
+// by earlier releases, which no longer have a reader.
 func (s Service) ReconcileEC2Events() error {
-	if err := s.reconcileSQSQueue(); err != nil {
+	queue, err := s.queues.ForRegion(s.scope.Region())
+	if err != nil {
+		return err
+	}
+	if err := queue.Reconcile(); err != nil {
+		return err
+	}
+
+	if err := s.migrateEC2Events(); err != nil {
 		return err
 	}
 
-	return s.reconcileRules()
+	identity, err := s.STSClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrap(err, "unable to get the account of the cluster")
+	}
+	accountID := aws.StringValue(identity.Account)
+
+	// Events of the management account are already matched by the rule targeting the queue.
+	if queue.IsLocal(accountID) {
+		return nil
+	}
+
+	if err := queue.AuthorizeAccount(accountID); err != nil {
+		return err
+	}
+
+	return s.reconcileForwardingRule(queue)
+}
+
+// migrateEC2Events deletes the per-cluster rule and queue once per cluster.
+func (s Service) migrateEC2Events() error {
+	key := s.scope.Namespace() + "/" + s.scope.Name()
+	if _, ok := s.queues.migrated.Load(key); ok {
+		return nil
+	}
+
+	if err := s.DeleteEC2Events(); err != nil {
+		return errors.Wrap(err, "unable to delete the per-cluster EventBridge rule and queue")
+	}
+
+	s.queues.migrated.Store(key, struct{}{})
+	return nil
 }
 
-// DeleteEC2Events will delete a Service's EC2 events.
+// DeleteEC2Events removes the per-cluster rule and queue created by earlier releases. The rule forwarding
+// events to the shared queue is shared by all the clusters of the account and is left in place.
 func (s Service) DeleteEC2Events() error {
 	if err := s.deleteRules(); err != nil {
 		return err
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/sts/mock_stsiface"
+)
+
+func TestReconcileEC2Events(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventBusARN := "arn:aws:events:us-west-2:111111111111:event-bus/default"
+
+	expectMigration := func(eb *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder, m *mock_sqsiface.MockSQSAPIMockRecorder) {
+		eb.RemoveTargets(gomock.Eq(&eventbridge.RemoveTargetsInput{
+			Rule: aws.String("test-cluster-ec2-rule"),
+			Ids:  aws.StringSlice([]string{"test-cluster-queue"}),
+		})).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil)).Times(1)
+		eb.DeleteRule(gomock.Eq(&eventbridge.DeleteRuleInput{
+			Name: aws.String("test-cluster-ec2-rule"),
+		})).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil)).Times(1)
+		m.GetQueueUrl(gomock.Eq(&sqs.GetQueueUrlInput{
+			QueueName: aws.String("test-cluster-queue"),
+		})).Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil)).Times(1)
+	}
+
+	testCases := []struct {
+		name              string
+		accountID         string
+		migrates          bool
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		queueExpect       func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		expectErr         bool
+	}{
+		{
+			name:              "migrates the per-cluster resources once and doesn't forward events of the management account",
+			accountID:         "111111111111",
+			migrates:          true,
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+			queueExpect:       func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+		},
+		{
+			name:      "forwards events of a workload account to the queue of the region of the cluster",
+			accountID: "222222222222",
+			migrates:  true,
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(gomock.Any()).Return(&eventbridge.DescribeRuleOutput{}, nil).Times(4)
+				m.ListTargetsByRule(gomock.Any()).Return(&eventbridge.ListTargetsByRuleOutput{
+					Targets: []*eventbridge.Target{{
+						Arn: aws.String(eventBusARN),
+						Id:  aws.String(SharedQueueName),
+					}},
+				}, nil).Times(4)
+			},
+			queueExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.PutPermission(gomock.Eq(&eventbridge.PutPermissionInput{
+					Action:      aws.String("events:PutEvents"),
+					Principal:   aws.String("222222222222"),
+					StatementId: aws.String(SharedQueueName + "-222222222222"),
+				})).Return(&eventbridge.PutPermissionOutput{}, nil).Times(1)
+			},
+		},
+		{
+			name:      "returns error when the per-cluster resources can't be deleted",
+			accountID: "111111111111",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.RemoveTargets(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			queueExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			eventbridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			stsMock := mock_stsiface.NewMockSTSAPI(mockCtrl)
+			queueEventBridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
+			if tc.migrates {
+				expectMigration(eventbridgeMock.EXPECT(), sqsMock.EXPECT())
+			}
+			tc.queueExpect(queueEventBridgeMock.EXPECT())
+			stsMock.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: aws.String(tc.accountID)}, nil).AnyTimes()
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+			clusterScope.AWSCluster.Spec.Region = "us-west-2"
+
+			// The queue of the management cluster in us-east-1 is left alone for a cluster in us-west-2.
+			queues := &SharedQueues{queues: map[string]*SharedQueue{
+				"us-east-1": {region: "us-east-1", url: "us-east-1-queue-url", accountID: "111111111111", partition: "aws"},
+				"us-west-2": {region: "us-west-2", url: "us-west-2-queue-url", accountID: "111111111111", partition: "aws", EventBridgeClient: queueEventBridgeMock},
+			}}
+			s := NewService(clusterScope, queues)
+			s.EventBridgeClient = eventbridgeMock
+			s.SQSClient = sqsMock
+			s.STSClient = stsMock
+
+			err = s.ReconcileEC2Events()
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(s.ReconcileEC2Events()).To(Succeed())
+		})
+	}
+}
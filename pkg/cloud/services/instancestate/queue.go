@@ -17,7 +17,6 @@ limitations under the License.
 package instancestate
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -25,29 +24,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/pkg/errors"
-
-	"sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 )
 
-func (s *Service) reconcileSQSQueue() error {
-	attrs := make(map[string]string)
-	attrs[sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds] = "20"
-
-	_, err := s.SQSClient.CreateQueue(&sqs.CreateQueueInput{
-		QueueName:  aws.String(GenerateQueueName(s.scope.Name())),
-		Attributes: aws.StringMap(attrs),
-	})
-
-	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() == sqs.ErrCodeQueueNameExists {
-				return nil
-			}
-		}
-	}
-	return errors.Wrap(err, "unable to create new queue")
-}
-
+// deleteSQSQueue removes the per-cluster queue created by earlier releases.
 func (s *Service) deleteSQSQueue() error {
 	resp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(GenerateQueueName(s.scope.Name()))})
 	if err != nil {
@@ -64,39 +43,7 @@ func (s *Service) deleteSQSQueue() error {
 	return errors.Wrap(err, "unable to delete queue")
 }
 
-func (s *Service) createPolicyForRule(input *createPolicyForRuleInput) error {
-	attrs := make(map[string]string)
-	policy := v1alpha4.PolicyDocument{
-		Version: v1alpha4.CurrentVersion,
-		ID:      input.QueueArn,
-		Statement: v1alpha4.Statements{
-			v1alpha4.StatementEntry{
-				Sid:       fmt.Sprintf("CAPAEvents_%s_%s", s.getEC2RuleName(), GenerateQueueName(s.scope.Name())),
-				Effect:    v1alpha4.EffectAllow,
-				Principal: v1alpha4.Principals{v1alpha4.PrincipalService: v1alpha4.PrincipalID{"events.amazonaws.com"}},
-				Action:    v1alpha4.Actions{"sqs:SendMessage"},
-				Resource:  v1alpha4.Resources{input.QueueArn},
-				Condition: v1alpha4.Conditions{
-					"ArnEquals": map[string]string{"aws:SourceArn": input.RuleArn},
-				},
-			},
-		},
-	}
-	policyData, err := json.Marshal(policy)
-	if err != nil {
-		return errors.Wrap(err, "unable to JSON marshal policy")
-	}
-	attrs[sqs.QueueAttributeNamePolicy] = string(policyData)
-
-	_, err = s.SQSClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
-		QueueUrl:   aws.String(input.QueueURL),
-		Attributes: aws.StringMap(attrs),
-	})
-
-	return errors.Wrap(err, "unable to update queue attributes")
-}
-
-// GenerateQueueName will generate a queue name.
+// GenerateQueueName will generate the name of the per-cluster queue created by earlier releases.
 func GenerateQueueName(clusterName string) string {
 	adjusted := strings.ReplaceAll(clusterName, ".", "-")
 	return fmt.Sprintf("%s-queue", adjusted)
@@ -110,9 +57,3 @@ func queueNotFoundError(err error) bool {
 	}
 	return false
 }
-
-type createPolicyForRuleInput struct {
-	QueueArn string
-	QueueURL string
-	RuleArn  string
-}
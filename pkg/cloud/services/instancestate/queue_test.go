@@ -17,8 +17,6 @@ limitations under the License.
 package instancestate
 
 import (
-	"bytes"
-	"encoding/json"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -31,75 +29,6 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
 )
 
-func TestReconcileSQSQueue(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	testCases := []struct {
-		name      string
-		expect    func(m *mock_sqsiface.MockSQSAPIMockRecorder)
-		expectErr bool
-	}{
-		{
-			name: "successfully creates an SQS queue",
-			expect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
-				attrs := make(map[string]string)
-				attrs[sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds] = "20"
-				m.CreateQueue(&sqs.CreateQueueInput{
-					QueueName:  aws.String("test-cluster-queue"),
-					Attributes: aws.StringMap(attrs),
-				}).Return(nil, nil)
-			},
-			expectErr: false,
-		},
-		{
-			name: "does not error if queue already exists",
-			expect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
-				attrs := make(map[string]string)
-				attrs[sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds] = "20"
-				m.CreateQueue(&sqs.CreateQueueInput{
-					QueueName:  aws.String("test-cluster-queue"),
-					Attributes: aws.StringMap(attrs),
-				}).Return(nil, awserr.New(sqs.ErrCodeQueueNameExists, "", nil))
-			},
-			expectErr: false,
-		},
-		{
-			name: "errors when unexpected error occurs",
-			expect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
-				attrs := make(map[string]string)
-				attrs[sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds] = "20"
-				m.CreateQueue(&sqs.CreateQueueInput{
-					QueueName:  aws.String("test-cluster-queue"),
-					Attributes: aws.StringMap(attrs),
-				}).Return(nil, errors.New("some error"))
-			},
-			expectErr: true,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			g := NewWithT(t)
-			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
-			clusterScope, err := setupCluster("test-cluster")
-			g.Expect(err).To(Not(HaveOccurred()))
-
-			tc.expect(sqsMock.EXPECT())
-			s := NewService(clusterScope)
-			s.SQSClient = sqsMock
-
-			err = s.reconcileSQSQueue()
-
-			if tc.expectErr {
-				g.Expect(err).NotTo(BeNil())
-			} else {
-				g.Expect(err).To(BeNil())
-			}
-		})
-	}
-}
-
 func TestDeleteSQSQueue(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -173,7 +102,7 @@ func TestDeleteSQSQueue(t *testing.T) {
 			g.Expect(err).To(Not(HaveOccurred()))
 
 			tc.expect(sqsMock.EXPECT())
-			s := NewService(clusterScope)
+			s := NewService(clusterScope, nil)
 			s.SQSClient = sqsMock
 
 			err = s.deleteSQSQueue()
@@ -187,59 +116,6 @@ func TestDeleteSQSQueue(t *testing.T) {
 	}
 }
 
-func TestCreatePolicyForRule(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	testCases := []struct {
-		name      string
-		input     *createPolicyForRuleInput
-		expect    func(m *mock_sqsiface.MockSQSAPIMockRecorder)
-		expectErr bool
-	}{
-		{
-			name: "creates a policy for a given rule",
-			input: &createPolicyForRuleInput{
-				QueueArn: "test-cluster-queue-arn",
-				QueueURL: "test-cluster-queue-url",
-				RuleArn:  "test-cluster-rule-arn",
-			},
-			expect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
-				buffer := new(bytes.Buffer)
-				_ = json.Compact(buffer, []byte(expectedPolicyJSON))
-				attrs := make(map[string]string)
-				attrs[sqs.QueueAttributeNamePolicy] = buffer.String()
-				m.SetQueueAttributes(&sqs.SetQueueAttributesInput{
-					QueueUrl:   aws.String("test-cluster-queue-url"),
-					Attributes: aws.StringMap(attrs),
-				}).Return(nil, nil)
-			},
-			expectErr: false,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			g := NewWithT(t)
-			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
-			clusterScope, err := setupCluster("test-cluster")
-			g.Expect(err).To(Not(HaveOccurred()))
-
-			tc.expect(sqsMock.EXPECT())
-			s := NewService(clusterScope)
-			s.SQSClient = sqsMock
-
-			err = s.createPolicyForRule(tc.input)
-
-			if tc.expectErr {
-				g.Expect(err).NotTo(BeNil())
-			} else {
-				g.Expect(err).To(BeNil())
-			}
-		})
-	}
-}
-
 func TestGenerateQueueName(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -23,124 +23,117 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/eventbridge"
-	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/pkg/errors"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 )
 
-// Ec2StateChangeNotification defines the EC2 instance's state change notification.
-const Ec2StateChangeNotification = "EC2 Instance State-change Notification"
+const (
+	// Ec2StateChangeNotification defines the EC2 instance's state change notification.
+	Ec2StateChangeNotification = "EC2 Instance State-change Notification"
 
-// reconcileRules creates rules and attaches the queue as a target.
-func (s Service) reconcileRules() error {
-	var ruleNotFound bool
-	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
-		Name: aws.String(s.getEC2RuleName()),
-	})
-	if err != nil {
-		if resourceNotFoundError(err) {
-			ruleNotFound = true
-		} else {
-			return errors.Wrapf(err, "unable to describe rule %s", s.getEC2RuleName())
-		}
+	// Ec2TerminateLifecycleAction defines the notification of an instance held by a terminating lifecycle hook of an ASG.
+	Ec2TerminateLifecycleAction = "EC2 Instance-terminate Lifecycle Action"
+
+	// LifecycleActionRuleName is the name of the EventBridge rules delivering the lifecycle actions to the shared queue.
+	LifecycleActionRuleName = SharedQueueName + "-lifecycle-action"
+)
+
+// sharedRule is a rule delivering events CAPA reacts to to the shared queue.
+type sharedRule struct {
+	name    string
+	pattern string
+}
+
+// sharedRules returns the rules delivering events to the shared queue. Every rule matches a single detail
+// type, because the details of the event types have nothing in common to match on.
+func sharedRules() []sharedRule {
+	return []sharedRule{
+		{name: SharedQueueName, pattern: instanceStatePattern()},
+		{name: LifecycleActionRuleName, pattern: lifecycleActionPattern()},
 	}
+}
 
-	if ruleNotFound {
-		err = s.createRule()
-		if err != nil {
-			return errors.Wrap(err, "unable to create rule")
-		}
-		// fetch newly created rule
-		ruleResp, err = s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
-			Name: aws.String(s.getEC2RuleName()),
-		})
+// instanceStatePattern returns the event pattern of the rules matching the EC2 instance state changes
+// CAPA reacts to.
+func instanceStatePattern() string {
+	pattern := eventPattern{
+		Source:     []string{"aws.ec2"},
+		DetailType: []string{Ec2StateChangeNotification},
+		EventDetail: &eventDetail{
+			States: []infrav1.InstanceState{infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated},
+		},
+	}
+	data, _ := json.Marshal(pattern)
+	return string(data)
+}
 
-		if err != nil {
-			return errors.Wrapf(err, "unable to describe new rule %s", s.getEC2RuleName())
+// lifecycleActionPattern returns the event pattern of the rules matching the instances held by a terminating
+// lifecycle hook of an ASG.
+func lifecycleActionPattern() string {
+	pattern := eventPattern{
+		Source:     []string{"aws.autoscaling"},
+		DetailType: []string{Ec2TerminateLifecycleAction},
+	}
+	data, _ := json.Marshal(pattern)
+	return string(data)
+}
+
+// reconcileForwardingRule ensures the account of the cluster forwards EC2 instance state changes and
+// lifecycle actions to the event bus of the shared queue. The rules are shared by all the clusters of the account.
+func (s Service) reconcileForwardingRule(queue *SharedQueue) error {
+	for _, rule := range sharedRules() {
+		if err := s.reconcileForwardingRuleNamed(queue, rule); err != nil {
+			return err
 		}
 	}
 
-	queueURLResp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{
-		QueueName: aws.String(GenerateQueueName(s.scope.Name())),
-	})
+	return nil
+}
 
-	if err != nil {
-		return errors.Wrap(err, "unable to get queue URL")
-	}
-	queueAttrs, err := s.SQSClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
-		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn, sqs.QueueAttributeNamePolicy}),
-		QueueUrl:       queueURLResp.QueueUrl,
+func (s Service) reconcileForwardingRuleNamed(queue *SharedQueue, rule sharedRule) error {
+	_, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
+		Name: aws.String(rule.name),
 	})
-
 	if err != nil {
-		return errors.Wrap(err, "unable to get queue attributes")
+		if !resourceNotFoundError(err) {
+			return errors.Wrapf(err, "unable to describe rule %s", rule.name)
+		}
+		if _, err := s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
+			Name:         aws.String(rule.name),
+			EventPattern: aws.String(rule.pattern),
+			State:        aws.String(eventbridge.RuleStateEnabled),
+		}); err != nil {
+			return errors.Wrapf(err, "unable to create rule %s", rule.name)
+		}
 	}
 
 	targetsResp, err := s.EventBridgeClient.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{
-		Rule: aws.String(s.getEC2RuleName()),
+		Rule: aws.String(rule.name),
 	})
 	if err != nil {
-		return errors.Wrapf(err, "unable to list targets for rule %s", s.getEC2RuleName())
+		return errors.Wrapf(err, "unable to list targets for rule %s", rule.name)
 	}
 
-	targetFound := false
+	eventBusARN := queue.EventBusARN()
 	for _, target := range targetsResp.Targets {
-		// check if queue is already added as a target
-		if *target.Id == GenerateQueueName(s.scope.Name()) && *target.Arn == *queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn] {
-			targetFound = true
-		}
-	}
-
-	if !targetFound {
-		_, err = s.EventBridgeClient.PutTargets(&eventbridge.PutTargetsInput{
-			Rule: ruleResp.Name,
-			Targets: []*eventbridge.Target{{
-				Arn: queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn],
-				Id:  aws.String(GenerateQueueName(s.scope.Name())),
-			}},
-		})
-
-		if err != nil {
-			return errors.Wrapf(err, "unable to add SQS target %s to rule %s", GenerateQueueName(s.scope.Name()), s.getEC2RuleName())
-		}
-	}
-
-	if queueAttrs.Attributes[sqs.QueueAttributeNamePolicy] == nil {
-		// add a policy for the rule so the rule is authorized to emit messages to the queue
-		err = s.createPolicyForRule(&createPolicyForRuleInput{
-			QueueArn: *queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn],
-			QueueURL: *queueURLResp.QueueUrl,
-			RuleArn:  *ruleResp.Arn,
-		})
-		if err != nil {
-			return err
+		if aws.StringValue(target.Id) == SharedQueueName && aws.StringValue(target.Arn) == eventBusARN {
+			return nil
 		}
 	}
 
-	return nil
-}
-
-func (s Service) createRule() error {
-	eventPattern := eventPattern{
-		Source:     []string{"aws.ec2"},
-		DetailType: []string{Ec2StateChangeNotification},
-		EventDetail: &eventDetail{
-			States: []infrav1.InstanceState{infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated},
-		},
-	}
-	data, _ := json.Marshal(eventPattern)
-	// create in disabled state so the rule doesn't pick up all EC2 instances. As machines get created,
-	// the rule will get updated to track those machines
-	_, err := s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
-		Name:         aws.String(s.getEC2RuleName()),
-		EventPattern: aws.String(string(data)),
-		State:        aws.String(eventbridge.RuleStateDisabled),
+	_, err = s.EventBridgeClient.PutTargets(&eventbridge.PutTargetsInput{
+		Rule: aws.String(rule.name),
+		Targets: []*eventbridge.Target{{
+			Arn: aws.String(eventBusARN),
+			Id:  aws.String(SharedQueueName),
+		}},
 	})
 
-	return err
+	return errors.Wrapf(err, "unable to add event bus %s as target of rule %s", eventBusARN, rule.name)
 }
 
+// deleteRules removes the per-cluster rule created by earlier releases.
 func (s Service) deleteRules() error {
 	_, err := s.EventBridgeClient.RemoveTargets(&eventbridge.RemoveTargetsInput{
 		Rule: aws.String(s.getEC2RuleName()),
@@ -160,84 +153,6 @@ func (s Service) deleteRules() error {
 	return err
 }
 
-// AddInstanceToEventPattern will add an instance to an event pattern.
-func (s Service) AddInstanceToEventPattern(instanceID string) error {
-	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
-		Name: aws.String(s.getEC2RuleName()),
-	})
-	if err != nil {
-		return errors.Wrapf(err, "unable to describe rule %s", s.getEC2RuleName())
-	}
-	e := eventPattern{}
-	err = json.Unmarshal([]byte(*ruleResp.EventPattern), &e)
-	if err != nil {
-		return err
-	}
-	e.DetailType = []string{Ec2StateChangeNotification}
-
-	for _, r := range e.EventDetail.InstanceIDs {
-		if r == instanceID {
-			// instance is already tracked by rule
-			return nil
-		}
-	}
-
-	e.EventDetail.InstanceIDs = append(e.EventDetail.InstanceIDs, instanceID)
-	eventData, err := json.Marshal(e)
-	if err != nil {
-		return err
-	}
-	_, err = s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
-		Name:         aws.String(s.getEC2RuleName()),
-		EventPattern: aws.String(string(eventData)),
-		State:        aws.String(eventbridge.RuleStateEnabled),
-	})
-	return err
-}
-
-// RemoveInstanceFromEventPattern attempts a best effort update to the event rule to remove the instance.
-// Any errors encountered won't be blocking.
-func (s Service) RemoveInstanceFromEventPattern(instanceID string) {
-	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
-		Name: aws.String(s.getEC2RuleName()),
-	})
-	if err != nil {
-		return
-	}
-	e := eventPattern{}
-	err = json.Unmarshal([]byte(*ruleResp.EventPattern), &e)
-	if err != nil {
-		return
-	}
-	e.DetailType = []string{Ec2StateChangeNotification}
-
-	found := false
-	for i, r := range e.EventDetail.InstanceIDs {
-		if r == instanceID {
-			found = true
-			e.EventDetail.InstanceIDs = append(e.EventDetail.InstanceIDs[:i], e.EventDetail.InstanceIDs[i+1:]...)
-			break
-		}
-	}
-
-	if found {
-		eventData, err := json.Marshal(e)
-		if err != nil {
-			return
-		}
-		input := &eventbridge.PutRuleInput{
-			Name:         aws.String(s.getEC2RuleName()),
-			EventPattern: aws.String(string(eventData)),
-			State:        aws.String(eventbridge.RuleStateEnabled),
-		}
-
-		if len(e.EventDetail.InstanceIDs) == 0 {
-			input.State = aws.String(eventbridge.RuleStateDisabled)
-		}
-		_, _ = s.EventBridgeClient.PutRule(input)
-	}
-}
-
 func (s Service) getEC2RuleName() string {
 	return fmt.Sprintf("%s-ec2-rule", s.scope.Name())
 }
@@ -256,6 +171,5 @@ type eventPattern struct {
 }
 
 type eventDetail struct {
-	InstanceIDs []string                `json:"instance-id,omitempty"`
-	States      []infrav1.InstanceState `json:"state,omitempty"`
+	States []infrav1.InstanceState `json:"state,omitempty"`
 }
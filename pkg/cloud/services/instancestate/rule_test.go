@@ -17,147 +17,17 @@ limitations under the License.
 package instancestate
 
 import (
-	"encoding/json"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/eventbridge"
-	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
-	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_eventbridgeiface"
-	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
 )
 
-func TestReconcileRules(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-	ruleName := "test-cluster-ec2-rule"
-
-	testCases := []struct {
-		name                        string
-		eventBridgeExpect           func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
-		postCreateEventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
-		sqsExpect                   func(m *mock_sqsiface.MockSQSAPIMockRecorder)
-		expectErr                   bool
-	}{
-		{
-			name: "successfully creates missing rule and target",
-			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
-					Name: aws.String(ruleName),
-				})).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
-				e := &eventPattern{
-					Source:     []string{"aws.ec2"},
-					DetailType: []string{Ec2StateChangeNotification},
-					EventDetail: &eventDetail{
-						States: []infrav1.InstanceState{infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated},
-					},
-				}
-				data, _ := json.Marshal(e)
-				m.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
-					Name:         aws.String(ruleName),
-					State:        aws.String(eventbridge.RuleStateDisabled),
-					EventPattern: aws.String(string(data)),
-				}))
-			},
-			postCreateEventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
-					Name: aws.String(ruleName),
-				})).Return(&eventbridge.DescribeRuleOutput{Name: aws.String(ruleName), Arn: aws.String("rule-arn")}, nil)
-				m.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{
-					Rule: aws.String(ruleName),
-				}).Return(&eventbridge.ListTargetsByRuleOutput{
-					Targets: []*eventbridge.Target{{
-						Id:  aws.String("another-queue"),
-						Arn: aws.String("another-queue-arn"),
-					}},
-				}, nil)
-				m.PutTargets(gomock.Eq(&eventbridge.PutTargetsInput{
-					Rule: aws.String(ruleName),
-					Targets: []*eventbridge.Target{{
-						Arn: aws.String("test-cluster-queue-arn"),
-						Id:  aws.String("test-cluster-queue"),
-					}},
-				}))
-			},
-			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
-				m.GetQueueUrl(gomock.Eq(&sqs.GetQueueUrlInput{
-					QueueName: aws.String("test-cluster-queue"),
-				})).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("test-cluster-queue-url")}, nil)
-				attrs := make(map[string]string)
-				attrs[sqs.QueueAttributeNameQueueArn] = "test-cluster-queue-arn"
-				m.GetQueueAttributes(gomock.Eq(&sqs.GetQueueAttributesInput{
-					AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn, sqs.QueueAttributeNamePolicy}),
-					QueueUrl:       aws.String("test-cluster-queue-url"),
-				})).Return(&sqs.GetQueueAttributesOutput{Attributes: aws.StringMap(attrs)}, nil)
-				m.SetQueueAttributes(gomock.AssignableToTypeOf(&sqs.SetQueueAttributesInput{})).Return(nil, nil)
-			},
-			expectErr: false,
-		},
-		{
-			name: "skips creating target and queue policy if they already exist",
-			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
-					Name: aws.String(ruleName),
-				})).Return(&eventbridge.DescribeRuleOutput{Name: aws.String(ruleName), Arn: aws.String("rule-arn")}, nil)
-				m.ListTargetsByRule(gomock.AssignableToTypeOf(&eventbridge.ListTargetsByRuleInput{})).Return(&eventbridge.ListTargetsByRuleOutput{
-					Targets: []*eventbridge.Target{{
-						Id:  aws.String("test-cluster-queue"),
-						Arn: aws.String("test-cluster-queue-arn"),
-					}},
-				}, nil)
-			},
-			postCreateEventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
-			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
-				m.GetQueueUrl(gomock.AssignableToTypeOf(&sqs.GetQueueUrlInput{})).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("test-cluster-queue-url")}, nil)
-				attrs := make(map[string]string)
-				attrs[sqs.QueueAttributeNameQueueArn] = "test-cluster-queue-arn"
-				attrs[sqs.QueueAttributeNamePolicy] = "some policy"
-				m.GetQueueAttributes(gomock.AssignableToTypeOf(&sqs.GetQueueAttributesInput{})).Return(&sqs.GetQueueAttributesOutput{Attributes: aws.StringMap(attrs)}, nil)
-			},
-		},
-		{
-			name: "returns error if DescribeRule runs into unexpected error",
-			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
-					Name: aws.String(ruleName),
-				})).Return(nil, errors.New("some error"))
-			},
-			postCreateEventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
-			sqsExpect:                   func(m *mock_sqsiface.MockSQSAPIMockRecorder) {},
-			expectErr:                   true,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			g := NewWithT(t)
-			eventbridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
-			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
-			clusterScope, err := setupCluster("test-cluster")
-			g.Expect(err).To(Not(HaveOccurred()))
-			tc.sqsExpect(sqsMock.EXPECT())
-			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
-			tc.postCreateEventBridgeExpect(eventbridgeMock.EXPECT())
-
-			s := NewService(clusterScope)
-			s.EventBridgeClient = eventbridgeMock
-			s.SQSClient = sqsMock
-
-			err = s.reconcileRules()
-			if tc.expectErr {
-				g.Expect(err).NotTo(BeNil())
-			} else {
-				g.Expect(err).To(BeNil())
-			}
-		})
-	}
-}
-
 func TestDeleteRules(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -216,7 +86,7 @@ func TestDeleteRules(t *testing.T) {
 			g.Expect(err).To(Not(HaveOccurred()))
 			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
 
-			s := NewService(clusterScope)
+			s := NewService(clusterScope, nil)
 			s.EventBridgeClient = eventbridgeMock
 
 			err = s.deleteRules()
@@ -229,147 +99,87 @@ func TestDeleteRules(t *testing.T) {
 	}
 }
 
-func TestAddInstanceToRule(t *testing.T) {
+func TestReconcileForwardingRule(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	pattern := eventPattern{
-		DetailType: []string{Ec2StateChangeNotification},
-		Source:     []string{"aws.ec2"},
-		EventDetail: &eventDetail{
-			InstanceIDs: []string{"instance-a"},
-		},
-	}
-	patternData, _ := json.Marshal(pattern)
+
+	eventBusARN := "arn:aws:events:us-east-1:111111111111:event-bus/default"
 
 	testCases := []struct {
 		name              string
 		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
-		newInstanceID     string
 		expectErr         bool
 	}{
 		{
-			name: "adds instance to event pattern when it doesn't exist",
+			name: "creates missing rule and targets the management event bus",
 			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(&eventbridge.DescribeRuleInput{
-					Name: aws.String("test-cluster-ec2-rule"),
-				}).Return(&eventbridge.DescribeRuleOutput{
-					EventPattern: aws.String(string(patternData)),
-				}, nil)
-				expectedPattern := pattern
-				expectedPattern.EventDetail.InstanceIDs = append(expectedPattern.EventDetail.InstanceIDs, "instance-b")
-				expectedData, _ := json.Marshal(expectedPattern)
-				m.PutRule(&eventbridge.PutRuleInput{
-					Name:         aws.String("test-cluster-ec2-rule"),
-					EventPattern: aws.String(string(expectedData)),
+				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
+					Name: aws.String(SharedQueueName),
+				})).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+				m.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+					Name:         aws.String(SharedQueueName),
+					EventPattern: aws.String(instanceStatePattern()),
 					State:        aws.String(eventbridge.RuleStateEnabled),
-				}).Return(nil, nil)
-			},
-			newInstanceID: "instance-b",
-			expectErr:     false,
-		},
-		{
-			name: "does nothing if instance is already tracked in event pattern",
-			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(&eventbridge.DescribeRuleInput{
-					Name: aws.String("test-cluster-ec2-rule"),
-				}).Return(&eventbridge.DescribeRuleOutput{
-					EventPattern: aws.String(string(patternData)),
-				}, nil)
+				})).Return(&eventbridge.PutRuleOutput{}, nil)
+				m.ListTargetsByRule(gomock.Eq(&eventbridge.ListTargetsByRuleInput{
+					Rule: aws.String(SharedQueueName),
+				})).Return(&eventbridge.ListTargetsByRuleOutput{}, nil)
+				m.PutTargets(gomock.Eq(&eventbridge.PutTargetsInput{
+					Rule: aws.String(SharedQueueName),
+					Targets: []*eventbridge.Target{{
+						Arn: aws.String(eventBusARN),
+						Id:  aws.String(SharedQueueName),
+					}},
+				})).Return(&eventbridge.PutTargetsOutput{}, nil)
+				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
+					Name: aws.String(LifecycleActionRuleName),
+				})).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+				m.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+					Name:         aws.String(LifecycleActionRuleName),
+					EventPattern: aws.String(lifecycleActionPattern()),
+					State:        aws.String(eventbridge.RuleStateEnabled),
+				})).Return(&eventbridge.PutRuleOutput{}, nil)
+				m.ListTargetsByRule(gomock.Eq(&eventbridge.ListTargetsByRuleInput{
+					Rule: aws.String(LifecycleActionRuleName),
+				})).Return(&eventbridge.ListTargetsByRuleOutput{}, nil)
+				m.PutTargets(gomock.Eq(&eventbridge.PutTargetsInput{
+					Rule: aws.String(LifecycleActionRuleName),
+					Targets: []*eventbridge.Target{{
+						Arn: aws.String(eventBusARN),
+						Id:  aws.String(SharedQueueName),
+					}},
+				})).Return(&eventbridge.PutTargetsOutput{}, nil)
 			},
-			newInstanceID: "instance-a",
-			expectErr:     false,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			g := NewWithT(t)
-			eventbridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
-			clusterScope, err := setupCluster("test-cluster")
-			g.Expect(err).To(Not(HaveOccurred()))
-			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
-
-			s := NewService(clusterScope)
-			s.EventBridgeClient = eventbridgeMock
-
-			err = s.AddInstanceToEventPattern(tc.newInstanceID)
-			if tc.expectErr {
-				g.Expect(err).NotTo(BeNil())
-			} else {
-				g.Expect(err).To(BeNil())
-			}
-		})
-	}
-}
-
-func TestRemoveInstanceStateFromEventPattern(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-	pattern := eventPattern{
-		DetailType: []string{Ec2StateChangeNotification},
-		Source:     []string{"aws.ec2"},
-		EventDetail: &eventDetail{
-			InstanceIDs: []string{"instance-a", "instance-b", "instance-c"},
+			expectErr: false,
 		},
-	}
-	patternData, _ := json.Marshal(pattern)
-
-	testCases := []struct {
-		name              string
-		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
-		instanceID        string
-	}{
 		{
-			name: "remove instance from instance IDs and disables rule when no instances are tracked",
+			name: "does nothing when the rules already target the management event bus",
 			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				singleInstanceEventPattern := pattern
-				singleInstanceEventPattern.EventDetail.InstanceIDs = []string{"instance-a"}
-				patternData, _ := json.Marshal(pattern)
-				m.DescribeRule(&eventbridge.DescribeRuleInput{
-					Name: aws.String("test-cluster-ec2-rule"),
-				}).Return(&eventbridge.DescribeRuleOutput{
-					EventPattern: aws.String(string(patternData)),
-				}, nil)
-				expectedPattern := pattern
-				expectedPattern.EventDetail.InstanceIDs = []string{}
-				expectedData, _ := json.Marshal(expectedPattern)
-				m.PutRule(&eventbridge.PutRuleInput{
-					Name:         aws.String("test-cluster-ec2-rule"),
-					EventPattern: aws.String(string(expectedData)),
-					State:        aws.String(eventbridge.RuleStateDisabled),
-				}).Return(nil, nil)
+				m.DescribeRule(gomock.Any()).Return(&eventbridge.DescribeRuleOutput{Name: aws.String(SharedQueueName)}, nil).Times(2)
+				m.ListTargetsByRule(gomock.Any()).Return(&eventbridge.ListTargetsByRuleOutput{
+					Targets: []*eventbridge.Target{{
+						Arn: aws.String(eventBusARN),
+						Id:  aws.String(SharedQueueName),
+					}},
+				}, nil).Times(2)
 			},
-			instanceID: "instance-a",
+			expectErr: false,
 		},
 		{
-			name: "remove instance from instance IDs and rule remains enabled when other instances are tracked",
+			name: "returns error when describing the rule fails unexpectedly",
 			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(&eventbridge.DescribeRuleInput{
-					Name: aws.String("test-cluster-ec2-rule"),
-				}).Return(&eventbridge.DescribeRuleOutput{
-					EventPattern: aws.String(string(patternData)),
-				}, nil)
-				expectedPattern := pattern
-				expectedPattern.EventDetail.InstanceIDs = []string{"instance-a", "instance-c"}
-				expectedData, _ := json.Marshal(expectedPattern)
-				m.PutRule(&eventbridge.PutRuleInput{
-					Name:         aws.String("test-cluster-ec2-rule"),
-					EventPattern: aws.String(string(expectedData)),
-					State:        aws.String(eventbridge.RuleStateEnabled),
-				}).Return(nil, nil)
+				m.DescribeRule(gomock.Any()).Return(nil, errors.New("some error"))
 			},
-			instanceID: "instance-b",
+			expectErr: true,
 		},
 		{
-			name: "does nothing when instanceID is not tracked",
+			name: "returns error when adding the target fails",
 			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
-				m.DescribeRule(&eventbridge.DescribeRuleInput{
-					Name: aws.String("test-cluster-ec2-rule"),
-				}).Return(&eventbridge.DescribeRuleOutput{
-					EventPattern: aws.String(string(patternData)),
-				}, nil)
+				m.DescribeRule(gomock.Any()).Return(&eventbridge.DescribeRuleOutput{Name: aws.String(SharedQueueName)}, nil)
+				m.ListTargetsByRule(gomock.Any()).Return(&eventbridge.ListTargetsByRuleOutput{}, nil)
+				m.PutTargets(gomock.Any()).Return(nil, errors.New("some error"))
 			},
-			instanceID: "instance-d",
+			expectErr: true,
 		},
 	}
 
@@ -381,10 +191,16 @@ func TestRemoveInstanceStateFromEventPattern(t *testing.T) {
 			g.Expect(err).To(Not(HaveOccurred()))
 			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
 
-			s := NewService(clusterScope)
+			queue := &SharedQueue{region: "us-east-1", accountID: "111111111111", partition: "aws"}
+			s := NewService(clusterScope, &SharedQueues{queues: map[string]*SharedQueue{"us-east-1": queue}})
 			s.EventBridgeClient = eventbridgeMock
 
-			s.RemoveInstanceFromEventPattern(tc.instanceID)
+			err = s.reconcileForwardingRule(queue)
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
 		})
 	}
 }
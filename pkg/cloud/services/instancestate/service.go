@@ -19,21 +19,26 @@ package instancestate
 import (
 	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 )
 
 // Service defines the specs for a service.
 type Service struct {
 	scope             scope.EC2Scope
+	queues            *SharedQueues
 	EventBridgeClient eventbridgeiface.EventBridgeAPI
 	SQSClient         sqsiface.SQSAPI
+	STSClient         stsiface.STSAPI
 }
 
-// NewService returns a new service given the ec2 api client.
-func NewService(clusterScope scope.EC2Scope) *Service {
+// NewService returns a new service given the cluster scope and the shared queues of the management cluster.
+func NewService(clusterScope scope.EC2Scope, queues *SharedQueues) *Service {
 	return &Service{
 		scope:             clusterScope,
+		queues:            queues,
 		EventBridgeClient: scope.NewEventBridgeClient(clusterScope, clusterScope, clusterScope.InfraCluster()),
 		SQSClient:         scope.NewSQSClient(clusterScope, clusterScope, clusterScope.InfraCluster()),
+		STSClient:         scope.NewSTSClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
 	}
 }
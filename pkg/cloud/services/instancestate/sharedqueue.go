@@ -0,0 +1,305 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+const (
+	// SharedQueueName is the name of the SQS queue, and of the EventBridge rules, EC2 instance state
+	// changes of all workload clusters are delivered through.
+	SharedQueueName = "cluster-api-provider-aws-instance-state"
+
+	sharedQueueControllerName = "awsinstancestate"
+)
+
+// SharedQueues are the SQS queues of the management cluster, one in each region workload clusters are in, since
+// EventBridge only delivers events to targets in the region they were raised in. The queue of a region is created
+// when the first cluster of the region is reconciled.
+type SharedQueues struct {
+	endpoints []scope.ServiceEndpoint
+
+	mu     sync.Mutex
+	queues map[string]*SharedQueue
+
+	// migrated are the clusters whose per-cluster rule and queue created by earlier releases were deleted.
+	migrated sync.Map
+}
+
+// NewSharedQueues returns the shared queues of the management cluster, starting with the queues of the given regions.
+func NewSharedQueues(endpoints []scope.ServiceEndpoint, regions ...string) (*SharedQueues, error) {
+	queues := &SharedQueues{endpoints: endpoints, queues: map[string]*SharedQueue{}}
+	for _, region := range regions {
+		if _, err := queues.ForRegion(region); err != nil {
+			return nil, err
+		}
+	}
+
+	return queues, nil
+}
+
+// ForRegion returns the shared queue of the given region.
+func (qs *SharedQueues) ForRegion(region string) (*SharedQueue, error) {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	if q, ok := qs.queues[region]; ok {
+		return q, nil
+	}
+
+	q, err := NewSharedQueue(region, qs.endpoints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create the instance state queue of region %s", region)
+	}
+	qs.queues[region] = q
+
+	return q, nil
+}
+
+// List returns the shared queues of the regions clusters were reconciled in, sorted by region.
+func (qs *SharedQueues) List() []*SharedQueue {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	queues := make([]*SharedQueue, 0, len(qs.queues))
+	for _, q := range qs.queues {
+		queues = append(queues, q)
+	}
+	sort.Slice(queues, func(i, j int) bool { return queues[i].region < queues[j].region })
+
+	return queues
+}
+
+// SharedQueue is the SQS queue of the management cluster that receives the EC2 instance state
+// changes of every workload cluster of its region. Events raised in the account and region of the queue are
+// delivered by a rule on the default event bus, events raised in other accounts of the same region
+// are forwarded to that event bus by a rule in the account of the workload cluster.
+type SharedQueue struct {
+	region            string
+	EventBridgeClient eventbridgeiface.EventBridgeAPI
+	SQSClient         sqsiface.SQSAPI
+	STSClient         stsiface.STSAPI
+
+	mu        sync.Mutex
+	url       string
+	accountID string
+	partition string
+
+	authorizedAccounts sync.Map
+}
+
+// NewSharedQueue returns the shared queue of the management cluster in the given region.
+func NewSharedQueue(region string, endpoints []scope.ServiceEndpoint) (*SharedQueue, error) {
+	globalScope, err := scope.NewGlobalScope(scope.GlobalScopeParams{
+		ControllerName: sharedQueueControllerName,
+		Region:         region,
+		Endpoints:      endpoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SharedQueue{
+		region:            region,
+		EventBridgeClient: scope.NewGlobalEventBridgeClient(globalScope, globalScope),
+		SQSClient:         scope.NewGlobalSQSClient(globalScope, globalScope),
+		STSClient:         scope.NewGlobalSTSClient(globalScope, globalScope),
+	}, nil
+}
+
+// Reconcile ensures the queue exists and is the target of the rules matching EC2 instance state
+// changes and ASG lifecycle actions on the default event bus. It only talks to AWS until it succeeds once.
+func (q *SharedQueue) Reconcile() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.url != "" {
+		return nil
+	}
+
+	identity, err := q.STSClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrap(err, "unable to get the management account")
+	}
+
+	queueURL, err := q.ensureQueue()
+	if err != nil {
+		return err
+	}
+
+	attrs, err := q.SQSClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn}),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get attributes of queue %s", SharedQueueName)
+	}
+	queueARN := aws.StringValue(attrs.Attributes[sqs.QueueAttributeNameQueueArn])
+
+	ruleARNs := []string{}
+	for _, rule := range sharedRules() {
+		out, err := q.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
+			Name:         aws.String(rule.name),
+			EventPattern: aws.String(rule.pattern),
+			State:        aws.String(eventbridge.RuleStateEnabled),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to put rule %s", rule.name)
+		}
+
+		if _, err := q.EventBridgeClient.PutTargets(&eventbridge.PutTargetsInput{
+			Rule:    aws.String(rule.name),
+			Targets: []*eventbridge.Target{{Id: aws.String(SharedQueueName), Arn: aws.String(queueARN)}},
+		}); err != nil {
+			return errors.Wrapf(err, "unable to add queue %s as target of rule %s", SharedQueueName, rule.name)
+		}
+		ruleARNs = append(ruleARNs, aws.StringValue(out.RuleArn))
+	}
+
+	policy, err := queuePolicy(queueARN, ruleARNs)
+	if err != nil {
+		return err
+	}
+	if _, err := q.SQSClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl:   aws.String(queueURL),
+		Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNamePolicy: policy}),
+	}); err != nil {
+		return errors.Wrapf(err, "unable to set policy of queue %s", SharedQueueName)
+	}
+
+	q.partition = awsarn.Partition(q.region)
+	q.accountID = aws.StringValue(identity.Account)
+	q.url = queueURL
+
+	return nil
+}
+
+func (q *SharedQueue) ensureQueue() (string, error) {
+	out, err := q.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(SharedQueueName)})
+	if err == nil {
+		return aws.StringValue(out.QueueUrl), nil
+	}
+	if !queueNotFoundError(err) {
+		return "", errors.Wrapf(err, "unable to get URL of queue %s", SharedQueueName)
+	}
+
+	created, err := q.SQSClient.CreateQueue(&sqs.CreateQueueInput{
+		QueueName: aws.String(SharedQueueName),
+		Attributes: aws.StringMap(map[string]string{
+			sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds: "20",
+		}),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to create queue %s", SharedQueueName)
+	}
+
+	return aws.StringValue(created.QueueUrl), nil
+}
+
+// URL returns the URL of the queue. It is empty until the queue has been reconciled.
+func (q *SharedQueue) URL() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.url
+}
+
+// Region returns the region of the queue.
+func (q *SharedQueue) Region() string {
+	return q.region
+}
+
+// IsLocal returns whether events raised in the given account reach the queue without being forwarded.
+func (q *SharedQueue) IsLocal(accountID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.accountID == accountID
+}
+
+// EventBusARN returns the ARN of the event bus workload accounts forward their events to.
+func (q *SharedQueue) EventBusARN() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return awsarn.EventBus(q.partition, q.region, q.accountID, "default")
+}
+
+// AuthorizeAccount allows the given account to put events on the event bus of the queue.
+func (q *SharedQueue) AuthorizeAccount(accountID string) error {
+	if q.IsLocal(accountID) {
+		return nil
+	}
+	if _, ok := q.authorizedAccounts.Load(accountID); ok {
+		return nil
+	}
+
+	_, err := q.EventBridgeClient.PutPermission(&eventbridge.PutPermissionInput{
+		Action:      aws.String("events:PutEvents"),
+		Principal:   aws.String(accountID),
+		StatementId: aws.String(fmt.Sprintf("%s-%s", SharedQueueName, accountID)),
+	})
+	if code, _ := awserrors.Code(err); err != nil && code != eventbridge.ErrCodeResourceAlreadyExistsException {
+		return errors.Wrapf(err, "unable to allow account %s to put events", accountID)
+	}
+
+	q.authorizedAccounts.Store(accountID, struct{}{})
+	return nil
+}
+
+func queuePolicy(queueARN string, ruleARNs []string) (string, error) {
+	policy := infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		ID:      queueARN,
+		Statement: infrav1.Statements{
+			infrav1.StatementEntry{
+				Sid:       SharedQueueName,
+				Effect:    infrav1.EffectAllow,
+				Principal: infrav1.Principals{infrav1.PrincipalService: infrav1.PrincipalID{"events.amazonaws.com"}},
+				Action:    infrav1.Actions{"sqs:SendMessage"},
+				Resource:  infrav1.Resources{queueARN},
+				Condition: infrav1.Conditions{
+					"ArnEquals": map[string][]string{"aws:SourceArn": ruleARNs},
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to JSON marshal policy")
+	}
+
+	return string(data), nil
+}
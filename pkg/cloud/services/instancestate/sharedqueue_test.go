@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/sts/mock_stsiface"
+)
+
+func TestSharedQueueReconcile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/111111111111/" + SharedQueueName
+	queueARN := "arn:aws:sqs:us-east-1:111111111111:" + SharedQueueName
+	ruleARN := "arn:aws:events:us-east-1:111111111111:rule/" + SharedQueueName
+	lifecycleRuleARN := "arn:aws:events:us-east-1:111111111111:rule/" + LifecycleActionRuleName
+
+	expectRuleAndPolicy := func(eb *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder, m *mock_sqsiface.MockSQSAPIMockRecorder) {
+		m.GetQueueAttributes(gomock.Eq(&sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueURL),
+			AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn}),
+		})).Return(&sqs.GetQueueAttributesOutput{
+			Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNameQueueArn: queueARN}),
+		}, nil)
+		eb.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+			Name:         aws.String(SharedQueueName),
+			EventPattern: aws.String(instanceStatePattern()),
+			State:        aws.String(eventbridge.RuleStateEnabled),
+		})).Return(&eventbridge.PutRuleOutput{RuleArn: aws.String(ruleARN)}, nil)
+		eb.PutTargets(gomock.Eq(&eventbridge.PutTargetsInput{
+			Rule:    aws.String(SharedQueueName),
+			Targets: []*eventbridge.Target{{Id: aws.String(SharedQueueName), Arn: aws.String(queueARN)}},
+		})).Return(&eventbridge.PutTargetsOutput{}, nil)
+		eb.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+			Name:         aws.String(LifecycleActionRuleName),
+			EventPattern: aws.String(lifecycleActionPattern()),
+			State:        aws.String(eventbridge.RuleStateEnabled),
+		})).Return(&eventbridge.PutRuleOutput{RuleArn: aws.String(lifecycleRuleARN)}, nil)
+		eb.PutTargets(gomock.Eq(&eventbridge.PutTargetsInput{
+			Rule:    aws.String(LifecycleActionRuleName),
+			Targets: []*eventbridge.Target{{Id: aws.String(SharedQueueName), Arn: aws.String(queueARN)}},
+		})).Return(&eventbridge.PutTargetsOutput{}, nil)
+		policy, _ := queuePolicy(queueARN, []string{ruleARN, lifecycleRuleARN})
+		m.SetQueueAttributes(gomock.Eq(&sqs.SetQueueAttributesInput{
+			QueueUrl:   aws.String(queueURL),
+			Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNamePolicy: policy}),
+		})).Return(&sqs.SetQueueAttributesOutput{}, nil)
+	}
+
+	testCases := []struct {
+		name              string
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		sqsExpect         func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		stsExpect         func(m *mock_stsiface.MockSTSAPIMockRecorder)
+		expectErr         bool
+	}{
+		{
+			name:              "creates the queue when it doesn't exist",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(gomock.Eq(&sqs.GetQueueUrlInput{QueueName: aws.String(SharedQueueName)})).
+					Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+				m.CreateQueue(gomock.Eq(&sqs.CreateQueueInput{
+					QueueName: aws.String(SharedQueueName),
+					Attributes: aws.StringMap(map[string]string{
+						sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds: "20",
+					}),
+				})).Return(&sqs.CreateQueueOutput{QueueUrl: aws.String(queueURL)}, nil)
+			},
+			stsExpect: func(m *mock_stsiface.MockSTSAPIMockRecorder) {
+				m.GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: aws.String("111111111111")}, nil)
+			},
+			expectErr: false,
+		},
+		{
+			name:              "uses the existing queue",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(gomock.Eq(&sqs.GetQueueUrlInput{QueueName: aws.String(SharedQueueName)})).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String(queueURL)}, nil)
+			},
+			stsExpect: func(m *mock_stsiface.MockSTSAPIMockRecorder) {
+				m.GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: aws.String("111111111111")}, nil)
+			},
+			expectErr: false,
+		},
+		{
+			name:              "returns error when the management account can't be determined",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+			sqsExpect:         func(m *mock_sqsiface.MockSQSAPIMockRecorder) {},
+			stsExpect: func(m *mock_stsiface.MockSTSAPIMockRecorder) {
+				m.GetCallerIdentity(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			eventbridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			stsMock := mock_stsiface.NewMockSTSAPI(mockCtrl)
+			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
+			tc.sqsExpect(sqsMock.EXPECT())
+			tc.stsExpect(stsMock.EXPECT())
+			if !tc.expectErr {
+				expectRuleAndPolicy(eventbridgeMock.EXPECT(), sqsMock.EXPECT())
+			}
+
+			q := &SharedQueue{region: "us-east-1", EventBridgeClient: eventbridgeMock, SQSClient: sqsMock, STSClient: stsMock}
+
+			err := q.Reconcile()
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+				g.Expect(q.URL()).To(BeEmpty())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(q.URL()).To(Equal(queueURL))
+			g.Expect(q.EventBusARN()).To(Equal("arn:aws:events:us-east-1:111111111111:event-bus/default"))
+
+			// Once reconciled, the queue doesn't talk to AWS anymore.
+			g.Expect(q.Reconcile()).To(Succeed())
+		})
+	}
+}
+
+func TestSharedQueueAuthorizeAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name              string
+		accountID         string
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		expectErr         bool
+	}{
+		{
+			name:              "doesn't need to authorize the management account",
+			accountID:         "111111111111",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {},
+		},
+		{
+			name:      "allows a workload account to put events once",
+			accountID: "222222222222",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.PutPermission(gomock.Eq(&eventbridge.PutPermissionInput{
+					Action:      aws.String("events:PutEvents"),
+					Principal:   aws.String("222222222222"),
+					StatementId: aws.String(SharedQueueName + "-222222222222"),
+				})).Return(&eventbridge.PutPermissionOutput{}, nil).Times(1)
+			},
+		},
+		{
+			name:      "tolerates an existing permission",
+			accountID: "222222222222",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.PutPermission(gomock.Any()).Return(nil, awserr.New(eventbridge.ErrCodeResourceAlreadyExistsException, "", nil))
+			},
+		},
+		{
+			name:      "returns error when the permission can't be added",
+			accountID: "222222222222",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.PutPermission(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			eventbridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
+
+			q := &SharedQueue{region: "us-east-1", accountID: "111111111111", EventBridgeClient: eventbridgeMock}
+
+			err := q.AuthorizeAccount(tc.accountID)
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(q.AuthorizeAccount(tc.accountID)).To(Succeed())
+		})
+	}
+}
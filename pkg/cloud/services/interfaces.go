@@ -17,6 +17,8 @@ limitations under the License.
 package services
 
 import (
+	"github.com/aws/aws-sdk-go/service/ec2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
@@ -27,6 +29,8 @@ const (
 	TemporaryResourceID = "temporary-resource-id"
 	// AnyIPv4CidrBlock is the CIDR block to match all IPv4 addresses.
 	AnyIPv4CidrBlock = "0.0.0.0/0"
+	// AnyIPv6CidrBlock is the CIDR block to match all IPv6 addresses.
+	AnyIPv6CidrBlock = "::/0"
 )
 
 // ASGInterface encapsulates the methods exposed to the machinepool
@@ -40,6 +44,10 @@ type ASGInterface interface {
 	CanStartASGInstanceRefresh(scope *scope.MachinePoolScope) (bool, error)
 	UpdateResourceTags(resourceID *string, create, remove map[string]string) error
 	DeleteASGAndWait(id string) error
+	ReconcileLifecycleHook(scope *scope.MachinePoolScope, drainTimeout *metav1.Duration) error
+	CompleteLifecycleAction(asgName, hookName, instanceID, token string) error
+	SetInstanceProtection(name string, instanceIDs []string, protected bool) error
+	TerminateInstanceInASG(instanceID string, decrementCapacity bool) error
 }
 
 // EC2MachineInterface encapsulates the methods exposed to the machine
@@ -48,10 +56,16 @@ type EC2MachineInterface interface {
 	InstanceIfExists(id *string) (*infrav1.Instance, error)
 	TerminateInstance(id string) error
 	CreateInstance(scope *scope.MachineScope, userData []byte) (*infrav1.Instance, error)
+	AdoptInstance(scope *scope.MachineScope, id string) (*infrav1.Instance, error)
 	GetRunningInstanceByTags(scope *scope.MachineScope) (*infrav1.Instance, error)
 
 	GetCoreSecurityGroups(machine *scope.MachineScope) ([]string, error)
 	GetInstanceSecurityGroups(instanceID string) (map[string][]string, error)
+	GetInstanceScheduledEvents(instanceID string) ([]infrav1.InstanceScheduledEvent, error)
+	GetInstanceSystemStatus(instanceID string) (*ec2.InstanceStatusSummary, error)
+	GetInstanceTypeInfo(instanceType string) (*ec2.InstanceTypeInfo, error)
+	GetInstanceTypeZones(instanceType string) (map[string]bool, error)
+	GetSpotPrice(instanceType, availabilityZone string) (float64, error)
 	GetFilteredSecurityGroupID(securityGroup infrav1.AWSResourceReference) (string, error)
 	UpdateInstanceSecurityGroups(id string, securityGroups []string) error
 	UpdateResourceTags(resourceID *string, create, remove map[string]string) error
@@ -59,14 +73,22 @@ type EC2MachineInterface interface {
 	TerminateInstanceAndWait(instanceID string) error
 	DetachSecurityGroupsFromNetworkInterface(groups []string, interfaceID string) error
 
+	ReconcileAPIServerConnectivity(scope *scope.MachineScope, instance *infrav1.Instance) (bool, error)
+	AnalyzeAPIServerConnectivity(scope *scope.MachineScope, instance *infrav1.Instance) (bool, error)
+	DeleteAPIServerConnectivityCheck(scope *scope.MachineScope) error
+
 	DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*string, error)
 	GetLaunchTemplate(id string) (lt *expinfrav1.AWSLaunchTemplate, userDataHash string, err error)
 	GetLaunchTemplateID(id string) (string, error)
 	CreateLaunchTemplate(scope *scope.MachinePoolScope, imageID *string, userData []byte) (string, error)
 	CreateLaunchTemplateVersion(scope *scope.MachinePoolScope, imageID *string, userData []byte) error
-	PruneLaunchTemplateVersions(id string) error
+	PruneLaunchTemplateVersions(id string, historyLimit int32) error
 	DeleteLaunchTemplate(id string) error
 	LaunchTemplateNeedsUpdate(scope *scope.MachinePoolScope, incoming *expinfrav1.AWSLaunchTemplate, existing *expinfrav1.AWSLaunchTemplate) (bool, error)
+
+	GetFleetInstances(scope *scope.MachinePoolScope) ([]infrav1.Instance, error)
+	GetInstancesByIDs(ids []string) ([]infrav1.Instance, error)
+	CreateFleetInstances(scope *scope.MachinePoolScope, existing []infrav1.Instance, count int32) ([]string, error)
 }
 
 // SecretInterface encapsulated the methods exposed to the
@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karpenter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+)
+
+// queueMessageRetentionSeconds is how long the interruption queue keeps messages. Interruption events are
+// only useful while the instance is still running, so they don't need to be kept longer.
+const queueMessageRetentionSeconds = "300"
+
+// interruptionRule is an EventBridge rule forwarding a kind of EC2 interruption event to the interruption queue.
+type interruptionRule struct {
+	suffix  string
+	pattern eventPattern
+}
+
+var interruptionRules = []interruptionRule{
+	{
+		suffix:  "scheduled-change",
+		pattern: eventPattern{Source: []string{"aws.health"}, DetailType: []string{"AWS Health Event"}},
+	},
+	{
+		suffix:  "spot-interruption",
+		pattern: eventPattern{Source: []string{"aws.ec2"}, DetailType: []string{"EC2 Spot Instance Interruption Warning"}},
+	},
+	{
+		suffix:  "rebalance",
+		pattern: eventPattern{Source: []string{"aws.ec2"}, DetailType: []string{"EC2 Instance Rebalance Recommendation"}},
+	},
+	{
+		suffix:  "instance-state-change",
+		pattern: eventPattern{Source: []string{"aws.ec2"}, DetailType: []string{"EC2 Instance State-change Notification"}},
+	},
+}
+
+type eventPattern struct {
+	Source     []string `json:"source"`
+	DetailType []string `json:"detail-type"`
+}
+
+// reconcileInterruptionQueue ensures the interruption queue exists and allows EventBridge to send
+// messages to it, and returns its ARN.
+func (s *Service) reconcileInterruptionQueue() (string, error) {
+	name := s.queueName()
+
+	var queueURL *string
+	out, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	switch code, _ := awserrors.Code(err); {
+	case code == sqs.ErrCodeQueueDoesNotExist:
+		created, err := s.SQSClient.CreateQueue(&sqs.CreateQueueInput{
+			QueueName: aws.String(name),
+			Attributes: aws.StringMap(map[string]string{
+				sqs.QueueAttributeNameMessageRetentionPeriod: queueMessageRetentionSeconds,
+			}),
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "creating queue %q", name)
+		}
+		s.scope.Info("Created Karpenter interruption queue", "queue", name)
+		queueURL = created.QueueUrl
+	case err != nil:
+		return "", errors.Wrapf(err, "getting URL of queue %q", name)
+	default:
+		queueURL = out.QueueUrl
+	}
+
+	attrs, err := s.SQSClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       queueURL,
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn, sqs.QueueAttributeNamePolicy}),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "getting attributes of queue %q", name)
+	}
+	queueARN := aws.StringValue(attrs.Attributes[sqs.QueueAttributeNameQueueArn])
+
+	policy, err := json.Marshal(infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		ID:      queueARN,
+		Statement: infrav1.Statements{
+			{
+				Sid:       "KarpenterInterruptionEvents",
+				Effect:    infrav1.EffectAllow,
+				Principal: infrav1.Principals{infrav1.PrincipalService: infrav1.PrincipalID{"events.amazonaws.com", "sqs.amazonaws.com"}},
+				Action:    infrav1.Actions{"sqs:SendMessage"},
+				Resource:  infrav1.Resources{queueARN},
+			},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "unable to JSON marshal policy")
+	}
+
+	if aws.StringValue(attrs.Attributes[sqs.QueueAttributeNamePolicy]) != string(policy) {
+		if _, err := s.SQSClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+			QueueUrl:   queueURL,
+			Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNamePolicy: string(policy)}),
+		}); err != nil {
+			return "", errors.Wrapf(err, "setting policy of queue %q", name)
+		}
+	}
+
+	return queueARN, nil
+}
+
+func (s *Service) deleteInterruptionQueue() error {
+	out, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(s.queueName())})
+	if code, _ := awserrors.Code(err); code == sqs.ErrCodeQueueDoesNotExist {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "getting URL of queue %q", s.queueName())
+	}
+
+	_, err = s.SQSClient.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: out.QueueUrl})
+	if code, _ := awserrors.Code(err); code == sqs.ErrCodeQueueDoesNotExist {
+		return nil
+	}
+	return err
+}
+
+// reconcileInterruptionRules ensures the EventBridge rules of the interruption events exist and target the queue.
+func (s *Service) reconcileInterruptionRules(queueARN string) error {
+	targetID := s.queueName()
+
+	for _, rule := range interruptionRules {
+		name := s.ruleName(rule)
+
+		_, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(name)})
+		if code, _ := awserrors.Code(err); code == eventbridge.ErrCodeResourceNotFoundException {
+			pattern, err := json.Marshal(rule.pattern)
+			if err != nil {
+				return err
+			}
+			if _, err := s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
+				Name:         aws.String(name),
+				EventPattern: aws.String(string(pattern)),
+				State:        aws.String(eventbridge.RuleStateEnabled),
+			}); err != nil {
+				return errors.Wrapf(err, "creating rule %q", name)
+			}
+			s.scope.Info("Created Karpenter interruption rule", "rule", name)
+		} else if err != nil {
+			return errors.Wrapf(err, "describing rule %q", name)
+		}
+
+		targets, err := s.EventBridgeClient.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: aws.String(name)})
+		if err != nil {
+			return errors.Wrapf(err, "listing targets of rule %q", name)
+		}
+
+		targetFound := false
+		for _, target := range targets.Targets {
+			if aws.StringValue(target.Id) == targetID && aws.StringValue(target.Arn) == queueARN {
+				targetFound = true
+			}
+		}
+		if targetFound {
+			continue
+		}
+
+		if _, err := s.EventBridgeClient.PutTargets(&eventbridge.PutTargetsInput{
+			Rule:    aws.String(name),
+			Targets: []*eventbridge.Target{{Id: aws.String(targetID), Arn: aws.String(queueARN)}},
+		}); err != nil {
+			return errors.Wrapf(err, "adding queue target to rule %q", name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteInterruptionRules() error {
+	for _, rule := range interruptionRules {
+		name := s.ruleName(rule)
+
+		_, err := s.EventBridgeClient.RemoveTargets(&eventbridge.RemoveTargetsInput{
+			Rule: aws.String(name),
+			Ids:  aws.StringSlice([]string{s.queueName()}),
+		})
+		if code, _ := awserrors.Code(err); err != nil && code != eventbridge.ErrCodeResourceNotFoundException {
+			return errors.Wrapf(err, "removing queue target of rule %q", name)
+		}
+
+		_, err = s.EventBridgeClient.DeleteRule(&eventbridge.DeleteRuleInput{Name: aws.String(name)})
+		if code, _ := awserrors.Code(err); err != nil && code != eventbridge.ErrCodeResourceNotFoundException {
+			return errors.Wrapf(err, "deleting rule %q", name)
+		}
+	}
+
+	return nil
+}
+
+// queueName returns the name of the interruption queue, which only allows alphanumeric characters, hyphens and underscores.
+func (s *Service) queueName() string {
+	return fmt.Sprintf("%s-karpenter", strings.ReplaceAll(s.scope.Name(), ".", "-"))
+}
+
+func (s *Service) ruleName(rule interruptionRule) string {
+	return fmt.Sprintf("%s-karpenter-%s", s.scope.Name(), rule.suffix)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karpenter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
+)
+
+const (
+	// maxIAMRoleNameLength is the maximum length of an IAM role name.
+	maxIAMRoleNameLength = 64
+
+	// nodeRoleTagValue is the role tag of the IAM role of the nodes launched by Karpenter.
+	nodeRoleTagValue = "karpenter-node"
+)
+
+// defaultNodePolicies are the AWS managed policies attached to the role of the nodes launched by Karpenter.
+var defaultNodePolicies = []string{
+	"AmazonEKSWorkerNodePolicy",
+	"AmazonEC2ContainerRegistryReadOnly",
+	"AmazonSSMManagedInstanceCore",
+}
+
+// ReconcileKarpenter ensures the node IAM role and instance profile and the interruption queue Karpenter
+// needs exist if the cluster opts in, and deletes them once the cluster opts out.
+func (s *Service) ReconcileKarpenter() error {
+	spec := s.scope.Karpenter()
+	if spec == nil {
+		if s.scope.KarpenterStatus() == nil {
+			return nil
+		}
+		if err := s.deleteResources(); err != nil {
+			return err
+		}
+		s.scope.SetKarpenterStatus(nil)
+		return nil
+	}
+
+	nodeRole := s.nodeRoleName()
+	policies := append(s.defaultNodePolicyARNs(), spec.AdditionalNodePolicyARNs...)
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(nodeRole),
+		Role:        aws.String(nodeRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	})
+	if err := s.IAMService.ReconcileRoleAndInstanceProfile(nodeRole, policies, tags); err != nil {
+		return errors.Wrap(err, "reconciling Karpenter node role")
+	}
+
+	queueARN, err := s.reconcileInterruptionQueue()
+	if err != nil {
+		return errors.Wrap(err, "reconciling Karpenter interruption queue")
+	}
+
+	if err := s.reconcileInterruptionRules(queueARN); err != nil {
+		return errors.Wrap(err, "reconciling Karpenter interruption rules")
+	}
+
+	s.scope.SetKarpenterStatus(&infrav1.KarpenterStatus{
+		NodeInstanceProfile: nodeRole,
+		InterruptionQueue:   s.queueName(),
+	})
+	return nil
+}
+
+// DeleteKarpenter deletes the AWS resources provisioned for Karpenter.
+func (s *Service) DeleteKarpenter() error {
+	if s.scope.Karpenter() == nil && s.scope.KarpenterStatus() == nil {
+		return nil
+	}
+
+	return s.deleteResources()
+}
+
+func (s *Service) deleteResources() error {
+	if err := s.deleteInterruptionRules(); err != nil {
+		return errors.Wrap(err, "deleting Karpenter interruption rules")
+	}
+
+	if err := s.deleteInterruptionQueue(); err != nil {
+		return errors.Wrap(err, "deleting Karpenter interruption queue")
+	}
+
+	if err := s.IAMService.DeleteRoleAndInstanceProfile(s.nodeRoleName()); err != nil {
+		return errors.Wrap(err, "deleting Karpenter node role")
+	}
+
+	return nil
+}
+
+// nodeRoleName returns the name of the IAM role and instance profile of the nodes launched by Karpenter.
+// Names exceeding the IAM role name limit are truncated and suffixed with a hash to keep them unique.
+func (s *Service) nodeRoleName() string {
+	name := fmt.Sprintf("%s-karpenter-node", s.scope.Name())
+	if len(name) <= maxIAMRoleNameLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:])[:8]
+	return fmt.Sprintf("%s-%s", name[:maxIAMRoleNameLength-len(suffix)-1], suffix)
+}
+
+func (s *Service) defaultNodePolicyARNs() []string {
+	partition := awsarn.Partition(s.scope.Region())
+	arns := make([]string, 0, len(defaultNodePolicies))
+	for _, policy := range defaultNodePolicies {
+		arns = append(arns, awsarn.AWSManagedPolicy(partition, policy))
+	}
+	return arns
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karpenter
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam/mock_iamiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
+)
+
+const (
+	queueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/test-cluster-karpenter"
+	queueARN = "arn:aws:sqs:us-east-1:123456789012:test-cluster-karpenter"
+)
+
+func TestReconcileKarpenter(t *testing.T) {
+	testCases := []struct {
+		name         string
+		karpenter    *infrav1.Karpenter
+		status       *infrav1.KarpenterStatus
+		expectIAM    func(m *mock_iamiface.MockIAMAPIMockRecorder)
+		expectSQS    func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		expectEvents func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		wantStatus   *infrav1.KarpenterStatus
+	}{
+		{
+			name: "does nothing if the cluster doesn't opt in",
+		},
+		{
+			name: "provisions the node role, the interruption queue and the rules",
+			karpenter: &infrav1.Karpenter{
+				AdditionalNodePolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"},
+			},
+			expectIAM: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String("test-cluster-karpenter-node")}).
+					Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+				m.CreateRole(gomock.Any()).Return(&iam.CreateRoleOutput{}, nil)
+				m.ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+				for _, arn := range []string{
+					"arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+					"arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+					"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore",
+					"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy",
+				} {
+					m.AttachRolePolicy(&iam.AttachRolePolicyInput{
+						RoleName:  aws.String("test-cluster-karpenter-node"),
+						PolicyArn: aws.String(arn),
+					}).Return(&iam.AttachRolePolicyOutput{}, nil)
+				}
+				m.GetInstanceProfile(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+				m.CreateInstanceProfile(gomock.Any()).Return(&iam.CreateInstanceProfileOutput{}, nil)
+				m.AddRoleToInstanceProfile(gomock.Any()).Return(&iam.AddRoleToInstanceProfileOutput{}, nil)
+			},
+			expectSQS: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("test-cluster-karpenter")}).
+					Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+				m.CreateQueue(&sqs.CreateQueueInput{
+					QueueName:  aws.String("test-cluster-karpenter"),
+					Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNameMessageRetentionPeriod: "300"}),
+				}).Return(&sqs.CreateQueueOutput{QueueUrl: aws.String(queueURL)}, nil)
+				m.GetQueueAttributes(gomock.Any()).Return(&sqs.GetQueueAttributesOutput{
+					Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNameQueueArn: queueARN}),
+				}, nil)
+				m.SetQueueAttributes(gomock.Any()).Return(&sqs.SetQueueAttributesOutput{}, nil)
+			},
+			expectEvents: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				for _, rule := range []string{"scheduled-change", "spot-interruption", "rebalance", "instance-state-change"} {
+					name := aws.String("test-cluster-karpenter-" + rule)
+					m.DescribeRule(&eventbridge.DescribeRuleInput{Name: name}).
+						Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+					m.PutRule(gomock.Any()).Return(&eventbridge.PutRuleOutput{}, nil)
+					m.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: name}).Return(&eventbridge.ListTargetsByRuleOutput{}, nil)
+					m.PutTargets(&eventbridge.PutTargetsInput{
+						Rule:    name,
+						Targets: []*eventbridge.Target{{Id: aws.String("test-cluster-karpenter"), Arn: aws.String(queueARN)}},
+					}).Return(&eventbridge.PutTargetsOutput{}, nil)
+				}
+			},
+			wantStatus: &infrav1.KarpenterStatus{
+				NodeInstanceProfile: "test-cluster-karpenter-node",
+				InterruptionQueue:   "test-cluster-karpenter",
+			},
+		},
+		{
+			name: "deletes the resources once the cluster opts out",
+			status: &infrav1.KarpenterStatus{
+				NodeInstanceProfile: "test-cluster-karpenter-node",
+				InterruptionQueue:   "test-cluster-karpenter",
+			},
+			expectIAM: func(m *mock_iamiface.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String("test-cluster-karpenter-node")}).Return(&iam.GetRoleOutput{
+					Role: &iam.Role{
+						RoleName: aws.String("test-cluster-karpenter-node"),
+						Tags:     []*iam.Tag{{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String(string(infrav1.ResourceLifecycleOwned))}},
+					},
+				}, nil)
+				m.RemoveRoleFromInstanceProfile(gomock.Any()).Return(&iam.RemoveRoleFromInstanceProfileOutput{}, nil)
+				m.DeleteInstanceProfile(gomock.Any()).Return(&iam.DeleteInstanceProfileOutput{}, nil)
+				m.ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+				m.DeleteRolePolicy(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+				m.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String("test-cluster-karpenter-node")}).Return(&iam.DeleteRoleOutput{}, nil)
+			},
+			expectSQS: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("test-cluster-karpenter")}).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String(queueURL)}, nil)
+				m.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String(queueURL)}).Return(&sqs.DeleteQueueOutput{}, nil)
+			},
+			expectEvents: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.RemoveTargets(gomock.Any()).Return(&eventbridge.RemoveTargetsOutput{}, nil).Times(4)
+				m.DeleteRule(gomock.Any()).Return(&eventbridge.DeleteRuleOutput{}, nil).Times(3)
+				m.DeleteRule(gomock.Any()).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			iamMock := mock_iamiface.NewMockIAMAPI(mockCtrl)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			eventsMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			if tc.expectIAM != nil {
+				tc.expectIAM(iamMock.EXPECT())
+			}
+			if tc.expectSQS != nil {
+				tc.expectSQS(sqsMock.EXPECT())
+			}
+			if tc.expectEvents != nil {
+				tc.expectEvents(eventsMock.EXPECT())
+			}
+
+			clusterScope, err := setupCluster("test-cluster", tc.karpenter, tc.status)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := NewService(clusterScope)
+			s.IAMService.IAMClient = iamMock
+			s.SQSClient = sqsMock
+			s.EventBridgeClient = eventsMock
+
+			g.Expect(s.ReconcileKarpenter()).To(Succeed())
+			g.Expect(clusterScope.KarpenterStatus()).To(Equal(tc.wantStatus))
+		})
+	}
+}
+
+func TestNodeRoleName(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope, err := setupCluster("a-cluster-with-a-very-long-name-which-exceeds-the-iam-limit", nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	name := NewService(clusterScope).nodeRoleName()
+	g.Expect(name).To(HaveLen(maxIAMRoleNameLength))
+	g.Expect(name).To(HavePrefix("a-cluster-with-a-very-long-name-which-exceeds-the-iam-limit"[:20]))
+}
+
+func TestDefaultNodePolicyARNs(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope, err := setupCluster("test-cluster", nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterScope.AWSCluster.Spec.Region = "cn-north-1"
+
+	g.Expect(NewService(clusterScope).defaultNodePolicyARNs()).To(ConsistOf(
+		"arn:aws-cn:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+		"arn:aws-cn:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+		"arn:aws-cn:iam::aws:policy/AmazonSSMManagedInstanceCore",
+	))
+}
+
+func setupCluster(clusterName string, karpenter *infrav1.Karpenter, status *infrav1.KarpenterStatus) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			Region:    "us-east-1",
+			Karpenter: karpenter,
+		},
+		Status: infrav1.AWSClusterStatus{Karpenter: status},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).Build()
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package karpenter
+
+import (
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	iamsvc "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the ec2 client.
+type Service struct {
+	scope             scope.KarpenterScope
+	IAMService        *iamsvc.Service
+	EventBridgeClient eventbridgeiface.EventBridgeAPI
+	SQSClient         sqsiface.SQSAPI
+}
+
+// NewService returns a new service given the api clients.
+func NewService(karpenterScope scope.KarpenterScope) *Service {
+	return &Service{
+		scope:             karpenterScope,
+		IAMService:        iamsvc.NewService(karpenterScope),
+		EventBridgeClient: scope.NewEventBridgeClient(karpenterScope, karpenterScope, karpenterScope.InfraCluster()),
+		SQSClient:         scope.NewSQSClient(karpenterScope, karpenterScope, karpenterScope.InfraCluster()),
+	}
+}
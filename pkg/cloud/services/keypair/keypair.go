@@ -0,0 +1,278 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keypair
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ReconcileKeyPair ensures the EC2 key pair of the cluster exists if the cluster has one. The key pair is
+// imported from the public key secret of the cluster, or generated by EC2 with its private key written to a
+// secret owned by the AWSCluster. A key pair with the same name which is not owned by the cluster is not
+// adopted.
+func (s *Service) ReconcileKeyPair() error {
+	spec := s.scope.SSHKeyPair()
+	if spec == nil {
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling SSH key pair")
+
+	name := s.scope.SSHKeyPairName()
+	existing, err := s.describeKeyPair(name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		if !converters.TagsToMap(existing.Tags).HasOwned(s.scope.Name()) {
+			return errors.Errorf("key pair %q already exists and is not owned by the cluster", name)
+		}
+		status := &infrav1.SSHKeyPairStatus{
+			Name:        name,
+			ID:          aws.StringValue(existing.KeyPairId),
+			Fingerprint: aws.StringValue(existing.KeyFingerprint),
+		}
+		if current := s.scope.SSHKeyPairStatus(); current != nil {
+			status.PrivateKeySecretName = current.PrivateKeySecretName
+		} else if spec.PublicKeySecretName == "" {
+			// The status is lost when the cluster is moved with clusterctl move, while the secret holding the
+			// private key is moved along with the AWSCluster which owns it.
+			secretName, err := s.existingPrivateKeySecretName()
+			if err != nil {
+				return err
+			}
+			status.PrivateKeySecretName = secretName
+		}
+		s.scope.SetSSHKeyPairStatus(status)
+		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.SSHKeyPairReadyCondition)
+		return nil
+	}
+
+	var status *infrav1.SSHKeyPairStatus
+	if spec.PublicKeySecretName != "" {
+		status, err = s.importKeyPair(name, spec.PublicKeySecretName)
+	} else {
+		status, err = s.createKeyPair(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.scope.SetSSHKeyPairStatus(status)
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.SSHKeyPairReadyCondition)
+	return nil
+}
+
+// DeleteKeyPair deletes the EC2 key pair of the cluster, if it exists and is owned by the cluster. The secret
+// holding its private key is deleted with the AWSCluster which owns it.
+func (s *Service) DeleteKeyPair() error {
+	if s.scope.SSHKeyPair() == nil && s.scope.SSHKeyPairStatus() == nil {
+		return nil
+	}
+
+	name := s.scope.SSHKeyPairName()
+	existing, err := s.describeKeyPair(name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		if !converters.TagsToMap(existing.Tags).HasOwned(s.scope.Name()) {
+			s.scope.V(2).Info("Skipping deletion of key pair not owned by the cluster", "name", name)
+		} else {
+			if _, err := s.EC2Client.DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: aws.String(name)}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedDeleteKeyPair", "Failed to delete key pair %q: %v", name, err)
+				return errors.Wrapf(err, "failed to delete key pair %q", name)
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteKeyPair", "Deleted key pair %q", name)
+		}
+	}
+
+	s.scope.SetSSHKeyPairStatus(nil)
+	return nil
+}
+
+// describeKeyPair returns the key pair with the given name, or nil if it doesn't exist.
+func (s *Service) describeKeyPair(name string) (*ec2.KeyPairInfo, error) {
+	out, err := s.EC2Client.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{
+		KeyNames: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		if code, ok := awserrors.Code(err); ok && code == awserrors.KeyPairNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to describe key pair %q", name)
+	}
+	if len(out.KeyPairs) == 0 {
+		return nil, nil
+	}
+	return out.KeyPairs[0], nil
+}
+
+// importKeyPair imports the public key of the given secret as the key pair of the cluster.
+func (s *Service) importKeyPair(name, secretName string) (*infrav1.SSHKeyPairStatus, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: s.scope.Namespace(), Name: secretName}
+	if err := s.scope.KubeClient().Get(context.TODO(), key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get public key secret %q", secretName)
+	}
+	publicKey, ok := secret.Data[infrav1.SSHPublicKeySecretKey]
+	if !ok || len(publicKey) == 0 {
+		return nil, errors.Errorf("public key secret %q has no %q key", secretName, infrav1.SSHPublicKeySecretKey)
+	}
+
+	out, err := s.EC2Client.ImportKeyPair(&ec2.ImportKeyPairInput{
+		KeyName:           aws.String(name),
+		PublicKeyMaterial: publicKey,
+		TagSpecifications: s.tagSpecifications(name),
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedImportKeyPair", "Failed to import key pair %q: %v", name, err)
+		return nil, errors.Wrapf(err, "failed to import key pair %q", name)
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulImportKeyPair", "Imported key pair %q from secret %q", name, secretName)
+
+	return &infrav1.SSHKeyPairStatus{
+		Name:        name,
+		ID:          aws.StringValue(out.KeyPairId),
+		Fingerprint: aws.StringValue(out.KeyFingerprint),
+	}, nil
+}
+
+// createKeyPair creates the key pair of the cluster and writes its private key to a secret. EC2 only returns
+// the private key when the key pair is created, so the key pair is deleted again if the secret can't be
+// written, to be created anew on the next reconciliation.
+func (s *Service) createKeyPair(name string) (*infrav1.SSHKeyPairStatus, error) {
+	out, err := s.EC2Client.CreateKeyPair(&ec2.CreateKeyPairInput{
+		KeyName:           aws.String(name),
+		TagSpecifications: s.tagSpecifications(name),
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateKeyPair", "Failed to create key pair %q: %v", name, err)
+		return nil, errors.Wrapf(err, "failed to create key pair %q", name)
+	}
+
+	secretName := s.privateKeySecretName()
+	if err := s.writePrivateKeySecret(secretName, []byte(aws.StringValue(out.KeyMaterial))); err != nil {
+		if _, deleteErr := s.EC2Client.DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: aws.String(name)}); deleteErr != nil {
+			s.scope.Error(deleteErr, "failed to delete key pair whose private key could not be stored", "name", name)
+		}
+		return nil, errors.Wrapf(err, "failed to write the private key of key pair %q", name)
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateKeyPair", "Created key pair %q with its private key in secret %q", name, secretName)
+
+	return &infrav1.SSHKeyPairStatus{
+		Name:                 name,
+		ID:                   aws.StringValue(out.KeyPairId),
+		Fingerprint:          aws.StringValue(out.KeyFingerprint),
+		PrivateKeySecretName: secretName,
+	}, nil
+}
+
+// writePrivateKeySecret creates or updates the secret holding the private key of the key pair, owned by the
+// AWSCluster. It fails rather than overwrite a secret of the same name which the AWSCluster doesn't own.
+func (s *Service) writePrivateKeySecret(secretName string, privateKey []byte) error {
+	ctx := context.TODO()
+	kubeClient := s.scope.KubeClient()
+
+	secret := &corev1.Secret{}
+	err := kubeClient.Get(ctx, types.NamespacedName{Namespace: s.scope.Namespace(), Name: secretName}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: s.scope.Namespace(),
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: s.scope.Name(),
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(s.scope.InfraCluster(), infrav1.GroupVersion.WithKind("AWSCluster")),
+				},
+			},
+			Type: corev1.SecretTypeSSHAuth,
+			Data: map[string][]byte{
+				corev1.SSHAuthPrivateKey: privateKey,
+			},
+		}
+		return kubeClient.Create(ctx, secret)
+	case err != nil:
+		return err
+	}
+
+	if !metav1.IsControlledBy(secret, s.scope.InfraCluster()) {
+		return errors.Errorf("secret %q already exists and is not owned by the cluster", secretName)
+	}
+	secret.Data = map[string][]byte{
+		corev1.SSHAuthPrivateKey: privateKey,
+	}
+	return kubeClient.Update(ctx, secret)
+}
+
+// existingPrivateKeySecretName returns the name of the secret holding the private key of the key pair, or an
+// empty string if it doesn't exist or is not owned by the cluster.
+func (s *Service) existingPrivateKeySecretName() (string, error) {
+	secretName := s.privateKeySecretName()
+	secret := &corev1.Secret{}
+	err := s.scope.KubeClient().Get(context.TODO(), types.NamespacedName{Namespace: s.scope.Namespace(), Name: secretName}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		return "", nil
+	case err != nil:
+		return "", errors.Wrapf(err, "failed to get private key secret %q", secretName)
+	}
+	if !metav1.IsControlledBy(secret, s.scope.InfraCluster()) {
+		return "", nil
+	}
+	return secretName, nil
+}
+
+// privateKeySecretName returns the name of the secret holding the private key of a key pair generated by EC2.
+func (s *Service) privateKeySecretName() string {
+	return fmt.Sprintf("%s-ssh-key", s.scope.Name())
+}
+
+func (s *Service) tagSpecifications(name string) []*ec2.TagSpecification {
+	return []*ec2.TagSpecification{
+		{
+			ResourceType: aws.String(ec2.ResourceTypeKeyPair),
+			Tags: converters.MapToTags(infrav1.Build(infrav1.BuildParams{
+				ClusterName: s.scope.Name(),
+				Lifecycle:   infrav1.ResourceLifecycleOwned,
+				Name:        aws.String(name),
+				Additional:  s.scope.AdditionalTags(),
+			})),
+		},
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keypair
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+)
+
+func TestReconcileKeyPair(t *testing.T) {
+	notFound := awserr.New(awserrors.KeyPairNotFound, "not found", nil)
+	ownedTags := []*ec2.Tag{{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String(string(infrav1.ResourceLifecycleOwned))}}
+
+	testCases := []struct {
+		name           string
+		keyPair        *infrav1.SSHKeyPair
+		objects        []client.Object
+		expect         func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		wantErr        bool
+		wantStatus     *infrav1.SSHKeyPairStatus
+		wantPrivateKey string
+		wantSSHKeyName *string
+	}{
+		{
+			name: "does nothing if the cluster has no key pair",
+		},
+		{
+			name:    "creates the key pair and writes its private key to a secret",
+			keyPair: &infrav1.SSHKeyPair{},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(nil, notFound)
+				m.CreateKeyPair(gomock.Any()).Return(&ec2.CreateKeyPairOutput{
+					KeyName:        aws.String("test-cluster-ssh-key"),
+					KeyPairId:      aws.String("key-1"),
+					KeyFingerprint: aws.String("fingerprint"),
+					KeyMaterial:    aws.String("private key"),
+				}, nil)
+			},
+			wantStatus: &infrav1.SSHKeyPairStatus{
+				Name:                 "test-cluster-ssh-key",
+				ID:                   "key-1",
+				Fingerprint:          "fingerprint",
+				PrivateKeySecretName: "test-cluster-ssh-key",
+			},
+			wantPrivateKey: "private key",
+			wantSSHKeyName: aws.String("test-cluster-ssh-key"),
+		},
+		{
+			name:    "imports the public key of the secret",
+			keyPair: &infrav1.SSHKeyPair{PublicKeySecretName: "public-key"},
+			objects: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "public-key"},
+				Data:       map[string][]byte{infrav1.SSHPublicKeySecretKey: []byte("ssh-ed25519 AAAA")},
+			}},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(nil, notFound)
+				m.ImportKeyPair(gomock.Any()).
+					DoAndReturn(func(input *ec2.ImportKeyPairInput) (*ec2.ImportKeyPairOutput, error) {
+						if string(input.PublicKeyMaterial) != "ssh-ed25519 AAAA" {
+							t.Errorf("Expected the public key of the secret, got %q", input.PublicKeyMaterial)
+						}
+						return &ec2.ImportKeyPairOutput{KeyPairId: aws.String("key-1"), KeyFingerprint: aws.String("fingerprint")}, nil
+					})
+			},
+			wantStatus:     &infrav1.SSHKeyPairStatus{Name: "test-cluster-ssh-key", ID: "key-1", Fingerprint: "fingerprint"},
+			wantSSHKeyName: aws.String("test-cluster-ssh-key"),
+		},
+		{
+			name:    "fails if the public key secret doesn't exist",
+			keyPair: &infrav1.SSHKeyPair{PublicKeySecretName: "public-key"},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(nil, notFound)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "keeps the existing key pair of the cluster",
+			keyPair: &infrav1.SSHKeyPair{},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(&ec2.DescribeKeyPairsOutput{
+					KeyPairs: []*ec2.KeyPairInfo{{KeyName: aws.String("test-cluster-ssh-key"), KeyPairId: aws.String("key-1"), Tags: ownedTags}},
+				}, nil)
+			},
+			wantStatus:     &infrav1.SSHKeyPairStatus{Name: "test-cluster-ssh-key", ID: "key-1"},
+			wantSSHKeyName: aws.String("test-cluster-ssh-key"),
+		},
+		{
+			name:    "fails rather than overwrite a private key secret which is not owned by the cluster",
+			keyPair: &infrav1.SSHKeyPair{},
+			objects: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster-ssh-key"},
+				Data:       map[string][]byte{corev1.SSHAuthPrivateKey: []byte("someone else's key")},
+			}},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(nil, notFound)
+				m.CreateKeyPair(gomock.Any()).Return(&ec2.CreateKeyPairOutput{
+					KeyName:     aws.String("test-cluster-ssh-key"),
+					KeyPairId:   aws.String("key-1"),
+					KeyMaterial: aws.String("private key"),
+				}, nil)
+				m.DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: aws.String("test-cluster-ssh-key")}).Return(&ec2.DeleteKeyPairOutput{}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "finds the private key secret of the existing key pair when the status was lost",
+			keyPair: &infrav1.SSHKeyPair{},
+			objects: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            "test-cluster-ssh-key",
+					OwnerReferences: []metav1.OwnerReference{{Kind: "AWSCluster", Name: "test", UID: "aws-cluster", Controller: aws.Bool(true)}},
+				},
+				Type: corev1.SecretTypeSSHAuth,
+				Data: map[string][]byte{corev1.SSHAuthPrivateKey: []byte("private key")},
+			}},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(&ec2.DescribeKeyPairsOutput{
+					KeyPairs: []*ec2.KeyPairInfo{{KeyName: aws.String("test-cluster-ssh-key"), KeyPairId: aws.String("key-1"), Tags: ownedTags}},
+				}, nil)
+			},
+			wantStatus:     &infrav1.SSHKeyPairStatus{Name: "test-cluster-ssh-key", ID: "key-1", PrivateKeySecretName: "test-cluster-ssh-key"},
+			wantSSHKeyName: aws.String("test-cluster-ssh-key"),
+		},
+		{
+			name:    "doesn't adopt a key pair which is not owned by the cluster",
+			keyPair: &infrav1.SSHKeyPair{},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeKeyPairs(gomock.Any()).Return(&ec2.DescribeKeyPairsOutput{
+					KeyPairs: []*ec2.KeyPairInfo{{KeyName: aws.String("test-cluster-ssh-key"), KeyPairId: aws.String("key-1")}},
+				}, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(ec2Mock.EXPECT())
+			}
+
+			clusterScope, err := setupCluster(tc.keyPair, tc.objects...)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			err = s.ReconcileKeyPair()
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(clusterScope.SSHKeyPairStatus()).To(Equal(tc.wantStatus))
+			g.Expect(clusterScope.SSHKeyName()).To(Equal(tc.wantSSHKeyName))
+
+			if tc.wantPrivateKey != "" {
+				secret := &corev1.Secret{}
+				g.Expect(clusterScope.KubeClient().Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-cluster-ssh-key"}, secret)).To(Succeed())
+				g.Expect(secret.Type).To(Equal(corev1.SecretTypeSSHAuth))
+				g.Expect(string(secret.Data[corev1.SSHAuthPrivateKey])).To(Equal(tc.wantPrivateKey))
+				g.Expect(secret.OwnerReferences).To(HaveLen(1))
+			}
+		})
+	}
+}
+
+func TestDeleteKeyPair(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	ec2Mock.EXPECT().DescribeKeyPairs(gomock.Any()).Return(&ec2.DescribeKeyPairsOutput{
+		KeyPairs: []*ec2.KeyPairInfo{{
+			KeyName: aws.String("test-cluster-ssh-key"),
+			Tags:    []*ec2.Tag{{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String(string(infrav1.ResourceLifecycleOwned))}},
+		}},
+	}, nil)
+	ec2Mock.EXPECT().DeleteKeyPair(gomock.Eq(&ec2.DeleteKeyPairInput{KeyName: aws.String("test-cluster-ssh-key")})).
+		Return(&ec2.DeleteKeyPairOutput{}, nil)
+
+	clusterScope, err := setupCluster(&infrav1.SSHKeyPair{})
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterScope.SetSSHKeyPairStatus(&infrav1.SSHKeyPairStatus{Name: "test-cluster-ssh-key"})
+
+	s := NewService(clusterScope)
+	s.EC2Client = ec2Mock
+
+	g.Expect(s.DeleteKeyPair()).To(Succeed())
+	g.Expect(clusterScope.SSHKeyPairStatus()).To(BeNil())
+}
+
+func setupCluster(keyPair *infrav1.SSHKeyPair, objects ...client.Object) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", UID: "aws-cluster"},
+		Spec: infrav1.AWSClusterSpec{
+			Region:     "us-east-1",
+			SSHKeyPair: keyPair,
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, awsCluster)...).Build()
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+}
@@ -24,6 +24,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1alpha4 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
 	scope "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 )
@@ -81,6 +82,20 @@ func (mr *MockASGInterfaceMockRecorder) CanStartASGInstanceRefresh(arg0 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanStartASGInstanceRefresh", reflect.TypeOf((*MockASGInterface)(nil).CanStartASGInstanceRefresh), arg0)
 }
 
+// CompleteLifecycleAction mocks base method.
+func (m *MockASGInterface) CompleteLifecycleAction(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLifecycleAction", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLifecycleAction indicates an expected call of CompleteLifecycleAction.
+func (mr *MockASGInterfaceMockRecorder) CompleteLifecycleAction(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLifecycleAction", reflect.TypeOf((*MockASGInterface)(nil).CompleteLifecycleAction), arg0, arg1, arg2, arg3)
+}
+
 // CreateASG mocks base method.
 func (m *MockASGInterface) CreateASG(arg0 *scope.MachinePoolScope) (*v1alpha4.AutoScalingGroup, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +140,34 @@ func (mr *MockASGInterfaceMockRecorder) GetASGByName(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetASGByName", reflect.TypeOf((*MockASGInterface)(nil).GetASGByName), arg0)
 }
 
+// ReconcileLifecycleHook mocks base method.
+func (m *MockASGInterface) ReconcileLifecycleHook(arg0 *scope.MachinePoolScope, arg1 *v1.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileLifecycleHook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileLifecycleHook indicates an expected call of ReconcileLifecycleHook.
+func (mr *MockASGInterfaceMockRecorder) ReconcileLifecycleHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileLifecycleHook", reflect.TypeOf((*MockASGInterface)(nil).ReconcileLifecycleHook), arg0, arg1)
+}
+
+// SetInstanceProtection mocks base method.
+func (m *MockASGInterface) SetInstanceProtection(arg0 string, arg1 []string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceProtection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceProtection indicates an expected call of SetInstanceProtection.
+func (mr *MockASGInterfaceMockRecorder) SetInstanceProtection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceProtection", reflect.TypeOf((*MockASGInterface)(nil).SetInstanceProtection), arg0, arg1, arg2)
+}
+
 // StartASGInstanceRefresh mocks base method.
 func (m *MockASGInterface) StartASGInstanceRefresh(arg0 *scope.MachinePoolScope) error {
 	m.ctrl.T.Helper()
@@ -139,6 +182,20 @@ func (mr *MockASGInterfaceMockRecorder) StartASGInstanceRefresh(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartASGInstanceRefresh", reflect.TypeOf((*MockASGInterface)(nil).StartASGInstanceRefresh), arg0)
 }
 
+// TerminateInstanceInASG mocks base method.
+func (m *MockASGInterface) TerminateInstanceInASG(arg0 string, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TerminateInstanceInASG", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TerminateInstanceInASG indicates an expected call of TerminateInstanceInASG.
+func (mr *MockASGInterfaceMockRecorder) TerminateInstanceInASG(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateInstanceInASG", reflect.TypeOf((*MockASGInterface)(nil).TerminateInstanceInASG), arg0, arg1)
+}
+
 // UpdateASG mocks base method.
 func (m *MockASGInterface) UpdateASG(arg0 *scope.MachinePoolScope) error {
 	m.ctrl.T.Helper()
@@ -23,6 +23,7 @@ package mock_services
 import (
 	reflect "reflect"
 
+	ec2 "github.com/aws/aws-sdk-go/service/ec2"
 	gomock "github.com/golang/mock/gomock"
 	v1alpha4 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	v1alpha40 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
@@ -52,6 +53,51 @@ func (m *MockEC2MachineInterface) EXPECT() *MockEC2MachineInterfaceMockRecorder
 	return m.recorder
 }
 
+// AdoptInstance mocks base method.
+func (m *MockEC2MachineInterface) AdoptInstance(arg0 *scope.MachineScope, arg1 string) (*v1alpha4.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdoptInstance", arg0, arg1)
+	ret0, _ := ret[0].(*v1alpha4.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdoptInstance indicates an expected call of AdoptInstance.
+func (mr *MockEC2MachineInterfaceMockRecorder) AdoptInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdoptInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).AdoptInstance), arg0, arg1)
+}
+
+// AnalyzeAPIServerConnectivity mocks base method.
+func (m *MockEC2MachineInterface) AnalyzeAPIServerConnectivity(arg0 *scope.MachineScope, arg1 *v1alpha4.Instance) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyzeAPIServerConnectivity", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyzeAPIServerConnectivity indicates an expected call of AnalyzeAPIServerConnectivity.
+func (mr *MockEC2MachineInterfaceMockRecorder) AnalyzeAPIServerConnectivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeAPIServerConnectivity", reflect.TypeOf((*MockEC2MachineInterface)(nil).AnalyzeAPIServerConnectivity), arg0, arg1)
+}
+
+// CreateFleetInstances mocks base method.
+func (m *MockEC2MachineInterface) CreateFleetInstances(arg0 *scope.MachinePoolScope, arg1 []v1alpha4.Instance, arg2 int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFleetInstances", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFleetInstances indicates an expected call of CreateFleetInstances.
+func (mr *MockEC2MachineInterfaceMockRecorder) CreateFleetInstances(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFleetInstances", reflect.TypeOf((*MockEC2MachineInterface)(nil).CreateFleetInstances), arg0, arg1, arg2)
+}
+
 // CreateInstance mocks base method.
 func (m *MockEC2MachineInterface) CreateInstance(arg0 *scope.MachineScope, arg1 []byte) (*v1alpha4.Instance, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +142,20 @@ func (mr *MockEC2MachineInterfaceMockRecorder) CreateLaunchTemplateVersion(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLaunchTemplateVersion", reflect.TypeOf((*MockEC2MachineInterface)(nil).CreateLaunchTemplateVersion), arg0, arg1, arg2)
 }
 
+// DeleteAPIServerConnectivityCheck mocks base method.
+func (m *MockEC2MachineInterface) DeleteAPIServerConnectivityCheck(arg0 *scope.MachineScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIServerConnectivityCheck", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIServerConnectivityCheck indicates an expected call of DeleteAPIServerConnectivityCheck.
+func (mr *MockEC2MachineInterfaceMockRecorder) DeleteAPIServerConnectivityCheck(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIServerConnectivityCheck", reflect.TypeOf((*MockEC2MachineInterface)(nil).DeleteAPIServerConnectivityCheck), arg0)
+}
+
 // DeleteLaunchTemplate mocks base method.
 func (m *MockEC2MachineInterface) DeleteLaunchTemplate(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -169,6 +229,36 @@ func (mr *MockEC2MachineInterfaceMockRecorder) GetFilteredSecurityGroupID(arg0 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredSecurityGroupID", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetFilteredSecurityGroupID), arg0)
 }
 
+// GetFleetInstances mocks base method.
+func (m *MockEC2MachineInterface) GetFleetInstances(arg0 *scope.MachinePoolScope) ([]v1alpha4.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFleetInstances", arg0)
+	ret0, _ := ret[0].([]v1alpha4.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFleetInstances indicates an expected call of GetFleetInstances.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetFleetInstances(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFleetInstances", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetFleetInstances), arg0)
+}
+
+// GetInstanceScheduledEvents mocks base method.
+func (m *MockEC2MachineInterface) GetInstanceScheduledEvents(arg0 string) ([]v1alpha4.InstanceScheduledEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceScheduledEvents", arg0)
+	ret0, _ := ret[0].([]v1alpha4.InstanceScheduledEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceScheduledEvents indicates an expected call of GetInstanceScheduledEvents.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceScheduledEvents(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceScheduledEvents", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceScheduledEvents), arg0)
+}
+
 // GetInstanceSecurityGroups mocks base method.
 func (m *MockEC2MachineInterface) GetInstanceSecurityGroups(arg0 string) (map[string][]string, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +274,66 @@ func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceSecurityGroups(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceSecurityGroups", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceSecurityGroups), arg0)
 }
 
+// GetInstanceSystemStatus mocks base method.
+func (m *MockEC2MachineInterface) GetInstanceSystemStatus(arg0 string) (*ec2.InstanceStatusSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceSystemStatus", arg0)
+	ret0, _ := ret[0].(*ec2.InstanceStatusSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceSystemStatus indicates an expected call of GetInstanceSystemStatus.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceSystemStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceSystemStatus", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceSystemStatus), arg0)
+}
+
+// GetInstanceTypeInfo mocks base method.
+func (m *MockEC2MachineInterface) GetInstanceTypeInfo(arg0 string) (*ec2.InstanceTypeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceTypeInfo", arg0)
+	ret0, _ := ret[0].(*ec2.InstanceTypeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceTypeInfo indicates an expected call of GetInstanceTypeInfo.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceTypeInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceTypeInfo", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceTypeInfo), arg0)
+}
+
+// GetInstanceTypeZones mocks base method.
+func (m *MockEC2MachineInterface) GetInstanceTypeZones(arg0 string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceTypeZones", arg0)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceTypeZones indicates an expected call of GetInstanceTypeZones.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceTypeZones(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceTypeZones", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceTypeZones), arg0)
+}
+
+// GetInstancesByIDs mocks base method.
+func (m *MockEC2MachineInterface) GetInstancesByIDs(arg0 []string) ([]v1alpha4.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstancesByIDs", arg0)
+	ret0, _ := ret[0].([]v1alpha4.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstancesByIDs indicates an expected call of GetInstancesByIDs.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetInstancesByIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstancesByIDs", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstancesByIDs), arg0)
+}
+
 // GetLaunchTemplate mocks base method.
 func (m *MockEC2MachineInterface) GetLaunchTemplate(arg0 string) (*v1alpha40.AWSLaunchTemplate, string, error) {
 	m.ctrl.T.Helper()
@@ -230,6 +380,21 @@ func (mr *MockEC2MachineInterfaceMockRecorder) GetRunningInstanceByTags(arg0 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningInstanceByTags", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetRunningInstanceByTags), arg0)
 }
 
+// GetSpotPrice mocks base method.
+func (m *MockEC2MachineInterface) GetSpotPrice(arg0, arg1 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpotPrice", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpotPrice indicates an expected call of GetSpotPrice.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetSpotPrice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpotPrice", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetSpotPrice), arg0, arg1)
+}
+
 // InstanceIfExists mocks base method.
 func (m *MockEC2MachineInterface) InstanceIfExists(arg0 *string) (*v1alpha4.Instance, error) {
 	m.ctrl.T.Helper()
@@ -261,17 +426,32 @@ func (mr *MockEC2MachineInterfaceMockRecorder) LaunchTemplateNeedsUpdate(arg0, a
 }
 
 // PruneLaunchTemplateVersions mocks base method.
-func (m *MockEC2MachineInterface) PruneLaunchTemplateVersions(arg0 string) error {
+func (m *MockEC2MachineInterface) PruneLaunchTemplateVersions(arg0 string, arg1 int32) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PruneLaunchTemplateVersions", arg0)
+	ret := m.ctrl.Call(m, "PruneLaunchTemplateVersions", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PruneLaunchTemplateVersions indicates an expected call of PruneLaunchTemplateVersions.
-func (mr *MockEC2MachineInterfaceMockRecorder) PruneLaunchTemplateVersions(arg0 interface{}) *gomock.Call {
+func (mr *MockEC2MachineInterfaceMockRecorder) PruneLaunchTemplateVersions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneLaunchTemplateVersions", reflect.TypeOf((*MockEC2MachineInterface)(nil).PruneLaunchTemplateVersions), arg0, arg1)
+}
+
+// ReconcileAPIServerConnectivity mocks base method.
+func (m *MockEC2MachineInterface) ReconcileAPIServerConnectivity(arg0 *scope.MachineScope, arg1 *v1alpha4.Instance) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileAPIServerConnectivity", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileAPIServerConnectivity indicates an expected call of ReconcileAPIServerConnectivity.
+func (mr *MockEC2MachineInterfaceMockRecorder) ReconcileAPIServerConnectivity(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneLaunchTemplateVersions", reflect.TypeOf((*MockEC2MachineInterface)(nil).PruneLaunchTemplateVersions), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileAPIServerConnectivity", reflect.TypeOf((*MockEC2MachineInterface)(nil).ReconcileAPIServerConnectivity), arg0, arg1)
 }
 
 // TerminateInstance mocks base method.
@@ -20,20 +20,31 @@ import (
 	"sort"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 )
 
 func (s *Service) getAvailableZones() ([]string, error) {
-	out, err := s.EC2Client.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+	zones, err := describeAvailableZones(s.EC2Client)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAvailableZone", "Failed getting available zones: %v", err)
+		return nil, err
+	}
+	return zones, nil
+}
+
+// describeAvailableZones returns the sorted names of the available zones of the region of the client,
+// without local zones.
+func describeAvailableZones(client ec2iface.EC2API) ([]string, error) {
+	out, err := client.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
 		Filters: []*ec2.Filter{
 			filter.EC2.Available(),
 			filter.EC2.IgnoreLocalZones(),
 		},
 	})
 	if err != nil {
-		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAvailableZone", "Failed getting available zones: %v", err)
 		return nil, errors.Wrap(err, "failed to describe availability zones")
 	}
 
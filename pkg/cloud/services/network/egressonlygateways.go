@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileEgressOnlyInternetGateways makes sure a managed VPC with IPv6 enabled has an egress-only internet gateway,
+// which the private subnets route their IPv6 traffic through.
+func (s *Service) reconcileEgressOnlyInternetGateways() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping egress-only internet gateways reconcile in unmanaged mode")
+		return nil
+	}
+
+	if !s.scope.VPC().IsIPv6Enabled() {
+		s.scope.V(4).Info("Skipping egress-only internet gateways reconcile, IPv6 is not enabled in the VPC")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling egress-only internet gateways")
+
+	eigws, err := s.describeEgressOnlyVpcInternetGateways()
+	if awserrors.IsNotFound(err) {
+		eigw, err := s.createEgressOnlyInternetGateway()
+		if err != nil {
+			return err
+		}
+		eigws = []*ec2.EgressOnlyInternetGateway{eigw}
+	} else if err != nil {
+		return err
+	}
+
+	s.scope.VPC().IPv6.EgressOnlyInternetGatewayID = eigws[0].EgressOnlyInternetGatewayId
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition)
+	return nil
+}
+
+func (s *Service) deleteEgressOnlyInternetGateways() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping egress-only internet gateway deletion in unmanaged mode")
+		return nil
+	}
+
+	eigws, err := s.describeEgressOnlyVpcInternetGateways()
+	if awserrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, eigw := range eigws {
+		if _, err := s.EC2Client.DeleteEgressOnlyInternetGateway(&ec2.DeleteEgressOnlyInternetGatewayInput{
+			EgressOnlyInternetGatewayId: eigw.EgressOnlyInternetGatewayId,
+		}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDeleteEgressOnlyInternetGateway", "Failed to delete Egress-Only Internet Gateway %q previously attached to VPC %q: %v", *eigw.EgressOnlyInternetGatewayId, s.scope.VPC().ID, err)
+			return errors.Wrapf(err, "failed to delete egress-only internet gateway %q", *eigw.EgressOnlyInternetGatewayId)
+		}
+
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteEgressOnlyInternetGateway", "Deleted Egress-Only Internet Gateway %q previously attached to VPC %q", *eigw.EgressOnlyInternetGatewayId, s.scope.VPC().ID)
+		s.scope.Info("Deleted egress-only internet gateway in VPC", "egress-only-internet-gateway-id", *eigw.EgressOnlyInternetGatewayId, "vpc-id", s.scope.VPC().ID)
+	}
+
+	return nil
+}
+
+func (s *Service) createEgressOnlyInternetGateway() (*ec2.EgressOnlyInternetGateway, error) {
+	out, err := s.EC2Client.CreateEgressOnlyInternetGateway(&ec2.CreateEgressOnlyInternetGatewayInput{
+		VpcId: aws.String(s.scope.VPC().ID),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypeEgressOnlyInternetGateway, s.getEgressOnlyGatewayTagParams(services.TemporaryResourceID)),
+		},
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateEgressOnlyInternetGateway", "Failed to create new managed Egress-Only Internet Gateway: %v", err)
+		return nil, errors.Wrap(err, "failed to create egress-only internet gateway")
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateEgressOnlyInternetGateway", "Created new managed Egress-Only Internet Gateway %q", *out.EgressOnlyInternetGateway.EgressOnlyInternetGatewayId)
+	s.scope.Info("Created egress-only internet gateway for VPC", "egress-only-internet-gateway-id", *out.EgressOnlyInternetGateway.EgressOnlyInternetGatewayId, "vpc-id", s.scope.VPC().ID)
+
+	return out.EgressOnlyInternetGateway, nil
+}
+
+// describeEgressOnlyVpcInternetGateways returns the egress-only internet gateways of the cluster attached to its VPC.
+// They can't be filtered by attachment, so the gateways tagged for the cluster are filtered by VPC.
+func (s *Service) describeEgressOnlyVpcInternetGateways() ([]*ec2.EgressOnlyInternetGateway, error) {
+	out, err := s.EC2Client.DescribeEgressOnlyInternetGateways(&ec2.DescribeEgressOnlyInternetGatewaysInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.Cluster(s.scope.Name()),
+		},
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeEgressOnlyInternetGateway", "Failed to describe egress-only internet gateways in vpc %q: %v", s.scope.VPC().ID, err)
+		return nil, errors.Wrapf(err, "failed to describe egress-only internet gateways in vpc %q", s.scope.VPC().ID)
+	}
+
+	var eigws []*ec2.EgressOnlyInternetGateway
+	for _, eigw := range out.EgressOnlyInternetGateways {
+		for _, attachment := range eigw.Attachments {
+			if aws.StringValue(attachment.VpcId) == s.scope.VPC().ID {
+				eigws = append(eigws, eigw)
+				break
+			}
+		}
+	}
+
+	if len(eigws) == 0 {
+		return nil, awserrors.NewNotFound(fmt.Sprintf("no egress-only internet gateways found in vpc %q", s.scope.VPC().ID))
+	}
+
+	return eigws, nil
+}
+
+func (s *Service) getEgressOnlyGatewayTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-eigw", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.CommonRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileEgressOnlyInternetGateways(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name     string
+		input    *infrav1.NetworkSpec
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectID *string
+	}{
+		{
+			name: "ipv6 not enabled, does nothing",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: "vpc-gateways",
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "has eigw",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: "vpc-gateways",
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+					IPv6: &infrav1.IPv6{CidrBlock: "2001:db8:1234:1a00::/56"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeEgressOnlyInternetGateways(gomock.AssignableToTypeOf(&ec2.DescribeEgressOnlyInternetGatewaysInput{})).
+					Return(&ec2.DescribeEgressOnlyInternetGatewaysOutput{
+						EgressOnlyInternetGateways: []*ec2.EgressOnlyInternetGateway{
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-other"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{
+										State: aws.String(ec2.AttachmentStatusAttached),
+										VpcId: aws.String("vpc-other"),
+									},
+								},
+							},
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-0"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{
+										State: aws.String(ec2.AttachmentStatusAttached),
+										VpcId: aws.String("vpc-gateways"),
+									},
+								},
+							},
+						},
+					}, nil)
+			},
+			expectID: aws.String("eigw-0"),
+		},
+		{
+			name: "no eigw attached, creates one",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: "vpc-gateways",
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+					IPv6: &infrav1.IPv6{CidrBlock: "2001:db8:1234:1a00::/56"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeEgressOnlyInternetGateways(gomock.AssignableToTypeOf(&ec2.DescribeEgressOnlyInternetGatewaysInput{})).
+					Return(&ec2.DescribeEgressOnlyInternetGatewaysOutput{}, nil)
+
+				m.CreateEgressOnlyInternetGateway(gomock.AssignableToTypeOf(&ec2.CreateEgressOnlyInternetGatewayInput{})).
+					Do(func(input *ec2.CreateEgressOnlyInternetGatewayInput) {
+						if aws.StringValue(input.VpcId) != "vpc-gateways" {
+							t.Fatalf("expected the egress-only internet gateway to be created in vpc %q, got %q", "vpc-gateways", aws.StringValue(input.VpcId))
+						}
+					}).
+					Return(&ec2.CreateEgressOnlyInternetGatewayOutput{
+						EgressOnlyInternetGateway: &ec2.EgressOnlyInternetGateway{
+							EgressOnlyInternetGatewayId: aws.String("eigw-1"),
+							Attachments: []*ec2.InternetGatewayAttachment{
+								{
+									State: aws.String(ec2.AttachmentStatusAttached),
+									VpcId: aws.String("vpc-gateways"),
+								},
+							},
+						},
+					}, nil)
+			},
+			expectID: aws.String("eigw-1"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: *tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			if err := s.reconcileEgressOnlyInternetGateways(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if tc.expectID == nil {
+				if scope.VPC().IPv6 != nil {
+					t.Fatalf("expected no ipv6 settings in the vpc, got %v", scope.VPC().IPv6)
+				}
+				return
+			}
+			if id := aws.StringValue(scope.VPC().IPv6.EgressOnlyInternetGatewayID); id != *tc.expectID {
+				t.Fatalf("expected egress-only internet gateway %q in spec, got %q", *tc.expectID, id)
+			}
+		})
+	}
+}
@@ -31,6 +31,10 @@ import (
 )
 
 func (s *Service) getOrAllocateAddresses(num int, role string) (eips []string, err error) {
+	if s.scope.ElasticIPPool() != nil {
+		return s.getPoolAddresses(num)
+	}
+
 	out, err := s.describeAddresses(role)
 	if err != nil {
 		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAddresses", "Failed to query addresses for role %q: %v", role, err)
@@ -54,6 +58,63 @@ func (s *Service) getOrAllocateAddresses(num int, role string) (eips []string, e
 	return eips, nil
 }
 
+// getPoolAddresses returns the allocation IDs of num Elastic IPs of the pool which are not associated yet. No Elastic
+// IP is allocated for a cluster with a pool, so that its public IPs remain those which are allowlisted.
+func (s *Service) getPoolAddresses(num int) ([]string, error) {
+	out, err := s.describePoolAddresses()
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAddresses", "Failed to query the addresses of the Elastic IP pool: %v", err)
+		return nil, errors.Wrap(err, "failed to query the addresses of the Elastic IP pool")
+	}
+
+	eips := []string{}
+	for _, address := range out.Addresses {
+		if address.AssociationId == nil {
+			eips = append(eips, aws.StringValue(address.AllocationId))
+		}
+	}
+
+	if len(eips) < num {
+		record.Warnf(s.scope.InfraCluster(), "InsufficientElasticIPPool", "The Elastic IP pool has %d available addresses, %d are needed", len(eips), num)
+		return nil, errors.Errorf("the Elastic IP pool has %d available addresses, %d are needed", len(eips), num)
+	}
+
+	return eips[:num], nil
+}
+
+// preallocatedAllocationIDs returns the allocation IDs of the Elastic IPs of the pool and of those set for the NAT
+// gateways, which are never released.
+func (s *Service) preallocatedAllocationIDs() (map[string]bool, error) {
+	ids := map[string]bool{}
+	for _, id := range s.scope.NatGatewayAllocationIDs() {
+		ids[id] = true
+	}
+	if s.scope.ElasticIPPool() == nil {
+		return ids, nil
+	}
+
+	out, err := s.describePoolAddresses()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query the addresses of the Elastic IP pool")
+	}
+	for _, address := range out.Addresses {
+		ids[aws.StringValue(address.AllocationId)] = true
+	}
+	return ids, nil
+}
+
+func (s *Service) describePoolAddresses() (*ec2.DescribeAddressesOutput, error) {
+	pool := s.scope.ElasticIPPool()
+	input := &ec2.DescribeAddressesInput{}
+	if len(pool.AllocationIDs) > 0 {
+		input.AllocationIds = aws.StringSlice(pool.AllocationIDs)
+	}
+	if len(pool.Tags) > 0 {
+		input.Filters = filter.EC2.Tags(pool.Tags)
+	}
+	return s.EC2Client.DescribeAddresses(input)
+}
+
 func (s *Service) allocateAddress(role string) (string, error) {
 	tagSpecifications := tags.BuildParamsToTagSpecification(ec2.ResourceTypeElasticIp, s.getEIPTagParams(role))
 	out, err := s.EC2Client.AllocateAddress(&ec2.AllocateAddressInput{
@@ -109,8 +170,16 @@ func (s *Service) releaseAddresses() error {
 		return errors.Wrapf(err, "failed to describe elastic IPs %q", err)
 	}
 
+	preallocated, err := s.preallocatedAllocationIDs()
+	if err != nil {
+		return err
+	}
+
 	for i := range out.Addresses {
 		ip := out.Addresses[i]
+		if preallocated[aws.StringValue(ip.AllocationId)] {
+			continue
+		}
 		if ip.AssociationId != nil {
 			_, err := s.EC2Client.DisassociateAddress(&ec2.DisassociateAddressInput{
 				AssociationId: ip.AssociationId,
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetOrAllocateAddressesFromPool(t *testing.T) {
+	pool := &infrav1.ElasticIPPool{
+		AllocationIDs: []string{"eipalloc-1", "eipalloc-2", "eipalloc-3"},
+		Tags:          infrav1.Tags{"allowlisted": "true"},
+	}
+	poolInput := &ec2.DescribeAddressesInput{
+		AllocationIds: aws.StringSlice([]string{"eipalloc-1", "eipalloc-2", "eipalloc-3"}),
+		Filters: []*ec2.Filter{
+			{Name: aws.String("tag:allowlisted"), Values: aws.StringSlice([]string{"true"})},
+		},
+	}
+	poolOutput := &ec2.DescribeAddressesOutput{
+		Addresses: []*ec2.Address{
+			{AllocationId: aws.String("eipalloc-1"), AssociationId: aws.String("eipassoc-1")},
+			{AllocationId: aws.String("eipalloc-2")},
+			{AllocationId: aws.String("eipalloc-3")},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		num     int
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "uses the addresses of the pool which are not associated",
+			num:  2,
+			want: []string{"eipalloc-2", "eipalloc-3"},
+		},
+		{
+			name:    "fails when the pool has too few available addresses",
+			num:     3,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{ElasticIPPool: pool},
+					},
+				},
+				Client: client,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			ec2Mock.EXPECT().DescribeAddresses(gomock.Eq(poolInput)).Return(poolOutput, nil)
+			ec2Mock.EXPECT().AllocateAddress(gomock.Any()).Times(0)
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			eips, err := s.getOrAllocateAddresses(tc.num, infrav1.APIServerRoleTagValue)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(eips).To(Equal(tc.want))
+		})
+	}
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/networkfirewall"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func (s *Service) reconcileFirewall() error {
+	firewall := s.scope.Firewall()
+	if firewall == nil {
+		s.scope.Network().Firewall = nil
+		return nil
+	}
+
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping network firewall reconcile in unmanaged mode")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling network firewall")
+
+	var out *networkfirewall.DescribeFirewallOutput
+	var err error
+	if firewall.IsManaged() {
+		if err := s.reconcileFirewallSubnets(); err != nil {
+			return err
+		}
+
+		out, err = s.describeFirewall(&networkfirewall.DescribeFirewallInput{FirewallName: aws.String(s.getFirewallName())})
+		if isFirewallNotFound(err) {
+			out, err = s.createFirewall()
+		}
+	} else {
+		out, err = s.describeFirewall(&networkfirewall.DescribeFirewallInput{FirewallArn: aws.String(firewall.ARN)})
+	}
+	if err != nil {
+		return err
+	}
+
+	if vpcID := aws.StringValue(out.Firewall.VpcId); vpcID != s.scope.VPC().ID {
+		return errors.Errorf("network firewall %q is in VPC %q, not in the VPC %q of the cluster", aws.StringValue(out.Firewall.FirewallArn), vpcID, s.scope.VPC().ID)
+	}
+
+	endpoints := firewallEndpoints(out.FirewallStatus)
+	s.scope.Network().Firewall = &infrav1.NetworkFirewallStatus{
+		ARN:       aws.StringValue(out.Firewall.FirewallArn),
+		Endpoints: endpoints,
+	}
+
+	// Falling back to the NAT gateways would let the egress of a zone bypass the inspection, so the routes
+	// are only reconciled once the firewall is ready in every zone of the private subnets.
+	if zones := missingFirewallZones(s.scope.Subnets().FilterPrivate(), endpoints); len(zones) > 0 {
+		return awserrors.NewFailedDependency(
+			fmt.Sprintf("network firewall %q has no ready endpoint in availability zones %v", aws.StringValue(out.Firewall.FirewallArn), zones),
+		)
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.NetworkFirewallReadyCondition)
+	return nil
+}
+
+// reconcileFirewallSubnets makes sure the subnets holding the endpoints of a firewall created by the provider exist.
+func (s *Service) reconcileFirewallSubnets() error {
+	existing, err := s.describeVpcSubnets()
+	if err != nil {
+		return err
+	}
+
+	subnets := s.scope.Firewall().Subnets
+	for i := range subnets {
+		sub := &subnets[i]
+		if existingSubnet := existing.FindEqual(sub); existingSubnet != nil {
+			sub.ID = existingSubnet.ID
+			sub.AvailabilityZone = existingSubnet.AvailabilityZone
+			sub.AvailabilityZoneID = existingSubnet.AvailabilityZoneID
+			sub.RouteTableID = existingSubnet.RouteTableID
+			continue
+		}
+
+		sub.IsPublic = false
+		created, err := s.createFirewallSubnet(sub)
+		if err != nil {
+			return err
+		}
+		sub.ID = created.ID
+		sub.AvailabilityZone = created.AvailabilityZone
+		sub.AvailabilityZoneID = created.AvailabilityZoneID
+	}
+
+	return nil
+}
+
+func (s *Service) createFirewallSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, error) {
+	zone := sn.AvailabilityZone
+	if zone == "" {
+		zone = sn.AvailabilityZoneID
+	}
+
+	input := &ec2.CreateSubnetInput{
+		VpcId:     aws.String(s.scope.VPC().ID),
+		CidrBlock: aws.String(sn.CidrBlock),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(
+				ec2.ResourceTypeSubnet,
+				s.getFirewallSubnetTagParams(services.TemporaryResourceID, zone, sn.Tags),
+			),
+		},
+	}
+	if sn.AvailabilityZone != "" {
+		input.AvailabilityZone = aws.String(sn.AvailabilityZone)
+	} else {
+		input.AvailabilityZoneId = aws.String(sn.AvailabilityZoneID)
+	}
+
+	out, err := s.EC2Client.CreateSubnet(input)
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateSubnet", "Failed creating new managed firewall Subnet %v", err)
+		return nil, errors.Wrap(err, "failed to create firewall subnet")
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateSubnet", "Created new managed firewall Subnet %q", *out.Subnet.SubnetId)
+
+	wReq := &ec2.DescribeSubnetsInput{SubnetIds: []*string{out.Subnet.SubnetId}}
+	if err := s.EC2Client.WaitUntilSubnetAvailable(wReq); err != nil {
+		return nil, errors.Wrapf(err, "failed to wait for subnet %q", *out.Subnet.SubnetId)
+	}
+
+	s.scope.V(2).Info("Created new firewall subnet in VPC with cidr and availability zone ",
+		"subnet-id", *out.Subnet.SubnetId,
+		"vpc-id", *out.Subnet.VpcId,
+		"cidr-block", *out.Subnet.CidrBlock,
+		"availability-zone", *out.Subnet.AvailabilityZone)
+
+	return &infrav1.SubnetSpec{
+		ID:                 *out.Subnet.SubnetId,
+		AvailabilityZone:   *out.Subnet.AvailabilityZone,
+		AvailabilityZoneID: aws.StringValue(out.Subnet.AvailabilityZoneId),
+		CidrBlock:          *out.Subnet.CidrBlock,
+	}, nil
+}
+
+func (s *Service) createFirewall() (*networkfirewall.DescribeFirewallOutput, error) {
+	firewall := s.scope.Firewall()
+
+	mappings := make([]*networkfirewall.SubnetMapping, 0, len(firewall.Subnets))
+	for _, sn := range firewall.Subnets {
+		mappings = append(mappings, &networkfirewall.SubnetMapping{SubnetId: aws.String(sn.ID)})
+	}
+
+	params := infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(s.getFirewallName()),
+		Role:        aws.String(infrav1.FirewallRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+	firewallTags := []*networkfirewall.Tag{}
+	for k, v := range infrav1.Build(params) {
+		firewallTags = append(firewallTags, &networkfirewall.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	out, err := s.NetworkFirewallClient.CreateFirewall(&networkfirewall.CreateFirewallInput{
+		FirewallName:      aws.String(s.getFirewallName()),
+		FirewallPolicyArn: aws.String(firewall.PolicyARN),
+		VpcId:             aws.String(s.scope.VPC().ID),
+		SubnetMappings:    mappings,
+		Tags:              firewallTags,
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateNetworkFirewall", "Failed to create managed network firewall: %v", err)
+		return nil, errors.Wrap(err, "failed to create network firewall")
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateNetworkFirewall", "Created managed network firewall %q", aws.StringValue(out.Firewall.FirewallArn))
+
+	return &networkfirewall.DescribeFirewallOutput{
+		Firewall:       out.Firewall,
+		FirewallStatus: out.FirewallStatus,
+	}, nil
+}
+
+func (s *Service) describeFirewall(input *networkfirewall.DescribeFirewallInput) (*networkfirewall.DescribeFirewallOutput, error) {
+	out, err := s.NetworkFirewallClient.DescribeFirewall(input)
+	if err != nil {
+		if isFirewallNotFound(err) {
+			return nil, err
+		}
+		record.Warnf(s.scope.InfraCluster(), "FailedDescribeNetworkFirewall", "Failed to describe network firewall: %v", err)
+		return nil, errors.Wrap(err, "failed to describe network firewall")
+	}
+	return out, nil
+}
+
+func (s *Service) deleteFirewall() error {
+	if !s.scope.Firewall().IsManaged() {
+		s.scope.Network().Firewall = nil
+		return nil
+	}
+
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping network firewall deletion in unmanaged mode")
+		return nil
+	}
+
+	out, err := s.describeFirewall(&networkfirewall.DescribeFirewallInput{FirewallName: aws.String(s.getFirewallName())})
+	if isFirewallNotFound(err) {
+		s.scope.Network().Firewall = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if aws.StringValue(out.FirewallStatus.Status) != networkfirewall.FirewallStatusValueDeleting {
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if _, err := s.NetworkFirewallClient.DeleteFirewall(&networkfirewall.DeleteFirewallInput{FirewallArn: out.Firewall.FirewallArn}); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, networkfirewall.ErrCodeInvalidOperationException); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDeleteNetworkFirewall", "Failed to delete managed network firewall %q: %v", aws.StringValue(out.Firewall.FirewallArn), err)
+			return errors.Wrapf(err, "failed to delete network firewall %q", aws.StringValue(out.Firewall.FirewallArn))
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteNetworkFirewall", "Deleted managed network firewall %q", aws.StringValue(out.Firewall.FirewallArn))
+	}
+
+	// The endpoints of the firewall keep their subnets in use until the firewall is gone.
+	return awserrors.NewFailedDependency(fmt.Sprintf("waiting for network firewall %q to be deleted", aws.StringValue(out.Firewall.FirewallArn)))
+}
+
+// getFirewallEndpointForSubnet returns the ID of the firewall endpoint in the zone of a private subnet, if any.
+func (s *Service) getFirewallEndpointForSubnet(sn *infrav1.SubnetSpec) string {
+	if sn.IsPublic || s.scope.Network().Firewall == nil {
+		return ""
+	}
+	return s.scope.Network().Firewall.Endpoints[sn.AvailabilityZone]
+}
+
+// getFirewallReturnRoutes returns the routes sending the traffic to the private subnets in the zone of a public
+// subnet back through the firewall, so that the inspection sees both directions of the connections.
+func (s *Service) getFirewallReturnRoutes(sn *infrav1.SubnetSpec) []*ec2.Route {
+	if s.scope.Network().Firewall == nil {
+		return nil
+	}
+
+	endpointID := s.scope.Network().Firewall.Endpoints[sn.AvailabilityZone]
+	if endpointID == "" {
+		return nil
+	}
+
+	routes := []*ec2.Route{}
+	for _, private := range s.scope.Subnets().FilterPrivate().FilterByZone(sn.AvailabilityZone) {
+		routes = append(routes, &ec2.Route{
+			DestinationCidrBlock: aws.String(private.CidrBlock),
+			GatewayId:            aws.String(endpointID),
+		})
+	}
+	return routes
+}
+
+func (s *Service) getFirewallName() string {
+	// Firewall names only allow alphanumeric characters and hyphens.
+	return strings.ReplaceAll(s.scope.Name(), ".", "-") + "-firewall"
+}
+
+func (s *Service) getFirewallSubnetTagParams(id string, zone string, manualTags infrav1.Tags) infrav1.BuildParams {
+	additionalTags := s.scope.AdditionalTags()
+	for k, v := range manualTags {
+		additionalTags[k] = v
+	}
+
+	var name strings.Builder
+	name.WriteString(s.scope.Name())
+	name.WriteString("-subnet-")
+	name.WriteString(infrav1.FirewallRoleTagValue)
+	name.WriteString("-")
+	name.WriteString(zone)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name.String()),
+		Role:        aws.String(infrav1.FirewallRoleTagValue),
+		Additional:  additionalTags,
+	}
+}
+
+func isFirewallNotFound(err error) bool {
+	code, ok := awserrors.Code(err)
+	return ok && code == networkfirewall.ErrCodeResourceNotFoundException
+}
+
+// firewallEndpoints returns the IDs of the ready endpoints of a firewall by availability zone.
+func firewallEndpoints(status *networkfirewall.FirewallStatus) map[string]string {
+	endpoints := map[string]string{}
+	if status == nil {
+		return endpoints
+	}
+
+	for zone, state := range status.SyncStates {
+		if state == nil || state.Attachment == nil {
+			continue
+		}
+		if aws.StringValue(state.Attachment.Status) != networkfirewall.AttachmentStatusReady || state.Attachment.EndpointId == nil {
+			continue
+		}
+		endpoints[zone] = *state.Attachment.EndpointId
+	}
+	return endpoints
+}
+
+// missingFirewallZones returns the sorted availability zones of the given subnets without a firewall endpoint.
+func missingFirewallZones(subnets infrav1.Subnets, endpoints map[string]string) []string {
+	missing := map[string]struct{}{}
+	for _, sn := range subnets {
+		if _, ok := endpoints[sn.AvailabilityZone]; !ok {
+			missing[sn.AvailabilityZone] = struct{}{}
+		}
+	}
+
+	zones := make([]string, 0, len(missing))
+	for zone := range missing {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
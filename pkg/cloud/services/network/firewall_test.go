@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/networkfirewall"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+func TestFirewallEndpoints(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   *networkfirewall.FirewallStatus
+		expected map[string]string
+	}{
+		{
+			name:     "no status",
+			expected: map[string]string{},
+		},
+		{
+			name: "only ready attachments have endpoints",
+			status: &networkfirewall.FirewallStatus{
+				SyncStates: map[string]*networkfirewall.SyncState{
+					"us-east-1a": {
+						Attachment: &networkfirewall.Attachment{
+							EndpointId: aws.String("vpce-1a"),
+							Status:     aws.String(networkfirewall.AttachmentStatusReady),
+							SubnetId:   aws.String("subnet-firewall-1a"),
+						},
+					},
+					"us-east-1b": {
+						Attachment: &networkfirewall.Attachment{
+							Status:   aws.String(networkfirewall.AttachmentStatusCreating),
+							SubnetId: aws.String("subnet-firewall-1b"),
+						},
+					},
+					"us-east-1c": {},
+				},
+			},
+			expected: map[string]string{
+				"us-east-1a": "vpce-1a",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if endpoints := firewallEndpoints(tc.status); !reflect.DeepEqual(endpoints, tc.expected) {
+				t.Fatalf("Expected endpoints %v, got %v", tc.expected, endpoints)
+			}
+		})
+	}
+}
+
+func TestMissingFirewallZones(t *testing.T) {
+	subnets := infrav1.Subnets{
+		{ID: "subnet-1b", AvailabilityZone: "us-east-1b"},
+		{ID: "subnet-1a", AvailabilityZone: "us-east-1a"},
+		{ID: "subnet-1c", AvailabilityZone: "us-east-1c"},
+		{ID: "subnet-1c-2", AvailabilityZone: "us-east-1c"},
+	}
+
+	zones := missingFirewallZones(subnets, map[string]string{"us-east-1a": "vpce-1a"})
+	if expected := []string{"us-east-1b", "us-east-1c"}; !reflect.DeepEqual(zones, expected) {
+		t.Fatalf("Expected zones %v, got %v", expected, zones)
+	}
+}
+
+func TestRouteTargetMatches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		current  *ec2.Route
+		spec     *ec2.Route
+		expected bool
+	}{
+		{
+			name:     "same NAT gateway",
+			current:  &ec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-01")},
+			spec:     &ec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-01")},
+			expected: true,
+		},
+		{
+			name:     "NAT gateway replaced by a firewall endpoint",
+			current:  &ec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-01")},
+			spec:     &ec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("vpce-01")},
+			expected: false,
+		},
+		{
+			name:     "firewall endpoint replaced by a NAT gateway",
+			current:  &ec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("vpce-01")},
+			spec:     &ec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-01")},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if matches := routeTargetMatches(tc.current, tc.spec); matches != tc.expected {
+				t.Fatalf("Expected match to be %v, got %v", tc.expected, matches)
+			}
+		})
+	}
+}
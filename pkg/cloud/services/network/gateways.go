@@ -58,6 +58,7 @@ func (s *Service) reconcileInternetGateways() error {
 
 	gateway := igs[0]
 	s.scope.VPC().InternetGatewayID = gateway.InternetGatewayId
+	s.scope.Network().InternetGatewayID = aws.StringValue(gateway.InternetGatewayId)
 
 	// Make sure tags are up to date.
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
@@ -37,9 +37,10 @@ func TestReconcileInternetGateways(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	testCases := []struct {
-		name   string
-		input  *infrav1.NetworkSpec
-		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		name     string
+		input    *infrav1.NetworkSpec
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectID string
 	}{
 		{
 			name: "has igw",
@@ -70,6 +71,7 @@ func TestReconcileInternetGateways(t *testing.T) {
 				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
 					Return(nil, nil)
 			},
+			expectID: "igw-0",
 		},
 		{
 			name: "no igw attached, creates one",
@@ -112,6 +114,7 @@ func TestReconcileInternetGateways(t *testing.T) {
 				})).
 					Return(&ec2.AttachInternetGatewayOutput{}, nil)
 			},
+			expectID: "igw-1",
 		},
 	}
 
@@ -146,6 +149,9 @@ func TestReconcileInternetGateways(t *testing.T) {
 			if err := s.reconcileInternetGateways(); err != nil {
 				t.Fatalf("got an unexpected error: %v", err)
 			}
+			if id := scope.Network().InternetGatewayID; id != tc.expectID {
+				t.Fatalf("expected internet gateway %q in status, got %q", tc.expectID, id)
+			}
 		})
 	}
 }
@@ -18,6 +18,7 @@ package network
 
 import (
 	"fmt"
+	"sort"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -45,6 +46,7 @@ func (s *Service) reconcileNatGateways() error {
 
 	if len(s.scope.Subnets().FilterPrivate()) == 0 {
 		s.scope.V(2).Info("No private subnets available, skipping NAT gateways")
+		s.scope.Network().NatGateways = nil
 		conditions.MarkFalse(
 			s.scope.InfraCluster(),
 			infrav1.NatGatewaysReadyCondition,
@@ -54,6 +56,7 @@ func (s *Service) reconcileNatGateways() error {
 		return nil
 	} else if len(s.scope.Subnets().FilterPublic()) == 0 {
 		s.scope.V(2).Info("No public subnets available. Cannot create NAT gateways for private subnets, this might be a configuration error.")
+		s.scope.Network().NatGateways = nil
 		conditions.MarkFalse(
 			s.scope.InfraCluster(),
 			infrav1.NatGatewaysReadyCondition,
@@ -69,6 +72,7 @@ func (s *Service) reconcileNatGateways() error {
 	}
 
 	subnetIDs := []string{}
+	natGateways := []infrav1.NatGateway{}
 
 	for _, sn := range s.scope.Subnets().FilterPublic() {
 		if sn.ID == "" {
@@ -76,6 +80,14 @@ func (s *Service) reconcileNatGateways() error {
 		}
 
 		if ngw, ok := existing[sn.ID]; ok {
+			status := natGatewayStatus(ngw)
+			natGateways = append(natGateways, status)
+
+			// The Elastic IP of a NAT gateway can't be changed: the NAT gateway has to be deleted to be recreated with it.
+			if want := s.scope.NatGatewayAllocationIDs()[sn.AvailabilityZone]; want != "" && status.AllocationID != "" && want != status.AllocationID {
+				record.Warnf(s.scope.InfraCluster(), "NATGatewayAllocationIDMismatch", "NAT Gateway %q uses Elastic IP %q instead of %q, delete it to recreate it with the configured Elastic IP", status.ID, status.AllocationID, want)
+			}
+
 			// Make sure tags are up to date.
 			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
 				buildParams := s.getNatGatewayTagParams(*ngw.NatGatewayId)
@@ -109,17 +121,98 @@ func (s *Service) reconcileNatGateways() error {
 		for _, ng := range ngws {
 			subnet := s.scope.Subnets().FindByID(*ng.SubnetId)
 			subnet.NatGatewayID = ng.NatGatewayId
+			natGateways = append(natGateways, natGatewayStatus(ng))
 		}
+		s.setNatGatewaysStatus(natGateways)
 
 		if err != nil {
 			return err
 		}
 		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition)
+		return nil
 	}
 
+	s.setNatGatewaysStatus(natGateways)
 	return nil
 }
 
+func (s *Service) setNatGatewaysStatus(natGateways []infrav1.NatGateway) {
+	sort.Slice(natGateways, func(i, j int) bool {
+		return natGateways[i].SubnetID < natGateways[j].SubnetID
+	})
+	s.scope.Network().NatGateways = natGateways
+}
+
+// natGatewayStatus returns the status of a NAT gateway, with the elastic IP address it uses.
+func natGatewayStatus(ngw *ec2.NatGateway) infrav1.NatGateway {
+	status := infrav1.NatGateway{
+		ID:       aws.StringValue(ngw.NatGatewayId),
+		SubnetID: aws.StringValue(ngw.SubnetId),
+	}
+	if len(ngw.NatGatewayAddresses) > 0 {
+		status.AllocationID = aws.StringValue(ngw.NatGatewayAddresses[0].AllocationId)
+		status.PublicIP = aws.StringValue(ngw.NatGatewayAddresses[0].PublicIp)
+	}
+	return status
+}
+
+// PlannedNatGateways returns the number of NAT gateways, each taking an elastic IP, that reconciling the
+// network creates: one for every public subnet of a managed VPC, or one for every availability zone the
+// VPC uses when its subnets are left to the defaults.
+func (s *Service) PlannedNatGateways() (int, error) {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		return 0, nil
+	}
+
+	if subnets := s.scope.Subnets(); len(subnets) > 0 {
+		if len(subnets.FilterPrivate()) == 0 {
+			return 0, nil
+		}
+		return len(subnets.FilterPublic()), nil
+	}
+
+	zones, err := s.getAvailableZones()
+	if err != nil {
+		return 0, err
+	}
+	maxZones := defaultMaxNumAZs
+	if s.scope.VPC().AvailabilityZoneUsageLimit != nil {
+		maxZones = *s.scope.VPC().AvailabilityZoneUsageLimit
+	}
+	if len(zones) > maxZones {
+		return maxZones, nil
+	}
+	return len(zones), nil
+}
+
+// PlannedElasticIPs returns the number of elastic IPs that reconciling the network allocates for the NAT gateways:
+// none with an Elastic IP pool, and none for the availability zones whose Elastic IP is set.
+func (s *Service) PlannedElasticIPs() (int, error) {
+	if s.scope.ElasticIPPool() != nil {
+		return 0, nil
+	}
+
+	natGateways, err := s.PlannedNatGateways()
+	if err != nil || natGateways == 0 {
+		return natGateways, err
+	}
+
+	configured := s.scope.NatGatewayAllocationIDs()
+	if subnets := s.scope.Subnets(); len(subnets) > 0 {
+		for _, sn := range subnets.FilterPublic() {
+			if configured[sn.AvailabilityZone] != "" {
+				natGateways--
+			}
+		}
+		return natGateways, nil
+	}
+
+	if natGateways < len(configured) {
+		return 0, nil
+	}
+	return natGateways - len(configured), nil
+}
+
 func (s *Service) deleteNatGateways() error {
 	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 		s.scope.V(4).Info("Skipping NAT gateway deletion in unmanaged mode")
@@ -194,7 +287,7 @@ func (s *Service) getNatGatewayTagParams(id string) infrav1.BuildParams {
 }
 
 func (s *Service) createNatGateways(subnetIDs []string) (natgateways []*ec2.NatGateway, err error) {
-	eips, err := s.getOrAllocateAddresses(len(subnetIDs), infrav1.APIServerRoleTagValue)
+	eips, err := s.natGatewayAddresses(subnetIDs)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create one or more IP addresses for NAT gateways")
 	}
@@ -221,6 +314,35 @@ func (s *Service) createNatGateways(subnetIDs []string) (natgateways []*ec2.NatG
 	return natgateways, nil
 }
 
+// natGatewayAddresses returns the allocation IDs of the Elastic IPs of the NAT gateways of the given subnets: those
+// set for the availability zones of the subnets, then Elastic IPs of the pool or new ones.
+func (s *Service) natGatewayAddresses(subnetIDs []string) ([]string, error) {
+	configured := s.scope.NatGatewayAllocationIDs()
+	eips := make([]string, len(subnetIDs))
+	missing := 0
+	for i, subnetID := range subnetIDs {
+		if sn := s.scope.Subnets().FindByID(subnetID); sn != nil && configured[sn.AvailabilityZone] != "" {
+			eips[i] = configured[sn.AvailabilityZone]
+			continue
+		}
+		missing++
+	}
+	if missing == 0 {
+		return eips, nil
+	}
+
+	allocated, err := s.getOrAllocateAddresses(missing, infrav1.APIServerRoleTagValue)
+	if err != nil {
+		return nil, err
+	}
+	for i := range eips {
+		if eips[i] == "" {
+			eips[i], allocated = allocated[0], allocated[1:]
+		}
+	}
+	return eips, nil
+}
+
 func (s *Service) createNatGateway(subnetID, ip string) (*ec2.NatGateway, error) {
 	var out *ec2.CreateNatGatewayOutput
 	var err error
@@ -305,12 +427,11 @@ func (s *Service) getNatGatewayForSubnet(sn *infrav1.SubnetSpec) (string, error)
 			continue
 		}
 
+		if psn.IsInSameZone(sn) {
+			return *psn.NatGatewayID, nil
+		}
 		azGateways[psn.AvailabilityZone] = append(azGateways[psn.AvailabilityZone], *psn.NatGatewayID)
 	}
 
-	if gws, ok := azGateways[sn.AvailabilityZone]; ok && len(gws) > 0 {
-		return gws[0], nil
-	}
-
 	return "", errors.Errorf("no nat gateways available in %q for private subnet %q, current state: %+v", sn.AvailabilityZone, sn.ID, azGateways)
 }
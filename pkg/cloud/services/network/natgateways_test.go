@@ -18,6 +18,7 @@ package network
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -41,9 +42,10 @@ func TestReconcileNatGateways(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	testCases := []struct {
-		name   string
-		input  []infrav1.SubnetSpec
-		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		name              string
+		input             []infrav1.SubnetSpec
+		expect            func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectNatGateways []infrav1.NatGateway
 	}{
 		{
 			name: "single private subnet exists, should create no NAT gateway",
@@ -313,6 +315,10 @@ func TestReconcileNatGateways(t *testing.T) {
 					funct(&ec2.DescribeNatGatewaysOutput{NatGateways: []*ec2.NatGateway{{
 						NatGatewayId: aws.String("gateway"),
 						SubnetId:     aws.String("subnet-1"),
+						NatGatewayAddresses: []*ec2.NatGatewayAddress{{
+							AllocationId: aws.String(ElasticIPAllocationID),
+							PublicIp:     aws.String("203.0.113.10"),
+						}},
 						Tags: []*ec2.Tag{
 							{
 								Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
@@ -334,6 +340,12 @@ func TestReconcileNatGateways(t *testing.T) {
 				m.AllocateAddress(gomock.Any()).Times(0)
 				m.CreateNatGateway(gomock.Any()).Times(0)
 			},
+			expectNatGateways: []infrav1.NatGateway{{
+				ID:           "gateway",
+				SubnetID:     "subnet-1",
+				AllocationID: ElasticIPAllocationID,
+				PublicIP:     "203.0.113.10",
+			}},
 		},
 		{
 			name: "public & private subnet declared, but don't exist yet",
@@ -400,6 +412,72 @@ func TestReconcileNatGateways(t *testing.T) {
 			if err := s.reconcileNatGateways(); err != nil {
 				t.Fatalf("got an unexpected error: %v", err)
 			}
+			if tc.expectNatGateways != nil && !reflect.DeepEqual(clusterScope.Network().NatGateways, tc.expectNatGateways) {
+				t.Fatalf("expected NAT gateways %v in status, got %v", tc.expectNatGateways, clusterScope.Network().NatGateways)
+			}
 		})
 	}
 }
+
+func TestNatGatewayAddresses(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{ID: "subnet-1", AvailabilityZone: "us-east-1a", IsPublic: true},
+						{ID: "subnet-2", AvailabilityZone: "us-east-1b", IsPublic: true},
+						{ID: "subnet-3", AvailabilityZone: "us-east-1c", IsPublic: true},
+						{ID: "subnet-4", AvailabilityZone: "us-east-1a", IsPublic: false},
+					},
+					NatGatewayAllocationIDs: map[string]string{
+						"us-east-1b": "eipalloc-configured",
+					},
+				},
+			},
+		},
+		Client: client,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	ec2Mock.EXPECT().DescribeAddresses(gomock.Any()).
+		Return(&ec2.DescribeAddressesOutput{
+			Addresses: []*ec2.Address{
+				{AllocationId: aws.String("eipalloc-existing-1")},
+				{AllocationId: aws.String("eipalloc-existing-2")},
+			},
+		}, nil)
+	ec2Mock.EXPECT().AllocateAddress(gomock.Any()).Times(0)
+
+	s := NewService(clusterScope)
+	s.EC2Client = ec2Mock
+
+	eips, err := s.natGatewayAddresses([]string{"subnet-1", "subnet-2", "subnet-3"})
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	expected := []string{"eipalloc-existing-1", "eipalloc-configured", "eipalloc-existing-2"}
+	if !reflect.DeepEqual(eips, expected) {
+		t.Fatalf("expected Elastic IPs %v, got %v", expected, eips)
+	}
+
+	planned, err := s.PlannedElasticIPs()
+	if err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+	if planned != 2 {
+		t.Fatalf("expected 2 planned Elastic IPs, got %d", planned)
+	}
+}
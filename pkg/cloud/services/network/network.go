@@ -17,6 +17,7 @@ limitations under the License.
 package network
 
 import (
+	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -29,38 +30,50 @@ func (s *Service) ReconcileNetwork() (err error) {
 
 	// VPC.
 	if err := s.reconcileVPC(); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VpcReadyCondition, infrav1.VpcReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VpcReadyCondition, awserrors.ConditionReason(err, infrav1.VpcReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.VpcReadyCondition)
 
 	// Secondary CIDR
 	if err := s.associateSecondaryCidr(); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SecondaryCidrsReadyCondition, infrav1.SecondaryCidrReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SecondaryCidrsReadyCondition, awserrors.ConditionReason(err, infrav1.SecondaryCidrReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 
 	// Subnets.
 	if err := s.reconcileSubnets(); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SubnetsReadyCondition, infrav1.SubnetsReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SubnetsReadyCondition, awserrors.ConditionReason(err, infrav1.SubnetsReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 
 	// Internet Gateways.
 	if err := s.reconcileInternetGateways(); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InternetGatewayReadyCondition, infrav1.InternetGatewayFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InternetGatewayReadyCondition, awserrors.ConditionReason(err, infrav1.InternetGatewayFailedReason), clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	// Egress-only Internet Gateways.
+	if err := s.reconcileEgressOnlyInternetGateways(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, awserrors.ConditionReason(err, infrav1.EgressOnlyInternetGatewayFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 
 	// NAT Gateways.
 	if err := s.reconcileNatGateways(); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition, infrav1.NatGatewaysReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition, awserrors.ConditionReason(err, infrav1.NatGatewaysReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	// Network Firewall.
+	if err := s.reconcileFirewall(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NetworkFirewallReadyCondition, awserrors.ConditionReason(err, infrav1.NetworkFirewallReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 
 	// Routing tables.
 	if err := s.reconcileRouteTables(); err != nil {
-		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition, infrav1.RouteTableReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition, awserrors.ConditionReason(err, infrav1.RouteTableReconciliationFailedReason), clusterv1.ConditionSeverityError, err.Error())
 		return err
 	}
 
@@ -68,6 +81,40 @@ func (s *Service) ReconcileNetwork() (err error) {
 	return nil
 }
 
+// DiscoverNetwork populates the VPC and subnets of an externally managed cluster from
+// the existing AWS resources, without creating or modifying any of them.
+// When no VPC ID is set, the VPC tagged for the cluster is used.
+// Subnets are only discovered when none are set in the spec.
+func (s *Service) DiscoverNetwork() error {
+	s.scope.V(2).Info("Discovering network for externally managed cluster", "cluster-name", s.scope.Name(), "cluster-namespace", s.scope.Namespace())
+
+	if s.scope.VPC().ID == "" {
+		vpc, err := s.describeClusterVPC()
+		if err != nil {
+			return err
+		}
+		s.scope.VPC().ID = vpc.ID
+	}
+
+	vpc, err := s.describeVPCByID()
+	if err != nil {
+		return errors.Wrapf(err, "failed to discover VPC %q", s.scope.VPC().ID)
+	}
+	s.scope.VPC().CidrBlock = vpc.CidrBlock
+	s.scope.VPC().Tags = vpc.Tags
+
+	if len(s.scope.Subnets()) == 0 {
+		subnets, err := s.describeVpcSubnets()
+		if err != nil {
+			return err
+		}
+		s.scope.SetSubnets(subnets)
+	}
+
+	s.scope.V(2).Info("Discover network completed successfully", "vpc-id", s.scope.VPC().ID, "subnets", len(s.scope.Subnets()))
+	return nil
+}
+
 // DeleteNetwork deletes the network of the given cluster.
 func (s *Service) DeleteNetwork() (err error) {
 	s.scope.V(2).Info("Deleting network")
@@ -107,6 +154,7 @@ func (s *Service) DeleteNetwork() (err error) {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
 		return err
 	}
+	s.scope.Network().RouteTables = nil
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 
 	// NAT Gateways.
@@ -119,6 +167,7 @@ func (s *Service) DeleteNetwork() (err error) {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
 		return err
 	}
+	s.scope.Network().NatGateways = nil
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 
 	// EIPs.
@@ -136,8 +185,37 @@ func (s *Service) DeleteNetwork() (err error) {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InternetGatewayReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
 		return err
 	}
+	s.scope.Network().InternetGatewayID = ""
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InternetGatewayReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 
+	// Egress-only Internet Gateways.
+	if s.scope.VPC().IsIPv6Enabled() {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+		if err := s.scope.PatchObject(); err != nil {
+			return err
+		}
+
+		if err := s.deleteEgressOnlyInternetGateways(); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
+	// Network Firewall.
+	if s.scope.Firewall() != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NetworkFirewallReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+		if err := s.scope.PatchObject(); err != nil {
+			return err
+		}
+
+		if err := s.deleteFirewall(); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NetworkFirewallReadyCondition, awserrors.ConditionReason(err, "DeletingFailed"), clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NetworkFirewallReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
 	// Subnets.
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SubnetsReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
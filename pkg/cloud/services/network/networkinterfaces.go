@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// cniNetworkInterfaceDescriptionPrefix prefixes the description of the network interfaces created by the
+	// Amazon VPC CNI plugin, followed by the ID of their instance.
+	cniNetworkInterfaceDescriptionPrefix = "aws-K8S-"
+
+	// cniNetworkInterfaceInstanceTagKey is the tag the Amazon VPC CNI plugin sets on its network interfaces to the ID
+	// of their instance.
+	cniNetworkInterfaceInstanceTagKey = "node.k8s.amazonaws.com/instance_id"
+)
+
+// networkInterfaceTypesDeletedWithNetwork are the types of the network interfaces of the NAT gateways and the
+// network firewall endpoints, which are deleted with the network itself. NAT gateway interfaces have been reported
+// as both nat_gateway and natGateway.
+var networkInterfaceTypesDeletedWithNetwork = map[string]bool{
+	"nat_gateway":                      true,
+	ec2.NetworkInterfaceTypeNatGateway: true,
+	"gateway_load_balancer_endpoint":   true,
+}
+
+// DependentNetworkInterfaces describes the network interfaces in the managed VPC of the cluster which would make
+// the deletion of its security groups and subnets fail, such as those of instances and load balancers which are
+// still being deleted or those left behind by other software. Nothing is returned for unmanaged VPCs, which are
+// not deleted.
+func (s *Service) DependentNetworkInterfaces() ([]string, error) {
+	if s.scope.VPC().ID == "" || s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		return nil, nil
+	}
+
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+		},
+	}
+
+	var interfaces []string
+	if err := s.EC2Client.DescribeNetworkInterfacesPages(input, func(out *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+		for _, ni := range out.NetworkInterfaces {
+			if networkInterfaceTypesDeletedWithNetwork[aws.StringValue(ni.InterfaceType)] {
+				continue
+			}
+			description := aws.StringValue(ni.NetworkInterfaceId)
+			if ni.Description != nil && *ni.Description != "" {
+				description = fmt.Sprintf("%s (%s)", description, *ni.Description)
+			}
+			interfaces = append(interfaces, description)
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe the network interfaces of VPC %q", s.scope.VPC().ID)
+	}
+
+	return interfaces, nil
+}
+
+// DeleteCNINetworkInterfaces deletes the network interfaces the Amazon VPC CNI plugin left behind in the managed VPC
+// of the cluster, which would make the deletion of its subnets fail. The interfaces still attached to instances which
+// are not running are detached first, and deleted once they are available, so they may take more than one call to
+// delete. Nothing is deleted in unmanaged VPCs.
+func (s *Service) DeleteCNINetworkInterfaces() error {
+	if s.scope.VPC().ID == "" || s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		return nil
+	}
+
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+		},
+	}
+
+	var interfaces []*ec2.NetworkInterface
+	if err := s.EC2Client.DescribeNetworkInterfacesPages(input, func(out *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+		for _, ni := range out.NetworkInterfaces {
+			if isCNINetworkInterface(ni) {
+				interfaces = append(interfaces, ni)
+			}
+		}
+		return true
+	}); err != nil {
+		return errors.Wrapf(err, "failed to describe the network interfaces of VPC %q", s.scope.VPC().ID)
+	}
+
+	running, err := s.runningInstances(interfaces)
+	if err != nil {
+		return err
+	}
+
+	for _, ni := range interfaces {
+		id := aws.StringValue(ni.NetworkInterfaceId)
+
+		switch aws.StringValue(ni.Status) {
+		case ec2.NetworkInterfaceStatusAvailable:
+			if _, err := s.EC2Client.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: ni.NetworkInterfaceId}); err != nil {
+				if awserrors.IsNotFound(err) {
+					continue
+				}
+				record.Warnf(s.scope.InfraCluster(), "FailedDeleteNetworkInterface", "Failed to delete CNI network interface %q: %v", id, err)
+				return errors.Wrapf(err, "failed to delete CNI network interface %q", id)
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteNetworkInterface", "Deleted CNI network interface %q", id)
+			s.scope.Info("Deleted CNI network interface", "network-interface-id", id)
+
+		case ec2.NetworkInterfaceStatusInUse:
+			attachment := ni.Attachment
+			if attachment == nil || attachment.AttachmentId == nil || running[aws.StringValue(attachment.InstanceId)] {
+				continue
+			}
+			if _, err := s.EC2Client.DetachNetworkInterface(&ec2.DetachNetworkInterfaceInput{
+				AttachmentId: attachment.AttachmentId,
+				Force:        aws.Bool(true),
+			}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedDetachNetworkInterface", "Failed to detach CNI network interface %q: %v", id, err)
+				return errors.Wrapf(err, "failed to detach CNI network interface %q", id)
+			}
+			s.scope.Info("Detached CNI network interface", "network-interface-id", id, "instance-id", aws.StringValue(attachment.InstanceId))
+		}
+	}
+
+	return nil
+}
+
+// runningInstances returns the IDs of the pending and running instances the network interfaces are attached to.
+func (s *Service) runningInstances(interfaces []*ec2.NetworkInterface) (map[string]bool, error) {
+	var ids []*string
+	for _, ni := range interfaces {
+		if ni.Attachment != nil && ni.Attachment.InstanceId != nil {
+			ids = append(ids, ni.Attachment.InstanceId)
+		}
+	}
+
+	running := map[string]bool{}
+	if len(ids) == 0 {
+		return running, nil
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		InstanceIds: ids,
+		Filters: []*ec2.Filter{
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning),
+		},
+	}
+	if err := s.EC2Client.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				running[aws.StringValue(instance.InstanceId)] = true
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to describe the instances of the CNI network interfaces")
+	}
+
+	return running, nil
+}
+
+// isCNINetworkInterface returns true if the network interface was created by the Amazon VPC CNI plugin.
+func isCNINetworkInterface(ni *ec2.NetworkInterface) bool {
+	if strings.HasPrefix(aws.StringValue(ni.Description), cniNetworkInterfaceDescriptionPrefix) {
+		return true
+	}
+	for _, tag := range ni.TagSet {
+		if aws.StringValue(tag.Key) == cniNetworkInterfaceInstanceTagKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDependentNetworkInterfaces(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	managedVPC := infrav1.VPCSpec{
+		ID: "vpc-managed",
+		Tags: infrav1.Tags{
+			infrav1.ClusterTagKey("test-cluster"): "owned",
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		vpc      infrav1.VPCSpec
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expected []string
+	}{
+		{
+			name: "lists the interfaces which aren't deleted with the network",
+			vpc:  managedVPC,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkInterfacesPages(gomock.Eq(&ec2.DescribeNetworkInterfacesInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("vpc-id"),
+							Values: aws.StringSlice([]string{"vpc-managed"}),
+						},
+					},
+				}), gomock.Any()).
+					DoAndReturn(func(_ *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool) error {
+						fn(&ec2.DescribeNetworkInterfacesOutput{
+							NetworkInterfaces: []*ec2.NetworkInterface{
+								{NetworkInterfaceId: aws.String("eni-nat"), InterfaceType: aws.String("nat_gateway")},
+								{NetworkInterfaceId: aws.String("eni-firewall"), InterfaceType: aws.String("gateway_load_balancer_endpoint")},
+								{NetworkInterfaceId: aws.String("eni-cni"), InterfaceType: aws.String("interface"), Description: aws.String("aws-K8S-i-0123")},
+								{NetworkInterfaceId: aws.String("eni-other"), InterfaceType: aws.String("interface")},
+							},
+						}, true)
+						return nil
+					})
+			},
+			expected: []string{"eni-cni (aws-K8S-i-0123)", "eni-other"},
+		},
+		{
+			name: "ignores unmanaged VPCs",
+			vpc:  infrav1.VPCSpec{ID: "vpc-unmanaged"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{VPC: tc.vpc},
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tc.expect != nil {
+				tc.expect(ec2Mock.EXPECT())
+			}
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			interfaces, err := s.DependentNetworkInterfaces()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(interfaces).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestDeleteCNINetworkInterfaces(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					VPC: infrav1.VPCSpec{
+						ID: "vpc-managed",
+						Tags: infrav1.Tags{
+							infrav1.ClusterTagKey("test-cluster"): "owned",
+						},
+					},
+				},
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	m := ec2Mock.EXPECT()
+	m.DescribeNetworkInterfacesPages(gomock.AssignableToTypeOf(&ec2.DescribeNetworkInterfacesInput{}), gomock.Any()).
+		DoAndReturn(func(_ *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool) error {
+			fn(&ec2.DescribeNetworkInterfacesOutput{
+				NetworkInterfaces: []*ec2.NetworkInterface{
+					{
+						NetworkInterfaceId: aws.String("eni-available"),
+						Description:        aws.String("aws-K8S-i-terminated"),
+						Status:             aws.String(ec2.NetworkInterfaceStatusAvailable),
+					},
+					{
+						NetworkInterfaceId: aws.String("eni-stopped"),
+						Status:             aws.String(ec2.NetworkInterfaceStatusInUse),
+						TagSet: []*ec2.Tag{
+							{Key: aws.String("node.k8s.amazonaws.com/instance_id"), Value: aws.String("i-stopped")},
+						},
+						Attachment: &ec2.NetworkInterfaceAttachment{
+							AttachmentId: aws.String("eni-attach-stopped"),
+							InstanceId:   aws.String("i-stopped"),
+						},
+					},
+					{
+						NetworkInterfaceId: aws.String("eni-running"),
+						Description:        aws.String("aws-K8S-i-running"),
+						Status:             aws.String(ec2.NetworkInterfaceStatusInUse),
+						Attachment: &ec2.NetworkInterfaceAttachment{
+							AttachmentId: aws.String("eni-attach-running"),
+							InstanceId:   aws.String("i-running"),
+						},
+					},
+					{
+						NetworkInterfaceId: aws.String("eni-other"),
+						Description:        aws.String("ELB test-apiserver"),
+						Status:             aws.String(ec2.NetworkInterfaceStatusAvailable),
+					},
+				},
+			}, true)
+			return nil
+		})
+	m.DescribeInstancesPages(gomock.AssignableToTypeOf(&ec2.DescribeInstancesInput{}), gomock.Any()).
+		DoAndReturn(func(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+			g.Expect(aws.StringValueSlice(input.InstanceIds)).To(ConsistOf("i-stopped", "i-running"))
+			fn(&ec2.DescribeInstancesOutput{
+				Reservations: []*ec2.Reservation{
+					{Instances: []*ec2.Instance{{InstanceId: aws.String("i-running")}}},
+				},
+			}, true)
+			return nil
+		})
+	m.DeleteNetworkInterface(gomock.Eq(&ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: aws.String("eni-available"),
+	})).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+	m.DetachNetworkInterface(gomock.Eq(&ec2.DetachNetworkInterfaceInput{
+		AttachmentId: aws.String("eni-attach-stopped"),
+		Force:        aws.Bool(true),
+	})).Return(&ec2.DetachNetworkInterfaceOutput{}, nil)
+
+	s := NewService(scope)
+	s.EC2Client = ec2Mock
+
+	g.Expect(s.DeleteCNINetworkInterfaces()).To(Succeed())
+}
@@ -38,13 +38,27 @@ const (
 )
 
 func (s *Service) reconcileRouteTables() error {
+	routeTables := map[string]string{}
+
 	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
 		s.scope.V(4).Info("Skipping routing tables reconcile in unmanaged mode")
+
+		// The subnets of an unmanaged VPC may still need to be associated with existing route tables.
+		if err := s.reconcileUnmanagedRouteTables(routeTables); err != nil {
+			return err
+		}
+		if len(routeTables) > 0 {
+			s.scope.Network().RouteTables = routeTables
+		}
 		return nil
 	}
 
 	s.scope.V(2).Info("Reconciling routing tables")
 
+	if err := s.reconcileUnmanagedRouteTables(routeTables); err != nil {
+		return err
+	}
+
 	subnetRouteMap, err := s.describeVpcRouteTablesBySubnet()
 	if err != nil {
 		return err
@@ -53,6 +67,9 @@ func (s *Service) reconcileRouteTables() error {
 	subnets := s.scope.Subnets()
 	for i := range subnets {
 		sn := subnets[i]
+		if sn.UnmanagedRouteTableID != "" {
+			continue
+		}
 		// We need to compile the minimum routes for this subnet first, so we can compare it or create them.
 		var routes []*ec2.Route
 		if sn.IsPublic {
@@ -60,6 +77,9 @@ func (s *Service) reconcileRouteTables() error {
 				return errors.Errorf("failed to create routing tables: internet gateway for %q is nil", s.scope.VPC().ID)
 			}
 			routes = append(routes, s.getGatewayPublicRoute())
+			routes = append(routes, s.getFirewallReturnRoutes(&sn)...)
+		} else if endpointID := s.getFirewallEndpointForSubnet(&sn); endpointID != "" {
+			routes = append(routes, s.getFirewallPrivateRoute(endpointID))
 		} else {
 			natGatewayID, err := s.getNatGatewayForSubnet(&sn)
 			if err != nil {
@@ -67,79 +87,243 @@ func (s *Service) reconcileRouteTables() error {
 			}
 			routes = append(routes, s.getNatGatewayPrivateRoute(natGatewayID))
 		}
-
-		if rt, ok := subnetRouteMap[sn.ID]; ok {
-			s.scope.V(2).Info("Subnet is already associated with route table", "subnet-id", sn.ID, "route-table-id", *rt.RouteTableId)
-			// TODO(vincepri): check that everything is in order, e.g. routes match the subnet type.
-
-			// For managed environments we need to reconcile the routes of our tables if there is a mistmatch.
-			// For example, a gateway can be deleted and our controller will re-create it, then we replace the route
-			// for the subnet to allow traffic to flow.
-			for _, currentRoute := range rt.Routes {
-				for i := range routes {
-					// Routes destination cidr blocks must be unique within a routing table.
-					// If there is a mistmatch, we replace the routing association.
-					specRoute := routes[i]
-					if *currentRoute.DestinationCidrBlock == *specRoute.DestinationCidrBlock &&
-						((currentRoute.GatewayId != nil && *currentRoute.GatewayId != *specRoute.GatewayId) ||
-							(currentRoute.NatGatewayId != nil && *currentRoute.NatGatewayId != *specRoute.NatGatewayId)) {
-						if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-							if _, err := s.EC2Client.ReplaceRoute(&ec2.ReplaceRouteInput{
-								RouteTableId:         rt.RouteTableId,
-								DestinationCidrBlock: specRoute.DestinationCidrBlock,
-								GatewayId:            specRoute.GatewayId,
-								NatGatewayId:         specRoute.NatGatewayId,
-							}); err != nil {
-								return false, err
-							}
-							return true, nil
-						}); err != nil {
-							record.Warnf(s.scope.InfraCluster(), "FailedReplaceRoute", "Failed to replace outdated route on managed RouteTable %q: %v", *rt.RouteTableId, err)
-							return errors.Wrapf(err, "failed to replace outdated route on route table %q", *rt.RouteTableId)
-						}
-					}
+		if s.scope.VPC().IsIPv6Enabled() {
+			if sn.IsPublic {
+				routes = append(routes, s.getGatewayPublicIPv6Route())
+			} else {
+				if s.scope.VPC().IPv6.EgressOnlyInternetGatewayID == nil {
+					return errors.Errorf("failed to create routing tables: egress-only internet gateway for %q is nil", s.scope.VPC().ID)
 				}
+				routes = append(routes, s.getEgressOnlyGatewayPrivateRoute())
 			}
+		}
+		routes = mergeRoutes(routes, s.getGatewayLoadBalancerEndpointRoutes(&sn))
+		routes = mergeRoutes(routes, getStaticRoutes(s.scope.Routes()))
+		routes = mergeRoutes(routes, getStaticRoutes(sn.Routes))
 
-			// Make sure tags are up to date.
-			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-				buildParams := s.getRouteTableTagParams(*rt.RouteTableId, sn.IsPublic, sn.AvailabilityZone)
-				tagsBuilder := tags.New(&buildParams, tags.WithEC2(s.EC2Client))
-				if err := tagsBuilder.Ensure(converters.TagsToMap(rt.Tags)); err != nil {
-					return false, err
-				}
-				return true, nil
-			}, awserrors.RouteTableNotFound); err != nil {
-				record.Warnf(s.scope.InfraCluster(), "FailedTagRouteTable", "Failed to tag managed RouteTable %q: %v", *rt.RouteTableId, err)
-				return errors.Wrapf(err, "failed to ensure tags on route table %q", *rt.RouteTableId)
+		routeTableID, err := s.reconcileSubnetRouteTable(&sn, routes, subnetRouteMap)
+		if err != nil {
+			return err
+		}
+		routeTables[sn.ID] = routeTableID
+	}
+
+	// The subnets of a firewall created by the provider send the traffic they inspected to the NAT gateways.
+	if firewall := s.scope.Firewall(); firewall.IsManaged() {
+		for i := range firewall.Subnets {
+			sn := firewall.Subnets[i]
+			natGatewayID, err := s.getNatGatewayForSubnet(&sn)
+			if err != nil {
+				return err
 			}
 
-			// Not recording "SuccessfulTagRouteTable" here as we don't know if this was a no-op or an actual change
+			routes := mergeRoutes([]*ec2.Route{s.getNatGatewayPrivateRoute(natGatewayID)}, getStaticRoutes(sn.Routes))
+			routeTableID, err := s.reconcileSubnetRouteTable(&sn, routes, subnetRouteMap)
+			if err != nil {
+				return err
+			}
+			routeTables[sn.ID] = routeTableID
+		}
+	}
+
+	s.scope.Network().RouteTables = routeTables
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition)
+	return nil
+}
+
+// reconcileUnmanagedRouteTables associates the subnets referencing an existing route table with it, without
+// changing the route table itself, and records the route tables of these subnets.
+func (s *Service) reconcileUnmanagedRouteTables(routeTables map[string]string) error {
+	subnetIDs := []string{}
+	routeTableIDs := []string{}
+	for _, sn := range s.scope.Subnets() {
+		if sn.UnmanagedRouteTableID == "" || sn.ID == "" {
 			continue
 		}
+		subnetIDs = append(subnetIDs, sn.ID)
+		routeTableIDs = append(routeTableIDs, sn.UnmanagedRouteTableID)
+	}
+	if len(subnetIDs) == 0 {
+		return nil
+	}
 
-		// For each subnet that doesn't have a routing table associated with it,
-		// create a new table with the appropriate default routes and associate it to the subnet.
-		rt, err := s.createRouteTableWithRoutes(routes, sn.IsPublic, sn.AvailabilityZone)
-		if err != nil {
-			return err
+	// Make sure the referenced route tables are in the VPC before moving any subnet to them.
+	existing, err := s.EC2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+		},
+		RouteTableIds: aws.StringSlice(routeTableIDs),
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDescribeVPCRouteTable", "Failed to describe unmanaged route tables in vpc %q: %v", s.scope.VPC().ID, err)
+		return errors.Wrapf(err, "failed to describe unmanaged route tables in vpc %q", s.scope.VPC().ID)
+	}
+	found := map[string]bool{}
+	for _, rt := range existing.RouteTables {
+		found[aws.StringValue(rt.RouteTableId)] = true
+	}
+	for _, id := range routeTableIDs {
+		if !found[id] {
+			record.Warnf(s.scope.InfraCluster(), "FailedFindRouteTable", "Failed to find unmanaged RouteTable %q in vpc %q", id, s.scope.VPC().ID)
+			return errors.Errorf("route table %q not found in vpc %q", id, s.scope.VPC().ID)
+		}
+	}
+
+	out, err := s.EC2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.RouteTableAssociatedSubnets(subnetIDs...),
+		},
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDescribeVPCRouteTable", "Failed to describe route tables of subnets in vpc %q: %v", s.scope.VPC().ID, err)
+		return errors.Wrapf(err, "failed to describe route tables of subnets in vpc %q", s.scope.VPC().ID)
+	}
+
+	associations := map[string]*ec2.RouteTableAssociation{}
+	for _, rt := range out.RouteTables {
+		for _, as := range rt.Associations {
+			if as.SubnetId != nil {
+				associations[*as.SubnetId] = as
+			}
+		}
+	}
+
+	subnets := s.scope.Subnets()
+	for i := range subnets {
+		sn := &subnets[i]
+		if sn.UnmanagedRouteTableID == "" || sn.ID == "" {
+			continue
+		}
+
+		as, ok := associations[sn.ID]
+		switch {
+		case ok && aws.StringValue(as.RouteTableId) == sn.UnmanagedRouteTableID:
+			s.scope.V(2).Info("Subnet is already associated with unmanaged route table", "subnet-id", sn.ID, "route-table-id", sn.UnmanagedRouteTableID)
+		case ok:
+			if _, err := s.EC2Client.ReplaceRouteTableAssociation(&ec2.ReplaceRouteTableAssociationInput{
+				AssociationId: as.RouteTableAssociationId,
+				RouteTableId:  aws.String(sn.UnmanagedRouteTableID),
+			}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedReplaceRouteTableAssociation", "Failed to associate unmanaged RouteTable %q with Subnet %q: %v", sn.UnmanagedRouteTableID, sn.ID, err)
+				return errors.Wrapf(err, "failed to replace the route table of subnet %q with %q", sn.ID, sn.UnmanagedRouteTableID)
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulReplaceRouteTableAssociation", "Associated unmanaged RouteTable %q with subnet %q instead of %q", sn.UnmanagedRouteTableID, sn.ID, aws.StringValue(as.RouteTableId))
+		default:
+			if _, err := s.EC2Client.AssociateRouteTable(&ec2.AssociateRouteTableInput{
+				RouteTableId: aws.String(sn.UnmanagedRouteTableID),
+				SubnetId:     aws.String(sn.ID),
+			}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedAssociateRouteTable", "Failed to associate unmanaged RouteTable %q with Subnet %q: %v", sn.UnmanagedRouteTableID, sn.ID, err)
+				return errors.Wrapf(err, "failed to associate route table %q to subnet %q", sn.UnmanagedRouteTableID, sn.ID)
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulAssociateRouteTable", "Associated unmanaged RouteTable %q with subnet %q", sn.UnmanagedRouteTableID, sn.ID)
+		}
+
+		sn.RouteTableID = aws.String(sn.UnmanagedRouteTableID)
+		routeTables[sn.ID] = sn.UnmanagedRouteTableID
+	}
+
+	return nil
+}
+
+// reconcileSubnetRouteTable makes sure the route table associated with the subnet has the given routes, creating
+// and associating one if there is none, and returns its ID.
+func (s *Service) reconcileSubnetRouteTable(sn *infrav1.SubnetSpec, routes []*ec2.Route, subnetRouteMap map[string]*ec2.RouteTable) (string, error) {
+	if rt, ok := subnetRouteMap[sn.ID]; ok {
+		s.scope.V(2).Info("Subnet is already associated with route table", "subnet-id", sn.ID, "route-table-id", *rt.RouteTableId)
+
+		// For managed environments we need to reconcile the routes of our tables if there is a mistmatch.
+		// For example, a gateway can be deleted and our controller will re-create it, then we replace the route
+		// for the subnet to allow traffic to flow.
+		for i := range routes {
+			specRoute := routes[i]
+
+			// Routes destination cidr blocks must be unique within a routing table.
+			// If there is a mistmatch, we replace the routing association.
+			var currentRoute *ec2.Route
+			for _, route := range rt.Routes {
+				if routeDestination(route) == routeDestination(specRoute) {
+					currentRoute = route
+					break
+				}
+			}
+
+			switch {
+			case currentRoute == nil:
+				if err := s.createRoute(*rt.RouteTableId, specRoute); err != nil {
+					return "", err
+				}
+			case !routeTargetMatches(currentRoute, specRoute):
+				if err := s.replaceRoute(*rt.RouteTableId, specRoute); err != nil {
+					return "", err
+				}
+			}
 		}
 
+		// Make sure tags are up to date.
 		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if err := s.associateRouteTable(rt, sn.ID); err != nil {
-				s.scope.Error(err, "trying to associate route table", "subnet_id", sn.ID)
+			buildParams := s.getRouteTableTagParams(*rt.RouteTableId, sn.IsPublic, sn.AvailabilityZone)
+			tagsBuilder := tags.New(&buildParams, tags.WithEC2(s.EC2Client))
+			if err := tagsBuilder.Ensure(converters.TagsToMap(rt.Tags)); err != nil {
 				return false, err
 			}
 			return true, nil
-		}, awserrors.RouteTableNotFound, awserrors.SubnetNotFound); err != nil {
-			return err
+		}, awserrors.RouteTableNotFound); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedTagRouteTable", "Failed to tag managed RouteTable %q: %v", *rt.RouteTableId, err)
+			return "", errors.Wrapf(err, "failed to ensure tags on route table %q", *rt.RouteTableId)
 		}
 
-		s.scope.V(2).Info("Subnet has been associated with route table", "subnet-id", sn.ID, "route-table-id", rt.ID)
-		sn.RouteTableID = aws.String(rt.ID)
+		// Not recording "SuccessfulTagRouteTable" here as we don't know if this was a no-op or an actual change
+		return *rt.RouteTableId, nil
 	}
-	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition)
-	return nil
+
+	// For each subnet that doesn't have a routing table associated with it,
+	// create a new table with the appropriate default routes and associate it to the subnet.
+	rt, err := s.createRouteTableWithRoutes(routes, sn.IsPublic, sn.AvailabilityZone)
+	if err != nil {
+		return "", err
+	}
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := s.associateRouteTable(rt, sn.ID); err != nil {
+			s.scope.Error(err, "trying to associate route table", "subnet_id", sn.ID)
+			return false, err
+		}
+		return true, nil
+	}, awserrors.RouteTableNotFound, awserrors.SubnetNotFound); err != nil {
+		return "", err
+	}
+
+	s.scope.V(2).Info("Subnet has been associated with route table", "subnet-id", sn.ID, "route-table-id", rt.ID)
+	sn.RouteTableID = aws.String(rt.ID)
+	return rt.ID, nil
+}
+
+// routeTargetMatches returns true if the targets set on the spec route are the targets of the current route.
+func routeTargetMatches(currentRoute, specRoute *ec2.Route) bool {
+	for _, target := range []struct{ current, spec *string }{
+		{currentRoute.GatewayId, specRoute.GatewayId},
+		{currentRoute.EgressOnlyInternetGatewayId, specRoute.EgressOnlyInternetGatewayId},
+		{currentRoute.NatGatewayId, specRoute.NatGatewayId},
+		{currentRoute.InstanceId, specRoute.InstanceId},
+		{currentRoute.NetworkInterfaceId, specRoute.NetworkInterfaceId},
+		{currentRoute.VpcPeeringConnectionId, specRoute.VpcPeeringConnectionId},
+		{currentRoute.TransitGatewayId, specRoute.TransitGatewayId},
+	} {
+		if target.spec != nil && aws.StringValue(target.current) != *target.spec {
+			return false
+		}
+	}
+	return true
+}
+
+// routeDestination returns the destination CIDR block of the route, be it an IPv4 or an IPv6 one.
+func routeDestination(route *ec2.Route) string {
+	if route.DestinationIpv6CidrBlock != nil {
+		return *route.DestinationIpv6CidrBlock
+	}
+	return aws.StringValue(route.DestinationCidrBlock)
 }
 
 func (s *Service) describeVpcRouteTablesBySubnet() (map[string]*ec2.RouteTable, error) {
@@ -237,28 +421,10 @@ func (s *Service) createRouteTableWithRoutes(routes []*ec2.Route, isPublic bool,
 	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateRouteTable", "Created managed RouteTable %q", *out.RouteTable.RouteTableId)
 
 	for i := range routes {
-		route := routes[i]
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if _, err := s.EC2Client.CreateRoute(&ec2.CreateRouteInput{
-				RouteTableId:                out.RouteTable.RouteTableId,
-				DestinationCidrBlock:        route.DestinationCidrBlock,
-				DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
-				EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
-				GatewayId:                   route.GatewayId,
-				InstanceId:                  route.InstanceId,
-				NatGatewayId:                route.NatGatewayId,
-				NetworkInterfaceId:          route.NetworkInterfaceId,
-				VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
-			}); err != nil {
-				return false, err
-			}
-			return true, nil
-		}, awserrors.RouteTableNotFound, awserrors.NATGatewayNotFound, awserrors.GatewayNotFound); err != nil {
+		if err := s.createRoute(*out.RouteTable.RouteTableId, routes[i]); err != nil {
 			// TODO(vincepri): cleanup the route table if this fails.
-			record.Warnf(s.scope.InfraCluster(), "FailedCreateRoute", "Failed to create route %s for RouteTable %q: %v", route.GoString(), *out.RouteTable.RouteTableId, err)
-			return nil, errors.Wrapf(err, "failed to create route in route table %q: %s", *out.RouteTable.RouteTableId, route.GoString())
+			return nil, err
 		}
-		record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateRoute", "Created route %s for RouteTable %q", route.GoString(), *out.RouteTable.RouteTableId)
 	}
 
 	return &infrav1.RouteTable{
@@ -266,6 +432,74 @@ func (s *Service) createRouteTableWithRoutes(routes []*ec2.Route, isPublic bool,
 	}, nil
 }
 
+func (s *Service) createRoute(routeTableID string, route *ec2.Route) error {
+	input := &ec2.CreateRouteInput{
+		RouteTableId:                aws.String(routeTableID),
+		DestinationCidrBlock:        route.DestinationCidrBlock,
+		DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
+		EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
+		GatewayId:                   route.GatewayId,
+		InstanceId:                  route.InstanceId,
+		NatGatewayId:                route.NatGatewayId,
+		NetworkInterfaceId:          route.NetworkInterfaceId,
+		TransitGatewayId:            route.TransitGatewayId,
+		VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
+	}
+	if isVpcEndpointID(route.GatewayId) {
+		input.GatewayId = nil
+		input.VpcEndpointId = route.GatewayId
+	}
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.EC2Client.CreateRoute(input); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.RouteTableNotFound, awserrors.NATGatewayNotFound, awserrors.GatewayNotFound); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateRoute", "Failed to create route %s for RouteTable %q: %v", route.GoString(), routeTableID, err)
+		return errors.Wrapf(err, "failed to create route in route table %q: %s", routeTableID, route.GoString())
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateRoute", "Created route %s for RouteTable %q", route.GoString(), routeTableID)
+	return nil
+}
+
+func (s *Service) replaceRoute(routeTableID string, route *ec2.Route) error {
+	input := &ec2.ReplaceRouteInput{
+		RouteTableId:                aws.String(routeTableID),
+		DestinationCidrBlock:        route.DestinationCidrBlock,
+		DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
+		EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
+		GatewayId:                   route.GatewayId,
+		NatGatewayId:                route.NatGatewayId,
+		InstanceId:                  route.InstanceId,
+		NetworkInterfaceId:          route.NetworkInterfaceId,
+		TransitGatewayId:            route.TransitGatewayId,
+		VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
+	}
+	if isVpcEndpointID(route.GatewayId) {
+		input.GatewayId = nil
+		input.VpcEndpointId = route.GatewayId
+	}
+
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.EC2Client.ReplaceRoute(input); err != nil {
+			return false, err
+		}
+		return true, nil
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedReplaceRoute", "Failed to replace outdated route on managed RouteTable %q: %v", routeTableID, err)
+		return errors.Wrapf(err, "failed to replace outdated route on route table %q", routeTableID)
+	}
+	return nil
+}
+
+// isVpcEndpointID returns true if the target of a route is a Gateway Load Balancer endpoint, such as an endpoint of
+// AWS Network Firewall. Route tables report these endpoints as gateways, but routes to them are created with their
+// VPC endpoint ID.
+func isVpcEndpointID(gatewayID *string) bool {
+	return strings.HasPrefix(aws.StringValue(gatewayID), "vpce-")
+}
+
 func (s *Service) associateRouteTable(rt *infrav1.RouteTable, subnetID string) error {
 	_, err := s.EC2Client.AssociateRouteTable(&ec2.AssociateRouteTableInput{
 		RouteTableId: aws.String(rt.ID),
@@ -288,6 +522,72 @@ func (s *Service) getNatGatewayPrivateRoute(natGatewayID string) *ec2.Route {
 	}
 }
 
+// getFirewallPrivateRoute returns the default route of a private subnet through the given firewall endpoint.
+func (s *Service) getFirewallPrivateRoute(endpointID string) *ec2.Route {
+	return &ec2.Route{
+		DestinationCidrBlock: aws.String(services.AnyIPv4CidrBlock),
+		GatewayId:            aws.String(endpointID),
+	}
+}
+
+// getGatewayLoadBalancerEndpointRoutes returns the routes through the Gateway Load Balancer endpoints in the zone
+// of the subnet.
+func (s *Service) getGatewayLoadBalancerEndpointRoutes(sn *infrav1.SubnetSpec) []*ec2.Route {
+	routes := []*ec2.Route{}
+	for i := range s.scope.GatewayLoadBalancerEndpointRoutes() {
+		route := &s.scope.GatewayLoadBalancerEndpointRoutes()[i]
+		endpointID, ok := route.Endpoints[sn.AvailabilityZone]
+		if !ok || !route.AppliesTo(sn) {
+			continue
+		}
+		routes = append(routes, &ec2.Route{
+			DestinationCidrBlock: aws.String(route.DestinationCidrBlock),
+			GatewayId:            aws.String(endpointID),
+		})
+	}
+	return routes
+}
+
+// getStaticRoutes returns the EC2 routes of the given static routes.
+func getStaticRoutes(staticRoutes []infrav1.Route) []*ec2.Route {
+	routes := make([]*ec2.Route, 0, len(staticRoutes))
+	for _, route := range staticRoutes {
+		routes = append(routes, &ec2.Route{
+			DestinationCidrBlock:   aws.String(route.DestinationCidrBlock),
+			InstanceId:             optionalString(route.InstanceID),
+			NetworkInterfaceId:     optionalString(route.NetworkInterfaceID),
+			TransitGatewayId:       optionalString(route.TransitGatewayID),
+			VpcPeeringConnectionId: optionalString(route.VpcPeeringConnectionID),
+		})
+	}
+	return routes
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+// mergeRoutes adds the routes to the given ones, replacing the routes to the same destination CIDR blocks.
+func mergeRoutes(routes []*ec2.Route, overrides []*ec2.Route) []*ec2.Route {
+	merged := make([]*ec2.Route, 0, len(routes)+len(overrides))
+	for _, route := range routes {
+		replaced := false
+		for _, override := range overrides {
+			if routeDestination(override) == routeDestination(route) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, route)
+		}
+	}
+	return append(merged, overrides...)
+}
+
 func (s *Service) getGatewayPublicRoute() *ec2.Route {
 	return &ec2.Route{
 		DestinationCidrBlock: aws.String(services.AnyIPv4CidrBlock),
@@ -295,6 +595,23 @@ func (s *Service) getGatewayPublicRoute() *ec2.Route {
 	}
 }
 
+// getGatewayPublicIPv6Route returns the IPv6 default route of a public subnet through the internet gateway.
+func (s *Service) getGatewayPublicIPv6Route() *ec2.Route {
+	return &ec2.Route{
+		DestinationIpv6CidrBlock: aws.String(services.AnyIPv6CidrBlock),
+		GatewayId:                aws.String(*s.scope.VPC().InternetGatewayID),
+	}
+}
+
+// getEgressOnlyGatewayPrivateRoute returns the IPv6 default route of a private subnet through the egress-only
+// internet gateway.
+func (s *Service) getEgressOnlyGatewayPrivateRoute() *ec2.Route {
+	return &ec2.Route{
+		DestinationIpv6CidrBlock:    aws.String(services.AnyIPv6CidrBlock),
+		EgressOnlyInternetGatewayId: s.scope.VPC().IPv6.EgressOnlyInternetGatewayID,
+	}
+}
+
 func (s *Service) getRouteTableTagParams(id string, public bool, zone string) infrav1.BuildParams {
 	var name strings.Builder
 
@@ -18,6 +18,8 @@ package network
 
 import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/networkfirewall/networkfirewalliface"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
@@ -42,23 +44,40 @@ type Scope interface {
 	SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup
 	// SecondaryCidrBlock returns the optional secondary CIDR block to use for pod IPs
 	SecondaryCidrBlock() *string
+	// Firewall returns the AWS Network Firewall inspecting the egress of the private subnets, if any.
+	Firewall() *infrav1.NetworkFirewall
+	// GatewayLoadBalancerEndpointRoutes returns the routes through Gateway Load Balancer endpoints of the route tables.
+	GatewayLoadBalancerEndpointRoutes() []infrav1.GatewayLoadBalancerEndpointRoute
+	// Routes returns the static routes of the route tables of all the subnets.
+	Routes() []infrav1.Route
+	// ElasticIPPool returns the pre-allocated Elastic IPs of the NAT gateways and the bastion host, if any.
+	ElasticIPPool() *infrav1.ElasticIPPool
+	// NatGatewayAllocationIDs returns the allocation IDs of the Elastic IPs of the NAT gateways by availability zone.
+	NatGatewayAllocationIDs() map[string]string
 
 	// Bastion returns the bastion details for the cluster.
 	Bastion() *infrav1.Bastion
+
+	// Karpenter returns the Karpenter configuration of the cluster.
+	Karpenter() *infrav1.Karpenter
 }
 
 // Service holds a collection of interfaces.
 // The interfaces are broken down like this to group functions together.
 // One alternative is to have a large list of functions from the ec2 client.
 type Service struct {
-	scope     Scope
-	EC2Client ec2iface.EC2API
+	scope                 Scope
+	EC2Client             ec2iface.EC2API
+	STSClient             stsiface.STSAPI
+	NetworkFirewallClient networkfirewalliface.NetworkFirewallAPI
 }
 
 // NewService returns a new service given the ec2 api client.
 func NewService(networkScope Scope) *Service {
 	return &Service{
-		scope:     networkScope,
-		EC2Client: scope.NewEC2Client(networkScope, networkScope, networkScope, networkScope.InfraCluster()),
+		scope:                 networkScope,
+		EC2Client:             scope.NewEC2Client(networkScope, networkScope, networkScope, networkScope.InfraCluster()),
+		STSClient:             scope.NewSTSClient(networkScope, networkScope, networkScope, networkScope.InfraCluster()),
+		NetworkFirewallClient: scope.NewNetworkFirewallClient(networkScope, networkScope, networkScope, networkScope.InfraCluster()),
 	}
 }
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// reconcileSharedVPC records in the status whether the unmanaged VPC of the cluster is owned by another
+// account, which shares its subnets with the account of the cluster through AWS Resource Access Manager.
+// The owner account alone can modify a shared VPC, so a spec which needs the VPC changed is rejected.
+func (s *Service) reconcileSharedVPC(ownerID string) error {
+	if ownerID == "" {
+		s.scope.Network().SharedVPC = nil
+		return nil
+	}
+
+	out, err := s.STSClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the account ID of the cluster")
+	}
+	if aws.StringValue(out.Account) == ownerID {
+		s.scope.Network().SharedVPC = nil
+		return nil
+	}
+
+	if s.scope.SecondaryCidrBlock() != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedSharedVPC", "Cannot associate a secondary CIDR block with VPC %q, which is owned by account %s", s.scope.VPC().ID, ownerID)
+		return errors.Errorf("cannot associate secondary CIDR block %q with VPC %q, which is shared by account %s", *s.scope.SecondaryCidrBlock(), s.scope.VPC().ID, ownerID)
+	}
+
+	if shared := s.scope.Network().SharedVPC; shared == nil || shared.OwnerID != ownerID {
+		s.scope.Info("Working on VPC shared by another account", "vpc-id", s.scope.VPC().ID, "owner-id", ownerID)
+		record.Eventf(s.scope.InfraCluster(), "SharedVPC", "VPC %q is shared by account %s, its subnets, route tables and gateways are left to the owner account", s.scope.VPC().ID, ownerID)
+		s.scope.Network().SharedVPC = &infrav1.SharedVPC{OwnerID: ownerID}
+	}
+	return nil
+}
+
+// isSharedVPC returns true if the VPC of the cluster is owned by another account.
+func (s *Service) isSharedVPC() bool {
+	return s.scope.Network().SharedVPC != nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/sts/mock_stsiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+const (
+	sharedVPCID        = "vpc-shared"
+	sharedVPCOwnerID   = "111111111111"
+	clusterAccountID   = "222222222222"
+	sharedSubnetID     = "subnet-shared"
+	sharedSubnetCIDR   = "10.0.10.0/24"
+	sharedSubnetZone   = "us-east-1a"
+	sharedSubnetZoneID = "use1-az4"
+)
+
+func newSharedVPCTestScope(t *testing.T, network infrav1.NetworkSpec) *scope.ClusterScope {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       infrav1.AWSClusterSpec{NetworkSpec: network},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := client.Create(context.TODO(), awsCluster); err != nil {
+		t.Fatalf("Failed to create AWSCluster: %v", err)
+	}
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+	return clusterScope
+}
+
+func TestReconcileSharedVPC(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name           string
+		network        infrav1.NetworkSpec
+		ownerID        *string
+		expectSTS      bool
+		expectedShared *infrav1.SharedVPC
+	}{
+		{
+			name:           "VPC owned by another account is shared",
+			network:        infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: sharedVPCID}},
+			ownerID:        aws.String(sharedVPCOwnerID),
+			expectSTS:      true,
+			expectedShared: &infrav1.SharedVPC{OwnerID: sharedVPCOwnerID},
+		},
+		{
+			name:      "VPC owned by the account of the cluster is not shared",
+			network:   infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: sharedVPCID}},
+			ownerID:   aws.String(clusterAccountID),
+			expectSTS: true,
+		},
+		{
+			name:    "VPC without an owner is not shared",
+			network: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: sharedVPCID}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			stsMock := mock_stsiface.NewMockSTSAPI(mockCtrl)
+
+			ec2Mock.EXPECT().DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+				Return(&ec2.DescribeVpcsOutput{
+					Vpcs: []*ec2.Vpc{
+						{
+							VpcId:     aws.String(sharedVPCID),
+							CidrBlock: aws.String("10.0.0.0/16"),
+							State:     aws.String(ec2.VpcStateAvailable),
+							OwnerId:   tc.ownerID,
+						},
+					},
+				}, nil)
+			if tc.expectSTS {
+				stsMock.EXPECT().GetCallerIdentity(&sts.GetCallerIdentityInput{}).
+					Return(&sts.GetCallerIdentityOutput{Account: aws.String(clusterAccountID)}, nil)
+			}
+
+			clusterScope := newSharedVPCTestScope(t, tc.network)
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+			s.STSClient = stsMock
+
+			g.Expect(s.reconcileVPC()).To(Succeed())
+			g.Expect(clusterScope.Network().SharedVPC).To(Equal(tc.expectedShared))
+		})
+	}
+}
+
+func TestReconcileSharedSubnets(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name        string
+		subnets     infrav1.Subnets
+		expected    infrav1.Subnets
+		expectError bool
+	}{
+		{
+			name:    "subnet marked public in the spec stays public",
+			subnets: infrav1.Subnets{{ID: sharedSubnetID, IsPublic: true}},
+			expected: infrav1.Subnets{
+				{
+					ID:                 sharedSubnetID,
+					CidrBlock:          sharedSubnetCIDR,
+					AvailabilityZone:   sharedSubnetZone,
+					AvailabilityZoneID: sharedSubnetZoneID,
+					IsPublic:           true,
+					Tags:               infrav1.Tags{},
+				},
+			},
+		},
+		{
+			name:        "subnet that isn't shared is rejected",
+			subnets:     infrav1.Subnets{{ID: "subnet-other"}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			ec2Mock.EXPECT().DescribeSubnets(gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).
+				Return(&ec2.DescribeSubnetsOutput{
+					Subnets: []*ec2.Subnet{
+						{
+							VpcId:              aws.String(sharedVPCID),
+							SubnetId:           aws.String(sharedSubnetID),
+							CidrBlock:          aws.String(sharedSubnetCIDR),
+							AvailabilityZone:   aws.String(sharedSubnetZone),
+							AvailabilityZoneId: aws.String(sharedSubnetZoneID),
+							OwnerId:            aws.String(sharedVPCOwnerID),
+						},
+					},
+				}, nil)
+			// The route tables and NAT gateways of the owner account aren't visible.
+			ec2Mock.EXPECT().DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+				Return(&ec2.DescribeRouteTablesOutput{}, nil)
+			ec2Mock.EXPECT().DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).
+				Return(nil)
+
+			clusterScope := newSharedVPCTestScope(t, infrav1.NetworkSpec{
+				VPC:     infrav1.VPCSpec{ID: sharedVPCID},
+				Subnets: tc.subnets,
+			})
+			clusterScope.Network().SharedVPC = &infrav1.SharedVPC{OwnerID: sharedVPCOwnerID}
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			err := s.reconcileSubnets()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(clusterScope.Subnets()).To(Equal(tc.expected))
+			g.Expect(clusterScope.Network().SharedVPC.SubnetIDs).To(Equal([]string{sharedSubnetID}))
+		})
+	}
+}
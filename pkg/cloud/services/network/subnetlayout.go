@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+// SubnetLayouts computes the network that the AWSCluster controller creates for a managed VPC without
+// subnets, so that the AWSCluster defaulting webhook can persist it when the cluster is created.
+type SubnetLayouts struct {
+	newClient func(region string) (ec2iface.EC2API, error)
+}
+
+// NewSubnetLayouts returns a SubnetLayouts which creates its EC2 clients with newClient.
+func NewSubnetLayouts(newClient func(region string) (ec2iface.EC2API, error)) *SubnetLayouts {
+	return &SubnetLayouts{newClient: newClient}
+}
+
+// DefaultNetwork sets the CIDR block of the VPC, if unset, and the default subnets of the network
+// in the availability zones of the region.
+func (l *SubnetLayouts) DefaultNetwork(region string, network *infrav1.NetworkSpec) error {
+	client, err := l.newClient(region)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create EC2 client for region %q", region)
+	}
+
+	zones, err := describeAvailableZones(client)
+	if err != nil {
+		return err
+	}
+
+	vpc := network.VPC.DeepCopy()
+	if vpc.CidrBlock == "" {
+		vpc.CidrBlock = defaultVPCCidr
+	}
+	subnets, err := defaultSubnets(vpc, zones)
+	if err != nil {
+		return err
+	}
+
+	network.VPC.CidrBlock = vpc.CidrBlock
+	network.Subnets = subnets
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/golang/mock/gomock"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+)
+
+func TestSubnetLayoutsDefaultNetwork(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+	ec2Mock.EXPECT().DescribeAvailabilityZones(gomock.AssignableToTypeOf(&ec2.DescribeAvailabilityZonesInput{})).
+		Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []*ec2.AvailabilityZone{
+				{ZoneName: aws.String("us-east-1c")},
+				{ZoneName: aws.String("us-east-1a")},
+				{ZoneName: aws.String("us-east-1b")},
+			},
+		}, nil)
+
+	var regions []string
+	layouts := NewSubnetLayouts(func(region string) (ec2iface.EC2API, error) {
+		regions = append(regions, region)
+		return ec2Mock, nil
+	})
+
+	network := &infrav1.NetworkSpec{
+		VPC: infrav1.VPCSpec{AvailabilityZoneUsageLimit: aws.Int(2)},
+	}
+	g.Expect(layouts.DefaultNetwork("us-east-1", network)).To(Succeed())
+	g.Expect(regions).To(Equal([]string{"us-east-1"}))
+	g.Expect(network.VPC.CidrBlock).To(Equal(defaultVPCCidr))
+	g.Expect(network.Subnets).To(Equal(infrav1.Subnets{
+		{CidrBlock: "10.0.0.0/19", AvailabilityZone: "us-east-1a", IsPublic: true},
+		{CidrBlock: "10.0.64.0/18", AvailabilityZone: "us-east-1a"},
+		{CidrBlock: "10.0.32.0/19", AvailabilityZone: "us-east-1b", IsPublic: true},
+		{CidrBlock: "10.0.128.0/18", AvailabilityZone: "us-east-1b"},
+	}))
+}
@@ -52,7 +52,7 @@ func (s *Service) reconcileSubnets() error {
 	}()
 
 	// Describe subnets in the vpc.
-	existing, err := s.describeVpcSubnets()
+	existing, discovered, err := s.discoverVpcSubnets()
 	if err != nil {
 		return err
 	}
@@ -128,9 +128,31 @@ func (s *Service) reconcileSubnets() error {
 				}
 			}
 
+			// The route tables and tags of the owner of a shared VPC can't be seen from the account of the
+			// cluster, so whether a shared subnet is public is taken from the spec as well.
+			isPublic := sub.IsPublic
+			isPublicOverride := sub.IsPublicOverride
+			routes := sub.Routes
+			unmanagedRouteTableID := sub.UnmanagedRouteTableID
+
 			// Update subnet spec with the existing subnet details
 			// TODO(vincepri): check if subnet needs to be updated.
 			existingSubnet.DeepCopyInto(sub)
+			if s.isSharedVPC() {
+				sub.IsPublic = sub.IsPublic || isPublic
+			}
+
+			// The detection doesn't work for subnets whose default route goes through an appliance rather than to an
+			// internet gateway, so it can be overridden for the subnets of an unmanaged VPC.
+			sub.IsPublicOverride = isPublicOverride
+			sub.Routes = routes
+			sub.UnmanagedRouteTableID = unmanagedRouteTableID
+			if unmanagedVPC && isPublicOverride != nil {
+				sub.IsPublic = *isPublicOverride
+			}
+		} else if s.isSharedVPC() {
+			record.Warnf(s.scope.InfraCluster(), "FailedMatchSubnet", "Failed to find subnet id %q, cidr %q in VPC shared by account %s", sub.ID, sub.CidrBlock, s.scope.Network().SharedVPC.OwnerID)
+			return errors.Errorf("subnet %s (cidr %s) specified but it doesn't exist in vpc %s or isn't shared with the account of the cluster by account %s", sub.ID, sub.CidrBlock, s.scope.VPC().ID, s.scope.Network().SharedVPC.OwnerID)
 		} else if unmanagedVPC {
 			// If there is no existing subnet and we have an umanaged vpc report an error
 			record.Warnf(s.scope.InfraCluster(), "FailedMatchSubnet", "Using unmanaged VPC and failed to find existing subnet for specified subnet id %d, cidr %q", sub.ID, sub.CidrBlock)
@@ -157,6 +179,12 @@ func (s *Service) reconcileSubnets() error {
 
 	// Proceed to create the rest of the subnets that don't have an ID.
 	if !unmanagedVPC {
+		if s.scope.VPC().IsIPv6Enabled() {
+			if err := assignIPv6CidrBlocks(s.scope.VPC().IPv6.CidrBlock, existing, subnets); err != nil {
+				return err
+			}
+		}
+
 		for i := range subnets {
 			subnet := &subnets[i]
 			if subnet.ID != "" {
@@ -167,32 +195,117 @@ func (s *Service) reconcileSubnets() error {
 			if err != nil {
 				return err
 			}
+			nsn.IPv6CidrBlock = subnet.IPv6CidrBlock
+			nsn.Routes = subnet.Routes
+			nsn.UnmanagedRouteTableID = subnet.UnmanagedRouteTableID
 			nsn.DeepCopyInto(subnet)
 		}
 	}
 
+	if s.isSharedVPC() {
+		s.scope.Network().SharedVPC.SubnetIDs = subnets.IDs()
+	}
+
+	// Record how the existing subnets of an unmanaged VPC look in AWS, so that users can tell why they are
+	// considered public or private.
+	s.scope.Network().DiscoveredSubnets = nil
+	if unmanagedVPC {
+		for _, sub := range subnets {
+			d, ok := discovered[sub.ID]
+			if !ok {
+				continue
+			}
+			switch {
+			case sub.IsPublicOverride != nil:
+				d.IsPublic = *sub.IsPublicOverride
+				d.Classification = infrav1.SubnetClassificationOverride
+			case sub.IsPublic && !d.IsPublic:
+				d.IsPublic = true
+				d.Classification = infrav1.SubnetClassificationSpec
+			}
+			s.scope.Network().DiscoveredSubnets = append(s.scope.Network().DiscoveredSubnets, *d)
+		}
+	}
+
 	s.scope.V(2).Info("reconciled subnets", "subnets", subnets)
 	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.SubnetsReadyCondition)
 	return nil
 }
 
+// assignIPv6CidrBlocks assigns a /64 block of the IPv6 CIDR block of the VPC to each of the subnets to create that
+// doesn't have one yet, skipping the blocks already used by the existing subnets.
+func assignIPv6CidrBlocks(vpcCidrBlock string, existing, subnets infrav1.Subnets) error {
+	if vpcCidrBlock == "" {
+		return errors.New("ipv6 is enabled but the ipv6 cidr block of the vpc is unknown")
+	}
+
+	used := make(map[string]bool, len(existing)+len(subnets))
+	pending := 0
+	for _, sub := range existing {
+		if sub.IPv6CidrBlock != "" {
+			used[sub.IPv6CidrBlock] = true
+		}
+	}
+	for _, sub := range subnets {
+		switch {
+		case sub.IPv6CidrBlock != "":
+			used[sub.IPv6CidrBlock] = true
+		case sub.ID == "":
+			pending++
+		}
+	}
+	if pending == 0 {
+		return nil
+	}
+
+	blocks, err := cidr.SplitIntoSubnetsIPv6(vpcCidrBlock, len(used)+pending)
+	if err != nil {
+		return errors.Wrapf(err, "failed splitting VPC IPv6 CIDR %s into subnets", vpcCidrBlock)
+	}
+
+	next := 0
+	for i := range subnets {
+		sub := &subnets[i]
+		if sub.ID != "" || sub.IPv6CidrBlock != "" {
+			continue
+		}
+		for used[blocks[next].String()] {
+			next++
+		}
+		sub.IPv6CidrBlock = blocks[next].String()
+		next++
+	}
+	return nil
+}
+
 func (s *Service) getDefaultSubnets() (infrav1.Subnets, error) {
 	zones, err := s.getAvailableZones()
 	if err != nil {
 		return nil, err
 	}
 
+	subnets, err := defaultSubnets(s.scope.VPC(), zones)
+	if err != nil {
+		return nil, err
+	}
+	s.scope.V(2).Info("default subnets computed", "region", s.scope.Region(), "subnets", subnets)
+	return subnets, nil
+}
+
+// defaultSubnets returns a public and a private subnet for each of the zones the VPC uses, up to its
+// AvailabilityZoneUsageLimit. The VPC CIDR block is split into one subnet for each private subnet and
+// one more, which is split further for the public subnets.
+func defaultSubnets(vpc *infrav1.VPCSpec, zones []string) (infrav1.Subnets, error) {
 	maxZones := defaultMaxNumAZs
-	if s.scope.VPC().AvailabilityZoneUsageLimit != nil {
-		maxZones = *s.scope.VPC().AvailabilityZoneUsageLimit
+	if vpc.AvailabilityZoneUsageLimit != nil {
+		maxZones = *vpc.AvailabilityZoneUsageLimit
 	}
 	selectionScheme := infrav1.AZSelectionSchemeOrdered
-	if s.scope.VPC().AvailabilityZoneSelection != nil {
-		selectionScheme = *s.scope.VPC().AvailabilityZoneSelection
+	if vpc.AvailabilityZoneSelection != nil {
+		selectionScheme = *vpc.AvailabilityZoneSelection
 	}
 
 	if len(zones) > maxZones {
-		s.scope.V(2).Info("region has more than AvailabilityZoneUsageLimit availability zones, picking zones to use", "region", s.scope.Region(), "AvailabilityZoneUsageLimit", maxZones)
 		if selectionScheme == infrav1.AZSelectionSchemeRandom {
 			rand.Shuffle(len(zones), func(i, j int) {
 				zones[i], zones[j] = zones[j], zones[i]
@@ -202,14 +315,13 @@ func (s *Service) getDefaultSubnets() (infrav1.Subnets, error) {
 			sort.Strings(zones)
 		}
 		zones = zones[:maxZones]
-		s.scope.V(2).Info("zones selected", "region", s.scope.Region(), "zones", zones)
 	}
 
 	// 1 private subnet for each AZ plus 1 other subnet that will be further sub-divided for the public subnets
 	numSubnets := len(zones) + 1
-	subnetCIDRs, err := cidr.SplitIntoSubnetsIPv4(s.scope.VPC().CidrBlock, numSubnets)
+	subnetCIDRs, err := cidr.SplitIntoSubnetsIPv4(vpc.CidrBlock, numSubnets)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed splitting VPC CIDR %s into subnets", s.scope.VPC().CidrBlock)
+		return nil, errors.Wrapf(err, "failed splitting VPC CIDR %s into subnets", vpc.CidrBlock)
 	}
 
 	publicSubnetCIDRs, err := cidr.SplitIntoSubnetsIPv4(subnetCIDRs[0].String(), len(zones))
@@ -257,6 +369,13 @@ func (s *Service) deleteSubnets() error {
 }
 
 func (s *Service) describeVpcSubnets() (infrav1.Subnets, error) {
+	subnets, _, err := s.discoverVpcSubnets()
+	return subnets, err
+}
+
+// discoverVpcSubnets describes the subnets of the VPC, along with how each of them looks in AWS and why it is
+// considered public or private, by subnet ID.
+func (s *Service) discoverVpcSubnets() (infrav1.Subnets, map[string]*infrav1.DiscoveredSubnet, error) {
 	input := &ec2.DescribeSubnetsInput{
 		Filters: []*ec2.Filter{
 			filter.EC2.SubnetStates(ec2.SubnetStatePending, ec2.SubnetStateAvailable),
@@ -272,78 +391,116 @@ func (s *Service) describeVpcSubnets() (infrav1.Subnets, error) {
 	out, err := s.EC2Client.DescribeSubnets(input)
 	if err != nil {
 		record.Eventf(s.scope.InfraCluster(), "FailedDescribeSubnet", "Failed to describe subnets in vpc %q: %v", s.scope.VPC().ID, err)
-		return nil, errors.Wrapf(err, "failed to describe subnets in vpc %q", s.scope.VPC().ID)
+		return nil, nil, errors.Wrapf(err, "failed to describe subnets in vpc %q", s.scope.VPC().ID)
 	}
 
 	routeTables, err := s.describeVpcRouteTablesBySubnet()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	natGateways, err := s.describeNatGatewaysBySubnet()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	subnets := make([]infrav1.SubnetSpec, 0, len(out.Subnets))
+	discovered := make(map[string]*infrav1.DiscoveredSubnet, len(out.Subnets))
 	// Besides what the AWS API tells us directly about the subnets, we also want to discover whether the subnet is "public" (i.e. directly connected to the internet) and if there are any associated NAT gateways.
 	// We also look for a tag indicating that a particular subnet should be public, to try and determine whether a managed VPC's subnet should have such a route, but does not.
 	for _, ec2sn := range out.Subnets {
 		spec := infrav1.SubnetSpec{
-			ID:               *ec2sn.SubnetId,
-			CidrBlock:        *ec2sn.CidrBlock,
-			AvailabilityZone: *ec2sn.AvailabilityZone,
-			Tags:             converters.TagsToMap(ec2sn.Tags),
+			ID:                 *ec2sn.SubnetId,
+			CidrBlock:          *ec2sn.CidrBlock,
+			AvailabilityZone:   *ec2sn.AvailabilityZone,
+			AvailabilityZoneID: aws.StringValue(ec2sn.AvailabilityZoneId),
+			Tags:               converters.TagsToMap(ec2sn.Tags),
 		}
-
-		// A subnet is public if it's tagged as such...
-		if spec.Tags.GetRole() == infrav1.PublicRoleTagValue {
-			spec.IsPublic = true
+		for _, association := range ec2sn.Ipv6CidrBlockAssociationSet {
+			if association.Ipv6CidrBlockState != nil && aws.StringValue(association.Ipv6CidrBlockState.State) == ec2.SubnetCidrBlockStateCodeAssociated {
+				spec.IPv6CidrBlock = aws.StringValue(association.Ipv6CidrBlock)
+				break
+			}
+		}
+		d := &infrav1.DiscoveredSubnet{
+			ID:   spec.ID,
+			Tags: spec.Tags.DeepCopy(),
 		}
 
-		// ... or if it has an internet route
 		rt := routeTables[*ec2sn.SubnetId]
 		if rt == nil {
 			// If there is no explicit association, subnet defaults to main route table as implicit association
 			rt = routeTables[mainRouteTableInVPCKey]
+			d.MainRouteTable = rt != nil
 		}
 		if rt != nil {
 			spec.RouteTableID = rt.RouteTableId
-			for _, route := range rt.Routes {
-				if route.GatewayId != nil && strings.HasPrefix(*route.GatewayId, "igw") {
-					spec.IsPublic = true
-				}
-			}
+			d.RouteTableID = aws.StringValue(rt.RouteTableId)
 		}
 
+		// A subnet is public if it has an internet route or if it's tagged as such.
+		d.IsPublic, d.Classification = classifySubnet(spec.Tags, rt)
+		spec.IsPublic = d.IsPublic
+
 		ngw := natGateways[*ec2sn.SubnetId]
 		if ngw != nil {
 			spec.NatGatewayID = ngw.NatGatewayId
 		}
 		subnets = append(subnets, spec)
+		discovered[spec.ID] = d
 	}
 
-	return subnets, nil
+	return subnets, discovered, nil
+}
+
+// classifySubnet returns whether a subnet is public, and why, from its tags and the route table it uses.
+func classifySubnet(subnetTags infrav1.Tags, rt *ec2.RouteTable) (bool, infrav1.SubnetClassification) {
+	if rt != nil {
+		for _, route := range rt.Routes {
+			if route.GatewayId != nil && strings.HasPrefix(*route.GatewayId, "igw") {
+				return true, infrav1.SubnetClassificationInternetGatewayRoute
+			}
+		}
+	}
+	if subnetTags.GetRole() == infrav1.PublicRoleTagValue {
+		return true, infrav1.SubnetClassificationRoleTag
+	}
+	return false, infrav1.SubnetClassificationNoInternetGatewayRoute
 }
 
 func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, error) {
-	out, err := s.EC2Client.CreateSubnet(&ec2.CreateSubnetInput{
-		VpcId:            aws.String(s.scope.VPC().ID),
-		CidrBlock:        aws.String(sn.CidrBlock),
-		AvailabilityZone: aws.String(sn.AvailabilityZone),
+	// The zone of the subnet may be given by its ID alone, which then also names the subnet.
+	zone := sn.AvailabilityZone
+	if zone == "" {
+		zone = sn.AvailabilityZoneID
+	}
+
+	input := &ec2.CreateSubnetInput{
+		VpcId:     aws.String(s.scope.VPC().ID),
+		CidrBlock: aws.String(sn.CidrBlock),
 		TagSpecifications: []*ec2.TagSpecification{
 			tags.BuildParamsToTagSpecification(
 				ec2.ResourceTypeSubnet,
-				s.getSubnetTagParams(services.TemporaryResourceID, sn.IsPublic, sn.AvailabilityZone, sn.Tags),
+				s.getSubnetTagParams(services.TemporaryResourceID, sn.IsPublic, zone, sn.Tags),
 			),
 		},
-	})
+	}
+	if sn.IPv6CidrBlock != "" {
+		input.Ipv6CidrBlock = aws.String(sn.IPv6CidrBlock)
+	}
+	if sn.AvailabilityZone != "" {
+		input.AvailabilityZone = aws.String(sn.AvailabilityZone)
+	} else {
+		input.AvailabilityZoneId = aws.String(sn.AvailabilityZoneID)
+	}
+
+	out, err := s.EC2Client.CreateSubnet(input)
 	if err != nil {
 		record.Warnf(s.scope.InfraCluster(), "FailedCreateSubnet", "Failed creating new managed Subnet %v", err)
 		return nil, errors.Wrap(err, "failed to create subnet")
 	}
 
-	s.scope.Info("created subnet", "id", *out.Subnet.SubnetId, "public", sn.IsPublic, "az", sn.AvailabilityZone, "cidr", sn.CidrBlock)
+	s.scope.Info("created subnet", "id", *out.Subnet.SubnetId, "public", sn.IsPublic, "az", zone, "cidr", sn.CidrBlock)
 	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateSubnet", "Created new managed Subnet %q", *out.Subnet.SubnetId)
 
 	wReq := &ec2.DescribeSubnetsInput{SubnetIds: []*string{out.Subnet.SubnetId}}
@@ -371,6 +528,26 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 		record.Eventf(s.scope.InfraCluster(), "SuccessfulModifySubnetAttributes", "Modified managed Subnet %q attributes", *out.Subnet.SubnetId)
 	}
 
+	if sn.IPv6CidrBlock != "" {
+		attReq := &ec2.ModifySubnetAttributeInput{
+			AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{
+				Value: aws.Bool(true),
+			},
+			SubnetId: out.Subnet.SubnetId,
+		}
+
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if _, err := s.EC2Client.ModifySubnetAttribute(attReq); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.SubnetNotFound); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedModifySubnetAttributes", "Failed modifying managed Subnet %q attributes: %v", *out.Subnet.SubnetId, err)
+			return nil, errors.Wrapf(err, "failed to set subnet %q attributes", *out.Subnet.SubnetId)
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulModifySubnetAttributes", "Modified managed Subnet %q attributes", *out.Subnet.SubnetId)
+	}
+
 	s.scope.V(2).Info("Created new subnet in VPC with cidr and availability zone ",
 		"subnet-id", *out.Subnet.SubnetId,
 		"vpc-id", *out.Subnet.VpcId,
@@ -378,10 +555,12 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 		"availability-zone", *out.Subnet.AvailabilityZone)
 
 	return &infrav1.SubnetSpec{
-		ID:               *out.Subnet.SubnetId,
-		AvailabilityZone: *out.Subnet.AvailabilityZone,
-		CidrBlock:        *out.Subnet.CidrBlock,
-		IsPublic:         sn.IsPublic,
+		ID:                 *out.Subnet.SubnetId,
+		AvailabilityZone:   *out.Subnet.AvailabilityZone,
+		AvailabilityZoneID: aws.StringValue(out.Subnet.AvailabilityZoneId),
+		CidrBlock:          *out.Subnet.CidrBlock,
+		IPv6CidrBlock:      sn.IPv6CidrBlock,
+		IsPublic:           sn.IsPublic,
 	}, nil
 }
 
@@ -409,6 +588,11 @@ func (s *Service) getSubnetTagParams(id string, public bool, zone string, manual
 	} else {
 		role = infrav1.PrivateRoleTagValue
 		additionalTags[internalLoadBalancerTag] = "1"
+
+		// Karpenter launches its nodes in the subnets carrying the discovery tag.
+		if s.scope.Karpenter() != nil {
+			additionalTags[infrav1.NameKarpenterDiscovery] = s.scope.Name()
+		}
 	}
 
 	// Add tag needed for Service type=LoadBalancer
@@ -1348,6 +1348,8 @@ func TestDiscoverSubnets(t *testing.T) {
 		input  *infrav1.NetworkSpec
 		mocks  func(m *mock_ec2iface.MockEC2APIMockRecorder)
 		expect []infrav1.SubnetSpec
+		// expectDiscovered is the expected discovered subnets in the network status.
+		expectDiscovered []infrav1.DiscoveredSubnet
 	}{
 		{
 			name: "provided VPC finds internet routes",
@@ -1487,6 +1489,121 @@ func TestDiscoverSubnets(t *testing.T) {
 					},
 				},
 			},
+			expectDiscovered: []infrav1.DiscoveredSubnet{
+				{
+					ID:             "subnet-1",
+					IsPublic:       true,
+					Classification: infrav1.SubnetClassificationInternetGatewayRoute,
+					RouteTableID:   "rtb-1",
+					Tags: infrav1.Tags{
+						"Name": "provided-subnet-public",
+					},
+				},
+				{
+					ID:             "subnet-2",
+					IsPublic:       false,
+					Classification: infrav1.SubnetClassificationNoInternetGatewayRoute,
+					RouteTableID:   "rtb-2",
+					Tags: infrav1.Tags{
+						"Name": "provided-subnet-private",
+					},
+				},
+			},
+		},
+		{
+			name: "provided VPC honors the public override",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: subnetsVPCID,
+				},
+				Subnets: []infrav1.SubnetSpec{
+					{
+						ID:               "subnet-1",
+						IsPublicOverride: aws.Bool(true),
+					},
+					{
+						ID: "subnet-2",
+					},
+				},
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSubnets(gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).
+					Return(&ec2.DescribeSubnetsOutput{
+						Subnets: []*ec2.Subnet{
+							{
+								VpcId:            aws.String(subnetsVPCID),
+								SubnetId:         aws.String("subnet-1"),
+								AvailabilityZone: aws.String("us-east-1a"),
+								CidrBlock:        aws.String("10.0.10.0/24"),
+							},
+							{
+								VpcId:            aws.String(subnetsVPCID),
+								SubnetId:         aws.String("subnet-2"),
+								AvailabilityZone: aws.String("us-east-1a"),
+								CidrBlock:        aws.String("10.0.11.0/24"),
+							},
+						},
+					}, nil)
+
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								Associations: []*ec2.RouteTableAssociation{
+									{
+										Main: aws.Bool(true),
+									},
+								},
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock: aws.String("0.0.0.0/0"),
+										GatewayId:            aws.String("vpce-0"),
+									},
+								},
+								RouteTableId: aws.String("rtb-main"),
+							},
+						},
+					}, nil)
+
+				m.DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).Return(nil)
+			},
+			expect: []infrav1.SubnetSpec{
+				{
+					ID:               "subnet-1",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "10.0.10.0/24",
+					IsPublic:         true,
+					IsPublicOverride: aws.Bool(true),
+					RouteTableID:     aws.String("rtb-main"),
+					Tags:             infrav1.Tags{},
+				},
+				{
+					ID:               "subnet-2",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "10.0.11.0/24",
+					IsPublic:         false,
+					RouteTableID:     aws.String("rtb-main"),
+					Tags:             infrav1.Tags{},
+				},
+			},
+			expectDiscovered: []infrav1.DiscoveredSubnet{
+				{
+					ID:             "subnet-1",
+					IsPublic:       true,
+					Classification: infrav1.SubnetClassificationOverride,
+					RouteTableID:   "rtb-main",
+					MainRouteTable: true,
+					Tags:           infrav1.Tags{},
+				},
+				{
+					ID:             "subnet-2",
+					IsPublic:       false,
+					Classification: infrav1.SubnetClassificationNoInternetGatewayRoute,
+					RouteTableID:   "rtb-main",
+					MainRouteTable: true,
+					Tags:           infrav1.Tags{},
+				},
+			},
 		},
 	}
 	for _, tc := range testCases {
@@ -1545,6 +1662,111 @@ func TestDiscoverSubnets(t *testing.T) {
 			if len(out) > 0 {
 				t.Errorf("Got unexpected subnets: %+v", out)
 			}
+
+			if discovered := s.scope.Network().DiscoveredSubnets; !reflect.DeepEqual(discovered, tc.expectDiscovered) {
+				expected, _ := json.MarshalIndent(tc.expectDiscovered, "", "\t")
+				actual, _ := json.MarshalIndent(discovered, "", "\t")
+				t.Errorf("Expected discovered subnets %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestClassifySubnet(t *testing.T) {
+	internetRouteTable := &ec2.RouteTable{
+		Routes: []*ec2.Route{
+			{
+				DestinationCidrBlock: aws.String("0.0.0.0/0"),
+				GatewayId:            aws.String("igw-0"),
+			},
+		},
+	}
+	firewallRouteTable := &ec2.RouteTable{
+		Routes: []*ec2.Route{
+			{
+				DestinationCidrBlock: aws.String("0.0.0.0/0"),
+				GatewayId:            aws.String("vpce-0"),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                   string
+		tags                   infrav1.Tags
+		routeTable             *ec2.RouteTable
+		expectPublic           bool
+		expectedClassification infrav1.SubnetClassification
+	}{
+		{
+			name:                   "route to an internet gateway",
+			routeTable:             internetRouteTable,
+			expectPublic:           true,
+			expectedClassification: infrav1.SubnetClassificationInternetGatewayRoute,
+		},
+		{
+			name:                   "public role tag",
+			tags:                   infrav1.Tags{infrav1.NameAWSClusterAPIRole: infrav1.PublicRoleTagValue},
+			routeTable:             firewallRouteTable,
+			expectPublic:           true,
+			expectedClassification: infrav1.SubnetClassificationRoleTag,
+		},
+		{
+			name:                   "no route to an internet gateway",
+			tags:                   infrav1.Tags{infrav1.NameAWSClusterAPIRole: infrav1.PrivateRoleTagValue},
+			routeTable:             firewallRouteTable,
+			expectPublic:           false,
+			expectedClassification: infrav1.SubnetClassificationNoInternetGatewayRoute,
+		},
+		{
+			name:                   "no route table",
+			expectPublic:           false,
+			expectedClassification: infrav1.SubnetClassificationNoInternetGatewayRoute,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			public, classification := classifySubnet(tc.tags, tc.routeTable)
+			if public != tc.expectPublic {
+				t.Errorf("Expected public %v, got %v", tc.expectPublic, public)
+			}
+			if classification != tc.expectedClassification {
+				t.Errorf("Expected classification %q, got %q", tc.expectedClassification, classification)
+			}
 		})
 	}
 }
+
+func TestAssignIPv6CidrBlocks(t *testing.T) {
+	existing := infrav1.Subnets{
+		{ID: "subnet-1", IPv6CidrBlock: "2001:db8:1234:1a00::/64"},
+		{ID: "subnet-2", IPv6CidrBlock: "2001:db8:1234:1a02::/64"},
+	}
+	subnets := infrav1.Subnets{
+		{ID: "subnet-1", IPv6CidrBlock: "2001:db8:1234:1a00::/64"},
+		{ID: "subnet-2", IPv6CidrBlock: "2001:db8:1234:1a02::/64"},
+		{CidrBlock: "10.0.2.0/24"},
+		{CidrBlock: "10.0.3.0/24", IPv6CidrBlock: "2001:db8:1234:1a03::/64"},
+		{CidrBlock: "10.0.4.0/24"},
+	}
+
+	if err := assignIPv6CidrBlocks("2001:db8:1234:1a00::/56", existing, subnets); err != nil {
+		t.Fatalf("got an unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"2001:db8:1234:1a00::/64",
+		"2001:db8:1234:1a02::/64",
+		"2001:db8:1234:1a01::/64",
+		"2001:db8:1234:1a03::/64",
+		"2001:db8:1234:1a04::/64",
+	}
+	for i, sub := range subnets {
+		if sub.IPv6CidrBlock != expected[i] {
+			t.Errorf("Expected ipv6 cidr block %q for subnet %d, got %q", expected[i], i, sub.IPv6CidrBlock)
+		}
+	}
+
+	if err := assignIPv6CidrBlocks("", nil, infrav1.Subnets{{CidrBlock: "10.0.2.0/24"}}); err == nil {
+		t.Fatal("Expected an error when the ipv6 cidr block of the vpc is unknown")
+	}
+}
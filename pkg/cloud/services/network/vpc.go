@@ -44,17 +44,32 @@ func (s *Service) reconcileVPC() error {
 
 	// If the ID is not nil, VPC is either managed or unmanaged but should exist in the AWS.
 	if s.scope.VPC().ID != "" {
-		vpc, err := s.describeVPCByID()
+		ec2vpc, err := s.describeVPC()
 		if err != nil {
 			return errors.Wrap(err, ".spec.vpc.id is set but VPC resource is missing in AWS; failed to describe VPC resources. (might be in creation process)")
 		}
+		vpc := vpcToSpec(ec2vpc)
 
 		s.scope.VPC().CidrBlock = vpc.CidrBlock
 		s.scope.VPC().Tags = vpc.Tags
 
+		if s.scope.VPC().IsIPv6Enabled() {
+			if vpc.IPv6 != nil {
+				s.scope.VPC().IPv6.CidrBlock = vpc.IPv6.CidrBlock
+			} else if vpc.IsUnmanaged(s.scope.Name()) {
+				record.Warnf(s.scope.InfraCluster(), "FailedMatchVPCIPv6", "IPv6 is enabled but unmanaged VPC %q has no IPv6 CIDR block", vpc.ID)
+				return errors.Errorf("ipv6 is enabled but unmanaged vpc %q has no ipv6 cidr block", vpc.ID)
+			} else if err := s.waitForVPCIPv6CidrBlock(); err != nil {
+				return err
+			}
+		}
+
 		// If VPC is unmanaged, return early.
 		if vpc.IsUnmanaged(s.scope.Name()) {
 			s.scope.V(2).Info("Working on unmanaged VPC", "vpc-id", vpc.ID)
+			if err := s.reconcileSharedVPC(aws.StringValue(ec2vpc.OwnerId)); err != nil {
+				return err
+			}
 			if err := s.scope.PatchObject(); err != nil {
 				return errors.Wrap(err, "failed to patch unmanaged VPC fields")
 			}
@@ -91,6 +106,13 @@ func (s *Service) reconcileVPC() error {
 	s.scope.VPC().Tags = vpc.Tags
 	s.scope.VPC().ID = vpc.ID
 
+	// The IPv6 CIDR block provided by Amazon is only known once it is associated with the VPC.
+	if s.scope.VPC().IsIPv6Enabled() {
+		if err := s.waitForVPCIPv6CidrBlock(); err != nil {
+			return err
+		}
+	}
+
 	// Make sure attributes are configured
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
 		if err := s.ensureManagedVPCAttributes(vpc); err != nil {
@@ -172,6 +194,9 @@ func (s *Service) createVPC() (*infrav1.VPCSpec, error) {
 			tags.BuildParamsToTagSpecification(ec2.ResourceTypeVpc, s.getVPCTagParams(services.TemporaryResourceID)),
 		},
 	}
+	if s.scope.VPC().IsIPv6Enabled() {
+		input.AmazonProvidedIpv6CidrBlock = aws.Bool(true)
+	}
 
 	out, err := s.EC2Client.CreateVpc(input)
 	if err != nil {
@@ -217,6 +242,14 @@ func (s *Service) deleteVPC() error {
 }
 
 func (s *Service) describeVPCByID() (*infrav1.VPCSpec, error) {
+	vpc, err := s.describeVPC()
+	if err != nil {
+		return nil, err
+	}
+	return vpcToSpec(vpc), nil
+}
+
+func (s *Service) describeVPC() (*ec2.Vpc, error) {
 	if s.scope.VPC().ID == "" {
 		return nil, errors.New("VPC ID is not set, failed to describe VPCs by ID")
 	}
@@ -250,9 +283,76 @@ func (s *Service) describeVPCByID() (*infrav1.VPCSpec, error) {
 		return nil, awserrors.NewNotFound("could not find available or pending vpc")
 	}
 
+	return out.Vpcs[0], nil
+}
+
+func vpcToSpec(vpc *ec2.Vpc) *infrav1.VPCSpec {
+	spec := &infrav1.VPCSpec{
+		ID:        *vpc.VpcId,
+		CidrBlock: *vpc.CidrBlock,
+		Tags:      converters.TagsToMap(vpc.Tags),
+	}
+	if cidrBlock := vpcIPv6CidrBlock(vpc); cidrBlock != "" {
+		spec.IPv6 = &infrav1.IPv6{CidrBlock: cidrBlock}
+	}
+	return spec
+}
+
+// vpcIPv6CidrBlock returns the IPv6 CIDR block associated with the VPC, if any.
+func vpcIPv6CidrBlock(vpc *ec2.Vpc) string {
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState == nil || aws.StringValue(association.Ipv6CidrBlock) == "" {
+			continue
+		}
+		switch aws.StringValue(association.Ipv6CidrBlockState.State) {
+		case ec2.VpcCidrBlockStateCodeAssociated, ec2.VpcCidrBlockStateCodeAssociating:
+			return *association.Ipv6CidrBlock
+		}
+	}
+	return ""
+}
+
+// waitForVPCIPv6CidrBlock waits for the IPv6 CIDR block requested for a managed VPC to be associated with it, and
+// records it.
+func (s *Service) waitForVPCIPv6CidrBlock() error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		vpc, err := s.describeVPC()
+		if err != nil {
+			return false, err
+		}
+		cidrBlock := vpcIPv6CidrBlock(vpc)
+		if cidrBlock == "" {
+			return false, nil
+		}
+		s.scope.VPC().IPv6.CidrBlock = cidrBlock
+		return true, nil
+	}, awserrors.VPCNotFound); err != nil {
+		return errors.Wrapf(err, "failed to wait for the ipv6 cidr block of vpc %q", s.scope.VPC().ID)
+	}
+	return nil
+}
+
+// describeClusterVPC looks up the VPC tagged with the cluster tag.
+func (s *Service) describeClusterVPC() (*infrav1.VPCSpec, error) {
+	out, err := s.EC2Client.DescribeVpcs(&ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.Cluster(s.scope.Name()),
+			filter.EC2.VPCStates(ec2.VpcStatePending, ec2.VpcStateAvailable),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query ec2 for VPCs")
+	}
+
+	if len(out.Vpcs) == 0 {
+		return nil, awserrors.NewNotFound(fmt.Sprintf("could not find a vpc tagged for cluster %q", s.scope.Name()))
+	} else if len(out.Vpcs) > 1 {
+		return nil, awserrors.NewConflict(fmt.Sprintf("found %v VPCs tagged for cluster %q, set .spec.networkSpec.vpc.id to choose one", len(out.Vpcs), s.scope.Name()))
+	}
+
 	return &infrav1.VPCSpec{
 		ID:        *out.Vpcs[0].VpcId,
-		CidrBlock: *out.Vpcs[0].CidrBlock,
+		CidrBlock: aws.StringValue(out.Vpcs[0].CidrBlock),
 		Tags:      converters.TagsToMap(out.Vpcs[0].Tags),
 	}, nil
 }
@@ -0,0 +1,258 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+const (
+	// priceCacheTTL is how long the prices returned by the Pricing API are reused. They seldom change.
+	priceCacheTTL = 24 * time.Hour
+
+	ec2ServiceCode = "AmazonEC2"
+	elbServiceCode = "AWSELB"
+
+	natGatewayUsageType   = "NatGateway-Hours"
+	loadBalancerUsageType = "LoadBalancerUsage"
+
+	classicLoadBalancerProductFamily = "Load Balancer"
+	networkLoadBalancerProductFamily = "Load Balancer-Network"
+)
+
+// priceCache holds the hourly prices looked up in the Pricing API, shared by all the clusters of the controller.
+var priceCache = struct {
+	mu      sync.Mutex
+	entries map[string]priceCacheEntry
+}{entries: map[string]priceCacheEntry{}}
+
+type priceCacheEntry struct {
+	price   float64
+	expires time.Time
+}
+
+// Estimate is the approximate on-demand hourly cost of AWS resources, in US dollars.
+type Estimate struct {
+	HourlyCost float64
+}
+
+// String formats the hourly cost with four decimals.
+func (e Estimate) String() string {
+	return strconv.FormatFloat(e.HourlyCost, 'f', 4, 64)
+}
+
+// EstimateClusterCost estimates the hourly cost of the NAT gateways, load balancers and bastion hosts of a cluster.
+// The traffic they process is not accounted for.
+func (s *Service) EstimateClusterCost(status *infrav1.AWSClusterStatus) (Estimate, error) {
+	estimate := Estimate{}
+
+	if natGateways := len(status.Network.NatGateways); natGateways > 0 {
+		price, err := s.NATGatewayHourlyPrice()
+		if err != nil {
+			return Estimate{}, err
+		}
+		estimate.HourlyCost += float64(natGateways) * price
+	}
+
+	classicLoadBalancers := 0
+	for _, lb := range []infrav1.ClassicELB{status.Network.APIServerELB, status.Network.InternalAPIServerELB} {
+		if lb.DNSName != "" {
+			classicLoadBalancers++
+		}
+	}
+	if classicLoadBalancers > 0 {
+		price, err := s.LoadBalancerHourlyPrice(classicLoadBalancerProductFamily)
+		if err != nil {
+			return Estimate{}, err
+		}
+		estimate.HourlyCost += float64(classicLoadBalancers) * price
+	}
+
+	if status.BastionLoadBalancer != nil {
+		price, err := s.LoadBalancerHourlyPrice(networkLoadBalancerProductFamily)
+		if err != nil {
+			return Estimate{}, err
+		}
+		estimate.HourlyCost += price
+	}
+
+	bastions := status.Bastions
+	if status.Bastion != nil {
+		bastions = append([]infrav1.Instance{*status.Bastion}, bastions...)
+	}
+	instances, err := s.EstimateInstancesCost(bastions)
+	if err != nil {
+		return Estimate{}, err
+	}
+	estimate.HourlyCost += instances.HourlyCost
+
+	return estimate, nil
+}
+
+// EstimateInstancesCost estimates the hourly cost of running instances at the on-demand price of their instance
+// type. Spot instances are counted at the on-demand price too, so the estimate is an upper bound for them.
+func (s *Service) EstimateInstancesCost(instances []infrav1.Instance) (Estimate, error) {
+	estimate := Estimate{}
+	for _, instance := range instances {
+		if instance.Type == "" || instance.State == infrav1.InstanceStateStopped || instance.State == infrav1.InstanceStateTerminated {
+			continue
+		}
+		price, err := s.InstanceHourlyPrice(instance.Type)
+		if err != nil {
+			return Estimate{}, err
+		}
+		estimate.HourlyCost += price
+	}
+	return estimate, nil
+}
+
+// InstanceHourlyPrice returns the on-demand hourly price of a Linux instance type with shared tenancy.
+func (s *Service) InstanceHourlyPrice(instanceType string) (float64, error) {
+	return s.hourlyPrice(ec2ServiceCode, map[string]string{
+		"instanceType":    instanceType,
+		"operatingSystem": "Linux",
+		"tenancy":         "Shared",
+		"preInstalledSw":  "NA",
+		"capacitystatus":  "Used",
+	}, "")
+}
+
+// NATGatewayHourlyPrice returns the hourly price of a NAT gateway.
+func (s *Service) NATGatewayHourlyPrice() (float64, error) {
+	return s.hourlyPrice(ec2ServiceCode, map[string]string{"productFamily": "NAT Gateway"}, natGatewayUsageType)
+}
+
+// LoadBalancerHourlyPrice returns the hourly price of a load balancer of the given product family. The load balancer
+// capacity units of Network Load Balancers are not accounted for.
+func (s *Service) LoadBalancerHourlyPrice(productFamily string) (float64, error) {
+	return s.hourlyPrice(elbServiceCode, map[string]string{"productFamily": productFamily}, loadBalancerUsageType)
+}
+
+// hourlyPrice looks up the on-demand hourly price of the products of a service matching the filters in the region of
+// the cluster. When usageTypeSuffix is set, only the products whose usage type ends with it are considered.
+func (s *Service) hourlyPrice(serviceCode string, attributes map[string]string, usageTypeSuffix string) (float64, error) {
+	attributes["regionCode"] = s.scope.Region()
+	key := priceCacheKey(serviceCode, attributes, usageTypeSuffix)
+
+	priceCache.mu.Lock()
+	entry, ok := priceCache.entries[key]
+	priceCache.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.price, nil
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String(serviceCode),
+	}
+	for field, value := range attributes {
+		input.Filters = append(input.Filters, &pricing.Filter{
+			Field: aws.String(field),
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Value: aws.String(value),
+		})
+	}
+
+	var price float64
+	var found bool
+	var parseErr error
+	if err := s.PricingClient.GetProductsPages(input, func(out *pricing.GetProductsOutput, lastPage bool) bool {
+		price, found, parseErr = parseHourlyPrice(out.PriceList, usageTypeSuffix)
+		return !found && parseErr == nil
+	}); err != nil {
+		return 0, errors.Wrapf(err, "failed to get the products of %q", serviceCode)
+	}
+	if parseErr != nil {
+		return 0, parseErr
+	}
+	if !found {
+		return 0, errors.Errorf("no on-demand price found for %q in region %q", key, s.scope.Region())
+	}
+
+	priceCache.mu.Lock()
+	priceCache.entries[key] = priceCacheEntry{price: price, expires: time.Now().Add(priceCacheTTL)}
+	priceCache.mu.Unlock()
+
+	return price, nil
+}
+
+func priceCacheKey(serviceCode string, attributes map[string]string, usageTypeSuffix string) string {
+	fields := make([]string, 0, len(attributes))
+	for field, value := range attributes {
+		fields = append(fields, field+"="+value)
+	}
+	sort.Strings(fields)
+	return serviceCode + "/" + strings.Join(fields, ",") + "/" + usageTypeSuffix
+}
+
+// priceListItem is the part of a product of the price list holding its usage type and on-demand prices.
+type priceListItem struct {
+	Product struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				Unit         string            `json:"unit"`
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// parseHourlyPrice returns the first hourly on-demand price in US dollars of the products of a price list whose
+// usage type ends with usageTypeSuffix.
+func parseHourlyPrice(priceList []aws.JSONValue, usageTypeSuffix string) (float64, bool, error) {
+	for _, product := range priceList {
+		raw, err := json.Marshal(product)
+		if err != nil {
+			return 0, false, errors.Wrap(err, "failed to read price list")
+		}
+		item := priceListItem{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return 0, false, errors.Wrap(err, "failed to read price list")
+		}
+
+		if !strings.HasSuffix(item.Product.Attributes["usagetype"], usageTypeSuffix) {
+			continue
+		}
+		for _, term := range item.Terms.OnDemand {
+			for _, dimension := range term.PriceDimensions {
+				usd, ok := dimension.PricePerUnit["USD"]
+				if !ok || !strings.EqualFold(dimension.Unit, "Hrs") {
+					continue
+				}
+				price, err := strconv.ParseFloat(usd, 64)
+				if err != nil {
+					return 0, false, errors.Wrapf(err, "invalid price %q", usd)
+				}
+				return price, true, nil
+			}
+		}
+	}
+	return 0, false, nil
+}
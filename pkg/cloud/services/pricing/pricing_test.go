@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/gomega"
+)
+
+func priceListProduct(t *testing.T, usageType, unit, usd string) aws.JSONValue {
+	t.Helper()
+	raw := `{
+		"product": {"attributes": {"usagetype": "` + usageType + `"}},
+		"terms": {"OnDemand": {"ABC.JRTCKXETXF": {"priceDimensions": {"ABC.JRTCKXETXF.6YS6EN2CT7": {
+			"unit": "` + unit + `",
+			"pricePerUnit": {"USD": "` + usd + `"}
+		}}}}}
+	}`
+	product := aws.JSONValue{}
+	if err := json.Unmarshal([]byte(raw), &product); err != nil {
+		t.Fatal(err)
+	}
+	return product
+}
+
+func TestParseHourlyPrice(t *testing.T) {
+	tests := []struct {
+		name            string
+		priceList       func(t *testing.T) []aws.JSONValue
+		usageTypeSuffix string
+		wantPrice       float64
+		wantFound       bool
+		wantErr         bool
+	}{
+		{
+			name: "returns the hourly price of the first product",
+			priceList: func(t *testing.T) []aws.JSONValue {
+				return []aws.JSONValue{priceListProduct(t, "BoxUsage:m5.large", "Hrs", "0.0960000000")}
+			},
+			wantPrice: 0.096,
+			wantFound: true,
+		},
+		{
+			name: "skips products with another usage type",
+			priceList: func(t *testing.T) []aws.JSONValue {
+				return []aws.JSONValue{
+					priceListProduct(t, "EU-NatGateway-Bytes", "GB", "0.0480000000"),
+					priceListProduct(t, "EU-NatGateway-Hours", "Hrs", "0.0480000000"),
+				}
+			},
+			usageTypeSuffix: natGatewayUsageType,
+			wantPrice:       0.048,
+			wantFound:       true,
+		},
+		{
+			name: "ignores prices not charged per hour",
+			priceList: func(t *testing.T) []aws.JSONValue {
+				return []aws.JSONValue{priceListProduct(t, "DataProcessing-Bytes", "GB", "0.0080000000")}
+			},
+		},
+		{
+			name: "fails on an invalid price",
+			priceList: func(t *testing.T) []aws.JSONValue {
+				return []aws.JSONValue{priceListProduct(t, "BoxUsage:m5.large", "Hrs", "free")}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			price, found, err := parseHourlyPrice(tt.priceList(t), tt.usageTypeSuffix)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(found).To(Equal(tt.wantFound))
+			g.Expect(price).To(BeNumerically("~", tt.wantPrice, 1e-9))
+		})
+	}
+}
+
+func TestEstimateString(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Estimate{HourlyCost: 0.19200001}.String()).To(Equal("0.1920"))
+	g.Expect(Estimate{}.String()).To(Equal("0.0000"))
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+)
+
+// Service and quota codes of the Service Quotas API. They are the same in every region.
+const (
+	vpcServiceCode = "vpc"
+	ec2ServiceCode = "ec2"
+
+	vpcsPerRegionQuotaCode             = "L-F678F1CE"
+	internetGatewaysPerRegionQuotaCode = "L-A4707A72"
+	rulesPerSecurityGroupQuotaCode     = "L-0EA8095F"
+	elasticIPsQuotaCode                = "L-0263D0A3"
+
+	standardOnDemandQuotaCode = "L-1216C47A"
+	fOnDemandQuotaCode        = "L-74FC7D96"
+	gOnDemandQuotaCode        = "L-DB2E81BA"
+	infOnDemandQuotaCode      = "L-1945791B"
+	pOnDemandQuotaCode        = "L-417A185B"
+	xOnDemandQuotaCode        = "L-7295265B"
+)
+
+// onDemandQuotaCodes maps instance families to the quota that limits the vCPUs of their running
+// on-demand instances. Families that are missing, such as dl, trn and u-, are not checked.
+var onDemandQuotaCodes = map[string]string{
+	"a":   standardOnDemandQuotaCode,
+	"c":   standardOnDemandQuotaCode,
+	"d":   standardOnDemandQuotaCode,
+	"h":   standardOnDemandQuotaCode,
+	"i":   standardOnDemandQuotaCode,
+	"im":  standardOnDemandQuotaCode,
+	"is":  standardOnDemandQuotaCode,
+	"m":   standardOnDemandQuotaCode,
+	"r":   standardOnDemandQuotaCode,
+	"t":   standardOnDemandQuotaCode,
+	"z":   standardOnDemandQuotaCode,
+	"f":   fOnDemandQuotaCode,
+	"g":   gOnDemandQuotaCode,
+	"vt":  gOnDemandQuotaCode,
+	"inf": infOnDemandQuotaCode,
+	"p":   pOnDemandQuotaCode,
+	"x":   xOnDemandQuotaCode,
+}
+
+// Requirements are the resources that creating a cluster takes from the service quotas of the account.
+type Requirements struct {
+	VPCs             int
+	InternetGateways int
+	ElasticIPs       int
+	// SecurityGroupRules is the largest number of rules that any one security group of the cluster needs.
+	SecurityGroupRules int
+	// InstanceTypes holds the instance type of every on-demand instance of the cluster.
+	InstanceTypes []string
+}
+
+// onDemandQuotaCode returns the quota code that limits the on-demand instances of an instance
+// type, or an empty string if the instance type is not checked. The family of an instance type
+// is made of the letters before its generation, e.g. m for m5a.large and inf for inf1.xlarge.
+func onDemandQuotaCode(instanceType string) string {
+	family := strings.ToLower(instanceType)
+	if i := strings.IndexAny(family, "0123456789"); i >= 0 {
+		family = family[:i]
+	}
+	return onDemandQuotaCodes[family]
+}
+
+// CheckRequirements compares the requirements with the service quotas of the account and the
+// resources already in use in the region. It returns a description of every quota that does not
+// leave enough room.
+func (s *Service) CheckRequirements(req Requirements) ([]string, error) {
+	var shortfalls []string
+
+	checks := []struct {
+		serviceCode string
+		quotaCode   string
+		needed      int
+		inUse       func() (int, error)
+	}{
+		{serviceCode: vpcServiceCode, quotaCode: vpcsPerRegionQuotaCode, needed: req.VPCs, inUse: s.countVPCs},
+		{serviceCode: vpcServiceCode, quotaCode: internetGatewaysPerRegionQuotaCode, needed: req.InternetGateways, inUse: s.countInternetGateways},
+		{serviceCode: ec2ServiceCode, quotaCode: elasticIPsQuotaCode, needed: req.ElasticIPs, inUse: s.countElasticIPs},
+	}
+	for _, check := range checks {
+		if check.needed == 0 {
+			continue
+		}
+		quota, err := s.getQuota(check.serviceCode, check.quotaCode)
+		if err != nil {
+			return nil, err
+		}
+		inUse, err := check.inUse()
+		if err != nil {
+			return nil, err
+		}
+		if inUse+check.needed > quotaValue(quota) {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: %d needed, %d of %d in use",
+				aws.StringValue(quota.QuotaName), check.needed, inUse, quotaValue(quota)))
+		}
+	}
+
+	if req.SecurityGroupRules > 0 {
+		quota, err := s.getQuota(vpcServiceCode, rulesPerSecurityGroupQuotaCode)
+		if err != nil {
+			return nil, err
+		}
+		if req.SecurityGroupRules > quotaValue(quota) {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: %d needed, quota is %d",
+				aws.StringValue(quota.QuotaName), req.SecurityGroupRules, quotaValue(quota)))
+		}
+	}
+
+	instanceShortfalls, err := s.checkOnDemandVCPUs(req.InstanceTypes)
+	if err != nil {
+		return nil, err
+	}
+	return append(shortfalls, instanceShortfalls...), nil
+}
+
+func (s *Service) checkOnDemandVCPUs(instanceTypes []string) ([]string, error) {
+	if len(instanceTypes) == 0 {
+		return nil, nil
+	}
+
+	vcpus, err := s.describeVCPUs(instanceTypes)
+	if err != nil {
+		return nil, err
+	}
+	needed := map[string]int{}
+	for _, instanceType := range instanceTypes {
+		if code := onDemandQuotaCode(instanceType); code != "" {
+			needed[code] += vcpus[instanceType]
+		}
+	}
+	if len(needed) == 0 {
+		return nil, nil
+	}
+
+	inUse, err := s.countOnDemandVCPUs()
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(needed))
+	for code := range needed {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var shortfalls []string
+	for _, code := range codes {
+		quota, err := s.getQuota(ec2ServiceCode, code)
+		if err != nil {
+			return nil, err
+		}
+		if inUse[code]+needed[code] > quotaValue(quota) {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: %d vCPUs needed, %d of %d in use",
+				aws.StringValue(quota.QuotaName), needed[code], inUse[code], quotaValue(quota)))
+		}
+	}
+	return shortfalls, nil
+}
+
+// getQuota returns the quota applied to the account, or the AWS default if it was never changed.
+func (s *Service) getQuota(serviceCode, quotaCode string) (*servicequotas.ServiceQuota, error) {
+	out, err := s.ServiceQuotasClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err == nil {
+		return out.Quota, nil
+	}
+	if code, _ := awserrors.Code(err); code != servicequotas.ErrCodeNoSuchResourceException {
+		return nil, errors.Wrapf(err, "failed to get service quota %s/%s", serviceCode, quotaCode)
+	}
+
+	defaultOut, err := s.ServiceQuotasClient.GetAWSDefaultServiceQuota(&servicequotas.GetAWSDefaultServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get default service quota %s/%s", serviceCode, quotaCode)
+	}
+	return defaultOut.Quota, nil
+}
+
+func quotaValue(quota *servicequotas.ServiceQuota) int {
+	return int(aws.Float64Value(quota.Value))
+}
+
+func (s *Service) countVPCs() (int, error) {
+	count := 0
+	err := s.EC2Client.DescribeVpcsPages(&ec2.DescribeVpcsInput{}, func(out *ec2.DescribeVpcsOutput, lastPage bool) bool {
+		count += len(out.Vpcs)
+		return true
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to describe VPCs")
+	}
+	return count, nil
+}
+
+func (s *Service) countInternetGateways() (int, error) {
+	count := 0
+	err := s.EC2Client.DescribeInternetGatewaysPages(&ec2.DescribeInternetGatewaysInput{}, func(out *ec2.DescribeInternetGatewaysOutput, lastPage bool) bool {
+		count += len(out.InternetGateways)
+		return true
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to describe internet gateways")
+	}
+	return count, nil
+}
+
+func (s *Service) countElasticIPs() (int, error) {
+	out, err := s.EC2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("domain"), Values: aws.StringSlice([]string{"vpc"})}},
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to describe elastic IPs")
+	}
+	return len(out.Addresses), nil
+}
+
+// countOnDemandVCPUs returns the vCPUs of the running on-demand instances in the region by quota code.
+func (s *Service) countOnDemandVCPUs() (map[string]int, error) {
+	vcpus := map[string]int{}
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning)},
+	}
+	err := s.EC2Client.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				// Spot and scheduled instances have their own quotas.
+				if instance.InstanceLifecycle != nil || instance.CpuOptions == nil {
+					continue
+				}
+				if code := onDemandQuotaCode(aws.StringValue(instance.InstanceType)); code != "" {
+					vcpus[code] += int(aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe instances")
+	}
+	return vcpus, nil
+}
+
+func (s *Service) describeVCPUs(instanceTypes []string) (map[string]int, error) {
+	unique := map[string]bool{}
+	input := &ec2.DescribeInstanceTypesInput{}
+	for _, instanceType := range instanceTypes {
+		if !unique[instanceType] {
+			unique[instanceType] = true
+			input.InstanceTypes = append(input.InstanceTypes, aws.String(instanceType))
+		}
+	}
+
+	vcpus := map[string]int{}
+	err := s.EC2Client.DescribeInstanceTypesPages(input, func(out *ec2.DescribeInstanceTypesOutput, lastPage bool) bool {
+		for _, info := range out.InstanceTypes {
+			if info.VCpuInfo != nil {
+				vcpus[aws.StringValue(info.InstanceType)] = int(aws.Int64Value(info.VCpuInfo.DefaultVCpus))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe instance types")
+	}
+	return vcpus, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotas
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/quotas/mock_servicequotasiface"
+)
+
+func quotaOutput(name string, value float64) *servicequotas.GetServiceQuotaOutput {
+	return &servicequotas.GetServiceQuotaOutput{
+		Quota: &servicequotas.ServiceQuota{QuotaName: aws.String(name), Value: aws.Float64(value)},
+	}
+}
+
+func quotaInput(serviceCode, quotaCode string) *servicequotas.GetServiceQuotaInput {
+	return &servicequotas.GetServiceQuotaInput{ServiceCode: aws.String(serviceCode), QuotaCode: aws.String(quotaCode)}
+}
+
+func TestOnDemandQuotaCode(t *testing.T) {
+	tests := map[string]string{
+		"m5.large":     standardOnDemandQuotaCode,
+		"t3a.micro":    standardOnDemandQuotaCode,
+		"im4gn.large":  standardOnDemandQuotaCode,
+		"G4dn.xlarge":  gOnDemandQuotaCode,
+		"vt1.3xlarge":  gOnDemandQuotaCode,
+		"inf1.xlarge":  infOnDemandQuotaCode,
+		"p3.2xlarge":   pOnDemandQuotaCode,
+		"x1e.xlarge":   xOnDemandQuotaCode,
+		"f1.2xlarge":   fOnDemandQuotaCode,
+		"dl1.24xlarge": "",
+		"":             "",
+	}
+	for instanceType, want := range tests {
+		if got := onDemandQuotaCode(instanceType); got != want {
+			t.Errorf("onDemandQuotaCode(%q) = %q, want %q", instanceType, got, want)
+		}
+	}
+}
+
+func TestCheckRequirements(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name       string
+		req        Requirements
+		expect     func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder)
+		shortfalls []string
+		expectErr  bool
+	}{
+		{
+			name: "nothing required makes no calls",
+			req:  Requirements{},
+			expect: func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder) {
+			},
+		},
+		{
+			name: "network fits in the quotas",
+			req:  Requirements{VPCs: 1, InternetGateways: 1, ElasticIPs: 3, SecurityGroupRules: 10},
+			expect: func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder) {
+				sqm.GetServiceQuota(quotaInput(vpcServiceCode, vpcsPerRegionQuotaCode)).Return(quotaOutput("VPCs per Region", 5), nil)
+				ec2m.DescribeVpcsPages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *ec2.DescribeVpcsInput, fn func(*ec2.DescribeVpcsOutput, bool) bool) error {
+					fn(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{}, {}}}, true)
+					return nil
+				})
+				sqm.GetServiceQuota(quotaInput(vpcServiceCode, internetGatewaysPerRegionQuotaCode)).Return(quotaOutput("Internet gateways per Region", 5), nil)
+				ec2m.DescribeInternetGatewaysPages(gomock.Any(), gomock.Any()).Return(nil)
+				sqm.GetServiceQuota(quotaInput(ec2ServiceCode, elasticIPsQuotaCode)).Return(quotaOutput("EC2-VPC Elastic IPs", 5), nil)
+				ec2m.DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{Addresses: []*ec2.Address{{}, {}}}, nil)
+				sqm.GetServiceQuota(quotaInput(vpcServiceCode, rulesPerSecurityGroupQuotaCode)).Return(quotaOutput("Inbound or outbound rules per security group", 60), nil)
+			},
+		},
+		{
+			name: "elastic IPs and security group rules exceed the quotas",
+			req:  Requirements{ElasticIPs: 3, SecurityGroupRules: 80},
+			expect: func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder) {
+				sqm.GetServiceQuota(quotaInput(ec2ServiceCode, elasticIPsQuotaCode)).Return(quotaOutput("EC2-VPC Elastic IPs", 5), nil)
+				ec2m.DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{Addresses: []*ec2.Address{{}, {}, {}}}, nil)
+				sqm.GetServiceQuota(quotaInput(vpcServiceCode, rulesPerSecurityGroupQuotaCode)).Return(quotaOutput("Inbound or outbound rules per security group", 60), nil)
+			},
+			shortfalls: []string{
+				"EC2-VPC Elastic IPs: 3 needed, 3 of 5 in use",
+				"Inbound or outbound rules per security group: 80 needed, quota is 60",
+			},
+		},
+		{
+			name: "falls back to the default quota",
+			req:  Requirements{VPCs: 1},
+			expect: func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder) {
+				sqm.GetServiceQuota(quotaInput(vpcServiceCode, vpcsPerRegionQuotaCode)).
+					Return(nil, awserr.New(servicequotas.ErrCodeNoSuchResourceException, "", nil))
+				sqm.GetAWSDefaultServiceQuota(&servicequotas.GetAWSDefaultServiceQuotaInput{
+					ServiceCode: aws.String(vpcServiceCode),
+					QuotaCode:   aws.String(vpcsPerRegionQuotaCode),
+				}).Return(&servicequotas.GetAWSDefaultServiceQuotaOutput{
+					Quota: &servicequotas.ServiceQuota{QuotaName: aws.String("VPCs per Region"), Value: aws.Float64(5)},
+				}, nil)
+				ec2m.DescribeVpcsPages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *ec2.DescribeVpcsInput, fn func(*ec2.DescribeVpcsOutput, bool) bool) error {
+					fn(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{}, {}, {}, {}, {}}}, true)
+					return nil
+				})
+			},
+			shortfalls: []string{"VPCs per Region: 1 needed, 5 of 5 in use"},
+		},
+		{
+			name: "on-demand vCPUs exceed the quota",
+			req:  Requirements{InstanceTypes: []string{"m5.xlarge", "m5.xlarge", "m5.large", "dl1.24xlarge"}},
+			expect: func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder) {
+				ec2m.DescribeInstanceTypesPages(&ec2.DescribeInstanceTypesInput{
+					InstanceTypes: aws.StringSlice([]string{"m5.xlarge", "m5.large", "dl1.24xlarge"}),
+				}, gomock.Any()).DoAndReturn(func(_ *ec2.DescribeInstanceTypesInput, fn func(*ec2.DescribeInstanceTypesOutput, bool) bool) error {
+					fn(&ec2.DescribeInstanceTypesOutput{InstanceTypes: []*ec2.InstanceTypeInfo{
+						{InstanceType: aws.String("m5.xlarge"), VCpuInfo: &ec2.VCpuInfo{DefaultVCpus: aws.Int64(4)}},
+						{InstanceType: aws.String("m5.large"), VCpuInfo: &ec2.VCpuInfo{DefaultVCpus: aws.Int64(2)}},
+						{InstanceType: aws.String("dl1.24xlarge"), VCpuInfo: &ec2.VCpuInfo{DefaultVCpus: aws.Int64(96)}},
+					}}, true)
+					return nil
+				})
+				ec2m.DescribeInstancesPages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+					fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{
+						{InstanceType: aws.String("c5.2xlarge"), CpuOptions: &ec2.CpuOptions{CoreCount: aws.Int64(4), ThreadsPerCore: aws.Int64(2)}},
+						{InstanceType: aws.String("c5.2xlarge"), CpuOptions: &ec2.CpuOptions{CoreCount: aws.Int64(4), ThreadsPerCore: aws.Int64(2)}, InstanceLifecycle: aws.String(ec2.InstanceLifecycleTypeSpot)},
+					}}}}, true)
+					return nil
+				})
+				sqm.GetServiceQuota(quotaInput(ec2ServiceCode, standardOnDemandQuotaCode)).
+					Return(quotaOutput("Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances", 16), nil)
+			},
+			shortfalls: []string{"Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances: 10 vCPUs needed, 8 of 16 in use"},
+		},
+		{
+			name: "quota lookup fails",
+			req:  Requirements{VPCs: 1},
+			expect: func(ec2m *mock_ec2iface.MockEC2APIMockRecorder, sqm *mock_servicequotasiface.MockServiceQuotasAPIMockRecorder) {
+				sqm.GetServiceQuota(quotaInput(vpcServiceCode, vpcsPerRegionQuotaCode)).
+					Return(nil, awserr.New("AccessDeniedException", "", nil))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			sqMock := mock_servicequotasiface.NewMockServiceQuotasAPI(mockCtrl)
+			tc.expect(ec2Mock.EXPECT(), sqMock.EXPECT())
+
+			s := &Service{EC2Client: ec2Mock, ServiceQuotasClient: sqMock}
+			shortfalls, err := s.CheckRequirements(tc.req)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(shortfalls).To(Equal(tc.shortfalls))
+		})
+	}
+}
@@ -0,0 +1,390 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awsarn"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+)
+
+const (
+	// errCodeBucketNotEmpty is returned by S3 when deleting a bucket which still holds objects.
+	errCodeBucketNotEmpty = "BucketNotEmpty"
+
+	// defaultRegion is the only region in which a bucket must be created without a location constraint.
+	defaultRegion = "us-east-1"
+
+	lifecycleRuleID = "expire-stale-objects"
+)
+
+// ReconcileBucket reconciles the S3 bucket of the cluster and its configuration.
+func (s *Service) ReconcileBucket() error {
+	if !s.bucketManagementEnabled() {
+		return nil
+	}
+
+	bucketName := s.bucketName()
+
+	if s.scope.Bucket().Unmanaged {
+		// The bucket is managed outside of Cluster API, only make sure it is reachable.
+		if _, err := s.S3Client.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(bucketName)}); err != nil {
+			return errors.Wrapf(err, "checking existence of unmanaged bucket %q", bucketName)
+		}
+		return nil
+	}
+
+	if err := s.createBucketIfNotExist(bucketName); err != nil {
+		return errors.Wrap(err, "ensuring bucket exists")
+	}
+
+	if err := s.ensureBucketVersioning(bucketName); err != nil {
+		return errors.Wrap(err, "ensuring bucket versioning")
+	}
+
+	if err := s.ensureBucketEncryption(bucketName); err != nil {
+		return errors.Wrap(err, "ensuring bucket encryption")
+	}
+
+	if err := s.ensureBucketPublicAccessBlock(bucketName); err != nil {
+		return errors.Wrap(err, "ensuring bucket public access block")
+	}
+
+	if err := s.ensureBucketLifecycle(bucketName); err != nil {
+		return errors.Wrap(err, "ensuring bucket lifecycle")
+	}
+
+	if err := s.ensureBucketPolicy(bucketName); err != nil {
+		return errors.Wrap(err, "ensuring bucket policy")
+	}
+
+	return nil
+}
+
+// DeleteBucket deletes the objects stored for the cluster and then the S3 bucket itself,
+// unless the bucket is unmanaged. Buckets which still contain objects are left in place.
+func (s *Service) DeleteBucket() error {
+	if !s.bucketManagementEnabled() {
+		return nil
+	}
+
+	bucketName := s.bucketName()
+
+	if err := s.deleteObjects(bucketName, s.keyPrefix()); err != nil {
+		return errors.Wrap(err, "deleting S3 objects")
+	}
+
+	if s.scope.Bucket().Unmanaged {
+		return nil
+	}
+
+	log := s.scope.WithValues("name", bucketName)
+
+	log.Info("Deleting S3 Bucket")
+
+	_, err := s.S3Client.DeleteBucket(&s3.DeleteBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err == nil {
+		return nil
+	}
+
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return errors.Wrap(err, "deleting S3 bucket")
+	}
+
+	switch aerr.Code() {
+	case s3.ErrCodeNoSuchBucket:
+		log.Info("Bucket already removed")
+	case errCodeBucketNotEmpty:
+		log.Info("Bucket not empty, skipping removal")
+	default:
+		return errors.Wrap(aerr, "deleting S3 bucket")
+	}
+
+	return nil
+}
+
+func (s *Service) createBucketIfNotExist(bucketName string) error {
+	input := &s3.CreateBucketInput{
+		Bucket: aws.String(bucketName),
+	}
+
+	// Buckets in us-east-1 must be created without a location constraint.
+	if s.scope.Region() != defaultRegion {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(s.scope.Region()),
+		}
+	}
+
+	_, err := s.S3Client.CreateBucket(input)
+	if err == nil {
+		s.scope.Info("Created bucket", "bucket_name", bucketName)
+	} else {
+		aerr, ok := err.(awserr.Error)
+		if !ok {
+			return errors.Wrap(err, "creating S3 bucket")
+		}
+
+		switch aerr.Code() {
+		// If bucket already exists, all good.
+		case s3.ErrCodeBucketAlreadyOwnedByYou:
+		default:
+			return errors.Wrap(aerr, "creating S3 bucket")
+		}
+	}
+
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(bucketName),
+		Additional:  s.scope.AdditionalTags(),
+	})
+
+	if _, err := s.S3Client.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket: aws.String(bucketName),
+		Tagging: &s3.Tagging{
+			TagSet: converters.MapToS3Tags(tags),
+		},
+	}); err != nil {
+		return errors.Wrap(err, "tagging S3 bucket")
+	}
+
+	return nil
+}
+
+func (s *Service) ensureBucketVersioning(bucketName string) error {
+	status := s3.BucketVersioningStatusEnabled
+
+	if !s.scope.Bucket().Versioning {
+		// Versioning can not be disabled once enabled, only suspended.
+		out, err := s.S3Client.GetBucketVersioning(&s3.GetBucketVersioningInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			return errors.Wrap(err, "getting bucket versioning")
+		}
+
+		if aws.StringValue(out.Status) != s3.BucketVersioningStatusEnabled {
+			return nil
+		}
+
+		status = s3.BucketVersioningStatusSuspended
+	}
+
+	_, err := s.S3Client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucketName),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(status),
+		},
+	})
+
+	return err
+}
+
+func (s *Service) ensureBucketEncryption(bucketName string) error {
+	sse := &s3.ServerSideEncryptionByDefault{
+		SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+	}
+	rule := &s3.ServerSideEncryptionRule{
+		ApplyServerSideEncryptionByDefault: sse,
+	}
+
+	if encryption := s.scope.Bucket().Encryption; encryption != nil {
+		sse.SSEAlgorithm = aws.String(s3.ServerSideEncryptionAwsKms)
+		sse.KMSMasterKeyID = aws.String(encryption.KMSKeyID)
+		// Reduces the number of requests made to KMS when reading and writing objects.
+		rule.BucketKeyEnabled = aws.Bool(true)
+	}
+
+	_, err := s.S3Client.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+		Bucket: aws.String(bucketName),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{rule},
+		},
+	})
+
+	return err
+}
+
+func (s *Service) ensureBucketPublicAccessBlock(bucketName string) error {
+	config := &s3.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       aws.Bool(true),
+		IgnorePublicAcls:      aws.Bool(true),
+		BlockPublicPolicy:     aws.Bool(true),
+		RestrictPublicBuckets: aws.Bool(true),
+	}
+
+	if block := s.scope.Bucket().PublicAccessBlock; block != nil {
+		config = &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(block.BlockPublicACLs),
+			IgnorePublicAcls:      aws.Bool(block.IgnorePublicACLs),
+			BlockPublicPolicy:     aws.Bool(block.BlockPublicPolicy),
+			RestrictPublicBuckets: aws.Bool(block.RestrictPublicBuckets),
+		}
+	}
+
+	_, err := s.S3Client.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket:                         aws.String(bucketName),
+		PublicAccessBlockConfiguration: config,
+	})
+
+	return err
+}
+
+func (s *Service) ensureBucketLifecycle(bucketName string) error {
+	lifecycle := s.scope.Bucket().Lifecycle
+	if lifecycle == nil {
+		// Deleting a lifecycle configuration which does not exist succeeds.
+		_, err := s.S3Client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(bucketName),
+		})
+		return err
+	}
+
+	rule := &s3.LifecycleRule{
+		ID:     aws.String(lifecycleRuleID),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+		// Only the objects stored for the cluster expire, as the bucket may be shared with other clusters.
+		Filter: &s3.LifecycleRuleFilter{
+			Prefix: aws.String(s.keyPrefix() + "/"),
+		},
+		Expiration: &s3.LifecycleExpiration{
+			Days: aws.Int64(int64(lifecycle.ExpirationDays)),
+		},
+	}
+
+	if lifecycle.NoncurrentVersionExpirationDays != nil {
+		rule.NoncurrentVersionExpiration = &s3.NoncurrentVersionExpiration{
+			NoncurrentDays: aws.Int64(int64(*lifecycle.NoncurrentVersionExpirationDays)),
+		}
+	}
+
+	_, err := s.S3Client.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucketName),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{rule},
+		},
+	})
+
+	return err
+}
+
+func (s *Service) ensureBucketPolicy(bucketName string) error {
+	bucketPolicy, err := s.bucketPolicy(bucketName)
+	if err != nil {
+		return errors.Wrap(err, "generating Bucket policy")
+	}
+
+	input := &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucketName),
+		Policy: aws.String(bucketPolicy),
+	}
+
+	if _, err := s.S3Client.PutBucketPolicy(input); err != nil {
+		return errors.Wrap(err, "creating S3 bucket policy")
+	}
+
+	s.scope.V(4).Info("Updated bucket policy", "bucket_name", bucketName)
+
+	return nil
+}
+
+func (s *Service) bucketPolicy(bucketName string) (string, error) {
+	roles := s.scope.Bucket().NodesIAMRoles
+	if len(roles) == 0 {
+		roles = []string{
+			"control-plane" + infrav1.DefaultNameSuffix,
+			"nodes" + infrav1.DefaultNameSuffix,
+		}
+	}
+
+	principals := make(infrav1.PrincipalID, 0, len(roles))
+	for _, role := range roles {
+		arn, err := s.roleARN(role)
+		if err != nil {
+			return "", err
+		}
+		principals = append(principals, arn)
+	}
+
+	partition := awsarn.Partition(s.scope.Region())
+	policy := infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		Statement: infrav1.Statements{
+			{
+				Sid:       "NodesReadObjects",
+				Effect:    infrav1.EffectAllow,
+				Principal: infrav1.Principals{infrav1.PrincipalAWS: principals},
+				Action:    infrav1.Actions{"s3:GetObject"},
+				Resource:  infrav1.Resources{awsarn.S3Object(partition, bucketName, fmt.Sprintf("%s/*", s.keyPrefix()))},
+			},
+			{
+				Sid:       "DenyInsecureTransport",
+				Effect:    infrav1.EffectDeny,
+				Principal: infrav1.Principals{infrav1.PrincipalAWS: infrav1.PrincipalID{infrav1.Any}},
+				Action:    infrav1.Actions{"s3:*"},
+				Resource: infrav1.Resources{
+					awsarn.S3Bucket(partition, bucketName),
+					awsarn.S3Object(partition, bucketName, "*"),
+				},
+				Condition: infrav1.Conditions{
+					"Bool": map[string]string{"aws:SecureTransport": "false"},
+				},
+			},
+		},
+	}
+
+	policyRaw, err := json.Marshal(policy)
+	if err != nil {
+		return "", errors.Wrap(err, "building bucket policy")
+	}
+
+	return string(policyRaw), nil
+}
+
+// roleARN returns the ARN of the IAM role with the given name, which includes the path of the role and the
+// partition it was created in. S3 rejects a bucket policy whose principals don't exist.
+func (s *Service) roleARN(name string) (string, error) {
+	out, err := s.IAMClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(name)})
+	if err != nil {
+		if code, ok := awserrors.Code(err); ok && code == iam.ErrCodeNoSuchEntityException {
+			return "", errors.Errorf("IAM role %q given access to the bucket does not exist", name)
+		}
+		return "", errors.Wrapf(err, "getting IAM role %q", name)
+	}
+	return aws.StringValue(out.Role.Arn), nil
+}
+
+func (s *Service) bucketManagementEnabled() bool {
+	return s.scope.Bucket() != nil
+}
+
+func (s *Service) bucketName() string {
+	return s.scope.Bucket().Name
+}
@@ -0,0 +1,518 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// fakeS3 records the bucket configuration requests made by the service.
+type fakeS3 struct {
+	s3iface.S3API
+
+	createErr         error
+	deleteErr         error
+	headErr           error
+	versioningStatus  string
+	objects           []string
+	listed            *s3.ListObjectsV2Input
+	listedVersions    *s3.ListObjectVersionsInput
+	deletedObjects    []string
+	deletedVersions   []string
+	bucketDeleted     bool
+	created           *s3.CreateBucketInput
+	versioning        *s3.PutBucketVersioningInput
+	encryption        *s3.PutBucketEncryptionInput
+	publicAccessBlock *s3.PutPublicAccessBlockInput
+	lifecycle         *s3.PutBucketLifecycleConfigurationInput
+	lifecycleDeleted  bool
+	policy            *s3.PutBucketPolicyInput
+}
+
+func (f *fakeS3) CreateBucket(in *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	f.created = in
+	return &s3.CreateBucketOutput{}, f.createErr
+}
+
+func (f *fakeS3) PutBucketTagging(*s3.PutBucketTaggingInput) (*s3.PutBucketTaggingOutput, error) {
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+func (f *fakeS3) GetBucketVersioning(*s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+	out := &s3.GetBucketVersioningOutput{}
+	if f.versioningStatus != "" {
+		out.Status = aws.String(f.versioningStatus)
+	}
+	return out, nil
+}
+
+func (f *fakeS3) PutBucketVersioning(in *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error) {
+	f.versioning = in
+	return &s3.PutBucketVersioningOutput{}, nil
+}
+
+func (f *fakeS3) PutBucketEncryption(in *s3.PutBucketEncryptionInput) (*s3.PutBucketEncryptionOutput, error) {
+	f.encryption = in
+	return &s3.PutBucketEncryptionOutput{}, nil
+}
+
+func (f *fakeS3) PutPublicAccessBlock(in *s3.PutPublicAccessBlockInput) (*s3.PutPublicAccessBlockOutput, error) {
+	f.publicAccessBlock = in
+	return &s3.PutPublicAccessBlockOutput{}, nil
+}
+
+func (f *fakeS3) PutBucketLifecycleConfiguration(in *s3.PutBucketLifecycleConfigurationInput) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	f.lifecycle = in
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (f *fakeS3) DeleteBucketLifecycle(*s3.DeleteBucketLifecycleInput) (*s3.DeleteBucketLifecycleOutput, error) {
+	f.lifecycleDeleted = true
+	return &s3.DeleteBucketLifecycleOutput{}, nil
+}
+
+func (f *fakeS3) PutBucketPolicy(in *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+	f.policy = in
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (f *fakeS3) DeleteBucket(*s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+	f.bucketDeleted = true
+	return &s3.DeleteBucketOutput{}, f.deleteErr
+}
+
+func (f *fakeS3) HeadBucket(*s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	return &s3.HeadBucketOutput{}, f.headErr
+}
+
+func (f *fakeS3) ListObjectsV2Pages(in *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	f.listed = in
+	page := &s3.ListObjectsV2Output{}
+	for _, key := range f.objects {
+		page.Contents = append(page.Contents, &s3.Object{Key: aws.String(key)})
+	}
+	fn(page, true)
+	return nil
+}
+
+// ListObjectVersionsPages returns two versions and a delete marker for each object.
+func (f *fakeS3) ListObjectVersionsPages(in *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+	f.listedVersions = in
+	page := &s3.ListObjectVersionsOutput{}
+	for _, key := range f.objects {
+		page.Versions = append(page.Versions,
+			&s3.ObjectVersion{Key: aws.String(key), VersionId: aws.String("v1")},
+			&s3.ObjectVersion{Key: aws.String(key), VersionId: aws.String("v2")},
+		)
+		page.DeleteMarkers = append(page.DeleteMarkers, &s3.DeleteMarkerEntry{Key: aws.String(key), VersionId: aws.String("v3")})
+	}
+	fn(page, true)
+	return nil
+}
+
+func (f *fakeS3) DeleteObjects(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	for _, object := range in.Delete.Objects {
+		if object.VersionId != nil {
+			f.deletedVersions = append(f.deletedVersions, aws.StringValue(object.Key)+"@"+aws.StringValue(object.VersionId))
+			continue
+		}
+		f.deletedObjects = append(f.deletedObjects, aws.StringValue(object.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+// fakeIAM returns the roles of an account whose roles were created under the given path.
+type fakeIAM struct {
+	iamiface.IAMAPI
+
+	partition string
+	path      string
+}
+
+func (f *fakeIAM) GetRole(in *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	name := aws.StringValue(in.RoleName)
+	if name == "missing-role" {
+		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)
+	}
+	partition, path := f.partition, f.path
+	if partition == "" {
+		partition = "aws"
+	}
+	if path == "" {
+		path = "/"
+	}
+	return &iam.GetRoleOutput{Role: &iam.Role{
+		RoleName: in.RoleName,
+		Path:     aws.String(path),
+		Arn:      aws.String(fmt.Sprintf("arn:%s:iam::123456789012:role%s%s", partition, path, name)),
+	}}, nil
+}
+
+func TestReconcileBucket(t *testing.T) {
+	testCases := []struct {
+		name      string
+		region    string
+		bucket    *infrav1.S3Bucket
+		s3        *fakeS3
+		iam       *fakeIAM
+		expectErr bool
+		verify    func(g *WithT, f *fakeS3)
+	}{
+		{
+			name:   "does nothing when bucket is not configured",
+			region: "us-west-2",
+			s3:     &fakeS3{},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.created).To(BeNil())
+			},
+		},
+		{
+			name:   "creates bucket with secure defaults",
+			region: "us-west-2",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket"},
+			s3:     &fakeS3{},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.created.CreateBucketConfiguration.LocationConstraint).To(Equal(aws.String("us-west-2")))
+				g.Expect(f.versioning).To(BeNil())
+				g.Expect(f.encryption.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault.SSEAlgorithm).To(Equal(aws.String(s3.ServerSideEncryptionAes256)))
+				g.Expect(f.publicAccessBlock.PublicAccessBlockConfiguration).To(Equal(&s3.PublicAccessBlockConfiguration{
+					BlockPublicAcls:       aws.Bool(true),
+					IgnorePublicAcls:      aws.Bool(true),
+					BlockPublicPolicy:     aws.Bool(true),
+					RestrictPublicBuckets: aws.Bool(true),
+				}))
+				g.Expect(f.lifecycleDeleted).To(BeTrue())
+
+				policy := infrav1.PolicyDocument{}
+				g.Expect(json.Unmarshal([]byte(aws.StringValue(f.policy.Policy)), &policy)).To(Succeed())
+				g.Expect(policy.Statement[0].Principal[infrav1.PrincipalAWS]).To(ConsistOf(
+					"arn:aws:iam::123456789012:role/control-plane.cluster-api-provider-aws.sigs.k8s.io",
+					"arn:aws:iam::123456789012:role/nodes.cluster-api-provider-aws.sigs.k8s.io",
+				))
+				g.Expect(policy.Statement[0].Resource).To(ConsistOf("arn:aws:s3:::test-bucket/test-cluster/*"))
+			},
+		},
+		{
+			name:   "creates bucket in us-east-1 without location constraint",
+			region: "us-east-1",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket"},
+			s3:     &fakeS3{},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.created.CreateBucketConfiguration).To(BeNil())
+			},
+		},
+		{
+			name:   "applies hardening options",
+			region: "us-west-2",
+			bucket: &infrav1.S3Bucket{
+				Name:          "test-bucket",
+				NodesIAMRoles: []string{"custom-role"},
+				Versioning:    true,
+				Encryption:    &infrav1.S3BucketEncryption{KMSKeyID: "alias/cluster"},
+				PublicAccessBlock: &infrav1.S3BucketPublicAccessBlock{
+					BlockPublicACLs: true,
+				},
+				Lifecycle: &infrav1.S3BucketLifecycle{
+					ExpirationDays:                  7,
+					NoncurrentVersionExpirationDays: pointer.Int32(1),
+				},
+			},
+			s3: &fakeS3{},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.versioning.VersioningConfiguration.Status).To(Equal(aws.String(s3.BucketVersioningStatusEnabled)))
+
+				rule := f.encryption.ServerSideEncryptionConfiguration.Rules[0]
+				g.Expect(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm).To(Equal(aws.String(s3.ServerSideEncryptionAwsKms)))
+				g.Expect(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID).To(Equal(aws.String("alias/cluster")))
+				g.Expect(rule.BucketKeyEnabled).To(Equal(aws.Bool(true)))
+
+				g.Expect(f.publicAccessBlock.PublicAccessBlockConfiguration.BlockPublicAcls).To(Equal(aws.Bool(true)))
+				g.Expect(f.publicAccessBlock.PublicAccessBlockConfiguration.BlockPublicPolicy).To(Equal(aws.Bool(false)))
+
+				lifecycleRule := f.lifecycle.LifecycleConfiguration.Rules[0]
+				g.Expect(lifecycleRule.Filter.Prefix).To(Equal(aws.String("test-cluster/")))
+				g.Expect(lifecycleRule.Expiration.Days).To(Equal(aws.Int64(7)))
+				g.Expect(lifecycleRule.NoncurrentVersionExpiration.NoncurrentDays).To(Equal(aws.Int64(1)))
+
+				policy := infrav1.PolicyDocument{}
+				g.Expect(json.Unmarshal([]byte(aws.StringValue(f.policy.Policy)), &policy)).To(Succeed())
+				g.Expect(policy.Statement[0].Principal[infrav1.PrincipalAWS]).To(ConsistOf("arn:aws:iam::123456789012:role/custom-role"))
+			},
+		},
+		{
+			name:   "suspends versioning previously enabled",
+			region: "us-west-2",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket"},
+			s3:     &fakeS3{versioningStatus: s3.BucketVersioningStatusEnabled},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.versioning.VersioningConfiguration.Status).To(Equal(aws.String(s3.BucketVersioningStatusSuspended)))
+			},
+		},
+		{
+			name:   "does not error if bucket is already owned",
+			region: "us-west-2",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket"},
+			s3:     &fakeS3{createErr: awserr.New(s3.ErrCodeBucketAlreadyOwnedByYou, "", nil)},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.policy).NotTo(BeNil())
+			},
+		},
+		{
+			name:   "only checks existence of unmanaged bucket",
+			region: "us-west-2",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket", Unmanaged: true},
+			s3:     &fakeS3{},
+			verify: func(g *WithT, f *fakeS3) {
+				g.Expect(f.created).To(BeNil())
+				g.Expect(f.encryption).To(BeNil())
+				g.Expect(f.publicAccessBlock).To(BeNil())
+				g.Expect(f.policy).To(BeNil())
+			},
+		},
+		{
+			name:      "errors if unmanaged bucket does not exist",
+			region:    "us-west-2",
+			bucket:    &infrav1.S3Bucket{Name: "test-bucket", Unmanaged: true},
+			s3:        &fakeS3{headErr: awserr.New("NotFound", "", nil)},
+			expectErr: true,
+		},
+		{
+			name:   "grants access to the ARNs of roles created under a path",
+			region: "cn-north-1",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket", NodesIAMRoles: []string{"nodes"}},
+			s3:     &fakeS3{},
+			iam:    &fakeIAM{partition: "aws-cn", path: "/capa/"},
+			verify: func(g *WithT, f *fakeS3) {
+				policy := infrav1.PolicyDocument{}
+				g.Expect(json.Unmarshal([]byte(aws.StringValue(f.policy.Policy)), &policy)).To(Succeed())
+				g.Expect(policy.Statement[0].Principal[infrav1.PrincipalAWS]).To(ConsistOf("arn:aws-cn:iam::123456789012:role/capa/nodes"))
+				g.Expect(policy.Statement[0].Resource).To(ConsistOf("arn:aws-cn:s3:::test-bucket/test-cluster/*"))
+			},
+		},
+		{
+			name:      "errors if a role given access doesn't exist",
+			region:    "us-west-2",
+			bucket:    &infrav1.S3Bucket{Name: "test-bucket", NodesIAMRoles: []string{"missing-role"}},
+			s3:        &fakeS3{},
+			expectErr: true,
+		},
+		{
+			name:   "restricts node access to key prefix",
+			region: "us-west-2",
+			bucket: &infrav1.S3Bucket{Name: "test-bucket", KeyPrefix: "/team-a/cluster/"},
+			s3:     &fakeS3{},
+			verify: func(g *WithT, f *fakeS3) {
+				policy := infrav1.PolicyDocument{}
+				g.Expect(json.Unmarshal([]byte(aws.StringValue(f.policy.Policy)), &policy)).To(Succeed())
+				g.Expect(policy.Statement[0].Resource).To(ConsistOf("arn:aws:s3:::test-bucket/team-a/cluster/*"))
+			},
+		},
+		{
+			name:      "errors if bucket name is taken",
+			region:    "us-west-2",
+			bucket:    &infrav1.S3Bucket{Name: "test-bucket"},
+			s3:        &fakeS3{createErr: awserr.New(s3.ErrCodeBucketAlreadyExists, "", nil)},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster(tc.region, tc.bucket)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := NewService(clusterScope)
+			s.S3Client = tc.s3
+			s.IAMClient = &fakeIAM{}
+			if tc.iam != nil {
+				s.IAMClient = tc.iam
+			}
+
+			err = s.ReconcileBucket()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.verify(g, tc.s3)
+		})
+	}
+}
+
+func TestDeleteBucket(t *testing.T) {
+	testCases := []struct {
+		name      string
+		deleteErr error
+		expectErr bool
+	}{
+		{
+			name: "deletes bucket",
+		},
+		{
+			name:      "does not error if bucket is already deleted",
+			deleteErr: awserr.New(s3.ErrCodeNoSuchBucket, "", nil),
+		},
+		{
+			name:      "does not error if bucket is not empty",
+			deleteErr: awserr.New(errCodeBucketNotEmpty, "", nil),
+		},
+		{
+			name:      "errors on unexpected error",
+			deleteErr: awserr.New("AccessDenied", "", nil),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster("us-west-2", &infrav1.S3Bucket{Name: "test-bucket"})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := NewService(clusterScope)
+			s.S3Client = &fakeS3{deleteErr: tc.deleteErr}
+
+			err = s.DeleteBucket()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBucketObjects(t *testing.T) {
+	testCases := []struct {
+		name                string
+		bucket              *infrav1.S3Bucket
+		expectPrefix        string
+		expectBucketDeleted bool
+	}{
+		{
+			name:                "deletes objects and managed bucket",
+			bucket:              &infrav1.S3Bucket{Name: "test-bucket"},
+			expectPrefix:        "test-cluster/",
+			expectBucketDeleted: true,
+		},
+		{
+			name:                "only deletes objects under prefix of unmanaged bucket",
+			bucket:              &infrav1.S3Bucket{Name: "test-bucket", Unmanaged: true, KeyPrefix: "team-a"},
+			expectPrefix:        "team-a/",
+			expectBucketDeleted: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster("us-west-2", tc.bucket)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			f := &fakeS3{objects: []string{tc.expectPrefix + "node/machine-1"}}
+			s := NewService(clusterScope)
+			s.S3Client = f
+
+			g.Expect(s.DeleteBucket()).To(Succeed())
+			g.Expect(f.listed.Prefix).To(Equal(aws.String(tc.expectPrefix)))
+			g.Expect(f.deletedObjects).To(ConsistOf(tc.expectPrefix + "node/machine-1"))
+			g.Expect(f.bucketDeleted).To(Equal(tc.expectBucketDeleted))
+		})
+	}
+}
+
+func TestDeleteVersionedBucket(t *testing.T) {
+	testCases := []struct {
+		name             string
+		versioningStatus string
+	}{
+		{
+			name:             "deletes object versions and delete markers of a versioned bucket",
+			versioningStatus: s3.BucketVersioningStatusEnabled,
+		},
+		{
+			name:             "deletes object versions of a bucket whose versioning is suspended",
+			versioningStatus: s3.BucketVersioningStatusSuspended,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster("us-west-2", &infrav1.S3Bucket{Name: "test-bucket", Versioning: true})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			f := &fakeS3{versioningStatus: tc.versioningStatus, objects: []string{"test-cluster/node/machine-1"}}
+			s := NewService(clusterScope)
+			s.S3Client = f
+
+			g.Expect(s.DeleteBucket()).To(Succeed())
+			g.Expect(f.listed).To(BeNil())
+			g.Expect(f.listedVersions.Prefix).To(Equal(aws.String("test-cluster/")))
+			g.Expect(f.deletedObjects).To(BeEmpty())
+			g.Expect(f.deletedVersions).To(ConsistOf(
+				"test-cluster/node/machine-1@v1",
+				"test-cluster/node/machine-1@v2",
+				"test-cluster/node/machine-1@v3",
+			))
+			g.Expect(f.bucketDeleted).To(BeTrue())
+		})
+	}
+}
+
+func setupCluster(region string, bucket *infrav1.S3Bucket) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			Region:   region,
+			S3Bucket: bucket,
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).Build()
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// deleteObjects deletes all objects stored under the given key prefix. If the bucket has or had versioning enabled,
+// all versions and delete markers of the objects are deleted, as the bucket can't be deleted while they remain.
+func (s *Service) deleteObjects(bucket, prefix string) error {
+	versioning, err := s.S3Client.GetBucketVersioning(&s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return errors.Wrap(err, "getting S3 bucket versioning")
+	}
+	if aws.StringValue(versioning.Status) != "" {
+		return s.deleteObjectVersions(bucket, prefix)
+	}
+
+	var objectsErr error
+
+	err = s.S3Client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix + "/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: object.Key})
+		}
+
+		objectsErr = s.deleteObjectIdentifiers(bucket, objects)
+		return objectsErr == nil
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return errors.Wrap(err, "listing S3 objects")
+	}
+
+	return objectsErr
+}
+
+// deleteObjectVersions deletes all versions and delete markers of the objects stored under the given key prefix.
+func (s *Service) deleteObjectVersions(bucket, prefix string) error {
+	var objectsErr error
+
+	err := s.S3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix + "/"),
+	}, func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+		// A page holds at most 1000 versions and delete markers together.
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
+		for _, version := range page.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range page.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+
+		objectsErr = s.deleteObjectIdentifiers(bucket, objects)
+		return objectsErr == nil
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return errors.Wrap(err, "listing S3 object versions")
+	}
+
+	return objectsErr
+}
+
+// deleteObjectIdentifiers deletes the given objects, which must not be more than the 1000 a single DeleteObjects
+// request accepts.
+func (s *Service) deleteObjectIdentifiers(bucket string, objects []*s3.ObjectIdentifier) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	out, err := s.S3Client.DeleteObjects(&s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(out.Errors) > 0 {
+		return errors.Errorf("failed to delete %d objects, first error: %s", len(out.Errors), aws.StringValue(out.Errors[0].Message))
+	}
+
+	s.scope.V(2).Info("Deleted objects", "bucket_name", bucket, "count", len(objects))
+	return nil
+}
+
+func (s *Service) keyPrefix() string {
+	if prefix := strings.Trim(s.scope.Bucket().KeyPrefix, "/"); prefix != "" {
+		return prefix
+	}
+
+	return s.scope.Name()
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the ec2 client.
+type Service struct {
+	scope     scope.S3Scope
+	S3Client  s3iface.S3API
+	IAMClient iamiface.IAMAPI
+}
+
+// NewService returns a new service given the api clients.
+func NewService(s3Scope scope.S3Scope) *Service {
+	return &Service{
+		scope:     s3Scope,
+		S3Client:  scope.NewS3Client(s3Scope, s3Scope, s3Scope, s3Scope.InfraCluster()),
+		IAMClient: scope.NewIAMClient(s3Scope, s3Scope, s3Scope, s3Scope.InfraCluster()),
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileSecurityGroupPolicy checks the ingress rules of the security groups managed for the cluster against its
+// security group policy, and reports the violations with the SecurityGroupPolicyCompliant condition.
+func (s *Service) reconcileSecurityGroupPolicy(rules map[infrav1.SecurityGroupRole]infrav1.IngressRules) {
+	policy := s.scope.SecurityGroupPolicy()
+	if policy == nil {
+		conditions.Delete(s.scope.InfraCluster(), infrav1.SecurityGroupPolicyCompliantCondition)
+		return
+	}
+
+	violations := securityGroupPolicyViolations(policy, rules, s.scope.SecurityGroups())
+	if len(violations) > 0 {
+		s.scope.Info("Security group ingress rules violate the security group policy", "violations", violations)
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SecurityGroupPolicyCompliantCondition, infrav1.SecurityGroupPolicyViolationReason,
+			clusterv1.ConditionSeverityWarning, "%s", strings.Join(violations, "; "))
+		return
+	}
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.SecurityGroupPolicyCompliantCondition)
+}
+
+// securityGroupPolicyViolations describes the ingress rules between the security groups of the cluster which open
+// ports the policy does not allow. Rules without a security group of the cluster as source are not checked.
+func securityGroupPolicyViolations(policy *infrav1.SecurityGroupPolicy, rules map[infrav1.SecurityGroupRole]infrav1.IngressRules,
+	groups map[infrav1.SecurityGroupRole]infrav1.SecurityGroup) []string {
+	groupRoles := map[string]infrav1.SecurityGroupRole{}
+	for role, sg := range groups {
+		if sg.ID != "" {
+			groupRoles[sg.ID] = role
+		}
+	}
+
+	roles := make([]string, 0, len(rules))
+	for role := range rules {
+		roles = append(roles, string(role))
+	}
+	sort.Strings(roles)
+
+	violations := []string{}
+	for _, role := range roles {
+		for _, rule := range rules[infrav1.SecurityGroupRole(role)] {
+			sources := []string{}
+			for _, id := range rule.SourceSecurityGroupIDs {
+				if source, ok := groupRoles[id]; ok {
+					sources = append(sources, string(source))
+				}
+			}
+			if len(sources) == 0 || policyAllows(policy, rule) {
+				continue
+			}
+			violations = append(violations, fmt.Sprintf("%s %s from %s to %s", rule.Description, rulePorts(rule), strings.Join(sources, ","), role))
+		}
+	}
+	return violations
+}
+
+// policyAllows returns whether the protocol and all the ports of the rule are within a port range of the policy.
+func policyAllows(policy *infrav1.SecurityGroupPolicy, rule infrav1.IngressRule) bool {
+	for _, allowed := range policy.AllowedPorts {
+		switch {
+		case allowed.Protocol == infrav1.SecurityGroupProtocolAll:
+			return true
+		case allowed.Protocol != rule.Protocol:
+			continue
+		}
+		switch rule.Protocol {
+		case infrav1.SecurityGroupProtocolTCP,
+			infrav1.SecurityGroupProtocolUDP,
+			infrav1.SecurityGroupProtocolICMP,
+			infrav1.SecurityGroupProtocolICMPv6:
+			if rule.FromPort >= allowed.FromPort && rule.ToPort <= allowed.ToPort {
+				return true
+			}
+		default:
+			// The other protocols have no ports.
+			return true
+		}
+	}
+	return false
+}
+
+// rulePorts describes the protocol and ports of a rule.
+func rulePorts(rule infrav1.IngressRule) string {
+	switch rule.Protocol {
+	case infrav1.SecurityGroupProtocolAll:
+		return "(all traffic)"
+	case infrav1.SecurityGroupProtocolTCP, infrav1.SecurityGroupProtocolUDP:
+		return fmt.Sprintf("(%s %d-%d)", rule.Protocol, rule.FromPort, rule.ToPort)
+	}
+	return fmt.Sprintf("(protocol %s)", rule.Protocol)
+}
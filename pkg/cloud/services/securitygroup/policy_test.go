@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroup
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileSecurityGroupPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	kubernetesPorts := []infrav1.SecurityGroupPortRange{
+		{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 22, ToPort: 22},
+		{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 2379, ToPort: 2380},
+		{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443},
+		{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 10250, ToPort: 10250},
+	}
+
+	testCases := []struct {
+		name             string
+		policy           *infrav1.SecurityGroupPolicy
+		allowNodeToNode  bool
+		expectCondition  bool
+		expectCompliant  bool
+		expectViolations []string
+	}{
+		{
+			name: "no condition without a policy",
+		},
+		{
+			name:            "compliant when the policy allows all the ports between the security groups",
+			policy:          &infrav1.SecurityGroupPolicy{AllowedPorts: append(kubernetesPorts, infrav1.SecurityGroupPortRange{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 179, ToPort: 179})},
+			expectCondition: true,
+			expectCompliant: true,
+		},
+		{
+			name:            "reports the CNI ports the policy does not allow",
+			policy:          &infrav1.SecurityGroupPolicy{AllowedPorts: kubernetesPorts},
+			expectCondition: true,
+			expectViolations: []string{
+				"bgp (tcp 179-179) from controlplane,node to controlplane",
+				"bgp (tcp 179-179) from controlplane,node to node",
+			},
+		},
+		{
+			name:            "reports all traffic between nodes unless the policy allows all protocols",
+			policy:          &infrav1.SecurityGroupPolicy{AllowedPorts: append(kubernetesPorts, infrav1.SecurityGroupPortRange{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 0, ToPort: 65535})},
+			allowNodeToNode: true,
+			expectCondition: true,
+			expectViolations: []string{
+				"All traffic between nodes (all traffic) from node to node",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							CNI: &infrav1.CNISpec{
+								CNIIngressRules: infrav1.CNIIngressRules{
+									{Description: "bgp", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 179, ToPort: 179},
+								},
+							},
+							AllowNodeToNodeTraffic: tc.allowNodeToNode,
+							SecurityGroupPolicy:    tc.policy,
+						},
+					},
+					Status: infrav1.AWSClusterStatus{
+						Network: infrav1.Network{
+							SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+								infrav1.SecurityGroupBastion:      {ID: "sg-bastion"},
+								infrav1.SecurityGroupAPIServerLB:  {ID: "sg-apiserver-lb"},
+								infrav1.SecurityGroupControlPlane: {ID: "sg-controlplane"},
+								infrav1.SecurityGroupNode:         {ID: "sg-node"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			s := NewService(scope)
+			rules := map[infrav1.SecurityGroupRole]infrav1.IngressRules{}
+			for _, role := range []infrav1.SecurityGroupRole{infrav1.SecurityGroupAPIServerLB, infrav1.SecurityGroupControlPlane, infrav1.SecurityGroupNode} {
+				rules[role], err = s.getSecurityGroupIngressRules(role)
+				if err != nil {
+					t.Fatalf("Failed to lookup %s security group ingress rules: %v", role, err)
+				}
+			}
+
+			s.reconcileSecurityGroupPolicy(rules)
+
+			condition := conditions.Get(scope.AWSCluster, infrav1.SecurityGroupPolicyCompliantCondition)
+			if (condition != nil) != tc.expectCondition {
+				t.Fatalf("Expected condition to be set: %t, got %v", tc.expectCondition, condition)
+			}
+			if condition == nil {
+				return
+			}
+			if compliant := conditions.IsTrue(scope.AWSCluster, infrav1.SecurityGroupPolicyCompliantCondition); compliant != tc.expectCompliant {
+				t.Fatalf("Expected compliant: %t, got %t", tc.expectCompliant, compliant)
+			}
+
+			violations := securityGroupPolicyViolations(tc.policy, rules, scope.SecurityGroups())
+			if len(tc.expectViolations) == 0 {
+				tc.expectViolations = []string{}
+			}
+			if !reflect.DeepEqual(violations, tc.expectViolations) {
+				t.Fatalf("Expected violations %v, got %v", tc.expectViolations, violations)
+			}
+		})
+	}
+}
+
+func TestPolicyAllows(t *testing.T) {
+	policy := &infrav1.SecurityGroupPolicy{
+		AllowedPorts: []infrav1.SecurityGroupPortRange{
+			{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30000, ToPort: 32767},
+			{Protocol: infrav1.SecurityGroupProtocolIPinIP, FromPort: -1, ToPort: -1},
+		},
+	}
+
+	testCases := []struct {
+		rule   infrav1.IngressRule
+		expect bool
+	}{
+		{rule: infrav1.IngressRule{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30000, ToPort: 32767}, expect: true},
+		{rule: infrav1.IngressRule{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 31000, ToPort: 31000}, expect: true},
+		{rule: infrav1.IngressRule{Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 29999, ToPort: 30000}, expect: false},
+		{rule: infrav1.IngressRule{Protocol: infrav1.SecurityGroupProtocolUDP, FromPort: 31000, ToPort: 31000}, expect: false},
+		{rule: infrav1.IngressRule{Protocol: infrav1.SecurityGroupProtocolIPinIP, FromPort: -1, ToPort: -1}, expect: true},
+		{rule: infrav1.IngressRule{Protocol: infrav1.SecurityGroupProtocolAll, FromPort: -1, ToPort: -1}, expect: false},
+	}
+
+	for _, tc := range testCases {
+		if got := policyAllows(policy, tc.rule); got != tc.expect {
+			t.Errorf("Expected %s %d-%d to be allowed: %t, got %t", tc.rule.Protocol, tc.rule.FromPort, tc.rule.ToPort, tc.expect, got)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
@@ -146,6 +147,7 @@ func (s *Service) ReconcileSecurityGroups() error {
 
 	// Second iteration creates or updates all permissions on the security group to match
 	// the specified ingress rules.
+	managedRules := map[infrav1.SecurityGroupRole]infrav1.IngressRules{}
 	for i := range s.scope.SecurityGroups() {
 		sg := s.scope.SecurityGroups()[i]
 		s.scope.V(2).Info("second pass security group reconciliation", "group-id", sg.ID, "name", sg.Name, "role", i)
@@ -165,6 +167,7 @@ func (s *Service) ReconcileSecurityGroups() error {
 		if err != nil {
 			return err
 		}
+		managedRules[i] = want
 
 		toRevoke := current.Difference(want)
 		if len(toRevoke) > 0 {
@@ -194,10 +197,62 @@ func (s *Service) ReconcileSecurityGroups() error {
 			s.scope.V(2).Info("Authorized ingress rules in security group", "authorized-ingress-rules", toAuthorize, "security-group-id", sg.ID)
 		}
 	}
+	s.reconcileSecurityGroupPolicy(managedRules)
 	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.ClusterSecurityGroupsReadyCondition)
 	return nil
 }
 
+// DiscoverSecurityGroups populates the security groups of an externally managed cluster,
+// without creating or modifying any of them. A role uses its security group override if
+// one is set, otherwise the security group tagged for the cluster with its default name
+// or with its role tag.
+func (s *Service) DiscoverSecurityGroups() error {
+	s.scope.V(2).Info("Discovering security groups")
+
+	if s.scope.Network().SecurityGroups == nil {
+		s.scope.Network().SecurityGroups = make(map[infrav1.SecurityGroupRole]infrav1.SecurityGroup)
+	}
+
+	securityGroupOverrides, err := s.describeSecurityGroupOverridesByID()
+	if err != nil {
+		return err
+	}
+
+	sgs, err := s.describeSecurityGroupsByName()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range s.roles {
+		if sgOverride, ok := securityGroupOverrides[role]; ok {
+			s.scope.SecurityGroups()[role] = s.ec2SecurityGroupToSecurityGroup(sgOverride)
+			continue
+		}
+
+		if sg, ok := sgs[s.getSecurityGroupName(s.scope.Name(), role)]; ok {
+			s.scope.SecurityGroups()[role] = sg
+			continue
+		}
+
+		var found []infrav1.SecurityGroup
+		for _, sg := range sgs {
+			if sg.Tags.GetRole() == string(role) {
+				found = append(found, sg)
+			}
+		}
+		switch len(found) {
+		case 0:
+			s.scope.V(2).Info("No security group found for role", "role", role)
+		case 1:
+			s.scope.SecurityGroups()[role] = found[0]
+		default:
+			return awserrors.NewConflict(fmt.Sprintf("found %d security groups tagged for cluster %q with role %q", len(found), s.scope.Name(), role))
+		}
+	}
+
+	return nil
+}
+
 func (s *Service) securityGroupIsOverridden(securityGroupID string) bool {
 	for _, overrideID := range s.scope.SecurityGroupOverrides() {
 		if overrideID == securityGroupID {
@@ -218,6 +273,10 @@ func (s *Service) describeSecurityGroupOverridesByID() (map[infrav1.SecurityGrou
 		return nil, nil
 	}
 
+	if s.scope.SecurityGroupOverrideTracking() == infrav1.SecurityGroupOverrideTrackingTags {
+		return s.describeSecurityGroupOverridesByTags()
+	}
+
 	if len(overrides) > 0 {
 		for _, role := range defaultRoles {
 			securityGroupID, ok := s.scope.SecurityGroupOverrides()[role]
@@ -251,6 +310,64 @@ func (s *Service) describeSecurityGroupOverridesByID() (map[infrav1.SecurityGrou
 	return res, nil
 }
 
+// describeSecurityGroupOverridesByTags looks up the security group overrides among the security groups of the
+// VPC tagged for the cluster. An override which no longer exists, or no longer carries the tag of its role, is
+// replaced in the spec by the only other security group tagged with that role.
+func (s *Service) describeSecurityGroupOverridesByTags() (map[infrav1.SecurityGroupRole]*ec2.SecurityGroup, error) {
+	out, err := s.EC2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.Cluster(s.scope.Name()),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe security groups in vpc %q", s.scope.VPC().ID)
+	}
+
+	overrides := s.scope.SecurityGroupOverrides()
+	res := make(map[infrav1.SecurityGroupRole]*ec2.SecurityGroup, len(overrides))
+	for _, role := range defaultRoles {
+		overrideID, ok := overrides[role]
+		if !ok {
+			continue
+		}
+
+		var current *ec2.SecurityGroup
+		var candidates []*ec2.SecurityGroup
+		for _, ec2sg := range out.SecurityGroups {
+			if converters.TagsToMap(ec2sg.Tags).GetRole() != string(role) {
+				continue
+			}
+			if aws.StringValue(ec2sg.GroupId) == overrideID {
+				current = ec2sg
+				break
+			}
+			candidates = append(candidates, ec2sg)
+		}
+
+		if current == nil {
+			switch len(candidates) {
+			case 0:
+				return nil, errors.Errorf("security group override %q for role %q not found among the security groups of vpc %q tagged for the cluster with this role",
+					overrideID, role, s.scope.VPC().ID)
+			case 1:
+				current = candidates[0]
+			default:
+				return nil, awserrors.NewConflict(fmt.Sprintf("found %d security groups tagged for cluster %q with role %q to replace security group override %q",
+					len(candidates), s.scope.Name(), role, overrideID))
+			}
+
+			overrides[role] = aws.StringValue(current.GroupId)
+			s.scope.Info("Replaced security group override", "role", role, "previous-id", overrideID, "id", overrides[role])
+			record.Eventf(s.scope.InfraCluster(), "SecurityGroupOverrideReplaced", "Replaced %s security group override %q by %q", role, overrideID, overrides[role])
+		}
+
+		res[role] = current
+	}
+
+	return res, nil
+}
+
 func (s *Service) ec2SecurityGroupToSecurityGroup(ec2SecurityGroup *ec2.SecurityGroup) infrav1.SecurityGroup {
 	sg := infrav1.SecurityGroup{
 		ID:   *ec2SecurityGroup.GroupId,
@@ -491,7 +608,7 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 
 	switch role {
 	case infrav1.SecurityGroupBastion:
-		return infrav1.IngressRules{
+		rules := infrav1.IngressRules{
 			{
 				Description: "SSH",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
@@ -499,15 +616,26 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				ToPort:      22,
 				CidrBlocks:  s.scope.Bastion().AllowedCIDRBlocks,
 			},
-		}, nil
+		}
+		// The health checks of a network load balancer come from its private IPs in the VPC.
+		if autoScaling := s.scope.Bastion().AutoScaling; autoScaling != nil && autoScaling.LoadBalancer && !s.scope.Bastion().DisableIngressRules {
+			rules = append(rules, infrav1.IngressRule{
+				Description: "Bastion load balancer health checks",
+				Protocol:    infrav1.SecurityGroupProtocolTCP,
+				FromPort:    22,
+				ToPort:      22,
+				CidrBlocks:  []string{s.scope.VPC().CidrBlock},
+			})
+		}
+		return rules, nil
 	case infrav1.SecurityGroupControlPlane:
 		rules := infrav1.IngressRules{
 			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
 			{
 				Description: "Kubernetes API",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
-				FromPort:    6443,
-				ToPort:      6443,
+				FromPort:    int64(s.scope.APIServerInstancePort()),
+				ToPort:      int64(s.scope.APIServerInstancePort()),
 				SourceSecurityGroupIDs: []string{
 					s.scope.SecurityGroups()[infrav1.SecurityGroupAPIServerLB].ID,
 					s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
@@ -553,19 +681,32 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				},
 			},
 		}
+		if s.scope.AllowNodeToNodeTraffic() {
+			rules = append(rules, infrav1.IngressRule{
+				Description:            "All traffic between nodes",
+				Protocol:               infrav1.SecurityGroupProtocolAll,
+				FromPort:               -1,
+				ToPort:                 -1,
+				SourceSecurityGroupIDs: []string{s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID},
+			})
+		}
 		return append(cniRules, rules...), nil
 	case infrav1.SecurityGroupEKSNodeAdditional:
 		return infrav1.IngressRules{
 			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
 		}, nil
 	case infrav1.SecurityGroupAPIServerLB:
+		cidrBlocks, err := s.apiServerLBCidrBlocks()
+		if err != nil {
+			return nil, err
+		}
 		return infrav1.IngressRules{
 			{
 				Description: "Kubernetes API",
 				Protocol:    infrav1.SecurityGroupProtocolTCP,
 				FromPort:    int64(s.scope.APIServerPort()),
 				ToPort:      int64(s.scope.APIServerPort()),
-				CidrBlocks:  []string{services.AnyIPv4CidrBlock},
+				CidrBlocks:  cidrBlocks,
 			},
 		}, nil
 	case infrav1.SecurityGroupLB:
@@ -576,6 +717,90 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 	return nil, errors.Errorf("Cannot determine ingress rules for unknown security group role %q", role)
 }
 
+// apiServerLBCidrBlocks returns the CIDR blocks that can reach the API server through its load balancer.
+// When the load balancer restricts them, the machines of the cluster must still be able to reach it: from
+// within the VPC, or through the NAT gateways of the VPC if the load balancer is internet-facing.
+func (s *Service) apiServerLBCidrBlocks() ([]string, error) {
+	lb := s.scope.ControlPlaneLoadBalancer()
+	if lb == nil || len(lb.AllowedCIDRBlocks) == 0 {
+		return []string{services.AnyIPv4CidrBlock}, nil
+	}
+
+	cidrBlocks := sets.NewString(lb.AllowedCIDRBlocks...)
+	if s.scope.VPC().CidrBlock != "" {
+		cidrBlocks.Insert(s.scope.VPC().CidrBlock)
+	}
+
+	if lb.Scheme == nil || *lb.Scheme == infrav1.ClassicELBSchemeInternetFacing {
+		ips, err := s.describeNatGatewayPublicIPs()
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			cidrBlocks.Insert(ip + "/32")
+		}
+	}
+
+	return cidrBlocks.List(), nil
+}
+
+// describeNatGatewayPublicIPs returns the public IPs of the available NAT gateways of the VPC.
+func (s *Service) describeNatGatewayPublicIPs() ([]string, error) {
+	if s.scope.VPC().ID == "" {
+		return nil, nil
+	}
+
+	var ips []string
+	input := &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.NATGatewayStates(ec2.NatGatewayStateAvailable),
+		},
+	}
+	if err := s.EC2Client.DescribeNatGatewaysPages(input, func(out *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+		for _, gateway := range out.NatGateways {
+			for _, address := range gateway.NatGatewayAddresses {
+				if address.PublicIp != nil {
+					ips = append(ips, *address.PublicIp)
+				}
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe NAT gateways of vpc %q", s.scope.VPC().ID)
+	}
+
+	return ips, nil
+}
+
+// RequiredIngressRules returns the largest number of ingress rules that any one of the security groups
+// managed for the cluster needs. Every source CIDR block and security group of a rule counts separately,
+// as they do towards the rules per security group quota.
+func (s *Service) RequiredIngressRules() (int, error) {
+	most := 0
+	for _, role := range s.roles {
+		if _, ok := s.scope.SecurityGroupOverrides()[role]; ok {
+			continue
+		}
+		rules, err := s.getSecurityGroupIngressRules(role)
+		if err != nil {
+			return 0, err
+		}
+		count := 0
+		for _, rule := range rules {
+			if sources := len(rule.CidrBlocks) + len(rule.SourceSecurityGroupIDs); sources > 0 {
+				count += sources
+			} else {
+				count++
+			}
+		}
+		if count > most {
+			most = count
+		}
+	}
+	return most, nil
+}
+
 func (s *Service) getSecurityGroupName(clusterName string, role infrav1.SecurityGroupRole) string {
 	groupPrefix := clusterName
 	if strings.HasPrefix(clusterName, "sg-") {
@@ -599,6 +824,9 @@ func (s *Service) getSecurityGroupTagParams(name, id string, role infrav1.Securi
 	if role == infrav1.SecurityGroupLB {
 		additional[infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name())] = string(infrav1.ResourceLifecycleOwned)
 	}
+	if role == infrav1.SecurityGroupNode && s.scope.Karpenter() != nil {
+		additional[infrav1.NameKarpenterDiscovery] = s.scope.Name()
+	}
 	return infrav1.BuildParams{
 		ClusterName: s.scope.Name(),
 		Lifecycle:   infrav1.ResourceLifecycleOwned,
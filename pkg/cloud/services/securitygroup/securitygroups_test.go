@@ -31,6 +31,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
@@ -361,6 +363,218 @@ func TestReconcileSecurityGroups(t *testing.T) {
 	}
 }
 
+func TestDescribeSecurityGroupOverridesByTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	nodeSG := func(id string, role infrav1.SecurityGroupRole) *ec2.SecurityGroup {
+		return &ec2.SecurityGroup{
+			GroupId:   aws.String(id),
+			GroupName: aws.String(id),
+			Tags: []*ec2.Tag{
+				{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String("shared")},
+				{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(string(role))},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		groups     []*ec2.SecurityGroup
+		expectID   string
+		expectErr  bool
+		isConflict bool
+	}{
+		{
+			name:     "keeps the override while it carries its role tag",
+			groups:   []*ec2.SecurityGroup{nodeSG("sg-node", infrav1.SecurityGroupNode), nodeSG("sg-node-new", infrav1.SecurityGroupNode)},
+			expectID: "sg-node",
+		},
+		{
+			name:     "replaces an override which no longer exists",
+			groups:   []*ec2.SecurityGroup{nodeSG("sg-node-new", infrav1.SecurityGroupNode), nodeSG("sg-lb", infrav1.SecurityGroupLB)},
+			expectID: "sg-node-new",
+		},
+		{
+			name:     "replaces an override which no longer carries its role tag",
+			groups:   []*ec2.SecurityGroup{nodeSG("sg-node", infrav1.SecurityGroupLB), nodeSG("sg-node-new", infrav1.SecurityGroupNode)},
+			expectID: "sg-node-new",
+		},
+		{
+			name:      "returns error when no security group is tagged with the role",
+			groups:    []*ec2.SecurityGroup{nodeSG("sg-lb", infrav1.SecurityGroupLB)},
+			expectErr: true,
+		},
+		{
+			name:       "returns conflict when several security groups are tagged with the role",
+			groups:     []*ec2.SecurityGroup{nodeSG("sg-node-a", infrav1.SecurityGroupNode), nodeSG("sg-node-b", infrav1.SecurityGroupNode)},
+			expectErr:  true,
+			isConflict: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{ID: "vpc-securitygroups"},
+							SecurityGroupOverrides: map[infrav1.SecurityGroupRole]string{
+								infrav1.SecurityGroupNode: "sg-node",
+							},
+							SecurityGroupOverrideTracking: infrav1.SecurityGroupOverrideTrackingTags,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{
+				Filters: []*ec2.Filter{
+					filter.EC2.VPC("vpc-securitygroups"),
+					filter.EC2.Cluster("test-cluster"),
+				},
+			})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: tc.groups}, nil)
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			overrides, err := s.describeSecurityGroupOverridesByID()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				if awserrors.IsConflict(err) != tc.isConflict {
+					t.Fatalf("Expected conflict: %t, got %v", tc.isConflict, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if got := aws.StringValue(overrides[infrav1.SecurityGroupNode].GroupId); got != tc.expectID {
+				t.Fatalf("Expected node security group %q, got %q", tc.expectID, got)
+			}
+			if got := scope.SecurityGroupOverrides()[infrav1.SecurityGroupNode]; got != tc.expectID {
+				t.Fatalf("Expected node security group override %q in the spec, got %q", tc.expectID, got)
+			}
+		})
+	}
+}
+
+func TestDiscoverSecurityGroups(t *testing.T) {
+	roleTags := func(role infrav1.SecurityGroupRole) []*ec2.Tag {
+		return []*ec2.Tag{
+			{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String("shared")},
+			{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(string(role))},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		groups []*ec2.SecurityGroup
+		expect map[infrav1.SecurityGroupRole]string
+		err    error
+	}{
+		{
+			name: "finds security groups by their default name",
+			groups: []*ec2.SecurityGroup{
+				{GroupId: aws.String("sg-node"), GroupName: aws.String("test-cluster-node")},
+				{GroupId: aws.String("sg-control"), GroupName: aws.String("test-cluster-controlplane")},
+			},
+			expect: map[infrav1.SecurityGroupRole]string{
+				infrav1.SecurityGroupNode:         "sg-node",
+				infrav1.SecurityGroupControlPlane: "sg-control",
+			},
+		},
+		{
+			name: "finds security groups by their role tag",
+			groups: []*ec2.SecurityGroup{
+				{GroupId: aws.String("sg-node"), GroupName: aws.String("terraform-workers"), Tags: roleTags(infrav1.SecurityGroupNode)},
+				{GroupId: aws.String("sg-lb"), GroupName: aws.String("terraform-lb"), Tags: roleTags(infrav1.SecurityGroupLB)},
+			},
+			expect: map[infrav1.SecurityGroupRole]string{
+				infrav1.SecurityGroupNode: "sg-node",
+				infrav1.SecurityGroupLB:   "sg-lb",
+			},
+		},
+		{
+			name: "fails when several security groups are tagged with the same role",
+			groups: []*ec2.SecurityGroup{
+				{GroupId: aws.String("sg-node-1"), GroupName: aws.String("terraform-workers-1"), Tags: roleTags(infrav1.SecurityGroupNode)},
+				{GroupId: aws.String("sg-node-2"), GroupName: aws.String("terraform-workers-2"), Tags: roleTags(infrav1.SecurityGroupNode)},
+			},
+			err: errors.New(`found 2 security groups tagged for cluster "test-cluster" with role "node"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-external"}},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			ec2Mock.EXPECT().DescribeSecurityGroups(gomock.AssignableToTypeOf(&ec2.DescribeSecurityGroupsInput{})).
+				Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: tc.groups}, nil)
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			err = s.DiscoverSecurityGroups()
+			if tc.err != nil {
+				if err == nil || !strings.Contains(err.Error(), tc.err.Error()) {
+					t.Fatalf("was expecting error to look like '%v', but got '%v'", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+
+			if len(scope.SecurityGroups()) != len(tc.expect) {
+				t.Fatalf("expected %d security groups, got %+v", len(tc.expect), scope.SecurityGroups())
+			}
+			for role, id := range tc.expect {
+				if got := scope.SecurityGroups()[role].ID; got != id {
+					t.Errorf("expected security group %q for role %q, got %q", id, role, got)
+				}
+			}
+		})
+	}
+}
+
 func TestControlPlaneSecurityGroupNotOpenToAnyCIDR(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = infrav1.AddToScheme(scheme)
@@ -389,6 +603,164 @@ func TestControlPlaneSecurityGroupNotOpenToAnyCIDR(t *testing.T) {
 	}
 }
 
+func TestAPIServerLBSecurityGroupAllowedCIDRBlocks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name     string
+		lb       *infrav1.AWSLoadBalancerSpec
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expected []string
+	}{
+		{
+			name:     "no load balancer spec, should allow any CIDR block",
+			lb:       nil,
+			expect:   func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expected: []string{services.AnyIPv4CidrBlock},
+		},
+		{
+			name:     "no allowed CIDR blocks, should allow any CIDR block",
+			lb:       &infrav1.AWSLoadBalancerSpec{},
+			expect:   func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expected: []string{services.AnyIPv4CidrBlock},
+		},
+		{
+			name: "internal load balancer, should allow the CIDR blocks and the VPC",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				Scheme:            &infrav1.ClassicELBSchemeInternal,
+				AllowedCIDRBlocks: []string{"192.168.0.0/16"},
+			},
+			expect:   func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expected: []string{"10.0.0.0/16", "192.168.0.0/16"},
+		},
+		{
+			name: "internet-facing load balancer, should allow the CIDR blocks, the VPC and the NAT gateways",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				AllowedCIDRBlocks: []string{"192.168.0.0/16", "10.0.0.0/16"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNatGatewaysPages(
+					gomock.Eq(&ec2.DescribeNatGatewaysInput{
+						Filter: []*ec2.Filter{
+							{
+								Name:   aws.String("vpc-id"),
+								Values: []*string{aws.String("vpc-securitygroups")},
+							},
+							{
+								Name:   aws.String("state"),
+								Values: []*string{aws.String("available")},
+							},
+						},
+					}),
+					gomock.Any()).
+					Do(func(_ *ec2.DescribeNatGatewaysInput, fn func(*ec2.DescribeNatGatewaysOutput, bool) bool) {
+						fn(&ec2.DescribeNatGatewaysOutput{
+							NatGateways: []*ec2.NatGateway{
+								{NatGatewayAddresses: []*ec2.NatGatewayAddress{{PublicIp: aws.String("1.2.3.4")}}},
+								{NatGatewayAddresses: []*ec2.NatGatewayAddress{{PublicIp: aws.String("5.6.7.8")}}},
+							},
+						}, true)
+					}).
+					Return(nil)
+			},
+			expected: []string{"1.2.3.4/32", "10.0.0.0/16", "192.168.0.0/16", "5.6.7.8/32"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					Spec: infrav1.AWSClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							VPC: infrav1.VPCSpec{
+								ID:        "vpc-securitygroups",
+								CidrBlock: "10.0.0.0/16",
+							},
+						},
+						ControlPlaneLoadBalancer: tc.lb,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			rules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupAPIServerLB)
+			if err != nil {
+				t.Fatalf("Failed to lookup API server load balancer security group ingress rules: %v", err)
+			}
+			if len(rules) != 1 {
+				t.Fatalf("Expected a single ingress rule, got %d", len(rules))
+			}
+			if !sets.NewString(rules[0].CidrBlocks...).Equal(sets.NewString(tc.expected...)) {
+				t.Fatalf("Expected CIDR blocks %v, got %v", tc.expected, rules[0].CidrBlocks)
+			}
+		})
+	}
+}
+
+func TestNodeSecurityGroupAllowNodeToNodeTraffic(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	for _, allow := range []bool{false, true} {
+		scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+			Client: client,
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+			},
+			AWSCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{AllowNodeToNodeTraffic: allow},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.Network{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupNode: {ID: "sg-node"},
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create test context: %v", err)
+		}
+
+		s := NewService(scope)
+		rules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupNode)
+		if err != nil {
+			t.Fatalf("Failed to lookup node security group ingress rules: %v", err)
+		}
+
+		found := false
+		for _, r := range rules {
+			if r.Protocol == infrav1.SecurityGroupProtocolAll && sets.NewString(r.SourceSecurityGroupIDs...).Has("sg-node") {
+				found = true
+			}
+		}
+		if found != allow {
+			t.Fatalf("Expected all traffic between nodes to be allowed: %t, got %t", allow, found)
+		}
+	}
+}
+
 func TestDeleteSecurityGroups(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -37,14 +37,29 @@ type Scope interface {
 	// SecurityGroupOverrides returns the security groups that are overridden in the cluster spec
 	SecurityGroupOverrides() map[infrav1.SecurityGroupRole]string
 
+	// SecurityGroupOverrideTracking returns how the security group overrides are followed.
+	SecurityGroupOverrideTracking() infrav1.SecurityGroupOverrideTracking
+
 	// VPC returns the cluster VPC.
 	VPC() *infrav1.VPCSpec
 
 	// CNIIngressRules returns the CNI spec ingress rules.
 	CNIIngressRules() infrav1.CNIIngressRules
 
+	// AllowNodeToNodeTraffic returns whether all traffic between nodes is allowed.
+	AllowNodeToNodeTraffic() bool
+
+	// SecurityGroupPolicy returns the ports the security groups of the cluster may open to each other.
+	SecurityGroupPolicy() *infrav1.SecurityGroupPolicy
+
 	// Bastion returns the bastion details for the cluster.
 	Bastion() *infrav1.Bastion
+
+	// ControlPlaneLoadBalancer returns the AWSLoadBalancerSpec.
+	ControlPlaneLoadBalancer() *infrav1.AWSLoadBalancerSpec
+
+	// Karpenter returns the Karpenter configuration of the cluster.
+	Karpenter() *infrav1.Karpenter
 }
 
 // Service holds a collection of interfaces.
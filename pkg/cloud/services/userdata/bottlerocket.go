@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+// The settings are appended to the TOML user data generated by the bootstrap
+// provider, which therefore must not define the same tables.
+var bottlerocketSettingsTemplate = template.Must(template.New("bottlerocket").Funcs(template.FuncMap{
+	"quote": tomlQuote,
+}).Parse(`
+{{- if .Sysctls }}
+[settings.kernel.sysctl]
+{{- range $name, $value := .Sysctls }}
+{{ quote $name }} = {{ quote $value }}
+{{- end }}
+{{ end }}
+{{- if .AdminContainer }}
+[settings.host-containers.admin]
+enabled = true
+{{ end }}
+{{- range .HostContainers }}
+[settings.host-containers.{{ .Name }}]
+enabled = true
+source = {{ quote .Source }}
+superpowered = {{ .Superpowered }}
+{{ end }}
+{{- range .RegistryMirrors }}
+[[settings.container-registry.mirrors]]
+registry = {{ quote .Registry }}
+endpoint = [{{ range $i, $endpoint := .Endpoints }}{{ if $i }}, {{ end }}{{ quote $endpoint }}{{ end }}]
+{{ end -}}
+`))
+
+// NewBottlerocketSettings renders the settings as Bottlerocket TOML user data.
+func NewBottlerocketSettings(settings *infrav1.BottlerocketSettings) ([]byte, error) {
+	var out bytes.Buffer
+	if err := bottlerocketSettingsTemplate.Execute(&out, settings); err != nil {
+		return nil, errors.Wrap(err, "failed to generate bottlerocket settings")
+	}
+	return out.Bytes(), nil
+}
+
+// AddBottlerocketSettings appends the settings to the TOML user data. The user
+// data is returned as is if no settings are set.
+func AddBottlerocketSettings(settings *infrav1.BottlerocketSettings, userData []byte) ([]byte, error) {
+	if settings == nil {
+		return userData, nil
+	}
+	rendered, err := NewBottlerocketSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	if len(rendered) == 0 {
+		return userData, nil
+	}
+
+	out := make([]byte, 0, len(userData)+len(rendered)+1)
+	out = append(out, userData...)
+	if len(out) > 0 && out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	return append(out, rendered...), nil
+}
+
+// tomlQuote returns s as a TOML basic string.
+func tomlQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+func TestAddBottlerocketSettings(t *testing.T) {
+	bootstrapData := []byte("[settings.kubernetes]\napi-server = \"https://example.com\"")
+
+	tests := []struct {
+		name     string
+		settings *infrav1.BottlerocketSettings
+		want     string
+	}{
+		{
+			name: "returns the user data without settings",
+			want: string(bootstrapData),
+		},
+		{
+			name:     "returns the user data with empty settings",
+			settings: &infrav1.BottlerocketSettings{},
+			want:     string(bootstrapData),
+		},
+		{
+			name: "appends the settings",
+			settings: &infrav1.BottlerocketSettings{
+				Sysctls:        map[string]string{"net.ipv4.ip_forward": "1", "vm.max_map_count": "262144"},
+				AdminContainer: true,
+				HostContainers: []infrav1.BottlerocketHostContainer{
+					{Name: "agent", Source: "public.ecr.aws/example/agent:v1", Superpowered: true},
+				},
+				RegistryMirrors: []infrav1.BottlerocketRegistryMirror{
+					{Registry: "docker.io", Endpoints: []string{"https://mirror-1.example.com", "https://mirror-2.example.com"}},
+				},
+			},
+			want: `[settings.kubernetes]
+api-server = "https://example.com"
+
+[settings.kernel.sysctl]
+"net.ipv4.ip_forward" = "1"
+"vm.max_map_count" = "262144"
+
+[settings.host-containers.admin]
+enabled = true
+
+[settings.host-containers.agent]
+enabled = true
+source = "public.ecr.aws/example/agent:v1"
+superpowered = true
+
+[[settings.container-registry.mirrors]]
+registry = "docker.io"
+endpoint = ["https://mirror-1.example.com", "https://mirror-2.example.com"]
+`,
+		},
+		{
+			name:     "escapes strings",
+			settings: &infrav1.BottlerocketSettings{Sysctls: map[string]string{"kernel.core_pattern": "|/bin/\"dump\"\t%p"}},
+			want:     string(bootstrapData) + "\n\n[settings.kernel.sysctl]\n\"kernel.core_pattern\" = \"|/bin/\\\"dump\\\"\\t%p\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			userData, err := AddBottlerocketSettings(tt.settings, bootstrapData)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(userData)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/internal/mime"
+)
+
+const (
+	// DefaultNvidiaDevicePluginImage is the NVIDIA device plugin run on kubeadm nodes.
+	DefaultNvidiaDevicePluginImage = "nvcr.io/nvidia/k8s-device-plugin:v0.9.0"
+
+	// Images that already ship the driver and container toolkit, such as the
+	// EKS optimized GPU AMI, skip the installation steps. The device plugin is
+	// run as a static pod, which is only possible on kubeadm nodes; EKS nodes
+	// rely on the device plugin DaemonSet being deployed to the cluster.
+	nvidiaGPUBashScript = `{{.Header}}
+
+if ! command -v nvidia-smi >/dev/null 2>&1; then
+  if command -v apt-get >/dev/null 2>&1; then
+    apt-get update
+    DEBIAN_FRONTEND=noninteractive apt-get install -y ubuntu-drivers-common
+    ubuntu-drivers install --gpgpu
+  elif command -v yum >/dev/null 2>&1; then
+    yum install -y "kernel-devel-$(uname -r)" "kernel-headers-$(uname -r)" yum-utils
+    yum-config-manager --add-repo https://developer.download.nvidia.com/compute/cuda/repos/rhel7/x86_64/cuda-rhel7.repo
+    yum install -y nvidia-driver-latest-dkms
+  fi
+fi
+
+if ! command -v nvidia-container-runtime >/dev/null 2>&1; then
+  if command -v apt-get >/dev/null 2>&1; then
+    curl -fsSL https://nvidia.github.io/libnvidia-container/gpgkey | gpg --dearmor -o /usr/share/keyrings/nvidia-container-toolkit-keyring.gpg
+    curl -fsSL https://nvidia.github.io/libnvidia-container/stable/deb/nvidia-container-toolkit.list \
+      | sed 's#deb https://#deb [signed-by=/usr/share/keyrings/nvidia-container-toolkit-keyring.gpg] https://#g' \
+      > /etc/apt/sources.list.d/nvidia-container-toolkit.list
+    apt-get update
+    DEBIAN_FRONTEND=noninteractive apt-get install -y nvidia-container-toolkit
+  elif command -v yum >/dev/null 2>&1; then
+    curl -fsSL https://nvidia.github.io/libnvidia-container/stable/rpm/nvidia-container-toolkit.repo \
+      > /etc/yum.repos.d/nvidia-container-toolkit.repo
+    yum install -y nvidia-container-toolkit
+  fi
+fi
+
+if command -v nvidia-ctk >/dev/null 2>&1; then
+  nvidia-ctk runtime configure --runtime=containerd --set-as-default
+  systemctl restart containerd
+fi
+
+if command -v kubeadm >/dev/null 2>&1; then
+  # kubeadm refuses to join a control plane node with a non-empty manifests
+  # directory, so wait for the node to join before adding the static pod.
+  nohup sh -c 'until [ -f /etc/kubernetes/kubelet.conf ]; do sleep 10; done
+cat > /etc/kubernetes/manifests/nvidia-device-plugin.yaml <<EOF
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nvidia-device-plugin
+  namespace: kube-system
+spec:
+  priorityClassName: system-node-critical
+  tolerations:
+  - operator: Exists
+  containers:
+  - name: nvidia-device-plugin
+    image: {{.DevicePluginImage}}
+    args: ["--fail-on-init-error=false"]
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: ["ALL"]
+    volumeMounts:
+    - name: device-plugins
+      mountPath: /var/lib/kubelet/device-plugins
+  volumes:
+  - name: device-plugins
+    hostPath:
+      path: /var/lib/kubelet/device-plugins
+EOF' >/dev/null 2>&1 &
+fi
+`
+)
+
+// NvidiaGPUInput defines the context to generate the script preparing an instance for NVIDIA GPUs.
+type NvidiaGPUInput struct {
+	baseUserData
+
+	DevicePluginImage string
+}
+
+// NewNvidiaGPU returns a script installing the NVIDIA driver, the container toolkit and the device plugin.
+func NewNvidiaGPU(input *NvidiaGPUInput) (string, error) {
+	input.Header = defaultHeader
+	if input.DevicePluginImage == "" {
+		input.DevicePluginImage = DefaultNvidiaDevicePluginImage
+	}
+	return generate("nvidia-gpu", nvidiaGPUBashScript, input)
+}
+
+// AddGPUBootstrap appends the script preparing an instance for GPUs of the given
+// vendor to the user data. The user data is returned as is if no vendor is set.
+func AddGPUBootstrap(vendor infrav1.GPUVendor, userData []byte) ([]byte, error) {
+	var script string
+	var err error
+	switch vendor {
+	case "":
+		return userData, nil
+	case infrav1.GPUVendorNvidia:
+		script, err = NewNvidiaGPU(&NvidiaGPUInput{})
+	default:
+		return nil, errors.Errorf("unsupported GPU vendor %q", vendor)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mime.AppendScript(userData, []byte(script))
+}
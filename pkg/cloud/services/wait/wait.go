@@ -31,6 +31,28 @@ import (
  implement waits manually here.
 */
 
+var (
+	// DefaultInstanceRunningTimeout is how long to wait for a new EC2 instance to be running when
+	// the cluster does not override it.
+	DefaultInstanceRunningTimeout = time.Minute
+
+	// DefaultLoadBalancerReadyTimeout is how long to wait for load balancer operations to complete
+	// when the cluster does not override it. It matches the total wait of NewBackoff.
+	DefaultLoadBalancerReadyTimeout = totalWait(NewBackoff())
+
+	// DefaultLoadBalancerDNSRequeueInterval is how often to check whether the control plane load balancer
+	// has a DNS name which resolves, when the cluster does not override it.
+	DefaultLoadBalancerDNSRequeueInterval = 15 * time.Second
+
+	// DefaultInstanceRunningRequeueInterval is how often to check whether a pending EC2 instance is running,
+	// when the cluster does not override it.
+	DefaultInstanceRunningRequeueInterval = 30 * time.Second
+
+	// DefaultNodegroupUpdateRequeueInterval is how often to check whether an update of an EKS managed node
+	// group has completed, when the cluster does not override it.
+	DefaultNodegroupUpdateRequeueInterval = time.Minute
+)
+
 // NewBackoff creates a new API Machinery backoff parameter set suitable
 // for use with AWS services.
 func NewBackoff() wait.Backoff {
@@ -47,10 +69,72 @@ func NewBackoff() wait.Backoff {
 	}
 }
 
+// maxBackoffStep caps the wait between two checks of a backoff built by
+// NewBackoffWithTimeout, so that long timeouts keep checking regularly.
+const maxBackoffStep = 2 * time.Minute
+
+// NewBackoffWithTimeout returns the backoff of NewBackoff with as many steps as
+// needed for the waits between them to add up to at least the given timeout.
+// No wait is longer than maxBackoffStep.
+func NewBackoffWithTimeout(timeout time.Duration) wait.Backoff {
+	backoff := NewBackoff()
+	backoff.Cap = maxBackoffStep
+	backoff.Steps = 1
+
+	delay, total := backoff.Duration, time.Duration(0)
+	for total < timeout {
+		total += delay
+		delay = nextDelay(backoff, delay)
+		backoff.Steps++
+	}
+	return backoff
+}
+
+// totalWait returns how long a backoff waits between its steps, without jitter.
+func totalWait(backoff wait.Backoff) time.Duration {
+	delay, total := backoff.Duration, time.Duration(0)
+	for i := 1; i < backoff.Steps; i++ {
+		total += delay
+		delay = nextDelay(backoff, delay)
+	}
+	return total
+}
+
+// nextDelay returns the wait which follows delay in the backoff, capped at its Cap.
+func nextDelay(backoff wait.Backoff, delay time.Duration) time.Duration {
+	delay = time.Duration(float64(delay) * backoff.Factor)
+	if backoff.Cap > 0 && delay > backoff.Cap {
+		return backoff.Cap
+	}
+	return delay
+}
+
+// exponentialBackoff repeats a condition check like wait.ExponentialBackoff,
+// except that reaching the Cap of the backoff doesn't end the checks early:
+// the remaining steps keep waiting for the Cap.
+func exponentialBackoff(backoff wait.Backoff, condition wait.ConditionFunc) error {
+	delay := backoff.Duration
+	for step := 1; step <= backoff.Steps; step++ {
+		if ok, err := condition(); err != nil || ok {
+			return err
+		}
+		if step == backoff.Steps {
+			break
+		}
+		if backoff.Jitter > 0 {
+			time.Sleep(wait.Jitter(delay, backoff.Jitter))
+		} else {
+			time.Sleep(delay)
+		}
+		delay = nextDelay(backoff, delay)
+	}
+	return wait.ErrWaitTimeout
+}
+
 // WaitForWithRetryable repeats a condition check with exponential backoff.
 func WaitForWithRetryable(backoff wait.Backoff, condition wait.ConditionFunc, retryableErrors ...string) error {
 	var errToReturn error
-	waitErr := wait.ExponentialBackoff(backoff, func() (bool, error) {
+	waitErr := exponentialBackoff(backoff, func() (bool, error) {
 		// clear errToReturn value from previous iteration
 		errToReturn = nil
 
@@ -147,3 +147,68 @@ func TestWaitForWithRetryable(t *testing.T) {
 		})
 	}
 }
+
+func TestNewBackoffWithTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{name: "short timeout", timeout: 10 * time.Second},
+		{name: "instance running default", timeout: DefaultInstanceRunningTimeout},
+		{name: "load balancer ready default", timeout: DefaultLoadBalancerReadyTimeout},
+		{name: "long timeout", timeout: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff := NewBackoffWithTimeout(tt.timeout)
+
+			var total, previous time.Duration
+			delay := backoff.Duration
+			for i := 1; i < backoff.Steps; i++ {
+				if delay > backoff.Cap {
+					t.Errorf("expected no wait longer than %v, got %v at step %d", backoff.Cap, delay, i)
+				}
+				previous = total
+				total += delay
+				delay = time.Duration(float64(delay) * backoff.Factor)
+				if delay > backoff.Cap {
+					delay = backoff.Cap
+				}
+			}
+			if total < tt.timeout {
+				t.Errorf("expected backoff of %d steps to wait at least %v, got %v", backoff.Steps, tt.timeout, total)
+			}
+			if previous >= tt.timeout {
+				t.Errorf("expected backoff of %d steps to be the shortest covering %v, %d steps already wait %v", backoff.Steps, tt.timeout, backoff.Steps-1, previous)
+			}
+		})
+	}
+}
+
+func TestDefaultLoadBalancerReadyTimeoutMatchesNewBackoff(t *testing.T) {
+	backoff := NewBackoffWithTimeout(DefaultLoadBalancerReadyTimeout)
+	if backoff.Steps != NewBackoff().Steps {
+		t.Errorf("expected the default load balancer timeout to take the %d steps of NewBackoff, got %d", NewBackoff().Steps, backoff.Steps)
+	}
+}
+
+func TestWaitForWithRetryableKeepsCheckingAfterCap(t *testing.T) {
+	backoff := wait.Backoff{
+		Duration: time.Millisecond,
+		Factor:   2,
+		Cap:      2 * time.Millisecond,
+		Steps:    5,
+	}
+
+	checks := 0
+	err := WaitForWithRetryable(backoff, func() (bool, error) {
+		checks++
+		return false, nil
+	})
+	if !errors.Is(err, wait.ErrWaitTimeout) {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if checks != backoff.Steps {
+		t.Errorf("expected %d checks, got %d", backoff.Steps, checks)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+)
+
+// backpressureJitter is the fraction of the backoff added at random to the requeues of the clusters sharing
+// a throttled account, so that they don't all retry at once when the backoff ends.
+const backpressureJitter = 0.2
+
+// DefaultBackpressure is the backpressure shared by the controllers of the manager.
+var DefaultBackpressure = NewBackpressure(30*time.Second, 5*time.Minute)
+
+// Backpressure slows down the reconciliation of the clusters sharing an AWS account and region while AWS
+// throttles their requests, and lets the clusters of other accounts proceed. The backoff of an account
+// doubles every time it is throttled again after its backoff ended, and starts over once the account was
+// not throttled for as long as its last backoff.
+type Backpressure struct {
+	base time.Duration
+	max  time.Duration
+	now  func() time.Time
+
+	mu       sync.Mutex
+	accounts map[string]*accountBackoff
+}
+
+type accountBackoff struct {
+	backoff time.Duration
+	until   time.Time
+}
+
+// NewBackpressure returns a backpressure whose backoff starts at base and is capped at max.
+func NewBackpressure(base, max time.Duration) *Backpressure {
+	return &Backpressure{
+		base:     base,
+		max:      max,
+		now:      time.Now,
+		accounts: map[string]*accountBackoff{},
+	}
+}
+
+// Throttled records that the requests made with the key were throttled, and returns how long to wait before
+// making requests with it again.
+func (b *Backpressure) Throttled(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	a, ok := b.accounts[key]
+	switch {
+	case !ok || now.After(a.until.Add(a.backoff)):
+		a = &accountBackoff{backoff: b.base}
+		b.accounts[key] = a
+	case now.Before(a.until):
+		// The requests of clusters reconciled when the account was first throttled don't extend the backoff.
+		return wait.Jitter(a.until.Sub(now), backpressureJitter)
+	default:
+		a.backoff *= 2
+		if a.backoff > b.max {
+			a.backoff = b.max
+		}
+	}
+	a.until = now.Add(a.backoff)
+	return wait.Jitter(a.backoff, backpressureJitter)
+}
+
+// Delay returns how long to wait before making requests with the key, or zero if they are not throttled.
+func (b *Backpressure) Delay(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	a, ok := b.accounts[key]
+	if !ok {
+		return 0
+	}
+	now := b.now()
+	if now.After(a.until.Add(a.backoff)) {
+		delete(b.accounts, key)
+		return 0
+	}
+	if !now.Before(a.until) {
+		return 0
+	}
+	return wait.Jitter(a.until.Sub(now), backpressureJitter)
+}
+
+// Account returns the backpressure of the clusters making requests with the key.
+func (b *Backpressure) Account(key string) AccountBackpressure {
+	return AccountBackpressure{backpressure: b, key: key}
+}
+
+// AccountBackpressure is the backpressure of the clusters sharing an AWS account and region.
+type AccountBackpressure struct {
+	backpressure *Backpressure
+	key          string
+}
+
+// Delay returns how long to wait before reconciling a cluster of the account, or zero if its requests are
+// not throttled.
+func (a AccountBackpressure) Delay() time.Duration {
+	return a.backpressure.Delay(a.key)
+}
+
+// Requeue returns the result and error of the reconciliation of a cluster of the account, like
+// awserrors.Requeue. A throttling error backs off all the clusters of the account instead of retrying it
+// after a fixed delay.
+func (a AccountBackpressure) Requeue(res ctrl.Result, err error) (ctrl.Result, error) {
+	if awserrors.Classify(err) == awserrors.ClassThrottling {
+		return ctrl.Result{RequeueAfter: a.backpressure.Throttled(a.key)}, nil
+	}
+	return awserrors.Requeue(res, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestBackpressure(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	b := NewBackpressure(10*time.Second, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	between := func(min time.Duration) types.GomegaMatcher {
+		return And(BeNumerically(">=", min), BeNumerically("<=", time.Duration(float64(min)*(1+backpressureJitter))))
+	}
+
+	g.Expect(b.Delay("a")).To(BeZero())
+
+	// The first throttle backs the account off for the base backoff, and leaves the others alone.
+	g.Expect(b.Throttled("a")).To(between(10 * time.Second))
+	g.Expect(b.Delay("a")).To(between(10 * time.Second))
+	g.Expect(b.Delay("b")).To(BeZero())
+
+	// Throttles during the backoff don't extend it.
+	now = now.Add(4 * time.Second)
+	g.Expect(b.Throttled("a")).To(between(6 * time.Second))
+	g.Expect(b.Delay("a")).To(between(6 * time.Second))
+
+	// Throttles soon after the backoff ended double it, up to the max.
+	now = now.Add(7 * time.Second)
+	g.Expect(b.Delay("a")).To(BeZero())
+	g.Expect(b.Throttled("a")).To(between(20 * time.Second))
+	now = now.Add(21 * time.Second)
+	g.Expect(b.Throttled("a")).To(between(30 * time.Second))
+
+	// The backoff starts over once the account was not throttled for as long as its last backoff.
+	now = now.Add(61 * time.Second)
+	g.Expect(b.Delay("a")).To(BeZero())
+	g.Expect(b.accounts).NotTo(HaveKey("a"))
+	g.Expect(b.Throttled("a")).To(between(10 * time.Second))
+}
+
+func TestAccountBackpressureRequeue(t *testing.T) {
+	g := NewWithT(t)
+
+	b := NewBackpressure(10*time.Second, 30*time.Second)
+	account := b.Account("a")
+
+	res, err := account.Requeue(ctrl.Result{}, errors.Wrap(awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil), "failed to describe instances"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(BeNumerically(">=", 10*time.Second))
+	g.Expect(account.Delay()).To(BeNumerically(">", 0))
+	g.Expect(b.Account("b").Delay()).To(BeZero())
+
+	// Other errors are requeued like awserrors.Requeue does.
+	res, err = account.Requeue(ctrl.Result{}, errors.New("boom"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{}))
+
+	res, err = account.Requeue(ctrl.Result{RequeueAfter: time.Minute}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+}
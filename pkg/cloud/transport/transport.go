@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transport provides the HTTP clients the AWS sessions of the controllers use.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// CABundle is a PEM encoded bundle of certificate authorities which are trusted for the AWS API endpoints
+// in addition to the system ones. It is loaded from the --aws-ca-bundle flag.
+var CABundle []byte
+
+// LoadCABundle reads the PEM encoded bundle of certificate authorities at path into CABundle.
+func LoadCABundle(path string) error {
+	bundle, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read CA bundle %q", path)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+		return errors.Errorf("CA bundle %q contains no PEM encoded certificates", path)
+	}
+	CABundle = bundle
+	return nil
+}
+
+// Options configures the HTTP client of an AWS session.
+type Options struct {
+	// CABundles are PEM encoded bundles of certificate authorities which are trusted in addition to the system
+	// ones and CABundle.
+	CABundles [][]byte
+	// ProxyURL is the URL of the HTTP(S) proxy requests are sent through. The proxy of the environment is used
+	// when it is empty.
+	ProxyURL string
+	// NoProxy lists the hosts which are reached directly rather than through ProxyURL, in the format of the
+	// NO_PROXY environment variable.
+	NoProxy []string
+}
+
+// NewHTTPClient returns an HTTP client which trusts the system certificate authorities, CABundle and those of
+// the options, and uses the proxy of the options. It returns nil when neither certificate authorities nor a
+// proxy are configured, so that the AWS SDK uses its default client.
+func NewHTTPClient(opts Options) (*http.Client, error) {
+	bundles := [][]byte{}
+	for _, bundle := range append([][]byte{CABundle}, opts.CABundles...) {
+		if len(bundle) > 0 {
+			bundles = append(bundles, bundle)
+		}
+	}
+	if len(bundles) == 0 && opts.ProxyURL == "" {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(bundles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, bundle := range bundles {
+			if !pool.AppendCertsFromPEM(bundle) {
+				return nil, errors.New("CA bundle contains no PEM encoded certificates")
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	if opts.ProxyURL != "" {
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  opts.ProxyURL,
+			HTTPSProxy: opts.ProxyURL,
+			NoProxy:    strings.Join(opts.NoProxy, ","),
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
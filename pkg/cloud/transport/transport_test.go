@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	t.Run("no CA bundles", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client, err := NewHTTPClient(Options{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(client).To(BeNil())
+	})
+
+	t.Run("trusts the CA bundle of the identity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client, err := NewHTTPClient(Options{CABundles: [][]byte{serverCA}})
+		g.Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Get(server.URL)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	})
+
+	t.Run("trusts the CA bundle of the flag", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		g.Expect(ioutil.WriteFile(path, serverCA, 0600)).To(Succeed())
+		g.Expect(LoadCABundle(path)).To(Succeed())
+		defer func() { CABundle = nil }()
+
+		client, err := NewHTTPClient(Options{})
+		g.Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Get(server.URL)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	})
+
+	t.Run("sends requests through the proxy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		proxied := []string{}
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = append(proxied, r.Host)
+		}))
+		defer proxy.Close()
+
+		client, err := NewHTTPClient(Options{ProxyURL: proxy.URL, NoProxy: []string{"direct.example.invalid"}})
+		g.Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Get("http://ec2.example.invalid/")
+		g.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		_, err = client.Get("http://direct.example.invalid/")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(proxied).To(Equal([]string{"ec2.example.invalid"}))
+	})
+
+	t.Run("rejects bundles without certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewHTTPClient(Options{CABundles: [][]byte{[]byte("not a certificate")}})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -63,3 +63,36 @@ func SplitIntoSubnetsIPv4(cidrBlock string, numSubnets int) ([]*net.IPNet, error
 
 	return subnets, nil
 }
+
+// SplitIntoSubnetsIPv6 splits an IPv6 CIDR into a specified number of /64 subnets, the size of
+// the IPv6 CIDR blocks of VPC subnets, starting with the first /64 of the CIDR.
+func SplitIntoSubnetsIPv6(cidrBlock string, numSubnets int) ([]*net.IPNet, error) {
+	_, parent, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CIDR")
+	}
+
+	if parent.IP.To4() != nil {
+		return nil, errors.Errorf("unexpected IP address type: %s", parent)
+	}
+
+	networkLen, _ := parent.Mask.Size()
+	if networkLen > 64 || (64-networkLen < 32 && numSubnets > 1<<uint(64-networkLen)) {
+		return nil, errors.Errorf("cidr %s cannot accommodate %d subnets", cidrBlock, numSubnets)
+	}
+
+	prefix := binary.BigEndian.Uint64(parent.IP.To16()[:8])
+
+	var subnets []*net.IPNet
+	for i := 0; i < numSubnets; i++ {
+		subnetIP := make(net.IP, net.IPv6len)
+		binary.BigEndian.PutUint64(subnetIP[:8], prefix+uint64(i))
+
+		subnets = append(subnets, &net.IPNet{
+			IP:   subnetIP,
+			Mask: net.CIDRMask(64, 128),
+		})
+	}
+
+	return subnets, nil
+}
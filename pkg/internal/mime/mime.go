@@ -18,9 +18,15 @@ package mime
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
+	"mime"
 	"mime/multipart"
+	"net/mail"
 	"net/textproto"
 	"strings"
 )
@@ -38,6 +44,15 @@ var (
 		"content-type": {"text/cloud-boothook"},
 	}
 
+	scriptType = textproto.MIMEHeader{
+		"content-type": {"text/x-shellscript"},
+	}
+
+	// plainType lets cloud-init infer the part type from its content.
+	plainType = textproto.MIMEHeader{
+		"content-type": {"text/plain"},
+	}
+
 	multipartHeader = strings.Join([]string{
 		"MIME-Version: 1.0",
 		"Content-Type: multipart/mixed; boundary=\"%s\"",
@@ -98,3 +113,105 @@ func GenerateInitDocument(secretPrefix string, chunks int32, region string, endp
 
 	return buf.Bytes(), nil
 }
+
+// PrependBoothook wraps the given user data in a MIME multi-part document,
+// preceded by a boothook part running the given script. If the user data
+// already is a multi-part document, the boothook is added to its parts.
+func PrependBoothook(userData []byte, boothook []byte) ([]byte, error) {
+	parts, err := userDataParts(userData)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return multipartDocument(append([]part{{header: boothookType, content: boothook}}, parts...))
+}
+
+// AppendScript wraps the given user data in a MIME multi-part document,
+// followed by a part running the given shell script. If the user data already
+// is a multi-part document, the script is added to its parts.
+func AppendScript(userData []byte, script []byte) ([]byte, error) {
+	parts, err := userDataParts(userData)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return multipartDocument(append(parts, part{header: scriptType, content: script}))
+}
+
+type part struct {
+	header  textproto.MIMEHeader
+	content []byte
+}
+
+// userDataParts returns the parts of a MIME multi-part user data document, or
+// the user data as a single part if it is not one.
+func userDataParts(userData []byte) ([]part, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(userData))
+	if err != nil {
+		return []part{{header: plainType, content: userData}}, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return []part{{header: plainType, content: userData}}, nil
+	}
+
+	var parts []part
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(p)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part{
+			header:  textproto.MIMEHeader{"content-type": {p.Header.Get("Content-Type")}},
+			content: content,
+		})
+	}
+}
+
+// multipartDocument renders the given parts as a MIME multi-part document. The
+// boundary is derived from the parts, so the same input always renders the
+// same document and instances and launch templates are not updated on every
+// reconcile.
+func multipartDocument(parts []part) ([]byte, error) {
+	var buf bytes.Buffer
+	mpWriter := multipart.NewWriter(&buf)
+	if err := mpWriter.SetBoundary(partsBoundary(parts)); err != nil {
+		return []byte{}, err
+	}
+	buf.WriteString(fmt.Sprintf(multipartHeader, mpWriter.Boundary()))
+
+	for _, part := range parts {
+		partWriter, err := mpWriter.CreatePart(part.header)
+		if err != nil {
+			return []byte{}, err
+		}
+		if _, err := partWriter.Write(part.content); err != nil {
+			return []byte{}, err
+		}
+	}
+
+	if err := mpWriter.Close(); err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// partsBoundary returns a boundary derived from the headers and contents of the
+// given parts.
+func partsBoundary(parts []part) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(hash, "%s\n%d\n", part.header.Get("content-type"), len(part.content))
+		hash.Write(part.content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
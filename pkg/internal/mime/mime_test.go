@@ -18,6 +18,8 @@ package mime
 
 import (
 	"bytes"
+	stdmime "mime"
+	"mime/multipart"
 	"net/mail"
 	"testing"
 )
@@ -30,3 +32,86 @@ func TestGenerateInitDocument(t *testing.T) {
 		t.Fatalf("Cannot parse MIME doc: %+v\n%s", err, string(doc))
 	}
 }
+
+func TestPrependBoothook(t *testing.T) {
+	doc, err := PrependBoothook([]byte("#cloud-config\nruncmd: []\n"), []byte("#cloud-boothook\n#!/bin/sh\n"))
+	if err != nil {
+		t.Fatalf("Failed to generate MIME doc: %+v", err)
+	}
+
+	again, err := PrependBoothook([]byte("#cloud-config\nruncmd: []\n"), []byte("#cloud-boothook\n#!/bin/sh\n"))
+	if err != nil {
+		t.Fatalf("Failed to generate MIME doc: %+v", err)
+	}
+	if !bytes.Equal(doc, again) {
+		t.Fatalf("Expected the same document for the same input, got\n%s\nand\n%s", string(doc), string(again))
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewBuffer(doc))
+	if err != nil {
+		t.Fatalf("Cannot parse MIME doc: %+v\n%s", err, string(doc))
+	}
+
+	_, params, err := stdmime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Cannot parse content type: %+v", err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var contentTypes []string
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+	}
+
+	if len(contentTypes) != 2 || contentTypes[0] != "text/cloud-boothook" || contentTypes[1] != "text/plain" {
+		t.Fatalf("Unexpected MIME parts: %v\n%s", contentTypes, string(doc))
+	}
+}
+
+func TestAppendScript(t *testing.T) {
+	withBoothook, err := PrependBoothook([]byte("#cloud-config\nruncmd: []\n"), []byte("#cloud-boothook\n#!/bin/sh\n"))
+	if err != nil {
+		t.Fatalf("Failed to generate MIME doc: %+v", err)
+	}
+
+	doc, err := AppendScript(withBoothook, []byte("#!/bin/sh\n"))
+	if err != nil {
+		t.Fatalf("Failed to generate MIME doc: %+v", err)
+	}
+
+	again, err := AppendScript(withBoothook, []byte("#!/bin/sh\n"))
+	if err != nil {
+		t.Fatalf("Failed to generate MIME doc: %+v", err)
+	}
+	if !bytes.Equal(doc, again) {
+		t.Fatalf("Expected the same document for the same input, got\n%s\nand\n%s", string(doc), string(again))
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewBuffer(doc))
+	if err != nil {
+		t.Fatalf("Cannot parse MIME doc: %+v\n%s", err, string(doc))
+	}
+
+	_, params, err := stdmime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Cannot parse content type: %+v", err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var contentTypes []string
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+	}
+
+	if len(contentTypes) != 3 || contentTypes[0] != "text/cloud-boothook" || contentTypes[1] != "text/plain" || contentTypes[2] != "text/x-shellscript" {
+		t.Fatalf("Unexpected MIME parts: %v\n%s", contentTypes, string(doc))
+	}
+}
@@ -34,11 +34,12 @@ func init() {
 	defaultRecorder = new(record.FakeRecorder)
 }
 
-// InitFromRecorder initializes the global default recorder. It can only be called once.
+// InitFromRecorder initializes the global default recorder, deduplicating and rate limiting
+// its events according to DefaultThrottleOptions. It can only be called once.
 // Subsequent calls are considered noops.
 func InitFromRecorder(recorder record.EventRecorder) {
 	initOnce.Do(func() {
-		defaultRecorder = recorder
+		defaultRecorder = NewThrottledRecorder(recorder, DefaultThrottleOptions)
 	})
 }
 
@@ -61,3 +62,13 @@ func Warn(object runtime.Object, reason, message string) {
 func Warnf(object runtime.Object, reason, message string, args ...interface{}) {
 	defaultRecorder.Eventf(object, corev1.EventTypeWarning, strings.Title(reason), message, args...)
 }
+
+// AnnotatedEventf is just like Eventf, but with annotations attached to the event.
+func AnnotatedEventf(object runtime.Object, annotations map[string]string, reason, message string, args ...interface{}) {
+	defaultRecorder.AnnotatedEventf(object, annotations, corev1.EventTypeNormal, strings.Title(reason), message, args...)
+}
+
+// AnnotatedWarnf is just like Warnf, but with annotations attached to the event.
+func AnnotatedWarnf(object runtime.Object, annotations map[string]string, reason, message string, args ...interface{}) {
+	defaultRecorder.AnnotatedEventf(object, annotations, corev1.EventTypeWarning, strings.Title(reason), message, args...)
+}
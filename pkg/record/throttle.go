@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/internal/rate"
+)
+
+// ThrottleOptions configures how events are deduplicated and rate limited before they reach
+// the API server.
+type ThrottleOptions struct {
+	// DedupWindow is how long identical events for an object are held back after one has been
+	// recorded. Repeats seen during the window are recorded as a single event carrying their
+	// count once it closes. Zero disables deduplication.
+	DedupWindow time.Duration
+
+	// PerObjectQPS is the sustained rate at which events may be recorded for a single object.
+	// Events over the limit are dropped. Zero disables rate limiting.
+	PerObjectQPS float64
+
+	// PerObjectBurst is the number of events that may be recorded for a single object before
+	// PerObjectQPS applies.
+	PerObjectBurst int
+}
+
+// DefaultThrottleOptions are the options applied to the recorder passed to InitFromRecorder.
+var DefaultThrottleOptions = ThrottleOptions{
+	DedupWindow:    5 * time.Minute,
+	PerObjectQPS:   1.0 / 30,
+	PerObjectBurst: 25,
+}
+
+type eventKey struct {
+	object    string
+	eventtype string
+	reason    string
+	message   string
+}
+
+type pendingEvent struct {
+	object      runtime.Object
+	annotations map[string]string
+	repeats     int
+}
+
+type objectLimiter struct {
+	*rate.Limiter
+	lastUsed time.Time
+}
+
+type throttledRecorder struct {
+	recorder record.EventRecorder
+	opts     ThrottleOptions
+
+	now       func() time.Time
+	afterFunc func(time.Duration, func())
+
+	mu        sync.Mutex
+	pending   map[eventKey]*pendingEvent
+	limiters  map[string]*objectLimiter
+	lastSweep time.Time
+}
+
+// NewThrottledRecorder returns an event recorder that deduplicates and rate limits events
+// according to opts before passing them on to recorder.
+func NewThrottledRecorder(recorder record.EventRecorder, opts ThrottleOptions) record.EventRecorder {
+	if opts.DedupWindow <= 0 && opts.PerObjectQPS <= 0 {
+		return recorder
+	}
+	if opts.PerObjectBurst < 1 {
+		opts.PerObjectBurst = 1
+	}
+	return &throttledRecorder{
+		recorder: recorder,
+		opts:     opts,
+		now:      time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		pending:  map[eventKey]*pendingEvent{},
+		limiters: map[string]*objectLimiter{},
+	}
+}
+
+// Event implements record.EventRecorder.
+func (r *throttledRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.record(object, nil, eventtype, reason, message)
+}
+
+// Eventf implements record.EventRecorder.
+func (r *throttledRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.record(object, nil, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder.
+func (r *throttledRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.record(object, annotations, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *throttledRecorder) record(object runtime.Object, annotations map[string]string, eventtype, reason, message string) {
+	key := eventKey{
+		object:    objectKey(object),
+		eventtype: eventtype,
+		reason:    reason,
+		message:   message,
+	}
+
+	r.mu.Lock()
+	if r.opts.DedupWindow > 0 {
+		if pending, ok := r.pending[key]; ok {
+			pending.repeats++
+			r.mu.Unlock()
+			return
+		}
+		r.pending[key] = &pendingEvent{object: object, annotations: annotations}
+		r.afterFunc(r.opts.DedupWindow, func() { r.flush(key) })
+	}
+	allowed := r.allow(key.object)
+	r.mu.Unlock()
+
+	if allowed {
+		r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// flush closes the deduplication window for key, recording the repeats seen during it as a
+// single event. The window is reopened while repeats keep arriving so that an error loop
+// produces one event per window rather than one per reconcile.
+func (r *throttledRecorder) flush(key eventKey) {
+	r.mu.Lock()
+	pending, ok := r.pending[key]
+	if !ok {
+		r.mu.Unlock()
+		return
+	}
+	if pending.repeats == 0 {
+		delete(r.pending, key)
+		r.mu.Unlock()
+		return
+	}
+	repeats := pending.repeats
+	pending.repeats = 0
+	allowed := r.allow(key.object)
+	if !allowed {
+		// Carry the count over to the next window instead of losing it.
+		pending.repeats = repeats
+	}
+	r.afterFunc(r.opts.DedupWindow, func() { r.flush(key) })
+	r.mu.Unlock()
+
+	if allowed {
+		r.recorder.AnnotatedEventf(pending.object, pending.annotations, key.eventtype, key.reason,
+			"%s (repeated %d times in the last %s)", key.message, repeats, r.opts.DedupWindow)
+	}
+}
+
+// allow reports whether another event may be recorded for the object. It must be called
+// with r.mu held.
+func (r *throttledRecorder) allow(object string) bool {
+	if r.opts.PerObjectQPS <= 0 {
+		return true
+	}
+
+	now := r.now()
+	r.sweepLimiters(now)
+
+	limiter, ok := r.limiters[object]
+	if !ok {
+		limiter = &objectLimiter{Limiter: rate.NewLimiter(rate.Limit(r.opts.PerObjectQPS), r.opts.PerObjectBurst)}
+		r.limiters[object] = limiter
+	}
+	limiter.lastUsed = now
+	return limiter.AllowN(now, 1)
+}
+
+// sweepLimiters forgets the limiters of objects that have been quiet for long enough to refill
+// their whole burst, since a new limiter would behave the same. It must be called with r.mu held.
+func (r *throttledRecorder) sweepLimiters(now time.Time) {
+	refill := time.Duration(float64(r.opts.PerObjectBurst) / r.opts.PerObjectQPS * float64(time.Second))
+	if now.Sub(r.lastSweep) < refill {
+		return
+	}
+	r.lastSweep = now
+	for object, limiter := range r.limiters {
+		if now.Sub(limiter.lastUsed) >= refill {
+			delete(r.limiters, object)
+		}
+	}
+}
+
+// objectKey identifies the object an event is about, preferring its UID so that a deleted and
+// recreated object does not inherit the state of its predecessor.
+func objectKey(object runtime.Object) string {
+	if ref, ok := object.(*corev1.ObjectReference); ok {
+		if ref.UID != "" {
+			return string(ref.UID)
+		}
+		return fmt.Sprintf("%s/%s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return fmt.Sprintf("%T", object)
+	}
+	if uid := accessor.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return fmt.Sprintf("%T/%s/%s", object, accessor.GetNamespace(), accessor.GetName())
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+type fakeClock struct {
+	now    time.Time
+	timers []func()
+}
+
+func (c *fakeClock) afterFunc(_ time.Duration, f func()) {
+	c.timers = append(c.timers, f)
+}
+
+// fire runs the timers that are due, as if the deduplication window had elapsed.
+func (c *fakeClock) fire(d time.Duration) {
+	c.now = c.now.Add(d)
+	timers := c.timers
+	c.timers = nil
+	for _, f := range timers {
+		f()
+	}
+}
+
+func newTestRecorder(opts ThrottleOptions) (*throttledRecorder, *record.FakeRecorder, *fakeClock) {
+	fake := record.NewFakeRecorder(100)
+	clock := &fakeClock{now: time.Now()}
+	r := NewThrottledRecorder(fake, opts).(*throttledRecorder)
+	r.now = func() time.Time { return clock.now }
+	r.afterFunc = clock.afterFunc
+	return r, fake, clock
+}
+
+func drain(fake *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-fake.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func testObject(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID("uid-" + name)}}
+}
+
+func TestThrottledRecorderDeduplicates(t *testing.T) {
+	g := NewWithT(t)
+	r, fake, clock := newTestRecorder(ThrottleOptions{DedupWindow: time.Minute})
+	obj := testObject("a")
+
+	for i := 0; i < 5; i++ {
+		r.Eventf(obj, corev1.EventTypeWarning, "FailedCreate", "failed to create instance: %s", "boom")
+	}
+	r.Event(obj, corev1.EventTypeWarning, "FailedCreate", "another message")
+	g.Expect(drain(fake)).To(Equal([]string{
+		"Warning FailedCreate failed to create instance: boom",
+		"Warning FailedCreate another message",
+	}))
+
+	clock.fire(time.Minute)
+	g.Expect(drain(fake)).To(Equal([]string{
+		"Warning FailedCreate failed to create instance: boom (repeated 4 times in the last 1m0s)",
+	}))
+
+	// The window stays open while the event keeps repeating.
+	r.Eventf(obj, corev1.EventTypeWarning, "FailedCreate", "failed to create instance: %s", "boom")
+	g.Expect(drain(fake)).To(BeEmpty())
+	clock.fire(time.Minute)
+	g.Expect(drain(fake)).To(Equal([]string{
+		"Warning FailedCreate failed to create instance: boom (repeated 1 times in the last 1m0s)",
+	}))
+
+	// Once a window closes without repeats the event is forgotten.
+	clock.fire(time.Minute)
+	g.Expect(r.pending).To(BeEmpty())
+	r.Eventf(obj, corev1.EventTypeWarning, "FailedCreate", "failed to create instance: %s", "boom")
+	g.Expect(drain(fake)).To(HaveLen(1))
+}
+
+func TestThrottledRecorderRateLimitsPerObject(t *testing.T) {
+	g := NewWithT(t)
+	r, fake, clock := newTestRecorder(ThrottleOptions{PerObjectQPS: 1, PerObjectBurst: 2})
+	a, b := testObject("a"), testObject("b")
+
+	for i := 0; i < 3; i++ {
+		r.Event(a, corev1.EventTypeNormal, "Reason", "message")
+	}
+	r.Event(b, corev1.EventTypeNormal, "Reason", "message")
+	g.Expect(drain(fake)).To(HaveLen(3))
+
+	clock.now = clock.now.Add(time.Second)
+	r.Event(a, corev1.EventTypeNormal, "Reason", "message")
+	g.Expect(drain(fake)).To(HaveLen(1))
+
+	// Limiters of quiet objects are dropped once they would have refilled.
+	clock.now = clock.now.Add(time.Minute)
+	r.Event(b, corev1.EventTypeNormal, "Reason", "message")
+	g.Expect(r.limiters).To(HaveLen(1))
+}
+
+func TestNewThrottledRecorderDisabled(t *testing.T) {
+	g := NewWithT(t)
+	fake := record.NewFakeRecorder(1)
+	g.Expect(NewThrottledRecorder(fake, ThrottleOptions{})).To(BeIdenticalTo(fake))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooks provides validating webhooks which can return admission warnings.
+package webhooks
+
+import (
+	"context"
+	goerrors "errors"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Warnings are non-fatal messages returned to the client along with the admission response.
+// kubectl prints them on apply, but they never reject the request.
+type Warnings []string
+
+// Validator is the webhook.Validator of controller-runtime extended with admission warnings.
+type Validator interface {
+	runtime.Object
+	ValidateCreate() (Warnings, error)
+	ValidateUpdate(old runtime.Object) (Warnings, error)
+	ValidateDelete() (Warnings, error)
+}
+
+// RegisterValidator registers the validating webhook of the given type on the path the
+// controller-runtime webhook builder uses, so that the generated webhook configuration keeps working.
+// The type must not implement webhook.Validator as well, or the builder registers the path twice.
+func RegisterValidator(mgr ctrl.Manager, obj Validator) error {
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	path := "/validate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+	mgr.GetWebhookServer().Register(path, &admission.Webhook{
+		Handler: &validatingHandler{validator: obj},
+	})
+	return nil
+}
+
+type validatingHandler struct {
+	validator Validator
+	decoder   *admission.Decoder
+}
+
+// InjectDecoder injects the decoder into the handler.
+func (h *validatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle decodes the object of the request and validates it.
+func (h *validatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := h.validator.DeepCopyObject().(Validator)
+
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.decoder.Decode(req, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		return response(obj.ValidateCreate())
+	case admissionv1.Update:
+		oldObj := obj.DeepCopyObject()
+		if err := h.decoder.DecodeRaw(req.Object, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := h.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		return response(obj.ValidateUpdate(oldObj))
+	case admissionv1.Delete:
+		// The object being deleted is sent as the old object.
+		if err := h.decoder.DecodeRaw(req.OldObject, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		return response(obj.ValidateDelete())
+	}
+
+	return admission.Allowed("")
+}
+
+func response(warnings Warnings, err error) admission.Response {
+	resp := admission.Allowed("")
+	if err != nil {
+		var apiStatus apierrors.APIStatus
+		if goerrors.As(err, &apiStatus) {
+			status := apiStatus.Status()
+			resp = admission.Response{
+				AdmissionResponse: admissionv1.AdmissionResponse{
+					Allowed: false,
+					Result:  &status,
+				},
+			}
+		} else {
+			resp = admission.Denied(err.Error())
+		}
+	}
+	resp.Warnings = warnings
+	return resp
+}
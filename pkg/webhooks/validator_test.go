@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestResponse(t *testing.T) {
+	invalid := apierrors.NewInvalid(schema.GroupKind{Group: "infrastructure.cluster.x-k8s.io", Kind: "AWSMachine"}, "machine",
+		field.ErrorList{field.Required(field.NewPath("spec", "instanceType"), "")})
+
+	tests := []struct {
+		name        string
+		warnings    Warnings
+		err         error
+		wantAllowed bool
+		wantCode    int32
+	}{
+		{
+			name:        "allowed without warnings",
+			wantAllowed: true,
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:        "allowed with warnings",
+			warnings:    Warnings{"first", "second"},
+			wantAllowed: true,
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:        "denied with an API status error",
+			warnings:    Warnings{"first"},
+			err:         invalid,
+			wantAllowed: false,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "denied with a plain error",
+			warnings:    Warnings{"first"},
+			err:         errors.New("denied"),
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			resp := response(tt.warnings, tt.err)
+			g.Expect(resp.Allowed).To(Equal(tt.wantAllowed))
+			g.Expect(resp.Result.Code).To(Equal(tt.wantCode))
+			g.Expect(resp.Warnings).To(Equal([]string(tt.warnings)))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package defaulting provides test utilities for the webhooks of types returning admission warnings.
+package defaulting
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/webhooks"
+)
+
+// DefaultingValidator is implemented by the types which have both a defaulting webhook and a validating
+// webhook returning warnings.
+type DefaultingValidator interface {
+	admission.Defaulter
+	webhooks.Validator
+}
+
+// DefaultValidateTest is the DefaultValidateTest of the Cluster API defaulting utilities for a Validator. It
+// returns a testing function which checks that defaulted objects pass validation on create, update and delete.
+func DefaultValidateTest(object DefaultingValidator) func(*testing.T) {
+	return func(t *testing.T) {
+		createCopy := object.DeepCopyObject().(DefaultingValidator)
+		updateCopy := object.DeepCopyObject().(DefaultingValidator)
+		deleteCopy := object.DeepCopyObject().(DefaultingValidator)
+		defaultingUpdateCopy := updateCopy.DeepCopyObject().(DefaultingValidator)
+
+		t.Run("validate-on-create", func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			createCopy.Default()
+			_, err := createCopy.ValidateCreate()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		t.Run("validate-on-update", func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			defaultingUpdateCopy.Default()
+			updateCopy.Default()
+			_, err := defaultingUpdateCopy.ValidateUpdate(updateCopy)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		t.Run("validate-on-delete", func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			deleteCopy.Default()
+			_, err := deleteCopy.ValidateDelete()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	}
+}